@@ -4,6 +4,8 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,24 +13,32 @@ import (
 
 	"github.com/autobrr/autobrr/internal/action"
 	"github.com/autobrr/autobrr/internal/api"
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/auth"
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/diagnostics"
+	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/events"
 	"github.com/autobrr/autobrr/internal/feed"
 	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/health"
 	"github.com/autobrr/autobrr/internal/http"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/irc"
+	"github.com/autobrr/autobrr/internal/list"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/maintenance"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/internal/mockindexer"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/proxy"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/releasedownload"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/internal/server"
+	"github.com/autobrr/autobrr/internal/storage"
 	"github.com/autobrr/autobrr/internal/update"
 	"github.com/autobrr/autobrr/internal/user"
 
@@ -47,8 +57,17 @@ var (
 )
 
 func main() {
-	var configPath string
+	var (
+		configPath     string
+		clientSelfTest int32
+		clientFixtures string
+		clientRecord   bool
+	)
+
 	pflag.StringVar(&configPath, "config", "", "path to configuration file")
+	pflag.Int32Var(&clientSelfTest, "client-selftest", 0, "verify the download client with this id against the recorded api fixture and exit, fixtures are bundled for qBittorrent and SABnzbd")
+	pflag.StringVar(&clientFixtures, "client-fixtures", "", "directory with client fixtures, defaults to the bundled fixtures")
+	pflag.BoolVar(&clientRecord, "client-record", false, "record the client selftest exchanges to --client-fixtures instead of verifying them")
 	pflag.Parse()
 
 	// read config
@@ -72,6 +91,7 @@ func main() {
 	// setup server-sent-events
 	serverEvents := sse.New()
 	serverEvents.CreateStreamWithOpts("logs", sse.StreamOpts{MaxEntries: 1000, AutoReplay: true})
+	serverEvents.CreateStreamWithOpts(domain.LiveStream, sse.StreamOpts{MaxEntries: 100, AutoReplay: false})
 
 	// register SSE hook on logger
 	log.RegisterSSEWriter(serverEvents)
@@ -94,42 +114,83 @@ func main() {
 
 	// setup repos
 	var (
-		apikeyRepo         = database.NewAPIRepo(log, db)
-		downloadClientRepo = database.NewDownloadClientRepo(log, db)
-		actionRepo         = database.NewActionRepo(log, db, downloadClientRepo)
-		filterRepo         = database.NewFilterRepo(log, db)
-		feedRepo           = database.NewFeedRepo(log, db)
-		feedCacheRepo      = database.NewFeedCacheRepo(log, db)
-		indexerRepo        = database.NewIndexerRepo(log, db)
-		ircRepo            = database.NewIrcRepo(log, db)
-		notificationRepo   = database.NewNotificationRepo(log, db)
-		releaseRepo        = database.NewReleaseRepo(log, db)
-		userRepo           = database.NewUserRepo(log, db)
-		proxyRepo          = database.NewProxyRepo(log, db)
+		apikeyRepo               = database.NewAPIRepo(log, db)
+		auditRepo                = database.NewAuditRepo(log, db)
+		downloadClientRepo       = database.NewDownloadClientRepo(log, db)
+		actionRepo               = database.NewActionRepo(log, db, downloadClientRepo)
+		freeleechTokenRepo       = database.NewFreeleechTokenRepo(log, db)
+		filterRepo               = database.NewFilterRepo(log, db)
+		filterGroupRepo          = database.NewFilterGroupRepo(log, db)
+		feedRepo                 = database.NewFeedRepo(log, db)
+		feedCacheRepo            = database.NewFeedCacheRepo(log, db)
+		indexerRepo              = database.NewIndexerRepo(log, db)
+		ircRepo                  = database.NewIrcRepo(log, db)
+		notificationRepo         = database.NewNotificationRepo(log, db)
+		notificationPrefRepo     = database.NewNotificationPreferenceRepo(log, db)
+		notificationDeliveryRepo = database.NewNotificationDeliveryRepo(log, db)
+		releaseRepo              = database.NewReleaseRepo(log, db)
+		releaseRetentionRepo     = database.NewReleaseRetentionRepo(log, db)
+		releasePendingRepo       = database.NewReleasePendingRepo(log, db)
+		announceHistoryRepo      = database.NewAnnounceHistoryRepo(log, db)
+		userRepo                 = database.NewUserRepo(log, db)
+		proxyRepo                = database.NewProxyRepo(log, db)
+		listRepo                 = database.NewListRepo(log, db)
+		maintenanceRepo          = database.NewMaintenanceRepo(log, db)
 	)
 
+	storageService, err := storage.NewService(log, cfg.Config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not setup storage")
+	}
+
 	// setup services
 	var (
 		apiService            = api.NewService(log, apikeyRepo)
+		auditService          = audit.NewService(log, auditRepo)
 		updateService         = update.NewUpdate(log, cfg.Config)
-		notificationService   = notification.NewService(log, notificationRepo)
-		schedulingService     = scheduler.NewService(log, cfg.Config, notificationService, updateService)
+		notificationService   = notification.NewService(log, notificationRepo, notificationPrefRepo, notificationDeliveryRepo, userRepo)
+		downloadClientService = download_client.NewService(log, downloadClientRepo)
+		schedulingService     = scheduler.NewService(log, cfg.Config, notificationService, updateService, downloadClientService)
 		indexerAPIService     = indexer.NewAPIService(log)
-		userService           = user.NewService(userRepo)
-		authService           = auth.NewService(log, userService)
+		userService           = user.NewService(userRepo, bus)
+		authService           = auth.NewService(log, userService, cfg.Config.OIDC)
 		proxyService          = proxy.NewService(log, proxyRepo)
 		downloadService       = releasedownload.NewDownloadService(log, releaseRepo, indexerRepo, proxyService)
-		downloadClientService = download_client.NewService(log, downloadClientRepo)
-		actionService         = action.NewService(log, actionRepo, downloadClientService, downloadService, bus)
+		actionService         = action.NewService(log, cfg.Config, actionRepo, freeleechTokenRepo, downloadClientService, downloadService, bus)
 		indexerService        = indexer.NewService(log, cfg.Config, indexerRepo, releaseRepo, indexerAPIService, schedulingService)
-		filterService         = filter.NewService(log, filterRepo, actionService, releaseRepo, indexerAPIService, indexerService, downloadService)
-		releaseService        = release.NewService(log, releaseRepo, actionService, filterService, indexerService)
+		filterService         = filter.NewService(log, cfg.Config, filterRepo, filterGroupRepo, actionService, downloadClientService, releaseRepo, indexerAPIService, indexerService, downloadService, schedulingService)
+		releaseService        = release.NewService(log, cfg.Config, releaseRepo, releaseRetentionRepo, releasePendingRepo, announceHistoryRepo, actionService, filterService, indexerService, schedulingService, storageService, bus)
 		ircService            = irc.NewService(log, serverEvents, ircRepo, releaseService, indexerService, notificationService, proxyService)
-		feedService           = feed.NewService(log, feedRepo, feedCacheRepo, releaseService, proxyService, schedulingService)
+		feedService           = feed.NewService(log, feedRepo, feedCacheRepo, releaseService, proxyService, schedulingService, notificationService)
+		listService           = list.NewService(log, listRepo, filterService, downloadClientService, schedulingService)
+		maintenanceService    = maintenance.NewService(log, cfg.Config, maintenanceRepo, schedulingService, storageService)
+		mockIndexerService    = mockindexer.NewService(log, cfg.Config, indexerService, releaseService, schedulingService)
+		healthService         = health.NewService(log, db, ircService, feedService, downloadClientService)
 	)
 
+	if clientSelfTest > 0 {
+		code := runClientSelfTest(downloadClientService, clientSelfTest, domain.DownloadClientSelfTestOptions{
+			FixtureDir: clientFixtures,
+			Record:     clientRecord,
+		})
+
+		db.Close()
+		os.Exit(code)
+	}
+
+	metrics.Setup(log, cfg.Config, ircService)
+
 	// register event subscribers
-	events.NewSubscribers(log, bus, notificationService, releaseService)
+	events.NewSubscribers(log, bus, notificationService, releaseService, indexerService, downloadClientService, serverEvents)
+
+	// invalidate caches when another instance sharing the database makes changes
+	if db.Driver == "postgres" {
+		if err := db.ListenForChanges(func(table string) {
+			bus.Publish("database:changed", table)
+		}); err != nil {
+			log.Error().Err(err).Msg("could not listen for database changes")
+		}
+	}
 
 	errorChannel := make(chan error)
 
@@ -144,12 +205,16 @@ func main() {
 			date,
 			actionService,
 			apiService,
+			auditService,
 			authService,
 			downloadClientService,
 			filterService,
 			feedService,
+			healthService,
 			indexerService,
 			ircService,
+			listService,
+			mockIndexerService,
 			notificationService,
 			proxyService,
 			releaseService,
@@ -161,7 +226,7 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
 
-	srv := server.NewServer(log, cfg.Config, ircService, indexerService, feedService, schedulingService, updateService)
+	srv := server.NewServer(log, cfg.Config, ircService, indexerService, feedService, filterService, listService, releaseService, maintenanceService, mockIndexerService, schedulingService, updateService)
 	if err := srv.Start(); err != nil {
 		log.Fatal().Stack().Err(err).Msg("could not start server")
 		return
@@ -179,3 +244,33 @@ func main() {
 		os.Exit(0)
 	}
 }
+
+func runClientSelfTest(downloadClientService download_client.Service, clientID int32, opts domain.DownloadClientSelfTestOptions) int {
+	if opts.Record && opts.FixtureDir == "" {
+		fmt.Fprintln(os.Stderr, "--client-record requires --client-fixtures")
+		return 1
+	}
+
+	report, err := downloadClientService.SelfTest(context.Background(), clientID, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "client selftest failed: %v\n", err)
+		return 1
+	}
+
+	if opts.Record {
+		fmt.Printf("recorded %d exchanges for %s (%s) to %s\n", report.Exchanges, report.ClientName, report.ClientType, report.FixturePath)
+		return 0
+	}
+
+	if report.Passed() {
+		fmt.Printf("client selftest passed for %s (%s): %d exchanges match the recorded api\n", report.ClientName, report.ClientType, report.Exchanges)
+		return 0
+	}
+
+	fmt.Printf("client selftest failed for %s (%s), the client api differs from the recorded fixture:\n", report.ClientName, report.ClientType)
+	for _, issue := range report.Issues {
+		fmt.Printf("  - %s\n", issue)
+	}
+
+	return 1
+}
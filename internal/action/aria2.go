@@ -0,0 +1,116 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/aria2"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+func (s *service) aria2(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.log.Debug().Msgf("action aria2: %s", action.Name)
+
+	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get client with id %d", action.ClientID)
+	}
+	action.Client = client
+
+	if !client.Enabled {
+		return nil, errors.New("client %s %s not enabled", client.Type, client.Name)
+	}
+
+	arc := client.Client.(*aria2.Client)
+
+	rejections, err := s.aria2CheckRulesCanDownload(ctx, action, client, arc)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking aria2 client rules: %s", action.Name)
+	}
+
+	if len(rejections) > 0 {
+		return rejections, nil
+	}
+
+	opts := &aria2.AddOptions{
+		Dir: action.SavePath,
+	}
+
+	// aria2 has no labels, downloads with a label are saved in a sub directory named after it
+	if action.Label != "" {
+		opts.Dir = filepath.Join(action.SavePath, action.Label)
+	}
+
+	if action.LimitDownloadSpeed > 0 {
+		opts.MaxDownloadLimit = fmt.Sprintf("%dK", action.LimitDownloadSpeed)
+	}
+
+	if action.LimitUploadSpeed > 0 {
+		opts.MaxUploadLimit = fmt.Sprintf("%dK", action.LimitUploadSpeed)
+	}
+
+	if action.Paused {
+		opts.Pause = "true"
+	}
+
+	if release.HasMagnetUri() {
+		gid, err := arc.AddUri(ctx, []string{release.MagnetURI}, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not add torrent from magnet %s to client: %s", release.MagnetURI, client.Name)
+		}
+
+		s.log.Info().Msgf("torrent from magnet successfully added to client: '%s' with gid %s", client.Name, gid)
+
+		return nil, nil
+	}
+
+	s.useFreeleechToken(ctx, action, release)
+
+	if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
+		return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
+	}
+
+	content, err := os.ReadFile(release.TorrentTmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file: %s", release.TorrentTmpFile)
+	}
+
+	gid, err := arc.AddTorrent(ctx, content, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Name)
+	}
+
+	s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s' with gid %s", release.TorrentHash, client.Name, gid)
+
+	return nil, nil
+}
+
+func (s *service) aria2CheckRulesCanDownload(ctx context.Context, action *domain.Action, client *domain.DownloadClient, arc *aria2.Client) ([]string, error) {
+	s.log.Trace().Msgf("action aria2: %s check rules", action.Name)
+
+	// check for active downloads and other rules
+	if client.Settings.Rules.Enabled && !action.IgnoreRules {
+		active, err := arc.TellActive(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch active downloads")
+		}
+
+		if client.Settings.Rules.MaxActiveDownloads > 0 {
+			if len(active) >= client.Settings.Rules.MaxActiveDownloads {
+				rejection := "max active downloads reached, skipping"
+
+				s.log.Debug().Msg(rejection)
+
+				return []string{rejection}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
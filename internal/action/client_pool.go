@@ -0,0 +1,164 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+// clientPool keeps the round-robin position of the actions with a client pool
+type clientPool struct {
+	mu   sync.Mutex
+	next map[int]int
+}
+
+// roundRobin returns the index of the client to use for the action and moves to the next one
+func (p *clientPool) roundRobin(actionID int, size int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.next == nil {
+		p.next = map[int]int{}
+	}
+
+	idx := p.next[actionID] % size
+	p.next[actionID] = idx + 1
+
+	return idx
+}
+
+// actionClientPool returns the clients of the action, the client followed by the failover clients
+func actionClientPool(action *domain.Action) []int32 {
+	pool := []int32{action.ClientID}
+
+	for _, id := range action.FailoverClientIDs {
+		if id > 0 && !slices.Contains(pool, id) {
+			pool = append(pool, id)
+		}
+	}
+
+	return pool
+}
+
+// pickPoolClient sets the client of the action picked from its pool by the pool mode,
+// the other clients of the pool become the failover clients in pool order after it
+func (s *service) pickPoolClient(ctx context.Context, action *domain.Action) {
+	if action.ClientPoolMode == "" {
+		return
+	}
+
+	pool := actionClientPool(action)
+	if len(pool) < 2 {
+		return
+	}
+
+	var idx int
+
+	switch action.ClientPoolMode {
+	case domain.ClientPoolModeLeastActive:
+		var err error
+		idx, err = s.leastActiveClient(ctx, pool)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("action %s: could not get active torrents of client pool, using round-robin", action.Name)
+			idx = s.clientPool.roundRobin(action.ID, len(pool))
+		}
+
+	case domain.ClientPoolModeRoundRobin:
+		idx = s.clientPool.roundRobin(action.ID, len(pool))
+
+	default:
+		s.log.Warn().Msgf("action %s: unknown client pool mode: %s", action.Name, action.ClientPoolMode)
+		return
+	}
+
+	ordered := append(slices.Clone(pool[idx:]), pool[:idx]...)
+
+	action.ClientID = ordered[0]
+	action.FailoverClientIDs = ordered[1:]
+
+	s.log.Debug().Msgf("action %s: picked client %d from pool %v by %s", action.Name, action.ClientID, pool, action.ClientPoolMode)
+}
+
+// leastActiveClient returns the index of the client with the least active torrents in the pool,
+// unreachable clients are skipped
+func (s *service) leastActiveClient(ctx context.Context, pool []int32) (int, error) {
+	idx := -1
+	least := math.MaxInt
+
+	var lastErr error
+
+	for i, id := range pool {
+		client, err := s.clientSvc.GetClient(ctx, id)
+		if err != nil {
+			lastErr = errors.Wrap(err, "could not get client with id %d", id)
+			continue
+		}
+
+		if !client.Enabled {
+			continue
+		}
+
+		active, err := activeTorrents(ctx, client)
+		if err != nil {
+			lastErr = errors.Wrap(err, "could not get active torrents of client: %s", client.Name)
+			continue
+		}
+
+		s.log.Trace().Msgf("client pool: client %s has %d active torrents", client.Name, active)
+
+		if active < least {
+			idx = i
+			least = active
+		}
+	}
+
+	if idx < 0 {
+		if lastErr == nil {
+			lastErr = errors.New("no enabled clients in pool")
+		}
+		return 0, lastErr
+	}
+
+	return idx, nil
+}
+
+// activeTorrents returns the number of torrents downloading or uploading data in the client
+func activeTorrents(ctx context.Context, client *domain.DownloadClient) (int, error) {
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		torrents, err := client.Client.(*qbittorrent.Client).GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Filter: qbittorrent.TorrentFilterActive})
+		if err != nil {
+			return 0, err
+		}
+
+		return len(torrents), nil
+
+	case domain.DownloadClientTypeTransmission:
+		torrents, err := client.Client.(*transmissionrpc.Client).TorrentGet(ctx, []string{"rateDownload", "rateUpload"}, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		active := 0
+		for _, torrent := range torrents {
+			if (torrent.RateDownload != nil && *torrent.RateDownload > 0) || (torrent.RateUpload != nil && *torrent.RateUpload > 0) {
+				active++
+			}
+		}
+
+		return active, nil
+
+	default:
+		return 0, errors.New("active torrents not supported for client type: %s", client.Type)
+	}
+}
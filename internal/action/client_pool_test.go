@@ -0,0 +1,79 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_actionClientPool(t *testing.T) {
+	assert.Equal(t, []int32{1}, actionClientPool(&domain.Action{ClientID: 1}))
+	assert.Equal(t, []int32{1, 3, 2}, actionClientPool(&domain.Action{ClientID: 1, FailoverClientIDs: []int32{3, 1, 2, 3, 0}}))
+}
+
+func Test_service_pickPoolClient_roundRobin(t *testing.T) {
+	s := &service{log: zerolog.Nop()}
+
+	var picked [][]int32
+	for i := 0; i < 4; i++ {
+		action := &domain.Action{ID: 1, ClientID: 1, FailoverClientIDs: []int32{2, 3}, ClientPoolMode: domain.ClientPoolModeRoundRobin}
+		s.pickPoolClient(context.Background(), action)
+
+		picked = append(picked, append([]int32{action.ClientID}, action.FailoverClientIDs...))
+	}
+
+	assert.Equal(t, [][]int32{{1, 2, 3}, {2, 3, 1}, {3, 1, 2}, {1, 2, 3}}, picked)
+
+	// without a pool mode the client and failover order are kept
+	action := &domain.Action{ID: 1, ClientID: 1, FailoverClientIDs: []int32{2, 3}}
+	s.pickPoolClient(context.Background(), action)
+	assert.Equal(t, int32(1), action.ClientID)
+	assert.Equal(t, []int32{2, 3}, action.FailoverClientIDs)
+}
+
+func Test_service_pickPoolClient_leastActive(t *testing.T) {
+	newQbittorrent := func(active int) *qbittorrent.Client {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v2/torrents/info" || r.URL.Query().Get("filter") != "active" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			torrents := make([]string, active)
+			for i := range torrents {
+				torrents[i] = fmt.Sprintf(`{"hash":"%d"}`, i)
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(torrents, ",") + "]"))
+		}))
+		t.Cleanup(srv.Close)
+
+		return qbittorrent.NewClient(qbittorrent.Config{Host: srv.URL})
+	}
+
+	clientSvc := &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+		1: {ID: 1, Name: "qbit-1", Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Client: newQbittorrent(5)},
+		2: {ID: 2, Name: "qbit-2", Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Client: newQbittorrent(1)},
+		3: {ID: 3, Name: "qbit-3", Type: domain.DownloadClientTypeQbittorrent, Enabled: false},
+	}}
+
+	s := &service{log: zerolog.Nop(), clientSvc: clientSvc}
+
+	// client 4 is unreachable and client 3 disabled, both are skipped
+	action := &domain.Action{ID: 1, ClientID: 1, FailoverClientIDs: []int32{2, 3, 4}, ClientPoolMode: domain.ClientPoolModeLeastActive}
+	s.pickPoolClient(context.Background(), action)
+
+	assert.Equal(t, int32(2), action.ClientID)
+	assert.Equal(t, []int32{3, 4, 1}, action.FailoverClientIDs)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+// CompletedTorrents returns when the torrents with the infohashes finished downloading in the client, keyed by lowercase infohash.
+// Torrents that are still downloading or not in the client are left out.
+func (s *service) CompletedTorrents(ctx context.Context, clientID int32, hashes []string) (map[string]time.Time, error) {
+	client, err := s.enabledClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := completedTorrents(ctx, client, hashes, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get completed torrents of client: %s", client.Name)
+	}
+
+	return completed, nil
+}
+
+// completedTorrents returns the completion time of the finished torrents, now is used when the client did not record one
+func completedTorrents(ctx context.Context, client *domain.DownloadClient, hashes []string, now time.Time) (map[string]time.Time, error) {
+	completed := make(map[string]time.Time)
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		torrents, err := client.Client.(*qbittorrent.Client).GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: hashes})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, torrent := range torrents {
+			if torrent.Progress < 1 {
+				continue
+			}
+
+			completedAt := now
+			if torrent.CompletionOn > 0 {
+				completedAt = time.Unix(torrent.CompletionOn, 0)
+			}
+
+			completed[strings.ToLower(torrent.Hash)] = completedAt
+		}
+
+	case domain.DownloadClientTypeTransmission:
+		torrents, err := client.Client.(*transmissionrpc.Client).TorrentGetHashes(ctx, []string{"hashString", "percentDone", "doneDate"}, hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, torrent := range torrents {
+			if torrent.HashString == nil || torrent.PercentDone == nil || *torrent.PercentDone < 1 {
+				continue
+			}
+
+			completedAt := now
+			if torrent.DoneDate != nil && torrent.DoneDate.Unix() > 0 {
+				completedAt = *torrent.DoneDate
+			}
+
+			completed[strings.ToLower(*torrent.HashString)] = completedAt
+		}
+
+	default:
+		return nil, errors.New("completion tracking not supported for client type: %s", client.Type)
+	}
+
+	return completed, nil
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// completionHookTimeout keeps a hanging hook from blocking the completion check
+const completionHookTimeout = 5 * time.Minute
+
+// RunCompletionHooks runs the completion hooks of the action for the release once its torrent finished downloading,
+// and the cross-seed search when the action triggers it on completion.
+// Failing hooks don't stop the others from running.
+func (s *service) RunCompletionHooks(ctx context.Context, action *domain.Action, release *domain.Release) error {
+	failed := 0
+
+	for i, hook := range action.CompletionHooks {
+		if err := s.runCompletionHook(ctx, action, hook, release); err != nil {
+			s.log.Error().Err(err).Msgf("action %s: completion hook %d (%s) failed for release: %s", action.Name, i+1, hook.Type, release.TorrentName)
+			failed++
+		}
+	}
+
+	total := len(action.CompletionHooks)
+
+	if action.CrossSeed == domain.ActionCrossSeedOnComplete {
+		total++
+
+		if err := s.crossSeedSearch(ctx, *release); err != nil {
+			s.log.Error().Err(err).Msgf("action %s: cross-seed search failed for release: %s", action.Name, release.TorrentName)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.New("%d of %d completion hooks failed", failed, total)
+	}
+
+	return nil
+}
+
+func (s *service) runCompletionHook(ctx context.Context, action *domain.Action, hook domain.CompletionHook, release *domain.Release) error {
+	ctx, cancel := context.WithTimeout(ctx, completionHookTimeout)
+	defer cancel()
+
+	m := domain.NewMacro(*release)
+
+	switch hook.Type {
+	case domain.CompletionHookTypeExec:
+		args, err := m.Parse(hook.ExecArgs)
+		if err != nil {
+			return errors.Wrap(err, "could not parse exec args")
+		}
+
+		// the exec output can't reject anything anymore so it's ignored
+		_, err = s.execCmd(ctx, &domain.Action{Name: action.Name, ExecCmd: hook.ExecCmd, ExecArgs: args}, release)
+		return err
+
+	case domain.CompletionHookTypeWebhook:
+		data, err := m.Parse(hook.WebhookData)
+		if err != nil {
+			return errors.Wrap(err, "could not parse webhook data")
+		}
+
+		return s.webhook(ctx, &domain.Action{Name: action.Name, WebhookHost: hook.WebhookHost, WebhookMethod: hook.WebhookMethod, WebhookData: data}, *release)
+
+	case domain.CompletionHookTypePlexScan:
+		path, err := m.Parse(hook.PlexPath)
+		if err != nil {
+			return errors.Wrap(err, "could not parse plex path")
+		}
+
+		return s.plexScan(ctx, hook, path)
+
+	case domain.CompletionHookTypeAutoscan:
+		path, err := completionScanPath(m, hook.AutoscanPath, action.SavePath)
+		if err != nil {
+			return errors.Wrap(err, "could not parse autoscan path")
+		}
+
+		return s.autoscanScan(ctx, hook, path)
+
+	case domain.CompletionHookTypeJellyfin:
+		path, err := completionScanPath(m, hook.JellyfinPath, action.SavePath)
+		if err != nil {
+			return errors.Wrap(err, "could not parse jellyfin path")
+		}
+
+		return s.jellyfinScan(ctx, hook, path)
+
+	default:
+		return errors.New("unsupported completion hook type: %s", hook.Type)
+	}
+}
+
+// plexScan asks plex to scan the library section, only the path when set
+func (s *service) plexScan(ctx context.Context, hook domain.CompletionHook, path string) error {
+	if hook.PlexHost == "" || hook.PlexSection == "" {
+		return errors.New("plex scan needs a host and library section")
+	}
+
+	params := url.Values{}
+	params.Set("X-Plex-Token", hook.PlexToken)
+	if path != "" {
+		params.Set("path", path)
+	}
+
+	scanURL := fmt.Sprintf("%s/library/sections/%s/refresh?%s", strings.TrimSuffix(hook.PlexHost, "/"), url.PathEscape(hook.PlexSection), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scanURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not build plex scan request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not make plex scan request")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("plex scan got unexpected status code: %d", res.StatusCode)
+	}
+
+	s.log.Debug().Msgf("plex scan of section %s requested: %s", hook.PlexSection, path)
+
+	return nil
+}
+
+// completionScanPath parses the scan path of the hook, or the save path of the action when the hook has none,
+// so the scan only covers the folder the release was downloaded to
+func completionScanPath(m domain.Macro, hookPath, savePath string) (string, error) {
+	if hookPath == "" {
+		hookPath = savePath
+	}
+
+	if hookPath == "" {
+		return "", nil
+	}
+
+	return m.Parse(hookPath)
+}
+
+// autoscanScan asks autoscan to scan the path through its manual trigger, it passes the path on to the configured media servers
+func (s *service) autoscanScan(ctx context.Context, hook domain.CompletionHook, path string) error {
+	if hook.AutoscanHost == "" || path == "" {
+		return errors.New("autoscan scan needs a host and a path")
+	}
+
+	scanURL := fmt.Sprintf("%s/triggers/manual?%s", strings.TrimSuffix(hook.AutoscanHost, "/"), url.Values{"dir": {path}}.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scanURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not build autoscan request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+	if hook.AutoscanUsername != "" {
+		req.SetBasicAuth(hook.AutoscanUsername, hook.AutoscanPassword)
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not make autoscan request")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("autoscan got unexpected status code: %d", res.StatusCode)
+	}
+
+	s.log.Debug().Msgf("autoscan scan requested: %s", path)
+
+	return nil
+}
+
+type jellyfinMediaUpdate struct {
+	Path       string `json:"Path"`
+	UpdateType string `json:"UpdateType"`
+}
+
+type jellyfinMediaUpdatedRequest struct {
+	Updates []jellyfinMediaUpdate `json:"Updates"`
+}
+
+// jellyfinScan tells jellyfin the path was created so only that folder is scanned,
+// without a path all libraries are refreshed
+func (s *service) jellyfinScan(ctx context.Context, hook domain.CompletionHook, path string) error {
+	if hook.JellyfinHost == "" || hook.JellyfinAPIKey == "" {
+		return errors.New("jellyfin scan needs a host and api key")
+	}
+
+	host := strings.TrimSuffix(hook.JellyfinHost, "/")
+
+	scanURL := host + "/Library/Refresh"
+	var body io.Reader = http.NoBody
+
+	if path != "" {
+		data, err := json.Marshal(jellyfinMediaUpdatedRequest{Updates: []jellyfinMediaUpdate{{Path: path, UpdateType: "Created"}}})
+		if err != nil {
+			return errors.Wrap(err, "could not marshal jellyfin request")
+		}
+
+		scanURL = host + "/Library/Media/Updated"
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scanURL, body)
+	if err != nil {
+		return errors.Wrap(err, "could not build jellyfin request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Emby-Token", hook.JellyfinAPIKey)
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not make jellyfin request")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.New("jellyfin got unexpected status code: %d", res.StatusCode)
+	}
+
+	s.log.Debug().Msgf("jellyfin scan requested: %s", path)
+
+	return nil
+}
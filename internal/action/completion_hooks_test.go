@@ -0,0 +1,62 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_RunCompletionHooks(t *testing.T) {
+	s := &service{log: logger.Mock().With().Logger(), httpClient: http.DefaultClient}
+	release := &domain.Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", TorrentHash: "abcdef"}
+
+	var requests []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if user, pass, ok := r.BasicAuth(); ok {
+			body = append(body, []byte(user+":"+pass)...)
+		}
+		if token := r.Header.Get("X-Emby-Token"); token != "" {
+			body = append(body, []byte(" token="+token)...)
+		}
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+	}))
+	defer ts.Close()
+
+	action := &domain.Action{
+		Name:     "qbit",
+		SavePath: "/downloads/tv",
+		CompletionHooks: []domain.CompletionHook{
+			{Type: domain.CompletionHookTypeWebhook, WebhookHost: ts.URL + "/webhook", WebhookData: `{"name":"{{ .TorrentName }}","hash":"{{ .TorrentHash }}"}`},
+			{Type: domain.CompletionHookTypePlexScan, PlexHost: ts.URL + "/", PlexToken: "token", PlexSection: "2", PlexPath: "/media/tv/{{ .TorrentName }}"},
+			{Type: domain.CompletionHookTypePlexScan, PlexHost: ts.URL},
+			{Type: domain.CompletionHookTypeAutoscan, AutoscanHost: ts.URL, AutoscanUsername: "user", AutoscanPassword: "pass", AutoscanPath: "/media/tv/{{ .TorrentName }}"},
+			{Type: domain.CompletionHookTypeAutoscan, AutoscanHost: ts.URL},
+			{Type: domain.CompletionHookTypeJellyfin, JellyfinHost: ts.URL, JellyfinAPIKey: "key"},
+			{Type: domain.CompletionHookTypeJellyfin, JellyfinHost: ts.URL},
+			{Type: "UNKNOWN"},
+		},
+	}
+
+	err := s.RunCompletionHooks(context.Background(), action, release)
+	assert.EqualError(t, err, "3 of 8 completion hooks failed")
+
+	assert.Equal(t, []string{
+		`POST /webhook {"name":"Show.Name.S01E01.1080p.WEB-DL.H264-GRP","hash":"abcdef"}`,
+		"GET /library/sections/2/refresh?X-Plex-Token=token&path=%2Fmedia%2Ftv%2FShow.Name.S01E01.1080p.WEB-DL.H264-GRP ",
+		"POST /triggers/manual?dir=%2Fmedia%2Ftv%2FShow.Name.S01E01.1080p.WEB-DL.H264-GRP user:pass",
+		"POST /triggers/manual?dir=%2Fdownloads%2Ftv ",
+		`POST /Library/Media/Updated {"Updates":[{"Path":"/downloads/tv","UpdateType":"Created"}]} token=key`,
+	}, requests)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_completedTorrents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/torrents/info" || r.URL.Query().Get("hashes") != "AAAA|bbbb|cccc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`[
+			{"hash":"aaaa","progress":1,"completion_on":1700000000},
+			{"hash":"bbbb","progress":0.5,"completion_on":-1},
+			{"hash":"cccc","progress":1,"completion_on":-1}
+		]`))
+	}))
+	defer srv.Close()
+
+	client := &domain.DownloadClient{
+		Name:   "qbit",
+		Type:   domain.DownloadClientTypeQbittorrent,
+		Client: qbittorrent.NewClient(qbittorrent.Config{Host: srv.URL}),
+	}
+
+	now := time.Now()
+
+	completed, err := completedTorrents(context.Background(), client, []string{"AAAA", "bbbb", "cccc"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"aaaa": time.Unix(1700000000, 0),
+		"cccc": now,
+	}, completed)
+
+	_, err = completedTorrents(context.Background(), &domain.DownloadClient{Type: domain.DownloadClientTypeDelugeV2}, []string{"aaaa"}, now)
+	assert.Error(t, err)
+}
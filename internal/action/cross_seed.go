@@ -0,0 +1,83 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// crossSeedTimeout keeps a slow cross-seed instance from holding on to the request
+const crossSeedTimeout = 30 * time.Second
+
+type crossSeedWebhookRequest struct {
+	InfoHash string `json:"infoHash,omitempty"`
+	Name     string `json:"name"`
+}
+
+// triggerCrossSeed asks the cross-seed instance in the background to search for the pushed release,
+// the push is done by now so a failing search is only logged
+func (s *service) triggerCrossSeed(action *domain.Action, release domain.Release) {
+	if action.CrossSeed != domain.ActionCrossSeedOnPush || release.Protocol != domain.ReleaseProtocolTorrent {
+		return
+	}
+
+	go func() {
+		if err := s.crossSeedSearch(context.Background(), release); err != nil {
+			s.log.Error().Err(err).Msgf("action %s: could not trigger cross-seed search for release: %s", action.Name, release.TorrentName)
+		}
+	}()
+}
+
+// crossSeedSearch sends the release to the webhook of the cross-seed instance,
+// which searches its indexers for copies of the torrent and adds the matches to the client
+func (s *service) crossSeedSearch(ctx context.Context, release domain.Release) error {
+	if s.config == nil || s.config.CrossSeed.Host == "" {
+		return errors.New("cross-seed host not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, crossSeedTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(crossSeedWebhookRequest{
+		InfoHash: strings.ToLower(release.TorrentHash),
+		Name:     release.TorrentName,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal cross-seed request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(s.config.CrossSeed.Host, "/")+"/api/webhook", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not build cross-seed request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+	if s.config.CrossSeed.APIKey != "" {
+		req.Header.Set("X-Api-Key", s.config.CrossSeed.APIKey)
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not make cross-seed request")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.New("cross-seed got unexpected status code: %d", res.StatusCode)
+	}
+
+	s.log.Debug().Msgf("cross-seed search requested for release: %s", release.TorrentName)
+
+	return nil
+}
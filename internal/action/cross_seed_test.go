@@ -0,0 +1,64 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_crossSeedSearch(t *testing.T) {
+	var requests []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Api-Key")+" "+string(body))
+
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	release := domain.Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", TorrentHash: "ABCDEF"}
+
+	tests := []struct {
+		name    string
+		config  *domain.Config
+		wantErr string
+	}{
+		{name: "not_configured", config: &domain.Config{}, wantErr: "cross-seed host not configured"},
+		{name: "ok", config: &domain.Config{CrossSeed: domain.CrossSeedConfig{Host: ts.URL + "/", APIKey: "secret"}}},
+		{name: "unauthorized", config: &domain.Config{CrossSeed: domain.CrossSeedConfig{Host: ts.URL, APIKey: "wrong"}}, wantErr: "cross-seed got unexpected status code: 401"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: logger.Mock().With().Logger(), config: tt.config, httpClient: http.DefaultClient}
+
+			err := s.crossSeedSearch(context.Background(), release)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+
+	assert.Equal(t, []string{
+		`POST /api/webhook secret {"infoHash":"abcdef","name":"Show.Name.S01E01.1080p.WEB-DL.H264-GRP"}`,
+		`POST /api/webhook wrong {"infoHash":"abcdef","name":"Show.Name.S01E01.1080p.WEB-DL.H264-GRP"}`,
+	}, requests)
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"encoding/base64"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -15,7 +17,7 @@ import (
 	"github.com/autobrr/go-deluge"
 )
 
-func (s *service) deluge(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) deluge(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action Deluge: %s", action.Name)
 
 	var err error
@@ -43,7 +45,7 @@ func (s *service) deluge(ctx context.Context, action *domain.Action, release dom
 	return rejections, err
 }
 
-func (s *service) delugeCheckRulesCanDownload(ctx context.Context, del deluge.DelugeClient, client *domain.DownloadClient, action *domain.Action) ([]string, error) {
+func (s *service) delugeCheckRulesCanDownload(ctx context.Context, del deluge.DelugeClient, client *domain.DownloadClient, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Trace().Msgf("action Deluge: %v check rules", action.Name)
 
 	// check for active downloads and other rules
@@ -83,12 +85,14 @@ func (s *service) delugeCheckRulesCanDownload(ctx context.Context, del deluge.De
 				//}
 			}
 		}
+
+		return s.checkFreeSpace(ctx, client, release, delugeFreeSpace(del, action.SavePath))
 	}
 
 	return nil, nil
 }
 
-func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release *domain.Release) ([]string, error) {
 	//downloadClient := client.Client.(*deluge.Client)
 	downloadClient := deluge.NewV1(deluge.Settings{
 		Hostname:             client.Host,
@@ -108,7 +112,7 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 	defer downloadClient.Close()
 
 	// perform connection to Deluge server
-	rejections, err := s.delugeCheckRulesCanDownload(ctx, downloadClient, client, action)
+	rejections, err := s.delugeCheckRulesCanDownload(ctx, downloadClient, client, action, release)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("error checking client rules: %s", action.Name)
 		return nil, err
@@ -117,6 +121,14 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 		return rejections, nil
 	}
 
+	// the label is created before the torrent is added, so setting it can't fail on a missing label
+	labelPlugin, err := s.delugePrepareLabel(ctx, downloadClient, action.Label)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not prepare label: %s on client: %s", action.Label, client.Name)
+	}
+
+	s.replaceOriginals(ctx, client, release, delugeRemove(downloadClient))
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareDelugeOptions(action)
 		if err != nil {
@@ -130,27 +142,18 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent magnet %s to client: %s", release.MagnetURI, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := downloadClient.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
-			}
-
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := s.delugeSetLabel(ctx, downloadClient, labelPlugin, torrentHash, action.Label); err != nil {
+			return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
 		}
 
 		s.log.Info().Msgf("torrent from magnet with hash %s successfully added to client: '%s'", torrentHash, client.Name)
 
 		return nil, nil
 	} else {
+		s.useFreeleechToken(ctx, action, release)
+
 		if release.TorrentTmpFile == "" {
-			if err := s.downloadSvc.DownloadRelease(ctx, &release); err != nil {
+			if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
 				return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
 			}
 		}
@@ -178,19 +181,8 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent %v to client: %v", release.TorrentTmpFile, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := downloadClient.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
-			}
-
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %v on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := s.delugeSetLabel(ctx, downloadClient, labelPlugin, torrentHash, action.Label); err != nil {
+			return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
 		}
 
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", torrentHash, client.Name)
@@ -199,7 +191,7 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 	return nil, nil
 }
 
-func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release *domain.Release) ([]string, error) {
 	//downloadClient := client.Client.(*deluge.ClientV2)
 	downloadClient := deluge.NewV2(deluge.Settings{
 		Hostname:             client.Host,
@@ -219,7 +211,7 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 	defer downloadClient.Close()
 
 	// perform connection to Deluge server
-	rejections, err := s.delugeCheckRulesCanDownload(ctx, downloadClient, client, action)
+	rejections, err := s.delugeCheckRulesCanDownload(ctx, downloadClient, client, action, release)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("error checking client rules: %s", action.Name)
 		return nil, err
@@ -228,6 +220,14 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 		return rejections, nil
 	}
 
+	// the label is created before the torrent is added, so setting it can't fail on a missing label
+	labelPlugin, err := s.delugePrepareLabel(ctx, &downloadClient.Client, action.Label)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not prepare label: %s on client: %s", action.Label, client.Name)
+	}
+
+	s.replaceOriginals(ctx, client, release, delugeRemove(downloadClient))
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareDelugeOptions(action)
 		if err != nil {
@@ -241,26 +241,17 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent magnet %s to client: %s", release.MagnetURI, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := downloadClient.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
-			}
-
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := s.delugeSetLabel(ctx, downloadClient, labelPlugin, torrentHash, action.Label); err != nil {
+			return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
 		}
 
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", torrentHash, client.Name)
 
 		return nil, nil
 	} else {
-		if err := s.downloadSvc.DownloadRelease(ctx, &release); err != nil {
+		s.useFreeleechToken(ctx, action, release)
+
+		if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
 			return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
 		}
 
@@ -288,19 +279,8 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := downloadClient.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
-			}
-
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := s.delugeSetLabel(ctx, downloadClient, labelPlugin, torrentHash, action.Label); err != nil {
+			return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
 		}
 
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", torrentHash, client.Name)
@@ -328,9 +308,77 @@ func (s *service) prepareDelugeOptions(action *domain.Action) (deluge.Options, e
 		maxUL := int(action.LimitUploadSpeed)
 		options.MaxUploadSpeed = &maxUL
 	}
+	if action.MaxConnections > 0 {
+		options.MaxConnections = &action.MaxConnections
+	}
+	if action.LimitRatio > 0 {
+		stopRatio := float32(action.LimitRatio)
+		options.StopAtRatio = &TrTrue
+		options.StopRatio = &stopRatio
+	}
+	if action.MoveCompletedPath != "" {
+		options.MoveCompleted = &TrTrue
+		options.MoveCompletedPath = &action.MoveCompletedPath
+	}
 	if action.SkipHashCheck {
 		options.V2.SeedMode = &action.SkipHashCheck
 	}
 
 	return options, nil
 }
+
+// delugePrepareLabel creates the label when missing, it returns nil without a label or when the label plugin is disabled
+func (s *service) delugePrepareLabel(ctx context.Context, del *deluge.Client, label string) (*deluge.LabelPlugin, error) {
+	label = delugeLabel(label)
+	if label == "" {
+		return nil, nil
+	}
+
+	labelPlugin, err := del.LabelPlugin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load label plugin")
+	}
+
+	if labelPlugin == nil {
+		s.log.Warn().Msgf("label plugin not enabled, label %s is not set", label)
+		return nil, nil
+	}
+
+	labels, err := labelPlugin.GetLabels(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get labels")
+	}
+
+	if slices.Contains(labels, label) {
+		return labelPlugin, nil
+	}
+
+	if err := labelPlugin.AddLabel(ctx, label); err != nil {
+		return nil, errors.Wrap(err, "could not add label")
+	}
+
+	return labelPlugin, nil
+}
+
+// delugeSetLabel sets the label on the added torrent, the torrent is removed again when it fails
+// so it's not left seeding without the options of its label
+func (s *service) delugeSetLabel(ctx context.Context, del deluge.DelugeClient, labelPlugin *deluge.LabelPlugin, hash string, label string) error {
+	if labelPlugin == nil {
+		return nil
+	}
+
+	if err := labelPlugin.SetTorrentLabel(ctx, hash, delugeLabel(label)); err != nil {
+		if _, rmErr := del.RemoveTorrent(ctx, hash, true); rmErr != nil {
+			s.log.Error().Err(rmErr).Msgf("could not remove torrent with hash %s after setting the label failed", hash)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// delugeLabel returns the label id, the label plugin only allows lowercase labels
+func delugeLabel(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_prepareDelugeOptions(t *testing.T) {
+	s := &service{}
+
+	t.Run("empty", func(t *testing.T) {
+		options, err := s.prepareDelugeOptions(&domain.Action{})
+		assert.NoError(t, err)
+		assert.Nil(t, options.MaxConnections)
+		assert.Nil(t, options.StopAtRatio)
+		assert.Nil(t, options.MoveCompleted)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		options, err := s.prepareDelugeOptions(&domain.Action{
+			SavePath:          "/downloads",
+			MoveCompletedPath: "/completed/tv",
+			MaxConnections:    50,
+			LimitRatio:        1.5,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "/downloads", *options.DownloadLocation)
+		assert.True(t, *options.MoveCompleted)
+		assert.Equal(t, "/completed/tv", *options.MoveCompletedPath)
+		assert.Equal(t, 50, *options.MaxConnections)
+		assert.True(t, *options.StopAtRatio)
+		assert.Equal(t, float32(1.5), *options.StopRatio)
+	})
+}
+
+func Test_delugeLabel(t *testing.T) {
+	assert.Equal(t, "tv-hd", delugeLabel(" TV-HD "))
+	assert.Equal(t, "", delugeLabel(" "))
+}
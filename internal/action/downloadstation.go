@@ -0,0 +1,121 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"os"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/downloadstation"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+func (s *service) downloadStation(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.log.Debug().Msgf("action Download Station: %s", action.Name)
+
+	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get client with id %d", action.ClientID)
+	}
+	action.Client = client
+
+	if !client.Enabled {
+		return nil, errors.New("client %s %s not enabled", client.Type, client.Name)
+	}
+
+	ds := client.Client.(*downloadstation.Client)
+
+	rejections, err := s.downloadStationCheckRulesCanDownload(ctx, action, client, ds)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking Download Station client rules: %s", action.Name)
+	}
+
+	if len(rejections) > 0 {
+		return rejections, nil
+	}
+
+	opts := downloadstation.AddOptions{
+		Destination: action.SavePath,
+	}
+
+	var ids []string
+
+	if release.HasMagnetUri() {
+		ids, err = ds.AddURL(ctx, release.MagnetURI, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not add torrent from magnet %s to client: %s", release.MagnetURI, client.Name)
+		}
+	} else {
+		s.useFreeleechToken(ctx, action, release)
+
+		if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
+			return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
+		}
+
+		content, err := os.ReadFile(release.TorrentTmpFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read file: %s", release.TorrentTmpFile)
+		}
+
+		ids, err = ds.AddTorrent(ctx, release.TorrentName+".torrent", content, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Name)
+		}
+	}
+
+	s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", release.TorrentHash, client.Name)
+
+	if priority := downloadStationPriority(action.PriorityLayout); priority != downloadstation.PriorityNormal && len(ids) > 0 {
+		// the task is added already, failing to set the priority does not fail the action
+		if err := ds.SetPriority(ctx, ids, priority); err != nil {
+			s.log.Error().Err(err).Msgf("could not set priority %s for torrent with hash %s on client: '%s'", priority, release.TorrentHash, client.Name)
+		}
+	}
+
+	return nil, nil
+}
+
+// downloadStationPriority maps the queue priority of the action to the task priority
+func downloadStationPriority(layout domain.PriorityLayout) downloadstation.Priority {
+	switch layout {
+	case domain.PriorityLayoutMax:
+		return downloadstation.PriorityHigh
+	case domain.PriorityLayoutMin:
+		return downloadstation.PriorityLow
+	default:
+		return downloadstation.PriorityNormal
+	}
+}
+
+func (s *service) downloadStationCheckRulesCanDownload(ctx context.Context, action *domain.Action, client *domain.DownloadClient, ds *downloadstation.Client) ([]string, error) {
+	s.log.Trace().Msgf("action Download Station: %s check rules", action.Name)
+
+	// check for active downloads and other rules
+	if client.Settings.Rules.Enabled && !action.IgnoreRules {
+		tasks, err := ds.ListTasks(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch active downloads")
+		}
+
+		if client.Settings.Rules.MaxActiveDownloads > 0 {
+			active := 0
+			for _, task := range tasks {
+				if task.Active() {
+					active++
+				}
+			}
+
+			if active >= client.Settings.Rules.MaxActiveDownloads {
+				rejection := "max active downloads reached, skipping"
+
+				s.log.Debug().Msg(rejection)
+
+				return []string{rejection}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
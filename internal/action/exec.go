@@ -4,8 +4,11 @@
 package action
 
 import (
+	"bytes"
 	"context"
+	"os"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -14,41 +17,111 @@ import (
 	"github.com/mattn/go-shellwords"
 )
 
-func (s *service) execCmd(ctx context.Context, action *domain.Action, release domain.Release) error {
+const (
+	execWaitDelay = 5 * time.Second
+
+	// execOutputMaxLen is the max length of command output added to errors shown in the action status
+	execOutputMaxLen = 1000
+)
+
+// execCmd runs the command and parses optional json output which can reject the release
+// or set category and macros for the following actions
+func (s *service) execCmd(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action exec: %s release: %s", action.Name, release.TorrentName)
 
 	// check if program exists
 	cmd, err := exec.LookPath(action.ExecCmd)
 	if err != nil {
-		return errors.Wrap(err, "exec failed, could not find program: %s", action.ExecCmd)
+		return nil, errors.Wrap(err, "exec failed, could not find program: %s", action.ExecCmd)
 	}
 
 	p := shellwords.NewParser()
 	p.ParseBacktick = true
 	args, err := p.Parse(action.ExecArgs)
 	if err != nil {
-		return errors.Wrap(err, "could not parse exec args: %s", action.ExecArgs)
+		return nil, errors.Wrap(err, "could not parse exec args: %s", action.ExecArgs)
+	}
+
+	for _, env := range action.ExecEnv {
+		if !strings.Contains(env, "=") {
+			return nil, errors.New("invalid exec env: %s, expected KEY=value", env)
+		}
 	}
 
-	// we need to split on space into a string slice, so we can spread the args into exec
+	if action.ExecTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(action.ExecTimeout)*time.Second)
+		defer cancel()
+	}
 
 	start := time.Now()
 
 	// setup command and args
 	command := exec.CommandContext(ctx, cmd, args...)
+	command.Dir = action.ExecDir
+
+	// don't wait for child processes holding on to the output after the command was killed
+	command.WaitDelay = execWaitDelay
+
+	if len(action.ExecEnv) > 0 {
+		command.Env = append(os.Environ(), action.ExecEnv...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
 
 	// execute command
-	output, err := command.CombinedOutput()
-	if err != nil {
+	if err := command.Run(); err != nil {
+		s.log.Trace().Msgf("executed command: '%s' '%s'", stdout.String(), stderr.String())
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, errors.New("command timed out after %ds: %s args: %s%s", action.ExecTimeout, cmd, args, execOutput(&stdout, &stderr))
+		}
+
 		// everything other than exit 0 is considered an error
-		return errors.Wrap(err, "error executing command: %s args: %s", cmd, args)
+		return nil, errors.Wrap(err, "error executing command: %s args: %s%s", cmd, args, execOutput(&stdout, &stderr))
 	}
 
-	s.log.Trace().Msgf("executed command: '%s'", string(output))
+	s.log.Trace().Msgf("executed command: '%s' '%s'", stdout.String(), stderr.String())
 
 	duration := time.Since(start)
 
 	s.log.Info().Msgf("executed command: '%s', args: '%s' %s,%s, total time %v", cmd, args, release.TorrentName, release.Indexer.Name, duration)
 
-	return nil
+	result, err := domain.ParseExecResult(stdout.Bytes())
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("could not parse exec output for action: %s", action.Name)
+		return nil, nil
+	}
+
+	if result == nil {
+		return nil, nil
+	}
+
+	if result.Reject {
+		return []string{result.RejectionReason()}, nil
+	}
+
+	result.Apply(release)
+
+	return nil, nil
+}
+
+// execOutput returns the end of stderr, or stdout when nothing was written to stderr, to debug failed commands
+func execOutput(stdout, stderr *bytes.Buffer) string {
+	output := strings.TrimSpace(stderr.String())
+	if output == "" {
+		output = strings.TrimSpace(stdout.String())
+	}
+
+	if output == "" {
+		return ""
+	}
+
+	if len(output) > execOutputMaxLen {
+		output = "..." + output[len(output)-execOutputMaxLen:]
+	}
+
+	return ", output: " + output
 }
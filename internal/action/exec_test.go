@@ -6,6 +6,7 @@ package action
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -104,7 +105,60 @@ func Test_service_execCmd(t *testing.T) {
 				clientSvc: nil,
 				bus:       nil,
 			}
-			s.execCmd(context.TODO(), tt.args.action, tt.args.release)
+			s.execCmd(context.TODO(), tt.args.action, &tt.args.release)
 		})
 	}
 }
+
+func Test_service_execCmd_options(t *testing.T) {
+	s := &service{log: logger.Mock().With().Logger()}
+	release := &domain.Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP"}
+
+	t.Run("env_and_dir", func(t *testing.T) {
+		dir := t.TempDir()
+
+		action := &domain.Action{
+			Name:     "env",
+			ExecCmd:  "sh",
+			ExecArgs: `-c 'test "$(pwd)" = "$EXPECTED_DIR" && test "$RELEASE" = "Show.Name.S01E01.1080p.WEB-DL.H264-GRP"'`,
+			ExecEnv:  []string{"EXPECTED_DIR=" + dir, "RELEASE={{ .TorrentName }}"},
+			ExecDir:  dir,
+		}
+		assert.NoError(t, action.ParseMacros(release))
+
+		_, err := s.execCmd(context.Background(), action, release)
+		assert.NoError(t, err)
+	})
+
+	t.Run("output_on_failure", func(t *testing.T) {
+		action := &domain.Action{
+			Name:     "fail",
+			ExecCmd:  "sh",
+			ExecArgs: `-c 'echo "disk full" >&2; exit 1'`,
+		}
+
+		_, err := s.execCmd(context.Background(), action, release)
+		assert.ErrorContains(t, err, "output: disk full")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		action := &domain.Action{
+			Name:        "timeout",
+			ExecCmd:     "sleep",
+			ExecArgs:    "5",
+			ExecTimeout: 1,
+		}
+
+		start := time.Now()
+		_, err := s.execCmd(context.Background(), action, release)
+		assert.ErrorContains(t, err, "timed out after 1s")
+		assert.Less(t, time.Since(start), 4*time.Second)
+	})
+
+	t.Run("invalid_env", func(t *testing.T) {
+		action := &domain.Action{Name: "env", ExecCmd: "true", ExecEnv: []string{"INVALID"}}
+
+		_, err := s.execCmd(context.Background(), action, release)
+		assert.ErrorContains(t, err, "invalid exec env")
+	})
+}
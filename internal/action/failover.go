@@ -0,0 +1,42 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// failover runs the action with the failover clients in order after the client of the action was unreachable
+// or rejected the push, until a client accepts it. The action is left with the client that accepted it.
+// The rejections or error of the last client are returned when none accepted it.
+func (s *service) failover(ctx context.Context, action *domain.Action, release *domain.Release, rejections []string, err error) ([]string, error) {
+	tried := map[int32]struct{}{action.ClientID: {}}
+
+	for _, clientID := range action.FailoverClientIDs {
+		if _, ok := tried[clientID]; ok {
+			continue
+		}
+		tried[clientID] = struct{}{}
+
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("action %s: client %d failed for %s, trying failover client %d", action.Name, action.ClientID, release.TorrentName, clientID)
+		} else {
+			s.log.Warn().Msgf("action %s: client %d rejected %s: %s, trying failover client %d", action.Name, action.ClientID, release.TorrentName, strings.Join(rejections, ", "), clientID)
+		}
+
+		action.ClientID = clientID
+		action.Client = nil
+
+		rejections, err = s.runActionType(ctx, action, release)
+		if err == nil && len(rejections) == 0 {
+			s.log.Info().Msgf("action %s: failover client %d accepted %s", action.Name, clientID, release.TorrentName)
+			return rejections, nil
+		}
+	}
+
+	return rejections, err
+}
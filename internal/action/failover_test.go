@@ -0,0 +1,75 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sabnzbd"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDownloadClientService only implements GetClient, calling anything else panics
+type mockDownloadClientService struct {
+	download_client.Service
+	clients map[int32]*domain.DownloadClient
+	gets    []int32
+}
+
+func (s *mockDownloadClientService) GetClient(ctx context.Context, clientId int32) (*domain.DownloadClient, error) {
+	s.gets = append(s.gets, clientId)
+
+	client, ok := s.clients[clientId]
+	if !ok {
+		return nil, errors.New("client %d not found", clientId)
+	}
+	return client, nil
+}
+
+func Test_service_failover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":true,"nzo_ids":["SABnzbd_nzo_1"]}`))
+	}))
+	defer srv.Close()
+
+	clientSvc := &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+		1: {ID: 1, Name: "sab-primary", Type: domain.DownloadClientTypeSabnzbd, Enabled: false},
+		3: {ID: 3, Name: "sab-backup", Type: domain.DownloadClientTypeSabnzbd, Enabled: true, Client: sabnzbd.New(sabnzbd.Options{Addr: srv.URL})},
+	}}
+
+	s := &service{log: zerolog.Nop(), clientSvc: clientSvc}
+	release := &domain.Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", Protocol: domain.ReleaseProtocolNzb, DownloadURL: srv.URL + "/nzb"}
+
+	t.Run("next_client_accepts", func(t *testing.T) {
+		clientSvc.gets = nil
+		action := &domain.Action{Name: "sab", Type: domain.ActionTypeSabnzbd, ClientID: 1, FailoverClientIDs: []int32{1, 2, 3}}
+
+		rejections, err := s.runActionType(context.Background(), action, release)
+		assert.Error(t, err)
+
+		rejections, err = s.failover(context.Background(), action, release, rejections, err)
+		assert.NoError(t, err)
+		assert.Empty(t, rejections)
+
+		// the primary client is not tried again and the unreachable client is skipped
+		assert.Equal(t, []int32{1, 2, 3}, clientSvc.gets)
+		assert.Equal(t, int32(3), action.ClientID)
+		assert.Equal(t, "sab-backup", action.Client.Name)
+	})
+
+	t.Run("none_accepts", func(t *testing.T) {
+		action := &domain.Action{Name: "sab", Type: domain.ActionTypeSabnzbd, ClientID: 3, FailoverClientIDs: []int32{1, 2}}
+
+		_, err := s.failover(context.Background(), action, release, []string{"max active downloads reached, skipping"}, nil)
+		assert.ErrorContains(t, err, "client 2 not found")
+	})
+}
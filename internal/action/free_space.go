@@ -0,0 +1,128 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/dustin/go-humanize"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+// clientFreeSpaceFunc returns the free space in bytes reported by the client
+type clientFreeSpaceFunc func(ctx context.Context) (uint64, error)
+
+// checkFreeSpace rejects the release when adding it leaves less free space than the minimum of the client rules.
+// The free space of the configured path is checked when set, otherwise it's queried from the client.
+func (s *service) checkFreeSpace(ctx context.Context, client *domain.DownloadClient, release *domain.Release, clientFreeSpace clientFreeSpaceFunc) ([]string, error) {
+	rules := client.Settings.Rules
+	if rules.MinFreeSpace <= 0 {
+		return nil, nil
+	}
+
+	var (
+		free uint64
+		err  error
+	)
+
+	if rules.FreeSpacePath != "" {
+		free, err = diskFreeSpace(rules.FreeSpacePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get free space of path: %s", rules.FreeSpacePath)
+		}
+	} else {
+		free, err = clientFreeSpace(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get free space from client: %s", client.Name)
+		}
+	}
+
+	if rejection := freeSpaceRejection(free, release.Size, uint64(rules.MinFreeSpace)*humanize.GByte); rejection != "" {
+		s.log.Debug().Msgf("%s for release: %s", rejection, release.TorrentName)
+		return []string{rejection}, nil
+	}
+
+	s.log.Trace().Msgf("free space %s on client: %s", humanize.Bytes(free), client.Name)
+
+	return nil, nil
+}
+
+// freeSpaceRejection returns the rejection when the release of size drops the free space below the minimum
+func freeSpaceRejection(free, size, minimum uint64) string {
+	if free >= minimum && free-minimum >= size {
+		return ""
+	}
+
+	return fmt.Sprintf("not enough free space: %s free, release %s, minimum %s", humanize.Bytes(free), humanize.Bytes(size), humanize.Bytes(minimum))
+}
+
+// qbittorrentFreeSpace returns the free space of the default save path from the sync/maindata server state
+func qbittorrentFreeSpace(client *domain.DownloadClient) clientFreeSpaceFunc {
+	return func(ctx context.Context) (uint64, error) {
+		api, err := newQbittorrentWebAPI(ctx, client)
+		if err != nil {
+			return 0, err
+		}
+
+		body, err := api.post(ctx, "sync/maindata", nil)
+		if err != nil {
+			return 0, err
+		}
+
+		var data struct {
+			ServerState struct {
+				FreeSpaceOnDisk uint64 `json:"free_space_on_disk"`
+			} `json:"server_state"`
+		}
+
+		if err := json.Unmarshal(body, &data); err != nil {
+			return 0, errors.Wrap(err, "could not unmarshal maindata")
+		}
+
+		return data.ServerState.FreeSpaceOnDisk, nil
+	}
+}
+
+// delugeFreeSpace returns the free space of path, the default download location when empty
+func delugeFreeSpace(del deluge.DelugeClient, path string) clientFreeSpaceFunc {
+	return func(ctx context.Context) (uint64, error) {
+		free, err := del.GetFreeSpace(ctx, path)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(max(free, 0)), nil
+	}
+}
+
+// transmissionFreeSpace returns the free space of path, the default download dir when empty
+func transmissionFreeSpace(tbt *transmissionrpc.Client, path string) clientFreeSpaceFunc {
+	return func(ctx context.Context) (uint64, error) {
+		if path == "" {
+			session, err := tbt.SessionArgumentsGet(ctx, []string{"download-dir"})
+			if err != nil {
+				return 0, errors.Wrap(err, "could not get session download dir")
+			}
+
+			if session.DownloadDir == nil {
+				return 0, errors.New("no session download dir")
+			}
+
+			path = *session.DownloadDir
+		}
+
+		free, _, err := tbt.FreeSpace(ctx, path)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(free.Byte()), nil
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_freeSpaceRejection(t *testing.T) {
+	tests := []struct {
+		name    string
+		free    uint64
+		size    uint64
+		minimum uint64
+		reject  bool
+	}{
+		{name: "enough", free: 100 * humanize.GByte, size: 10 * humanize.GByte, minimum: 50 * humanize.GByte},
+		{name: "exactly_minimum_left", free: 60 * humanize.GByte, size: 10 * humanize.GByte, minimum: 50 * humanize.GByte},
+		{name: "release_too_big", free: 55 * humanize.GByte, size: 10 * humanize.GByte, minimum: 50 * humanize.GByte, reject: true},
+		{name: "already_below_minimum", free: 40 * humanize.GByte, size: 0, minimum: 50 * humanize.GByte, reject: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejection := freeSpaceRejection(tt.free, tt.size, tt.minimum)
+			assert.Equal(t, tt.reject, rejection != "", rejection)
+		})
+	}
+
+	assert.Equal(t, "not enough free space: 55 GB free, release 10 GB, minimum 50 GB", freeSpaceRejection(55*humanize.GByte, 10*humanize.GByte, 50*humanize.GByte))
+}
+
+func Test_service_checkFreeSpace(t *testing.T) {
+	s := &service{log: zerolog.Nop()}
+	release := &domain.Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", Size: 2 * humanize.GByte}
+
+	clientFree := func(free uint64) clientFreeSpaceFunc {
+		return func(ctx context.Context) (uint64, error) {
+			return free, nil
+		}
+	}
+
+	client := &domain.DownloadClient{Name: "qbit"}
+
+	rejections, err := s.checkFreeSpace(context.Background(), client, release, clientFree(0))
+	assert.NoError(t, err)
+	assert.Empty(t, rejections, "no minimum set")
+
+	client.Settings.Rules.MinFreeSpace = 10
+
+	rejections, err = s.checkFreeSpace(context.Background(), client, release, clientFree(20*humanize.GByte))
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+
+	rejections, err = s.checkFreeSpace(context.Background(), client, release, clientFree(11*humanize.GByte))
+	assert.NoError(t, err)
+	assert.Len(t, rejections, 1)
+
+	// the path is checked instead of the client
+	client.Settings.Rules.FreeSpacePath = t.TempDir()
+	client.Settings.Rules.MinFreeSpace = 1 << 40
+
+	rejections, err = s.checkFreeSpace(context.Background(), client, release, nil)
+	assert.NoError(t, err)
+	assert.Len(t, rejections, 1)
+}
+
+func Test_qbittorrentFreeSpace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/sync/maindata" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"rid":1,"full_update":true,"server_state":{"free_space_on_disk":123456789}}`))
+	}))
+	defer srv.Close()
+
+	client := &domain.DownloadClient{Type: domain.DownloadClientTypeQbittorrent, Host: srv.URL}
+
+	free, err := qbittorrentFreeSpace(client)(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(123456789), free)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+)
+
+// useFreeleechToken downloads the torrent with the freeleech token url if the action has tokens enabled,
+// the release is above the min size and the indexer has budget left this month.
+// Clients call it after their rule checks passed, right before the push, so no token is spent on rejected releases.
+// Errors are logged and the release is pushed without a token instead.
+func (s *service) useFreeleechToken(ctx context.Context, action *domain.Action, release *domain.Release) {
+	if !action.FreeleechToken || release.FreeleechTokenUsed || release.FreeleechTokenURL == "" {
+		return
+	}
+
+	l := s.log.With().Str("action", action.Name).Str("indexer", release.Indexer.Identifier).Logger()
+
+	if release.Freeleech {
+		l.Debug().Msgf("release is already freeleech, skip token: %s", release.TorrentName)
+		return
+	}
+
+	if action.FreeleechTokenMinSize != "" {
+		minSize, err := humanize.ParseBytes(action.FreeleechTokenMinSize)
+		if err != nil {
+			l.Error().Err(err).Msgf("could not parse freeleech token min size: %s", action.FreeleechTokenMinSize)
+			return
+		}
+
+		// size is not part of every announce, the torrent file has it
+		if release.Size == 0 {
+			if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
+				l.Error().Err(err).Msgf("could not download torrent file to check size for freeleech token: %s", release.TorrentName)
+				return
+			}
+		}
+
+		if release.Size < minSize {
+			l.Debug().Msgf("release size %s below freeleech token min size %s, skip token: %s", humanize.Bytes(release.Size), action.FreeleechTokenMinSize, release.TorrentName)
+			return
+		}
+	}
+
+	// only one token at a time so concurrent releases can't go over budget
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+
+	now := time.Now()
+
+	budget, err := s.tokenRepo.GetBudget(ctx, release.Indexer.Identifier, domain.FreeleechTokenMonthStart(now))
+	if err != nil {
+		l.Error().Err(err).Msg("could not get freeleech token budget")
+		return
+	}
+
+	if budget == nil || budget.MonthlyBudget <= 0 {
+		l.Debug().Msgf("no freeleech token budget set for indexer, skip token: %s", release.TorrentName)
+		return
+	}
+
+	if budget.Remaining() == 0 {
+		l.Info().Msgf("freeleech token budget of %d used up this month, skip token: %s", budget.MonthlyBudget, release.TorrentName)
+		return
+	}
+
+	if err := s.downloadWithFreeleechToken(ctx, release); err != nil {
+		l.Warn().Err(err).Msgf("could not use freeleech token, continue without: %s", release.TorrentName)
+		return
+	}
+
+	usage := domain.FreeleechTokenUsage{
+		Indexer:     release.Indexer.Identifier,
+		TorrentName: release.TorrentName,
+		FilterID:    release.FilterID,
+		Size:        release.Size,
+		UsedAt:      now,
+	}
+
+	if err := s.tokenRepo.StoreUsage(ctx, usage); err != nil {
+		l.Error().Err(err).Msg("could not store freeleech token usage")
+	}
+
+	l.Info().Msgf("used freeleech token (%d/%d this month): %s", budget.Used+1, budget.MonthlyBudget, release.TorrentName)
+}
+
+// downloadWithFreeleechToken downloads the torrent again from the token url, the tracker spends the token on download
+func (s *service) downloadWithFreeleechToken(ctx context.Context, release *domain.Release) error {
+	downloadURL := release.DownloadURL
+	tmpFile := release.TorrentTmpFile
+	rawBytes := release.TorrentDataRawBytes
+
+	release.DownloadURL = release.FreeleechTokenURL
+	release.TorrentTmpFile = ""
+	release.TorrentDataRawBytes = nil
+
+	if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
+		// restore so the release is pushed with the regular torrent
+		release.DownloadURL = downloadURL
+		release.TorrentTmpFile = tmpFile
+		release.TorrentDataRawBytes = rawBytes
+
+		return errors.Wrap(err, "could not download torrent with freeleech token")
+	}
+
+	// the torrent downloaded without token is not needed anymore
+	if tmpFile != "" {
+		os.Remove(tmpFile)
+	}
+
+	release.FreeleechTokenUsed = true
+
+	if rawBytes != nil {
+		return release.OpenTorrentFile()
+	}
+
+	return nil
+}
+
+func (s *service) ListFreeleechTokenBudgets(ctx context.Context) ([]domain.FreeleechTokenBudget, error) {
+	return s.tokenRepo.ListBudgets(ctx, domain.FreeleechTokenMonthStart(time.Now()))
+}
+
+func (s *service) UpdateFreeleechTokenBudget(ctx context.Context, budget domain.FreeleechTokenBudget) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.UpdateBudget(ctx, budget)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/releasedownload"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTokenRepo keeps the usages in memory, calling anything else panics
+type mockTokenRepo struct {
+	domain.FreeleechTokenRepo
+
+	mu      sync.Mutex
+	budget  int
+	usages  []domain.FreeleechTokenUsage
+	initial int
+}
+
+func (r *mockTokenRepo) GetBudget(ctx context.Context, indexer string, since time.Time) (*domain.FreeleechTokenBudget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &domain.FreeleechTokenBudget{Indexer: indexer, MonthlyBudget: r.budget, Used: r.initial + len(r.usages)}, nil
+}
+
+func (r *mockTokenRepo) StoreUsage(ctx context.Context, usage domain.FreeleechTokenUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.usages = append(r.usages, usage)
+	return nil
+}
+
+// mockIndexerRepo only implements FindByID for the download service
+type mockIndexerRepo struct {
+	domain.IndexerRepo
+}
+
+func (r *mockIndexerRepo) FindByID(ctx context.Context, id int) (*domain.Indexer, error) {
+	return &domain.Indexer{ID: int64(id), Identifier: "mock"}, nil
+}
+
+func testTorrent(t *testing.T, size int64) []byte {
+	t.Helper()
+
+	info, err := bencode.Marshal(metainfo.Info{
+		Name:        "Artist - Album (2024) [FLAC]",
+		Length:      size,
+		PieceLength: 1 << 20,
+		Pieces:      make([]byte, 20),
+	})
+	assert.NoError(t, err)
+
+	data, err := bencode.Marshal(metainfo.MetaInfo{InfoBytes: info})
+	assert.NoError(t, err)
+
+	return data
+}
+
+// tokenTracker serves the torrent and counts the downloads from the token url
+type tokenTracker struct {
+	*httptest.Server
+	tokens atomic.Int32
+}
+
+func newTokenTracker(t *testing.T) *tokenTracker {
+	torrent := testTorrent(t, 2<<30)
+
+	tracker := &tokenTracker{}
+	tracker.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("usetoken") == "1" {
+			tracker.tokens.Add(1)
+		}
+		w.Write(torrent)
+	}))
+	t.Cleanup(tracker.Close)
+
+	return tracker
+}
+
+func newTokenRelease(tracker *tokenTracker, id int) *domain.Release {
+	release := domain.NewRelease(domain.IndexerMinimal{ID: 1, Name: "Mock", Identifier: "mock"})
+	release.TorrentName = fmt.Sprintf("Artist - Album %d (2024) [FLAC]", id)
+	release.Protocol = domain.ReleaseProtocolTorrent
+	release.DownloadURL = fmt.Sprintf("%s/torrents.php?action=download&id=%d", tracker.URL, id)
+	release.FreeleechTokenURL = release.DownloadURL + "&usetoken=1"
+	return release
+}
+
+func newTokenService(repo *mockTokenRepo) *service {
+	return &service{
+		log:         zerolog.Nop(),
+		tokenRepo:   repo,
+		downloadSvc: releasedownload.NewDownloadService(logger.Mock(), nil, &mockIndexerRepo{}, nil),
+	}
+}
+
+func TestService_useFreeleechToken(t *testing.T) {
+	action := &domain.Action{Name: "qbit", FreeleechToken: true}
+
+	t.Run("spends_token", func(t *testing.T) {
+		tracker := newTokenTracker(t)
+		repo := &mockTokenRepo{budget: 2}
+		s := newTokenService(repo)
+
+		release := newTokenRelease(tracker, 1)
+		s.useFreeleechToken(context.Background(), action, release)
+		defer os.Remove(release.TorrentTmpFile)
+
+		assert.True(t, release.FreeleechTokenUsed)
+		assert.Equal(t, release.FreeleechTokenURL, release.DownloadURL)
+		assert.Equal(t, int32(1), tracker.tokens.Load())
+		assert.Len(t, repo.usages, 1)
+
+		// a second action for the same release does not spend another token
+		s.useFreeleechToken(context.Background(), action, release)
+		assert.Equal(t, int32(1), tracker.tokens.Load())
+	})
+
+	t.Run("budget_exhausted", func(t *testing.T) {
+		tracker := newTokenTracker(t)
+		repo := &mockTokenRepo{budget: 2, initial: 2}
+		s := newTokenService(repo)
+
+		release := newTokenRelease(tracker, 1)
+		s.useFreeleechToken(context.Background(), action, release)
+
+		assert.False(t, release.FreeleechTokenUsed)
+		assert.NotEqual(t, release.FreeleechTokenURL, release.DownloadURL)
+		assert.Equal(t, int32(0), tracker.tokens.Load())
+		assert.Len(t, repo.usages, 0)
+	})
+
+	t.Run("below_min_size", func(t *testing.T) {
+		tracker := newTokenTracker(t)
+		repo := &mockTokenRepo{budget: 2}
+		s := newTokenService(repo)
+
+		minSize := &domain.Action{Name: "qbit", FreeleechToken: true, FreeleechTokenMinSize: "10GB"}
+
+		// the size is checked with the regular torrent when the announce has none
+		release := newTokenRelease(tracker, 1)
+		s.useFreeleechToken(context.Background(), minSize, release)
+		defer os.Remove(release.TorrentTmpFile)
+
+		assert.False(t, release.FreeleechTokenUsed)
+		assert.Equal(t, uint64(2<<30), release.Size)
+		assert.Equal(t, int32(0), tracker.tokens.Load())
+		assert.Len(t, repo.usages, 0)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		tracker := newTokenTracker(t)
+		repo := &mockTokenRepo{budget: 3}
+		s := newTokenService(repo)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+
+				release := newTokenRelease(tracker, id)
+				s.useFreeleechToken(context.Background(), action, release)
+				os.Remove(release.TorrentTmpFile)
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(3), tracker.tokens.Load())
+		assert.Len(t, repo.usages, 3)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/mylar"
+)
+
+// comicIssueRegex splits a comic release like "Series.Name.023.2024.Digital-GRP" into series and issue number
+var comicIssueRegex = regexp.MustCompile(`^(.*?)\s+#?(\d{1,4}(?:\.\d+)?)(?:\s|$)`)
+
+// comicYearRegex finds the year of a comic release
+var comicYearRegex = regexp.MustCompile(`\((\d{4})\)|\s(\d{4})(?:\s|$)`)
+
+// parseComicIssue returns the series, issue number and year of a comic release
+func parseComicIssue(title string) (series string, issue string, year string) {
+	// scene style names use dots as separators, keep them in names with spaces for issues like "#0.5"
+	if !strings.Contains(title, " ") {
+		title = strings.NewReplacer(".", " ", "_", " ").Replace(title)
+	}
+
+	m := comicIssueRegex.FindStringSubmatch(title)
+	if m == nil {
+		return "", "", ""
+	}
+
+	series = strings.TrimSpace(m[1])
+	issue = normalizeIssueNumber(m[2])
+
+	if y := comicYearRegex.FindStringSubmatch(title[len(m[0])-1:]); y != nil {
+		year = y[1] + y[2]
+	}
+
+	return series, issue, year
+}
+
+// normalizeIssueNumber drops leading zeros so "023" matches issue "23"
+func normalizeIssueNumber(number string) string {
+	whole, fraction, found := strings.Cut(number, ".")
+
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+
+	if found {
+		if f, err := strconv.Atoi(fraction); err == nil && f == 0 {
+			return whole
+		}
+		return whole + "." + fraction
+	}
+
+	return whole
+}
+
+// normalizeComicName drops case and separators so "Series-Name" matches "Series Name"
+func normalizeComicName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// mylar queues the issue of the release in mylar. Mylar can't take a release like the arrs,
+// so the matching issue on the watchlist is marked wanted and mylar grabs it from its own providers.
+func (s *service) mylar(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+	s.log.Trace().Msg("action MYLAR")
+
+	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get client with id %d", action.ClientID)
+	}
+	action.Client = client
+
+	if !client.Enabled {
+		return nil, errors.New("client %s %s not enabled", client.Type, client.Name)
+	}
+
+	arr := client.Client.(mylar.Client)
+
+	series, number, year := parseComicIssue(release.TorrentName)
+	if series == "" {
+		return []string{"could not parse series and issue of release"}, nil
+	}
+
+	comics, err := arr.GetIndex(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "mylar: could not get watchlist")
+	}
+
+	var comic *mylar.Comic
+	for i := range comics {
+		if normalizeComicName(comics[i].Name) != normalizeComicName(series) {
+			continue
+		}
+
+		// volumes of a series share the name, prefer the one matching the year of the release
+		if comic == nil || (year != "" && comics[i].Year == year) {
+			comic = &comics[i]
+		}
+	}
+
+	if comic == nil {
+		return []string{"series not on watchlist: " + series}, nil
+	}
+
+	issues, err := arr.GetComic(ctx, comic.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "mylar: could not get issues of %s", comic.Name)
+	}
+
+	var issue *mylar.Issue
+	for i := range issues {
+		if normalizeIssueNumber(issues[i].Number) == number {
+			issue = &issues[i]
+			break
+		}
+	}
+
+	if issue == nil {
+		return []string{"issue not found: " + comic.Name + " #" + number}, nil
+	}
+
+	switch issue.Status {
+	case "Downloaded", "Archived":
+		return []string{"issue already " + strings.ToLower(issue.Status) + ": " + comic.Name + " #" + number}, nil
+	}
+
+	if err := arr.QueueIssue(ctx, issue.ID); err != nil {
+		return nil, errors.Wrap(err, "mylar: failed to queue issue: %s #%s", comic.Name, number)
+	}
+
+	s.log.Debug().Msgf("mylar: successfully queued issue: %s #%s for release %v to %v", comic.Name, number, release.TorrentName, client.Host)
+
+	return nil, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/mylar"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseComicIssue(t *testing.T) {
+	tests := []struct {
+		title  string
+		series string
+		issue  string
+		year   string
+	}{
+		{title: "Saga.067.2024.Digital.Zone-Empire", series: "Saga", issue: "67", year: "2024"},
+		{title: "The Amazing Spider-Man #001 (2022) (Digital) (Zone-Empire)", series: "The Amazing Spider-Man", issue: "1", year: "2022"},
+		{title: "Batman_135_2023", series: "Batman", issue: "135", year: "2023"},
+		{title: "Invincible 000.5 (2005)", series: "Invincible", issue: "0.5", year: "2005"},
+		{title: "Some.Comic.TPB.Digital-GRP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			series, issue, year := parseComicIssue(tt.title)
+			assert.Equal(t, tt.series, series)
+			assert.Equal(t, tt.issue, issue)
+			assert.Equal(t, tt.year, year)
+		})
+	}
+}
+
+func Test_service_mylar(t *testing.T) {
+	var queued []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("apikey") != "key" {
+			_, _ = w.Write([]byte(`{"success":false,"error":{"code":401,"message":"Incorrect API key"}}`))
+			return
+		}
+
+		switch q.Get("cmd") {
+		case "getIndex":
+			_, _ = w.Write([]byte(`{"success":true,"data":[{"id":"1","name":"Saga","year":"2012"},{"id":"2","name":"Batman","year":"2016"},{"id":"3","name":"Batman","year":"2023"}]}`))
+		case "getComic":
+			switch q.Get("id") {
+			case "1":
+				_, _ = w.Write([]byte(`{"success":true,"data":{"issues":[{"id":"101","number":"66","status":"Downloaded"},{"id":"102","number":"67","status":"Skipped"}]}}`))
+			case "3":
+				_, _ = w.Write([]byte(`{"success":true,"data":{"issues":[{"id":"301","number":"135","status":"Wanted"}]}}`))
+			default:
+				_, _ = w.Write([]byte(`{"success":true,"data":{"issues":[]}}`))
+			}
+		case "queueIssue":
+			queued = append(queued, q.Get("id"))
+			_, _ = w.Write([]byte(`{"success":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{log: zerolog.Nop(), clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+		1: {ID: 1, Name: "mylar", Type: domain.DownloadClientTypeMylar, Enabled: true, Client: mylar.New(mylar.Config{Hostname: srv.URL, APIKey: "key"})},
+		2: {ID: 2, Name: "mylar-bad-key", Type: domain.DownloadClientTypeMylar, Enabled: true, Client: mylar.New(mylar.Config{Hostname: srv.URL, APIKey: "bad"})},
+		3: {ID: 3, Name: "mylar-disabled", Type: domain.DownloadClientTypeMylar, Enabled: false},
+	}}}
+
+	tests := []struct {
+		name       string
+		clientID   int32
+		title      string
+		rejections []string
+		queued     []string
+		wantErr    string
+	}{
+		{name: "queued", clientID: 1, title: "Saga.067.2024.Digital.Zone-Empire", queued: []string{"102"}},
+		{name: "volume_by_year", clientID: 1, title: "Batman.135.2023.Digital-GRP", queued: []string{"301"}},
+		{name: "downloaded", clientID: 1, title: "Saga.066.2024.Digital.Zone-Empire", rejections: []string{"issue already downloaded: Saga #66"}},
+		{name: "issue_not_found", clientID: 1, title: "Saga.068.2024.Digital.Zone-Empire", rejections: []string{"issue not found: Saga #68"}},
+		{name: "not_on_watchlist", clientID: 1, title: "Monstress.050.2024.Digital-GRP", rejections: []string{"series not on watchlist: Monstress"}},
+		{name: "not_parsed", clientID: 1, title: "Saga.Compendium.Digital-GRP", rejections: []string{"could not parse series and issue of release"}},
+		{name: "bad_key", clientID: 2, title: "Saga.067.2024.Digital.Zone-Empire", wantErr: "mylar: could not get watchlist: could not get watchlist: mylar getIndex failed: Incorrect API key"},
+		{name: "disabled", clientID: 3, title: "Saga.067.2024.Digital.Zone-Empire", wantErr: "client MYLAR mylar-disabled not enabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queued = nil
+			action := &domain.Action{Name: "mylar", Type: domain.ActionTypeMylar, ClientID: tt.clientID}
+
+			rejections, err := s.mylar(context.Background(), action, domain.Release{TorrentName: tt.title})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.rejections, rejections)
+			assert.Equal(t, tt.queued, queued)
+		})
+	}
+}
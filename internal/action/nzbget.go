@@ -0,0 +1,101 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/nzbget"
+)
+
+func (s *service) nzbget(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+	s.log.Trace().Msg("action NZBGet")
+
+	if release.Protocol != domain.ReleaseProtocolNzb {
+		return nil, errors.New("action type: %s invalid protocol: %s", action.Type, release.Protocol)
+	}
+
+	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get client with id %d", action.ClientID)
+	}
+	action.Client = client
+
+	if !client.Enabled {
+		return nil, errors.New("client %s %s not enabled", client.Type, client.Name)
+	}
+
+	nzb := client.Client.(*nzbget.Client)
+
+	rejections, err := s.nzbgetCheckRulesCanDownload(ctx, action, client, nzb)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking NZBGet client rules: %s", action.Name)
+	}
+
+	if len(rejections) > 0 {
+		return rejections, nil
+	}
+
+	// NZBGet downloads the nzb itself
+	id, err := nzb.Append(ctx, nzbget.AppendRequest{
+		Name:      release.TorrentName + ".nzb",
+		Content:   release.DownloadURL,
+		Category:  action.Category,
+		Priority:  nzbgetPriority(action.PriorityLayout),
+		AddPaused: action.Paused,
+		DupeKey:   action.DupeKey,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not add nzb to nzbget")
+	}
+
+	s.log.Info().Msgf("nzb successfully added to client: '%s' with id %d", client.Name, id)
+
+	return nil, nil
+}
+
+// nzbgetPriority maps the queue priority of the action to the nzb priority
+func nzbgetPriority(layout domain.PriorityLayout) nzbget.Priority {
+	switch layout {
+	case domain.PriorityLayoutMax:
+		return nzbget.PriorityHigh
+	case domain.PriorityLayoutMin:
+		return nzbget.PriorityLow
+	default:
+		return nzbget.PriorityNormal
+	}
+}
+
+func (s *service) nzbgetCheckRulesCanDownload(ctx context.Context, action *domain.Action, client *domain.DownloadClient, nzb *nzbget.Client) ([]string, error) {
+	s.log.Trace().Msgf("action NZBGet: %s check rules", action.Name)
+
+	// check for active downloads and other rules
+	if client.Settings.Rules.Enabled && !action.IgnoreRules {
+		groups, err := nzb.ListGroups(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch active downloads")
+		}
+
+		if client.Settings.Rules.MaxActiveDownloads > 0 {
+			active := 0
+			for _, group := range groups {
+				if group.Active() {
+					active++
+				}
+			}
+
+			if active >= client.Settings.Rules.MaxActiveDownloads {
+				rejection := "max active downloads reached, skipping"
+
+				s.log.Debug().Msg(rejection)
+
+				return []string{rejection}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
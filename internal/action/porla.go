@@ -15,7 +15,7 @@ import (
 	"github.com/autobrr/autobrr/pkg/porla"
 )
 
-func (s *service) porla(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) porla(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action Porla: %s", action.Name)
 
 	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
@@ -75,7 +75,9 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 
 		return nil, nil
 	} else {
-		if err := s.downloadSvc.DownloadRelease(ctx, &release); err != nil {
+		s.useFreeleechToken(ctx, action, release)
+
+		if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
 			return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
 		}
 
@@ -14,7 +14,7 @@ import (
 	"github.com/autobrr/go-qbittorrent"
 )
 
-func (s *service) qbittorrent(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) qbittorrent(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action qBittorrent: %s", action.Name)
 
 	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
@@ -39,8 +39,19 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		if len(rejections) > 0 {
 			return rejections, nil
 		}
+
+		rejections, err = s.checkFreeSpace(ctx, client, release, qbittorrentFreeSpace(client))
+		if err != nil {
+			return nil, errors.Wrap(err, "error checking free space: %s", action.Name)
+		}
+
+		if len(rejections) > 0 {
+			return rejections, nil
+		}
 	}
 
+	s.replaceOriginals(ctx, client, release, qbittorrentRemove(qbtClient))
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareQbitOptions(action)
 		if err != nil {
@@ -55,10 +66,17 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 
 		s.log.Info().Msgf("torrent from magnet successfully added to client: '%s'", client.Name)
 
+		if release.TorrentHash != "" {
+			s.qbittorrentSetShareLimits(ctx, action, client, release.TorrentHash)
+			s.verifySize(action, *release, qbittorrentSize(qbtClient, release.TorrentHash))
+		}
+
 		return nil, nil
 	}
 
-	if err := s.downloadSvc.DownloadRelease(ctx, &release); err != nil {
+	s.useFreeleechToken(ctx, action, release)
+
+	if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
 		return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
 	}
 
@@ -107,20 +125,16 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		default:
 			s.log.Warn().Msgf("unknown priority setting: '%v', no priority changes made", action.PriorityLayout)
 		}
+
+		s.qbittorrentSetShareLimits(ctx, action, client, release.TorrentHash)
 	} else {
 		// add anyway if no hash
-		s.log.Trace().Msg("no torrent hash provided, skipping priority setting")
+		s.log.Trace().Msg("no torrent hash provided, skipping priority and share limits setting")
 	}
 
 	if !action.Paused && !action.ReAnnounceSkip && release.TorrentHash != "" {
-		opts := qbittorrent.ReannounceOptions{
-			Interval:        int(action.ReAnnounceInterval),
-			MaxAttempts:     int(action.ReAnnounceMaxAttempts),
-			DeleteOnFailure: action.ReAnnounceDelete,
-		}
-
-		if err := qbtClient.ReannounceTorrentWithRetry(ctx, release.TorrentHash, &opts); err != nil {
-			if errors.Is(err, qbittorrent.ErrReannounceTookTooLong) {
+		if err := s.reannounce(ctx, action, release.TorrentHash, &qbittorrentReannouncer{client: qbtClient, hash: release.TorrentHash}); err != nil {
+			if errors.Is(err, ErrReannounceTookTooLong) {
 				return []string{fmt.Sprintf("re-announce took too long for hash: %s", release.TorrentHash)}, nil
 			}
 
@@ -130,9 +144,89 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 
 	s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", release.TorrentHash, client.Name)
 
+	if release.TorrentHash != "" {
+		s.verifySize(action, *release, qbittorrentSize(qbtClient, release.TorrentHash))
+	}
+
 	return nil, nil
 }
 
+// qbittorrentSetShareLimits applies the seeding goals of the action to the added torrent, a failure is logged
+// as the torrent is added already
+func (s *service) qbittorrentSetShareLimits(ctx context.Context, action *domain.Action, client *domain.DownloadClient, hash string) {
+	limits := qbittorrentActionShareLimits(action)
+	if !limits.isSet() {
+		return
+	}
+
+	if err := qbittorrentSetShareLimits(ctx, client, hash, limits); err != nil {
+		s.log.Error().Err(err).Msgf("could not set share limits for torrent with hash %s in client: '%s'", hash, client.Name)
+		return
+	}
+
+	s.log.Debug().Msgf("torrent with hash %s share limits set to %+v in client: '%s'", hash, limits, client.Name)
+}
+
+// qbittorrentReannouncer re-announces a torrent until a tracker is working
+type qbittorrentReannouncer struct {
+	client *qbittorrent.Client
+	hash   string
+}
+
+// working reports if a tracker is working, a tracker with an unregistered message is not, even with an ok status
+func (r *qbittorrentReannouncer) working(ctx context.Context) (bool, string, error) {
+	trackers, err := r.client.GetTorrentTrackersCtx(ctx, r.hash)
+	if err != nil {
+		return false, "", errors.Wrap(err, "could not get trackers for torrent with hash: %s", r.hash)
+	}
+
+	message := ""
+
+	for _, tracker := range trackers {
+		if tracker.Status == qbittorrent.TrackerStatusDisabled {
+			continue
+		}
+
+		if tracker.Message != "" {
+			message = tracker.Message
+		}
+
+		if isUnregistered(tracker.Message) {
+			return false, message, nil
+		}
+
+		if tracker.Status == qbittorrent.TrackerStatusOK {
+			return true, message, nil
+		}
+	}
+
+	return false, message, nil
+}
+
+func (r *qbittorrentReannouncer) reannounce(ctx context.Context) error {
+	return r.client.ReAnnounceTorrentsCtx(ctx, []string{r.hash})
+}
+
+func (r *qbittorrentReannouncer) remove(ctx context.Context) error {
+	return r.client.DeleteTorrentsCtx(ctx, []string{r.hash}, false)
+}
+
+// qbittorrentSize returns the total size of the torrent with hash, 0 until the metadata is downloaded
+func qbittorrentSize(qbt *qbittorrent.Client, hash string) clientSizeFunc {
+	return func(ctx context.Context) (uint64, error) {
+		torrents, err := qbt.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: []string{hash}})
+		if err != nil {
+			return 0, errors.Wrap(err, "could not get torrent %s", hash)
+		}
+
+		if len(torrents) == 0 || torrents[0].TotalSize <= 0 {
+			return 0, nil
+		}
+
+		return uint64(torrents[0].TotalSize), nil
+	}
+}
+
 func (s *service) prepareQbitOptions(action *domain.Action) (map[string]string, error) {
 	opts := &qbittorrent.TorrentAddOptions{}
 
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// qbittorrentShareLimitGlobal makes qBittorrent use the global share limit
+const qbittorrentShareLimitGlobal = "-2"
+
+// qbittorrentShareLimits are the seeding goals of a torrent, unset limits use the global limits of the client
+type qbittorrentShareLimits struct {
+	Ratio            float64
+	SeedTime         int64
+	InactiveSeedTime int64
+}
+
+func qbittorrentActionShareLimits(action *domain.Action) qbittorrentShareLimits {
+	return qbittorrentShareLimits{
+		Ratio:            action.LimitRatio,
+		SeedTime:         action.LimitSeedTime,
+		InactiveSeedTime: action.LimitInactiveSeedTime,
+	}
+}
+
+func (l qbittorrentShareLimits) isSet() bool {
+	return l.Ratio > 0 || l.SeedTime > 0 || l.InactiveSeedTime > 0
+}
+
+// form returns the torrents/setShareLimits parameters, all three limits are required by the api
+func (l qbittorrentShareLimits) form(hash string) url.Values {
+	form := url.Values{
+		"hashes":                   {hash},
+		"ratioLimit":               {qbittorrentShareLimitGlobal},
+		"seedingTimeLimit":         {qbittorrentShareLimitGlobal},
+		"inactiveSeedingTimeLimit": {qbittorrentShareLimitGlobal},
+	}
+
+	if l.Ratio > 0 {
+		form.Set("ratioLimit", strconv.FormatFloat(l.Ratio, 'f', 2, 64))
+	}
+	if l.SeedTime > 0 {
+		form.Set("seedingTimeLimit", strconv.FormatInt(l.SeedTime, 10))
+	}
+	if l.InactiveSeedTime > 0 {
+		form.Set("inactiveSeedingTimeLimit", strconv.FormatInt(l.InactiveSeedTime, 10))
+	}
+
+	return form
+}
+
+// qbittorrentSetShareLimits sets the seeding goals of the torrent with torrents/setShareLimits,
+// which isn't supported by the qBittorrent client library
+func qbittorrentSetShareLimits(ctx context.Context, client *domain.DownloadClient, hash string, limits qbittorrentShareLimits) error {
+	api, err := newQbittorrentWebAPI(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if _, err := api.post(ctx, "torrents/setShareLimits", limits.form(hash)); err != nil {
+		return errors.Wrap(err, "could not set share limits")
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_qbittorrentShareLimits_form(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits qbittorrentShareLimits
+		want   url.Values
+	}{
+		{
+			name:   "all",
+			limits: qbittorrentShareLimits{Ratio: 2.5, SeedTime: 1440, InactiveSeedTime: 60},
+			want: url.Values{
+				"hashes":                   {"abc"},
+				"ratioLimit":               {"2.50"},
+				"seedingTimeLimit":         {"1440"},
+				"inactiveSeedingTimeLimit": {"60"},
+			},
+		},
+		{
+			name:   "unset_use_global",
+			limits: qbittorrentShareLimits{InactiveSeedTime: 30},
+			want: url.Values{
+				"hashes":                   {"abc"},
+				"ratioLimit":               {"-2"},
+				"seedingTimeLimit":         {"-2"},
+				"inactiveSeedingTimeLimit": {"30"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.limits.isSet())
+			assert.Equal(t, tt.want, tt.limits.form("abc"))
+		})
+	}
+
+	assert.False(t, qbittorrentShareLimits{}.isSet())
+}
+
+func Test_qbittorrentSetShareLimits(t *testing.T) {
+	var got url.Values
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			if r.FormValue("username") != "user" || r.FormValue("password") != "pass" {
+				_, _ = w.Write([]byte("Fails."))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session", Path: "/"})
+			_, _ = w.Write([]byte("Ok."))
+
+		case "/api/v2/torrents/setShareLimits":
+			if _, err := r.Cookie("SID"); err != nil {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_ = r.ParseForm()
+			got = r.PostForm
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := &domain.DownloadClient{Type: domain.DownloadClientTypeQbittorrent, Host: srv.URL, Username: "user", Password: "pass"}
+	limits := qbittorrentShareLimits{Ratio: 1, SeedTime: 60}
+
+	assert.NoError(t, qbittorrentSetShareLimits(context.Background(), client, "abc", limits))
+	assert.Equal(t, limits.form("abc"), got)
+
+	client.Password = "wrong"
+	assert.ErrorContains(t, qbittorrentSetShareLimits(context.Background(), client, "abc", limits), "bad credentials")
+}
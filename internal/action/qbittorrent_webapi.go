@@ -0,0 +1,96 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+// qbittorrentWebAPI calls the qBittorrent web api endpoints which aren't supported by the qBittorrent client library
+type qbittorrentWebAPI struct {
+	client     *domain.DownloadClient
+	host       string
+	httpClient *http.Client
+}
+
+// newQbittorrentWebAPI logs in to the web api of the client when it has credentials
+func newQbittorrentWebAPI(ctx context.Context, client *domain.DownloadClient) (*qbittorrentWebAPI, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create cookiejar")
+	}
+
+	api := &qbittorrentWebAPI{
+		client: client,
+		host:   client.BuildLegacyHost(),
+		httpClient: &http.Client{
+			Jar:       jar,
+			Timeout:   30 * time.Second,
+			Transport: sharedhttp.Transport,
+		},
+	}
+
+	if client.TLSSkipVerify {
+		api.httpClient.Transport = sharedhttp.TransportTLSInsecure
+	}
+
+	if client.Username != "" || client.Password != "" {
+		body, err := api.post(ctx, "auth/login", url.Values{"username": {client.Username}, "password": {client.Password}})
+		if err != nil {
+			return nil, errors.Wrap(err, "login error")
+		}
+
+		if string(body) == "Fails." {
+			return nil, errors.New("bad credentials")
+		}
+	}
+
+	return api, nil
+}
+
+func (a *qbittorrentWebAPI) post(ctx context.Context, endpoint string, form url.Values) ([]byte, error) {
+	reqUrl, err := url.JoinPath(a.host, "/api/v2/", endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if a.client.Settings.Auth.Username != "" && a.client.Settings.Auth.Password != "" {
+		req.SetBasicAuth(a.client.Settings.Auth.Username, a.client.Settings.Auth.Password)
+	}
+
+	res, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error making post request: %s", endpoint)
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read body")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("%s unexpected status: %d", endpoint, res.StatusCode)
+	}
+
+	return body, nil
+}
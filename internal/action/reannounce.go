@@ -0,0 +1,125 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	ReannounceMaxAttempts = 50
+	ReannounceInterval    = 7 // interval in seconds
+)
+
+var ErrReannounceTookTooLong = errors.New("ErrReannounceTookTooLong")
+
+// reannouncer checks the trackers of a torrent added to a client and re-announces it
+type reannouncer interface {
+	// working reports if a tracker accepted the torrent, the message is recorded with the attempt
+	working(ctx context.Context) (bool, string, error)
+	reannounce(ctx context.Context) error
+	remove(ctx context.Context) error
+}
+
+type reannounceOptions struct {
+	interval        time.Duration
+	jitter          time.Duration
+	maxAttempts     int
+	deleteOnFailure bool
+}
+
+func newReannounceOptions(action *domain.Action) reannounceOptions {
+	opts := reannounceOptions{
+		interval:        ReannounceInterval * time.Second,
+		jitter:          time.Duration(action.ReAnnounceJitter) * time.Second,
+		maxAttempts:     ReannounceMaxAttempts,
+		deleteOnFailure: action.ReAnnounceDelete,
+	}
+
+	if action.ReAnnounceInterval > 0 {
+		opts.interval = time.Duration(action.ReAnnounceInterval) * time.Second
+	}
+
+	if action.ReAnnounceMaxAttempts > 0 {
+		opts.maxAttempts = int(action.ReAnnounceMaxAttempts)
+	}
+
+	return opts
+}
+
+// wait returns the interval plus a random part of the jitter, so torrents added together don't announce in lockstep
+func (o reannounceOptions) wait() time.Duration {
+	if o.jitter <= 0 {
+		return o.interval
+	}
+
+	return o.interval + time.Duration(rand.Int63n(int64(o.jitter)+1))
+}
+
+// run re-announces the torrent until a tracker accepts it or the max attempts are reached, and removes it
+// when configured. It returns the attempts made, also when it fails.
+func (o reannounceOptions) run(ctx context.Context, r reannouncer) ([]domain.ReannounceAttempt, error) {
+	var attempts []domain.ReannounceAttempt
+
+	for attempt := 1; attempt <= o.maxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return attempts, ctx.Err()
+		case <-time.After(o.wait()):
+		}
+
+		ok, message, err := r.working(ctx)
+		if err != nil {
+			return attempts, errors.Wrap(err, "could not get trackers")
+		}
+
+		attempts = append(attempts, domain.ReannounceAttempt{
+			Attempt:   attempt,
+			Timestamp: time.Now(),
+			Working:   ok,
+			Message:   message,
+		})
+
+		if ok {
+			return attempts, nil
+		}
+
+		if err := r.reannounce(ctx); err != nil {
+			return attempts, errors.Wrap(err, "could not re-announce")
+		}
+	}
+
+	if !o.deleteOnFailure {
+		return attempts, nil
+	}
+
+	if err := r.remove(ctx); err != nil {
+		return attempts, errors.Wrap(err, "could not delete torrent after max re-announce attempts reached")
+	}
+
+	return attempts, ErrReannounceTookTooLong
+}
+
+// reannounce runs the re-announce of the action and records the attempts for the action status
+func (s *service) reannounce(ctx context.Context, action *domain.Action, name string, r reannouncer) error {
+	opts := newReannounceOptions(action)
+
+	s.log.Debug().Msgf("re-announce %s: interval %s jitter %s max attempts %d", name, opts.interval, opts.jitter, opts.maxAttempts)
+
+	attempts, err := opts.run(ctx, r)
+	action.ReAnnounceAttempts = attempts
+
+	if err == nil {
+		s.log.Debug().Msgf("re-announce %s done after %d attempts", name, len(attempts))
+	} else if errors.Is(err, ErrReannounceTookTooLong) {
+		s.log.Info().Msgf("re-announce for %s took too long, deleted torrent", name)
+	}
+
+	return err
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReannouncer struct {
+	// workingAfter is the number of checks until a tracker is working, 0 never
+	workingAfter int
+
+	checks      int
+	reannounced int
+	removed     bool
+}
+
+func (r *mockReannouncer) working(ctx context.Context) (bool, string, error) {
+	r.checks++
+	if r.workingAfter > 0 && r.checks >= r.workingAfter {
+		return true, "", nil
+	}
+	return false, "torrent not registered with this tracker", nil
+}
+
+func (r *mockReannouncer) reannounce(ctx context.Context) error {
+	r.reannounced++
+	return nil
+}
+
+func (r *mockReannouncer) remove(ctx context.Context) error {
+	r.removed = true
+	return nil
+}
+
+func Test_newReannounceOptions(t *testing.T) {
+	opts := newReannounceOptions(&domain.Action{})
+	assert.Equal(t, reannounceOptions{interval: 7 * time.Second, maxAttempts: 50}, opts)
+
+	opts = newReannounceOptions(&domain.Action{ReAnnounceInterval: 3, ReAnnounceMaxAttempts: 10, ReAnnounceJitter: 2, ReAnnounceDelete: true})
+	assert.Equal(t, reannounceOptions{interval: 3 * time.Second, jitter: 2 * time.Second, maxAttempts: 10, deleteOnFailure: true}, opts)
+
+	for i := 0; i < 20; i++ {
+		wait := opts.wait()
+		assert.GreaterOrEqual(t, wait, 3*time.Second)
+		assert.LessOrEqual(t, wait, 5*time.Second)
+	}
+}
+
+func Test_reannounceOptions_run(t *testing.T) {
+	opts := reannounceOptions{interval: time.Millisecond, jitter: time.Millisecond, maxAttempts: 3}
+
+	t.Run("working", func(t *testing.T) {
+		r := &mockReannouncer{workingAfter: 2}
+
+		attempts, err := opts.run(context.Background(), r)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, r.reannounced)
+		if assert.Len(t, attempts, 2) {
+			assert.False(t, attempts[0].Working)
+			assert.Equal(t, "torrent not registered with this tracker", attempts[0].Message)
+			assert.True(t, attempts[1].Working)
+			assert.Equal(t, 2, attempts[1].Attempt)
+		}
+	})
+
+	t.Run("not_working", func(t *testing.T) {
+		r := &mockReannouncer{}
+
+		attempts, err := opts.run(context.Background(), r)
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 3)
+		assert.Equal(t, 3, r.reannounced)
+		assert.False(t, r.removed)
+	})
+
+	t.Run("delete_on_failure", func(t *testing.T) {
+		r := &mockReannouncer{}
+
+		deleteOpts := opts
+		deleteOpts.deleteOnFailure = true
+
+		attempts, err := deleteOpts.run(context.Background(), r)
+		assert.ErrorIs(t, err, ErrReannounceTookTooLong)
+		assert.Len(t, attempts, 3)
+		assert.True(t, r.removed)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		attempts, err := reannounceOptions{interval: time.Hour, maxAttempts: 3}.run(ctx, &mockReannouncer{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, attempts)
+	})
+}
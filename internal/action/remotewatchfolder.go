@@ -0,0 +1,111 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/remotefs"
+)
+
+func (s *service) remoteWatchFolder(ctx context.Context, action *domain.Action, release domain.Release) error {
+	s.log.Trace().Msgf("action REMOTE_WATCH_FOLDER: %s file: %s", action.WatchFolder, release.TorrentName)
+
+	if release.HasMagnetUri() {
+		return errors.New("action remote watch folder does not support magnet links: %s", release.TorrentName)
+	}
+
+	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
+	if err != nil {
+		return errors.Wrap(err, "could not get client with id %d", action.ClientID)
+	}
+	action.Client = client
+
+	if !client.Enabled {
+		return errors.New("client %s %s not enabled", client.Type, client.Name)
+	}
+
+	rfs := client.Client.(*remotefs.Client)
+
+	var (
+		content []byte
+		ext     string
+	)
+
+	switch release.Protocol {
+	case domain.ReleaseProtocolTorrent:
+		if len(release.TorrentDataRawBytes) < 1 {
+			return errors.New("remote watch folder: missing torrent %s", release.TorrentName)
+		}
+
+		content = release.TorrentDataRawBytes
+		ext = ".torrent"
+
+	case domain.ReleaseProtocolNzb:
+		content, err = s.downloadNzb(ctx, release)
+		if err != nil {
+			return err
+		}
+
+		ext = ".nzb"
+
+	default:
+		return errors.New("action type: %s invalid protocol: %s", action.Type, release.Protocol)
+	}
+
+	// the watch folder is relative to the path of the client url, unless it starts with /
+	//  {{.Indexer}}
+	//  mock/Torrent.Name-GROUP.torrent
+	//  /mnt/watch/{{.Indexer}}-{{.TorrentName}}.torrent
+	fileName := action.WatchFolder
+	if !strings.HasSuffix(fileName, ext) {
+		fileName = path.Join(fileName, strings.ReplaceAll(release.TorrentName, "/", "_")+ext)
+	}
+
+	if err := rfs.Upload(ctx, fileName, content); err != nil {
+		return errors.Wrap(err, "could not upload file to remote watch folder: %s", client.Name)
+	}
+
+	s.log.Info().Msgf("uploaded file to remote watch folder: '%s' %s", client.Name, fileName)
+
+	return nil
+}
+
+// downloadNzb fetches the nzb, unlike torrents it's not downloaded before the action runs
+func (s *service) downloadNzb(ctx context.Context, release domain.Release) ([]byte, error) {
+	if release.DownloadURL == "" {
+		return nil, errors.New("download url can't be empty: %s", release.TorrentName)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, release.DownloadURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not download nzb: %s", release.TorrentName)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("could not download nzb: %s unexpected status: %d", release.TorrentName, res.StatusCode)
+	}
+
+	content, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read nzb: %s", release.TorrentName)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+// TorrentStates returns the seeding state of the torrents with the infohashes in the client, keyed by lowercase infohash.
+// Torrents that are not in the client are left out.
+func (s *service) TorrentStates(ctx context.Context, clientID int32, hashes []string) (map[string]domain.TorrentState, error) {
+	client, err := s.enabledClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]domain.TorrentState)
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		torrents, err := client.Client.(*qbittorrent.Client).GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: hashes})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get torrents from client: %s", client.Name)
+		}
+
+		for _, torrent := range torrents {
+			states[strings.ToLower(torrent.Hash)] = domain.TorrentState{
+				Ratio:       torrent.Ratio,
+				SeedingTime: time.Duration(torrent.SeedingTime) * time.Second,
+			}
+		}
+
+	case domain.DownloadClientTypeTransmission:
+		torrents, err := client.Client.(*transmissionrpc.Client).TorrentGetHashes(ctx, []string{"hashString", "uploadRatio", "secondsSeeding"}, hashes)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get torrents from client: %s", client.Name)
+		}
+
+		for _, torrent := range torrents {
+			if torrent.HashString == nil {
+				continue
+			}
+
+			var state domain.TorrentState
+			if torrent.UploadRatio != nil {
+				state.Ratio = *torrent.UploadRatio
+			}
+			if torrent.TimeSeeding != nil {
+				state.SeedingTime = *torrent.TimeSeeding
+			}
+
+			states[strings.ToLower(*torrent.HashString)] = state
+		}
+
+	default:
+		return nil, errors.New("removal policies not supported for client type: %s", client.Type)
+	}
+
+	return states, nil
+}
+
+// RemoveTorrent removes the torrent with the infohash from the client, with its downloaded data when deleteData is set
+func (s *service) RemoveTorrent(ctx context.Context, clientID int32, hash string, deleteData bool) error {
+	client, err := s.enabledClient(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		if err := client.Client.(*qbittorrent.Client).DeleteTorrentsCtx(ctx, []string{hash}, deleteData); err != nil {
+			return errors.Wrap(err, "could not remove torrent %s from client: %s", hash, client.Name)
+		}
+
+	case domain.DownloadClientTypeTransmission:
+		tc := client.Client.(*transmissionrpc.Client)
+
+		torrents, err := tc.TorrentGetHashes(ctx, []string{"id"}, []string{hash})
+		if err != nil {
+			return errors.Wrap(err, "could not get torrent %s from client: %s", hash, client.Name)
+		}
+
+		if len(torrents) == 0 || torrents[0].ID == nil {
+			return nil
+		}
+
+		if err := tc.TorrentRemove(ctx, transmissionrpc.TorrentRemovePayload{IDs: []int64{*torrents[0].ID}, DeleteLocalData: deleteData}); err != nil {
+			return errors.Wrap(err, "could not remove torrent %s from client: %s", hash, client.Name)
+		}
+
+	default:
+		return errors.New("removal policies not supported for client type: %s", client.Type)
+	}
+
+	s.log.Debug().Msgf("removed torrent %s from client %s, data deleted: %t", hash, client.Name, deleteData)
+
+	return nil
+}
+
+// enabledClient returns the client with the id, an error when it's disabled
+func (s *service) enabledClient(ctx context.Context, clientID int32) (*domain.DownloadClient, error) {
+	client, err := s.clientSvc.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get client with id %d", clientID)
+	}
+
+	if !client.Enabled {
+		return nil, errors.New("client %s is disabled", client.Name)
+	}
+
+	return client, nil
+}
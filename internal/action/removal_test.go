@@ -0,0 +1,52 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_TorrentStates(t *testing.T) {
+	var deleted string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			_, _ = w.Write([]byte(`[{"hash":"AAAA","ratio":1.5,"seeding_time":3600}]`))
+		case "/api/v2/torrents/delete":
+			_ = r.ParseForm()
+			deleted = r.Form.Get("hashes") + ":" + r.Form.Get("deleteFiles")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	clientSvc := &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+		1: {ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Client: qbittorrent.NewClient(qbittorrent.Config{Host: srv.URL})},
+		2: {ID: 2, Name: "disabled", Type: domain.DownloadClientTypeQbittorrent, Enabled: false},
+	}}
+
+	s := &service{log: zerolog.Nop(), clientSvc: clientSvc}
+
+	states, err := s.TorrentStates(context.Background(), 1, []string{"aaaa", "bbbb"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]domain.TorrentState{"aaaa": {Ratio: 1.5, SeedingTime: time.Hour}}, states)
+
+	assert.NoError(t, s.RemoveTorrent(context.Background(), 1, "aaaa", true))
+	assert.Equal(t, "aaaa:true", deleted)
+
+	_, err = s.TorrentStates(context.Background(), 2, []string{"aaaa"})
+	assert.Error(t, err)
+}
@@ -5,7 +5,9 @@ package action
 
 import (
 	"context"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -13,7 +15,7 @@ import (
 	"github.com/autobrr/go-rtorrent"
 )
 
-func (s *service) rtorrent(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) rtorrent(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action rTorrent: %s", action.Name)
 
 	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
@@ -30,28 +32,10 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 
 	var rejections []string
 
-	if release.HasMagnetUri() {
-		var args []*rtorrent.FieldValue
+	s.replaceOriginals(ctx, client, release, rtorrentRemove(rt))
 
-		if action.Label != "" {
-			args = append(args, &rtorrent.FieldValue{
-				Field: rtorrent.DLabel,
-				Value: action.Label,
-			})
-		}
-		if action.SavePath != "" {
-			if action.ContentLayout == domain.ActionContentLayoutSubfolderNone {
-				args = append(args, &rtorrent.FieldValue{
-					Field: "d.directory_base",
-					Value: action.SavePath,
-				})
-			} else {
-				args = append(args, &rtorrent.FieldValue{
-					Field: rtorrent.DDirectory,
-					Value: action.SavePath,
-				})
-			}
-		}
+	if release.HasMagnetUri() {
+		args := s.rtorrentFieldValues(action)
 
 		var addTorrentMagnet func(context.Context, string, ...*rtorrent.FieldValue) error
 		if action.Paused {
@@ -69,7 +53,9 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 		return nil, nil
 	}
 
-	if err := s.downloadSvc.DownloadRelease(ctx, &release); err != nil {
+	s.useFreeleechToken(ctx, action, release)
+
+	if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
 		return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
 	}
 
@@ -78,13 +64,43 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 		return nil, errors.Wrap(err, "could not read torrent file: %s", release.TorrentTmpFile)
 	}
 
+	args := s.rtorrentFieldValues(action)
+
+	var addTorrentFile func(context.Context, []byte, ...*rtorrent.FieldValue) error
+	if action.Paused {
+		addTorrentFile = rt.AddTorrentStopped
+	} else {
+		addTorrentFile = rt.AddTorrent
+	}
+
+	if err := addTorrentFile(ctx, tmpFile, args...); err != nil {
+		return nil, errors.Wrap(err, "could not add torrent file: %s", release.TorrentTmpFile)
+	}
+
+	s.log.Info().Msgf("torrent successfully added to client: '%s'", client.Name)
+
+	return rejections, nil
+}
+
+// rtorrentCustom2 is the second custom field of a "Downloading Item"
+const rtorrentCustom2 rtorrent.Field = "d.custom2"
+
+// rtorrentFieldValues returns the fields set on the torrent when added
+func (s *service) rtorrentFieldValues(action *domain.Action) []*rtorrent.FieldValue {
 	var args []*rtorrent.FieldValue
 
-	if action.Label != "" {
-		args = append(args, &rtorrent.FieldValue{
-			Field: rtorrent.DLabel,
-			Value: action.Label,
-		})
+	// ruTorrent keeps its label url encoded in custom1, a custom1 value is set as is and replaces the label
+	if action.Custom1 != "" {
+		if action.Label != "" {
+			s.log.Warn().Msgf("action rTorrent: %s custom1 is set, label %q is ignored", action.Name, action.Label)
+		}
+
+		args = append(args, rtorrent.DLabel.SetValue(rtorrentEscape(action.Custom1)))
+	} else if action.Label != "" {
+		args = append(args, rtorrent.DLabel.SetValue(url.PathEscape(action.Label)))
+	}
+	if action.Custom2 != "" {
+		args = append(args, rtorrentCustom2.SetValue(rtorrentEscape(action.Custom2)))
 	}
 	if action.SavePath != "" {
 		if action.ContentLayout == domain.ActionContentLayoutSubfolderNone {
@@ -100,18 +116,10 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 		}
 	}
 
-	var addTorrentFile func(context.Context, []byte, ...*rtorrent.FieldValue) error
-	if action.Paused {
-		addTorrentFile = rt.AddTorrentStopped
-	} else {
-		addTorrentFile = rt.AddTorrent
-	}
-
-	if err := addTorrentFile(ctx, tmpFile, args...); err != nil {
-		return nil, errors.Wrap(err, "could not add torrent file: %s", release.TorrentTmpFile)
-	}
-
-	s.log.Info().Msgf("torrent successfully added to client: '%s'", client.Name)
+	return args
+}
 
-	return rejections, nil
+// rtorrentEscape escapes the value to be set within double quotes in an rTorrent command
+func rtorrentEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
 }
@@ -0,0 +1,49 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_rtorrentFieldValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		action domain.Action
+		want   []string
+	}{
+		{
+			name:   "label_url_encoded",
+			action: domain.Action{Label: `TV Shows/"HD"`, SavePath: "/downloads"},
+			want:   []string{`d.custom1.set="TV%20Shows%2F%22HD%22"`, `d.directory.set="/downloads"`},
+		},
+		{
+			name:   "custom_fields",
+			action: domain.Action{Label: "tv", Custom1: `say "hi"`, Custom2: `C:\tv`},
+			want:   []string{`d.custom1.set="say \"hi\""`, `d.custom2.set="C:\\tv"`},
+		},
+		{
+			name:   "empty",
+			action: domain.Action{},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: zerolog.Nop()}
+
+			var got []string
+			for _, fv := range s.rtorrentFieldValues(&tt.action) {
+				got = append(got, fv.String())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -6,6 +6,9 @@ package action
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +19,8 @@ import (
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/avast/retry-go/v4"
 )
 
 func (s *service) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
@@ -42,33 +47,102 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		return nil, err
 	}
 
+	rejections, err = s.validateTorrent(ctx, action, release)
+	if err == nil && len(rejections) == 0 {
+		s.pickPoolClient(ctx, action)
+
+		rejections, err = s.runActionType(ctx, action, release)
+		if (err != nil || len(rejections) > 0) && len(action.FailoverClientIDs) > 0 {
+			rejections, err = s.failover(ctx, action, release, rejections, err)
+		}
+	}
+
+	payload := &domain.NotificationPayload{
+		Event:          domain.NotificationEventPushApproved,
+		ReleaseName:    release.TorrentName,
+		Filter:         release.FilterName,
+		Indexer:        release.Indexer.Name,
+		InfoHash:       release.TorrentHash,
+		Size:           release.Size,
+		Status:         domain.ReleasePushStatusApproved,
+		Action:         action.Name,
+		ActionType:     action.Type,
+		Rejections:     []string{},
+		Protocol:       release.Protocol,
+		Implementation: release.Implementation,
+		Timestamp:      time.Now(),
+		Release:        release,
+	}
+
+	if action.Client != nil {
+		payload.ActionClient = action.Client.Name
+	}
+
+	if err != nil {
+		s.log.Error().Err(err).Msgf("process action failed: %v for '%v'", action.Name, release.TorrentName)
+
+		payload.Event = domain.NotificationEventPushError
+		payload.Status = domain.ReleasePushStatusErr
+		payload.Rejections = []string{err.Error()}
+	}
+
+	if rejections != nil {
+		payload.Event = domain.NotificationEventPushRejected
+		payload.Status = domain.ReleasePushStatusRejected
+		payload.Rejections = rejections
+	}
+
+	if err == nil && rejections == nil {
+		s.triggerCrossSeed(action, *release)
+	}
+
+	// send separate event for notifications
+	s.bus.Publish("events:notification", &payload.Event, payload)
+
+	return rejections, err
+}
+
+// runActionType runs the action with the client of the action
+func (s *service) runActionType(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	var (
+		err        error
+		rejections []string
+	)
+
 	switch action.Type {
 	case domain.ActionTypeTest:
 		s.test(action.Name)
 
 	case domain.ActionTypeExec:
-		err = s.execCmd(ctx, action, *release)
+		rejections, err = s.execCmd(ctx, action, release)
 
 	case domain.ActionTypeWatchFolder:
+		s.useFreeleechToken(ctx, action, release)
 		err = s.watchFolder(ctx, action, *release)
 
 	case domain.ActionTypeWebhook:
 		err = s.webhook(ctx, action, *release)
 
 	case domain.ActionTypeDelugeV1, domain.ActionTypeDelugeV2:
-		rejections, err = s.deluge(ctx, action, *release)
+		rejections, err = s.deluge(ctx, action, release)
 
 	case domain.ActionTypeQbittorrent:
-		rejections, err = s.qbittorrent(ctx, action, *release)
+		rejections, err = s.qbittorrent(ctx, action, release)
 
 	case domain.ActionTypeRTorrent:
-		rejections, err = s.rtorrent(ctx, action, *release)
+		rejections, err = s.rtorrent(ctx, action, release)
 
 	case domain.ActionTypeTransmission:
-		rejections, err = s.transmission(ctx, action, *release)
+		rejections, err = s.transmission(ctx, action, release)
 
 	case domain.ActionTypePorla:
-		rejections, err = s.porla(ctx, action, *release)
+		rejections, err = s.porla(ctx, action, release)
+
+	case domain.ActionTypeAria2:
+		rejections, err = s.aria2(ctx, action, release)
+
+	case domain.ActionTypeDownloadStation:
+		rejections, err = s.downloadStation(ctx, action, release)
 
 	case domain.ActionTypeRadarr:
 		rejections, err = s.radarr(ctx, action, *release)
@@ -85,50 +159,22 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 	case domain.ActionTypeReadarr:
 		rejections, err = s.readarr(ctx, action, *release)
 
+	case domain.ActionTypeMylar:
+		rejections, err = s.mylar(ctx, action, *release)
+
 	case domain.ActionTypeSabnzbd:
 		rejections, err = s.sabnzbd(ctx, action, *release)
 
-	default:
-		return nil, errors.New("unsupported action type: %s", action.Type)
-	}
-
-	payload := &domain.NotificationPayload{
-		Event:          domain.NotificationEventPushApproved,
-		ReleaseName:    release.TorrentName,
-		Filter:         release.FilterName,
-		Indexer:        release.Indexer.Name,
-		InfoHash:       release.TorrentHash,
-		Size:           release.Size,
-		Status:         domain.ReleasePushStatusApproved,
-		Action:         action.Name,
-		ActionType:     action.Type,
-		Rejections:     []string{},
-		Protocol:       release.Protocol,
-		Implementation: release.Implementation,
-		Timestamp:      time.Now(),
-	}
-
-	if action.Client != nil {
-		payload.ActionClient = action.Client.Name
-	}
-
-	if err != nil {
-		s.log.Error().Err(err).Msgf("process action failed: %v for '%v'", action.Name, release.TorrentName)
+	case domain.ActionTypeNzbget:
+		rejections, err = s.nzbget(ctx, action, *release)
 
-		payload.Event = domain.NotificationEventPushError
-		payload.Status = domain.ReleasePushStatusErr
-		payload.Rejections = []string{err.Error()}
-	}
+	case domain.ActionTypeRemoteWatchFolder:
+		err = s.remoteWatchFolder(ctx, action, *release)
 
-	if rejections != nil {
-		payload.Event = domain.NotificationEventPushRejected
-		payload.Status = domain.ReleasePushStatusRejected
-		payload.Rejections = rejections
+	default:
+		return nil, errors.New("unsupported action type: %s", action.Type)
 	}
 
-	// send separate event for notifications
-	s.bus.Publish("events:notification", &payload.Event, payload)
-
 	return rejections, err
 }
 
@@ -207,6 +253,9 @@ func (s *service) watchFolder(ctx context.Context, action *domain.Action, releas
 	return nil
 }
 
+// webhookSignatureHeader is the HMAC-SHA256 of the payload, signed with the webhook secret of the action
+const webhookSignatureHeader = "X-Autobrr-Signature"
+
 func (s *service) webhook(ctx context.Context, action *domain.Action, release domain.Release) error {
 	s.log.Trace().Msgf("action WEBHOOK: '%s' file: %s", action.Name, release.TorrentName)
 	if len(action.WebhookData) > 1024 {
@@ -215,22 +264,79 @@ func (s *service) webhook(ctx context.Context, action *domain.Action, release do
 		s.log.Trace().Msgf("webhook action '%s' - host: %s data: %s", action.Name, action.WebhookHost, action.WebhookData)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, action.WebhookHost, bytes.NewBufferString(action.WebhookData))
-	if err != nil {
-		return errors.Wrap(err, "could not build request for webhook")
+	method := http.MethodPost
+	if action.WebhookMethod != "" {
+		method = action.WebhookMethod
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "autobrr")
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("User-Agent", "autobrr")
+
+	for _, h := range action.WebhookHeaders {
+		key, value, ok := strings.Cut(h, "=")
+		if !ok {
+			return errors.New("invalid webhook header: %s, expected Key=Value", h)
+		}
+
+		header.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	if action.WebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(action.WebhookSecret))
+		mac.Write([]byte(action.WebhookData))
+		header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	opts := []retry.Option{
+		retry.Context(ctx),
+		retry.DelayType(retry.FixedDelay),
+		retry.LastErrorOnly(true),
+	}
+
+	if action.WebhookRetryAttempts > 0 {
+		opts = append(opts, retry.Attempts(uint(action.WebhookRetryAttempts)))
+	} else {
+		opts = append(opts, retry.Attempts(1))
+	}
+	if action.WebhookRetryDelaySeconds > 0 {
+		opts = append(opts, retry.Delay(time.Duration(action.WebhookRetryDelaySeconds)*time.Second))
+	}
 
 	start := time.Now()
-	res, err := s.httpClient.Do(req)
+
+	err := retry.Do(
+		func() error {
+			req, err := http.NewRequestWithContext(ctx, method, action.WebhookHost, strings.NewReader(action.WebhookData))
+			if err != nil {
+				return retry.Unrecoverable(errors.Wrap(err, "could not build request for webhook"))
+			}
+
+			req.Header = header.Clone()
+
+			res, err := s.httpClient.Do(req)
+			if err != nil {
+				return errors.Wrap(err, "could not make request for webhook")
+			}
+
+			defer res.Body.Close()
+
+			// only server errors are retried, the request is not going to succeed on a client error
+			if res.StatusCode >= http.StatusInternalServerError {
+				return errors.New("webhook got server error status code: %d", res.StatusCode)
+			}
+
+			if res.StatusCode >= http.StatusBadRequest {
+				return retry.Unrecoverable(errors.New("webhook got client error status code: %d", res.StatusCode))
+			}
+
+			return nil
+		},
+		opts...)
 	if err != nil {
-		return errors.Wrap(err, "could not make request for webhook")
+		return err
 	}
 
-	defer res.Body.Close()
-
 	if len(action.WebhookData) > 256 {
 		s.log.Info().Msgf("successfully ran webhook action: '%s' to: %s payload: %s finished in %s", action.Name, action.WebhookHost, action.WebhookData[:256], time.Since(start))
 	} else {
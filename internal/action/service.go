@@ -7,6 +7,7 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -31,23 +32,38 @@ type Service interface {
 	ToggleEnabled(actionID int) error
 
 	RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error)
+	CompletedTorrents(ctx context.Context, clientID int32, hashes []string) (map[string]time.Time, error)
+	RunCompletionHooks(ctx context.Context, action *domain.Action, release *domain.Release) error
+	TorrentStates(ctx context.Context, clientID int32, hashes []string) (map[string]domain.TorrentState, error)
+	RemoveTorrent(ctx context.Context, clientID int32, hash string, deleteData bool) error
+
+	ListFreeleechTokenBudgets(ctx context.Context) ([]domain.FreeleechTokenBudget, error)
+	UpdateFreeleechTokenBudget(ctx context.Context, budget domain.FreeleechTokenBudget) error
 }
 
 type service struct {
 	log         zerolog.Logger
 	subLogger   *log.Logger
+	config      *domain.Config
 	repo        domain.ActionRepo
+	tokenRepo   domain.FreeleechTokenRepo
 	clientSvc   download_client.Service
 	downloadSvc *releasedownload.DownloadService
 	bus         EventBus.Bus
 
 	httpClient *http.Client
+
+	tokenMu sync.Mutex
+
+	clientPool clientPool
 }
 
-func NewService(log logger.Logger, repo domain.ActionRepo, clientSvc download_client.Service, downloadSvc *releasedownload.DownloadService, bus EventBus.Bus) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.ActionRepo, tokenRepo domain.FreeleechTokenRepo, clientSvc download_client.Service, downloadSvc *releasedownload.DownloadService, bus EventBus.Bus) Service {
 	s := &service{
 		log:         log.With().Str("module", "action").Logger(),
+		config:      config,
 		repo:        repo,
+		tokenRepo:   tokenRepo,
 		clientSvc:   clientSvc,
 		downloadSvc: downloadSvc,
 		bus:         bus,
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	sizeCheckInterval = 5 * time.Second
+	sizeCheckTimeout  = 5 * time.Minute
+)
+
+// clientSizeFunc returns the torrent size reported by the client, 0 while the metadata is not downloaded yet
+type clientSizeFunc func(ctx context.Context) (uint64, error)
+
+// verifySize waits in the background for the client to report the torrent size and notifies when it differs
+// from the size claimed by the announce by more than the configured threshold
+func (s *service) verifySize(action *domain.Action, release domain.Release, clientSize clientSizeFunc) {
+	if s.config == nil || s.config.SizeMismatchThreshold <= 0 {
+		return
+	}
+
+	claimed := release.ClaimedSize()
+	if claimed == 0 {
+		s.log.Trace().Msgf("size check: no announced size for %s, skipping", release.TorrentName)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sizeCheckTimeout)
+		defer cancel()
+
+		actual, err := waitForClientSize(ctx, clientSize)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("size check: could not get size from client for %s", release.TorrentName)
+			return
+		}
+
+		percent, mismatch := domain.SizeMismatch(claimed, actual, s.config.SizeMismatchThreshold)
+		if !mismatch {
+			s.log.Debug().Msgf("size check: %s matches announced size %s, diff %.1f%%", release.TorrentName, humanize.Bytes(claimed), percent)
+			return
+		}
+
+		message := fmt.Sprintf("announced %s, client reports %s (%.1f%% diff)", humanize.Bytes(claimed), humanize.Bytes(actual), percent)
+
+		s.log.Warn().Msgf("size check: mismatch for %s: %s", release.TorrentName, message)
+
+		payload := &domain.NotificationPayload{
+			Subject:        "Size mismatch",
+			Message:        message,
+			Event:          domain.NotificationEventSizeMismatch,
+			ReleaseName:    release.TorrentName,
+			Filter:         release.FilterName,
+			Indexer:        release.Indexer.Name,
+			InfoHash:       release.TorrentHash,
+			Size:           actual,
+			Action:         action.Name,
+			ActionType:     action.Type,
+			Protocol:       release.Protocol,
+			Implementation: release.Implementation,
+			Timestamp:      time.Now(),
+			Release:        &release,
+		}
+
+		if action.Client != nil {
+			payload.ActionClient = action.Client.Name
+		}
+
+		s.bus.Publish("events:notification", &payload.Event, payload)
+	}()
+}
+
+// waitForClientSize polls the client until it reports a size, magnets have no size until the metadata is downloaded
+func waitForClientSize(ctx context.Context, clientSize clientSizeFunc) (uint64, error) {
+	ticker := time.NewTicker(sizeCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		size, err := clientSize(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		if size > 0 {
+			return size, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
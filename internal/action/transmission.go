@@ -14,15 +14,9 @@ import (
 	"github.com/hekmon/transmissionrpc/v3"
 )
 
-const (
-	ReannounceMaxAttempts = 50
-	ReannounceInterval    = 7 // interval in seconds
-)
-
-var ErrReannounceTookTooLong = errors.New("ErrReannounceTookTooLong")
 var TrTrue = true
 
-func (s *service) transmission(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) transmission(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action Transmission: %s", action.Name)
 
 	client, err := s.clientSvc.GetClient(ctx, action.ClientID)
@@ -37,7 +31,7 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 
 	tbt := client.Client.(*transmissionrpc.Client)
 
-	rejections, err := s.transmissionCheckRulesCanDownload(ctx, action, client, tbt)
+	rejections, err := s.transmissionCheckRulesCanDownload(ctx, action, client, release, tbt)
 	if err != nil {
 		return nil, errors.Wrap(err, "error checking client rules: %s", action.Name)
 	}
@@ -46,6 +40,8 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return rejections, nil
 	}
 
+	s.replaceOriginals(ctx, client, release, transmissionRemove(tbt))
+
 	payload := transmissionrpc.TorrentAddPayload{}
 
 	if action.SavePath != "" {
@@ -64,43 +60,8 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 			return nil, errors.Wrap(err, "could not add torrent from magnet %s to client: %s", release.MagnetURI, client.Host)
 		}
 
-		if action.Label != "" || action.LimitUploadSpeed > 0 || action.LimitDownloadSpeed > 0 || action.LimitRatio > 0 || action.LimitSeedTime > 0 {
-			p := transmissionrpc.TorrentSetPayload{
-				IDs: []int64{*torrent.ID},
-			}
-
-			if action.Label != "" {
-				p.Labels = []string{action.Label}
-			}
-
-			if action.LimitUploadSpeed > 0 {
-				p.UploadLimit = &action.LimitUploadSpeed
-				p.UploadLimited = &TrTrue
-			}
-			if action.LimitDownloadSpeed > 0 {
-				p.DownloadLimit = &action.LimitDownloadSpeed
-				p.DownloadLimited = &TrTrue
-			}
-			if action.LimitRatio > 0 {
-				p.SeedRatioLimit = &action.LimitRatio
-				ratioMode := transmissionrpc.SeedRatioModeCustom
-				p.SeedRatioMode = &ratioMode
-			}
-			if action.LimitSeedTime > 0 {
-				t := time.Duration(action.LimitSeedTime) * time.Minute
-				//p.SeedIdleLimit = &action.LimitSeedTime
-				p.SeedIdleLimit = &t
-
-				// seed idle mode 1
-				seedIdleMode := int64(1)
-				p.SeedIdleMode = &seedIdleMode
-			}
-
-			if err := tbt.TorrentSet(ctx, p); err != nil {
-				return nil, errors.Wrap(err, "could not set label for hash %s to client: %s", *torrent.HashString, client.Host)
-			}
-
-			s.log.Debug().Msgf("set label for torrent hash %s successful to client: '%s'", *torrent.HashString, client.Name)
+		if err := s.transmissionSetTorrent(ctx, action, client, tbt, torrent); err != nil {
+			return nil, err
 		}
 
 		s.log.Info().Msgf("torrent from magnet with hash %v successfully added to client: '%s'", torrent.HashString, client.Name)
@@ -108,7 +69,9 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return nil, nil
 	}
 
-	if err := s.downloadSvc.DownloadRelease(ctx, &release); err != nil {
+	s.useFreeleechToken(ctx, action, release)
+
+	if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
 		return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
 	}
 
@@ -125,48 +88,12 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Host)
 	}
 
-	if action.Label != "" || action.LimitUploadSpeed > 0 || action.LimitDownloadSpeed > 0 || action.LimitRatio > 0 || action.LimitSeedTime > 0 {
-		p := transmissionrpc.TorrentSetPayload{
-			IDs: []int64{*torrent.ID},
-		}
-
-		if action.Label != "" {
-			p.Labels = []string{action.Label}
-		}
-
-		if action.LimitUploadSpeed > 0 {
-			p.UploadLimit = &action.LimitUploadSpeed
-			p.UploadLimited = &TrTrue
-		}
-		if action.LimitDownloadSpeed > 0 {
-			p.DownloadLimit = &action.LimitDownloadSpeed
-			p.DownloadLimited = &TrTrue
-		}
-		if action.LimitRatio > 0 {
-			p.SeedRatioLimit = &action.LimitRatio
-			ratioMode := transmissionrpc.SeedRatioModeCustom
-			p.SeedRatioMode = &ratioMode
-		}
-		if action.LimitSeedTime > 0 {
-			t := time.Duration(action.LimitSeedTime) * time.Minute
-			p.SeedIdleLimit = &t
-
-			// seed idle mode 1
-			seedIdleMode := int64(1)
-			p.SeedIdleMode = &seedIdleMode
-		}
-
-		s.log.Trace().Msgf("transmission torrent set payload: %+v for torrent hash %s client: %s", p, *torrent.HashString, client.Name)
-
-		if err := tbt.TorrentSet(ctx, p); err != nil {
-			return nil, errors.Wrap(err, "could not set label for hash %s to client: %s", *torrent.HashString, client.Host)
-		}
-
-		s.log.Debug().Msgf("set label for torrent hash %s successful to client: '%s'", *torrent.HashString, client.Name)
+	if err := s.transmissionSetTorrent(ctx, action, client, tbt, torrent); err != nil {
+		return nil, err
 	}
 
 	if !action.Paused && !action.ReAnnounceSkip {
-		if err := s.transmissionReannounce(ctx, action, tbt, *torrent.ID); err != nil {
+		if err := s.reannounce(ctx, action, *torrent.HashString, &transmissionReannouncer{client: tbt, id: *torrent.ID}); err != nil {
 			if errors.Is(err, ErrReannounceTookTooLong) {
 				return []string{fmt.Sprintf("reannounce took too long for torrent: %s, deleted", *torrent.HashString)}, nil
 			}
@@ -182,77 +109,126 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 	return rejections, nil
 }
 
-func (s *service) transmissionReannounce(ctx context.Context, action *domain.Action, tbt *transmissionrpc.Client, torrentId int64) error {
-	interval := ReannounceInterval
-	if action.ReAnnounceInterval > 0 {
-		interval = int(action.ReAnnounceInterval)
+// transmissionSetTorrent sets the labels, bandwidth group and limits of the action on the added torrent
+func (s *service) transmissionSetTorrent(ctx context.Context, action *domain.Action, client *domain.DownloadClient, tbt *transmissionrpc.Client, torrent transmissionrpc.Torrent) error {
+	p := transmissionTorrentSetPayload(action, *torrent.ID)
+	if p == nil {
+		return nil
 	}
 
-	maxAttempts := ReannounceMaxAttempts
-	if action.ReAnnounceMaxAttempts > 0 {
-		maxAttempts = int(action.ReAnnounceMaxAttempts)
-	}
+	s.log.Trace().Msgf("transmission torrent set payload: %+v for torrent hash %s client: %s", p, *torrent.HashString, client.Name)
 
-	attempts := 0
+	if err := tbt.TorrentSet(ctx, *p); err != nil {
+		return errors.Wrap(err, "could not set torrent options for hash %s to client: %s", *torrent.HashString, client.Host)
+	}
 
-	for attempts <= maxAttempts {
-		s.log.Debug().Msgf("re-announce %d attempt: %d/%d", torrentId, attempts, maxAttempts)
+	s.log.Debug().Msgf("set torrent options for torrent hash %s successful to client: '%s'", *torrent.HashString, client.Name)
 
-		// add delay for next run
-		time.Sleep(time.Duration(interval) * time.Second)
+	return nil
+}
 
-		t, err := tbt.TorrentGet(ctx, []string{"trackerStats"}, []int64{torrentId})
-		if err != nil {
-			return errors.Wrap(err, "reannounced, failed to find torrentid")
-		}
+// transmissionTorrentSetPayload returns the torrent-set payload for the action, nil when there is nothing to set
+func transmissionTorrentSetPayload(action *domain.Action, id int64) *transmissionrpc.TorrentSetPayload {
+	labels := transmissionLabels(action.Label)
 
-		if len(t) < 1 {
-			return errors.Wrap(err, "reannounced, failed to get torrent from id")
-		}
+	if len(labels) == 0 && action.BandwidthGroup == "" && action.LimitUploadSpeed <= 0 && action.LimitDownloadSpeed <= 0 && action.LimitRatio <= 0 && action.LimitSeedTime <= 0 {
+		return nil
+	}
 
-		for _, tracker := range t[0].TrackerStats {
-			tracker := tracker
+	p := &transmissionrpc.TorrentSetPayload{
+		IDs: []int64{id},
+	}
 
-			s.log.Trace().Msgf("transmission tracker: %+v", tracker)
+	if len(labels) > 0 {
+		p.Labels = labels
+	}
+	if action.BandwidthGroup != "" {
+		p.Group = &action.BandwidthGroup
+	}
+	if action.LimitUploadSpeed > 0 {
+		p.UploadLimit = &action.LimitUploadSpeed
+		p.UploadLimited = &TrTrue
+	}
+	if action.LimitDownloadSpeed > 0 {
+		p.DownloadLimit = &action.LimitDownloadSpeed
+		p.DownloadLimited = &TrTrue
+	}
+	if action.LimitRatio > 0 {
+		p.SeedRatioLimit = &action.LimitRatio
+		ratioMode := transmissionrpc.SeedRatioModeCustom
+		p.SeedRatioMode = &ratioMode
+	}
+	if action.LimitSeedTime > 0 {
+		t := time.Duration(action.LimitSeedTime) * time.Minute
+		p.SeedIdleLimit = &t
 
-			if tracker.IsBackup {
-				continue
-			}
+		// seed idle mode 1
+		seedIdleMode := int64(1)
+		p.SeedIdleMode = &seedIdleMode
+	}
 
-			if isUnregistered(tracker.LastAnnounceResult) {
-				continue
-			}
+	return p
+}
 
-			if tracker.SeederCount > 0 {
-				return nil
-			} else if tracker.LeecherCount > 0 {
-				return nil
-			}
+// transmissionLabels splits the comma separated labels, Transmission doesn't allow commas within a label
+func transmissionLabels(label string) []string {
+	var labels []string
+	for _, l := range strings.Split(label, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			labels = append(labels, l)
 		}
+	}
+	return labels
+}
 
-		s.log.Debug().Msgf("transmission re-announce not working yet, lets re-announce %d again attempt: %d/%d", torrentId, attempts, maxAttempts)
+// transmissionReannouncer re-announces a torrent until a tracker reports peers
+type transmissionReannouncer struct {
+	client *transmissionrpc.Client
+	id     int64
+}
 
-		if err := tbt.TorrentReannounceIDs(ctx, []int64{torrentId}); err != nil {
-			return errors.Wrap(err, "failed to reannounce")
-		}
+func (r *transmissionReannouncer) working(ctx context.Context) (bool, string, error) {
+	t, err := r.client.TorrentGet(ctx, []string{"trackerStats"}, []int64{r.id})
+	if err != nil {
+		return false, "", errors.Wrap(err, "reannounced, failed to find torrentid")
+	}
 
-		attempts++
+	if len(t) < 1 {
+		return false, "", errors.New("reannounced, failed to get torrent from id: %d", r.id)
 	}
 
-	if attempts == maxAttempts && action.ReAnnounceDelete {
-		s.log.Info().Msgf("re-announce for %v took too long, deleting torrent", torrentId)
+	message := ""
+
+	for _, tracker := range t[0].TrackerStats {
+		if tracker.IsBackup {
+			continue
+		}
+
+		if tracker.LastAnnounceResult != "" {
+			message = tracker.LastAnnounceResult
+		}
 
-		if err := tbt.TorrentRemove(ctx, transmissionrpc.TorrentRemovePayload{IDs: []int64{torrentId}}); err != nil {
-			return errors.Wrap(err, "could not delete torrent: %v from client after max re-announce attempts reached", torrentId)
+		if isUnregistered(tracker.LastAnnounceResult) {
+			continue
 		}
 
-		return errors.Wrap(ErrReannounceTookTooLong, "transmission re-announce took too long, deleted torrent %v", torrentId)
+		if tracker.SeederCount > 0 || tracker.LeecherCount > 0 {
+			return true, message, nil
+		}
 	}
 
-	return nil
+	return false, message, nil
+}
+
+func (r *transmissionReannouncer) reannounce(ctx context.Context) error {
+	return r.client.TorrentReannounceIDs(ctx, []int64{r.id})
+}
+
+func (r *transmissionReannouncer) remove(ctx context.Context) error {
+	return r.client.TorrentRemove(ctx, transmissionrpc.TorrentRemovePayload{IDs: []int64{r.id}})
 }
 
-func (s *service) transmissionCheckRulesCanDownload(ctx context.Context, action *domain.Action, client *domain.DownloadClient, tbt *transmissionrpc.Client) ([]string, error) {
+func (s *service) transmissionCheckRulesCanDownload(ctx context.Context, action *domain.Action, client *domain.DownloadClient, release *domain.Release, tbt *transmissionrpc.Client) ([]string, error) {
 	s.log.Trace().Msgf("action transmission: %s check rules", action.Name)
 
 	// check for active downloads and other rules
@@ -283,6 +259,8 @@ func (s *service) transmissionCheckRulesCanDownload(ctx context.Context, action
 				return []string{rejection}, nil
 			}
 		}
+
+		return s.checkFreeSpace(ctx, client, release, transmissionFreeSpace(tbt, action.SavePath))
 	}
 
 	return nil, nil
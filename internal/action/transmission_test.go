@@ -0,0 +1,39 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_transmissionTorrentSetPayload(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert.Nil(t, transmissionTorrentSetPayload(&domain.Action{Label: " , "}, 1))
+	})
+
+	t.Run("labels_and_group", func(t *testing.T) {
+		p := transmissionTorrentSetPayload(&domain.Action{Label: "tv, freeleech,,", BandwidthGroup: "racing"}, 1)
+		if assert.NotNil(t, p) {
+			assert.Equal(t, []int64{1}, p.IDs)
+			assert.Equal(t, []string{"tv", "freeleech"}, p.Labels)
+			assert.Equal(t, "racing", *p.Group)
+			assert.Nil(t, p.UploadLimit)
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		p := transmissionTorrentSetPayload(&domain.Action{LimitUploadSpeed: 1024, LimitRatio: 2}, 1)
+		if assert.NotNil(t, p) {
+			assert.Nil(t, p.Labels)
+			assert.Nil(t, p.Group)
+			assert.Equal(t, int64(1024), *p.UploadLimit)
+			assert.True(t, *p.UploadLimited)
+			assert.Equal(t, 2.0, *p.SeedRatioLimit)
+		}
+	})
+}
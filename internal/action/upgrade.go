@@ -0,0 +1,89 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/autobrr/go-rtorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+// replaceOriginals removes the torrents replaced by a PROPER or REPACK from the client before the upgrade is added.
+// The downloaded data is kept. A failed removal is logged and the upgrade is added anyway.
+func (s *service) replaceOriginals(ctx context.Context, client *domain.DownloadClient, release *domain.Release, remove func(ctx context.Context, hashes []string) error) {
+	hashes := release.ReplacedInfoHashes(client.ID)
+	if len(hashes) == 0 {
+		return
+	}
+
+	if err := remove(ctx, hashes); err != nil {
+		s.log.Error().Err(err).Msgf("could not remove torrents %v replaced by %s from client: %s", hashes, release.TorrentName, client.Name)
+		return
+	}
+
+	s.log.Info().Msgf("removed torrents %v replaced by %s from client: %s", hashes, release.TorrentName, client.Name)
+
+	// other actions for the same client don't have to remove them again
+	release.Replaces = slices.DeleteFunc(release.Replaces, func(original domain.ReleaseUpgradeOriginal) bool {
+		return original.ClientID == client.ID
+	})
+}
+
+func qbittorrentRemove(qbt *qbittorrent.Client) func(ctx context.Context, hashes []string) error {
+	return func(ctx context.Context, hashes []string) error {
+		return qbt.DeleteTorrentsCtx(ctx, hashes, false)
+	}
+}
+
+func delugeRemove(del deluge.DelugeClient) func(ctx context.Context, hashes []string) error {
+	return func(ctx context.Context, hashes []string) error {
+		for _, hash := range hashes {
+			if _, err := del.RemoveTorrent(ctx, hash, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func transmissionRemove(tbt *transmissionrpc.Client) func(ctx context.Context, hashes []string) error {
+	return func(ctx context.Context, hashes []string) error {
+		torrents, err := tbt.TorrentGetAllForHashes(ctx, hashes)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, 0, len(torrents))
+		for _, torrent := range torrents {
+			if torrent.ID != nil {
+				ids = append(ids, *torrent.ID)
+			}
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return tbt.TorrentRemove(ctx, transmissionrpc.TorrentRemovePayload{IDs: ids})
+	}
+}
+
+func rtorrentRemove(rt *rtorrent.Client) func(ctx context.Context, hashes []string) error {
+	return func(ctx context.Context, hashes []string) error {
+		for _, hash := range hashes {
+			// rTorrent hashes are upper case
+			if err := rt.Delete(ctx, rtorrent.Torrent{Hash: strings.ToUpper(hash)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_replaceOriginals(t *testing.T) {
+	s := &service{log: zerolog.Nop()}
+	client := &domain.DownloadClient{ID: 1, Name: "qbt"}
+
+	newRelease := func() *domain.Release {
+		return &domain.Release{
+			TorrentName: "Show.Name.S01E01.PROPER.1080p.WEB-DL.x264-GRP",
+			Replaces: []domain.ReleaseUpgradeOriginal{
+				{ClientID: 1, InfoHash: "aaa"},
+				{ClientID: 2, InfoHash: "bbb"},
+				{ClientID: 1, InfoHash: "ccc"},
+			},
+		}
+	}
+
+	t.Run("removes_originals_of_client", func(t *testing.T) {
+		release := newRelease()
+
+		var removed []string
+		s.replaceOriginals(context.Background(), client, release, func(ctx context.Context, hashes []string) error {
+			removed = append(removed, hashes...)
+			return nil
+		})
+
+		assert.Equal(t, []string{"aaa", "ccc"}, removed)
+		assert.Equal(t, []domain.ReleaseUpgradeOriginal{{ClientID: 2, InfoHash: "bbb"}}, release.Replaces)
+
+		// the next action for the client has nothing left to remove
+		s.replaceOriginals(context.Background(), client, release, func(ctx context.Context, hashes []string) error {
+			t.Fatal("unexpected removal")
+			return nil
+		})
+	})
+
+	t.Run("failed_removal", func(t *testing.T) {
+		release := newRelease()
+
+		s.replaceOriginals(context.Background(), client, release, func(ctx context.Context, hashes []string) error {
+			return errors.New("connection refused")
+		})
+
+		assert.Len(t, release.Replaces, 3)
+	})
+}
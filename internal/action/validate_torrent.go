@@ -0,0 +1,53 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/releasedownload"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// validateTorrent checks the torrent file of the release before it's pushed and returns the reasons to reject it.
+// Magnet links and usenet releases have no torrent file to check.
+func (s *service) validateTorrent(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	if !action.ValidateTorrent && action.ValidatePrivate == "" {
+		return nil, nil
+	}
+
+	if release.Protocol != domain.ReleaseProtocolTorrent || release.HasMagnetUri() {
+		s.log.Debug().Msgf("action %s: skip torrent validation for %s, no torrent file", action.Name, release.TorrentName)
+		return nil, nil
+	}
+
+	if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
+		return nil, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(action.ValidateAnnounce, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	rejections, err := s.downloadSvc.ValidateTorrent(ctx, release, releasedownload.TorrentValidation{
+		Announce:      action.ValidateTorrent,
+		AnnounceHosts: hosts,
+		History:       action.ValidateTorrent,
+		Private:       action.ValidatePrivate,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not validate torrent file for release: %s", release.TorrentName)
+	}
+
+	if len(rejections) > 0 {
+		s.log.Info().Msgf("action %s: torrent validation rejected %s: %s", action.Name, release.TorrentName, strings.Join(rejections, ", "))
+	}
+
+	return rejections, nil
+}
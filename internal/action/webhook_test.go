@@ -0,0 +1,82 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_webhook(t *testing.T) {
+	s := &service{log: logger.Mock().With().Logger(), httpClient: http.DefaultClient}
+	release := domain.Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP"}
+
+	t.Run("headers_and_signature", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, `{"name":"Show.Name.S01E01.1080p.WEB-DL.H264-GRP"}`, string(body))
+			assert.Equal(t, "Bearer abc=", r.Header.Get("Authorization"))
+			assert.Equal(t, "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", r.Header.Get("X-Release"))
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write(body)
+			assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(webhookSignatureHeader))
+		}))
+		defer ts.Close()
+
+		action := &domain.Action{
+			Name:           "webhook",
+			WebhookHost:    ts.URL,
+			WebhookMethod:  http.MethodPut,
+			WebhookData:    `{"name":"{{ .TorrentName }}"}`,
+			WebhookHeaders: []string{"Authorization=Bearer abc=", "X-Release={{ .TorrentName }}", ""},
+			WebhookSecret:  "secret",
+		}
+		assert.NoError(t, action.ParseMacros(&release))
+
+		assert.NoError(t, s.webhook(context.Background(), action, release))
+	})
+
+	t.Run("retry_server_error", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer ts.Close()
+
+		action := &domain.Action{Name: "webhook", WebhookHost: ts.URL, WebhookRetryAttempts: 3}
+
+		assert.NoError(t, s.webhook(context.Background(), action, release))
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("no_retry_client_error", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer ts.Close()
+
+		action := &domain.Action{Name: "webhook", WebhookHost: ts.URL, WebhookRetryAttempts: 3}
+
+		assert.ErrorContains(t, s.webhook(context.Background(), action, release), "status code: 401")
+		assert.Equal(t, 1, requests)
+	})
+}
@@ -135,6 +135,8 @@ func (a *announceProcessor) AddLineToQueue(channel string, line string) error {
 		return errors.New("no queue for channel (%v) found", channel)
 	}
 
+	a.releaseSvc.LogAnnounce(a.indexer.Identifier, channel, line)
+
 	queue <- line
 
 	a.log.Trace().Msgf("announce: queued line: %v", line)
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -19,7 +20,7 @@ type Service interface {
 	List(ctx context.Context) ([]domain.APIKey, error)
 	Store(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
-	ValidateAPIKey(ctx context.Context, token string) bool
+	ValidateAPIKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type service struct {
@@ -52,6 +53,15 @@ func (s *service) List(ctx context.Context) ([]domain.APIKey, error) {
 }
 
 func (s *service) Store(ctx context.Context, apiKey *domain.APIKey) error {
+	if err := apiKey.Validate(); err != nil {
+		return err
+	}
+
+	// keys without scopes have full access
+	if apiKey.Scopes == nil {
+		apiKey.Scopes = []string{}
+	}
+
 	apiKey.Key = GenerateSecureToken(16)
 
 	if err := s.repo.Store(ctx, apiKey); err != nil {
@@ -83,23 +93,30 @@ func (s *service) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
-func (s *service) ValidateAPIKey(ctx context.Context, key string) bool {
-	if _, ok := s.keyCache[key]; ok {
+// ValidateAPIKey returns the key if it exists and has not expired
+func (s *service) ValidateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	apiKey, ok := s.keyCache[key]
+	if ok {
 		s.log.Trace().Msgf("api service key cache hit: %s", key)
-		return true
-	}
+	} else {
+		k, err := s.repo.GetKey(ctx, key)
+		if err != nil {
+			s.log.Trace().Msgf("api service key cache invalid key: %s", key)
+			return nil, domain.ErrAPIKeyInvalid
+		}
 
-	apiKey, err := s.repo.GetKey(ctx, key)
-	if err != nil {
-		s.log.Trace().Msgf("api service key cache invalid key: %s", key)
-		return false
-	}
+		s.log.Trace().Msgf("api service key cache miss: %s", key)
 
-	s.log.Trace().Msgf("api service key cache miss: %s", key)
+		apiKey = *k
+		s.keyCache[key] = apiKey
+	}
 
-	s.keyCache[key] = *apiKey
+	if apiKey.Expired(time.Now()) {
+		s.log.Trace().Msgf("api service key expired: %s", key)
+		return nil, domain.ErrAPIKeyExpired
+	}
 
-	return true
+	return &apiKey, nil
 }
 
 func GenerateSecureToken(length int) string {
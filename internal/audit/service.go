@@ -0,0 +1,53 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package audit
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/rs/zerolog"
+)
+
+type Service interface {
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+	Find(ctx context.Context, params domain.AuditQueryParams) (*domain.FindAuditResponse, error)
+}
+
+type service struct {
+	log  zerolog.Logger
+	repo domain.AuditRepo
+}
+
+func NewService(log logger.Logger, repo domain.AuditRepo) Service {
+	return &service{
+		log:  log.With().Str("module", "audit").Logger(),
+		repo: repo,
+	}
+}
+
+// Record stores the change attributed to the user or api key of the request
+func (s *service) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	if user := domain.UserFromContext(ctx); user != nil {
+		entry.Username = user.Username
+	}
+
+	if key := domain.APIKeyFromContext(ctx); key != nil {
+		entry.APIKey = key.Name
+	}
+
+	if err := s.repo.Store(ctx, entry); err != nil {
+		return err
+	}
+
+	s.log.Debug().Msgf("%s %s %s by user %q api key %q", entry.Action, entry.Resource, entry.ResourceID, entry.Username, entry.APIKey)
+
+	return nil
+}
+
+func (s *service) Find(ctx context.Context, params domain.AuditQueryParams) (*domain.FindAuditResponse, error) {
+	return s.repo.Find(ctx, params)
+}
@@ -0,0 +1,510 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// oidcClockSkew is the leeway given to the expiry and issue time of id tokens
+const oidcClockSkew = time.Minute
+
+// OIDCClaims are the claims of a verified id token autobrr cares about
+type OIDCClaims struct {
+	Issuer            string
+	Subject           string
+	PreferredUsername string
+	Email             string
+	Groups            []string
+}
+
+// Username is the name the user is shown as, users are identified by the issuer and subject
+func (c OIDCClaims) Username() string {
+	switch {
+	case c.PreferredUsername != "":
+		return c.PreferredUsername
+	case c.Email != "":
+		return c.Email
+	}
+
+	return c.Subject
+}
+
+// OIDC logs users in with the authorization code flow of an OpenID Connect provider.
+// The provider is discovered on first use so autobrr starts when it is down.
+type OIDC struct {
+	log    zerolog.Logger
+	config domain.OIDCConfig
+	client *http.Client
+
+	m         sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]crypto.PublicKey
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewOIDC(log logger.Logger, config domain.OIDCConfig) *OIDC {
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+
+	return &OIDC{
+		log:    log.With().Str("module", "oidc").Logger(),
+		config: config,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// NewOIDCVerifier returns a random value for the state, nonce and pkce verifier of a login
+func NewOIDCVerifier() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// AuthCodeURL is the url of the provider the user is sent to for logging in
+func (o *OIDC) AuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error) {
+	d, err := o.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", o.config.ClientID)
+	params.Set("redirect_uri", redirectURL)
+	params.Set("scope", "openid profile email "+o.config.GroupsClaim)
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return d.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange trades the authorization code for an id token and verifies it.
+// Users outside the allowed groups are rejected.
+func (o *OIDC) Exchange(ctx context.Context, redirectURL, code, nonce, verifier string) (*OIDCClaims, error) {
+	d, err := o.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create token request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(o.config.ClientSecret))
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not exchange authorization code")
+	}
+	defer res.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return nil, errors.Wrapf(err, "could not decode token response, status: %d", res.StatusCode)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("could not exchange authorization code: %s %s", token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no id token")
+	}
+
+	claims, err := o.Verify(ctx, token.IDToken, nonce, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(o.config.AllowedGroups) > 0 && !slices.ContainsFunc(claims.Groups, func(group string) bool {
+		return slices.Contains(o.config.AllowedGroups, group)
+	}) {
+		return nil, errors.Errorf("user %s is not in any of the allowed groups", claims.Username())
+	}
+
+	return claims, nil
+}
+
+// Verify checks the signature and claims of the id token
+func (o *OIDC) Verify(ctx context.Context, rawIDToken, nonce string, now time.Time) (*OIDCClaims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "could not decode id token header")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode id token signature")
+	}
+
+	key, err := o.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		Issuer            string        `json:"iss"`
+		Subject           string        `json:"sub"`
+		Audience          audienceClaim `json:"aud"`
+		Expiry            float64       `json:"exp"`
+		IssuedAt          float64       `json:"iat"`
+		Nonce             string        `json:"nonce"`
+		PreferredUsername string        `json:"preferred_username"`
+		Email             string        `json:"email"`
+	}
+
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "could not decode id token claims")
+	}
+
+	d, err := o.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case claims.Issuer != d.Issuer:
+		return nil, errors.Errorf("id token issued by %q, expected %q", claims.Issuer, d.Issuer)
+	case !slices.Contains(claims.Audience, o.config.ClientID):
+		return nil, errors.Errorf("id token is not issued for client %q", o.config.ClientID)
+	case claims.Expiry == 0 || now.After(time.Unix(int64(claims.Expiry), 0).Add(oidcClockSkew)):
+		return nil, errors.New("id token expired")
+	case claims.IssuedAt != 0 && now.Add(oidcClockSkew).Before(time.Unix(int64(claims.IssuedAt), 0)):
+		return nil, errors.New("id token issued in the future")
+	case claims.Nonce != nonce:
+		return nil, errors.New("id token nonce mismatch")
+	case claims.Subject == "":
+		return nil, errors.New("id token has no subject")
+	}
+
+	result := &OIDCClaims{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		PreferredUsername: claims.PreferredUsername,
+		Email:             claims.Email,
+	}
+
+	// the groups claim is configurable, some providers send a single group as a string
+	var raw map[string]json.RawMessage
+	if err := decodeJWTPart(parts[1], &raw); err != nil {
+		return nil, errors.Wrap(err, "could not decode id token claims")
+	}
+
+	if groups, ok := raw[o.config.GroupsClaim]; ok {
+		var list audienceClaim
+		if err := json.Unmarshal(groups, &list); err != nil {
+			return nil, errors.Wrapf(err, "invalid id token claim %s", o.config.GroupsClaim)
+		}
+		result.Groups = list
+	}
+
+	return result, nil
+}
+
+// audienceClaim is a string or a list of strings, used for the audience and groups claims
+type audienceClaim []string
+
+func (a *audienceClaim) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audienceClaim{s}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+
+	*a = list
+	return nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256", "PS256":
+		hash = crypto.SHA256
+	case "RS384", "ES384", "PS384":
+		hash = crypto.SHA384
+	case "RS512", "ES512", "PS512":
+		hash = crypto.SHA512
+	default:
+		return errors.Errorf("unsupported id token algorithm %q", alg)
+	}
+
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return errors.Wrap(rsa.VerifyPKCS1v15(k, hash, digest, signature), "invalid id token signature")
+		case "PS":
+			return errors.Wrap(rsa.VerifyPSS(k, hash, digest, signature, nil), "invalid id token signature")
+		}
+
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			break
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid id token signature")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("invalid id token signature")
+		}
+
+		return nil
+	}
+
+	return errors.Errorf("id token algorithm %q does not match the signing key", alg)
+}
+
+func (o *OIDC) discover(ctx context.Context) (*oidcDiscovery, error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(o.config.Issuer, "/")
+
+	var d oidcDiscovery
+	if err := o.getJSON(ctx, issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, errors.Wrap(err, "could not discover openid provider")
+	}
+
+	if strings.TrimSuffix(d.Issuer, "/") != issuer {
+		return nil, errors.Errorf("openid provider issuer %q does not match %q", d.Issuer, o.config.Issuer)
+	}
+
+	o.discovery = &d
+
+	return o.discovery, nil
+}
+
+// publicKey returns the signing key of the provider, the keys are fetched again for unknown key ids to follow key rotation
+func (o *OIDC) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	d, err := o.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if key, ok := o.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := o.getJSON(ctx, d.JWKSURI, &jwks); err != nil {
+		return nil, errors.Wrap(err, "could not fetch openid provider keys")
+	}
+
+	o.keys = make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			o.log.Warn().Err(err).Msgf("skipping openid provider key %s", jwk.Kid)
+			continue
+		}
+
+		o.keys[jwk.Kid] = key
+	}
+
+	if key, ok := o.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, errors.Errorf("unknown id token signing key %q", kid)
+}
+
+// lookupKey finds the key by id, tokens without a key id are signed by the only key
+func (o *OIDC) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(o.keys) == 1 {
+		for _, key := range o.keys {
+			return key, true
+		}
+	}
+
+	key, ok := o.keys[kid]
+	return key, ok
+}
+
+func (o *OIDC) getJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status: %d", res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	decode := func(v string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, errors.Errorf("unsupported key type %q", k.Kty)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]any
+}
+
+// newTestProvider serves discovery, keys and a token endpoint which returns an id token with the claims
+func newTestProvider(t *testing.T) *testProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &testProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "autobrr" || clientSecret != "secret" || r.PostFormValue("code") != "code" || r.PostFormValue("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": p.sign(t, p.claims)})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	p.claims = map[string]any{
+		"iss":                p.URL,
+		"sub":                "1234",
+		"aud":                "autobrr",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"nonce":              "nonce",
+		"preferred_username": "john",
+		"groups":             []string{"media"},
+	}
+
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, claims map[string]any) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (p *testProvider) oidc(allowedGroups ...string) *OIDC {
+	return NewOIDC(logger.Mock(), domain.OIDCConfig{
+		Enabled:       true,
+		Issuer:        p.URL + "/",
+		ClientID:      "autobrr",
+		ClientSecret:  "secret",
+		AllowedGroups: allowedGroups,
+	})
+}
+
+func TestOIDC_AuthCodeURL(t *testing.T) {
+	p := newTestProvider(t)
+
+	u, err := p.oidc().AuthCodeURL(context.Background(), "http://localhost:7474/api/auth/oidc/callback", "state", "nonce", "verifier")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(u)
+	require.NoError(t, err)
+
+	challenge := sha256.Sum256([]byte("verifier"))
+
+	assert.Equal(t, p.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "code", parsed.Query().Get("response_type"))
+	assert.Equal(t, "autobrr", parsed.Query().Get("client_id"))
+	assert.Equal(t, "openid profile email groups", parsed.Query().Get("scope"))
+	assert.Equal(t, "state", parsed.Query().Get("state"))
+	assert.Equal(t, "nonce", parsed.Query().Get("nonce"))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), parsed.Query().Get("code_challenge"))
+}
+
+func TestOIDC_Exchange(t *testing.T) {
+	p := newTestProvider(t)
+
+	claims, err := p.oidc("admins", "media").Exchange(context.Background(), "http://localhost/callback", "code", "nonce", "verifier")
+	require.NoError(t, err)
+	assert.Equal(t, "john", claims.Username())
+	assert.Equal(t, []string{"media"}, claims.Groups)
+
+	_, err = p.oidc("admins").Exchange(context.Background(), "http://localhost/callback", "code", "nonce", "verifier")
+	assert.ErrorContains(t, err, "not in any of the allowed groups")
+
+	_, err = p.oidc().Exchange(context.Background(), "http://localhost/callback", "wrong", "nonce", "verifier")
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestOIDC_Verify(t *testing.T) {
+	p := newTestProvider(t)
+	o := p.oidc()
+
+	tests := []struct {
+		name    string
+		claims  map[string]any
+		wantErr string
+	}{
+		{name: "valid"},
+		{name: "audience_list", claims: map[string]any{"aud": []string{"other", "autobrr"}}},
+		{name: "wrong_issuer", claims: map[string]any{"iss": "https://evil.example.com"}, wantErr: "id token issued by"},
+		{name: "wrong_audience", claims: map[string]any{"aud": "other"}, wantErr: "not issued for client"},
+		{name: "expired", claims: map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}, wantErr: "expired"},
+		{name: "nonce", claims: map[string]any{"nonce": "other"}, wantErr: "nonce mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := map[string]any{}
+			for k, v := range p.claims {
+				claims[k] = v
+			}
+			for k, v := range tt.claims {
+				claims[k] = v
+			}
+
+			_, err := o.Verify(context.Background(), p.sign(t, claims), "nonce", time.Now())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	// tampered claims don't match the signature
+	token := strings.Split(p.sign(t, p.claims), ".")
+	payload, err := json.Marshal(map[string]any{"iss": p.URL, "sub": "admin", "aud": "autobrr", "exp": time.Now().Add(time.Hour).Unix(), "nonce": "nonce"})
+	require.NoError(t, err)
+
+	_, err = o.Verify(context.Background(), token[0]+"."+base64.RawURLEncoding.EncodeToString(payload)+"."+token[2], "nonce", time.Now())
+	assert.ErrorContains(t, err, "invalid id token signature")
+}
@@ -5,6 +5,7 @@ package auth
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -22,18 +23,32 @@ type Service interface {
 	UpdateUser(ctx context.Context, req domain.UpdateUserRequest) error
 	CreateHash(password string) (hash string, err error)
 	ComparePasswordAndHash(password string, hash string) (match bool, err error)
+	ListUsers(ctx context.Context) ([]domain.User, error)
+	FindUserByID(ctx context.Context, id int) (*domain.User, error)
+	AddUser(ctx context.Context, req domain.CreateUserRequest) error
+	UpdateUserRole(ctx context.Context, id int, role domain.UserRole) error
+	DeleteUser(ctx context.Context, id int) error
+	OIDCAuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error)
+	OIDCLogin(ctx context.Context, redirectURL, code, nonce, verifier string) (*domain.User, error)
 }
 
 type service struct {
 	log     zerolog.Logger
 	userSvc user.Service
+	oidc    *OIDC
 }
 
-func NewService(log logger.Logger, userSvc user.Service) Service {
-	return &service{
+func NewService(log logger.Logger, userSvc user.Service, oidcConfig domain.OIDCConfig) Service {
+	s := &service{
 		log:     log.With().Str("module", "auth").Logger(),
 		userSvc: userSvc,
 	}
+
+	if oidcConfig.Enabled {
+		s.oidc = NewOIDC(log, oidcConfig)
+	}
+
+	return s
 }
 
 func (s *service) GetUserCount(ctx context.Context) (int, error) {
@@ -82,10 +97,13 @@ func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest)
 		return err
 	}
 
+	// more users are added by an admin
 	if userCount > 0 {
-		return errors.New("only 1 user account is supported at the moment")
+		return errors.New("onboarding is only available for the first user")
 	}
 
+	req.Role = domain.UserRoleAdmin
+
 	hashed, err := s.CreateHash(req.Password)
 	if err != nil {
 		return errors.New("failed to hash password")
@@ -151,6 +169,134 @@ func (s *service) UpdateUser(ctx context.Context, req domain.UpdateUserRequest)
 	return nil
 }
 
+// OIDCAuthCodeURL is the url of the openid provider the user logs in at
+func (s *service) OIDCAuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error) {
+	if s.oidc == nil {
+		return "", errors.New("oidc login is not enabled")
+	}
+
+	return s.oidc.AuthCodeURL(ctx, redirectURL, state, nonce, verifier)
+}
+
+// OIDCLogin completes the login at the openid provider and returns the user.
+// Users are identified by the issuer and subject of the id token, the preferred username and email can change
+// and other accounts can report the same ones. Users are created on their first login, as admin when they are
+// the first user and read-only otherwise.
+func (s *service) OIDCLogin(ctx context.Context, redirectURL, code, nonce, verifier string) (*domain.User, error) {
+	if s.oidc == nil {
+		return nil, errors.New("oidc login is not enabled")
+	}
+
+	claims, err := s.oidc.Exchange(ctx, redirectURL, code, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Debug().Msgf("oidc login for user %s (%s) with groups: %v", claims.Username(), claims.Subject, claims.Groups)
+
+	u, err := s.userSvc.FindByOIDCSubject(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		return u, nil
+	} else if !errors.Is(err, domain.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	username, err := s.oidcUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	userCount, err := s.userSvc.GetUserCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	role := domain.UserRoleReadOnly
+	if userCount == 0 {
+		role = domain.UserRoleAdmin
+	}
+
+	// oidc users have no password and can't use the built-in login
+	req := domain.CreateUserRequest{Username: username, Role: role, OIDCIssuer: claims.Issuer, OIDCSubject: claims.Subject}
+	if err := s.userSvc.CreateUser(ctx, req); err != nil {
+		return nil, errors.Wrapf(err, "could not create oidc user: %s", username)
+	}
+
+	s.log.Info().Msgf("created %s user %s on first oidc login", role, username)
+
+	return s.userSvc.FindByOIDCSubject(ctx, claims.Issuer, claims.Subject)
+}
+
+// oidcUsername returns the name of a new oidc user, the subject is added when another user has the name already.
+// Users created by oidc logins before the subject was stored aren't taken over by name,
+// they can be given a password with autobrrctl.
+func (s *service) oidcUsername(ctx context.Context, claims *OIDCClaims) (string, error) {
+	username := claims.Username()
+
+	existing, err := s.userSvc.FindByUsername(ctx, username)
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		return username, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if existing.Password == "" && existing.OIDCSubject == "" {
+		s.log.Warn().Msgf("oidc user %s was created before users were identified by their subject, the login creates another user", username)
+	}
+
+	username = fmt.Sprintf("%s (%s)", username, claims.Subject)
+	if _, err := s.userSvc.FindByUsername(ctx, username); err == nil {
+		return "", errors.Errorf("oidc user %s already exists", username)
+	} else if !errors.Is(err, domain.ErrRecordNotFound) {
+		return "", err
+	}
+
+	return username, nil
+}
+
+func (s *service) ListUsers(ctx context.Context) ([]domain.User, error) {
+	return s.userSvc.List(ctx)
+}
+
+func (s *service) FindUserByID(ctx context.Context, id int) (*domain.User, error) {
+	return s.userSvc.FindByID(ctx, id)
+}
+
+// AddUser creates another user, read-only unless the role is set
+func (s *service) AddUser(ctx context.Context, req domain.CreateUserRequest) error {
+	if req.Username == "" {
+		return errors.New("validation error: empty username supplied")
+	} else if req.Password == "" {
+		return errors.New("validation error: empty password supplied")
+	}
+
+	if req.Role == "" {
+		req.Role = domain.UserRoleReadOnly
+	}
+
+	hashed, err := s.CreateHash(req.Password)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	req.Password = hashed
+
+	if err := s.userSvc.CreateUser(ctx, req); err != nil {
+		s.log.Error().Err(err).Msgf("could not create user: %s", req.Username)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateUserRole(ctx context.Context, id int, role domain.UserRole) error {
+	return s.userSvc.UpdateRole(ctx, id, role)
+}
+
+func (s *service) DeleteUser(ctx context.Context, id int) error {
+	return s.userSvc.Delete(ctx, id)
+}
+
 func (s *service) ComparePasswordAndHash(password string, hash string) (match bool, err error) {
 	return argon2id.ComparePasswordAndHash(password, hash)
 }
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/user"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserService struct {
+	user.Service
+	users []*domain.User
+}
+
+func (s *mockUserService) GetUserCount(_ context.Context) (int, error) {
+	return len(s.users), nil
+}
+
+func (s *mockUserService) FindByUsername(_ context.Context, username string) (*domain.User, error) {
+	for _, u := range s.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+func (s *mockUserService) FindByOIDCSubject(_ context.Context, issuer, subject string) (*domain.User, error) {
+	for _, u := range s.users {
+		if u.OIDCIssuer == issuer && u.OIDCSubject == subject {
+			return u, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+func (s *mockUserService) CreateUser(_ context.Context, req domain.CreateUserRequest) error {
+	s.users = append(s.users, &domain.User{
+		ID:          len(s.users) + 1,
+		Username:    req.Username,
+		Password:    req.Password,
+		Role:        req.Role,
+		OIDCIssuer:  req.OIDCIssuer,
+		OIDCSubject: req.OIDCSubject,
+	})
+
+	return nil
+}
+
+func TestService_OIDCLogin(t *testing.T) {
+	p := newTestProvider(t)
+	users := &mockUserService{}
+	s := &service{log: zerolog.Nop(), userSvc: users, oidc: p.oidc()}
+
+	login := func() *domain.User {
+		u, err := s.OIDCLogin(context.Background(), "http://localhost/callback", "code", "nonce", "verifier")
+		require.NoError(t, err)
+		return u
+	}
+
+	// the first user is admin
+	admin := login()
+	assert.Equal(t, "john", admin.Username)
+	assert.Equal(t, domain.UserRoleAdmin, admin.Role)
+	assert.Equal(t, p.URL, admin.OIDCIssuer)
+	assert.Equal(t, "1234", admin.OIDCSubject)
+
+	// the name can change
+	p.claims["preferred_username"] = "johnny"
+	assert.Equal(t, admin.ID, login().ID)
+
+	// another account with the same name is another user
+	p.claims["sub"] = "5678"
+	p.claims["preferred_username"] = "john"
+	other := login()
+	assert.NotEqual(t, admin.ID, other.ID)
+	assert.Equal(t, "john (5678)", other.Username)
+	assert.Equal(t, domain.UserRoleReadOnly, other.Role)
+
+	// and can't take over local users either
+	users.users = append(users.users, &domain.User{ID: 10, Username: "jane", Password: "hash", Role: domain.UserRoleAdmin})
+	p.claims["sub"] = "9012"
+	p.claims["preferred_username"] = "jane"
+	jane := login()
+	assert.NotEqual(t, 10, jane.ID)
+	assert.Equal(t, domain.UserRoleReadOnly, jane.Role)
+}
@@ -33,11 +33,27 @@ var configTemplate = `# config.toml
 host = "{{ .host }}"
 
 # Port
+# Set to 0 to only listen on the unix socket.
 #
 # Default: 7474
 #
 port = 7474
 
+# Unix socket
+# Listen on a unix socket as well, eg. for nginx with proxy_pass http://unix:/run/autobrr/autobrr.sock
+# Set the X-Real-IP header in the proxy so rate limits apply per client ip, otherwise each connection of the socket is limited on its own.
+#
+# Optional
+#
+#unixSocket = "/run/autobrr/autobrr.sock"
+
+# Unix socket permissions
+# Octal file mode of the unix socket.
+#
+# Default: "0660"
+#
+#unixSocketPermissions = "0660"
+
 # Base url
 # Set custom baseUrl eg /autobrr/ to serve in subdirectory.
 # Not needed for subdomain, or by accessing with the :port directly.
@@ -82,6 +98,79 @@ logLevel = "DEBUG"
 #
 checkForUpdates = true
 
+# Feed consistency check
+# Compare releases from feeds against IRC announces for the same indexer and log releases missed by either.
+# Useful to detect broken announce parsing.
+#
+# Default: false
+#
+#feedConsistencyCheck = false
+
+# Announce history buffer size
+# Number of recent announces kept in memory for the feed consistency check. Older entries are moved to the database.
+#
+# Default: 1000
+#
+#announceHistoryBufferSize = 1000
+
+# Announce history spill batch size
+# Number of entries written to the database at once when the buffer is full.
+#
+# Default: 100
+#
+#announceHistorySpillBatchSize = 100
+
+# Push budget per hour
+# Max size of releases sent to download clients per hour, eg. 50GB. When exceeded, matched releases are queued
+# by filter priority until the next hour. Releases with unknown size are not limited.
+#
+# Default: "" (disabled)
+#
+#pushBudgetPerHour = ""
+
+# Dedupe window
+# Seconds after a push during which the same release is not pushed again by another filter or indexer.
+# Releases are matched by title, ignoring case and separators, and by infohash when known.
+# Filters can opt out with "Skip dedupe".
+#
+# Default: 0 (disabled)
+#
+#dedupeWindow = 0
+
+# Size mismatch threshold
+# Compare the size reported by the download client after the push with the size claimed by the announce and
+# notify when they differ by more than this percentage. Catches mislabeled announces and wrong category grabs.
+# Only supported for qBittorrent.
+#
+# Default: 0 (disabled)
+#
+#sizeMismatchThreshold = 0
+
+# Completion check interval
+# Poll the download clients every this many minutes for torrents pushed in the last 7 days, store when they
+# finished downloading, send a Download Complete notification and run the completion hooks of the action.
+# Only supported for qBittorrent and Transmission.
+#
+# Default: 0 (disabled)
+#
+#completionCheckInterval = 0
+
+# Filter trash retention
+# Deleted filters are kept in the trash and can be restored until they are purged after this many days.
+# Set to 0 to keep trashed filters until they are purged manually.
+#
+# Default: 30
+#
+#filterTrashDays = 30
+
+# Orphaned data cleanup
+# Remove rows left behind by deleted releases, filters, clients and feeds once a day.
+# Run "autobrrctl db:cleanup --dry-run" to see what would be removed first.
+#
+# Default: false
+#
+#orphanCleanup = false
+
 # Session secret
 #
 sessionSecret = "{{ .sessionSecret }}"
@@ -94,6 +183,133 @@ sessionSecret = "{{ .sessionSecret }}"
 #
 # Default: 6060
 #profilingPort = 6060
+
+# Prometheus metrics, served on their own listener at /metrics
+#
+#metricsEnabled = false
+#
+#metricsHost = "127.0.0.1"
+#
+# Default: 9074
+#metricsPort = 9074
+
+# Processing pipeline per indexer
+# Keep tables like this at the end of the file.
+# Stages run in order for each matched filter: duplicateCheck, enrichment, external, delay.
+# Remove a stage to skip it, delay must be last. forceDelay replaces the filter delay in seconds.
+# The effective pipeline is shown at /api/indexer/{id}/pipeline.
+#
+# Default: all stages in the order above
+#
+#[pipeline.ptp]
+#stages = ["enrichment", "duplicateCheck", "external", "delay"]
+#forceDelay = 0
+
+# Storage for generated files like database backups, release exports and raw announce logs
+# Keep tables like this at the end of the file.
+# Files are stored under backups/, exports/ and announces/ with their own retention.
+# Type is local or s3. Local paths are relative to the config dir.
+# Database backups are only supported for sqlite.
+# announceLogSchedule writes the raw announce lines received since the last run.
+#
+# Default: local storage in the storage dir, no retention, no scheduled backups and no announce log
+#
+#[storage]
+#type = "local"
+#path = "storage"
+#keep = 7
+#maxAgeDays = 0
+#backupSchedule = "0 3 * * *"
+#announceLogSchedule = "0 * * * *"
+#
+# S3 compatible storage, eg. AWS, Backblaze B2, MinIO. Set pathStyle for MinIO.
+#
+#type = "s3"
+#endpoint = "https://s3.us-east-1.amazonaws.com"
+#region = "us-east-1"
+#bucket = "autobrr"
+#prefix = "autobrr"
+#accessKey = ""
+#secretKey = ""
+#pathStyle = false
+#
+# Retention per artifact replaces keep and maxAgeDays above
+#
+#[storage.retention.announces]
+#keep = 0
+#maxAgeDays = 14
+
+# Cross-seed instance searched for cross-seedable copies of pushed torrents
+# Keep tables like this at the end of the file.
+# Enable the search per action, on push or once the torrent completed.
+# The api key is sent as the X-Api-Key header.
+#
+# Default: not configured
+#
+#[crossSeed]
+#host = "http://127.0.0.1:2468"
+#apiKey = ""
+
+# TMDB api used to resolve the tmdb, tvdb and imdb ids of releases for the match ids of filters
+# Keep tables like this at the end of the file.
+# Resolved ids are cached for a day.
+#
+# Default: not configured
+#
+#[tmdb]
+#apiKey = ""
+
+# OpenID Connect login, eg. with Authentik, Keycloak or Authelia
+# Keep tables like this at the end of the file.
+# Register autobrr at the provider with the redirect url https://autobrr.example.com/api/auth/oidc/callback,
+# include the base url if set. The redirect url is derived from the request when not set.
+# Users are allowed when they are in any of the allowed groups, everyone can log in when empty.
+#
+# Default: disabled
+#
+#[oidc]
+#enabled = false
+#issuer = "https://auth.example.com/application/o/autobrr/"
+#clientId = ""
+#clientSecret = ""
+#redirectUrl = ""
+#groupsClaim = "groups"
+#allowedGroups = []
+#disableBuiltInLogin = false
+
+# API rate limits in requests per minute, 0 is unlimited
+# Keep tables like this at the end of the file.
+# perIp applies to every request of a client ip, the web ui included.
+# perKey applies to requests made with an api key on top of the limit of the ip.
+# auth applies to logins and onboarding of a client ip.
+# Clients over the limit get 429 Too Many Requests with a Retry-After header.
+#
+# Default: unlimited
+#
+#[rateLimit]
+#perIp = 0
+#perKey = 0
+#auth = 0
+
+# Serve https on host and port without a reverse proxy
+# Keep tables like this at the end of the file.
+# Use your own certificate with certFile and keyFile, they are reloaded when changed.
+# Or leave them empty to get a certificate for acmeDomains from Let's Encrypt, or the CA of acmeDirectory.
+# The CA must reach autobrr on port 443 for tls-alpn-01, or on acmeHttpPort for http-01 challenges.
+# acmeHttpPort also redirects plain http to https, 0 disables it.
+# acmeCacheDir keeps the account and certificates, certs in the config directory by default.
+#
+# Default: disabled
+#
+#[tls]
+#enabled = false
+#certFile = ""
+#keyFile = ""
+#acmeDomains = ["autobrr.example.com"]
+#acmeEmail = ""
+#acmeDirectory = ""
+#acmeCacheDir = ""
+#acmeHttpPort = 80
 `
 
 func (c *AppConfig) writeConfig(configPath string, configFile string) error {
@@ -231,6 +447,30 @@ func (c *AppConfig) defaults() {
 		ProfilingEnabled:    false,
 		ProfilingHost:       "127.0.0.1",
 		ProfilingPort:       6060,
+		MetricsEnabled:      false,
+		MetricsHost:         "127.0.0.1",
+		MetricsPort:         9074,
+
+		FeedConsistencyCheck:          false,
+		AnnounceHistoryBufferSize:     1000,
+		AnnounceHistorySpillBatchSize: 100,
+
+		UnixSocketPermissions: "0660",
+
+		FilterTrashDays: 30,
+
+		OIDC: domain.OIDCConfig{
+			GroupsClaim: "groups",
+		},
+
+		Storage: domain.StorageConfig{
+			Type: domain.StorageTypeLocal,
+			Path: "storage",
+		},
+
+		TLS: domain.TLSConfig{
+			ACMEHTTPPort: 80,
+		},
 	}
 
 }
@@ -249,6 +489,14 @@ func (c *AppConfig) loadFromEnv() {
 		}
 	}
 
+	if v := os.Getenv(prefix + "UNIX_SOCKET"); v != "" {
+		c.Config.UnixSocket = v
+	}
+
+	if v := os.Getenv(prefix + "UNIX_SOCKET_PERMISSIONS"); v != "" {
+		c.Config.UnixSocketPermissions = v
+	}
+
 	if v := os.Getenv(prefix + "BASE_URL"); v != "" {
 		c.Config.BaseURL = v
 	}
@@ -324,6 +572,168 @@ func (c *AppConfig) loadFromEnv() {
 		c.Config.PostgresExtraParams = v
 	}
 
+	if v := os.Getenv(prefix + "FEED_CONSISTENCY_CHECK"); v != "" {
+		c.Config.FeedConsistencyCheck = strings.EqualFold(strings.ToLower(v), "true")
+	}
+
+	if v := os.Getenv(prefix + "ANNOUNCE_HISTORY_BUFFER_SIZE"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.AnnounceHistoryBufferSize = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "ANNOUNCE_HISTORY_SPILL_BATCH_SIZE"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.AnnounceHistorySpillBatchSize = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "PUSH_BUDGET_PER_HOUR"); v != "" {
+		c.Config.PushBudgetPerHour = v
+	}
+
+	if v := os.Getenv(prefix + "DEDUPE_WINDOW"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.DedupeWindow = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "SIZE_MISMATCH_THRESHOLD"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.SizeMismatchThreshold = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "COMPLETION_CHECK_INTERVAL"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.CompletionCheckInterval = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "FILTER_TRASH_DAYS"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.FilterTrashDays = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "ORPHAN_CLEANUP"); v != "" {
+		c.Config.OrphanCleanup = strings.EqualFold(strings.ToLower(v), "true")
+	}
+
+	if v := os.Getenv(prefix + "STORAGE_ACCESS_KEY"); v != "" {
+		c.Config.Storage.AccessKey = v
+	}
+
+	if v := os.Getenv(prefix + "STORAGE_SECRET_KEY"); v != "" {
+		c.Config.Storage.SecretKey = v
+	}
+
+	if v := os.Getenv(prefix + "CROSS_SEED_HOST"); v != "" {
+		c.Config.CrossSeed.Host = v
+	}
+
+	if v := os.Getenv(prefix + "CROSS_SEED_API_KEY"); v != "" {
+		c.Config.CrossSeed.APIKey = v
+	}
+
+	if v := os.Getenv(prefix + "TMDB_API_KEY"); v != "" {
+		c.Config.TMDB.APIKey = v
+	}
+
+	if v := os.Getenv(prefix + "OIDC_ENABLED"); v != "" {
+		c.Config.OIDC.Enabled = strings.EqualFold(strings.ToLower(v), "true")
+	}
+
+	if v := os.Getenv(prefix + "OIDC_ISSUER"); v != "" {
+		c.Config.OIDC.Issuer = v
+	}
+
+	if v := os.Getenv(prefix + "OIDC_CLIENT_ID"); v != "" {
+		c.Config.OIDC.ClientID = v
+	}
+
+	if v := os.Getenv(prefix + "OIDC_CLIENT_SECRET"); v != "" {
+		c.Config.OIDC.ClientSecret = v
+	}
+
+	if v := os.Getenv(prefix + "OIDC_REDIRECT_URL"); v != "" {
+		c.Config.OIDC.RedirectURL = v
+	}
+
+	if v := os.Getenv(prefix + "OIDC_GROUPS_CLAIM"); v != "" {
+		c.Config.OIDC.GroupsClaim = v
+	}
+
+	if v := os.Getenv(prefix + "OIDC_ALLOWED_GROUPS"); v != "" {
+		c.Config.OIDC.AllowedGroups = strings.Split(v, ",")
+	}
+
+	if v := os.Getenv(prefix + "OIDC_DISABLE_BUILT_IN_LOGIN"); v != "" {
+		c.Config.OIDC.DisableBuiltInLogin = strings.EqualFold(strings.ToLower(v), "true")
+	}
+
+	if v := os.Getenv(prefix + "RATE_LIMIT_PER_IP"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.RateLimit.PerIP = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "RATE_LIMIT_PER_KEY"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.RateLimit.PerKey = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "RATE_LIMIT_AUTH"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.RateLimit.Auth = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "TLS_ENABLED"); v != "" {
+		c.Config.TLS.Enabled = strings.EqualFold(strings.ToLower(v), "true")
+	}
+
+	if v := os.Getenv(prefix + "TLS_CERT_FILE"); v != "" {
+		c.Config.TLS.CertFile = v
+	}
+
+	if v := os.Getenv(prefix + "TLS_KEY_FILE"); v != "" {
+		c.Config.TLS.KeyFile = v
+	}
+
+	if v := os.Getenv(prefix + "TLS_ACME_DOMAINS"); v != "" {
+		c.Config.TLS.ACMEDomains = strings.Split(v, ",")
+	}
+
+	if v := os.Getenv(prefix + "TLS_ACME_EMAIL"); v != "" {
+		c.Config.TLS.ACMEEmail = v
+	}
+
+	if v := os.Getenv(prefix + "TLS_ACME_DIRECTORY"); v != "" {
+		c.Config.TLS.ACMEDirectory = v
+	}
+
+	if v := os.Getenv(prefix + "TLS_ACME_CACHE_DIR"); v != "" {
+		c.Config.TLS.ACMECacheDir = v
+	}
+
+	if v := os.Getenv(prefix + "TLS_ACME_HTTP_PORT"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.TLS.ACMEHTTPPort = int(i)
+		}
+	}
+
 	if v := os.Getenv(prefix + "PROFILING_ENABLED"); v != "" {
 		c.Config.ProfilingEnabled = strings.EqualFold(strings.ToLower(v), "true")
 	}
@@ -338,6 +748,21 @@ func (c *AppConfig) loadFromEnv() {
 			c.Config.ProfilingPort = int(i)
 		}
 	}
+
+	if v := os.Getenv(prefix + "METRICS_ENABLED"); v != "" {
+		c.Config.MetricsEnabled = strings.EqualFold(strings.ToLower(v), "true")
+	}
+
+	if v := os.Getenv(prefix + "METRICS_HOST"); v != "" {
+		c.Config.MetricsHost = v
+	}
+
+	if v := os.Getenv(prefix + "METRICS_PORT"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.MetricsPort = int(i)
+		}
+	}
 }
 
 func validDatabaseType(v string) bool {
@@ -384,6 +809,27 @@ func (c *AppConfig) load(configPath string) {
 	if err := viper.Unmarshal(c.Config); err != nil {
 		log.Fatalf("Could not unmarshal config file: %v: err %q", viper.ConfigFileUsed(), err)
 	}
+
+	c.normalizePipeline()
+}
+
+// normalizePipeline lowercases the indexer keys of the pipeline tables to match the indexer identifiers
+func (c *AppConfig) normalizePipeline() {
+	if len(c.Config.Pipeline) == 0 {
+		return
+	}
+
+	pipeline := make(map[string]domain.PipelineConfig, len(c.Config.Pipeline))
+	for indexer, cfg := range c.Config.Pipeline {
+		key := strings.ToLower(indexer)
+		if _, ok := pipeline[key]; ok {
+			log.Printf("duplicate pipeline for indexer %q with different case, only one is used", key)
+		}
+
+		pipeline[key] = cfg
+	}
+
+	c.Config.Pipeline = pipeline
 }
 
 func (c *AppConfig) DynamicReload(log logger.Logger) {
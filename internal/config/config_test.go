@@ -56,3 +56,26 @@ func TestAppConfig_processLines(t *testing.T) {
 		})
 	}
 }
+
+func TestAppConfig_normalizePipeline(t *testing.T) {
+	c := &AppConfig{
+		Config: &domain.Config{
+			Pipeline: map[string]domain.PipelineConfig{
+				"PTP": {Stages: []string{"enrichment", "delay"}},
+				"red": {ForceDelay: 5},
+			},
+		},
+		m: new(sync.Mutex),
+	}
+
+	c.normalizePipeline()
+
+	assert.Equal(t, map[string]domain.PipelineConfig{
+		"ptp": {Stages: []string{"enrichment", "delay"}},
+		"red": {ForceDelay: 5},
+	}, c.Config.Pipeline)
+
+	p, err := c.Config.IndexerPipeline("PTP")
+	assert.NoError(t, err)
+	assert.True(t, p.Custom)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 )
 
@@ -56,26 +57,57 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, filterID int, active *b
 			"a.ignore_rules",
 			"a.first_last_piece_prio",
 			"a.skip_hash_check",
+			"a.freeleech_token",
+			"a.freeleech_token_min_size",
 			"a.content_layout",
 			"a.priority",
 			"a.limit_download_speed",
 			"a.limit_upload_speed",
 			"a.limit_ratio",
 			"a.limit_seed_time",
+			"a.limit_inactive_seed_time",
+			"a.max_connections",
 			"a.reannounce_skip",
 			"a.reannounce_delete",
 			"a.reannounce_interval",
 			"a.reannounce_max_attempts",
+			"a.reannounce_jitter",
+			"a.require_approval",
+			"a.remove_ratio",
+			"a.remove_seed_time",
+			"a.remove_after_days",
+			"a.remove_delete_data",
+			"a.validate_torrent",
+			"a.run_condition",
+			"a.dupe_key",
+			"a.cross_seed",
+			"a.completion_hooks",
+			"a.validate_announce",
+			"a.validate_private",
+			"a.client_pool_mode",
+			"a.move_completed_path",
+			"a.bandwidth_group",
+			"a.custom1",
+			"a.custom2",
+			"a.exec_timeout",
+			"a.exec_env",
+			"a.failover_clients",
+			"a.exec_dir",
 			"a.webhook_host",
 			"a.webhook_type",
 			"a.webhook_method",
 			"a.webhook_data",
+			"a.webhook_headers",
+			"a.webhook_secret",
+			"a.webhook_retry_attempts",
+			"a.webhook_retry_delay_seconds",
 			"a.external_client_id",
 			"a.external_client",
 			"a.client_id",
 		).
 		From("action a").
-		Where(sq.Eq{"a.filter_id": filterID})
+		Where(sq.Eq{"a.filter_id": filterID}).
+		OrderBy("a.id ASC")
 
 	if active != nil {
 		queryBuilder = queryBuilder.Where(sq.Eq{"enabled": *active})
@@ -97,17 +129,21 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, filterID int, active *b
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient, runCondition, dupeKey, crossSeed, completionHooks, validateAnnounce, validatePrivate, clientPoolMode, moveCompletedPath, bandwidthGroup, custom1, custom2, execDir, webhookSecret sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 
 		var externalClientID, clientID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &externalClient, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &a.FreeleechToken, &a.FreeleechTokenMinSize, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.LimitInactiveSeedTime, &a.MaxConnections, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceJitter, &a.RequireApproval, &a.RemoveRatio, &a.RemoveSeedTime, &a.RemoveAfterDays, &a.RemoveDeleteData, &a.ValidateTorrent, &runCondition, &dupeKey, &crossSeed, &completionHooks, &validateAnnounce, &validatePrivate, &clientPoolMode, &moveCompletedPath, &bandwidthGroup, &custom1, &custom2, &a.ExecTimeout, pq.Array(&a.ExecEnv), pq.Array(&a.FailoverClientIDs), &execDir, &webhookHost, &webhookType, &webhookMethod, &webhookData, pq.Array(&a.WebhookHeaders), &webhookSecret, &a.WebhookRetryAttempts, &a.WebhookRetryDelaySeconds, &externalClientID, &externalClient, &clientID); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		if a.CompletionHooks, err = unmarshalCompletionHooks(completionHooks.String); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal completion hooks")
+		}
+
 		a.ExecCmd = execCmd.String
 		a.ExecArgs = execArgs.String
 		a.WatchFolder = watchFolder.String
@@ -129,9 +165,21 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, filterID int, active *b
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.WebhookSecret = webhookSecret.String
 
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ExternalDownloadClient = externalClient.String
+		a.RunCondition = domain.ActionRunCondition(runCondition.String)
+		a.DupeKey = dupeKey.String
+		a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
+		a.ValidateAnnounce = validateAnnounce.String
+		a.ValidatePrivate = domain.TorrentPrivacy(validatePrivate.String)
+		a.ClientPoolMode = domain.ClientPoolMode(clientPoolMode.String)
+		a.MoveCompletedPath = moveCompletedPath.String
+		a.BandwidthGroup = bandwidthGroup.String
+		a.Custom1 = custom1.String
+		a.Custom2 = custom2.String
+		a.ExecDir = execDir.String
 		a.ClientID = clientID.Int32
 
 		actions = append(actions, &a)
@@ -162,20 +210,50 @@ func (r *ActionRepo) findByFilterIDWithClient(ctx context.Context, filterID int,
 			"a.ignore_rules",
 			"a.first_last_piece_prio",
 			"a.skip_hash_check",
+			"a.freeleech_token",
+			"a.freeleech_token_min_size",
 			"a.content_layout",
 			"a.priority",
 			"a.limit_download_speed",
 			"a.limit_upload_speed",
 			"a.limit_ratio",
 			"a.limit_seed_time",
+			"a.limit_inactive_seed_time",
+			"a.max_connections",
 			"a.reannounce_skip",
 			"a.reannounce_delete",
 			"a.reannounce_interval",
 			"a.reannounce_max_attempts",
+			"a.reannounce_jitter",
+			"a.require_approval",
+			"a.remove_ratio",
+			"a.remove_seed_time",
+			"a.remove_after_days",
+			"a.remove_delete_data",
+			"a.validate_torrent",
+			"a.run_condition",
+			"a.dupe_key",
+			"a.cross_seed",
+			"a.completion_hooks",
+			"a.validate_announce",
+			"a.validate_private",
+			"a.client_pool_mode",
+			"a.move_completed_path",
+			"a.bandwidth_group",
+			"a.custom1",
+			"a.custom2",
+			"a.exec_timeout",
+			"a.exec_env",
+			"a.failover_clients",
+			"a.exec_dir",
 			"a.webhook_host",
 			"a.webhook_type",
 			"a.webhook_method",
 			"a.webhook_data",
+			"a.webhook_headers",
+			"a.webhook_secret",
+			"a.webhook_retry_attempts",
+			"a.webhook_retry_delay_seconds",
 			"a.external_client_id",
 			"a.external_client",
 			"a.client_id",
@@ -193,7 +271,8 @@ func (r *ActionRepo) findByFilterIDWithClient(ctx context.Context, filterID int,
 		).
 		From("action a").
 		Join("client c ON a.client_id = c.id").
-		Where(sq.Eq{"a.filter_id": filterID})
+		Where(sq.Eq{"a.filter_id": filterID}).
+		OrderBy("a.id ASC")
 
 	if active != nil {
 		queryBuilder = queryBuilder.Where(sq.Eq{"enabled": *active})
@@ -216,7 +295,7 @@ func (r *ActionRepo) findByFilterIDWithClient(ctx context.Context, filterID int,
 		var a domain.Action
 		var c domain.DownloadClient
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient, runCondition, dupeKey, crossSeed, completionHooks, validateAnnounce, validatePrivate, clientPoolMode, moveCompletedPath, bandwidthGroup, custom1, custom2, execDir, webhookSecret sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 
@@ -227,10 +306,14 @@ func (r *ActionRepo) findByFilterIDWithClient(ctx context.Context, filterID int,
 		var clientName, clientType, clientHost, clientUsername, clientPassword, clientSettings sql.Null[string]
 		var clientEnabled, clientTLS, clientTLSSkip sql.Null[bool]
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &externalClient, &clientID, &clientClientId, &clientName, &clientType, &clientEnabled, &clientHost, &clientPort, &clientTLS, &clientTLSSkip, &clientUsername, &clientPassword, &clientSettings); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &a.FreeleechToken, &a.FreeleechTokenMinSize, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.LimitInactiveSeedTime, &a.MaxConnections, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceJitter, &a.RequireApproval, &a.RemoveRatio, &a.RemoveSeedTime, &a.RemoveAfterDays, &a.RemoveDeleteData, &a.ValidateTorrent, &runCondition, &dupeKey, &crossSeed, &completionHooks, &validateAnnounce, &validatePrivate, &clientPoolMode, &moveCompletedPath, &bandwidthGroup, &custom1, &custom2, &a.ExecTimeout, pq.Array(&a.ExecEnv), pq.Array(&a.FailoverClientIDs), &execDir, &webhookHost, &webhookType, &webhookMethod, &webhookData, pq.Array(&a.WebhookHeaders), &webhookSecret, &a.WebhookRetryAttempts, &a.WebhookRetryDelaySeconds, &externalClientID, &externalClient, &clientID, &clientClientId, &clientName, &clientType, &clientEnabled, &clientHost, &clientPort, &clientTLS, &clientTLSSkip, &clientUsername, &clientPassword, &clientSettings); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		if a.CompletionHooks, err = unmarshalCompletionHooks(completionHooks.String); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal completion hooks")
+		}
+
 		a.ExecCmd = execCmd.String
 		a.ExecArgs = execArgs.String
 		a.WatchFolder = watchFolder.String
@@ -252,9 +335,21 @@ func (r *ActionRepo) findByFilterIDWithClient(ctx context.Context, filterID int,
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.WebhookSecret = webhookSecret.String
 
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ExternalDownloadClient = externalClient.String
+		a.RunCondition = domain.ActionRunCondition(runCondition.String)
+		a.DupeKey = dupeKey.String
+		a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
+		a.ValidateAnnounce = validateAnnounce.String
+		a.ValidatePrivate = domain.TorrentPrivacy(validatePrivate.String)
+		a.ClientPoolMode = domain.ClientPoolMode(clientPoolMode.String)
+		a.MoveCompletedPath = moveCompletedPath.String
+		a.BandwidthGroup = bandwidthGroup.String
+		a.Custom1 = custom1.String
+		a.Custom2 = custom2.String
+		a.ExecDir = execDir.String
 		a.ClientID = clientID.Int32
 
 		c.ID = clientClientId.V
@@ -336,26 +431,57 @@ func (r *ActionRepo) findByFilterIDTx(ctx context.Context, tx *Tx, filterID int,
 			"ignore_rules",
 			"first_last_piece_prio",
 			"skip_hash_check",
+			"freeleech_token",
+			"freeleech_token_min_size",
 			"content_layout",
 			"priority",
 			"limit_download_speed",
 			"limit_upload_speed",
 			"limit_ratio",
 			"limit_seed_time",
+			"limit_inactive_seed_time",
+			"max_connections",
 			"reannounce_skip",
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_jitter",
+			"require_approval",
+			"remove_ratio",
+			"remove_seed_time",
+			"remove_after_days",
+			"remove_delete_data",
+			"validate_torrent",
+			"run_condition",
+			"dupe_key",
+			"cross_seed",
+			"completion_hooks",
+			"validate_announce",
+			"validate_private",
+			"client_pool_mode",
+			"move_completed_path",
+			"bandwidth_group",
+			"custom1",
+			"custom2",
+			"exec_timeout",
+			"exec_env",
+			"failover_clients",
+			"exec_dir",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"webhook_headers",
+			"webhook_secret",
+			"webhook_retry_attempts",
+			"webhook_retry_delay_seconds",
 			"external_client_id",
 			"external_client",
 			"client_id",
 		).
 		From("action").
-		Where(sq.Eq{"filter_id": filterID})
+		Where(sq.Eq{"filter_id": filterID}).
+		OrderBy("id ASC")
 
 	if active != nil {
 		queryBuilder = queryBuilder.Where(sq.Eq{"enabled": *active})
@@ -377,17 +503,21 @@ func (r *ActionRepo) findByFilterIDTx(ctx context.Context, tx *Tx, filterID int,
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient, runCondition, dupeKey, crossSeed, completionHooks, validateAnnounce, validatePrivate, clientPoolMode, moveCompletedPath, bandwidthGroup, custom1, custom2, execDir, webhookSecret sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 
 		var externalClientID, clientID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &externalClient, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &a.FreeleechToken, &a.FreeleechTokenMinSize, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.LimitInactiveSeedTime, &a.MaxConnections, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceJitter, &a.RequireApproval, &a.RemoveRatio, &a.RemoveSeedTime, &a.RemoveAfterDays, &a.RemoveDeleteData, &a.ValidateTorrent, &runCondition, &dupeKey, &crossSeed, &completionHooks, &validateAnnounce, &validatePrivate, &clientPoolMode, &moveCompletedPath, &bandwidthGroup, &custom1, &custom2, &a.ExecTimeout, pq.Array(&a.ExecEnv), pq.Array(&a.FailoverClientIDs), &execDir, &webhookHost, &webhookType, &webhookMethod, &webhookData, pq.Array(&a.WebhookHeaders), &webhookSecret, &a.WebhookRetryAttempts, &a.WebhookRetryDelaySeconds, &externalClientID, &externalClient, &clientID); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		if a.CompletionHooks, err = unmarshalCompletionHooks(completionHooks.String); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal completion hooks")
+		}
+
 		a.ExecCmd = execCmd.String
 		a.ExecArgs = execArgs.String
 		a.WatchFolder = watchFolder.String
@@ -409,9 +539,21 @@ func (r *ActionRepo) findByFilterIDTx(ctx context.Context, tx *Tx, filterID int,
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.WebhookSecret = webhookSecret.String
 
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ExternalDownloadClient = externalClient.String
+		a.RunCondition = domain.ActionRunCondition(runCondition.String)
+		a.DupeKey = dupeKey.String
+		a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
+		a.ValidateAnnounce = validateAnnounce.String
+		a.ValidatePrivate = domain.TorrentPrivacy(validatePrivate.String)
+		a.ClientPoolMode = domain.ClientPoolMode(clientPoolMode.String)
+		a.MoveCompletedPath = moveCompletedPath.String
+		a.BandwidthGroup = bandwidthGroup.String
+		a.Custom1 = custom1.String
+		a.Custom2 = custom2.String
+		a.ExecDir = execDir.String
 		a.ClientID = clientID.Int32
 
 		actions = append(actions, &a)
@@ -491,20 +633,50 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 			"ignore_rules",
 			"first_last_piece_prio",
 			"skip_hash_check",
+			"freeleech_token",
+			"freeleech_token_min_size",
 			"content_layout",
 			"priority",
 			"limit_download_speed",
 			"limit_upload_speed",
 			"limit_ratio",
 			"limit_seed_time",
+			"limit_inactive_seed_time",
+			"max_connections",
 			"reannounce_skip",
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_jitter",
+			"require_approval",
+			"remove_ratio",
+			"remove_seed_time",
+			"remove_after_days",
+			"remove_delete_data",
+			"validate_torrent",
+			"run_condition",
+			"dupe_key",
+			"cross_seed",
+			"completion_hooks",
+			"validate_announce",
+			"validate_private",
+			"client_pool_mode",
+			"move_completed_path",
+			"bandwidth_group",
+			"custom1",
+			"custom2",
+			"exec_timeout",
+			"exec_env",
+			"failover_clients",
+			"exec_dir",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"webhook_headers",
+			"webhook_secret",
+			"webhook_retry_attempts",
+			"webhook_retry_delay_seconds",
 			"external_client_id",
 			"external_client",
 			"client_id",
@@ -527,16 +699,20 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient, runCondition, dupeKey, crossSeed, completionHooks, validateAnnounce, validatePrivate, clientPoolMode, moveCompletedPath, bandwidthGroup, custom1, custom2, execDir, webhookSecret sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 		var externalClientID, clientID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &externalClient, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &a.FreeleechToken, &a.FreeleechTokenMinSize, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.LimitInactiveSeedTime, &a.MaxConnections, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceJitter, &a.RequireApproval, &a.RemoveRatio, &a.RemoveSeedTime, &a.RemoveAfterDays, &a.RemoveDeleteData, &a.ValidateTorrent, &runCondition, &dupeKey, &crossSeed, &completionHooks, &validateAnnounce, &validatePrivate, &clientPoolMode, &moveCompletedPath, &bandwidthGroup, &custom1, &custom2, &a.ExecTimeout, pq.Array(&a.ExecEnv), pq.Array(&a.FailoverClientIDs), &execDir, &webhookHost, &webhookType, &webhookMethod, &webhookData, pq.Array(&a.WebhookHeaders), &webhookSecret, &a.WebhookRetryAttempts, &a.WebhookRetryDelaySeconds, &externalClientID, &externalClient, &clientID); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		if a.CompletionHooks, err = unmarshalCompletionHooks(completionHooks.String); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal completion hooks")
+		}
+
 		a.Category = category.String
 		a.Tags = tags.String
 		a.Label = label.String
@@ -555,9 +731,21 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.WebhookSecret = webhookSecret.String
 
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ExternalDownloadClient = externalClient.String
+		a.RunCondition = domain.ActionRunCondition(runCondition.String)
+		a.DupeKey = dupeKey.String
+		a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
+		a.ValidateAnnounce = validateAnnounce.String
+		a.ValidatePrivate = domain.TorrentPrivacy(validatePrivate.String)
+		a.ClientPoolMode = domain.ClientPoolMode(clientPoolMode.String)
+		a.MoveCompletedPath = moveCompletedPath.String
+		a.BandwidthGroup = bandwidthGroup.String
+		a.Custom1 = custom1.String
+		a.Custom2 = custom2.String
+		a.ExecDir = execDir.String
 		a.ClientID = clientID.Int32
 
 		actions = append(actions, a)
@@ -588,20 +776,50 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 			"ignore_rules",
 			"first_last_piece_prio",
 			"skip_hash_check",
+			"freeleech_token",
+			"freeleech_token_min_size",
 			"content_layout",
 			"priority",
 			"limit_download_speed",
 			"limit_upload_speed",
 			"limit_ratio",
 			"limit_seed_time",
+			"limit_inactive_seed_time",
+			"max_connections",
 			"reannounce_skip",
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_jitter",
+			"require_approval",
+			"remove_ratio",
+			"remove_seed_time",
+			"remove_after_days",
+			"remove_delete_data",
+			"validate_torrent",
+			"run_condition",
+			"dupe_key",
+			"cross_seed",
+			"completion_hooks",
+			"validate_announce",
+			"validate_private",
+			"client_pool_mode",
+			"move_completed_path",
+			"bandwidth_group",
+			"custom1",
+			"custom2",
+			"exec_timeout",
+			"exec_env",
+			"failover_clients",
+			"exec_dir",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"webhook_headers",
+			"webhook_secret",
+			"webhook_retry_attempts",
+			"webhook_retry_delay_seconds",
 			"external_client_id",
 			"external_client",
 			"client_id",
@@ -622,13 +840,13 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 
 	var a domain.Action
 
-	var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient sql.NullString
+	var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, priorityLayout, webhookHost, webhookType, webhookMethod, webhookData, externalClient, runCondition, dupeKey, crossSeed, completionHooks, validateAnnounce, validatePrivate, clientPoolMode, moveCompletedPath, bandwidthGroup, custom1, custom2, execDir, webhookSecret sql.NullString
 	var limitUl, limitDl, limitSeedTime sql.NullInt64
 	var limitRatio sql.NullFloat64
 	var externalClientID, clientID, filterID sql.NullInt32
 	var paused, ignoreRules sql.NullBool
 
-	if err := row.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &externalClient, &clientID, &filterID); err != nil {
+	if err := row.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.FirstLastPiecePrio, &a.SkipHashCheck, &a.FreeleechToken, &a.FreeleechTokenMinSize, &contentLayout, &priorityLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.LimitInactiveSeedTime, &a.MaxConnections, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceJitter, &a.RequireApproval, &a.RemoveRatio, &a.RemoveSeedTime, &a.RemoveAfterDays, &a.RemoveDeleteData, &a.ValidateTorrent, &runCondition, &dupeKey, &crossSeed, &completionHooks, &validateAnnounce, &validatePrivate, &clientPoolMode, &moveCompletedPath, &bandwidthGroup, &custom1, &custom2, &a.ExecTimeout, pq.Array(&a.ExecEnv), pq.Array(&a.FailoverClientIDs), &execDir, &webhookHost, &webhookType, &webhookMethod, &webhookData, pq.Array(&a.WebhookHeaders), &webhookSecret, &a.WebhookRetryAttempts, &a.WebhookRetryDelaySeconds, &externalClientID, &externalClient, &clientID, &filterID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -636,6 +854,10 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
+	if a.CompletionHooks, err = unmarshalCompletionHooks(completionHooks.String); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal completion hooks")
+	}
+
 	a.ExecCmd = execCmd.String
 	a.ExecArgs = execArgs.String
 	a.WatchFolder = watchFolder.String
@@ -657,9 +879,21 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 	a.WebhookType = webhookType.String
 	a.WebhookMethod = webhookMethod.String
 	a.WebhookData = webhookData.String
+	a.WebhookSecret = webhookSecret.String
 
 	a.ExternalDownloadClientID = externalClientID.Int32
 	a.ExternalDownloadClient = externalClient.String
+	a.RunCondition = domain.ActionRunCondition(runCondition.String)
+	a.DupeKey = dupeKey.String
+	a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
+	a.ValidateAnnounce = validateAnnounce.String
+	a.ValidatePrivate = domain.TorrentPrivacy(validatePrivate.String)
+	a.ClientPoolMode = domain.ClientPoolMode(clientPoolMode.String)
+	a.MoveCompletedPath = moveCompletedPath.String
+	a.BandwidthGroup = bandwidthGroup.String
+	a.Custom1 = custom1.String
+	a.Custom2 = custom2.String
+	a.ExecDir = execDir.String
 	a.ClientID = clientID.Int32
 	a.FilterID = int(filterID.Int32)
 
@@ -707,6 +941,11 @@ func (r *ActionRepo) DeleteByFilterID(ctx context.Context, filterID int) error {
 }
 
 func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.Action, error) {
+	completionHooks, err := marshalCompletionHooks(action.CompletionHooks)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal completion hooks")
+	}
+
 	queryBuilder := r.db.squirrel.
 		Insert("action").
 		Columns(
@@ -724,20 +963,50 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			"ignore_rules",
 			"first_last_piece_prio",
 			"skip_hash_check",
+			"freeleech_token",
+			"freeleech_token_min_size",
 			"content_layout",
 			"priority",
 			"limit_upload_speed",
 			"limit_download_speed",
 			"limit_ratio",
 			"limit_seed_time",
+			"limit_inactive_seed_time",
+			"max_connections",
 			"reannounce_skip",
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_jitter",
+			"require_approval",
+			"remove_ratio",
+			"remove_seed_time",
+			"remove_after_days",
+			"remove_delete_data",
+			"validate_torrent",
+			"run_condition",
+			"dupe_key",
+			"cross_seed",
+			"completion_hooks",
+			"validate_announce",
+			"validate_private",
+			"client_pool_mode",
+			"move_completed_path",
+			"bandwidth_group",
+			"custom1",
+			"custom2",
+			"exec_timeout",
+			"exec_env",
+			"failover_clients",
+			"exec_dir",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"webhook_headers",
+			"webhook_secret",
+			"webhook_retry_attempts",
+			"webhook_retry_delay_seconds",
 			"external_client_id",
 			"external_client",
 			"client_id",
@@ -758,20 +1027,50 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			action.IgnoreRules,
 			action.FirstLastPiecePrio,
 			action.SkipHashCheck,
+			action.FreeleechToken,
+			action.FreeleechTokenMinSize,
 			toNullString(string(action.ContentLayout)),
 			toNullString(string(action.PriorityLayout)),
 			toNullInt64(action.LimitUploadSpeed),
 			toNullInt64(action.LimitDownloadSpeed),
 			toNullFloat64(action.LimitRatio),
 			toNullInt64(action.LimitSeedTime),
+			action.LimitInactiveSeedTime,
+			action.MaxConnections,
 			action.ReAnnounceSkip,
 			action.ReAnnounceDelete,
 			action.ReAnnounceInterval,
 			action.ReAnnounceMaxAttempts,
+			action.ReAnnounceJitter,
+			action.RequireApproval,
+			action.RemoveRatio,
+			action.RemoveSeedTime,
+			action.RemoveAfterDays,
+			action.RemoveDeleteData,
+			action.ValidateTorrent,
+			toNullString(string(action.RunCondition)),
+			toNullString(action.DupeKey),
+			toNullString(string(action.CrossSeed)),
+			completionHooks,
+			toNullString(action.ValidateAnnounce),
+			toNullString(string(action.ValidatePrivate)),
+			toNullString(string(action.ClientPoolMode)),
+			toNullString(action.MoveCompletedPath),
+			toNullString(action.BandwidthGroup),
+			toNullString(action.Custom1),
+			toNullString(action.Custom2),
+			action.ExecTimeout,
+			pq.Array(action.ExecEnv),
+			pq.Array(action.FailoverClientIDs),
+			toNullString(action.ExecDir),
 			toNullString(action.WebhookHost),
 			toNullString(action.WebhookType),
 			toNullString(action.WebhookMethod),
 			toNullString(action.WebhookData),
+			pq.Array(action.WebhookHeaders),
+			toNullString(action.WebhookSecret),
+			action.WebhookRetryAttempts,
+			action.WebhookRetryDelaySeconds,
 			toNullInt32(action.ExternalDownloadClientID),
 			toNullString(action.ExternalDownloadClient),
 			toNullInt32(action.ClientID),
@@ -794,6 +1093,11 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 }
 
 func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.Action, error) {
+	completionHooks, err := marshalCompletionHooks(action.CompletionHooks)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal completion hooks")
+	}
+
 	queryBuilder := r.db.squirrel.
 		Update("action").
 		Set("name", action.Name).
@@ -810,20 +1114,50 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 		Set("ignore_rules", action.IgnoreRules).
 		Set("first_last_piece_prio", action.FirstLastPiecePrio).
 		Set("skip_hash_check", action.SkipHashCheck).
+		Set("freeleech_token", action.FreeleechToken).
+		Set("freeleech_token_min_size", action.FreeleechTokenMinSize).
 		Set("content_layout", toNullString(string(action.ContentLayout))).
 		Set("priority", toNullString(string(action.PriorityLayout))).
 		Set("limit_upload_speed", toNullInt64(action.LimitUploadSpeed)).
 		Set("limit_download_speed", toNullInt64(action.LimitDownloadSpeed)).
 		Set("limit_ratio", toNullFloat64(action.LimitRatio)).
 		Set("limit_seed_time", toNullInt64(action.LimitSeedTime)).
+		Set("limit_inactive_seed_time", action.LimitInactiveSeedTime).
+		Set("max_connections", action.MaxConnections).
 		Set("reannounce_skip", action.ReAnnounceSkip).
 		Set("reannounce_delete", action.ReAnnounceDelete).
 		Set("reannounce_interval", action.ReAnnounceInterval).
 		Set("reannounce_max_attempts", action.ReAnnounceMaxAttempts).
+		Set("reannounce_jitter", action.ReAnnounceJitter).
+		Set("require_approval", action.RequireApproval).
+		Set("remove_ratio", action.RemoveRatio).
+		Set("remove_seed_time", action.RemoveSeedTime).
+		Set("remove_after_days", action.RemoveAfterDays).
+		Set("remove_delete_data", action.RemoveDeleteData).
+		Set("validate_torrent", action.ValidateTorrent).
+		Set("run_condition", toNullString(string(action.RunCondition))).
+		Set("dupe_key", toNullString(action.DupeKey)).
+		Set("cross_seed", toNullString(string(action.CrossSeed))).
+		Set("completion_hooks", completionHooks).
+		Set("validate_announce", toNullString(action.ValidateAnnounce)).
+		Set("validate_private", toNullString(string(action.ValidatePrivate))).
+		Set("client_pool_mode", toNullString(string(action.ClientPoolMode))).
+		Set("move_completed_path", toNullString(action.MoveCompletedPath)).
+		Set("bandwidth_group", toNullString(action.BandwidthGroup)).
+		Set("custom1", toNullString(action.Custom1)).
+		Set("custom2", toNullString(action.Custom2)).
+		Set("exec_timeout", action.ExecTimeout).
+		Set("exec_env", pq.Array(action.ExecEnv)).
+		Set("failover_clients", pq.Array(action.FailoverClientIDs)).
+		Set("exec_dir", toNullString(action.ExecDir)).
 		Set("webhook_host", toNullString(action.WebhookHost)).
 		Set("webhook_type", toNullString(action.WebhookType)).
 		Set("webhook_method", toNullString(action.WebhookMethod)).
 		Set("webhook_data", toNullString(action.WebhookData)).
+		Set("webhook_headers", pq.Array(action.WebhookHeaders)).
+		Set("webhook_secret", toNullString(action.WebhookSecret)).
+		Set("webhook_retry_attempts", action.WebhookRetryAttempts).
+		Set("webhook_retry_delay_seconds", action.WebhookRetryDelaySeconds).
 		Set("external_client_id", toNullInt32(action.ExternalDownloadClientID)).
 		Set("external_client", toNullString(action.ExternalDownloadClient)).
 		Set("client_id", toNullInt32(action.ClientID)).
@@ -855,6 +1189,11 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 	for _, action := range actions {
 		action := action
 
+		completionHooks, err := marshalCompletionHooks(action.CompletionHooks)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal completion hooks")
+		}
+
 		if action.ID > 0 {
 			queryBuilder := r.db.squirrel.
 				Update("action").
@@ -872,20 +1211,50 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 				Set("ignore_rules", action.IgnoreRules).
 				Set("first_last_piece_prio", action.FirstLastPiecePrio).
 				Set("skip_hash_check", action.SkipHashCheck).
+				Set("freeleech_token", action.FreeleechToken).
+				Set("freeleech_token_min_size", action.FreeleechTokenMinSize).
 				Set("content_layout", toNullString(string(action.ContentLayout))).
 				Set("priority", toNullString(string(action.PriorityLayout))).
 				Set("limit_upload_speed", toNullInt64(action.LimitUploadSpeed)).
 				Set("limit_download_speed", toNullInt64(action.LimitDownloadSpeed)).
 				Set("limit_ratio", toNullFloat64(action.LimitRatio)).
 				Set("limit_seed_time", toNullInt64(action.LimitSeedTime)).
+				Set("limit_inactive_seed_time", action.LimitInactiveSeedTime).
+				Set("max_connections", action.MaxConnections).
 				Set("reannounce_skip", action.ReAnnounceSkip).
 				Set("reannounce_delete", action.ReAnnounceDelete).
 				Set("reannounce_interval", action.ReAnnounceInterval).
 				Set("reannounce_max_attempts", action.ReAnnounceMaxAttempts).
+				Set("reannounce_jitter", action.ReAnnounceJitter).
+				Set("require_approval", action.RequireApproval).
+				Set("remove_ratio", action.RemoveRatio).
+				Set("remove_seed_time", action.RemoveSeedTime).
+				Set("remove_after_days", action.RemoveAfterDays).
+				Set("remove_delete_data", action.RemoveDeleteData).
+				Set("validate_torrent", action.ValidateTorrent).
+				Set("run_condition", toNullString(string(action.RunCondition))).
+				Set("dupe_key", toNullString(action.DupeKey)).
+				Set("cross_seed", toNullString(string(action.CrossSeed))).
+				Set("completion_hooks", completionHooks).
+				Set("validate_announce", toNullString(action.ValidateAnnounce)).
+				Set("validate_private", toNullString(string(action.ValidatePrivate))).
+				Set("client_pool_mode", toNullString(string(action.ClientPoolMode))).
+				Set("move_completed_path", toNullString(action.MoveCompletedPath)).
+				Set("bandwidth_group", toNullString(action.BandwidthGroup)).
+				Set("custom1", toNullString(action.Custom1)).
+				Set("custom2", toNullString(action.Custom2)).
+				Set("exec_timeout", action.ExecTimeout).
+				Set("exec_env", pq.Array(action.ExecEnv)).
+				Set("failover_clients", pq.Array(action.FailoverClientIDs)).
+				Set("exec_dir", toNullString(action.ExecDir)).
 				Set("webhook_host", toNullString(action.WebhookHost)).
 				Set("webhook_type", toNullString(action.WebhookType)).
 				Set("webhook_method", toNullString(action.WebhookMethod)).
 				Set("webhook_data", toNullString(action.WebhookData)).
+				Set("webhook_headers", pq.Array(action.WebhookHeaders)).
+				Set("webhook_secret", toNullString(action.WebhookSecret)).
+				Set("webhook_retry_attempts", action.WebhookRetryAttempts).
+				Set("webhook_retry_delay_seconds", action.WebhookRetryDelaySeconds).
 				Set("external_client_id", toNullInt32(action.ExternalDownloadClientID)).
 				Set("external_client", toNullString(action.ExternalDownloadClient)).
 				Set("client_id", toNullInt32(action.ClientID)).
@@ -921,20 +1290,50 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					"ignore_rules",
 					"first_last_piece_prio",
 					"skip_hash_check",
+					"freeleech_token",
+					"freeleech_token_min_size",
 					"content_layout",
 					"priority",
 					"limit_upload_speed",
 					"limit_download_speed",
 					"limit_ratio",
 					"limit_seed_time",
+					"limit_inactive_seed_time",
+					"max_connections",
 					"reannounce_skip",
 					"reannounce_delete",
 					"reannounce_interval",
 					"reannounce_max_attempts",
+					"reannounce_jitter",
+					"require_approval",
+					"remove_ratio",
+					"remove_seed_time",
+					"remove_after_days",
+					"remove_delete_data",
+					"validate_torrent",
+					"run_condition",
+					"dupe_key",
+					"cross_seed",
+					"completion_hooks",
+					"validate_announce",
+					"validate_private",
+					"client_pool_mode",
+					"move_completed_path",
+					"bandwidth_group",
+					"custom1",
+					"custom2",
+					"exec_timeout",
+					"exec_env",
+					"failover_clients",
+					"exec_dir",
 					"webhook_host",
 					"webhook_type",
 					"webhook_method",
 					"webhook_data",
+					"webhook_headers",
+					"webhook_secret",
+					"webhook_retry_attempts",
+					"webhook_retry_delay_seconds",
 					"external_client_id",
 					"external_client",
 					"client_id",
@@ -955,20 +1354,50 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					action.IgnoreRules,
 					action.FirstLastPiecePrio,
 					action.SkipHashCheck,
+					action.FreeleechToken,
+					action.FreeleechTokenMinSize,
 					toNullString(string(action.ContentLayout)),
 					toNullString(string(action.PriorityLayout)),
 					toNullInt64(action.LimitUploadSpeed),
 					toNullInt64(action.LimitDownloadSpeed),
 					toNullFloat64(action.LimitRatio),
 					toNullInt64(action.LimitSeedTime),
+					action.LimitInactiveSeedTime,
+					action.MaxConnections,
 					action.ReAnnounceSkip,
 					action.ReAnnounceDelete,
 					action.ReAnnounceInterval,
 					action.ReAnnounceMaxAttempts,
+					action.ReAnnounceJitter,
+					action.RequireApproval,
+					action.RemoveRatio,
+					action.RemoveSeedTime,
+					action.RemoveAfterDays,
+					action.RemoveDeleteData,
+					action.ValidateTorrent,
+					toNullString(string(action.RunCondition)),
+					toNullString(action.DupeKey),
+					toNullString(string(action.CrossSeed)),
+					completionHooks,
+					toNullString(action.ValidateAnnounce),
+					toNullString(string(action.ValidatePrivate)),
+					toNullString(string(action.ClientPoolMode)),
+					toNullString(action.MoveCompletedPath),
+					toNullString(action.BandwidthGroup),
+					toNullString(action.Custom1),
+					toNullString(action.Custom2),
+					action.ExecTimeout,
+					pq.Array(action.ExecEnv),
+					pq.Array(action.FailoverClientIDs),
+					toNullString(action.ExecDir),
 					toNullString(action.WebhookHost),
 					toNullString(action.WebhookType),
 					toNullString(action.WebhookMethod),
 					toNullString(action.WebhookData),
+					pq.Array(action.WebhookHeaders),
+					toNullString(action.WebhookSecret),
+					action.WebhookRetryAttempts,
+					action.WebhookRetryDelaySeconds,
 					toNullInt32(action.ExternalDownloadClientID),
 					toNullString(action.ExternalDownloadClient),
 					toNullInt32(action.ClientID),
@@ -1024,3 +1453,30 @@ func (r *ActionRepo) ToggleEnabled(actionID int) error {
 
 	return nil
 }
+
+// marshalCompletionHooks returns the completion hooks as json, null when there are none
+func marshalCompletionHooks(hooks []domain.CompletionHook) (sql.Null[string], error) {
+	if len(hooks) == 0 {
+		return sql.Null[string]{}, nil
+	}
+
+	data, err := json.Marshal(hooks)
+	if err != nil {
+		return sql.Null[string]{}, err
+	}
+
+	return toNullString(string(data)), nil
+}
+
+func unmarshalCompletionHooks(data string) ([]domain.CompletionHook, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var hooks []domain.CompletionHook
+	if err := json.Unmarshal([]byte(data), &hooks); err != nil {
+		return nil, err
+	}
+
+	return hooks, nil
+}
@@ -234,6 +234,50 @@ func TestActionRepo_FindByFilterID(t *testing.T) {
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
 		})
 
+		t.Run(fmt.Sprintf("FindByFilterID_Chain_Order [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			primary := getMockAction()
+			primary.Name = "primary"
+			primary.ClientID = mock.ID
+			primary.FilterID = createdFilters[0].ID
+
+			fallback := getMockAction()
+			fallback.Name = "fallback"
+			fallback.ClientID = mock.ID
+			fallback.FilterID = createdFilters[0].ID
+			fallback.RunCondition = domain.ActionRunConditionOnFailure
+
+			createdActions, err := repo.StoreFilterActions(context.Background(), int64(createdFilters[0].ID), []*domain.Action{&primary, &fallback})
+			assert.NoError(t, err)
+
+			actions, err := repo.FindByFilterID(context.Background(), createdFilters[0].ID, nil, false)
+			assert.NoError(t, err)
+			if assert.Len(t, actions, 2) {
+				assert.Equal(t, "primary", actions[0].Name)
+				assert.Equal(t, domain.ActionRunCondition(""), actions[0].RunCondition)
+				assert.Equal(t, "fallback", actions[1].Name)
+				assert.Equal(t, domain.ActionRunConditionOnFailure, actions[1].RunCondition)
+			}
+
+			// Cleanup
+			for _, action := range createdActions {
+				_ = repo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: action.ID})
+			}
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+
 		t.Run(fmt.Sprintf("FindByFilterID_Fails_No_Actions [%s]", dbType), func(t *testing.T) {
 			// Setup
 			err := filterRepo.Store(context.Background(), getMockFilter())
@@ -344,6 +388,12 @@ func TestActionRepo_Get(t *testing.T) {
 
 			mockData.ClientID = mock.ID
 			mockData.FilterID = createdFilters[0].ID
+			mockData.FailoverClientIDs = []int32{mock.ID + 1, mock.ID + 2}
+			mockData.CompletionHooks = []domain.CompletionHook{
+				{Type: domain.CompletionHookTypeWebhook, WebhookHost: "http://localhost:8080", WebhookData: `{"name":"{{ .TorrentName }}"}`},
+				{Type: domain.CompletionHookTypePlexScan, PlexHost: "http://localhost:32400", PlexToken: "token", PlexSection: "2"},
+			}
+			mockData.CrossSeed = domain.ActionCrossSeedOnComplete
 			createdActions, err := repo.StoreFilterActions(context.Background(), int64(createdFilters[0].ID), []*domain.Action{&mockData})
 			assert.NoError(t, err)
 
@@ -352,6 +402,9 @@ func TestActionRepo_Get(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, action)
 			assert.Equal(t, createdActions[0].ID, action.ID)
+			assert.Equal(t, mockData.FailoverClientIDs, action.FailoverClientIDs)
+			assert.Equal(t, mockData.CompletionHooks, action.CompletionHooks)
+			assert.Equal(t, mockData.CrossSeed, action.CrossSeed)
 
 			// Cleanup
 			_ = repo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdActions[0].ID})
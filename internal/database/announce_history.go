@@ -0,0 +1,124 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type AnnounceHistoryRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewAnnounceHistoryRepo(log logger.Logger, db *DB) domain.AnnounceHistoryRepo {
+	return &AnnounceHistoryRepo{
+		log: log.With().Str("module", "database").Str("repo", "announce_history").Logger(),
+		db:  db,
+	}
+}
+
+func (r *AnnounceHistoryRepo) StoreMany(ctx context.Context, entries []domain.AnnounceHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("announce_history").
+		Columns("indexer", "source", "torrent_name", "seen_at")
+
+	for _, entry := range entries {
+		queryBuilder = queryBuilder.Values(entry.Indexer, entry.Source, entry.TorrentName, entry.SeenAt)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Trace().Msgf("stored %d announce history entries", len(entries))
+
+	return nil
+}
+
+func (r *AnnounceHistoryRepo) FindSince(ctx context.Context, since time.Time) ([]domain.AnnounceHistoryEntry, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"indexer",
+			"source",
+			"torrent_name",
+			"seen_at",
+		).
+		From("announce_history").
+		Where(sq.GtOrEq{"seen_at": since}).
+		OrderBy("seen_at ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	entries := make([]domain.AnnounceHistoryEntry, 0)
+
+	for rows.Next() {
+		var e domain.AnnounceHistoryEntry
+
+		if err := rows.Scan(&e.ID, &e.Indexer, &e.Source, &e.TorrentName, &e.SeenAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return entries, nil
+}
+
+func (r *AnnounceHistoryRepo) DeleteOlderThan(ctx context.Context, before time.Time) error {
+	queryBuilder := r.db.squirrel.
+		Delete("announce_history").
+		Where(sq.Lt{"seen_at": before})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error exec result")
+	}
+
+	r.log.Debug().Msgf("deleted %d rows from announce history", rows)
+
+	return nil
+}
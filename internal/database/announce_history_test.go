@@ -0,0 +1,88 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockAnnounceHistoryEntries(seenAt time.Time) []domain.AnnounceHistoryEntry {
+	return []domain.AnnounceHistoryEntry{
+		{
+			Indexer:     "mock-indexer",
+			Source:      domain.AnnounceSourceIRC,
+			TorrentName: "Some.Release.2024.1080p.WEB-DL.H.264-GROUP",
+			SeenAt:      seenAt,
+		},
+		{
+			Indexer:     "mock-indexer",
+			Source:      domain.AnnounceSourceFeed,
+			TorrentName: "Some.Release.2024.1080p.WEB-DL.H.264-GROUP",
+			SeenAt:      seenAt,
+		},
+	}
+}
+
+func TestAnnounceHistoryRepo_FindSince(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewAnnounceHistoryRepo(log, db)
+
+		t.Run(fmt.Sprintf("FindSince_Succeeds [%s]", dbType), func(t *testing.T) {
+			now := time.Now().UTC().Truncate(time.Second)
+
+			// Setup
+			err := repo.StoreMany(context.Background(), getMockAnnounceHistoryEntries(now))
+			assert.NoError(t, err)
+
+			err = repo.StoreMany(context.Background(), getMockAnnounceHistoryEntries(now.Add(-2*time.Hour)))
+			assert.NoError(t, err)
+
+			// Execute
+			entries, err := repo.FindSince(context.Background(), now.Add(-time.Hour))
+			assert.NoError(t, err)
+			assert.Len(t, entries, 2)
+
+			// Cleanup
+			_ = repo.DeleteOlderThan(context.Background(), now.Add(time.Hour))
+		})
+
+		t.Run(fmt.Sprintf("StoreMany_Empty [%s]", dbType), func(t *testing.T) {
+			err := repo.StoreMany(context.Background(), nil)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAnnounceHistoryRepo_DeleteOlderThan(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewAnnounceHistoryRepo(log, db)
+
+		t.Run(fmt.Sprintf("DeleteOlderThan_Succeeds [%s]", dbType), func(t *testing.T) {
+			now := time.Now().UTC().Truncate(time.Second)
+
+			// Setup
+			err := repo.StoreMany(context.Background(), getMockAnnounceHistoryEntries(now.Add(-2*time.Hour)))
+			assert.NoError(t, err)
+
+			// Execute
+			err = repo.DeleteOlderThan(context.Background(), now.Add(-time.Hour))
+			assert.NoError(t, err)
+
+			entries, err := repo.FindSince(context.Background(), now.Add(-3*time.Hour))
+			assert.NoError(t, err)
+			assert.Len(t, entries, 0)
+		})
+	}
+}
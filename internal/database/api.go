@@ -36,11 +36,15 @@ func (r *APIRepo) Store(ctx context.Context, key *domain.APIKey) error {
 			"name",
 			"key",
 			"scopes",
+			"expires_at",
+			"user_id",
 		).
 		Values(
 			key.Name,
 			key.Key,
 			pq.Array(key.Scopes),
+			key.ExpiresAt,
+			toNullInt32(int32(key.UserID)),
 		).
 		Suffix("RETURNING created_at").RunWith(r.db.handler)
 
@@ -75,7 +79,7 @@ func (r *APIRepo) Delete(ctx context.Context, key string) error {
 
 func (r *APIRepo) GetAllAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
 	queryBuilder := r.db.squirrel.
-		Select("name", "key", "scopes", "created_at").
+		Select("name", "key", "scopes", "expires_at", "user_id", "created_at").
 		From("api_key")
 
 	query, args, err := queryBuilder.ToSql()
@@ -95,12 +99,19 @@ func (r *APIRepo) GetAllAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
 		var a domain.APIKey
 
 		var name sql.NullString
+		var expiresAt sql.NullTime
+		var userID sql.NullInt32
 
-		if err := rows.Scan(&name, &a.Key, pq.Array(&a.Scopes), &a.CreatedAt); err != nil {
+		if err := rows.Scan(&name, &a.Key, pq.Array(&a.Scopes), &expiresAt, &userID, &a.CreatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		a.Name = name.String
+		a.UserID = int(userID.Int32)
+
+		if expiresAt.Valid {
+			a.ExpiresAt = &expiresAt.Time
+		}
 
 		keys = append(keys, a)
 	}
@@ -110,7 +121,7 @@ func (r *APIRepo) GetAllAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
 
 func (r *APIRepo) GetKey(ctx context.Context, key string) (*domain.APIKey, error) {
 	queryBuilder := r.db.squirrel.
-		Select("name", "key", "scopes", "created_at").
+		Select("name", "key", "scopes", "expires_at", "user_id", "created_at").
 		From("api_key").
 		Where(sq.Eq{"key": key})
 
@@ -127,8 +138,10 @@ func (r *APIRepo) GetKey(ctx context.Context, key string) (*domain.APIKey, error
 	var apiKey domain.APIKey
 
 	var name sql.NullString
+	var expiresAt sql.NullTime
+	var userID sql.NullInt32
 
-	if err := row.Scan(&name, &apiKey.Key, pq.Array(&apiKey.Scopes), &apiKey.CreatedAt); err != nil {
+	if err := row.Scan(&name, &apiKey.Key, pq.Array(&apiKey.Scopes), &expiresAt, &userID, &apiKey.CreatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -137,6 +150,11 @@ func (r *APIRepo) GetKey(ctx context.Context, key string) (*domain.APIKey, error
 	}
 
 	apiKey.Name = name.String
+	apiKey.UserID = int(userID.Int32)
+
+	if expiresAt.Valid {
+		apiKey.ExpiresAt = &expiresAt.Time
+	}
 
 	return &apiKey, nil
 }
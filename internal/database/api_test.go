@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 
@@ -107,6 +108,19 @@ func TestAPIRepo_GetKey(t *testing.T) {
 			_ = repo.Delete(context.Background(), key.Key)
 		})
 
+		t.Run(fmt.Sprintf("GetKey_Returns_Expiry [%s]", dbType), func(t *testing.T) {
+			expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+			key := &domain.APIKey{Name: "TestKey", Key: "123", Scopes: []string{"read"}, ExpiresAt: &expiresAt}
+			_ = repo.Store(context.Background(), key)
+			apiKey, err := repo.GetKey(context.Background(), key.Key)
+			assert.NoError(t, err)
+			assert.NotNil(t, apiKey.ExpiresAt)
+			assert.True(t, expiresAt.Equal(*apiKey.ExpiresAt))
+			assert.Equal(t, []string{"read"}, apiKey.Scopes)
+			// Cleanup
+			_ = repo.Delete(context.Background(), key.Key)
+		})
+
 		t.Run(fmt.Sprintf("GetKeys_Returns_Empty_If_No_Keys [%s]", dbType), func(t *testing.T) {
 			key, err := repo.GetKey(context.Background(), "nonexistent")
 			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
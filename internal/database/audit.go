@@ -0,0 +1,106 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type AuditRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewAuditRepo(log logger.Logger, db *DB) domain.AuditRepo {
+	return &AuditRepo{
+		log: log.With().Str("repo", "audit").Logger(),
+		db:  db,
+	}
+}
+
+func (r *AuditRepo) Store(ctx context.Context, entry *domain.AuditEntry) error {
+	queryBuilder := r.db.squirrel.
+		Insert("audit_log").
+		Columns("action", "resource", "resource_id", "resource_name", "description", "username", "api_key").
+		Values(entry.Action, entry.Resource, toNullString(entry.ResourceID), toNullString(entry.ResourceName), entry.Description, toNullString(entry.Username), toNullString(entry.APIKey)).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// Find returns the newest entries first
+func (r *AuditRepo) Find(ctx context.Context, params domain.AuditQueryParams) (*domain.FindAuditResponse, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "action", "resource", "resource_id", "resource_name", "description", "username", "api_key", "created_at", "COUNT(*) OVER() AS total_count").
+		From("audit_log").
+		OrderBy("id DESC")
+
+	if params.Resource != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"resource": params.Resource})
+	}
+
+	if params.Username != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"username": params.Username})
+	}
+
+	if params.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(params.Limit)
+	}
+
+	if params.Offset > 0 {
+		queryBuilder = queryBuilder.Offset(params.Offset)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	resp := &domain.FindAuditResponse{
+		Data: make([]domain.AuditEntry, 0),
+	}
+
+	for rows.Next() {
+		var e domain.AuditEntry
+
+		var resourceID, resourceName, description, username, apiKey sql.NullString
+
+		if err := rows.Scan(&e.ID, &e.Action, &e.Resource, &resourceID, &resourceName, &description, &username, &apiKey, &e.CreatedAt, &resp.TotalCount); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		e.ResourceID = resourceID.String
+		e.ResourceName = resourceName.String
+		e.Description = description.String
+		e.Username = username.String
+		e.APIKey = apiKey.String
+
+		resp.Data = append(resp.Data, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows find")
+	}
+
+	return resp, nil
+}
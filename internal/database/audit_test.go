@@ -0,0 +1,58 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepo_Find(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewAuditRepo(log, db)
+
+		t.Run(fmt.Sprintf("Find_Newest_First_Paginated [%s]", dbType), func(t *testing.T) {
+			entries := []domain.AuditEntry{
+				{Action: domain.AuditActionCreate, Resource: domain.AuditResourceFilter, ResourceID: "1", ResourceName: "movies", Description: "Create filter", Username: "admin"},
+				{Action: domain.AuditActionUpdate, Resource: domain.AuditResourceFilter, ResourceID: "1", Description: "Update filter", APIKey: "scripts"},
+				{Action: domain.AuditActionDelete, Resource: domain.AuditResourceIndexer, ResourceID: "2", Description: "Delete indexer", Username: "admin"},
+			}
+
+			for i := range entries {
+				assert.NoError(t, repo.Store(context.Background(), &entries[i]))
+				assert.NotZero(t, entries[i].ID)
+				assert.NotZero(t, entries[i].CreatedAt)
+			}
+
+			resp, err := repo.Find(context.Background(), domain.AuditQueryParams{Limit: 2})
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(3), resp.TotalCount)
+			assert.Len(t, resp.Data, 2)
+			assert.Equal(t, "Delete indexer", resp.Data[0].Description)
+			assert.Equal(t, "scripts", resp.Data[1].APIKey)
+			assert.Empty(t, resp.Data[1].Username)
+
+			resp, err = repo.Find(context.Background(), domain.AuditQueryParams{Limit: 2, Offset: 2})
+			assert.NoError(t, err)
+			assert.Len(t, resp.Data, 1)
+			assert.Equal(t, "movies", resp.Data[0].ResourceName)
+
+			resp, err = repo.Find(context.Background(), domain.AuditQueryParams{Resource: domain.AuditResourceFilter, Username: "admin"})
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), resp.TotalCount)
+			assert.Equal(t, domain.AuditActionCreate, resp.Data[0].Action)
+
+			// Cleanup
+			_, _ = db.handler.Exec("DELETE FROM audit_log")
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -28,6 +29,9 @@ type DB struct {
 	Driver string
 	DSN    string
 
+	// instanceName identifies this instance in change notifications from postgres
+	instanceName string
+
 	squirrel sq.StatementBuilderType
 }
 
@@ -55,6 +59,10 @@ func NewDB(cfg *domain.Config, log logger.Logger) (*DB, error) {
 		if cfg.PostgresExtraParams != "" {
 			db.DSN = fmt.Sprintf("%s&%s", db.DSN, cfg.PostgresExtraParams)
 		}
+		if !strings.Contains(cfg.PostgresExtraParams, "application_name=") {
+			db.instanceName = newInstanceName()
+			db.DSN = fmt.Sprintf("%s&application_name=%s", db.DSN, db.instanceName)
+		}
 		db.Driver = "postgres"
 	default:
 		return nil, errors.New("unsupported database: %v", cfg.DatabaseType)
@@ -38,6 +38,7 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 			"i.name",
 			"i.use_proxy",
 			"i.proxy_id",
+			"i.identity",
 			"f.name",
 			"f.type",
 			"f.enabled",
@@ -69,8 +70,9 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 
 	var apiKey, cookie, settings sql.NullString
 	var proxyID sql.NullInt64
+	var identity sql.Null[string]
 
-	if err := row.Scan(&f.ID, &f.Indexer.ID, &f.Indexer.Identifier, &f.Indexer.IdentifierExternal, &f.Indexer.Name, &f.UseProxy, &proxyID, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	if err := row.Scan(&f.ID, &f.Indexer.ID, &f.Indexer.Identifier, &f.Indexer.IdentifierExternal, &f.Indexer.Name, &f.UseProxy, &proxyID, &identity, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -79,6 +81,11 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 	}
 
 	f.ProxyID = proxyID.Int64
+
+	if f.Identity, err = unmarshalIndexerIdentity(identity); err != nil {
+		return nil, err
+	}
+
 	f.ApiKey = apiKey.String
 	f.Cookie = cookie.String
 
@@ -104,6 +111,7 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 			"i.name",
 			"i.use_proxy",
 			"i.proxy_id",
+			"i.identity",
 			"f.name",
 			"f.type",
 			"f.enabled",
@@ -135,8 +143,9 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 
 	var apiKey, cookie, settings sql.NullString
 	var proxyID sql.NullInt64
+	var identity sql.Null[string]
 
-	if err := row.Scan(&f.ID, &f.Indexer.ID, &f.Indexer.Identifier, &f.Indexer.IdentifierExternal, &f.Indexer.Name, &f.UseProxy, &proxyID, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	if err := row.Scan(&f.ID, &f.Indexer.ID, &f.Indexer.Identifier, &f.Indexer.IdentifierExternal, &f.Indexer.Name, &f.UseProxy, &proxyID, &identity, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -145,6 +154,11 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 	}
 
 	f.ProxyID = proxyID.Int64
+
+	if f.Identity, err = unmarshalIndexerIdentity(identity); err != nil {
+		return nil, err
+	}
+
 	f.ApiKey = apiKey.String
 	f.Cookie = cookie.String
 
@@ -168,6 +182,7 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 			"i.name",
 			"i.use_proxy",
 			"i.proxy_id",
+			"i.identity",
 			"f.name",
 			"f.type",
 			"f.enabled",
@@ -207,12 +222,18 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 		var lastRun sql.NullTime
 
 		var proxyID sql.NullInt64
+		var identity sql.Null[string]
 
-		if err := rows.Scan(&f.ID, &f.Indexer.ID, &f.Indexer.Identifier, &f.Indexer.IdentifierExternal, &f.Indexer.Name, &f.UseProxy, &proxyID, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &lastRun, &lastRunData, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		if err := rows.Scan(&f.ID, &f.Indexer.ID, &f.Indexer.Identifier, &f.Indexer.IdentifierExternal, &f.Indexer.Name, &f.UseProxy, &proxyID, &identity, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &lastRun, &lastRunData, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		f.ProxyID = proxyID.Int64
+
+		if f.Identity, err = unmarshalIndexerIdentity(identity); err != nil {
+			return nil, err
+		}
+
 		f.LastRun = lastRun.Time
 		f.LastRunData = lastRunData.String
 		f.ApiKey = apiKey.String
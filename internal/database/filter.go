@@ -6,6 +6,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -58,6 +59,10 @@ func (r *FilterRepo) find(ctx context.Context, params domain.FilterQueryParams)
 			"f.enabled",
 			"f.name",
 			"f.priority",
+			"f.group_id",
+			"f.schedule",
+			"f.created_by",
+			"f.updated_by",
 			"f.created_at",
 			"f.updated_at",
 		).
@@ -66,7 +71,8 @@ func (r *FilterRepo) find(ctx context.Context, params domain.FilterQueryParams)
 		Column(sq.Alias(actionEnabledCountQuery, "actions_enabled_count")).
 		LeftJoin("filter_indexer fi ON f.id = fi.filter_id").
 		LeftJoin("indexer i ON i.id = fi.indexer_id").
-		From("filter f")
+		From("filter f").
+		Where(sq.Eq{"f.deleted_at": nil})
 
 	if params.Search != "" {
 		queryBuilder = queryBuilder.Where(sq.Like{"f.name": params.Search + "%"})
@@ -102,11 +108,21 @@ func (r *FilterRepo) find(ctx context.Context, params domain.FilterQueryParams)
 	var filters []domain.Filter
 	for rows.Next() {
 		var f domain.Filter
+		var groupID sql.NullInt32
+		var schedule, createdBy, updatedBy sql.Null[string]
 
-		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Priority, &f.CreatedAt, &f.UpdatedAt, &f.ActionsCount, &f.ActionsEnabledCount); err != nil {
+		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Priority, &groupID, &schedule, &createdBy, &updatedBy, &f.CreatedAt, &f.UpdatedAt, &f.ActionsCount, &f.ActionsEnabledCount); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		if f.Schedule, err = unmarshalFilterSchedule(schedule); err != nil {
+			return nil, err
+		}
+
+		f.GroupID = int(groupID.Int32)
+		f.CreatedBy = createdBy.V
+		f.UpdatedBy = updatedBy.V
+
 		filters = append(filters, f)
 	}
 	if err := rows.Err(); err != nil {
@@ -133,6 +149,7 @@ func (r *FilterRepo) ListFilters(ctx context.Context) ([]domain.Filter, error) {
 		).
 		Column(sq.Alias(actionCountQuery, "action_count")).
 		From("filter f").
+		Where(sq.Eq{"f.deleted_at": nil}).
 		OrderBy("f.name ASC")
 
 	query, args, err := queryBuilder.ToSql()
@@ -192,6 +209,11 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"f.freeleech",
 			"f.freeleech_percent",
 			"f.smart_episode",
+			"f.ignore_season_packs",
+			"f.upgrade_replace",
+			"f.group_tiers",
+			"f.smart_music",
+			"f.smart_music_match",
 			"f.shows",
 			"f.seasons",
 			"f.episodes",
@@ -232,11 +254,34 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"f.max_seeders",
 			"f.min_leechers",
 			"f.max_leechers",
+			"f.breaker_threshold",
+			"f.breaker_cooldown",
+			"f.breaker_fail_open",
+			"f.schedule",
+			"f.advanced_expression",
+			"f.normalize_categories",
+			"f.smart_duplicate_days",
+			"f.group_id",
+			"f.max_downloads_size",
+			"f.max_downloads_size_unit",
+			"f.skip_dedupe",
+			"f.max_files",
+			"f.match_file_extensions",
+			"f.except_file_extensions",
+			"f.match_ids",
+			"f.match_musicbrainz",
+			"f.sonarr_check_client_id",
+			"f.radarr_check_client_id",
+			"f.lidarr_check_client_id",
+			"f.library_check_client_id",
+			"f.created_by",
+			"f.updated_by",
 			"f.created_at",
 			"f.updated_at",
 		).
 		From("filter f").
-		Where(sq.Eq{"f.id": filterID})
+		Where(sq.Eq{"f.id": filterID}).
+		Where(sq.Eq{"f.deleted_at": nil})
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -257,7 +302,10 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 	// filter
 	var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, matchReleaseTags, exceptReleaseTags, matchDescription, exceptDescription, freeleechPercent, shows, seasons, episodes, years, months, days, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, tagsMatchLogic, exceptTagsMatchLogic sql.NullString
 	var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac sql.NullBool
-	var delay, maxDownloads, logScore sql.NullInt32
+	var delay, maxDownloads, logScore, smartDuplicateDays sql.NullInt32
+	var schedule, groupTiers, createdBy, updatedBy sql.Null[string]
+	var advancedExpression, maxDownloadsSize, maxDownloadsSizeUnit, matchFileExtensions, exceptFileExtensions, matchIDs, matchMusicBrainz sql.NullString
+	var groupID sql.NullInt32
 
 	err = row.Scan(
 		&f.ID,
@@ -284,6 +332,11 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 		&freeleech,
 		&freeleechPercent,
 		&f.SmartEpisode,
+		&f.IgnoreSeasonPacks,
+		&f.UpgradeReplace,
+		&groupTiers,
+		&f.SmartMusic,
+		pq.Array(&f.SmartMusicMatch),
 		&shows,
 		&seasons,
 		&episodes,
@@ -324,6 +377,28 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 		&f.MaxSeeders,
 		&f.MinLeechers,
 		&f.MaxLeechers,
+		&f.BreakerThreshold,
+		&f.BreakerCooldown,
+		&f.BreakerFailOpen,
+		&schedule,
+		&advancedExpression,
+		&f.NormalizeCategories,
+		&smartDuplicateDays,
+		&groupID,
+		&maxDownloadsSize,
+		&maxDownloadsSizeUnit,
+		&f.SkipDedupe,
+		&f.MaxFiles,
+		&matchFileExtensions,
+		&exceptFileExtensions,
+		&matchIDs,
+		&matchMusicBrainz,
+		&f.SonarrCheckClientID,
+		&f.RadarrCheckClientID,
+		&f.LidarrCheckClientID,
+		&f.LibraryCheckClientID,
+		&createdBy,
+		&updatedBy,
 		&f.CreatedAt,
 		&f.UpdatedAt,
 	)
@@ -335,11 +410,20 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
+	f.CreatedBy = createdBy.V
+	f.UpdatedBy = updatedBy.V
 	f.MinSize = minSize.String
 	f.MaxSize = maxSize.String
 	f.Delay = int(delay.Int32)
 	f.MaxDownloads = int(maxDownloads.Int32)
+	f.SmartDuplicateDays = int(smartDuplicateDays.Int32)
 	f.MaxDownloadsUnit = domain.FilterMaxDownloadsUnit(maxDownloadsUnit.String)
+	f.MaxDownloadsSize = maxDownloadsSize.String
+	f.MaxDownloadsSizeUnit = domain.FilterMaxDownloadsUnit(maxDownloadsSizeUnit.String)
+	f.MatchFileExtensions = matchFileExtensions.String
+	f.ExceptFileExtensions = exceptFileExtensions.String
+	f.MatchIDs = matchIDs.String
+	f.MatchMusicBrainz = matchMusicBrainz.String
 	f.MatchReleases = matchReleases.String
 	f.ExceptReleases = exceptReleases.String
 	f.MatchReleaseGroups = matchReleaseGroups.String
@@ -372,6 +456,17 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 	f.UseRegex = useRegex.Bool
 	f.Scene = scene.Bool
 	f.Freeleech = freeleech.Bool
+	f.AdvancedExpression = advancedExpression.String
+
+	if f.Schedule, err = unmarshalFilterSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if f.GroupTiers, err = unmarshalFilterGroupTiers(groupTiers); err != nil {
+		return nil, err
+	}
+
+	f.GroupID = int(groupID.Int32)
 
 	return &f, nil
 }
@@ -408,6 +503,11 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			"f.freeleech",
 			"f.freeleech_percent",
 			"f.smart_episode",
+			"f.ignore_season_packs",
+			"f.upgrade_replace",
+			"f.group_tiers",
+			"f.smart_music",
+			"f.smart_music_match",
 			"f.shows",
 			"f.seasons",
 			"f.episodes",
@@ -448,16 +548,39 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			"f.max_seeders",
 			"f.min_leechers",
 			"f.max_leechers",
+			"f.breaker_threshold",
+			"f.breaker_cooldown",
+			"f.breaker_fail_open",
+			"f.schedule",
+			"f.advanced_expression",
+			"f.normalize_categories",
+			"f.smart_duplicate_days",
+			"f.group_id",
+			"f.max_downloads_size",
+			"f.max_downloads_size_unit",
+			"f.skip_dedupe",
+			"f.max_files",
+			"f.match_file_extensions",
+			"f.except_file_extensions",
+			"f.match_ids",
+			"f.match_musicbrainz",
+			"f.sonarr_check_client_id",
+			"f.radarr_check_client_id",
+			"f.lidarr_check_client_id",
+			"f.library_check_client_id",
 			"f.created_at",
 			"f.updated_at",
 		).
 		From("filter f").
 		Join("filter_indexer fi ON f.id = fi.filter_id").
 		Join("indexer i ON i.id = fi.indexer_id").
+		LeftJoin("filter_group g ON g.id = f.group_id").
 		Where(sq.Eq{"i.identifier": indexer}).
 		Where(sq.Eq{"i.enabled": true}).
 		Where(sq.Eq{"f.enabled": true}).
-		OrderBy("f.priority DESC")
+		Where(sq.Eq{"f.deleted_at": nil}).
+		Where(sq.Or{sq.Eq{"f.group_id": nil}, sq.Eq{"g.enabled": true}}).
+		OrderBy("COALESCE(g.priority, 0) DESC", "f.priority DESC")
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -478,7 +601,10 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 
 		var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, matchReleaseTags, exceptReleaseTags, matchDescription, exceptDescription, freeleechPercent, shows, seasons, episodes, years, months, days, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, tagsMatchLogic, exceptTagsMatchLogic sql.NullString
 		var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac sql.NullBool
-		var delay, maxDownloads, logScore sql.NullInt32
+		var delay, maxDownloads, logScore, smartDuplicateDays sql.NullInt32
+		var schedule, groupTiers sql.Null[string]
+		var advancedExpression, maxDownloadsSize, maxDownloadsSizeUnit, matchFileExtensions, exceptFileExtensions, matchIDs, matchMusicBrainz sql.NullString
+		var groupID sql.NullInt32
 
 		err := rows.Scan(
 			&f.ID,
@@ -505,6 +631,11 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			&freeleech,
 			&freeleechPercent,
 			&f.SmartEpisode,
+			&f.IgnoreSeasonPacks,
+			&f.UpgradeReplace,
+			&groupTiers,
+			&f.SmartMusic,
+			pq.Array(&f.SmartMusicMatch),
 			&shows,
 			&seasons,
 			&episodes,
@@ -545,6 +676,26 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			&f.MaxSeeders,
 			&f.MinLeechers,
 			&f.MaxLeechers,
+			&f.BreakerThreshold,
+			&f.BreakerCooldown,
+			&f.BreakerFailOpen,
+			&schedule,
+			&advancedExpression,
+			&f.NormalizeCategories,
+			&smartDuplicateDays,
+			&groupID,
+			&maxDownloadsSize,
+			&maxDownloadsSizeUnit,
+			&f.SkipDedupe,
+			&f.MaxFiles,
+			&matchFileExtensions,
+			&exceptFileExtensions,
+			&matchIDs,
+			&matchMusicBrainz,
+			&f.SonarrCheckClientID,
+			&f.RadarrCheckClientID,
+			&f.LidarrCheckClientID,
+			&f.LibraryCheckClientID,
 			&f.CreatedAt,
 			&f.UpdatedAt,
 		)
@@ -556,7 +707,14 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 		f.MaxSize = maxSize.String
 		f.Delay = int(delay.Int32)
 		f.MaxDownloads = int(maxDownloads.Int32)
+		f.SmartDuplicateDays = int(smartDuplicateDays.Int32)
 		f.MaxDownloadsUnit = domain.FilterMaxDownloadsUnit(maxDownloadsUnit.String)
+		f.MaxDownloadsSize = maxDownloadsSize.String
+		f.MaxDownloadsSizeUnit = domain.FilterMaxDownloadsUnit(maxDownloadsSizeUnit.String)
+		f.MatchFileExtensions = matchFileExtensions.String
+		f.ExceptFileExtensions = exceptFileExtensions.String
+		f.MatchIDs = matchIDs.String
+		f.MatchMusicBrainz = matchMusicBrainz.String
 		f.MatchReleases = matchReleases.String
 		f.ExceptReleases = exceptReleases.String
 		f.MatchReleaseGroups = matchReleaseGroups.String
@@ -589,6 +747,17 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 		f.UseRegex = useRegex.Bool
 		f.Scene = scene.Bool
 		f.Freeleech = freeleech.Bool
+		f.AdvancedExpression = advancedExpression.String
+
+		if f.Schedule, err = unmarshalFilterSchedule(schedule); err != nil {
+			return nil, err
+		}
+
+		if f.GroupTiers, err = unmarshalFilterGroupTiers(groupTiers); err != nil {
+			return nil, err
+		}
+
+		f.GroupID = int(groupID.Int32)
 
 		f.Rejections = []string{}
 
@@ -617,6 +786,7 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 			"fe.webhook_retry_status",
 			"fe.webhook_retry_attempts",
 			"fe.webhook_retry_delay_seconds",
+			"fe.webhook_timeout_seconds",
 		).
 		From("filter_external fe").
 		Where(sq.Eq{"fe.filter_id": filterId})
@@ -641,7 +811,7 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 
 		// filter external
 		var extExecCmd, extExecArgs, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData, extWebhookRetryStatus sql.NullString
-		var extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extExecStatus sql.NullInt32
+		var extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extWebhookTimeoutSeconds, extExecStatus sql.NullInt32
 
 		if err := rows.Scan(
 			&external.ID,
@@ -660,6 +830,7 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 			&extWebhookRetryStatus,
 			&extWebhookRetryAttempts,
 			&extWebhookDelaySeconds,
+			&extWebhookTimeoutSeconds,
 		); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
@@ -676,6 +847,7 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 		external.WebhookRetryStatus = extWebhookRetryStatus.String
 		external.WebhookRetryAttempts = int(extWebhookRetryAttempts.Int32)
 		external.WebhookRetryDelaySeconds = int(extWebhookDelaySeconds.Int32)
+		external.WebhookTimeoutSeconds = int(extWebhookTimeoutSeconds.Int32)
 
 		externalFilters = append(externalFilters, external)
 	}
@@ -684,6 +856,16 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 }
 
 func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
+	schedule, err := marshalFilterSchedule(filter.Schedule)
+	if err != nil {
+		return err
+	}
+
+	groupTiers, err := marshalFilterGroupTiers(filter.GroupTiers)
+	if err != nil {
+		return err
+	}
+
 	queryBuilder := r.db.squirrel.
 		Insert("filter").
 		Columns(
@@ -710,6 +892,11 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			"freeleech",
 			"freeleech_percent",
 			"smart_episode",
+			"ignore_season_packs",
+			"upgrade_replace",
+			"group_tiers",
+			"smart_music",
+			"smart_music_match",
 			"shows",
 			"seasons",
 			"episodes",
@@ -750,6 +937,28 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			"max_seeders",
 			"min_leechers",
 			"max_leechers",
+			"breaker_threshold",
+			"breaker_cooldown",
+			"breaker_fail_open",
+			"schedule",
+			"advanced_expression",
+			"normalize_categories",
+			"smart_duplicate_days",
+			"group_id",
+			"max_downloads_size",
+			"max_downloads_size_unit",
+			"skip_dedupe",
+			"max_files",
+			"match_file_extensions",
+			"except_file_extensions",
+			"match_ids",
+			"match_musicbrainz",
+			"sonarr_check_client_id",
+			"radarr_check_client_id",
+			"lidarr_check_client_id",
+			"library_check_client_id",
+			"created_by",
+			"updated_by",
 		).
 		Values(
 			filter.Name,
@@ -775,6 +984,11 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			filter.Freeleech,
 			filter.FreeleechPercent,
 			filter.SmartEpisode,
+			filter.IgnoreSeasonPacks,
+			filter.UpgradeReplace,
+			groupTiers,
+			filter.SmartMusic,
+			pq.Array(filter.SmartMusicMatch),
 			filter.Shows,
 			filter.Seasons,
 			filter.Episodes,
@@ -815,6 +1029,28 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			filter.MaxSeeders,
 			filter.MinLeechers,
 			filter.MaxLeechers,
+			filter.BreakerThreshold,
+			filter.BreakerCooldown,
+			filter.BreakerFailOpen,
+			schedule,
+			toNullString(filter.AdvancedExpression),
+			filter.NormalizeCategories,
+			filter.SmartDuplicateDays,
+			toNullInt32(int32(filter.GroupID)),
+			toNullString(filter.MaxDownloadsSize),
+			toNullString(string(filter.MaxDownloadsSizeUnit)),
+			filter.SkipDedupe,
+			filter.MaxFiles,
+			toNullString(filter.MatchFileExtensions),
+			toNullString(filter.ExceptFileExtensions),
+			toNullString(filter.MatchIDs),
+			toNullString(filter.MatchMusicBrainz),
+			filter.SonarrCheckClientID,
+			filter.RadarrCheckClientID,
+			filter.LidarrCheckClientID,
+			filter.LibraryCheckClientID,
+			toNullString(filter.CreatedBy),
+			toNullString(filter.UpdatedBy),
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -831,7 +1067,15 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 }
 
 func (r *FilterRepo) Update(ctx context.Context, filter *domain.Filter) error {
-	var err error
+	schedule, err := marshalFilterSchedule(filter.Schedule)
+	if err != nil {
+		return err
+	}
+
+	groupTiers, err := marshalFilterGroupTiers(filter.GroupTiers)
+	if err != nil {
+		return err
+	}
 
 	queryBuilder := r.db.squirrel.
 		Update("filter").
@@ -858,6 +1102,11 @@ func (r *FilterRepo) Update(ctx context.Context, filter *domain.Filter) error {
 		Set("freeleech", filter.Freeleech).
 		Set("freeleech_percent", filter.FreeleechPercent).
 		Set("smart_episode", filter.SmartEpisode).
+		Set("ignore_season_packs", filter.IgnoreSeasonPacks).
+		Set("upgrade_replace", filter.UpgradeReplace).
+		Set("group_tiers", groupTiers).
+		Set("smart_music", filter.SmartMusic).
+		Set("smart_music_match", pq.Array(filter.SmartMusicMatch)).
 		Set("shows", filter.Shows).
 		Set("seasons", filter.Seasons).
 		Set("episodes", filter.Episodes).
@@ -898,6 +1147,27 @@ func (r *FilterRepo) Update(ctx context.Context, filter *domain.Filter) error {
 		Set("max_seeders", filter.MaxSeeders).
 		Set("min_leechers", filter.MinLeechers).
 		Set("max_leechers", filter.MaxLeechers).
+		Set("breaker_threshold", filter.BreakerThreshold).
+		Set("breaker_cooldown", filter.BreakerCooldown).
+		Set("breaker_fail_open", filter.BreakerFailOpen).
+		Set("schedule", schedule).
+		Set("advanced_expression", toNullString(filter.AdvancedExpression)).
+		Set("normalize_categories", filter.NormalizeCategories).
+		Set("smart_duplicate_days", filter.SmartDuplicateDays).
+		Set("group_id", toNullInt32(int32(filter.GroupID))).
+		Set("max_downloads_size", toNullString(filter.MaxDownloadsSize)).
+		Set("max_downloads_size_unit", toNullString(string(filter.MaxDownloadsSizeUnit))).
+		Set("skip_dedupe", filter.SkipDedupe).
+		Set("max_files", filter.MaxFiles).
+		Set("match_file_extensions", toNullString(filter.MatchFileExtensions)).
+		Set("except_file_extensions", toNullString(filter.ExceptFileExtensions)).
+		Set("match_ids", toNullString(filter.MatchIDs)).
+		Set("match_musicbrainz", toNullString(filter.MatchMusicBrainz)).
+		Set("sonarr_check_client_id", filter.SonarrCheckClientID).
+		Set("radarr_check_client_id", filter.RadarrCheckClientID).
+		Set("lidarr_check_client_id", filter.LidarrCheckClientID).
+		Set("library_check_client_id", filter.LibraryCheckClientID).
+		Set("updated_by", toNullString(filter.UpdatedBy)).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where(sq.Eq{"id": filter.ID})
 
@@ -949,6 +1219,45 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.MaxDownloadsUnit != nil {
 		q = q.Set("max_downloads_unit", filter.MaxDownloadsUnit)
 	}
+	if filter.MaxDownloadsSize != nil {
+		q = q.Set("max_downloads_size", toNullString(*filter.MaxDownloadsSize))
+	}
+	if filter.MaxDownloadsSizeUnit != nil {
+		q = q.Set("max_downloads_size_unit", toNullString(string(*filter.MaxDownloadsSizeUnit)))
+	}
+	if filter.SkipDedupe != nil {
+		q = q.Set("skip_dedupe", filter.SkipDedupe)
+	}
+	if filter.MaxFiles != nil {
+		q = q.Set("max_files", filter.MaxFiles)
+	}
+	if filter.MatchFileExtensions != nil {
+		q = q.Set("match_file_extensions", toNullString(*filter.MatchFileExtensions))
+	}
+	if filter.ExceptFileExtensions != nil {
+		q = q.Set("except_file_extensions", toNullString(*filter.ExceptFileExtensions))
+	}
+	if filter.MatchIDs != nil {
+		q = q.Set("match_ids", toNullString(*filter.MatchIDs))
+	}
+	if filter.MatchMusicBrainz != nil {
+		q = q.Set("match_musicbrainz", toNullString(*filter.MatchMusicBrainz))
+	}
+	if filter.SonarrCheckClientID != nil {
+		q = q.Set("sonarr_check_client_id", filter.SonarrCheckClientID)
+	}
+	if filter.RadarrCheckClientID != nil {
+		q = q.Set("radarr_check_client_id", filter.RadarrCheckClientID)
+	}
+	if filter.LidarrCheckClientID != nil {
+		q = q.Set("lidarr_check_client_id", filter.LidarrCheckClientID)
+	}
+	if filter.LibraryCheckClientID != nil {
+		q = q.Set("library_check_client_id", filter.LibraryCheckClientID)
+	}
+	if filter.UpdatedBy != nil {
+		q = q.Set("updated_by", filter.UpdatedBy)
+	}
 	if filter.UseRegex != nil {
 		q = q.Set("use_regex", filter.UseRegex)
 	}
@@ -994,6 +1303,25 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.SmartEpisode != nil {
 		q = q.Set("smart_episode", filter.SmartEpisode)
 	}
+	if filter.IgnoreSeasonPacks != nil {
+		q = q.Set("ignore_season_packs", filter.IgnoreSeasonPacks)
+	}
+	if filter.UpgradeReplace != nil {
+		q = q.Set("upgrade_replace", filter.UpgradeReplace)
+	}
+	if filter.GroupTiers != nil {
+		groupTiers, err := marshalFilterGroupTiers(*filter.GroupTiers)
+		if err != nil {
+			return err
+		}
+		q = q.Set("group_tiers", groupTiers)
+	}
+	if filter.SmartMusic != nil {
+		q = q.Set("smart_music", filter.SmartMusic)
+	}
+	if filter.SmartMusicMatch != nil {
+		q = q.Set("smart_music_match", pq.Array(filter.SmartMusicMatch))
+	}
 	if filter.Shows != nil {
 		q = q.Set("shows", filter.Shows)
 	}
@@ -1114,6 +1442,34 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.MaxLeechers != nil {
 		q = q.Set("max_leechers", filter.MaxLeechers)
 	}
+	if filter.BreakerThreshold != nil {
+		q = q.Set("breaker_threshold", filter.BreakerThreshold)
+	}
+	if filter.BreakerCooldown != nil {
+		q = q.Set("breaker_cooldown", filter.BreakerCooldown)
+	}
+	if filter.BreakerFailOpen != nil {
+		q = q.Set("breaker_fail_open", filter.BreakerFailOpen)
+	}
+	if filter.GroupID != nil {
+		q = q.Set("group_id", toNullInt32(int32(*filter.GroupID)))
+	}
+	if filter.Schedule != nil {
+		schedule, err := marshalFilterSchedule(filter.Schedule)
+		if err != nil {
+			return err
+		}
+		q = q.Set("schedule", schedule)
+	}
+	if filter.AdvancedExpression != nil {
+		q = q.Set("advanced_expression", toNullString(*filter.AdvancedExpression))
+	}
+	if filter.NormalizeCategories != nil {
+		q = q.Set("normalize_categories", filter.NormalizeCategories)
+	}
+	if filter.SmartDuplicateDays != nil {
+		q = q.Set("smart_duplicate_days", filter.SmartDuplicateDays)
+	}
 
 	q = q.Where(sq.Eq{"id": filter.ID})
 
@@ -1303,6 +1659,114 @@ func (r *FilterRepo) Delete(ctx context.Context, filterID int) error {
 	return nil
 }
 
+// Trash marks the filter as deleted, trashed filters are hidden and not checked until restored
+func (r *FilterRepo) Trash(ctx context.Context, filterID int) error {
+	queryBuilder := r.db.squirrel.
+		Update("filter").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": filterID}).
+		Where(sq.Eq{"deleted_at": nil})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	} else if rowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	r.log.Debug().Msgf("filter.trash: successfully trashed: %v", filterID)
+
+	return nil
+}
+
+func (r *FilterRepo) Restore(ctx context.Context, filterID int) error {
+	queryBuilder := r.db.squirrel.
+		Update("filter").
+		Set("deleted_at", nil).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": filterID}).
+		Where(sq.NotEq{"deleted_at": nil})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	} else if rowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	r.log.Debug().Msgf("filter.restore: successfully restored: %v", filterID)
+
+	return nil
+}
+
+func (r *FilterRepo) ListTrashed(ctx context.Context) ([]domain.Filter, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"f.id",
+			"f.enabled",
+			"f.name",
+			"f.priority",
+			"f.created_at",
+			"f.updated_at",
+			"f.deleted_at",
+		).
+		From("filter f").
+		Where(sq.NotEq{"f.deleted_at": nil}).
+		OrderBy("f.deleted_at DESC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	filters := make([]domain.Filter, 0)
+	for rows.Next() {
+		var f domain.Filter
+		var deletedAt sql.NullTime
+
+		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Priority, &f.CreatedAt, &f.UpdatedAt, &deletedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		if deletedAt.Valid {
+			f.DeletedAt = &deletedAt.Time
+		}
+
+		filters = append(filters, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return filters, nil
+}
+
 // GetDownloadsByFilterId looks up how many `PENDING` or `PUSH_APPROVED`
 // releases there have been for the given filter in the current time window
 // starting at the start of the unit (since the beginning of the most recent
@@ -1343,6 +1807,26 @@ WHERE (release_action_status.status = 'PUSH_APPROVED' OR release_action_status.s
 		return nil, errors.Wrap(err, "error scanning stats data sqlite")
 	}
 
+	// a release pushed by several actions is only counted once, from its first push
+	sizeQuery := `SELECT
+	COALESCE(SUM(CASE WHEN pushed_at >= CAST(strftime('%s', strftime('%Y-%m-%dT%H:00:00', datetime('now','localtime'))) AS INTEGER) THEN size END), 0) as "hour_size",
+	COALESCE(SUM(CASE WHEN pushed_at >= CAST(strftime('%s', datetime('now', 'localtime', 'start of day')) AS INTEGER) THEN size END), 0) as "day_size",
+	COALESCE(SUM(CASE WHEN pushed_at >= CAST(strftime('%s', datetime('now', 'localtime', 'weekday 0', '-7 days', 'start of day')) AS INTEGER) THEN size END), 0) as "week_size",
+	COALESCE(SUM(CASE WHEN pushed_at >= CAST(strftime('%s', datetime('now', 'localtime', 'start of month')) AS INTEGER) THEN size END), 0) as "month_size",
+	COALESCE(SUM(size), 0) as "total_size"
+FROM (
+	SELECT r.size AS size, MIN(CAST(strftime('%s', datetime(release_action_status.timestamp, 'localtime')) AS INTEGER)) AS pushed_at
+	FROM release_action_status
+	JOIN "release" r ON r.id = release_action_status.release_id
+	WHERE (release_action_status.status = 'PUSH_APPROVED' OR release_action_status.status = 'PENDING') AND release_action_status.filter_id = ?
+	GROUP BY r.id, r.size
+);`
+
+	row = r.db.handler.QueryRowContext(ctx, sizeQuery, filterID)
+	if err := row.Scan(&f.HourSize, &f.DaySize, &f.WeekSize, &f.MonthSize, &f.TotalSize); err != nil {
+		return nil, errors.Wrap(err, "error scanning size stats data sqlite")
+	}
+
 	r.log.Trace().Msgf("filter %v downloads: %+v", filterID, &f)
 
 	return &f, nil
@@ -1373,6 +1857,26 @@ WHERE (release_action_status.status = 'PUSH_APPROVED' OR release_action_status.s
 		return nil, errors.Wrap(err, "error scanning stats data postgres")
 	}
 
+	// a release pushed by several actions is only counted once, from its first push
+	sizeQuery := `SELECT
+    COALESCE(SUM(CASE WHEN pushed_at >= date_trunc('hour', CURRENT_TIMESTAMP) THEN size ELSE 0 END),0) as "hour_size",
+    COALESCE(SUM(CASE WHEN pushed_at >= date_trunc('day', CURRENT_DATE) THEN size ELSE 0 END),0) as "day_size",
+    COALESCE(SUM(CASE WHEN pushed_at >= date_trunc('week', CURRENT_DATE) THEN size ELSE 0 END),0) as "week_size",
+    COALESCE(SUM(CASE WHEN pushed_at >= date_trunc('month', CURRENT_DATE) THEN size ELSE 0 END),0) as "month_size",
+    COALESCE(SUM(size),0) as "total_size"
+FROM (
+    SELECT r.size AS size, MIN(release_action_status.timestamp) AS pushed_at
+    FROM release_action_status
+    JOIN "release" r ON r.id = release_action_status.release_id
+    WHERE (release_action_status.status = 'PUSH_APPROVED' OR release_action_status.status = 'PENDING') AND release_action_status.filter_id = $1
+    GROUP BY r.id, r.size
+) pushed;`
+
+	row = r.db.handler.QueryRowContext(ctx, sizeQuery, filterID)
+	if err := row.Scan(&f.HourSize, &f.DaySize, &f.WeekSize, &f.MonthSize, &f.TotalSize); err != nil {
+		return nil, errors.Wrap(err, "error scanning size stats data postgres")
+	}
+
 	return &f, nil
 }
 
@@ -1424,6 +1928,7 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 			"webhook_retry_status",
 			"webhook_retry_attempts",
 			"webhook_retry_delay_seconds",
+			"webhook_timeout_seconds",
 			"filter_id",
 		)
 
@@ -1444,6 +1949,7 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 			toNullString(external.WebhookRetryStatus),
 			toNullInt32(int32(external.WebhookRetryAttempts)),
 			toNullInt32(int32(external.WebhookRetryDelaySeconds)),
+			toNullInt32(int32(external.WebhookTimeoutSeconds)),
 			filterID,
 		)
 	}
@@ -1466,3 +1972,55 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 
 	return nil
 }
+
+func marshalFilterSchedule(schedule *domain.FilterSchedule) (sql.Null[string], error) {
+	if !schedule.IsSet() {
+		return sql.Null[string]{}, nil
+	}
+
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return sql.Null[string]{}, errors.Wrap(err, "error marshaling schedule")
+	}
+
+	return sql.Null[string]{V: string(data), Valid: true}, nil
+}
+
+func unmarshalFilterSchedule(data sql.Null[string]) (*domain.FilterSchedule, error) {
+	if !data.Valid || data.V == "" {
+		return nil, nil
+	}
+
+	var schedule domain.FilterSchedule
+	if err := json.Unmarshal([]byte(data.V), &schedule); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal schedule")
+	}
+
+	return &schedule, nil
+}
+
+func marshalFilterGroupTiers(tiers []domain.FilterGroupTier) (sql.Null[string], error) {
+	if len(tiers) == 0 {
+		return sql.Null[string]{}, nil
+	}
+
+	data, err := json.Marshal(tiers)
+	if err != nil {
+		return sql.Null[string]{}, errors.Wrap(err, "error marshaling group tiers")
+	}
+
+	return sql.Null[string]{V: string(data), Valid: true}, nil
+}
+
+func unmarshalFilterGroupTiers(data sql.Null[string]) ([]domain.FilterGroupTier, error) {
+	if !data.Valid || data.V == "" {
+		return nil, nil
+	}
+
+	var tiers []domain.FilterGroupTier
+	if err := json.Unmarshal([]byte(data.V), &tiers); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal group tiers")
+	}
+
+	return tiers, nil
+}
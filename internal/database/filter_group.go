@@ -0,0 +1,280 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type FilterGroupRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewFilterGroupRepo(log logger.Logger, db *DB) domain.FilterGroupRepo {
+	return &FilterGroupRepo{
+		log: log.With().Str("repo", "filter_group").Logger(),
+		db:  db,
+	}
+}
+
+func (r *FilterGroupRepo) selectGroups() sq.SelectBuilder {
+	filterCountQuery := r.db.squirrel.
+		Select("COUNT(*)").
+		From("filter f").
+		Where("f.group_id = g.id").
+		Where(sq.Eq{"f.deleted_at": nil})
+
+	return r.db.squirrel.
+		Select(
+			"g.id",
+			"g.name",
+			"g.enabled",
+			"g.priority",
+			"g.created_at",
+			"g.updated_at",
+		).
+		Column(sq.Alias(filterCountQuery, "filter_count")).
+		From("filter_group g")
+}
+
+func (r *FilterGroupRepo) List(ctx context.Context) ([]domain.FilterGroup, error) {
+	queryBuilder := r.selectGroups().
+		OrderBy("g.priority DESC", "g.name ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	groups := make([]domain.FilterGroup, 0)
+	for rows.Next() {
+		var g domain.FilterGroup
+
+		if err := rows.Scan(&g.ID, &g.Name, &g.Enabled, &g.Priority, &g.CreatedAt, &g.UpdatedAt, &g.FilterCount); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return groups, nil
+}
+
+func (r *FilterGroupRepo) FindByID(ctx context.Context, groupID int) (*domain.FilterGroup, error) {
+	queryBuilder := r.selectGroups().
+		Where(sq.Eq{"g.id": groupID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	var g domain.FilterGroup
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Scan(&g.ID, &g.Name, &g.Enabled, &g.Priority, &g.CreatedAt, &g.UpdatedAt, &g.FilterCount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	return &g, nil
+}
+
+func (r *FilterGroupRepo) Store(ctx context.Context, group *domain.FilterGroup) error {
+	queryBuilder := r.db.squirrel.
+		Insert("filter_group").
+		Columns(
+			"name",
+			"enabled",
+			"priority",
+		).
+		Values(
+			group.Name,
+			group.Enabled,
+			group.Priority,
+		).
+		Suffix("RETURNING id, created_at, updated_at").
+		RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FilterGroupRepo) Update(ctx context.Context, group *domain.FilterGroup) error {
+	queryBuilder := r.db.squirrel.
+		Update("filter_group").
+		Set("name", group.Name).
+		Set("enabled", group.Enabled).
+		Set("priority", group.Priority).
+		Set("updated_at", time.Now().Format(time.RFC3339)).
+		Where(sq.Eq{"id": group.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	} else if rowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *FilterGroupRepo) ToggleEnabled(ctx context.Context, groupID int, enabled bool) error {
+	queryBuilder := r.db.squirrel.
+		Update("filter_group").
+		Set("enabled", enabled).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": groupID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	} else if rowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the group, its filters are kept without a group
+func (r *FilterGroupRepo) Delete(ctx context.Context, groupID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	// foreign keys are not enforced by sqlite so clear the group of the filters explicitly
+	ungroupQueryBuilder := r.db.squirrel.
+		Update("filter").
+		Set("group_id", nil).
+		Where(sq.Eq{"group_id": groupID})
+
+	query, args, err := ungroupQueryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	deleteQueryBuilder := r.db.squirrel.
+		Delete("filter_group").
+		Where(sq.Eq{"id": groupID})
+
+	query, args, err = deleteQueryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	} else if rowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit deleting filter group")
+	}
+
+	r.log.Debug().Msgf("filter_group.delete: successfully deleted: %v", groupID)
+
+	return nil
+}
+
+// MoveFilters sets the group of the filters, group id 0 removes them from their group.
+// Nothing is moved if one of the filters does not exist.
+func (r *FilterGroupRepo) MoveFilters(ctx context.Context, groupID int, filterIDs []int) error {
+	if len(filterIDs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	queryBuilder := r.db.squirrel.
+		Update("filter").
+		Set("group_id", toNullInt32(int32(groupID))).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": filterIDs}).
+		Where(sq.Eq{"deleted_at": nil})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	} else if rowsAffected != int64(len(filterIDs)) {
+		return domain.ErrRecordNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit moving filters")
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterGroupRepo_Store(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewFilterGroupRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_Update_Delete_Succeeds [%s]", dbType), func(t *testing.T) {
+			group := &domain.FilterGroup{Name: "TV", Enabled: true, Priority: 10}
+
+			err := repo.Store(context.Background(), group)
+			require.NoError(t, err)
+			assert.NotZero(t, group.ID)
+
+			group.Name = "Series"
+			group.Priority = 5
+			err = repo.Update(context.Background(), group)
+			assert.NoError(t, err)
+
+			err = repo.ToggleEnabled(context.Background(), group.ID, false)
+			assert.NoError(t, err)
+
+			found, err := repo.FindByID(context.Background(), group.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Series", found.Name)
+			assert.Equal(t, int32(5), found.Priority)
+			assert.False(t, found.Enabled)
+
+			groups, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, groups, 1)
+
+			err = repo.Delete(context.Background(), group.ID)
+			assert.NoError(t, err)
+
+			_, err = repo.FindByID(context.Background(), group.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			err = repo.Delete(context.Background(), group.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+		})
+	}
+}
+
+func TestFilterGroupRepo_Ordering(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewFilterGroupRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		indexerRepo := NewIndexerRepo(log, db)
+
+		t.Run(fmt.Sprintf("FindByIndexerIdentifier_Orders_By_Group [%s]", dbType), func(t *testing.T) {
+			indexer, err := indexerRepo.Store(context.Background(), getMockIndexer())
+			require.NoError(t, err)
+
+			high := &domain.FilterGroup{Name: "high", Enabled: true, Priority: 10}
+			require.NoError(t, repo.Store(context.Background(), high))
+
+			disabled := &domain.FilterGroup{Name: "disabled", Enabled: false, Priority: 20}
+			require.NoError(t, repo.Store(context.Background(), disabled))
+
+			var filters []*domain.Filter
+			for _, data := range []struct {
+				name     string
+				priority int32
+			}{{"ungrouped", 100}, {"high low", 1}, {"high high", 2}, {"disabled", 50}} {
+				filter := getMockFilter()
+				filter.Name = data.name
+				filter.Priority = data.priority
+				filters = append(filters, filter)
+			}
+
+			for _, filter := range filters {
+				require.NoError(t, filterRepo.Store(context.Background(), filter))
+				require.NoError(t, filterRepo.StoreIndexerConnection(context.Background(), filter.ID, int(indexer.ID)))
+			}
+
+			require.NoError(t, repo.MoveFilters(context.Background(), high.ID, []int{filters[1].ID, filters[2].ID}))
+			require.NoError(t, repo.MoveFilters(context.Background(), disabled.ID, []int{filters[3].ID}))
+
+			// unknown filters are reported
+			err = repo.MoveFilters(context.Background(), high.ID, []int{filters[0].ID, 99999})
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			found, err := filterRepo.FindByIndexerIdentifier(context.Background(), indexer.Identifier)
+			require.NoError(t, err)
+
+			var names []string
+			for _, f := range found {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, []string{"high high", "high low", "ungrouped"}, names)
+
+			groups, err := repo.List(context.Background())
+			require.NoError(t, err)
+			require.Len(t, groups, 2)
+			assert.Equal(t, "disabled", groups[0].Name)
+			assert.Equal(t, 1, groups[0].FilterCount)
+
+			// deleting a group keeps its filters
+			require.NoError(t, repo.Delete(context.Background(), disabled.ID))
+
+			filter, err := filterRepo.FindByID(context.Background(), filters[3].ID)
+			require.NoError(t, err)
+			assert.Zero(t, filter.GroupID)
+
+			// Cleanup
+			_ = indexerRepo.Delete(context.Background(), int(indexer.ID))
+			for _, filter := range filters {
+				_ = filterRepo.Delete(context.Background(), filter.ID)
+			}
+			_ = repo.Delete(context.Background(), high.ID)
+		})
+	}
+}
@@ -221,6 +221,188 @@ func TestFilterRepo_Delete(t *testing.T) {
 	}
 }
 
+func TestFilterRepo_Trash(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewFilterRepo(log, db)
+		mockData := getMockFilter()
+
+		t.Run(fmt.Sprintf("Trash_And_Restore_Succeeds [%s]", dbType), func(t *testing.T) {
+			// Setup
+			err := repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			// Execute
+			err = repo.Trash(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+
+			// Verify that the filter is hidden but kept in the trash
+			_, err = repo.FindByID(context.Background(), mockData.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			filters, err := repo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, filters)
+
+			trashed, err := repo.ListTrashed(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, trashed, 1)
+			assert.Equal(t, mockData.ID, trashed[0].ID)
+			assert.NotNil(t, trashed[0].DeletedAt)
+
+			// trashing twice is not allowed
+			err = repo.Trash(context.Background(), mockData.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			err = repo.Restore(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+
+			filter, err := repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, mockData.Name, filter.Name)
+
+			trashed, err = repo.ListTrashed(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, trashed)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+		})
+
+		t.Run(fmt.Sprintf("Restore_Fails_Not_Trashed [%s]", dbType), func(t *testing.T) {
+			err := repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			err = repo.Restore(context.Background(), mockData.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+		})
+	}
+}
+
+func TestFilterRepo_Schedule(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewFilterRepo(log, db)
+		mockData := getMockFilter()
+		mockData.Schedule = &domain.FilterSchedule{
+			Timezone: "UTC",
+			Windows:  []domain.FilterScheduleWindow{{Days: []string{"sat", "sun"}, Start: "08:00", End: "20:00"}},
+		}
+
+		t.Run(fmt.Sprintf("Store_And_Clear_Schedule [%s]", dbType), func(t *testing.T) {
+			err := repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			filter, err := repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, mockData.Schedule, filter.Schedule)
+
+			filters, err := repo.Find(context.Background(), domain.FilterQueryParams{})
+			assert.NoError(t, err)
+			assert.Len(t, filters, 1)
+			assert.Equal(t, mockData.Schedule, filters[0].Schedule)
+
+			// an empty schedule clears it
+			err = repo.UpdatePartial(context.Background(), domain.FilterUpdate{ID: mockData.ID, Schedule: &domain.FilterSchedule{}})
+			assert.NoError(t, err)
+
+			filter, err = repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Nil(t, filter.Schedule)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+		})
+	}
+}
+
+func TestFilterRepo_FileChecks(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewFilterRepo(log, db)
+		mockData := getMockFilter()
+		mockData.MaxFiles = 20
+		mockData.MatchFileExtensions = "mkv,mp4"
+		mockData.ExceptFileExtensions = "exe,lnk"
+		mockData.SonarrCheckClientID = 3
+		mockData.RadarrCheckClientID = 4
+		mockData.LidarrCheckClientID = 5
+		mockData.LibraryCheckClientID = 6
+		mockData.MatchIDs = "tt0944947,tvdb:121361"
+		mockData.MatchMusicBrainz = "Daft Punk"
+
+		t.Run(fmt.Sprintf("Store_And_Clear_File_Checks [%s]", dbType), func(t *testing.T) {
+			err := repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			filter, err := repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, 20, filter.MaxFiles)
+			assert.Equal(t, "mkv,mp4", filter.MatchFileExtensions)
+			assert.Equal(t, "exe,lnk", filter.ExceptFileExtensions)
+			assert.Equal(t, int32(3), filter.SonarrCheckClientID)
+			assert.Equal(t, int32(4), filter.RadarrCheckClientID)
+			assert.Equal(t, int32(5), filter.LidarrCheckClientID)
+			assert.Equal(t, int32(6), filter.LibraryCheckClientID)
+			assert.Equal(t, "tt0944947,tvdb:121361", filter.MatchIDs)
+			assert.Equal(t, "Daft Punk", filter.MatchMusicBrainz)
+
+			maxFiles, exceptFileExtensions, sonarrCheckClientID := 0, "", int32(0)
+			err = repo.UpdatePartial(context.Background(), domain.FilterUpdate{ID: mockData.ID, MaxFiles: &maxFiles, ExceptFileExtensions: &exceptFileExtensions, SonarrCheckClientID: &sonarrCheckClientID})
+			assert.NoError(t, err)
+
+			filter, err = repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, filter.MaxFiles)
+			assert.Equal(t, "mkv,mp4", filter.MatchFileExtensions)
+			assert.Empty(t, filter.ExceptFileExtensions)
+			assert.Zero(t, filter.SonarrCheckClientID)
+			assert.Equal(t, int32(4), filter.RadarrCheckClientID)
+			assert.Equal(t, int32(5), filter.LidarrCheckClientID)
+			assert.Equal(t, int32(6), filter.LibraryCheckClientID)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+		})
+	}
+}
+
+func TestFilterRepo_GroupTiers(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewFilterRepo(log, db)
+		mockData := getMockFilter()
+		mockData.GroupTiers = []domain.FilterGroupTier{
+			{Groups: []string{"GROUP-A"}},
+			{Groups: []string{"GROUP-B", "GROUP-C"}, DelayMinutes: 30},
+			{Groups: []string{"*"}, DelayMinutes: 120},
+		}
+
+		t.Run(fmt.Sprintf("Store_And_Clear_Group_Tiers [%s]", dbType), func(t *testing.T) {
+			err := repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			filter, err := repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, mockData.GroupTiers, filter.GroupTiers)
+
+			// no tiers clears them
+			err = repo.UpdatePartial(context.Background(), domain.FilterUpdate{ID: mockData.ID, GroupTiers: &[]domain.FilterGroupTier{}})
+			assert.NoError(t, err)
+
+			filter, err = repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Nil(t, filter.GroupTiers)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+		})
+	}
+}
+
 func TestFilterRepo_UpdatePartial(t *testing.T) {
 	for dbType, db := range testDBs {
 		log := setupLoggerForTest()
@@ -814,23 +996,34 @@ func TestFilterRepo_GetDownloadsByFilterId(t *testing.T) {
 			err = releaseRepo.StoreReleaseActionStatus(context.Background(), mockReleaseActionStatus)
 			assert.NoError(t, err)
 
+			// a second push of the same release counts as a download but not towards the size
+			secondStatus := *mockReleaseActionStatus
+			secondStatus.ID = 0
+			err = releaseRepo.StoreReleaseActionStatus(context.Background(), &secondStatus)
+			assert.NoError(t, err)
+
 			// Execute
 			downloads, err := repo.GetDownloadsByFilterId(context.Background(), mockData.ID)
 			assert.NoError(t, err)
 			assert.NotNil(t, downloads)
 			assert.Equal(t, downloads, &domain.FilterDownloads{
-				HourCount:  1,
-				DayCount:   1,
-				WeekCount:  1,
-				MonthCount: 1,
-				TotalCount: 1,
+				HourCount:  2,
+				DayCount:   2,
+				WeekCount:  2,
+				MonthCount: 2,
+				TotalCount: 2,
+				HourSize:   mockRelease.Size,
+				DaySize:    mockRelease.Size,
+				WeekSize:   mockRelease.Size,
+				MonthSize:  mockRelease.Size,
+				TotalSize:  mockRelease.Size,
 			})
 
 			// Cleanup
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: action.ID})
 			_ = repo.Delete(context.Background(), mockData.ID)
 			_ = downloadClientRepo.Delete(context.Background(), mockClient.ID)
-			_ = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 		})
 
 		t.Run(fmt.Sprintf("GetDownloadsByFilterId_Fails_Invalid_ID [%s]", dbType), func(t *testing.T) {
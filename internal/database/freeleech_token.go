@@ -0,0 +1,124 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type FreeleechTokenRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewFreeleechTokenRepo(log logger.Logger, db *DB) domain.FreeleechTokenRepo {
+	return &FreeleechTokenRepo{
+		log: log.With().Str("module", "database").Str("repo", "freeleech_token").Logger(),
+		db:  db,
+	}
+}
+
+func (r *FreeleechTokenRepo) budgetQuery(since time.Time) sq.SelectBuilder {
+	return r.db.squirrel.
+		Select("b.indexer", "b.monthly_budget").
+		Column(sq.Expr("(SELECT COUNT(*) FROM freeleech_token_usage u WHERE u.indexer = b.indexer AND u.used_at >= ?) AS used", since)).
+		From("freeleech_token_budget b")
+}
+
+// ListBudgets returns all budgets with the tokens used since the start of the period
+func (r *FreeleechTokenRepo) ListBudgets(ctx context.Context, since time.Time) ([]domain.FreeleechTokenBudget, error) {
+	query, args, err := r.budgetQuery(since).OrderBy("b.indexer ASC").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	budgets := make([]domain.FreeleechTokenBudget, 0)
+	for rows.Next() {
+		var b domain.FreeleechTokenBudget
+		if err := rows.Scan(&b.Indexer, &b.MonthlyBudget, &b.Used); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		budgets = append(budgets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return budgets, nil
+}
+
+// GetBudget returns the budget for the indexer, or nil if none is configured
+func (r *FreeleechTokenRepo) GetBudget(ctx context.Context, indexer string, since time.Time) (*domain.FreeleechTokenBudget, error) {
+	query, args, err := r.budgetQuery(since).Where(sq.Eq{"b.indexer": indexer}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	var b domain.FreeleechTokenBudget
+	if err := r.db.handler.QueryRowContext(ctx, query, args...).Scan(&b.Indexer, &b.MonthlyBudget, &b.Used); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	return &b, nil
+}
+
+func (r *FreeleechTokenRepo) UpdateBudget(ctx context.Context, budget domain.FreeleechTokenBudget) error {
+	queryBuilder := r.db.squirrel.
+		Insert("freeleech_token_budget").
+		Columns("indexer", "monthly_budget", "updated_at").
+		Values(budget.Indexer, budget.MonthlyBudget, time.Now()).
+		Suffix("ON CONFLICT (indexer) DO UPDATE SET monthly_budget = EXCLUDED.monthly_budget, updated_at = EXCLUDED.updated_at")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("freeleech token budget for %s set to %d", budget.Indexer, budget.MonthlyBudget)
+
+	return nil
+}
+
+func (r *FreeleechTokenRepo) StoreUsage(ctx context.Context, usage domain.FreeleechTokenUsage) error {
+	queryBuilder := r.db.squirrel.
+		Insert("freeleech_token_usage").
+		Columns("indexer", "torrent_name", "filter_id", "size", "used_at").
+		Values(usage.Indexer, usage.TorrentName, toNullInt32(int32(usage.FilterID)), usage.Size, usage.UsedAt)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
@@ -35,9 +35,14 @@ func (r *IndexerRepo) Store(ctx context.Context, indexer domain.Indexer) (*domai
 		return nil, errors.Wrap(err, "error marshaling json data")
 	}
 
+	identity, err := marshalIndexerIdentity(indexer.Identity)
+	if err != nil {
+		return nil, err
+	}
+
 	queryBuilder := r.db.squirrel.
-		Insert("indexer").Columns("enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings").
-		Values(indexer.Enabled, indexer.Name, indexer.Identifier, indexer.IdentifierExternal, indexer.Implementation, indexer.BaseURL, indexer.UseProxy, toNullInt64(indexer.ProxyID), settings).
+		Insert("indexer").Columns("enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings", "identity").
+		Values(indexer.Enabled, indexer.Name, indexer.Identifier, indexer.IdentifierExternal, indexer.Implementation, indexer.BaseURL, indexer.UseProxy, toNullInt64(indexer.ProxyID), settings, identity).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
 	// return values
@@ -55,6 +60,11 @@ func (r *IndexerRepo) Update(ctx context.Context, indexer domain.Indexer) (*doma
 		return nil, errors.Wrap(err, "error marshaling json data")
 	}
 
+	identity, err := marshalIndexerIdentity(indexer.Identity)
+	if err != nil {
+		return nil, err
+	}
+
 	queryBuilder := r.db.squirrel.
 		Update("indexer").
 		Set("enabled", indexer.Enabled).
@@ -64,6 +74,7 @@ func (r *IndexerRepo) Update(ctx context.Context, indexer domain.Indexer) (*doma
 		Set("use_proxy", indexer.UseProxy).
 		Set("proxy_id", toNullInt64(indexer.ProxyID)).
 		Set("settings", settings).
+		Set("identity", identity).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where(sq.Eq{"id": indexer.ID})
 
@@ -91,7 +102,7 @@ func (r *IndexerRepo) Update(ctx context.Context, indexer domain.Indexer) (*doma
 
 func (r *IndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings").
+		Select("id", "enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings", "identity").
 		From("indexer").
 		OrderBy("name ASC")
 
@@ -116,8 +127,9 @@ func (r *IndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
 		var proxyID sql.Null[int64]
 		var settings string
 		var settingsMap map[string]string
+		var identity sql.Null[string]
 
-		if err := rows.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &identifierExternal, &implementation, &baseURL, &i.UseProxy, &proxyID, &settings); err != nil {
+		if err := rows.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &identifierExternal, &implementation, &baseURL, &i.UseProxy, &proxyID, &settings, &identity); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -132,6 +144,10 @@ func (r *IndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
 
 		i.Settings = settingsMap
 
+		if i.Identity, err = unmarshalIndexerIdentity(identity); err != nil {
+			return nil, err
+		}
+
 		indexers = append(indexers, i)
 	}
 	if err := rows.Err(); err != nil {
@@ -143,7 +159,7 @@ func (r *IndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
 
 func (r *IndexerRepo) FindByID(ctx context.Context, id int) (*domain.Indexer, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings").
+		Select("id", "enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings", "identity").
 		From("indexer").
 		Where(sq.Eq{"id": id})
 
@@ -159,10 +175,10 @@ func (r *IndexerRepo) FindByID(ctx context.Context, id int) (*domain.Indexer, er
 
 	var i domain.Indexer
 
-	var identifierExternal, implementation, baseURL, settings sql.Null[string]
+	var identifierExternal, implementation, baseURL, settings, identity sql.Null[string]
 	var proxyID sql.Null[int64]
 
-	if err := row.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &identifierExternal, &implementation, &baseURL, &i.UseProxy, &proxyID, &settings); err != nil {
+	if err := row.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &identifierExternal, &implementation, &baseURL, &i.UseProxy, &proxyID, &settings, &identity); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -182,12 +198,16 @@ func (r *IndexerRepo) FindByID(ctx context.Context, id int) (*domain.Indexer, er
 
 	i.Settings = settingsMap
 
+	if i.Identity, err = unmarshalIndexerIdentity(identity); err != nil {
+		return nil, err
+	}
+
 	return &i, nil
 }
 
 func (r *IndexerRepo) GetBy(ctx context.Context, req domain.GetIndexerRequest) (*domain.Indexer, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings").
+		Select("id", "enabled", "name", "identifier", "identifier_external", "implementation", "base_url", "use_proxy", "proxy_id", "settings", "identity").
 		From("indexer")
 
 	if req.ID > 0 {
@@ -210,10 +230,10 @@ func (r *IndexerRepo) GetBy(ctx context.Context, req domain.GetIndexerRequest) (
 
 	var i domain.Indexer
 
-	var identifierExternal, implementation, baseURL, settings sql.Null[string]
+	var identifierExternal, implementation, baseURL, settings, identity sql.Null[string]
 	var proxyID sql.Null[int64]
 
-	if err := row.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &identifierExternal, &implementation, &baseURL, &i.UseProxy, &proxyID, &settings); err != nil {
+	if err := row.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &identifierExternal, &implementation, &baseURL, &i.UseProxy, &proxyID, &settings, &identity); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -233,6 +253,10 @@ func (r *IndexerRepo) GetBy(ctx context.Context, req domain.GetIndexerRequest) (
 
 	i.Settings = settingsMap
 
+	if i.Identity, err = unmarshalIndexerIdentity(identity); err != nil {
+		return nil, err
+	}
+
 	return &i, nil
 }
 
@@ -344,3 +368,30 @@ func (r *IndexerRepo) ToggleEnabled(ctx context.Context, indexerID int, enabled
 
 	return nil
 }
+
+// marshalIndexerIdentity returns the identity as json, an empty identity is stored as null
+func marshalIndexerIdentity(identity *domain.IndexerIdentity) (sql.Null[string], error) {
+	if !identity.IsSet() {
+		return sql.Null[string]{}, nil
+	}
+
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return sql.Null[string]{}, errors.Wrap(err, "error marshaling identity")
+	}
+
+	return sql.Null[string]{V: string(data), Valid: true}, nil
+}
+
+func unmarshalIndexerIdentity(data sql.Null[string]) (*domain.IndexerIdentity, error) {
+	if !data.Valid || data.V == "" {
+		return nil, nil
+	}
+
+	var identity domain.IndexerIdentity
+	if err := json.Unmarshal([]byte(data.V), &identity); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal identity")
+	}
+
+	return &identity, nil
+}
@@ -49,6 +49,35 @@ func TestIndexerRepo_Store(t *testing.T) {
 			_ = repo.Delete(context.Background(), int(createdIndexer.ID))
 		})
 
+		t.Run(fmt.Sprintf("Store_With_Identity [%s]", dbType), func(t *testing.T) {
+			// Setup
+			data := getMockIndexer()
+			data.Identity = &domain.IndexerIdentity{
+				UserAgent: "qBittorrent/4.6.5",
+				Headers:   map[string]string{"X-Client": "qbt"},
+				Cookie:    "uid=1",
+			}
+
+			createdIndexer, err := repo.Store(context.Background(), data)
+			assert.NoError(t, err)
+
+			// Verify
+			indexer, err := repo.FindByID(context.Background(), int(createdIndexer.ID))
+			assert.NoError(t, err)
+			assert.Equal(t, data.Identity, indexer.Identity)
+
+			// an empty identity is removed
+			indexer.Identity = &domain.IndexerIdentity{}
+			_, err = repo.Update(context.Background(), *indexer)
+			assert.NoError(t, err)
+
+			indexer, err = repo.FindByID(context.Background(), int(createdIndexer.ID))
+			assert.NoError(t, err)
+			assert.Nil(t, indexer.Identity)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), int(createdIndexer.ID))
+		})
 	}
 }
 
@@ -30,7 +30,7 @@ func NewIrcRepo(log logger.Logger, db *DB) domain.IrcRepo {
 
 func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id", "status_command", "status_nicks").
 		From("irc_network").
 		Where(sq.Eq{"id": id})
 
@@ -42,13 +42,13 @@ func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetw
 
 	var n domain.IrcNetwork
 
-	var pass, nick, inviteCmd, bouncerAddr sql.Null[string]
+	var pass, nick, inviteCmd, bouncerAddr, statusCmd, statusNicks sql.Null[string]
 	var account, password sql.Null[string]
 	var tls sql.Null[bool]
 	var proxyId sql.Null[int64]
 
 	row := r.db.handler.QueryRowContext(ctx, query, args...)
-	if err := row.Scan(&n.ID, &n.Enabled, &n.Name, &n.Server, &n.Port, &tls, &pass, &nick, &n.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &n.UseBouncer, &n.BotMode, &n.UseProxy, &proxyId); err != nil {
+	if err := row.Scan(&n.ID, &n.Enabled, &n.Name, &n.Server, &n.Port, &tls, &pass, &nick, &n.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &n.UseBouncer, &n.BotMode, &n.UseProxy, &proxyId, &statusCmd, &statusNicks); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -59,6 +59,8 @@ func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetw
 	n.Pass = pass.V
 	n.Nick = nick.V
 	n.InviteCommand = inviteCmd.V
+	n.StatusCommand = statusCmd.V
+	n.StatusNicks = statusNicks.V
 	n.BouncerAddr = bouncerAddr.V
 	n.Auth.Account = account.V
 	n.Auth.Password = password.V
@@ -112,7 +114,7 @@ func (r *IrcRepo) DeleteNetwork(ctx context.Context, id int64) error {
 
 func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id", "status_command", "status_nicks").
 		From("irc_network").
 		Where(sq.Eq{"enabled": true})
 
@@ -132,12 +134,12 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 	for rows.Next() {
 		var net domain.IrcNetwork
 
-		var pass, nick, inviteCmd, bouncerAddr sql.Null[string]
+		var pass, nick, inviteCmd, bouncerAddr, statusCmd, statusNicks sql.Null[string]
 		var account, password sql.Null[string]
 		var tls sql.Null[bool]
 		var proxyId sql.Null[int64]
 
-		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &net.BotMode, &net.UseProxy, &proxyId); err != nil {
+		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &net.BotMode, &net.UseProxy, &proxyId, &statusCmd, &statusNicks); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -145,6 +147,8 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 		net.Pass = pass.V
 		net.Nick = nick.V
 		net.InviteCommand = inviteCmd.V
+		net.StatusCommand = statusCmd.V
+		net.StatusNicks = statusNicks.V
 		net.BouncerAddr = bouncerAddr.V
 		net.Auth.Account = account.V
 		net.Auth.Password = password.V
@@ -162,7 +166,7 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 
 func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id", "status_command", "status_nicks").
 		From("irc_network").
 		OrderBy("name ASC")
 
@@ -182,12 +186,12 @@ func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 	for rows.Next() {
 		var net domain.IrcNetwork
 
-		var pass, nick, inviteCmd, bouncerAddr sql.Null[string]
+		var pass, nick, inviteCmd, bouncerAddr, statusCmd, statusNicks sql.Null[string]
 		var account, password sql.Null[string]
 		var tls sql.Null[bool]
 		var proxyId sql.Null[int64]
 
-		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &net.BotMode, &net.UseProxy, &proxyId); err != nil {
+		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &net.BotMode, &net.UseProxy, &proxyId, &statusCmd, &statusNicks); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -195,6 +199,8 @@ func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 		net.Pass = pass.V
 		net.Nick = nick.V
 		net.InviteCommand = inviteCmd.V
+		net.StatusCommand = statusCmd.V
+		net.StatusNicks = statusNicks.V
 		net.BouncerAddr = bouncerAddr.V
 		net.Auth.Account = account.V
 		net.Auth.Password = password.V
@@ -249,7 +255,7 @@ func (r *IrcRepo) ListChannels(networkID int64) ([]domain.IrcChannel, error) {
 
 func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcNetwork) (*domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "bot_mode", "use_proxy", "proxy_id", "status_command", "status_nicks").
 		From("irc_network").
 		Where(sq.Eq{"server": network.Server}).
 		Where(sq.Eq{"port": network.Port}).
@@ -268,12 +274,12 @@ func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcN
 
 	var net domain.IrcNetwork
 
-	var pass, nick, inviteCmd, bouncerAddr sql.Null[string]
+	var pass, nick, inviteCmd, bouncerAddr, statusCmd, statusNicks sql.Null[string]
 	var account, password sql.Null[string]
 	var tls sql.Null[bool]
 	var proxyId sql.Null[int64]
 
-	if err = row.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &net.BotMode, &net.UseProxy, &proxyId); err != nil {
+	if err = row.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &net.BotMode, &net.UseProxy, &proxyId, &statusCmd, &statusNicks); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// no result is not an error in our case
 			return nil, nil
@@ -286,6 +292,8 @@ func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcN
 	net.Pass = pass.V
 	net.Nick = nick.V
 	net.InviteCommand = inviteCmd.V
+	net.StatusCommand = statusCmd.V
+	net.StatusNicks = statusNicks.V
 	net.BouncerAddr = bouncerAddr.V
 	net.Auth.Account = account.V
 	net.Auth.Password = password.V
@@ -313,6 +321,8 @@ func (r *IrcRepo) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 			"bouncer_addr",
 			"use_bouncer",
 			"bot_mode",
+			"status_command",
+			"status_nicks",
 		).
 		Values(
 			network.Enabled,
@@ -329,6 +339,8 @@ func (r *IrcRepo) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 			toNullString(network.BouncerAddr),
 			network.UseBouncer,
 			network.BotMode,
+			toNullString(network.StatusCommand),
+			toNullString(network.StatusNicks),
 		).
 		Suffix("RETURNING id").
 		RunWith(r.db.handler)
@@ -359,6 +371,8 @@ func (r *IrcRepo) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork)
 		Set("bot_mode", network.BotMode).
 		Set("use_proxy", network.UseProxy).
 		Set("proxy_id", toNullInt64(network.ProxyId)).
+		Set("status_command", toNullString(network.StatusCommand)).
+		Set("status_nicks", toNullString(network.StatusNicks)).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where(sq.Eq{"id": network.ID})
 
@@ -0,0 +1,393 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+type ListRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewListRepo(log logger.Logger, db *DB) domain.ListRepo {
+	return &ListRepo{
+		log: log.With().Str("repo", "list").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ListRepo) selectQuery() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"type",
+			"enabled",
+			"url",
+			"api_key",
+			"client_id",
+			"tags_include",
+			"tags_exclude",
+			"filter_field",
+			"refresh_interval",
+			"last_refresh_time",
+			"last_refresh_status",
+			"last_refresh_error",
+			"created_at",
+			"updated_at",
+		).
+		From("list")
+}
+
+func scanList(row interface{ Scan(dest ...any) error }) (*domain.List, error) {
+	var list domain.List
+
+	var listURL, apiKey, lastRefreshStatus, lastRefreshError sql.NullString
+	var lastRefreshTime sql.NullTime
+
+	if err := row.Scan(&list.ID, &list.Name, &list.Type, &list.Enabled, &listURL, &apiKey, &list.ClientID, pq.Array(&list.TagsInclude), pq.Array(&list.TagsExclude), &list.FilterField, &list.RefreshInterval, &lastRefreshTime, &lastRefreshStatus, &lastRefreshError, &list.CreatedAt, &list.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	list.URL = listURL.String
+	list.APIKey = apiKey.String
+	list.LastRefreshTime = lastRefreshTime.Time
+	list.LastRefreshStatus = domain.ListRefreshStatus(lastRefreshStatus.String)
+	list.LastRefreshError = lastRefreshError.String
+	list.Filters = []domain.ListFilter{}
+
+	return &list, nil
+}
+
+func (r *ListRepo) List(ctx context.Context) ([]*domain.List, error) {
+	query, args, err := r.selectQuery().OrderBy("name ASC").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	lists := make([]*domain.List, 0)
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		lists = append(lists, list)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error row")
+	}
+
+	for _, list := range lists {
+		if list.Filters, err = r.findFilters(ctx, list.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return lists, nil
+}
+
+func (r *ListRepo) FindByID(ctx context.Context, id int64) (*domain.List, error) {
+	query, args, err := r.selectQuery().Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	list, err := scanList(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	if list.Filters, err = r.findFilters(ctx, list.ID); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// findFilters returns the filters the list is synced into
+func (r *ListRepo) findFilters(ctx context.Context, listID int64) ([]domain.ListFilter, error) {
+	queryBuilder := r.db.squirrel.
+		Select("f.id", "f.name").
+		From("list_filter lf").
+		Join("filter f ON f.id = lf.filter_id").
+		Where(sq.Eq{"lf.list_id": listID}).
+		OrderBy("f.name ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	filters := make([]domain.ListFilter, 0)
+	for rows.Next() {
+		var f domain.ListFilter
+		if err := rows.Scan(&f.ID, &f.Name); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		filters = append(filters, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error row")
+	}
+
+	return filters, nil
+}
+
+func (r *ListRepo) Store(ctx context.Context, list *domain.List) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	queryBuilder := r.db.squirrel.
+		Insert("list").
+		Columns(
+			"name",
+			"type",
+			"enabled",
+			"url",
+			"api_key",
+			"client_id",
+			"tags_include",
+			"tags_exclude",
+			"filter_field",
+			"refresh_interval",
+		).
+		Values(
+			list.Name,
+			list.Type,
+			list.Enabled,
+			toNullString(list.URL),
+			toNullString(list.APIKey),
+			list.ClientID,
+			pq.Array(list.TagsInclude),
+			pq.Array(list.TagsExclude),
+			list.FilterField,
+			list.RefreshInterval,
+		).
+		Suffix("RETURNING id").
+		RunWith(tx)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&list.ID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if err := r.storeFilters(ctx, tx, list.ID, list.Filters); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit storing list")
+	}
+
+	return nil
+}
+
+func (r *ListRepo) Update(ctx context.Context, list *domain.List) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	queryBuilder := r.db.squirrel.
+		Update("list").
+		Set("name", list.Name).
+		Set("type", list.Type).
+		Set("enabled", list.Enabled).
+		Set("url", toNullString(list.URL)).
+		Set("api_key", toNullString(list.APIKey)).
+		Set("client_id", list.ClientID).
+		Set("tags_include", pq.Array(list.TagsInclude)).
+		Set("tags_exclude", pq.Array(list.TagsExclude)).
+		Set("filter_field", list.FilterField).
+		Set("refresh_interval", list.RefreshInterval).
+		Set("updated_at", time.Now().Format(time.RFC3339)).
+		Where(sq.Eq{"id": list.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUpdateFailed
+	}
+
+	if err := r.storeFilters(ctx, tx, list.ID, list.Filters); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit updating list")
+	}
+
+	return nil
+}
+
+// storeFilters replaces the filters the list is synced into
+func (r *ListRepo) storeFilters(ctx context.Context, tx *Tx, listID int64, filters []domain.ListFilter) error {
+	deleteQuery, deleteArgs, err := r.db.squirrel.
+		Delete("list_filter").
+		Where(sq.Eq{"list_id": listID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if len(filters) == 0 {
+		return nil
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("list_filter").
+		Columns("list_id", "filter_id")
+
+	for _, f := range filters {
+		queryBuilder = queryBuilder.Values(listID, f.ID)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *ListRepo) UpdateLastRefresh(ctx context.Context, list *domain.List) error {
+	queryBuilder := r.db.squirrel.
+		Update("list").
+		Set("last_refresh_time", list.LastRefreshTime).
+		Set("last_refresh_status", list.LastRefreshStatus).
+		Set("last_refresh_error", toNullString(list.LastRefreshError)).
+		Where(sq.Eq{"id": list.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *ListRepo) ToggleEnabled(ctx context.Context, id int64, enabled bool) error {
+	queryBuilder := r.db.squirrel.
+		Update("list").
+		Set("enabled", enabled).
+		Set("updated_at", time.Now().Format(time.RFC3339)).
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUpdateFailed
+	}
+
+	return nil
+}
+
+func (r *ListRepo) Delete(ctx context.Context, id int64) error {
+	queryBuilder := r.db.squirrel.
+		Delete("list").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDeleteFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockList() *domain.List {
+	return &domain.List{
+		Name:            "Watchlist",
+		Type:            domain.ListTypeTrakt,
+		Enabled:         true,
+		URL:             "https://api.trakt.tv/users/me/watchlist/shows",
+		APIKey:          "client-id",
+		FilterField:     domain.ListFilterFieldShows,
+		RefreshInterval: 60,
+	}
+}
+
+func TestListRepo_Store(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewListRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_And_Update_Succeeds [%s]", dbType), func(t *testing.T) {
+			mockFilter := getMockFilter()
+			err := filterRepo.Store(context.Background(), mockFilter)
+			assert.NoError(t, err)
+
+			mockData := getMockList()
+			mockData.Filters = []domain.ListFilter{{ID: mockFilter.ID}}
+
+			err = repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+			assert.NotZero(t, mockData.ID)
+
+			list, err := repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, mockData.Name, list.Name)
+			assert.Equal(t, mockData.APIKey, list.APIKey)
+			assert.Equal(t, domain.ListFilterFieldShows, list.FilterField)
+			assert.Equal(t, []domain.ListFilter{{ID: mockFilter.ID, Name: mockFilter.Name}}, list.Filters)
+
+			mockData.Name = "Updated"
+			mockData.Filters = nil
+			mockData.TagsInclude = []string{"autobrr"}
+			err = repo.Update(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			mockData.LastRefreshTime = time.Now().UTC().Truncate(time.Second)
+			mockData.LastRefreshStatus = domain.ListRefreshStatusError
+			mockData.LastRefreshError = "unexpected status code: 500"
+			err = repo.UpdateLastRefresh(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			lists, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, lists, 1)
+			assert.Equal(t, "Updated", lists[0].Name)
+			assert.Empty(t, lists[0].Filters)
+			assert.Equal(t, []string{"autobrr"}, lists[0].TagsInclude)
+			assert.Empty(t, lists[0].TagsExclude)
+			assert.Equal(t, domain.ListRefreshStatusError, lists[0].LastRefreshStatus)
+			assert.Equal(t, "unexpected status code: 500", lists[0].LastRefreshError)
+			assert.WithinDuration(t, mockData.LastRefreshTime, lists[0].LastRefreshTime, time.Second)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+			_ = filterRepo.Delete(context.Background(), mockFilter.ID)
+		})
+
+		t.Run(fmt.Sprintf("Delete_Fails_No_Record [%s]", dbType), func(t *testing.T) {
+			err := repo.Delete(context.Background(), 9999)
+			assert.ErrorIs(t, err, domain.ErrDeleteFailed)
+		})
+
+		t.Run(fmt.Sprintf("FindByID_Fails_No_Record [%s]", dbType), func(t *testing.T) {
+			_, err := repo.FindByID(context.Background(), 9999)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+		})
+	}
+}
+
+func TestListRepo_ToggleEnabled(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewListRepo(log, db)
+		mockData := getMockList()
+
+		t.Run(fmt.Sprintf("ToggleEnabled_Succeeds [%s]", dbType), func(t *testing.T) {
+			err := repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			err = repo.ToggleEnabled(context.Background(), mockData.ID, false)
+			assert.NoError(t, err)
+
+			list, err := repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.False(t, list.Enabled)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type MaintenanceRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewMaintenanceRepo(log logger.Logger, db *DB) domain.MaintenanceRepo {
+	return &MaintenanceRepo{
+		log: log.With().Str("repo", "maintenance").Logger(),
+		db:  db,
+	}
+}
+
+type orphanCheck struct {
+	table       string
+	description string
+	condition   string
+}
+
+// orphanChecks are run in order, rows referencing orphans are removed before the orphans themselves
+var orphanChecks = []orphanCheck{
+	{
+		table:       "release_action_status",
+		description: "action statuses without release",
+		condition:   "NOT EXISTS (SELECT 1 FROM release r WHERE r.id = release_action_status.release_id)",
+	},
+	{
+		table:       "filter_indexer",
+		description: "indexer connections for deleted filters or indexers",
+		condition:   "NOT EXISTS (SELECT 1 FROM filter f WHERE f.id = filter_indexer.filter_id) OR NOT EXISTS (SELECT 1 FROM indexer i WHERE i.id = filter_indexer.indexer_id)",
+	},
+	{
+		table:       "filter_external",
+		description: "external filters for deleted filters",
+		condition:   "NOT EXISTS (SELECT 1 FROM filter f WHERE f.id = filter_external.filter_id)",
+	},
+	{
+		table:       "action",
+		description: "actions for deleted filters",
+		condition:   "filter_id IS NULL OR NOT EXISTS (SELECT 1 FROM filter f WHERE f.id = action.filter_id)",
+	},
+	{
+		table:       "action",
+		description: "actions referencing deleted download clients",
+		condition:   "client_id IS NOT NULL AND client_id > 0 AND NOT EXISTS (SELECT 1 FROM client c WHERE c.id = action.client_id)",
+	},
+	{
+		table:       "feed_cache",
+		description: "feed cache for deleted feeds",
+		condition:   "NOT EXISTS (SELECT 1 FROM feed f WHERE f.id = feed_cache.feed_id)",
+	},
+	{
+		table:       "irc_channel",
+		description: "irc channels for deleted networks",
+		condition:   "NOT EXISTS (SELECT 1 FROM irc_network n WHERE n.id = irc_channel.network_id)",
+	},
+	{
+		table:       "user_notification_preference",
+		description: "notification preferences of deleted users",
+		condition:   "NOT EXISTS (SELECT 1 FROM users u WHERE u.id = user_notification_preference.user_id)",
+	},
+}
+
+// CleanupOrphans finds rows referencing deleted parents and removes them unless dryRun is set
+func (r *MaintenanceRepo) CleanupOrphans(ctx context.Context, dryRun bool) ([]domain.OrphanCleanupResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	results := make([]domain.OrphanCleanupResult, 0, len(orphanChecks))
+
+	for _, check := range orphanChecks {
+		result := domain.OrphanCleanupResult{
+			Table:       check.table,
+			Description: check.description,
+		}
+
+		countQuery, countArgs, err := r.db.squirrel.
+			Select("COUNT(*)").
+			From(check.table).
+			Where(sq.Expr(check.condition)).
+			ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "error building query")
+		}
+
+		if err := tx.QueryRowContext(ctx, countQuery, countArgs...).Scan(&result.Count); err != nil {
+			return nil, errors.Wrap(err, "error executing query")
+		}
+
+		if result.Count > 0 && !dryRun {
+			if check.table == "action" {
+				// keep the release history but unlink it from the actions about to be removed
+				if err := r.unlinkActionStatuses(ctx, tx, check.condition); err != nil {
+					return nil, err
+				}
+			}
+
+			deleteQuery, deleteArgs, err := r.db.squirrel.
+				Delete(check.table).
+				Where(sq.Expr(check.condition)).
+				ToSql()
+			if err != nil {
+				return nil, errors.Wrap(err, "error building query")
+			}
+
+			if _, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+				return nil, errors.Wrap(err, "error executing query")
+			}
+
+			r.log.Debug().Msgf("removed %d orphaned rows from %s: %s", result.Count, check.table, check.description)
+		}
+
+		results = append(results, result)
+	}
+
+	if dryRun {
+		return results, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "error commit transaction")
+	}
+
+	return results, nil
+}
+
+func (r *MaintenanceRepo) unlinkActionStatuses(ctx context.Context, tx *Tx, actionCondition string) error {
+	query, args, err := r.db.squirrel.
+		Update("release_action_status").
+		Set("action_id", nil).
+		Where("action_id IN (SELECT id FROM action WHERE " + actionCondition + ")").
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// Backup writes a consistent copy of the database to dst, only sqlite is supported
+func (r *MaintenanceRepo) Backup(ctx context.Context, dst string) error {
+	if r.db.Driver != "sqlite" {
+		return errors.New("database backups are not supported for %s, use pg_dump instead", r.db.Driver)
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, "VACUUM INTO ?", dst); err != nil {
+		return errors.Wrap(err, "could not backup database")
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func insertTestAction(t *testing.T, db *DB, name string, filterID *int) int {
+	t.Helper()
+
+	var id int
+	err := db.squirrel.
+		Insert("action").
+		Columns("name", "type", "enabled", "filter_id").
+		Values(name, domain.ActionTypeTest, true, filterID).
+		Suffix("RETURNING id").
+		RunWith(db.handler).
+		QueryRow().
+		Scan(&id)
+	assert.NoError(t, err)
+
+	return id
+}
+
+func actionExists(t *testing.T, db *DB, id int) bool {
+	t.Helper()
+
+	var count int
+	err := db.squirrel.
+		Select("COUNT(*)").
+		From("action").
+		Where(sq.Eq{"id": id}).
+		RunWith(db.handler).
+		QueryRow().
+		Scan(&count)
+	assert.NoError(t, err)
+
+	return count > 0
+}
+
+func orphanCount(results []domain.OrphanCleanupResult, description string) int64 {
+	for _, result := range results {
+		if result.Description == description {
+			return result.Count
+		}
+	}
+	return 0
+}
+
+func TestMaintenanceRepo_CleanupOrphans(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewMaintenanceRepo(log, db)
+
+		t.Run(fmt.Sprintf("CleanupOrphans_DryRun [%s]", dbType), func(t *testing.T) {
+			results, err := repo.CleanupOrphans(context.Background(), true)
+			assert.NoError(t, err)
+			assert.Len(t, results, len(orphanChecks))
+
+			for _, result := range results {
+				assert.NotEmpty(t, result.Table)
+				assert.GreaterOrEqual(t, result.Count, int64(0))
+			}
+		})
+
+		t.Run(fmt.Sprintf("CleanupOrphans_RemovesOrphans [%s]", dbType), func(t *testing.T) {
+			filterRepo := NewFilterRepo(log, db)
+
+			filter := getMockFilter()
+			err := filterRepo.Store(context.Background(), filter)
+			assert.NoError(t, err)
+
+			keptID := insertTestAction(t, db, "kept", &filter.ID)
+			orphanID := insertTestAction(t, db, "orphan", nil)
+
+			// dry run only reports the orphan
+			results, err := repo.CleanupOrphans(context.Background(), true)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, orphanCount(results, "actions for deleted filters"), int64(1))
+			assert.True(t, actionExists(t, db, orphanID))
+
+			results, err = repo.CleanupOrphans(context.Background(), false)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, orphanCount(results, "actions for deleted filters"), int64(1))
+
+			assert.False(t, actionExists(t, db, orphanID))
+			assert.True(t, actionExists(t, db, keptID))
+
+			// Cleanup
+			_, _ = db.squirrel.Delete("action").Where(sq.Eq{"id": keptID}).RunWith(db.handler).Exec()
+			_ = filterRepo.Delete(context.Background(), filter.ID)
+		})
+
+		t.Run(fmt.Sprintf("CleanupOrphans_Succeeds [%s]", dbType), func(t *testing.T) {
+			_, err := repo.CleanupOrphans(context.Background(), false)
+			assert.NoError(t, err)
+
+			// nothing left after cleanup
+			results, err := repo.CleanupOrphans(context.Background(), true)
+			assert.NoError(t, err)
+
+			for _, result := range results {
+				assert.Equal(t, int64(0), result.Count, "%s: %s", result.Table, result.Description)
+			}
+		})
+	}
+}
+
+func TestMaintenanceRepo_Backup(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewMaintenanceRepo(log, db)
+
+		t.Run(fmt.Sprintf("Backup [%s]", dbType), func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "autobrr.db")
+
+			err := repo.Backup(context.Background(), dst)
+			if dbType != "sqlite" {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			info, err := os.Stat(dst)
+			assert.NoError(t, err)
+			assert.Greater(t, info.Size(), int64(0))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -30,7 +31,7 @@ func NewNotificationRepo(log logger.Logger, db *DB) domain.NotificationRepo {
 
 func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQueryParams) ([]domain.Notification, int, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "name", "type", "enabled", "events", "webhook", "token", "api_key", "channel", "priority", "topic", "host", "username", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
+		Select("id", "name", "type", "enabled", "events", "webhook", "token", "api_key", "channel", "priority", "topic", "host", "username", "headers", "template", "secret", "message_templates", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "quiet_hours_timezone", "quiet_hours_mode", "quiet_hours_allow_errors", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
 		From("notification").
 		OrderBy("name")
 
@@ -51,12 +52,17 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 	for rows.Next() {
 		var n domain.Notification
 
-		var webhook, token, apiKey, channel, host, topic, username sql.NullString
+		var webhook, token, apiKey, channel, host, topic, username, headers, tmpl, secret, messageTemplates, quietHoursStart, quietHoursEnd, quietHoursTimezone, quietHoursMode sql.NullString
 
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &webhook, &token, &apiKey, &channel, &n.Priority, &topic, &host, &username, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &webhook, &token, &apiKey, &channel, &n.Priority, &topic, &host, &username, &headers, &tmpl, &secret, &messageTemplates, &n.QuietHoursEnabled, &quietHoursStart, &quietHoursEnd, &quietHoursTimezone, &quietHoursMode, &n.QuietHoursAllowErrors, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
 			return nil, 0, errors.Wrap(err, "error scanning row")
 		}
 
+		var err error
+		if n.MessageTemplates, err = unmarshalMessageTemplates(messageTemplates); err != nil {
+			return nil, 0, errors.Wrap(err, "error unmarshal message templates")
+		}
+
 		n.APIKey = apiKey.String
 		n.Webhook = webhook.String
 		n.Token = token.String
@@ -64,6 +70,13 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 		n.Topic = topic.String
 		n.Host = host.String
 		n.Username = username.String
+		n.Headers = headers.String
+		n.Template = tmpl.String
+		n.Secret = secret.String
+		n.QuietHoursStart = quietHoursStart.String
+		n.QuietHoursEnd = quietHoursEnd.String
+		n.QuietHoursTimezone = quietHoursTimezone.String
+		n.QuietHoursMode = domain.QuietHoursMode(quietHoursMode.String)
 
 		notifications = append(notifications, n)
 	}
@@ -75,7 +88,7 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 }
 
 func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, error) {
-	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, token, api_key,  webhook, title, icon, host, username, password, channel, targets, devices, priority, topic, created_at, updated_at FROM notification ORDER BY name ASC")
+	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, token, api_key,  webhook, title, icon, host, username, password, channel, targets, devices, priority, topic, headers, template, secret, message_templates, quiet_hours_enabled, quiet_hours_start, quiet_hours_end, quiet_hours_timezone, quiet_hours_mode, quiet_hours_allow_errors, created_at, updated_at FROM notification ORDER BY name ASC")
 	if err != nil {
 		return nil, errors.Wrap(err, "error executing query")
 	}
@@ -87,11 +100,16 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		var n domain.Notification
 		//var eventsSlice []string
 
-		var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic sql.NullString
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic, headers, tmpl, secret, messageTemplates, quietHoursStart, quietHoursEnd, quietHoursTimezone, quietHoursMode sql.NullString
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &headers, &tmpl, &secret, &messageTemplates, &n.QuietHoursEnabled, &quietHoursStart, &quietHoursEnd, &quietHoursTimezone, &quietHoursMode, &n.QuietHoursAllowErrors, &n.CreatedAt, &n.UpdatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		var err error
+		if n.MessageTemplates, err = unmarshalMessageTemplates(messageTemplates); err != nil {
+			return nil, errors.Wrap(err, "error unmarshal message templates")
+		}
+
 		//n.Events = ([]domain.NotificationEvent)(eventsSlice)
 		n.Token = token.String
 		n.APIKey = apiKey.String
@@ -105,6 +123,13 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		n.Targets = targets.String
 		n.Devices = devices.String
 		n.Topic = topic.String
+		n.Headers = headers.String
+		n.Template = tmpl.String
+		n.Secret = secret.String
+		n.QuietHoursStart = quietHoursStart.String
+		n.QuietHoursEnd = quietHoursEnd.String
+		n.QuietHoursTimezone = quietHoursTimezone.String
+		n.QuietHoursMode = domain.QuietHoursMode(quietHoursMode.String)
 
 		notifications = append(notifications, n)
 	}
@@ -136,6 +161,16 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 			"devices",
 			"priority",
 			"topic",
+			"headers",
+			"template",
+			"secret",
+			"message_templates",
+			"quiet_hours_enabled",
+			"quiet_hours_start",
+			"quiet_hours_end",
+			"quiet_hours_timezone",
+			"quiet_hours_mode",
+			"quiet_hours_allow_errors",
 			"created_at",
 			"updated_at",
 		).
@@ -154,8 +189,8 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 
 	var n domain.Notification
 
-	var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic sql.NullString
-	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &n.CreatedAt, &n.UpdatedAt); err != nil {
+	var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic, headers, tmpl, secret, messageTemplates, quietHoursStart, quietHoursEnd, quietHoursTimezone, quietHoursMode sql.NullString
+	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &headers, &tmpl, &secret, &messageTemplates, &n.QuietHoursEnabled, &quietHoursStart, &quietHoursEnd, &quietHoursTimezone, &quietHoursMode, &n.QuietHoursAllowErrors, &n.CreatedAt, &n.UpdatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -163,6 +198,10 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
+	if n.MessageTemplates, err = unmarshalMessageTemplates(messageTemplates); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal message templates")
+	}
+
 	n.Token = token.String
 	n.APIKey = apiKey.String
 	n.Webhook = webhook.String
@@ -175,6 +214,13 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 	n.Targets = targets.String
 	n.Devices = devices.String
 	n.Topic = topic.String
+	n.Headers = headers.String
+	n.Template = tmpl.String
+	n.Secret = secret.String
+	n.QuietHoursStart = quietHoursStart.String
+	n.QuietHoursEnd = quietHoursEnd.String
+	n.QuietHoursTimezone = quietHoursTimezone.String
+	n.QuietHoursMode = domain.QuietHoursMode(quietHoursMode.String)
 
 	return &n, nil
 }
@@ -188,6 +234,11 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 	host := toNullString(notification.Host)
 	username := toNullString(notification.Username)
 
+	messageTemplates, err := marshalMessageTemplates(notification.MessageTemplates)
+	if err != nil {
+		return nil, err
+	}
+
 	queryBuilder := r.db.squirrel.
 		Insert("notification").
 		Columns(
@@ -203,6 +254,16 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 			"topic",
 			"host",
 			"username",
+			"headers",
+			"template",
+			"secret",
+			"message_templates",
+			"quiet_hours_enabled",
+			"quiet_hours_start",
+			"quiet_hours_end",
+			"quiet_hours_timezone",
+			"quiet_hours_mode",
+			"quiet_hours_allow_errors",
 		).
 		Values(
 			notification.Name,
@@ -217,6 +278,16 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 			topic,
 			host,
 			username,
+			toNullString(notification.Headers),
+			toNullString(notification.Template),
+			toNullString(notification.Secret),
+			messageTemplates,
+			notification.QuietHoursEnabled,
+			toNullString(notification.QuietHoursStart),
+			toNullString(notification.QuietHoursEnd),
+			toNullString(notification.QuietHoursTimezone),
+			toNullString(string(notification.QuietHoursMode)),
+			notification.QuietHoursAllowErrors,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -242,6 +313,11 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 	host := toNullString(notification.Host)
 	username := toNullString(notification.Username)
 
+	messageTemplates, err := marshalMessageTemplates(notification.MessageTemplates)
+	if err != nil {
+		return nil, err
+	}
+
 	queryBuilder := r.db.squirrel.
 		Update("notification").
 		Set("name", notification.Name).
@@ -256,6 +332,16 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 		Set("topic", topic).
 		Set("host", host).
 		Set("username", username).
+		Set("headers", toNullString(notification.Headers)).
+		Set("template", toNullString(notification.Template)).
+		Set("secret", toNullString(notification.Secret)).
+		Set("message_templates", messageTemplates).
+		Set("quiet_hours_enabled", notification.QuietHoursEnabled).
+		Set("quiet_hours_start", toNullString(notification.QuietHoursStart)).
+		Set("quiet_hours_end", toNullString(notification.QuietHoursEnd)).
+		Set("quiet_hours_timezone", toNullString(notification.QuietHoursTimezone)).
+		Set("quiet_hours_mode", toNullString(string(notification.QuietHoursMode))).
+		Set("quiet_hours_allow_errors", notification.QuietHoursAllowErrors).
 		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
 		Where(sq.Eq{"id": notification.ID})
 
@@ -291,3 +377,37 @@ func (r *NotificationRepo) Delete(ctx context.Context, notificationID int) error
 
 	return nil
 }
+
+// marshalMessageTemplates returns the message templates which are set as json, null when there are none
+func marshalMessageTemplates(templates map[domain.NotificationEvent]domain.NotificationMessageTemplate) (sql.Null[string], error) {
+	set := make(map[domain.NotificationEvent]domain.NotificationMessageTemplate, len(templates))
+	for event, t := range templates {
+		if t.IsSet() {
+			set[event] = t
+		}
+	}
+
+	if len(set) == 0 {
+		return sql.Null[string]{}, nil
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		return sql.Null[string]{}, errors.Wrap(err, "error marshaling message templates")
+	}
+
+	return sql.Null[string]{V: string(data), Valid: true}, nil
+}
+
+func unmarshalMessageTemplates(data sql.NullString) (map[domain.NotificationEvent]domain.NotificationMessageTemplate, error) {
+	if !data.Valid || data.String == "" {
+		return nil, nil
+	}
+
+	var templates map[domain.NotificationEvent]domain.NotificationMessageTemplate
+	if err := json.Unmarshal([]byte(data.String), &templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type NotificationDeliveryRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewNotificationDeliveryRepo(log logger.Logger, db *DB) domain.NotificationDeliveryRepo {
+	return &NotificationDeliveryRepo{
+		log: log.With().Str("repo", "notification_delivery").Logger(),
+		db:  db,
+	}
+}
+
+func (r *NotificationDeliveryRepo) selectQuery() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"d.id",
+			"d.notification_id",
+			"n.name",
+			"d.event",
+			"d.payload",
+			"d.status",
+			"d.attempts",
+			"d.last_error",
+			"d.next_attempt_at",
+			"d.created_at",
+			"d.updated_at",
+		).
+		From("notification_delivery d").
+		Join("notification n ON n.id = d.notification_id")
+}
+
+func scanNotificationDelivery(row interface{ Scan(dest ...any) error }) (*domain.NotificationDelivery, error) {
+	var d domain.NotificationDelivery
+
+	var name, lastError sql.NullString
+	var payload string
+
+	if err := row.Scan(&d.ID, &d.NotificationID, &name, &d.Event, &payload, &d.Status, &d.Attempts, &lastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(payload), &d.Payload); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal payload of notification delivery: %d", d.ID)
+	}
+
+	d.NotificationName = name.String
+	d.LastError = lastError.String
+
+	return &d, nil
+}
+
+func (r *NotificationDeliveryRepo) list(ctx context.Context, queryBuilder sq.SelectBuilder) ([]domain.NotificationDelivery, error) {
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	deliveries := make([]domain.NotificationDelivery, 0)
+	for rows.Next() {
+		d, err := scanNotificationDelivery(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		deliveries = append(deliveries, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows list")
+	}
+
+	return deliveries, nil
+}
+
+func (r *NotificationDeliveryRepo) Find(ctx context.Context, params domain.NotificationDeliveryQueryParams) ([]domain.NotificationDelivery, error) {
+	queryBuilder := r.selectQuery().OrderBy("d.created_at DESC")
+
+	if params.Status != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"d.status": params.Status})
+	}
+
+	if params.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(params.Limit)
+	}
+
+	return r.list(ctx, queryBuilder)
+}
+
+// FindDue returns the deliveries with the status due for their next attempt, oldest first
+func (r *NotificationDeliveryRepo) FindDue(ctx context.Context, status domain.NotificationDeliveryStatus, now time.Time, limit uint64) ([]domain.NotificationDelivery, error) {
+	queryBuilder := r.selectQuery().
+		Where(sq.Eq{"d.status": status}).
+		Where(sq.LtOrEq{"d.next_attempt_at": now.UTC()}).
+		OrderBy("d.next_attempt_at ASC").
+		Limit(limit)
+
+	return r.list(ctx, queryBuilder)
+}
+
+func (r *NotificationDeliveryRepo) FindByID(ctx context.Context, id int64) (*domain.NotificationDelivery, error) {
+	query, args, err := r.selectQuery().Where(sq.Eq{"d.id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	d, err := scanNotificationDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	return d, nil
+}
+
+func (r *NotificationDeliveryRepo) Store(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	payload, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal payload")
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("notification_delivery").
+		Columns("notification_id", "event", "payload", "status", "attempts", "last_error", "next_attempt_at").
+		Values(delivery.NotificationID, delivery.Event, string(payload), delivery.Status, delivery.Attempts, toNullString(delivery.LastError), delivery.NextAttemptAt.UTC()).
+		Suffix("RETURNING id").
+		RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&delivery.ID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// Update stores the status and attempts of the delivery
+func (r *NotificationDeliveryRepo) Update(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	queryBuilder := r.db.squirrel.
+		Update("notification_delivery").
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("last_error", toNullString(delivery.LastError)).
+		Set("next_attempt_at", delivery.NextAttemptAt.UTC()).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": delivery.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUpdateFailed
+	}
+
+	return nil
+}
+
+func (r *NotificationDeliveryRepo) Delete(ctx context.Context, id int64) error {
+	queryBuilder := r.db.squirrel.
+		Delete("notification_delivery").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDeleteFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDeliveryRepo(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		repo := NewNotificationDeliveryRepo(log, db)
+		notificationRepo := NewNotificationRepo(log, db)
+
+		t.Run(fmt.Sprintf("Queue_Succeeds [%s]", dbType), func(t *testing.T) {
+			notification, err := notificationRepo.Store(context.Background(), getMockNotification())
+			require.NoError(t, err)
+
+			now := time.Now()
+
+			due := &domain.NotificationDelivery{
+				NotificationID: notification.ID,
+				Event:          domain.NotificationEventPushApproved,
+				Payload: domain.NotificationPayload{
+					ReleaseName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+					Size:        1500,
+					Rejections:  []string{"a", "b"},
+				},
+			}
+			due.AttemptFailed(errors.New("unexpected status: 503"), now.Add(-2*time.Minute))
+			require.NoError(t, repo.Store(context.Background(), due))
+
+			later := &domain.NotificationDelivery{NotificationID: notification.ID, Event: domain.NotificationEventPushError}
+			later.AttemptFailed(errors.New("timeout"), now)
+			require.NoError(t, repo.Store(context.Background(), later))
+
+			deferred := &domain.NotificationDelivery{
+				NotificationID: notification.ID,
+				Event:          domain.NotificationEventPushRejected,
+				Status:         domain.NotificationDeliveryStatusDeferred,
+				NextAttemptAt:  now.Add(-time.Minute),
+			}
+			require.NoError(t, repo.Store(context.Background(), deferred))
+
+			// deferred deliveries are due for the digest
+			digest, err := repo.FindDue(context.Background(), domain.NotificationDeliveryStatusDeferred, now, 10)
+			require.NoError(t, err)
+			require.Len(t, digest, 1)
+			assert.Equal(t, deferred.ID, digest[0].ID)
+
+			require.NoError(t, repo.Delete(context.Background(), deferred.ID))
+
+			// only the delivery past its next attempt is due
+			deliveries, err := repo.FindDue(context.Background(), domain.NotificationDeliveryStatusPending, now, 10)
+			require.NoError(t, err)
+			require.Len(t, deliveries, 1)
+			assert.Equal(t, due.ID, deliveries[0].ID)
+			assert.Equal(t, notification.Name, deliveries[0].NotificationName)
+			assert.Equal(t, due.Payload, deliveries[0].Payload)
+			assert.Equal(t, "unexpected status: 503", deliveries[0].LastError)
+
+			// run out of attempts
+			for due.Status != domain.NotificationDeliveryStatusFailed {
+				due.AttemptFailed(errors.New("unexpected status: 503"), now)
+			}
+			require.NoError(t, repo.Update(context.Background(), due))
+
+			failed, err := repo.Find(context.Background(), domain.NotificationDeliveryQueryParams{Status: domain.NotificationDeliveryStatusFailed})
+			require.NoError(t, err)
+			require.Len(t, failed, 1)
+			assert.Equal(t, domain.NotificationDeliveryMaxAttempts, failed[0].Attempts)
+
+			all, err := repo.Find(context.Background(), domain.NotificationDeliveryQueryParams{})
+			require.NoError(t, err)
+			assert.Len(t, all, 2)
+
+			require.NoError(t, repo.Delete(context.Background(), due.ID))
+
+			_, err = repo.FindByID(context.Background(), due.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			// deleting the notification agent removes its deliveries
+			require.NoError(t, notificationRepo.Delete(context.Background(), notification.ID))
+
+			_, err = repo.FindByID(context.Background(), later.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+		})
+	}
+}
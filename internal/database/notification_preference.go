@@ -0,0 +1,148 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+type NotificationPreferenceRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewNotificationPreferenceRepo(log logger.Logger, db *DB) domain.NotificationPreferenceRepo {
+	return &NotificationPreferenceRepo{
+		log: log.With().Str("repo", "notification_preference").Logger(),
+		db:  db,
+	}
+}
+
+func (r *NotificationPreferenceRepo) List(ctx context.Context) ([]domain.UserNotificationPreference, error) {
+	queryBuilder := r.db.squirrel.
+		Select("user_id", "events", "notifications", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "updated_at").
+		From("user_notification_preference")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	prefs := make([]domain.UserNotificationPreference, 0)
+	for rows.Next() {
+		p, err := scanNotificationPreference(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		prefs = append(prefs, *p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows list")
+	}
+
+	return prefs, nil
+}
+
+func (r *NotificationPreferenceRepo) FindByUserID(ctx context.Context, userID int) (*domain.UserNotificationPreference, error) {
+	queryBuilder := r.db.squirrel.
+		Select("user_id", "events", "notifications", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "updated_at").
+		From("user_notification_preference").
+		Where(sq.Eq{"user_id": userID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	p, err := scanNotificationPreference(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (r *NotificationPreferenceRepo) Store(ctx context.Context, pref *domain.UserNotificationPreference) error {
+	notifications := make(pq.Int64Array, 0, len(pref.Notifications))
+	for _, id := range pref.Notifications {
+		notifications = append(notifications, int64(id))
+	}
+
+	pref.UpdatedAt = time.Now()
+
+	queryBuilder := r.db.squirrel.
+		Insert("user_notification_preference").
+		Columns("user_id", "events", "notifications", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "updated_at").
+		Values(pref.UserID, pq.Array(pref.Events), notifications, pref.QuietHoursEnabled, pref.QuietHoursStart, pref.QuietHoursEnd, pref.UpdatedAt).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET events = EXCLUDED.events, notifications = EXCLUDED.notifications, quiet_hours_enabled = EXCLUDED.quiet_hours_enabled, quiet_hours_start = EXCLUDED.quiet_hours_start, quiet_hours_end = EXCLUDED.quiet_hours_end, updated_at = EXCLUDED.updated_at")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("notification_preference.store: user %d", pref.UserID)
+
+	return nil
+}
+
+type notificationPreferenceScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNotificationPreference(row notificationPreferenceScanner) (*domain.UserNotificationPreference, error) {
+	var p domain.UserNotificationPreference
+	var notifications pq.Int64Array
+	var quietHoursStart, quietHoursEnd sql.NullString
+
+	if err := row.Scan(&p.UserID, pq.Array(&p.Events), &notifications, &p.QuietHoursEnabled, &quietHoursStart, &quietHoursEnd, &p.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	p.Notifications = make([]int, 0, len(notifications))
+	for _, id := range notifications {
+		p.Notifications = append(p.Notifications, int(id))
+	}
+
+	p.QuietHoursStart = quietHoursStart.String
+	p.QuietHoursEnd = quietHoursEnd.String
+
+	return &p, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreferenceRepo_Store(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		repo := NewNotificationPreferenceRepo(log, db)
+		userRepo := NewUserRepo(log, db)
+
+		user := getMockUser()
+		err := userRepo.Store(context.Background(), domain.CreateUserRequest{
+			Username: user.Username,
+			Password: user.Password,
+		})
+		assert.NoError(t, err)
+
+		storedUser, err := userRepo.FindByUsername(context.Background(), user.Username)
+		assert.NoError(t, err)
+
+		t.Run(fmt.Sprintf("Store_Succeeds [%s]", dbType), func(t *testing.T) {
+			pref := &domain.UserNotificationPreference{
+				UserID:            storedUser.ID,
+				Events:            []string{string(domain.NotificationEventPushApproved)},
+				Notifications:     []int{1, 2},
+				QuietHoursEnabled: true,
+				QuietHoursStart:   "22:00",
+				QuietHoursEnd:     "07:00",
+			}
+
+			// Execute
+			err := repo.Store(context.Background(), pref)
+			assert.NoError(t, err)
+
+			// Update existing
+			pref.Notifications = []int{3}
+			err = repo.Store(context.Background(), pref)
+			assert.NoError(t, err)
+
+			// Verify
+			found, err := repo.FindByUserID(context.Background(), storedUser.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, []int{3}, found.Notifications)
+			assert.Equal(t, pref.Events, found.Events)
+			assert.Equal(t, "22:00", found.QuietHoursStart)
+
+			list, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, list, 1)
+		})
+
+		t.Run(fmt.Sprintf("FindByUserID_Fails_NotFound [%s]", dbType), func(t *testing.T) {
+			_, err := repo.FindByUserID(context.Background(), -1)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+		})
+
+		// Cleanup
+		_ = userRepo.Delete(context.Background(), user.Username)
+	}
+}
@@ -18,27 +18,39 @@ import (
 
 func getMockNotification() domain.Notification {
 	return domain.Notification{
-		ID:        1,
-		Name:      "MockNotification",
-		Type:      domain.NotificationTypeSlack,
-		Enabled:   true,
-		Events:    []string{"event1", "event2"},
-		Token:     "mock-token",
-		APIKey:    "mock-api-key",
-		Webhook:   "https://webhook.example.com",
-		Title:     "Mock Title",
-		Icon:      "https://icon.example.com",
-		Username:  "mock-username",
-		Host:      "https://host.example.com",
-		Password:  "mock-password",
-		Channel:   "#mock-channel",
-		Rooms:     "room1,room2",
-		Targets:   "target1,target2",
-		Devices:   "device1,device2",
-		Priority:  1,
-		Topic:     "mock-topic",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:       1,
+		Name:     "MockNotification",
+		Type:     domain.NotificationTypeSlack,
+		Enabled:  true,
+		Events:   []string{"event1", "event2"},
+		Token:    "mock-token",
+		APIKey:   "mock-api-key",
+		Webhook:  "https://webhook.example.com",
+		Title:    "Mock Title",
+		Icon:     "https://icon.example.com",
+		Username: "mock-username",
+		Host:     "https://host.example.com",
+		Password: "mock-password",
+		Channel:  "#mock-channel",
+		Rooms:    "room1,room2",
+		Targets:  "target1,target2",
+		Devices:  "device1,device2",
+		Priority: 1,
+		Topic:    "mock-topic",
+		Headers:  "X-Mock: value",
+		Template: `{"release": {{ toJson .ReleaseName }}}`,
+		Secret:   "mock-secret",
+		MessageTemplates: map[domain.NotificationEvent]domain.NotificationMessageTemplate{
+			domain.NotificationEventPushApproved: {Title: "Grabbed {{ .Title }}", Message: "{{ .TorrentName }} by {{ .FilterName }}"},
+		},
+		QuietHoursEnabled:     true,
+		QuietHoursStart:       "22:00",
+		QuietHoursEnd:         "07:00",
+		QuietHoursTimezone:    "Europe/Stockholm",
+		QuietHoursMode:        domain.QuietHoursModeDefer,
+		QuietHoursAllowErrors: true,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
 	}
 }
 
@@ -199,6 +211,14 @@ func TestNotificationRepo_FindByID(t *testing.T) {
 			assert.NotNil(t, notification)
 			assert.Equal(t, mockData.Name, notification.Name)
 			assert.Equal(t, mockData.Type, notification.Type)
+			assert.Equal(t, mockData.Headers, notification.Headers)
+			assert.Equal(t, mockData.Template, notification.Template)
+			assert.Equal(t, mockData.Secret, notification.Secret)
+			assert.Equal(t, mockData.MessageTemplates, notification.MessageTemplates)
+			assert.Equal(t, mockData.QuietHoursStart, notification.QuietHoursStart)
+			assert.Equal(t, mockData.QuietHoursTimezone, notification.QuietHoursTimezone)
+			assert.Equal(t, mockData.QuietHoursMode, notification.QuietHoursMode)
+			assert.True(t, notification.QuietHoursAllowErrors)
 
 			// Cleanup
 			_ = repo.Delete(context.Background(), mockData.ID)
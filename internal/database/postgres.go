@@ -15,7 +15,7 @@ func (db *DB) openPostgres() error {
 	var err error
 
 	// open database connection
-	if db.handler, err = sql.Open("postgres", db.DSN); err != nil {
+	if db.handler, err = openTimed("postgres", db.DSN); err != nil {
 		db.log.Fatal().Err(err).Msg("could not open postgres connection")
 		return errors.Wrap(err, "could not open postgres connection")
 	}
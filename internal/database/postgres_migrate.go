@@ -9,11 +9,17 @@ CREATE TABLE users
     id         SERIAL PRIMARY KEY,
     username   TEXT NOT NULL,
     password   TEXT NOT NULL,
+    role       TEXT DEFAULT 'admin' NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    oidc_issuer TEXT,
+    oidc_subject TEXT,
     UNIQUE (username)
 );
 
+CREATE UNIQUE INDEX users_oidc_issuer_subject_index
+    ON users (oidc_issuer, oidc_subject);
+
 CREATE TABLE proxy
 (
     id             SERIAL PRIMARY KEY,
@@ -38,6 +44,7 @@ CREATE TABLE indexer
     enabled             BOOLEAN,
     name                TEXT NOT NULL,
     settings            TEXT,
+    identity            TEXT,
     use_proxy           BOOLEAN DEFAULT FALSE,
     proxy_id            INTEGER,
     created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -70,6 +77,8 @@ CREATE TABLE irc_network
     connected_since     TIMESTAMP,
     use_proxy           BOOLEAN DEFAULT FALSE,
     proxy_id            INTEGER,
+    status_command      TEXT,
+    status_nicks        TEXT,
     created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (proxy_id) REFERENCES proxy(id) ON DELETE SET NULL,
@@ -88,6 +97,16 @@ CREATE TABLE irc_channel
     UNIQUE (network_id, name)
 );
 
+CREATE TABLE filter_group
+(
+    id         SERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    enabled    BOOLEAN DEFAULT TRUE,
+    priority   INTEGER DEFAULT 0 NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE filter
 (
     id                             SERIAL PRIMARY KEY,
@@ -114,6 +133,8 @@ CREATE TABLE filter
     freeleech                      BOOLEAN,
     freeleech_percent              TEXT,
     smart_episode                  BOOLEAN DEFAULT FALSE,
+    smart_music                    BOOLEAN DEFAULT FALSE,
+    smart_music_match              TEXT []   DEFAULT '{}',
     shows                          TEXT,
     seasons                        TEXT,
     episodes                       TEXT,
@@ -156,7 +177,34 @@ CREATE TABLE filter
     min_seeders                    INTEGER DEFAULT 0,
     max_seeders                    INTEGER DEFAULT 0,
     min_leechers                   INTEGER DEFAULT 0,
-    max_leechers                   INTEGER DEFAULT 0
+    max_leechers                   INTEGER DEFAULT 0,
+    breaker_threshold              INTEGER DEFAULT 0,
+    breaker_cooldown               INTEGER DEFAULT 0,
+    breaker_fail_open              BOOLEAN DEFAULT FALSE,
+    schedule                       TEXT,
+    advanced_expression            TEXT,
+    normalize_categories           BOOLEAN DEFAULT FALSE,
+    smart_duplicate_days           INTEGER DEFAULT 0,
+    deleted_at                     TIMESTAMP,
+    group_id                       INTEGER,
+    max_downloads_size             TEXT,
+    max_downloads_size_unit        TEXT,
+    skip_dedupe                    BOOLEAN DEFAULT FALSE,
+    max_files                      INTEGER DEFAULT 0,
+    match_file_extensions          TEXT,
+    except_file_extensions         TEXT,
+    sonarr_check_client_id         INTEGER DEFAULT 0,
+    radarr_check_client_id         INTEGER DEFAULT 0,
+    lidarr_check_client_id         INTEGER DEFAULT 0,
+    library_check_client_id        INTEGER DEFAULT 0,
+    match_ids                      TEXT,
+    match_musicbrainz              TEXT,
+    ignore_season_packs            BOOLEAN DEFAULT FALSE,
+    upgrade_replace                BOOLEAN DEFAULT FALSE,
+    group_tiers                    TEXT,
+    created_by                     TEXT,
+    updated_by                     TEXT,
+    FOREIGN KEY (group_id) REFERENCES filter_group(id) ON DELETE SET NULL
 );
 
 CREATE INDEX filter_enabled_index
@@ -165,6 +213,9 @@ CREATE INDEX filter_enabled_index
 CREATE INDEX filter_priority_index
     ON filter (priority);
 
+CREATE INDEX filter_group_id_index
+    ON filter (group_id);
+
 CREATE TABLE filter_external
 (
     id                                  SERIAL PRIMARY KEY,
@@ -183,6 +234,7 @@ CREATE TABLE filter_external
     webhook_retry_status                TEXT,
     webhook_retry_attempts              INTEGER,
     webhook_retry_delay_seconds         INTEGER,
+    webhook_timeout_seconds             INTEGER,
     filter_id                           INTEGER NOT NULL,
     FOREIGN KEY (filter_id)             REFERENCES filter(id) ON DELETE CASCADE
 );
@@ -228,21 +280,50 @@ CREATE TABLE action
     ignore_rules            BOOLEAN,
     first_last_piece_prio   BOOLEAN DEFAULT false,
     skip_hash_check         BOOLEAN DEFAULT false,
+    freeleech_token         BOOLEAN DEFAULT false,
+    freeleech_token_min_size TEXT DEFAULT '' NOT NULL,
     content_layout          TEXT,
     limit_upload_speed      INT,
     limit_download_speed    INT,
     limit_ratio             REAL,
     limit_seed_time         INT,
+    limit_inactive_seed_time INTEGER DEFAULT 0,
+    max_connections         INTEGER DEFAULT 0,
     priority			    TEXT,
     reannounce_skip         BOOLEAN DEFAULT false,
     reannounce_delete       BOOLEAN DEFAULT false,
     reannounce_interval     INTEGER DEFAULT 7,
     reannounce_max_attempts INTEGER DEFAULT 50,
+    reannounce_jitter       INTEGER DEFAULT 0,
+    require_approval        BOOLEAN DEFAULT false,
+    remove_ratio            REAL DEFAULT 0,
+    remove_seed_time        INTEGER DEFAULT 0,
+    remove_after_days       INTEGER DEFAULT 0,
+    remove_delete_data      BOOLEAN DEFAULT false,
+    validate_torrent        BOOLEAN DEFAULT false,
+    run_condition           TEXT,
+    dupe_key                TEXT,
+    cross_seed              TEXT,
+    completion_hooks        TEXT,
+    validate_announce       TEXT,
+    validate_private        TEXT,
+    client_pool_mode        TEXT,
+    move_completed_path     TEXT,
+    custom1                 TEXT,
+    custom2                 TEXT,
+    bandwidth_group         TEXT,
+    exec_timeout            INTEGER DEFAULT 0,
+    exec_env                TEXT []   DEFAULT '{}',
+    failover_clients        INTEGER [] DEFAULT '{}',
+    exec_dir                TEXT,
     webhook_host            TEXT,
     webhook_method          TEXT,
     webhook_type            TEXT,
     webhook_data            TEXT,
     webhook_headers         TEXT[] DEFAULT '{}',
+    webhook_secret          TEXT,
+    webhook_retry_attempts  INTEGER DEFAULT 0,
+    webhook_retry_delay_seconds INTEGER DEFAULT 0,
     external_client_id      INTEGER,
     external_client         TEXT,
     client_id               INTEGER,
@@ -281,6 +362,8 @@ CREATE TABLE "release"
     container         TEXT,
     hdr               TEXT,
     audio             TEXT,
+    audio_format      TEXT,
+    bitrate           TEXT,
     release_group     TEXT,
     region            TEXT,
     language          TEXT,
@@ -304,6 +387,9 @@ CREATE TABLE "release"
     freeleech_percent INTEGER,
     uploader          TEXT,
 	pre_time          TEXT,
+    artists           TEXT,
+    notes             TEXT,
+    user_tags         TEXT,
     filter_id         INTEGER
         CONSTRAINT release_filter_id_fk
             REFERENCES filter
@@ -322,6 +408,18 @@ CREATE INDEX release_timestamp_index
 CREATE INDEX release_torrent_name_index
     ON "release" (torrent_name);
 
+CREATE INDEX release_resolution_index
+    ON "release" (resolution);
+
+CREATE INDEX release_source_index
+    ON "release" (source);
+
+CREATE INDEX release_release_group_index
+    ON "release" (release_group);
+
+CREATE INDEX release_size_index
+    ON "release" (size);
+
 CREATE TABLE release_action_status
 (
 	id            SERIAL PRIMARY KEY,
@@ -336,6 +434,11 @@ CREATE TABLE release_action_status
 	timestamp     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	raw           TEXT,
 	log           TEXT,
+	latency_ms    INTEGER DEFAULT 0,
+	info_hash     TEXT,
+	reannounce    TEXT,
+	completed_at  TIMESTAMP,
+	removed_at    TIMESTAMP,
 	release_id    INTEGER NOT NULL,
 	FOREIGN KEY (action_id) REFERENCES "action"(id) ON DELETE SET NULL,
 	FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
@@ -345,6 +448,9 @@ CREATE TABLE release_action_status
 CREATE INDEX release_action_status_release_id_index
     ON release_action_status (release_id);
 
+CREATE INDEX release_action_status_client_index
+    ON release_action_status (client);
+
 CREATE TABLE notification
 (
 	id         SERIAL PRIMARY KEY,
@@ -366,6 +472,16 @@ CREATE TABLE notification
 	devices    TEXT,
 	topic      TEXT,
 	priority   INTEGER DEFAULT 0,
+	headers    TEXT,
+	template   TEXT,
+	secret     TEXT,
+	message_templates TEXT,
+	quiet_hours_enabled BOOLEAN DEFAULT FALSE,
+	quiet_hours_start TEXT,
+	quiet_hours_end TEXT,
+	quiet_hours_timezone TEXT,
+	quiet_hours_mode TEXT,
+	quiet_hours_allow_errors BOOLEAN DEFAULT FALSE,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -411,7 +527,161 @@ CREATE TABLE api_key
 	name       TEXT,
 	key        TEXT PRIMARY KEY,
 	scopes     TEXT []   DEFAULT '{}' NOT NULL,
-	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	expires_at TIMESTAMP,
+	user_id    INTEGER,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE announce_history
+(
+    id           SERIAL PRIMARY KEY,
+    indexer      TEXT NOT NULL,
+    source       TEXT NOT NULL,
+    torrent_name TEXT NOT NULL,
+    seen_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX announce_history_seen_at_index
+    ON announce_history (seen_at);
+
+CREATE TABLE user_notification_preference
+(
+    user_id             INTEGER PRIMARY KEY,
+    events              TEXT []   DEFAULT '{}' NOT NULL,
+    notifications       INTEGER [] DEFAULT '{}' NOT NULL,
+    quiet_hours_enabled BOOLEAN DEFAULT FALSE,
+    quiet_hours_start   TEXT,
+    quiet_hours_end     TEXT,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE notification_delivery
+(
+    id              SERIAL PRIMARY KEY,
+    notification_id INTEGER NOT NULL,
+    event           TEXT NOT NULL,
+    payload         TEXT NOT NULL,
+    status          TEXT NOT NULL,
+    attempts        INTEGER DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (notification_id) REFERENCES notification(id) ON DELETE CASCADE
+);
+
+CREATE INDEX notification_delivery_status_next_attempt_at_index
+    ON notification_delivery (status, next_attempt_at);
+
+CREATE TABLE audit_log
+(
+    id            SERIAL PRIMARY KEY,
+    action        TEXT NOT NULL,
+    resource      TEXT NOT NULL,
+    resource_id   TEXT,
+    resource_name TEXT,
+    description   TEXT,
+    username      TEXT,
+    api_key       TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_created_at_index
+    ON audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION notify_autobrr_change() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('autobrr_changes', json_build_object('table', TG_TABLE_NAME, 'source', current_setting('application_name'))::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER indexer_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON indexer
+    FOR EACH STATEMENT EXECUTE PROCEDURE notify_autobrr_change();
+
+CREATE TRIGGER client_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON client
+    FOR EACH STATEMENT EXECUTE PROCEDURE notify_autobrr_change();
+
+CREATE TABLE freeleech_token_budget
+(
+    indexer        TEXT PRIMARY KEY,
+    monthly_budget INTEGER DEFAULT 0 NOT NULL,
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE freeleech_token_usage
+(
+    id           SERIAL PRIMARY KEY,
+    indexer      TEXT NOT NULL,
+    torrent_name TEXT NOT NULL,
+    filter_id    INTEGER,
+    size         BIGINT DEFAULT 0,
+    used_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX freeleech_token_usage_indexer_used_at_index
+    ON freeleech_token_usage (indexer, used_at);
+
+CREATE TABLE release_retention
+(
+    id                   INTEGER PRIMARY KEY,
+    enabled              BOOLEAN DEFAULT FALSE,
+    max_age_days         INTEGER DEFAULT 0 NOT NULL,
+    max_rows_per_indexer INTEGER DEFAULT 0 NOT NULL,
+    schedule             TEXT DEFAULT '0 3 * * *' NOT NULL,
+    last_run             TIMESTAMP,
+    last_deleted         INTEGER DEFAULT 0 NOT NULL,
+    updated_at           TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE release_pending
+(
+    id         SERIAL PRIMARY KEY,
+    release_id INTEGER NOT NULL
+        REFERENCES "release"
+            ON DELETE CASCADE,
+    filter_id  INTEGER NOT NULL
+        REFERENCES filter
+            ON DELETE CASCADE,
+    type       TEXT DEFAULT 'DELAY' NOT NULL,
+    action_id  INTEGER
+        REFERENCES action
+            ON DELETE CASCADE,
+    run_at     TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE list
+(
+    id                  SERIAL PRIMARY KEY,
+    name                TEXT NOT NULL,
+    type                TEXT NOT NULL,
+    enabled             BOOLEAN DEFAULT FALSE,
+    url                 TEXT,
+    api_key             TEXT,
+    client_id           INTEGER DEFAULT 0,
+    tags_include        TEXT []   DEFAULT '{}',
+    tags_exclude        TEXT []   DEFAULT '{}',
+    filter_field        TEXT NOT NULL,
+    refresh_interval    INTEGER DEFAULT 60,
+    last_refresh_time   TIMESTAMP,
+    last_refresh_status TEXT,
+    last_refresh_error  TEXT,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE list_filter
+(
+    list_id   INTEGER,
+    filter_id INTEGER,
+    FOREIGN KEY (list_id) REFERENCES list(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    PRIMARY KEY (list_id, filter_id)
 );
 `
 
@@ -970,5 +1240,458 @@ ALTER TABLE irc_network
 
 CREATE INDEX filter_priority_index
 	ON filter (priority);
+`,
+	`CREATE TABLE announce_history
+(
+    id           SERIAL PRIMARY KEY,
+    indexer      TEXT NOT NULL,
+    source       TEXT NOT NULL,
+    torrent_name TEXT NOT NULL,
+    seen_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX announce_history_seen_at_index
+    ON announce_history (seen_at);
+`,
+	`CREATE TABLE user_notification_preference
+(
+    user_id             INTEGER PRIMARY KEY,
+    events              TEXT []   DEFAULT '{}' NOT NULL,
+    notifications       INTEGER [] DEFAULT '{}' NOT NULL,
+    quiet_hours_enabled BOOLEAN DEFAULT FALSE,
+    quiet_hours_start   TEXT,
+    quiet_hours_end     TEXT,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+`,
+	`ALTER TABLE release_action_status
+    ADD COLUMN latency_ms INTEGER DEFAULT 0;
+`,
+	`CREATE OR REPLACE FUNCTION notify_autobrr_change() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('autobrr_changes', json_build_object('table', TG_TABLE_NAME, 'source', current_setting('application_name'))::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER indexer_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON indexer
+    FOR EACH STATEMENT EXECUTE PROCEDURE notify_autobrr_change();
+
+CREATE TRIGGER client_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON client
+    FOR EACH STATEMENT EXECUTE PROCEDURE notify_autobrr_change();
+`,
+	`ALTER TABLE action
+    ADD COLUMN freeleech_token BOOLEAN DEFAULT false;
+
+ALTER TABLE action
+    ADD COLUMN freeleech_token_min_size TEXT DEFAULT '' NOT NULL;
+
+CREATE TABLE freeleech_token_budget
+(
+    indexer        TEXT PRIMARY KEY,
+    monthly_budget INTEGER DEFAULT 0 NOT NULL,
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE freeleech_token_usage
+(
+    id           SERIAL PRIMARY KEY,
+    indexer      TEXT NOT NULL,
+    torrent_name TEXT NOT NULL,
+    filter_id    INTEGER,
+    size         BIGINT DEFAULT 0,
+    used_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX freeleech_token_usage_indexer_used_at_index
+    ON freeleech_token_usage (indexer, used_at);
+`,
+	`CREATE TABLE release_retention
+(
+    id                   INTEGER PRIMARY KEY,
+    enabled              BOOLEAN DEFAULT FALSE,
+    max_age_days         INTEGER DEFAULT 0 NOT NULL,
+    max_rows_per_indexer INTEGER DEFAULT 0 NOT NULL,
+    schedule             TEXT DEFAULT '0 3 * * *' NOT NULL,
+    last_run             TIMESTAMP,
+    last_deleted         INTEGER DEFAULT 0 NOT NULL,
+    updated_at           TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	`ALTER TABLE filter
+    ADD COLUMN breaker_threshold INTEGER DEFAULT 0;
+
+ALTER TABLE filter
+    ADD COLUMN breaker_cooldown INTEGER DEFAULT 0;
+
+ALTER TABLE filter
+    ADD COLUMN breaker_fail_open BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN smart_music BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE filter
+    ADD COLUMN smart_music_match TEXT []   DEFAULT '{}';
+
+ALTER TABLE "release"
+    ADD COLUMN audio_format TEXT;
+
+ALTER TABLE "release"
+    ADD COLUMN bitrate TEXT;
+`,
+	`ALTER TABLE irc_network
+    ADD COLUMN status_command TEXT;
+
+ALTER TABLE irc_network
+    ADD COLUMN status_nicks TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN deleted_at TIMESTAMP;
+`,
+	`ALTER TABLE indexer
+    ADD COLUMN identity TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN schedule TEXT;
+`,
+	`CREATE TABLE filter_group
+(
+    id         SERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    enabled    BOOLEAN DEFAULT TRUE,
+    priority   INTEGER DEFAULT 0 NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE filter
+    ADD group_id INTEGER
+        CONSTRAINT filter_group_id_fk
+            REFERENCES filter_group(id)
+            ON DELETE SET NULL;
+
+CREATE INDEX filter_group_id_index
+    ON filter (group_id);
+`,
+	`ALTER TABLE filter_external
+    ADD COLUMN webhook_timeout_seconds INTEGER;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN advanced_expression TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN normalize_categories BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN smart_duplicate_days INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE "release"
+    ADD COLUMN artists TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN max_downloads_size TEXT;
+
+ALTER TABLE filter
+    ADD COLUMN max_downloads_size_unit TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN skip_dedupe BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN ignore_season_packs BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN upgrade_replace BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE release_action_status
+    ADD COLUMN info_hash TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN group_tiers TEXT;
+`,
+	`CREATE TABLE release_pending
+(
+    id         SERIAL PRIMARY KEY,
+    release_id INTEGER NOT NULL
+        REFERENCES "release"
+            ON DELETE CASCADE,
+    filter_id  INTEGER NOT NULL
+        REFERENCES filter
+            ON DELETE CASCADE,
+    run_at     TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	`ALTER TABLE action
+    ADD COLUMN require_approval BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE release_pending
+    ADD COLUMN type TEXT DEFAULT 'DELAY' NOT NULL;
+
+ALTER TABLE release_pending
+    ADD COLUMN action_id INTEGER
+        REFERENCES action
+            ON DELETE CASCADE;
+`,
+	`ALTER TABLE action
+    ADD COLUMN run_condition TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN dupe_key TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN exec_timeout INTEGER DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN exec_env TEXT [] DEFAULT '{}';
+
+ALTER TABLE action
+    ADD COLUMN exec_dir TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN webhook_secret TEXT;
+
+ALTER TABLE action
+    ADD COLUMN webhook_retry_attempts INTEGER DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN webhook_retry_delay_seconds INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN limit_inactive_seed_time INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN custom1 TEXT;
+
+ALTER TABLE action
+    ADD COLUMN custom2 TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN bandwidth_group TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN max_connections INTEGER DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN move_completed_path TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN reannounce_jitter INTEGER DEFAULT 0;
+
+ALTER TABLE release_action_status
+    ADD COLUMN reannounce TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN failover_clients INTEGER [] DEFAULT '{}';
+`,
+	`ALTER TABLE action
+    ADD COLUMN client_pool_mode TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN validate_torrent BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE action
+    ADD COLUMN validate_announce TEXT;
+
+ALTER TABLE action
+    ADD COLUMN validate_private TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN max_files INTEGER DEFAULT 0;
+
+ALTER TABLE filter
+    ADD COLUMN match_file_extensions TEXT;
+
+ALTER TABLE filter
+    ADD COLUMN except_file_extensions TEXT;
+`,
+	`ALTER TABLE release_action_status
+    ADD COLUMN completed_at TIMESTAMP;
+`,
+	`ALTER TABLE action
+    ADD COLUMN completion_hooks TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN remove_ratio REAL DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN remove_seed_time INTEGER DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN remove_after_days INTEGER DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN remove_delete_data BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE release_action_status
+    ADD COLUMN removed_at TIMESTAMP;
+`,
+	`ALTER TABLE action
+    ADD COLUMN cross_seed TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN sonarr_check_client_id INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN radarr_check_client_id INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN lidarr_check_client_id INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN library_check_client_id INTEGER DEFAULT 0;
+`,
+	`CREATE TABLE list
+(
+    id                  SERIAL PRIMARY KEY,
+    name                TEXT NOT NULL,
+    type                TEXT NOT NULL,
+    enabled             BOOLEAN DEFAULT FALSE,
+    url                 TEXT,
+    api_key             TEXT,
+    filter_field        TEXT NOT NULL,
+    refresh_interval    INTEGER DEFAULT 60,
+    last_refresh_time   TIMESTAMP,
+    last_refresh_status TEXT,
+    last_refresh_error  TEXT,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE list_filter
+(
+    list_id   INTEGER,
+    filter_id INTEGER,
+    FOREIGN KEY (list_id) REFERENCES list(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    PRIMARY KEY (list_id, filter_id)
+);
+`,
+	`ALTER TABLE list
+    ADD COLUMN client_id INTEGER DEFAULT 0;
+
+ALTER TABLE list
+    ADD COLUMN tags_include TEXT []   DEFAULT '{}';
+
+ALTER TABLE list
+    ADD COLUMN tags_exclude TEXT []   DEFAULT '{}';
+`,
+	`ALTER TABLE filter
+    ADD COLUMN match_ids TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN match_musicbrainz TEXT;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN headers TEXT;
+
+ALTER TABLE notification
+    ADD COLUMN template TEXT;
+
+ALTER TABLE notification
+    ADD COLUMN secret TEXT;
+`,
+	`CREATE TABLE notification_delivery
+(
+    id              SERIAL PRIMARY KEY,
+    notification_id INTEGER NOT NULL,
+    event           TEXT NOT NULL,
+    payload         TEXT NOT NULL,
+    status          TEXT NOT NULL,
+    attempts        INTEGER DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (notification_id) REFERENCES notification(id) ON DELETE CASCADE
+);
+
+CREATE INDEX notification_delivery_status_next_attempt_at_index
+    ON notification_delivery (status, next_attempt_at);
+`,
+	`ALTER TABLE notification
+    ADD COLUMN message_templates TEXT;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN quiet_hours_enabled BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE notification
+    ADD COLUMN quiet_hours_start TEXT;
+
+ALTER TABLE notification
+    ADD COLUMN quiet_hours_end TEXT;
+
+ALTER TABLE notification
+    ADD COLUMN quiet_hours_timezone TEXT;
+
+ALTER TABLE notification
+    ADD COLUMN quiet_hours_mode TEXT;
+
+ALTER TABLE notification
+    ADD COLUMN quiet_hours_allow_errors BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE api_key
+    ADD COLUMN expires_at TIMESTAMP;
+`,
+	`ALTER TABLE users
+    ADD COLUMN role TEXT DEFAULT 'admin' NOT NULL;
+
+ALTER TABLE api_key
+    ADD COLUMN user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE filter
+    ADD COLUMN created_by TEXT;
+
+ALTER TABLE filter
+    ADD COLUMN updated_by TEXT;
+`,
+	`CREATE TABLE audit_log
+(
+    id            SERIAL PRIMARY KEY,
+    action        TEXT NOT NULL,
+    resource      TEXT NOT NULL,
+    resource_id   TEXT,
+    resource_name TEXT,
+    description   TEXT,
+    username      TEXT,
+    api_key       TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_created_at_index
+    ON audit_log (created_at);
+`,
+	`CREATE INDEX release_resolution_index
+    ON "release" (resolution);
+
+CREATE INDEX release_source_index
+    ON "release" (source);
+
+CREATE INDEX release_release_group_index
+    ON "release" (release_group);
+
+CREATE INDEX release_size_index
+    ON "release" (size);
+
+CREATE INDEX release_action_status_client_index
+    ON release_action_status (client);
+`,
+	`ALTER TABLE "release"
+    ADD COLUMN notes TEXT;
+
+ALTER TABLE "release"
+    ADD COLUMN user_tags TEXT;
+`,
+	`ALTER TABLE users
+    ADD COLUMN oidc_issuer TEXT;
+
+ALTER TABLE users
+    ADD COLUMN oidc_subject TEXT;
+
+CREATE UNIQUE INDEX users_oidc_issuer_subject_index
+    ON users (oidc_issuer, oidc_subject);
 `,
 }
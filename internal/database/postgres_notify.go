@@ -0,0 +1,135 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// changeNotifyChannel is notified by triggers on tables that are cached in memory
+	changeNotifyChannel = "autobrr_changes"
+
+	// changeDebounce groups notifications from bulk changes into a single invalidation
+	changeDebounce = 1 * time.Second
+)
+
+// ChangeHandler is called with the name of the changed table.
+// An empty table means all cached data should be invalidated, eg. after a reconnect where notifications might have been missed.
+type ChangeHandler func(table string)
+
+type changeNotification struct {
+	Table  string `json:"table"`
+	Source string `json:"source"`
+}
+
+func newInstanceName() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "autobrr"
+	}
+
+	return "autobrr-" + hex.EncodeToString(b)
+}
+
+// ListenForChanges uses postgres LISTEN/NOTIFY to get notified when cached tables are changed by another instance sharing the database.
+// Changes made by this instance are ignored since the services already update their caches.
+func (db *DB) ListenForChanges(handler ChangeHandler) error {
+	if db.Driver != "postgres" {
+		return errors.New("change notifications are only supported with postgres")
+	}
+
+	listener := pq.NewListener(db.DSN, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			db.log.Error().Err(err).Msg("database change listener error")
+		}
+	})
+
+	if err := listener.Listen(changeNotifyChannel); err != nil {
+		listener.Close()
+		return errors.Wrap(err, "could not listen on channel: %s", changeNotifyChannel)
+	}
+
+	go func() {
+		defer listener.Close()
+		db.handleChanges(listener.Notify, listener.Ping, handler)
+	}()
+
+	db.log.Debug().Msgf("listening for database changes on channel: %s", changeNotifyChannel)
+
+	return nil
+}
+
+// handleChanges debounces the notifications and calls the handler once per changed table
+func (db *DB) handleChanges(notify <-chan *pq.Notification, ping func() error, handler ChangeHandler) {
+	pending := make(map[string]struct{})
+
+	debounce := time.NewTimer(changeDebounce)
+	debounce.Stop()
+
+	pingTicker := time.NewTicker(90 * time.Second)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-db.ctx.Done():
+			return
+
+		case n := <-notify:
+			// a nil notification is sent after reconnecting
+			if n == nil {
+				db.log.Debug().Msg("database change listener reconnected, invalidate all")
+				if len(pending) == 0 {
+					debounce.Reset(changeDebounce)
+				}
+				pending[""] = struct{}{}
+				continue
+			}
+
+			var change changeNotification
+			if err := json.Unmarshal([]byte(n.Extra), &change); err != nil {
+				db.log.Error().Err(err).Msgf("could not decode database change notification: %s", n.Extra)
+				continue
+			}
+
+			if db.instanceName != "" && change.Source == db.instanceName {
+				continue
+			}
+
+			db.log.Trace().Msgf("database change notification for table: %s from: %s", change.Table, change.Source)
+
+			// start the timer on the first change so continuous changes are still flushed
+			if len(pending) == 0 {
+				debounce.Reset(changeDebounce)
+			}
+			pending[change.Table] = struct{}{}
+
+		case <-debounce.C:
+			// invalidating everything covers the single tables
+			if _, ok := pending[""]; ok {
+				pending = map[string]struct{}{"": {}}
+			}
+
+			for table := range pending {
+				handler(table)
+			}
+
+			pending = make(map[string]struct{})
+
+		case <-pingTicker.C:
+			go func() {
+				if err := ping(); err != nil {
+					db.log.Error().Err(err).Msg("database change listener ping failed")
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type changeRecorder struct {
+	mu     sync.Mutex
+	tables []string
+	called chan struct{}
+}
+
+func (r *changeRecorder) handle(table string) {
+	r.mu.Lock()
+	r.tables = append(r.tables, table)
+	r.mu.Unlock()
+
+	r.called <- struct{}{}
+}
+
+func (r *changeRecorder) wait(t *testing.T, n int) []string {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.called:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("handler called %d times, want %d", i, n)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tables := r.tables
+	r.tables = nil
+	sort.Strings(tables)
+
+	return tables
+}
+
+func TestDB_handleChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := &DB{log: zerolog.Nop(), ctx: ctx, instanceName: "autobrr-self"}
+
+	notify := make(chan *pq.Notification)
+	recorder := &changeRecorder{called: make(chan struct{}, 10)}
+
+	done := make(chan struct{})
+	go func() {
+		db.handleChanges(notify, func() error { return nil }, recorder.handle)
+		close(done)
+	}()
+
+	send := func(extra string) {
+		notify <- &pq.Notification{Channel: changeNotifyChannel, Extra: extra}
+	}
+
+	// changes are debounced into a single call per table and own changes are ignored
+	send(`{"table":"indexer","source":"autobrr-other"}`)
+	send(`{"table":"indexer","source":"autobrr-other"}`)
+	send(`{"table":"client","source":"autobrr-other"}`)
+	send(`{"table":"filter","source":"autobrr-self"}`)
+	send(`not json`)
+
+	assert.Equal(t, []string{"client", "indexer"}, recorder.wait(t, 2))
+
+	// a reconnect invalidates everything instead of the single tables
+	send(`{"table":"indexer","source":"autobrr-other"}`)
+	notify <- nil
+
+	assert.Equal(t, []string{""}, recorder.wait(t, 1))
+
+	select {
+	case <-recorder.called:
+		t.Fatal("unexpected handler call")
+	case <-time.After(2 * changeDebounce):
+	}
+
+	cancel()
+	<-done
+}
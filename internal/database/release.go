@@ -6,6 +6,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -38,8 +39,8 @@ func (repo *ReleaseRepo) Store(ctx context.Context, r *domain.Release) error {
 
 	queryBuilder := repo.db.squirrel.
 		Insert("release").
-		Columns("filter_status", "rejections", "indexer", "filter", "protocol", "implementation", "timestamp", "group_id", "torrent_id", "info_url", "download_url", "torrent_name", "size", "title", "category", "season", "episode", "year", "month", "day", "resolution", "source", "codec", "container", "hdr", "release_group", "proper", "repack", "website", "type", "origin", "tags", "uploader", "pre_time", "filter_id").
-		Values(r.FilterStatus, pq.Array(r.Rejections), r.Indexer.Identifier, r.FilterName, r.Protocol, r.Implementation, r.Timestamp.Format(time.RFC3339), r.GroupID, r.TorrentID, r.InfoURL, r.DownloadURL, r.TorrentName, r.Size, r.Title, r.Category, r.Season, r.Episode, r.Year, r.Month, r.Day, r.Resolution, r.Source, codecStr, r.Container, hdrStr, r.Group, r.Proper, r.Repack, r.Website, r.Type, r.Origin, pq.Array(r.Tags), r.Uploader, r.PreTime, r.FilterID).
+		Columns("filter_status", "rejections", "indexer", "filter", "protocol", "implementation", "timestamp", "group_id", "torrent_id", "info_url", "download_url", "torrent_name", "size", "title", "category", "season", "episode", "year", "month", "day", "resolution", "source", "codec", "container", "hdr", "audio_format", "bitrate", "release_group", "proper", "repack", "website", "type", "origin", "tags", "uploader", "pre_time", "artists", "filter_id").
+		Values(r.FilterStatus, pq.Array(r.Rejections), r.Indexer.Identifier, r.FilterName, r.Protocol, r.Implementation, r.Timestamp.Format(time.RFC3339), r.GroupID, r.TorrentID, r.InfoURL, r.DownloadURL, r.TorrentName, r.Size, r.Title, r.Category, r.Season, r.Episode, r.Year, r.Month, r.Day, r.Resolution, r.Source, codecStr, r.Container, hdrStr, r.AudioFormat, r.Bitrate, r.Group, r.Proper, r.Repack, r.Website, r.Type, r.Origin, pq.Array(r.Tags), r.Uploader, r.PreTime, r.Artists, r.FilterID).
 		Suffix("RETURNING id").RunWith(repo.db.handler)
 
 	// return values
@@ -57,12 +58,20 @@ func (repo *ReleaseRepo) Store(ctx context.Context, r *domain.Release) error {
 }
 
 func (repo *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *domain.ReleaseActionStatus) error {
+	reannounce, err := marshalReannounce(status.Reannounce)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal reannounce attempts")
+	}
+
 	if status.ID != 0 {
 		queryBuilder := repo.db.squirrel.
 			Update("release_action_status").
 			Set("status", status.Status).
 			Set("rejections", pq.Array(status.Rejections)).
 			Set("timestamp", status.Timestamp.Format(time.RFC3339)).
+			Set("latency_ms", status.LatencyMs).
+			Set("info_hash", toNullString(status.InfoHash)).
+			Set("reannounce", reannounce).
 			Where(sq.Eq{"id": status.ID}).
 			Where(sq.Eq{"release_id": status.ReleaseID})
 
@@ -78,8 +87,8 @@ func (repo *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *d
 	} else {
 		queryBuilder := repo.db.squirrel.
 			Insert("release_action_status").
-			Columns("status", "action", "action_id", "type", "client", "filter", "filter_id", "rejections", "timestamp", "release_id").
-			Values(status.Status, status.Action, status.ActionID, status.Type, status.Client, status.Filter, status.FilterID, pq.Array(status.Rejections), status.Timestamp.Format(time.RFC3339), status.ReleaseID).
+			Columns("status", "action", "action_id", "type", "client", "filter", "filter_id", "rejections", "timestamp", "latency_ms", "info_hash", "reannounce", "release_id").
+			Values(status.Status, status.Action, status.ActionID, status.Type, status.Client, status.Filter, status.FilterID, pq.Array(status.Rejections), status.Timestamp.Format(time.RFC3339), status.LatencyMs, toNullString(status.InfoHash), reannounce, status.ReleaseID).
 			Suffix("RETURNING id").RunWith(repo.db.handler)
 
 		// return values
@@ -124,6 +133,14 @@ var reservedSearch = map[string]*regexp.Regexp{
 	"r.codec":         regexp.MustCompile(`(?i)(?:` + `codec` + `:)(?P<value>'.*?'|".*?"|\S+)`),
 	"r.hdr":           regexp.MustCompile(`(?i)(?:` + `hdr` + `:)(?P<value>'.*?'|".*?"|\S+)`),
 	"r.filter":        regexp.MustCompile(`(?i)(?:` + `filter` + `:)(?P<value>'.*?'|".*?"|\S+)`),
+	"r.notes":         regexp.MustCompile(`(?i)(?:` + `notes` + `:)(?P<value>'.*?'|".*?"|\S+)`),
+	"r.user_tags":     regexp.MustCompile(`(?i)(?:` + `tag` + `:)(?P<value>'.*?'|".*?"|\S+)`),
+}
+
+// containsSearch are the reserved search fields matched anywhere instead of at the start
+var containsSearch = map[string]bool{
+	"r.notes":     true,
+	"r.user_tags": true,
 }
 
 func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain.ReleaseQueryParams) (*domain.FindReleasesResponse, error) {
@@ -138,7 +155,11 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 			if reskey := regex.FindAllStringSubmatch(search, -1); len(reskey) != 0 {
 				filter := sq.Or{}
 				for _, found := range reskey {
-					filter = append(filter, repo.db.ILike(dbField, strings.ReplaceAll(strings.Trim(strings.Trim(found[1], `"`), `'`), ".", "_")+"%"))
+					value := strings.ReplaceAll(strings.Trim(strings.Trim(found[1], `"`), `'`), ".", "_") + "%"
+					if containsSearch[dbField] {
+						value = "%" + value
+					}
+					filter = append(filter, repo.db.ILike(dbField, value))
 				}
 
 				if len(filter) == 0 {
@@ -159,25 +180,81 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 		}
 	}
 
-	if params.Filters.Indexers != nil {
-		filter := sq.And{}
-		for _, v := range params.Filters.Indexers {
-			filter = append(filter, sq.Eq{"r.indexer": v})
+	if len(params.Filters.Indexers) > 0 {
+		whereQueryBuilder = append(whereQueryBuilder, sq.Eq{"r.indexer": params.Filters.Indexers})
+	}
+
+	if len(params.Filters.Resolutions) > 0 {
+		whereQueryBuilder = append(whereQueryBuilder, sq.Eq{"r.resolution": params.Filters.Resolutions})
+	}
+
+	if len(params.Filters.Sources) > 0 {
+		whereQueryBuilder = append(whereQueryBuilder, sq.Eq{"r.source": params.Filters.Sources})
+	}
+
+	if len(params.Filters.ReleaseGroups) > 0 {
+		whereQueryBuilder = append(whereQueryBuilder, sq.Eq{"r.release_group": params.Filters.ReleaseGroups})
+	}
+
+	// codec and hdr are stored comma separated
+	if len(params.Filters.Codecs) > 0 {
+		filter := sq.Or{}
+		for _, v := range params.Filters.Codecs {
+			filter = append(filter, repo.db.ILike("r.codec", "%"+v+"%"))
+		}
+		whereQueryBuilder = append(whereQueryBuilder, filter)
+	}
+
+	if len(params.Filters.HDR) > 0 {
+		filter := sq.Or{}
+		for _, v := range params.Filters.HDR {
+			filter = append(filter, repo.db.ILike("r.hdr", "%"+v+"%"))
 		}
+		whereQueryBuilder = append(whereQueryBuilder, filter)
+	}
 
-		if len(filter) > 0 {
-			whereQueryBuilder = append(whereQueryBuilder, filter)
+	if params.Filters.MinSize > 0 {
+		whereQueryBuilder = append(whereQueryBuilder, sq.GtOrEq{"r.size": params.Filters.MinSize})
+	}
+
+	if params.Filters.MaxSize > 0 {
+		whereQueryBuilder = append(whereQueryBuilder, sq.LtOrEq{"r.size": params.Filters.MaxSize})
+	}
+
+	if !params.Filters.From.IsZero() {
+		whereQueryBuilder = append(whereQueryBuilder, sq.GtOrEq{"r.timestamp": repo.timestampArg(params.Filters.From)})
+	}
+
+	if !params.Filters.To.IsZero() {
+		whereQueryBuilder = append(whereQueryBuilder, sq.Lt{"r.timestamp": repo.timestampArg(params.Filters.To)})
+	}
+
+	if len(params.Filters.UserTags) > 0 {
+		filter := sq.Or{}
+		for _, tag := range params.Filters.UserTags {
+			filter = append(filter, repo.userTagCondition(tag))
 		}
+		whereQueryBuilder = append(whereQueryBuilder, filter)
 	}
 
-	whereQuery, _, err := whereQueryBuilder.ToSql()
-	if err != nil {
-		return nil, errors.Wrap(err, "error building wherequery")
+	// one action status has to match all the action conditions
+	actionStatusQuery := sq.And{}
+	if params.Filters.PushStatus != "" {
+		actionStatusQuery = append(actionStatusQuery, sq.Eq{"ras.status": params.Filters.PushStatus})
+	}
+
+	if len(params.Filters.Clients) > 0 {
+		actionStatusQuery = append(actionStatusQuery, sq.Eq{"ras.client": params.Filters.Clients})
 	}
 
-	subQueryBuilder := repo.db.squirrel.
+	if len(actionStatusQuery) > 0 {
+		whereQueryBuilder = append(whereQueryBuilder, sq.Expr("r.id IN (?)", sq.Select("ras.release_id").From("release_action_status ras").Where(actionStatusQuery)))
+	}
+
+	// the sub and count queries are nested in the query, they keep the default placeholders
+	// so the placeholders of the query are numbered once for postgres
+	subQueryBuilder := sq.
 		Select("r.id").
-		Distinct().
 		From("release r").
 		OrderBy("r.id DESC")
 
@@ -191,17 +268,11 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 		subQueryBuilder = subQueryBuilder.Offset(params.Offset)
 	}
 
+	countQuery := sq.Select("COUNT(*)").From("release r")
+
 	if len(whereQueryBuilder) != 0 {
 		subQueryBuilder = subQueryBuilder.Where(whereQueryBuilder)
-	}
-
-	countQuery := repo.db.squirrel.Select("COUNT(*)").From("release r").Where(whereQuery)
-
-	if params.Filters.PushStatus != "" {
-		subQueryBuilder = subQueryBuilder.InnerJoin("release_action_status ras ON r.id = ras.release_id").Where(sq.Eq{"ras.status": params.Filters.PushStatus})
-
-		// using sq.Eq for countQuery breaks search with Postgres.
-		countQuery = countQuery.InnerJoin("release_action_status ras ON r.id = ras.release_id").Where("ras.status = '" + params.Filters.PushStatus + `'`)
+		countQuery = countQuery.Where(whereQueryBuilder)
 	}
 
 	subQuery, subArgs, err := subQueryBuilder.ToSql()
@@ -210,8 +281,8 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 	}
 
 	queryBuilder := repo.db.squirrel.
-		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "i.id", "i.name", "i.identifier_external", "r.filter", "r.protocol", "r.info_url", "r.download_url", "r.title", "r.torrent_name", "r.size", "r.category", "r.season", "r.episode", "r.year", "r.resolution", "r.source", "r.codec", "r.container", "r.release_group", "r.timestamp",
-			"ras.id", "ras.status", "ras.action", "ras.action_id", "ras.type", "ras.client", "ras.filter", "ras.filter_id", "ras.release_id", "ras.rejections", "ras.timestamp").
+		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "i.id", "i.name", "i.identifier_external", "r.filter", "r.protocol", "r.info_url", "r.download_url", "r.title", "r.torrent_name", "r.size", "r.category", "r.season", "r.episode", "r.year", "r.resolution", "r.source", "r.codec", "r.container", "r.release_group", "r.origin", "r.tags", "r.uploader", "r.artists", "r.notes", "r.user_tags", "r.timestamp",
+			"ras.id", "ras.status", "ras.action", "ras.action_id", "ras.type", "ras.client", "ras.filter", "ras.filter_id", "ras.release_id", "ras.rejections", "ras.timestamp", "ras.reannounce", "ras.completed_at", "ras.removed_at").
 		Column(sq.Alias(countQuery, "page_total")).
 		From("release r").
 		OrderBy("r.id DESC").
@@ -247,15 +318,15 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 		var rls domain.Release
 		var ras domain.ReleaseActionStatus
 
-		var rlsIndexer, rlsIndexerName, rlsIndexerExternalName, rlsFilter, infoUrl, downloadUrl, codec sql.NullString
+		var rlsIndexer, rlsIndexerName, rlsIndexerExternalName, rlsFilter, infoUrl, downloadUrl, codec, origin, uploader, artists, notes, userTags sql.NullString
 
 		var rlsIndexerID sql.NullInt64
 		var rasId, rasFilterId, rasReleaseId, rasActionId sql.NullInt64
-		var rasStatus, rasAction, rasType, rasClient, rasFilter sql.NullString
+		var rasStatus, rasAction, rasType, rasClient, rasFilter, rasReannounce sql.NullString
 		var rasRejections []sql.NullString
-		var rasTimestamp sql.NullTime
+		var rasTimestamp, rasCompletedAt, rasRemovedAt sql.NullTime
 
-		if err := rows.Scan(&rls.ID, &rls.FilterStatus, pq.Array(&rls.Rejections), &rlsIndexer, &rlsIndexerID, &rlsIndexerName, &rlsIndexerExternalName, &rlsFilter, &rls.Protocol, &infoUrl, &downloadUrl, &rls.Title, &rls.TorrentName, &rls.Size, &rls.Category, &rls.Season, &rls.Episode, &rls.Year, &rls.Resolution, &rls.Source, &codec, &rls.Container, &rls.Group, &rls.Timestamp, &rasId, &rasStatus, &rasAction, &rasActionId, &rasType, &rasClient, &rasFilter, &rasFilterId, &rasReleaseId, pq.Array(&rasRejections), &rasTimestamp, &resp.TotalCount); err != nil {
+		if err := rows.Scan(&rls.ID, &rls.FilterStatus, pq.Array(&rls.Rejections), &rlsIndexer, &rlsIndexerID, &rlsIndexerName, &rlsIndexerExternalName, &rlsFilter, &rls.Protocol, &infoUrl, &downloadUrl, &rls.Title, &rls.TorrentName, &rls.Size, &rls.Category, &rls.Season, &rls.Episode, &rls.Year, &rls.Resolution, &rls.Source, &codec, &rls.Container, &rls.Group, &origin, pq.Array(&rls.Tags), &uploader, &artists, &notes, &userTags, &rls.Timestamp, &rasId, &rasStatus, &rasAction, &rasActionId, &rasType, &rasClient, &rasFilter, &rasFilterId, &rasReleaseId, pq.Array(&rasRejections), &rasTimestamp, &rasReannounce, &rasCompletedAt, &rasRemovedAt, &resp.TotalCount); err != nil {
 			return resp, errors.Wrap(err, "error scanning row")
 		}
 
@@ -274,12 +345,18 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 		ras.FilterID = rasFilterId.Int64
 		ras.Timestamp = rasTimestamp.Time
 		ras.ReleaseID = rasReleaseId.Int64
+		ras.CompletedAt = nullTimePtr(rasCompletedAt)
+		ras.RemovedAt = nullTimePtr(rasRemovedAt)
 		ras.Rejections = []string{}
 
 		for _, rejection := range rasRejections {
 			ras.Rejections = append(ras.Rejections, rejection.String)
 		}
 
+		if ras.Reannounce, err = unmarshalReannounce(rasReannounce.String); err != nil {
+			return resp, errors.Wrap(err, "could not unmarshal reannounce attempts")
+		}
+
 		idx := 0
 		for ; idx < len(resp.Data); idx++ {
 			if resp.Data[idx].ID != rls.ID {
@@ -304,6 +381,11 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 		rls.InfoURL = infoUrl.String
 		rls.DownloadURL = downloadUrl.String
 		rls.Codec = strings.Split(codec.String, ",")
+		rls.Origin = origin.String
+		rls.Uploader = uploader.String
+		rls.Artists = artists.String
+		rls.Notes = notes.String
+		rls.UserTags = splitUserTags(userTags.String)
 
 		// only add ActionStatus if it's not empty
 		if ras.ID > 0 {
@@ -321,6 +403,120 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 	return resp, nil
 }
 
+// Export streams the releases matching params with their action statuses to fn, newest first
+func (repo *ReleaseRepo) Export(ctx context.Context, params domain.ReleaseExportParams, fn func(release *domain.Release) error) error {
+	queryBuilder := repo.db.squirrel.
+		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "i.id", "i.name", "i.identifier_external", "r.filter", "r.protocol", "r.info_url", "r.download_url", "r.title", "r.torrent_name", "r.size", "r.category", "r.season", "r.episode", "r.year", "r.resolution", "r.source", "r.codec", "r.container", "r.release_group", "r.timestamp",
+			"ras.id", "ras.status", "ras.action", "ras.action_id", "ras.type", "ras.client", "ras.filter", "ras.filter_id", "ras.release_id", "ras.rejections", "ras.timestamp").
+		From("release r").
+		LeftJoin("release_action_status ras ON r.id = ras.release_id").
+		LeftJoin("indexer i ON r.indexer = i.identifier").
+		OrderBy("r.id DESC", "ras.id ASC")
+
+	if len(params.Indexers) > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.indexer": params.Indexers})
+	}
+
+	if params.PushStatus != "" {
+		queryBuilder = queryBuilder.Where("r.id IN (SELECT release_id FROM release_action_status WHERE status = ?)", params.PushStatus)
+	}
+
+	if !params.From.IsZero() {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"r.timestamp": repo.timestampArg(params.From)})
+	}
+
+	if !params.To.IsZero() {
+		queryBuilder = queryBuilder.Where(sq.Lt{"r.timestamp": repo.timestampArg(params.To)})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	repo.log.Trace().Str("database", "release.export").Msgf("query: '%v', args: '%v'", query, args)
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	// rows are ordered by release so a release is complete once the next one starts
+	var current *domain.Release
+
+	for rows.Next() {
+		var rls domain.Release
+		var ras domain.ReleaseActionStatus
+
+		var rlsIndexer, rlsIndexerName, rlsIndexerExternalName, rlsFilter, infoUrl, downloadUrl, codec sql.NullString
+
+		var rlsIndexerID sql.NullInt64
+		var rasId, rasFilterId, rasReleaseId, rasActionId sql.NullInt64
+		var rasStatus, rasAction, rasType, rasClient, rasFilter sql.NullString
+		var rasRejections []sql.NullString
+		var rasTimestamp sql.NullTime
+
+		if err := rows.Scan(&rls.ID, &rls.FilterStatus, pq.Array(&rls.Rejections), &rlsIndexer, &rlsIndexerID, &rlsIndexerName, &rlsIndexerExternalName, &rlsFilter, &rls.Protocol, &infoUrl, &downloadUrl, &rls.Title, &rls.TorrentName, &rls.Size, &rls.Category, &rls.Season, &rls.Episode, &rls.Year, &rls.Resolution, &rls.Source, &codec, &rls.Container, &rls.Group, &rls.Timestamp, &rasId, &rasStatus, &rasAction, &rasActionId, &rasType, &rasClient, &rasFilter, &rasFilterId, &rasReleaseId, pq.Array(&rasRejections), &rasTimestamp); err != nil {
+			return errors.Wrap(err, "error scanning row")
+		}
+
+		if current == nil || current.ID != rls.ID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+
+			rls.Indexer.Identifier = rlsIndexer.String
+			rls.Indexer.ID = int(rlsIndexerID.Int64)
+			rls.Indexer.Name = rlsIndexerName.String
+			rls.Indexer.IdentifierExternal = rlsIndexerExternalName.String
+
+			rls.FilterName = rlsFilter.String
+			rls.ActionStatus = make([]domain.ReleaseActionStatus, 0)
+			rls.InfoURL = infoUrl.String
+			rls.DownloadURL = downloadUrl.String
+			rls.Codec = strings.Split(codec.String, ",")
+
+			current = &rls
+		}
+
+		if !rasId.Valid {
+			continue
+		}
+
+		ras.ID = rasId.Int64
+		ras.Status = domain.ReleasePushStatus(rasStatus.String)
+		ras.Action = rasAction.String
+		ras.ActionID = rasActionId.Int64
+		ras.Type = domain.ActionType(rasType.String)
+		ras.Client = rasClient.String
+		ras.Filter = rasFilter.String
+		ras.FilterID = rasFilterId.Int64
+		ras.Timestamp = rasTimestamp.Time
+		ras.ReleaseID = rasReleaseId.Int64
+		ras.Rejections = []string{}
+
+		for _, rejection := range rasRejections {
+			ras.Rejections = append(ras.Rejections, rejection.String)
+		}
+
+		current.ActionStatus = append(current.ActionStatus, ras)
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "error rows export")
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+
+	return nil
+}
+
 func (repo *ReleaseRepo) GetIndexerOptions(ctx context.Context) ([]string, error) {
 	query := `SELECT DISTINCT indexer FROM "release" UNION SELECT DISTINCT identifier indexer FROM indexer;`
 
@@ -354,7 +550,7 @@ func (repo *ReleaseRepo) GetIndexerOptions(ctx context.Context) ([]string, error
 
 func (repo *ReleaseRepo) GetActionStatusByReleaseID(ctx context.Context, releaseID int64) ([]domain.ReleaseActionStatus, error) {
 	queryBuilder := repo.db.squirrel.
-		Select("id", "status", "action", "action_id", "type", "client", "filter", "release_id", "rejections", "timestamp").
+		Select("id", "status", "action", "action_id", "type", "client", "filter", "release_id", "rejections", "timestamp", "reannounce", "completed_at", "removed_at").
 		From("release_action_status").
 		Where(sq.Eq{"release_id": releaseID})
 
@@ -380,16 +576,23 @@ func (repo *ReleaseRepo) GetActionStatusByReleaseID(ctx context.Context, release
 	for rows.Next() {
 		var rls domain.ReleaseActionStatus
 
-		var client, filter sql.NullString
+		var client, filter, reannounce sql.NullString
 		var actionId sql.NullInt64
+		var completedAt, removedAt sql.NullTime
 
-		if err := rows.Scan(&rls.ID, &rls.Status, &rls.Action, &actionId, &rls.Type, &client, &filter, &rls.ReleaseID, pq.Array(&rls.Rejections), &rls.Timestamp); err != nil {
+		if err := rows.Scan(&rls.ID, &rls.Status, &rls.Action, &actionId, &rls.Type, &client, &filter, &rls.ReleaseID, pq.Array(&rls.Rejections), &rls.Timestamp, &reannounce, &completedAt, &removedAt); err != nil {
 			return res, errors.Wrap(err, "error scanning row")
 		}
 
 		rls.ActionID = actionId.Int64
 		rls.Client = client.String
 		rls.Filter = filter.String
+		rls.CompletedAt = nullTimePtr(completedAt)
+		rls.RemovedAt = nullTimePtr(removedAt)
+
+		if rls.Reannounce, err = unmarshalReannounce(reannounce.String); err != nil {
+			return res, errors.Wrap(err, "could not unmarshal reannounce attempts")
+		}
 
 		res = append(res, rls)
 	}
@@ -399,7 +602,7 @@ func (repo *ReleaseRepo) GetActionStatusByReleaseID(ctx context.Context, release
 
 func (repo *ReleaseRepo) Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error) {
 	queryBuilder := repo.db.squirrel.
-		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "r.filter", "r.filter_id", "r.protocol", "r.implementation", "r.info_url", "r.download_url", "r.title", "r.torrent_name", "r.category", "r.size", "r.group_id", "r.torrent_id", "r.uploader", "r.timestamp").
+		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "r.filter", "r.filter_id", "r.protocol", "r.implementation", "r.info_url", "r.download_url", "r.title", "r.torrent_name", "r.category", "r.size", "r.group_id", "r.torrent_id", "r.uploader", "r.notes", "r.user_tags", "r.timestamp").
 		From("release r").
 		OrderBy("r.id DESC").
 		Where(sq.Eq{"r.id": req.Id})
@@ -418,10 +621,10 @@ func (repo *ReleaseRepo) Get(ctx context.Context, req *domain.GetReleaseRequest)
 
 	var rls domain.Release
 
-	var indexerName, filterName, infoUrl, downloadUrl, groupId, torrentId, category, uploader sql.NullString
+	var indexerName, filterName, infoUrl, downloadUrl, groupId, torrentId, category, uploader, notes, userTags sql.NullString
 	var filterId sql.NullInt64
 
-	if err := row.Scan(&rls.ID, &rls.FilterStatus, pq.Array(&rls.Rejections), &indexerName, &filterName, &filterId, &rls.Protocol, &rls.Implementation, &infoUrl, &downloadUrl, &rls.Title, &rls.TorrentName, &category, &rls.Size, &groupId, &torrentId, &uploader, &rls.Timestamp); err != nil {
+	if err := row.Scan(&rls.ID, &rls.FilterStatus, pq.Array(&rls.Rejections), &indexerName, &filterName, &filterId, &rls.Protocol, &rls.Implementation, &infoUrl, &downloadUrl, &rls.Title, &rls.TorrentName, &category, &rls.Size, &groupId, &torrentId, &uploader, &notes, &userTags, &rls.Timestamp); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -439,13 +642,69 @@ func (repo *ReleaseRepo) Get(ctx context.Context, req *domain.GetReleaseRequest)
 	rls.GroupID = groupId.String
 	rls.TorrentID = torrentId.String
 	rls.Uploader = uploader.String
+	rls.Notes = notes.String
+	rls.UserTags = splitUserTags(userTags.String)
 
 	return &rls, nil
 }
 
+// UpdateAnnotation sets the notes and user tags of the release, the user tags are stored comma separated
+func (repo *ReleaseRepo) UpdateAnnotation(ctx context.Context, req *domain.ReleaseAnnotationReq) error {
+	queryBuilder := repo.db.squirrel.
+		Update("release").
+		Where(sq.Eq{"id": req.ReleaseID})
+
+	if req.Notes != nil {
+		queryBuilder = queryBuilder.Set("notes", *req.Notes)
+	}
+
+	if req.UserTags != nil {
+		queryBuilder = queryBuilder.Set("user_tags", strings.Join(*req.UserTags, ","))
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := repo.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	}
+
+	if rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func splitUserTags(userTags string) []string {
+	if userTags == "" {
+		return []string{}
+	}
+
+	return strings.Split(userTags, ",")
+}
+
+// userTagCondition matches the tag as a whole in the comma separated user tags
+func (repo *ReleaseRepo) userTagCondition(tag string) sq.Sqlizer {
+	return sq.Or{
+		repo.db.ILike("r.user_tags", tag),
+		repo.db.ILike("r.user_tags", tag+",%"),
+		repo.db.ILike("r.user_tags", "%,"+tag),
+		repo.db.ILike("r.user_tags", "%,"+tag+",%"),
+	}
+}
+
 func (repo *ReleaseRepo) GetActionStatus(ctx context.Context, req *domain.GetReleaseActionStatusRequest) (*domain.ReleaseActionStatus, error) {
 	queryBuilder := repo.db.squirrel.
-		Select("id", "status", "action", "action_id", "type", "client", "filter", "filter_id", "release_id", "rejections", "timestamp").
+		Select("id", "status", "action", "action_id", "type", "client", "filter", "filter_id", "release_id", "rejections", "timestamp", "reannounce", "completed_at", "removed_at").
 		From("release_action_status").
 		Where(sq.Eq{"id": req.Id})
 
@@ -461,10 +720,11 @@ func (repo *ReleaseRepo) GetActionStatus(ctx context.Context, req *domain.GetRel
 
 	var rls domain.ReleaseActionStatus
 
-	var client, filter sql.NullString
+	var client, filter, reannounce sql.NullString
 	var actionId, filterId sql.NullInt64
+	var completedAt, removedAt sql.NullTime
 
-	if err := row.Scan(&rls.ID, &rls.Status, &rls.Action, &actionId, &rls.Type, &client, &filter, &filterId, &rls.ReleaseID, pq.Array(&rls.Rejections), &rls.Timestamp); err != nil {
+	if err := row.Scan(&rls.ID, &rls.Status, &rls.Action, &actionId, &rls.Type, &client, &filter, &filterId, &rls.ReleaseID, pq.Array(&rls.Rejections), &rls.Timestamp, &reannounce, &completedAt, &removedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -476,6 +736,12 @@ func (repo *ReleaseRepo) GetActionStatus(ctx context.Context, req *domain.GetRel
 	rls.Client = client.String
 	rls.Filter = filter.String
 	rls.FilterID = filterId.Int64
+	rls.CompletedAt = nullTimePtr(completedAt)
+	rls.RemovedAt = nullTimePtr(removedAt)
+
+	if rls.Reannounce, err = unmarshalReannounce(reannounce.String); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal reannounce attempts")
+	}
 
 	return &rls, nil
 }
@@ -556,10 +822,54 @@ CROSS JOIN (
 	return &rls, nil
 }
 
-func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error {
+// GetPushLatencies returns the latency of approved pushes, newest first.
+// At most one more than domain.ReleasePushLatencySampleLimit is returned so callers can tell the sample was capped.
+func (repo *ReleaseRepo) GetPushLatencies(ctx context.Context, since time.Time) ([]domain.ReleasePushLatency, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("r.indexer", "ras.latency_ms", "ras.timestamp").
+		From("release_action_status ras").
+		Join("release r ON r.id = ras.release_id").
+		Where(sq.Eq{"ras.status": domain.ReleasePushStatusApproved}).
+		Where(sq.Gt{"ras.latency_ms": 0}).
+		Where(sq.GtOrEq{"ras.timestamp": repo.timestampArg(since)}).
+		OrderBy("ras.id DESC").
+		Limit(domain.ReleasePushLatencySampleLimit + 1)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	latencies := make([]domain.ReleasePushLatency, 0)
+	for rows.Next() {
+		var l domain.ReleasePushLatency
+
+		if err := rows.Scan(&l.Indexer, &l.LatencyMs, &l.Timestamp); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		latencies = append(latencies, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return latencies, nil
+}
+
+// Delete removes the releases matching the request with their action statuses, it returns the number of deleted releases
+func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error) {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
-		return errors.Wrap(err, "could not start transaction")
+		return 0, errors.Wrap(err, "could not start transaction")
 	}
 
 	defer func() {
@@ -603,18 +913,22 @@ func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRe
 		qb = qb.Where(sq.Eq{"indexer": req.Indexers})
 	}
 
+	if len(req.FilterIDs) > 0 {
+		qb = qb.Where(sq.Eq{"filter_id": req.FilterIDs})
+	}
+
 	if len(req.ReleaseStatuses) > 0 {
 		subQuery := sq.Select("release_id").From("release_action_status").Where(sq.Eq{"status": req.ReleaseStatuses})
 		subQueryText, subQueryArgs, err := subQuery.ToSql()
 		if err != nil {
-			return errors.Wrap(err, "error building subquery")
+			return 0, errors.Wrap(err, "error building subquery")
 		}
 		qb = qb.Where("id IN ("+subQueryText+")", subQueryArgs...)
 	}
 
 	query, args, err := qb.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "error building SQL query")
+		return 0, errors.Wrap(err, "error building SQL query")
 	}
 
 	repo.log.Trace().Str("query", query).Interface("args", args).Msg("Executing combined delete query")
@@ -622,12 +936,12 @@ func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRe
 	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		repo.log.Error().Err(err).Str("query", query).Interface("args", args).Msg("Error executing combined delete query")
-		return errors.Wrap(err, "error executing delete query")
+		return 0, errors.Wrap(err, "error executing delete query")
 	}
 
 	deletedRows, err := result.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error fetching rows affected")
+		return 0, errors.Wrap(err, "error fetching rows affected")
 	}
 
 	repo.log.Debug().Msgf("deleted %d rows from release table", deletedRows)
@@ -635,17 +949,17 @@ func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRe
 	// clean up orphaned rows
 	orphanedResult, err := tx.ExecContext(ctx, `DELETE FROM release_action_status WHERE release_id NOT IN (SELECT id FROM "release")`)
 	if err != nil {
-		return errors.Wrap(err, "error executing query")
+		return 0, errors.Wrap(err, "error executing query")
 	}
 
 	deletedRowsOrphaned, err := orphanedResult.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error fetching rows affected")
+		return 0, errors.Wrap(err, "error fetching rows affected")
 	}
 
 	repo.log.Debug().Msgf("deleted %d orphaned rows from release table", deletedRowsOrphaned)
 
-	return nil
+	return deletedRows, nil
 }
 
 func (repo *ReleaseRepo) CheckSmartEpisodeCanDownload(ctx context.Context, p *domain.SmartEpisodeParams) (bool, error) {
@@ -658,6 +972,10 @@ func (repo *ReleaseRepo) CheckSmartEpisodeCanDownload(ctx context.Context, p *do
 			sq.Eq{"ras.status": "PUSH_APPROVED"},
 		})
 
+	if p.WithinDays > 0 {
+		queryBuilder = queryBuilder.Where(repo.pushedWithinDays(p.WithinDays))
+	}
+
 	if p.Proper {
 		queryBuilder = queryBuilder.Where(sq.Eq{"r.proper": p.Proper})
 	}
@@ -669,14 +987,21 @@ func (repo *ReleaseRepo) CheckSmartEpisodeCanDownload(ctx context.Context, p *do
 	}
 
 	if p.Season > 0 && p.Episode > 0 {
-		queryBuilder = queryBuilder.Where(sq.Or{
+		newer := sq.Or{
 			sq.And{
 				sq.Eq{"r.season": p.Season},
 				sq.Gt{"r.episode": p.Episode},
 			},
 			sq.Gt{"r.season": p.Season},
-		})
-	} else if p.Season > 0 && p.Episode == 0 {
+		}
+
+		// the episode is part of a season pack already pushed
+		if p.SeasonPacks {
+			newer = append(newer, sq.Eq{"r.season": p.Season, "r.episode": 0})
+		}
+
+		queryBuilder = queryBuilder.Where(newer)
+	} else if p.IsSeasonPack() {
 		queryBuilder = queryBuilder.Where(sq.Gt{"r.season": p.Season})
 	} else if p.Year > 0 && p.Month > 0 && p.Day > 0 {
 		queryBuilder = queryBuilder.Where(sq.Or{
@@ -722,6 +1047,384 @@ func (repo *ReleaseRepo) CheckSmartEpisodeCanDownload(ctx context.Context, p *do
 	return true, nil
 }
 
+// timestampArg returns the time to compare the timestamp columns with, sqlite stores them as text
+func (repo *ReleaseRepo) timestampArg(t time.Time) any {
+	if repo.db.Driver == "sqlite" {
+		return t.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	return t
+}
+
+// pushedWithinDays limits the release action status to the last days
+func (repo *ReleaseRepo) pushedWithinDays(days int) sq.Sqlizer {
+	if repo.db.Driver == "sqlite" {
+		return sq.Expr(fmt.Sprintf("ras.timestamp >= strftime('%%Y-%%m-%%dT%%H:%%M:%%S', datetime('now','-%d days'))", days))
+	}
+
+	return sq.GtOrEq{"ras.timestamp": time.Now().AddDate(0, 0, -days)}
+}
+
+// CheckSmartMusicCanDownload reports whether no release of the same album was pushed before.
+// Releases differing in one of the match fields of the params are not duplicates.
+func (repo *ReleaseRepo) CheckSmartMusicCanDownload(ctx context.Context, p *domain.SmartMusicParams) (bool, error) {
+	if p.Title == "" {
+		return true, nil
+	}
+
+	queryBuilder := repo.db.squirrel.
+		Select("COUNT(*)").
+		From("release r").
+		LeftJoin("release_action_status ras ON r.id = ras.release_id").
+		Where(sq.And{
+			repo.db.ILike("r.title", p.Title),
+			sq.Eq{"ras.status": "PUSH_APPROVED"},
+		})
+
+	if p.Artists != "" {
+		queryBuilder = queryBuilder.Where(repo.db.ILike("r.artists", p.Artists))
+	}
+	if p.Year > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.year": p.Year})
+	}
+	if p.WithinDays > 0 {
+		queryBuilder = queryBuilder.Where(repo.pushedWithinDays(p.WithinDays))
+	}
+	if p.Matches(domain.SmartMusicMatchFormat) {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.audio_format": p.Format})
+	}
+	if p.Matches(domain.SmartMusicMatchBitrate) {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.bitrate": p.Bitrate})
+	}
+	if p.Matches(domain.SmartMusicMatchMedia) {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.source": p.Media})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	repo.log.Trace().Str("method", "CheckSmartMusicCanDownload").Str("query", query).Interface("args", args).Msgf("executing query")
+
+	var count int
+
+	if err := repo.db.handler.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, errors.Wrap(err, "error scanning row")
+	}
+
+	return count == 0, nil
+}
+
+// FindUpgradeOriginals finds the torrents pushed to clients for the release a PROPER or REPACK replaces.
+// Other PROPER or REPACK releases are never replaced.
+func (repo *ReleaseRepo) FindUpgradeOriginals(ctx context.Context, p *domain.ReleaseUpgradeParams) ([]domain.ReleaseUpgradeOriginal, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("DISTINCT r.id", "r.torrent_name", "a.client_id", "ras.info_hash").
+		From("release r").
+		Join("release_action_status ras ON r.id = ras.release_id").
+		Join("action a ON a.id = ras.action_id").
+		Where(sq.And{
+			repo.db.ILike("r.title", p.Title),
+			sq.Eq{"ras.status": "PUSH_APPROVED"},
+			sq.NotEq{"ras.info_hash": nil},
+			sq.NotEq{"ras.info_hash": ""},
+			sq.Eq{"r.proper": false},
+			sq.Eq{"r.repack": false},
+			sq.Eq{"r.season": p.Season},
+			sq.Eq{"r.episode": p.Episode},
+			sq.Eq{"r.year": p.Year},
+			sq.Eq{"r.month": p.Month},
+			sq.Eq{"r.day": p.Day},
+			sq.Eq{"r.resolution": p.Resolution},
+			sq.Eq{"r.source": p.Source},
+		})
+
+	if p.Group != "" {
+		queryBuilder = queryBuilder.Where(repo.db.ILike("r.release_group", p.Group))
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	repo.log.Trace().Str("method", "FindUpgradeOriginals").Str("query", query).Interface("args", args).Msgf("executing query")
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+	defer rows.Close()
+
+	var originals []domain.ReleaseUpgradeOriginal
+	for rows.Next() {
+		var original domain.ReleaseUpgradeOriginal
+		var clientID sql.NullInt32
+
+		if err := rows.Scan(&original.ReleaseID, &original.TorrentName, &clientID, &original.InfoHash); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		original.ClientID = clientID.Int32
+		originals = append(originals, original)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return originals, nil
+}
+
+// CheckInfoHashPushed reports if a torrent with the infohash was pushed to a client for another release
+func (repo *ReleaseRepo) CheckInfoHashPushed(ctx context.Context, infoHash string, excludeReleaseID int64) (bool, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("COUNT(*)").
+		From("release_action_status").
+		Where(sq.And{
+			sq.Eq{"status": "PUSH_APPROVED"},
+			repo.db.ILike("info_hash", infoHash),
+			sq.NotEq{"release_id": excludeReleaseID},
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	repo.log.Trace().Str("method", "CheckInfoHashPushed").Str("query", query).Interface("args", args).Msgf("executing query")
+
+	var count int
+
+	if err := repo.db.handler.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, errors.Wrap(err, "error scanning row")
+	}
+
+	return count > 0, nil
+}
+
+// FindTrackedPushes returns the torrents pushed to a client since the time that did not finish downloading yet, newest first.
+// The client is looked up by the name stored on push since failover might have used another client than the one of the action.
+func (repo *ReleaseRepo) FindTrackedPushes(ctx context.Context, since time.Time) ([]domain.ReleaseTrackedPush, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("ras.id", "ras.release_id", "r.torrent_name", "r.size", "r.indexer", "ras.filter", "ras.action_id", "ras.action", "ras.type", "COALESCE((SELECT MIN(c.id) FROM client c WHERE c.name = ras.client), a.client_id)", "ras.client", "ras.info_hash", "ras.timestamp").
+		From("release_action_status ras").
+		Join("release r ON r.id = ras.release_id").
+		LeftJoin("action a ON a.id = ras.action_id").
+		Where(sq.And{
+			sq.Eq{"ras.status": domain.ReleasePushStatusApproved},
+			sq.NotEq{"ras.info_hash": nil},
+			sq.NotEq{"ras.info_hash": ""},
+			sq.Eq{"ras.completed_at": nil},
+			sq.GtOrEq{"ras.timestamp": repo.timestampArg(since)},
+		}).
+		OrderBy("ras.id DESC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	repo.log.Trace().Str("method", "FindTrackedPushes").Str("query", query).Interface("args", args).Msgf("executing query")
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+	defer rows.Close()
+
+	pushes := make([]domain.ReleaseTrackedPush, 0)
+	for rows.Next() {
+		var push domain.ReleaseTrackedPush
+		var indexer, filter, client sql.NullString
+		var actionID sql.NullInt64
+		var clientID sql.NullInt32
+
+		if err := rows.Scan(&push.ActionStatusID, &push.ReleaseID, &push.TorrentName, &push.Size, &indexer, &filter, &actionID, &push.Action, &push.ActionType, &clientID, &client, &push.InfoHash, &push.Timestamp); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		push.Indexer = indexer.String
+		push.Filter = filter.String
+		push.ActionID = actionID.Int64
+		push.ClientID = clientID.Int32
+		push.Client = client.String
+
+		pushes = append(pushes, push)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return pushes, nil
+}
+
+// FindFailedActionStatuses returns the PUSH_ERROR statuses of actions that still exist matching the request, newest first
+func (repo *ReleaseRepo) FindFailedActionStatuses(ctx context.Context, req *domain.ReleaseRetryFailedReq) ([]domain.ReleaseActionStatus, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("ras.id", "ras.status", "ras.action", "ras.action_id", "ras.type", "ras.client", "ras.filter", "ras.filter_id", "ras.release_id", "ras.rejections", "ras.timestamp").
+		From("release_action_status ras").
+		Join("release r ON r.id = ras.release_id").
+		Join("action a ON a.id = ras.action_id").
+		Where(sq.Eq{"ras.status": domain.ReleasePushStatusErr}).
+		OrderBy("ras.id DESC").
+		Limit(domain.ReleaseRetryFailedLimit)
+
+	if req.Client != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"ras.client": req.Client})
+	}
+
+	if req.Indexer != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.indexer": req.Indexer})
+	}
+
+	if req.MaxAge > 0 {
+		since := time.Now().Add(-time.Duration(req.MaxAge) * time.Hour)
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"ras.timestamp": repo.timestampArg(since)})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+	defer rows.Close()
+
+	statuses := make([]domain.ReleaseActionStatus, 0)
+	for rows.Next() {
+		var status domain.ReleaseActionStatus
+		var client, filter sql.NullString
+		var filterID sql.NullInt64
+
+		if err := rows.Scan(&status.ID, &status.Status, &status.Action, &status.ActionID, &status.Type, &client, &filter, &filterID, &status.ReleaseID, pq.Array(&status.Rejections), &status.Timestamp); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		status.Client = client.String
+		status.Filter = filter.String
+		status.FilterID = filterID.Int64
+
+		statuses = append(statuses, status)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return statuses, nil
+}
+
+// SetActionStatusCompleted stores when the client finished downloading the torrent of the action status
+func (repo *ReleaseRepo) SetActionStatusCompleted(ctx context.Context, id int64, completedAt time.Time) error {
+	queryBuilder := repo.db.squirrel.
+		Update("release_action_status").
+		Set("completed_at", completedAt.Format(time.RFC3339)).
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := repo.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// FindRemovablePushes returns the torrents pushed by actions with a removal policy that were not removed from the client yet.
+// The client is looked up by the name stored on push since failover might have used another client than the one of the action.
+func (repo *ReleaseRepo) FindRemovablePushes(ctx context.Context) ([]domain.ReleaseRemovablePush, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("ras.id", "ras.release_id", "r.torrent_name", "ras.action", "COALESCE((SELECT MIN(c.id) FROM client c WHERE c.name = ras.client), a.client_id)", "ras.client", "ras.info_hash", "ras.timestamp", "a.remove_ratio", "a.remove_seed_time", "a.remove_after_days", "a.remove_delete_data").
+		From("release_action_status ras").
+		Join("release r ON r.id = ras.release_id").
+		Join("action a ON a.id = ras.action_id").
+		Where(sq.And{
+			sq.Eq{"ras.status": domain.ReleasePushStatusApproved},
+			sq.NotEq{"ras.info_hash": nil},
+			sq.NotEq{"ras.info_hash": ""},
+			sq.Eq{"ras.removed_at": nil},
+			sq.Or{
+				sq.Gt{"a.remove_ratio": 0},
+				sq.Gt{"a.remove_seed_time": 0},
+				sq.Gt{"a.remove_after_days": 0},
+			},
+		}).
+		OrderBy("ras.id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	repo.log.Trace().Str("method", "FindRemovablePushes").Str("query", query).Interface("args", args).Msgf("executing query")
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+	defer rows.Close()
+
+	pushes := make([]domain.ReleaseRemovablePush, 0)
+	for rows.Next() {
+		var push domain.ReleaseRemovablePush
+		var client sql.NullString
+		var clientID sql.NullInt32
+
+		if err := rows.Scan(&push.ActionStatusID, &push.ReleaseID, &push.TorrentName, &push.Action, &clientID, &client, &push.InfoHash, &push.Timestamp, &push.RemoveRatio, &push.RemoveSeedTime, &push.RemoveAfterDays, &push.RemoveDeleteData); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		push.ClientID = clientID.Int32
+		push.Client = client.String
+
+		pushes = append(pushes, push)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return pushes, nil
+}
+
+// SetActionStatusRemoved stores when the torrent of the action status was removed from the client
+func (repo *ReleaseRepo) SetActionStatusRemoved(ctx context.Context, id int64, removedAt time.Time) error {
+	queryBuilder := repo.db.squirrel.
+		Update("release_action_status").
+		Set("removed_at", removedAt.Format(time.RFC3339)).
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := repo.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
 func (repo *ReleaseRepo) UpdateBaseURL(ctx context.Context, indexer string, oldBaseURL, newBaseURL string) error {
 	tx, err := repo.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -771,3 +1474,30 @@ func (repo *ReleaseRepo) UpdateBaseURL(ctx context.Context, indexer string, oldB
 
 	return nil
 }
+
+// marshalReannounce returns the reannounce attempts as json, null when there are none
+func marshalReannounce(attempts []domain.ReannounceAttempt) (sql.Null[string], error) {
+	if len(attempts) == 0 {
+		return sql.Null[string]{}, nil
+	}
+
+	data, err := json.Marshal(attempts)
+	if err != nil {
+		return sql.Null[string]{}, err
+	}
+
+	return toNullString(string(data)), nil
+}
+
+func unmarshalReannounce(data string) ([]domain.ReannounceAttempt, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var attempts []domain.ReannounceAttempt
+	if err := json.Unmarshal([]byte(data), &attempts); err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type ReleasePendingRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewReleasePendingRepo(log logger.Logger, db *DB) domain.ReleasePendingRepo {
+	return &ReleasePendingRepo{
+		log: log.With().Str("repo", "release_pending").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ReleasePendingRepo) selectPending() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select("p.id", "p.type", "p.release_id", "p.filter_id", "f.name", "p.action_id", "a.name", "rl.torrent_name", "rl.indexer", "p.run_at", "p.created_at").
+		From("release_pending p").
+		Join(`"release" rl ON rl.id = p.release_id`).
+		LeftJoin("filter f ON f.id = p.filter_id").
+		LeftJoin("action a ON a.id = p.action_id")
+}
+
+func scanPending(row interface{ Scan(dest ...any) error }) (*domain.ReleasePending, error) {
+	var p domain.ReleasePending
+	var filterName, actionName, indexer sql.NullString
+	var actionID sql.NullInt32
+
+	if err := row.Scan(&p.ID, &p.Type, &p.ReleaseID, &p.FilterID, &filterName, &actionID, &actionName, &p.TorrentName, &indexer, &p.RunAt, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	p.FilterName = filterName.String
+	p.ActionID = int(actionID.Int32)
+	p.ActionName = actionName.String
+	p.Indexer = indexer.String
+
+	return &p, nil
+}
+
+// List returns the pending releases of the type ordered by run at, all of them when the type is empty
+func (r *ReleasePendingRepo) List(ctx context.Context, pendingType domain.ReleasePendingType) ([]*domain.ReleasePending, error) {
+	queryBuilder := r.selectPending().OrderBy("p.run_at ASC")
+
+	if pendingType != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"p.type": pendingType})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	pending := make([]*domain.ReleasePending, 0)
+
+	for rows.Next() {
+		p, err := scanPending(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		pending = append(pending, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return pending, nil
+}
+
+func (r *ReleasePendingRepo) Get(ctx context.Context, id int64) (*domain.ReleasePending, error) {
+	query, args, err := r.selectPending().Where(sq.Eq{"p.id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	p, err := scanPending(r.db.handler.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	return p, nil
+}
+
+func (r *ReleasePendingRepo) Counts(ctx context.Context) (*domain.ReleasePendingCounts, error) {
+	queryBuilder := r.db.squirrel.
+		Select("type", "COUNT(*)").
+		From("release_pending").
+		GroupBy("type")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	counts := &domain.ReleasePendingCounts{}
+
+	for rows.Next() {
+		var pendingType domain.ReleasePendingType
+		var count int
+
+		if err := rows.Scan(&pendingType, &count); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		switch pendingType {
+		case domain.ReleasePendingTypeDelay:
+			counts.Delay = count
+		case domain.ReleasePendingTypeApproval:
+			counts.Approval = count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return counts, nil
+}
+
+func (r *ReleasePendingRepo) Store(ctx context.Context, pending *domain.ReleasePending) error {
+	queryBuilder := r.db.squirrel.
+		Insert("release_pending").
+		Columns("type", "release_id", "filter_id", "action_id", "run_at", "created_at").
+		Values(pending.Type, pending.ReleaseID, pending.FilterID, toNullInt32(int32(pending.ActionID)), pending.RunAt, pending.CreatedAt).
+		Suffix("RETURNING id").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&pending.ID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *ReleasePendingRepo) Delete(ctx context.Context, id int64) error {
+	queryBuilder := r.db.squirrel.
+		Delete("release_pending").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	}
+
+	if rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
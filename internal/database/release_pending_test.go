@@ -0,0 +1,149 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleasePendingRepo(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		releaseRepo := NewReleaseRepo(log, db)
+		repo := NewReleasePendingRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_List_Delete [%s]", dbType), func(t *testing.T) {
+			// Setup
+			err := filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			release := getMockRelease()
+			release.FilterID = createdFilters[0].ID
+			release.FilterName = createdFilters[0].Name
+			err = releaseRepo.Store(context.Background(), release)
+			assert.NoError(t, err)
+
+			runAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+			later := domain.NewReleasePending(release, runAt)
+			err = repo.Store(context.Background(), later)
+			assert.NoError(t, err)
+			assert.NotZero(t, later.ID)
+
+			sooner := domain.NewReleasePending(release, runAt.Add(-time.Minute))
+			err = repo.Store(context.Background(), sooner)
+			assert.NoError(t, err)
+
+			pending, err := repo.List(context.Background(), "")
+			assert.NoError(t, err)
+			if assert.Len(t, pending, 2) {
+				assert.Equal(t, sooner.ID, pending[0].ID)
+				assert.Equal(t, release.ID, pending[0].ReleaseID)
+				assert.Equal(t, createdFilters[0].Name, pending[0].FilterName)
+				assert.Equal(t, release.TorrentName, pending[0].TorrentName)
+				assert.Equal(t, release.Indexer.Identifier, pending[0].Indexer)
+				assert.True(t, runAt.Equal(pending[1].RunAt))
+			}
+
+			err = repo.Delete(context.Background(), sooner.ID)
+			assert.NoError(t, err)
+
+			err = repo.Delete(context.Background(), sooner.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			pending, err = repo.List(context.Background(), "")
+			assert.NoError(t, err)
+			assert.Len(t, pending, 1)
+
+			// deleting the release drops the pending release with it
+			_, err = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			assert.NoError(t, err)
+
+			pending, err = repo.List(context.Background(), "")
+			assert.NoError(t, err)
+			assert.Empty(t, pending)
+
+			// Cleanup
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+		})
+
+		t.Run(fmt.Sprintf("Approval_Get_Counts [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData := getMockAction()
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			actionMockData.RequireApproval = true
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			action, err := actionRepo.Get(context.Background(), &domain.GetActionRequest{Id: createdAction.ID})
+			assert.NoError(t, err)
+			assert.True(t, action.RequireApproval)
+
+			release := getMockRelease()
+			release.FilterID = createdFilters[0].ID
+			err = releaseRepo.Store(context.Background(), release)
+			assert.NoError(t, err)
+
+			delayed := domain.NewReleasePending(release, time.Now().Add(time.Hour))
+			err = repo.Store(context.Background(), delayed)
+			assert.NoError(t, err)
+
+			approval := domain.NewReleasePendingApproval(release, action)
+			err = repo.Store(context.Background(), approval)
+			assert.NoError(t, err)
+
+			pending, err := repo.Get(context.Background(), approval.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, domain.ReleasePendingTypeApproval, pending.Type)
+			assert.Equal(t, action.ID, pending.ActionID)
+			assert.Equal(t, action.Name, pending.ActionName)
+
+			approvals, err := repo.List(context.Background(), domain.ReleasePendingTypeApproval)
+			assert.NoError(t, err)
+			assert.Len(t, approvals, 1)
+
+			counts, err := repo.Counts(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, &domain.ReleasePendingCounts{Delay: 1, Approval: 1}, counts)
+
+			_, err = repo.Get(context.Background(), approval.ID+100)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			// Cleanup
+			_, _ = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
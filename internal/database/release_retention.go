@@ -0,0 +1,243 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+// the retention config is a single row
+const releaseRetentionID = 1
+
+type ReleaseRetentionRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewReleaseRetentionRepo(log logger.Logger, db *DB) domain.ReleaseRetentionRepo {
+	return &ReleaseRetentionRepo{
+		log: log.With().Str("repo", "release_retention").Logger(),
+		db:  db,
+	}
+}
+
+// Get returns the retention config, or the defaults if never saved
+func (r *ReleaseRetentionRepo) Get(ctx context.Context) (*domain.ReleaseRetention, error) {
+	queryBuilder := r.db.squirrel.
+		Select("enabled", "max_age_days", "max_rows_per_indexer", "schedule", "last_run", "last_deleted").
+		From("release_retention").
+		Where(sq.Eq{"id": releaseRetentionID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	retention := domain.NewReleaseRetention()
+
+	var lastRun sql.NullTime
+
+	if err := r.db.handler.QueryRowContext(ctx, query, args...).Scan(&retention.Enabled, &retention.MaxAgeDays, &retention.MaxRowsPerIndexer, &retention.Schedule, &lastRun, &retention.LastDeleted); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return retention, nil
+		}
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	if lastRun.Valid {
+		retention.LastRun = &lastRun.Time
+	}
+
+	return retention, nil
+}
+
+func (r *ReleaseRetentionRepo) Update(ctx context.Context, retention *domain.ReleaseRetention) error {
+	queryBuilder := r.db.squirrel.
+		Insert("release_retention").
+		Columns("id", "enabled", "max_age_days", "max_rows_per_indexer", "schedule", "updated_at").
+		Values(releaseRetentionID, retention.Enabled, retention.MaxAgeDays, retention.MaxRowsPerIndexer, retention.Schedule, time.Now()).
+		Suffix("ON CONFLICT (id) DO UPDATE SET enabled = EXCLUDED.enabled, max_age_days = EXCLUDED.max_age_days, max_rows_per_indexer = EXCLUDED.max_rows_per_indexer, schedule = EXCLUDED.schedule, updated_at = EXCLUDED.updated_at")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *ReleaseRetentionRepo) StoreRun(ctx context.Context, runAt time.Time, deleted int64) error {
+	queryBuilder := r.db.squirrel.
+		Insert("release_retention").
+		Columns("id", "last_run", "last_deleted").
+		Values(releaseRetentionID, runAt, deleted).
+		Suffix("ON CONFLICT (id) DO UPDATE SET last_run = EXCLUDED.last_run, last_deleted = EXCLUDED.last_deleted")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// Prune deletes releases older than maxAge and above maxRowsPerIndexer newest per indexer, 0 disables each limit.
+// The action statuses are deleted first so the counts don't depend on the foreign keys cascading.
+func (r *ReleaseRetentionRepo) Prune(ctx context.Context, maxAge time.Duration, maxRowsPerIndexer int) (*domain.ReleasePruneResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start transaction")
+	}
+
+	defer tx.Rollback()
+
+	result := &domain.ReleasePruneResult{}
+
+	if maxAge > 0 {
+		var olderThan sq.Sqlizer
+
+		if r.db.Driver == "sqlite" {
+			olderThan = sq.Expr(fmt.Sprintf("timestamp < strftime('%%Y-%%m-%%dT%%H:00:00', datetime('now','-%d hours'))", int(maxAge.Hours())))
+		} else {
+			olderThan = sq.Lt{"timestamp": time.Now().Add(-maxAge)}
+		}
+
+		subQuery := r.db.squirrel.Select("id").From(`"release"`).Where(olderThan)
+
+		statuses, releases, err := r.deleteReleases(ctx, tx, subQuery)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not prune releases by age")
+		}
+
+		result.ActionStatusDeleted += statuses
+		result.ReleasesByAge = releases
+	}
+
+	if maxRowsPerIndexer > 0 {
+		ranked := r.db.squirrel.
+			Select("id", "ROW_NUMBER() OVER (PARTITION BY indexer ORDER BY timestamp DESC, id DESC) AS rn").
+			From(`"release"`)
+
+		subQuery := r.db.squirrel.Select("id").FromSelect(ranked, "ranked").Where(sq.Gt{"rn": maxRowsPerIndexer})
+
+		statuses, releases, err := r.deleteReleases(ctx, tx, subQuery)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not prune releases by row limit")
+		}
+
+		result.ActionStatusDeleted += statuses
+		result.ReleasesByRowLimit = releases
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "error committing transaction")
+	}
+
+	return result, nil
+}
+
+// deleteReleases deletes the releases with ids from subQuery and their action statuses
+func (r *ReleaseRetentionRepo) deleteReleases(ctx context.Context, tx *Tx, subQuery sq.SelectBuilder) (int64, int64, error) {
+	subQueryText, subQueryArgs, err := subQuery.ToSql()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "error building subquery")
+	}
+
+	ids, err := r.selectIDs(ctx, tx, subQueryText, subQueryArgs)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+
+	var statuses, releases int64
+
+	for _, chunk := range chunkIDs(ids, 500) {
+		res, err := r.exec(ctx, tx, r.db.squirrel.Delete("release_action_status").Where(sq.Eq{"release_id": chunk}))
+		if err != nil {
+			return 0, 0, err
+		}
+		statuses += res
+
+		res, err = r.exec(ctx, tx, r.db.squirrel.Delete(`"release"`).Where(sq.Eq{"id": chunk}))
+		if err != nil {
+			return 0, 0, err
+		}
+		releases += res
+	}
+
+	return statuses, releases, nil
+}
+
+func (r *ReleaseRetentionRepo) selectIDs(ctx context.Context, tx *Tx, query string, args []interface{}) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return ids, nil
+}
+
+func (r *ReleaseRetentionRepo) exec(ctx context.Context, tx *Tx, queryBuilder sq.DeleteBuilder) (int64, error) {
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error fetching rows affected")
+	}
+
+	return rows, nil
+}
+
+func chunkIDs(ids []int64, size int) [][]int64 {
+	chunks := make([][]int64, 0, len(ids)/size+1)
+	for size < len(ids) {
+		ids, chunks = ids[size:], append(chunks, ids[:size:size])
+	}
+
+	return append(chunks, ids)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseRetentionRepo_Settings(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewReleaseRetentionRepo(log, db)
+
+		t.Run(fmt.Sprintf("Get_Default [%s]", dbType), func(t *testing.T) {
+			_, _ = db.handler.Exec("DELETE FROM release_retention")
+
+			retention, err := repo.Get(context.Background())
+			assert.NoError(t, err)
+			assert.False(t, retention.Enabled)
+			assert.Equal(t, domain.DefaultReleaseRetentionSchedule, retention.Schedule)
+			assert.Nil(t, retention.LastRun)
+		})
+
+		t.Run(fmt.Sprintf("Update_And_StoreRun [%s]", dbType), func(t *testing.T) {
+			err := repo.Update(context.Background(), &domain.ReleaseRetention{
+				Enabled:           true,
+				MaxAgeDays:        90,
+				MaxRowsPerIndexer: 1000,
+				Schedule:          "0 4 * * *",
+			})
+			assert.NoError(t, err)
+
+			err = repo.StoreRun(context.Background(), time.Now(), 12)
+			assert.NoError(t, err)
+
+			retention, err := repo.Get(context.Background())
+			assert.NoError(t, err)
+			assert.True(t, retention.Enabled)
+			assert.Equal(t, 90, retention.MaxAgeDays)
+			assert.Equal(t, 1000, retention.MaxRowsPerIndexer)
+			assert.Equal(t, "0 4 * * *", retention.Schedule)
+			assert.NotNil(t, retention.LastRun)
+			assert.Equal(t, int64(12), retention.LastDeleted)
+
+			// Cleanup
+			_, _ = db.handler.Exec("DELETE FROM release_retention")
+		})
+	}
+}
+
+func TestReleaseRetentionRepo_Prune(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		repo := NewReleaseRetentionRepo(log, db)
+		releaseRepo := NewReleaseRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+
+		t.Run(fmt.Sprintf("Prune_MaxRowsPerIndexer [%s]", dbType), func(t *testing.T) {
+			mockFilter := getMockFilter()
+			err := filterRepo.Store(context.Background(), mockFilter)
+			assert.NoError(t, err)
+
+			for i := 0; i < 3; i++ {
+				rls := getMockRelease()
+				rls.FilterID = mockFilter.ID
+				rls.Timestamp = time.Now().Add(-time.Duration(i) * time.Minute)
+
+				err := releaseRepo.Store(context.Background(), rls)
+				assert.NoError(t, err)
+			}
+
+			result, err := repo.Prune(context.Background(), 0, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), result.ReleasesByRowLimit)
+			assert.Equal(t, int64(0), result.ReleasesByAge)
+
+			// nothing left to prune
+			result, err = repo.Prune(context.Background(), 0, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(0), result.Total())
+
+			// Cleanup
+			_, _ = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = filterRepo.Delete(context.Background(), mockFilter.ID)
+		})
+	}
+}
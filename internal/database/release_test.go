@@ -122,7 +122,7 @@ func TestReleaseRepo_Store(t *testing.T) {
 			assert.NotEqual(t, int64(0), mockData.ID)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
@@ -178,7 +178,7 @@ func TestReleaseRepo_StoreReleaseActionStatus(t *testing.T) {
 			assert.NotEqual(t, int64(0), releaseActionMockData.ID)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
@@ -192,7 +192,7 @@ func TestReleaseRepo_Find(t *testing.T) {
 
 		downloadClientRepo := NewDownloadClientRepo(log, db)
 		filterRepo := NewFilterRepo(log, db)
-		//actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
 		repo := NewReleaseRepo(log, db)
 
 		mockData := getMockRelease()
@@ -239,7 +239,182 @@ func TestReleaseRepo_Find(t *testing.T) {
 			assert.True(t, resp.NextCursor >= 0)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+
+		t.Run(fmt.Sprintf("FindReleases_Advanced_Filters [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			type pushed struct {
+				status domain.ReleasePushStatus
+				client string
+			}
+
+			store := func(resolution string, size uint64, pushes ...pushed) *domain.Release {
+				release := getMockRelease()
+				release.FilterID = createdFilters[0].ID
+				release.Resolution = resolution
+				release.Source = "UHD.BluRay"
+				release.Codec = []string{"HEVC"}
+				release.HDR = []string{"DV", "HDR10"}
+				release.Group = "FraMeSToR"
+				release.Size = size
+
+				err := repo.Store(context.Background(), release)
+				assert.NoError(t, err)
+
+				for _, p := range pushes {
+					status := getMockReleaseActionStatus()
+					status.ReleaseID = release.ID
+					status.ActionID = int64(createdAction.ID)
+					status.FilterID = int64(createdFilters[0].ID)
+					status.Status = p.status
+					status.Client = p.client
+
+					err = repo.StoreReleaseActionStatus(context.Background(), status)
+					assert.NoError(t, err)
+				}
+
+				return release
+			}
+
+			want := store("2160p", 60_000_000_000, pushed{domain.ReleasePushStatusApproved, "qBittorrent"})
+			store("2160p", 60_000_000_000, pushed{domain.ReleasePushStatusApproved, "Deluge"})
+			store("2160p", 60_000_000_000, pushed{domain.ReleasePushStatusApproved, "Deluge"}, pushed{domain.ReleasePushStatusRejected, "qBittorrent"})
+			store("2160p", 20_000_000_000, pushed{domain.ReleasePushStatusApproved, "qBittorrent"})
+			store("1080p", 60_000_000_000, pushed{domain.ReleasePushStatusApproved, "qBittorrent"})
+
+			queryParams := domain.ReleaseQueryParams{Limit: 10}
+			queryParams.Filters.Resolutions = []string{"2160p"}
+			queryParams.Filters.Sources = []string{"UHD.BluRay"}
+			queryParams.Filters.Codecs = []string{"HEVC"}
+			queryParams.Filters.HDR = []string{"DV"}
+			queryParams.Filters.ReleaseGroups = []string{"FraMeSToR"}
+			queryParams.Filters.MinSize = 50_000_000_000
+			queryParams.Filters.PushStatus = string(domain.ReleasePushStatusApproved)
+			queryParams.Filters.Clients = []string{"qBittorrent"}
+			queryParams.Filters.From = time.Now().AddDate(0, -1, 0)
+
+			// Execute
+			resp, err := repo.Find(context.Background(), queryParams)
+
+			// Verify
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), resp.TotalCount)
+			if assert.Len(t, resp.Data, 1) {
+				assert.Equal(t, want.ID, resp.Data[0].ID)
+			}
+
+			queryParams.Filters.To = time.Now().AddDate(0, 0, -1)
+
+			resp, err = repo.Find(context.Background(), queryParams)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(0), resp.TotalCount)
+			assert.Len(t, resp.Data, 0)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
+
+func TestReleaseRepo_Export(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		mockData := getMockRelease()
+		releaseActionMockData := getMockReleaseActionStatus()
+		actionMockData := getMockAction()
+
+		t.Run(fmt.Sprintf("Export_Succeeds [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+			assert.NotNil(t, mock)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			mockData.FilterID = createdFilters[0].ID
+
+			err = repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			releaseActionMockData.ReleaseID = mockData.ID
+			releaseActionMockData.ActionID = int64(createdAction.ID)
+			releaseActionMockData.FilterID = int64(createdFilters[0].ID)
+
+			err = repo.StoreReleaseActionStatus(context.Background(), releaseActionMockData)
+			assert.NoError(t, err)
+
+			// Execute
+			var exported []*domain.Release
+			err = repo.Export(context.Background(), domain.ReleaseExportParams{PushStatus: string(releaseActionMockData.Status)}, func(release *domain.Release) error {
+				exported = append(exported, release)
+				return nil
+			})
+
+			// Verify
+			assert.NoError(t, err)
+			assert.Len(t, exported, 1)
+			assert.Equal(t, mockData.ID, exported[0].ID)
+			assert.Len(t, exported[0].ActionStatus, 1)
+			assert.Equal(t, releaseActionMockData.ID, exported[0].ActionStatus[0].ID)
+
+			exported = nil
+			err = repo.Export(context.Background(), domain.ReleaseExportParams{From: mockData.Timestamp.Add(-time.Hour), To: mockData.Timestamp.Add(time.Hour)}, func(release *domain.Release) error {
+				exported = append(exported, release)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Len(t, exported, 1)
+
+			exported = nil
+			err = repo.Export(context.Background(), domain.ReleaseExportParams{From: mockData.Timestamp.Add(-2 * time.Hour), To: mockData.Timestamp.Add(-time.Hour)}, func(release *domain.Release) error {
+				exported = append(exported, release)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Len(t, exported, 0)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
 		})
@@ -286,9 +461,12 @@ func TestReleaseRepo_FindRecent(t *testing.T) {
 			// Verify
 			assert.NotNil(t, resp.Data)
 			assert.Lenf(t, resp.Data, 1, "Expected 1 release, got %d", len(resp.Data))
+			assert.Equal(t, mockData.Uploader, resp.Data[0].Uploader)
+			assert.Equal(t, mockData.Origin, resp.Data[0].Origin)
+			assert.Equal(t, mockData.Tags, resp.Data[0].Tags)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
 		})
@@ -346,7 +524,7 @@ func TestReleaseRepo_GetIndexerOptions(t *testing.T) {
 			assert.Len(t, options, 1)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
@@ -393,6 +571,10 @@ func TestReleaseRepo_GetActionStatusByReleaseID(t *testing.T) {
 			releaseActionMockData.ReleaseID = mockData.ID
 			releaseActionMockData.ActionID = int64(createdAction.ID)
 			releaseActionMockData.FilterID = int64(createdFilters[0].ID)
+			releaseActionMockData.Reannounce = []domain.ReannounceAttempt{
+				{Attempt: 1, Timestamp: time.Now().UTC().Truncate(time.Second), Message: "unregistered torrent"},
+				{Attempt: 2, Timestamp: time.Now().UTC().Truncate(time.Second), Working: true},
+			}
 
 			err = repo.StoreReleaseActionStatus(context.Background(), releaseActionMockData)
 			assert.NoError(t, err)
@@ -404,9 +586,12 @@ func TestReleaseRepo_GetActionStatusByReleaseID(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, actionStatus)
 			assert.Equal(t, releaseActionMockData.ID, actionStatus.ID)
+			assert.Equal(t, releaseActionMockData.Reannounce, actionStatus.Reannounce)
+
+			releaseActionMockData.Reannounce = nil
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
@@ -466,7 +651,7 @@ func TestReleaseRepo_Get(t *testing.T) {
 			assert.Equal(t, mockData.ID, release.ID)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
@@ -474,6 +659,82 @@ func TestReleaseRepo_Get(t *testing.T) {
 	}
 }
 
+func TestReleaseRepo_UpdateAnnotation(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		filterRepo := NewFilterRepo(log, db)
+		repo := NewReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("UpdateAnnotation_Succeeds [%s]", dbType), func(t *testing.T) {
+			// Setup
+			err := filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			release := getMockRelease()
+			release.FilterID = createdFilters[0].ID
+
+			err = repo.Store(context.Background(), release)
+			assert.NoError(t, err)
+
+			other := getMockRelease()
+			other.FilterID = createdFilters[0].ID
+
+			err = repo.Store(context.Background(), other)
+			assert.NoError(t, err)
+
+			notes := "Grabbed for the remux collection"
+			tags := []string{"follow-up", "remux"}
+
+			// Execute
+			err = repo.UpdateAnnotation(context.Background(), &domain.ReleaseAnnotationReq{ReleaseID: release.ID, Notes: &notes, UserTags: &tags})
+			assert.NoError(t, err)
+
+			otherTags := []string{"follow"}
+			err = repo.UpdateAnnotation(context.Background(), &domain.ReleaseAnnotationReq{ReleaseID: other.ID, UserTags: &otherTags})
+			assert.NoError(t, err)
+
+			// Verify
+			stored, err := repo.Get(context.Background(), &domain.GetReleaseRequest{Id: int(release.ID)})
+			assert.NoError(t, err)
+			assert.Equal(t, notes, stored.Notes)
+			assert.Equal(t, tags, stored.UserTags)
+
+			stored, err = repo.Get(context.Background(), &domain.GetReleaseRequest{Id: int(other.ID)})
+			assert.NoError(t, err)
+			assert.Equal(t, "", stored.Notes)
+			assert.Equal(t, otherTags, stored.UserTags)
+
+			queryParams := domain.ReleaseQueryParams{Limit: 10}
+			queryParams.Filters.UserTags = []string{"follow-up"}
+
+			resp, err := repo.Find(context.Background(), queryParams)
+			assert.NoError(t, err)
+			if assert.Len(t, resp.Data, 1) {
+				assert.Equal(t, release.ID, resp.Data[0].ID)
+				assert.Equal(t, tags, resp.Data[0].UserTags)
+			}
+
+			resp, err = repo.Find(context.Background(), domain.ReleaseQueryParams{Limit: 10, Search: "notes:remux"})
+			assert.NoError(t, err)
+			if assert.Len(t, resp.Data, 1) {
+				assert.Equal(t, release.ID, resp.Data[0].ID)
+			}
+
+			err = repo.UpdateAnnotation(context.Background(), &domain.ReleaseAnnotationReq{ReleaseID: other.ID + 1000, Notes: &notes})
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+		})
+	}
+}
+
 func TestReleaseRepo_Stats(t *testing.T) {
 	for dbType, db := range testDBs {
 		log := setupLoggerForTest()
@@ -526,7 +787,7 @@ func TestReleaseRepo_Stats(t *testing.T) {
 			assert.Equal(t, int64(1), stats.PushApprovedCount)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
@@ -578,7 +839,7 @@ func TestReleaseRepo_Delete(t *testing.T) {
 			assert.NoError(t, err)
 
 			// Execute
-			err = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, err = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 
 			// Verify
 			assert.NoError(t, err)
@@ -588,6 +849,45 @@ func TestReleaseRepo_Delete(t *testing.T) {
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
 		})
+
+		t.Run(fmt.Sprintf("Delete_By_Indexer_And_Filter [%s]", dbType), func(t *testing.T) {
+			// Setup
+			err := filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			filterID := createdFilters[0].ID
+
+			for _, identifier := range []string{"btn", "ptp"} {
+				release := getMockRelease()
+				release.Indexer.Identifier = identifier
+				release.FilterID = filterID
+
+				err = repo.Store(context.Background(), release)
+				assert.NoError(t, err)
+			}
+
+			// Execute
+			deleted, err := repo.Delete(context.Background(), &domain.DeleteReleaseRequest{Indexers: []string{"btn"}, FilterIDs: []int{filterID}})
+
+			// Verify
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), deleted)
+
+			deleted, err = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{Indexers: []string{"btn"}})
+			assert.NoError(t, err)
+			assert.Equal(t, int64(0), deleted)
+
+			deleted, err = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{FilterIDs: []int{filterID}})
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), deleted)
+
+			// Cleanup
+			_ = filterRepo.Delete(context.Background(), filterID)
+		})
 	}
 }
 
@@ -651,7 +951,608 @@ func TestReleaseRepo_CheckSmartEpisodeCanDownloadShow(t *testing.T) {
 			assert.True(t, canDownload)
 
 			// Cleanup
-			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+
+		t.Run(fmt.Sprintf("Check_Smart_Episode_Season_Pack [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData := getMockAction()
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			// the season pack of season 1 was pushed
+			seasonPack := getMockRelease()
+			seasonPack.Episode = 0
+			seasonPack.FilterID = createdFilters[0].ID
+			err = repo.Store(context.Background(), seasonPack)
+			assert.NoError(t, err)
+
+			status := getMockReleaseActionStatus()
+			status.ReleaseID = seasonPack.ID
+			status.ActionID = int64(createdAction.ID)
+			status.FilterID = int64(createdFilters[0].ID)
+			err = repo.StoreReleaseActionStatus(context.Background(), status)
+			assert.NoError(t, err)
+
+			episode := &domain.SmartEpisodeParams{Title: seasonPack.Title, Season: 1, Episode: 5, SeasonPacks: true}
+
+			// Execute
+			canDownload, err := repo.CheckSmartEpisodeCanDownload(context.Background(), episode)
+			assert.NoError(t, err)
+			assert.False(t, canDownload)
+
+			// opted out, only newer episodes reject
+			episode.SeasonPacks = false
+			canDownload, err = repo.CheckSmartEpisodeCanDownload(context.Background(), episode)
+			assert.NoError(t, err)
+			assert.True(t, canDownload)
+
+			// episodes of the next season are not in the pack
+			canDownload, err = repo.CheckSmartEpisodeCanDownload(context.Background(), &domain.SmartEpisodeParams{Title: seasonPack.Title, Season: 2, Episode: 1, SeasonPacks: true})
+			assert.NoError(t, err)
+			assert.True(t, canDownload)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
+
+func TestReleaseRepo_FindUpgradeOriginals(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("FindUpgradeOriginals [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData := getMockAction()
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			storePushed := func(release *domain.Release, infoHash string) {
+				release.FilterID = createdFilters[0].ID
+				err := repo.Store(context.Background(), release)
+				assert.NoError(t, err)
+
+				status := getMockReleaseActionStatus()
+				status.ReleaseID = release.ID
+				status.ActionID = int64(createdAction.ID)
+				status.FilterID = int64(createdFilters[0].ID)
+				status.InfoHash = infoHash
+				err = repo.StoreReleaseActionStatus(context.Background(), status)
+				assert.NoError(t, err)
+			}
+
+			original := getMockRelease()
+			original.Proper = false
+			storePushed(original, "abcdef")
+
+			// an earlier proper is never replaced
+			storePushed(getMockRelease(), "123456")
+
+			// another resolution is not replaced
+			other := getMockRelease()
+			other.Proper = false
+			other.Resolution = "2160p"
+			storePushed(other, "fedcba")
+
+			upgrade := getMockRelease()
+			upgrade.Group = "OtherGroup"
+
+			// Execute
+			originals, err := repo.FindUpgradeOriginals(context.Background(), domain.NewReleaseUpgradeParams(upgrade))
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.ReleaseUpgradeOriginal{
+				{ReleaseID: original.ID, TorrentName: original.TorrentName, ClientID: mock.ID, InfoHash: "abcdef"},
+			}, originals)
+
+			// a repack only replaces releases of its group
+			upgrade.Proper = false
+			upgrade.Repack = true
+			originals, err = repo.FindUpgradeOriginals(context.Background(), domain.NewReleaseUpgradeParams(upgrade))
+			assert.NoError(t, err)
+			assert.Empty(t, originals)
+
+			// the infohashes are in the history for other releases only
+			pushed, err := repo.CheckInfoHashPushed(context.Background(), "abcdef", other.ID)
+			assert.NoError(t, err)
+			assert.True(t, pushed)
+
+			pushed, err = repo.CheckInfoHashPushed(context.Background(), "abcdef", original.ID)
+			assert.NoError(t, err)
+			assert.False(t, pushed)
+
+			pushed, err = repo.CheckInfoHashPushed(context.Background(), "0a1b2c", other.ID)
+			assert.NoError(t, err)
+			assert.False(t, pushed)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
+
+func TestReleaseRepo_TrackedPushes(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("TrackedPushes [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData := getMockAction()
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			storePushed := func(client string, infoHash string, timestamp time.Time) *domain.ReleaseActionStatus {
+				release := getMockRelease()
+				release.FilterID = createdFilters[0].ID
+				err := repo.Store(context.Background(), release)
+				assert.NoError(t, err)
+
+				status := getMockReleaseActionStatus()
+				status.ReleaseID = release.ID
+				status.ActionID = int64(createdAction.ID)
+				status.FilterID = int64(createdFilters[0].ID)
+				status.Client = client
+				status.InfoHash = infoHash
+				status.Timestamp = timestamp
+				err = repo.StoreReleaseActionStatus(context.Background(), status)
+				assert.NoError(t, err)
+
+				return status
+			}
+
+			now := time.Now()
+
+			// too old to track
+			storePushed(mock.Name, "aaaaaa", now.Add(-48*time.Hour))
+			// unknown clients fall back to the client of the action
+			fallback := storePushed("removed client", "bbbbbb", now.Add(-time.Hour))
+			pushed := storePushed(mock.Name, "cccccc", now)
+			// no infohash to track
+			storePushed(mock.Name, "", now)
+			// older pushes stored later don't hide the ones before them
+			storePushed(mock.Name, "dddddd", now.Add(-72*time.Hour))
+
+			// Execute
+			pushes, err := repo.FindTrackedPushes(context.Background(), now.Add(-24*time.Hour))
+			assert.NoError(t, err)
+			assert.Len(t, pushes, 2)
+
+			assert.Equal(t, pushed.ID, pushes[0].ActionStatusID)
+			assert.Equal(t, "cccccc", pushes[0].InfoHash)
+			assert.Equal(t, mock.ID, pushes[0].ClientID)
+			assert.Equal(t, mock.Name, pushes[0].Client)
+			assert.Equal(t, fallback.ID, pushes[1].ActionStatusID)
+			assert.Equal(t, mock.ID, pushes[1].ClientID)
+
+			completedAt := now.Truncate(time.Second)
+			err = repo.SetActionStatusCompleted(context.Background(), pushed.ID, completedAt)
+			assert.NoError(t, err)
+
+			pushes, err = repo.FindTrackedPushes(context.Background(), now.Add(-24*time.Hour))
+			assert.NoError(t, err)
+			assert.Len(t, pushes, 1)
+			assert.Equal(t, fallback.ID, pushes[0].ActionStatusID)
+
+			status, err := repo.GetActionStatus(context.Background(), &domain.GetReleaseActionStatusRequest{Id: int(pushed.ID)})
+			assert.NoError(t, err)
+			if assert.NotNil(t, status.CompletedAt) {
+				assert.True(t, completedAt.Equal(*status.CompletedAt))
+			}
+
+			err = repo.SetActionStatusCompleted(context.Background(), -1, completedAt)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
+
+func TestReleaseRepo_GetPushLatencies(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("GetPushLatencies [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData := getMockAction()
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			storePushed := func(latency int64, timestamp time.Time) {
+				release := getMockRelease()
+				release.FilterID = createdFilters[0].ID
+				err := repo.Store(context.Background(), release)
+				assert.NoError(t, err)
+
+				status := getMockReleaseActionStatus()
+				status.ReleaseID = release.ID
+				status.ActionID = int64(createdAction.ID)
+				status.FilterID = int64(createdFilters[0].ID)
+				status.LatencyMs = latency
+				status.Timestamp = timestamp
+				err = repo.StoreReleaseActionStatus(context.Background(), status)
+				assert.NoError(t, err)
+			}
+
+			now := time.Now()
+
+			storePushed(100, now.Add(-time.Hour))
+			// no latency recorded
+			storePushed(0, now)
+			// older pushes stored later don't hide the ones before them
+			storePushed(300, now.Add(-48*time.Hour))
+			storePushed(200, now)
+
+			// Execute
+			latencies, err := repo.GetPushLatencies(context.Background(), now.Add(-24*time.Hour))
+			assert.NoError(t, err)
+			if assert.Len(t, latencies, 2) {
+				assert.Equal(t, int64(200), latencies[0].LatencyMs)
+				assert.Equal(t, int64(100), latencies[1].LatencyMs)
+			}
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
+
+func TestReleaseRepo_FindFailedActionStatuses(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("FindFailedActionStatuses [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData := getMockAction()
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			storeStatus := func(indexer string, client string, status domain.ReleasePushStatus, timestamp time.Time) *domain.ReleaseActionStatus {
+				release := getMockRelease()
+				release.Indexer.Identifier = indexer
+				release.FilterID = createdFilters[0].ID
+				err := repo.Store(context.Background(), release)
+				assert.NoError(t, err)
+
+				s := getMockReleaseActionStatus()
+				s.ReleaseID = release.ID
+				s.ActionID = int64(createdAction.ID)
+				s.FilterID = int64(createdFilters[0].ID)
+				s.Client = client
+				s.Status = status
+				s.Timestamp = timestamp
+				err = repo.StoreReleaseActionStatus(context.Background(), s)
+				assert.NoError(t, err)
+
+				return s
+			}
+
+			now := time.Now()
+
+			old := storeStatus("btn", "qbit", domain.ReleasePushStatusErr, now.Add(-48*time.Hour))
+			other := storeStatus("ptp", "deluge", domain.ReleasePushStatusErr, now.Add(-time.Hour))
+			recent := storeStatus("btn", "qbit", domain.ReleasePushStatusErr, now)
+			// only failed pushes are retried
+			storeStatus("btn", "qbit", domain.ReleasePushStatusApproved, now)
+
+			// Execute
+			statuses, err := repo.FindFailedActionStatuses(context.Background(), &domain.ReleaseRetryFailedReq{})
+			assert.NoError(t, err)
+			if assert.Len(t, statuses, 3) {
+				assert.Equal(t, recent.ID, statuses[0].ID)
+				assert.Equal(t, int64(createdAction.ID), statuses[0].ActionID)
+				assert.Equal(t, "qbit", statuses[0].Client)
+				assert.Equal(t, other.ID, statuses[1].ID)
+				assert.Equal(t, old.ID, statuses[2].ID)
+			}
+
+			statuses, err = repo.FindFailedActionStatuses(context.Background(), &domain.ReleaseRetryFailedReq{Client: "qbit", Indexer: "btn", MaxAge: 24})
+			assert.NoError(t, err)
+			if assert.Len(t, statuses, 1) {
+				assert.Equal(t, recent.ID, statuses[0].ID)
+			}
+
+			statuses, err = repo.FindFailedActionStatuses(context.Background(), &domain.ReleaseRetryFailedReq{Indexer: "ptp"})
+			assert.NoError(t, err)
+			if assert.Len(t, statuses, 1) {
+				assert.Equal(t, other.ID, statuses[0].ID)
+			}
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
+
+func TestReleaseRepo_RemovablePushes(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("RemovablePushes [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			storeAction := func(removeRatio float64) *domain.Action {
+				actionMockData := getMockAction()
+				actionMockData.FilterID = createdFilters[0].ID
+				actionMockData.ClientID = mock.ID
+				actionMockData.RemoveRatio = removeRatio
+				actionMockData.RemoveDeleteData = removeRatio > 0
+				createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+				assert.NoError(t, err)
+				return createdAction
+			}
+
+			withPolicy := storeAction(1.5)
+			withoutPolicy := storeAction(0)
+
+			storePushed := func(action *domain.Action, infoHash string) *domain.ReleaseActionStatus {
+				release := getMockRelease()
+				release.FilterID = createdFilters[0].ID
+				err := repo.Store(context.Background(), release)
+				assert.NoError(t, err)
+
+				status := getMockReleaseActionStatus()
+				status.ReleaseID = release.ID
+				status.ActionID = int64(action.ID)
+				status.FilterID = int64(createdFilters[0].ID)
+				status.Client = mock.Name
+				status.InfoHash = infoHash
+				err = repo.StoreReleaseActionStatus(context.Background(), status)
+				assert.NoError(t, err)
+
+				return status
+			}
+
+			pushed := storePushed(withPolicy, "aaaaaa")
+			storePushed(withoutPolicy, "bbbbbb")
+
+			// Execute
+			pushes, err := repo.FindRemovablePushes(context.Background())
+			assert.NoError(t, err)
+			if assert.Len(t, pushes, 1) {
+				assert.Equal(t, pushed.ID, pushes[0].ActionStatusID)
+				assert.Equal(t, "aaaaaa", pushes[0].InfoHash)
+				assert.Equal(t, mock.ID, pushes[0].ClientID)
+				assert.Equal(t, 1.5, pushes[0].RemoveRatio)
+				assert.True(t, pushes[0].RemoveDeleteData)
+			}
+
+			err = repo.SetActionStatusRemoved(context.Background(), pushed.ID, time.Now())
+			assert.NoError(t, err)
+
+			pushes, err = repo.FindRemovablePushes(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, pushes)
+
+			status, err := repo.GetActionStatus(context.Background(), &domain.GetReleaseActionStatusRequest{Id: int(pushed.ID)})
+			assert.NoError(t, err)
+			assert.NotNil(t, status.RemovedAt)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: withPolicy.ID})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: withoutPolicy.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
+		})
+	}
+}
+
+func TestReleaseRepo_CheckSmartMusicCanDownload(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		mockData := getMockRelease()
+		mockData.Title = "Albumname"
+		mockData.Artists = "Artist"
+		mockData.Year = 2008
+		mockData.AudioFormat = "FLAC"
+		mockData.Bitrate = "Lossless"
+		mockData.Source = "CD"
+
+		releaseActionMockData := getMockReleaseActionStatus()
+		releaseActionMockData.Timestamp = time.Now().AddDate(0, 0, -30)
+		actionMockData := getMockAction()
+
+		t.Run(fmt.Sprintf("Check_Smart_Music_Can_Download [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mock := getMockDownloadClient()
+			err := downloadClientRepo.Store(context.Background(), &mock)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = mock.ID
+			mockData.FilterID = createdFilters[0].ID
+
+			err = repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			releaseActionMockData.ReleaseID = mockData.ID
+			releaseActionMockData.ActionID = int64(createdAction.ID)
+			releaseActionMockData.FilterID = int64(createdFilters[0].ID)
+
+			err = repo.StoreReleaseActionStatus(context.Background(), releaseActionMockData)
+			assert.NoError(t, err)
+
+			params := &domain.SmartMusicParams{
+				Artists: "Artist",
+				Title:   "Albumname",
+				Year:    2008,
+				Format:  "MP3",
+				Bitrate: "V0 (VBR)",
+				Media:   "CD",
+			}
+
+			// Execute
+			canDownload, err := repo.CheckSmartMusicCanDownload(context.Background(), params)
+
+			// Verify the V0 is a duplicate of the FLAC
+			assert.NoError(t, err)
+			assert.False(t, canDownload)
+
+			params.Match = []string{domain.SmartMusicMatchFormat}
+
+			canDownload, err = repo.CheckSmartMusicCanDownload(context.Background(), params)
+
+			// Verify the MP3 is wanted when the format is part of the profile
+			assert.NoError(t, err)
+			assert.True(t, canDownload)
+
+			// Verify the album pushed 30 days ago is only a duplicate within the window
+			params.Match = nil
+			params.WithinDays = 7
+
+			canDownload, err = repo.CheckSmartMusicCanDownload(context.Background(), params)
+			assert.NoError(t, err)
+			assert.True(t, canDownload)
+
+			params.WithinDays = 60
+
+			canDownload, err = repo.CheckSmartMusicCanDownload(context.Background(), params)
+			assert.NoError(t, err)
+			assert.False(t, canDownload)
+
+			// Verify an album with the same title and year by another artist is not a duplicate
+			params.Artists = "Other Artist"
+
+			canDownload, err = repo.CheckSmartMusicCanDownload(context.Background(), params)
+			assert.NoError(t, err)
+			assert.True(t, canDownload)
+
+			// Cleanup
+			_, _ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
 			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
 			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
 			_ = downloadClientRepo.Delete(context.Background(), mock.ID)
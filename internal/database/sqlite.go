@@ -22,7 +22,7 @@ func (db *DB) openSQLite() error {
 	var err error
 
 	// open database connection
-	if db.handler, err = sql.Open("sqlite", db.DSN+"?_pragma=busy_timeout%3d1000"); err != nil {
+	if db.handler, err = openTimed("sqlite", db.DSN+"?_pragma=busy_timeout%3d1000"); err != nil {
 		db.log.Fatal().Err(err).Msg("could not open db connection")
 		return err
 	}
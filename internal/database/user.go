@@ -48,11 +48,76 @@ func (r *UserRepo) GetUserCount(ctx context.Context) (int, error) {
 	return result, nil
 }
 
+// GetAdminCount is used to keep at least one admin
+func (r *UserRepo) GetAdminCount(ctx context.Context) (int, error) {
+	queryBuilder := r.db.squirrel.Select("count(*)").From("users").Where(sq.Eq{"role": domain.UserRoleAdmin})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	result := 0
+	if err := r.db.handler.QueryRowContext(ctx, query, args...).Scan(&result); err != nil {
+		return 0, errors.Wrap(err, "error scanning row")
+	}
+
+	return result, nil
+}
+
+func (r *UserRepo) List(ctx context.Context) ([]domain.User, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "username", "role", "created_at").
+		From("users").
+		OrderBy("id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		var user domain.User
+
+		if err := rows.Scan(&user.ID, &user.Username, &user.Role, &user.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return users, nil
+}
+
+func (r *UserRepo) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	return r.findOne(ctx, sq.Eq{"id": id})
+}
+
 func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.findOne(ctx, sq.Eq{"username": username})
+}
+
+func (r *UserRepo) FindByOIDCSubject(ctx context.Context, issuer, subject string) (*domain.User, error) {
+	return r.findOne(ctx, sq.Eq{"oidc_issuer": issuer, "oidc_subject": subject})
+}
+
+func (r *UserRepo) findOne(ctx context.Context, where sq.Eq) (*domain.User, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "username", "password").
+		Select("id", "username", "password", "role", "created_at", "oidc_issuer", "oidc_subject").
 		From("users").
-		Where(sq.Eq{"username": username})
+		Where(where)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -65,8 +130,9 @@ func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain
 	}
 
 	var user domain.User
+	var oidcIssuer, oidcSubject sql.NullString
 
-	if err := row.Scan(&user.ID, &user.Username, &user.Password); err != nil {
+	if err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Role, &user.CreatedAt, &oidcIssuer, &oidcSubject); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -74,6 +140,9 @@ func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
+	user.OIDCIssuer = oidcIssuer.String
+	user.OIDCSubject = oidcSubject.String
+
 	return &user, nil
 }
 
@@ -83,6 +152,21 @@ func (r *UserRepo) Store(ctx context.Context, req domain.CreateUserRequest) erro
 		Columns("username", "password").
 		Values(req.Username, req.Password)
 
+	// users are admin unless the role is set
+	if req.Role != "" {
+		queryBuilder = r.db.squirrel.
+			Insert("users").
+			Columns("username", "password", "role").
+			Values(req.Username, req.Password, req.Role)
+	}
+
+	if req.OIDCSubject != "" {
+		queryBuilder = r.db.squirrel.
+			Insert("users").
+			Columns("username", "password", "role", "oidc_issuer", "oidc_subject").
+			Values(req.Username, req.Password, req.Role, req.OIDCIssuer, req.OIDCSubject)
+	}
+
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return errors.Wrap(err, "error building query")
@@ -122,7 +206,53 @@ func (r *UserRepo) Update(ctx context.Context, user domain.UpdateUserRequest) er
 	return nil
 }
 
+func (r *UserRepo) UpdateRole(ctx context.Context, id int, role domain.UserRole) error {
+	queryBuilder := r.db.squirrel.
+		Update("users").
+		Set("role", role).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepo) Delete(ctx context.Context, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// sqlite doesn't enforce the foreign keys of the api keys and notification preferences outside of tests
+	for _, table := range []string{"api_key", "user_notification_preference"} {
+		query, args, err := r.db.squirrel.
+			Delete(table).
+			Where(sq.Expr("user_id IN (SELECT id FROM users WHERE username = ?)", username)).
+			ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
 	queryBuilder := r.db.squirrel.
 		Delete("users").
 		Where(sq.Eq{"username": username})
@@ -133,11 +263,14 @@ func (r *UserRepo) Delete(ctx context.Context, username string) error {
 	}
 
 	// Execute the query.
-	_, err = r.db.handler.ExecContext(ctx, query, args...)
-	if err != nil {
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 		return errors.Wrap(err, "error executing query")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error deleting user: %s", username)
+	}
+
 	// Log the deletion.
 	r.log.Debug().Msgf("user.delete: successfully deleted user: %s", username)
 
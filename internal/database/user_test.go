@@ -166,5 +166,100 @@ func TestUserRepo_Delete(t *testing.T) {
 			assert.Error(t, err)
 			assert.Equal(t, domain.ErrRecordNotFound, err)
 		})
+
+		t.Run(fmt.Sprintf("DeleteUser_Removes_APIKeys [%s]", dbType), func(t *testing.T) {
+			// Setup
+			apiRepo := NewAPIRepo(log, db)
+
+			err := repo.Store(context.Background(), domain.CreateUserRequest{
+				Username: user.Username,
+				Password: user.Password,
+			})
+			assert.NoError(t, err)
+
+			stored, err := repo.FindByUsername(context.Background(), user.Username)
+			assert.NoError(t, err)
+
+			key := &domain.APIKey{Name: "owned", Key: "owned-by-user", Scopes: []string{"read"}, UserID: stored.ID}
+			assert.NoError(t, apiRepo.Store(context.Background(), key))
+
+			// Execute
+			err = repo.Delete(context.Background(), user.Username)
+			assert.NoError(t, err)
+
+			// Verify
+			_, err = apiRepo.GetKey(context.Background(), key.Key)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+		})
+
+		t.Run(fmt.Sprintf("DeleteUser_Removes_NotificationPreferences [%s]", dbType), func(t *testing.T) {
+			// Setup
+			prefRepo := NewNotificationPreferenceRepo(log, db)
+
+			err := repo.Store(context.Background(), domain.CreateUserRequest{
+				Username: user.Username,
+				Password: user.Password,
+			})
+			assert.NoError(t, err)
+
+			stored, err := repo.FindByUsername(context.Background(), user.Username)
+			assert.NoError(t, err)
+
+			pref := &domain.UserNotificationPreference{UserID: stored.ID, Events: []string{}, Notifications: []int{}}
+			assert.NoError(t, prefRepo.Store(context.Background(), pref))
+
+			// Execute
+			err = repo.Delete(context.Background(), user.Username)
+			assert.NoError(t, err)
+
+			// Verify
+			_, err = prefRepo.FindByUserID(context.Background(), stored.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+		})
+	}
+}
+
+func TestUserRepo_Roles(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		repo := NewUserRepo(log, db)
+
+		user := getMockUser()
+
+		t.Run(fmt.Sprintf("Roles_Succeeds [%s]", dbType), func(t *testing.T) {
+			err := repo.Store(context.Background(), domain.CreateUserRequest{
+				Username: user.Username,
+				Password: user.Password,
+				Role:     domain.UserRoleReadOnly,
+			})
+			assert.NoError(t, err)
+
+			stored, err := repo.FindByUsername(context.Background(), user.Username)
+			assert.NoError(t, err)
+			assert.Equal(t, domain.UserRoleReadOnly, stored.Role)
+
+			admins, err := repo.GetAdminCount(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 0, admins)
+
+			err = repo.UpdateRole(context.Background(), stored.ID, domain.UserRoleAdmin)
+			assert.NoError(t, err)
+
+			found, err := repo.FindByID(context.Background(), stored.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, domain.UserRoleAdmin, found.Role)
+
+			users, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, users, 1)
+			assert.Empty(t, users[0].Password)
+
+			err = repo.UpdateRole(context.Background(), stored.ID+1, domain.UserRoleAdmin)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), user.Username)
+		})
 	}
 }
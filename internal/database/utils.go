@@ -6,6 +6,7 @@ package database
 import (
 	"database/sql"
 	"path"
+	"time"
 )
 
 func dataSourceName(configPath string, name string) string {
@@ -42,3 +43,11 @@ func toNullFloat64(s float64) sql.NullFloat64 {
 		Valid:   s != 0,
 	}
 }
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
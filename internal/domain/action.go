@@ -28,6 +28,9 @@ type Action struct {
 	Enabled                  bool                `json:"enabled"`
 	ExecCmd                  string              `json:"exec_cmd,omitempty"`
 	ExecArgs                 string              `json:"exec_args,omitempty"`
+	ExecTimeout              int                 `json:"exec_timeout,omitempty"`
+	ExecEnv                  []string            `json:"exec_env,omitempty"`
+	ExecDir                  string              `json:"exec_dir,omitempty"`
 	WatchFolder              string              `json:"watch_folder,omitempty"`
 	Category                 string              `json:"category,omitempty"`
 	Tags                     string              `json:"tags,omitempty"`
@@ -37,26 +40,97 @@ type Action struct {
 	IgnoreRules              bool                `json:"ignore_rules,omitempty"`
 	FirstLastPiecePrio       bool                `json:"first_last_piece_prio,omitempty"`
 	SkipHashCheck            bool                `json:"skip_hash_check,omitempty"`
+	FreeleechToken           bool                `json:"freeleech_token,omitempty"`
+	FreeleechTokenMinSize    string              `json:"freeleech_token_min_size,omitempty"`
 	ContentLayout            ActionContentLayout `json:"content_layout,omitempty"`
 	LimitUploadSpeed         int64               `json:"limit_upload_speed,omitempty"`
 	LimitDownloadSpeed       int64               `json:"limit_download_speed,omitempty"`
 	LimitRatio               float64             `json:"limit_ratio,omitempty"`
 	LimitSeedTime            int64               `json:"limit_seed_time,omitempty"`
+	LimitInactiveSeedTime    int64               `json:"limit_inactive_seed_time,omitempty"`
+	MaxConnections           int                 `json:"max_connections,omitempty"`
 	PriorityLayout           PriorityLayout      `json:"priority,omitempty"`
 	ReAnnounceSkip           bool                `json:"reannounce_skip,omitempty"`
 	ReAnnounceDelete         bool                `json:"reannounce_delete,omitempty"`
 	ReAnnounceInterval       int64               `json:"reannounce_interval,omitempty"`
 	ReAnnounceMaxAttempts    int64               `json:"reannounce_max_attempts,omitempty"`
+	ReAnnounceJitter         int64               `json:"reannounce_jitter,omitempty"`
+	RequireApproval          bool                `json:"require_approval,omitempty"`
+	RemoveRatio              float64             `json:"remove_ratio,omitempty"`       // remove the torrent from the client once seeded to this ratio
+	RemoveSeedTime           int64               `json:"remove_seed_time,omitempty"`   // in minutes
+	RemoveAfterDays          int                 `json:"remove_after_days,omitempty"`  // days after the push
+	RemoveDeleteData         bool                `json:"remove_delete_data,omitempty"` // delete the downloaded data along with the torrent
+	RunCondition             ActionRunCondition  `json:"run_condition,omitempty"`
+	ValidateTorrent          bool                `json:"validate_torrent,omitempty"`  // check the announce urls and history of the torrent file
+	ValidateAnnounce         string              `json:"validate_announce,omitempty"` // comma separated announce hosts, defaults to the indexer hosts
+	ValidatePrivate          TorrentPrivacy      `json:"validate_private,omitempty"`
+	DupeKey                  string              `json:"dupe_key,omitempty"`
+	CompletionHooks          []CompletionHook    `json:"completion_hooks,omitempty"` // run when the client finished downloading the torrent
+	CrossSeed                ActionCrossSeed     `json:"cross_seed,omitempty"`       // when the cross-seed instance is asked to search for the release
+	Custom1                  string              `json:"custom1,omitempty"`
+	Custom2                  string              `json:"custom2,omitempty"`
+	BandwidthGroup           string              `json:"bandwidth_group,omitempty"`
+	MoveCompletedPath        string              `json:"move_completed_path,omitempty"`
 	WebhookHost              string              `json:"webhook_host,omitempty"`
 	WebhookType              string              `json:"webhook_type,omitempty"`
 	WebhookMethod            string              `json:"webhook_method,omitempty"`
 	WebhookData              string              `json:"webhook_data,omitempty"`
 	WebhookHeaders           []string            `json:"webhook_headers,omitempty"`
+	WebhookSecret            string              `json:"webhook_secret,omitempty"`
+	WebhookRetryAttempts     int                 `json:"webhook_retry_attempts,omitempty"`
+	WebhookRetryDelaySeconds int                 `json:"webhook_retry_delay_seconds,omitempty"`
 	ExternalDownloadClientID int32               `json:"external_download_client_id,omitempty"`
 	ExternalDownloadClient   string              `json:"external_download_client,omitempty"`
 	FilterID                 int                 `json:"filter_id,omitempty"`
 	ClientID                 int32               `json:"client_id,omitempty"`
+	FailoverClientIDs        []int32             `json:"failover_client_ids,omitempty"` // tried in order when the client is unreachable or rejects
+	ClientPoolMode           ClientPoolMode      `json:"client_pool_mode,omitempty"`    // picks the client from the client and failover clients
 	Client                   *DownloadClient     `json:"client,omitempty"`
+
+	// ReAnnounceAttempts is set while the action runs, it's stored with the action status
+	ReAnnounceAttempts []ReannounceAttempt `json:"-"`
+}
+
+type CompletionHookType string
+
+const (
+	CompletionHookTypeExec     CompletionHookType = "EXEC"
+	CompletionHookTypeWebhook  CompletionHookType = "WEBHOOK"
+	CompletionHookTypePlexScan CompletionHookType = "PLEX_SCAN"
+	CompletionHookTypeAutoscan CompletionHookType = "AUTOSCAN"
+	CompletionHookTypeJellyfin CompletionHookType = "JELLYFIN_SCAN"
+)
+
+// CompletionHook runs when the download client finished downloading a torrent pushed by the action.
+// Exec args, webhook data and the scan paths support macros.
+type CompletionHook struct {
+	Type             CompletionHookType `json:"type"`
+	ExecCmd          string             `json:"exec_cmd,omitempty"`
+	ExecArgs         string             `json:"exec_args,omitempty"`
+	WebhookHost      string             `json:"webhook_host,omitempty"`
+	WebhookMethod    string             `json:"webhook_method,omitempty"`
+	WebhookData      string             `json:"webhook_data,omitempty"`
+	PlexHost         string             `json:"plex_host,omitempty"`
+	PlexToken        string             `json:"plex_token,omitempty"`
+	PlexSection      string             `json:"plex_section,omitempty"` // library section id
+	PlexPath         string             `json:"plex_path,omitempty"`    // only this folder of the section is scanned when set
+	AutoscanHost     string             `json:"autoscan_host,omitempty"`
+	AutoscanUsername string             `json:"autoscan_username,omitempty"`
+	AutoscanPassword string             `json:"autoscan_password,omitempty"`
+	AutoscanPath     string             `json:"autoscan_path,omitempty"` // defaults to the save path of the action
+	JellyfinHost     string             `json:"jellyfin_host,omitempty"`
+	JellyfinAPIKey   string             `json:"jellyfin_api_key,omitempty"`
+	JellyfinPath     string             `json:"jellyfin_path,omitempty"` // defaults to the save path of the action, all libraries are refreshed without one
+}
+
+// HasCompletionHooks reports if anything runs once the client finished downloading a torrent pushed by the action
+func (a *Action) HasCompletionHooks() bool {
+	return len(a.CompletionHooks) > 0 || a.CrossSeed == ActionCrossSeedOnComplete
+}
+
+// HasRemovalPolicy reports if torrents pushed by the action are removed from the client once a limit is reached
+func (a *Action) HasRemovalPolicy() bool {
+	return a.RemoveRatio > 0 || a.RemoveSeedTime > 0 || a.RemoveAfterDays > 0
 }
 
 // CheckMacrosNeedTorrentTmpFile check if macros needs torrent downloaded
@@ -70,7 +144,8 @@ func (a *Action) CheckMacrosNeedTorrentTmpFile(release *Release) bool {
 			strings.Contains(a.WebhookData, "TorrentHash") ||
 			strings.Contains(a.SavePath, "TorrentPathName") ||
 			strings.Contains(a.SavePath, "TorrentHash") ||
-			a.Type == ActionTypeWatchFolder) {
+			a.Type == ActionTypeWatchFolder ||
+			(a.Type == ActionTypeRemoteWatchFolder && release.Protocol == ReleaseProtocolTorrent)) {
 		return true
 	}
 
@@ -81,7 +156,8 @@ func (a *Action) CheckMacrosNeedRawDataBytes(release *Release) bool {
 	// if webhook data contains TorrentDataRawBytes, lets read the file into bytes we can then use in the macro
 	if len(release.TorrentDataRawBytes) == 0 &&
 		(strings.Contains(a.ExecArgs, "TorrentDataRawBytes") || strings.Contains(a.WebhookData, "TorrentDataRawBytes") ||
-			a.Type == ActionTypeWatchFolder) {
+			a.Type == ActionTypeWatchFolder ||
+			(a.Type == ActionTypeRemoteWatchFolder && release.Protocol == ReleaseProtocolTorrent)) {
 		return true
 	}
 
@@ -99,6 +175,28 @@ func (a *Action) ParseMacros(release *Release) error {
 		return errors.Wrap(err, "could not parse exec args")
 	}
 
+	// parsed into a new slice, copies of the action share the backing array
+	if len(a.ExecEnv) > 0 {
+		env := make([]string, 0, len(a.ExecEnv))
+		for _, e := range a.ExecEnv {
+			if strings.TrimSpace(e) == "" {
+				continue
+			}
+
+			parsed, err := m.Parse(e)
+			if err != nil {
+				return errors.Wrap(err, "could not parse exec env")
+			}
+			env = append(env, parsed)
+		}
+		a.ExecEnv = env
+	}
+
+	a.ExecDir, err = m.Parse(a.ExecDir)
+	if err != nil {
+		return errors.Wrap(err, "could not parse exec dir")
+	}
+
 	a.WatchFolder, err = m.Parse(a.WatchFolder)
 	if err != nil {
 		return errors.Wrap(err, "could not parse watch folder")
@@ -118,37 +216,85 @@ func (a *Action) ParseMacros(release *Release) error {
 	if err != nil {
 		return errors.Wrap(err, "could not parse label")
 	}
+
+	// category set by an exec filter or action overrides the configured one
+	if release.ActionCategory != "" {
+		switch a.Type {
+		case ActionTypeDelugeV1, ActionTypeDelugeV2, ActionTypeRTorrent:
+			a.Label = release.ActionCategory
+		default:
+			a.Category = release.ActionCategory
+		}
+	}
 	a.SavePath, err = m.Parse(a.SavePath)
 	if err != nil {
 		return errors.Wrap(err, "could not parse save_path")
 	}
+	a.MoveCompletedPath, err = m.Parse(a.MoveCompletedPath)
+	if err != nil {
+		return errors.Wrap(err, "could not parse move_completed_path")
+	}
+	a.DupeKey, err = m.Parse(a.DupeKey)
+	if err != nil {
+		return errors.Wrap(err, "could not parse dupe_key")
+	}
+	a.Custom1, err = m.Parse(a.Custom1)
+	if err != nil {
+		return errors.Wrap(err, "could not parse custom1")
+	}
+	a.Custom2, err = m.Parse(a.Custom2)
+	if err != nil {
+		return errors.Wrap(err, "could not parse custom2")
+	}
 	a.WebhookData, err = m.Parse(a.WebhookData)
 	if err != nil {
 		return errors.Wrap(err, "could not parse webhook_data")
 	}
 
+	// parsed into a new slice, copies of the action share the backing array
+	if len(a.WebhookHeaders) > 0 {
+		headers := make([]string, 0, len(a.WebhookHeaders))
+		for _, h := range a.WebhookHeaders {
+			if strings.TrimSpace(h) == "" {
+				continue
+			}
+
+			parsed, err := m.Parse(h)
+			if err != nil {
+				return errors.Wrap(err, "could not parse webhook_headers")
+			}
+			headers = append(headers, parsed)
+		}
+		a.WebhookHeaders = headers
+	}
+
 	return nil
 }
 
 type ActionType string
 
 const (
-	ActionTypeTest         ActionType = "TEST"
-	ActionTypeExec         ActionType = "EXEC"
-	ActionTypeQbittorrent  ActionType = "QBITTORRENT"
-	ActionTypeDelugeV1     ActionType = "DELUGE_V1"
-	ActionTypeDelugeV2     ActionType = "DELUGE_V2"
-	ActionTypeRTorrent     ActionType = "RTORRENT"
-	ActionTypeTransmission ActionType = "TRANSMISSION"
-	ActionTypePorla        ActionType = "PORLA"
-	ActionTypeWatchFolder  ActionType = "WATCH_FOLDER"
-	ActionTypeWebhook      ActionType = "WEBHOOK"
-	ActionTypeRadarr       ActionType = "RADARR"
-	ActionTypeSonarr       ActionType = "SONARR"
-	ActionTypeLidarr       ActionType = "LIDARR"
-	ActionTypeWhisparr     ActionType = "WHISPARR"
-	ActionTypeReadarr      ActionType = "READARR"
-	ActionTypeSabnzbd      ActionType = "SABNZBD"
+	ActionTypeTest              ActionType = "TEST"
+	ActionTypeExec              ActionType = "EXEC"
+	ActionTypeQbittorrent       ActionType = "QBITTORRENT"
+	ActionTypeDelugeV1          ActionType = "DELUGE_V1"
+	ActionTypeDelugeV2          ActionType = "DELUGE_V2"
+	ActionTypeRTorrent          ActionType = "RTORRENT"
+	ActionTypeTransmission      ActionType = "TRANSMISSION"
+	ActionTypePorla             ActionType = "PORLA"
+	ActionTypeWatchFolder       ActionType = "WATCH_FOLDER"
+	ActionTypeWebhook           ActionType = "WEBHOOK"
+	ActionTypeRadarr            ActionType = "RADARR"
+	ActionTypeSonarr            ActionType = "SONARR"
+	ActionTypeLidarr            ActionType = "LIDARR"
+	ActionTypeWhisparr          ActionType = "WHISPARR"
+	ActionTypeReadarr           ActionType = "READARR"
+	ActionTypeMylar             ActionType = "MYLAR"
+	ActionTypeSabnzbd           ActionType = "SABNZBD"
+	ActionTypeAria2             ActionType = "ARIA2"
+	ActionTypeDownloadStation   ActionType = "DOWNLOAD_STATION"
+	ActionTypeNzbget            ActionType = "NZBGET"
+	ActionTypeRemoteWatchFolder ActionType = "REMOTE_WATCH_FOLDER"
 )
 
 type ActionContentLayout string
@@ -167,6 +313,56 @@ const (
 	PriorityLayoutDefault PriorityLayout = ""
 )
 
+// ActionRunCondition decides if an action runs based on the result of the previous action that ran
+type ActionRunCondition string
+
+const (
+	ActionRunConditionAlways    ActionRunCondition = "ALWAYS"
+	ActionRunConditionOnSuccess ActionRunCondition = "ON_SUCCESS"
+	ActionRunConditionOnFailure ActionRunCondition = "ON_FAILURE"
+)
+
+// ShouldRun checks the run condition against the status of the previous action that ran, nil when none ran yet.
+// Actions without a condition always run, conditional actions only run after another action.
+func (a *Action) ShouldRun(previous *ReleasePushStatus) bool {
+	switch a.RunCondition {
+	case ActionRunConditionOnSuccess:
+		return previous != nil && *previous == ReleasePushStatusApproved
+	case ActionRunConditionOnFailure:
+		return previous != nil && *previous != ReleasePushStatusApproved
+	default:
+		return true
+	}
+}
+
+// ClientPoolMode picks the client of an action from its pool, the client and the failover clients.
+// The other clients of the pool are tried in order when the picked one fails.
+type ClientPoolMode string
+
+const (
+	// ClientPoolModeRoundRobin picks the clients of the pool in turn
+	ClientPoolModeRoundRobin ClientPoolMode = "ROUND_ROBIN"
+
+	// ClientPoolModeLeastActive picks the client with the least active torrents
+	ClientPoolModeLeastActive ClientPoolMode = "LEAST_ACTIVE"
+)
+
+// ActionCrossSeed is when the configured cross-seed instance searches the other indexers for a pushed release
+type ActionCrossSeed string
+
+const (
+	ActionCrossSeedOnPush     ActionCrossSeed = "ON_PUSH"
+	ActionCrossSeedOnComplete ActionCrossSeed = "ON_COMPLETE"
+)
+
+// TorrentPrivacy is the private flag a torrent file must have to be pushed
+type TorrentPrivacy string
+
+const (
+	TorrentPrivacyPrivate TorrentPrivacy = "PRIVATE"
+	TorrentPrivacyPublic  TorrentPrivacy = "PUBLIC"
+)
+
 type GetActionRequest struct {
 	Id int
 }
@@ -0,0 +1,31 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type AnnounceHistoryRepo interface {
+	StoreMany(ctx context.Context, entries []AnnounceHistoryEntry) error
+	FindSince(ctx context.Context, since time.Time) ([]AnnounceHistoryEntry, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) error
+}
+
+type AnnounceSource string
+
+const (
+	AnnounceSourceIRC  AnnounceSource = "IRC"
+	AnnounceSourceFeed AnnounceSource = "FEED"
+)
+
+// AnnounceHistoryEntry is a release seen by either IRC or a feed for an indexer
+type AnnounceHistoryEntry struct {
+	ID          int64          `json:"id"`
+	Indexer     string         `json:"indexer"`
+	Source      AnnounceSource `json:"source"`
+	TorrentName string         `json:"torrent_name"`
+	SeenAt      time.Time      `json:"seen_at"`
+}
@@ -5,7 +5,15 @@ package domain
 
 import (
 	"context"
+	"slices"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var (
+	ErrAPIKeyInvalid = errors.New("invalid api key")
+	ErrAPIKeyExpired = errors.New("api key expired")
 )
 
 type APIRepo interface {
@@ -16,8 +24,94 @@ type APIRepo interface {
 }
 
 type APIKey struct {
-	Name      string    `json:"name"`
-	Key       string    `json:"key"`
-	Scopes    []string  `json:"scopes"`
+	Name      string     `json:"name"`
+	Key       string     `json:"key"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	// UserID is the owner of the key, keys created before users had roles have none
+	UserID    int       `json:"user_id,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+type APIKeyScope string
+
+const (
+	// APIKeyScopeRead allows reading everything except api keys and logs
+	APIKeyScopeRead APIKeyScope = "read"
+
+	// APIKeyScopeReleasesWrite allows changing releases, like replaying actions and deleting history
+	APIKeyScopeReleasesWrite APIKeyScope = "releases:write"
+
+	// APIKeyScopeFiltersWrite allows changing filters and their actions
+	APIKeyScopeFiltersWrite APIKeyScope = "filters:write"
+
+	// APIKeyScopeAdmin allows everything
+	APIKeyScopeAdmin APIKeyScope = "admin"
+)
+
+var APIKeyScopes = []APIKeyScope{
+	APIKeyScopeRead,
+	APIKeyScopeReleasesWrite,
+	APIKeyScopeFiltersWrite,
+	APIKeyScopeAdmin,
+}
+
+func (k APIKey) Validate() error {
+	var errs ValidationErrors
+
+	if k.Name == "" {
+		errs.Add("name", "name is required")
+	}
+
+	for _, scope := range k.Scopes {
+		if !slices.Contains(APIKeyScopes, APIKeyScope(scope)) {
+			errs.Add("scopes", "invalid scope %q", scope)
+		}
+	}
+
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(time.Now()) {
+		errs.Add("expires_at", "expiry must be in the future")
+	}
+
+	return errs.Err()
+}
+
+// Expired reports whether the key has passed its expiry, keys without one never expire
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// HasScope reports whether the key is allowed to act within the scope.
+// Keys created before scopes existed have none and keep full access,
+// the write scopes include read access.
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+
+	for _, s := range k.Scopes {
+		switch APIKeyScope(s) {
+		case APIKeyScopeAdmin, scope:
+			return true
+		case APIKeyScopeReleasesWrite, APIKeyScopeFiltersWrite:
+			if scope == APIKeyScopeRead {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey returns the context of a request made with the api key
+func ContextWithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// APIKeyFromContext returns the api key the request was made with, nil for sessions and internal requests
+func APIKeyFromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_HasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  APIKeyScope
+		want   bool
+	}{
+		{name: "no_scopes_full_access", scopes: nil, scope: APIKeyScopeAdmin, want: true},
+		{name: "read", scopes: []string{"read"}, scope: APIKeyScopeRead, want: true},
+		{name: "read_no_write", scopes: []string{"read"}, scope: APIKeyScopeFiltersWrite, want: false},
+		{name: "write_includes_read", scopes: []string{"releases:write"}, scope: APIKeyScopeRead, want: true},
+		{name: "write_other", scopes: []string{"releases:write"}, scope: APIKeyScopeFiltersWrite, want: false},
+		{name: "write_no_admin", scopes: []string{"releases:write", "filters:write"}, scope: APIKeyScopeAdmin, want: false},
+		{name: "admin", scopes: []string{"admin"}, scope: APIKeyScopeFiltersWrite, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, APIKey{Scopes: tt.scopes}.HasScope(tt.scope))
+		})
+	}
+}
+
+func TestAPIKey_Expired(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour)
+
+	assert.False(t, APIKey{}.Expired(now))
+	assert.False(t, APIKey{ExpiresAt: &expires}.Expired(now))
+	assert.True(t, APIKey{ExpiresAt: &expires}.Expired(expires))
+}
+
+func TestAPIKey_Validate(t *testing.T) {
+	expires := time.Now().Add(24 * time.Hour)
+
+	k := APIKey{Name: "dashboard", Scopes: []string{"read"}, ExpiresAt: &expires}
+	assert.NoError(t, k.Validate())
+
+	k.Scopes = []string{"read", "write"}
+	assert.ErrorContains(t, k.Validate(), `invalid scope "write"`)
+
+	past := time.Now().Add(-time.Hour)
+	k.Scopes = nil
+	k.ExpiresAt = &past
+	assert.ErrorContains(t, k.Validate(), "expires_at")
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type AuditRepo interface {
+	Store(ctx context.Context, entry *AuditEntry) error
+	Find(ctx context.Context, params AuditQueryParams) (*FindAuditResponse, error)
+}
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+type AuditResource string
+
+const (
+	AuditResourceAction         AuditResource = "action"
+	AuditResourceAPIKey         AuditResource = "api_key"
+	AuditResourceDownloadClient AuditResource = "download_client"
+	AuditResourceFeed           AuditResource = "feed"
+	AuditResourceFilter         AuditResource = "filter"
+	AuditResourceFilterGroup    AuditResource = "filter_group"
+	AuditResourceIndexer        AuditResource = "indexer"
+	AuditResourceIRCNetwork     AuditResource = "irc_network"
+	AuditResourceList           AuditResource = "list"
+	AuditResourceNotification   AuditResource = "notification"
+	AuditResourceProxy          AuditResource = "proxy"
+	AuditResourceSettings       AuditResource = "settings"
+	AuditResourceUser           AuditResource = "user"
+)
+
+// AuditEntry is a change of the configuration and who made it
+type AuditEntry struct {
+	ID           int64         `json:"id"`
+	Action       AuditAction   `json:"action"`
+	Resource     AuditResource `json:"resource"`
+	ResourceID   string        `json:"resource_id,omitempty"`
+	ResourceName string        `json:"resource_name,omitempty"`
+	Description  string        `json:"description"`
+	// Username is empty for api keys not owned by a user
+	Username string `json:"username,omitempty"`
+	// APIKey is the name of the api key the change was made with, empty for sessions
+	APIKey    string    `json:"api_key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuditQueryParams struct {
+	Limit    uint64
+	Offset   uint64
+	Resource AuditResource
+	Username string
+}
+
+type FindAuditResponse struct {
+	Data       []AuditEntry `json:"data"`
+	TotalCount uint64       `json:"count"`
+}
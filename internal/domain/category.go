@@ -0,0 +1,244 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Shared category taxonomy used to match categories across indexers.
+// Normalized categories are a base type optionally followed by a subtype, eg. Movies/HD
+const (
+	CategoryMovies     = "Movies"
+	CategoryTV         = "TV"
+	CategoryAnime      = "Anime"
+	CategoryMusic      = "Music"
+	CategoryAudiobooks = "Audiobooks"
+	CategoryBooks      = "Books"
+	CategoryComics     = "Comics"
+	CategoryGames      = "Games"
+	CategoryApps       = "Apps"
+	CategoryXXX        = "XXX"
+)
+
+const (
+	CategorySubtypeUHD      = "UHD"
+	CategorySubtypeHD       = "HD"
+	CategorySubtypeSD       = "SD"
+	CategorySubtypeLossless = "Lossless"
+	CategorySubtypeLossy    = "Lossy"
+	CategorySubtypePC       = "PC"
+	CategorySubtypeConsole  = "Console"
+)
+
+type categoryRule struct {
+	base     string
+	keywords []string
+}
+
+// categoryRules are checked in order, the first base type with a matching keyword wins
+var categoryRules = []categoryRule{
+	{base: CategoryXXX, keywords: []string{"xxx", "adult", "porn"}},
+	{base: CategoryAnime, keywords: []string{"anime"}},
+	{base: CategoryAudiobooks, keywords: []string{"audiobook", "audiobooks", "abook", "abooks"}},
+	{base: CategoryComics, keywords: []string{"comic", "comics", "manga"}},
+	{base: CategoryBooks, keywords: []string{"book", "books", "ebook", "ebooks", "epub", "magazine", "magazines"}},
+	{base: CategoryTV, keywords: []string{"tv", "hdtv", "sdtv", "uhdtv", "episode", "episodes", "series", "show", "shows", "season", "seasons", "boxset", "sport", "sports"}},
+	{base: CategoryMovies, keywords: []string{"movie", "movies", "film", "films", "cinema"}},
+	{base: CategoryMusic, keywords: []string{"music", "audio", "album", "albums", "flac", "mp3", "aac", "lossless", "vinyl", "discography"}},
+	{base: CategoryGames, keywords: []string{"game", "games", "gaming", "console", "ps3", "ps4", "ps5", "xbox", "nintendo", "switch", "wii"}},
+	{base: CategoryApps, keywords: []string{"app", "apps", "application", "applications", "software", "0day"}},
+}
+
+var categorySubtypeRules = map[string][]categoryRule{
+	CategoryMovies: videoSubtypeRules,
+	CategoryTV:     videoSubtypeRules,
+	CategoryAnime:  videoSubtypeRules,
+	CategoryMusic: {
+		{base: CategorySubtypeLossless, keywords: []string{"flac", "lossless", "24bit", "alac"}},
+		{base: CategorySubtypeLossy, keywords: []string{"mp3", "aac", "lossy", "ogg"}},
+	},
+	CategoryGames: {
+		{base: CategorySubtypePC, keywords: []string{"pc", "windows", "mac", "linux"}},
+		{base: CategorySubtypeConsole, keywords: []string{"console", "ps3", "ps4", "ps5", "xbox", "nintendo", "switch", "wii"}},
+	},
+}
+
+var videoSubtypeRules = []categoryRule{
+	{base: CategorySubtypeUHD, keywords: []string{"uhd", "2160p", "4k", "uhdtv"}},
+	{base: CategorySubtypeHD, keywords: []string{"hd", "hdtv", "1080p", "1080i", "720p", "x264", "x265", "h264", "h265", "bluray", "webdl"}},
+	{base: CategorySubtypeSD, keywords: []string{"sd", "sdtv", "480p", "576p", "xvid", "dvd", "dvdr", "dvdrip"}},
+}
+
+// NormalizeCategories maps raw indexer categories, both names and torznab/newznab ids, to the shared taxonomy.
+// The result contains both the base types and the base/subtype combinations, eg. [TV TV/HD]
+func NormalizeCategories(raw []string) []string {
+	normalized := make([]string, 0)
+
+	add := func(category string) {
+		if category != "" && !slices.Contains(normalized, category) {
+			normalized = append(normalized, category)
+		}
+	}
+
+	for _, category := range raw {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			continue
+		}
+
+		if id, err := strconv.Atoi(category); err == nil {
+			base, subtype := normalizeCategoryID(id)
+			add(base)
+			if base != "" && subtype != "" {
+				add(base + "/" + subtype)
+			}
+			continue
+		}
+
+		tokens := categoryTokens(category)
+
+		base := matchCategoryRules(categoryRules, tokens)
+		if base == "" {
+			continue
+		}
+
+		add(base)
+
+		if subtype := matchCategoryRules(categorySubtypeRules[base], tokens); subtype != "" {
+			add(base + "/" + subtype)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return normalized
+}
+
+// NormalizeIndexerCategories maps raw categories with the category map from the indexer definition first.
+// Categories without a mapping fall back to NormalizeCategories.
+func NormalizeIndexerCategories(mapping map[string]string, raw []string) []string {
+	if len(mapping) == 0 {
+		return NormalizeCategories(raw)
+	}
+
+	normalized := make([]string, 0)
+	unmapped := make([]string, 0)
+
+	for _, category := range raw {
+		mapped, ok := lookupCategoryMapping(mapping, category)
+		if !ok {
+			unmapped = append(unmapped, category)
+			continue
+		}
+
+		base, _, hasSubtype := strings.Cut(mapped, "/")
+		if !slices.Contains(normalized, base) {
+			normalized = append(normalized, base)
+		}
+		if hasSubtype && !slices.Contains(normalized, mapped) {
+			normalized = append(normalized, mapped)
+		}
+	}
+
+	for _, category := range NormalizeCategories(unmapped) {
+		if !slices.Contains(normalized, category) {
+			normalized = append(normalized, category)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return normalized
+}
+
+func lookupCategoryMapping(mapping map[string]string, category string) (string, bool) {
+	category = strings.TrimSpace(category)
+
+	for raw, mapped := range mapping {
+		if strings.EqualFold(raw, category) && mapped != "" {
+			return mapped, true
+		}
+	}
+
+	return "", false
+}
+
+func matchCategoryRules(rules []categoryRule, tokens []string) string {
+	for _, rule := range rules {
+		for _, keyword := range rule.keywords {
+			if slices.Contains(tokens, keyword) {
+				return rule.base
+			}
+		}
+	}
+
+	return ""
+}
+
+// categoryTokens splits a raw category like "TV-HD/X264" into lowercase tokens [tv hd x264]
+func categoryTokens(category string) []string {
+	return strings.FieldsFunc(strings.ToLower(category), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalizeCategoryID maps torznab/newznab category ids
+func normalizeCategoryID(id int) (string, string) {
+	switch id {
+	case 2045, 5045:
+		return categoryIDBase(id), CategorySubtypeUHD
+	case 2040, 2050, 2060, 5040:
+		return categoryIDBase(id), CategorySubtypeHD
+	case 2030, 5030:
+		return categoryIDBase(id), CategorySubtypeSD
+	case 3040:
+		return CategoryMusic, CategorySubtypeLossless
+	case 3010:
+		return CategoryMusic, CategorySubtypeLossy
+	case 3030:
+		return CategoryAudiobooks, ""
+	case 4050:
+		return CategoryGames, CategorySubtypePC
+	case 5070:
+		return CategoryAnime, ""
+	case 7030:
+		return CategoryComics, ""
+	}
+
+	if id >= 1000 && id < 2000 {
+		return CategoryGames, CategorySubtypeConsole
+	}
+
+	return categoryIDBase(id), ""
+}
+
+func categoryIDBase(id int) string {
+	// custom indexer categories are offset by 100000
+	if id >= 100000 {
+		return ""
+	}
+
+	switch id / 1000 {
+	case 1:
+		return CategoryGames
+	case 2:
+		return CategoryMovies
+	case 3:
+		return CategoryMusic
+	case 4:
+		return CategoryApps
+	case 5:
+		return CategoryTV
+	case 6:
+		return CategoryXXX
+	case 7:
+		return CategoryBooks
+	}
+
+	return ""
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []string
+		want []string
+	}{
+		{name: "empty", raw: nil, want: []string{}},
+		{name: "tv_hd", raw: []string{"TV-HD/X264"}, want: []string{"TV", "TV/HD"}},
+		{name: "tv_episodes", raw: []string{"Episodes/SD"}, want: []string{"TV", "TV/SD"}},
+		{name: "movies_uhd", raw: []string{"Movies/UHD"}, want: []string{"Movies", "Movies/UHD"}},
+		{name: "music_mp3", raw: []string{"Music/MP3"}, want: []string{"Music", "Music/Lossy"}},
+		{name: "music_flac", raw: []string{"FLAC"}, want: []string{"Music", "Music/Lossless"}},
+		{name: "games_pc", raw: []string{"Games/PC"}, want: []string{"Games", "Games/PC"}},
+		{name: "xxx", raw: []string{"XXX/0-Day"}, want: []string{"XXX"}},
+		{name: "anime", raw: []string{"Anime"}, want: []string{"Anime"}},
+		{name: "ebooks", raw: []string{"E-Books"}, want: []string{"Books"}},
+		{name: "audiobooks", raw: []string{"Audiobooks"}, want: []string{"Audiobooks"}},
+		{name: "torznab_ids", raw: []string{"Movies/HD", "2040"}, want: []string{"Movies", "Movies/HD"}},
+		{name: "torznab_tv_uhd", raw: []string{"5045"}, want: []string{"TV", "TV/UHD"}},
+		{name: "torznab_anime", raw: []string{"5070"}, want: []string{"Anime"}},
+		{name: "torznab_custom", raw: []string{"100001"}, want: []string{}},
+		{name: "unknown", raw: []string{"Misc"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeCategories(tt.raw))
+		})
+	}
+}
+
+func TestNormalizeIndexerCategories(t *testing.T) {
+	mapping := map[string]string{
+		"PC :: Iso":          "Apps",
+		"TV :: Episodes HD":  "TV/HD",
+		"Animation :: Anime": "Anime",
+	}
+
+	tests := []struct {
+		name    string
+		mapping map[string]string
+		raw     []string
+		want    []string
+	}{
+		{name: "no_mapping", mapping: nil, raw: []string{"TV-HD/X264"}, want: []string{"TV", "TV/HD"}},
+		{name: "mapped", mapping: mapping, raw: []string{"PC :: Iso"}, want: []string{"Apps"}},
+		{name: "mapped_case_insensitive", mapping: mapping, raw: []string{"tv :: episodes hd"}, want: []string{"TV", "TV/HD"}},
+		{name: "mapped_overrides_keywords", mapping: mapping, raw: []string{"Animation :: Anime"}, want: []string{"Anime"}},
+		{name: "unmapped_fallback", mapping: mapping, raw: []string{"Movies :: Bluray"}, want: []string{"Movies", "Movies/HD"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeIndexerCategories(tt.mapping, tt.raw))
+		})
+	}
+}
+
+func TestFilter_CheckFilter_NormalizeCategories(t *testing.T) {
+	tests := []struct {
+		name      string
+		normalize bool
+		release   *Release
+		want      bool
+	}{
+		{name: "disabled", normalize: false, release: &Release{Category: "TV :: Episodes HD"}, want: false},
+		{name: "enabled", normalize: true, release: &Release{Category: "TV :: Episodes HD"}, want: true},
+		{name: "enabled_indexer_map", normalize: true, release: &Release{Category: "PC :: Iso", NormalizedCategories: []string{"Apps"}}, want: false},
+		{name: "raw_match", normalize: false, release: &Release{Category: "TV/HD"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{MatchCategories: "TV/HD", NormalizeCategories: tt.normalize}
+			_, got := f.CheckFilter(tt.release)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
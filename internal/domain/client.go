@@ -8,8 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-
-	"github.com/autobrr/autobrr/pkg/errors"
+	"time"
 )
 
 type DownloadClientRepo interface {
@@ -37,6 +36,82 @@ type DownloadClient struct {
 	Client any
 }
 
+// Redacted returns the client without its password and api key, for callers that can't see credentials
+func (c DownloadClient) Redacted() DownloadClient {
+	c.Password = ""
+	c.Settings.APIKey = ""
+	c.Settings.Basic.Password = ""
+	c.Settings.Auth.Password = ""
+	c.Client = nil
+
+	return c
+}
+
+// DownloadClientInventory holds the existing categories, labels, tags and save paths of a download client
+type DownloadClientInventory struct {
+	ClientID   int32                    `json:"client_id"`
+	Categories []DownloadClientCategory `json:"categories"`
+	Labels     []string                 `json:"labels"`
+	Tags       []string                 `json:"tags"`
+	SavePaths  []string                 `json:"save_paths"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
+
+// DownloadClientTorrent is a torrent found in a download client
+type DownloadClientTorrent struct {
+	ClientID   int32              `json:"client_id"`
+	ClientName string             `json:"client_name"`
+	ClientType DownloadClientType `json:"client_type"`
+	Hash       string             `json:"hash"`
+	Name       string             `json:"name"`
+	State      string             `json:"state"`
+	SavePath   string             `json:"save_path"`
+	Category   string             `json:"category,omitempty"`
+	Labels     []string           `json:"labels,omitempty"`
+	Size       int64              `json:"size,omitempty"`
+	Progress   float64            `json:"progress"`
+}
+
+// DownloadClientSearchResult holds the torrents matching a search across all enabled torrent clients.
+// Clients that could not be searched are listed in Errors.
+type DownloadClientSearchResult struct {
+	Query    string                      `json:"query"`
+	Torrents []DownloadClientTorrent     `json:"torrents"`
+	Errors   []DownloadClientSearchError `json:"errors"`
+}
+
+type DownloadClientSearchError struct {
+	ClientID   int32  `json:"client_id"`
+	ClientName string `json:"client_name"`
+	Error      string `json:"error"`
+}
+
+// DownloadClientSelfTestOptions control the client selftest.
+// FixtureDir overrides the bundled fixtures, with Record the live exchanges are written there instead of verified.
+type DownloadClientSelfTestOptions struct {
+	FixtureDir string
+	Record     bool
+}
+
+// DownloadClientSelfTestReport lists differences between the live client api and the recorded fixture
+type DownloadClientSelfTestReport struct {
+	ClientID    int32              `json:"client_id"`
+	ClientName  string             `json:"client_name"`
+	ClientType  DownloadClientType `json:"client_type"`
+	Exchanges   int                `json:"exchanges"`
+	Issues      []string           `json:"issues"`
+	FixturePath string             `json:"fixture_path,omitempty"`
+}
+
+func (r DownloadClientSelfTestReport) Passed() bool {
+	return len(r.Issues) == 0
+}
+
+type DownloadClientCategory struct {
+	Name     string `json:"name"`
+	SavePath string `json:"save_path"`
+}
+
 type DownloadClientSettings struct {
 	APIKey                   string              `json:"apikey,omitempty"`
 	Basic                    BasicAuth           `json:"basic,omitempty"` // Deprecated: Use Auth instead
@@ -114,6 +189,8 @@ type DownloadClientRules struct {
 	IgnoreSlowTorrentsCondition IgnoreSlowTorrentsCondition `json:"ignore_slow_torrents_condition,omitempty"`
 	DownloadSpeedThreshold      int64                       `json:"download_speed_threshold"`
 	UploadSpeedThreshold        int64                       `json:"upload_speed_threshold"`
+	MinFreeSpace                int64                       `json:"min_free_space,omitempty"`  // in GB, rejects releases which would leave less free space
+	FreeSpacePath               string                      `json:"free_space_path,omitempty"` // checked instead of querying the client when set
 }
 
 type BasicAuth struct {
@@ -132,30 +209,41 @@ const (
 type DownloadClientType string
 
 const (
-	DownloadClientTypeQbittorrent  DownloadClientType = "QBITTORRENT"
-	DownloadClientTypeDelugeV1     DownloadClientType = "DELUGE_V1"
-	DownloadClientTypeDelugeV2     DownloadClientType = "DELUGE_V2"
-	DownloadClientTypeRTorrent     DownloadClientType = "RTORRENT"
-	DownloadClientTypeTransmission DownloadClientType = "TRANSMISSION"
-	DownloadClientTypePorla        DownloadClientType = "PORLA"
-	DownloadClientTypeRadarr       DownloadClientType = "RADARR"
-	DownloadClientTypeSonarr       DownloadClientType = "SONARR"
-	DownloadClientTypeLidarr       DownloadClientType = "LIDARR"
-	DownloadClientTypeWhisparr     DownloadClientType = "WHISPARR"
-	DownloadClientTypeReadarr      DownloadClientType = "READARR"
-	DownloadClientTypeSabnzbd      DownloadClientType = "SABNZBD"
+	DownloadClientTypeQbittorrent       DownloadClientType = "QBITTORRENT"
+	DownloadClientTypeDelugeV1          DownloadClientType = "DELUGE_V1"
+	DownloadClientTypeDelugeV2          DownloadClientType = "DELUGE_V2"
+	DownloadClientTypeRTorrent          DownloadClientType = "RTORRENT"
+	DownloadClientTypeTransmission      DownloadClientType = "TRANSMISSION"
+	DownloadClientTypePorla             DownloadClientType = "PORLA"
+	DownloadClientTypeRadarr            DownloadClientType = "RADARR"
+	DownloadClientTypeSonarr            DownloadClientType = "SONARR"
+	DownloadClientTypeLidarr            DownloadClientType = "LIDARR"
+	DownloadClientTypeWhisparr          DownloadClientType = "WHISPARR"
+	DownloadClientTypeReadarr           DownloadClientType = "READARR"
+	DownloadClientTypeMylar             DownloadClientType = "MYLAR"
+	DownloadClientTypePlex              DownloadClientType = "PLEX"
+	DownloadClientTypeJellyfin          DownloadClientType = "JELLYFIN"
+	DownloadClientTypeSabnzbd           DownloadClientType = "SABNZBD"
+	DownloadClientTypeAria2             DownloadClientType = "ARIA2"
+	DownloadClientTypeDownloadStation   DownloadClientType = "DOWNLOAD_STATION"
+	DownloadClientTypeNzbget            DownloadClientType = "NZBGET"
+	DownloadClientTypeRemoteWatchFolder DownloadClientType = "REMOTE_WATCH_FOLDER"
 )
 
 // Validate basic validation of client
 func (c DownloadClient) Validate() error {
+	var errs ValidationErrors
+
 	// basic validation of client
 	if c.Host == "" {
-		return errors.New("validation error: missing host")
-	} else if c.Type == "" {
-		return errors.New("validation error: missing type")
+		errs.Add("host", "missing host")
 	}
 
-	return nil
+	if c.Type == "" {
+		errs.Add("type", "missing type")
+	}
+
+	return errs.Err()
 }
 
 func (c DownloadClient) BuildLegacyHost() string {
@@ -27,6 +27,101 @@ type Config struct {
 	ProfilingEnabled    bool   `toml:"profilingEnabled"`
 	ProfilingHost       string `toml:"profilingHost"`
 	ProfilingPort       int    `toml:"profilingPort"`
+	MetricsEnabled      bool   `toml:"metricsEnabled"`
+	MetricsHost         string `toml:"metricsHost"`
+	MetricsPort         int    `toml:"metricsPort"`
+
+	// UnixSocket is the path of a unix socket served next to, or with port 0 instead of, host and port
+	UnixSocket string `toml:"unixSocket"`
+	// UnixSocketPermissions is the octal file mode of the socket, eg. "0660"
+	UnixSocketPermissions string `toml:"unixSocketPermissions"`
+
+	FeedConsistencyCheck          bool `toml:"feedConsistencyCheck"`
+	AnnounceHistoryBufferSize     int  `toml:"announceHistoryBufferSize"`
+	AnnounceHistorySpillBatchSize int  `toml:"announceHistorySpillBatchSize"`
+
+	PushBudgetPerHour string `toml:"pushBudgetPerHour"`
+
+	// DedupeWindow is in seconds
+	DedupeWindow int `toml:"dedupeWindow"`
+
+	SizeMismatchThreshold int `toml:"sizeMismatchThreshold"`
+
+	// CompletionCheckInterval is in minutes
+	CompletionCheckInterval int `toml:"completionCheckInterval"`
+
+	FilterTrashDays int `toml:"filterTrashDays"`
+
+	OrphanCleanup bool `toml:"orphanCleanup"`
+
+	// Pipeline is keyed by indexer identifier
+	Pipeline map[string]PipelineConfig `toml:"pipeline"`
+
+	Storage StorageConfig `toml:"storage"`
+
+	CrossSeed CrossSeedConfig `toml:"crossSeed"`
+
+	TMDB TMDBConfig `toml:"tmdb"`
+
+	OIDC OIDCConfig `toml:"oidc"`
+
+	RateLimit RateLimitConfig `toml:"rateLimit"`
+
+	TLS TLSConfig `toml:"tls"`
+}
+
+// CrossSeedConfig is the cross-seed instance actions can trigger searches on
+type CrossSeedConfig struct {
+	Host   string `toml:"host"`
+	APIKey string `toml:"apiKey"`
+}
+
+// TMDBConfig is the tmdb api used to resolve the ids of releases for the match ids of filters
+type TMDBConfig struct {
+	APIKey string `toml:"apiKey"`
+}
+
+// OIDCConfig is the OpenID Connect provider users can log in with next to the local user
+type OIDCConfig struct {
+	Enabled      bool   `toml:"enabled"`
+	Issuer       string `toml:"issuer"`
+	ClientID     string `toml:"clientId"`
+	ClientSecret string `toml:"clientSecret"`
+	// RedirectURL is the callback url registered at the provider, derived from the request when empty
+	RedirectURL string `toml:"redirectUrl"`
+	// GroupsClaim is the id token claim listing the groups of the user, "groups" by default
+	GroupsClaim string `toml:"groupsClaim"`
+	// AllowedGroups restricts the login to members of any of the groups, everyone is allowed when empty
+	AllowedGroups       []string `toml:"allowedGroups"`
+	DisableBuiltInLogin bool     `toml:"disableBuiltInLogin"`
+}
+
+// RateLimitConfig is the number of api requests allowed per minute, 0 is unlimited
+type RateLimitConfig struct {
+	// PerIP limits every request of a client ip
+	PerIP int `toml:"perIp"`
+	// PerKey limits the requests made with an api key, on top of the limit of the ip
+	PerKey int `toml:"perKey"`
+	// Auth limits the logins and onboarding of a client ip
+	Auth int `toml:"auth"`
+}
+
+// TLSConfig serves https without a reverse proxy, with the certificate of CertFile and KeyFile
+// or one issued by an ACME CA for ACMEDomains
+type TLSConfig struct {
+	Enabled bool `toml:"enabled"`
+	// CertFile and KeyFile are reloaded when they change
+	CertFile string `toml:"certFile"`
+	KeyFile  string `toml:"keyFile"`
+	// ACMEDomains are the domains to issue a certificate for when no CertFile is set
+	ACMEDomains []string `toml:"acmeDomains"`
+	ACMEEmail   string   `toml:"acmeEmail"`
+	// ACMEDirectory is the directory url of the CA, Let's Encrypt when empty
+	ACMEDirectory string `toml:"acmeDirectory"`
+	// ACMECacheDir keeps the account and certificates, certs in the config directory when empty
+	ACMECacheDir string `toml:"acmeCacheDir"`
+	// ACMEHTTPPort answers http-01 challenges and redirects to https, 0 only uses tls-alpn-01 on the https port
+	ACMEHTTPPort int `toml:"acmeHttpPort"`
 }
 
 type ConfigUpdate struct {
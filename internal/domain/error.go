@@ -5,6 +5,8 @@ package domain
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/autobrr/autobrr/pkg/errors"
 )
@@ -14,3 +16,38 @@ var (
 	ErrUpdateFailed   = errors.New("update failed")
 	ErrDeleteFailed   = errors.New("delete failed")
 )
+
+// ValidationError is a validation error for a single field
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects field validation errors, the api responds with 400 and the list of fields
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return "validation error: " + strings.Join(msgs, ", ")
+}
+
+func (e *ValidationErrors) Add(field, message string, args ...any) {
+	*e = append(*e, ValidationError{Field: field, Message: fmt.Sprintf(message, args...)})
+}
+
+// Err returns nil if there are no errors
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ExecResult is optional structured output from exec filters and actions.
+// The last line of stdout starting with { is parsed, eg.
+//
+//	{"reject": true, "reason": "already have it"}
+//	{"category": "tv-uhd", "macros": {"target": "/mnt/media"}}
+//
+// Macros are available in later actions as {{ .Vars.target }}
+type ExecResult struct {
+	Reject   bool              `json:"reject"`
+	Reason   string            `json:"reason"`
+	Category string            `json:"category"`
+	Macros   map[string]string `json:"macros"`
+}
+
+// ParseExecResult returns the structured result from script output, or nil if there is none
+func ParseExecResult(output []byte) (*ExecResult, error) {
+	lines := bytes.Split(bytes.TrimSpace(output), []byte("\n"))
+
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := bytes.TrimSpace(lines[i])
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var result ExecResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			return nil, err
+		}
+
+		return &result, nil
+	}
+
+	return nil, nil
+}
+
+// Apply sets the target category and macro variables on the release for the following actions
+func (r *ExecResult) Apply(release *Release) {
+	if r.Category != "" {
+		release.ActionCategory = r.Category
+	}
+
+	if len(r.Macros) > 0 {
+		if release.MacroVars == nil {
+			release.MacroVars = make(map[string]string, len(r.Macros))
+		}
+
+		for k, v := range r.Macros {
+			release.MacroVars[k] = v
+		}
+	}
+}
+
+// RejectionReason returns the reason or a default message
+func (r *ExecResult) RejectionReason() string {
+	if r.Reason != "" {
+		return r.Reason
+	}
+
+	return "rejected by exec output"
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExecResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    *ExecResult
+		wantErr bool
+	}{
+		{
+			name:   "no_output",
+			output: "",
+			want:   nil,
+		},
+		{
+			name:   "plain_text",
+			output: "checking release\ndone\n",
+			want:   nil,
+		},
+		{
+			name:   "reject",
+			output: "checking release\n{\"reject\": true, \"reason\": \"already in library\"}\n",
+			want:   &ExecResult{Reject: true, Reason: "already in library"},
+		},
+		{
+			name:   "last_json_line",
+			output: "{\"reject\": true}\nchanged my mind\n{\"category\": \"tv-uhd\", \"macros\": {\"target\": \"/mnt/tv\"}}",
+			want:   &ExecResult{Category: "tv-uhd", Macros: map[string]string{"target": "/mnt/tv"}},
+		},
+		{
+			name:    "invalid_json",
+			output:  "{reject: true",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExecResult([]byte(tt.output))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExecResult_Apply(t *testing.T) {
+	release := &Release{MacroVars: map[string]string{"keep": "1"}}
+
+	result := &ExecResult{Category: "movies-uhd", Macros: map[string]string{"target": "/mnt/movies"}}
+	result.Apply(release)
+
+	assert.Equal(t, "movies-uhd", release.ActionCategory)
+	assert.Equal(t, map[string]string{"keep": "1", "target": "/mnt/movies"}, release.MacroVars)
+
+	m := NewMacro(*release)
+	assert.Equal(t, "/mnt/movies", m.MustParse("{{ .Vars.target }}"))
+}
@@ -58,6 +58,7 @@ type Feed struct {
 	ProxyID  int64
 	UseProxy bool
 	Proxy    *Proxy
+	Identity *IndexerIdentity
 }
 
 type FeedSettingsJSON struct {
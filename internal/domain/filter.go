@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -36,6 +38,9 @@ type FilterRepo interface {
 	UpdatePartial(ctx context.Context, filter FilterUpdate) error
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
 	Delete(ctx context.Context, filterID int) error
+	Trash(ctx context.Context, filterID int) error
+	Restore(ctx context.Context, filterID int) error
+	ListTrashed(ctx context.Context) ([]Filter, error)
 	StoreIndexerConnection(ctx context.Context, filterID int, indexerID int) error
 	StoreIndexerConnections(ctx context.Context, filterID int, indexers []Indexer) error
 	StoreFilterExternal(ctx context.Context, filterID int, externalFilters []FilterExternal) error
@@ -50,6 +55,13 @@ type FilterDownloads struct {
 	WeekCount  int
 	MonthCount int
 	TotalCount int
+
+	// bytes of the pushed releases, a release pushed by several actions is counted once
+	HourSize  uint64
+	DaySize   uint64
+	WeekSize  uint64
+	MonthSize uint64
+	TotalSize uint64
 }
 
 type FilterMaxDownloadsUnit string
@@ -72,12 +84,62 @@ type SmartEpisodeParams struct {
 	Repack  bool
 	Proper  bool
 	Group   string
+
+	// WithinDays limits the check to releases pushed within the last days, 0 checks all
+	WithinDays int
+
+	// SeasonPacks treats a pushed season pack as owning all episodes of the season
+	SeasonPacks bool
+}
+
+// IsSeasonPack reports whether the params are for a full season
+func (p *SmartEpisodeParams) IsSeasonPack() bool {
+	return p.Season > 0 && p.Episode == 0
 }
 
 func (p *SmartEpisodeParams) IsDailyEpisode() bool {
 	return p.Year != 0 && p.Month != 0 && p.Day != 0
 }
 
+const (
+	SmartMusicMatchFormat  = "format"
+	SmartMusicMatchBitrate = "bitrate"
+	SmartMusicMatchMedia   = "media"
+)
+
+// SmartMusicParams identifies an album, a pushed release with the same artists, title and year is a duplicate
+// unless it differs in one of the Match fields
+type SmartMusicParams struct {
+	Artists string
+	Title   string
+	Year    int
+	Format  string
+	Bitrate string
+	Media   string
+	Match   []string
+
+	// WithinDays limits the check to releases pushed within the last days, 0 checks all
+	WithinDays int
+}
+
+func NewSmartMusicParams(r *Release, match []string, withinDays int) *SmartMusicParams {
+	return &SmartMusicParams{
+		Artists:    r.Artists,
+		Title:      r.Title,
+		Year:       r.Year,
+		Format:     r.AudioFormat,
+		Bitrate:    r.Bitrate,
+		Media:      r.Source,
+		Match:      match,
+		WithinDays: withinDays,
+	}
+}
+
+// Matches reports whether the field is part of the duplicate profile
+func (p *SmartMusicParams) Matches(field string) bool {
+	return slices.Contains(p.Match, field)
+}
+
 type FilterQueryParams struct {
 	Sort    map[string]string
 	Filters struct {
@@ -92,12 +154,28 @@ type Filter struct {
 	Enabled              bool                   `json:"enabled"`
 	CreatedAt            time.Time              `json:"created_at"`
 	UpdatedAt            time.Time              `json:"updated_at"`
+	CreatedBy            string                 `json:"created_by,omitempty"` // username of the user who created the filter
+	UpdatedBy            string                 `json:"updated_by,omitempty"` // username of the user who last changed the filter
+	DeletedAt            *time.Time             `json:"deleted_at,omitempty"`
 	MinSize              string                 `json:"min_size,omitempty"`
 	MaxSize              string                 `json:"max_size,omitempty"`
+	MaxFiles             int                    `json:"max_files,omitempty"`              // checked against the file list of the torrent
+	MatchFileExtensions  string                 `json:"match_file_extensions,omitempty"`  // one of the files of the torrent must have one of them, eg. mkv,mp4
+	ExceptFileExtensions string                 `json:"except_file_extensions,omitempty"` // none of the files of the torrent may have one of them, eg. exe,lnk
+	SonarrCheckClientID  int32                  `json:"sonarr_check_client_id,omitempty"` // sonarr client that must want the episodes, 0 disables the check
+	RadarrCheckClientID  int32                  `json:"radarr_check_client_id,omitempty"` // radarr client that must want the movie, 0 disables the check
+	LidarrCheckClientID  int32                  `json:"lidarr_check_client_id,omitempty"` // lidarr client that must miss the album, 0 disables the check
+	LibraryCheckClientID int32                  `json:"library_check_client_id,omitempty"`
+	MatchIDs             string                 `json:"match_ids,omitempty"`
+	MatchMusicBrainz     string                 `json:"match_musicbrainz,omitempty"`
 	Delay                int                    `json:"delay,omitempty"`
 	Priority             int32                  `json:"priority"`
+	GroupID              int                    `json:"group_id,omitempty"`
 	MaxDownloads         int                    `json:"max_downloads,omitempty"`
 	MaxDownloadsUnit     FilterMaxDownloadsUnit `json:"max_downloads_unit,omitempty"`
+	MaxDownloadsSize     string                 `json:"max_downloads_size,omitempty"` // byte budget per unit, eg. 500GB
+	MaxDownloadsSizeUnit FilterMaxDownloadsUnit `json:"max_downloads_size_unit,omitempty"`
+	SkipDedupe           bool                   `json:"skip_dedupe,omitempty"` // push even when another filter or indexer pushed the release within the dedupe window
 	MatchReleases        string                 `json:"match_releases,omitempty"`
 	ExceptReleases       string                 `json:"except_releases,omitempty"`
 	UseRegex             bool                   `json:"use_regex,omitempty"`
@@ -110,6 +188,12 @@ type Filter struct {
 	Freeleech            bool                   `json:"freeleech,omitempty"`
 	FreeleechPercent     string                 `json:"freeleech_percent,omitempty"`
 	SmartEpisode         bool                   `json:"smart_episode"`
+	IgnoreSeasonPacks    bool                   `json:"ignore_season_packs,omitempty"` // smart episode: pushed season packs don't reject their episodes
+	UpgradeReplace       bool                   `json:"upgrade_replace,omitempty"`     // remove the original torrent from the client before adding its PROPER or REPACK
+	GroupTiers           []FilterGroupTier      `json:"group_tiers,omitempty"`         // hold releases of less preferred groups, see FilterGroupTier
+	SmartMusic           bool                   `json:"smart_music"`
+	SmartMusicMatch      []string               `json:"smart_music_match,omitempty"`    // format, bitrate, media. Albums differing in these are not duplicates
+	SmartDuplicateDays   int                    `json:"smart_duplicate_days,omitempty"` // only releases pushed within this many days are duplicates, 0 for ever
 	Shows                string                 `json:"shows,omitempty"`
 	Seasons              string                 `json:"seasons,omitempty"`
 	Episodes             string                 `json:"episodes,omitempty"`
@@ -137,6 +221,7 @@ type Filter struct {
 	LogScore             int                    `json:"log_score,omitempty"`
 	MatchCategories      string                 `json:"match_categories,omitempty"`
 	ExceptCategories     string                 `json:"except_categories,omitempty"`
+	NormalizeCategories  bool                   `json:"normalize_categories,omitempty"` // also match categories mapped to the shared taxonomy, eg. TV/HD
 	MatchUploaders       string                 `json:"match_uploaders,omitempty"`
 	ExceptUploaders      string                 `json:"except_uploaders,omitempty"`
 	MatchLanguage        []string               `json:"match_language,omitempty"`
@@ -157,6 +242,13 @@ type Filter struct {
 	MaxSeeders           int                    `json:"max_seeders,omitempty"`
 	MinLeechers          int                    `json:"min_leechers,omitempty"`
 	MaxLeechers          int                    `json:"max_leechers,omitempty"`
+	BreakerThreshold     int                    `json:"breaker_threshold,omitempty"` // consecutive external check failures before the breaker opens, 0 disables it
+	BreakerCooldown      int                    `json:"breaker_cooldown,omitempty"`  // seconds
+	BreakerFailOpen      bool                   `json:"breaker_fail_open,omitempty"`
+	Breakers             FilterBreakers         `json:"breakers,omitempty"`
+	Schedule             *FilterSchedule        `json:"schedule,omitempty"`
+	AdvancedExpression   string                 `json:"advanced_expression,omitempty"` // CEL expression, see filter_expression.go
+	NextActivation       *time.Time             `json:"next_activation,omitempty"`     // set when the filter is outside its schedule
 	ActionsCount         int                    `json:"actions_count"`
 	ActionsEnabledCount  int                    `json:"actions_enabled_count"`
 	Actions              []*Action              `json:"actions,omitempty"`
@@ -183,6 +275,7 @@ type FilterExternal struct {
 	WebhookRetryStatus       string             `json:"webhook_retry_status,omitempty"`
 	WebhookRetryAttempts     int                `json:"webhook_retry_attempts,omitempty"`
 	WebhookRetryDelaySeconds int                `json:"webhook_retry_delay_seconds,omitempty"`
+	WebhookTimeoutSeconds    int                `json:"webhook_timeout_seconds,omitempty"`
 	FilterId                 int                `json:"-"`
 }
 
@@ -207,6 +300,9 @@ type FilterExternalType string
 const (
 	ExternalFilterTypeExec    FilterExternalType = "EXEC"
 	ExternalFilterTypeWebhook FilterExternalType = "WEBHOOK"
+
+	// ExternalFilterTypeWebhookVerdict posts the release as json and reads the verdict from the response
+	ExternalFilterTypeWebhookVerdict FilterExternalType = "WEBHOOK_VERDICT"
 )
 
 type FilterUpdate struct {
@@ -215,10 +311,24 @@ type FilterUpdate struct {
 	Enabled              *bool                   `json:"enabled,omitempty"`
 	MinSize              *string                 `json:"min_size,omitempty"`
 	MaxSize              *string                 `json:"max_size,omitempty"`
+	MaxFiles             *int                    `json:"max_files,omitempty"`
+	MatchFileExtensions  *string                 `json:"match_file_extensions,omitempty"`
+	ExceptFileExtensions *string                 `json:"except_file_extensions,omitempty"`
+	SonarrCheckClientID  *int32                  `json:"sonarr_check_client_id,omitempty"`
+	RadarrCheckClientID  *int32                  `json:"radarr_check_client_id,omitempty"`
+	LidarrCheckClientID  *int32                  `json:"lidarr_check_client_id,omitempty"`
+	LibraryCheckClientID *int32                  `json:"library_check_client_id,omitempty"`
+	UpdatedBy            *string                 `json:"-"`
+	MatchIDs             *string                 `json:"match_ids,omitempty"`
+	MatchMusicBrainz     *string                 `json:"match_musicbrainz,omitempty"`
 	Delay                *int                    `json:"delay,omitempty"`
 	Priority             *int32                  `json:"priority,omitempty"`
+	GroupID              *int                    `json:"group_id,omitempty"` // 0 removes the filter from its group
 	MaxDownloads         *int                    `json:"max_downloads,omitempty"`
 	MaxDownloadsUnit     *FilterMaxDownloadsUnit `json:"max_downloads_unit,omitempty"`
+	MaxDownloadsSize     *string                 `json:"max_downloads_size,omitempty"`
+	MaxDownloadsSizeUnit *FilterMaxDownloadsUnit `json:"max_downloads_size_unit,omitempty"`
+	SkipDedupe           *bool                   `json:"skip_dedupe,omitempty"`
 	MatchReleases        *string                 `json:"match_releases,omitempty"`
 	ExceptReleases       *string                 `json:"except_releases,omitempty"`
 	UseRegex             *bool                   `json:"use_regex,omitempty"`
@@ -237,6 +347,12 @@ type FilterUpdate struct {
 	Freeleech            *bool                   `json:"freeleech,omitempty"`
 	FreeleechPercent     *string                 `json:"freeleech_percent,omitempty"`
 	SmartEpisode         *bool                   `json:"smart_episode,omitempty"`
+	IgnoreSeasonPacks    *bool                   `json:"ignore_season_packs,omitempty"`
+	UpgradeReplace       *bool                   `json:"upgrade_replace,omitempty"`
+	GroupTiers           *[]FilterGroupTier      `json:"group_tiers,omitempty"`
+	SmartMusic           *bool                   `json:"smart_music,omitempty"`
+	SmartMusicMatch      *[]string               `json:"smart_music_match,omitempty"`
+	SmartDuplicateDays   *int                    `json:"smart_duplicate_days,omitempty"`
 	Shows                *string                 `json:"shows,omitempty"`
 	Seasons              *string                 `json:"seasons,omitempty"`
 	Episodes             *string                 `json:"episodes,omitempty"`
@@ -264,6 +380,7 @@ type FilterUpdate struct {
 	LogScore             *int                    `json:"log_score,omitempty"`
 	MatchCategories      *string                 `json:"match_categories,omitempty"`
 	ExceptCategories     *string                 `json:"except_categories,omitempty"`
+	NormalizeCategories  *bool                   `json:"normalize_categories,omitempty"`
 	MatchUploaders       *string                 `json:"match_uploaders,omitempty"`
 	ExceptUploaders      *string                 `json:"except_uploaders,omitempty"`
 	MatchLanguage        *[]string               `json:"match_language,omitempty"`
@@ -278,6 +395,11 @@ type FilterUpdate struct {
 	MaxSeeders           *int                    `json:"max_seeders,omitempty"`
 	MinLeechers          *int                    `json:"min_leechers,omitempty"`
 	MaxLeechers          *int                    `json:"max_leechers,omitempty"`
+	BreakerThreshold     *int                    `json:"breaker_threshold,omitempty"`
+	BreakerCooldown      *int                    `json:"breaker_cooldown,omitempty"`
+	BreakerFailOpen      *bool                   `json:"breaker_fail_open,omitempty"`
+	Schedule             *FilterSchedule         `json:"schedule,omitempty"`
+	AdvancedExpression   *string                 `json:"advanced_expression,omitempty"`
 	Actions              []*Action               `json:"actions,omitempty"`
 	External             []FilterExternal        `json:"external,omitempty"`
 	Indexers             []Indexer               `json:"indexers,omitempty"`
@@ -285,13 +407,51 @@ type FilterUpdate struct {
 
 func (f *Filter) Validate() error {
 	if f.Name == "" {
-		return errors.New("validation: name can't be empty")
+		return ValidationErrors{{Field: "name", Message: "name can't be empty"}}
 	}
 
 	if _, _, err := f.parsedSizeLimits(); err != nil {
 		return fmt.Errorf("error validating filter size limits: %w", err)
 	}
 
+	if f.MaxDownloadsSize != "" {
+		if _, err := humanize.ParseBytes(f.MaxDownloadsSize); err != nil {
+			return ValidationErrors{{Field: "max_downloads_size", Message: fmt.Sprintf("invalid size: %q", f.MaxDownloadsSize)}}
+		}
+
+		if f.MaxDownloadsSizeUnit == "" {
+			return ValidationErrors{{Field: "max_downloads_size_unit", Message: "unit is required for the max downloads size"}}
+		}
+	}
+
+	for _, field := range f.SmartMusicMatch {
+		switch field {
+		case SmartMusicMatchFormat, SmartMusicMatchBitrate, SmartMusicMatchMedia:
+		default:
+			return ValidationErrors{{Field: "smart_music_match", Message: fmt.Sprintf("unknown field %q, valid fields are format, bitrate and media", field)}}
+		}
+	}
+
+	if _, err := ParseMediaIDs(f.MatchIDs); err != nil {
+		return ValidationErrors{{Field: "match_ids", Message: err.Error()}}
+	}
+
+	if f.BreakerThreshold < 0 || f.BreakerCooldown < 0 {
+		return ValidationErrors{{Field: "breaker_threshold", Message: "breaker threshold and cooldown can't be negative"}}
+	}
+
+	if err := f.Schedule.Validate(); err != nil {
+		return err
+	}
+
+	if err := validateGroupTiers(f.GroupTiers); err != nil {
+		return err
+	}
+
+	if err := ValidateAdvancedExpression(f.AdvancedExpression); err != nil {
+		return err
+	}
+
 	for _, external := range f.External {
 		if external.Type == ExternalFilterTypeExec {
 			if external.ExecCmd != "" && external.Enabled {
@@ -361,6 +521,11 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 		return f.Rejections, false
 	}
 
+	if f.MaxDownloadsSize != "" && !f.checkMaxDownloadsSize(r.Size) {
+		f.addRejectionF("max downloads size (%s) this (%v) reached", f.MaxDownloadsSize, f.MaxDownloadsSizeUnit)
+		return f.Rejections, false
+	}
+
 	if len(f.Bonus) > 0 && !sliceContainsSlice(r.Bonus, f.Bonus) {
 		r.addRejectionF("bonus not matching. got: %v want: %v", r.Bonus, f.Bonus)
 	}
@@ -512,7 +677,7 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 		if r.Category != "" {
 			categories = append(categories, r.Category)
 		}
-		if !contains(r.Category, f.MatchCategories) && !containsAny(categories, f.MatchCategories) {
+		if !contains(r.Category, f.MatchCategories) && !containsAny(categories, f.MatchCategories) && !f.matchNormalizedCategories(r, categories) {
 			f.addRejectionF("category not matching. got: %v want: %v", strings.Join(categories, ","), f.MatchCategories)
 		}
 	}
@@ -634,6 +799,11 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 		}
 	}
 
+	// the advanced expression is checked last, it can reference anything on the release
+	if len(f.Rejections) == 0 && f.AdvancedExpression != "" {
+		f.checkAdvancedExpression(r)
+	}
+
 	if len(f.Rejections) > 0 {
 		return f.Rejections, false
 	}
@@ -641,6 +811,20 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 	return nil, true
 }
 
+// matchNormalizedCategories matches the categories mapped to the shared taxonomy, eg. Movies/HD, when enabled for the filter
+func (f *Filter) matchNormalizedCategories(r *Release, categories []string) bool {
+	if !f.NormalizeCategories {
+		return false
+	}
+
+	normalized := r.NormalizedCategories
+	if normalized == nil {
+		normalized = NormalizeCategories(categories)
+	}
+
+	return containsAny(normalized, f.MatchCategories)
+}
+
 func (f *Filter) checkMaxDownloads() bool {
 	if f.Downloads == nil {
 		return false
@@ -663,6 +847,40 @@ func (f *Filter) checkMaxDownloads() bool {
 	return count < f.MaxDownloads
 }
 
+// HasDownloadLimits reports whether the download counts must be loaded before checking the filter
+func (f *Filter) HasDownloadLimits() bool {
+	return f.MaxDownloads > 0 || f.MaxDownloadsSize != ""
+}
+
+// checkMaxDownloadsSize checks that the release fits in what is left of the byte budget of the current unit.
+// The budget resets at the start of the next hour, day, week or month like the max downloads.
+func (f *Filter) checkMaxDownloadsSize(size uint64) bool {
+	if f.Downloads == nil {
+		return false
+	}
+
+	limit, err := humanize.ParseBytes(f.MaxDownloadsSize)
+	if err != nil {
+		return false
+	}
+
+	var used uint64
+	switch f.MaxDownloadsSizeUnit {
+	case FilterMaxDownloadsHour:
+		used = f.Downloads.HourSize
+	case FilterMaxDownloadsDay:
+		used = f.Downloads.DaySize
+	case FilterMaxDownloadsWeek:
+		used = f.Downloads.WeekSize
+	case FilterMaxDownloadsMonth:
+		used = f.Downloads.MonthSize
+	case FilterMaxDownloadsEver:
+		used = f.Downloads.TotalSize
+	}
+
+	return used < limit && size <= limit-used
+}
+
 // isPerfectFLAC Perfect is "CD FLAC Cue Log 100% Lossless or 24bit Lossless"
 func (f *Filter) isPerfectFLAC(r *Release) bool {
 	if !contains(r.Source, "CD") {
@@ -1133,6 +1351,85 @@ func (f *Filter) CheckReleaseSize(releaseSize uint64) (bool, error) {
 	return true, nil
 }
 
+// MatchMediaIDs reports whether one of the resolved ids of the release is in the match ids of the filter
+func (f *Filter) MatchMediaIDs(ids MediaIDs) bool {
+	match, err := ParseMediaIDs(f.MatchIDs)
+	if err != nil {
+		f.AddRejectionF("match ids: %v", err)
+		return false
+	}
+
+	for _, key := range ids.Keys() {
+		if _, ok := match[key]; ok {
+			return true
+		}
+	}
+
+	f.AddRejectionF("match ids: %s not in match ids", ids.String())
+
+	return false
+}
+
+// HasLookupChecks reports if the filter matches on the ids the enrichment stage looks up for the release
+func (f *Filter) HasLookupChecks() bool {
+	return f.MatchIDs != "" || f.MatchMusicBrainz != ""
+}
+
+// HasFileChecks reports if the filter checks the file list of the torrent
+func (f *Filter) HasFileChecks() bool {
+	return f.MaxFiles > 0 || f.MatchFileExtensions != "" || f.ExceptFileExtensions != ""
+}
+
+// CheckFiles checks the file list of the torrent against the file count and file extensions of the filter
+func (f *Filter) CheckFiles(files []string) bool {
+	if f.MaxFiles > 0 && len(files) > f.MaxFiles {
+		f.addRejectionF("file count %d is more than filter max files %d", len(files), f.MaxFiles)
+		return false
+	}
+
+	if f.ExceptFileExtensions != "" {
+		except := parseFileExtensions(f.ExceptFileExtensions)
+
+		for _, file := range files {
+			if slices.Contains(except, fileExtension(file)) {
+				f.addRejectionF("except file extensions: unwanted file %s", file)
+				return false
+			}
+		}
+	}
+
+	if f.MatchFileExtensions != "" {
+		match := parseFileExtensions(f.MatchFileExtensions)
+
+		if !slices.ContainsFunc(files, func(file string) bool {
+			return slices.Contains(match, fileExtension(file))
+		}) {
+			f.addRejectionF("match file extensions: no file with extension %s", strings.Join(match, ", "))
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseFileExtensions parses a comma separated list of file extensions, with or without the leading dot
+func parseFileExtensions(s string) []string {
+	var extensions []string
+
+	for _, ext := range strings.Split(s, ",") {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext != "" {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	return extensions
+}
+
+func fileExtension(file string) string {
+	return strings.ToLower(strings.TrimPrefix(path.Ext(file), "."))
+}
+
 // parsedSizeLimits parses filter bytes limits (expressed as a string) into a
 // uint64 number of bytes. The bounds are returned as *uint64 number of bytes,
 // with "nil" representing "no limit". We break out filter size limit parsing
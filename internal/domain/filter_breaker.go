@@ -0,0 +1,92 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"time"
+)
+
+type FilterBreakerState string
+
+const (
+	FilterBreakerStateClosed   FilterBreakerState = "CLOSED"
+	FilterBreakerStateOpen     FilterBreakerState = "OPEN"
+	FilterBreakerStateHalfOpen FilterBreakerState = "HALF_OPEN"
+)
+
+const DefaultFilterBreakerCooldown = 5 * time.Minute
+
+// FilterBreaker is the circuit breaker for the external checks and indexer api lookups of a filter.
+// The state is kept in memory and reset on restart or when the filter is updated.
+type FilterBreaker struct {
+	State         FilterBreakerState `json:"state"`
+	Failures      int                `json:"failures"`
+	Trips         int                `json:"trips"`
+	Bypassed      int                `json:"bypassed"`
+	LastError     string             `json:"last_error,omitempty"`
+	LastFailureAt *time.Time         `json:"last_failure_at,omitempty"`
+	OpenUntil     *time.Time         `json:"open_until,omitempty"`
+}
+
+// FilterBreakers are the breakers of a filter by pipeline stage
+type FilterBreakers map[PipelineStage]*FilterBreaker
+
+func NewFilterBreaker() *FilterBreaker {
+	return &FilterBreaker{State: FilterBreakerStateClosed}
+}
+
+// BreakerCooldownDuration returns how long the breaker stays open before a probe is let through
+func (f *Filter) BreakerCooldownDuration() time.Duration {
+	if f.BreakerCooldown <= 0 {
+		return DefaultFilterBreakerCooldown
+	}
+	return time.Duration(f.BreakerCooldown) * time.Second
+}
+
+// Allow reports whether the checks should run. Once the cooldown of an open breaker has passed
+// a single probe is allowed, other checks are bypassed until the probe reports back or the cooldown passes again.
+func (b *FilterBreaker) Allow(now time.Time, cooldown time.Duration) bool {
+	if b.State == FilterBreakerStateClosed {
+		return true
+	}
+
+	if b.OpenUntil != nil && now.Before(*b.OpenUntil) {
+		return false
+	}
+
+	openUntil := now.Add(cooldown)
+
+	b.State = FilterBreakerStateHalfOpen
+	b.OpenUntil = &openUntil
+
+	return true
+}
+
+func (b *FilterBreaker) Success() {
+	b.State = FilterBreakerStateClosed
+	b.Failures = 0
+	b.OpenUntil = nil
+}
+
+// Failure records a failed check and opens the breaker after threshold consecutive failures or a failed probe.
+// It returns true when the breaker was opened.
+func (b *FilterBreaker) Failure(now time.Time, err error, threshold int, cooldown time.Duration) bool {
+	b.Failures++
+	b.LastFailureAt = &now
+	if err != nil {
+		b.LastError = err.Error()
+	}
+
+	if b.State != FilterBreakerStateHalfOpen && b.Failures < threshold {
+		return false
+	}
+
+	openUntil := now.Add(cooldown)
+
+	b.State = FilterBreakerStateOpen
+	b.OpenUntil = &openUntil
+	b.Trips++
+
+	return true
+}
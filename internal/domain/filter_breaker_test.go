@@ -0,0 +1,58 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := time.Minute
+	errTimeout := errors.New("webhook timeout")
+
+	b := NewFilterBreaker()
+	assert.True(t, b.Allow(now, cooldown))
+
+	// opens after threshold consecutive failures
+	assert.False(t, b.Failure(now, errTimeout, 2, cooldown))
+	assert.Equal(t, FilterBreakerStateClosed, b.State)
+	assert.True(t, b.Failure(now, errTimeout, 2, cooldown))
+	assert.Equal(t, FilterBreakerStateOpen, b.State)
+	assert.Equal(t, 1, b.Trips)
+	assert.Equal(t, "webhook timeout", b.LastError)
+
+	// bypassed until the cooldown has passed
+	assert.False(t, b.Allow(now.Add(30*time.Second), cooldown))
+
+	// a single probe after the cooldown
+	assert.True(t, b.Allow(now.Add(cooldown), cooldown))
+	assert.Equal(t, FilterBreakerStateHalfOpen, b.State)
+	assert.False(t, b.Allow(now.Add(cooldown+time.Second), cooldown))
+
+	// a failed probe opens the breaker again
+	assert.True(t, b.Failure(now.Add(cooldown), errTimeout, 2, cooldown))
+	assert.Equal(t, FilterBreakerStateOpen, b.State)
+	assert.Equal(t, 2, b.Trips)
+
+	// a successful probe closes it
+	assert.True(t, b.Allow(now.Add(2*cooldown), cooldown))
+	b.Success()
+	assert.Equal(t, FilterBreakerStateClosed, b.State)
+	assert.Equal(t, 0, b.Failures)
+	assert.Nil(t, b.OpenUntil)
+	assert.True(t, b.Allow(now.Add(2*cooldown), cooldown))
+}
+
+func TestFilter_BreakerCooldownDuration(t *testing.T) {
+	f := &Filter{}
+	assert.Equal(t, DefaultFilterBreakerCooldown, f.BreakerCooldownDuration())
+
+	f.BreakerCooldown = 30
+	assert.Equal(t, 30*time.Second, f.BreakerCooldownDuration())
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type FilterCloneRequest struct {
+	Name            string `json:"name"`
+	IncludeActions  bool   `json:"include_actions"`
+	IncludeExternal bool   `json:"include_external"`
+}
+
+// FilterFieldDiff is a changed field, keyed by its json name
+type FilterFieldDiff struct {
+	Field  string `json:"field"`
+	Source any    `json:"source"`
+	Target any    `json:"target"`
+}
+
+type FilterCloneResponse struct {
+	SourceID int               `json:"source_id"`
+	Filter   *Filter           `json:"filter"`
+	Diff     []FilterFieldDiff `json:"diff"`
+}
+
+type FilterBulkOperation string
+
+const (
+	FilterBulkOperationSet    FilterBulkOperation = "SET"
+	FilterBulkOperationAdd    FilterBulkOperation = "ADD"
+	FilterBulkOperationRemove FilterBulkOperation = "REMOVE"
+)
+
+// FilterBulkUpdateRequest applies the same change to a field of multiple filters.
+// Add and remove work with list fields and comma separated string fields, eg. add 2160p to resolutions.
+type FilterBulkUpdateRequest struct {
+	FilterIDs []int               `json:"filter_ids"`
+	Operation FilterBulkOperation `json:"operation"`
+	Field     string              `json:"field"`
+	Value     json.RawMessage     `json:"value"`
+	DryRun    bool                `json:"dry_run"`
+}
+
+type FilterBulkUpdateResult struct {
+	FilterID int               `json:"filter_id"`
+	Name     string            `json:"name"`
+	Diff     []FilterFieldDiff `json:"diff"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// filterDiffIgnoredFields are not compared field by field
+var filterDiffIgnoredFields = []string{"id", "created_at", "updated_at", "actions", "external", "actions_count", "actions_enabled_count"}
+
+// filterBulkIgnoredFields can not be changed with a bulk update
+var filterBulkIgnoredFields = []string{"id", "name", "created_at", "updated_at", "actions", "external", "indexers", "actions_count", "actions_enabled_count"}
+
+func (r *FilterBulkUpdateRequest) Validate() error {
+	if len(r.FilterIDs) == 0 {
+		return errors.New("filter_ids required")
+	}
+
+	if r.Field == "" {
+		return errors.New("field required")
+	}
+
+	if slices.Contains(filterBulkIgnoredFields, r.Field) {
+		return errors.New("field %s can not be bulk updated", r.Field)
+	}
+
+	switch r.Operation {
+	case FilterBulkOperationSet, FilterBulkOperationAdd, FilterBulkOperationRemove:
+	default:
+		return errors.New("invalid operation: %s", r.Operation)
+	}
+
+	if len(r.Value) == 0 {
+		return errors.New("value required")
+	}
+
+	return nil
+}
+
+// FilterUpdate returns the partial update for the filter and the resulting diff.
+// The update is nil if nothing would change.
+func (r *FilterBulkUpdateRequest) FilterUpdate(f *Filter) (*FilterUpdate, []FilterFieldDiff, error) {
+	fields, err := filterFields(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(r.Value, &value); err != nil {
+		return nil, nil, errors.Wrap(err, "could not decode value")
+	}
+
+	current := fields[r.Field]
+
+	var updated any
+
+	switch r.Operation {
+	case FilterBulkOperationSet:
+		updated = value
+
+	case FilterBulkOperationAdd, FilterBulkOperationRemove:
+		updated, err = updateFilterList(current, value, r.Operation == FilterBulkOperationAdd)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not update field %s", r.Field)
+		}
+	}
+
+	if reflect.DeepEqual(current, updated) {
+		return nil, []FilterFieldDiff{}, nil
+	}
+
+	data, err := json.Marshal(map[string]any{"id": f.ID, r.Field: updated})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var update FilterUpdate
+	if err := json.Unmarshal(data, &update); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid value for field %s", r.Field)
+	}
+
+	// unknown fields are silently dropped by the decoder so check it made it into the update
+	check, err := filterFields(update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := check[r.Field]; !ok {
+		return nil, nil, errors.New("field %s can not be bulk updated", r.Field)
+	}
+
+	return &update, []FilterFieldDiff{{Field: r.Field, Source: current, Target: updated}}, nil
+}
+
+// updateFilterList adds or removes values from a list or comma separated string
+func updateFilterList(current any, value any, add bool) (any, error) {
+	var values []string
+
+	switch v := value.(type) {
+	case string:
+		values = splitFilterList(v)
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, errors.New("list values must be strings")
+			}
+			values = append(values, s)
+		}
+	default:
+		return nil, errors.New("value must be a string or list of strings")
+	}
+
+	var list []string
+	isString := false
+
+	switch c := current.(type) {
+	case nil:
+	case string:
+		isString = true
+		list = splitFilterList(c)
+	case []any:
+		for _, item := range c {
+			s, _ := item.(string)
+			list = append(list, s)
+		}
+	default:
+		return nil, errors.New("field is not a list")
+	}
+
+	for _, v := range values {
+		if add {
+			if !slices.Contains(list, v) {
+				list = append(list, v)
+			}
+			continue
+		}
+
+		list = slices.DeleteFunc(list, func(s string) bool {
+			return s == v
+		})
+	}
+
+	if isString {
+		return strings.Join(list, ","), nil
+	}
+
+	result := make([]any, 0, len(list))
+	for _, s := range list {
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+func splitFilterList(s string) []string {
+	list := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// DiffFilters returns the fields that differ between source and target.
+// Actions and external filters are only compared by count.
+func DiffFilters(source, target *Filter) ([]FilterFieldDiff, error) {
+	sourceFields, err := filterFields(source)
+	if err != nil {
+		return nil, err
+	}
+
+	targetFields, err := filterFields(target)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(sourceFields)+len(targetFields))
+	for k := range sourceFields {
+		keys = append(keys, k)
+	}
+	for k := range targetFields {
+		if _, ok := sourceFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	diff := make([]FilterFieldDiff, 0)
+
+	for _, k := range keys {
+		if slices.Contains(filterDiffIgnoredFields, k) {
+			continue
+		}
+
+		if !reflect.DeepEqual(sourceFields[k], targetFields[k]) {
+			diff = append(diff, FilterFieldDiff{Field: k, Source: sourceFields[k], Target: targetFields[k]})
+		}
+	}
+
+	if len(source.Actions) != len(target.Actions) {
+		diff = append(diff, FilterFieldDiff{Field: "actions", Source: len(source.Actions), Target: len(target.Actions)})
+	}
+
+	if len(source.External) != len(target.External) {
+		diff = append(diff, FilterFieldDiff{Field: "external", Source: len(source.External), Target: len(target.External)})
+	}
+
+	return diff, nil
+}
+
+// filterFields returns the json representation of a filter as a map
+func filterFields(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode filter")
+	}
+
+	fields := make(map[string]any)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.Wrap(err, "could not decode filter")
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterBulkUpdateRequest_FilterUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *Filter
+		req      FilterBulkUpdateRequest
+		wantDiff []FilterFieldDiff
+		wantNil  bool
+		wantErr  bool
+	}{
+		{
+			name:   "add_resolution",
+			filter: &Filter{ID: 1, Resolutions: []string{"1080p"}},
+			req:    FilterBulkUpdateRequest{Operation: FilterBulkOperationAdd, Field: "resolutions", Value: json.RawMessage(`"2160p"`)},
+			wantDiff: []FilterFieldDiff{
+				{Field: "resolutions", Source: []any{"1080p"}, Target: []any{"1080p", "2160p"}},
+			},
+		},
+		{
+			name:    "add_existing_resolution",
+			filter:  &Filter{ID: 1, Resolutions: []string{"1080p", "2160p"}},
+			req:     FilterBulkUpdateRequest{Operation: FilterBulkOperationAdd, Field: "resolutions", Value: json.RawMessage(`["2160p"]`)},
+			wantNil: true,
+		},
+		{
+			name:   "remove_release_group",
+			filter: &Filter{ID: 1, MatchReleaseGroups: "GRP1, GRP2,GRP3"},
+			req:    FilterBulkUpdateRequest{Operation: FilterBulkOperationRemove, Field: "match_release_groups", Value: json.RawMessage(`"GRP2"`)},
+			wantDiff: []FilterFieldDiff{
+				{Field: "match_release_groups", Source: "GRP1, GRP2,GRP3", Target: "GRP1,GRP3"},
+			},
+		},
+		{
+			name:   "set_max_size",
+			filter: &Filter{ID: 1, MaxSize: "10GB"},
+			req:    FilterBulkUpdateRequest{Operation: FilterBulkOperationSet, Field: "max_size", Value: json.RawMessage(`"20GB"`)},
+			wantDiff: []FilterFieldDiff{
+				{Field: "max_size", Source: "10GB", Target: "20GB"},
+			},
+		},
+		{
+			name:    "unknown_field",
+			filter:  &Filter{ID: 1},
+			req:     FilterBulkUpdateRequest{Operation: FilterBulkOperationSet, Field: "does_not_exist", Value: json.RawMessage(`"x"`)},
+			wantErr: true,
+		},
+		{
+			name:    "add_to_non_list",
+			filter:  &Filter{ID: 1, Priority: 5},
+			req:     FilterBulkUpdateRequest{Operation: FilterBulkOperationAdd, Field: "priority", Value: json.RawMessage(`"1"`)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update, diff, err := tt.req.FilterUpdate(tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if tt.wantNil {
+				assert.Nil(t, update)
+				assert.Empty(t, diff)
+				return
+			}
+
+			assert.NotNil(t, update)
+			assert.Equal(t, tt.filter.ID, update.ID)
+			assert.Equal(t, tt.wantDiff, diff)
+		})
+	}
+}
+
+func TestDiffFilters(t *testing.T) {
+	source := &Filter{ID: 1, Name: "movies", Enabled: true, Resolutions: []string{"1080p"}, Actions: []*Action{{Name: "qbit"}}}
+	target := &Filter{ID: 2, Name: "movies Copy", Enabled: false, Resolutions: []string{"1080p"}}
+
+	diff, err := DiffFilters(source, target)
+	assert.NoError(t, err)
+	assert.Equal(t, []FilterFieldDiff{
+		{Field: "enabled", Source: true, Target: false},
+		{Field: "name", Source: "movies", Target: "movies Copy"},
+		{Field: "actions", Source: 1, Target: 0},
+	}, diff)
+}
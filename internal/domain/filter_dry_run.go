@@ -0,0 +1,139 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	FilterDryRunDefaultLimit = 100
+	FilterDryRunMaxLimit     = 1000
+)
+
+// FilterDryRunRequest replays stored releases through a saved filter, or through
+// an unsaved filter definition when Filter is set.
+type FilterDryRunRequest struct {
+	FilterID int     `json:"filter_id"`
+	Filter   *Filter `json:"filter,omitempty"`
+	Limit    int     `json:"limit"`
+}
+
+func (r *FilterDryRunRequest) Validate() error {
+	if r.FilterID == 0 && r.Filter == nil {
+		return ValidationErrors{{Field: "filter_id", Message: "filter id or filter is required"}}
+	}
+
+	if r.Limit < 0 || r.Limit > FilterDryRunMaxLimit {
+		return ValidationErrors{{Field: "limit", Message: "limit must be between 0 and 1000"}}
+	}
+
+	return nil
+}
+
+type FilterDryRunResult struct {
+	ReleaseID   int64     `json:"release_id"`
+	TorrentName string    `json:"name"`
+	Indexer     string    `json:"indexer"`
+	Size        uint64    `json:"size"`
+	Timestamp   time.Time `json:"timestamp"`
+	Match       bool      `json:"match"`
+	Rejections  []string  `json:"rejections"`
+	// Skipped lists the checks of the filter that need release data which is not stored
+	Skipped []string `json:"skipped"`
+	// SizeUnknown is set when the release was matched without a size and the
+	// out of band size check was skipped
+	SizeUnknown bool `json:"size_unknown"`
+}
+
+type FilterDryRunResponse struct {
+	FilterID   int                  `json:"filter_id"`
+	FilterName string               `json:"filter_name"`
+	Checked    int                  `json:"checked"`
+	Matched    int                  `json:"matched"`
+	Results    []FilterDryRunResult `json:"results"`
+}
+
+// DryRun checks a stored release against the filter without changing the filter or the release.
+// Only the fields stored with the release are available, the release name is parsed again for the rest.
+// Checks on announce data that is not stored are skipped instead of rejecting the release.
+// Checks with side effects like external filters, smart episode and out of band size checks are not run.
+func (f *Filter) DryRun(stored *Release) FilterDryRunResult {
+	result := FilterDryRunResult{
+		ReleaseID:   stored.ID,
+		TorrentName: stored.TorrentName,
+		Indexer:     stored.Indexer.Identifier,
+		Size:        stored.Size,
+		Timestamp:   stored.Timestamp,
+		Rejections:  []string{},
+		Skipped:     []string{},
+	}
+
+	if len(f.Indexers) > 0 && !slices.ContainsFunc(f.Indexers, func(indexer Indexer) bool {
+		return indexer.Identifier == stored.Indexer.Identifier
+	}) {
+		result.Rejections = append(result.Rejections, "indexer not enabled for filter: "+stored.Indexer.Identifier)
+		return result
+	}
+
+	release := NewRelease(stored.Indexer)
+	release.ID = stored.ID
+	release.Protocol = stored.Protocol
+	release.Timestamp = stored.Timestamp
+	release.Size = stored.Size
+	release.Category = stored.Category
+	release.Group = stored.Group
+	release.ParseString(stored.TorrentName)
+	release.Origin = stored.Origin
+	release.Uploader = stored.Uploader
+	if stored.Tags != nil {
+		release.Tags = stored.Tags
+	}
+	if stored.Artists != "" {
+		release.Artists = stored.Artists
+	}
+
+	// copy so the rejections of the filter are left untouched
+	check := *f
+	check.Rejections = nil
+
+	result.Skipped = append(result.Skipped, check.skipUnstoredChecks()...)
+
+	rejections, match := check.CheckFilter(release)
+
+	result.Match = match
+	result.Rejections = append(result.Rejections, rejections...)
+	result.SizeUnknown = match && release.AdditionalSizeCheckRequired
+
+	return result
+}
+
+// skipUnstoredChecks clears the checks that depend on announce data which is not stored with the release
+// and returns their names. It must only be called on a copy of the filter.
+func (f *Filter) skipUnstoredChecks() []string {
+	var skipped []string
+
+	skip := func(name string, set bool, clear func()) {
+		if set {
+			skipped = append(skipped, name)
+			clear()
+		}
+	}
+
+	skip("bonus", len(f.Bonus) > 0, func() { f.Bonus = nil })
+	skip("freeleech", f.Freeleech, func() { f.Freeleech = false })
+	skip("freeleech percent", f.FreeleechPercent != "", func() { f.FreeleechPercent = "" })
+	skip("release tags", f.MatchReleaseTags != "" || f.ExceptReleaseTags != "", func() { f.MatchReleaseTags, f.ExceptReleaseTags = "", "" })
+	skip("description", f.MatchDescription != "" || f.ExceptDescription != "", func() { f.MatchDescription, f.ExceptDescription = "", "" })
+	skip("perfect flac", f.PerfectFlac, func() { f.PerfectFlac = false })
+	skip("formats", len(f.Formats) > 0, func() { f.Formats = nil })
+	skip("quality", len(f.Quality) > 0, func() { f.Quality = nil })
+	skip("cue", f.Cue, func() { f.Cue = false })
+	skip("log", f.Log, func() { f.Log, f.LogScore = false, 0 })
+	skip("seeders", f.MinSeeders > 0 || f.MaxSeeders > 0, func() { f.MinSeeders, f.MaxSeeders = 0, 0 })
+	skip("leechers", f.MinLeechers > 0 || f.MaxLeechers > 0, func() { f.MinLeechers, f.MaxLeechers = 0, 0 })
+
+	return skipped
+}
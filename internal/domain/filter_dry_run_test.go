@@ -0,0 +1,97 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_DryRun(t *testing.T) {
+	stored := &Release{
+		ID:          1,
+		Indexer:     IndexerMinimal{Identifier: "mock"},
+		TorrentName: "That.Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		Size:        2 * 1024 * 1024 * 1024,
+		Uploader:    "uploader",
+		Origin:      "P2P",
+		Tags:        []string{"tv"},
+	}
+
+	tests := []struct {
+		name           string
+		filter         *Filter
+		release        *Release
+		wantMatch      bool
+		wantRejections int
+		wantSize       bool
+		wantSkipped    []string
+	}{
+		{
+			name:      "match",
+			filter:    &Filter{Shows: "That Show", Resolutions: []string{"1080p"}, MaxSize: "10GB"},
+			release:   stored,
+			wantMatch: true,
+		},
+		{
+			name:           "reject_size_and_resolution",
+			filter:         &Filter{Resolutions: []string{"2160p"}, MaxSize: "1GB"},
+			release:        stored,
+			wantRejections: 3,
+		},
+		{
+			name:           "reject_indexer",
+			filter:         &Filter{Indexers: []Indexer{{Identifier: "other"}}},
+			release:        stored,
+			wantRejections: 1,
+		},
+		{
+			name:      "stored_fields",
+			filter:    &Filter{MatchUploaders: "uploader", Origins: []string{"P2P"}, Tags: "tv"},
+			release:   stored,
+			wantMatch: true,
+		},
+		{
+			name:           "reject_stored_fields",
+			filter:         &Filter{ExceptUploaders: "uploader", ExceptOrigins: []string{"P2P"}},
+			release:        stored,
+			wantRejections: 2,
+		},
+		{
+			name:        "skip_unstored_fields",
+			filter:      &Filter{Freeleech: true, MatchDescription: "*internal*", MinSeeders: 10},
+			release:     stored,
+			wantMatch:   true,
+			wantSkipped: []string{"freeleech", "description", "seeders"},
+		},
+		{
+			name:      "size_unknown",
+			filter:    &Filter{MaxSize: "1GB"},
+			release:   &Release{ID: 2, Indexer: IndexerMinimal{Identifier: "mock"}, TorrentName: stored.TorrentName},
+			wantMatch: true,
+			wantSize:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// rejections of earlier runs must not leak into the next one
+			for range 2 {
+				got := tt.filter.DryRun(tt.release)
+				assert.Equal(t, tt.release.ID, got.ReleaseID)
+				assert.Equal(t, tt.wantMatch, got.Match)
+				assert.Len(t, got.Rejections, tt.wantRejections)
+				assert.Equal(t, tt.wantSize, got.SizeUnknown)
+
+				if tt.wantSkipped == nil {
+					assert.Empty(t, got.Skipped)
+				} else {
+					assert.Equal(t, tt.wantSkipped, got.Skipped)
+				}
+			}
+
+			assert.Empty(t, tt.filter.Rejections)
+		})
+	}
+}
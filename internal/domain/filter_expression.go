@@ -0,0 +1,214 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/cel-go/cel"
+	celtypes "github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// expressionRelease is the release as seen by advanced expressions. Only the fields listed here
+// can be used, credentials and the raw torrent data of the release are never exposed.
+type expressionRelease struct {
+	Indexer          expressionIndexer
+	Protocol         string
+	Implementation   string
+	Timestamp        time.Time
+	TorrentName      string
+	Size             uint64
+	Title            string
+	Category         string
+	Categories       []string
+	Season           int
+	Episode          int
+	Year             int
+	Month            int
+	Day              int
+	Resolution       string
+	Source           string
+	Codec            []string
+	Container        string
+	HDR              []string
+	Audio            []string
+	AudioChannels    string
+	Group            string
+	Region           string
+	Language         []string
+	Proper           bool
+	Repack           bool
+	Website          string
+	Artists          string
+	Type             string
+	Origin           string
+	Tags             []string
+	ReleaseTags      string
+	Freeleech        bool
+	FreeleechPercent int
+	Bonus            []string
+	Uploader         string
+	PreTime          string
+	Other            []string
+	Seeders          int
+	Leechers         int
+	Description      string
+}
+
+type expressionIndexer struct {
+	Name       string
+	Identifier string
+}
+
+func newExpressionRelease(r *Release) *expressionRelease {
+	return &expressionRelease{
+		Indexer:          expressionIndexer{Name: r.Indexer.Name, Identifier: r.Indexer.Identifier},
+		Protocol:         string(r.Protocol),
+		Implementation:   string(r.Implementation),
+		Timestamp:        r.Timestamp,
+		TorrentName:      r.TorrentName,
+		Size:             r.Size,
+		Title:            r.Title,
+		Category:         r.Category,
+		Categories:       r.Categories,
+		Season:           r.Season,
+		Episode:          r.Episode,
+		Year:             r.Year,
+		Month:            r.Month,
+		Day:              r.Day,
+		Resolution:       r.Resolution,
+		Source:           r.Source,
+		Codec:            r.Codec,
+		Container:        r.Container,
+		HDR:              r.HDR,
+		Audio:            r.Audio,
+		AudioChannels:    r.AudioChannels,
+		Group:            r.Group,
+		Region:           r.Region,
+		Language:         r.Language,
+		Proper:           r.Proper,
+		Repack:           r.Repack,
+		Website:          r.Website,
+		Artists:          r.Artists,
+		Type:             r.Type,
+		Origin:           r.Origin,
+		Tags:             r.Tags,
+		ReleaseTags:      r.ReleaseTags,
+		Freeleech:        r.Freeleech,
+		FreeleechPercent: r.FreeleechPercent,
+		Bonus:            r.Bonus,
+		Uploader:         r.Uploader,
+		PreTime:          r.PreTime,
+		Other:            r.Other,
+		Seeders:          r.Seeders,
+		Leechers:         r.Leechers,
+		Description:      r.Description,
+	}
+}
+
+// expressionEnv declares the release and the parseSize function for advanced expressions, eg.
+//
+//	release.Size > parseSize("5GB") && release.Group in ["A", "B"] && !release.Proper
+var expressionEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		ext.NativeTypes(reflect.TypeOf(&expressionRelease{})),
+		ext.Strings(),
+		cel.CrossTypeNumericComparisons(true),
+		cel.Variable("release", cel.ObjectType("domain.expressionRelease")),
+		cel.Function("parseSize",
+			cel.Overload("parse_size_string", []*cel.Type{cel.StringType}, cel.UintType,
+				cel.UnaryBinding(func(value ref.Val) ref.Val {
+					size, err := humanize.ParseBytes(fmt.Sprint(value.Value()))
+					if err != nil {
+						return celtypes.NewErr("invalid size: %v", value.Value())
+					}
+					return celtypes.Uint(size)
+				}),
+			),
+		),
+	)
+})
+
+var expressionCache = ttlcache.New[string, cel.Program](
+	ttlcache.WithTTL[string, cel.Program](30 * time.Minute),
+)
+
+func init() {
+	go expressionCache.Start()
+}
+
+// compileExpression type checks the expression against the release and requires a bool result
+func compileExpression(source string) (cel.Program, error) {
+	env, err := expressionEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create expression environment")
+	}
+
+	ast, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, errors.New("expression must return bool, got %s", ast.OutputType())
+	}
+
+	return env.Program(ast)
+}
+
+// compileExpressionCached returns the cached program for the expression, or compiles and caches it
+func compileExpressionCached(source string) (cel.Program, error) {
+	if item := expressionCache.Get(source); item != nil {
+		return item.Value(), nil
+	}
+
+	program, err := compileExpression(source)
+	if err != nil {
+		return nil, err
+	}
+
+	expressionCache.Set(source, program, ttlcache.DefaultTTL)
+
+	return program, nil
+}
+
+// ValidateAdvancedExpression checks the syntax and the release fields used by the expression
+func ValidateAdvancedExpression(source string) error {
+	if source == "" {
+		return nil
+	}
+
+	if _, err := compileExpression(source); err != nil {
+		return ValidationErrors{{Field: "advanced_expression", Message: fmt.Sprintf("invalid expression: %v", err)}}
+	}
+
+	return nil
+}
+
+func (f *Filter) checkAdvancedExpression(r *Release) {
+	program, err := compileExpressionCached(f.AdvancedExpression)
+	if err != nil {
+		f.addRejectionF("advanced expression invalid: %v", err)
+		return
+	}
+
+	out, _, err := program.Eval(map[string]any{"release": newExpressionRelease(r)})
+	if err != nil {
+		f.addRejectionF("advanced expression error: %v", err)
+		return
+	}
+
+	if match, ok := out.Value().(bool); !ok || !match {
+		f.addRejectionF("advanced expression not matching: %s", f.AdvancedExpression)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_CheckFilter_AdvancedExpression(t *testing.T) {
+	newRelease := func(name string, size uint64) *Release {
+		r := NewRelease(IndexerMinimal{Identifier: "mock"})
+		r.ParseString(name)
+		r.Size = size
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		release    *Release
+		want       bool
+	}{
+		{
+			name:       "match",
+			expression: `release.Size > parseSize("5GB") && release.Group in ["A", "B"] && !release.Proper`,
+			release:    newRelease("That.Show.S01E02.2160p.WEB-DL.DDP5.1.H.265-A", 8_000_000_000),
+			want:       true,
+		},
+		{
+			name:       "proper",
+			expression: `release.Size > parseSize("5GB") && release.Group in ["A", "B"] && !release.Proper`,
+			release:    newRelease("That.Show.S01E02.PROPER.2160p.WEB-DL.DDP5.1.H.265-A", 8_000_000_000),
+			want:       false,
+		},
+		{
+			name:       "indexer_and_resolution",
+			expression: `release.Indexer.Identifier == "mock" && release.Resolution == "2160p"`,
+			release:    newRelease("That.Show.S01E02.2160p.WEB-DL.DDP5.1.H.265-A", 1),
+			want:       true,
+		},
+		{
+			name:       "functions",
+			expression: `release.TorrentName.matches("(?i)web-?dl") && release.Title.lowerAscii().startsWith("that") && "DDP" in release.Audio && release.Size >= 1`,
+			release:    newRelease("That.Show.S01E02.2160p.WEB-DL.DDP5.1.H.265-A", 1),
+			want:       true,
+		},
+		{
+			name:       "runtime_error",
+			expression: `release.Season / 0 > 1`,
+			release:    newRelease("That.Show.S01E02.2160p.WEB-DL.DDP5.1.H.265-A", 1),
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{AdvancedExpression: tt.expression}
+
+			rejections, got := f.CheckFilter(tt.release)
+			assert.Equal(t, tt.want, got)
+			if !tt.want {
+				assert.Len(t, rejections, 1)
+			}
+		})
+	}
+}
+
+func TestValidateAdvancedExpression(t *testing.T) {
+	assert.NoError(t, ValidateAdvancedExpression(""))
+	assert.NoError(t, ValidateAdvancedExpression(`release.Size > parseSize("5GB") && "HDR" in release.HDR`))
+	assert.Error(t, ValidateAdvancedExpression(`release.Size >`))
+	assert.Error(t, ValidateAdvancedExpression(`release.Sise > 5u`))
+	assert.Error(t, ValidateAdvancedExpression(`rls.Size > 5u`))
+	assert.Error(t, ValidateAdvancedExpression(`release.Group > 1`))
+	assert.Error(t, ValidateAdvancedExpression(`release.Size`))
+
+	// only the fields of the expression release are available
+	assert.Error(t, ValidateAdvancedExpression(`release.RawCookie != ""`))
+	assert.Error(t, ValidateAdvancedExpression(`size(release.TorrentDataRawBytes) > 0`))
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type FilterGroupRepo interface {
+	List(ctx context.Context) ([]FilterGroup, error)
+	FindByID(ctx context.Context, groupID int) (*FilterGroup, error)
+	Store(ctx context.Context, group *FilterGroup) error
+	Update(ctx context.Context, group *FilterGroup) error
+	ToggleEnabled(ctx context.Context, groupID int, enabled bool) error
+	Delete(ctx context.Context, groupID int) error
+	MoveFilters(ctx context.Context, groupID int, filterIDs []int) error
+}
+
+// FilterGroup organizes filters. A disabled group disables all of its filters, and filters are
+// checked by group priority first and filter priority second. Filters without a group have group priority 0.
+type FilterGroup struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Enabled     bool      `json:"enabled"`
+	Priority    int32     `json:"priority"`
+	FilterCount int       `json:"filter_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (g *FilterGroup) Validate() error {
+	if g.Name == "" {
+		return ValidationErrors{{Field: "name", Message: "name can't be empty"}}
+	}
+
+	return nil
+}
+
+// FilterGroupMoveRequest moves filters into a group, group id 0 removes them from their group
+type FilterGroupMoveRequest struct {
+	GroupID   int   `json:"group_id"`
+	FilterIDs []int `json:"filter_ids"`
+}
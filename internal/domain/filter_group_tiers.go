@@ -0,0 +1,53 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FilterGroupTier accepts releases of its groups once the delay has passed since the first matching
+// release of the same episode or movie was seen. Tiers are ordered by preference, * matches any group.
+type FilterGroupTier struct {
+	Groups       []string `json:"groups"`
+	DelayMinutes int      `json:"delay_minutes"`
+}
+
+func (t FilterGroupTier) Delay() time.Duration {
+	return time.Duration(t.DelayMinutes) * time.Minute
+}
+
+func validateGroupTiers(tiers []FilterGroupTier) error {
+	for idx, tier := range tiers {
+		if len(tier.Groups) == 0 {
+			return ValidationErrors{{Field: fmt.Sprintf("group_tiers[%d].groups", idx), Message: "groups can't be empty"}}
+		}
+
+		if tier.DelayMinutes < 0 {
+			return ValidationErrors{{Field: fmt.Sprintf("group_tiers[%d].delay_minutes", idx), Message: "delay can't be negative"}}
+		}
+
+		// a preferred tier waiting longer than a fallback would never be used
+		if idx > 0 && tier.DelayMinutes < tiers[idx-1].DelayMinutes {
+			return ValidationErrors{{Field: fmt.Sprintf("group_tiers[%d].delay_minutes", idx), Message: "delay can't be shorter than the delay of the tier before"}}
+		}
+	}
+
+	return nil
+}
+
+// GroupTier returns the index of the first tier with the group, or -1 when no tier has it
+func (f *Filter) GroupTier(group string) int {
+	for idx, tier := range f.GroupTiers {
+		for _, g := range tier.Groups {
+			if g == "*" || strings.EqualFold(strings.TrimSpace(g), group) {
+				return idx
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var scheduleWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// FilterSchedule limits the times a filter is active. A filter without windows is always active.
+type FilterSchedule struct {
+	Timezone string                 `json:"timezone,omitempty"` // IANA name, eg. Europe/Stockholm. Defaults to the local timezone
+	Windows  []FilterScheduleWindow `json:"windows,omitempty"`
+}
+
+// FilterScheduleWindow is active from Start to End on Days. Times are HH:MM, a window with an End before
+// its Start continues past midnight and belongs to the day it starts on. Start equal to End is the whole day.
+type FilterScheduleWindow struct {
+	Days  []string `json:"days,omitempty"` // sun, mon, tue, wed, thu, fri, sat. Empty is every day
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+func (s *FilterSchedule) IsSet() bool {
+	return s != nil && len(s.Windows) > 0
+}
+
+func (s *FilterSchedule) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if _, err := s.location(); err != nil {
+		return ValidationErrors{{Field: "schedule.timezone", Message: fmt.Sprintf("unknown timezone %q", s.Timezone)}}
+	}
+
+	for idx, w := range s.Windows {
+		for _, day := range w.Days {
+			if _, ok := scheduleWeekdays[strings.ToLower(day)]; !ok {
+				return ValidationErrors{{Field: fmt.Sprintf("schedule.windows[%d].days", idx), Message: fmt.Sprintf("unknown day %q", day)}}
+			}
+		}
+
+		if _, _, err := parseScheduleClock(w.Start); err != nil {
+			return ValidationErrors{{Field: fmt.Sprintf("schedule.windows[%d].start", idx), Message: err.Error()}}
+		}
+
+		if _, _, err := parseScheduleClock(w.End); err != nil {
+			return ValidationErrors{{Field: fmt.Sprintf("schedule.windows[%d].end", idx), Message: err.Error()}}
+		}
+	}
+
+	return nil
+}
+
+// Active reports whether now falls within one of the windows
+func (s *FilterSchedule) Active(now time.Time) bool {
+	if !s.IsSet() {
+		return true
+	}
+
+	loc, err := s.location()
+	if err != nil {
+		return true
+	}
+
+	now = now.In(loc)
+
+	for _, w := range s.Windows {
+		// a window that started yesterday can still be running
+		for _, offset := range []int{0, -1} {
+			start, end, ok := w.on(now.AddDate(0, 0, offset), loc)
+			if ok && !now.Before(start) && now.Before(end) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// NextActivation returns the next time the filter becomes active. It returns nil when the schedule
+// is active now or has no windows.
+func (s *FilterSchedule) NextActivation(now time.Time) *time.Time {
+	if !s.IsSet() || s.Active(now) {
+		return nil
+	}
+
+	loc, err := s.location()
+	if err != nil {
+		return nil
+	}
+
+	now = now.In(loc)
+
+	var next *time.Time
+	for _, w := range s.Windows {
+		for offset := 0; offset <= 7; offset++ {
+			start, _, ok := w.on(now.AddDate(0, 0, offset), loc)
+			if !ok || !start.After(now) {
+				continue
+			}
+
+			if next == nil || start.Before(*next) {
+				next = &start
+			}
+			break
+		}
+	}
+
+	return next
+}
+
+func (s *FilterSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(s.Timezone)
+}
+
+// on returns the window starting on the date of day, ok is false if the window is not active that weekday
+func (w FilterScheduleWindow) on(day time.Time, loc *time.Location) (start time.Time, end time.Time, ok bool) {
+	if len(w.Days) > 0 {
+		for _, d := range w.Days {
+			if scheduleWeekdays[strings.ToLower(d)] == day.Weekday() {
+				ok = true
+				break
+			}
+		}
+
+		if !ok {
+			return start, end, false
+		}
+	}
+
+	startHour, startMinute, err := parseScheduleClock(w.Start)
+	if err != nil {
+		return start, end, false
+	}
+
+	endHour, endMinute, err := parseScheduleClock(w.End)
+	if err != nil {
+		return start, end, false
+	}
+
+	y, m, d := day.Date()
+	start = time.Date(y, m, d, startHour, startMinute, 0, 0, loc)
+	end = time.Date(y, m, d, endHour, endMinute, 0, 0, loc)
+
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return start, end, true
+}
+
+func parseScheduleClock(value string) (hour int, minute int, err error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	return t.Hour(), t.Minute(), nil
+}
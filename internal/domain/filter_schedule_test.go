@@ -0,0 +1,136 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterSchedule_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *FilterSchedule
+		wantErr  bool
+	}{
+		{name: "nil", schedule: nil, wantErr: false},
+		{name: "valid", schedule: &FilterSchedule{Timezone: "Europe/Stockholm", Windows: []FilterScheduleWindow{{Days: []string{"mon", "Fri"}, Start: "22:00", End: "06:00"}}}, wantErr: false},
+		{name: "invalid_timezone", schedule: &FilterSchedule{Timezone: "Mars/Olympus"}, wantErr: true},
+		{name: "invalid_day", schedule: &FilterSchedule{Windows: []FilterScheduleWindow{{Days: []string{"monday"}, Start: "00:00", End: "01:00"}}}, wantErr: true},
+		{name: "invalid_start", schedule: &FilterSchedule{Windows: []FilterScheduleWindow{{Start: "25:00", End: "01:00"}}}, wantErr: true},
+		{name: "missing_end", schedule: &FilterSchedule{Windows: []FilterScheduleWindow{{Start: "01:00"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFilterSchedule_Active(t *testing.T) {
+	// 2024-01-01 is a monday
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule *FilterSchedule
+		now      time.Time
+		want     bool
+		wantNext *time.Time
+	}{
+		{
+			name:     "no_schedule",
+			schedule: nil,
+			now:      at(1, 12, 0),
+			want:     true,
+		},
+		{
+			name:     "inside_window",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Days: []string{"mon"}, Start: "09:00", End: "17:00"}}},
+			now:      at(1, 9, 0),
+			want:     true,
+		},
+		{
+			name:     "end_is_exclusive",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Days: []string{"mon"}, Start: "09:00", End: "17:00"}}},
+			now:      at(1, 17, 0),
+			want:     false,
+			wantNext: ptr(at(8, 9, 0)),
+		},
+		{
+			name:     "other_day",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Days: []string{"mon", "wed"}, Start: "09:00", End: "17:00"}}},
+			now:      at(2, 12, 0),
+			want:     false,
+			wantNext: ptr(at(3, 9, 0)),
+		},
+		{
+			name:     "overnight_after_midnight",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Days: []string{"fri"}, Start: "22:00", End: "06:00"}}},
+			now:      at(6, 3, 0),
+			want:     true,
+		},
+		{
+			name:     "overnight_belongs_to_start_day",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Days: []string{"sat"}, Start: "22:00", End: "06:00"}}},
+			now:      at(6, 3, 0),
+			want:     false,
+			wantNext: ptr(at(6, 22, 0)),
+		},
+		{
+			name:     "whole_day",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Days: []string{"sun"}, Start: "00:00", End: "00:00"}}},
+			now:      at(7, 23, 59),
+			want:     true,
+		},
+		{
+			name:     "every_day",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Start: "01:00", End: "02:00"}}},
+			now:      at(4, 0, 30),
+			want:     false,
+			wantNext: ptr(at(4, 1, 0)),
+		},
+		{
+			name:     "earliest_window",
+			schedule: &FilterSchedule{Timezone: "UTC", Windows: []FilterScheduleWindow{{Days: []string{"thu"}, Start: "10:00", End: "11:00"}, {Days: []string{"tue"}, Start: "20:00", End: "21:00"}}},
+			now:      at(2, 12, 0),
+			want:     false,
+			wantNext: ptr(at(2, 20, 0)),
+		},
+		{
+			name:     "timezone",
+			schedule: &FilterSchedule{Timezone: "Europe/Stockholm", Windows: []FilterScheduleWindow{{Days: []string{"mon"}, Start: "09:00", End: "10:00"}}},
+			now:      at(1, 8, 30),
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.Active(tt.now))
+
+			next := tt.schedule.NextActivation(tt.now)
+			if tt.wantNext == nil {
+				assert.Nil(t, next)
+				return
+			}
+
+			require.NotNil(t, next)
+			assert.True(t, tt.wantNext.Equal(*next), "want %s got %s", tt.wantNext, next)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -4,6 +4,7 @@
 package domain
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -2231,6 +2232,12 @@ func Test_validation(t *testing.T) {
 		{name: "valid size limit", filter: Filter{Name: "test", MaxSize: "12MB"}, valid: true},
 		{name: "gibberish max size limit", filter: Filter{Name: "test", MaxSize: "asdf"}, valid: false},
 		{name: "gibberish min size limit", filter: Filter{Name: "test", MinSize: "qwerty"}, valid: false},
+		{name: "valid max downloads size", filter: Filter{Name: "test", MaxDownloadsSize: "500GB", MaxDownloadsSizeUnit: FilterMaxDownloadsWeek}, valid: true},
+		{name: "gibberish max downloads size", filter: Filter{Name: "test", MaxDownloadsSize: "asdf", MaxDownloadsSizeUnit: FilterMaxDownloadsWeek}, valid: false},
+		{name: "max downloads size without unit", filter: Filter{Name: "test", MaxDownloadsSize: "500GB"}, valid: false},
+		{name: "valid group tiers", filter: Filter{Name: "test", GroupTiers: []FilterGroupTier{{Groups: []string{"A"}}, {Groups: []string{"*"}, DelayMinutes: 120}}}, valid: true},
+		{name: "group tier without groups", filter: Filter{Name: "test", GroupTiers: []FilterGroupTier{{DelayMinutes: 30}}}, valid: false},
+		{name: "group tier delay shorter than tier before", filter: Filter{Name: "test", GroupTiers: []FilterGroupTier{{Groups: []string{"A"}, DelayMinutes: 60}, {Groups: []string{"B"}, DelayMinutes: 30}}}, valid: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2268,3 +2275,60 @@ func Test_checkSizeFilter(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkMaxDownloadsSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      Filter
+		releaseSize uint64
+		want        bool
+	}{
+		{name: "empty budget", filter: Filter{MaxDownloadsSize: "10GB", MaxDownloadsSizeUnit: FilterMaxDownloadsDay, Downloads: &FilterDownloads{}}, releaseSize: 4000000000, want: true},
+		{name: "fits the remaining budget", filter: Filter{MaxDownloadsSize: "10GB", MaxDownloadsSizeUnit: FilterMaxDownloadsDay, Downloads: &FilterDownloads{DaySize: 6000000000}}, releaseSize: 4000000000, want: true},
+		{name: "exceeds the remaining budget", filter: Filter{MaxDownloadsSize: "10GB", MaxDownloadsSizeUnit: FilterMaxDownloadsDay, Downloads: &FilterDownloads{DaySize: 6000000001}}, releaseSize: 4000000000, want: false},
+		{name: "budget used up", filter: Filter{MaxDownloadsSize: "10GB", MaxDownloadsSizeUnit: FilterMaxDownloadsDay, Downloads: &FilterDownloads{DaySize: 12000000000}}, releaseSize: 1, want: false},
+		{name: "other unit", filter: Filter{MaxDownloadsSize: "10GB", MaxDownloadsSizeUnit: FilterMaxDownloadsHour, Downloads: &FilterDownloads{HourSize: 1000000000, DaySize: 12000000000}}, releaseSize: 4000000000, want: true},
+		{name: "release larger than budget", filter: Filter{MaxDownloadsSize: "1GB", MaxDownloadsSizeUnit: FilterMaxDownloadsEver, Downloads: &FilterDownloads{}}, releaseSize: 2000000000, want: false},
+		{name: "downloads not loaded", filter: Filter{MaxDownloadsSize: "10GB", MaxDownloadsSizeUnit: FilterMaxDownloadsDay}, releaseSize: 1, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.checkMaxDownloadsSize(tt.releaseSize))
+
+			rejections, match := tt.filter.CheckFilter(&Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.x264-GROUP", Size: tt.releaseSize})
+			assert.Equal(t, tt.want, match)
+			if !tt.want {
+				assert.Equal(t, []string{fmt.Sprintf("max downloads size (%s) this (%s) reached", tt.filter.MaxDownloadsSize, tt.filter.MaxDownloadsSizeUnit)}, rejections)
+			}
+		})
+	}
+}
+
+func TestFilter_CheckFiles(t *testing.T) {
+	files := []string{"Show.Name.S01E01.1080p.WEB-DL.x264-GROUP/Show.Name.S01E01.1080p.WEB-DL.x264-GROUP.MKV", "Show.Name.S01E01.1080p.WEB-DL.x264-GROUP/Sample/sample.mkv", "Show.Name.S01E01.1080p.WEB-DL.x264-GROUP/info.nfo"}
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		files     []string
+		want      bool
+		rejection string
+	}{
+		{name: "no checks", filter: Filter{}, files: files, want: true},
+		{name: "max files", filter: Filter{MaxFiles: 3}, files: files, want: true},
+		{name: "too many files", filter: Filter{MaxFiles: 2}, files: files, want: false, rejection: "file count 3 is more than filter max files 2"},
+		{name: "except extensions", filter: Filter{ExceptFileExtensions: "exe, .lnk"}, files: files, want: true},
+		{name: "unwanted file", filter: Filter{ExceptFileExtensions: "exe,.NFO"}, files: files, want: false, rejection: "except file extensions: unwanted file Show.Name.S01E01.1080p.WEB-DL.x264-GROUP/info.nfo"},
+		{name: "match extensions", filter: Filter{MatchFileExtensions: "mp4,.mkv"}, files: files, want: true},
+		{name: "no media file", filter: Filter{MatchFileExtensions: "mp4,avi"}, files: []string{"Show.Name.S01E01.1080p.WEB-DL.x264-GROUP.exe"}, want: false, rejection: "match file extensions: no file with extension mp4, avi"},
+		{name: "single file torrent", filter: Filter{MaxFiles: 1, MatchFileExtensions: "mkv", ExceptFileExtensions: "exe"}, files: []string{"Show.Name.S01E01.1080p.WEB-DL.x264-GROUP.mkv"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.CheckFiles(tt.files))
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, tt.filter.Rejections)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type FreeleechTokenRepo interface {
+	ListBudgets(ctx context.Context, since time.Time) ([]FreeleechTokenBudget, error)
+	GetBudget(ctx context.Context, indexer string, since time.Time) (*FreeleechTokenBudget, error)
+	UpdateBudget(ctx context.Context, budget FreeleechTokenBudget) error
+	StoreUsage(ctx context.Context, usage FreeleechTokenUsage) error
+}
+
+// FreeleechTokenBudget is the number of freeleech tokens autobrr may spend per month on an indexer.
+// Tokens are only spent for indexers with a budget above 0.
+type FreeleechTokenBudget struct {
+	Indexer       string `json:"indexer"`
+	MonthlyBudget int    `json:"monthly_budget"`
+	Used          int    `json:"used"`
+}
+
+func (b FreeleechTokenBudget) Validate() error {
+	var errs ValidationErrors
+
+	if b.Indexer == "" {
+		errs.Add("indexer", "indexer can't be empty")
+	}
+
+	if b.MonthlyBudget < 0 {
+		errs.Add("monthly_budget", "monthly budget can't be negative")
+	}
+
+	return errs.Err()
+}
+
+func (b FreeleechTokenBudget) Remaining() int {
+	if b.Used >= b.MonthlyBudget {
+		return 0
+	}
+	return b.MonthlyBudget - b.Used
+}
+
+type FreeleechTokenUsage struct {
+	Indexer     string    `json:"indexer"`
+	TorrentName string    `json:"torrent_name"`
+	FilterID    int       `json:"filter_id"`
+	Size        uint64    `json:"size"`
+	UsedAt      time.Time `json:"used_at"`
+}
+
+// FreeleechTokenMonthStart returns the start of the budget period for t
+func FreeleechTokenMonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
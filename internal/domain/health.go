@@ -0,0 +1,61 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import "time"
+
+type HealthState string
+
+const (
+	HealthStateOK       HealthState = "ok"
+	HealthStateDegraded HealthState = "degraded"
+	HealthStateDown     HealthState = "down"
+	HealthStateDisabled HealthState = "disabled"
+)
+
+type HealthComponentType string
+
+const (
+	HealthComponentDatabase       HealthComponentType = "database"
+	HealthComponentIRC            HealthComponentType = "irc"
+	HealthComponentFeed           HealthComponentType = "feed"
+	HealthComponentDownloadClient HealthComponentType = "download_client"
+)
+
+type HealthComponent struct {
+	Type    HealthComponentType `json:"type"`
+	ID      int64               `json:"id,omitempty"`
+	Name    string              `json:"name"`
+	State   HealthState         `json:"state"`
+	Message string              `json:"message,omitempty"`
+}
+
+// HealthReport is the state of every component. The status is down when the database is,
+// degraded when any other component isn't ok and ok otherwise. Disabled components don't count.
+type HealthReport struct {
+	Status     HealthState       `json:"status"`
+	Components []HealthComponent `json:"components"`
+	CheckedAt  time.Time         `json:"checked_at"`
+}
+
+// NewHealthReport returns the report of the components with the status derived from them
+func NewHealthReport(components []HealthComponent, checkedAt time.Time) *HealthReport {
+	report := &HealthReport{
+		Status:     HealthStateOK,
+		Components: components,
+		CheckedAt:  checkedAt,
+	}
+
+	for _, c := range components {
+		switch {
+		case c.Type == HealthComponentDatabase && c.State == HealthStateDown:
+			report.Status = HealthStateDown
+			return report
+		case c.State == HealthStateDegraded || c.State == HealthStateDown:
+			report.Status = HealthStateDegraded
+		}
+	}
+
+	return report
+}
@@ -37,6 +37,7 @@ type Indexer struct {
 	UseProxy           bool              `json:"use_proxy"`
 	Proxy              *Proxy            `json:"proxy"`
 	ProxyID            int64             `json:"proxy_id"`
+	Identity           *IndexerIdentity  `json:"identity,omitempty"`
 	Settings           map[string]string `json:"settings,omitempty"`
 }
 
@@ -71,6 +72,8 @@ type IndexerDefinition struct {
 	Supports           []string          `json:"supports"`
 	UseProxy           bool              `json:"use_proxy"`
 	ProxyID            int64             `json:"proxy_id"`
+	Identity           *IndexerIdentity  `json:"identity,omitempty"`
+	Categories         map[string]string `json:"categories,omitempty"` // raw indexer category to shared taxonomy, eg. "PC :: Iso": Apps
 	Settings           []IndexerSetting  `json:"settings,omitempty"`
 	SettingsMap        map[string]string `json:"-"`
 	IRC                *IndexerIRC       `json:"irc,omitempty"`
@@ -86,6 +89,7 @@ const (
 	IndexerImplementationTorznab IndexerImplementation = "torznab"
 	IndexerImplementationNewznab IndexerImplementation = "newznab"
 	IndexerImplementationRSS     IndexerImplementation = "rss"
+	IndexerImplementationMock    IndexerImplementation = "mock"
 	IndexerImplementationLegacy  IndexerImplementation = ""
 )
 
@@ -99,6 +103,8 @@ func (i IndexerImplementation) String() string {
 		return "newznab"
 	case IndexerImplementationRSS:
 		return "rss"
+	case IndexerImplementationMock:
+		return "mock"
 	case IndexerImplementationLegacy:
 		return ""
 	}
@@ -128,6 +134,7 @@ type IndexerDefinitionCustom struct {
 	Protocol       string            `json:"protocol"`
 	URLS           []string          `json:"urls"`
 	Supports       []string          `json:"supports"`
+	Categories     map[string]string `json:"categories,omitempty"`
 	Settings       []IndexerSetting  `json:"settings,omitempty"`
 	SettingsMap    map[string]string `json:"-"`
 	IRC            *IndexerIRC       `json:"irc,omitempty"`
@@ -151,6 +158,7 @@ func (i *IndexerDefinitionCustom) ToIndexerDefinition() *IndexerDefinition {
 		Protocol:       i.Protocol,
 		URLS:           i.URLS,
 		Supports:       i.Supports,
+		Categories:     i.Categories,
 		Settings:       i.Settings,
 		SettingsMap:    i.SettingsMap,
 		IRC:            i.IRC,
@@ -203,6 +211,30 @@ type IndexerIRC struct {
 	SettingsMap map[string]string `json:"-"`
 	Settings    []IndexerSetting  `json:"settings"`
 	Parse       *IndexerIRCParse  `json:"parse,omitempty"`
+
+	// Invite is the sequence of commands sent to enter the announce channels.
+	// When set it replaces the plain invite command for the network.
+	Invite []IndexerIRCInviteStep `json:"invite,omitempty"`
+}
+
+// IndexerIRCInviteStep is a single step of the invite sequence
+type IndexerIRCInviteStep struct {
+	// Send is sent as /msg, eg. "Voyager autobot {{ .nick }} {{ .irckey }}".
+	// Vars are the indexer settings plus nick and invite_command from the network.
+	Send string `json:"send,omitempty"`
+
+	// Expect is a regex matched against NOTICE and PRIVMSG messages and INVITE events as "INVITE #channel".
+	// The next step waits until it matches, empty means don't wait.
+	Expect string `json:"expect,omitempty"`
+
+	// From limits Expect to messages from this nick
+	From string `json:"from,omitempty"`
+
+	// Timeout in seconds to wait for Expect, defaults to 30
+	Timeout int `json:"timeout,omitempty"`
+
+	// Retries is how many times Send is repeated when Expect times out
+	Retries int `json:"retries,omitempty"`
 }
 
 func (i IndexerIRC) ValidAnnouncer(announcer string) bool {
@@ -247,6 +279,9 @@ type IndexerIRCParseMatch struct {
 	TorrentName string   `json:"torrentname"`
 	InfoURL     string   `json:"infourl"`
 	Encode      []string `json:"encode"`
+
+	// FreeleechToken are query params added to the torrent url to spend a freeleech token, eg. usetoken=1
+	FreeleechToken string `json:"freeleechtoken"`
 }
 
 type IndexerIRCParseMatched struct {
@@ -324,6 +359,22 @@ func (p *IndexerIRCParseMatch) ParseURLs(baseURL string, vars map[string]string,
 		}
 
 		rls.DownloadURL = downloadURL.String()
+
+		if p.FreeleechToken != "" {
+			tokenParams, err := url.ParseQuery(p.FreeleechToken)
+			if err != nil {
+				return errors.Wrap(err, "could not parse freeleech token params")
+			}
+
+			query := downloadURL.Query()
+			for k, v := range tokenParams {
+				query[k] = v
+			}
+
+			downloadURL.RawQuery = query.Encode()
+
+			rls.FreeleechTokenURL = downloadURL.String()
+		}
 	}
 
 	return nil
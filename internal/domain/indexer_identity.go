@@ -0,0 +1,116 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// IndexerIdentity is the request identity sent with feed fetches and torrent downloads of an indexer,
+// some trackers only allow downloads from specific clients
+type IndexerIdentity struct {
+	UserAgent string            `json:"user_agent,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Cookie    string            `json:"cookie,omitempty"`
+}
+
+func (i *IndexerIdentity) IsSet() bool {
+	return i != nil && (i.UserAgent != "" || len(i.Headers) > 0 || i.Cookie != "")
+}
+
+func (i *IndexerIdentity) Validate() error {
+	if i == nil {
+		return nil
+	}
+
+	if !httpguts.ValidHeaderFieldValue(i.UserAgent) {
+		return errors.New("invalid user agent: %q", i.UserAgent)
+	}
+
+	if !httpguts.ValidHeaderFieldValue(i.Cookie) {
+		return errors.New("invalid cookie")
+	}
+
+	for name, value := range i.Headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return errors.New("invalid header name: %q", name)
+		}
+
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return errors.New("invalid value for header: %q", name)
+		}
+
+		if strings.EqualFold(name, "Host") {
+			return errors.New("header %q can not be set", name)
+		}
+	}
+
+	return nil
+}
+
+// Apply sets the identity on req. The user agent and headers replace the defaults of the request,
+// the cookie is only used when the request has no cookie of its own, eg. from the feed or indexer settings.
+func (i *IndexerIdentity) Apply(req *http.Request) {
+	if !i.IsSet() {
+		return
+	}
+
+	if i.UserAgent != "" {
+		req.Header.Set("User-Agent", i.UserAgent)
+	}
+
+	for name, value := range i.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if i.Cookie != "" && req.Header.Get("Cookie") == "" {
+		req.Header.Set("Cookie", i.Cookie)
+	}
+}
+
+// IdentityTransport applies the identity to the requests to Host before passing them to the base transport.
+// Redirects to other hosts, eg. a CDN or a tracker of another site, are sent without the identity
+// so the cookie and headers don't leak.
+type IdentityTransport struct {
+	Base     http.RoundTripper
+	Identity *IndexerIdentity
+	Host     string
+}
+
+// NewIdentityTransport returns a transport that applies identity to the requests for the host of rawURL
+func NewIdentityTransport(base http.RoundTripper, identity *IndexerIdentity, rawURL string) (*IdentityTransport, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse url")
+	}
+
+	if u.Host == "" {
+		return nil, errors.New("url has no host: %q", rawURL)
+	}
+
+	return &IdentityTransport{Base: base, Identity: identity, Host: u.Host}, nil
+}
+
+func (t *IdentityTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if !t.Identity.IsSet() || !strings.EqualFold(req.URL.Host, t.Host) {
+		return base.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the request
+	r := req.Clone(req.Context())
+	t.Identity.Apply(r)
+
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexerIdentity_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *IndexerIdentity
+		wantErr  bool
+	}{
+		{name: "nil", identity: nil, wantErr: false},
+		{name: "valid", identity: &IndexerIdentity{UserAgent: "qBittorrent/4.6.5", Headers: map[string]string{"X-Client": "qbt"}, Cookie: "uid=1; pass=2"}, wantErr: false},
+		{name: "invalid_user_agent", identity: &IndexerIdentity{UserAgent: "qbt\r\nX-Injected: 1"}, wantErr: true},
+		{name: "invalid_header_name", identity: &IndexerIdentity{Headers: map[string]string{"X Client": "qbt"}}, wantErr: true},
+		{name: "invalid_header_value", identity: &IndexerIdentity{Headers: map[string]string{"X-Client": "qbt\n"}}, wantErr: true},
+		{name: "host_header", identity: &IndexerIdentity{Headers: map[string]string{"host": "example.com"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.identity.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIndexerIdentity_Apply(t *testing.T) {
+	identity := &IndexerIdentity{
+		UserAgent: "qBittorrent/4.6.5",
+		Headers:   map[string]string{"X-Client": "qbt"},
+		Cookie:    "uid=1",
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/download/1", nil)
+	req.Header.Set("User-Agent", "autobrr")
+
+	identity.Apply(req)
+	assert.Equal(t, "qBittorrent/4.6.5", req.Header.Get("User-Agent"))
+	assert.Equal(t, "qbt", req.Header.Get("X-Client"))
+	assert.Equal(t, "uid=1", req.Header.Get("Cookie"))
+
+	// the cookie of the request takes precedence
+	req = httptest.NewRequest(http.MethodGet, "https://example.com/download/1", nil)
+	req.Header.Set("Cookie", "uid=2")
+
+	identity.Apply(req)
+	assert.Equal(t, "uid=2", req.Header.Get("Cookie"))
+
+	// nil identity keeps the request as is
+	req = httptest.NewRequest(http.MethodGet, "https://example.com/download/1", nil)
+	req.Header.Set("User-Agent", "autobrr")
+
+	var empty *IndexerIdentity
+	empty.Apply(req)
+	assert.Equal(t, "autobrr", req.Header.Get("User-Agent"))
+}
+
+func TestIdentityTransport(t *testing.T) {
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("User-Agent") + "|" + r.Header.Get("X-Client") + "|" + r.Header.Get("Cookie")))
+	}
+
+	// the other host is a different port on localhost, the identity must not follow the redirect
+	other := httptest.NewServer(http.HandlerFunc(echo))
+	defer other.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, other.URL+"/download", http.StatusFound)
+			return
+		}
+		echo(w, r)
+	}))
+	defer srv.Close()
+
+	transport, err := NewIdentityTransport(nil, &IndexerIdentity{UserAgent: "qBittorrent/4.6.5", Headers: map[string]string{"X-Client": "qbt"}, Cookie: "uid=1"}, srv.URL+"/rss")
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: transport}
+
+	get := func(url string) string {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("User-Agent", "Gofeed/1.0")
+
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		// the original request is not modified
+		assert.Equal(t, "Gofeed/1.0", req.Header.Get("User-Agent"))
+
+		return string(body)
+	}
+
+	assert.Equal(t, "qBittorrent/4.6.5|qbt|uid=1", get(srv.URL+"/download"))
+	assert.Equal(t, "Gofeed/1.0||", get(srv.URL+"/redirect"))
+	assert.Equal(t, "Gofeed/1.0||", get(other.URL+"/download"))
+
+	_, err = NewIdentityTransport(nil, &IndexerIdentity{UserAgent: "qbt"}, "/rss")
+	assert.Error(t, err)
+}
@@ -15,6 +15,8 @@ func TestIndexerIRCParseMatch_ParseUrls(t *testing.T) {
 		TorrentName string
 		InfoURL     string
 		Encode      []string
+
+		FreeleechToken string
 	}
 	type args struct {
 		baseURL string
@@ -149,6 +151,26 @@ func TestIndexerIRCParseMatch_ParseUrls(t *testing.T) {
 				DownloadURL: "https://mock.local/rss/?action=download&key=KEY&token=TOKEN&hash=240860011&title=The+Show+2019+S03E08+2160p+DV+WEBRip+6CH+x265+HEVC-GROUP",
 			},
 		},
+		{
+			name: "freeleech_token",
+			fields: fields{
+				TorrentURL:     "/torrents.php?action=download&id={{ .torrentId }}&torrent_pass={{ .passkey }}",
+				FreeleechToken: "usetoken=1",
+			},
+			args: args{
+				baseURL: "https://mock.local/",
+				vars: map[string]string{
+					"torrentName": "Artist - Album [2024] [WEB FLAC]",
+					"torrentId":   "240860011",
+					"passkey":     "PASSKEY",
+				},
+				rls: &Release{},
+			},
+			want: &Release{
+				DownloadURL:       "https://mock.local/torrents.php?action=download&id=240860011&torrent_pass=PASSKEY",
+				FreeleechTokenURL: "https://mock.local/torrents.php?action=download&id=240860011&torrent_pass=PASSKEY&usetoken=1",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -157,6 +179,8 @@ func TestIndexerIRCParseMatch_ParseUrls(t *testing.T) {
 				TorrentName: tt.fields.TorrentName,
 				InfoURL:     tt.fields.InfoURL,
 				Encode:      tt.fields.Encode,
+
+				FreeleechToken: tt.fields.FreeleechToken,
 			}
 			p.ParseURLs(tt.args.baseURL, tt.args.vars, tt.args.rls)
 			assert.Equal(t, tt.want, tt.args.rls)
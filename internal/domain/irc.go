@@ -52,6 +52,8 @@ type IrcNetwork struct {
 	ProxyId        int64        `json:"proxy_id"`
 	Proxy          *Proxy       `json:"proxy"`
 	BotMode        bool         `json:"bot_mode"`
+	StatusCommand  string       `json:"status_command"`
+	StatusNicks    string       `json:"status_nicks"`
 	Channels       []IrcChannel `json:"channels"`
 	Connected      bool         `json:"connected"`
 	ConnectedSince *time.Time   `json:"connected_since"`
@@ -71,6 +73,8 @@ type IrcNetworkWithHealth struct {
 	UseBouncer       bool                `json:"use_bouncer"`
 	BouncerAddr      string              `json:"bouncer_addr"`
 	BotMode          bool                `json:"bot_mode"`
+	StatusCommand    string              `json:"status_command"`
+	StatusNicks      string              `json:"status_nicks"`
 	CurrentNick      string              `json:"current_nick"`
 	PreferredNick    string              `json:"preferred_nick"`
 	UseProxy         bool                `json:"use_proxy"`
@@ -81,6 +85,27 @@ type IrcNetworkWithHealth struct {
 	ConnectedSince   time.Time           `json:"connected_since"`
 	ConnectionErrors []string            `json:"connection_errors"`
 	Healthy          bool                `json:"healthy"`
+	InviteStatus     []IrcInviteStatus   `json:"invite_status"`
+}
+
+type IrcInviteState string
+
+const (
+	IrcInviteStatePending IrcInviteState = "PENDING"
+	IrcInviteStateRunning IrcInviteState = "RUNNING"
+	IrcInviteStateDone    IrcInviteState = "DONE"
+	IrcInviteStateFailed  IrcInviteState = "FAILED"
+)
+
+// IrcInviteStatus is the progress of the invite sequence for an indexer on the network
+type IrcInviteStatus struct {
+	Indexer   string         `json:"indexer"`
+	State     IrcInviteState `json:"state"`
+	Step      int            `json:"step"`
+	Steps     int            `json:"steps"`
+	Attempt   int            `json:"attempt"`
+	Error     string         `json:"error,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
 }
 
 type ChannelWithHealth struct {
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type ListRepo interface {
+	List(ctx context.Context) ([]*List, error)
+	FindByID(ctx context.Context, id int64) (*List, error)
+	Store(ctx context.Context, list *List) error
+	Update(ctx context.Context, list *List) error
+	UpdateLastRefresh(ctx context.Context, list *List) error
+	ToggleEnabled(ctx context.Context, id int64, enabled bool) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// List is an external list of titles which is synced into a field of the connected filters
+type List struct {
+	ID                int64             `json:"id"`
+	Name              string            `json:"name"`
+	Type              ListType          `json:"type"`
+	Enabled           bool              `json:"enabled"`
+	URL               string            `json:"url"`
+	APIKey            string            `json:"api_key,omitempty"` // trakt client id or mdblist api key
+	ClientID          int32             `json:"client_id"`         // arr client of the arr list types
+	TagsInclude       []string          `json:"tags_include"`
+	TagsExclude       []string          `json:"tags_exclude"`
+	FilterField       ListFilterField   `json:"filter_field"`
+	Filters           []ListFilter      `json:"filters"`
+	RefreshInterval   int               `json:"refresh_interval"` // in minutes
+	LastRefreshTime   time.Time         `json:"last_refresh_time"`
+	LastRefreshStatus ListRefreshStatus `json:"last_refresh_status"`
+	LastRefreshError  string            `json:"last_refresh_error"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+type ListFilter struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ListType string
+
+const (
+	ListTypeTrakt     ListType = "TRAKT"
+	ListTypeMDBList   ListType = "MDBLIST"
+	ListTypeIMDb      ListType = "IMDB"
+	ListTypePlaintext ListType = "PLAINTEXT"
+	ListTypeSonarr    ListType = "SONARR"
+	ListTypeRadarr    ListType = "RADARR"
+	ListTypeLidarr    ListType = "LIDARR"
+	ListTypeReadarr   ListType = "READARR"
+)
+
+// IsArr tells if the list is the monitored items of an arr client instead of an url
+func (t ListType) IsArr() bool {
+	switch t {
+	case ListTypeSonarr, ListTypeRadarr, ListTypeLidarr, ListTypeReadarr:
+		return true
+	default:
+		return false
+	}
+}
+
+// ListFilterField is the filter field the titles of a list replace
+type ListFilterField string
+
+const (
+	ListFilterFieldShows          ListFilterField = "SHOWS"
+	ListFilterFieldMatchReleases  ListFilterField = "MATCH_RELEASES"
+	ListFilterFieldExceptReleases ListFilterField = "EXCEPT_RELEASES"
+)
+
+type ListRefreshStatus string
+
+const (
+	ListRefreshStatusSuccess ListRefreshStatus = "SUCCESS"
+	ListRefreshStatusError   ListRefreshStatus = "ERROR"
+)
+
+// ListMinRefreshInterval keeps lists from hammering the list providers
+const ListMinRefreshInterval = 15
+
+func (l *List) Validate() error {
+	var errs ValidationErrors
+
+	if l.Name == "" {
+		errs.Add("name", "name is required")
+	}
+
+	switch l.Type {
+	case ListTypeTrakt, ListTypeMDBList, ListTypeIMDb, ListTypePlaintext:
+		if u, err := url.Parse(l.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Add("url", "url must be a http(s) url")
+		}
+	case ListTypeSonarr, ListTypeRadarr, ListTypeLidarr, ListTypeReadarr:
+		if l.ClientID == 0 {
+			errs.Add("client_id", "%s lists need a client", strings.ToLower(string(l.Type)))
+		}
+	default:
+		errs.Add("type", "invalid list type: %s", l.Type)
+	}
+
+	if l.Type == ListTypeTrakt && l.APIKey == "" {
+		errs.Add("api_key", "trakt lists need the client id of a trakt api app")
+	}
+
+	switch l.FilterField {
+	case ListFilterFieldShows, ListFilterFieldMatchReleases, ListFilterFieldExceptReleases:
+	default:
+		errs.Add("filter_field", "invalid filter field: %s", l.FilterField)
+	}
+
+	if l.RefreshInterval < ListMinRefreshInterval {
+		errs.Add("refresh_interval", "refresh interval must be at least %d minutes", ListMinRefreshInterval)
+	}
+
+	return errs.Err()
+}
+
+// FilterUpdate returns the partial filter update which replaces the list field of the filter with the value
+func (l *List) FilterUpdate(filterID int, value string) FilterUpdate {
+	update := FilterUpdate{ID: filterID}
+
+	switch l.FilterField {
+	case ListFilterFieldShows:
+		update.Shows = &value
+	case ListFilterFieldMatchReleases:
+		update.MatchReleases = &value
+	case ListFilterFieldExceptReleases:
+		update.ExceptReleases = &value
+	}
+
+	return update
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// LiveStream is the server-sent events stream of the live events, subscribe with /api/events?stream=live
+const LiveStream = "live"
+
+type LiveEventType string
+
+const (
+	// LiveEventReleaseDecision is a filter that matched or rejected a release
+	LiveEventReleaseDecision LiveEventType = "release-decision"
+	// LiveEventActionStatus is a new or updated status of an action run for a release
+	LiveEventActionStatus LiveEventType = "action-status"
+	// LiveEventIRCHealth is an irc network that connected or disconnected, or a channel that is monitored or not anymore
+	LiveEventIRCHealth LiveEventType = "irc-health"
+)
+
+type LiveEvent struct {
+	Type      LiveEventType `json:"type"`
+	Timestamp time.Time     `json:"timestamp"`
+	Data      any           `json:"data"`
+}
+
+func NewLiveEvent(eventType LiveEventType, data any) *LiveEvent {
+	return &LiveEvent{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+}
+
+func (e LiveEvent) Bytes() []byte {
+	j, err := json.Marshal(e)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+type LiveReleaseDecision struct {
+	TorrentName string   `json:"torrent_name"`
+	Indexer     string   `json:"indexer"`
+	FilterID    int      `json:"filter_id"`
+	Filter      string   `json:"filter"`
+	Match       bool     `json:"match"`
+	Rejections  []string `json:"rejections,omitempty"`
+}
+
+type LiveIRCHealth struct {
+	NetworkID  int64  `json:"network_id"`
+	Network    string `json:"network"`
+	Connected  bool   `json:"connected"`
+	Channel    string `json:"channel,omitempty"`
+	Monitoring bool   `json:"monitoring"`
+}
@@ -77,6 +77,7 @@ type Macro struct {
 	TorrentTmpFile            string
 	Type                      string
 	Uploader                  string
+	Vars                      map[string]string
 	Website                   string
 	Year                      int
 	Month                     int
@@ -148,6 +149,7 @@ func NewMacro(release Release) Macro {
 		TorrentTmpFile:            release.TorrentTmpFile,
 		Type:                      release.Type,
 		Uploader:                  release.Uploader,
+		Vars:                      release.MacroVars,
 		Website:                   release.Website,
 		Year:                      release.Year,
 		Month:                     release.Month,
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type MaintenanceRepo interface {
+	CleanupOrphans(ctx context.Context, dryRun bool) ([]OrphanCleanupResult, error)
+	Backup(ctx context.Context, dst string) error
+}
+
+// OrphanCleanupResult is the number of orphaned rows found, and removed unless dry run, for a table
+type OrphanCleanupResult struct {
+	Table       string `json:"table"`
+	Description string `json:"description"`
+	Count       int64  `json:"count"`
+}
+
+type OrphanCleanupReport struct {
+	DryRun  bool                  `json:"dry_run"`
+	Results []OrphanCleanupResult `json:"results"`
+	Total   int64                 `json:"total"`
+	RunAt   time.Time             `json:"run_at"`
+}
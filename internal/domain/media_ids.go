@@ -0,0 +1,80 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var imdbIDRegex = regexp.MustCompile(`^tt\d+$`)
+
+// MediaIDs are the ids of the movie or show of a release at tmdb, tvdb and imdb, zero when unknown
+type MediaIDs struct {
+	TMDB int64  `json:"tmdb,omitempty"`
+	TVDB int64  `json:"tvdb,omitempty"`
+	IMDb string `json:"imdb,omitempty"`
+}
+
+func (m MediaIDs) IsEmpty() bool {
+	return m.TMDB == 0 && m.TVDB == 0 && m.IMDb == ""
+}
+
+// Keys returns the known ids in the provider:id form of the match ids of filters
+func (m MediaIDs) Keys() []string {
+	var keys []string
+
+	if m.TMDB > 0 {
+		keys = append(keys, fmt.Sprintf("tmdb:%d", m.TMDB))
+	}
+	if m.TVDB > 0 {
+		keys = append(keys, fmt.Sprintf("tvdb:%d", m.TVDB))
+	}
+	if m.IMDb != "" {
+		keys = append(keys, "imdb:"+m.IMDb)
+	}
+
+	return keys
+}
+
+func (m MediaIDs) String() string {
+	return strings.Join(m.Keys(), ", ")
+}
+
+// ParseMediaIDs parses a comma separated list of ids like "tt0944947,tmdb:1399,tvdb:121361" into their provider:id keys.
+// Bare tt ids are imdb ids.
+func ParseMediaIDs(s string) (map[string]struct{}, error) {
+	ids := map[string]struct{}{}
+
+	for _, field := range strings.Split(s, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+
+		provider, id, found := strings.Cut(field, ":")
+		if !found {
+			provider, id = "imdb", field
+		}
+
+		switch provider {
+		case "imdb":
+			if !imdbIDRegex.MatchString(id) {
+				return nil, fmt.Errorf("invalid imdb id: %q", field)
+			}
+		case "tmdb", "tvdb":
+			if n, err := strconv.ParseInt(id, 10, 64); err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid %s id: %q", provider, field)
+			}
+		default:
+			return nil, fmt.Errorf("unknown id provider %q, valid providers are imdb, tmdb and tvdb", provider)
+		}
+
+		ids[provider+":"+id] = struct{}{}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMediaIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]struct{}
+		wantErr string
+	}{
+		{name: "empty", input: "", want: map[string]struct{}{}},
+		{name: "providers", input: "tt0944947, TMDB:1399,tvdb:121361,imdb:tt0903747", want: map[string]struct{}{"imdb:tt0944947": {}, "tmdb:1399": {}, "tvdb:121361": {}, "imdb:tt0903747": {}}},
+		{name: "invalid_imdb", input: "0944947", wantErr: `invalid imdb id: "0944947"`},
+		{name: "invalid_tmdb", input: "tmdb:abc", wantErr: `invalid tmdb id: "tmdb:abc"`},
+		{name: "unknown_provider", input: "trakt:1", wantErr: `unknown id provider "trakt", valid providers are imdb, tmdb and tvdb`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMediaIDs(tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilter_MatchMediaIDs(t *testing.T) {
+	ids := MediaIDs{TMDB: 1399, TVDB: 121361, IMDb: "tt0944947"}
+
+	tests := []struct {
+		name      string
+		matchIDs  string
+		want      bool
+		rejection string
+	}{
+		{name: "imdb", matchIDs: "tt0944947", want: true},
+		{name: "tvdb", matchIDs: "tmdb:1,tvdb:121361", want: true},
+		{name: "no_match", matchIDs: "tt0903747,tmdb:1396", rejection: "match ids: tmdb:1399, tvdb:121361, imdb:tt0944947 not in match ids"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{MatchIDs: tt.matchIDs}
+
+			assert.Equal(t, tt.want, f.MatchMediaIDs(ids))
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+var mbidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// MusicBrainzMatch is the artist and album of a release at musicbrainz, the fields are empty when unknown
+type MusicBrainzMatch struct {
+	ArtistID       string `json:"artist_id,omitempty"`
+	ArtistName     string `json:"artist_name,omitempty"` // canonical name of the artist
+	ReleaseGroupID string `json:"release_group_id,omitempty"`
+}
+
+func (m MusicBrainzMatch) IsEmpty() bool {
+	return m.ArtistID == "" && m.ReleaseGroupID == ""
+}
+
+func (m MusicBrainzMatch) String() string {
+	var parts []string
+
+	if m.ArtistID != "" {
+		parts = append(parts, m.ArtistName+" ("+m.ArtistID+")")
+	}
+	if m.ReleaseGroupID != "" {
+		parts = append(parts, "release group "+m.ReleaseGroupID)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// MatchMusicBrainzEntries reports whether the artist or album of the release is in the musicbrainz field of the filter.
+// Entries are artist or release group mbids, anything else is compared to the canonical artist name.
+func (f *Filter) MatchMusicBrainzEntries(m MusicBrainzMatch) bool {
+	for _, entry := range strings.Split(f.MatchMusicBrainz, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if mbidRegex.MatchString(strings.ToLower(entry)) {
+			if strings.EqualFold(entry, m.ArtistID) || strings.EqualFold(entry, m.ReleaseGroupID) {
+				return true
+			}
+			continue
+		}
+
+		if m.ArtistName != "" && strings.EqualFold(entry, m.ArtistName) {
+			return true
+		}
+	}
+
+	f.AddRejectionF("match musicbrainz: %s not in match musicbrainz", m.String())
+
+	return false
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_MatchMusicBrainzEntries(t *testing.T) {
+	match := MusicBrainzMatch{
+		ArtistID:       "056e4f3e-d505-4dad-8ec1-d04f521cbb56",
+		ArtistName:     "Daft Punk",
+		ReleaseGroupID: "aa997ea0-2936-40bd-884d-3af8a0e064dc",
+	}
+
+	tests := []struct {
+		name      string
+		entries   string
+		want      bool
+		rejection string
+	}{
+		{name: "artist_name", entries: "Justice, daft punk", want: true},
+		{name: "artist_mbid", entries: "056E4F3E-D505-4DAD-8EC1-D04F521CBB56", want: true},
+		{name: "release_group_mbid", entries: "aa997ea0-2936-40bd-884d-3af8a0e064dc", want: true},
+		{name: "other_mbid", entries: "5b11f4ce-a62d-471e-81fc-a69a8278c7da", rejection: "match musicbrainz: Daft Punk (056e4f3e-d505-4dad-8ec1-d04f521cbb56), release group aa997ea0-2936-40bd-884d-3af8a0e064dc not in match musicbrainz"},
+		{name: "other_name", entries: "Daft", rejection: "match musicbrainz: Daft Punk (056e4f3e-d505-4dad-8ec1-d04f521cbb56), release group aa997ea0-2936-40bd-884d-3af8a0e064dc not in match musicbrainz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{MatchMusicBrainz: tt.entries}
+
+			assert.Equal(t, tt.want, f.MatchMusicBrainzEntries(match))
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+}
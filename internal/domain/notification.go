@@ -17,6 +17,12 @@ type NotificationRepo interface {
 	Delete(ctx context.Context, notificationID int) error
 }
 
+type NotificationPreferenceRepo interface {
+	List(ctx context.Context) ([]UserNotificationPreference, error)
+	FindByUserID(ctx context.Context, userID int) (*UserNotificationPreference, error)
+	Store(ctx context.Context, pref *UserNotificationPreference) error
+}
+
 type NotificationSender interface {
 	Send(event NotificationEvent, payload NotificationPayload) error
 	CanSend(event NotificationEvent) bool
@@ -24,27 +30,39 @@ type NotificationSender interface {
 }
 
 type Notification struct {
-	ID        int              `json:"id"`
-	Name      string           `json:"name"`
-	Type      NotificationType `json:"type"`
-	Enabled   bool             `json:"enabled"`
-	Events    []string         `json:"events"`
-	Token     string           `json:"token"`
-	APIKey    string           `json:"api_key"`
-	Webhook   string           `json:"webhook"`
-	Title     string           `json:"title"`
-	Icon      string           `json:"icon"`
-	Username  string           `json:"username"`
-	Host      string           `json:"host"`
-	Password  string           `json:"password"`
-	Channel   string           `json:"channel"`
-	Rooms     string           `json:"rooms"`
-	Targets   string           `json:"targets"`
-	Devices   string           `json:"devices"`
-	Priority  int32            `json:"priority"`
-	Topic     string           `json:"topic"`
-	CreatedAt time.Time        `json:"created_at"`
-	UpdatedAt time.Time        `json:"updated_at"`
+	ID       int              `json:"id"`
+	Name     string           `json:"name"`
+	Type     NotificationType `json:"type"`
+	Enabled  bool             `json:"enabled"`
+	Events   []string         `json:"events"`
+	Token    string           `json:"token"`
+	APIKey   string           `json:"api_key"`
+	Webhook  string           `json:"webhook"`
+	Title    string           `json:"title"`
+	Icon     string           `json:"icon"`
+	Username string           `json:"username"`
+	Host     string           `json:"host"`
+	Password string           `json:"password"`
+	Channel  string           `json:"channel"`
+	Rooms    string           `json:"rooms"`
+	Targets  string           `json:"targets"`
+	Devices  string           `json:"devices"`
+	Priority int32            `json:"priority"`
+	Topic    string           `json:"topic"`
+	Headers  string           `json:"headers"`  // webhook headers, one "Name: value" per line
+	Template string           `json:"template"` // webhook json body template
+	Secret   string           `json:"secret"`   // webhook hmac signing secret
+	// MessageTemplates replace the default title and message per event
+	MessageTemplates map[NotificationEvent]NotificationMessageTemplate `json:"message_templates"`
+	// QuietHours suppress or defer the events of the agent to a digest, in the timezone or the server timezone when empty
+	QuietHoursEnabled     bool           `json:"quiet_hours_enabled"`
+	QuietHoursStart       string         `json:"quiet_hours_start"`
+	QuietHoursEnd         string         `json:"quiet_hours_end"`
+	QuietHoursTimezone    string         `json:"quiet_hours_timezone"`
+	QuietHoursMode        QuietHoursMode `json:"quiet_hours_mode"`
+	QuietHoursAllowErrors bool           `json:"quiet_hours_allow_errors"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
 }
 
 type NotificationPayload struct {
@@ -65,6 +83,13 @@ type NotificationPayload struct {
 	Implementation ReleaseImplementation // irc, rss, api
 	Timestamp      time.Time
 	Sender         string
+
+	// Title and Body are rendered from the message template of the agent and replace the default title and message
+	Title string
+	Body  string
+
+	// Release is the data of the message templates, it is not kept when the notification is queued
+	Release *Release `json:"-"`
 }
 
 type NotificationType string
@@ -85,20 +110,64 @@ const (
 	NotificationTypeNtfy       NotificationType = "NTFY"
 	NotificationTypeLunaSea    NotificationType = "LUNASEA"
 	NotificationTypeShoutrrr   NotificationType = "SHOUTRRR"
+	NotificationTypeWebhook    NotificationType = "WEBHOOK"
 )
 
 type NotificationEvent string
 
 const (
-	NotificationEventAppUpdateAvailable NotificationEvent = "APP_UPDATE_AVAILABLE"
-	NotificationEventPushApproved       NotificationEvent = "PUSH_APPROVED"
-	NotificationEventPushRejected       NotificationEvent = "PUSH_REJECTED"
-	NotificationEventPushError          NotificationEvent = "PUSH_ERROR"
-	NotificationEventIRCDisconnected    NotificationEvent = "IRC_DISCONNECTED"
-	NotificationEventIRCReconnected     NotificationEvent = "IRC_RECONNECTED"
-	NotificationEventTest               NotificationEvent = "TEST"
+	NotificationEventAppUpdateAvailable      NotificationEvent = "APP_UPDATE_AVAILABLE"
+	NotificationEventPushApproved            NotificationEvent = "PUSH_APPROVED"
+	NotificationEventPushRejected            NotificationEvent = "PUSH_REJECTED"
+	NotificationEventPushError               NotificationEvent = "PUSH_ERROR"
+	NotificationEventIRCDisconnected         NotificationEvent = "IRC_DISCONNECTED"
+	NotificationEventIRCReconnected          NotificationEvent = "IRC_RECONNECTED"
+	NotificationEventIRCChannelParted        NotificationEvent = "IRC_CHANNEL_PARTED"
+	NotificationEventIRCChannelKicked        NotificationEvent = "IRC_CHANNEL_KICKED"
+	NotificationEventFeedError               NotificationEvent = "FEED_ERROR"
+	NotificationEventDownloadClientUnhealthy NotificationEvent = "DOWNLOAD_CLIENT_UNHEALTHY"
+	NotificationEventDelayedReleaseExpired   NotificationEvent = "DELAYED_RELEASE_EXPIRED"
+	NotificationEventSizeMismatch            NotificationEvent = "SIZE_MISMATCH"
+	NotificationEventDownloadComplete        NotificationEvent = "DOWNLOAD_COMPLETE"
+	NotificationEventTest                    NotificationEvent = "TEST"
+
+	// NotificationEventDigest holds the events deferred during the quiet hours of an agent
+	NotificationEventDigest NotificationEvent = "DIGEST"
 )
 
+// IsError checks if the event reports a failure, those can still be sent during quiet hours
+func (e NotificationEvent) IsError() bool {
+	switch e {
+	case NotificationEventPushError,
+		NotificationEventIRCDisconnected,
+		NotificationEventIRCChannelParted,
+		NotificationEventIRCChannelKicked,
+		NotificationEventFeedError,
+		NotificationEventDownloadClientUnhealthy,
+		NotificationEventSizeMismatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// notificationEvents are the events which can be sent, and have a message template
+var notificationEvents = map[NotificationEvent]struct{}{
+	NotificationEventAppUpdateAvailable:      {},
+	NotificationEventPushApproved:            {},
+	NotificationEventPushRejected:            {},
+	NotificationEventPushError:               {},
+	NotificationEventIRCDisconnected:         {},
+	NotificationEventIRCReconnected:          {},
+	NotificationEventIRCChannelParted:        {},
+	NotificationEventIRCChannelKicked:        {},
+	NotificationEventFeedError:               {},
+	NotificationEventDownloadClientUnhealthy: {},
+	NotificationEventDelayedReleaseExpired:   {},
+	NotificationEventSizeMismatch:            {},
+	NotificationEventDownloadComplete:        {},
+}
+
 type NotificationEventArr []NotificationEvent
 
 type NotificationQueryParams struct {
@@ -111,3 +180,97 @@ type NotificationQueryParams struct {
 	}
 	Search string
 }
+
+const quietHoursLayout = "15:04"
+
+// UserNotificationPreference holds which events a user wants from which notification agents.
+// Agents not subscribed to by any user keep sending all of their events.
+type UserNotificationPreference struct {
+	UserID            int       `json:"user_id"`
+	Events            []string  `json:"events"`
+	Notifications     []int     `json:"notifications"`
+	QuietHoursEnabled bool      `json:"quiet_hours_enabled"`
+	QuietHoursStart   string    `json:"quiet_hours_start"`
+	QuietHoursEnd     string    `json:"quiet_hours_end"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (p UserNotificationPreference) Validate() error {
+	if !p.QuietHoursEnabled {
+		return nil
+	}
+
+	var errs ValidationErrors
+
+	if _, err := time.Parse(quietHoursLayout, p.QuietHoursStart); err != nil {
+		errs.Add("quiet_hours_start", "invalid quiet hours start %q, expected HH:MM", p.QuietHoursStart)
+	}
+
+	if _, err := time.Parse(quietHoursLayout, p.QuietHoursEnd); err != nil {
+		errs.Add("quiet_hours_end", "invalid quiet hours end %q, expected HH:MM", p.QuietHoursEnd)
+	}
+
+	return errs.Err()
+}
+
+// Subscribed checks if the user is subscribed to the notification agent
+func (p UserNotificationPreference) Subscribed(notificationID int) bool {
+	for _, id := range p.Notifications {
+		if id == notificationID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WantsEvent checks if the user wants the event. No events means all events.
+func (p UserNotificationPreference) WantsEvent(event NotificationEvent) bool {
+	if len(p.Events) == 0 {
+		return true
+	}
+
+	for _, e := range p.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InQuietHours checks if t is within the quiet hours. Quiet hours may span midnight, eg. 22:00 - 07:00.
+func (p UserNotificationPreference) InQuietHours(t time.Time) bool {
+	if !p.QuietHoursEnabled {
+		return false
+	}
+
+	return inQuietHours(p.QuietHoursStart, p.QuietHoursEnd, t)
+}
+
+// inQuietHours checks if the clock of t is within start and end, in HH:MM. The range may span midnight.
+func inQuietHours(startClock, endClock string, t time.Time) bool {
+	start, err := time.Parse(quietHoursLayout, startClock)
+	if err != nil {
+		return false
+	}
+
+	end, err := time.Parse(quietHoursLayout, endClock)
+	if err != nil {
+		return false
+	}
+
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+	nowMinutes := t.Hour()*60 + t.Minute()
+
+	if startMinutes == endMinutes {
+		return false
+	}
+
+	if startMinutes < endMinutes {
+		return nowMinutes >= startMinutes && nowMinutes < endMinutes
+	}
+
+	return nowMinutes >= startMinutes || nowMinutes < endMinutes
+}
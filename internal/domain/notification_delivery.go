@@ -0,0 +1,75 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type NotificationDeliveryRepo interface {
+	Find(ctx context.Context, params NotificationDeliveryQueryParams) ([]NotificationDelivery, error)
+	FindByID(ctx context.Context, id int64) (*NotificationDelivery, error)
+	FindDue(ctx context.Context, status NotificationDeliveryStatus, now time.Time, limit uint64) ([]NotificationDelivery, error)
+	Store(ctx context.Context, delivery *NotificationDelivery) error
+	Update(ctx context.Context, delivery *NotificationDelivery) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// NotificationDelivery is a notification which could not be sent, it is retried with a backoff until it is sent
+// or it runs out of attempts and is kept as failed until it is resent or deleted. Notifications deferred by the
+// quiet hours of their agent wait in the queue until the quiet hours end and are sent as a digest.
+type NotificationDelivery struct {
+	ID               int64                      `json:"id"`
+	NotificationID   int                        `json:"notification_id"`
+	NotificationName string                     `json:"notification_name"`
+	Event            NotificationEvent          `json:"event"`
+	Payload          NotificationPayload        `json:"payload"`
+	Status           NotificationDeliveryStatus `json:"status"`
+	Attempts         int                        `json:"attempts"`
+	LastError        string                     `json:"last_error"`
+	NextAttemptAt    time.Time                  `json:"next_attempt_at"`
+	CreatedAt        time.Time                  `json:"created_at"`
+	UpdatedAt        time.Time                  `json:"updated_at"`
+}
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryStatusPending NotificationDeliveryStatus = "PENDING"
+	NotificationDeliveryStatusFailed  NotificationDeliveryStatus = "FAILED"
+	// NotificationDeliveryStatusDeferred waits for the end of the quiet hours of the agent
+	NotificationDeliveryStatusDeferred NotificationDeliveryStatus = "DEFERRED"
+)
+
+type NotificationDeliveryQueryParams struct {
+	Status NotificationDeliveryStatus
+	Limit  uint64
+}
+
+// notificationDeliveryBackoff is the wait before each retry, a delivery fails after the last one
+var notificationDeliveryBackoff = [...]time.Duration{
+	1 * time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	1 * time.Hour,
+	3 * time.Hour,
+}
+
+// NotificationDeliveryMaxAttempts is the first attempt and the retries
+const NotificationDeliveryMaxAttempts = len(notificationDeliveryBackoff) + 1
+
+// AttemptFailed records the failed attempt and schedules the next one, or marks the delivery failed when out of attempts
+func (d *NotificationDelivery) AttemptFailed(err error, now time.Time) {
+	d.Attempts++
+	d.LastError = err.Error()
+
+	if d.Attempts >= NotificationDeliveryMaxAttempts {
+		d.Status = NotificationDeliveryStatusFailed
+		return
+	}
+
+	d.Status = NotificationDeliveryStatusPending
+	d.NextAttemptAt = now.Add(notificationDeliveryBackoff[d.Attempts-1])
+}
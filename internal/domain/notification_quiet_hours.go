@@ -0,0 +1,105 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"time"
+)
+
+// QuietHoursMode is what happens to the events of a notification agent during its quiet hours
+type QuietHoursMode string
+
+const (
+	// QuietHoursModeSuppress drops the events
+	QuietHoursModeSuppress QuietHoursMode = "SUPPRESS"
+	// QuietHoursModeDefer keeps the events and sends them as a digest when the quiet hours end
+	QuietHoursModeDefer QuietHoursMode = "DEFER"
+)
+
+// Validate checks the message templates and quiet hours of the notification agent
+func (n Notification) Validate() error {
+	var errs ValidationErrors
+
+	n.validateMessageTemplates(&errs)
+
+	if !n.QuietHoursEnabled {
+		return errs.Err()
+	}
+
+	if _, err := time.Parse(quietHoursLayout, n.QuietHoursStart); err != nil {
+		errs.Add("quiet_hours_start", "invalid quiet hours start %q, expected HH:MM", n.QuietHoursStart)
+	}
+
+	if _, err := time.Parse(quietHoursLayout, n.QuietHoursEnd); err != nil {
+		errs.Add("quiet_hours_end", "invalid quiet hours end %q, expected HH:MM", n.QuietHoursEnd)
+	}
+
+	if n.QuietHoursTimezone != "" {
+		if _, err := time.LoadLocation(n.QuietHoursTimezone); err != nil {
+			errs.Add("quiet_hours_timezone", "unknown timezone %q", n.QuietHoursTimezone)
+		}
+	}
+
+	switch n.QuietHoursMode {
+	case "", QuietHoursModeSuppress, QuietHoursModeDefer:
+	default:
+		errs.Add("quiet_hours_mode", "invalid quiet hours mode %q", n.QuietHoursMode)
+	}
+
+	return errs.Err()
+}
+
+// quietHoursLocation is the timezone of the quiet hours, the server timezone when not set or unknown
+func (n Notification) quietHoursLocation() *time.Location {
+	if n.QuietHoursTimezone == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(n.QuietHoursTimezone)
+	if err != nil {
+		return time.Local
+	}
+
+	return loc
+}
+
+// InQuietHours checks if t is within the quiet hours of the agent, in their timezone
+func (n Notification) InQuietHours(t time.Time) bool {
+	if !n.QuietHoursEnabled {
+		return false
+	}
+
+	return inQuietHours(n.QuietHoursStart, n.QuietHoursEnd, t.In(n.quietHoursLocation()))
+}
+
+// QuietHoursSkip checks if the event is held back at t, errors are let through when allowed
+func (n Notification) QuietHoursSkip(event NotificationEvent, t time.Time) bool {
+	if !n.InQuietHours(t) {
+		return false
+	}
+
+	return !(n.QuietHoursAllowErrors && event.IsError())
+}
+
+// QuietHoursDeferred checks if held back events are kept for the digest instead of dropped
+func (n Notification) QuietHoursDeferred() bool {
+	return n.QuietHoursMode == QuietHoursModeDefer
+}
+
+// QuietHoursEndAfter returns when the quiet hours which t is in end
+func (n Notification) QuietHoursEndAfter(t time.Time) time.Time {
+	end, err := time.Parse(quietHoursLayout, n.QuietHoursEnd)
+	if err != nil {
+		return t
+	}
+
+	local := t.In(n.quietHoursLocation())
+
+	next := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, local.Location())
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_InQuietHours(t *testing.T) {
+	n := Notification{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", QuietHoursTimezone: "Asia/Tokyo"}
+
+	// 14:30 UTC is 23:30 in Tokyo
+	assert.True(t, n.InQuietHours(time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC)))
+	// 23:30 UTC is 08:30 in Tokyo
+	assert.False(t, n.InQuietHours(time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)))
+
+	n.QuietHoursEnabled = false
+	assert.False(t, n.InQuietHours(time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC)))
+}
+
+func TestNotification_QuietHoursSkip(t *testing.T) {
+	n := Notification{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", QuietHoursTimezone: "UTC"}
+
+	night := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, n.QuietHoursSkip(NotificationEventPushApproved, night))
+	assert.True(t, n.QuietHoursSkip(NotificationEventPushError, night))
+	assert.False(t, n.QuietHoursSkip(NotificationEventPushApproved, day))
+
+	// errors are still delivered when allowed
+	n.QuietHoursAllowErrors = true
+	assert.False(t, n.QuietHoursSkip(NotificationEventPushError, night))
+	assert.False(t, n.QuietHoursSkip(NotificationEventIRCDisconnected, night))
+	assert.True(t, n.QuietHoursSkip(NotificationEventPushRejected, night))
+}
+
+func TestNotification_QuietHoursEndAfter(t *testing.T) {
+	n := Notification{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", QuietHoursTimezone: "Asia/Tokyo"}
+
+	// 23:30 in Tokyo ends the next morning
+	end := n.QuietHoursEndAfter(time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC))
+	assert.True(t, end.Equal(time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)))
+
+	// 03:00 in Tokyo ends the same morning
+	end = n.QuietHoursEndAfter(time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC))
+	assert.True(t, end.Equal(time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)))
+}
+
+func TestNotification_Validate_quietHours(t *testing.T) {
+	n := Notification{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", QuietHoursTimezone: "Europe/Stockholm", QuietHoursMode: QuietHoursModeDefer}
+	assert.NoError(t, n.Validate())
+
+	n.QuietHoursEnd = "7am"
+	assert.ErrorContains(t, n.Validate(), "quiet_hours_end")
+
+	n.QuietHoursEnd = "07:00"
+	n.QuietHoursTimezone = "Mars/Olympus"
+	assert.ErrorContains(t, n.Validate(), `unknown timezone "Mars/Olympus"`)
+
+	n.QuietHoursTimezone = ""
+	n.QuietHoursMode = "LATER"
+	assert.ErrorContains(t, n.Validate(), `invalid quiet hours mode "LATER"`)
+
+	// not checked while disabled
+	n.QuietHoursEnabled = false
+	assert.NoError(t, n.Validate())
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// NotificationMessageTemplate replaces the default title and message of an event with macros,
+// an empty title or message keeps the default
+type NotificationMessageTemplate struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+func (t NotificationMessageTemplate) IsSet() bool {
+	return strings.TrimSpace(t.Title) != "" || strings.TrimSpace(t.Message) != ""
+}
+
+// NotificationMacro is the data of notification message templates, the release macros
+// and the fields of the notification like the action and its status
+type NotificationMacro struct {
+	Macro
+
+	Event        string
+	Subject      string
+	Message      string
+	Status       string
+	Action       string
+	ActionType   string
+	ActionClient string
+	Rejections   []string
+	Timestamp    time.Time
+}
+
+// NewNotificationMacro creates the macro from the release of the payload, or from its fields
+// when there is no release like for a queued notification sent again
+func NewNotificationMacro(event NotificationEvent, payload NotificationPayload) NotificationMacro {
+	release := payload.Release
+	if release == nil {
+		release = &Release{
+			TorrentName:    payload.ReleaseName,
+			TorrentHash:    payload.InfoHash,
+			FilterName:     payload.Filter,
+			Indexer:        IndexerMinimal{Name: payload.Indexer},
+			Size:           payload.Size,
+			Protocol:       payload.Protocol,
+			Implementation: payload.Implementation,
+		}
+	}
+
+	timestamp := payload.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	m := NotificationMacro{
+		Macro:        NewMacro(*release),
+		Event:        string(event),
+		Subject:      payload.Subject,
+		Message:      payload.Message,
+		Action:       payload.Action,
+		ActionType:   string(payload.ActionType),
+		ActionClient: payload.ActionClient,
+		Rejections:   payload.Rejections,
+		Timestamp:    timestamp,
+	}
+
+	if payload.Status != "" {
+		m.Status = payload.Status.String()
+	}
+
+	return m
+}
+
+// Render executes the title and message templates with the payload
+func (t NotificationMessageTemplate) Render(event NotificationEvent, payload NotificationPayload) (title string, message string, err error) {
+	m := NewNotificationMacro(event, payload)
+
+	if title, err = m.parse(t.Title); err != nil {
+		return "", "", errors.Wrap(err, "could not render title")
+	}
+
+	if message, err = m.parse(t.Message); err != nil {
+		return "", "", errors.Wrap(err, "could not render message")
+	}
+
+	return strings.TrimSpace(title), strings.TrimSpace(message), nil
+}
+
+func (m NotificationMacro) parse(text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New("notification").Funcs(sprig.TxtFuncMap()).Parse(text)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, m); err != nil {
+		return "", errors.Wrap(err, "could not execute template")
+	}
+
+	return buf.String(), nil
+}
+
+// validateMessageTemplates checks the message templates parse and are for known events
+func (n Notification) validateMessageTemplates(errs *ValidationErrors) {
+	for event, t := range n.MessageTemplates {
+		field := fmt.Sprintf("message_templates.%s", event)
+
+		if _, ok := notificationEvents[event]; !ok {
+			errs.Add(field, "unknown event %q", event)
+			continue
+		}
+
+		if _, err := template.New("notification").Funcs(sprig.TxtFuncMap()).Parse(t.Title); err != nil {
+			errs.Add(field+".title", "invalid template: %s", err.Error())
+		}
+
+		if _, err := template.New("notification").Funcs(sprig.TxtFuncMap()).Parse(t.Message); err != nil {
+			errs.Add(field+".message", "invalid template: %s", err.Error())
+		}
+	}
+}
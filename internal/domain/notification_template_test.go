@@ -0,0 +1,104 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationMessageTemplate_Render(t *testing.T) {
+	release := &Release{
+		TorrentName: "Movie.2024.2160p.UHD.BluRay.REMUX.HDR.HEVC-GROUP",
+		Title:       "Movie",
+		Year:        2024,
+		Resolution:  "2160p",
+		FilterName:  "movies",
+		Indexer:     IndexerMinimal{Name: "Mock Indexer", Identifier: "mock"},
+		Size:        50000000000,
+	}
+
+	tests := []struct {
+		name        string
+		template    NotificationMessageTemplate
+		event       NotificationEvent
+		payload     NotificationPayload
+		wantTitle   string
+		wantMessage string
+		wantErr     bool
+	}{
+		{
+			name:     "release_macros",
+			template: NotificationMessageTemplate{Title: "Grabbed {{ .Title }} ({{ .Year }})", Message: "{{ .Resolution }} {{ .SizeString }} from {{ .IndexerName }} by {{ .FilterName }}"},
+			event:    NotificationEventPushApproved,
+			payload: NotificationPayload{
+				ReleaseName: release.TorrentName,
+				Status:      ReleasePushStatusApproved,
+				Release:     release,
+			},
+			wantTitle:   "Grabbed Movie (2024)",
+			wantMessage: "2160p 50 GB from Mock Indexer by movies",
+		},
+		{
+			name:     "action_status",
+			template: NotificationMessageTemplate{Message: "{{ .Status }}: {{ .Action }} ({{ .ActionClient }}) {{ join \", \" .Rejections }}"},
+			event:    NotificationEventPushRejected,
+			payload: NotificationPayload{
+				Status:       ReleasePushStatusRejected,
+				Action:       "Send to Radarr",
+				ActionClient: "Radarr",
+				Rejections:   []string{"Item already exists", "Not an upgrade"},
+				Release:      release,
+			},
+			wantMessage: "Rejected: Send to Radarr (Radarr) Item already exists, Not an upgrade",
+		},
+		{
+			name:     "payload_fields_without_release",
+			template: NotificationMessageTemplate{Title: "{{ .Event }}", Message: "{{ .TorrentName }} {{ .IndexerName }} {{ .FilterName }}"},
+			event:    NotificationEventDownloadComplete,
+			payload: NotificationPayload{
+				ReleaseName: release.TorrentName,
+				Filter:      "movies",
+				Indexer:     "Mock Indexer",
+			},
+			wantTitle:   "DOWNLOAD_COMPLETE",
+			wantMessage: "Movie.2024.2160p.UHD.BluRay.REMUX.HDR.HEVC-GROUP Mock Indexer movies",
+		},
+		{
+			name:     "invalid",
+			template: NotificationMessageTemplate{Message: "{{ .Unknown }}"},
+			event:    NotificationEventPushApproved,
+			payload:  NotificationPayload{Release: release},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, message, err := tt.template.Render(tt.event, tt.payload)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, title)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestNotification_Validate_messageTemplates(t *testing.T) {
+	n := Notification{MessageTemplates: map[NotificationEvent]NotificationMessageTemplate{
+		NotificationEventPushApproved: {Title: "{{ .Title }}", Message: "{{ .TorrentName }}"},
+	}}
+	assert.NoError(t, n.Validate())
+
+	n.MessageTemplates[NotificationEventPushError] = NotificationMessageTemplate{Message: "{{ .TorrentName "}
+	assert.ErrorContains(t, n.Validate(), "message_templates.PUSH_ERROR.message")
+
+	n = Notification{MessageTemplates: map[NotificationEvent]NotificationMessageTemplate{"UNKNOWN": {Title: "test"}}}
+	assert.ErrorContains(t, n.Validate(), `unknown event "UNKNOWN"`)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserNotificationPreference_InQuietHours(t *testing.T) {
+	tests := []struct {
+		name  string
+		pref  UserNotificationPreference
+		clock string
+		want  bool
+	}{
+		{
+			name:  "disabled",
+			pref:  UserNotificationPreference{QuietHoursEnabled: false, QuietHoursStart: "00:00", QuietHoursEnd: "23:59"},
+			clock: "12:00",
+			want:  false,
+		},
+		{
+			name:  "same_day_inside",
+			pref:  UserNotificationPreference{QuietHoursEnabled: true, QuietHoursStart: "09:00", QuietHoursEnd: "17:00"},
+			clock: "12:30",
+			want:  true,
+		},
+		{
+			name:  "same_day_end_exclusive",
+			pref:  UserNotificationPreference{QuietHoursEnabled: true, QuietHoursStart: "09:00", QuietHoursEnd: "17:00"},
+			clock: "17:00",
+			want:  false,
+		},
+		{
+			name:  "over_midnight_late",
+			pref:  UserNotificationPreference{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			clock: "23:15",
+			want:  true,
+		},
+		{
+			name:  "over_midnight_early",
+			pref:  UserNotificationPreference{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			clock: "06:59",
+			want:  true,
+		},
+		{
+			name:  "over_midnight_outside",
+			pref:  UserNotificationPreference{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			clock: "12:00",
+			want:  false,
+		},
+		{
+			name:  "invalid",
+			pref:  UserNotificationPreference{QuietHoursEnabled: true, QuietHoursStart: "late", QuietHoursEnd: "07:00"},
+			clock: "23:00",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock, err := time.Parse("15:04", tt.clock)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, tt.pref.InQuietHours(clock))
+		})
+	}
+}
+
+func TestUserNotificationPreference_WantsEvent(t *testing.T) {
+	pref := UserNotificationPreference{}
+	assert.True(t, pref.WantsEvent(NotificationEventPushApproved))
+
+	pref.Events = []string{string(NotificationEventPushError)}
+	assert.True(t, pref.WantsEvent(NotificationEventPushError))
+	assert.False(t, pref.WantsEvent(NotificationEventPushApproved))
+}
+
+func TestNotificationDelivery_AttemptFailed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sendErr := errors.New("unexpected status: 503")
+
+	d := &NotificationDelivery{}
+
+	d.AttemptFailed(sendErr, now)
+	assert.Equal(t, NotificationDeliveryStatusPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, "unexpected status: 503", d.LastError)
+	assert.Equal(t, now.Add(time.Minute), d.NextAttemptAt)
+
+	d.AttemptFailed(sendErr, now)
+	assert.Equal(t, now.Add(5*time.Minute), d.NextAttemptAt)
+
+	for d.Attempts < NotificationDeliveryMaxAttempts-1 {
+		d.AttemptFailed(sendErr, now)
+	}
+	assert.Equal(t, NotificationDeliveryStatusPending, d.Status)
+	assert.Equal(t, now.Add(3*time.Hour), d.NextAttemptAt)
+
+	d.AttemptFailed(sendErr, now)
+	assert.Equal(t, NotificationDeliveryStatusFailed, d.Status)
+	assert.Equal(t, NotificationDeliveryMaxAttempts, d.Attempts)
+
+	// a failed resend keeps it failed
+	d.AttemptFailed(sendErr, now)
+	assert.Equal(t, NotificationDeliveryStatusFailed, d.Status)
+}
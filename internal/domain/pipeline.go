@@ -0,0 +1,169 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// PipelineStage is a step run for a matched filter before the actions
+type PipelineStage string
+
+const (
+	// PipelineStageDuplicateCheck is the smart episode check
+	PipelineStageDuplicateCheck PipelineStage = "duplicateCheck"
+
+	// PipelineStageEnrichment fetches missing data like size from the indexer api or torrent file
+	PipelineStageEnrichment PipelineStage = "enrichment"
+
+	// PipelineStageExternal runs the external filters and the sonarr, radarr and lidarr checks
+	PipelineStageExternal PipelineStage = "external"
+
+	// PipelineStageDelay sleeps for the filter delay, always runs last
+	PipelineStageDelay PipelineStage = "delay"
+)
+
+var DefaultPipelineStages = []PipelineStage{
+	PipelineStageDuplicateCheck,
+	PipelineStageEnrichment,
+	PipelineStageExternal,
+	PipelineStageDelay,
+}
+
+// PipelineConfig is the pipeline for an indexer from the config file
+type PipelineConfig struct {
+	Stages     []string `toml:"stages"`
+	ForceDelay int      `toml:"forceDelay"`
+}
+
+// IndexerPipeline is the effective pipeline for an indexer
+type IndexerPipeline struct {
+	Indexer    string          `json:"indexer"`
+	Custom     bool            `json:"custom"`
+	Stages     []PipelineStage `json:"stages"`
+	Skipped    []PipelineStage `json:"skipped"`
+	ForceDelay int             `json:"force_delay"`
+
+	// Error is set when the config is invalid and the default pipeline is used instead
+	Error string `json:"error,omitempty"`
+}
+
+// Has reports if the stage is part of the pipeline, a nil pipeline has all stages
+func (p *IndexerPipeline) Has(stage PipelineStage) bool {
+	if p == nil {
+		return slices.Contains(DefaultPipelineStages, stage)
+	}
+
+	return slices.Contains(p.Stages, stage)
+}
+
+// Delay returns the delay in seconds for the filter, the forced delay replaces the filter delay
+func (p *IndexerPipeline) Delay(f *Filter) int {
+	if p == nil {
+		return f.Delay
+	}
+
+	if !p.Has(PipelineStageDelay) {
+		return 0
+	}
+
+	if p.ForceDelay > 0 {
+		return p.ForceDelay
+	}
+
+	return f.Delay
+}
+
+// NewIndexerPipeline returns the default pipeline with the order and stages from cfg
+func NewIndexerPipeline(indexer string, cfg *PipelineConfig) (*IndexerPipeline, error) {
+	p := &IndexerPipeline{
+		Indexer: indexer,
+		Stages:  slices.Clone(DefaultPipelineStages),
+		Skipped: []PipelineStage{},
+	}
+
+	if cfg == nil {
+		return p, nil
+	}
+
+	p.Custom = true
+
+	var errs ValidationErrors
+
+	if cfg.Stages != nil {
+		p.Stages = make([]PipelineStage, 0, len(cfg.Stages))
+
+		for _, s := range cfg.Stages {
+			stage, ok := parsePipelineStage(s)
+			if !ok {
+				errs.Add("stages", "unknown stage %q for %s", s, indexer)
+				continue
+			}
+
+			if slices.Contains(p.Stages, stage) {
+				errs.Add("stages", "duplicate stage %q for %s", s, indexer)
+				continue
+			}
+
+			p.Stages = append(p.Stages, stage)
+		}
+
+		// the delay runs after the checks, just before the actions
+		if i := slices.Index(p.Stages, PipelineStageDelay); i >= 0 && i != len(p.Stages)-1 {
+			errs.Add("stages", "stage %q must be last for %s", PipelineStageDelay, indexer)
+		}
+
+		for _, stage := range DefaultPipelineStages {
+			if !slices.Contains(p.Stages, stage) {
+				p.Skipped = append(p.Skipped, stage)
+			}
+		}
+	}
+
+	if cfg.ForceDelay < 0 {
+		errs.Add("forceDelay", "must be 0 or more for %s", indexer)
+	} else if cfg.ForceDelay > 0 && !p.Has(PipelineStageDelay) {
+		errs.Add("forceDelay", "needs stage %q for %s", PipelineStageDelay, indexer)
+	}
+
+	p.ForceDelay = cfg.ForceDelay
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func parsePipelineStage(s string) (PipelineStage, bool) {
+	for _, stage := range DefaultPipelineStages {
+		if strings.EqualFold(string(stage), s) {
+			return stage, true
+		}
+	}
+
+	return "", false
+}
+
+// IndexerPipeline returns the effective pipeline for the indexer identifier
+func (c *Config) IndexerPipeline(indexer string) (*IndexerPipeline, error) {
+	cfg, ok := c.Pipeline[strings.ToLower(indexer)]
+	if !ok {
+		return NewIndexerPipeline(indexer, nil)
+	}
+
+	return NewIndexerPipeline(indexer, &cfg)
+}
+
+// EffectiveIndexerPipeline returns the pipeline used for the indexer, the default pipeline with the error if the config is invalid
+func (c *Config) EffectiveIndexerPipeline(indexer string) *IndexerPipeline {
+	p, err := c.IndexerPipeline(indexer)
+	if err != nil {
+		p, _ = NewIndexerPipeline(indexer, nil)
+		p.Error = err.Error()
+	}
+
+	return p
+}
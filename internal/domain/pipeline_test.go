@@ -0,0 +1,110 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIndexerPipeline(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *PipelineConfig
+		want    *IndexerPipeline
+		wantErr string
+	}{
+		{
+			name: "default",
+			cfg:  nil,
+			want: &IndexerPipeline{
+				Indexer: "mock",
+				Stages:  DefaultPipelineStages,
+				Skipped: []PipelineStage{},
+			},
+		},
+		{
+			name: "reorder_and_skip",
+			cfg: &PipelineConfig{
+				Stages:     []string{"enrichment", "duplicatecheck", "delay"},
+				ForceDelay: 5,
+			},
+			want: &IndexerPipeline{
+				Indexer:    "mock",
+				Custom:     true,
+				Stages:     []PipelineStage{PipelineStageEnrichment, PipelineStageDuplicateCheck, PipelineStageDelay},
+				Skipped:    []PipelineStage{PipelineStageExternal},
+				ForceDelay: 5,
+			},
+		},
+		{
+			name: "only_force_delay",
+			cfg:  &PipelineConfig{ForceDelay: 10},
+			want: &IndexerPipeline{
+				Indexer:    "mock",
+				Custom:     true,
+				Stages:     DefaultPipelineStages,
+				Skipped:    []PipelineStage{},
+				ForceDelay: 10,
+			},
+		},
+		{
+			name:    "unknown_stage",
+			cfg:     &PipelineConfig{Stages: []string{"enrichment", "crossSeed"}},
+			wantErr: `validation error: stages: unknown stage "crossSeed" for mock`,
+		},
+		{
+			name:    "delay_not_last",
+			cfg:     &PipelineConfig{Stages: []string{"delay", "external"}},
+			wantErr: `validation error: stages: stage "delay" must be last for mock`,
+		},
+		{
+			name:    "force_delay_without_stage",
+			cfg:     &PipelineConfig{Stages: []string{"external"}, ForceDelay: 5},
+			wantErr: `validation error: forceDelay: needs stage "delay" for mock`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewIndexerPipeline("mock", tt.cfg)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIndexerPipeline_Delay(t *testing.T) {
+	f := &Filter{Delay: 30}
+
+	var nilPipeline *IndexerPipeline
+	assert.Equal(t, 30, nilPipeline.Delay(f))
+
+	p, _ := NewIndexerPipeline("mock", &PipelineConfig{ForceDelay: 5})
+	assert.Equal(t, 5, p.Delay(f))
+
+	p, _ = NewIndexerPipeline("mock", &PipelineConfig{Stages: []string{"external"}})
+	assert.Equal(t, 0, p.Delay(f))
+}
+
+func TestConfig_EffectiveIndexerPipeline(t *testing.T) {
+	cfg := &Config{
+		Pipeline: map[string]PipelineConfig{
+			"mock": {Stages: []string{"nope"}},
+		},
+	}
+
+	p := cfg.EffectiveIndexerPipeline("Mock")
+	assert.Equal(t, DefaultPipelineStages, p.Stages)
+	assert.Equal(t, `validation error: stages: unknown stage "nope" for Mock`, p.Error)
+
+	p = cfg.EffectiveIndexerPipeline("other")
+	assert.False(t, p.Custom)
+	assert.Empty(t, p.Error)
+}
@@ -45,19 +45,21 @@ func (p Proxy) ValidProxyType() bool {
 }
 
 func (p Proxy) Validate() error {
+	var errs ValidationErrors
+
 	if !p.ValidProxyType() {
-		return errors.New("invalid proxy type: %s", p.Type)
+		errs.Add("type", "invalid proxy type: %s", p.Type)
 	}
 
 	if err := ValidateProxyAddr(p.Addr); err != nil {
-		return err
+		errs.Add("addr", err.Error())
 	}
 
 	if p.Name == "" {
-		return errors.New("name is required")
+		errs.Add("name", "name is required")
 	}
 
-	return nil
+	return errs.Err()
 }
 
 func ValidateProxyAddr(addr string) error {
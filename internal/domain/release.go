@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"html"
 	"io"
+	"math"
 	"net/http"
 	"net/http/cookiejar"
 	"os"
@@ -17,6 +18,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/sharedhttp"
@@ -32,15 +34,26 @@ import (
 type ReleaseRepo interface {
 	Store(ctx context.Context, release *Release) error
 	Find(ctx context.Context, params ReleaseQueryParams) (*FindReleasesResponse, error)
+	Export(ctx context.Context, params ReleaseExportParams, fn func(release *Release) error) error
 	Get(ctx context.Context, req *GetReleaseRequest) (*Release, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*ReleaseStats, error)
-	Delete(ctx context.Context, req *DeleteReleaseRequest) error
+	Delete(ctx context.Context, req *DeleteReleaseRequest) (int64, error)
 	CheckSmartEpisodeCanDownload(ctx context.Context, p *SmartEpisodeParams) (bool, error)
+	CheckSmartMusicCanDownload(ctx context.Context, p *SmartMusicParams) (bool, error)
+	FindUpgradeOriginals(ctx context.Context, p *ReleaseUpgradeParams) ([]ReleaseUpgradeOriginal, error)
+	CheckInfoHashPushed(ctx context.Context, infoHash string, excludeReleaseID int64) (bool, error)
 	UpdateBaseURL(ctx context.Context, indexer string, oldBaseURL, newBaseURL string) error
 
 	GetActionStatus(ctx context.Context, req *GetReleaseActionStatusRequest) (*ReleaseActionStatus, error)
 	StoreReleaseActionStatus(ctx context.Context, status *ReleaseActionStatus) error
+	GetPushLatencies(ctx context.Context, since time.Time) ([]ReleasePushLatency, error)
+	FindTrackedPushes(ctx context.Context, since time.Time) ([]ReleaseTrackedPush, error)
+	SetActionStatusCompleted(ctx context.Context, id int64, completedAt time.Time) error
+	FindRemovablePushes(ctx context.Context) ([]ReleaseRemovablePush, error)
+	SetActionStatusRemoved(ctx context.Context, id int64, removedAt time.Time) error
+	FindFailedActionStatuses(ctx context.Context, req *ReleaseRetryFailedReq) ([]ReleaseActionStatus, error)
+	UpdateAnnotation(ctx context.Context, req *ReleaseAnnotationReq) error
 }
 
 type Release struct {
@@ -66,11 +79,14 @@ type Release struct {
 	Description                 string                `json:"-"`
 	Category                    string                `json:"category"`
 	Categories                  []string              `json:"categories,omitempty"`
+	NormalizedCategories        []string              `json:"-"` // set from the indexer category map, see NormalizeIndexerCategories
 	Season                      int                   `json:"season"`
 	Episode                     int                   `json:"episode"`
 	Year                        int                   `json:"year"`
 	Month                       int                   `json:"month"`
 	Day                         int                   `json:"day"`
+	MediaIDs                    *MediaIDs             `json:"media_ids,omitempty"`   // resolved by the id check of the enrichment stage
+	MusicBrainz                 *MusicBrainzMatch     `json:"musicbrainz,omitempty"` // resolved by the musicbrainz check of the enrichment stage
 	Resolution                  string                `json:"resolution"`
 	Source                      string                `json:"source"`
 	Codec                       []string              `json:"codec"`
@@ -107,6 +123,30 @@ type Release struct {
 	FilterID                    int                   `json:"-"`
 	Filter                      *Filter               `json:"-"`
 	ActionStatus                []ReleaseActionStatus `json:"action_status"`
+
+	// Notes and UserTags are set by users on the stored release
+	Notes    string   `json:"notes"`
+	UserTags []string `json:"user_tags"`
+
+	// Pipeline is the processing pipeline for the indexer, default stages when nil
+	Pipeline *IndexerPipeline `json:"-"`
+
+	// download url which spends a freeleech token, only set for indexers supporting it
+	FreeleechTokenURL  string `json:"-"`
+	FreeleechTokenUsed bool   `json:"-"`
+
+	// set by exec filters and actions, see ExecResult
+	ActionCategory string            `json:"-"`
+	MacroVars      map[string]string `json:"-"`
+
+	// AnnouncedSize is the size claimed by the announce or feed before it's replaced by the torrent size
+	AnnouncedSize uint64 `json:"-"`
+
+	// Replaces are the torrents removed from the clients before this PROPER or REPACK is added
+	Replaces []ReleaseUpgradeOriginal `json:"-"`
+
+	// Reprocessed releases were stored before and run through the filters again, their push latency isn't measured
+	Reprocessed bool `json:"-"`
 }
 
 func (r *Release) Raw(s string) rls.Release {
@@ -114,25 +154,175 @@ func (r *Release) Raw(s string) rls.Release {
 }
 
 type ReleaseActionStatus struct {
-	ID         int64             `json:"id"`
-	Status     ReleasePushStatus `json:"status"`
-	Action     string            `json:"action"`
-	ActionID   int64             `json:"action_id"`
-	Type       ActionType        `json:"type"`
-	Client     string            `json:"client"`
-	Filter     string            `json:"filter"`
-	FilterID   int64             `json:"filter_id"`
-	Rejections []string          `json:"rejections"`
-	ReleaseID  int64             `json:"release_id"`
-	Timestamp  time.Time         `json:"timestamp"`
+	ID          int64               `json:"id"`
+	Status      ReleasePushStatus   `json:"status"`
+	Action      string              `json:"action"`
+	ActionID    int64               `json:"action_id"`
+	Type        ActionType          `json:"type"`
+	Client      string              `json:"client"`
+	Filter      string              `json:"filter"`
+	FilterID    int64               `json:"filter_id"`
+	Rejections  []string            `json:"rejections"`
+	ReleaseID   int64               `json:"release_id"`
+	Timestamp   time.Time           `json:"timestamp"`
+	LatencyMs   int64               `json:"latency_ms,omitempty"` // time from announce to client accepted
+	InfoHash    string              `json:"info_hash,omitempty"`  // of the torrent added to the client, used to replace it with an upgrade
+	Reannounce  []ReannounceAttempt `json:"reannounce,omitempty"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"` // when the client finished downloading the torrent
+	RemovedAt   *time.Time          `json:"removed_at,omitempty"`   // when the torrent was removed from the client by the removal policy of the action
+}
+
+// ReleaseRetryFailedReq selects the failed pushes to retry, empty fields match everything
+type ReleaseRetryFailedReq struct {
+	Client  string `json:"client,omitempty"`
+	Indexer string `json:"indexer,omitempty"`
+	// MaxAge in hours only retries the pushes that failed within the last hours, 0 retries all
+	MaxAge int `json:"max_age,omitempty"`
+}
+
+// ReleaseRetryFailedLimit caps the number of failed pushes retried at once, the newest are retried first
+const ReleaseRetryFailedLimit = 500
+
+type ReleaseRetryFailedResponse struct {
+	Retrying int `json:"retrying"`
+}
+
+const (
+	ReleaseNotesMaxLength   = 4096
+	ReleaseUserTagMaxLength = 64
+	ReleaseUserTagsMax      = 20
+)
+
+// ReleaseAnnotationReq sets the notes and user tags of a stored release, the fields left out are kept
+type ReleaseAnnotationReq struct {
+	ReleaseID int64     `json:"-"`
+	Notes     *string   `json:"notes,omitempty"`
+	UserTags  *[]string `json:"user_tags,omitempty"`
+}
+
+// Validate trims the notes and tags and drops duplicate tags.
+// Tags are stored comma separated so they can't contain commas.
+func (r *ReleaseAnnotationReq) Validate() error {
+	var errs ValidationErrors
+
+	if r.Notes == nil && r.UserTags == nil {
+		errs.Add("notes", "notes or user_tags is required")
+		return errs.Err()
+	}
+
+	if r.Notes != nil {
+		notes := strings.TrimSpace(*r.Notes)
+		if utf8.RuneCountInString(notes) > ReleaseNotesMaxLength {
+			errs.Add("notes", "notes must be at most %d characters", ReleaseNotesMaxLength)
+		}
+		r.Notes = &notes
+	}
+
+	if r.UserTags != nil {
+		tags := make([]string, 0, len(*r.UserTags))
+		for _, tag := range *r.UserTags {
+			tag = strings.TrimSpace(tag)
+
+			switch {
+			case tag == "":
+				continue
+			case strings.Contains(tag, ","):
+				errs.Add("user_tags", "tag %q must not contain a comma", tag)
+			case utf8.RuneCountInString(tag) > ReleaseUserTagMaxLength:
+				errs.Add("user_tags", "tag %q must be at most %d characters", tag, ReleaseUserTagMaxLength)
+			case slices.Contains(tags, tag):
+				continue
+			}
+
+			tags = append(tags, tag)
+		}
+
+		if len(tags) > ReleaseUserTagsMax {
+			errs.Add("user_tags", "at most %d tags are allowed", ReleaseUserTagsMax)
+		}
+		r.UserTags = &tags
+	}
+
+	return errs.Err()
+}
+
+// ReleaseTrackedPush is a torrent pushed to a client that is tracked until it finished downloading
+type ReleaseTrackedPush struct {
+	ActionStatusID int64
+	ReleaseID      int64
+	TorrentName    string
+	Size           uint64
+	Indexer        string
+	Filter         string
+	ActionID       int64
+	Action         string
+	ActionType     ActionType
+	ClientID       int32
+	Client         string
+	InfoHash       string
+	Timestamp      time.Time
+}
+
+// ReleaseRemovablePush is a torrent pushed to a client by an action with a removal policy
+type ReleaseRemovablePush struct {
+	ActionStatusID   int64
+	ReleaseID        int64
+	TorrentName      string
+	Action           string
+	ClientID         int32
+	Client           string
+	InfoHash         string
+	Timestamp        time.Time
+	RemoveRatio      float64
+	RemoveSeedTime   int64
+	RemoveAfterDays  int
+	RemoveDeleteData bool
+}
+
+// TorrentState is the seeding state of a torrent in a download client
+type TorrentState struct {
+	Ratio       float64
+	SeedingTime time.Duration
+}
+
+// RemovalReason returns which limit of the removal policy the torrent reached, empty while none is reached
+func (p ReleaseRemovablePush) RemovalReason(state TorrentState, now time.Time) string {
+	if p.RemoveRatio > 0 && state.Ratio >= p.RemoveRatio {
+		return fmt.Sprintf("ratio %.2f reached %.2f", state.Ratio, p.RemoveRatio)
+	}
+
+	if p.RemoveSeedTime > 0 && state.SeedingTime >= time.Duration(p.RemoveSeedTime)*time.Minute {
+		return fmt.Sprintf("seeded for %s", state.SeedingTime.Round(time.Minute))
+	}
+
+	if p.RemoveAfterDays > 0 && now.Sub(p.Timestamp) >= time.Duration(p.RemoveAfterDays)*24*time.Hour {
+		return fmt.Sprintf("pushed more than %d days ago", p.RemoveAfterDays)
+	}
+
+	return ""
+}
+
+// ReannounceAttempt is a check of the trackers of a torrent after it was added, re-announced when not working yet
+type ReannounceAttempt struct {
+	Attempt   int       `json:"attempt"`
+	Timestamp time.Time `json:"timestamp"`
+	Working   bool      `json:"working"`
+	Message   string    `json:"message,omitempty"`
 }
 
+// DeleteReleaseRequest selects the releases to delete, the conditions are combined and empty ones match all releases
 type DeleteReleaseRequest struct {
+	// OlderThan is in hours
 	OlderThan       int
 	Indexers        []string
+	FilterIDs       []int
 	ReleaseStatuses []string
 }
 
+type DeleteReleaseResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
 func NewReleaseActionStatus(action *Action, release *Release) *ReleaseActionStatus {
 	s := &ReleaseActionStatus{
 		ID:         0,
@@ -168,6 +358,84 @@ type ReleaseStats struct {
 	PushErrorCount      int64 `json:"push_error_count"`
 }
 
+// ReleasePushLatencySampleLimit caps the number of pushes used for the latency percentiles
+const ReleasePushLatencySampleLimit = 10000
+
+// ReleasePushLatency is the time from announce to the client accepting the release
+type ReleasePushLatency struct {
+	Indexer   string
+	LatencyMs int64
+	Timestamp time.Time
+}
+
+type LatencyPercentiles struct {
+	Count int   `json:"count"`
+	Min   int64 `json:"min_ms"`
+	P50   int64 `json:"p50_ms"`
+	P90   int64 `json:"p90_ms"`
+	P95   int64 `json:"p95_ms"`
+	P99   int64 `json:"p99_ms"`
+	Max   int64 `json:"max_ms"`
+}
+
+// NewLatencyPercentiles calculates nearest-rank percentiles of latencies in milliseconds
+func NewLatencyPercentiles(latencies []int64) LatencyPercentiles {
+	if len(latencies) == 0 {
+		return LatencyPercentiles{}
+	}
+
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	percentile := func(p float64) int64 {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1]
+	}
+
+	return LatencyPercentiles{
+		Count: len(sorted),
+		Min:   sorted[0],
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P95:   percentile(95),
+		P99:   percentile(99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+type IndexerLatencyStats struct {
+	Indexer string             `json:"indexer"`
+	Latency LatencyPercentiles `json:"latency"`
+	Recent  LatencyPercentiles `json:"recent"`
+
+	// Regression is set when the recent p95 is considerably slower than the baseline
+	Regression bool `json:"regression"`
+}
+
+type ReleaseLatencyStats struct {
+	Since    time.Time             `json:"since"`
+	Overall  LatencyPercentiles    `json:"overall"`
+	Indexers []IndexerLatencyStats `json:"indexers"`
+
+	// SampleCapped is set when there were more pushes than ReleasePushLatencySampleLimit,
+	// the percentiles then only cover the newest pushes since SampleSince
+	SampleCapped bool       `json:"sample_capped"`
+	SampleSince  *time.Time `json:"sample_since,omitempty"`
+}
+
+// ReleaseConsistencyReport lists releases seen by only one of IRC or feeds for an indexer
+type ReleaseConsistencyReport struct {
+	Indexer      string    `json:"indexer"`
+	IRCCount     int       `json:"irc_count"`
+	FeedCount    int       `json:"feed_count"`
+	MissedByIRC  []string  `json:"missed_by_irc"`
+	MissedByFeed []string  `json:"missed_by_feed"`
+	CheckedAt    time.Time `json:"checked_at"`
+}
+
 type ReleasePushStatus string
 
 const (
@@ -241,6 +509,8 @@ const (
 	ReleaseImplementationTorznab ReleaseImplementation = "TORZNAB"
 	ReleaseImplementationNewznab ReleaseImplementation = "NEWZNAB"
 	ReleaseImplementationRSS     ReleaseImplementation = "RSS"
+	ReleaseImplementationWebhook ReleaseImplementation = "WEBHOOK"
+	ReleaseImplementationMock    ReleaseImplementation = "MOCK"
 )
 
 func (r ReleaseImplementation) String() string {
@@ -253,6 +523,10 @@ func (r ReleaseImplementation) String() string {
 		return "NEWZNAB"
 	case ReleaseImplementationRSS:
 		return "RSS"
+	case ReleaseImplementationWebhook:
+		return "WEBHOOK"
+	case ReleaseImplementationMock:
+		return "MOCK"
 	default:
 		return "IRC"
 	}
@@ -266,6 +540,26 @@ type ReleaseQueryParams struct {
 	Filters struct {
 		Indexers   []string
 		PushStatus string
+
+		Resolutions   []string
+		Sources       []string
+		Codecs        []string
+		HDR           []string
+		ReleaseGroups []string
+
+		// MinSize and MaxSize are in bytes, zero is no limit
+		MinSize uint64
+		MaxSize uint64
+
+		// Clients matches the releases with an action status of one of the download clients,
+		// combined with PushStatus it has to be the same action status
+		Clients []string
+
+		// UserTags matches releases with any of the tags
+		UserTags []string
+
+		From time.Time
+		To   time.Time
 	}
 	Search string
 }
@@ -288,6 +582,12 @@ type ReleaseProcessReq struct {
 	AnnounceLines         []string `json:"announce_lines"`
 }
 
+// ReleaseReprocessReq runs a stored release through the filters of its indexer again, or only FilterID when set
+type ReleaseReprocessReq struct {
+	ReleaseID int `json:"-"`
+	FilterID  int `json:"filter_id,omitempty"`
+}
+
 type GetReleaseRequest struct {
 	Id int
 }
@@ -431,6 +731,26 @@ func (r *Release) ParseSizeBytesString(size string) {
 	}
 }
 
+// TorrentFiles returns the paths of the files in the downloaded torrent file, relative to the torrent folder
+func (r *Release) TorrentFiles() ([]string, error) {
+	meta, err := metainfo.LoadFromFile(r.TorrentTmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load torrent file: %s", r.TorrentTmpFile)
+	}
+
+	info, err := meta.UnmarshalInfo()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal info from torrent: %s", r.TorrentTmpFile)
+	}
+
+	var files []string
+	for _, file := range info.UpvertedFiles() {
+		files = append(files, file.DisplayPath(&info))
+	}
+
+	return files, nil
+}
+
 func (r *Release) OpenTorrentFile() error {
 	tmpFile, err := os.ReadFile(r.TorrentTmpFile)
 	if err != nil {
@@ -589,7 +909,7 @@ func (r *Release) downloadTorrentFile(ctx context.Context) error {
 
 		r.TorrentTmpFile = tmpFile.Name()
 		r.TorrentHash = meta.HashInfoBytes().String()
-		r.Size = uint64(torrentMetaInfo.TotalLength())
+		r.SetTorrentSize(uint64(torrentMetaInfo.TotalLength()))
 
 		return nil
 	},
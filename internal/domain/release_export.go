@@ -0,0 +1,199 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type ReleaseExportFormat string
+
+const (
+	ReleaseExportFormatCSV  ReleaseExportFormat = "csv"
+	ReleaseExportFormatJSON ReleaseExportFormat = "json"
+)
+
+// ReleaseExportParams filters the releases to export, the zero value exports the full history
+type ReleaseExportParams struct {
+	Indexers   []string
+	PushStatus string
+	From       time.Time
+	To         time.Time
+}
+
+// ReleaseExportWriter writes releases one at a time so the history can be streamed
+type ReleaseExportWriter interface {
+	Write(release *Release) error
+	Close() error
+}
+
+func NewReleaseExportWriter(format ReleaseExportFormat, w io.Writer) (ReleaseExportWriter, error) {
+	switch format {
+	case ReleaseExportFormatCSV:
+		return newReleaseCSVWriter(w), nil
+	case ReleaseExportFormatJSON:
+		return &releaseJSONWriter{w: w}, nil
+	default:
+		return nil, errors.New("unsupported export format: %q", format)
+	}
+}
+
+var releaseCSVHeader = []string{
+	"release_id",
+	"timestamp",
+	"indexer",
+	"filter",
+	"filter_status",
+	"rejections",
+	"protocol",
+	"name",
+	"title",
+	"size",
+	"category",
+	"season",
+	"episode",
+	"year",
+	"resolution",
+	"source",
+	"codec",
+	"container",
+	"group",
+	"info_url",
+	"download_url",
+	"action",
+	"action_type",
+	"action_client",
+	"action_status",
+	"action_rejections",
+	"action_timestamp",
+}
+
+// releaseCSVWriter writes a row per action status, releases without actions get a single row with empty action columns
+type releaseCSVWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newReleaseCSVWriter(w io.Writer) *releaseCSVWriter {
+	return &releaseCSVWriter{w: csv.NewWriter(w)}
+}
+
+func (c *releaseCSVWriter) Write(release *Release) error {
+	if !c.headerWritten {
+		if err := c.w.Write(releaseCSVHeader); err != nil {
+			return errors.Wrap(err, "could not write csv header")
+		}
+		c.headerWritten = true
+	}
+
+	row := []string{
+		strconv.FormatInt(release.ID, 10),
+		release.Timestamp.UTC().Format(time.RFC3339),
+		release.Indexer.Identifier,
+		release.FilterName,
+		string(release.FilterStatus),
+		strings.Join(release.Rejections, "; "),
+		release.Protocol.String(),
+		release.TorrentName,
+		release.Title,
+		strconv.FormatUint(release.Size, 10),
+		release.Category,
+		strconv.Itoa(release.Season),
+		strconv.Itoa(release.Episode),
+		strconv.Itoa(release.Year),
+		release.Resolution,
+		release.Source,
+		strings.Join(release.Codec, ","),
+		release.Container,
+		release.Group,
+		release.InfoURL,
+		release.DownloadURL,
+	}
+
+	if len(release.ActionStatus) == 0 {
+		if err := c.w.Write(append(row, "", "", "", "", "", "")); err != nil {
+			return errors.Wrap(err, "could not write csv row")
+		}
+	}
+
+	for _, status := range release.ActionStatus {
+		actionRow := append(slices.Clone(row),
+			status.Action,
+			string(status.Type),
+			status.Client,
+			string(status.Status),
+			strings.Join(status.Rejections, "; "),
+			status.Timestamp.UTC().Format(time.RFC3339),
+		)
+
+		if err := c.w.Write(actionRow); err != nil {
+			return errors.Wrap(err, "could not write csv row")
+		}
+	}
+
+	// flush per release so the rows are streamed
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+func (c *releaseCSVWriter) Close() error {
+	if !c.headerWritten {
+		if err := c.w.Write(releaseCSVHeader); err != nil {
+			return errors.Wrap(err, "could not write csv header")
+		}
+	}
+
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+// releaseJSONWriter writes a json array of releases including their action statuses
+type releaseJSONWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *releaseJSONWriter) Write(release *Release) error {
+	data, err := json.Marshal(release)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal release %d", release.ID)
+	}
+
+	prefix := ","
+	if j.count == 0 {
+		prefix = "["
+	}
+
+	if _, err := io.WriteString(j.w, prefix); err != nil {
+		return err
+	}
+
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+
+	j.count++
+
+	return nil
+}
+
+func (j *releaseJSONWriter) Close() error {
+	end := "]"
+	if j.count == 0 {
+		end = "[]"
+	}
+
+	_, err := io.WriteString(j.w, end)
+	return err
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseExportWriter(t *testing.T) {
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	releases := []*Release{
+		{
+			ID:           2,
+			FilterStatus: ReleaseStatusFilterApproved,
+			Rejections:   []string{},
+			Indexer:      IndexerMinimal{Identifier: "mock"},
+			FilterName:   "movies",
+			Protocol:     ReleaseProtocolTorrent,
+			Timestamp:    timestamp,
+			TorrentName:  "That Movie 2020 1080p BluRay x264-GROUP",
+			Title:        "That Movie",
+			Size:         1024,
+			Year:         2020,
+			Codec:        []string{"x264"},
+			ActionStatus: []ReleaseActionStatus{
+				{ID: 1, Action: "qbit", Type: ActionTypeQbittorrent, Client: "qb", Status: ReleasePushStatusApproved, Rejections: []string{}, Timestamp: timestamp},
+				{ID: 2, Action: "deluge", Type: ActionTypeDelugeV2, Client: "deluge", Status: ReleasePushStatusRejected, Rejections: []string{"max downloads", "disk full"}, Timestamp: timestamp},
+			},
+		},
+		{
+			ID:           1,
+			FilterStatus: ReleaseStatusFilterPending,
+			Rejections:   []string{"size too big"},
+			Indexer:      IndexerMinimal{Identifier: "mock"},
+			Protocol:     ReleaseProtocolTorrent,
+			Timestamp:    timestamp,
+			TorrentName:  "That Show S01E01 720p WEB h264-GROUP",
+			Season:       1,
+			Episode:      1,
+		},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		w, err := NewReleaseExportWriter(ReleaseExportFormatCSV, &buf)
+		assert.NoError(t, err)
+
+		for _, rls := range releases {
+			assert.NoError(t, w.Write(rls))
+		}
+		assert.NoError(t, w.Close())
+
+		want := "release_id,timestamp,indexer,filter,filter_status,rejections,protocol,name,title,size,category,season,episode,year,resolution,source,codec,container,group,info_url,download_url,action,action_type,action_client,action_status,action_rejections,action_timestamp\n" +
+			"2,2024-01-02T03:04:05Z,mock,movies,FILTER_APPROVED,,torrent,That Movie 2020 1080p BluRay x264-GROUP,That Movie,1024,,0,0,2020,,,x264,,,,,qbit,QBITTORRENT,qb,PUSH_APPROVED,,2024-01-02T03:04:05Z\n" +
+			"2,2024-01-02T03:04:05Z,mock,movies,FILTER_APPROVED,,torrent,That Movie 2020 1080p BluRay x264-GROUP,That Movie,1024,,0,0,2020,,,x264,,,,,deluge,DELUGE_V2,deluge,PUSH_REJECTED,max downloads; disk full,2024-01-02T03:04:05Z\n" +
+			"1,2024-01-02T03:04:05Z,mock,,PENDING,size too big,torrent,That Show S01E01 720p WEB h264-GROUP,,0,,1,1,0,,,,,,,,,,,,,\n"
+
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		w, err := NewReleaseExportWriter(ReleaseExportFormatJSON, &buf)
+		assert.NoError(t, err)
+
+		for _, rls := range releases {
+			assert.NoError(t, w.Write(rls))
+		}
+		assert.NoError(t, w.Close())
+
+		var got []Release
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Len(t, got, 2)
+		assert.Equal(t, int64(2), got[0].ID)
+		assert.Len(t, got[0].ActionStatus, 2)
+		assert.Equal(t, ReleasePushStatusRejected, got[0].ActionStatus[1].Status)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		w, err := NewReleaseExportWriter(ReleaseExportFormatJSON, &buf)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+		assert.Equal(t, "[]", buf.String())
+	})
+
+	t.Run("unsupported_format", func(t *testing.T) {
+		_, err := NewReleaseExportWriter("xml", &bytes.Buffer{})
+		assert.EqualError(t, err, `unsupported export format: "xml"`)
+	})
+}
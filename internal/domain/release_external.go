@@ -0,0 +1,317 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// ExternalRelease is a release pushed from Prowlarr or Jackett.
+// Prowlarr search results, Prowlarr webhook releases and Jackett results are accepted.
+type ExternalRelease struct {
+	Title       string          `json:"title"`
+	DownloadURL string          `json:"download_url"`
+	MagnetURL   string          `json:"magnet_url"`
+	InfoURL     string          `json:"info_url"`
+	InfoHash    string          `json:"info_hash"`
+	Size        uint64          `json:"size"`
+	Indexer     string          `json:"indexer"`
+	Categories  []string        `json:"categories"`
+	Protocol    ReleaseProtocol `json:"protocol"`
+	Seeders     int             `json:"seeders"`
+	Leechers    int             `json:"leechers"`
+	PublishDate time.Time       `json:"publish_date"`
+}
+
+// externalReleasePayload has the field names of both tools, json matches them case-insensitively
+type externalReleasePayload struct {
+	// Prowlarr
+	Title        string          `json:"title"`
+	ReleaseTitle string          `json:"releaseTitle"`
+	DownloadURL  string          `json:"downloadUrl"`
+	MagnetURL    string          `json:"magnetUrl"`
+	InfoURL      string          `json:"infoUrl"`
+	Indexer      string          `json:"indexer"`
+	Categories   json.RawMessage `json:"categories"`
+	Protocol     string          `json:"protocol"`
+	Leechers     int             `json:"leechers"`
+
+	// Jackett
+	Link         string `json:"Link"`
+	Details      string `json:"Details"`
+	Comments     string `json:"Comments"`
+	MagnetURI    string `json:"MagnetUri"`
+	Tracker      string `json:"Tracker"`
+	TrackerID    string `json:"TrackerId"`
+	CategoryDesc string `json:"CategoryDesc"`
+	Category     []int  `json:"Category"`
+	Peers        int    `json:"Peers"`
+
+	// shared
+	Guid        string    `json:"guid"`
+	InfoHash    string    `json:"infoHash"`
+	Size        uint64    `json:"size"`
+	Seeders     int       `json:"seeders"`
+	PublishDate time.Time `json:"publishDate"`
+}
+
+// externalReleaseEnvelope wraps the releases of a Jackett search response or a Prowlarr webhook
+type externalReleaseEnvelope struct {
+	EventType string                   `json:"eventType"`
+	Release   *externalReleasePayload  `json:"release"`
+	Results   []externalReleasePayload `json:"Results"`
+}
+
+// ParseExternalReleases parses a single release, a list of releases, a Jackett search response
+// or a Prowlarr webhook from data
+func ParseExternalReleases(data []byte) ([]ExternalRelease, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty payload")
+	}
+
+	var payloads []externalReleasePayload
+
+	switch data[0] {
+	case '[':
+		if err := json.Unmarshal(data, &payloads); err != nil {
+			return nil, errors.Wrap(err, "could not decode releases")
+		}
+
+	case '{':
+		var envelope externalReleaseEnvelope
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return nil, errors.Wrap(err, "could not decode release")
+		}
+
+		switch {
+		case envelope.EventType == "Test":
+			// prowlarr sends a test event when saving the connection
+			return []ExternalRelease{}, nil
+		case envelope.Release != nil:
+			payloads = append(payloads, *envelope.Release)
+		case envelope.Results != nil:
+			payloads = envelope.Results
+		default:
+			var payload externalReleasePayload
+			if err := json.Unmarshal(data, &payload); err != nil {
+				return nil, errors.Wrap(err, "could not decode release")
+			}
+			payloads = append(payloads, payload)
+		}
+
+	default:
+		return nil, errors.New("payload is not a json object or array")
+	}
+
+	releases := make([]ExternalRelease, 0, len(payloads))
+
+	for idx, payload := range payloads {
+		release, err := payload.toExternalRelease()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid release at index %d", idx)
+		}
+
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+// ParseWebhookReleases parses a release or a list of releases submitted to the release webhook
+// by custom tools, the fields are those of ExternalRelease
+func ParseWebhookReleases(data []byte) ([]ExternalRelease, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty payload")
+	}
+
+	var releases []ExternalRelease
+
+	switch data[0] {
+	case '[':
+		if err := json.Unmarshal(data, &releases); err != nil {
+			return nil, errors.Wrap(err, "could not decode releases")
+		}
+
+	case '{':
+		var release ExternalRelease
+		if err := json.Unmarshal(data, &release); err != nil {
+			return nil, errors.Wrap(err, "could not decode release")
+		}
+		releases = append(releases, release)
+
+	default:
+		return nil, errors.New("payload is not a json object or array")
+	}
+
+	if len(releases) == 0 {
+		return nil, errors.New("no releases in payload")
+	}
+
+	for idx := range releases {
+		if err := releases[idx].validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid release at index %d", idx)
+		}
+	}
+
+	return releases, nil
+}
+
+// validate checks the release can be downloaded, a magnet link passed as download url is moved to the magnet url
+func (e *ExternalRelease) validate() error {
+	if e.Title == "" {
+		return errors.New("missing title")
+	}
+
+	if strings.HasPrefix(e.DownloadURL, "magnet:") {
+		e.MagnetURL, e.DownloadURL = e.DownloadURL, ""
+	}
+
+	if e.DownloadURL == "" && e.MagnetURL == "" {
+		return errors.New("missing download url for %q", e.Title)
+	}
+
+	switch e.Protocol {
+	case "":
+		e.Protocol = ReleaseProtocolTorrent
+	case ReleaseProtocolTorrent, ReleaseProtocolNzb:
+	default:
+		return errors.New("invalid protocol %q for %q", string(e.Protocol), e.Title)
+	}
+
+	e.InfoHash = strings.ToLower(e.InfoHash)
+
+	return nil
+}
+
+func (p *externalReleasePayload) toExternalRelease() (ExternalRelease, error) {
+	r := ExternalRelease{
+		Title:       firstNonEmpty(p.Title, p.ReleaseTitle),
+		DownloadURL: firstNonEmpty(p.DownloadURL, p.Link),
+		MagnetURL:   firstNonEmpty(p.MagnetURL, p.MagnetURI),
+		InfoURL:     firstNonEmpty(p.InfoURL, p.Details, p.Comments, p.Guid),
+		InfoHash:    strings.ToLower(p.InfoHash),
+		Size:        p.Size,
+		Indexer:     firstNonEmpty(p.Indexer, p.Tracker, p.TrackerID),
+		Protocol:    ReleaseProtocolTorrent,
+		Seeders:     p.Seeders,
+		Leechers:    p.Leechers,
+		PublishDate: p.PublishDate,
+	}
+
+	if p.Protocol == ReleaseProtocolNzb.String() {
+		r.Protocol = ReleaseProtocolNzb
+	}
+
+	if err := r.validate(); err != nil {
+		return r, err
+	}
+
+	// jackett reports peers including seeders
+	if r.Leechers == 0 && p.Peers > r.Seeders {
+		r.Leechers = p.Peers - r.Seeders
+	}
+
+	categories, err := parseExternalCategories(p.Categories)
+	if err != nil {
+		return r, err
+	}
+
+	if len(categories) == 0 && p.CategoryDesc != "" {
+		categories = append(categories, p.CategoryDesc)
+	}
+
+	if len(categories) == 0 {
+		for _, id := range p.Category {
+			categories = append(categories, strconv.Itoa(id))
+		}
+	}
+
+	r.Categories = categories
+
+	return r, nil
+}
+
+// parseExternalCategories parses Prowlarr categories, a list of objects in search results and names in webhooks
+func parseExternalCategories(data json.RawMessage) ([]string, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err == nil {
+		return names, nil
+	}
+
+	var categories []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return nil, errors.Wrap(err, "could not decode categories")
+	}
+
+	names = make([]string, 0, len(categories))
+	for _, category := range categories {
+		if category.Name != "" {
+			names = append(names, category.Name)
+		} else {
+			names = append(names, strconv.Itoa(category.ID))
+		}
+	}
+
+	return names, nil
+}
+
+// Release maps the external release into a release for indexer
+func (e *ExternalRelease) Release(indexer IndexerMinimal) *Release {
+	rls := NewRelease(indexer)
+	rls.Implementation = ReleaseImplementationWebhook
+	rls.Protocol = e.Protocol
+
+	rls.TorrentName = e.Title
+	rls.DownloadURL = e.DownloadURL
+	rls.MagnetURI = e.MagnetURL
+	rls.InfoURL = e.InfoURL
+	rls.TorrentHash = e.InfoHash
+	rls.Size = e.Size
+	rls.Seeders = e.Seeders
+	rls.Leechers = e.Leechers
+
+	if len(e.Categories) > 0 {
+		rls.Categories = e.Categories
+		rls.Category = e.Categories[0]
+	}
+
+	rls.ParseString(e.Title)
+
+	return rls
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ReleaseWebhookResponse is the number of releases the release webhook queued for processing
+type ReleaseWebhookResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+type ReleaseProcessExternalReq struct {
+	// IndexerIdentifier overrides the indexer of the releases
+	IndexerIdentifier string
+	Releases          []ExternalRelease
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExternalReleases(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []ExternalRelease
+		wantErr string
+	}{
+		{
+			name: "prowlarr_search_results",
+			payload: `[{
+				"guid": "https://example.com/torrents.php?id=1",
+				"title": "That Movie 2020 1080p BluRay x264-GROUP",
+				"size": 8589934592,
+				"indexer": "PassThePopcorn",
+				"indexerId": 3,
+				"downloadUrl": "https://prowlarr.local/3/download?link=abc",
+				"infoUrl": "https://example.com/torrents.php?id=1",
+				"infoHash": "ABCDEF",
+				"seeders": 20,
+				"leechers": 2,
+				"protocol": "torrent",
+				"publishDate": "2024-01-02T03:04:05Z",
+				"categories": [{"id": 2040, "name": "Movies/HD", "subCategories": []}]
+			}]`,
+			want: []ExternalRelease{
+				{
+					Title:       "That Movie 2020 1080p BluRay x264-GROUP",
+					DownloadURL: "https://prowlarr.local/3/download?link=abc",
+					InfoURL:     "https://example.com/torrents.php?id=1",
+					InfoHash:    "abcdef",
+					Size:        8589934592,
+					Indexer:     "PassThePopcorn",
+					Categories:  []string{"Movies/HD"},
+					Protocol:    ReleaseProtocolTorrent,
+					Seeders:     20,
+					Leechers:    2,
+					PublishDate: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "jackett_results",
+			payload: `{"Results": [{
+				"Tracker": "IPTorrents",
+				"TrackerId": "iptorrents",
+				"CategoryDesc": "TV/HD",
+				"Title": "That Show S01E01 720p WEB h264-GROUP",
+				"Guid": "https://iptorrents.com/t/1",
+				"Link": "https://jackett.local/dl/iptorrents/?jackett_apikey=x&path=y",
+				"Details": "https://iptorrents.com/t/1",
+				"Category": [5040, 100022],
+				"Size": 1073741824,
+				"Seeders": 5,
+				"Peers": 8,
+				"MagnetUri": null,
+				"InfoHash": null
+			}]}`,
+			want: []ExternalRelease{
+				{
+					Title:       "That Show S01E01 720p WEB h264-GROUP",
+					DownloadURL: "https://jackett.local/dl/iptorrents/?jackett_apikey=x&path=y",
+					InfoURL:     "https://iptorrents.com/t/1",
+					Size:        1073741824,
+					Indexer:     "IPTorrents",
+					Categories:  []string{"TV/HD"},
+					Protocol:    ReleaseProtocolTorrent,
+					Seeders:     5,
+					Leechers:    3,
+				},
+			},
+		},
+		{
+			name: "magnet_download_url",
+			payload: `{
+				"title": "That Album 2020 FLAC",
+				"downloadUrl": "magnet:?xt=urn:btih:abc",
+				"indexer": "mock",
+				"categories": ["Audio"]
+			}`,
+			want: []ExternalRelease{
+				{
+					Title:      "That Album 2020 FLAC",
+					MagnetURL:  "magnet:?xt=urn:btih:abc",
+					Indexer:    "mock",
+					Categories: []string{"Audio"},
+					Protocol:   ReleaseProtocolTorrent,
+				},
+			},
+		},
+		{
+			name:    "prowlarr_test_event",
+			payload: `{"eventType": "Test", "instanceName": "Prowlarr"}`,
+			want:    []ExternalRelease{},
+		},
+		{
+			name:    "missing_download_url",
+			payload: `{"title": "That Movie 2020 1080p BluRay x264-GROUP"}`,
+			wantErr: `invalid release at index 0: missing download url for "That Movie 2020 1080p BluRay x264-GROUP"`,
+		},
+		{
+			name:    "not_json",
+			payload: `announce`,
+			wantErr: "payload is not a json object or array",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExternalReleases([]byte(tt.payload))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseWebhookReleases(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []ExternalRelease
+		wantErr string
+	}{
+		{
+			name: "single_release",
+			payload: `{
+				"title": "That Movie 2020 1080p BluRay x264-GROUP",
+				"indexer": "mock",
+				"download_url": "https://example.com/download/1",
+				"size": 8589934592
+			}`,
+			want: []ExternalRelease{
+				{
+					Title:       "That Movie 2020 1080p BluRay x264-GROUP",
+					Indexer:     "mock",
+					DownloadURL: "https://example.com/download/1",
+					Size:        8589934592,
+					Protocol:    ReleaseProtocolTorrent,
+				},
+			},
+		},
+		{
+			name: "list_with_magnet_and_usenet",
+			payload: `[
+				{"title": "That Show S01E01 720p WEB h264-GROUP", "download_url": "magnet:?xt=urn:btih:ABCDEF", "info_hash": "ABCDEF"},
+				{"title": "That Show S01E02 720p WEB h264-GROUP", "download_url": "https://example.com/nzb/2", "protocol": "usenet", "categories": ["TV/HD"]}
+			]`,
+			want: []ExternalRelease{
+				{
+					Title:     "That Show S01E01 720p WEB h264-GROUP",
+					MagnetURL: "magnet:?xt=urn:btih:ABCDEF",
+					InfoHash:  "abcdef",
+					Protocol:  ReleaseProtocolTorrent,
+				},
+				{
+					Title:       "That Show S01E02 720p WEB h264-GROUP",
+					DownloadURL: "https://example.com/nzb/2",
+					Categories:  []string{"TV/HD"},
+					Protocol:    ReleaseProtocolNzb,
+				},
+			},
+		},
+		{
+			name:    "missing_title",
+			payload: `{"download_url": "https://example.com/download/1"}`,
+			wantErr: "invalid release at index 0: missing title",
+		},
+		{
+			name:    "invalid_protocol",
+			payload: `[{"title": "That Movie 2020 1080p BluRay x264-GROUP", "download_url": "https://example.com/download/1", "protocol": "ddl"}]`,
+			wantErr: `invalid release at index 0: invalid protocol "ddl" for "That Movie 2020 1080p BluRay x264-GROUP"`,
+		},
+		{
+			name:    "empty_list",
+			payload: `[]`,
+			wantErr: "no releases in payload",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWebhookReleases([]byte(tt.payload))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExternalRelease_Release(t *testing.T) {
+	ext := &ExternalRelease{
+		Title:       "That Movie 2020 1080p BluRay x264-GROUP",
+		DownloadURL: "https://prowlarr.local/3/download?link=abc",
+		Size:        8589934592,
+		Categories:  []string{"Movies/HD"},
+		Protocol:    ReleaseProtocolTorrent,
+	}
+
+	rls := ext.Release(IndexerMinimal{ID: 1, Name: "Mock", Identifier: "mock"})
+
+	assert.Equal(t, ReleaseImplementationWebhook, rls.Implementation)
+	assert.Equal(t, "mock", rls.Indexer.Identifier)
+	assert.Equal(t, ext.DownloadURL, rls.DownloadURL)
+	assert.Equal(t, uint64(8589934592), rls.Size)
+	assert.Equal(t, "Movies/HD", rls.Category)
+	assert.Equal(t, 2020, rls.Year)
+	assert.Equal(t, "1080p", rls.Resolution)
+	assert.Equal(t, "GROUP", rls.Group)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ReleasePendingRepo interface {
+	List(ctx context.Context, pendingType ReleasePendingType) ([]*ReleasePending, error)
+	Get(ctx context.Context, id int64) (*ReleasePending, error)
+	Counts(ctx context.Context) (*ReleasePendingCounts, error)
+	Store(ctx context.Context, pending *ReleasePending) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type ReleasePendingType string
+
+const (
+	// ReleasePendingTypeDelay waits for the delay of the filter before the actions run
+	ReleasePendingTypeDelay ReleasePendingType = "DELAY"
+
+	// ReleasePendingTypeApproval waits for a user to approve the action
+	ReleasePendingTypeApproval ReleasePendingType = "APPROVAL"
+)
+
+func (t ReleasePendingType) Valid() bool {
+	switch t {
+	case ReleasePendingTypeDelay, ReleasePendingTypeApproval:
+		return true
+	}
+	return false
+}
+
+// ReleasePending is a matched release waiting for the delay of its filter or the approval of an action
+type ReleasePending struct {
+	ID          int64              `json:"id"`
+	Type        ReleasePendingType `json:"type"`
+	ReleaseID   int64              `json:"release_id"`
+	FilterID    int                `json:"filter_id"`
+	FilterName  string             `json:"filter"`
+	ActionID    int                `json:"action_id,omitempty"`
+	ActionName  string             `json:"action,omitempty"`
+	TorrentName string             `json:"name"`
+	Indexer     string             `json:"indexer"`
+	RunAt       time.Time          `json:"run_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// ReleasePendingCounts is the number of pending releases by type
+type ReleasePendingCounts struct {
+	Delay    int `json:"delay"`
+	Approval int `json:"approval"`
+}
+
+func NewReleasePending(release *Release, runAt time.Time) *ReleasePending {
+	return &ReleasePending{
+		Type:        ReleasePendingTypeDelay,
+		ReleaseID:   release.ID,
+		FilterID:    release.FilterID,
+		FilterName:  release.FilterName,
+		TorrentName: release.TorrentName,
+		Indexer:     release.Indexer.Identifier,
+		RunAt:       runAt,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// NewReleasePendingApproval parks the action for the release until a user approves or rejects it
+func NewReleasePendingApproval(release *Release, action *Action) *ReleasePending {
+	p := NewReleasePending(release, time.Now())
+	p.Type = ReleasePendingTypeApproval
+	p.ActionID = action.ID
+	p.ActionName = action.Name
+
+	return p
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type ReleaseRetentionRepo interface {
+	Get(ctx context.Context) (*ReleaseRetention, error)
+	Update(ctx context.Context, retention *ReleaseRetention) error
+	StoreRun(ctx context.Context, runAt time.Time, deleted int64) error
+	Prune(ctx context.Context, maxAge time.Duration, maxRowsPerIndexer int) (*ReleasePruneResult, error)
+}
+
+const DefaultReleaseRetentionSchedule = "0 3 * * *"
+
+// ReleaseRetention is the config for the release history pruning job
+type ReleaseRetention struct {
+	Enabled           bool       `json:"enabled"`
+	MaxAgeDays        int        `json:"max_age_days"`
+	MaxRowsPerIndexer int        `json:"max_rows_per_indexer"`
+	Schedule          string     `json:"schedule"`
+	LastRun           *time.Time `json:"last_run"`
+	LastDeleted       int64      `json:"last_deleted"`
+	NextRun           *time.Time `json:"next_run,omitempty"`
+}
+
+func NewReleaseRetention() *ReleaseRetention {
+	return &ReleaseRetention{
+		Schedule: DefaultReleaseRetentionSchedule,
+	}
+}
+
+func (r *ReleaseRetention) Validate() error {
+	var errs ValidationErrors
+
+	if r.MaxAgeDays < 0 {
+		errs.Add("max_age_days", "must be 0 or more")
+	}
+
+	if r.MaxRowsPerIndexer < 0 {
+		errs.Add("max_rows_per_indexer", "must be 0 or more")
+	}
+
+	if r.Enabled && r.MaxAgeDays == 0 && r.MaxRowsPerIndexer == 0 {
+		errs.Add("max_age_days", "set max age or max rows per indexer")
+	}
+
+	if r.Schedule == "" {
+		errs.Add("schedule", "required")
+	} else if _, err := cron.ParseStandard(r.Schedule); err != nil {
+		errs.Add("schedule", "invalid cron schedule: %v", err)
+	}
+
+	return errs.Err()
+}
+
+// MaxAge returns the max age, 0 means no limit
+func (r *ReleaseRetention) MaxAge() time.Duration {
+	return time.Duration(r.MaxAgeDays) * 24 * time.Hour
+}
+
+type ReleasePruneResult struct {
+	ReleasesByAge       int64 `json:"releases_by_age"`
+	ReleasesByRowLimit  int64 `json:"releases_by_row_limit"`
+	ActionStatusDeleted int64 `json:"action_status_deleted"`
+}
+
+func (r *ReleasePruneResult) Total() int64 {
+	return r.ReleasesByAge + r.ReleasesByRowLimit
+}
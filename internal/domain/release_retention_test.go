@@ -0,0 +1,48 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseRetention_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention ReleaseRetention
+		wantErr   string
+	}{
+		{
+			name:      "disabled_without_limits",
+			retention: ReleaseRetention{Schedule: DefaultReleaseRetentionSchedule},
+		},
+		{
+			name:      "enabled_with_max_age",
+			retention: ReleaseRetention{Enabled: true, MaxAgeDays: 90, Schedule: "@daily"},
+		},
+		{
+			name:      "enabled_without_limits",
+			retention: ReleaseRetention{Enabled: true, Schedule: DefaultReleaseRetentionSchedule},
+			wantErr:   "validation error: max_age_days: set max age or max rows per indexer",
+		},
+		{
+			name:      "negative_and_bad_schedule",
+			retention: ReleaseRetention{MaxRowsPerIndexer: -1, Schedule: "every day"},
+			wantErr:   "validation error: max_rows_per_indexer: must be 0 or more, schedule: invalid cron schedule: expected exactly 5 fields, found 2: [every day]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.retention.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+// ReleaseSimulateReq is a raw announce to parse and check against the filters of the indexer
+// without storing the release or running any actions.
+type ReleaseSimulateReq struct {
+	IndexerIdentifier string   `json:"indexer_identifier"`
+	AnnounceLines     []string `json:"announce_lines"`
+}
+
+func (r *ReleaseSimulateReq) Validate() error {
+	var validationErrs ValidationErrors
+	if r.IndexerIdentifier == "" {
+		validationErrs.Add("indexer_identifier", "field indexer_identifier empty")
+	}
+
+	if len(r.AnnounceLines) == 0 {
+		validationErrs.Add("announce_lines", "field announce_lines empty")
+	}
+
+	return validationErrs.Err()
+}
+
+type ReleaseSimulateFilterResult struct {
+	FilterID   int      `json:"filter_id"`
+	FilterName string   `json:"filter_name"`
+	Priority   int32    `json:"priority"`
+	Match      bool     `json:"match"`
+	Rejections []string `json:"rejections"`
+	// Skipped are pipeline stages with side effects that were not run, eg. external filters
+	Skipped []PipelineStage `json:"skipped,omitempty"`
+}
+
+type ReleaseSimulateResponse struct {
+	Release *Release `json:"release"`
+	// Filters are in the order they are checked when the release is announced
+	Filters []ReleaseSimulateFilterResult `json:"filters"`
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+// SetTorrentSize sets the size from the torrent and keeps the first announced size for verification
+func (r *Release) SetTorrentSize(size uint64) {
+	if r.AnnouncedSize == 0 {
+		r.AnnouncedSize = r.Size
+	}
+
+	r.Size = size
+}
+
+// ClaimedSize returns the size claimed by the announce, or the current size when it was never replaced
+func (r *Release) ClaimedSize() uint64 {
+	if r.AnnouncedSize > 0 {
+		return r.AnnouncedSize
+	}
+
+	return r.Size
+}
+
+// SizeMismatch returns the difference between the claimed and actual size in percent of the claimed size
+// and whether it is above threshold percent. Unknown sizes and a threshold of 0 never mismatch.
+func SizeMismatch(claimed, actual uint64, threshold int) (float64, bool) {
+	if claimed == 0 || actual == 0 {
+		return 0, false
+	}
+
+	diff := float64(actual) - float64(claimed)
+	if diff < 0 {
+		diff = -diff
+	}
+
+	percent := diff / float64(claimed) * 100
+
+	return percent, threshold > 0 && percent > float64(threshold)
+}
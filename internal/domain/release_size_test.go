@@ -0,0 +1,49 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeMismatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		claimed      uint64
+		actual       uint64
+		threshold    int
+		wantPercent  float64
+		wantMismatch bool
+	}{
+		{name: "equal", claimed: 1000, actual: 1000, threshold: 10, wantPercent: 0, wantMismatch: false},
+		{name: "within_threshold", claimed: 1000, actual: 1100, threshold: 10, wantPercent: 10, wantMismatch: false},
+		{name: "larger", claimed: 1000, actual: 2500, threshold: 10, wantPercent: 150, wantMismatch: true},
+		{name: "smaller", claimed: 1000, actual: 500, threshold: 10, wantPercent: 50, wantMismatch: true},
+		{name: "unknown_claimed", claimed: 0, actual: 500, threshold: 10, wantPercent: 0, wantMismatch: false},
+		{name: "unknown_actual", claimed: 1000, actual: 0, threshold: 10, wantPercent: 0, wantMismatch: false},
+		{name: "disabled", claimed: 1000, actual: 5000, threshold: 0, wantPercent: 400, wantMismatch: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, mismatch := SizeMismatch(tt.claimed, tt.actual, tt.threshold)
+			assert.InDelta(t, tt.wantPercent, percent, 0.001)
+			assert.Equal(t, tt.wantMismatch, mismatch)
+		})
+	}
+}
+
+func TestRelease_SetTorrentSize(t *testing.T) {
+	r := &Release{Size: 1000}
+	assert.Equal(t, uint64(1000), r.ClaimedSize())
+
+	r.SetTorrentSize(2000)
+	assert.Equal(t, uint64(2000), r.Size)
+	assert.Equal(t, uint64(1000), r.ClaimedSize())
+
+	// downloading again keeps the announced size
+	r.SetTorrentSize(2000)
+	assert.Equal(t, uint64(1000), r.AnnouncedSize)
+}
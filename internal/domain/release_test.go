@@ -4,9 +4,16 @@
 package domain
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRelease_Parse(t *testing.T) {
@@ -784,3 +791,109 @@ func Test_getUniqueTags(t *testing.T) {
 		})
 	}
 }
+
+func TestNewLatencyPercentiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []int64
+		want      LatencyPercentiles
+	}{
+		{
+			name:      "empty",
+			latencies: nil,
+			want:      LatencyPercentiles{},
+		},
+		{
+			name:      "single",
+			latencies: []int64{250},
+			want:      LatencyPercentiles{Count: 1, Min: 250, P50: 250, P90: 250, P95: 250, P99: 250, Max: 250},
+		},
+		{
+			name:      "unsorted",
+			latencies: []int64{900, 100, 500, 300, 700, 200, 800, 400, 1000, 600},
+			want:      LatencyPercentiles{Count: 10, Min: 100, P50: 500, P90: 900, P95: 1000, P99: 1000, Max: 1000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewLatencyPercentiles(tt.latencies))
+		})
+	}
+}
+
+func TestRelease_TorrentFiles(t *testing.T) {
+	info := metainfo.Info{
+		Name:        "Show.Name.S01E01.1080p.WEB-DL.x264-GROUP",
+		PieceLength: 16384,
+		Pieces:      make([]byte, 20),
+		Files: []metainfo.FileInfo{
+			{Path: []string{"Show.Name.S01E01.1080p.WEB-DL.x264-GROUP.mkv"}, Length: 1000},
+			{Path: []string{"Sample", "sample.mkv"}, Length: 100},
+		},
+	}
+
+	infoBytes, err := bencode.Marshal(info)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "release.torrent")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, (&metainfo.MetaInfo{InfoBytes: infoBytes}).Write(f))
+	require.NoError(t, f.Close())
+
+	r := &Release{TorrentTmpFile: path}
+
+	files, err := r.TorrentFiles()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Show.Name.S01E01.1080p.WEB-DL.x264-GROUP.mkv", "Sample/sample.mkv"}, files)
+}
+
+func TestReleaseRemovablePush_RemovalReason(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		push  ReleaseRemovablePush
+		state TorrentState
+		want  string
+	}{
+		{name: "ratio_reached", push: ReleaseRemovablePush{RemoveRatio: 2}, state: TorrentState{Ratio: 2.5}, want: "ratio 2.50 reached 2.00"},
+		{name: "ratio_not_reached", push: ReleaseRemovablePush{RemoveRatio: 2}, state: TorrentState{Ratio: 1.5}, want: ""},
+		{name: "seed_time_reached", push: ReleaseRemovablePush{RemoveSeedTime: 60}, state: TorrentState{SeedingTime: 90 * time.Minute}, want: "seeded for 1h30m0s"},
+		{name: "seed_time_not_reached", push: ReleaseRemovablePush{RemoveSeedTime: 60}, state: TorrentState{SeedingTime: 30 * time.Minute}, want: ""},
+		{name: "days_reached", push: ReleaseRemovablePush{RemoveAfterDays: 7, Timestamp: now.AddDate(0, 0, -8)}, want: "pushed more than 7 days ago"},
+		{name: "days_not_reached", push: ReleaseRemovablePush{RemoveAfterDays: 7, Timestamp: now.AddDate(0, 0, -6)}, want: ""},
+		{name: "no_policy", push: ReleaseRemovablePush{Timestamp: now.AddDate(-1, 0, 0)}, state: TorrentState{Ratio: 10, SeedingTime: 1000 * time.Hour}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.push.RemovalReason(tt.state, now))
+		})
+	}
+}
+
+func TestReleaseAnnotationReq_Validate(t *testing.T) {
+	notes := "  grabbed for the remux collection  "
+	tags := []string{" follow-up ", "remux", "", "follow-up"}
+
+	req := ReleaseAnnotationReq{Notes: &notes, UserTags: &tags}
+	assert.NoError(t, req.Validate())
+	assert.Equal(t, "grabbed for the remux collection", *req.Notes)
+	assert.Equal(t, []string{"follow-up", "remux"}, *req.UserTags)
+
+	assert.ErrorContains(t, (&ReleaseAnnotationReq{}).Validate(), "notes or user_tags is required")
+
+	tags = []string{"a,b"}
+	assert.ErrorContains(t, (&ReleaseAnnotationReq{UserTags: &tags}).Validate(), `tag "a,b" must not contain a comma`)
+
+	tags = make([]string, ReleaseUserTagsMax+1)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag%d", i)
+	}
+	assert.ErrorContains(t, (&ReleaseAnnotationReq{UserTags: &tags}).Validate(), "at most 20 tags")
+
+	// clearing is allowed
+	notes = ""
+	tags = []string{}
+	assert.NoError(t, (&ReleaseAnnotationReq{Notes: &notes, UserTags: &tags}).Validate())
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+// ReleaseUpgradeParams identifies the pushed releases a PROPER or REPACK replaces
+type ReleaseUpgradeParams struct {
+	Title      string
+	Season     int
+	Episode    int
+	Year       int
+	Month      int
+	Day        int
+	Resolution string
+	Source     string
+
+	// Group is only set for repacks, a proper can come from another group
+	Group string
+}
+
+// NewReleaseUpgradeParams returns nil when the release is not a PROPER or REPACK
+func NewReleaseUpgradeParams(r *Release) *ReleaseUpgradeParams {
+	if !r.Proper && !r.Repack {
+		return nil
+	}
+
+	p := &ReleaseUpgradeParams{
+		Title:      r.Title,
+		Season:     r.Season,
+		Episode:    r.Episode,
+		Year:       r.Year,
+		Month:      r.Month,
+		Day:        r.Day,
+		Resolution: r.Resolution,
+		Source:     r.Source,
+	}
+
+	if r.Repack && !r.Proper {
+		p.Group = r.Group
+	}
+
+	return p
+}
+
+// ReleaseUpgradeOriginal is a torrent pushed to a client that is replaced by an upgrade
+type ReleaseUpgradeOriginal struct {
+	ReleaseID   int64
+	TorrentName string
+	ClientID    int32
+	InfoHash    string
+}
+
+// ReplacedInfoHashes returns the infohashes of the originals pushed to the client
+func (r *Release) ReplacedInfoHashes(clientID int32) []string {
+	var hashes []string
+	for _, original := range r.Replaces {
+		if original.ClientID == clientID {
+			hashes = append(hashes, original.InfoHash)
+		}
+	}
+	return hashes
+}
@@ -26,8 +26,8 @@ func init() {
 		{tag: "AAC", title: "Advanced Audio Coding (LC)", regexp: "", re: nil},
 		{tag: "AC3D", title: "", regexp: "ac[\\-\\._ ]?3d", re: nil},
 		{tag: "Atmos", title: "Dolby Atmos", regexp: "", re: nil},
-		{tag: "APS (VBR)", title: "APS Variable Bit Rate", regexp: "", re: nil},
-		{tag: "APX (VBR)", title: "APX Variable Bit Rate", regexp: "", re: nil},
+		{tag: "APS (VBR)", title: "APS Variable Bit Rate", regexp: "\\baps\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
+		{tag: "APX (VBR)", title: "APX Variable Bit Rate", regexp: "\\bapx\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
 		{tag: "CBR", title: "Constant Bit Rate", regexp: "", re: nil},
 		{tag: "Cue", title: "Cue File", regexp: "", re: nil},
 		{tag: "DDPA", title: "Dolby Digital+ Atmos (E-AC-3+Atmos)", regexp: "dd[p\\+]a", re: nil},
@@ -55,9 +55,9 @@ func init() {
 		{tag: "OPUS", title: "", regexp: "", re: nil},
 		{tag: "TrueHD", title: "Dolby TrueHD", regexp: "(?:dolby[\\-\\._ ]?)?true[\\-\\._ ]?hd", re: nil},
 		{tag: "VBR", title: "Variable Bit Rate", regexp: "", re: nil},
-		{tag: "V0 (VBR)", title: "V0 Variable Bit Rate", regexp: "", re: nil},
-		{tag: "V1 (VBR)", title: "V1 Variable Bit Rate", regexp: "", re: nil},
-		{tag: "V2 (VBR)", title: "V2 Variable Bit Rate", regexp: "", re: nil},
+		{tag: "V0 (VBR)", title: "V0 Variable Bit Rate", regexp: "\\bv0\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
+		{tag: "V1 (VBR)", title: "V1 Variable Bit Rate", regexp: "\\bv1\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
+		{tag: "V2 (VBR)", title: "V2 Variable Bit Rate", regexp: "\\bv2\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
 	}
 	types["audio"] = audio
 
@@ -69,14 +69,14 @@ func init() {
 		{tag: "256", title: "256 Kbps", regexp: "256[\\\\-\\\\._ kbps]?", re: nil},
 		{tag: "192", title: "192 Kbps", regexp: "192[\\\\-\\\\._ kbps]?", re: nil},
 		{tag: "128", title: "128 Kbps", regexp: "128[\\\\-\\\\._ kbps]?", re: nil},
-		{tag: "APS (VBR)", title: "APS Variable Bit Rate", regexp: "", re: nil},
-		{tag: "APX (VBR)", title: "APX Variable Bit Rate", regexp: "", re: nil},
+		{tag: "APS (VBR)", title: "APS Variable Bit Rate", regexp: "\\baps\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
+		{tag: "APX (VBR)", title: "APX Variable Bit Rate", regexp: "\\bapx\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
 		{tag: "CBR", title: "Constant Bit Rate", regexp: "", re: nil},
 		{tag: "Lossless", title: "", regexp: "(?i:(?:^|[^t] )Lossless)", re: nil},
 		{tag: "VBR", title: "Variable Bit Rate", regexp: "", re: nil},
-		{tag: "V0 (VBR)", title: "V0 Variable Bit Rate", regexp: "", re: nil},
-		{tag: "V1 (VBR)", title: "V1 Variable Bit Rate", regexp: "", re: nil},
-		{tag: "V2 (VBR)", title: "V2 Variable Bit Rate", regexp: "", re: nil},
+		{tag: "V0 (VBR)", title: "V0 Variable Bit Rate", regexp: "\\bv0\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
+		{tag: "V1 (VBR)", title: "V1 Variable Bit Rate", regexp: "\\bv1\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
+		{tag: "V2 (VBR)", title: "V2 Variable Bit Rate", regexp: "\\bv2\\b(?:[\\-\\._ ]?\\(vbr\\))?", re: nil},
 	}
 	types["audioBitrate"] = audioBitrate
 
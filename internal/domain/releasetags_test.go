@@ -43,6 +43,9 @@ func TestParseReleaseTagString(t *testing.T) {
 		{name: "music_4", args: args{tags: "FLAC 24bit Lossless Log 100% Cue CD"}, want: ReleaseTags{Audio: []string{"24BIT Lossless", "Cue", "FLAC", "Log100", "Log"}, AudioBitrate: "24BIT Lossless", AudioFormat: "FLAC", Source: "CD", HasLog: true, LogScore: 100, HasCue: true}},
 		{name: "music_5", args: args{tags: "MP3 320 WEB"}, want: ReleaseTags{Audio: []string{"320", "MP3"}, AudioBitrate: "320", AudioFormat: "MP3", Source: "WEB"}},
 		{name: "music_6", args: args{tags: "FLAC Lossless Log (100%) Cue CD"}, want: ReleaseTags{Audio: []string{"Cue", "FLAC", "Lossless", "Log100", "Log"}, AudioBitrate: "Lossless", AudioFormat: "FLAC", Source: "CD", HasCue: true, HasLog: true, LogScore: 100}},
+		{name: "music_7", args: args{tags: "MP3 / V0 (VBR) / WEB"}, want: ReleaseTags{Audio: []string{"MP3", "VBR", "V0 (VBR)"}, AudioBitrate: "V0 (VBR)", AudioFormat: "MP3", Source: "WEB"}},
+		{name: "music_8", args: args{tags: "MP3 / V0 / Vinyl"}, want: ReleaseTags{Audio: []string{"MP3", "V0 (VBR)"}, AudioBitrate: "V0 (VBR)", AudioFormat: "MP3", Source: "Vinyl"}},
+		{name: "music_9", args: args{tags: "MP3 / APS / CD"}, want: ReleaseTags{Audio: []string{"APS (VBR)", "MP3"}, AudioBitrate: "APS (VBR)", AudioFormat: "MP3", Source: "CD"}},
 		{name: "movies_1", args: args{tags: "x264 Blu-ray MKV 1080p"}, want: ReleaseTags{Codec: "x264", Source: "BluRay", Resolution: "1080p", Container: "mkv"}},
 		{name: "movies_2", args: args{tags: "HEVC HDR Blu-ray mp4 2160p"}, want: ReleaseTags{Codec: "HEVC", Source: "BluRay", Resolution: "2160p", Container: "mp4", HDR: []string{"HDR"}}},
 		{name: "movies_3", args: args{tags: "HEVC HDR DV Blu-ray mp4 2160p"}, want: ReleaseTags{Codec: "HEVC", Source: "BluRay", Resolution: "2160p", Container: "mp4", HDR: []string{"HDR", "DV"}}},
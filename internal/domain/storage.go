@@ -0,0 +1,103 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type StorageType string
+
+const (
+	StorageTypeLocal StorageType = "local"
+	StorageTypeS3    StorageType = "s3"
+)
+
+// StorageArtifact is the kind of generated file, each kind is kept under its own prefix with its own retention
+type StorageArtifact string
+
+const (
+	StorageArtifactBackup      StorageArtifact = "backups"
+	StorageArtifactExport      StorageArtifact = "exports"
+	StorageArtifactAnnounceLog StorageArtifact = "announces"
+)
+
+// StorageRetention is how long the files of an artifact are kept
+type StorageRetention struct {
+	// Keep is the number of files kept, 0 keeps all
+	Keep int `toml:"keep"`
+	// MaxAgeDays removes files older than this, 0 disables
+	MaxAgeDays int `toml:"maxAgeDays"`
+}
+
+// StorageConfig is the target for generated files from the config file
+type StorageConfig struct {
+	Type StorageType `toml:"type"`
+
+	// Path is the directory for local storage, relative to the config dir
+	Path string `toml:"path"`
+
+	// S3 compatible storage
+	Endpoint  string `toml:"endpoint"`
+	Region    string `toml:"region"`
+	Bucket    string `toml:"bucket"`
+	Prefix    string `toml:"prefix"`
+	AccessKey string `toml:"accessKey"`
+	SecretKey string `toml:"secretKey"`
+	PathStyle bool   `toml:"pathStyle"`
+
+	// Keep is the number of files kept per artifact, 0 keeps all
+	Keep int `toml:"keep"`
+	// MaxAgeDays removes files older than this, 0 disables
+	MaxAgeDays int `toml:"maxAgeDays"`
+
+	// Retention replaces Keep and MaxAgeDays for an artifact, keyed by backups, exports or announces
+	Retention map[string]StorageRetention `toml:"retention"`
+
+	// BackupSchedule is the cron schedule for database backups, empty disables scheduled backups
+	BackupSchedule string `toml:"backupSchedule"`
+
+	// AnnounceLogSchedule is the cron schedule to write the raw announce lines, empty disables the announce log
+	AnnounceLogSchedule string `toml:"announceLogSchedule"`
+}
+
+// ArtifactRetention returns the retention for artifact, Keep and MaxAgeDays unless the artifact has its own
+func (c StorageConfig) ArtifactRetention(artifact StorageArtifact) StorageRetention {
+	if retention, ok := c.Retention[string(artifact)]; ok {
+		return retention
+	}
+
+	return StorageRetention{Keep: c.Keep, MaxAgeDays: c.MaxAgeDays}
+}
+
+type StorageObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Expired returns the objects outside the retention, the newest Keep objects younger than MaxAgeDays are kept
+func (c StorageRetention) Expired(objects []StorageObject, now time.Time) []StorageObject {
+	sorted := make([]StorageObject, len(objects))
+	copy(sorted, objects)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LastModified.After(sorted[j].LastModified)
+	})
+
+	var expired []StorageObject
+	for idx, object := range sorted {
+		if c.Keep > 0 && idx >= c.Keep {
+			expired = append(expired, object)
+			continue
+		}
+
+		if c.MaxAgeDays > 0 && now.Sub(object.LastModified) > time.Duration(c.MaxAgeDays)*24*time.Hour {
+			expired = append(expired, object)
+		}
+	}
+
+	return expired
+}
@@ -3,20 +3,75 @@
 
 package domain
 
-import "context"
+import (
+	"context"
+	"slices"
+	"time"
+)
 
 type UserRepo interface {
 	GetUserCount(ctx context.Context) (int, error)
+	GetAdminCount(ctx context.Context) (int, error)
+	List(ctx context.Context) ([]User, error)
+	FindByID(ctx context.Context, id int) (*User, error)
 	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindByOIDCSubject(ctx context.Context, issuer, subject string) (*User, error)
 	Store(ctx context.Context, req CreateUserRequest) error
 	Update(ctx context.Context, req UpdateUserRequest) error
+	UpdateRole(ctx context.Context, id int, role UserRole) error
 	Delete(ctx context.Context, username string) error
 }
 
 type User struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	Role      UserRole  `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// OIDCIssuer and OIDCSubject identify users created by an oidc login, their username is only shown
+	OIDCIssuer  string `json:"-"`
+	OIDCSubject string `json:"-"`
+}
+
+type UserRole string
+
+const (
+	// UserRoleAdmin can do everything, including managing users
+	UserRoleAdmin UserRole = "admin"
+
+	// UserRoleOperator can edit filters and releases, but not the settings
+	UserRoleOperator UserRole = "operator"
+
+	// UserRoleReadOnly can only look
+	UserRoleReadOnly UserRole = "read-only"
+)
+
+var UserRoles = []UserRole{
+	UserRoleAdmin,
+	UserRoleOperator,
+	UserRoleReadOnly,
+}
+
+func (r UserRole) Valid() bool {
+	return slices.Contains(UserRoles, r)
+}
+
+// Scopes are the api key scopes the role is allowed to use, unknown roles can only read
+func (r UserRole) Scopes() []string {
+	switch r {
+	case UserRoleAdmin:
+		return []string{string(APIKeyScopeAdmin)}
+	case UserRoleOperator:
+		return []string{string(APIKeyScopeFiltersWrite), string(APIKeyScopeReleasesWrite)}
+	}
+
+	return []string{string(APIKeyScopeRead)}
+}
+
+// HasScope reports whether users with the role are allowed to act within the scope
+func (r UserRole) HasScope(scope APIKeyScope) bool {
+	return APIKey{Scopes: r.Scopes()}.HasScope(scope)
 }
 
 type UpdateUserRequest struct {
@@ -28,6 +83,27 @@ type UpdateUserRequest struct {
 }
 
 type CreateUserRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Role     UserRole `json:"role,omitempty"`
+
+	OIDCIssuer  string `json:"-"`
+	OIDCSubject string `json:"-"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role UserRole `json:"role"`
+}
+
+type userContextKey struct{}
+
+// ContextWithUser returns the context of a request made by the user
+func ContextWithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user making the request, nil for api keys not owned by a user and internal requests
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userContextKey{}).(*User)
+	return user
 }
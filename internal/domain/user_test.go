@@ -0,0 +1,33 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRole_HasScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		role  UserRole
+		scope APIKeyScope
+		want  bool
+	}{
+		{name: "admin", role: UserRoleAdmin, scope: APIKeyScopeAdmin, want: true},
+		{name: "operator_filters", role: UserRoleOperator, scope: APIKeyScopeFiltersWrite, want: true},
+		{name: "operator_releases", role: UserRoleOperator, scope: APIKeyScopeReleasesWrite, want: true},
+		{name: "operator_read", role: UserRoleOperator, scope: APIKeyScopeRead, want: true},
+		{name: "operator_no_admin", role: UserRoleOperator, scope: APIKeyScopeAdmin, want: false},
+		{name: "read_only", role: UserRoleReadOnly, scope: APIKeyScopeRead, want: true},
+		{name: "read_only_no_write", role: UserRoleReadOnly, scope: APIKeyScopeFiltersWrite, want: false},
+		{name: "unknown_read_only", role: UserRole("other"), scope: APIKeyScopeReleasesWrite, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.role.HasScope(tt.scope))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// WebhookVerdict is the optional json body of a webhook verdict response, eg.
+//
+//	{"approve": false, "reason": "already have it"}
+//	{"reject": true, "reason": "bad group"}
+//	{"approve": true, "category": "tv-uhd", "macros": {"target": "/mnt/media"}}
+//
+// Category and macros work like the exec output.
+type WebhookVerdict struct {
+	ExecResult
+	Approve *bool `json:"approve"`
+}
+
+// ParseWebhookVerdict returns the verdict from a response body, or nil if the body is not a json object
+func ParseWebhookVerdict(body []byte) (*WebhookVerdict, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return nil, nil
+	}
+
+	var verdict WebhookVerdict
+	if err := json.Unmarshal(body, &verdict); err != nil {
+		return nil, err
+	}
+
+	return &verdict, nil
+}
+
+func (v *WebhookVerdict) Rejected() bool {
+	return v.Reject || (v.Approve != nil && !*v.Approve)
+}
+
+// RejectionReason returns the reason or a default message
+func (v *WebhookVerdict) RejectionReason() string {
+	if v.Reason != "" {
+		return v.Reason
+	}
+
+	return "rejected by webhook verdict"
+}
+
+// WebhookVerdictStatusOK checks the response status, any 2xx status is ok when no status is expected
+func WebhookVerdictStatusOK(expected, status int) error {
+	if expected > 0 {
+		if status != expected {
+			return fmt.Errorf("unexpected status code. got: %d want: %d", status, expected)
+		}
+
+		return nil
+	}
+
+	if status < 200 || status > 299 {
+		return fmt.Errorf("unexpected status code. got: %d want: 2xx", status)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWebhookVerdict(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantNil      bool
+		wantRejected bool
+		wantReason   string
+		wantErr      bool
+	}{
+		{name: "empty", body: "", wantNil: true},
+		{name: "plain_text", body: "ok", wantNil: true},
+		{name: "approve", body: `{"approve": true}`},
+		{name: "no_verdict", body: `{"status": "ok"}`},
+		{name: "approve_false", body: `{"approve": false, "reason": "already have it"}`, wantRejected: true, wantReason: "already have it"},
+		{name: "reject", body: ` {"reject": true}`, wantRejected: true, wantReason: "rejected by webhook verdict"},
+		{name: "invalid", body: `{"approve": }`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWebhookVerdict([]byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.Equal(t, tt.wantRejected, got.Rejected())
+			if tt.wantRejected {
+				assert.Equal(t, tt.wantReason, got.RejectionReason())
+			}
+		})
+	}
+}
+
+func TestWebhookVerdictStatusOK(t *testing.T) {
+	assert.NoError(t, WebhookVerdictStatusOK(0, 200))
+	assert.NoError(t, WebhookVerdictStatusOK(0, 204))
+	assert.Error(t, WebhookVerdictStatusOK(0, 500))
+	assert.NoError(t, WebhookVerdictStatusOK(202, 202))
+	assert.Error(t, WebhookVerdictStatusOK(202, 200))
+}
+
+func TestWebhookVerdict_Apply(t *testing.T) {
+	verdict, err := ParseWebhookVerdict([]byte(`{"approve": true, "category": "tv-uhd", "macros": {"target": "/mnt/media"}}`))
+	assert.NoError(t, err)
+
+	release := &Release{}
+	verdict.Apply(release)
+
+	assert.Equal(t, "tv-uhd", release.ActionCategory)
+	assert.Equal(t, map[string]string{"target": "/mnt/media"}, release.MacroVars)
+}
@@ -13,6 +13,7 @@ type ClientCacheStore interface {
 	Set(id int32, client *domain.DownloadClient)
 	Get(id int32) *domain.DownloadClient
 	Pop(id int32)
+	Clear()
 }
 
 type ClientCache struct {
@@ -49,3 +50,9 @@ func (c *ClientCache) Pop(id int32) {
 	delete(c.clients, id)
 	c.mu.Unlock()
 }
+
+func (c *ClientCache) Clear() {
+	c.mu.Lock()
+	c.clients = make(map[int32]*domain.DownloadClient)
+	c.mu.Unlock()
+}
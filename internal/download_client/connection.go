@@ -12,11 +12,18 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/aria2"
+	"github.com/autobrr/autobrr/pkg/downloadstation"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/jellyfin"
 	"github.com/autobrr/autobrr/pkg/lidarr"
+	"github.com/autobrr/autobrr/pkg/mylar"
+	"github.com/autobrr/autobrr/pkg/nzbget"
+	"github.com/autobrr/autobrr/pkg/plex"
 	"github.com/autobrr/autobrr/pkg/porla"
 	"github.com/autobrr/autobrr/pkg/radarr"
 	"github.com/autobrr/autobrr/pkg/readarr"
+	"github.com/autobrr/autobrr/pkg/remotefs"
 	"github.com/autobrr/autobrr/pkg/sabnzbd"
 	"github.com/autobrr/autobrr/pkg/sonarr"
 	"github.com/autobrr/autobrr/pkg/transmission"
@@ -47,6 +54,12 @@ func (s *service) testConnection(ctx context.Context, client domain.DownloadClie
 	case domain.DownloadClientTypePorla:
 		return s.testPorlaConnection(client)
 
+	case domain.DownloadClientTypeAria2:
+		return s.testAria2Connection(ctx, client)
+
+	case domain.DownloadClientTypeDownloadStation:
+		return s.testDownloadStationConnection(ctx, client)
+
 	case domain.DownloadClientTypeRadarr:
 		return s.testRadarrConnection(ctx, client)
 
@@ -62,9 +75,24 @@ func (s *service) testConnection(ctx context.Context, client domain.DownloadClie
 	case domain.DownloadClientTypeReadarr:
 		return s.testReadarrConnection(ctx, client)
 
+	case domain.DownloadClientTypeMylar:
+		return s.testMylarConnection(ctx, client)
+
+	case domain.DownloadClientTypePlex:
+		return s.testPlexConnection(ctx, client)
+
+	case domain.DownloadClientTypeJellyfin:
+		return s.testJellyfinConnection(ctx, client)
+
 	case domain.DownloadClientTypeSabnzbd:
 		return s.testSabnzbdConnection(ctx, client)
 
+	case domain.DownloadClientTypeNzbget:
+		return s.testNzbgetConnection(ctx, client)
+
+	case domain.DownloadClientTypeRemoteWatchFolder:
+		return s.testRemoteWatchFolderConnection(ctx, client)
+
 	default:
 		return errors.New("unsupported client: %s", client.Type)
 	}
@@ -328,6 +356,57 @@ func (s *service) testReadarrConnection(ctx context.Context, client domain.Downl
 	return nil
 }
 
+func (s *service) testMylarConnection(ctx context.Context, client domain.DownloadClient) error {
+	r := mylar.New(mylar.Config{
+		Hostname:  client.Host,
+		APIKey:    client.Settings.APIKey,
+		BasicAuth: client.Settings.Auth.Enabled,
+		Username:  client.Settings.Auth.Username,
+		Password:  client.Settings.Auth.Password,
+		Log:       s.subLogger,
+	})
+
+	if _, err := r.Test(ctx); err != nil {
+		return errors.Wrap(err, "mylar: connection test failed: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for mylar: success")
+
+	return nil
+}
+
+func (s *service) testPlexConnection(ctx context.Context, client domain.DownloadClient) error {
+	p := plex.New(plex.Config{
+		Hostname: client.Host,
+		Token:    client.Settings.APIKey,
+		Log:      s.subLogger,
+	})
+
+	if _, err := p.Test(ctx); err != nil {
+		return errors.Wrap(err, "plex: connection test failed: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for plex: success")
+
+	return nil
+}
+
+func (s *service) testJellyfinConnection(ctx context.Context, client domain.DownloadClient) error {
+	j := jellyfin.New(jellyfin.Config{
+		Hostname: client.Host,
+		APIKey:   client.Settings.APIKey,
+		Log:      s.subLogger,
+	})
+
+	if _, err := j.Test(ctx); err != nil {
+		return errors.Wrap(err, "jellyfin: connection test failed: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for jellyfin: success")
+
+	return nil
+}
+
 func (s *service) testPorlaConnection(client domain.DownloadClient) error {
 	p := porla.NewClient(porla.Config{
 		Hostname:      client.Host,
@@ -355,6 +434,47 @@ func (s *service) testPorlaConnection(client domain.DownloadClient) error {
 	return nil
 }
 
+func (s *service) testAria2Connection(ctx context.Context, client domain.DownloadClient) error {
+	a := aria2.NewClient(aria2.Config{
+		Hostname:      client.Host,
+		Secret:        client.Settings.APIKey,
+		TLSSkipVerify: client.TLSSkipVerify,
+		BasicUser:     client.Settings.Auth.Username,
+		BasicPass:     client.Settings.Auth.Password,
+		Log:           s.subLogger,
+	})
+
+	version, err := a.Version(ctx)
+	if err != nil {
+		return errors.Wrap(err, "aria2: failed to get version: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for aria2: found version %s", version.Version)
+
+	return nil
+}
+
+func (s *service) testDownloadStationConnection(ctx context.Context, client domain.DownloadClient) error {
+	ds := downloadstation.NewClient(downloadstation.Config{
+		Host:          client.Host,
+		Username:      client.Username,
+		Password:      client.Password,
+		TLSSkipVerify: client.TLSSkipVerify,
+		BasicUser:     client.Settings.Auth.Username,
+		BasicPass:     client.Settings.Auth.Password,
+		Log:           s.subLogger,
+	})
+
+	info, err := ds.Info(ctx)
+	if err != nil {
+		return errors.Wrap(err, "download station: failed to get info: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for download station: found version %s", info.VersionString)
+
+	return nil
+}
+
 func (s *service) testSabnzbdConnection(ctx context.Context, client domain.DownloadClient) error {
 	opts := sabnzbd.Options{
 		Addr:      client.Host,
@@ -374,3 +494,41 @@ func (s *service) testSabnzbdConnection(ctx context.Context, client domain.Downl
 
 	return nil
 }
+
+func (s *service) testNzbgetConnection(ctx context.Context, client domain.DownloadClient) error {
+	nzb := nzbget.NewClient(nzbget.Config{
+		Host:          client.Host,
+		Username:      client.Username,
+		Password:      client.Password,
+		TLSSkipVerify: client.TLSSkipVerify,
+		Log:           s.subLogger,
+	})
+
+	version, err := nzb.Version(ctx)
+	if err != nil {
+		return errors.Wrap(err, "nzbget: failed to get version: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for nzbget: found version %s", version)
+
+	return nil
+}
+
+func (s *service) testRemoteWatchFolderConnection(ctx context.Context, client domain.DownloadClient) error {
+	rfs := remotefs.NewClient(remotefs.Config{
+		URL:                client.Host,
+		Username:           client.Username,
+		Password:           client.Password,
+		TLSSkipVerify:      client.TLSSkipVerify,
+		HostKeyFingerprint: client.Settings.APIKey,
+		Log:                s.subLogger,
+	})
+
+	if err := rfs.Test(ctx); err != nil {
+		return errors.Wrap(err, "remote watch folder: failed to connect: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for remote watch folder: success")
+
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-deluge"
+)
+
+// delugeSession keeps the daemon connection of a cached Deluge client open between calls
+type delugeSession struct {
+	mu        sync.Mutex
+	client    deluge.DelugeClient
+	connected bool
+}
+
+type delugeSessions struct {
+	mu    sync.Mutex
+	items map[int32]*delugeSession
+}
+
+func newDelugeSessions() *delugeSessions {
+	return &delugeSessions{
+		items: make(map[int32]*delugeSession),
+	}
+}
+
+// get returns the session for the client, a changed client replaces the previous session
+func (d *delugeSessions) get(id int32, client deluge.DelugeClient) *delugeSession {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	session, ok := d.items[id]
+	if ok && session.client == client {
+		return session
+	}
+
+	if ok {
+		session.close()
+	}
+
+	session = &delugeSession{client: client}
+	d.items[id] = session
+
+	return session
+}
+
+func (d *delugeSessions) pop(id int32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if session, ok := d.items[id]; ok {
+		session.close()
+		delete(d.items, id)
+	}
+}
+
+func (d *delugeSessions) clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, session := range d.items {
+		session.close()
+		delete(d.items, id)
+	}
+}
+
+func (s *delugeSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.connected {
+		_ = s.client.Close()
+		s.connected = false
+	}
+}
+
+// withDeluge runs fn with the connected Deluge client from GetClient.
+// The connection is reused by later calls and dropped when fn fails, so the next call reconnects.
+func (s *service) withDeluge(ctx context.Context, client *domain.DownloadClient, fn func(del deluge.DelugeClient) error) error {
+	del, ok := client.Client.(deluge.DelugeClient)
+	if !ok {
+		return errors.New("could not get Deluge client: %s", client.Name)
+	}
+
+	session := s.delugeSessions.get(client.ID, del)
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if !session.connected {
+		if err := session.client.Connect(ctx); err != nil {
+			return errors.Wrap(err, "error logging into client: %v", client.Host)
+		}
+		session.connected = true
+	}
+
+	if err := fn(session.client); err != nil {
+		_ = session.client.Close()
+		session.connected = false
+		return err
+	}
+
+	return nil
+}
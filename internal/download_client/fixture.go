@@ -0,0 +1,415 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// fixtures are recorded client api exchanges, used as the contract for the client selftest and replayed in tests
+//
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fixture is a recorded set of api exchanges with a download client
+type Fixture struct {
+	ClientType domain.DownloadClientType `json:"client_type"`
+	RecordedAt time.Time                 `json:"recorded_at"`
+	Exchanges  []FixtureExchange         `json:"exchanges"`
+}
+
+// FixtureExchange is a single request and response.
+// Only the request method, path and rpc method are kept so credentials and api keys never end up in fixtures.
+type FixtureExchange struct {
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	RPCMethod string            `json:"rpc_method,omitempty"`
+	Status    int               `json:"status"`
+	Header    map[string]string `json:"header,omitempty"`
+	Body      string            `json:"body"`
+}
+
+func (e FixtureExchange) key() string {
+	if e.RPCMethod != "" {
+		return fmt.Sprintf("%s %s %s", e.Method, e.Path, e.RPCMethod)
+	}
+	return fmt.Sprintf("%s %s", e.Method, e.Path)
+}
+
+// fixtureHeaders are the response headers clients depend on
+var fixtureHeaders = []string{"Content-Type", "Set-Cookie", "X-Transmission-Session-Id"}
+
+// fixtureRedacted replaces session ids and cookie values so fixtures can be shared
+const fixtureRedacted = "fixture"
+
+// scrubFixtureHeader removes secrets from recorded header values, clients only need them to be present
+func scrubFixtureHeader(name, value string) string {
+	switch name {
+	case "Set-Cookie":
+		cookie, err := http.ParseSetCookie(value)
+		if err != nil {
+			return fixtureRedacted + "=" + fixtureRedacted
+		}
+
+		cookie.Value = fixtureRedacted
+		cookie.Expires = time.Time{}
+		cookie.RawExpires = ""
+		cookie.Raw = ""
+
+		return cookie.String()
+
+	case "X-Transmission-Session-Id":
+		return fixtureRedacted
+	}
+
+	return value
+}
+
+func LoadFixture(clientType domain.DownloadClientType, dir string) (*Fixture, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	name := fixtureFileName(clientType)
+
+	if dir != "" {
+		data, err = os.ReadFile(filepath.Join(dir, name))
+	} else {
+		data, err = fixtures.ReadFile("fixtures/" + name)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read fixture for client type: %s", clientType)
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, errors.Wrap(err, "could not parse fixture for client type: %s", clientType)
+	}
+
+	return &f, nil
+}
+
+func (f *Fixture) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrap(err, "could not create fixture dir: %s", dir)
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "could not marshal fixture")
+	}
+
+	path := filepath.Join(dir, fixtureFileName(f.ClientType))
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", errors.Wrap(err, "could not write fixture: %s", path)
+	}
+
+	return path, nil
+}
+
+func fixtureFileName(clientType domain.DownloadClientType) string {
+	return strings.ToLower(string(clientType)) + ".json"
+}
+
+// fixtureRecorder is a reverse proxy in front of a download client which records all exchanges
+type fixtureRecorder struct {
+	proxy *httputil.ReverseProxy
+
+	mu        sync.Mutex
+	exchanges []FixtureExchange
+}
+
+func newFixtureRecorder(target *url.URL, tlsSkipVerify bool) *fixtureRecorder {
+	r := &fixtureRecorder{}
+
+	r.proxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.Host = target.Host
+
+			// record plain bodies
+			pr.Out.Header.Del("Accept-Encoding")
+		},
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: tlsSkipVerify},
+		},
+		ModifyResponse: r.record,
+	}
+
+	return r
+}
+
+func (r *fixtureRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	// keep what is needed to match the exchange on the response
+	req = req.WithContext(withFixtureRequest(req.Context(), FixtureExchange{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		RPCMethod: rpcMethod(req, body),
+	}))
+
+	r.proxy.ServeHTTP(w, req)
+}
+
+func (r *fixtureRecorder) record(res *http.Response) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange, _ := fixtureRequestFromContext(res.Request.Context())
+	exchange.Status = res.StatusCode
+	exchange.Body = string(body)
+
+	for _, name := range fixtureHeaders {
+		if v := res.Header.Get(name); v != "" {
+			if exchange.Header == nil {
+				exchange.Header = make(map[string]string)
+			}
+			exchange.Header[name] = scrubFixtureHeader(name, v)
+		}
+	}
+
+	r.mu.Lock()
+	r.exchanges = append(r.exchanges, exchange)
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *fixtureRecorder) Exchanges() []FixtureExchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]FixtureExchange(nil), r.exchanges...)
+}
+
+// fixtureReplayer serves recorded responses, exchanges with the same key are replayed in recorded order
+type fixtureReplayer struct {
+	mu        sync.Mutex
+	exchanges map[string][]FixtureExchange
+	served    map[string]int
+}
+
+func newFixtureReplayer(f *Fixture) *fixtureReplayer {
+	r := &fixtureReplayer{
+		exchanges: make(map[string][]FixtureExchange),
+		served:    make(map[string]int),
+	}
+
+	for _, exchange := range f.Exchanges {
+		r.exchanges[exchange.key()] = append(r.exchanges[exchange.key()], exchange)
+	}
+
+	return r
+}
+
+func (r *fixtureReplayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	key := FixtureExchange{Method: req.Method, Path: req.URL.Path, RPCMethod: rpcMethod(req, body)}.key()
+
+	r.mu.Lock()
+	recorded := r.exchanges[key]
+	if len(recorded) == 0 {
+		r.mu.Unlock()
+		http.Error(w, fmt.Sprintf("no fixture for: %s", key), http.StatusNotImplemented)
+		return
+	}
+
+	// repeat the last exchange once all have been served
+	i := r.served[key]
+	if i >= len(recorded) {
+		i = len(recorded) - 1
+	}
+	r.served[key]++
+	r.mu.Unlock()
+
+	exchange := recorded[i]
+
+	for name, v := range exchange.Header {
+		w.Header().Set(name, v)
+	}
+
+	w.WriteHeader(exchange.Status)
+	w.Write([]byte(exchange.Body))
+}
+
+var xmlRPCMethod = regexp.MustCompile(`<methodName>\s*([^<\s]+)\s*</methodName>`)
+
+// rpcMethod returns the rpc method of a request for clients using a single endpoint,
+// eg. json-rpc for Transmission and Porla, xml-rpc for rTorrent and the mode param for SABnzbd
+func rpcMethod(req *http.Request, body []byte) string {
+	if mode := req.URL.Query().Get("mode"); mode != "" {
+		return mode
+	}
+
+	if len(body) == 0 {
+		return ""
+	}
+
+	if m := xmlRPCMethod.FindSubmatch(body); m != nil {
+		return string(m[1])
+	}
+
+	var rpc struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &rpc); err == nil {
+		return rpc.Method
+	}
+
+	return ""
+}
+
+// VerifyFixture compares live exchanges against the recorded contract.
+// Status codes must match and json responses must still contain all recorded fields, new fields are allowed.
+func VerifyFixture(contract *Fixture, live []FixtureExchange) []string {
+	issues := make([]string, 0)
+
+	liveByKey := make(map[string][]FixtureExchange)
+	for _, exchange := range live {
+		liveByKey[exchange.key()] = append(liveByKey[exchange.key()], exchange)
+	}
+
+	seen := make(map[string]int)
+
+	for _, expected := range contract.Exchanges {
+		key := expected.key()
+
+		i := seen[key]
+		seen[key]++
+
+		exchanges := liveByKey[key]
+		if i >= len(exchanges) {
+			// only report the first missing call per endpoint
+			if i == len(exchanges) {
+				issues = append(issues, fmt.Sprintf("%s: not called", key))
+			}
+			continue
+		}
+
+		actual := exchanges[i]
+
+		if actual.Status != expected.Status {
+			issues = append(issues, fmt.Sprintf("%s: status %d, expected %d", key, actual.Status, expected.Status))
+			continue
+		}
+
+		var expectedBody, actualBody any
+		if err := json.Unmarshal([]byte(expected.Body), &expectedBody); err != nil {
+			// not json, only the status can be compared
+			continue
+		}
+
+		if err := json.Unmarshal([]byte(actual.Body), &actualBody); err != nil {
+			issues = append(issues, fmt.Sprintf("%s: response is no longer json", key))
+			continue
+		}
+
+		for _, field := range missingFields(expectedBody, actualBody, "") {
+			issues = append(issues, fmt.Sprintf("%s: missing field %s", key, field))
+		}
+	}
+
+	return issues
+}
+
+// missingFields returns the paths of all fields in expected which are not in actual.
+// Arrays are compared by their first element since contents differ between clients.
+func missingFields(expected, actual any, path string) []string {
+	missing := make([]string, 0)
+
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			return append(missing, fieldPath(path, "")+" (not an object)")
+		}
+
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			v, ok := a[k]
+			if !ok {
+				missing = append(missing, fieldPath(path, k))
+				continue
+			}
+
+			missing = append(missing, missingFields(e[k], v, fieldPath(path, k))...)
+		}
+
+	case []any:
+		a, ok := actual.([]any)
+		if !ok {
+			return append(missing, fieldPath(path, "")+" (not an array)")
+		}
+
+		if len(e) > 0 && len(a) > 0 {
+			missing = append(missing, missingFields(e[0], a[0], path+"[]")...)
+		}
+	}
+
+	return missing
+}
+
+func fieldPath(path, field string) string {
+	switch {
+	case path == "" && field == "":
+		return "."
+	case path == "":
+		return field
+	case field == "":
+		return path
+	}
+	return path + "." + field
+}
+
+type fixtureRequestKey struct{}
+
+func withFixtureRequest(ctx context.Context, exchange FixtureExchange) context.Context {
+	return context.WithValue(ctx, fixtureRequestKey{}, exchange)
+}
+
+func fixtureRequestFromContext(ctx context.Context) (FixtureExchange, bool) {
+	exchange, ok := ctx.Value(fixtureRequestKey{}).(FixtureExchange)
+	return exchange, ok
+}
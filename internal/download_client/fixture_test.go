@@ -0,0 +1,174 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/dcarbone/zadapters/zstdlog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService() *service {
+	s := &service{log: zerolog.Nop()}
+	s.subLogger = zstdlog.NewStdLoggerWithLevel(s.log, zerolog.TraceLevel)
+	return s
+}
+
+func TestFixture_Replay(t *testing.T) {
+	tests := []struct {
+		name   string
+		client domain.DownloadClient
+	}{
+		{
+			name: "qbittorrent",
+			client: domain.DownloadClient{
+				Type:     domain.DownloadClientTypeQbittorrent,
+				Username: "admin",
+				Password: "adminadmin",
+			},
+		},
+		{
+			name: "sabnzbd",
+			client: domain.DownloadClient{
+				Type:     domain.DownloadClientTypeSabnzbd,
+				Settings: domain.DownloadClientSettings{APIKey: "apikey"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture, err := LoadFixture(tt.client.Type, "")
+			assert.NoError(t, err)
+
+			replay := httptest.NewServer(newFixtureReplayer(fixture))
+			defer replay.Close()
+
+			// record the replayed exchanges to check the recorder and verify against the same fixture
+			target, _ := url.Parse(replay.URL)
+			recorder := newFixtureRecorder(target, false)
+
+			proxy := httptest.NewServer(recorder)
+			defer proxy.Close()
+
+			client, err := proxiedClient(tt.client, strings.TrimPrefix(proxy.URL, "http://"))
+			assert.NoError(t, err)
+
+			assert.NoError(t, newTestService().testConnection(context.Background(), client))
+
+			live := recorder.Exchanges()
+			assert.Len(t, live, len(fixture.Exchanges))
+			assert.Empty(t, VerifyFixture(fixture, live))
+		})
+	}
+}
+
+func TestVerifyFixture(t *testing.T) {
+	contract := &Fixture{
+		Exchanges: []FixtureExchange{
+			{Method: "POST", Path: "/api/v2/auth/login", Status: 200, Body: "Ok."},
+			{Method: "GET", Path: "/api/v2/torrents/info", Status: 200, Body: `[{"hash":"abc","name":"Show","tags":"autobrr","trackers":{"count":1}}]`},
+			{Method: "GET", Path: "/api", RPCMethod: "version", Status: 200, Body: `{"version":"4.2.3"}`},
+		},
+	}
+
+	tests := []struct {
+		name string
+		live []FixtureExchange
+		want []string
+	}{
+		{
+			name: "unchanged_with_new_fields",
+			live: []FixtureExchange{
+				{Method: "POST", Path: "/api/v2/auth/login", Status: 200, Body: "Ok."},
+				{Method: "GET", Path: "/api/v2/torrents/info", Status: 200, Body: `[{"hash":"def","name":"Movie","tags":"","trackers":{"count":2},"popularity":1}]`},
+				{Method: "GET", Path: "/api", RPCMethod: "version", Status: 200, Body: `{"version":"4.3.0"}`},
+			},
+			want: []string{},
+		},
+		{
+			name: "drift",
+			live: []FixtureExchange{
+				{Method: "POST", Path: "/api/v2/auth/login", Status: 403, Body: "Forbidden"},
+				{Method: "GET", Path: "/api/v2/torrents/info", Status: 200, Body: `[{"infohash":"def","name":"Movie","trackers":[]}]`},
+			},
+			want: []string{
+				"POST /api/v2/auth/login: status 403, expected 200",
+				"GET /api/v2/torrents/info: missing field [].hash",
+				"GET /api/v2/torrents/info: missing field [].tags",
+				"GET /api/v2/torrents/info: missing field [].trackers (not an object)",
+				"GET /api version: not called",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, VerifyFixture(contract, tt.live))
+		})
+	}
+}
+
+func TestScrubFixtureHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   string
+	}{
+		{
+			name:   "session_cookie",
+			header: "Set-Cookie",
+			value:  "SID=J2Jm5qZ0r1l8d7xYk3Vb; HttpOnly; SameSite=Strict; path=/",
+			want:   "SID=fixture; Path=/; HttpOnly; SameSite=Strict",
+		},
+		{
+			name:   "expiring_cookie",
+			header: "Set-Cookie",
+			value:  "session=abc.def; Expires=Wed, 21 Oct 2026 07:28:00 GMT; Secure",
+			want:   "session=fixture; Secure",
+		},
+		{
+			name:   "transmission_session",
+			header: "X-Transmission-Session-Id",
+			value:  "z8kJ2pQ1xV9mN3bL7cR5tY0wE4uI6oA",
+			want:   "fixture",
+		},
+		{
+			name:   "content_type",
+			header: "Content-Type",
+			value:  "application/json",
+			want:   "application/json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scrubFixtureHeader(tt.header, tt.value))
+		})
+	}
+}
+
+// mockDownloadClientRepo only implements FindByID, calling anything else panics
+type mockDownloadClientRepo struct {
+	domain.DownloadClientRepo
+	client *domain.DownloadClient
+}
+
+func (r *mockDownloadClientRepo) FindByID(ctx context.Context, id int32) (*domain.DownloadClient, error) {
+	return r.client, nil
+}
+
+func TestService_SelfTest_Unsupported(t *testing.T) {
+	s := newTestService()
+	s.repo = &mockDownloadClientRepo{client: &domain.DownloadClient{ID: 1, Name: "transmission", Type: domain.DownloadClientTypeTransmission}}
+
+	_, err := s.SelfTest(context.Background(), 1, domain.DownloadClientSelfTestOptions{})
+	assert.ErrorContains(t, err, "selftest not supported for client type: TRANSMISSION")
+}
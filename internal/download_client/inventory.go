@@ -0,0 +1,298 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/autobrr/go-rtorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+const inventoryCacheTTL = 15 * time.Minute
+
+type InventoryCache struct {
+	mu    sync.RWMutex
+	items map[int32]*domain.DownloadClientInventory
+}
+
+func NewInventoryCache() *InventoryCache {
+	return &InventoryCache{
+		items: make(map[int32]*domain.DownloadClientInventory),
+	}
+}
+
+func (c *InventoryCache) Set(id int32, inventory *domain.DownloadClientInventory) {
+	if inventory != nil {
+		c.mu.Lock()
+		c.items[id] = inventory
+		c.mu.Unlock()
+	}
+}
+
+// Get returns the cached inventory if it has not expired
+func (c *InventoryCache) Get(id int32) *domain.DownloadClientInventory {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.items[id]
+	if ok && time.Since(v.UpdatedAt) < inventoryCacheTTL {
+		return v
+	}
+	return nil
+}
+
+func (c *InventoryCache) Pop(id int32) {
+	c.mu.Lock()
+	delete(c.items, id)
+	c.mu.Unlock()
+}
+
+func (c *InventoryCache) Clear() {
+	c.mu.Lock()
+	c.items = make(map[int32]*domain.DownloadClientInventory)
+	c.mu.Unlock()
+}
+
+// GetInventory returns categories, labels, tags and save paths from the download client.
+// Results are cached, set refresh to query the client again.
+func (s *service) GetInventory(ctx context.Context, clientID int32, refresh bool) (*domain.DownloadClientInventory, error) {
+	if !refresh {
+		if inventory := s.inventoryCache.Get(clientID); inventory != nil {
+			return inventory, nil
+		}
+	}
+
+	client, err := s.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &domain.DownloadClientInventory{
+		ClientID:   client.ID,
+		Categories: []domain.DownloadClientCategory{},
+		Labels:     []string{},
+		Tags:       []string{},
+		SavePaths:  []string{},
+	}
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		qbt, ok := client.Client.(*qbittorrent.Client)
+		if !ok {
+			return nil, errors.New("could not get qBittorrent client: %s", client.Name)
+		}
+		err = qbittorrentInventory(ctx, qbt, inventory)
+
+	case domain.DownloadClientTypeDelugeV1, domain.DownloadClientTypeDelugeV2:
+		err = s.withDeluge(ctx, client, func(del deluge.DelugeClient) error {
+			return delugeInventory(ctx, del, inventory)
+		})
+
+	case domain.DownloadClientTypeRTorrent:
+		rt, ok := client.Client.(*rtorrent.Client)
+		if !ok {
+			return nil, errors.New("could not get rTorrent client: %s", client.Name)
+		}
+		err = rtorrentInventory(ctx, rt, inventory)
+
+	case domain.DownloadClientTypeTransmission:
+		tbt, ok := client.Client.(*transmissionrpc.Client)
+		if !ok {
+			return nil, errors.New("could not get Transmission client: %s", client.Name)
+		}
+		err = transmissionInventory(ctx, tbt, inventory)
+
+	default:
+		return nil, errors.New("inventory not supported for client type: %s", client.Type)
+	}
+
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not get inventory for client: %s", client.Name)
+		return nil, err
+	}
+
+	inventory.UpdatedAt = time.Now()
+
+	s.inventoryCache.Set(clientID, inventory)
+
+	return inventory, nil
+}
+
+type qbittorrentInventoryClient interface {
+	GetCategoriesCtx(ctx context.Context) (map[string]qbittorrent.Category, error)
+	GetTagsCtx(ctx context.Context) ([]string, error)
+	GetAppPreferencesCtx(ctx context.Context) (qbittorrent.AppPreferences, error)
+}
+
+func qbittorrentInventory(ctx context.Context, qbt qbittorrentInventoryClient, inventory *domain.DownloadClientInventory) error {
+	categories, err := qbt.GetCategoriesCtx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get categories")
+	}
+
+	paths := map[string]struct{}{}
+
+	for _, category := range categories {
+		inventory.Categories = append(inventory.Categories, domain.DownloadClientCategory{
+			Name:     category.Name,
+			SavePath: category.SavePath,
+		})
+
+		if category.SavePath != "" {
+			paths[category.SavePath] = struct{}{}
+		}
+	}
+
+	sort.Slice(inventory.Categories, func(i, j int) bool {
+		return inventory.Categories[i].Name < inventory.Categories[j].Name
+	})
+
+	tags, err := qbt.GetTagsCtx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get tags")
+	}
+
+	inventory.Tags = append(inventory.Tags, tags...)
+	sort.Strings(inventory.Tags)
+
+	prefs, err := qbt.GetAppPreferencesCtx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get app preferences")
+	}
+
+	if prefs.SavePath != "" {
+		paths[prefs.SavePath] = struct{}{}
+	}
+
+	inventory.SavePaths = sortedKeys(paths)
+
+	return nil
+}
+
+// delugeLabelClient is implemented by both the Deluge v1 and v2 clients
+type delugeLabelClient interface {
+	LabelPlugin(ctx context.Context) (*deluge.LabelPlugin, error)
+}
+
+type delugeLabelLister interface {
+	GetLabels(ctx context.Context) ([]string, error)
+}
+
+func delugeInventory(ctx context.Context, del deluge.DelugeClient, inventory *domain.DownloadClientInventory) error {
+	labeler, ok := del.(delugeLabelClient)
+	if !ok {
+		return nil
+	}
+
+	labelPlugin, err := labeler.LabelPlugin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not load label plugin")
+	}
+
+	// label plugin is not enabled
+	if labelPlugin == nil {
+		return nil
+	}
+
+	return delugeLabelInventory(ctx, labelPlugin, inventory)
+}
+
+func delugeLabelInventory(ctx context.Context, labelPlugin delugeLabelLister, inventory *domain.DownloadClientInventory) error {
+	labels, err := labelPlugin.GetLabels(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get labels")
+	}
+
+	inventory.Labels = append(inventory.Labels, labels...)
+	sort.Strings(inventory.Labels)
+
+	return nil
+}
+
+type rtorrentInventoryClient interface {
+	GetTorrents(ctx context.Context, view rtorrent.View) ([]rtorrent.Torrent, error)
+}
+
+func rtorrentInventory(ctx context.Context, rt rtorrentInventoryClient, inventory *domain.DownloadClientInventory) error {
+	torrents, err := rt.GetTorrents(ctx, rtorrent.ViewMain)
+	if err != nil {
+		return errors.Wrap(err, "could not get torrents")
+	}
+
+	labels := map[string]struct{}{}
+	paths := map[string]struct{}{}
+
+	for _, torrent := range torrents {
+		if torrent.Label != "" {
+			labels[torrent.Label] = struct{}{}
+		}
+		if torrent.Path != "" {
+			paths[torrent.Path] = struct{}{}
+		}
+	}
+
+	inventory.Labels = sortedKeys(labels)
+	inventory.SavePaths = sortedKeys(paths)
+
+	return nil
+}
+
+type transmissionInventoryClient interface {
+	TorrentGet(ctx context.Context, fields []string, ids []int64) ([]transmissionrpc.Torrent, error)
+	SessionArgumentsGetAll(ctx context.Context) (transmissionrpc.SessionArguments, error)
+}
+
+func transmissionInventory(ctx context.Context, tbt transmissionInventoryClient, inventory *domain.DownloadClientInventory) error {
+	torrents, err := tbt.TorrentGet(ctx, []string{"labels", "downloadDir"}, []int64{})
+	if err != nil {
+		return errors.Wrap(err, "could not get torrents")
+	}
+
+	labels := map[string]struct{}{}
+	paths := map[string]struct{}{}
+
+	for _, torrent := range torrents {
+		for _, label := range torrent.Labels {
+			if label != "" {
+				labels[label] = struct{}{}
+			}
+		}
+		if torrent.DownloadDir != nil && *torrent.DownloadDir != "" {
+			paths[*torrent.DownloadDir] = struct{}{}
+		}
+	}
+
+	session, err := tbt.SessionArgumentsGetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get session arguments")
+	}
+
+	if session.DownloadDir != nil && *session.DownloadDir != "" {
+		paths[*session.DownloadDir] = struct{}{}
+	}
+
+	inventory.Labels = sortedKeys(labels)
+	inventory.SavePaths = sortedKeys(paths)
+
+	return nil
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/autobrr/go-rtorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newInventory() *domain.DownloadClientInventory {
+	return &domain.DownloadClientInventory{
+		Categories: []domain.DownloadClientCategory{},
+		Labels:     []string{},
+		Tags:       []string{},
+		SavePaths:  []string{},
+	}
+}
+
+type fakeQbittorrent struct {
+	categories map[string]qbittorrent.Category
+	tags       []string
+	prefs      qbittorrent.AppPreferences
+}
+
+func (f *fakeQbittorrent) GetCategoriesCtx(ctx context.Context) (map[string]qbittorrent.Category, error) {
+	return f.categories, nil
+}
+
+func (f *fakeQbittorrent) GetTagsCtx(ctx context.Context) ([]string, error) {
+	return f.tags, nil
+}
+
+func (f *fakeQbittorrent) GetAppPreferencesCtx(ctx context.Context) (qbittorrent.AppPreferences, error) {
+	return f.prefs, nil
+}
+
+func TestQbittorrentInventory(t *testing.T) {
+	qbt := &fakeQbittorrent{
+		categories: map[string]qbittorrent.Category{
+			"tv":     {Name: "tv", SavePath: "/data/tv"},
+			"movies": {Name: "movies", SavePath: "/data/movies"},
+			"misc":   {Name: "misc"},
+		},
+		tags:  []string{"race", "autobrr"},
+		prefs: qbittorrent.AppPreferences{SavePath: "/data/tv"},
+	}
+
+	inventory := newInventory()
+	assert.NoError(t, qbittorrentInventory(context.Background(), qbt, inventory))
+
+	assert.Equal(t, []domain.DownloadClientCategory{
+		{Name: "misc"},
+		{Name: "movies", SavePath: "/data/movies"},
+		{Name: "tv", SavePath: "/data/tv"},
+	}, inventory.Categories)
+	assert.Equal(t, []string{"autobrr", "race"}, inventory.Tags)
+	assert.Equal(t, []string{"/data/movies", "/data/tv"}, inventory.SavePaths)
+}
+
+type fakeRTorrent struct {
+	torrents []rtorrent.Torrent
+}
+
+func (f *fakeRTorrent) GetTorrents(ctx context.Context, view rtorrent.View) ([]rtorrent.Torrent, error) {
+	return f.torrents, nil
+}
+
+func TestRTorrentInventory(t *testing.T) {
+	rt := &fakeRTorrent{
+		torrents: []rtorrent.Torrent{
+			{Name: "a", Label: "tv", Path: "/data/tv/a"},
+			{Name: "b", Label: "tv", Path: "/data/tv/b"},
+			{Name: "c", Label: "", Path: ""},
+			{Name: "d", Label: "movies", Path: "/data/movies/d"},
+		},
+	}
+
+	inventory := newInventory()
+	assert.NoError(t, rtorrentInventory(context.Background(), rt, inventory))
+
+	assert.Equal(t, []string{"movies", "tv"}, inventory.Labels)
+	assert.Equal(t, []string{"/data/movies/d", "/data/tv/a", "/data/tv/b"}, inventory.SavePaths)
+}
+
+type fakeTransmission struct {
+	torrents []transmissionrpc.Torrent
+	session  transmissionrpc.SessionArguments
+}
+
+func (f *fakeTransmission) TorrentGet(ctx context.Context, fields []string, ids []int64) ([]transmissionrpc.Torrent, error) {
+	return f.torrents, nil
+}
+
+func (f *fakeTransmission) SessionArgumentsGetAll(ctx context.Context) (transmissionrpc.SessionArguments, error) {
+	return f.session, nil
+}
+
+func TestTransmissionInventory(t *testing.T) {
+	dir := func(s string) *string { return &s }
+
+	tbt := &fakeTransmission{
+		torrents: []transmissionrpc.Torrent{
+			{Labels: []string{"tv", "race"}, DownloadDir: dir("/data/tv")},
+			{Labels: []string{"", "tv"}, DownloadDir: dir("")},
+			{Labels: nil, DownloadDir: nil},
+		},
+		session: transmissionrpc.SessionArguments{DownloadDir: dir("/data/downloads")},
+	}
+
+	inventory := newInventory()
+	assert.NoError(t, transmissionInventory(context.Background(), tbt, inventory))
+
+	assert.Equal(t, []string{"race", "tv"}, inventory.Labels)
+	assert.Equal(t, []string{"/data/downloads", "/data/tv"}, inventory.SavePaths)
+}
+
+type fakeLabelPlugin struct {
+	labels []string
+}
+
+func (f *fakeLabelPlugin) GetLabels(ctx context.Context) ([]string, error) {
+	return f.labels, nil
+}
+
+func TestDelugeLabelInventory(t *testing.T) {
+	inventory := newInventory()
+	assert.NoError(t, delugeLabelInventory(context.Background(), &fakeLabelPlugin{labels: []string{"tv", "movies"}}, inventory))
+
+	assert.Equal(t, []string{"movies", "tv"}, inventory.Labels)
+}
+
+// fakeDeluge only implements the methods used by the session, calling anything else panics
+type fakeDeluge struct {
+	deluge.DelugeClient
+
+	connects int
+	closes   int
+	err      error
+}
+
+func (f *fakeDeluge) Connect(ctx context.Context) error {
+	f.connects++
+	return nil
+}
+
+func (f *fakeDeluge) Close() error {
+	f.closes++
+	return nil
+}
+
+func (f *fakeDeluge) TorrentsStatus(ctx context.Context, state deluge.TorrentState, ids []string) (map[string]*deluge.TorrentStatus, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return map[string]*deluge.TorrentStatus{
+		"abc": {Name: "Show.S01E01.1080p.WEB-DL.x264-GROUP", SavePath: "/data/tv"},
+	}, nil
+}
+
+func TestService_withDeluge(t *testing.T) {
+	s := &service{
+		log:            zerolog.Nop(),
+		delugeSessions: newDelugeSessions(),
+	}
+
+	del := &fakeDeluge{}
+	client := &domain.DownloadClient{ID: 1, Name: "deluge", Type: domain.DownloadClientTypeDelugeV2, Client: del}
+
+	// the connection is reused between calls
+	for i := 0; i < 3; i++ {
+		torrents, err := s.delugeTorrents(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Len(t, torrents, 1)
+	}
+	assert.Equal(t, 1, del.connects)
+	assert.Equal(t, 0, del.closes)
+
+	// a failed call drops the connection and the next call reconnects
+	del.err = errors.New("connection reset")
+	_, err := s.delugeTorrents(context.Background(), client)
+	assert.Error(t, err)
+	assert.Equal(t, 1, del.closes)
+
+	del.err = nil
+	_, err = s.delugeTorrents(context.Background(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, del.connects)
+
+	// an updated client replaces the session
+	updated := &fakeDeluge{}
+	client.Client = updated
+	_, err = s.delugeTorrents(context.Background(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, del.closes)
+	assert.Equal(t, 1, updated.connects)
+
+	s.delugeSessions.pop(client.ID)
+	assert.Equal(t, 1, updated.closes)
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/autobrr/go-rtorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+// SearchTorrents searches all enabled torrent clients for torrents matching the hash or name
+func (s *service) SearchTorrents(ctx context.Context, query string) (*domain.DownloadClientSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ValidationErrors{{Field: "q", Message: "query can't be empty"}}
+	}
+
+	clients, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list download clients")
+	}
+
+	result := &domain.DownloadClientSearchResult{
+		Query:    query,
+		Torrents: []domain.DownloadClientTorrent{},
+		Errors:   []domain.DownloadClientSearchError{},
+	}
+
+	matcher := newTorrentMatcher(query)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, c := range clients {
+		if !c.Enabled || !searchableClientTypes[c.Type] {
+			continue
+		}
+
+		wg.Add(1)
+		go func(c domain.DownloadClient) {
+			defer wg.Done()
+
+			torrents, err := s.clientTorrents(ctx, c.ID)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				s.log.Error().Err(err).Msgf("could not search torrents in client: %s", c.Name)
+				result.Errors = append(result.Errors, domain.DownloadClientSearchError{
+					ClientID:   c.ID,
+					ClientName: c.Name,
+					Error:      err.Error(),
+				})
+				return
+			}
+
+			for _, torrent := range torrents {
+				if !matcher.match(torrent) {
+					continue
+				}
+
+				torrent.ClientID = c.ID
+				torrent.ClientName = c.Name
+				torrent.ClientType = c.Type
+
+				result.Torrents = append(result.Torrents, torrent)
+			}
+		}(c)
+	}
+
+	wg.Wait()
+
+	sort.Slice(result.Torrents, func(i, j int) bool {
+		if result.Torrents[i].ClientName != result.Torrents[j].ClientName {
+			return result.Torrents[i].ClientName < result.Torrents[j].ClientName
+		}
+		return result.Torrents[i].Name < result.Torrents[j].Name
+	})
+
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].ClientName < result.Errors[j].ClientName
+	})
+
+	return result, nil
+}
+
+var searchableClientTypes = map[domain.DownloadClientType]bool{
+	domain.DownloadClientTypeQbittorrent:  true,
+	domain.DownloadClientTypeDelugeV1:     true,
+	domain.DownloadClientTypeDelugeV2:     true,
+	domain.DownloadClientTypeRTorrent:     true,
+	domain.DownloadClientTypeTransmission: true,
+}
+
+// torrentMatcher matches torrents by exact info hash or by name, ignoring case and separators
+type torrentMatcher struct {
+	hash string
+	name string
+}
+
+func newTorrentMatcher(query string) torrentMatcher {
+	return torrentMatcher{
+		hash: strings.ToLower(query),
+		name: normalizeTorrentName(query),
+	}
+}
+
+func (m torrentMatcher) match(torrent domain.DownloadClientTorrent) bool {
+	if strings.ToLower(torrent.Hash) == m.hash {
+		return true
+	}
+
+	return m.name != "" && strings.Contains(normalizeTorrentName(torrent.Name), m.name)
+}
+
+var torrentNameReplacer = strings.NewReplacer(".", " ", "_", " ", "-", " ")
+
+func normalizeTorrentName(name string) string {
+	return strings.Join(strings.Fields(torrentNameReplacer.Replace(strings.ToLower(name))), " ")
+}
+
+func (s *service) clientTorrents(ctx context.Context, clientID int32) ([]domain.DownloadClientTorrent, error) {
+	client, err := s.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		return s.qbittorrentTorrents(ctx, client)
+
+	case domain.DownloadClientTypeDelugeV1, domain.DownloadClientTypeDelugeV2:
+		return s.delugeTorrents(ctx, client)
+
+	case domain.DownloadClientTypeRTorrent:
+		return s.rtorrentTorrents(ctx, client)
+
+	case domain.DownloadClientTypeTransmission:
+		return s.transmissionTorrents(ctx, client)
+	}
+
+	return nil, errors.New("search not supported for client type: %s", client.Type)
+}
+
+func (s *service) qbittorrentTorrents(ctx context.Context, client *domain.DownloadClient) ([]domain.DownloadClientTorrent, error) {
+	qbt, ok := client.Client.(*qbittorrent.Client)
+	if !ok {
+		return nil, errors.New("could not get qBittorrent client: %s", client.Name)
+	}
+
+	torrents, err := qbt.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Filter: qbittorrent.TorrentFilterAll})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get torrents")
+	}
+
+	result := make([]domain.DownloadClientTorrent, 0, len(torrents))
+	for _, torrent := range torrents {
+		t := domain.DownloadClientTorrent{
+			Hash:     torrent.Hash,
+			Name:     torrent.Name,
+			State:    string(torrent.State),
+			SavePath: torrent.SavePath,
+			Category: torrent.Category,
+			Size:     torrent.Size,
+			Progress: torrent.Progress,
+		}
+
+		for _, tag := range strings.Split(torrent.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				t.Labels = append(t.Labels, tag)
+			}
+		}
+
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (s *service) delugeTorrents(ctx context.Context, client *domain.DownloadClient) ([]domain.DownloadClientTorrent, error) {
+	var torrents map[string]*deluge.TorrentStatus
+
+	err := s.withDeluge(ctx, client, func(del deluge.DelugeClient) error {
+		var err error
+		torrents, err = del.TorrentsStatus(ctx, deluge.StateUnspecified, nil)
+		if err != nil {
+			return errors.Wrap(err, "could not get torrents")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.DownloadClientTorrent, 0, len(torrents))
+	for hash, torrent := range torrents {
+		savePath := torrent.SavePath
+		if torrent.DownloadLocation != "" {
+			savePath = torrent.DownloadLocation
+		}
+
+		result = append(result, domain.DownloadClientTorrent{
+			Hash:     hash,
+			Name:     torrent.Name,
+			State:    torrent.State,
+			SavePath: savePath,
+			Size:     torrent.TotalSize,
+			Progress: float64(torrent.Progress) / 100,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *service) rtorrentTorrents(ctx context.Context, client *domain.DownloadClient) ([]domain.DownloadClientTorrent, error) {
+	rt, ok := client.Client.(*rtorrent.Client)
+	if !ok {
+		return nil, errors.New("could not get rTorrent client: %s", client.Name)
+	}
+
+	torrents, err := rt.GetTorrents(ctx, rtorrent.ViewMain)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get torrents")
+	}
+
+	result := make([]domain.DownloadClientTorrent, 0, len(torrents))
+	for _, torrent := range torrents {
+		t := domain.DownloadClientTorrent{
+			Hash:     torrent.Hash,
+			Name:     torrent.Name,
+			State:    "downloading",
+			SavePath: torrent.Path,
+			Size:     int64(torrent.Size),
+		}
+
+		if torrent.Completed {
+			t.State = "completed"
+			t.Progress = 1
+		}
+
+		if torrent.Label != "" {
+			t.Labels = []string{torrent.Label}
+		}
+
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (s *service) transmissionTorrents(ctx context.Context, client *domain.DownloadClient) ([]domain.DownloadClientTorrent, error) {
+	tbt, ok := client.Client.(*transmissionrpc.Client)
+	if !ok {
+		return nil, errors.New("could not get Transmission client: %s", client.Name)
+	}
+
+	torrents, err := tbt.TorrentGet(ctx, []string{"hashString", "name", "status", "downloadDir", "labels", "percentDone"}, []int64{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get torrents")
+	}
+
+	result := make([]domain.DownloadClientTorrent, 0, len(torrents))
+	for _, torrent := range torrents {
+		t := domain.DownloadClientTorrent{
+			Labels: torrent.Labels,
+		}
+
+		if torrent.HashString != nil {
+			t.Hash = *torrent.HashString
+		}
+		if torrent.Name != nil {
+			t.Name = *torrent.Name
+		}
+		if torrent.Status != nil {
+			t.State = torrent.Status.String()
+		}
+		if torrent.DownloadDir != nil {
+			t.SavePath = *torrent.DownloadDir
+		}
+		if torrent.PercentDone != nil {
+			t.Progress = *torrent.PercentDone
+		}
+
+		result = append(result, t)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTorrentMatcher(t *testing.T) {
+	torrent := domain.DownloadClientTorrent{
+		Hash: "8D2B5E1C4FF7C9A3F0E6B1D2C3A4B5C6D7E8F901",
+		Name: "Show.S01E01.1080p.WEB-DL.x264-GROUP",
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "8d2b5e1c4ff7c9a3f0e6b1d2c3a4b5c6d7e8f901", want: true},
+		{query: "8d2b5e1c", want: false},
+		{query: "Show.S01E01.1080p.WEB-DL.x264-GROUP", want: true},
+		{query: "show s01e01", want: true},
+		{query: "s01e01 1080p web dl", want: true},
+		{query: "Show S01E02", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, newTorrentMatcher(tt.query).match(torrent))
+		})
+	}
+}
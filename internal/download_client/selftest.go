@@ -0,0 +1,189 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// selfTestClientTypes are the clients with a fixture in fixtures/
+var selfTestClientTypes = []domain.DownloadClientType{
+	domain.DownloadClientTypeQbittorrent,
+	domain.DownloadClientTypeSabnzbd,
+}
+
+func selfTestClientTypesString() string {
+	types := make([]string, 0, len(selfTestClientTypes))
+	for _, t := range selfTestClientTypes {
+		types = append(types, string(t))
+	}
+	return strings.Join(types, ", ")
+}
+
+// SelfTest runs the connection test for a client through a recording proxy and compares the exchanges
+// with the recorded fixture to catch api changes in new client versions early.
+// With opts.Record the exchanges are saved as the new fixture instead.
+func (s *service) SelfTest(ctx context.Context, clientID int32, opts domain.DownloadClientSelfTestOptions) (*domain.DownloadClientSelfTestReport, error) {
+	client, err := s.repo.FindByID(ctx, clientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find download client by id: %d", clientID)
+	}
+
+	report := &domain.DownloadClientSelfTestReport{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		ClientType: client.Type,
+		Issues:     []string{},
+	}
+
+	var contract *Fixture
+	if !opts.Record {
+		// only the embedded fixtures are shipped, other clients need a recorded fixture dir
+		if opts.FixtureDir == "" && !slices.Contains(selfTestClientTypes, client.Type) {
+			return nil, errors.New("selftest not supported for client type: %s, fixtures are included for: %s. Record one with --client-record", client.Type, selfTestClientTypesString())
+		}
+
+		contract, err = LoadFixture(client.Type, opts.FixtureDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "no fixture recorded for client type: %s", client.Type)
+		}
+	}
+
+	target, err := selfTestTarget(client)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start recording proxy")
+	}
+
+	recorder := newFixtureRecorder(target, client.TLSSkipVerify)
+
+	srv := &http.Server{Handler: recorder, ReadHeaderTimeout: 15 * time.Second}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	proxied, err := proxiedClient(*client, listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Debug().Msgf("client selftest: %s via recording proxy %s", client.Name, listener.Addr().String())
+
+	testErr := s.testConnection(ctx, proxied)
+
+	exchanges := recorder.Exchanges()
+	report.Exchanges = len(exchanges)
+
+	if opts.Record {
+		if testErr != nil {
+			return nil, errors.Wrap(testErr, "connection test failed, fixture not recorded")
+		}
+
+		fixture := &Fixture{
+			ClientType: client.Type,
+			RecordedAt: time.Now().UTC(),
+			Exchanges:  exchanges,
+		}
+
+		report.FixturePath, err = fixture.Save(opts.FixtureDir)
+		if err != nil {
+			return nil, err
+		}
+
+		return report, nil
+	}
+
+	if testErr != nil {
+		report.Issues = append(report.Issues, fmt.Sprintf("connection test failed: %v", testErr))
+	}
+
+	report.Issues = append(report.Issues, VerifyFixture(contract, exchanges)...)
+
+	return report, nil
+}
+
+// selfTestTarget returns the scheme and host of the client api, the path is kept by the proxied client
+func selfTestTarget(client *domain.DownloadClient) (*url.URL, error) {
+	host := client.Host
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		host = client.BuildLegacyHost()
+
+	case domain.DownloadClientTypeTransmission:
+		scheme := "http"
+		if client.TLS {
+			scheme = "https"
+		}
+		return &url.URL{Scheme: scheme, Host: fmt.Sprintf("%s:%d", client.Host, client.Port)}, nil
+
+	case domain.DownloadClientTypeDelugeV1, domain.DownloadClientTypeDelugeV2:
+		return nil, errors.New("selftest not supported for client type: %s", client.Type)
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse client host: %s", client.Host)
+	}
+
+	return &url.URL{Scheme: u.Scheme, Host: u.Host}, nil
+}
+
+// proxiedClient returns a copy of the client which connects through the proxy at addr
+func proxiedClient(client domain.DownloadClient, addr string) (domain.DownloadClient, error) {
+	client.TLS = false
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		u, err := url.Parse(client.BuildLegacyHost())
+		if err != nil {
+			return client, errors.Wrap(err, "could not parse client host: %s", client.Host)
+		}
+
+		u.Scheme = "http"
+		u.Host = addr
+
+		client.Host = u.String()
+		client.Port = 0
+
+		return client, nil
+
+	case domain.DownloadClientTypeTransmission:
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return client, err
+		}
+
+		client.Host = host
+		client.Port, err = strconv.Atoi(port)
+
+		return client, err
+	}
+
+	u, err := url.Parse(client.Host)
+	if err != nil {
+		return client, errors.Wrap(err, "could not parse client host: %s", client.Host)
+	}
+
+	u.Scheme = "http"
+	u.Host = addr
+
+	client.Host = u.String()
+
+	return client, nil
+}
@@ -15,11 +15,18 @@ import (
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/aria2"
+	"github.com/autobrr/autobrr/pkg/downloadstation"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/jellyfin"
 	"github.com/autobrr/autobrr/pkg/lidarr"
+	"github.com/autobrr/autobrr/pkg/mylar"
+	"github.com/autobrr/autobrr/pkg/nzbget"
+	"github.com/autobrr/autobrr/pkg/plex"
 	"github.com/autobrr/autobrr/pkg/porla"
 	"github.com/autobrr/autobrr/pkg/radarr"
 	"github.com/autobrr/autobrr/pkg/readarr"
+	"github.com/autobrr/autobrr/pkg/remotefs"
 	"github.com/autobrr/autobrr/pkg/sabnzbd"
 	"github.com/autobrr/autobrr/pkg/sonarr"
 	"github.com/autobrr/autobrr/pkg/transmission"
@@ -42,6 +49,10 @@ type Service interface {
 	Test(ctx context.Context, client domain.DownloadClient) error
 
 	GetClient(ctx context.Context, clientId int32) (*domain.DownloadClient, error)
+	GetInventory(ctx context.Context, clientID int32, refresh bool) (*domain.DownloadClientInventory, error)
+	ClearCache()
+	SearchTorrents(ctx context.Context, query string) (*domain.DownloadClientSearchResult, error)
+	SelfTest(ctx context.Context, clientID int32, opts domain.DownloadClientSelfTestOptions) (*domain.DownloadClientSelfTestReport, error)
 }
 
 type service struct {
@@ -49,8 +60,10 @@ type service struct {
 	repo      domain.DownloadClientRepo
 	subLogger *log.Logger
 
-	cache *ClientCache
-	m     sync.RWMutex
+	cache          *ClientCache
+	inventoryCache *InventoryCache
+	delugeSessions *delugeSessions
+	m              sync.RWMutex
 }
 
 func NewService(log logger.Logger, repo domain.DownloadClientRepo) Service {
@@ -58,8 +71,10 @@ func NewService(log logger.Logger, repo domain.DownloadClientRepo) Service {
 		log:  log.With().Str("module", "download_client").Logger(),
 		repo: repo,
 
-		cache: NewClientCache(),
-		m:     sync.RWMutex{},
+		cache:          NewClientCache(),
+		inventoryCache: NewInventoryCache(),
+		delugeSessions: newDelugeSessions(),
+		m:              sync.RWMutex{},
 	}
 
 	s.subLogger = zstdlog.NewStdLoggerWithLevel(s.log.With().Logger(), zerolog.TraceLevel)
@@ -126,6 +141,8 @@ func (s *service) Update(ctx context.Context, client *domain.DownloadClient) err
 	}
 
 	s.cache.Set(client.ID, client)
+	s.inventoryCache.Pop(client.ID)
+	s.delugeSessions.pop(client.ID)
 
 	return err
 }
@@ -137,10 +154,19 @@ func (s *service) Delete(ctx context.Context, clientID int32) error {
 	}
 
 	s.cache.Pop(clientID)
+	s.inventoryCache.Pop(clientID)
+	s.delugeSessions.pop(clientID)
 
 	return nil
 }
 
+// ClearCache drops all cached clients, eg. after they were changed by another instance
+func (s *service) ClearCache() {
+	s.cache.Clear()
+	s.inventoryCache.Clear()
+	s.delugeSessions.clear()
+}
+
 func (s *service) Test(ctx context.Context, client domain.DownloadClient) error {
 	// basic validation of client
 	if err := client.Validate(); err != nil {
@@ -201,6 +227,46 @@ func (s *service) GetClient(ctx context.Context, clientId int32) (*domain.Downlo
 			Log:           zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "Porla").Str("client", client.Name).Logger(), zerolog.TraceLevel),
 		})
 
+	case domain.DownloadClientTypeAria2:
+		client.Client = aria2.NewClient(aria2.Config{
+			Hostname:      client.Host,
+			Secret:        client.Settings.APIKey,
+			TLSSkipVerify: client.TLSSkipVerify,
+			BasicUser:     client.Settings.Auth.Username,
+			BasicPass:     client.Settings.Auth.Password,
+			Log:           zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "aria2").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+		})
+
+	case domain.DownloadClientTypeDownloadStation:
+		client.Client = downloadstation.NewClient(downloadstation.Config{
+			Host:          client.Host,
+			Username:      client.Username,
+			Password:      client.Password,
+			TLSSkipVerify: client.TLSSkipVerify,
+			BasicUser:     client.Settings.Auth.Username,
+			BasicPass:     client.Settings.Auth.Password,
+			Log:           zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "Download Station").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+		})
+
+	case domain.DownloadClientTypeNzbget:
+		client.Client = nzbget.NewClient(nzbget.Config{
+			Host:          client.Host,
+			Username:      client.Username,
+			Password:      client.Password,
+			TLSSkipVerify: client.TLSSkipVerify,
+			Log:           zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "NZBGet").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+		})
+
+	case domain.DownloadClientTypeRemoteWatchFolder:
+		client.Client = remotefs.NewClient(remotefs.Config{
+			URL:                client.Host,
+			Username:           client.Username,
+			Password:           client.Password,
+			TLSSkipVerify:      client.TLSSkipVerify,
+			HostKeyFingerprint: client.Settings.APIKey,
+			Log:                zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "Remote Watch Folder").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+		})
+
 	case domain.DownloadClientTypeDelugeV1:
 		client.Client = deluge.NewV1(deluge.Settings{
 			Hostname:             client.Host,
@@ -306,6 +372,30 @@ func (s *service) GetClient(ctx context.Context, clientId int32) (*domain.Downlo
 			Password:  client.Settings.Auth.Password,
 		})
 
+	case domain.DownloadClientTypeMylar:
+		client.Client = mylar.New(mylar.Config{
+			Hostname:  client.Host,
+			APIKey:    client.Settings.APIKey,
+			Log:       zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "Mylar").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+			BasicAuth: client.Settings.Auth.Enabled,
+			Username:  client.Settings.Auth.Username,
+			Password:  client.Settings.Auth.Password,
+		})
+
+	case domain.DownloadClientTypePlex:
+		client.Client = plex.New(plex.Config{
+			Hostname: client.Host,
+			Token:    client.Settings.APIKey,
+			Log:      zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "Plex").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+		})
+
+	case domain.DownloadClientTypeJellyfin:
+		client.Client = jellyfin.New(jellyfin.Config{
+			Hostname: client.Host,
+			APIKey:   client.Settings.APIKey,
+			Log:      zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "Jellyfin").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+		})
+
 	case domain.DownloadClientTypeSonarr:
 		client.Client = sonarr.New(sonarr.Config{
 			Hostname:  client.Host,
@@ -7,27 +7,36 @@ import (
 	"context"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
 
 	"github.com/asaskevich/EventBus"
+	"github.com/r3labs/sse/v2"
 	"github.com/rs/zerolog"
 )
 
 type Subscriber struct {
-	log             zerolog.Logger
-	eventbus        EventBus.Bus
-	notificationSvc notification.Service
-	releaseSvc      release.Service
+	log               zerolog.Logger
+	eventbus          EventBus.Bus
+	notificationSvc   notification.Service
+	releaseSvc        release.Service
+	indexerSvc        indexer.Service
+	downloadClientSvc download_client.Service
+	sse               *sse.Server
 }
 
-func NewSubscribers(log logger.Logger, eventbus EventBus.Bus, notificationSvc notification.Service, releaseSvc release.Service) Subscriber {
+func NewSubscribers(log logger.Logger, eventbus EventBus.Bus, notificationSvc notification.Service, releaseSvc release.Service, indexerSvc indexer.Service, downloadClientSvc download_client.Service, sse *sse.Server) Subscriber {
 	s := Subscriber{
-		log:             log.With().Str("module", "events").Logger(),
-		eventbus:        eventbus,
-		notificationSvc: notificationSvc,
-		releaseSvc:      releaseSvc,
+		log:               log.With().Str("module", "events").Logger(),
+		eventbus:          eventbus,
+		notificationSvc:   notificationSvc,
+		releaseSvc:        releaseSvc,
+		indexerSvc:        indexerSvc,
+		downloadClientSvc: downloadClientSvc,
+		sse:               sse,
 	}
 
 	s.Register()
@@ -39,6 +48,9 @@ func (s Subscriber) Register() {
 	s.eventbus.Subscribe("release:store-action-status", s.releaseActionStatus)
 	s.eventbus.Subscribe("release:push", s.releasePushStatus)
 	s.eventbus.Subscribe("events:notification", s.sendNotification)
+	s.eventbus.Subscribe("database:changed", s.databaseChanged)
+	s.eventbus.Subscribe("events:live", s.publishLive)
+	s.eventbus.Subscribe("user:deleted", s.userDeleted)
 }
 
 func (s Subscriber) releaseActionStatus(actionStatus *domain.ReleaseActionStatus) {
@@ -58,8 +70,44 @@ func (s Subscriber) releasePushStatus(actionStatus *domain.ReleaseActionStatus)
 	}
 }
 
+func (s Subscriber) userDeleted(userID int) {
+	s.log.Trace().Msgf("events: 'user:deleted' '%d'", userID)
+
+	s.notificationSvc.ForgetUserPreference(userID)
+}
+
 func (s Subscriber) sendNotification(event *domain.NotificationEvent, payload *domain.NotificationPayload) {
 	s.log.Trace().Msgf("events: '%v' '%+v'", *event, payload)
 
 	s.notificationSvc.Send(*event, *payload)
 }
+
+// databaseChanged invalidates in-memory caches when another instance changed the database
+func (s Subscriber) databaseChanged(table string) {
+	s.log.Trace().Msgf("events: 'database:changed' '%s'", table)
+
+	if table == "" || table == "indexer" {
+		if err := s.indexerSvc.ReloadIndexers(); err != nil {
+			s.log.Error().Err(err).Msgf("events: 'database:changed' could not reload indexers")
+		} else {
+			s.log.Info().Msg("reloaded indexers after external database change")
+		}
+	}
+
+	if table == "" || table == "client" {
+		s.downloadClientSvc.ClearCache()
+		s.log.Info().Msg("cleared download client cache after external database change")
+	}
+}
+
+// publishLive streams the event to the clients subscribed to the live stream
+func (s Subscriber) publishLive(event *domain.LiveEvent) {
+	if s.sse == nil {
+		return
+	}
+
+	s.sse.Publish(domain.LiveStream, &sse.Event{
+		Event: []byte(event.Type),
+		Data:  event.Bytes(),
+	})
+}
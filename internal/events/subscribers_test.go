@@ -0,0 +1,150 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/internal/notification"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/r3labs/sse/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIndexerService only implements ReloadIndexers, calling anything else panics
+type mockIndexerService struct {
+	indexer.Service
+	reloads int
+}
+
+func (s *mockIndexerService) ReloadIndexers() error {
+	s.reloads++
+	return nil
+}
+
+// mockDownloadClientService only implements ClearCache, calling anything else panics
+type mockDownloadClientService struct {
+	download_client.Service
+	clears int
+}
+
+func (s *mockDownloadClientService) ClearCache() {
+	s.clears++
+}
+
+// mockNotificationService only implements ForgetUserPreference, calling anything else panics
+type mockNotificationService struct {
+	notification.Service
+	forgotten []int
+}
+
+func (s *mockNotificationService) ForgetUserPreference(userID int) {
+	s.forgotten = append(s.forgotten, userID)
+}
+
+func TestSubscriber_userDeleted(t *testing.T) {
+	bus := EventBus.New()
+	notificationSvc := &mockNotificationService{}
+
+	s := Subscriber{log: zerolog.Nop(), eventbus: bus, notificationSvc: notificationSvc}
+	s.Register()
+
+	bus.Publish("user:deleted", 3)
+
+	assert.Equal(t, []int{3}, notificationSvc.forgotten)
+}
+
+func TestSubscriber_databaseChanged(t *testing.T) {
+	tests := []struct {
+		table   string
+		reloads int
+		clears  int
+	}{
+		{table: "", reloads: 1, clears: 1},
+		{table: "indexer", reloads: 1, clears: 0},
+		{table: "client", reloads: 0, clears: 1},
+		{table: "filter", reloads: 0, clears: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			indexerSvc := &mockIndexerService{}
+			downloadClientSvc := &mockDownloadClientService{}
+
+			s := Subscriber{log: zerolog.Nop(), indexerSvc: indexerSvc, downloadClientSvc: downloadClientSvc}
+			s.databaseChanged(tt.table)
+
+			assert.Equal(t, tt.reloads, indexerSvc.reloads)
+			assert.Equal(t, tt.clears, downloadClientSvc.clears)
+		})
+	}
+}
+
+func TestSubscriber_publishLive(t *testing.T) {
+	server := sse.New()
+	defer server.Close()
+
+	// replay so the event published before connecting is received
+	server.CreateStreamWithOpts(domain.LiveStream, sse.StreamOpts{AutoReplay: true})
+
+	s := Subscriber{log: zerolog.Nop(), sse: server}
+	s.publishLive(domain.NewLiveEvent(domain.LiveEventReleaseDecision, domain.LiveReleaseDecision{
+		TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP",
+		Indexer:     "mock",
+		FilterID:    1,
+		Filter:      "movies",
+		Match:       true,
+	}))
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"?stream="+domain.LiveStream, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if scanner.Text() == "" && len(lines) > 0 {
+			break
+		}
+		if scanner.Text() != "" {
+			lines = append(lines, scanner.Text())
+		}
+	}
+
+	var event, data string
+	for _, line := range lines {
+		if v, ok := strings.CutPrefix(line, "event: "); ok {
+			event = v
+		}
+		if v, ok := strings.CutPrefix(line, "data: "); ok {
+			data = v
+		}
+	}
+
+	assert.Equal(t, string(domain.LiveEventReleaseDecision), event)
+	assert.Contains(t, data, `"type":"release-decision"`)
+	assert.Contains(t, data, `"torrent_name":"That.Movie.2023.1080p.BluRay.x264-GROUP"`)
+	assert.Contains(t, data, `"match":true`)
+}
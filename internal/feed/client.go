@@ -9,6 +9,9 @@ import (
 	"net/http/cookiejar"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/proxy"
+	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/sharedhttp"
 
 	"github.com/mmcdole/gofeed"
@@ -95,3 +98,43 @@ func (c *RSSParser) ParseURLWithContext(ctx context.Context, feedURL string) (fe
 
 	return c.parser.Parse(resp.Body)
 }
+
+// feedHTTPClient returns a http client with the proxy and request identity of the indexer of feed.
+// It returns nil when neither is set so the default client of the parser or api client is kept.
+func feedHTTPClient(feed *domain.Feed, transport http.RoundTripper) (*http.Client, error) {
+	useProxy := feed.UseProxy && feed.Proxy != nil
+
+	if !useProxy && !feed.Identity.IsSet() {
+		return nil, nil
+	}
+
+	timeout := time.Duration(feed.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = time.Second * 60
+	}
+
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	if useProxy {
+		proxyClient, err := proxy.GetProxiedHTTPClient(feed.Proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get proxy client")
+		}
+
+		client = proxyClient
+	}
+
+	if feed.Identity.IsSet() {
+		identityTransport, err := domain.NewIdentityTransport(client.Transport, feed.Identity, feed.URL)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not setup identity for feed: %s", feed.Name)
+		}
+
+		client.Transport = identityTransport
+	}
+
+	return client, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/notification"
+)
+
+// feedHealth tracks the feeds which failed their last run, so a failing feed is notified once
+// instead of on every run until it recovers. It is kept by the service since jobs are recreated on updates.
+type feedHealth struct {
+	notificationSvc notification.Service
+
+	m       sync.Mutex
+	failing map[int]struct{}
+}
+
+func newFeedHealth(notificationSvc notification.Service) *feedHealth {
+	return &feedHealth{
+		notificationSvc: notificationSvc,
+		failing:         map[int]struct{}{},
+	}
+}
+
+// report records the result of a run of the feed and sends a notification when it starts failing
+func (h *feedHealth) report(feed *domain.Feed, err error) {
+	if h == nil || feed == nil {
+		return
+	}
+
+	h.m.Lock()
+	_, wasFailing := h.failing[feed.ID]
+	if err == nil {
+		delete(h.failing, feed.ID)
+	} else {
+		h.failing[feed.ID] = struct{}{}
+	}
+	h.m.Unlock()
+
+	if err == nil || wasFailing || h.notificationSvc == nil {
+		return
+	}
+
+	h.notificationSvc.Send(domain.NotificationEventFeedError, domain.NotificationPayload{
+		Subject:   "Feed Error",
+		Message:   fmt.Sprintf("Feed: %s Error: %s", feed.Name, err.Error()),
+		Indexer:   feed.Indexer.Name,
+		Timestamp: time.Now(),
+	})
+}
+
+// forget removes the feed, e.g. when it is disabled or deleted
+func (h *feedHealth) forget(id int) {
+	if h == nil {
+		return
+	}
+
+	h.m.Lock()
+	delete(h.failing, id)
+	h.m.Unlock()
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/notification"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNotificationService struct {
+	notification.Service
+
+	sent []domain.NotificationPayload
+}
+
+func (m *mockNotificationService) Send(event domain.NotificationEvent, payload domain.NotificationPayload) {
+	payload.Event = event
+	m.sent = append(m.sent, payload)
+}
+
+func TestFeedHealth_report(t *testing.T) {
+	notifications := &mockNotificationService{}
+	health := newFeedHealth(notifications)
+
+	feed := &domain.Feed{ID: 1, Name: "Mock Feed", Indexer: domain.IndexerMinimal{Name: "Mock Indexer"}}
+	other := &domain.Feed{ID: 2, Name: "Other Feed"}
+
+	health.report(feed, nil)
+	assert.Empty(t, notifications.sent)
+
+	// only the first error of a failing feed is notified
+	health.report(feed, errors.New("unexpected status: 503"))
+	health.report(feed, errors.New("unexpected status: 503"))
+	health.report(other, errors.New("timeout"))
+
+	if assert.Len(t, notifications.sent, 2) {
+		assert.Equal(t, domain.NotificationEventFeedError, notifications.sent[0].Event)
+		assert.Equal(t, "Feed: Mock Feed Error: unexpected status: 503", notifications.sent[0].Message)
+		assert.Equal(t, "Mock Indexer", notifications.sent[0].Indexer)
+	}
+
+	// a recovered feed is notified again when it fails the next time
+	health.report(feed, nil)
+	health.report(feed, errors.New("timeout"))
+	assert.Len(t, notifications.sent, 3)
+
+	// forgotten feeds start over
+	health.forget(other.ID)
+	health.report(other, errors.New("timeout"))
+	assert.Len(t, notifications.sent, 4)
+
+	// jobs created without health tracking
+	var none *feedHealth
+	none.report(feed, errors.New("timeout"))
+	none.forget(feed.ID)
+}
@@ -10,11 +10,12 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
-	"github.com/autobrr/autobrr/internal/proxy"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/newznab"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 
 	"github.com/rs/zerolog"
 )
@@ -32,11 +33,12 @@ type NewznabJob struct {
 
 	attempts int
 	errors   []error
+	health   *feedHealth
 
 	JobID int
 }
 
-func NewNewznabJob(feed *domain.Feed, name string, log zerolog.Logger, url string, client newznab.Client, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service) FeedJob {
+func NewNewznabJob(feed *domain.Feed, name string, log zerolog.Logger, url string, client newznab.Client, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, health *feedHealth) FeedJob {
 	return &NewznabJob{
 		Feed:       feed,
 		Name:       name,
@@ -46,13 +48,17 @@ func NewNewznabJob(feed *domain.Feed, name string, log zerolog.Logger, url strin
 		Repo:       repo,
 		CacheRepo:  cacheRepo,
 		ReleaseSvc: releaseSvc,
+		health:     health,
 	}
 }
 
 func (j *NewznabJob) Run() {
 	ctx := context.Background()
 
-	if err := j.RunE(ctx); err != nil {
+	err := j.RunE(ctx)
+	j.health.report(j.Feed, err)
+
+	if err != nil {
 		j.Log.Err(err).Int("attempts", j.attempts).Msg("newznab process error")
 
 		j.errors = append(j.errors, err)
@@ -73,7 +79,9 @@ func (j *NewznabJob) RunE(ctx context.Context) error {
 
 func (j *NewznabJob) process(ctx context.Context) error {
 	// get feed
+	start := time.Now()
 	items, err := j.getFeed(ctx)
+	metrics.FeedFetched(j.Feed, time.Since(start), err)
 	if err != nil {
 		j.Log.Error().Err(err).Msgf("error fetching feed items")
 		return errors.Wrap(err, "error getting feed items")
@@ -130,15 +138,17 @@ func (j *NewznabJob) process(ctx context.Context) error {
 }
 
 func (j *NewznabJob) getFeed(ctx context.Context) ([]newznab.FeedItem, error) {
-	// add proxy if enabled and exists
-	if j.Feed.UseProxy && j.Feed.Proxy != nil {
-		proxyClient, err := proxy.GetProxiedHTTPClient(j.Feed.Proxy)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not get proxy client")
-		}
+	// add proxy and request identity of the indexer if set
+	httpClient, err := feedHTTPClient(j.Feed, sharedhttp.Transport)
+	if err != nil {
+		return nil, err
+	}
 
-		j.Client.WithHTTPClient(proxyClient)
+	if httpClient != nil {
+		j.Client.WithHTTPClient(httpClient)
+	}
 
+	if j.Feed.UseProxy && j.Feed.Proxy != nil {
 		j.Log.Debug().Msgf("using proxy %s for feed %s", j.Feed.Proxy.Name, j.Feed.Name)
 	}
 
@@ -13,9 +13,10 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
-	"github.com/autobrr/autobrr/internal/proxy"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 
 	"github.com/dustin/go-humanize"
 	"github.com/mmcdole/gofeed"
@@ -40,11 +41,12 @@ type RSSJob struct {
 
 	attempts int
 	errors   []error
+	health   *feedHealth
 
 	JobID int
 }
 
-func NewRSSJob(feed *domain.Feed, name string, log zerolog.Logger, url string, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, timeout time.Duration) FeedJob {
+func NewRSSJob(feed *domain.Feed, name string, log zerolog.Logger, url string, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, timeout time.Duration, health *feedHealth) FeedJob {
 	return &RSSJob{
 		Feed:       feed,
 		Name:       name,
@@ -54,13 +56,17 @@ func NewRSSJob(feed *domain.Feed, name string, log zerolog.Logger, url string, r
 		CacheRepo:  cacheRepo,
 		ReleaseSvc: releaseSvc,
 		Timeout:    timeout,
+		health:     health,
 	}
 }
 
 func (j *RSSJob) Run() {
 	ctx := context.Background()
 
-	if err := j.RunE(ctx); err != nil {
+	err := j.RunE(ctx)
+	j.health.report(j.Feed, err)
+
+	if err != nil {
 		j.Log.Err(err).Int("attempts", j.attempts).Msg("rss feed process error")
 
 		j.errors = append(j.errors, err)
@@ -80,7 +86,9 @@ func (j *RSSJob) RunE(ctx context.Context) error {
 }
 
 func (j *RSSJob) process(ctx context.Context) error {
+	start := time.Now()
 	items, err := j.getFeed(ctx)
+	metrics.FeedFetched(j.Feed, time.Since(start), err)
 	if err != nil {
 		j.Log.Error().Err(err).Msgf("error fetching rss feed items")
 		return errors.Wrap(err, "error getting rss feed items")
@@ -249,14 +257,17 @@ func (j *RSSJob) getFeed(ctx context.Context) (items []*gofeed.Item, err error)
 
 	feedParser := NewFeedParser(j.Timeout, j.Feed.Cookie)
 
-	if j.Feed.UseProxy && j.Feed.Proxy != nil {
-		proxyClient, err := proxy.GetProxiedHTTPClient(j.Feed.Proxy)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not get proxy client")
-		}
+	// add proxy and request identity of the indexer if set
+	httpClient, err := feedHTTPClient(j.Feed, sharedhttp.TransportTLSInsecure)
+	if err != nil {
+		return nil, err
+	}
 
-		feedParser.WithHTTPClient(proxyClient)
+	if httpClient != nil {
+		feedParser.WithHTTPClient(httpClient)
+	}
 
+	if j.Feed.UseProxy && j.Feed.Proxy != nil {
 		j.Log.Debug().Msgf("using proxy %s for feed %s", j.Feed.Proxy.Name, j.Feed.Name)
 	}
 
@@ -11,11 +11,13 @@ import (
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/proxy"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/newznab"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 	"github.com/autobrr/autobrr/pkg/torznab"
 
 	"github.com/dcarbone/zadapters/zstdlog"
@@ -71,9 +73,10 @@ type service struct {
 	releaseSvc release.Service
 	proxySvc   proxy.Service
 	scheduler  scheduler.Service
+	health     *feedHealth
 }
 
-func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, proxySvc proxy.Service, scheduler scheduler.Service) Service {
+func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, proxySvc proxy.Service, scheduler scheduler.Service, notificationSvc notification.Service) Service {
 	return &service{
 		log:        log.With().Str("module", "feed").Logger(),
 		jobs:       map[string]int{},
@@ -82,6 +85,7 @@ func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCa
 		releaseSvc: releaseSvc,
 		proxySvc:   proxySvc,
 		scheduler:  scheduler,
+		health:     newFeedHealth(notificationSvc),
 	}
 }
 
@@ -278,15 +282,17 @@ func (s *service) test(ctx context.Context, feed *domain.Feed) error {
 func (s *service) testRSS(ctx context.Context, feed *domain.Feed) error {
 	feedParser := NewFeedParser(time.Duration(feed.Timeout)*time.Second, feed.Cookie)
 
-	// add proxy if enabled and exists
-	if feed.UseProxy && feed.Proxy != nil {
-		proxyClient, err := proxy.GetProxiedHTTPClient(feed.Proxy)
-		if err != nil {
-			return errors.Wrap(err, "could not get proxy client")
-		}
+	// add proxy and request identity of the indexer if set
+	httpClient, err := feedHTTPClient(feed, sharedhttp.TransportTLSInsecure)
+	if err != nil {
+		return err
+	}
 
-		feedParser.WithHTTPClient(proxyClient)
+	if httpClient != nil {
+		feedParser.WithHTTPClient(httpClient)
+	}
 
+	if feed.UseProxy && feed.Proxy != nil {
 		s.log.Debug().Msgf("using proxy %s for feed %s", feed.Proxy.Name, feed.Name)
 	}
 
@@ -305,15 +311,17 @@ func (s *service) testTorznab(ctx context.Context, feed *domain.Feed, subLogger
 	// setup torznab Client
 	c := torznab.NewClient(torznab.Config{Host: feed.URL, ApiKey: feed.ApiKey, Log: subLogger})
 
-	// add proxy if enabled and exists
-	if feed.UseProxy && feed.Proxy != nil {
-		proxyClient, err := proxy.GetProxiedHTTPClient(feed.Proxy)
-		if err != nil {
-			return errors.Wrap(err, "could not get proxy client")
-		}
+	// add proxy and request identity of the indexer if set
+	httpClient, err := feedHTTPClient(feed, sharedhttp.Transport)
+	if err != nil {
+		return err
+	}
 
-		c.WithHTTPClient(proxyClient)
+	if httpClient != nil {
+		c.WithHTTPClient(httpClient)
+	}
 
+	if feed.UseProxy && feed.Proxy != nil {
 		s.log.Debug().Msgf("using proxy %s for feed %s", feed.Proxy.Name, feed.Name)
 	}
 
@@ -332,15 +340,17 @@ func (s *service) testNewznab(ctx context.Context, feed *domain.Feed, subLogger
 	// setup newznab Client
 	c := newznab.NewClient(newznab.Config{Host: feed.URL, ApiKey: feed.ApiKey, Log: subLogger})
 
-	// add proxy if enabled and exists
-	if feed.UseProxy && feed.Proxy != nil {
-		proxyClient, err := proxy.GetProxiedHTTPClient(feed.Proxy)
-		if err != nil {
-			return errors.Wrap(err, "could not get proxy client")
-		}
+	// add proxy and request identity of the indexer if set
+	httpClient, err := feedHTTPClient(feed, sharedhttp.Transport)
+	if err != nil {
+		return err
+	}
 
-		c.WithHTTPClient(proxyClient)
+	if httpClient != nil {
+		c.WithHTTPClient(httpClient)
+	}
 
+	if feed.UseProxy && feed.Proxy != nil {
 		s.log.Debug().Msgf("using proxy %s for feed %s", feed.Proxy.Name, feed.Name)
 	}
 
@@ -530,7 +540,7 @@ func (s *service) createTorznabJob(f feedInstance) (FeedJob, error) {
 	client := torznab.NewClient(torznab.Config{Host: f.URL, ApiKey: f.ApiKey, Timeout: f.Timeout})
 
 	// create job
-	job := NewTorznabJob(f.Feed, f.Name, l, f.URL, client, s.repo, s.cacheRepo, s.releaseSvc)
+	job := NewTorznabJob(f.Feed, f.Name, l, f.URL, client, s.repo, s.cacheRepo, s.releaseSvc, s.health)
 
 	return job, nil
 }
@@ -549,7 +559,7 @@ func (s *service) createNewznabJob(f feedInstance) (FeedJob, error) {
 	client := newznab.NewClient(newznab.Config{Host: f.URL, ApiKey: f.ApiKey, Timeout: f.Timeout})
 
 	// create job
-	job := NewNewznabJob(f.Feed, f.Name, l, f.URL, client, s.repo, s.cacheRepo, s.releaseSvc)
+	job := NewNewznabJob(f.Feed, f.Name, l, f.URL, client, s.repo, s.cacheRepo, s.releaseSvc, s.health)
 
 	return job, nil
 }
@@ -569,7 +579,7 @@ func (s *service) createRSSJob(f feedInstance) (FeedJob, error) {
 	l := s.log.With().Str("feed", f.Name).Logger()
 
 	// create job
-	job := NewRSSJob(f.Feed, f.Name, l, f.URL, s.repo, s.cacheRepo, s.releaseSvc, f.Timeout)
+	job := NewRSSJob(f.Feed, f.Name, l, f.URL, s.repo, s.cacheRepo, s.releaseSvc, f.Timeout, s.health)
 
 	return job, nil
 }
@@ -601,6 +611,8 @@ func (s *service) stopFeedJob(id int) error {
 		return errors.Wrap(err, "stop job failed")
 	}
 
+	s.health.forget(id)
+
 	s.log.Debug().Msgf("stop feed job: %d", id)
 
 	return nil
@@ -5,16 +5,17 @@ package feed
 
 import (
 	"context"
-	"github.com/autobrr/autobrr/internal/proxy"
 	"math"
 	"sort"
 	"strconv"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 	"github.com/autobrr/autobrr/pkg/torznab"
 
 	"github.com/rs/zerolog"
@@ -33,6 +34,7 @@ type TorznabJob struct {
 
 	attempts int
 	errors   []error
+	health   *feedHealth
 
 	JobID int
 }
@@ -42,7 +44,7 @@ type FeedJob interface {
 	RunE(ctx context.Context) error
 }
 
-func NewTorznabJob(feed *domain.Feed, name string, log zerolog.Logger, url string, client torznab.Client, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service) FeedJob {
+func NewTorznabJob(feed *domain.Feed, name string, log zerolog.Logger, url string, client torznab.Client, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, health *feedHealth) FeedJob {
 	return &TorznabJob{
 		Feed:       feed,
 		Name:       name,
@@ -52,13 +54,17 @@ func NewTorznabJob(feed *domain.Feed, name string, log zerolog.Logger, url strin
 		Repo:       repo,
 		CacheRepo:  cacheRepo,
 		ReleaseSvc: releaseSvc,
+		health:     health,
 	}
 }
 
 func (j *TorznabJob) Run() {
 	ctx := context.Background()
 
-	if err := j.RunE(ctx); err != nil {
+	err := j.RunE(ctx)
+	j.health.report(j.Feed, err)
+
+	if err != nil {
 		j.Log.Err(err).Int("attempts", j.attempts).Msg("torznab process error")
 
 		j.errors = append(j.errors, err)
@@ -79,7 +85,9 @@ func (j *TorznabJob) RunE(ctx context.Context) error {
 
 func (j *TorznabJob) process(ctx context.Context) error {
 	// get feed
+	start := time.Now()
 	items, err := j.getFeed(ctx)
+	metrics.FeedFetched(j.Feed, time.Since(start), err)
 	if err != nil {
 		j.Log.Error().Err(err).Msgf("error fetching feed items")
 		return errors.Wrap(err, "error getting feed items")
@@ -225,15 +233,17 @@ func mapFreeleechToBonus(percentage int) string {
 }
 
 func (j *TorznabJob) getFeed(ctx context.Context) ([]torznab.FeedItem, error) {
-	// add proxy if enabled and exists
-	if j.Feed.UseProxy && j.Feed.Proxy != nil {
-		proxyClient, err := proxy.GetProxiedHTTPClient(j.Feed.Proxy)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not get proxy client")
-		}
+	// add proxy and request identity of the indexer if set
+	httpClient, err := feedHTTPClient(j.Feed, sharedhttp.Transport)
+	if err != nil {
+		return nil, err
+	}
 
-		j.Client.WithHTTPClient(proxyClient)
+	if httpClient != nil {
+		j.Client.WithHTTPClient(httpClient)
+	}
 
+	if j.Feed.UseProxy && j.Feed.Proxy != nil {
 		j.Log.Debug().Msgf("using proxy %s for feed %s", j.Feed.Proxy.Name, j.Feed.Name)
 	}
 
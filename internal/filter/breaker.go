@@ -0,0 +1,145 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// breakerKey separates the breakers of the pipeline stages, a failing indexer api does not bypass the external checks
+type breakerKey struct {
+	filterID int
+	stage    domain.PipelineStage
+}
+
+// breakers keeps the circuit breaker state of the guarded checks by filter id and stage
+type breakers struct {
+	mu    sync.Mutex
+	state map[breakerKey]*domain.FilterBreaker
+}
+
+func newBreakers() *breakers {
+	return &breakers{
+		state: map[breakerKey]*domain.FilterBreaker{},
+	}
+}
+
+// get returns a copy of the breaker state by stage for filterID, nil if no breaker ran
+func (b *breakers) get(filterID int) domain.FilterBreakers {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var states domain.FilterBreakers
+	for key, breaker := range b.state {
+		if key.filterID != filterID {
+			continue
+		}
+
+		if states == nil {
+			states = domain.FilterBreakers{}
+		}
+
+		state := *breaker
+		states[key.stage] = &state
+	}
+
+	return states
+}
+
+func (b *breakers) reset(filterID int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key := range b.state {
+		if key.filterID == filterID {
+			delete(b.state, key)
+		}
+	}
+}
+
+// guardedCheck runs check behind the circuit breaker of the filter. When the breaker is open, or the check fails
+// with the breaker enabled, the filter passes when it fails open and is rejected otherwise.
+func (s *service) guardedCheck(f *domain.Filter, stage domain.PipelineStage, check func() (bool, error)) (bool, error) {
+	if f.BreakerThreshold <= 0 {
+		return check()
+	}
+
+	cooldown := f.BreakerCooldownDuration()
+
+	key := breakerKey{filterID: f.ID, stage: stage}
+
+	s.breakers.mu.Lock()
+	breaker, ok := s.breakers.state[key]
+	if !ok {
+		breaker = domain.NewFilterBreaker()
+		s.breakers.state[key] = breaker
+	}
+
+	allowed := breaker.Allow(time.Now(), cooldown)
+	if !allowed {
+		breaker.Bypassed++
+	}
+	openUntil := breaker.OpenUntil
+	s.breakers.mu.Unlock()
+
+	if !allowed {
+		return s.breakerFallback(f, stage, "circuit breaker open until %s", openUntil.Format(time.RFC3339))
+	}
+
+	ok, err := check()
+
+	s.breakers.mu.Lock()
+	if err != nil {
+		if breaker.Failure(time.Now(), err, f.BreakerThreshold, cooldown) {
+			s.log.Warn().Err(err).Msgf("filter %s: circuit breaker opened after %d failed checks, bypassing %s checks for %s", f.Name, breaker.Failures, stage, cooldown)
+		}
+	} else {
+		breaker.Success()
+	}
+	s.breakers.mu.Unlock()
+
+	if err != nil {
+		s.log.Error().Err(err).Msgf("filter %s: %s check failed", f.Name, stage)
+		return s.breakerFallback(f, stage, "check failed: %v", err)
+	}
+
+	return ok, nil
+}
+
+func (s *service) breakerFallback(f *domain.Filter, stage domain.PipelineStage, reason string, args ...any) (bool, error) {
+	if f.BreakerFailOpen {
+		s.log.Debug().Msgf("filter %s: skipping %s check, failing open: "+reason, append([]any{f.Name, stage}, args...)...)
+		return true, nil
+	}
+
+	f.AddRejectionF("%s check unavailable: "+reason, append([]any{stage}, args...)...)
+	return false, nil
+}
+
+// hasEnabledExternal reports whether the external stage makes any calls, so only real checks move the breaker
+func hasEnabledExternal(f *domain.Filter) bool {
+	if f.SonarrCheckClientID > 0 || f.RadarrCheckClientID > 0 || f.LidarrCheckClientID > 0 || f.LibraryCheckClientID > 0 {
+		return true
+	}
+
+	for _, external := range f.External {
+		if external.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookServerError turns an unexpected 5xx response into an error, so a failing webhook counts towards the breaker
+func webhookServerError(expected, status int) error {
+	if status >= http.StatusInternalServerError && status != expected {
+		return errors.New("webhook server error. got: %d", status)
+	}
+	return nil
+}
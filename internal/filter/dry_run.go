@@ -0,0 +1,94 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// DryRun replays the latest stored releases through a saved or unsaved filter.
+// No actions are run and nothing is stored.
+func (s *service) DryRun(ctx context.Context, req domain.FilterDryRunRequest) (*domain.FilterDryRunResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	f, err := s.dryRunFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = domain.FilterDryRunDefaultLimit
+	}
+
+	releases, err := s.releaseRepo.Find(ctx, domain.ReleaseQueryParams{Limit: uint64(limit)})
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not find releases for dry run")
+		return nil, err
+	}
+
+	resp := &domain.FilterDryRunResponse{
+		FilterID:   f.ID,
+		FilterName: f.Name,
+		Results:    make([]domain.FilterDryRunResult, 0, len(releases.Data)),
+	}
+
+	for _, release := range releases.Data {
+		result := f.DryRun(release)
+		if result.Match {
+			resp.Matched++
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	resp.Checked = len(resp.Results)
+
+	s.log.Debug().Msgf("filter dry run %q: %d of %d releases matched", f.Name, resp.Matched, resp.Checked)
+
+	return resp, nil
+}
+
+// dryRunFilter returns the unsaved filter of the request, or the saved filter with its download counts
+func (s *service) dryRunFilter(ctx context.Context, req domain.FilterDryRunRequest) (*domain.Filter, error) {
+	if req.Filter != nil {
+		f := req.Filter
+		if f.Name == "" {
+			f.Name = "unsaved filter"
+		}
+
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+
+		if err := f.Sanitize(); err != nil {
+			return nil, errors.Wrap(err, "could not sanitize filter")
+		}
+
+		// an unsaved filter has not downloaded anything yet
+		f.Downloads = &domain.FilterDownloads{}
+
+		return f, nil
+	}
+
+	f, err := s.FindByID(ctx, req.FilterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.HasDownloadLimits() {
+		downloads, err := s.repo.GetDownloadsByFilterId(ctx, f.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get download counts for filter: %d", f.ID)
+		}
+		f.Downloads = downloads
+	}
+
+	return f, nil
+}
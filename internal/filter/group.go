@@ -0,0 +1,122 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+func (s *service) ListGroups(ctx context.Context) ([]domain.FilterGroup, error) {
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not list filter groups")
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (s *service) FindGroupByID(ctx context.Context, groupID int) (*domain.FilterGroup, error) {
+	group, err := s.groupRepo.FindByID(ctx, groupID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find filter group: %v", groupID)
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *service) StoreGroup(ctx context.Context, group *domain.FilterGroup) error {
+	if err := group.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.groupRepo.Store(ctx, group); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter group: %s", group.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateGroup(ctx context.Context, group *domain.FilterGroup) error {
+	if err := group.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.groupRepo.Update(ctx, group); err != nil {
+		s.log.Error().Err(err).Msgf("could not update filter group: %v", group.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ToggleGroupEnabled(ctx context.Context, groupID int, enabled bool) error {
+	if err := s.groupRepo.ToggleEnabled(ctx, groupID, enabled); err != nil {
+		s.log.Error().Err(err).Msgf("could not toggle filter group: %v", groupID)
+		return err
+	}
+
+	s.log.Debug().Msgf("filter group %v enabled: %v", groupID, enabled)
+
+	return nil
+}
+
+// DeleteGroup removes the group, the filters of the group are kept without a group
+func (s *service) DeleteGroup(ctx context.Context, groupID int) error {
+	if err := s.groupRepo.Delete(ctx, groupID); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete filter group: %v", groupID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) MoveToGroup(ctx context.Context, req domain.FilterGroupMoveRequest) error {
+	if len(req.FilterIDs) == 0 {
+		return domain.ValidationErrors{{Field: "filter_ids", Message: "no filters selected"}}
+	}
+
+	if err := s.validateGroup(ctx, req.GroupID); err != nil {
+		return err
+	}
+
+	filterIDs := slices.Clone(req.FilterIDs)
+	slices.Sort(filterIDs)
+	filterIDs = slices.Compact(filterIDs)
+
+	if err := s.groupRepo.MoveFilters(ctx, req.GroupID, filterIDs); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			return errors.Wrap(err, "one or more filters not found")
+		}
+
+		s.log.Error().Err(err).Msgf("could not move filters %v to group: %v", filterIDs, req.GroupID)
+		return err
+	}
+
+	return nil
+}
+
+// validateGroup makes sure the group of a filter exists, group id 0 is no group
+func (s *service) validateGroup(ctx context.Context, groupID int) error {
+	if groupID == 0 {
+		return nil
+	}
+
+	if _, err := s.groupRepo.FindByID(ctx, groupID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			return domain.ValidationErrors{{Field: "group_id", Message: fmt.Sprintf("filter group %d not found", groupID)}}
+		}
+
+		return err
+	}
+
+	return nil
+}
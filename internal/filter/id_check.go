@@ -0,0 +1,138 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/tmdb"
+
+	"github.com/dcarbone/zadapters/zstdlog"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/rs/zerolog"
+)
+
+// mediaIDsTTL is how long resolved ids are kept, titles rarely move to another movie or show
+const mediaIDsTTL = 24 * time.Hour
+
+// mediaIDsCache keeps the resolved ids by kind, normalized title and year, titles that could not be resolved are cached as empty ids
+type mediaIDsCache struct {
+	cache *ttlcache.Cache[string, domain.MediaIDs]
+}
+
+func newMediaIDsCache() *mediaIDsCache {
+	c := &mediaIDsCache{
+		cache: ttlcache.New[string, domain.MediaIDs](
+			ttlcache.WithTTL[string, domain.MediaIDs](mediaIDsTTL),
+		),
+	}
+
+	go c.cache.Start()
+
+	return c
+}
+
+func newTMDBClient(log logger.Logger, config *domain.Config) tmdb.Client {
+	if config == nil || config.TMDB.APIKey == "" {
+		return nil
+	}
+
+	return tmdb.New(tmdb.Config{
+		APIKey: config.TMDB.APIKey,
+		Log:    zstdlog.NewStdLoggerWithLevel(log.With().Str("module", "tmdb").Logger(), zerolog.TraceLevel),
+	})
+}
+
+// isEpisodic tells if the release is of a show, the ids are looked up as tv then
+func isEpisodic(release *domain.Release) bool {
+	return release.Season > 0 || release.Episode > 0 || (release.Month > 0 && release.Day > 0)
+}
+
+func mediaIDsKey(release *domain.Release) string {
+	kind := "movie"
+	if isEpisodic(release) {
+		kind = "tv"
+	}
+
+	return fmt.Sprintf("%s/%s/%d", kind, normalizeTitle(release.Title), release.Year)
+}
+
+// idCheck rejects the release when none of the ids of its movie or show is in the match ids of the filter
+func (s *service) idCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	if release.MediaIDs == nil {
+		ids, err := s.resolveMediaIDs(ctx, release)
+		if err != nil {
+			return false, err
+		}
+
+		release.MediaIDs = &ids
+	}
+
+	if release.MediaIDs.IsEmpty() {
+		f.AddRejectionF("match ids: could not resolve ids of %q", release.Title)
+		return false, nil
+	}
+
+	return f.MatchMediaIDs(*release.MediaIDs), nil
+}
+
+// resolveMediaIDs looks up the tmdb, tvdb and imdb ids of the parsed title of the release, from the cache when it was resolved before
+func (s *service) resolveMediaIDs(ctx context.Context, release *domain.Release) (domain.MediaIDs, error) {
+	var ids domain.MediaIDs
+
+	if s.tmdb == nil {
+		return ids, errors.New("match ids need a tmdb api key in the config")
+	}
+
+	if release.Title == "" {
+		return ids, nil
+	}
+
+	key := mediaIDsKey(release)
+	if item := s.mediaIDs.cache.Get(key); item != nil {
+		return item.Value(), nil
+	}
+
+	var (
+		results []tmdb.SearchResult
+		err     error
+	)
+
+	if isEpisodic(release) {
+		results, err = s.tmdb.SearchTV(ctx, release.Title, release.Year)
+	} else {
+		results, err = s.tmdb.SearchMovie(ctx, release.Title, release.Year)
+	}
+	if err != nil {
+		return ids, errors.Wrap(err, "could not search tmdb for: %s", release.Title)
+	}
+
+	if len(results) > 0 {
+		ids.TMDB = results[0].ID
+
+		var external *tmdb.ExternalIDs
+		if isEpisodic(release) {
+			external, err = s.tmdb.TVExternalIDs(ctx, ids.TMDB)
+		} else {
+			external, err = s.tmdb.MovieExternalIDs(ctx, ids.TMDB)
+		}
+		if err != nil {
+			return ids, err
+		}
+
+		ids.TVDB = external.TVDBID
+		ids.IMDb = external.IMDbID
+	}
+
+	s.log.Debug().Msgf("id check: resolved %q to %s", release.Title, ids.String())
+
+	s.mediaIDs.cache.Set(key, ids, ttlcache.DefaultTTL)
+
+	return ids, nil
+}
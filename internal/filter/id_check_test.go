@@ -0,0 +1,98 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/tmdb"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_idCheck(t *testing.T) {
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		requests = append(requests, r.URL.Path)
+
+		switch r.URL.Path {
+		case "/3/search/tv":
+			if r.URL.Query().Get("query") != "Game of Thrones" {
+				_, _ = w.Write([]byte(`{"results":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"id":1399,"name":"Game of Thrones"}]}`))
+		case "/3/tv/1399/external_ids":
+			_, _ = w.Write([]byte(`{"imdb_id":"tt0944947","tvdb_id":121361}`))
+		case "/3/search/movie":
+			if r.URL.Query().Get("year") != "1999" {
+				_, _ = w.Write([]byte(`{"results":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"id":603,"title":"The Matrix"}]}`))
+		case "/3/movie/603/external_ids":
+			_, _ = w.Write([]byte(`{"imdb_id":"tt0133093"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{
+		log:      zerolog.Nop(),
+		mediaIDs: newMediaIDsCache(),
+		tmdb:     tmdb.New(tmdb.Config{Hostname: srv.URL, APIKey: "key"}),
+	}
+
+	tests := []struct {
+		name      string
+		matchIDs  string
+		release   *domain.Release
+		want      bool
+		rejection string
+	}{
+		{name: "tv_tvdb", matchIDs: "tvdb:121361", release: &domain.Release{Title: "Game of Thrones", Season: 1, Episode: 1}, want: true},
+		{name: "tv_cached", matchIDs: "tt0944947", release: &domain.Release{Title: "Game.of.Thrones", Season: 2}, want: true},
+		{name: "movie_imdb", matchIDs: "tt0133093", release: &domain.Release{Title: "The Matrix", Year: 1999}, want: true},
+		{name: "movie_other", matchIDs: "tt0944947", release: &domain.Release{Title: "The Matrix", Year: 1999}, rejection: "match ids: tmdb:603, imdb:tt0133093 not in match ids"},
+		{name: "unresolved", matchIDs: "tt0944947", release: &domain.Release{Title: "Unknown Show", Season: 1}, rejection: `match ids: could not resolve ids of "Unknown Show"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &domain.Filter{Name: "ids", MatchIDs: tt.matchIDs}
+
+			got, err := s.idCheck(context.Background(), f, tt.release)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+
+	// titles are only resolved once, the cache is keyed by the normalized title
+	assert.Equal(t, []string{
+		"/3/search/tv",
+		"/3/tv/1399/external_ids",
+		"/3/search/movie",
+		"/3/movie/603/external_ids",
+		"/3/search/tv",
+	}, requests)
+
+	s.tmdb = nil
+	_, err := s.idCheck(context.Background(), &domain.Filter{MatchIDs: "tt0944947"}, &domain.Release{Title: "Other"})
+	assert.EqualError(t, err, "match ids need a tmdb api key in the config")
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/jellyfin"
+	"github.com/autobrr/autobrr/pkg/plex"
+)
+
+// resolutionRank buckets a video height so slightly cropped encodes rank with their resolution, 0 is unknown
+func resolutionRank(height int) int {
+	switch {
+	case height >= 2000:
+		return 4
+	case height >= 1000:
+		return 3
+	case height >= 700:
+		return 2
+	case height > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// releaseResolutionRank ranks the parsed resolution of the release, eg. 1080p
+func releaseResolutionRank(resolution string) int {
+	height, _ := strconv.Atoi(strings.TrimSuffix(strings.ToLower(resolution), "p"))
+	return resolutionRank(height)
+}
+
+// plexResolutionRank ranks the video resolution plex reports, eg. sd, 720, 1080 or 4k
+func plexResolutionRank(resolution string) int {
+	switch strings.ToLower(resolution) {
+	case "4k":
+		return resolutionRank(2160)
+	case "sd":
+		return resolutionRank(480)
+	}
+
+	height, _ := strconv.Atoi(resolution)
+	return resolutionRank(height)
+}
+
+// jellyfinResolutionRank ranks the video size of a jellyfin item by width too, widescreen movies are cropped in height
+func jellyfinResolutionRank(width, height int) int {
+	return max(resolutionRank(height), resolutionRank(width*9/16))
+}
+
+// libraryCheck rejects the release when the plex or jellyfin client of the filter already has the movie or episode
+// at the same or a better resolution. Season packs are let through, the library can't tell if a season is complete.
+func (s *service) libraryCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	client, err := s.clientSvc.GetClient(ctx, f.LibraryCheckClientID)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get library client with id %d", f.LibraryCheckClientID)
+	}
+
+	if client.Type != domain.DownloadClientTypePlex && client.Type != domain.DownloadClientTypeJellyfin {
+		return false, errors.New("client %s is not a plex or jellyfin client", client.Name)
+	}
+
+	if !client.Enabled {
+		return false, errors.New("library client %s is disabled", client.Name)
+	}
+
+	if release.Title == "" {
+		f.AddRejectionF("library check: could not parse title of %s", release.TorrentName)
+		return false, nil
+	}
+
+	if release.Season > 0 && release.Episode == 0 {
+		s.log.Trace().Msgf("library check: season pack not checked: %s", release.TorrentName)
+		return true, nil
+	}
+
+	var rank int
+	var found bool
+
+	switch c := client.Client.(type) {
+	case plex.Client:
+		rank, found, err = plexLibraryRank(ctx, c, release)
+	case jellyfin.Client:
+		rank, found, err = jellyfinLibraryRank(ctx, c, release)
+	default:
+		return false, errors.New("could not get library client: %s", client.Name)
+	}
+
+	if err != nil {
+		return false, errors.Wrap(err, "could not search library of client: %s", client.Name)
+	}
+
+	if found && rank >= releaseResolutionRank(release.Resolution) {
+		f.AddRejectionF("library check: already in %s at equal or better quality: %s", client.Name, release.Title)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// plexLibraryRank returns the rank of the best copy of the movie or episode of the release in plex
+func plexLibraryRank(ctx context.Context, c plex.Client, release *domain.Release) (int, bool, error) {
+	items, err := c.Search(ctx, release.Title)
+	if err != nil {
+		return 0, false, err
+	}
+
+	itemType := "movie"
+	if release.Episode > 0 {
+		itemType = "show"
+	}
+
+	rank, found := 0, false
+	for _, item := range items {
+		if item.Type != itemType || normalizeTitle(item.Title) != normalizeTitle(release.Title) {
+			continue
+		}
+
+		if release.Year > 0 && item.Year > 0 && item.Year != release.Year {
+			continue
+		}
+
+		candidates := []plex.Metadata{item}
+		if itemType == "show" {
+			episodes, err := c.GetAllLeaves(ctx, item.RatingKey)
+			if err != nil {
+				return 0, false, err
+			}
+
+			candidates = candidates[:0]
+			for _, episode := range episodes {
+				if episode.ParentIndex == release.Season && episode.Index == release.Episode {
+					candidates = append(candidates, episode)
+				}
+			}
+		}
+
+		for _, candidate := range candidates {
+			found = true
+			for _, media := range candidate.Media {
+				rank = max(rank, plexResolutionRank(media.VideoResolution))
+			}
+		}
+	}
+
+	return rank, found, nil
+}
+
+// jellyfinLibraryRank returns the rank of the best copy of the movie or episode of the release in jellyfin
+func jellyfinLibraryRank(ctx context.Context, c jellyfin.Client, release *domain.Release) (int, bool, error) {
+	itemType := "Movie"
+	if release.Episode > 0 {
+		itemType = "Series"
+	}
+
+	items, err := c.SearchItems(ctx, release.Title, itemType)
+	if err != nil {
+		return 0, false, err
+	}
+
+	rank, found := 0, false
+	for _, item := range items {
+		if normalizeTitle(item.Name) != normalizeTitle(release.Title) {
+			continue
+		}
+
+		if release.Year > 0 && item.ProductionYear > 0 && item.ProductionYear != release.Year {
+			continue
+		}
+
+		candidates := []jellyfin.Item{item}
+		if itemType == "Series" {
+			episodes, err := c.GetEpisodes(ctx, item.ID, release.Season)
+			if err != nil {
+				return 0, false, err
+			}
+
+			candidates = candidates[:0]
+			for _, episode := range episodes {
+				if episode.ParentIndexNumber == release.Season && episode.IndexNumber == release.Episode {
+					candidates = append(candidates, episode)
+				}
+			}
+		}
+
+		for _, candidate := range candidates {
+			found = true
+			rank = max(rank, jellyfinResolutionRank(candidate.VideoSize()))
+		}
+	}
+
+	return rank, found, nil
+}
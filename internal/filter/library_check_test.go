@@ -0,0 +1,104 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/jellyfin"
+	"github.com/autobrr/autobrr/pkg/plex"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_libraryCheck(t *testing.T) {
+	plexSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hubs/search":
+			_, _ = w.Write([]byte(`{"MediaContainer":{"Hub":[
+				{"type":"movie","Metadata":[{"ratingKey":"1","type":"movie","title":"Movie Title","year":2024,"Media":[{"videoResolution":"1080"}]}]},
+				{"type":"show","Metadata":[{"ratingKey":"2","type":"show","title":"Show Name","year":2020}]}
+			]}}`))
+		case "/library/metadata/2/allLeaves":
+			_, _ = w.Write([]byte(`{"MediaContainer":{"Metadata":[{"ratingKey":"20","type":"episode","title":"Pilot","parentIndex":1,"index":1,"Media":[{"videoResolution":"720"}]}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer plexSrv.Close()
+
+	jellyfinSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Items":
+			switch r.URL.Query().Get("IncludeItemTypes") {
+			case "Movie":
+				// cropped widescreen 4k encode
+				_, _ = w.Write([]byte(`{"Items":[{"Id":"m1","Name":"Movie Title","Type":"Movie","ProductionYear":2024,"MediaSources":[{"MediaStreams":[{"Type":"Video","Width":3840,"Height":1600}]}]}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"Items":[{"Id":"s1","Name":"Show Name","Type":"Series","ProductionYear":2020}]}`))
+			}
+		case "/Shows/s1/Episodes":
+			_, _ = w.Write([]byte(`{"Items":[{"Id":"e1","Name":"Pilot","Type":"Episode","ParentIndexNumber":1,"IndexNumber":1,"MediaSources":[{"MediaStreams":[{"Type":"Video","Width":1920,"Height":1080}]}]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer jellyfinSrv.Close()
+
+	s := &service{log: zerolog.Nop(), clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+		1: {ID: 1, Name: "plex", Type: domain.DownloadClientTypePlex, Enabled: true, Client: plex.New(plex.Config{Hostname: plexSrv.URL})},
+		2: {ID: 2, Name: "jellyfin", Type: domain.DownloadClientTypeJellyfin, Enabled: true, Client: jellyfin.New(jellyfin.Config{Hostname: jellyfinSrv.URL})},
+		3: {ID: 3, Name: "sonarr", Type: domain.DownloadClientTypeSonarr, Enabled: true},
+		4: {ID: 4, Name: "plex-disabled", Type: domain.DownloadClientTypePlex, Enabled: false},
+	}}}
+
+	tests := []struct {
+		name      string
+		clientID  int32
+		title     string
+		want      bool
+		rejection string
+		wantErr   string
+	}{
+		{name: "plex_movie_same", clientID: 1, title: "Movie.Title.2024.1080p.BluRay.x264-GRP", rejection: "library check: already in plex at equal or better quality: Movie Title"},
+		{name: "plex_movie_lower", clientID: 1, title: "Movie.Title.2024.720p.BluRay.x264-GRP", rejection: "library check: already in plex at equal or better quality: Movie Title"},
+		{name: "plex_movie_upgrade", clientID: 1, title: "Movie.Title.2024.2160p.UHD.BluRay.x265-GRP", want: true},
+		{name: "plex_movie_other_year", clientID: 1, title: "Movie.Title.1984.1080p.BluRay.x264-GRP", want: true},
+		{name: "plex_episode_upgrade", clientID: 1, title: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", want: true},
+		{name: "plex_episode_same", clientID: 1, title: "Show.Name.S01E01.720p.WEB-DL.H264-GRP", rejection: "library check: already in plex at equal or better quality: Show Name"},
+		{name: "plex_episode_missing", clientID: 1, title: "Show.Name.S01E02.720p.WEB-DL.H264-GRP", want: true},
+		{name: "season_pack", clientID: 1, title: "Show.Name.S01.720p.WEB-DL.H264-GRP", want: true},
+		{name: "jellyfin_movie_cropped", clientID: 2, title: "Movie.Title.2024.2160p.UHD.BluRay.x265-GRP", rejection: "library check: already in jellyfin at equal or better quality: Movie Title"},
+		{name: "jellyfin_episode_same", clientID: 2, title: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", rejection: "library check: already in jellyfin at equal or better quality: Show Name"},
+		{name: "jellyfin_episode_upgrade", clientID: 2, title: "Show.Name.S01E01.2160p.WEB-DL.H265-GRP", want: true},
+		{name: "not_library", clientID: 3, title: "Movie.Title.2024.1080p.BluRay.x264-GRP", wantErr: "client sonarr is not a plex or jellyfin client"},
+		{name: "disabled", clientID: 4, title: "Movie.Title.2024.1080p.BluRay.x264-GRP", wantErr: "library client plex-disabled is disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &domain.Filter{Name: "media", LibraryCheckClientID: tt.clientID}
+			release := domain.NewRelease(domain.IndexerMinimal{Identifier: "mock"})
+			release.TorrentName = tt.title
+			release.ParseString(tt.title)
+
+			got, err := s.libraryCheck(context.Background(), f, release)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+}
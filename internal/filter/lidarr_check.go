@@ -0,0 +1,118 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/lidarr"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// wantedAlbumsTTL is how long the wanted list of a lidarr client is kept, music trackers announce too much to fetch it every time
+const wantedAlbumsTTL = 15 * time.Minute
+
+// wantedAlbumsCache keeps the wanted albums per lidarr client, keyed by normalized artist and album title
+type wantedAlbumsCache struct {
+	cache *ttlcache.Cache[int32, map[string]struct{}]
+}
+
+func newWantedAlbumsCache() *wantedAlbumsCache {
+	c := &wantedAlbumsCache{
+		cache: ttlcache.New[int32, map[string]struct{}](
+			ttlcache.WithTTL[int32, map[string]struct{}](wantedAlbumsTTL),
+		),
+	}
+
+	go c.cache.Start()
+
+	return c
+}
+
+// normalizeTitle drops case and separators so "Artist.Name" matches "Artist Name"
+func normalizeTitle(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// wantedAlbumKey drops case and separators so "Artist.Name-Album.Title" matches "Artist Name" and "Album Title"
+func wantedAlbumKey(artist, album string) string {
+	return normalizeTitle(artist) + "/" + normalizeTitle(album)
+}
+
+// lidarrCheck rejects the release when its album is not on the wanted list of the lidarr client of the filter,
+// so music trackers with a high announce volume only push albums lidarr is missing
+func (s *service) lidarrCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	if release.Artists == "" || release.Title == "" {
+		f.AddRejectionF("lidarr check: could not parse artist and album of %s", release.TorrentName)
+		return false, nil
+	}
+
+	wanted, err := s.lidarrWanted(ctx, f.LidarrCheckClientID)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := wanted[wantedAlbumKey(release.Artists, release.Title)]; !ok {
+		f.AddRejectionF("lidarr check: album not wanted: %s - %s", release.Artists, release.Title)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// lidarrWanted returns the wanted albums of the lidarr client, from the cache when it was fetched recently
+func (s *service) lidarrWanted(ctx context.Context, clientID int32) (map[string]struct{}, error) {
+	if item := s.wantedAlbums.cache.Get(clientID); item != nil {
+		return item.Value(), nil
+	}
+
+	client, err := s.clientSvc.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get lidarr client with id %d", clientID)
+	}
+
+	if client.Type != domain.DownloadClientTypeLidarr {
+		return nil, errors.New("client %s is not a lidarr client", client.Name)
+	}
+
+	if !client.Enabled {
+		return nil, errors.New("lidarr client %s is disabled", client.Name)
+	}
+
+	arr, ok := client.Client.(lidarr.Client)
+	if !ok {
+		return nil, errors.New("could not get lidarr client: %s", client.Name)
+	}
+
+	albums, err := arr.WantedMissing(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get wanted albums of lidarr client: %s", client.Name)
+	}
+
+	wanted := make(map[string]struct{}, len(albums))
+	for _, album := range albums {
+		if album.Artist == nil {
+			continue
+		}
+
+		wanted[wantedAlbumKey(album.Artist.ArtistName, album.Title)] = struct{}{}
+	}
+
+	s.log.Debug().Msgf("lidarr check: got %d wanted albums from %s", len(wanted), client.Name)
+
+	s.wantedAlbums.cache.Set(clientID, wanted, ttlcache.DefaultTTL)
+
+	return wanted, nil
+}
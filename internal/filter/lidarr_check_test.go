@@ -0,0 +1,88 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/lidarr"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_lidarrCheck(t *testing.T) {
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/wanted/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		requests = append(requests, r.URL.RawQuery)
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			_, _ = w.Write([]byte(`{"page":1,"totalRecords":3,"records":[{"id":1,"title":"Wanted Album","artist":{"artistName":"The Artist"}},{"id":2,"title":"No Artist"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"page":2,"totalRecords":3,"records":[{"id":3,"title":"Other: Album","artist":{"artistName":"Other Artist"}}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{
+		log:          zerolog.Nop(),
+		wantedAlbums: newWantedAlbumsCache(),
+		clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+			1: {ID: 1, Name: "lidarr", Type: domain.DownloadClientTypeLidarr, Enabled: true, Client: lidarr.New(lidarr.Config{Hostname: srv.URL})},
+			2: {ID: 2, Name: "lidarr-disabled", Type: domain.DownloadClientTypeLidarr, Enabled: false},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		clientID  int32
+		artist    string
+		album     string
+		want      bool
+		rejection string
+		wantErr   string
+	}{
+		{name: "wanted", clientID: 1, artist: "The Artist", album: "Wanted Album", want: true},
+		{name: "wanted_separators", clientID: 1, artist: "Other.Artist", album: "Other Album", want: true},
+		{name: "not_wanted", clientID: 1, artist: "The Artist", album: "Owned Album", rejection: "lidarr check: album not wanted: The Artist - Owned Album"},
+		{name: "not_parsed", clientID: 1, album: "Wanted Album", rejection: "lidarr check: could not parse artist and album of release"},
+		{name: "disabled", clientID: 2, artist: "The Artist", album: "Wanted Album", wantErr: "lidarr client lidarr-disabled is disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &domain.Filter{Name: "music", LidarrCheckClientID: tt.clientID}
+			release := &domain.Release{TorrentName: "release", Artists: tt.artist, Title: tt.album}
+
+			got, err := s.lidarrCheck(context.Background(), f, release)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+
+	// the wanted list is fetched once and cached for the other checks
+	assert.Equal(t, []string{
+		"includeArtist=true&monitored=true&page=1&pageSize=250",
+		"includeArtist=true&monitored=true&page=2&pageSize=250",
+	}, requests)
+}
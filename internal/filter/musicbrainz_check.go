@@ -0,0 +1,122 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/musicbrainz"
+
+	"github.com/dcarbone/zadapters/zstdlog"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/rs/zerolog"
+)
+
+// musicBrainzTTL is how long lookups are kept, musicbrainz allows one request per second and music trackers announce a lot
+const musicBrainzTTL = 7 * 24 * time.Hour
+
+// musicBrainzMinScore is the search score a result needs to be taken as the artist or album of the release
+const musicBrainzMinScore = 90
+
+// musicBrainzCache keeps the lookups by normalized artist and album title, releases that could not be found are cached as empty
+type musicBrainzCache struct {
+	cache *ttlcache.Cache[string, domain.MusicBrainzMatch]
+}
+
+func newMusicBrainzCache() *musicBrainzCache {
+	c := &musicBrainzCache{
+		cache: ttlcache.New[string, domain.MusicBrainzMatch](
+			ttlcache.WithTTL[string, domain.MusicBrainzMatch](musicBrainzTTL),
+		),
+	}
+
+	go c.cache.Start()
+
+	return c
+}
+
+func newMusicBrainzClient(log logger.Logger) musicbrainz.Client {
+	return musicbrainz.New(musicbrainz.Config{
+		Log: zstdlog.NewStdLoggerWithLevel(log.With().Str("module", "musicbrainz").Logger(), zerolog.TraceLevel),
+	})
+}
+
+// musicBrainzCheck rejects the release when neither its artist nor its album at musicbrainz is in the musicbrainz field of the filter
+func (s *service) musicBrainzCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	if release.Artists == "" {
+		f.AddRejectionF("match musicbrainz: could not parse artist of %s", release.TorrentName)
+		return false, nil
+	}
+
+	if release.MusicBrainz == nil {
+		match, err := s.lookupMusicBrainz(ctx, release)
+		if err != nil {
+			return false, err
+		}
+
+		release.MusicBrainz = &match
+	}
+
+	if release.MusicBrainz.IsEmpty() {
+		f.AddRejectionF("match musicbrainz: could not find %s - %s", release.Artists, release.Title)
+		return false, nil
+	}
+
+	return f.MatchMusicBrainzEntries(*release.MusicBrainz), nil
+}
+
+// lookupMusicBrainz finds the album of the release at musicbrainz, or only the artist when the album is unknown,
+// from the cache when it was looked up before
+func (s *service) lookupMusicBrainz(ctx context.Context, release *domain.Release) (domain.MusicBrainzMatch, error) {
+	var match domain.MusicBrainzMatch
+
+	key := wantedAlbumKey(release.Artists, release.Title)
+	if item := s.musicBrainzMatches.cache.Get(key); item != nil {
+		return item.Value(), nil
+	}
+
+	if release.Title != "" {
+		groups, err := s.musicBrainz.SearchReleaseGroups(ctx, release.Artists, release.Title)
+		if err != nil {
+			return match, errors.Wrap(err, "could not look up album at musicbrainz")
+		}
+
+		for _, group := range groups {
+			if group.Score < musicBrainzMinScore {
+				break
+			}
+
+			match.ReleaseGroupID = group.ID
+
+			if len(group.ArtistCredit) > 0 {
+				match.ArtistID = group.ArtistCredit[0].Artist.ID
+				match.ArtistName = group.ArtistCredit[0].Artist.Name
+			}
+
+			break
+		}
+	}
+
+	if match.ArtistID == "" {
+		artists, err := s.musicBrainz.SearchArtists(ctx, release.Artists)
+		if err != nil {
+			return match, errors.Wrap(err, "could not look up artist at musicbrainz")
+		}
+
+		if len(artists) > 0 && artists[0].Score >= musicBrainzMinScore {
+			match.ArtistID = artists[0].ID
+			match.ArtistName = artists[0].Name
+		}
+	}
+
+	s.log.Debug().Msgf("musicbrainz check: found %s - %s as %s", release.Artists, release.Title, match.String())
+
+	s.musicBrainzMatches.cache.Set(key, match, ttlcache.DefaultTTL)
+
+	return match, nil
+}
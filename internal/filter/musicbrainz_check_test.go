@@ -0,0 +1,86 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/musicbrainz"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_musicBrainzCheck(t *testing.T) {
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.Query().Get("query"))
+
+		switch r.URL.Path {
+		case "/ws/2/release-group":
+			if r.URL.Query().Get("query") == `releasegroup:"Discovery" AND artist:"Daft Punk"` {
+				_, _ = w.Write([]byte(`{"release-groups":[{"id":"48117b82-8cf5-3e4d-a4b5-0e2d8e4d4c15","title":"Discovery","score":100,"artist-credit":[{"name":"Daft Punk","artist":{"id":"056e4f3e-d505-4dad-8ec1-d04f521cbb56","name":"Daft Punk"}}]}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"release-groups":[{"id":"00000000-0000-0000-0000-000000000000","title":"Other","score":40}]}`))
+		case "/ws/2/artist":
+			if r.URL.Query().Get("query") == `artist:"Daft Punk"` {
+				_, _ = w.Write([]byte(`{"artists":[{"id":"056e4f3e-d505-4dad-8ec1-d04f521cbb56","name":"Daft Punk","score":100}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"artists":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{
+		log:                zerolog.Nop(),
+		musicBrainzMatches: newMusicBrainzCache(),
+		musicBrainz:        musicbrainz.New(musicbrainz.Config{Hostname: srv.URL}),
+	}
+
+	tests := []struct {
+		name      string
+		entries   string
+		artist    string
+		album     string
+		want      bool
+		rejection string
+	}{
+		{name: "album", entries: "48117b82-8cf5-3e4d-a4b5-0e2d8e4d4c15", artist: "Daft Punk", album: "Discovery", want: true},
+		{name: "artist_name_cached", entries: "daft punk", artist: "Daft.Punk", album: "Discovery", want: true},
+		{name: "artist_without_album", entries: "056e4f3e-d505-4dad-8ec1-d04f521cbb56", artist: "Daft Punk", album: "Unknown Album", want: true},
+		{name: "not_found", entries: "Daft Punk", artist: "Nobody", rejection: "match musicbrainz: could not find Nobody - "},
+		{name: "not_parsed", entries: "Daft Punk", rejection: "match musicbrainz: could not parse artist of release"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &domain.Filter{Name: "music", MatchMusicBrainz: tt.entries}
+			release := &domain.Release{TorrentName: "release", Artists: tt.artist, Title: tt.album}
+
+			got, err := s.musicBrainzCheck(context.Background(), f, release)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+
+	// lookups are cached by normalized artist and album
+	assert.Equal(t, []string{
+		`/ws/2/release-group?releasegroup:"Discovery" AND artist:"Daft Punk"`,
+		`/ws/2/release-group?releasegroup:"Unknown Album" AND artist:"Daft Punk"`,
+		`/ws/2/artist?artist:"Daft Punk"`,
+		`/ws/2/artist?artist:"Nobody"`,
+	}, requests)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/radarr"
+)
+
+// radarrCheck asks the radarr client of the filter if it wants the release, so pushes radarr would ignore are rejected early.
+// The movie is wanted when it's monitored, available and either missing or below the quality cutoff of its profile.
+func (s *service) radarrCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	client, err := s.clientSvc.GetClient(ctx, f.RadarrCheckClientID)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get radarr client with id %d", f.RadarrCheckClientID)
+	}
+
+	if client.Type != domain.DownloadClientTypeRadarr {
+		return false, errors.New("client %s is not a radarr client", client.Name)
+	}
+
+	if !client.Enabled {
+		return false, errors.New("radarr client %s is disabled", client.Name)
+	}
+
+	arr, ok := client.Client.(radarr.Client)
+	if !ok {
+		return false, errors.New("could not get radarr client: %s", client.Name)
+	}
+
+	parsed, err := arr.Parse(ctx, release.TorrentName)
+	if err != nil {
+		return false, errors.Wrap(err, "could not parse release with radarr client: %s", client.Name)
+	}
+
+	movie := parsed.Movie
+	if movie == nil {
+		f.AddRejectionF("radarr check: movie unknown to %s: %s (%d)", client.Name, release.Title, release.Year)
+		return false, nil
+	}
+
+	if !movie.Monitored {
+		f.AddRejectionF("radarr check: movie not monitored in %s: %s (%d)", client.Name, movie.Title, movie.Year)
+		return false, nil
+	}
+
+	if !movie.IsAvailable {
+		f.AddRejectionF("radarr check: movie not available yet in %s: %s (%d)", client.Name, movie.Title, movie.Year)
+		return false, nil
+	}
+
+	if !movie.HasFile {
+		return true, nil
+	}
+
+	files, err := arr.GetMovieFiles(ctx, movie.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get movie files with radarr client: %s", client.Name)
+	}
+
+	for _, file := range files {
+		if file.QualityCutoffNotMet {
+			return true, nil
+		}
+	}
+
+	f.AddRejectionF("radarr check: quality cutoff already met in %s: %s (%d)", client.Name, movie.Title, movie.Year)
+
+	return false, nil
+}
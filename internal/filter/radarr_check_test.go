@@ -0,0 +1,92 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/radarr"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_radarrCheck(t *testing.T) {
+	// parse responses by release title
+	parsed := map[string]string{
+		"Unknown.Movie.2024.1080p.WEB-DL.H264-GRP": `{}`,
+		"Unmonitored.2024.1080p.WEB-DL.H264-GRP":   `{"movie":{"id":1,"title":"Unmonitored","year":2024,"monitored":false,"isAvailable":true}}`,
+		"Unreleased.2024.1080p.WEB-DL.H264-GRP":    `{"movie":{"id":2,"title":"Unreleased","year":2024,"monitored":true,"isAvailable":false}}`,
+		"Missing.2024.1080p.WEB-DL.H264-GRP":       `{"movie":{"id":3,"title":"Missing","year":2024,"monitored":true,"isAvailable":true,"hasFile":false}}`,
+		"Upgrade.2024.1080p.WEB-DL.H264-GRP":       `{"movie":{"id":4,"title":"Upgrade","year":2024,"monitored":true,"isAvailable":true,"hasFile":true}}`,
+		"Cutoff.Met.2024.1080p.WEB-DL.H264-GRP":    `{"movie":{"id":5,"title":"Cutoff Met","year":2024,"monitored":true,"isAvailable":true,"hasFile":true}}`,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/parse":
+			_, _ = w.Write([]byte(parsed[r.URL.Query().Get("title")]))
+		case "/api/v3/moviefile":
+			switch r.URL.Query().Get("movieId") {
+			case "4":
+				_, _ = w.Write([]byte(`[{"id":10,"movieId":4,"qualityCutoffNotMet":true}]`))
+			case "5":
+				_, _ = w.Write([]byte(`[{"id":11,"movieId":5,"qualityCutoffNotMet":false}]`))
+			default:
+				_, _ = w.Write([]byte(`[]`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{log: zerolog.Nop(), clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+		1: {ID: 1, Name: "radarr", Type: domain.DownloadClientTypeRadarr, Enabled: true, Client: radarr.New(radarr.Config{Hostname: srv.URL})},
+		2: {ID: 2, Name: "sonarr", Type: domain.DownloadClientTypeSonarr, Enabled: true},
+	}}}
+
+	tests := []struct {
+		name      string
+		clientID  int32
+		title     string
+		want      bool
+		rejection string
+		wantErr   string
+	}{
+		{name: "unknown_movie", clientID: 1, title: "Unknown.Movie.2024.1080p.WEB-DL.H264-GRP", rejection: "radarr check: movie unknown to radarr: Unknown Movie (2024)"},
+		{name: "unmonitored", clientID: 1, title: "Unmonitored.2024.1080p.WEB-DL.H264-GRP", rejection: "radarr check: movie not monitored in radarr: Unmonitored (2024)"},
+		{name: "not_available", clientID: 1, title: "Unreleased.2024.1080p.WEB-DL.H264-GRP", rejection: "radarr check: movie not available yet in radarr: Unreleased (2024)"},
+		{name: "missing", clientID: 1, title: "Missing.2024.1080p.WEB-DL.H264-GRP", want: true},
+		{name: "upgrade", clientID: 1, title: "Upgrade.2024.1080p.WEB-DL.H264-GRP", want: true},
+		{name: "cutoff_met", clientID: 1, title: "Cutoff.Met.2024.1080p.WEB-DL.H264-GRP", rejection: "radarr check: quality cutoff already met in radarr: Cutoff Met (2024)"},
+		{name: "not_radarr", clientID: 2, title: "Missing.2024.1080p.WEB-DL.H264-GRP", wantErr: "client sonarr is not a radarr client"},
+		{name: "client_not_found", clientID: 3, title: "Missing.2024.1080p.WEB-DL.H264-GRP", wantErr: "could not get radarr client with id 3: client 3 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &domain.Filter{Name: "movies", RadarrCheckClientID: tt.clientID}
+			release := domain.NewRelease(domain.IndexerMinimal{Identifier: "mock"})
+			release.TorrentName = tt.title
+			release.ParseString(tt.title)
+
+			got, err := s.radarrCheck(context.Background(), f, release)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"os/exec"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,12 +18,16 @@ import (
 
 	"github.com/autobrr/autobrr/internal/action"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/releasedownload"
+	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/internal/utils"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/musicbrainz"
 	"github.com/autobrr/autobrr/pkg/sharedhttp"
+	"github.com/autobrr/autobrr/pkg/tmdb"
 
 	"github.com/avast/retry-go/v4"
 	"github.com/mattn/go-shellwords"
@@ -34,13 +39,29 @@ type Service interface {
 	FindByIndexerIdentifier(ctx context.Context, indexer string) ([]*domain.Filter, error)
 	Find(ctx context.Context, params domain.FilterQueryParams) ([]domain.Filter, error)
 	CheckFilter(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error)
+	Simulate(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, []domain.PipelineStage, error)
 	ListFilters(ctx context.Context) ([]domain.Filter, error)
 	Store(ctx context.Context, filter *domain.Filter) error
 	Update(ctx context.Context, filter *domain.Filter) error
 	UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error
 	Duplicate(ctx context.Context, filterID int) (*domain.Filter, error)
+	Clone(ctx context.Context, filterID int, req domain.FilterCloneRequest) (*domain.FilterCloneResponse, error)
+	BulkUpdate(ctx context.Context, req domain.FilterBulkUpdateRequest) ([]domain.FilterBulkUpdateResult, error)
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
 	Delete(ctx context.Context, filterID int) error
+	Restore(ctx context.Context, filterID int) error
+	ListTrashed(ctx context.Context) ([]domain.Filter, error)
+	Purge(ctx context.Context, filterID int) error
+	PurgeTrashed(ctx context.Context) (int, error)
+	ListGroups(ctx context.Context) ([]domain.FilterGroup, error)
+	FindGroupByID(ctx context.Context, groupID int) (*domain.FilterGroup, error)
+	StoreGroup(ctx context.Context, group *domain.FilterGroup) error
+	UpdateGroup(ctx context.Context, group *domain.FilterGroup) error
+	ToggleGroupEnabled(ctx context.Context, groupID int, enabled bool) error
+	DeleteGroup(ctx context.Context, groupID int) error
+	MoveToGroup(ctx context.Context, req domain.FilterGroupMoveRequest) error
+	DryRun(ctx context.Context, req domain.FilterDryRunRequest) (*domain.FilterDryRunResponse, error)
+	Start() error
 	AdditionalSizeCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error)
 	CheckSmartEpisodeCanDownload(ctx context.Context, params *domain.SmartEpisodeParams) (bool, error)
 	GetDownloadsByFilterId(ctx context.Context, filterID int) (*domain.FilterDownloads, error)
@@ -48,25 +69,57 @@ type Service interface {
 
 type service struct {
 	log           zerolog.Logger
+	config        *domain.Config
 	repo          domain.FilterRepo
+	groupRepo     domain.FilterGroupRepo
 	actionService action.Service
+	clientSvc     download_client.Service
 	releaseRepo   domain.ReleaseRepo
 	indexerSvc    indexer.Service
 	apiService    indexer.APIService
 	downloadSvc   *releasedownload.DownloadService
+	scheduler     scheduler.Service
+
+	// breakers guard the external checks of filters with a breaker threshold
+	breakers *breakers
+
+	// sizes resolved by the additional size check
+	sizes *sizeCache
+
+	// wanted albums of the lidarr clients used by the lidarr check
+	wantedAlbums *wantedAlbumsCache
+
+	// media ids resolved by the id check, tmdb is nil without an api key
+	mediaIDs *mediaIDsCache
+	tmdb     tmdb.Client
+
+	// artists and albums looked up by the musicbrainz check
+	musicBrainzMatches *musicBrainzCache
+	musicBrainz        musicbrainz.Client
 
 	httpClient *http.Client
 }
 
-func NewService(log logger.Logger, repo domain.FilterRepo, actionSvc action.Service, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service, downloadSvc *releasedownload.DownloadService) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.FilterRepo, groupRepo domain.FilterGroupRepo, actionSvc action.Service, clientSvc download_client.Service, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service, downloadSvc *releasedownload.DownloadService, scheduler scheduler.Service) Service {
 	return &service{
-		log:           log.With().Str("module", "filter").Logger(),
-		repo:          repo,
-		releaseRepo:   releaseRepo,
-		actionService: actionSvc,
-		apiService:    apiService,
-		indexerSvc:    indexerSvc,
-		downloadSvc:   downloadSvc,
+		log:                log.With().Str("module", "filter").Logger(),
+		config:             config,
+		repo:               repo,
+		groupRepo:          groupRepo,
+		releaseRepo:        releaseRepo,
+		actionService:      actionSvc,
+		clientSvc:          clientSvc,
+		apiService:         apiService,
+		indexerSvc:         indexerSvc,
+		downloadSvc:        downloadSvc,
+		scheduler:          scheduler,
+		breakers:           newBreakers(),
+		sizes:              newSizeCache(),
+		wantedAlbums:       newWantedAlbumsCache(),
+		mediaIDs:           newMediaIDsCache(),
+		tmdb:               newTMDBClient(log, config),
+		musicBrainzMatches: newMusicBrainzCache(),
+		musicBrainz:        newMusicBrainzClient(log),
 		httpClient: &http.Client{
 			Timeout:   time.Second * 120,
 			Transport: sharedhttp.TransportTLSInsecure,
@@ -90,6 +143,7 @@ func (s *service) Find(ctx context.Context, params domain.FilterQueryParams) ([]
 			return ret, err
 		}
 		filter.Indexers = indexers
+		filter.NextActivation = filter.Schedule.NextActivation(time.Now())
 
 		ret = append(ret, filter)
 	}
@@ -146,6 +200,9 @@ func (s *service) FindByID(ctx context.Context, filterID int) (*domain.Filter, e
 	}
 	filter.Indexers = indexers
 
+	filter.Breakers = s.breakers.get(filter.ID)
+	filter.NextActivation = filter.Schedule.NextActivation(time.Now())
+
 	return filter, nil
 }
 
@@ -156,20 +213,31 @@ func (s *service) FindByIndexerIdentifier(ctx context.Context, indexer string) (
 		return nil, err
 	}
 
+	now := time.Now()
+
+	active := make([]*domain.Filter, 0, len(filters))
+
 	// we do not load actions here since we do not need it at this stage
 	// only load those after filter has matched
 	for _, filter := range filters {
 		filter := filter
 
+		// skip filters outside their schedule
+		if !filter.Schedule.Active(now) {
+			s.log.Trace().Msgf("filter %s is outside its schedule, next activation: %v", filter.Name, filter.Schedule.NextActivation(now))
+			continue
+		}
+
 		externalFilters, err := s.repo.FindExternalFiltersByID(ctx, filter.ID)
 		if err != nil {
 			s.log.Error().Err(err).Msgf("could not find external filters for filter id: %v", filter.ID)
 		}
 		filter.External = externalFilters
 
+		active = append(active, filter)
 	}
 
-	return filters, nil
+	return active, nil
 }
 
 func (s *service) GetDownloadsByFilterId(ctx context.Context, filterID int) (*domain.FilterDownloads, error) {
@@ -182,6 +250,13 @@ func (s *service) Store(ctx context.Context, filter *domain.Filter) error {
 		return err
 	}
 
+	if err := s.validateGroup(ctx, filter.GroupID); err != nil {
+		return err
+	}
+
+	filter.CreatedBy = requestUsername(ctx)
+	filter.UpdatedBy = filter.CreatedBy
+
 	if err := s.repo.Store(ctx, filter); err != nil {
 		s.log.Error().Err(err).Msgf("could not store filter: %v", filter)
 		return err
@@ -197,12 +272,18 @@ func (s *service) Update(ctx context.Context, filter *domain.Filter) error {
 		return err
 	}
 
+	if err := s.validateGroup(ctx, filter.GroupID); err != nil {
+		return err
+	}
+
 	err = filter.Sanitize()
 	if err != nil {
 		s.log.Error().Err(err).Msgf("could not sanitize filter: %v", filter)
 		return err
 	}
 
+	filter.UpdatedBy = requestUsername(ctx)
+
 	// update
 	err = s.repo.Update(ctx, filter)
 	if err != nil {
@@ -233,10 +314,29 @@ func (s *service) Update(ctx context.Context, filter *domain.Filter) error {
 
 	filter.Actions = actions
 
+	// the breaker settings or external filters might have changed
+	s.breakers.reset(filter.ID)
+
 	return nil
 }
 
 func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error {
+	if err := filter.Schedule.Validate(); err != nil {
+		return err
+	}
+
+	if filter.AdvancedExpression != nil {
+		if err := domain.ValidateAdvancedExpression(*filter.AdvancedExpression); err != nil {
+			return err
+		}
+	}
+
+	if filter.GroupID != nil {
+		if err := s.validateGroup(ctx, *filter.GroupID); err != nil {
+			return err
+		}
+	}
+
 	// cleanup
 	if filter.Shows != nil {
 		// replace newline with comma
@@ -246,6 +346,10 @@ func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate)
 		filter.Shows = &clean
 	}
 
+	if username := requestUsername(ctx); username != "" {
+		filter.UpdatedBy = &username
+	}
+
 	// update
 	if err := s.repo.UpdatePartial(ctx, filter); err != nil {
 		s.log.Error().Err(err).Msgf("could not update partial filter: %v", filter.ID)
@@ -276,10 +380,27 @@ func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate)
 		}
 	}
 
+	s.breakers.reset(filter.ID)
+
 	return nil
 }
 
 func (s *service) Duplicate(ctx context.Context, filterID int) (*domain.Filter, error) {
+	resp, err := s.Clone(ctx, filterID, domain.FilterCloneRequest{IncludeActions: true, IncludeExternal: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Filter, nil
+}
+
+// Clone copies a filter, optionally with actions and external filters, and returns the diff to the source
+func (s *service) Clone(ctx context.Context, filterID int, req domain.FilterCloneRequest) (*domain.FilterCloneResponse, error) {
+	source, err := s.FindByID(ctx, filterID)
+	if err != nil {
+		return nil, err
+	}
+
 	// find filter with actions, indexers and external filters
 	filter, err := s.FindByID(ctx, filterID)
 	if err != nil {
@@ -289,8 +410,22 @@ func (s *service) Duplicate(ctx context.Context, filterID int) (*domain.Filter,
 	// reset id and name
 	filter.ID = 0
 	filter.Name = fmt.Sprintf("%s Copy", filter.Name)
+	if req.Name != "" {
+		filter.Name = req.Name
+	}
 	filter.Enabled = false
 
+	if !req.IncludeActions {
+		filter.Actions = nil
+	}
+
+	if !req.IncludeExternal {
+		filter.External = nil
+	}
+
+	filter.CreatedBy = requestUsername(ctx)
+	filter.UpdatedBy = filter.CreatedBy
+
 	// store new filter
 	if err := s.repo.Store(ctx, filter); err != nil {
 		s.log.Error().Err(err).Msgf("could not update filter: %s", filter.Name)
@@ -303,27 +438,83 @@ func (s *service) Duplicate(ctx context.Context, filterID int) (*domain.Filter,
 		return nil, err
 	}
 
-	// reset action id to 0
-	for i, a := range filter.Actions {
-		a := a
-		a.ID = 0
-		filter.Actions[i] = a
+	if len(filter.Actions) > 0 {
+		// reset action id to 0
+		for i, a := range filter.Actions {
+			a := a
+			a.ID = 0
+			filter.Actions[i] = a
+		}
+
+		// take care of filter actions
+		if _, err := s.actionService.StoreFilterActions(ctx, int64(filter.ID), filter.Actions); err != nil {
+			s.log.Error().Err(err).Msgf("could not store filter actions: %s", filter.Name)
+			return nil, err
+		}
 	}
 
-	// take care of filter actions
-	if _, err := s.actionService.StoreFilterActions(ctx, int64(filter.ID), filter.Actions); err != nil {
-		s.log.Error().Err(err).Msgf("could not store filter actions: %s", filter.Name)
+	if len(filter.External) > 0 {
+		// take care of connected external filters
+		// the external filters are fetched with FindByID
+		if err := s.repo.StoreFilterExternal(ctx, filter.ID, filter.External); err != nil {
+			s.log.Error().Err(err).Msgf("could not store external filters: %s", filter.Name)
+			return nil, err
+		}
+	}
+
+	diff, err := domain.DiffFilters(source, filter)
+	if err != nil {
 		return nil, err
 	}
 
-	// take care of connected external filters
-	// the external filters are fetched with FindByID
-	if err := s.repo.StoreFilterExternal(ctx, filter.ID, filter.External); err != nil {
-		s.log.Error().Err(err).Msgf("could not store external filters: %s", filter.Name)
+	return &domain.FilterCloneResponse{
+		SourceID: source.ID,
+		Filter:   filter,
+		Diff:     diff,
+	}, nil
+}
+
+// BulkUpdate applies the same change to multiple filters. Errors are reported per filter.
+func (s *service) BulkUpdate(ctx context.Context, req domain.FilterBulkUpdateRequest) ([]domain.FilterBulkUpdateResult, error) {
+	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	return filter, nil
+	results := make([]domain.FilterBulkUpdateResult, 0, len(req.FilterIDs))
+
+	for _, filterID := range req.FilterIDs {
+		result := domain.FilterBulkUpdateResult{FilterID: filterID}
+
+		filter, err := s.repo.FindByID(ctx, filterID)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Name = filter.Name
+
+		update, diff, err := req.FilterUpdate(filter)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Diff = diff
+
+		if update != nil && !req.DryRun {
+			if err := s.UpdatePartial(ctx, *update); err != nil {
+				result.Error = err.Error()
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	s.log.Debug().Msgf("bulk %s %s on %d filters (dry run: %t)", req.Operation, req.Field, len(req.FilterIDs), req.DryRun)
+
+	return results, nil
 }
 
 func (s *service) ToggleEnabled(ctx context.Context, filterID int, enabled bool) error {
@@ -337,11 +528,28 @@ func (s *service) ToggleEnabled(ctx context.Context, filterID int, enabled bool)
 	return nil
 }
 
+// Delete moves the filter to the trash, it can be restored until it is purged
 func (s *service) Delete(ctx context.Context, filterID int) error {
 	if filterID == 0 {
 		return nil
 	}
 
+	if err := s.repo.Trash(ctx, filterID); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete filter: %v", filterID)
+		return err
+	}
+
+	s.breakers.reset(filterID)
+
+	return nil
+}
+
+// Purge permanently deletes the filter with its actions, indexers and external filters
+func (s *service) Purge(ctx context.Context, filterID int) error {
+	if filterID == 0 {
+		return nil
+	}
+
 	// take care of filter actions
 	if err := s.actionService.DeleteByFilterID(ctx, filterID); err != nil {
 		s.log.Error().Err(err).Msg("could not delete filter actions")
@@ -366,6 +574,8 @@ func (s *service) Delete(ctx context.Context, filterID int) error {
 		return err
 	}
 
+	s.breakers.reset(filterID)
+
 	return nil
 }
 
@@ -375,8 +585,12 @@ func (s *service) CheckFilter(ctx context.Context, f *domain.Filter, release *do
 	l.Trace().Msgf("checking filter: %s %+v", f.Name, f)
 	l.Trace().Msgf("checking filter: %s for release: %+v", f.Name, release)
 
+	// clear results from exec filters and actions of previously checked filters
+	release.ActionCategory = ""
+	release.MacroVars = nil
+
 	// do additional fetch to get download counts for filter
-	if f.MaxDownloads > 0 {
+	if f.HasDownloadLimits() {
 		downloadCounts, err := s.repo.GetDownloadsByFilterId(ctx, f.ID)
 		if err != nil {
 			l.Error().Err(err).Msg("error getting download counters for filter")
@@ -392,70 +606,55 @@ func (s *service) CheckFilter(ctx context.Context, f *domain.Filter, release *do
 	}
 
 	if matchedFilter {
-		// smartEpisode check
-		if f.SmartEpisode {
-			params := &domain.SmartEpisodeParams{
-				Title:   release.Title,
-				Season:  release.Season,
-				Episode: release.Episode,
-				Year:    release.Year,
-				Month:   release.Month,
-				Day:     release.Day,
-				Repack:  release.Repack,
-				Proper:  release.Proper,
-				Group:   release.Group,
-			}
-			canDownloadShow, err := s.CheckSmartEpisodeCanDownload(ctx, params)
-			if err != nil {
-				l.Trace().Msgf("failed smart episode check: %s", f.Name)
-				return false, nil
-			}
-
-			if !canDownloadShow {
-				l.Trace().Msgf("failed smart episode check: %s", f.Name)
+		l.Debug().Msgf("found and matched filter: %s", f.Name)
 
-				if params.IsDailyEpisode() {
-					f.AddRejectionF("smart episode check: not new: (%s) Daily: %d-%d-%d", release.Title, release.Year, release.Month, release.Day)
-				} else {
-					f.AddRejectionF("smart episode check: not new: (%s) season: %d ep: %d", release.Title, release.Season, release.Episode)
-				}
+		stages := domain.DefaultPipelineStages
+		if release.Pipeline != nil {
+			stages = release.Pipeline.Stages
+		}
 
-				return false, nil
-			}
+		if s.sizeCheckSkipped(f, release, stages) || s.fileCheckSkipped(f, release, stages) || s.lookupCheckSkipped(f, release, stages) {
+			return false, nil
 		}
 
-		// if matched, do additional size check if needed, attach actions and return the filter
+		// run the checks in the order of the indexer pipeline, the delay is handled before the actions
+		for _, stage := range stages {
+			var (
+				ok  bool
+				err error
+			)
 
-		l.Debug().Msgf("found and matched filter: %s", f.Name)
+			switch stage {
+			case domain.PipelineStageDuplicateCheck:
+				ok = s.smartEpisodeCheck(ctx, f, release) && s.smartMusicCheck(ctx, f, release)
 
-		// If size constraints are set in a filter and the indexer did not
-		// announce the size, we need to do an additional out of band size
-		// check.
-		if release.AdditionalSizeCheckRequired {
-			l.Debug().Msgf("(%s) additional size check required", f.Name)
+			case domain.PipelineStageEnrichment:
+				if !release.AdditionalSizeCheckRequired && !f.HasFileChecks() && !f.HasLookupChecks() {
+					continue
+				}
 
-			ok, err := s.AdditionalSizeCheck(ctx, f, release)
-			if err != nil {
-				l.Error().Err(err).Msgf("(%s) additional size check error", f.Name)
-				return false, err
-			}
+				ok, err = s.guardedCheck(f, stage, func() (bool, error) {
+					return s.enrichmentCheck(ctx, f, release)
+				})
 
-			if !ok {
-				l.Trace().Msgf("(%s) additional size check not matching what filter wanted", f.Name)
-				return false, nil
+			case domain.PipelineStageExternal:
+				if !hasEnabledExternal(f) {
+					continue
+				}
+
+				ok, err = s.guardedCheck(f, stage, func() (bool, error) {
+					return s.externalCheck(ctx, f, release)
+				})
+
+			default:
+				continue
 			}
-		}
 
-		// run external filters
-		if f.External != nil {
-			externalOk, err := s.RunExternalFilters(ctx, f, f.External, release)
 			if err != nil {
-				l.Error().Err(err).Msgf("(%s) external filter check error", f.Name)
 				return false, err
 			}
 
-			if !externalOk {
-				l.Debug().Msgf("(%s) external filter check not matching what filter wanted", f.Name)
+			if !ok {
 				return false, nil
 			}
 		}
@@ -467,6 +666,280 @@ func (s *service) CheckFilter(ctx context.Context, f *domain.Filter, release *do
 	return false, nil
 }
 
+// smartEpisodeCheck rejects episodes older than or the same as ones already pushed
+func (s *service) smartEpisodeCheck(ctx context.Context, f *domain.Filter, release *domain.Release) bool {
+	if !f.SmartEpisode {
+		return true
+	}
+
+	l := s.log.With().Str("method", "CheckFilter").Logger()
+
+	params := &domain.SmartEpisodeParams{
+		Title:   release.Title,
+		Season:  release.Season,
+		Episode: release.Episode,
+		Year:    release.Year,
+		Month:   release.Month,
+		Day:     release.Day,
+		Repack:  release.Repack,
+		Proper:  release.Proper,
+		Group:   release.Group,
+
+		WithinDays:  f.SmartDuplicateDays,
+		SeasonPacks: !f.IgnoreSeasonPacks,
+	}
+	canDownloadShow, err := s.CheckSmartEpisodeCanDownload(ctx, params)
+	if err != nil {
+		l.Trace().Msgf("failed smart episode check: %s", f.Name)
+		return false
+	}
+
+	if !canDownloadShow {
+		l.Trace().Msgf("failed smart episode check: %s", f.Name)
+
+		if params.IsDailyEpisode() {
+			f.AddRejectionF("smart episode check: not new: (%s) Daily: %d-%d-%d", release.Title, release.Year, release.Month, release.Day)
+		} else if params.SeasonPacks && !params.IsSeasonPack() {
+			f.AddRejectionF("smart episode check: not new or in a pushed season pack: (%s) season: %d ep: %d", release.Title, release.Season, release.Episode)
+		} else {
+			f.AddRejectionF("smart episode check: not new: (%s) season: %d ep: %d", release.Title, release.Season, release.Episode)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// smartMusicCheck rejects other versions of albums already pushed, eg. the V0 when the FLAC was grabbed
+func (s *service) smartMusicCheck(ctx context.Context, f *domain.Filter, release *domain.Release) bool {
+	if !f.SmartMusic {
+		return true
+	}
+
+	l := s.log.With().Str("method", "CheckFilter").Logger()
+
+	params := domain.NewSmartMusicParams(release, f.SmartMusicMatch, f.SmartDuplicateDays)
+
+	canDownload, err := s.releaseRepo.CheckSmartMusicCanDownload(ctx, params)
+	if err != nil {
+		l.Error().Err(err).Msgf("(%s) failed smart music check", f.Name)
+		return false
+	}
+
+	if !canDownload {
+		l.Trace().Msgf("failed smart music check: %s", f.Name)
+
+		f.AddRejectionF("smart music check: already have (%s) %d, got: %s / %s / %s", release.Title, release.Year, release.AudioFormat, release.Bitrate, release.Source)
+
+		return false
+	}
+
+	return true
+}
+
+// sizeCheckSkipped rejects the release when the filter needs the size from the enrichment
+// stage but the indexer pipeline skips it, the size constraints could not be checked otherwise.
+func (s *service) sizeCheckSkipped(f *domain.Filter, release *domain.Release, stages []domain.PipelineStage) bool {
+	if !release.AdditionalSizeCheckRequired || slices.Contains(stages, domain.PipelineStageEnrichment) {
+		return false
+	}
+
+	s.log.Warn().Str("method", "CheckFilter").Msgf("(%s) size of %s is unknown and the pipeline for %s skips the enrichment stage", f.Name, release.TorrentName, release.Indexer.Identifier)
+
+	f.AddRejectionF("size check: size unknown and stage %q skipped by the pipeline for %s", domain.PipelineStageEnrichment, release.Indexer.Identifier)
+
+	return true
+}
+
+// fileCheckSkipped rejects the release when the filter checks the files of the torrent
+// but the indexer pipeline skips the enrichment stage that downloads it.
+func (s *service) fileCheckSkipped(f *domain.Filter, release *domain.Release, stages []domain.PipelineStage) bool {
+	if !f.HasFileChecks() || release.Protocol != domain.ReleaseProtocolTorrent || slices.Contains(stages, domain.PipelineStageEnrichment) {
+		return false
+	}
+
+	s.log.Warn().Str("method", "CheckFilter").Msgf("(%s) files of %s can't be checked, the pipeline for %s skips the enrichment stage", f.Name, release.TorrentName, release.Indexer.Identifier)
+
+	f.AddRejectionF("file check: torrent not downloaded, stage %q skipped by the pipeline for %s", domain.PipelineStageEnrichment, release.Indexer.Identifier)
+
+	return true
+}
+
+// lookupCheckSkipped rejects the release when the filter matches on tmdb or musicbrainz ids
+// but the indexer pipeline skips the enrichment stage that looks them up.
+func (s *service) lookupCheckSkipped(f *domain.Filter, release *domain.Release, stages []domain.PipelineStage) bool {
+	if !f.HasLookupChecks() || slices.Contains(stages, domain.PipelineStageEnrichment) {
+		return false
+	}
+
+	s.log.Warn().Str("method", "CheckFilter").Msgf("(%s) ids of %s can't be looked up, the pipeline for %s skips the enrichment stage", f.Name, release.TorrentName, release.Indexer.Identifier)
+
+	f.AddRejectionF("lookup check: ids unknown and stage %q skipped by the pipeline for %s", domain.PipelineStageEnrichment, release.Indexer.Identifier)
+
+	return true
+}
+
+// enrichmentCheck does the additional size check, the file checks, the id check and the musicbrainz check if needed.
+// If size constraints are set in a filter and the indexer did not
+// announce the size, we need to do an additional out of band size
+// check.
+func (s *service) enrichmentCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	l := s.log.With().Str("method", "CheckFilter").Logger()
+
+	if release.AdditionalSizeCheckRequired {
+		l.Debug().Msgf("(%s) additional size check required", f.Name)
+
+		ok, err := s.AdditionalSizeCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) additional size check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Trace().Msgf("(%s) additional size check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	if f.HasFileChecks() {
+		ok, err := s.fileCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) file check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Debug().Msgf("(%s) file check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	if f.MatchIDs != "" {
+		ok, err := s.idCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) id check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Debug().Msgf("(%s) id check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	if f.MatchMusicBrainz != "" {
+		ok, err := s.musicBrainzCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) musicbrainz check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Debug().Msgf("(%s) musicbrainz check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// fileCheck downloads the torrent file and checks its file list.
+// Usenet releases are not checked, magnet links are rejected since their file list is unknown.
+func (s *service) fileCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	if release.Protocol != domain.ReleaseProtocolTorrent {
+		return true, nil
+	}
+
+	if release.HasMagnetUri() {
+		f.AddRejectionF("file check: no torrent file for magnet link")
+		return false, nil
+	}
+
+	if err := s.downloadSvc.DownloadRelease(ctx, release); err != nil {
+		return false, errors.Wrap(err, "could not download torrent file for release: %s", release.TorrentName)
+	}
+
+	files, err := release.TorrentFiles()
+	if err != nil {
+		return false, err
+	}
+
+	return f.CheckFiles(files), nil
+}
+
+// externalCheck runs the external filters, the sonarr, radarr and lidarr checks and the library check
+func (s *service) externalCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	l := s.log.With().Str("method", "CheckFilter").Logger()
+
+	if f.External != nil {
+		externalOk, err := s.RunExternalFilters(ctx, f, f.External, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) external filter check error", f.Name)
+			return false, err
+		}
+
+		if !externalOk {
+			l.Debug().Msgf("(%s) external filter check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	if f.SonarrCheckClientID > 0 {
+		ok, err := s.sonarrCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) sonarr check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Debug().Msgf("(%s) sonarr check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	if f.RadarrCheckClientID > 0 {
+		ok, err := s.radarrCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) radarr check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Debug().Msgf("(%s) radarr check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	if f.LidarrCheckClientID > 0 {
+		ok, err := s.lidarrCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) lidarr check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Debug().Msgf("(%s) lidarr check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	if f.LibraryCheckClientID > 0 {
+		ok, err := s.libraryCheck(ctx, f, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("(%s) library check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			l.Debug().Msgf("(%s) library check not matching what filter wanted", f.Name)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // AdditionalSizeCheck performs additional out of band checks to determine the
 // size of a torrent. Some indexers do not announce torrent size, so it is
 // necessary to determine the size of the torrent in some other way. Some
@@ -486,6 +959,12 @@ func (s *service) AdditionalSizeCheck(ctx context.Context, f *domain.Filter, rel
 
 	l.Debug().Msgf("(%s) additional size check required", f.Name)
 
+	if size, ok := s.sizes.get(release); ok && release.Size == 0 {
+		l.Debug().Msgf("(%s) got size %d of %s from cache", f.Name, size, release.TorrentName)
+
+		release.Size = size
+	}
+
 	switch release.Indexer.Identifier {
 	case "ptp", "btn", "ggn", "redacted", "ops", "mock":
 		if release.Size == 0 {
@@ -503,6 +982,10 @@ func (s *service) AdditionalSizeCheck(ctx context.Context, f *domain.Filter, rel
 		}
 
 	default:
+		if release.Size > 0 {
+			break
+		}
+
 		l.Trace().Msgf("(%s) preparing to download torrent metafile", f.Name)
 
 		// if indexer doesn't have api, download torrent and add to tmpPath
@@ -512,6 +995,8 @@ func (s *service) AdditionalSizeCheck(ctx context.Context, f *domain.Filter, rel
 		}
 	}
 
+	s.sizes.set(release, release.Size)
+
 	sizeOk, err := f.CheckReleaseSize(release.Size)
 	if err != nil {
 		l.Error().Err(err).Msgf("(%s) error comparing release and filter size", f.Name)
@@ -559,7 +1044,7 @@ func (s *service) RunExternalFilters(ctx context.Context, f *domain.Filter, exte
 		switch external.Type {
 		case domain.ExternalFilterTypeExec:
 			// run external script
-			exitCode, err := s.execCmd(ctx, external, release)
+			exitCode, output, err := s.execCmd(ctx, external, release)
 			if err != nil {
 				return false, errors.Wrap(err, "error executing external command")
 			}
@@ -570,6 +1055,20 @@ func (s *service) RunExternalFilters(ctx context.Context, f *domain.Filter, exte
 				return false, nil
 			}
 
+			// scripts can optionally print json to veto the release or set category and macros
+			result, err := domain.ParseExecResult(output)
+			if err != nil {
+				s.log.Warn().Err(err).Msgf("filter.Service.CheckFilter: could not parse external script output for filter: %s", external.Name)
+			} else if result != nil {
+				if result.Reject {
+					s.log.Trace().Msgf("filter.Service.CheckFilter: external script rejected release: %s", result.RejectionReason())
+					f.AddRejectionF("external script rejected: %s", result.RejectionReason())
+					return false, nil
+				}
+
+				result.Apply(release)
+			}
+
 		case domain.ExternalFilterTypeWebhook:
 			// run external webhook
 			statusCode, err := s.webhook(ctx, external, release)
@@ -577,31 +1076,45 @@ func (s *service) RunExternalFilters(ctx context.Context, f *domain.Filter, exte
 				return false, errors.Wrap(err, "error executing external webhook")
 			}
 
+			if err := webhookServerError(external.WebhookExpectStatus, statusCode); err != nil {
+				return false, errors.Wrap(err, "error executing external webhook")
+			}
+
 			if statusCode != external.WebhookExpectStatus {
 				s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook unexpected status code. got: %d want: %d", statusCode, external.WebhookExpectStatus)
 				f.AddRejectionF("external webhook unexpected status code. got: %d want: %d", statusCode, external.WebhookExpectStatus)
 				return false, nil
 			}
+
+		case domain.ExternalFilterTypeWebhookVerdict:
+			ok, err := s.checkWebhookVerdict(ctx, f, external, release)
+			if err != nil {
+				return false, err
+			}
+
+			if !ok {
+				return false, nil
+			}
 		}
 	}
 
 	return true, nil
 }
 
-func (s *service) execCmd(_ context.Context, external domain.FilterExternal, release *domain.Release) (int, error) {
+func (s *service) execCmd(_ context.Context, external domain.FilterExternal, release *domain.Release) (int, []byte, error) {
 	s.log.Trace().Msgf("filter exec release: %s", release.TorrentName)
 
 	// read the file into bytes we can then use in the macro
 	if len(release.TorrentDataRawBytes) == 0 && release.TorrentTmpFile != "" {
 		if err := release.OpenTorrentFile(); err != nil {
-			return 0, errors.Wrap(err, "could not open torrent file for release: %s", release.TorrentName)
+			return 0, nil, errors.Wrap(err, "could not open torrent file for release: %s", release.TorrentName)
 		}
 	}
 
 	// check if program exists
 	cmd, err := exec.LookPath(external.ExecCmd)
 	if err != nil {
-		return 0, errors.Wrap(err, "exec failed, could not find program: %s", cmd)
+		return 0, nil, errors.Wrap(err, "exec failed, could not find program: %s", cmd)
 	}
 
 	// handle args and replace vars
@@ -610,7 +1123,7 @@ func (s *service) execCmd(_ context.Context, external domain.FilterExternal, rel
 	// parse and replace values in argument string before continuing
 	parsedArgs, err := m.Parse(external.ExecArgs)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not parse macro")
+		return 0, nil, errors.Wrap(err, "could not parse macro")
 	}
 
 	// we need to split on space into a string slice, so we can spread the args into exec
@@ -618,7 +1131,7 @@ func (s *service) execCmd(_ context.Context, external domain.FilterExternal, rel
 	p.ParseBacktick = true
 	commandArgs, err := p.Parse(parsedArgs)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not parse into shell-words")
+		return 0, nil, errors.Wrap(err, "could not parse into shell-words")
 	}
 
 	start := time.Now()
@@ -632,7 +1145,7 @@ func (s *service) execCmd(_ context.Context, external domain.FilterExternal, rel
 	cmdOutput, err := command.StdoutPipe()
 	if err != nil {
 		s.log.Error().Err(err).Msg("could not create stdout pipe")
-		return 0, err
+		return 0, nil, err
 	}
 
 	duration := time.Since(start)
@@ -640,12 +1153,15 @@ func (s *service) execCmd(_ context.Context, external domain.FilterExternal, rel
 	// Start the command
 	if err := command.Start(); err != nil {
 		s.log.Error().Err(err).Msg("error starting command")
-		return 0, err
+		return 0, nil, err
 	}
 
 	// Create a buffer to store the output
 	outputBuffer := make([]byte, 4096)
 
+	// keep the full output to parse the optional json result
+	var output bytes.Buffer
+
 	execLogger := s.log.With().Str("release", release.TorrentName).Str("filter", release.FilterName).Logger()
 
 	for {
@@ -655,6 +1171,8 @@ func (s *service) execCmd(_ context.Context, external domain.FilterExternal, rel
 			break
 		}
 
+		output.Write(outputBuffer[:n])
+
 		// Write the output to the logger
 		execLogger.Trace().Msg(string(outputBuffer[:n]))
 	}
@@ -664,16 +1182,16 @@ func (s *service) execCmd(_ context.Context, external domain.FilterExternal, rel
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) {
 			s.log.Debug().Msgf("filter script command exited with non zero code: %v", exitErr.ExitCode())
-			return exitErr.ExitCode(), nil
+			return exitErr.ExitCode(), output.Bytes(), nil
 		}
 
 		s.log.Error().Err(err).Msg("error waiting for command")
-		return 0, err
+		return 0, nil, err
 	}
 
 	s.log.Debug().Msgf("executed external script: (%s), args: (%s) for release: (%s) indexer: (%s) total time (%s)", cmd, parsedArgs, release.TorrentName, release.Indexer.Name, duration)
 
-	return 0, nil
+	return 0, output.Bytes(), nil
 }
 
 func (s *service) webhook(ctx context.Context, external domain.FilterExternal, release *domain.Release) (int, error) {
@@ -713,20 +1231,7 @@ func (s *service) webhook(ctx context.Context, external domain.FilterExternal, r
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "autobrr")
 
-	if external.WebhookHeaders != "" {
-		headers := strings.Split(external.WebhookHeaders, ";")
-
-		for _, header := range headers {
-			h := strings.Split(header, "=")
-
-			if len(h) != 2 {
-				continue
-			}
-
-			// add header to req
-			req.Header.Add(h[0], h[1]) // go already canonicalizes the provided header key.
-		}
-	}
+	setWebhookHeaders(req, external.WebhookHeaders)
 
 	var opts []retry.Option
 
@@ -785,3 +1290,12 @@ func (s *service) webhook(ctx context.Context, external domain.FilterExternal, r
 
 	return statusCode, err
 }
+
+// requestUsername returns the user changing the filter, empty for api keys without owner
+func requestUsername(ctx context.Context) string {
+	if user := domain.UserFromContext(ctx); user != nil {
+		return user.Username
+	}
+
+	return ""
+}
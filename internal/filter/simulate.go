@@ -0,0 +1,58 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// Simulate checks the release like CheckFilter but only runs the pipeline stages without side effects.
+// The out of band size check, the file checks, the lookup checks and the external filters are returned as skipped instead.
+func (s *service) Simulate(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, []domain.PipelineStage, error) {
+	if f.HasDownloadLimits() {
+		downloadCounts, err := s.repo.GetDownloadsByFilterId(ctx, f.ID)
+		if err != nil {
+			return false, nil, err
+		}
+		f.Downloads = downloadCounts
+	}
+
+	if _, match := f.CheckFilter(release); !match {
+		return false, nil, nil
+	}
+
+	stages := domain.DefaultPipelineStages
+	if release.Pipeline != nil {
+		stages = release.Pipeline.Stages
+	}
+
+	if s.sizeCheckSkipped(f, release, stages) || s.fileCheckSkipped(f, release, stages) || s.lookupCheckSkipped(f, release, stages) {
+		return false, nil, nil
+	}
+
+	var skipped []domain.PipelineStage
+
+	for _, stage := range stages {
+		switch stage {
+		case domain.PipelineStageDuplicateCheck:
+			if !s.smartEpisodeCheck(ctx, f, release) || !s.smartMusicCheck(ctx, f, release) {
+				return false, skipped, nil
+			}
+
+		case domain.PipelineStageEnrichment:
+			if release.AdditionalSizeCheckRequired || f.HasFileChecks() || f.HasLookupChecks() {
+				skipped = append(skipped, stage)
+			}
+
+		case domain.PipelineStageExternal:
+			if hasEnabledExternal(f) {
+				skipped = append(skipped, stage)
+			}
+		}
+	}
+
+	return true, skipped, nil
+}
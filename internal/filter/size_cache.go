@@ -0,0 +1,69 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// sizeCacheTTL is how long a resolved size is kept, torrents are usually announced again within minutes
+const sizeCacheTTL = 6 * time.Hour
+
+// sizeCache keeps the sizes resolved by the additional size check, so a torrent announced
+// again, eg. by the rss feed after irc, doesn't need another api call or download
+type sizeCache struct {
+	cache *ttlcache.Cache[string, uint64]
+}
+
+func newSizeCache() *sizeCache {
+	c := &sizeCache{
+		cache: ttlcache.New[string, uint64](
+			ttlcache.WithTTL[string, uint64](sizeCacheTTL),
+			ttlcache.WithCapacity[string, uint64](10000),
+		),
+	}
+
+	go c.cache.Start()
+
+	return c
+}
+
+// sizeCacheKey returns the key of the torrent of the release, empty if the torrent can't be identified
+func sizeCacheKey(release *domain.Release) string {
+	switch {
+	case release.TorrentID != "":
+		return release.Indexer.Identifier + ":" + release.TorrentID
+	case release.DownloadURL != "":
+		return release.Indexer.Identifier + ":" + release.DownloadURL
+	default:
+		return ""
+	}
+}
+
+func (c *sizeCache) get(release *domain.Release) (uint64, bool) {
+	key := sizeCacheKey(release)
+	if key == "" {
+		return 0, false
+	}
+
+	item := c.cache.Get(key)
+	if item == nil {
+		return 0, false
+	}
+
+	return item.Value(), true
+}
+
+func (c *sizeCache) set(release *domain.Release, size uint64) {
+	key := sizeCacheKey(release)
+	if key == "" || size == 0 {
+		return
+	}
+
+	c.cache.Set(key, size, ttlcache.DefaultTTL)
+}
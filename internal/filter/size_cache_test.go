@@ -0,0 +1,37 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sizeCache(t *testing.T) {
+	c := newSizeCache()
+
+	irc := &domain.Release{Indexer: domain.IndexerMinimal{Identifier: "mock"}, TorrentID: "123"}
+	feed := &domain.Release{Indexer: domain.IndexerMinimal{Identifier: "mock"}, TorrentID: "123", DownloadURL: "https://example.com/rss/download/123"}
+	other := &domain.Release{Indexer: domain.IndexerMinimal{Identifier: "other"}, TorrentID: "123"}
+
+	_, ok := c.get(irc)
+	assert.False(t, ok)
+
+	c.set(irc, 1024)
+
+	size, ok := c.get(feed)
+	assert.True(t, ok, "same torrent announced again")
+	assert.Equal(t, uint64(1024), size)
+
+	_, ok = c.get(other)
+	assert.False(t, ok, "same torrent id on another indexer")
+
+	// releases without torrent id or download url are not cached
+	c.set(&domain.Release{Indexer: domain.IndexerMinimal{Identifier: "mock"}}, 2048)
+	_, ok = c.get(&domain.Release{Indexer: domain.IndexerMinimal{Identifier: "mock"}})
+	assert.False(t, ok)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+)
+
+// sonarrCheck asks the sonarr client of the filter if it wants the release, so pushes sonarr would ignore are rejected early.
+// An episode is wanted when it's monitored and either missing or below the quality cutoff of the series.
+func (s *service) sonarrCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	client, err := s.clientSvc.GetClient(ctx, f.SonarrCheckClientID)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get sonarr client with id %d", f.SonarrCheckClientID)
+	}
+
+	if client.Type != domain.DownloadClientTypeSonarr {
+		return false, errors.New("client %s is not a sonarr client", client.Name)
+	}
+
+	if !client.Enabled {
+		return false, errors.New("sonarr client %s is disabled", client.Name)
+	}
+
+	arr, ok := client.Client.(sonarr.Client)
+	if !ok {
+		return false, errors.New("could not get sonarr client: %s", client.Name)
+	}
+
+	parsed, err := arr.Parse(ctx, release.TorrentName)
+	if err != nil {
+		return false, errors.Wrap(err, "could not parse release with sonarr client: %s", client.Name)
+	}
+
+	if parsed.Series == nil {
+		f.AddRejectionF("sonarr check: series unknown to %s: %s", client.Name, release.Title)
+		return false, nil
+	}
+
+	if !parsed.Series.Monitored {
+		f.AddRejectionF("sonarr check: series not monitored in %s: %s", client.Name, parsed.Series.Title)
+		return false, nil
+	}
+
+	for _, episode := range parsed.Episodes {
+		if !episode.Monitored {
+			continue
+		}
+
+		if !episode.HasFile || episode.EpisodeFileID == 0 {
+			return true, nil
+		}
+
+		file, err := arr.GetEpisodeFile(ctx, episode.EpisodeFileID)
+		if err != nil {
+			return false, errors.Wrap(err, "could not get episode file with sonarr client: %s", client.Name)
+		}
+
+		if file.QualityCutoffNotMet {
+			return true, nil
+		}
+	}
+
+	f.AddRejectionF("sonarr check: no monitored episodes missing or below the quality cutoff in %s: %s season: %d ep: %d", client.Name, parsed.Series.Title, release.Season, release.Episode)
+
+	return false, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDownloadClientService only implements GetClient, calling anything else panics
+type mockDownloadClientService struct {
+	download_client.Service
+	clients map[int32]*domain.DownloadClient
+}
+
+func (s *mockDownloadClientService) GetClient(ctx context.Context, clientId int32) (*domain.DownloadClient, error) {
+	client, ok := s.clients[clientId]
+	if !ok {
+		return nil, errors.New("client %d not found", clientId)
+	}
+	return client, nil
+}
+
+func Test_service_sonarrCheck(t *testing.T) {
+	// parse responses by release title
+	parsed := map[string]string{
+		"Unknown.Show.S01E01.1080p.WEB-DL.H264-GRP":     `{"episodes":[]}`,
+		"Unmonitored.S01E01.1080p.WEB-DL.H264-GRP":      `{"series":{"id":1,"title":"Unmonitored","monitored":false},"episodes":[{"id":1,"monitored":true}]}`,
+		"Missing.S01E01.1080p.WEB-DL.H264-GRP":          `{"series":{"id":2,"title":"Missing","monitored":true},"episodes":[{"id":2,"monitored":true,"hasFile":false}]}`,
+		"Upgrade.S01E01.1080p.WEB-DL.H264-GRP":          `{"series":{"id":3,"title":"Upgrade","monitored":true},"episodes":[{"id":3,"monitored":true,"hasFile":true,"episodeFileId":10}]}`,
+		"Cutoff.Met.S01E01.1080p.WEB-DL.H264-GRP":       `{"series":{"id":4,"title":"Cutoff Met","monitored":true},"episodes":[{"id":4,"monitored":true,"hasFile":true,"episodeFileId":11}]}`,
+		"Episode.Unmonitored.S01.1080p.WEB-DL.H264-GRP": `{"series":{"id":5,"title":"Episode Unmonitored","monitored":true},"episodes":[{"id":5,"monitored":false,"hasFile":false},{"id":6,"monitored":true,"hasFile":true,"episodeFileId":11}]}`,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/parse":
+			_, _ = w.Write([]byte(parsed[r.URL.Query().Get("title")]))
+		case "/api/v3/episodefile/10":
+			_, _ = w.Write([]byte(`{"id":10,"qualityCutoffNotMet":true}`))
+		case "/api/v3/episodefile/11":
+			_, _ = w.Write([]byte(`{"id":11,"qualityCutoffNotMet":false}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{log: zerolog.Nop(), clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+		1: {ID: 1, Name: "sonarr", Type: domain.DownloadClientTypeSonarr, Enabled: true, Client: sonarr.New(sonarr.Config{Hostname: srv.URL})},
+		2: {ID: 2, Name: "sonarr-disabled", Type: domain.DownloadClientTypeSonarr, Enabled: false},
+		3: {ID: 3, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Enabled: true},
+	}}}
+
+	tests := []struct {
+		name      string
+		clientID  int32
+		title     string
+		want      bool
+		rejection string
+		wantErr   string
+	}{
+		{name: "unknown_series", clientID: 1, title: "Unknown.Show.S01E01.1080p.WEB-DL.H264-GRP", rejection: "sonarr check: series unknown to sonarr: Unknown Show"},
+		{name: "series_unmonitored", clientID: 1, title: "Unmonitored.S01E01.1080p.WEB-DL.H264-GRP", rejection: "sonarr check: series not monitored in sonarr: Unmonitored"},
+		{name: "missing", clientID: 1, title: "Missing.S01E01.1080p.WEB-DL.H264-GRP", want: true},
+		{name: "upgrade", clientID: 1, title: "Upgrade.S01E01.1080p.WEB-DL.H264-GRP", want: true},
+		{name: "cutoff_met", clientID: 1, title: "Cutoff.Met.S01E01.1080p.WEB-DL.H264-GRP", rejection: "sonarr check: no monitored episodes missing or below the quality cutoff in sonarr: Cutoff Met season: 1 ep: 1"},
+		{name: "episode_unmonitored", clientID: 1, title: "Episode.Unmonitored.S01.1080p.WEB-DL.H264-GRP", rejection: "sonarr check: no monitored episodes missing or below the quality cutoff in sonarr: Episode Unmonitored season: 1 ep: 0"},
+		{name: "disabled", clientID: 2, title: "Missing.S01E01.1080p.WEB-DL.H264-GRP", wantErr: "sonarr client sonarr-disabled is disabled"},
+		{name: "not_sonarr", clientID: 3, title: "Missing.S01E01.1080p.WEB-DL.H264-GRP", wantErr: "client qbit is not a sonarr client"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &domain.Filter{Name: "tv", SonarrCheckClientID: tt.clientID}
+			release := domain.NewRelease(domain.IndexerMinimal{Identifier: "mock"})
+			release.TorrentName = tt.title
+			release.ParseString(tt.title)
+
+			got, err := s.sonarrCheck(context.Background(), f, release)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			if tt.rejection != "" {
+				assert.Equal(t, []string{tt.rejection}, f.Rejections)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	trashPurgeJobName  = "filter-trash-purge"
+	trashPurgeInterval = 6 * time.Hour
+)
+
+func (s *service) Restore(ctx context.Context, filterID int) error {
+	if err := s.repo.Restore(ctx, filterID); err != nil {
+		s.log.Error().Err(err).Msgf("could not restore filter: %v", filterID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ListTrashed(ctx context.Context) ([]domain.Filter, error) {
+	filters, err := s.repo.ListTrashed(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not list trashed filters")
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// PurgeTrashed permanently deletes filters that have been in the trash longer than the configured days
+func (s *service) PurgeTrashed(ctx context.Context) (int, error) {
+	if s.config.FilterTrashDays == 0 {
+		return 0, nil
+	}
+
+	filters, err := s.repo.ListTrashed(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not list trashed filters")
+	}
+
+	purged := 0
+	for _, f := range expiredTrash(filters, s.config.FilterTrashDays, time.Now()) {
+		if err := s.Purge(ctx, f.ID); err != nil {
+			return purged, errors.Wrap(err, "could not purge filter: %d", f.ID)
+		}
+
+		s.log.Debug().Msgf("purged filter %d %q from trash", f.ID, f.Name)
+		purged++
+	}
+
+	return purged, nil
+}
+
+// expiredTrash returns the filters deleted more than days ago
+func expiredTrash(filters []domain.Filter, days int, now time.Time) []domain.Filter {
+	cutoff := now.AddDate(0, 0, -days)
+
+	var expired []domain.Filter
+	for _, f := range filters {
+		if f.DeletedAt != nil && f.DeletedAt.Before(cutoff) {
+			expired = append(expired, f)
+		}
+	}
+
+	return expired
+}
+
+func (s *service) Start() error {
+	if s.config.FilterTrashDays == 0 {
+		return nil
+	}
+
+	job := &TrashPurgeJob{
+		Name: trashPurgeJobName,
+		Log:  s.log.With().Str("job", trashPurgeJobName).Logger(),
+		svc:  s,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(job, trashPurgeInterval, job.Name); err != nil {
+		return errors.Wrap(err, "could not schedule job: %s", job.Name)
+	}
+
+	return nil
+}
+
+type TrashPurgeJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+func (j *TrashPurgeJob) Run() {
+	purged, err := j.svc.PurgeTrashed(context.Background())
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not purge trashed filters")
+		return
+	}
+
+	if purged == 0 {
+		j.Log.Debug().Msg("no trashed filters to purge")
+		return
+	}
+
+	j.Log.Info().Msgf("purged %d filters from trash after %d days", purged, j.svc.config.FilterTrashDays)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/utils"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/avast/retry-go/v4"
+)
+
+const (
+	defaultWebhookVerdictTimeout = 15 * time.Second
+
+	// maxWebhookVerdictBody limits how much of the response is read for the verdict
+	maxWebhookVerdictBody = 64 * 1024
+)
+
+type webhookVerdictResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// webhookVerdict posts the release as json to the webhook and returns the response used for the verdict.
+// Each attempt is limited by the timeout of the external filter.
+func (s *service) webhookVerdict(ctx context.Context, external domain.FilterExternal, release *domain.Release) (*webhookVerdictResponse, error) {
+	if external.WebhookHost == "" {
+		return nil, errors.New("external filter: missing host for webhook")
+	}
+
+	payload, err := json.Marshal(release)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal release for webhook")
+	}
+
+	method := http.MethodPost
+	if external.WebhookMethod != "" {
+		method = external.WebhookMethod
+	}
+
+	timeout := defaultWebhookVerdictTimeout
+	if external.WebhookTimeoutSeconds > 0 {
+		timeout = time.Duration(external.WebhookTimeoutSeconds) * time.Second
+	}
+
+	opts := []retry.Option{
+		retry.DelayType(retry.FixedDelay),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	}
+
+	if external.WebhookRetryAttempts > 0 {
+		opts = append(opts, retry.Attempts(uint(external.WebhookRetryAttempts)))
+	}
+	if external.WebhookRetryDelaySeconds > 0 {
+		opts = append(opts, retry.Delay(time.Duration(external.WebhookRetryDelaySeconds)*time.Second))
+	}
+
+	var retryStatusCodes []string
+	if external.WebhookRetryStatus != "" {
+		retryStatusCodes = strings.Split(strings.ReplaceAll(external.WebhookRetryStatus, " ", ""), ",")
+	}
+
+	s.log.Trace().Msgf("sending %s to external webhook verdict filter: (%s)", method, external.WebhookHost)
+
+	start := time.Now()
+
+	resp, err := retry.DoWithData(
+		func() (*webhookVerdictResponse, error) {
+			reqCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(reqCtx, method, external.WebhookHost, bytes.NewReader(payload))
+			if err != nil {
+				return nil, retry.Unrecoverable(errors.Wrap(err, "could not build request for webhook"))
+			}
+
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", "autobrr")
+			setWebhookHeaders(req, external.WebhookHeaders)
+
+			res, err := s.httpClient.Do(req)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not make request for webhook")
+			}
+
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(io.LimitReader(res.Body, maxWebhookVerdictBody))
+			if err != nil {
+				return nil, errors.Wrap(err, "could not read webhook response body")
+			}
+
+			s.log.Debug().Msgf("filter external webhook verdict response status: %d body: %s", res.StatusCode, body)
+
+			if utils.StrSliceContains(retryStatusCodes, strconv.Itoa(res.StatusCode)) {
+				return nil, errors.New("webhook got unwanted status code: %d", res.StatusCode)
+			}
+
+			return &webhookVerdictResponse{StatusCode: res.StatusCode, Body: body}, nil
+		},
+		opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Debug().Msgf("successfully ran external webhook verdict filter to: (%s) finished in %s", external.WebhookHost, time.Since(start))
+
+	return resp, nil
+}
+
+// checkWebhookVerdict rejects the release on an unexpected status or a rejecting verdict in the response body
+func (s *service) checkWebhookVerdict(ctx context.Context, f *domain.Filter, external domain.FilterExternal, release *domain.Release) (bool, error) {
+	resp, err := s.webhookVerdict(ctx, external, release)
+	if err != nil {
+		return false, errors.Wrap(err, "error executing external webhook verdict")
+	}
+
+	if err := webhookServerError(external.WebhookExpectStatus, resp.StatusCode); err != nil {
+		return false, errors.Wrap(err, "error executing external webhook verdict")
+	}
+
+	if err := domain.WebhookVerdictStatusOK(external.WebhookExpectStatus, resp.StatusCode); err != nil {
+		s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook verdict %s", err)
+		f.AddRejectionF("external webhook verdict %s", err)
+		return false, nil
+	}
+
+	verdict, err := domain.ParseWebhookVerdict(resp.Body)
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("filter.Service.CheckFilter: could not parse webhook verdict for filter: %s", external.Name)
+		return true, nil
+	}
+
+	if verdict == nil {
+		return true, nil
+	}
+
+	if verdict.Rejected() {
+		s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook rejected release: %s", verdict.RejectionReason())
+		f.AddRejectionF("external webhook rejected: %s", verdict.RejectionReason())
+		return false, nil
+	}
+
+	verdict.Apply(release)
+
+	return true, nil
+}
+
+// setWebhookHeaders adds headers in the format key=value;key2=value2
+func setWebhookHeaders(req *http.Request, headers string) {
+	if headers == "" {
+		return
+	}
+
+	for _, header := range strings.Split(headers, ";") {
+		h := strings.Split(header, "=")
+
+		if len(h) != 2 {
+			continue
+		}
+
+		// add header to req
+		req.Header.Add(h[0], h[1]) // go already canonicalizes the provided header key.
+	}
+}
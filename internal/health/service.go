@@ -0,0 +1,249 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// clientCheckTTL is how long the result of a download client connection test is reused,
+	// monitors polling every few seconds shouldn't hammer the clients
+	clientCheckTTL = time.Minute
+
+	clientCheckTimeout = 10 * time.Second
+
+	// feedGracePeriod is added to twice the interval before a feed without a successful run is degraded
+	feedGracePeriod = 5 * time.Minute
+)
+
+type Pinger interface {
+	Ping() error
+}
+
+type IRCService interface {
+	GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetworkWithHealth, error)
+}
+
+type FeedService interface {
+	Find(ctx context.Context) ([]domain.Feed, error)
+}
+
+type DownloadClientService interface {
+	List(ctx context.Context) ([]domain.DownloadClient, error)
+	Test(ctx context.Context, client domain.DownloadClient) error
+}
+
+type Service interface {
+	Check(ctx context.Context) *domain.HealthReport
+}
+
+type service struct {
+	log zerolog.Logger
+
+	db                Pinger
+	ircSvc            IRCService
+	feedSvc           FeedService
+	downloadClientSvc DownloadClientService
+
+	m       sync.Mutex
+	clients map[int32]clientCheck
+
+	now func() time.Time
+}
+
+type clientCheck struct {
+	err       error
+	checkedAt time.Time
+}
+
+func NewService(log logger.Logger, db Pinger, ircSvc IRCService, feedSvc FeedService, downloadClientSvc DownloadClientService) Service {
+	return &service{
+		log:               log.With().Str("module", "health").Logger(),
+		db:                db,
+		ircSvc:            ircSvc,
+		feedSvc:           feedSvc,
+		downloadClientSvc: downloadClientSvc,
+		clients:           map[int32]clientCheck{},
+		now:               time.Now,
+	}
+}
+
+// Check reports the state of the database, irc networks, feeds and download clients
+func (s *service) Check(ctx context.Context) *domain.HealthReport {
+	now := s.now()
+
+	components := []domain.HealthComponent{s.checkDatabase()}
+
+	// the other components are read from the database
+	if components[0].State == domain.HealthStateDown {
+		return domain.NewHealthReport(components, now)
+	}
+
+	components = append(components, s.checkIRC(ctx)...)
+	components = append(components, s.checkFeeds(ctx, now)...)
+	components = append(components, s.checkDownloadClients(ctx, now)...)
+
+	return domain.NewHealthReport(components, now)
+}
+
+func (s *service) checkDatabase() domain.HealthComponent {
+	c := domain.HealthComponent{Type: domain.HealthComponentDatabase, Name: "database", State: domain.HealthStateOK}
+
+	if err := s.db.Ping(); err != nil {
+		c.State = domain.HealthStateDown
+		c.Message = err.Error()
+	}
+
+	return c
+}
+
+func (s *service) checkIRC(ctx context.Context) []domain.HealthComponent {
+	networks, err := s.ircSvc.GetNetworksWithHealth(ctx)
+	if err != nil {
+		return []domain.HealthComponent{{Type: domain.HealthComponentIRC, Name: "irc", State: domain.HealthStateDown, Message: err.Error()}}
+	}
+
+	components := make([]domain.HealthComponent, 0, len(networks))
+
+	for _, network := range networks {
+		c := domain.HealthComponent{Type: domain.HealthComponentIRC, ID: network.ID, Name: network.Name, State: domain.HealthStateOK}
+
+		switch {
+		case !network.Enabled:
+			c.State = domain.HealthStateDisabled
+
+		case !network.Connected:
+			c.State = domain.HealthStateDown
+			c.Message = "not connected"
+			if n := len(network.ConnectionErrors); n > 0 {
+				c.Message = network.ConnectionErrors[n-1]
+			}
+
+		default:
+			var missing []string
+			for _, channel := range network.Channels {
+				if channel.Enabled && !channel.Detached && !channel.Monitoring {
+					missing = append(missing, channel.Name)
+				}
+			}
+
+			if len(missing) > 0 {
+				c.State = domain.HealthStateDegraded
+				c.Message = fmt.Sprintf("not monitoring %s", strings.Join(missing, ", "))
+			}
+		}
+
+		components = append(components, c)
+	}
+
+	return components
+}
+
+// checkFeeds reports feeds degraded when they didn't fetch successfully for twice their interval
+func (s *service) checkFeeds(ctx context.Context, now time.Time) []domain.HealthComponent {
+	feeds, err := s.feedSvc.Find(ctx)
+	if err != nil {
+		return []domain.HealthComponent{{Type: domain.HealthComponentFeed, Name: "feeds", State: domain.HealthStateDown, Message: err.Error()}}
+	}
+
+	components := make([]domain.HealthComponent, 0, len(feeds))
+
+	for _, feed := range feeds {
+		c := domain.HealthComponent{Type: domain.HealthComponentFeed, ID: int64(feed.ID), Name: feed.Name, State: domain.HealthStateOK}
+
+		if !feed.Enabled {
+			c.State = domain.HealthStateDisabled
+			components = append(components, c)
+			continue
+		}
+
+		// new or changed feeds get the time to run first
+		since := feed.LastRun
+		if feed.UpdatedAt.After(since) {
+			since = feed.UpdatedAt
+		}
+
+		if now.Sub(since) > 2*time.Duration(feed.Interval)*time.Minute+feedGracePeriod {
+			c.State = domain.HealthStateDegraded
+			if feed.LastRun.IsZero() {
+				c.Message = "never fetched successfully"
+			} else {
+				c.Message = fmt.Sprintf("last fetched successfully at %s", feed.LastRun.Format(time.RFC3339))
+			}
+		}
+
+		components = append(components, c)
+	}
+
+	return components
+}
+
+// checkDownloadClients tests the connection of the enabled clients at the same time, results are reused for a minute
+func (s *service) checkDownloadClients(ctx context.Context, now time.Time) []domain.HealthComponent {
+	clients, err := s.downloadClientSvc.List(ctx)
+	if err != nil {
+		return []domain.HealthComponent{{Type: domain.HealthComponentDownloadClient, Name: "download clients", State: domain.HealthStateDown, Message: err.Error()}}
+	}
+
+	components := make([]domain.HealthComponent, len(clients))
+
+	var wg sync.WaitGroup
+
+	for i, client := range clients {
+		components[i] = domain.HealthComponent{Type: domain.HealthComponentDownloadClient, ID: int64(client.ID), Name: client.Name, State: domain.HealthStateOK}
+
+		if !client.Enabled {
+			components[i].State = domain.HealthStateDisabled
+			continue
+		}
+
+		wg.Add(1)
+		go func(c *domain.HealthComponent, client domain.DownloadClient) {
+			defer wg.Done()
+
+			if err := s.testClient(ctx, client, now); err != nil {
+				c.State = domain.HealthStateDown
+				c.Message = err.Error()
+			}
+		}(&components[i], client)
+	}
+
+	wg.Wait()
+
+	return components
+}
+
+func (s *service) testClient(ctx context.Context, client domain.DownloadClient, now time.Time) error {
+	s.m.Lock()
+	check, ok := s.clients[client.ID]
+	s.m.Unlock()
+
+	if ok && now.Sub(check.checkedAt) < clientCheckTTL {
+		return check.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, clientCheckTimeout)
+	defer cancel()
+
+	err := s.downloadClientSvc.Test(ctx, client)
+	if err != nil {
+		s.log.Debug().Err(err).Msgf("download client %s is unreachable", client.Name)
+	}
+
+	s.m.Lock()
+	s.clients[client.ID] = clientCheck{err: err, checkedAt: now}
+	s.m.Unlock()
+
+	return err
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPinger struct {
+	err error
+}
+
+func (p mockPinger) Ping() error {
+	return p.err
+}
+
+type mockIRCService struct {
+	networks []domain.IrcNetworkWithHealth
+}
+
+func (s mockIRCService) GetNetworksWithHealth(_ context.Context) ([]domain.IrcNetworkWithHealth, error) {
+	return s.networks, nil
+}
+
+type mockFeedService struct {
+	feeds []domain.Feed
+}
+
+func (s mockFeedService) Find(_ context.Context) ([]domain.Feed, error) {
+	return s.feeds, nil
+}
+
+type mockDownloadClientService struct {
+	clients []domain.DownloadClient
+	errs    map[int32]error
+	tests   atomic.Int32
+}
+
+func (s *mockDownloadClientService) List(_ context.Context) ([]domain.DownloadClient, error) {
+	return s.clients, nil
+}
+
+func (s *mockDownloadClientService) Test(_ context.Context, client domain.DownloadClient) error {
+	s.tests.Add(1)
+	return s.errs[client.ID]
+}
+
+func newTestService(db Pinger, ircSvc IRCService, feedSvc FeedService, clientSvc DownloadClientService, now time.Time) *service {
+	return &service{
+		log:               zerolog.Nop(),
+		db:                db,
+		ircSvc:            ircSvc,
+		feedSvc:           feedSvc,
+		downloadClientSvc: clientSvc,
+		clients:           map[int32]clientCheck{},
+		now:               func() time.Time { return now },
+	}
+}
+
+func TestService_Check(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	ircSvc := mockIRCService{networks: []domain.IrcNetworkWithHealth{
+		{ID: 1, Name: "healthy", Enabled: true, Connected: true, Channels: []domain.ChannelWithHealth{
+			{Name: "#announce", Enabled: true, Monitoring: true},
+			{Name: "#detached", Enabled: true, Detached: true},
+		}},
+		{ID: 2, Name: "unmonitored", Enabled: true, Connected: true, Channels: []domain.ChannelWithHealth{
+			{Name: "#announce", Enabled: true},
+		}},
+		{ID: 3, Name: "disconnected", Enabled: true, ConnectionErrors: []string{"first", "connection refused"}},
+		{ID: 4, Name: "off"},
+	}}
+
+	feedSvc := mockFeedService{feeds: []domain.Feed{
+		{ID: 1, Name: "fresh", Enabled: true, Interval: 15, LastRun: now.Add(-10 * time.Minute)},
+		{ID: 2, Name: "stale", Enabled: true, Interval: 15, LastRun: now.Add(-time.Hour)},
+		{ID: 3, Name: "new", Enabled: true, Interval: 15, UpdatedAt: now.Add(-time.Minute)},
+		{ID: 4, Name: "off"},
+	}}
+
+	clientSvc := &mockDownloadClientService{
+		clients: []domain.DownloadClient{
+			{ID: 1, Name: "up", Enabled: true},
+			{ID: 2, Name: "unreachable", Enabled: true},
+			{ID: 3, Name: "off"},
+		},
+		errs: map[int32]error{2: errors.New("connection refused")},
+	}
+
+	s := newTestService(mockPinger{}, ircSvc, feedSvc, clientSvc, now)
+
+	report := s.Check(context.Background())
+
+	assert.Equal(t, domain.HealthStateDegraded, report.Status)
+	assert.Equal(t, now, report.CheckedAt)
+	assert.Equal(t, []domain.HealthComponent{
+		{Type: domain.HealthComponentDatabase, Name: "database", State: domain.HealthStateOK},
+		{Type: domain.HealthComponentIRC, ID: 1, Name: "healthy", State: domain.HealthStateOK},
+		{Type: domain.HealthComponentIRC, ID: 2, Name: "unmonitored", State: domain.HealthStateDegraded, Message: "not monitoring #announce"},
+		{Type: domain.HealthComponentIRC, ID: 3, Name: "disconnected", State: domain.HealthStateDown, Message: "connection refused"},
+		{Type: domain.HealthComponentIRC, ID: 4, Name: "off", State: domain.HealthStateDisabled},
+		{Type: domain.HealthComponentFeed, ID: 1, Name: "fresh", State: domain.HealthStateOK},
+		{Type: domain.HealthComponentFeed, ID: 2, Name: "stale", State: domain.HealthStateDegraded, Message: "last fetched successfully at 2024-06-01T11:00:00Z"},
+		{Type: domain.HealthComponentFeed, ID: 3, Name: "new", State: domain.HealthStateOK},
+		{Type: domain.HealthComponentFeed, ID: 4, Name: "off", State: domain.HealthStateDisabled},
+		{Type: domain.HealthComponentDownloadClient, ID: 1, Name: "up", State: domain.HealthStateOK},
+		{Type: domain.HealthComponentDownloadClient, ID: 2, Name: "unreachable", State: domain.HealthStateDown, Message: "connection refused"},
+		{Type: domain.HealthComponentDownloadClient, ID: 3, Name: "off", State: domain.HealthStateDisabled},
+	}, report.Components)
+}
+
+func TestService_Check_databaseDown(t *testing.T) {
+	s := newTestService(mockPinger{err: errors.New("database is locked")}, nil, nil, nil, time.Now())
+
+	report := s.Check(context.Background())
+
+	assert.Equal(t, domain.HealthStateDown, report.Status)
+	assert.Equal(t, []domain.HealthComponent{
+		{Type: domain.HealthComponentDatabase, Name: "database", State: domain.HealthStateDown, Message: "database is locked"},
+	}, report.Components)
+}
+
+func TestService_Check_clientsCached(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	clientSvc := &mockDownloadClientService{clients: []domain.DownloadClient{{ID: 1, Name: "up", Enabled: true}}}
+
+	s := newTestService(mockPinger{}, mockIRCService{}, mockFeedService{}, clientSvc, now)
+
+	s.Check(context.Background())
+	s.Check(context.Background())
+	assert.Equal(t, int32(1), clientSvc.tests.Load())
+
+	s.now = func() time.Time { return now.Add(clientCheckTTL) }
+
+	s.Check(context.Background())
+	assert.Equal(t, int32(2), clientSvc.tests.Load())
+}
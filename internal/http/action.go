@@ -20,6 +20,8 @@ type actionService interface {
 	Store(ctx context.Context, action domain.Action) (*domain.Action, error)
 	Delete(ctx context.Context, req *domain.DeleteActionRequest) error
 	ToggleEnabled(actionID int) error
+	ListFreeleechTokenBudgets(ctx context.Context) ([]domain.FreeleechTokenBudget, error)
+	UpdateFreeleechTokenBudget(ctx context.Context, budget domain.FreeleechTokenBudget) error
 }
 
 type actionHandler struct {
@@ -38,6 +40,11 @@ func (h actionHandler) Routes(r chi.Router) {
 	r.Get("/", h.getActions)
 	r.Post("/", h.storeAction)
 
+	r.Route("/freeleech-tokens", func(r chi.Router) {
+		r.Get("/", h.listFreeleechTokenBudgets)
+		r.Put("/", h.updateFreeleechTokenBudget)
+	})
+
 	r.Route("/{actionID}", func(r chi.Router) {
 		r.Delete("/", h.deleteAction)
 		r.Put("/", h.updateAction)
@@ -124,3 +131,29 @@ func parseInt(s string) (int, error) {
 	}
 	return int(u), nil
 }
+
+// listFreeleechTokenBudgets returns the monthly freeleech token budget per indexer and the tokens used this month
+func (h actionHandler) listFreeleechTokenBudgets(w http.ResponseWriter, r *http.Request) {
+	budgets, err := h.service.ListFreeleechTokenBudgets(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, budgets)
+}
+
+func (h actionHandler) updateFreeleechTokenBudget(w http.ResponseWriter, r *http.Request) {
+	var data domain.FreeleechTokenBudget
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.UpdateFreeleechTokenBudget(r.Context(), data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
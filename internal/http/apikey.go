@@ -6,6 +6,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -19,7 +20,7 @@ type apikeyService interface {
 	List(ctx context.Context) ([]domain.APIKey, error)
 	Store(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
-	ValidateAPIKey(ctx context.Context, token string) bool
+	ValidateAPIKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type apikeyHandler struct {
@@ -47,7 +48,20 @@ func (h apikeyHandler) list(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	render.JSON(w, r, keys)
+	user, restricted := restrictedUser(r.Context())
+	if !restricted {
+		render.JSON(w, r, keys)
+		return
+	}
+
+	own := make([]domain.APIKey, 0)
+	for _, key := range keys {
+		if key.UserID == user.ID {
+			own = append(own, key)
+		}
+	}
+
+	render.JSON(w, r, own)
 }
 
 func (h apikeyHandler) store(w http.ResponseWriter, r *http.Request) {
@@ -57,6 +71,24 @@ func (h apikeyHandler) store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if user := domain.UserFromContext(r.Context()); user != nil {
+		data.UserID = user.ID
+
+		// keys can't do more than the role of their owner allows
+		if user.Role != domain.UserRoleAdmin {
+			if len(data.Scopes) == 0 {
+				data.Scopes = user.Role.Scopes()
+			}
+
+			for _, scope := range data.Scopes {
+				if !user.Role.HasScope(domain.APIKeyScope(scope)) {
+					h.encoder.ErrorCode(w, http.StatusForbidden, errorCodeMissingScope, fmt.Sprintf("role %s is missing scope %s", user.Role, scope))
+					return
+				}
+			}
+		}
+	}
+
 	if err := h.service.Store(r.Context(), &data); err != nil {
 		h.encoder.Error(w, err)
 		return
@@ -68,6 +100,27 @@ func (h apikeyHandler) store(w http.ResponseWriter, r *http.Request) {
 func (h apikeyHandler) delete(w http.ResponseWriter, r *http.Request) {
 	apiKey := chi.URLParam(r, "apikey")
 
+	if user, restricted := restrictedUser(r.Context()); restricted {
+		keys, err := h.service.List(r.Context())
+		if err != nil {
+			h.encoder.Error(w, err)
+			return
+		}
+
+		owned := false
+		for _, key := range keys {
+			if key.Key == apiKey && key.UserID == user.ID {
+				owned = true
+				break
+			}
+		}
+
+		if !owned {
+			h.encoder.NotFoundErr(w, errors.New("api key %s not found", apiKey))
+			return
+		}
+	}
+
 	if err := h.service.Delete(r.Context(), apiKey); err != nil {
 		if errors.Is(err, domain.ErrRecordNotFound) {
 			h.encoder.NotFoundErr(w, errors.New("api key %s not found", apiKey))
@@ -80,3 +133,13 @@ func (h apikeyHandler) delete(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+// restrictedUser returns the user of the request if it may only manage its own things
+func restrictedUser(ctx context.Context) (*domain.User, bool) {
+	user := domain.UserFromContext(ctx)
+	if user == nil || user.Role == domain.UserRoleAdmin {
+		return nil, false
+	}
+
+	return user, true
+}
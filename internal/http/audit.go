@@ -0,0 +1,254 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+)
+
+type auditService interface {
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+	Find(ctx context.Context, params domain.AuditQueryParams) (*domain.FindAuditResponse, error)
+}
+
+type auditHandler struct {
+	encoder encoder
+	service auditService
+}
+
+func newAuditHandler(encoder encoder, service auditService) *auditHandler {
+	return &auditHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h auditHandler) Routes(r chi.Router) {
+	r.Get("/", h.find)
+}
+
+func (h auditHandler) find(w http.ResponseWriter, r *http.Request) {
+	params := domain.AuditQueryParams{
+		Limit:    50,
+		Resource: domain.AuditResource(r.URL.Query().Get("resource")),
+		Username: r.URL.Query().Get("user"),
+	}
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || limit == 0 {
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "limit parameter is invalid")
+			return
+		}
+		params.Limit = limit
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "offset parameter is invalid")
+			return
+		}
+		params.Offset = offset
+	}
+
+	resp, err := h.service.Find(r.Context(), params)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, resp)
+}
+
+type auditOperation struct {
+	action   domain.AuditAction
+	resource domain.AuditResource
+}
+
+// auditedOperations are the routes changing the configuration, keyed like openAPIOperations.
+// Tests, manual runs and the like don't change anything and are left out.
+var auditedOperations = map[string]auditOperation{
+	"POST /actions":                           {domain.AuditActionCreate, domain.AuditResourceAction},
+	"PUT /actions/freeleech-tokens":           {domain.AuditActionUpdate, domain.AuditResourceAction},
+	"PUT /actions/{actionID}":                 {domain.AuditActionUpdate, domain.AuditResourceAction},
+	"DELETE /actions/{actionID}":              {domain.AuditActionDelete, domain.AuditResourceAction},
+	"PATCH /actions/{actionID}/toggleEnabled": {domain.AuditActionUpdate, domain.AuditResourceAction},
+
+	"PATCH /config":                 {domain.AuditActionUpdate, domain.AuditResourceSettings},
+	"PUT /release/retention":        {domain.AuditActionUpdate, domain.AuditResourceSettings},
+	"PUT /notification/preferences": {domain.AuditActionUpdate, domain.AuditResourceSettings},
+
+	"POST /download_clients":              {domain.AuditActionCreate, domain.AuditResourceDownloadClient},
+	"PUT /download_clients":               {domain.AuditActionUpdate, domain.AuditResourceDownloadClient},
+	"DELETE /download_clients/{clientID}": {domain.AuditActionDelete, domain.AuditResourceDownloadClient},
+
+	"POST /feeds":                   {domain.AuditActionCreate, domain.AuditResourceFeed},
+	"PUT /feeds/{feedID}":           {domain.AuditActionUpdate, domain.AuditResourceFeed},
+	"DELETE /feeds/{feedID}":        {domain.AuditActionDelete, domain.AuditResourceFeed},
+	"PATCH /feeds/{feedID}/enabled": {domain.AuditActionUpdate, domain.AuditResourceFeed},
+
+	"POST /filters":                         {domain.AuditActionCreate, domain.AuditResourceFilter},
+	"POST /filters/bulk":                    {domain.AuditActionUpdate, domain.AuditResourceFilter},
+	"POST /filters/move":                    {domain.AuditActionUpdate, domain.AuditResourceFilter},
+	"PUT /filters/{filterID}":               {domain.AuditActionUpdate, domain.AuditResourceFilter},
+	"PATCH /filters/{filterID}":             {domain.AuditActionUpdate, domain.AuditResourceFilter},
+	"DELETE /filters/{filterID}":            {domain.AuditActionDelete, domain.AuditResourceFilter},
+	"POST /filters/{filterID}/clone":        {domain.AuditActionCreate, domain.AuditResourceFilter},
+	"PUT /filters/{filterID}/enabled":       {domain.AuditActionUpdate, domain.AuditResourceFilter},
+	"POST /filters/{filterID}/restore":      {domain.AuditActionUpdate, domain.AuditResourceFilter},
+	"POST /filters/groups":                  {domain.AuditActionCreate, domain.AuditResourceFilterGroup},
+	"PUT /filters/groups/{groupID}":         {domain.AuditActionUpdate, domain.AuditResourceFilterGroup},
+	"DELETE /filters/groups/{groupID}":      {domain.AuditActionDelete, domain.AuditResourceFilterGroup},
+	"PUT /filters/groups/{groupID}/enabled": {domain.AuditActionUpdate, domain.AuditResourceFilterGroup},
+
+	"POST /indexer":                      {domain.AuditActionCreate, domain.AuditResourceIndexer},
+	"PUT /indexer/{indexerID}":           {domain.AuditActionUpdate, domain.AuditResourceIndexer},
+	"DELETE /indexer/{indexerID}":        {domain.AuditActionDelete, domain.AuditResourceIndexer},
+	"PATCH /indexer/{indexerID}/enabled": {domain.AuditActionUpdate, domain.AuditResourceIndexer},
+
+	"POST /irc":                             {domain.AuditActionCreate, domain.AuditResourceIRCNetwork},
+	"PUT /irc/network/{networkID}":          {domain.AuditActionUpdate, domain.AuditResourceIRCNetwork},
+	"DELETE /irc/network/{networkID}":       {domain.AuditActionDelete, domain.AuditResourceIRCNetwork},
+	"POST /irc/network/{networkID}/channel": {domain.AuditActionUpdate, domain.AuditResourceIRCNetwork},
+
+	"POST /keys":            {domain.AuditActionCreate, domain.AuditResourceAPIKey},
+	"DELETE /keys/{apikey}": {domain.AuditActionDelete, domain.AuditResourceAPIKey},
+
+	"POST /lists":                   {domain.AuditActionCreate, domain.AuditResourceList},
+	"PUT /lists/{listID}":           {domain.AuditActionUpdate, domain.AuditResourceList},
+	"DELETE /lists/{listID}":        {domain.AuditActionDelete, domain.AuditResourceList},
+	"PATCH /lists/{listID}/enabled": {domain.AuditActionUpdate, domain.AuditResourceList},
+
+	"POST /notification":                    {domain.AuditActionCreate, domain.AuditResourceNotification},
+	"PUT /notification/{notificationID}":    {domain.AuditActionUpdate, domain.AuditResourceNotification},
+	"DELETE /notification/{notificationID}": {domain.AuditActionDelete, domain.AuditResourceNotification},
+
+	"POST /proxy":             {domain.AuditActionCreate, domain.AuditResourceProxy},
+	"PUT /proxy/{proxyID}":    {domain.AuditActionUpdate, domain.AuditResourceProxy},
+	"DELETE /proxy/{proxyID}": {domain.AuditActionDelete, domain.AuditResourceProxy},
+
+	"POST /users":                {domain.AuditActionCreate, domain.AuditResourceUser},
+	"PATCH /users/{userID}/role": {domain.AuditActionUpdate, domain.AuditResourceUser},
+	"DELETE /users/{userID}":     {domain.AuditActionDelete, domain.AuditResourceUser},
+}
+
+// auditResponseLimit is how much of a response is read for the id and name of created resources
+const auditResponseLimit = 64 * 1024
+
+// AuditLog records the successful changes of the configuration with the user or api key making them.
+// It runs after IsAuthenticated which puts them in the context.
+func AuditLog(log zerolog.Logger, service auditService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			body := &limitedBuffer{limit: auditResponseLimit}
+			ww.Tee(body)
+
+			next.ServeHTTP(ww, r)
+
+			// the route is only known once routed
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil {
+				return
+			}
+
+			key := r.Method + " " + auditRoute(rctx.RoutePattern())
+
+			op, ok := auditedOperations[key]
+			if !ok {
+				return
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusBadRequest {
+				return
+			}
+
+			entry := &domain.AuditEntry{
+				Action:      op.action,
+				Resource:    op.resource,
+				Description: openAPIOperations[key].Summary,
+			}
+
+			for i := len(rctx.URLParams.Keys) - 1; i >= 0; i-- {
+				// only ids, other params like api keys are secret
+				if strings.HasSuffix(rctx.URLParams.Keys[i], "ID") {
+					entry.ResourceID = rctx.URLParams.Values[i]
+					break
+				}
+			}
+
+			if !body.overflow {
+				var resource struct {
+					ID   json.Number `json:"id"`
+					Name string      `json:"name"`
+				}
+				if err := json.Unmarshal(body.Bytes(), &resource); err == nil {
+					if entry.ResourceID == "" {
+						entry.ResourceID = resource.ID.String()
+					}
+					entry.ResourceName = resource.Name
+				}
+			}
+
+			if err := service.Record(r.Context(), entry); err != nil {
+				log.Error().Err(err).Msgf("could not record audit entry for %s", key)
+			}
+		})
+	}
+}
+
+// auditRoute returns the route pattern without /api like the keys of openAPIOperations
+func auditRoute(pattern string) string {
+	if i := strings.Index(pattern, "/api/"); i >= 0 {
+		pattern = pattern[i+len("/api"):]
+	}
+
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return "/"
+	}
+
+	return pattern
+}
+
+// limitedBuffer keeps the first bytes written to it and remembers if there was more
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); len(p) > room {
+		b.overflow = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+
+	return b.Buffer.Write(p)
+}
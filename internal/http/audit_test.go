@@ -0,0 +1,97 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditServiceMock struct {
+	entries []domain.AuditEntry
+}
+
+func (s *auditServiceMock) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	if user := domain.UserFromContext(ctx); user != nil {
+		entry.Username = user.Username
+	}
+
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+func (s *auditServiceMock) Find(ctx context.Context, params domain.AuditQueryParams) (*domain.FindAuditResponse, error) {
+	return &domain.FindAuditResponse{Data: s.entries, TotalCount: uint64(len(s.entries))}, nil
+}
+
+// TestAuditedOperations_exist makes sure the audited routes are real routes
+func TestAuditedOperations_exist(t *testing.T) {
+	for key := range auditedOperations {
+		assert.Contains(t, openAPIOperations, key, "audited route does not exist")
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	service := &auditServiceMock{}
+
+	withUser := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithUser(r.Context(), &domain.User{Username: "admin"})))
+		})
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api", func(r chi.Router) {
+		r.Use(withUser)
+		r.Use(AuditLog(zerolog.Nop(), service))
+
+		r.Route("/filters", func(r chi.Router) {
+			r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id": 12, "name": "movies"}`))
+			})
+			r.Post("/dry-run", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			r.Put("/{filterID}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+			r.Delete("/{filterID}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+		})
+
+		r.Delete("/keys/{apikey}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+	})
+
+	for _, req := range []struct {
+		method string
+		target string
+	}{
+		{http.MethodPost, "/api/filters"},
+		{http.MethodPost, "/api/filters/dry-run"},
+		{http.MethodPut, "/api/filters/12"},
+		{http.MethodDelete, "/api/filters/13"},
+		{http.MethodDelete, "/api/keys/secret"},
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.target, nil))
+	}
+
+	assert.Equal(t, []domain.AuditEntry{
+		{Action: domain.AuditActionCreate, Resource: domain.AuditResourceFilter, ResourceID: "12", ResourceName: "movies", Description: "Create filter", Username: "admin"},
+		{Action: domain.AuditActionUpdate, Resource: domain.AuditResourceFilter, ResourceID: "12", Description: "Update filter", Username: "admin"},
+		{Action: domain.AuditActionDelete, Resource: domain.AuditResourceAPIKey, Description: "Delete api key", Username: "admin"},
+	}, service.entries)
+}
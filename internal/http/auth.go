@@ -6,9 +6,11 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/auth"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
 
@@ -22,6 +24,10 @@ type authService interface {
 	Login(ctx context.Context, username, password string) (*domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
 	UpdateUser(ctx context.Context, req domain.UpdateUserRequest) error
+	OIDCAuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error)
+	OIDCLogin(ctx context.Context, redirectURL, code, nonce, verifier string) (*domain.User, error)
+	FindUserByID(ctx context.Context, id int) (*domain.User, error)
+	userService
 }
 
 type authHandler struct {
@@ -50,61 +56,88 @@ func (h authHandler) Routes(r chi.Router) {
 	r.Post("/onboard", h.onboard)
 	r.Get("/onboard", h.canOnboard)
 
+	r.Route("/oidc", func(r chi.Router) {
+		r.Get("/config", h.getOIDCConfig)
+		r.Get("/login", h.oidcLogin)
+		r.Get("/callback", h.oidcCallback)
+	})
+
 	// Group for authenticated routes
 	r.Group(func(r chi.Router) {
 		r.Use(h.server.IsAuthenticated)
 
 		r.Post("/logout", h.logout)
 		r.Get("/validate", h.validate)
+		r.Get("/user", h.getUser)
 		r.Patch("/user/{username}", h.updateUser)
 	})
 }
 
 func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
+	if h.config.OIDC.Enabled && h.config.OIDC.DisableBuiltInLogin {
+		h.encoder.StatusError(w, http.StatusForbidden, errors.New("built-in login is disabled, log in with oidc"))
+		return
+	}
+
 	var data domain.User
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
 		return
 	}
 
-	if _, err := h.service.Login(r.Context(), data.Username, data.Password); err != nil {
+	user, err := h.service.Login(r.Context(), data.Username, data.Password)
+	if err != nil {
 		h.log.Error().Err(err).Msgf("Auth: Failed login attempt username: [%s] ip: %s", data.Username, r.RemoteAddr)
 		h.encoder.StatusError(w, http.StatusForbidden, errors.New("could not login: bad credentials"))
 		return
 	}
 
-	// create new session
+	if err := h.startSession(w, r, user, authMethodPassword); err != nil {
+		h.log.Error().Err(err).Msgf("Auth: Failed to create session for username: [%s] ip: %s", data.Username, r.RemoteAddr)
+		h.encoder.StatusError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+const (
+	authMethodPassword = "password"
+	authMethodOIDC     = "oidc"
+)
+
+// startSession logs the user in with a new session cookie
+func (h authHandler) startSession(w http.ResponseWriter, r *http.Request, user *domain.User, method string) error {
 	session, err := h.cookieStore.Get(r, "user_session")
 	if err != nil {
-		h.log.Error().Err(err).Msgf("Auth: Failed to create cookies with attempt username: [%s] ip: %s", data.Username, r.RemoteAddr)
-		h.encoder.StatusError(w, http.StatusInternalServerError, errors.New("could not create cookies"))
-		return
+		return errors.New("could not create cookies")
 	}
 
 	// Set user as authenticated
 	session.Values["authenticated"] = true
 	session.Values["created"] = time.Now().Unix()
+	session.Values["user_id"] = user.ID
+	session.Values["username"] = user.Username
+	session.Values["auth_method"] = method
 
 	// Set cookie options
 	session.Options.HttpOnly = true
 	session.Options.SameSite = http.SameSiteLaxMode
 	session.Options.Path = h.config.BaseURL
 
-	// autobrr does not support serving on TLS / https, so this is only available behind reverse proxy
-	// if forwarded protocol is https then set cookie secure
+	// if served with tls or the forwarded protocol is https then set cookie secure
 	// SameSite Strict can only be set with a secure cookie. So we overwrite it here if possible.
 	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie/SameSite
-	if r.Header.Get("X-Forwarded-Proto") == "https" {
+	if isSecureRequest(r) {
 		session.Options.Secure = true
 		session.Options.SameSite = http.SameSiteStrictMode
 	}
 
 	if err := session.Save(r, w); err != nil {
-		h.encoder.StatusError(w, http.StatusInternalServerError, errors.Wrap(err, "could not save session"))
-		return
+		return errors.Wrap(err, "could not save session")
 	}
 
-	h.encoder.NoContent(w)
+	return nil
 }
 
 func (h authHandler) logout(w http.ResponseWriter, r *http.Request) {
@@ -167,6 +200,10 @@ func (h authHandler) canOnboard(w http.ResponseWriter, r *http.Request) {
 
 // onboardEligible checks if the onboarding process is eligible.
 func (h authHandler) onboardEligible(ctx context.Context) (int, error) {
+	if h.config.OIDC.Enabled && h.config.OIDC.DisableBuiltInLogin {
+		return http.StatusServiceUnavailable, errors.New("onboarding unavailable, log in with oidc")
+	}
+
 	userCount, err := h.service.GetUserCount(ctx)
 	if err != nil {
 		return http.StatusInternalServerError, errors.New("could not get user count")
@@ -191,6 +228,30 @@ func (h authHandler) validate(w http.ResponseWriter, r *http.Request) {
 	h.encoder.NoContent(w)
 }
 
+type currentUser struct {
+	ID         int             `json:"id"`
+	Username   string          `json:"username"`
+	Role       domain.UserRole `json:"role"`
+	AuthMethod string          `json:"auth_method"`
+}
+
+// getUser returns the user of the session, eg. after logging in with oidc
+func (h authHandler) getUser(w http.ResponseWriter, r *http.Request) {
+	session, ok := r.Context().Value("session").(*sessions.Session)
+	user := domain.UserFromContext(r.Context())
+	if !ok || session == nil || user == nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("current user requires a user session"))
+		return
+	}
+
+	res := currentUser{ID: user.ID, Username: user.Username, Role: user.Role, AuthMethod: authMethodPassword}
+	if method, ok := session.Values["auth_method"].(string); ok {
+		res.AuthMethod = method
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, res)
+}
+
 func (h authHandler) updateUser(w http.ResponseWriter, r *http.Request) {
 	var data domain.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
@@ -200,6 +261,12 @@ func (h authHandler) updateUser(w http.ResponseWriter, r *http.Request) {
 
 	data.UsernameCurrent = chi.URLParam(r, "username")
 
+	// admins can change every user, other users only themselves
+	if user := domain.UserFromContext(r.Context()); user != nil && user.Role != domain.UserRoleAdmin && user.Username != data.UsernameCurrent {
+		h.encoder.ErrorCode(w, http.StatusForbidden, errorCodeMissingScope, "users can only update themselves")
+		return
+	}
+
 	if err := h.service.UpdateUser(r.Context(), data); err != nil {
 		h.encoder.StatusError(w, http.StatusForbidden, err)
 		return
@@ -208,3 +275,134 @@ func (h authHandler) updateUser(w http.ResponseWriter, r *http.Request) {
 	// send response as ok
 	h.encoder.StatusResponseMessage(w, http.StatusOK, "user successfully updated")
 }
+
+type oidcConfigResponse struct {
+	Enabled             bool `json:"enabled"`
+	DisableBuiltInLogin bool `json:"disable_built_in_login"`
+}
+
+func (h authHandler) getOIDCConfig(w http.ResponseWriter, r *http.Request) {
+	h.encoder.StatusResponse(w, http.StatusOK, oidcConfigResponse{
+		Enabled:             h.config.OIDC.Enabled,
+		DisableBuiltInLogin: h.config.OIDC.Enabled && h.config.OIDC.DisableBuiltInLogin,
+	})
+}
+
+// oidcStateMaxAge is how long the user has to log in at the provider
+const oidcStateMaxAge = 10 * 60
+
+// oidcLogin sends the user to the openid provider, the state, nonce and pkce verifier
+// are kept in a short-lived cookie to check the callback against
+func (h authHandler) oidcLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.config.OIDC.Enabled {
+		h.encoder.StatusError(w, http.StatusNotFound, errors.New("oidc login is not enabled"))
+		return
+	}
+
+	state, nonce, verifier := auth.NewOIDCVerifier(), auth.NewOIDCVerifier(), auth.NewOIDCVerifier()
+
+	authURL, err := h.service.OIDCAuthCodeURL(r.Context(), h.oidcRedirectURL(r), state, nonce, verifier)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Auth: could not start oidc login")
+		h.encoder.StatusError(w, http.StatusBadGateway, errors.New("could not reach the openid provider"))
+		return
+	}
+
+	session, _ := h.cookieStore.New(r, "oidc_state")
+	session.Values["state"] = state
+	session.Values["nonce"] = nonce
+	session.Values["verifier"] = verifier
+
+	// the callback is a cross-site navigation from the provider, strict cookies are not sent
+	session.Options.MaxAge = oidcStateMaxAge
+	session.Options.HttpOnly = true
+	session.Options.SameSite = http.SameSiteLaxMode
+	session.Options.Path = h.config.BaseURL
+	session.Options.Secure = isSecureRequest(r)
+
+	if err := session.Save(r, w); err != nil {
+		h.encoder.StatusError(w, http.StatusInternalServerError, errors.Wrap(err, "could not save session"))
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h authHandler) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	if !h.config.OIDC.Enabled {
+		h.encoder.StatusError(w, http.StatusNotFound, errors.New("oidc login is not enabled"))
+		return
+	}
+
+	query := r.URL.Query()
+
+	if errCode := query.Get("error"); errCode != "" {
+		h.log.Error().Msgf("Auth: oidc login failed: %s %s ip: %s", errCode, query.Get("error_description"), r.RemoteAddr)
+		h.encoder.StatusError(w, http.StatusForbidden, errors.New("could not login: %s", errCode))
+		return
+	}
+
+	stateSession, err := h.cookieStore.Get(r, "oidc_state")
+	if err != nil || stateSession.IsNew {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("oidc login expired, try again"))
+		return
+	}
+
+	state, _ := stateSession.Values["state"].(string)
+	nonce, _ := stateSession.Values["nonce"].(string)
+	verifier, _ := stateSession.Values["verifier"].(string)
+
+	// the state is used once
+	stateSession.Options.MaxAge = -1
+	stateSession.Options.Path = h.config.BaseURL
+	if err := stateSession.Save(r, w); err != nil {
+		h.log.Error().Err(err).Msgf("could not remove oidc state: %s", r.RemoteAddr)
+	}
+
+	if state == "" || query.Get("state") != state {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("oidc state mismatch"))
+		return
+	}
+
+	user, err := h.service.OIDCLogin(r.Context(), h.oidcRedirectURL(r), query.Get("code"), nonce, verifier)
+	if err != nil {
+		h.log.Error().Err(err).Msgf("Auth: Failed oidc login attempt ip: %s", r.RemoteAddr)
+		h.encoder.StatusError(w, http.StatusForbidden, errors.New("could not login with oidc"))
+		return
+	}
+
+	if err := h.startSession(w, r, user, authMethodOIDC); err != nil {
+		h.log.Error().Err(err).Msgf("Auth: Failed to create session for oidc username: [%s] ip: %s", user.Username, r.RemoteAddr)
+		h.encoder.StatusError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.log.Info().Msgf("Auth: oidc login for username: [%s] ip: %s", user.Username, r.RemoteAddr)
+
+	// the web app picks up the session on the login page
+	http.Redirect(w, r, h.config.BaseURL+"login", http.StatusFound)
+}
+
+// oidcRedirectURL is the configured callback url, or the callback of this instance as reached by the user
+func (h authHandler) oidcRedirectURL(r *http.Request) string {
+	if h.config.OIDC.RedirectURL != "" {
+		return h.config.OIDC.RedirectURL
+	}
+
+	scheme := "http"
+	if isSecureRequest(r) {
+		scheme = "https"
+	}
+
+	host := r.Host
+	if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+
+	return fmt.Sprintf("%s://%s%sapi/auth/oidc/callback", scheme, host, h.config.BaseURL)
+}
+
+// isSecureRequest reports whether the client reached autobrr over https, directly or through a reverse proxy
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
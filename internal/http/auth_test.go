@@ -74,6 +74,59 @@ func (a authServiceMock) UpdateUser(ctx context.Context, req domain.UpdateUserRe
 	return nil
 }
 
+func (a authServiceMock) OIDCAuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error) {
+	return "", errors.New("oidc login is not enabled")
+}
+
+func (a authServiceMock) OIDCLogin(ctx context.Context, redirectURL, code, nonce, verifier string) (*domain.User, error) {
+	return nil, errors.New("oidc login is not enabled")
+}
+
+func (a authServiceMock) FindUserByID(ctx context.Context, id int) (*domain.User, error) {
+	for _, u := range a.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+func (a authServiceMock) ListUsers(ctx context.Context) ([]domain.User, error) {
+	users := make([]domain.User, 0, len(a.users))
+	for _, u := range a.users {
+		users = append(users, *u)
+	}
+
+	return users, nil
+}
+
+func (a authServiceMock) AddUser(ctx context.Context, req domain.CreateUserRequest) error {
+	return a.CreateUser(ctx, req)
+}
+
+func (a authServiceMock) UpdateUserRole(ctx context.Context, id int, role domain.UserRole) error {
+	u, err := a.FindUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	u.Role = role
+
+	return nil
+}
+
+func (a authServiceMock) DeleteUser(ctx context.Context, id int) error {
+	u, err := a.FindUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	delete(a.users, u.Username)
+
+	return nil
+}
+
 func setupServer() chi.Router {
 	r := chi.NewRouter()
 	//r.Use(middleware.Logger)
@@ -109,6 +162,7 @@ func TestAuthHandlerLogin(t *testing.T) {
 	server := Server{
 		log:         logger,
 		cookieStore: cookieStore,
+		authService: service,
 	}
 
 	handler := newAuthHandler(encoder, logger, server, &domain.Config{}, cookieStore, service)
@@ -170,6 +224,7 @@ func TestAuthHandlerValidateOK(t *testing.T) {
 	server := Server{
 		log:         logger,
 		cookieStore: cookieStore,
+		authService: service,
 	}
 
 	handler := newAuthHandler(encoder, logger, server, &domain.Config{}, cookieStore, service)
@@ -241,6 +296,7 @@ func TestAuthHandlerValidateBad(t *testing.T) {
 	server := Server{
 		log:         logger,
 		cookieStore: cookieStore,
+		authService: service,
 	}
 
 	handler := newAuthHandler(encoder, logger, server, &domain.Config{}, cookieStore, service)
@@ -335,6 +391,7 @@ func TestAuthHandlerLogout(t *testing.T) {
 	server := Server{
 		log:         logger,
 		cookieStore: cookieStore,
+		authService: service,
 	}
 
 	handler := newAuthHandler(encoder, logger, server, &domain.Config{}, cookieStore, service)
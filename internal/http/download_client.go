@@ -22,6 +22,8 @@ type downloadClientService interface {
 	Update(ctx context.Context, client *domain.DownloadClient) error
 	Delete(ctx context.Context, clientID int32) error
 	Test(ctx context.Context, client domain.DownloadClient) error
+	GetInventory(ctx context.Context, clientID int32, refresh bool) (*domain.DownloadClientInventory, error)
+	SearchTorrents(ctx context.Context, query string) (*domain.DownloadClientSearchResult, error)
 }
 
 type downloadClientHandler struct {
@@ -41,10 +43,12 @@ func (h downloadClientHandler) Routes(r chi.Router) {
 	r.Post("/", h.store)
 	r.Put("/", h.update)
 	r.Post("/test", h.test)
+	r.Get("/search", h.search)
 
 	r.Route("/{clientID}", func(r chi.Router) {
 		r.Get("/", h.findByID)
 		r.Delete("/", h.delete)
+		r.Get("/inventory", h.getInventory)
 	})
 }
 
@@ -55,6 +59,12 @@ func (h downloadClientHandler) listDownloadClients(w http.ResponseWriter, r *htt
 		return
 	}
 
+	if !canReadCredentials(r) {
+		for i := range clients {
+			clients[i] = clients[i].Redacted()
+		}
+	}
+
 	h.encoder.StatusResponse(w, http.StatusOK, clients)
 }
 
@@ -75,6 +85,11 @@ func (h downloadClientHandler) findByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !canReadCredentials(r) {
+		redacted := client.Redacted()
+		client = &redacted
+	}
+
 	h.encoder.StatusResponse(w, http.StatusOK, client)
 }
 
@@ -139,3 +154,37 @@ func (h downloadClientHandler) delete(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+func (h downloadClientHandler) getInventory(w http.ResponseWriter, r *http.Request) {
+	clientID, err := strconv.ParseInt(chi.URLParam(r, "clientID"), 10, 32)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	refresh := r.URL.Query().Get("refresh") == "true"
+
+	inventory, err := h.service.GetInventory(r.Context(), int32(clientID), refresh)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("download client with id %d not found", clientID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, inventory)
+}
+
+// search looks up a torrent by name or hash in all enabled torrent clients, eg. /api/download_clients/search?q=<hash>
+func (h downloadClientHandler) search(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.SearchTorrents(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
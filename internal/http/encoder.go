@@ -6,13 +6,24 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
 )
 
-type encoder struct{}
+type encoder struct {
+	log zerolog.Logger
+}
 
+// errorResponse is the body of all api errors
 type errorResponse struct {
-	Message string `json:"message"`
-	Status  int    `json:"status,omitempty"`
+	Code      string                   `json:"code"`
+	Message   string                   `json:"message"`
+	Status    int                      `json:"status,omitempty"`
+	Errors    []domain.ValidationError `json:"errors,omitempty"`
+	RequestID string                   `json:"request_id,omitempty"`
 }
 
 type statusResponse struct {
@@ -67,44 +78,65 @@ func (e encoder) NoContent(w http.ResponseWriter) {
 }
 
 func (e encoder) StatusNotFound(w http.ResponseWriter) {
-	w.WriteHeader(http.StatusNotFound)
+	e.writeError(w, http.StatusNotFound, "", http.StatusText(http.StatusNotFound), nil)
 }
 
 func (e encoder) NotFoundErr(w http.ResponseWriter, err error) {
-	res := errorResponse{
-		Message: err.Error(),
-	}
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusNotFound)
-
-	if err := json.NewEncoder(w).Encode(res); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
+	e.writeError(w, http.StatusNotFound, "", err.Error(), err)
 }
 
 func (e encoder) StatusInternalError(w http.ResponseWriter) {
-	w.WriteHeader(http.StatusInternalServerError)
+	e.writeError(w, http.StatusInternalServerError, "", http.StatusText(http.StatusInternalServerError), nil)
 }
 
+// Error responds with 404 for missing records, 400 for validation errors and 500 otherwise
 func (e encoder) Error(w http.ResponseWriter, err error) {
-	res := errorResponse{
-		Message: err.Error(),
-	}
+	status := http.StatusInternalServerError
 
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusInternalServerError)
+	var validationErrs domain.ValidationErrors
 
-	if err := json.NewEncoder(w).Encode(res); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound):
+		status = http.StatusNotFound
+	case errors.As(err, &validationErrs):
+		status = http.StatusBadRequest
 	}
+
+	e.writeError(w, status, "", err.Error(), err)
 }
 
 func (e encoder) StatusError(w http.ResponseWriter, status int, err error) {
+	e.writeError(w, status, "", err.Error(), err)
+}
+
+// ErrorCode responds with a specific error code, eg. BAD_REQUEST_PARAMS
+func (e encoder) ErrorCode(w http.ResponseWriter, status int, code string, message string) {
+	e.writeError(w, status, code, message, nil)
+}
+
+func (e encoder) writeError(w http.ResponseWriter, status int, code string, message string, err error) {
+	if code == "" {
+		code = errorCode(status)
+	}
+
 	res := errorResponse{
-		Message: err.Error(),
+		Code:      code,
+		Message:   message,
+		Status:    status,
+		RequestID: w.Header().Get(requestIDHeader),
+	}
+
+	var validationErrs domain.ValidationErrors
+	if err != nil && errors.As(err, &validationErrs) {
+		res.Code = "VALIDATION_ERROR"
+		res.Errors = validationErrs
+	}
+
+	// log server errors with the request id so they can be correlated with what the client got
+	if status >= http.StatusInternalServerError {
+		e.log.Error().Err(err).Str("request_id", res.RequestID).Str("code", res.Code).Msg(message)
+	} else {
+		e.log.Debug().Str("request_id", res.RequestID).Str("code", res.Code).Msg(message)
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -115,3 +147,30 @@ func (e encoder) StatusError(w http.ResponseWriter, status int, err error) {
 		return
 	}
 }
+
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "BAD_REQUEST"
+	case http.StatusUnauthorized:
+		return "UNAUTHORIZED"
+	case http.StatusForbidden:
+		return "FORBIDDEN"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusConflict:
+		return "CONFLICT"
+	case http.StatusUnprocessableEntity:
+		return "VALIDATION_ERROR"
+	case http.StatusTooManyRequests:
+		return "TOO_MANY_REQUESTS"
+	case http.StatusServiceUnavailable:
+		return "SERVICE_UNAVAILABLE"
+	}
+
+	if status >= http.StatusInternalServerError {
+		return "INTERNAL_SERVER_ERROR"
+	}
+
+	return "BAD_REQUEST"
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantErrors []domain.ValidationError
+	}{
+		{
+			name:       "not_found",
+			err:        errors.Wrap(domain.ErrRecordNotFound, "could not find filter"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "NOT_FOUND",
+		},
+		{
+			name:       "validation",
+			err:        domain.ValidationErrors{{Field: "name", Message: "name can't be empty"}},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+			wantErrors: []domain.ValidationError{{Field: "name", Message: "name can't be empty"}},
+		},
+		{
+			name:       "wrapped_validation",
+			err:        errors.Wrap(domain.ValidationErrors{{Field: "host", Message: "host can't be empty"}}, "could not store client"),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+			wantErrors: []domain.ValidationError{{Field: "host", Message: "host can't be empty"}},
+		},
+		{
+			name:       "internal",
+			err:        errors.New("database is locked"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "INTERNAL_SERVER_ERROR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set(requestIDHeader, "host/abc-000001")
+
+			encoder{}.Error(w, tt.err)
+
+			var res errorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantStatus, res.Status)
+			assert.Equal(t, tt.wantCode, res.Code)
+			assert.Equal(t, tt.err.Error(), res.Message)
+			assert.Equal(t, tt.wantErrors, res.Errors)
+			assert.Equal(t, "host/abc-000001", res.RequestID)
+		})
+	}
+}
+
+func TestRequestIDHeader(t *testing.T) {
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder{}.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "limit parameter is invalid")
+	})
+
+	handler = RequestIDHeader(handler)
+	handler = middleware.RequestID(handler)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/release?limit=x", nil))
+
+	var res errorResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+
+	assert.Equal(t, "BAD_REQUEST_PARAMS", res.Code)
+	assert.NotEmpty(t, res.RequestID)
+	assert.Equal(t, w.Header().Get(requestIDHeader), res.RequestID)
+}
+
+type apikeyServiceMock struct {
+	apikeyService
+	valid  string
+	scopes []string
+	userID int
+}
+
+func (s apikeyServiceMock) ValidateAPIKey(ctx context.Context, token string) (*domain.APIKey, error) {
+	if token != s.valid {
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	return &domain.APIKey{Key: token, Scopes: s.scopes, UserID: s.userID}, nil
+}
+
+type userFinderMock struct {
+	authService
+	user *domain.User
+}
+
+func (s userFinderMock) FindUserByID(ctx context.Context, id int) (*domain.User, error) {
+	if s.user == nil || s.user.ID != id {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return s.user, nil
+}
+
+func TestMiddleware_StructuredErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		target     string
+		token      string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "invalid_api_key_header",
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			target:     "/api/filters",
+			token:      "wrong",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "UNAUTHORIZED",
+		},
+		{
+			name:       "invalid_api_key_param",
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			target:     "/api/filters?apikey=wrong",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "UNAUTHORIZED",
+		},
+		{
+			name:       "panic",
+			handler:    func(w http.ResponseWriter, r *http.Request) { panic("boom") },
+			target:     "/api/filters?apikey=secret",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "INTERNAL_SERVER_ERROR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Server{apiService: apikeyServiceMock{valid: "secret"}}
+
+			var handler http.Handler = tt.handler
+			handler = s.IsAuthenticated(handler)
+			handler = Recoverer(encoder{})(handler)
+			handler = RequestIDHeader(handler)
+			handler = middleware.RequestID(handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.token != "" {
+				req.Header.Set("X-API-Token", tt.token)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			var res errorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantCode, res.Code)
+			assert.NotEmpty(t, res.RequestID)
+		})
+	}
+}
+
+func TestMiddleware_APIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     []string
+		method     string
+		target     string
+		wantStatus int
+	}{
+		{name: "read", scopes: []string{"read"}, method: http.MethodGet, target: "/api/filters"},
+		{name: "read_write_filters", scopes: []string{"read"}, method: http.MethodPut, target: "/api/filters/1", wantStatus: http.StatusForbidden},
+		{name: "read_keys", scopes: []string{"read"}, method: http.MethodGet, target: "/api/keys", wantStatus: http.StatusForbidden},
+		{name: "read_logs", scopes: []string{"read"}, method: http.MethodGet, target: "/api/logs/files", wantStatus: http.StatusForbidden},
+		{name: "read_log_stream", scopes: []string{"read"}, method: http.MethodGet, target: "/api/events?stream=logs", wantStatus: http.StatusForbidden},
+		{name: "read_indexers", scopes: []string{"read"}, method: http.MethodGet, target: "/api/indexer", wantStatus: http.StatusForbidden},
+		{name: "read_indexer_options", scopes: []string{"read"}, method: http.MethodGet, target: "/api/indexer/options"},
+		{name: "read_irc", scopes: []string{"read"}, method: http.MethodGet, target: "/api/irc", wantStatus: http.StatusForbidden},
+		{name: "read_notifications", scopes: []string{"read"}, method: http.MethodGet, target: "/api/notification/1", wantStatus: http.StatusForbidden},
+		{name: "read_feeds", scopes: []string{"read"}, method: http.MethodGet, target: "/api/feeds", wantStatus: http.StatusForbidden},
+		{name: "filters_write", scopes: []string{"filters:write"}, method: http.MethodPut, target: "/api/filters/1"},
+		{name: "filters_write_actions", scopes: []string{"filters:write"}, method: http.MethodPatch, target: "/api/actions/1/toggleEnabled"},
+		{name: "filters_write_releases", scopes: []string{"filters:write"}, method: http.MethodDelete, target: "/api/release", wantStatus: http.StatusForbidden},
+		{name: "releases_write", scopes: []string{"releases:write"}, method: http.MethodDelete, target: "/api/release"},
+		{name: "releases_write_config", scopes: []string{"releases:write"}, method: http.MethodPatch, target: "/api/config", wantStatus: http.StatusForbidden},
+		{name: "admin", scopes: []string{"admin"}, method: http.MethodPost, target: "/api/keys"},
+		{name: "no_scopes", method: http.MethodPost, target: "/api/keys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newOpenAPITestServer()
+			s.apiService = apikeyServiceMock{valid: "secret", scopes: tt.scopes}
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Header.Set("X-API-Token", "secret")
+
+			w := httptest.NewRecorder()
+			s.Handler().ServeHTTP(w, req)
+
+			// allowed requests reach the handlers which have no services in this test
+			if tt.wantStatus == 0 {
+				assert.NotEqual(t, http.StatusForbidden, w.Code)
+				return
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestMiddleware_APIKeyOwnerRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       domain.UserRole
+		userID     int
+		method     string
+		target     string
+		wantStatus int
+	}{
+		{name: "read_only_read", role: domain.UserRoleReadOnly, userID: 1, method: http.MethodGet, target: "/api/filters"},
+		{name: "read_only_write", role: domain.UserRoleReadOnly, userID: 1, method: http.MethodPut, target: "/api/filters/1", wantStatus: http.StatusForbidden},
+		{name: "operator_write", role: domain.UserRoleOperator, userID: 1, method: http.MethodPut, target: "/api/filters/1"},
+		{name: "operator_users", role: domain.UserRoleOperator, userID: 1, method: http.MethodGet, target: "/api/users", wantStatus: http.StatusForbidden},
+		{name: "read_only_log_stream", role: domain.UserRoleReadOnly, userID: 1, method: http.MethodGet, target: "/api/events?stream=logs", wantStatus: http.StatusForbidden},
+		{name: "read_only_irc", role: domain.UserRoleReadOnly, userID: 1, method: http.MethodGet, target: "/api/irc", wantStatus: http.StatusForbidden},
+		{name: "operator_notifications", role: domain.UserRoleOperator, userID: 1, method: http.MethodGet, target: "/api/notification", wantStatus: http.StatusForbidden},
+		{name: "owner_removed", role: domain.UserRoleAdmin, userID: 2, method: http.MethodGet, target: "/api/filters", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newOpenAPITestServer()
+			s.apiService = apikeyServiceMock{valid: "secret", userID: tt.userID}
+			s.authService = userFinderMock{user: &domain.User{ID: 1, Username: "john", Role: tt.role}}
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Header.Set("X-API-Token", "secret")
+
+			w := httptest.NewRecorder()
+			s.Handler().ServeHTTP(w, req)
+
+			// allowed requests reach the handlers which have no services in this test
+			if tt.wantStatus == 0 {
+				assert.NotEqual(t, http.StatusForbidden, w.Code)
+				return
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestMiddleware_SessionRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       domain.UserRole
+		method     string
+		target     string
+		wantStatus int
+	}{
+		{name: "read_only_read", role: domain.UserRoleReadOnly, method: http.MethodGet, target: "/api/filters"},
+		{name: "read_only_download_clients", role: domain.UserRoleReadOnly, method: http.MethodGet, target: "/api/download_clients"},
+		{name: "read_only_log_stream", role: domain.UserRoleReadOnly, method: http.MethodGet, target: "/api/events?stream=logs", wantStatus: http.StatusForbidden},
+		{name: "read_only_indexers", role: domain.UserRoleReadOnly, method: http.MethodGet, target: "/api/indexer", wantStatus: http.StatusForbidden},
+		{name: "read_only_irc", role: domain.UserRoleReadOnly, method: http.MethodGet, target: "/api/irc", wantStatus: http.StatusForbidden},
+		{name: "read_only_notifications", role: domain.UserRoleReadOnly, method: http.MethodGet, target: "/api/notification", wantStatus: http.StatusForbidden},
+		{name: "read_only_own_preferences", role: domain.UserRoleReadOnly, method: http.MethodGet, target: "/api/notification/preferences"},
+		{name: "admin_irc", role: domain.UserRoleAdmin, method: http.MethodGet, target: "/api/irc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newOpenAPITestServer()
+			s.authService = userFinderMock{user: &domain.User{ID: 1, Username: "john", Role: tt.role}}
+
+			// log in
+			login := httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+			loginW := httptest.NewRecorder()
+			session, _ := s.cookieStore.Get(login, "user_session")
+			session.Values["authenticated"] = true
+			session.Values["user_id"] = 1
+			assert.NoError(t, session.Save(login, loginW))
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			for _, cookie := range loginW.Result().Cookies() {
+				req.AddCookie(cookie)
+			}
+
+			w := httptest.NewRecorder()
+			s.Handler().ServeHTTP(w, req)
+
+			// allowed requests reach the handlers which have no services in this test
+			if tt.wantStatus == 0 {
+				assert.NotEqual(t, http.StatusForbidden, w.Code)
+				return
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+type downloadClientServiceMock struct {
+	downloadClientService
+	client domain.DownloadClient
+}
+
+func (s downloadClientServiceMock) List(ctx context.Context) ([]domain.DownloadClient, error) {
+	return []domain.DownloadClient{s.client}, nil
+}
+
+func (s downloadClientServiceMock) FindByID(ctx context.Context, id int32) (*domain.DownloadClient, error) {
+	return &s.client, nil
+}
+
+// requestDownloadClients returns the download clients listed and found by id within the context
+func requestDownloadClients(t *testing.T, ctx context.Context) []domain.DownloadClient {
+	t.Helper()
+
+	client := domain.DownloadClient{ID: 1, Name: "qbit", Username: "admin", Password: "secret", Settings: domain.DownloadClientSettings{
+		APIKey: "apikey",
+		Auth:   domain.DownloadClientAuth{Enabled: true, Type: domain.DownloadClientAuthTypeBasic, Username: "basic", Password: "basic-secret"},
+	}}
+
+	r := chi.NewRouter()
+	r.Route("/download_clients", newDownloadClientHandler(encoder{}, downloadClientServiceMock{client: client}).Routes)
+
+	var clients []domain.DownloadClient
+	for _, target := range []string{"/download_clients", "/download_clients/1"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		body := w.Body.Bytes()
+		if target == "/download_clients" {
+			var list []domain.DownloadClient
+			assert.NoError(t, json.Unmarshal(body, &list))
+			clients = append(clients, list...)
+		} else {
+			var found domain.DownloadClient
+			assert.NoError(t, json.Unmarshal(body, &found))
+			clients = append(clients, found)
+		}
+	}
+
+	return clients
+}
+
+func TestDownloadClientHandler_redactsCredentials(t *testing.T) {
+	readKey := domain.ContextWithAPIKey(context.Background(), &domain.APIKey{Scopes: []string{"read"}})
+
+	for _, client := range requestDownloadClients(t, readKey) {
+		assert.Equal(t, "admin", client.Username)
+		assert.Empty(t, client.Password)
+		assert.Empty(t, client.Settings.APIKey)
+		assert.Equal(t, "basic", client.Settings.Auth.Username)
+		assert.Empty(t, client.Settings.Auth.Password)
+	}
+
+	adminKey := domain.ContextWithAPIKey(context.Background(), &domain.APIKey{Scopes: []string{"admin"}})
+
+	for _, client := range requestDownloadClients(t, adminKey) {
+		assert.Equal(t, "secret", client.Password)
+		assert.Equal(t, "apikey", client.Settings.APIKey)
+		assert.Equal(t, "basic-secret", client.Settings.Auth.Password)
+	}
+}
+
+func TestDownloadClientHandler_redactsCredentialsOfRoles(t *testing.T) {
+	for _, role := range []domain.UserRole{domain.UserRoleReadOnly, domain.UserRoleOperator} {
+		ctx := domain.ContextWithUser(context.Background(), &domain.User{ID: 1, Role: role})
+
+		for _, client := range requestDownloadClients(t, ctx) {
+			assert.Empty(t, client.Password, role)
+			assert.Empty(t, client.Settings.APIKey, role)
+			assert.Empty(t, client.Settings.Auth.Password, role)
+		}
+	}
+
+	ctx := domain.ContextWithUser(context.Background(), &domain.User{ID: 1, Role: domain.UserRoleAdmin})
+
+	for _, client := range requestDownloadClients(t, ctx) {
+		assert.Equal(t, "secret", client.Password)
+	}
+}
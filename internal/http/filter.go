@@ -23,10 +23,23 @@ type filterService interface {
 	Find(ctx context.Context, params domain.FilterQueryParams) ([]domain.Filter, error)
 	Store(ctx context.Context, filter *domain.Filter) error
 	Delete(ctx context.Context, filterID int) error
+	Restore(ctx context.Context, filterID int) error
+	ListTrashed(ctx context.Context) ([]domain.Filter, error)
+	Purge(ctx context.Context, filterID int) error
 	Update(ctx context.Context, filter *domain.Filter) error
 	UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error
 	Duplicate(ctx context.Context, filterID int) (*domain.Filter, error)
+	Clone(ctx context.Context, filterID int, req domain.FilterCloneRequest) (*domain.FilterCloneResponse, error)
+	BulkUpdate(ctx context.Context, req domain.FilterBulkUpdateRequest) ([]domain.FilterBulkUpdateResult, error)
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
+	ListGroups(ctx context.Context) ([]domain.FilterGroup, error)
+	FindGroupByID(ctx context.Context, groupID int) (*domain.FilterGroup, error)
+	StoreGroup(ctx context.Context, group *domain.FilterGroup) error
+	UpdateGroup(ctx context.Context, group *domain.FilterGroup) error
+	ToggleGroupEnabled(ctx context.Context, groupID int, enabled bool) error
+	DeleteGroup(ctx context.Context, groupID int) error
+	MoveToGroup(ctx context.Context, req domain.FilterGroupMoveRequest) error
+	DryRun(ctx context.Context, req domain.FilterDryRunRequest) (*domain.FilterDryRunResponse, error)
 }
 
 type filterHandler struct {
@@ -44,6 +57,22 @@ func newFilterHandler(encoder encoder, service filterService) *filterHandler {
 func (h filterHandler) Routes(r chi.Router) {
 	r.Get("/", h.getFilters)
 	r.Post("/", h.store)
+	r.Post("/bulk", h.bulkUpdate)
+	r.Get("/trash", h.getTrashed)
+	r.Post("/move", h.moveToGroup)
+	r.Post("/dry-run", h.dryRun)
+
+	r.Route("/groups", func(r chi.Router) {
+		r.Get("/", h.getGroups)
+		r.Post("/", h.storeGroup)
+
+		r.Route("/{groupID}", func(r chi.Router) {
+			r.Get("/", h.getGroupByID)
+			r.Put("/", h.updateGroup)
+			r.Delete("/", h.deleteGroup)
+			r.Put("/enabled", h.toggleGroupEnabled)
+		})
+	})
 
 	r.Route("/{filterID}", func(r chi.Router) {
 		r.Get("/", h.getByID)
@@ -52,7 +81,9 @@ func (h filterHandler) Routes(r chi.Router) {
 		r.Delete("/", h.delete)
 
 		r.Get("/duplicate", h.duplicate)
+		r.Post("/clone", h.clone)
 		r.Put("/enabled", h.toggleEnabled)
+		r.Post("/restore", h.restore)
 	})
 }
 
@@ -84,10 +115,7 @@ func (h filterHandler) getFilters(w http.ResponseWriter, r *http.Request) {
 
 	u, err := url.Parse(r.URL.String())
 	if err != nil {
-		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-			"code":    "BAD_REQUEST_PARAMS",
-			"message": "indexer parameter is invalid",
-		})
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "indexer parameter is invalid")
 		return
 	}
 	vals := u.Query()
@@ -144,6 +172,75 @@ func (h filterHandler) duplicate(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusResponse(w, http.StatusOK, filter)
 }
 
+func (h filterHandler) clone(w http.ResponseWriter, r *http.Request) {
+	filterID, err := strconv.Atoi(chi.URLParam(r, "filterID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	var data domain.FilterCloneRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	resp, err := h.service.Clone(r.Context(), filterID, data)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("filter with id %d not found", filterID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, resp)
+}
+
+func (h filterHandler) bulkUpdate(w http.ResponseWriter, r *http.Request) {
+	var data domain.FilterBulkUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := h.service.BulkUpdate(r.Context(), data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, results)
+}
+
+func (h filterHandler) dryRun(w http.ResponseWriter, r *http.Request) {
+	var data domain.FilterDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	resp, err := h.service.DryRun(r.Context(), data)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("filter with id %d not found", data.FilterID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, resp)
+}
+
 func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 	var data *domain.Filter
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
@@ -227,10 +324,41 @@ func (h filterHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Delete(r.Context(), filterID); err != nil {
+	// filters are moved to the trash unless permanent deletion is requested
+	deleteFunc := h.service.Delete
+	if permanent, _ := strconv.ParseBool(r.URL.Query().Get("permanent")); permanent {
+		deleteFunc = h.service.Purge
+	}
+
+	if err := deleteFunc(r.Context(), filterID); err != nil {
 		h.encoder.Error(w, err)
 		return
 	}
 
 	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
 }
+
+func (h filterHandler) getTrashed(w http.ResponseWriter, r *http.Request) {
+	filters, err := h.service.ListTrashed(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, filters)
+}
+
+func (h filterHandler) restore(w http.ResponseWriter, r *http.Request) {
+	filterID, err := strconv.Atoi(chi.URLParam(r, "filterID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.Restore(r.Context(), filterID); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
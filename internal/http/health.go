@@ -4,28 +4,41 @@
 package http
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
 
 	"github.com/go-chi/chi/v5"
 )
 
+type healthService interface {
+	Check(ctx context.Context) *domain.HealthReport
+}
+
 type healthHandler struct {
-	encoder encoder
-	db      *database.DB
+	encoder      encoder
+	db           *database.DB
+	service      healthService
+	authenticate func(next http.Handler) http.Handler
 }
 
-func newHealthHandler(encoder encoder, db *database.DB) *healthHandler {
+func newHealthHandler(encoder encoder, db *database.DB, service healthService, authenticate func(next http.Handler) http.Handler) *healthHandler {
 	return &healthHandler{
-		encoder: encoder,
-		db:      db,
+		encoder:      encoder,
+		db:           db,
+		service:      service,
+		authenticate: authenticate,
 	}
 }
 
 func (h healthHandler) Routes(r chi.Router) {
 	r.Get("/liveness", h.handleLiveness)
 	r.Get("/readiness", h.handleReadiness)
+
+	// the components name the networks, feeds and clients so unlike the probes they need auth
+	r.With(h.authenticate).Get("/components", h.handleComponents)
 }
 
 func (h healthHandler) handleLiveness(w http.ResponseWriter, _ *http.Request) {
@@ -41,6 +54,18 @@ func (h healthHandler) handleReadiness(w http.ResponseWriter, _ *http.Request) {
 	writeHealthy(w)
 }
 
+// handleComponents responds with 503 when any component is degraded or down so monitors can alert on the status code alone
+func (h healthHandler) handleComponents(w http.ResponseWriter, r *http.Request) {
+	report := h.service.Check(r.Context())
+
+	status := http.StatusOK
+	if report.Status != domain.HealthStateOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	h.encoder.StatusResponse(w, status, report)
+}
+
 func writeHealthy(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(http.StatusOK)
@@ -25,19 +25,22 @@ type indexerService interface {
 	Delete(ctx context.Context, id int) error
 	TestApi(ctx context.Context, req domain.IndexerTestApiRequest) error
 	ToggleEnabled(ctx context.Context, indexerID int, enabled bool) error
+	GetPipeline(ctx context.Context, id int) (*domain.IndexerPipeline, error)
 }
 
 type indexerHandler struct {
 	encoder encoder
 	service indexerService
 	ircSvc  ircService
+	mockSvc mockIndexerService
 }
 
-func newIndexerHandler(encoder encoder, service indexerService, ircSvc ircService) *indexerHandler {
+func newIndexerHandler(encoder encoder, service indexerService, ircSvc ircService, mockSvc mockIndexerService) *indexerHandler {
 	return &indexerHandler{
 		encoder: encoder,
 		service: service,
 		ircSvc:  ircSvc,
+		mockSvc: mockSvc,
 	}
 }
 
@@ -52,6 +55,7 @@ func (h indexerHandler) Routes(r chi.Router) {
 		r.Put("/", h.update)
 		r.Delete("/", h.delete)
 		r.Post("/api/test", h.testApi)
+		r.Get("/pipeline", h.getPipeline)
 
 		r.Patch("/enabled", h.toggleEnabled)
 	})
@@ -80,6 +84,8 @@ func (h indexerHandler) store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.syncMockIndexers()
+
 	h.encoder.StatusResponse(w, http.StatusCreated, indexer)
 }
 
@@ -96,6 +102,8 @@ func (h indexerHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.syncMockIndexers()
+
 	h.encoder.StatusResponse(w, http.StatusOK, indexer)
 }
 
@@ -111,6 +119,8 @@ func (h indexerHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.syncMockIndexers()
+
 	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
 }
 
@@ -155,6 +165,27 @@ func (h indexerHandler) findByID(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusResponse(w, http.StatusOK, indexer)
 }
 
+func (h indexerHandler) getPipeline(w http.ResponseWriter, r *http.Request) {
+	indexerID, err := strconv.Atoi(chi.URLParam(r, "indexerID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	pipeline, err := h.service.GetPipeline(r.Context(), indexerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("indexer with id %d not found", indexerID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, pipeline)
+}
+
 func (h indexerHandler) testApi(w http.ResponseWriter, r *http.Request) {
 	indexerID, err := strconv.Atoi(chi.URLParam(r, "indexerID"))
 	if err != nil {
@@ -207,5 +238,13 @@ func (h indexerHandler) toggleEnabled(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.syncMockIndexers()
+
 	h.encoder.NoContent(w)
 }
+
+// syncMockIndexers starts or stops the mock indexer announces after the indexers changed.
+// The change itself succeeded, errors are logged by the mock indexer service.
+func (h indexerHandler) syncMockIndexers() {
+	_ = h.mockSvc.Sync()
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type listService interface {
+	List(ctx context.Context) ([]*domain.List, error)
+	FindByID(ctx context.Context, id int64) (*domain.List, error)
+	Store(ctx context.Context, list *domain.List) error
+	Update(ctx context.Context, list *domain.List) error
+	Delete(ctx context.Context, id int64) error
+	ToggleEnabled(ctx context.Context, id int64, enabled bool) error
+	RefreshList(ctx context.Context, id int64) error
+}
+
+type listHandler struct {
+	encoder encoder
+	service listService
+}
+
+func newListHandler(encoder encoder, service listService) *listHandler {
+	return &listHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h listHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+
+	r.Route("/{listID}", func(r chi.Router) {
+		r.Get("/", h.findByID)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+		r.Patch("/enabled", h.toggleEnabled)
+		r.Post("/refresh", h.refresh)
+	})
+}
+
+func (h listHandler) list(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.service.List(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, lists)
+}
+
+func (h listHandler) store(w http.ResponseWriter, r *http.Request) {
+	var data domain.List
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.Store(r.Context(), &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, data)
+}
+
+func (h listHandler) findByID(w http.ResponseWriter, r *http.Request) {
+	listID, err := strconv.Atoi(chi.URLParam(r, "listID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	list, err := h.service.FindByID(r.Context(), int64(listID))
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("could not find list with id %d", listID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, list)
+}
+
+func (h listHandler) update(w http.ResponseWriter, r *http.Request) {
+	listID, err := strconv.Atoi(chi.URLParam(r, "listID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	var data domain.List
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	data.ID = int64(listID)
+
+	if err := h.service.Update(r.Context(), &data); err != nil {
+		if errors.Is(err, domain.ErrUpdateFailed) {
+			h.encoder.StatusError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h listHandler) delete(w http.ResponseWriter, r *http.Request) {
+	listID, err := strconv.Atoi(chi.URLParam(r, "listID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), int64(listID)); err != nil {
+		if errors.Is(err, domain.ErrDeleteFailed) {
+			h.encoder.StatusError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h listHandler) toggleEnabled(w http.ResponseWriter, r *http.Request) {
+	listID, err := strconv.Atoi(chi.URLParam(r, "listID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	var data struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.ToggleEnabled(r.Context(), int64(listID), data.Enabled); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+// refresh syncs the list into its filters right away
+func (h listHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	listID, err := strconv.Atoi(chi.URLParam(r, "listID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.RefreshList(r.Context(), int64(listID)); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
@@ -5,30 +5,52 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"runtime/debug"
 	"strings"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/domain"
+
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 )
 
 func (s Server) IsAuthenticated(next http.Handler) http.Handler {
+	e := encoder{log: s.log}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if token := r.Header.Get("X-API-Token"); token != "" {
-			// check header
-			if !s.apiService.ValidateAPIKey(r.Context(), token) {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		if token := requestAPIToken(r); token != "" {
+			apiKey, err := s.apiService.ValidateAPIKey(r.Context(), token)
+			if err != nil {
+				e.ErrorCode(w, http.StatusUnauthorized, "", err.Error())
 				return
 			}
 
-		} else if key := r.URL.Query().Get("apikey"); key != "" {
-			// check query param like ?apikey=TOKEN
-			if !s.apiService.ValidateAPIKey(r.Context(), key) {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			scope := requestScope(r)
+			if !apiKey.HasScope(scope) {
+				e.ErrorCode(w, http.StatusForbidden, errorCodeMissingScope, fmt.Sprintf("api key is missing scope %s", scope))
 				return
 			}
+
+			// keys can't do more than their owner
+			if apiKey.UserID != 0 {
+				owner, err := s.authService.FindUserByID(r.Context(), apiKey.UserID)
+				if err != nil {
+					e.ErrorCode(w, http.StatusUnauthorized, "", "api key owner not found")
+					return
+				}
+
+				if !owner.Role.HasScope(scope) {
+					e.ErrorCode(w, http.StatusForbidden, errorCodeMissingScope, fmt.Sprintf("api key owner with role %s is missing scope %s", owner.Role, scope))
+					return
+				}
+
+				r = r.WithContext(domain.ContextWithUser(r.Context(), owner))
+			}
+
+			r = r.WithContext(domain.ContextWithAPIKey(r.Context(), apiKey))
 		} else {
 			// check session
 			session, err := s.cookieStore.Get(r, "user_session")
@@ -42,10 +64,10 @@ func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 
 				if err := session.Save(r, w); err != nil {
 					s.log.Error().Err(err).Msgf("could not store session: %s", r.RemoteAddr)
-					http.Error(w, err.Error(), http.StatusInternalServerError)
+					e.StatusError(w, http.StatusInternalServerError, err)
 					return
 				}
-				http.Error(w, err.Error(), http.StatusForbidden)
+				e.StatusError(w, http.StatusForbidden, err)
 				return
 			}
 
@@ -53,7 +75,7 @@ func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 			if auth, ok := session.Values["authenticated"].(bool); !ok || !auth {
 				s.log.Warn().Msg("session not authenticated")
 
-				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				e.ErrorCode(w, http.StatusForbidden, "", http.StatusText(http.StatusForbidden))
 				return
 			}
 
@@ -72,13 +94,33 @@ func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 					// https://github.com/gorilla/sessions/issues/178#issuecomment-447674812
 					if err := session.Save(r, w); err != nil {
 						s.log.Error().Err(err).Msgf("could not store session: %s", r.RemoteAddr)
-						http.Error(w, err.Error(), http.StatusInternalServerError)
+						e.StatusError(w, http.StatusInternalServerError, err)
 						return
 					}
 				}
 			}
 
+			// sessions from before users had roles have no user id and need to log in again
+			userID, ok := session.Values["user_id"].(int)
+			if !ok {
+				e.ErrorCode(w, http.StatusForbidden, "", "session expired, log in again")
+				return
+			}
+
+			user, err := s.authService.FindUserByID(r.Context(), userID)
+			if err != nil {
+				s.log.Warn().Err(err).Msgf("could not find user %d of session", userID)
+				e.ErrorCode(w, http.StatusForbidden, "", "user not found")
+				return
+			}
+
+			if scope := requestScope(r); !isSelfService(r) && !user.Role.HasScope(scope) {
+				e.ErrorCode(w, http.StatusForbidden, errorCodeMissingScope, fmt.Sprintf("role %s is missing scope %s", user.Role, scope))
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), "session", session)
+			ctx = domain.ContextWithUser(ctx, user)
 			r = r.WithContext(ctx)
 		}
 
@@ -86,27 +128,153 @@ func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 	})
 }
 
-func LoggerMiddleware(logger *zerolog.Logger) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		fn := func(w http.ResponseWriter, r *http.Request) {
-			log := logger.With().Logger()
+// requestAPIToken returns the api key of the request from the header, or query param like ?apikey=TOKEN
+func requestAPIToken(r *http.Request) string {
+	if token := r.Header.Get("X-API-Token"); token != "" {
+		return token
+	}
 
-			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	return r.URL.Query().Get("apikey")
+}
 
-			t1 := time.Now()
-			defer func() {
-				t2 := time.Now()
+// errorCodeMissingScope tells clients the request is not allowed, opposed to an expired session
+const errorCodeMissingScope = "MISSING_SCOPE"
+
+// requestScope returns the scope an api key or the role of a user needs for the request.
+// Api keys, users, logs, the log stream, the audit log and the settings holding credentials are admin only,
+// everything else can be read by anyone.
+func requestScope(r *http.Request) domain.APIKeyScope {
+	path := apiPath(r)
+
+	switch {
+	case hasPathPrefix(path, "/keys"), hasPathPrefix(path, "/users"), hasPathPrefix(path, "/logs"), hasPathPrefix(path, "/audit"):
+		return domain.APIKeyScopeAdmin
+	case path == "/events" && r.URL.Query().Get("stream") == "logs":
+		return domain.APIKeyScopeAdmin
+	case hasCredentials(path):
+		return domain.APIKeyScopeAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return domain.APIKeyScopeRead
+	case hasPathPrefix(path, "/release"):
+		return domain.APIKeyScopeReleasesWrite
+	case hasPathPrefix(path, "/filters"), hasPathPrefix(path, "/actions"):
+		return domain.APIKeyScopeFiltersWrite
+	}
+
+	return domain.APIKeyScopeAdmin
+}
+
+// credentialPaths respond with passwords, api keys, passkeys or tokens.
+// Download clients are used in the filter actions and are redacted instead, see canReadCredentials.
+var credentialPaths = []string{"/feeds", "/indexer", "/irc", "/lists", "/notification", "/proxy"}
+
+// hasCredentials reports whether the path responds with stored credentials.
+// The indexer options and schema and the own notification preferences don't.
+func hasCredentials(path string) bool {
+	switch {
+	case hasPathPrefix(path, "/indexer/options"), hasPathPrefix(path, "/indexer/schema"), hasPathPrefix(path, "/notification/preferences"):
+		return false
+	}
+
+	for _, prefix := range credentialPaths {
+		if hasPathPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canReadCredentials reports whether the api key or user of the request may see stored credentials
+func canReadCredentials(r *http.Request) bool {
+	if apiKey := domain.APIKeyFromContext(r.Context()); apiKey != nil && !apiKey.HasScope(domain.APIKeyScopeAdmin) {
+		return false
+	}
+
+	if user := domain.UserFromContext(r.Context()); user != nil && !user.Role.HasScope(domain.APIKeyScopeAdmin) {
+		return false
+	}
+
+	return true
+}
+
+// isSelfService reports whether logged-in users manage their own things with the request,
+// the handlers make sure they only touch what they own
+func isSelfService(r *http.Request) bool {
+	path := apiPath(r)
+
+	return hasPathPrefix(path, "/keys") || hasPathPrefix(path, "/auth") || hasPathPrefix(path, "/notification/preferences")
+}
+
+// apiPath returns the path of the request relative to /api
+func apiPath(r *http.Request) string {
+	path := r.URL.Path
+	if i := strings.Index(path, "/api/"); i >= 0 {
+		path = path[i+len("/api"):]
+	}
+
+	return path
+}
 
-				// Recover and record stack traces in case of a panic
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestIDHeader exposes the request id set by middleware.RequestID so clients can reference it
+// when reporting errors, the same id is logged server-side
+func RequestIDHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reqID := middleware.GetReqID(r.Context()); reqID != "" {
+			w.Header().Set(requestIDHeader, reqID)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Recoverer responds to panics with the structured internal error and logs the stack trace
+func Recoverer(e encoder) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
 				if rec := recover(); rec != nil {
-					log.Error().
+					// the client went away, let net/http handle it
+					if rec == http.ErrAbortHandler {
+						panic(rec)
+					}
+
+					e.log.Error().
 						Str("type", "error").
 						Timestamp().
+						Str("request_id", middleware.GetReqID(r.Context())).
 						Interface("recover_info", rec).
 						Bytes("debug_stack", debug.Stack()).
 						Msg("log system error")
-					http.Error(ww, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+					if r.Header.Get("Connection") != "Upgrade" {
+						e.StatusInternalError(w)
+					}
 				}
+			}()
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func LoggerMiddleware(logger *zerolog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			log := logger.With().Logger()
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			t1 := time.Now()
+			defer func() {
+				t2 := time.Now()
 
 				if !strings.Contains("/api/healthz/liveness|/api/healthz/readiness", r.URL.Path) {
 					// log end request
@@ -114,6 +282,7 @@ func LoggerMiddleware(logger *zerolog.Logger) func(next http.Handler) http.Handl
 						Str("type", "access").
 						Timestamp().
 						Fields(map[string]interface{}{
+							"request_id": middleware.GetReqID(r.Context()),
 							"remote_ip":  r.RemoteAddr,
 							"url":        r.URL.Path,
 							"proto":      r.Proto,
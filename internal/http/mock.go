@@ -0,0 +1,65 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type mockIndexerService interface {
+	Sync() error
+	Torrent(ctx context.Context, name string, size uint64) ([]byte, error)
+}
+
+// mockIndexerHandler serves the fake torrents of the mock indexer. The download clients fetch them
+// without a session, so the routes are public and only respond while a mock indexer is enabled.
+type mockIndexerHandler struct {
+	encoder encoder
+	service mockIndexerService
+}
+
+func newMockIndexerHandler(encoder encoder, service mockIndexerService) *mockIndexerHandler {
+	return &mockIndexerHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h mockIndexerHandler) Routes(r chi.Router) {
+	r.Get("/torrent", h.torrent)
+}
+
+func (h mockIndexerHandler) torrent(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	size, err := strconv.ParseUint(r.URL.Query().Get("size"), 10, 64)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("invalid size"))
+		return
+	}
+
+	data, err := h.service.Torrent(r.Context(), name, size)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-bittorrent")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".torrent"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/sessions"
 )
 
 type notificationService interface {
@@ -22,6 +23,11 @@ type notificationService interface {
 	Update(ctx context.Context, n domain.Notification) (*domain.Notification, error)
 	Delete(ctx context.Context, id int) error
 	Test(ctx context.Context, notification domain.Notification) error
+	FindDeliveries(ctx context.Context, params domain.NotificationDeliveryQueryParams) ([]domain.NotificationDelivery, error)
+	ResendDelivery(ctx context.Context, id int64) error
+	DeleteDelivery(ctx context.Context, id int64) error
+	GetUserPreference(ctx context.Context, username string) (*domain.UserNotificationPreference, error)
+	UpdateUserPreference(ctx context.Context, username string, pref domain.UserNotificationPreference) (*domain.UserNotificationPreference, error)
 }
 
 type notificationHandler struct {
@@ -40,6 +46,17 @@ func (h notificationHandler) Routes(r chi.Router) {
 	r.Get("/", h.list)
 	r.Post("/", h.store)
 	r.Post("/test", h.test)
+	r.Get("/preferences", h.getPreferences)
+	r.Put("/preferences", h.updatePreferences)
+
+	r.Route("/deliveries", func(r chi.Router) {
+		r.Get("/", h.listDeliveries)
+
+		r.Route("/{deliveryID}", func(r chi.Router) {
+			r.Post("/resend", h.resendDelivery)
+			r.Delete("/", h.deleteDelivery)
+		})
+	})
 
 	r.Route("/{notificationID}", func(r chi.Router) {
 		r.Get("/", h.findByID)
@@ -92,7 +109,7 @@ func (h notificationHandler) findByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.encoder.StatusResponse(w, http.StatusNoContent, notification)
+	h.encoder.StatusResponse(w, http.StatusOK, notification)
 }
 
 func (h notificationHandler) update(w http.ResponseWriter, r *http.Request) {
@@ -140,3 +157,123 @@ func (h notificationHandler) test(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+// listDeliveries returns the queued notifications, ?status=FAILED lists the ones which ran out of attempts
+func (h notificationHandler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	params := domain.NotificationDeliveryQueryParams{
+		Status: domain.NotificationDeliveryStatus(r.URL.Query().Get("status")),
+	}
+
+	switch params.Status {
+	case "", domain.NotificationDeliveryStatusPending, domain.NotificationDeliveryStatusFailed, domain.NotificationDeliveryStatusDeferred:
+	default:
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("invalid status: %s", params.Status))
+		return
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			h.encoder.StatusError(w, http.StatusBadRequest, errors.New("invalid limit: %s", limit))
+			return
+		}
+
+		params.Limit = l
+	}
+
+	deliveries, err := h.service.FindDeliveries(r.Context(), params)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, deliveries)
+}
+
+func (h notificationHandler) resendDelivery(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.ResendDelivery(r.Context(), deliveryID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("notification delivery with id %d not found", deliveryID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h notificationHandler) deleteDelivery(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteDelivery(r.Context(), deliveryID); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+// sessionUsername returns the username of the logged-in user. API key requests have no user.
+func sessionUsername(r *http.Request) (string, bool) {
+	session, ok := r.Context().Value("session").(*sessions.Session)
+	if !ok || session == nil {
+		return "", false
+	}
+
+	username, ok := session.Values["username"].(string)
+	if !ok || username == "" {
+		return "", false
+	}
+
+	return username, true
+}
+
+func (h notificationHandler) getPreferences(w http.ResponseWriter, r *http.Request) {
+	username, ok := sessionUsername(r)
+	if !ok {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("notification preferences require a user session"))
+		return
+	}
+
+	pref, err := h.service.GetUserPreference(r.Context(), username)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, pref)
+}
+
+func (h notificationHandler) updatePreferences(w http.ResponseWriter, r *http.Request) {
+	username, ok := sessionUsername(r)
+	if !ok {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("notification preferences require a user session"))
+		return
+	}
+
+	var data domain.UserNotificationPreference
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	pref, err := h.service.UpdateUserPreference(r.Context(), username, data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, pref)
+}
@@ -0,0 +1,566 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// openAPIOperation annotates a route with what can't be introspected from the router,
+// the routes themselves, their path params and authentication are taken from the router
+type openAPIOperation struct {
+	Summary string
+	// Query params of the route
+	Query []openAPIQueryParam
+	// Request is a value of the json body, eg. domain.Filter{}
+	Request any
+	// Response is a value of the json response, no content when nil
+	Response any
+	// Status of a successful response, 200 or 204 without a response by default
+	Status int
+	// ContentType of a response which is not json, eg. a file download
+	ContentType string
+}
+
+type openAPIQueryParam struct {
+	Name        string
+	Type        string
+	Description string
+	Array       bool
+}
+
+type openAPISpec struct {
+	OpenAPI    string                               `json:"openapi"`
+	Info       openAPIInfo                          `json:"info"`
+	Servers    []openAPIServer                      `json:"servers"`
+	Paths      map[string]map[string]*openAPIPathOp `json:"paths"`
+	Components openAPIComponents                    `json:"components"`
+	Tags       []openAPITag                         `json:"tags,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPITag struct {
+	Name string `json:"name"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type openAPIPathOp struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// openAPISecurity are the ways to authenticate, see Server.IsAuthenticated
+var openAPISecurity = []map[string][]string{{"apiKeyHeader": {}}, {"apiKeyQuery": {}}, {"session": {}}}
+
+// openAPIMethods are the methods documented, routes registered for every method like the event streams are documented as GET
+var openAPIMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+var openAPIPathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]+)?}`)
+
+type openAPIHandler struct {
+	encoder encoder
+	routes  chi.Routes
+	version string
+	baseURL string
+
+	once sync.Once
+	spec *openAPISpec
+	err  error
+}
+
+func newOpenAPIHandler(encoder encoder, routes chi.Routes, version string, baseURL string) *openAPIHandler {
+	return &openAPIHandler{
+		encoder: encoder,
+		routes:  routes,
+		version: version,
+		baseURL: baseURL,
+	}
+}
+
+// getSpec serves the OpenAPI spec, it is built on the first request since the routes don't change
+func (h *openAPIHandler) getSpec(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(func() {
+		h.spec, h.err = buildOpenAPISpec(h.routes, h.version, h.baseURL)
+	})
+
+	if h.err != nil {
+		h.encoder.Error(w, h.err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, h.spec)
+}
+
+type openAPIRoute struct {
+	method      string
+	path        string
+	handler     string
+	middlewares []string
+}
+
+// walkOpenAPIRoutes returns the documented routes of the router with the names of their handlers
+func walkOpenAPIRoutes(routes chi.Routes) ([]openAPIRoute, error) {
+	byPath := map[string][]openAPIRoute{}
+
+	err := walkRoutes(routes, "", nil, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		path := strings.TrimSuffix(route, "/")
+		if path == "" {
+			path = "/"
+		}
+
+		if strings.Contains(path, "*") {
+			return nil
+		}
+
+		documented := false
+		for _, m := range openAPIMethods {
+			documented = documented || m == method
+		}
+		if !documented {
+			return nil
+		}
+
+		rt := openAPIRoute{method: method, path: path, handler: funcName(handler)}
+		for _, mw := range middlewares {
+			rt.middlewares = append(rt.middlewares, funcName(mw))
+		}
+
+		byPath[path] = append(byPath[path], rt)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []openAPIRoute
+	for _, rts := range byPath {
+		if len(rts) == len(openAPIMethods) && rts[0].handler == rts[len(rts)-1].handler {
+			for _, rt := range rts {
+				if rt.method == http.MethodGet {
+					rts = []openAPIRoute{rt}
+					break
+				}
+			}
+		}
+
+		result = append(result, rts...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].path == result[j].path {
+			return result[i].method < result[j].method
+		}
+		return result[i].path < result[j].path
+	})
+
+	return result, nil
+}
+
+// walkRoutes is chi.Walk which also passes the middlewares of inline groups to the routers mounted in them,
+// eg. IsAuthenticated to the routers in the authenticated group
+func walkRoutes(r chi.Routes, parentRoute string, parentMw chi.Middlewares, walkFn chi.WalkFunc) error {
+	for _, route := range r.Routes() {
+		mws := append(append(chi.Middlewares{}, parentMw...), r.Middlewares()...)
+
+		if route.SubRoutes != nil {
+			for _, handler := range route.Handlers {
+				if chain, ok := handler.(*chi.ChainHandler); ok {
+					mws = append(mws, chain.Middlewares...)
+				}
+				break
+			}
+
+			if err := walkRoutes(route.SubRoutes, parentRoute+strings.TrimSuffix(route.Pattern, "/*"), mws, walkFn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for method, handler := range route.Handlers {
+			if method == "*" {
+				continue
+			}
+
+			fullRoute := parentRoute + route.Pattern
+
+			if chain, ok := handler.(*chi.ChainHandler); ok {
+				if err := walkFn(method, fullRoute, chain.Endpoint, append(mws, chain.Middlewares...)...); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := walkFn(method, fullRoute, handler, mws...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func buildOpenAPISpec(routes chi.Routes, version string, baseURL string) (*openAPISpec, error) {
+	rts, err := walkOpenAPIRoutes(routes)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := newOpenAPISchemas()
+
+	spec := &openAPISpec{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "autobrr", Version: version},
+		Servers: []openAPIServer{{URL: strings.TrimSuffix(baseURL, "/") + "/api"}},
+		Paths:   map[string]map[string]*openAPIPathOp{},
+		Components: openAPIComponents{
+			Schemas: schemas.components,
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"apiKeyHeader": {Type: "apiKey", In: "header", Name: "X-API-Token"},
+				"apiKeyQuery":  {Type: "apiKey", In: "query", Name: "apikey"},
+				"session":      {Type: "apiKey", In: "cookie", Name: "user_session"},
+			},
+		},
+	}
+
+	errorSchema := schemas.schema(reflect.TypeOf(errorResponse{}))
+
+	tags := map[string]struct{}{}
+	operationIDs := map[string]int{}
+
+	for _, rt := range rts {
+		annotation := openAPIOperations[rt.method+" "+rt.path]
+
+		op := &openAPIPathOp{
+			OperationID: operationID(rt),
+			Summary:     annotation.Summary,
+			Responses:   map[string]openAPIResponse{},
+			Security:    []map[string][]string{},
+		}
+
+		// keep operation ids unique for generated clients
+		if n := operationIDs[op.OperationID]; n > 0 {
+			operationIDs[op.OperationID]++
+			op.OperationID = fmt.Sprintf("%s%d", op.OperationID, n+1)
+		} else {
+			operationIDs[op.OperationID] = 1
+		}
+
+		if tag := strings.Split(strings.TrimPrefix(rt.path, "/"), "/")[0]; tag != "" {
+			op.Tags = []string{tag}
+			tags[tag] = struct{}{}
+		}
+
+		for _, mw := range rt.middlewares {
+			if strings.HasSuffix(mw, ".IsAuthenticated-fm") {
+				op.Security = openAPISecurity
+			}
+		}
+
+		for _, match := range openAPIPathParam.FindAllStringSubmatch(rt.path, -1) {
+			param := openAPIParameter{Name: match[1], In: "path", Required: true, Schema: &openAPISchema{Type: "string"}}
+			if strings.HasSuffix(param.Name, "ID") {
+				param.Schema = &openAPISchema{Type: "integer"}
+			}
+			op.Parameters = append(op.Parameters, param)
+		}
+
+		for _, q := range annotation.Query {
+			param := openAPIParameter{Name: q.Name, In: "query", Description: q.Description, Schema: &openAPISchema{Type: q.Type}}
+			if q.Array {
+				param.Schema = &openAPISchema{Type: "array", Items: param.Schema}
+			}
+			op.Parameters = append(op.Parameters, param)
+		}
+
+		if annotation.Request != nil {
+			op.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: schemas.schema(reflect.TypeOf(annotation.Request))}},
+			}
+		}
+
+		status := annotation.Status
+		switch {
+		case status != 0:
+		case annotation.Response != nil || annotation.ContentType != "":
+			status = http.StatusOK
+		default:
+			status = http.StatusNoContent
+		}
+
+		response := openAPIResponse{Description: http.StatusText(status)}
+		switch {
+		case annotation.ContentType != "":
+			response.Content = map[string]openAPIMediaType{annotation.ContentType: {Schema: &openAPISchema{Type: "string"}}}
+		case annotation.Response != nil:
+			response.Content = map[string]openAPIMediaType{"application/json": {Schema: schemas.schema(reflect.TypeOf(annotation.Response))}}
+		}
+
+		op.Responses[fmt.Sprintf("%d", status)] = response
+		op.Responses["default"] = openAPIResponse{
+			Description: "Error",
+			Content:     map[string]openAPIMediaType{"application/json": {Schema: errorSchema}},
+		}
+
+		// openapi paths don't have regexp params
+		path := openAPIPathParam.ReplaceAllString(rt.path, "{$1}")
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = map[string]*openAPIPathOp{}
+		}
+		spec.Paths[path][strings.ToLower(rt.method)] = op
+	}
+
+	for tag := range tags {
+		spec.Tags = append(spec.Tags, openAPITag{Name: tag})
+	}
+	sort.Slice(spec.Tags, func(i, j int) bool { return spec.Tags[i].Name < spec.Tags[j].Name })
+
+	return spec, nil
+}
+
+// funcName is the name of the function of a handler or middleware, eg. github.com/autobrr/autobrr/internal/http.filterHandler.getByID-fm
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return reflect.TypeOf(fn).String()
+	}
+
+	if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		return f.Name()
+	}
+
+	return ""
+}
+
+// operationID is the handler method with its handler, eg. filterGetByID. Handlers which aren't methods
+// of a handler, like the event streams, get one from the method and path.
+func operationID(rt openAPIRoute) string {
+	name := strings.TrimSuffix(rt.handler[strings.LastIndex(rt.handler, "/")+1:], "-fm")
+
+	parts := strings.Split(name, ".")
+	if len(parts) == 3 {
+		receiver := strings.TrimSuffix(strings.TrimPrefix(parts[1], "(*"), ")")
+		if strings.HasSuffix(receiver, "Handler") {
+			return strings.TrimSuffix(receiver, "Handler") + upperFirst(parts[2])
+		}
+	}
+
+	id := strings.ToLower(rt.method)
+	for _, segment := range strings.FieldsFunc(openAPIPathParam.ReplaceAllString(rt.path, "$1"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		id += upperFirst(segment)
+	}
+
+	return id
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+
+	return string(r)
+}
+
+// openAPISchemas generates the schemas of go types from their json encoding,
+// named structs are added to the components and referenced
+type openAPISchemas struct {
+	components map[string]*openAPISchema
+	names      map[reflect.Type]string
+}
+
+func newOpenAPISchemas() *openAPISchemas {
+	return &openAPISchemas{
+		components: map[string]*openAPISchema{},
+		names:      map[reflect.Type]string{},
+	}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (s *openAPISchemas) schema(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &openAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + s.component(t)}
+	default:
+		// any value
+		return &openAPISchema{}
+	}
+}
+
+// component adds the struct to the components once, named by its type
+func (s *openAPISchemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := upperFirst(t.Name())
+	if _, taken := s.components[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = upperFirst(pkg) + name
+	}
+
+	// registered before the properties for types referencing themselves
+	s.names[t] = name
+	s.components[name] = &openAPISchema{}
+	*s.components[name] = *s.object(t)
+
+	return name
+}
+
+func (s *openAPISchemas) object(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	s.properties(t, schema.Properties)
+
+	return schema
+}
+
+// properties adds the fields of the struct as encoded by encoding/json, including the fields of embedded structs
+func (s *openAPISchemas) properties(t reflect.Type, properties map[string]*openAPISchema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				s.properties(ft, properties)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if strings.Contains(opts, "string") {
+			properties[name] = &openAPISchema{Type: "string"}
+			continue
+		}
+
+		properties[name] = s.schema(field.Type)
+	}
+}
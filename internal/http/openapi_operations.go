@@ -0,0 +1,265 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/version"
+)
+
+// toggleEnabledRequest is the body of the routes toggling enabled
+type toggleEnabledRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// openAPIOperations annotate the routes by "METHOD /path" as walked from the api router,
+// a route without an annotation is still in the spec. Keep them next to the routes when adding or changing one.
+var openAPIOperations = map[string]openAPIOperation{
+	// actions
+	"GET /actions":                            {Summary: "List actions", Response: []domain.Action{}},
+	"POST /actions":                           {Summary: "Create action", Request: domain.Action{}, Response: domain.Action{}, Status: http.StatusCreated},
+	"GET /actions/freeleech-tokens":           {Summary: "List freeleech token budgets", Response: []domain.FreeleechTokenBudget{}},
+	"PUT /actions/freeleech-tokens":           {Summary: "Update freeleech token budget", Request: domain.FreeleechTokenBudget{}},
+	"PUT /actions/{actionID}":                 {Summary: "Update action", Request: domain.Action{}, Response: domain.Action{}, Status: http.StatusCreated},
+	"DELETE /actions/{actionID}":              {Summary: "Delete action"},
+	"PATCH /actions/{actionID}/toggleEnabled": {Summary: "Toggle action enabled", Status: http.StatusCreated},
+
+	// auth
+	"POST /auth/login":            {Summary: "Log in with username and password", Request: domain.User{}},
+	"POST /auth/logout":           {Summary: "Log out"},
+	"GET /auth/onboard":           {Summary: "Check if the first user can be created"},
+	"POST /auth/onboard":          {Summary: "Create the first user", Request: domain.CreateUserRequest{}, Response: statusResponse{}},
+	"GET /auth/validate":          {Summary: "Validate the session"},
+	"GET /auth/user":              {Summary: "Get the user of the session", Response: currentUser{}},
+	"PATCH /auth/user/{username}": {Summary: "Update user", Request: domain.UpdateUserRequest{}, Response: statusResponse{}},
+	"GET /auth/oidc/config":       {Summary: "Get the OpenID Connect login options", Response: oidcConfigResponse{}},
+	"GET /auth/oidc/login":        {Summary: "Redirect to the OpenID Connect provider to log in", Status: http.StatusFound},
+	"GET /auth/oidc/callback": {Summary: "Complete the OpenID Connect login and redirect to the web app", Query: []openAPIQueryParam{
+		{Name: "code", Type: "string", Description: "Authorization code"},
+		{Name: "state", Type: "string", Description: "State of the login"},
+	}, Status: http.StatusFound},
+
+	// config
+	"GET /audit": {Summary: "List changes of the configuration, newest first", Query: []openAPIQueryParam{
+		{Name: "limit", Type: "integer", Description: "Entries per page, 50 by default"},
+		{Name: "offset", Type: "integer", Description: "Entries to skip"},
+		{Name: "resource", Type: "string", Description: "Resource like filter or indexer"},
+		{Name: "user", Type: "string", Description: "Username"},
+	}, Response: domain.FindAuditResponse{}},
+
+	"GET /config":   {Summary: "Get config", Response: configJson{}},
+	"PATCH /config": {Summary: "Update config", Request: domain.ConfigUpdate{}},
+
+	// download clients
+	"GET /download_clients":                      {Summary: "List download clients", Response: []domain.DownloadClient{}},
+	"POST /download_clients":                     {Summary: "Create download client", Request: domain.DownloadClient{}, Response: domain.DownloadClient{}, Status: http.StatusCreated},
+	"PUT /download_clients":                      {Summary: "Update download client", Request: domain.DownloadClient{}, Response: domain.DownloadClient{}, Status: http.StatusCreated},
+	"POST /download_clients/test":                {Summary: "Test download client connection", Request: domain.DownloadClient{}},
+	"GET /download_clients/search":               {Summary: "Search torrents of all download clients", Query: []openAPIQueryParam{{Name: "q", Type: "string", Description: "Torrent name"}}, Response: domain.DownloadClientSearchResult{}},
+	"GET /download_clients/{clientID}":           {Summary: "Get download client", Response: domain.DownloadClient{}},
+	"DELETE /download_clients/{clientID}":        {Summary: "Delete download client"},
+	"GET /download_clients/{clientID}/inventory": {Summary: "Get download client inventory", Query: []openAPIQueryParam{{Name: "refresh", Type: "boolean", Description: "Skip the cache"}}, Response: domain.DownloadClientInventory{}},
+
+	// event stream
+	"GET /events": {Summary: "Stream server sent events, stream logs or live for release decisions, action statuses and irc health", ContentType: "text/event-stream"},
+
+	// feeds
+	"GET /feeds":                    {Summary: "List feeds", Response: []domain.Feed{}},
+	"POST /feeds":                   {Summary: "Create feed", Request: domain.Feed{}, Response: domain.Feed{}, Status: http.StatusCreated},
+	"POST /feeds/test":              {Summary: "Test feed", Request: domain.Feed{}},
+	"GET /feeds/{feedID}":           {Summary: "Get feed", Response: domain.Feed{}},
+	"PUT /feeds/{feedID}":           {Summary: "Update feed", Request: domain.Feed{}, Response: domain.Feed{}, Status: http.StatusCreated},
+	"DELETE /feeds/{feedID}":        {Summary: "Delete feed"},
+	"DELETE /feeds/{feedID}/cache":  {Summary: "Clear feed cache"},
+	"PATCH /feeds/{feedID}/enabled": {Summary: "Toggle feed enabled", Request: toggleEnabledRequest{}},
+	"GET /feeds/{feedID}/latest":    {Summary: "Get data of the latest feed run", ContentType: "text/plain"},
+	"POST /feeds/{feedID}/forcerun": {Summary: "Run feed now"},
+
+	// filters
+	"GET /filters": {Summary: "List filters", Query: []openAPIQueryParam{
+		{Name: "sort", Type: "string", Description: "Field and order, eg. name-asc or priority-desc"},
+		{Name: "indexer", Type: "string", Description: "Indexer identifier", Array: true},
+	}, Response: []domain.Filter{}},
+	"POST /filters":                         {Summary: "Create filter", Request: domain.Filter{}, Response: domain.Filter{}, Status: http.StatusCreated},
+	"POST /filters/bulk":                    {Summary: "Update filters in bulk", Request: domain.FilterBulkUpdateRequest{}, Response: []domain.FilterBulkUpdateResult{}},
+	"GET /filters/trash":                    {Summary: "List deleted filters", Response: []domain.Filter{}},
+	"POST /filters/move":                    {Summary: "Move filters to group", Request: domain.FilterGroupMoveRequest{}},
+	"POST /filters/dry-run":                 {Summary: "Replay stored releases against a filter", Request: domain.FilterDryRunRequest{}, Response: domain.FilterDryRunResponse{}},
+	"GET /filters/groups":                   {Summary: "List filter groups", Response: []domain.FilterGroup{}},
+	"POST /filters/groups":                  {Summary: "Create filter group", Request: domain.FilterGroup{}, Response: domain.FilterGroup{}, Status: http.StatusCreated},
+	"GET /filters/groups/{groupID}":         {Summary: "Get filter group", Response: domain.FilterGroup{}},
+	"PUT /filters/groups/{groupID}":         {Summary: "Update filter group", Request: domain.FilterGroup{}, Response: domain.FilterGroup{}},
+	"DELETE /filters/groups/{groupID}":      {Summary: "Delete filter group"},
+	"PUT /filters/groups/{groupID}/enabled": {Summary: "Toggle filter group enabled", Request: toggleEnabledRequest{}},
+	"GET /filters/{filterID}":               {Summary: "Get filter", Response: domain.Filter{}},
+	"PUT /filters/{filterID}":               {Summary: "Update filter", Request: domain.Filter{}, Response: domain.Filter{}},
+	"PATCH /filters/{filterID}":             {Summary: "Update filter partially", Request: domain.FilterUpdate{}},
+	"DELETE /filters/{filterID}": {Summary: "Delete filter, to the trash unless permanent", Query: []openAPIQueryParam{
+		{Name: "permanent", Type: "boolean", Description: "Delete instead of moving to the trash"},
+	}},
+	"GET /filters/{filterID}/duplicate": {Summary: "Duplicate filter", Response: domain.Filter{}},
+	"POST /filters/{filterID}/clone":    {Summary: "Clone filter", Request: domain.FilterCloneRequest{}, Response: domain.FilterCloneResponse{}, Status: http.StatusCreated},
+	"PUT /filters/{filterID}/enabled":   {Summary: "Toggle filter enabled", Request: toggleEnabledRequest{}},
+	"POST /filters/{filterID}/restore":  {Summary: "Restore filter from the trash"},
+
+	// health
+	"GET /healthz/liveness":   {Summary: "Liveness probe", ContentType: "text/plain"},
+	"GET /healthz/readiness":  {Summary: "Readiness probe", ContentType: "text/plain"},
+	"GET /healthz/components": {Summary: "Health of the database, irc networks, feeds and download clients, 503 when any is degraded or down", Response: domain.HealthReport{}},
+
+	// indexers
+	"GET /indexer":                       {Summary: "List indexer definitions", Response: []domain.IndexerDefinition{}},
+	"POST /indexer":                      {Summary: "Create indexer", Request: domain.Indexer{}, Response: domain.Indexer{}, Status: http.StatusCreated},
+	"GET /indexer/options":               {Summary: "List indexers", Response: []domain.Indexer{}},
+	"GET /indexer/schema":                {Summary: "List indexer templates", Response: []domain.IndexerDefinition{}},
+	"GET /indexer/{indexerID}":           {Summary: "Get indexer", Response: domain.Indexer{}},
+	"PUT /indexer/{indexerID}":           {Summary: "Update indexer", Request: domain.Indexer{}, Response: domain.Indexer{}},
+	"DELETE /indexer/{indexerID}":        {Summary: "Delete indexer"},
+	"POST /indexer/{indexerID}/api/test": {Summary: "Test indexer api", Request: domain.IndexerTestApiRequest{}, Response: statusResponse{}},
+	"GET /indexer/{indexerID}/pipeline":  {Summary: "Get indexer pipeline", Response: domain.IndexerPipeline{}},
+	"PATCH /indexer/{indexerID}/enabled": {Summary: "Toggle indexer enabled", Request: toggleEnabledRequest{}},
+
+	// irc
+	"GET /irc":                              {Summary: "List irc networks with health", Response: []domain.IrcNetworkWithHealth{}},
+	"POST /irc":                             {Summary: "Create irc network", Request: domain.IrcNetwork{}},
+	"GET /irc/events":                       {Summary: "Stream irc events", ContentType: "text/event-stream"},
+	"GET /irc/network/{networkID}":          {Summary: "Get irc network", Response: domain.IrcNetwork{}},
+	"PUT /irc/network/{networkID}":          {Summary: "Update irc network", Request: domain.IrcNetwork{}},
+	"DELETE /irc/network/{networkID}":       {Summary: "Delete irc network"},
+	"POST /irc/network/{networkID}/cmd":     {Summary: "Send irc command", Request: domain.SendIrcCmdRequest{}},
+	"POST /irc/network/{networkID}/channel": {Summary: "Add irc channel", Request: domain.IrcChannel{}},
+	"GET /irc/network/{networkID}/restart":  {Summary: "Restart irc network"},
+	"POST /irc/network/{networkID}/channel/{channel}/announce/process": {Summary: "Process announce manually", Request: domain.IRCManualProcessRequest{}},
+
+	// users
+	"GET /users":                 {Summary: "List users", Response: []domain.User{}},
+	"POST /users":                {Summary: "Add user", Request: domain.CreateUserRequest{}, Status: http.StatusCreated},
+	"PATCH /users/{userID}/role": {Summary: "Change the role of a user", Request: domain.UpdateUserRoleRequest{}},
+	"DELETE /users/{userID}":     {Summary: "Delete user"},
+
+	// api keys
+	"GET /keys":             {Summary: "List api keys", Response: []domain.APIKey{}},
+	"POST /keys":            {Summary: "Create api key", Request: domain.APIKey{}, Response: domain.APIKey{}, Status: http.StatusCreated},
+	"DELETE /keys/{apikey}": {Summary: "Delete api key"},
+
+	// lists
+	"GET /lists":                    {Summary: "List lists", Response: []domain.List{}},
+	"POST /lists":                   {Summary: "Create list", Request: domain.List{}, Response: domain.List{}, Status: http.StatusCreated},
+	"GET /lists/{listID}":           {Summary: "Get list", Response: domain.List{}},
+	"PUT /lists/{listID}":           {Summary: "Update list", Request: domain.List{}},
+	"DELETE /lists/{listID}":        {Summary: "Delete list"},
+	"PATCH /lists/{listID}/enabled": {Summary: "Toggle list enabled", Request: toggleEnabledRequest{}},
+	"POST /lists/{listID}/refresh":  {Summary: "Refresh list"},
+
+	// logs
+	"GET /logs/files":           {Summary: "List log files", Response: LogfilesResponse{}},
+	"GET /logs/files/{logFile}": {Summary: "Download log file", ContentType: "text/plain"},
+
+	// mock indexer
+	"GET /mock/torrent": {Summary: "Download torrent of the mock indexer", Query: []openAPIQueryParam{
+		{Name: "name", Type: "string"},
+		{Name: "size", Type: "integer"},
+	}, ContentType: "application/x-bittorrent"},
+
+	// notifications
+	"GET /notification":             {Summary: "List notification agents", Response: []domain.Notification{}},
+	"POST /notification":            {Summary: "Create notification agent", Request: domain.Notification{}, Response: domain.Notification{}, Status: http.StatusCreated},
+	"POST /notification/test":       {Summary: "Send test notification", Request: domain.Notification{}},
+	"GET /notification/preferences": {Summary: "Get notification preferences of the user", Response: domain.UserNotificationPreference{}},
+	"PUT /notification/preferences": {Summary: "Update notification preferences of the user", Request: domain.UserNotificationPreference{}, Response: domain.UserNotificationPreference{}},
+	"GET /notification/deliveries": {Summary: "List queued notifications", Query: []openAPIQueryParam{
+		{Name: "status", Type: "string", Description: "PENDING, FAILED or DEFERRED"},
+		{Name: "limit", Type: "integer"},
+	}, Response: []domain.NotificationDelivery{}},
+	"POST /notification/deliveries/{deliveryID}/resend": {Summary: "Resend queued notification"},
+	"DELETE /notification/deliveries/{deliveryID}":      {Summary: "Delete queued notification"},
+	"GET /notification/{notificationID}":                {Summary: "Get notification agent", Response: domain.Notification{}},
+	"PUT /notification/{notificationID}":                {Summary: "Update notification agent", Request: domain.Notification{}, Response: domain.Notification{}},
+	"DELETE /notification/{notificationID}":             {Summary: "Delete notification agent"},
+
+	// openapi
+	"GET /openapi.json": {Summary: "Get the OpenAPI spec of the api", Response: map[string]any{}},
+
+	// proxies
+	"GET /proxy":              {Summary: "List proxies", Response: []domain.Proxy{}},
+	"POST /proxy":             {Summary: "Create proxy", Request: domain.Proxy{}},
+	"POST /proxy/test":        {Summary: "Test proxy", Request: domain.Proxy{}},
+	"GET /proxy/{proxyID}":    {Summary: "Get proxy", Response: domain.Proxy{}},
+	"PUT /proxy/{proxyID}":    {Summary: "Update proxy", Request: domain.Proxy{}},
+	"DELETE /proxy/{proxyID}": {Summary: "Delete proxy"},
+
+	// releases
+	"GET /release": {Summary: "Find releases", Query: []openAPIQueryParam{
+		{Name: "limit", Type: "integer"},
+		{Name: "offset", Type: "integer"},
+		{Name: "cursor", Type: "integer"},
+		{Name: "indexer", Type: "string", Description: "Indexer identifier", Array: true},
+		{Name: "push_status", Type: "string", Description: "PUSH_APPROVED, PUSH_REJECTED or PUSH_ERROR"},
+		{Name: "client", Type: "string", Description: "Download client of the same action status as push_status", Array: true},
+		{Name: "resolution", Type: "string", Array: true},
+		{Name: "source", Type: "string", Array: true},
+		{Name: "codec", Type: "string", Array: true},
+		{Name: "hdr", Type: "string", Array: true},
+		{Name: "group", Type: "string", Description: "Release group", Array: true},
+		{Name: "min_size", Type: "string", Description: "Bytes or a size like 10GB"},
+		{Name: "max_size", Type: "string", Description: "Bytes or a size like 10GB"},
+		{Name: "from", Type: "string", Description: "RFC 3339 or date"},
+		{Name: "to", Type: "string", Description: "RFC 3339 or date"},
+		{Name: "user_tag", Type: "string", Description: "User tag", Array: true},
+		{Name: "q", Type: "string", Description: "Search, notes:value and tag:value match the notes and user tags"},
+	}, Response: domain.FindReleasesResponse{}},
+	"DELETE /release": {Summary: "Delete releases matching all of the conditions", Response: domain.DeleteReleaseResponse{}, Query: []openAPIQueryParam{
+		{Name: "olderThan", Type: "integer", Description: "Hours"},
+		{Name: "indexer", Type: "string", Description: "Indexer identifier", Array: true},
+		{Name: "filter", Type: "integer", Description: "Filter id", Array: true},
+		{Name: "releaseStatus", Type: "string", Description: "Push status of any action", Array: true},
+	}},
+	"GET /release/recent":  {Summary: "List the latest releases", Response: domain.FindReleasesResponse{}},
+	"GET /release/export":  {Summary: "Export releases", Query: openAPIReleaseExportQuery, ContentType: "text/csv"},
+	"POST /release/export": {Summary: "Export releases to storage", Query: openAPIReleaseExportQuery, Response: domain.StorageObject{}, Status: http.StatusCreated},
+	"GET /release/stats":   {Summary: "Get release stats", Response: domain.ReleaseStats{}},
+	"GET /release/stats/latency": {Summary: "Get release latency stats", Query: []openAPIQueryParam{
+		{Name: "days", Type: "integer"},
+	}, Response: domain.ReleaseLatencyStats{}},
+	"GET /release/indexers":         {Summary: "List indexers of the releases", Response: []string{}},
+	"GET /release/consistency":      {Summary: "Check release consistency", Response: []domain.ReleaseConsistencyReport{}},
+	"GET /release/retention":        {Summary: "Get release retention", Response: domain.ReleaseRetention{}},
+	"PUT /release/retention":        {Summary: "Update release retention", Request: domain.ReleaseRetention{}},
+	"POST /release/retention/prune": {Summary: "Prune releases by the retention", Response: domain.ReleasePruneResult{}},
+	"GET /release/pending": {Summary: "List pending releases", Query: []openAPIQueryParam{
+		{Name: "type", Type: "string"},
+	}, Response: []domain.ReleasePending{}},
+	"GET /release/pending/counts":               {Summary: "Count pending releases", Response: domain.ReleasePendingCounts{}},
+	"DELETE /release/pending/{pendingID}":       {Summary: "Cancel pending release"},
+	"POST /release/pending/{pendingID}/push":    {Summary: "Push pending release now"},
+	"POST /release/pending/{pendingID}/approve": {Summary: "Approve pending release"},
+	"POST /release/pending/{pendingID}/reject":  {Summary: "Reject pending release"},
+	"POST /release/process":                     {Summary: "Process announce", Request: domain.ReleaseProcessReq{}},
+	"POST /release/process/external": {Summary: "Process Prowlarr or Jackett releases", Query: []openAPIQueryParam{
+		{Name: "indexer", Type: "string", Description: "Indexer identifier"},
+	}, Request: []map[string]any{}},
+	"POST /release/webhook": {Summary: "Submit releases from custom tools for processing", Query: []openAPIQueryParam{
+		{Name: "indexer", Type: "string", Description: "Indexer identifier"},
+	}, Request: []domain.ExternalRelease{}, Response: domain.ReleaseWebhookResponse{}},
+	"POST /release/simulate":                                   {Summary: "Simulate announce against the filters", Request: domain.ReleaseSimulateReq{}, Response: domain.ReleaseSimulateResponse{}},
+	"POST /release/actions/retry":                              {Summary: "Retry the failed pushes matching client, indexer and max_age in hours", Request: domain.ReleaseRetryFailedReq{}, Response: domain.ReleaseRetryFailedResponse{}, Status: http.StatusAccepted},
+	"GET /release/{releaseID}":                                 {Summary: "Get release", Response: domain.Release{}},
+	"PATCH /release/{releaseID}":                               {Summary: "Set the notes and user tags of a release", Request: domain.ReleaseAnnotationReq{}, Response: domain.Release{}},
+	"POST /release/{releaseID}/actions/{actionStatusID}/retry": {Summary: "Retry action of release"},
+	"POST /release/{releaseID}/reprocess":                      {Summary: "Run release through the filters of its indexer again, or only filter_id", Request: domain.ReleaseReprocessReq{}, Status: http.StatusAccepted},
+
+	// updates
+	"GET /updates/latest": {Summary: "Get latest release of autobrr", Response: version.Release{}},
+	"GET /updates/check":  {Summary: "Check for updates"},
+}
+
+var openAPIReleaseExportQuery = []openAPIQueryParam{
+	{Name: "format", Type: "string", Description: "csv or json"},
+	{Name: "indexer", Type: "string", Description: "Indexer identifier", Array: true},
+	{Name: "push_status", Type: "string"},
+	{Name: "from", Type: "string", Description: "RFC 3339 or date"},
+	{Name: "to", Type: "string", Description: "RFC 3339 or date"},
+}
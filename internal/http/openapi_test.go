@@ -0,0 +1,138 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/config"
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOpenAPITestServer() Server {
+	return Server{
+		config:      &config.AppConfig{Config: &domain.Config{BaseURL: "/autobrr/"}},
+		cookieStore: sessions.NewCookieStore([]byte("test")),
+		version:     "v1.0.0",
+	}
+}
+
+// apiRoutes returns the routes mounted on /api
+func apiRoutes(t *testing.T, s Server) chi.Routes {
+	for _, route := range s.Handler().(chi.Routes).Routes() {
+		if route.Pattern == "/api/*" {
+			return route.SubRoutes
+		}
+	}
+
+	t.Fatal("api routes not found")
+	return nil
+}
+
+// TestOpenAPIOperations_inSync makes sure every route is annotated and no annotation is left behind
+func TestOpenAPIOperations_inSync(t *testing.T) {
+	rts, err := walkOpenAPIRoutes(apiRoutes(t, newOpenAPITestServer()))
+	require.NoError(t, err)
+
+	routes := map[string]struct{}{}
+	for _, rt := range rts {
+		key := rt.method + " " + rt.path
+		routes[key] = struct{}{}
+
+		assert.Contains(t, openAPIOperations, key, "route is not annotated in openAPIOperations")
+	}
+
+	for key := range openAPIOperations {
+		assert.Contains(t, routes, key, "annotated route does not exist")
+	}
+}
+
+func TestOpenAPIHandler_getSpec(t *testing.T) {
+	testServer := httptest.NewServer(newOpenAPITestServer().Handler())
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/api/openapi.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var spec openAPISpec
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+	assert.Equal(t, "v1.0.0", spec.Info.Version)
+	assert.Equal(t, "/autobrr/api", spec.Servers[0].URL)
+
+	op := spec.Paths["/filters/{filterID}"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "filterGetByID", op.OperationID)
+	assert.Equal(t, []string{"filters"}, op.Tags)
+	assert.Equal(t, []openAPIParameter{{Name: "filterID", In: "path", Required: true, Schema: &openAPISchema{Type: "integer"}}}, op.Parameters)
+	assert.Equal(t, "#/components/schemas/Filter", op.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, openAPISecurity, op.Security)
+
+	store := spec.Paths["/filters"]["post"]
+	require.NotNil(t, store)
+	assert.Equal(t, "#/components/schemas/Filter", store.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, store.Responses, "201")
+
+	// public routes
+	assert.Empty(t, spec.Paths["/healthz/liveness"]["get"].Security)
+
+	// routes for every method are documented as GET
+	assert.Len(t, spec.Paths["/events"], 1)
+	assert.Contains(t, spec.Paths["/events"], "get")
+
+	filter := spec.Components.Schemas["Filter"]
+	require.NotNil(t, filter)
+	assert.Equal(t, &openAPISchema{Type: "string"}, filter.Properties["name"])
+	assert.Equal(t, &openAPISchema{Type: "string", Format: "date-time"}, filter.Properties["created_at"])
+}
+
+func TestOpenAPISchemas_schema(t *testing.T) {
+	type embedded struct {
+		ID int64 `json:"id"`
+	}
+
+	type item struct {
+		embedded
+		Name     string            `json:"name"`
+		Secret   string            `json:"-"`
+		Count    int               `json:"count,string"`
+		Tags     []string          `json:"tags,omitempty"`
+		Labels   map[string]string `json:"labels"`
+		Data     []byte            `json:"data"`
+		Parent   *item             `json:"parent"`
+		Timeout  time.Duration     `json:"timeout"`
+		Untagged bool
+		hidden   string
+	}
+
+	schemas := newOpenAPISchemas()
+
+	assert.Equal(t, &openAPISchema{Type: "array", Items: &openAPISchema{Ref: "#/components/schemas/Item"}}, schemas.schema(reflect.TypeOf([]item{})))
+
+	assert.Equal(t, &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{
+		"id":       {Type: "integer", Format: "int64"},
+		"name":     {Type: "string"},
+		"count":    {Type: "string"},
+		"tags":     {Type: "array", Items: &openAPISchema{Type: "string"}},
+		"labels":   {Type: "object", AdditionalProperties: &openAPISchema{Type: "string"}},
+		"data":     {Type: "string", Format: "byte"},
+		"parent":   {Ref: "#/components/schemas/Item"},
+		"timeout":  {Type: "integer", Format: "int64"},
+		"Untagged": {Type: "boolean"},
+	}}, schemas.components["Item"])
+}
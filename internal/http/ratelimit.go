@@ -0,0 +1,175 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterIdle is how long a client is kept after its last request
+const rateLimiterIdle = 10 * time.Minute
+
+// rateLimiter allows a number of requests per minute for each client, bursts up to the full minute are allowed
+type rateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	m         sync.Mutex
+	clients   map[string]*rateLimiterClient
+	lastPrune time.Time
+}
+
+type rateLimiterClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter returns nil when perMinute is unlimited
+func newRateLimiter(perMinute int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+
+	return &rateLimiter{
+		limit:   rate.Limit(float64(perMinute) / time.Minute.Seconds()),
+		burst:   perMinute,
+		clients: map[string]*rateLimiterClient{},
+	}
+}
+
+// reserve takes a request of the client, it returns how long to wait when over the limit
+func (l *rateLimiter) reserve(key string, now time.Time) (time.Duration, bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.prune(now)
+
+	client, ok := l.clients[key]
+	if !ok {
+		client = &rateLimiterClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = client
+	}
+
+	client.lastSeen = now
+
+	if client.limiter.AllowN(now, 1) {
+		return 0, true
+	}
+
+	// the time until the next request is allowed, without taking it
+	r := client.limiter.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	r.CancelAt(now)
+
+	return delay, false
+}
+
+// prune forgets the clients which went quiet so the map doesn't grow forever
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+
+	for key, client := range l.clients {
+		if now.Sub(client.lastSeen) > rateLimiterIdle {
+			delete(l.clients, key)
+		}
+	}
+
+	l.lastPrune = now
+}
+
+// rateLimit is the limiter a request counts against and the client it counts for
+type rateLimit struct {
+	limiter *rateLimiter
+	key     string
+}
+
+// RateLimit limits the api requests per client ip and api key, logins and onboarding have their own limit.
+// Limits of 0 are unlimited, health checks are never limited.
+func RateLimit(e encoder, cfg domain.RateLimitConfig) func(next http.Handler) http.Handler {
+	perIP := newRateLimiter(cfg.PerIP)
+	perKey := newRateLimiter(cfg.PerKey)
+	auth := newRateLimiter(cfg.Auth)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// probes of the health checks must not fail because of other clients
+			if hasPathPrefix(apiPath(r), "/healthz") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			ip := clientIP(r)
+
+			limits := []rateLimit{{limiter: perIP, key: ip}}
+
+			if token := requestAPIToken(r); token != "" {
+				limits = append(limits, rateLimit{limiter: perKey, key: token})
+			}
+
+			if isAuthAttempt(r) {
+				limits = append(limits, rateLimit{limiter: auth, key: ip})
+			}
+
+			for _, limit := range limits {
+				if limit.limiter == nil {
+					continue
+				}
+
+				if delay, ok := limit.limiter.reserve(limit.key, now); !ok {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+					e.ErrorCode(w, http.StatusTooManyRequests, "", "rate limit exceeded, try again later")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAuthAttempt reports whether the request tries to log in or create the first user,
+// checking or ending the session isn't limited
+func isAuthAttempt(r *http.Request) bool {
+	path := apiPath(r)
+
+	switch r.Method {
+	case http.MethodPost:
+		return path == "/auth/login" || path == "/auth/onboard"
+	case http.MethodGet:
+		return path == "/auth/oidc/callback"
+	}
+
+	return false
+}
+
+// clientIP is the ip of the client without port, middleware.RealIP already applied the proxy headers.
+// Requests of the unix socket without proxy headers have no ip, each connection counts as a client.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err == nil {
+		return host
+	}
+
+	if net.ParseIP(r.RemoteAddr) != nil {
+		return r.RemoteAddr
+	}
+
+	if id, ok := r.Context().Value(unixConnKey{}).(uint64); ok {
+		return "unix:" + strconv.FormatUint(id, 10)
+	}
+
+	return r.RemoteAddr
+}
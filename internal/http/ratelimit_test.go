@@ -0,0 +1,128 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_reserve(t *testing.T) {
+	l := newRateLimiter(2)
+	now := time.Now()
+
+	_, ok := l.reserve("a", now)
+	assert.True(t, ok)
+	_, ok = l.reserve("a", now)
+	assert.True(t, ok)
+
+	delay, ok := l.reserve("a", now)
+	assert.False(t, ok)
+	assert.Equal(t, 30*time.Second, delay)
+
+	// other clients have their own limit
+	_, ok = l.reserve("b", now)
+	assert.True(t, ok)
+
+	// a request is allowed again once the limit refilled
+	_, ok = l.reserve("a", now.Add(30*time.Second))
+	assert.True(t, ok)
+
+	// quiet clients are forgotten
+	l.reserve("b", now.Add(rateLimiterIdle+2*time.Minute))
+	assert.Len(t, l.clients, 1)
+
+	assert.Nil(t, newRateLimiter(0))
+}
+
+func TestRateLimit(t *testing.T) {
+	request := func(handler http.Handler, method, path, remoteAddr, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("X-API-Token", token)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("per_ip", func(t *testing.T) {
+		handler := RateLimit(encoder{log: zerolog.Nop()}, domain.RateLimitConfig{PerIP: 1})(ok)
+
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/filters", "10.0.0.1:1234", "").Code)
+
+		w := request(handler, http.MethodGet, "/api/filters", "10.0.0.1:4321", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "TOO_MANY_REQUESTS")
+
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/filters", "10.0.0.2:1234", "").Code)
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/healthz/liveness", "10.0.0.1:1234", "").Code)
+	})
+
+	t.Run("per_key", func(t *testing.T) {
+		handler := RateLimit(encoder{log: zerolog.Nop()}, domain.RateLimitConfig{PerKey: 1})(ok)
+
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/filters", "10.0.0.1:1234", "key-a").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(handler, http.MethodGet, "/api/filters", "10.0.0.2:1234", "key-a").Code)
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/filters", "10.0.0.1:1234", "key-b").Code)
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/filters", "10.0.0.1:1234", "").Code)
+	})
+
+	t.Run("auth", func(t *testing.T) {
+		handler := RateLimit(encoder{log: zerolog.Nop()}, domain.RateLimitConfig{Auth: 1})(ok)
+
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodPost, "/api/auth/login", "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(handler, http.MethodPost, "/api/auth/login", "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(handler, http.MethodPost, "/api/auth/onboard", "10.0.0.1:1234", "").Code)
+
+		// the session checks of the web ui aren't logins
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/auth/validate", "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodGet, "/api/filters", "10.0.0.1:1234", "").Code)
+	})
+
+	t.Run("unix_socket", func(t *testing.T) {
+		handler := middleware.RealIP(RateLimit(encoder{log: zerolog.Nop()}, domain.RateLimitConfig{PerIP: 1})(ok))
+
+		unixRequest := func(conn uint64, realIP string) int {
+			req := httptest.NewRequest(http.MethodGet, "/api/filters", nil)
+			req = req.WithContext(context.WithValue(req.Context(), unixConnKey{}, conn))
+			req.RemoteAddr = "@"
+			if realIP != "" {
+				req.Header.Set("X-Real-IP", realIP)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			return w.Code
+		}
+
+		// without proxy headers each connection is limited on its own
+		assert.Equal(t, http.StatusNoContent, unixRequest(1, ""))
+		assert.Equal(t, http.StatusTooManyRequests, unixRequest(1, ""))
+		assert.Equal(t, http.StatusNoContent, unixRequest(2, ""))
+
+		// the client ip of the proxy is limited across connections
+		assert.Equal(t, http.StatusNoContent, unixRequest(3, "10.0.0.1"))
+		assert.Equal(t, http.StatusTooManyRequests, unixRequest(4, "10.0.0.1"))
+		assert.Equal(t, http.StatusNoContent, unixRequest(4, "10.0.0.2"))
+	})
+}
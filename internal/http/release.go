@@ -7,24 +7,45 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/dustin/go-humanize"
 	"github.com/go-chi/chi/v5"
 )
 
 type releaseService interface {
 	Find(ctx context.Context, query domain.ReleaseQueryParams) (*domain.FindReleasesResponse, error)
+	Export(ctx context.Context, params domain.ReleaseExportParams, fn func(release *domain.Release) error) error
+	StoreExport(ctx context.Context, params domain.ReleaseExportParams, format domain.ReleaseExportFormat) (*domain.StorageObject, error)
 	Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
-	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error
+	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error)
 	Retry(ctx context.Context, req *domain.ReleaseActionRetryReq) error
+	Reprocess(ctx context.Context, req *domain.ReleaseReprocessReq) error
+	RetryFailed(ctx context.Context, req *domain.ReleaseRetryFailedReq) (*domain.ReleaseRetryFailedResponse, error)
+	Annotate(ctx context.Context, req *domain.ReleaseAnnotationReq) (*domain.Release, error)
 	ProcessManual(ctx context.Context, req *domain.ReleaseProcessReq) error
+	Simulate(ctx context.Context, req *domain.ReleaseSimulateReq) (*domain.ReleaseSimulateResponse, error)
+	ProcessExternal(ctx context.Context, req *domain.ReleaseProcessExternalReq) error
+	CheckConsistency(ctx context.Context) ([]domain.ReleaseConsistencyReport, error)
+	LatencyStats(ctx context.Context, days int) (*domain.ReleaseLatencyStats, error)
+	GetRetention(ctx context.Context) (*domain.ReleaseRetention, error)
+	UpdateRetention(ctx context.Context, retention *domain.ReleaseRetention) error
+	PruneReleases(ctx context.Context) (*domain.ReleasePruneResult, error)
+	ListPending(ctx context.Context, pendingType domain.ReleasePendingType) ([]domain.ReleasePending, error)
+	PendingCounts(ctx context.Context) (*domain.ReleasePendingCounts, error)
+	CancelPending(ctx context.Context, id int64) error
+	PushPending(ctx context.Context, id int64) error
+	ApprovePending(ctx context.Context, id int64) error
+	RejectPending(ctx context.Context, id int64) error
 }
 
 type releaseHandler struct {
@@ -42,15 +63,40 @@ func newReleaseHandler(encoder encoder, service releaseService) *releaseHandler
 func (h releaseHandler) Routes(r chi.Router) {
 	r.Get("/", h.findReleases)
 	r.Get("/recent", h.findRecentReleases)
+	r.Get("/export", h.exportReleases)
+	r.Post("/export", h.storeExport)
 	r.Get("/stats", h.getStats)
+	r.Get("/stats/latency", h.getLatencyStats)
 	r.Get("/indexers", h.getIndexerOptions)
+	r.Get("/consistency", h.checkConsistency)
 	r.Delete("/", h.deleteReleases)
 
-	//r.Post("/process", h.retryAction)
+	r.Route("/retention", func(r chi.Router) {
+		r.Get("/", h.getRetention)
+		r.Put("/", h.updateRetention)
+		r.Post("/prune", h.pruneReleases)
+	})
+
+	r.Route("/pending", func(r chi.Router) {
+		r.Get("/", h.listPending)
+		r.Get("/counts", h.getPendingCounts)
+		r.Delete("/{pendingID}", h.cancelPending)
+		r.Post("/{pendingID}/push", h.pushPending)
+		r.Post("/{pendingID}/approve", h.approvePending)
+		r.Post("/{pendingID}/reject", h.rejectPending)
+	})
+
+	r.Post("/process", h.process)
+	r.Post("/process/external", h.processExternal)
+	r.Post("/webhook", h.processWebhook)
+	r.Post("/simulate", h.simulate)
+	r.Post("/actions/retry", h.retryFailedActions)
 
 	r.Route("/{releaseID}", func(r chi.Router) {
 		r.Get("/", h.getReleaseByID)
+		r.Patch("/", h.annotate)
 		r.Post("/actions/{actionStatusID}/retry", h.retryAction)
+		r.Post("/reprocess", h.reprocess)
 	})
 }
 
@@ -58,10 +104,7 @@ func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
 	limitP := r.URL.Query().Get("limit")
 	limit, err := strconv.Atoi(limitP)
 	if err != nil && limitP != "" {
-		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-			"code":    "BAD_REQUEST_PARAMS",
-			"message": "limit parameter is invalid",
-		})
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "limit parameter is invalid")
 		return
 	}
 	if limit == 0 {
@@ -71,10 +114,7 @@ func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
 	offsetP := r.URL.Query().Get("offset")
 	offset, err := strconv.Atoi(offsetP)
 	if err != nil && offsetP != "" {
-		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-			"code":    "BAD_REQUEST_PARAMS",
-			"message": "offset parameter is invalid",
-		})
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "offset parameter is invalid")
 		return
 	}
 
@@ -83,20 +123,14 @@ func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
 	if cursorP != "" {
 		cursor, err = strconv.Atoi(cursorP)
 		if err != nil && cursorP != "" {
-			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-				"code":    "BAD_REQUEST_PARAMS",
-				"message": "cursor parameter is invalid",
-			})
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "cursor parameter is invalid")
 		}
 		return
 	}
 
 	u, err := url.Parse(r.URL.String())
 	if err != nil {
-		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-			"code":    "BAD_REQUEST_PARAMS",
-			"message": "indexer parameter is invalid",
-		})
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "indexer parameter is invalid")
 		return
 	}
 	vals := u.Query()
@@ -105,10 +139,7 @@ func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
 	pushStatus := r.URL.Query().Get("push_status")
 	if pushStatus != "" {
 		if !domain.ValidReleasePushStatus(pushStatus) {
-			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-				"code":    "BAD_REQUEST_PARAMS",
-				"message": fmt.Sprintf("push_status parameter is of invalid type: %v", pushStatus),
-			})
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", fmt.Sprintf("push_status parameter is of invalid type: %v", pushStatus))
 			return
 		}
 	}
@@ -120,19 +151,51 @@ func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
 		Offset: uint64(offset),
 		Cursor: uint64(cursor),
 		Sort:   nil,
-		Filters: struct {
-			Indexers   []string
-			PushStatus string
-		}{Indexers: indexer, PushStatus: pushStatus},
 		Search: search,
 	}
 
+	query.Filters.Indexers = indexer
+	query.Filters.PushStatus = pushStatus
+	query.Filters.Resolutions = vals["resolution"]
+	query.Filters.Sources = vals["source"]
+	query.Filters.Codecs = vals["codec"]
+	query.Filters.HDR = vals["hdr"]
+	query.Filters.ReleaseGroups = vals["group"]
+	query.Filters.Clients = vals["client"]
+	query.Filters.UserTags = vals["user_tag"]
+
+	if v := vals.Get("min_size"); v != "" {
+		if query.Filters.MinSize, err = humanize.ParseBytes(v); err != nil {
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "min_size parameter is invalid")
+			return
+		}
+	}
+
+	if v := vals.Get("max_size"); v != "" {
+		if query.Filters.MaxSize, err = humanize.ParseBytes(v); err != nil {
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "max_size parameter is invalid")
+			return
+		}
+	}
+
+	if query.Filters.MaxSize > 0 && query.Filters.MinSize > query.Filters.MaxSize {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "min_size is larger than max_size")
+		return
+	}
+
+	if query.Filters.From, err = parseExportTime(vals.Get("from")); err != nil {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "from parameter is invalid")
+		return
+	}
+
+	if query.Filters.To, err = parseExportTime(vals.Get("to")); err != nil {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "to parameter is invalid")
+		return
+	}
+
 	resp, err := h.service.Find(r.Context(), query)
 	if err != nil {
-		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]any{
-			"code":    "INTERNAL_SERVER_ERROR",
-			"message": err.Error(),
-		})
+		h.encoder.ErrorCode(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err.Error())
 		return
 	}
 
@@ -142,16 +205,120 @@ func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
 func (h releaseHandler) findRecentReleases(w http.ResponseWriter, r *http.Request) {
 	resp, err := h.service.Find(r.Context(), domain.ReleaseQueryParams{Limit: 10})
 	if err != nil {
-		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]any{
-			"code":    "INTERNAL_SERVER_ERROR",
-			"message": err.Error(),
-		})
+		h.encoder.ErrorCode(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err.Error())
 		return
 	}
 
 	h.encoder.StatusResponse(w, http.StatusOK, resp)
 }
 
+// exportParams parses the export query parameters, on invalid parameters the error is written and ok is false
+func (h releaseHandler) exportParams(w http.ResponseWriter, r *http.Request) (format domain.ReleaseExportFormat, params domain.ReleaseExportParams, ok bool) {
+	format = domain.ReleaseExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = domain.ReleaseExportFormatCSV
+	}
+
+	if format != domain.ReleaseExportFormatCSV && format != domain.ReleaseExportFormatJSON {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", fmt.Sprintf("format parameter is of invalid type: %v", format))
+		return format, params, false
+	}
+
+	params = domain.ReleaseExportParams{
+		Indexers:   r.URL.Query()["indexer"],
+		PushStatus: r.URL.Query().Get("push_status"),
+	}
+
+	if params.PushStatus != "" && !domain.ValidReleasePushStatus(params.PushStatus) {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", fmt.Sprintf("push_status parameter is of invalid type: %v", params.PushStatus))
+		return format, params, false
+	}
+
+	var err error
+	if params.From, err = parseExportTime(r.URL.Query().Get("from")); err != nil {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "from parameter is invalid")
+		return format, params, false
+	}
+
+	if params.To, err = parseExportTime(r.URL.Query().Get("to")); err != nil {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "to parameter is invalid")
+		return format, params, false
+	}
+
+	return format, params, true
+}
+
+// exportReleases streams the release history with action statuses as csv or json
+func (h releaseHandler) exportReleases(w http.ResponseWriter, r *http.Request) {
+	format, params, ok := h.exportParams(w, r)
+	if !ok {
+		return
+	}
+
+	writer, err := domain.NewReleaseExportWriter(format, w)
+	if err != nil {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", err.Error())
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == domain.ReleaseExportFormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+
+	filename := fmt.Sprintf("autobrr-releases-%s.%s", time.Now().Format("20060102-150405"), format)
+
+	// headers are sent with the first release, errors after that can only abort the stream
+	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(filename))
+	w.Header().Set("Content-Type", contentType)
+
+	written := 0
+	if err := h.service.Export(r.Context(), params, func(release *domain.Release) error {
+		written++
+		return writer.Write(release)
+	}); err != nil {
+		if written == 0 {
+			w.Header().Del("Content-Disposition")
+			h.encoder.Error(w, err)
+			return
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	if err := writer.Close(); err != nil {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// storeExport writes the release history export to the configured storage
+func (h releaseHandler) storeExport(w http.ResponseWriter, r *http.Request) {
+	format, params, ok := h.exportParams(w, r)
+	if !ok {
+		return
+	}
+
+	object, err := h.service.StoreExport(r.Context(), params, format)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, object)
+}
+
+// parseExportTime parses an RFC3339 timestamp or a date, empty values are zero
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
+
 func (h releaseHandler) getReleaseByID(w http.ResponseWriter, r *http.Request) {
 	releaseID, err := strconv.Atoi(chi.URLParam(r, "releaseID"))
 	if err != nil {
@@ -176,10 +343,7 @@ func (h releaseHandler) getReleaseByID(w http.ResponseWriter, r *http.Request) {
 func (h releaseHandler) getIndexerOptions(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.service.GetIndexerOptions(r.Context())
 	if err != nil {
-		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]any{
-			"code":    "INTERNAL_SERVER_ERROR",
-			"message": err.Error(),
-		})
+		h.encoder.ErrorCode(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err.Error())
 		return
 	}
 
@@ -189,16 +353,79 @@ func (h releaseHandler) getIndexerOptions(w http.ResponseWriter, r *http.Request
 func (h releaseHandler) getStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.service.Stats(r.Context())
 	if err != nil {
-		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]any{
-			"code":    "INTERNAL_SERVER_ERROR",
-			"message": err.Error(),
-		})
+		h.encoder.ErrorCode(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err.Error())
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, stats)
+}
+
+func (h releaseHandler) getLatencyStats(w http.ResponseWriter, r *http.Request) {
+	days := 7
+
+	if daysP := r.URL.Query().Get("days"); daysP != "" {
+		d, err := strconv.Atoi(daysP)
+		if err != nil || d <= 0 {
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "days parameter must be a positive number")
+			return
+		}
+		days = d
+	}
+
+	stats, err := h.service.LatencyStats(r.Context(), days)
+	if err != nil {
+		h.encoder.ErrorCode(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err.Error())
 		return
 	}
 
 	h.encoder.StatusResponse(w, http.StatusOK, stats)
 }
 
+func (h releaseHandler) checkConsistency(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.CheckConsistency(r.Context())
+	if err != nil {
+		h.encoder.ErrorCode(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err.Error())
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, reports)
+}
+
+func (h releaseHandler) getRetention(w http.ResponseWriter, r *http.Request) {
+	retention, err := h.service.GetRetention(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, retention)
+}
+
+func (h releaseHandler) updateRetention(w http.ResponseWriter, r *http.Request) {
+	var data domain.ReleaseRetention
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.UpdateRetention(r.Context(), &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) pruneReleases(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.PruneReleases(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
+
 func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 	req := domain.DeleteReleaseRequest{}
 
@@ -206,10 +433,7 @@ func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 	if olderThanParam != "" {
 		duration, err := strconv.Atoi(olderThanParam)
 		if err != nil {
-			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-				"code":    "BAD_REQUEST_PARAMS",
-				"message": "olderThan parameter is invalid",
-			})
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "olderThan parameter is invalid")
 			return
 		}
 		req.OlderThan = duration
@@ -220,6 +444,15 @@ func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 		req.Indexers = indexers
 	}
 
+	for _, v := range r.URL.Query()["filter"] {
+		filterID, err := strconv.Atoi(v)
+		if err != nil {
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", "filter parameter is invalid")
+			return
+		}
+		req.FilterIDs = append(req.FilterIDs, filterID)
+	}
+
 	releaseStatuses := r.URL.Query()["releaseStatus"]
 	validStatuses := map[string]bool{
 		"PUSH_APPROVED": true,
@@ -231,21 +464,19 @@ func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 		if _, valid := validStatuses[status]; valid {
 			filteredStatuses = append(filteredStatuses, status)
 		} else {
-			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-				"code":    "INVALID_RELEASE_STATUS",
-				"message": "releaseStatus contains invalid value",
-			})
+			h.encoder.ErrorCode(w, http.StatusBadRequest, "INVALID_RELEASE_STATUS", "releaseStatus contains invalid value")
 			return
 		}
 	}
 	req.ReleaseStatuses = filteredStatuses
 
-	if err := h.service.Delete(r.Context(), &req); err != nil {
+	deleted, err := h.service.Delete(r.Context(), &req)
+	if err != nil {
 		h.encoder.Error(w, err)
 		return
 	}
 
-	h.encoder.NoContent(w)
+	h.encoder.StatusResponse(w, http.StatusOK, domain.DeleteReleaseResponse{Deleted: deleted})
 }
 
 func (h releaseHandler) process(w http.ResponseWriter, r *http.Request) {
@@ -256,18 +487,18 @@ func (h releaseHandler) process(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var validationErrs domain.ValidationErrors
 	if req.IndexerIdentifier == "" {
-		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-			"code":    "VALIDATION_ERROR",
-			"message": "field indexer_identifier empty",
-		})
+		validationErrs.Add("indexer_identifier", "field indexer_identifier empty")
 	}
 
 	if len(req.AnnounceLines) == 0 {
-		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]any{
-			"code":    "VALIDATION_ERROR",
-			"message": "field announce_lines empty",
-		})
+		validationErrs.Add("announce_lines", "field announce_lines empty")
+	}
+
+	if err := validationErrs.Err(); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
 	}
 
 	err = h.service.ProcessManual(r.Context(), req)
@@ -279,6 +510,183 @@ func (h releaseHandler) process(w http.ResponseWriter, r *http.Request) {
 	h.encoder.NoContent(w)
 }
 
+func (h releaseHandler) simulate(w http.ResponseWriter, r *http.Request) {
+	var req domain.ReleaseSimulateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	resp, err := h.service.Simulate(r.Context(), &req)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, resp)
+}
+
+// processExternal accepts Prowlarr and Jackett releases with an optional indexer param
+func (h releaseHandler) processExternal(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	releases, err := domain.ParseExternalReleases(body)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req := &domain.ReleaseProcessExternalReq{
+		IndexerIdentifier: r.URL.Query().Get("indexer"),
+		Releases:          releases,
+	}
+
+	if err := h.service.ProcessExternal(r.Context(), req); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+// processWebhook accepts releases from custom tools and runs them through the filters,
+// the indexer of the releases can be set with the indexer param
+func (h releaseHandler) processWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	releases, err := domain.ParseWebhookReleases(body)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req := &domain.ReleaseProcessExternalReq{
+		IndexerIdentifier: r.URL.Query().Get("indexer"),
+		Releases:          releases,
+	}
+
+	if err := h.service.ProcessExternal(r.Context(), req); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusAccepted, domain.ReleaseWebhookResponse{Accepted: len(releases)})
+}
+
+func (h releaseHandler) listPending(w http.ResponseWriter, r *http.Request) {
+	pendingType := domain.ReleasePendingType(r.URL.Query().Get("type"))
+	if pendingType != "" && !pendingType.Valid() {
+		h.encoder.ErrorCode(w, http.StatusBadRequest, "BAD_REQUEST_PARAMS", fmt.Sprintf("type parameter is invalid: %v", pendingType))
+		return
+	}
+
+	pending, err := h.service.ListPending(r.Context(), pendingType)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, pending)
+}
+
+func (h releaseHandler) getPendingCounts(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.service.PendingCounts(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, counts)
+}
+
+func (h releaseHandler) cancelPending(w http.ResponseWriter, r *http.Request) {
+	pendingID, err := strconv.ParseInt(chi.URLParam(r, "pendingID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.CancelPending(r.Context(), pendingID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("could not find pending release with id %d", pendingID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) pushPending(w http.ResponseWriter, r *http.Request) {
+	pendingID, err := strconv.ParseInt(chi.URLParam(r, "pendingID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.PushPending(r.Context(), pendingID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("could not find pending release with id %d", pendingID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) approvePending(w http.ResponseWriter, r *http.Request) {
+	pendingID, err := strconv.ParseInt(chi.URLParam(r, "pendingID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.ApprovePending(r.Context(), pendingID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("could not find pending release with id %d", pendingID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) rejectPending(w http.ResponseWriter, r *http.Request) {
+	pendingID, err := strconv.ParseInt(chi.URLParam(r, "pendingID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.RejectPending(r.Context(), pendingID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, errors.New("could not find pending release with id %d", pendingID))
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
 func (h releaseHandler) retryAction(w http.ResponseWriter, r *http.Request) {
 	releaseID, err := strconv.Atoi(chi.URLParam(r, "releaseID"))
 	if err != nil {
@@ -309,3 +717,79 @@ func (h releaseHandler) retryAction(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+// reprocess runs the release through the filters in the background, the body with the filter is optional
+func (h releaseHandler) reprocess(w http.ResponseWriter, r *http.Request) {
+	releaseID, err := strconv.Atoi(chi.URLParam(r, "releaseID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req domain.ReleaseReprocessReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req.ReleaseID = releaseID
+
+	if err := h.service.Reprocess(r.Context(), &req); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.NotFoundErr(w, err)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusAccepted, nil)
+}
+
+// retryFailedActions retries the failed pushes in the background, without body all of them are retried
+func (h releaseHandler) retryFailedActions(w http.ResponseWriter, r *http.Request) {
+	var req domain.ReleaseRetryFailedReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.MaxAge < 0 {
+		h.encoder.StatusError(w, http.StatusBadRequest, domain.ValidationErrors{{Field: "max_age", Message: "max_age must not be negative"}})
+		return
+	}
+
+	resp, err := h.service.RetryFailed(r.Context(), &req)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusAccepted, resp)
+}
+
+// annotate sets the notes and user tags of the release, the fields left out of the body are kept
+func (h releaseHandler) annotate(w http.ResponseWriter, r *http.Request) {
+	releaseID, err := strconv.Atoi(chi.URLParam(r, "releaseID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req domain.ReleaseAnnotationReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req.ReleaseID = int64(releaseID)
+
+	release, err := h.service.Annotate(r.Context(), &req)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, release)
+}
@@ -4,6 +4,7 @@
 package http
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/web"
 
 	"github.com/go-chi/chi/v5"
@@ -36,19 +38,23 @@ type Server struct {
 
 	actionService         actionService
 	apiService            apikeyService
+	auditService          auditService
 	authService           authService
 	downloadClientService downloadClientService
 	filterService         filterService
 	feedService           feedService
+	healthService         healthService
 	indexerService        indexerService
 	ircService            ircService
+	listService           listService
+	mockIndexerService    mockIndexerService
 	notificationService   notificationService
 	proxyService          proxyService
 	releaseService        releaseService
 	updateService         updateService
 }
 
-func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db *database.DB, version string, commit string, date string, actionService actionService, apiService apikeyService, authService authService, downloadClientSvc downloadClientService, filterSvc filterService, feedSvc feedService, indexerSvc indexerService, ircSvc ircService, notificationSvc notificationService, proxySvc proxyService, releaseSvc releaseService, updateSvc updateService) Server {
+func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db *database.DB, version string, commit string, date string, actionService actionService, apiService apikeyService, auditService auditService, authService authService, downloadClientSvc downloadClientService, filterSvc filterService, feedSvc feedService, healthSvc healthService, indexerSvc indexerService, ircSvc ircService, listSvc listService, mockIndexerSvc mockIndexerService, notificationSvc notificationService, proxySvc proxyService, releaseSvc releaseService, updateSvc updateService) Server {
 	return Server{
 		log:     log.With().Str("module", "http").Logger(),
 		config:  config,
@@ -62,12 +68,16 @@ func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db
 
 		actionService:         actionService,
 		apiService:            apiService,
+		auditService:          auditService,
 		authService:           authService,
 		downloadClientService: downloadClientSvc,
 		filterService:         filterSvc,
 		feedService:           feedSvc,
+		healthService:         healthSvc,
 		indexerService:        indexerSvc,
 		ircService:            ircSvc,
+		listService:           listSvc,
+		mockIndexerService:    mockIndexerSvc,
 		notificationService:   notificationSvc,
 		proxyService:          proxySvc,
 		releaseService:        releaseSvc,
@@ -75,12 +85,55 @@ func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db
 	}
 }
 
+// Open serves on host and port, the unix socket or both, it returns when any of them stops
 func (s Server) Open() error {
+	if s.config.Config.Port <= 0 && s.config.Config.UnixSocket == "" {
+		return errors.New("nothing to listen on, set port or unixSocket")
+	}
+
+	errCh := make(chan error, 2)
+
+	if s.config.Config.UnixSocket != "" {
+		listener, err := listenUnix(s.config.Config.UnixSocket, s.config.Config.UnixSocketPermissions)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			errCh <- s.serveUnix(listener)
+		}()
+	}
+
+	if s.config.Config.Port > 0 {
+		go func() {
+			errCh <- s.openTCP()
+		}()
+	}
+
+	return <-errCh
+}
+
+func (s Server) openTCP() error {
 	addr := fmt.Sprintf("%v:%v", s.config.Config.Host, s.config.Config.Port)
 
+	var tlsConfig *tls.Config
+	if s.config.Config.TLS.Enabled {
+		var challengeHandler http.Handler
+		var err error
+
+		tlsConfig, challengeHandler, err = newTLSConfig(s.log, s.config.Config.TLS, s.config.Config.ConfigPath)
+		if err != nil {
+			return err
+		}
+
+		if challengeHandler != nil && s.config.Config.TLS.ACMEHTTPPort > 0 {
+			go s.serveACMEChallenges(challengeHandler)
+		}
+	}
+
 	var err error
 	for _, proto := range []string{"tcp", "tcp4", "tcp6"} {
-		if err = s.tryToServe(addr, proto); err == nil {
+		if err = s.tryToServe(addr, proto, tlsConfig); err == nil {
 			break
 		}
 
@@ -90,29 +143,69 @@ func (s Server) Open() error {
 	return err
 }
 
-func (s Server) tryToServe(addr, protocol string) error {
+// serveUnix serves plain http on the socket, tls is left to the local proxy
+func (s Server) serveUnix(listener net.Listener) error {
+	s.log.Info().Msgf("Starting API unix socket server. Listening on %s", listener.Addr().String())
+
+	server := http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: time.Second * 15,
+		ConnContext:       unixConnContext,
+	}
+
+	return server.Serve(listener)
+}
+
+func (s Server) tryToServe(addr, protocol string, tlsConfig *tls.Config) error {
 	listener, err := net.Listen(protocol, addr)
 	if err != nil {
 		return err
 	}
 
-	s.log.Info().Msgf("Starting API %s server. Listening on %s", protocol, listener.Addr().String())
-
 	server := http.Server{
 		Handler:           s.Handler(),
 		ReadHeaderTimeout: time.Second * 15,
+		TLSConfig:         tlsConfig,
 	}
 
+	if tlsConfig != nil {
+		s.log.Info().Msgf("Starting API %s server with TLS. Listening on %s", protocol, listener.Addr().String())
+
+		return server.ServeTLS(listener, "", "")
+	}
+
+	s.log.Info().Msgf("Starting API %s server. Listening on %s", protocol, listener.Addr().String())
+
 	return server.Serve(listener)
 }
 
+// serveACMEChallenges answers the http-01 challenges of the CA and redirects other requests to https
+func (s Server) serveACMEChallenges(handler http.Handler) {
+	addr := fmt.Sprintf("%v:%v", s.config.Config.Host, s.config.Config.TLS.ACMEHTTPPort)
+
+	s.log.Info().Msgf("Starting ACME challenge server. Listening on %s", addr)
+
+	server := http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Second * 15,
+	}
+
+	if err := server.ListenAndServe(); err != nil {
+		s.log.Error().Err(err).Msgf("could not start ACME challenge server on %s", addr)
+	}
+}
+
 func (s Server) Handler() http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
+	r.Use(RequestIDHeader)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Recoverer)
+	encoder := encoder{log: s.log}
+
 	r.Use(LoggerMiddleware(&s.log))
+	r.Use(Recoverer(encoder))
 
 	c := cors.New(cors.Options{
 		AllowCredentials:   true,
@@ -125,28 +218,34 @@ func (s Server) Handler() http.Handler {
 
 	r.Use(c.Handler)
 
-	encoder := encoder{}
-
 	r.Route("/api", func(r chi.Router) {
+		r.Use(RateLimit(encoder, s.config.Config.RateLimit))
+
 		r.Route("/auth", newAuthHandler(encoder, s.log, s, s.config.Config, s.cookieStore, s.authService).Routes)
-		r.Route("/healthz", newHealthHandler(encoder, s.db).Routes)
+		r.Route("/healthz", newHealthHandler(encoder, s.db, s.healthService, s.IsAuthenticated).Routes)
+		r.Route("/mock", newMockIndexerHandler(encoder, s.mockIndexerService).Routes)
+		r.Get("/openapi.json", newOpenAPIHandler(encoder, r, s.version, s.config.Config.BaseURL).getSpec)
 
 		r.Group(func(r chi.Router) {
 			r.Use(s.IsAuthenticated)
+			r.Use(AuditLog(s.log, s.auditService))
 
 			r.Route("/actions", newActionHandler(encoder, s.actionService).Routes)
+			r.Route("/audit", newAuditHandler(encoder, s.auditService).Routes)
 			r.Route("/config", newConfigHandler(encoder, s, s.config).Routes)
 			r.Route("/download_clients", newDownloadClientHandler(encoder, s.downloadClientService).Routes)
 			r.Route("/filters", newFilterHandler(encoder, s.filterService).Routes)
 			r.Route("/feeds", newFeedHandler(encoder, s.feedService).Routes)
 			r.Route("/irc", newIrcHandler(encoder, s.sse, s.ircService).Routes)
-			r.Route("/indexer", newIndexerHandler(encoder, s.indexerService, s.ircService).Routes)
+			r.Route("/indexer", newIndexerHandler(encoder, s.indexerService, s.ircService, s.mockIndexerService).Routes)
 			r.Route("/keys", newAPIKeyHandler(encoder, s.apiService).Routes)
+			r.Route("/lists", newListHandler(encoder, s.listService).Routes)
 			r.Route("/logs", newLogsHandler(s.config).Routes)
 			r.Route("/notification", newNotificationHandler(encoder, s.notificationService).Routes)
 			r.Route("/proxy", newProxyHandler(encoder, s.proxyService).Routes)
 			r.Route("/release", newReleaseHandler(encoder, s.releaseService).Routes)
 			r.Route("/updates", newUpdateHandler(encoder, s.updateService).Routes)
+			r.Route("/users", newUserHandler(encoder, s.authService).Routes)
 
 			r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
 
@@ -0,0 +1,172 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// newTLSConfig returns the tls config serving the configured certificate or the one issued with ACME.
+// The handler answers the http-01 challenges and is nil when certificates are not issued with ACME.
+func newTLSConfig(log zerolog.Logger, cfg domain.TLSConfig, configPath string) (*tls.Config, http.Handler, error) {
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, nil, errors.New("tls: both certFile and keyFile are required")
+		}
+
+		reloader, err := newCertReloader(log, cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		}, nil, nil
+	}
+
+	if len(cfg.ACMEDomains) == 0 {
+		return nil, nil, errors.New("tls: set certFile and keyFile or acmeDomains")
+	}
+
+	cacheDir := cfg.ACMECacheDir
+	if cacheDir == "" {
+		cacheDir = filepath.Join(configPath, "certs")
+	}
+
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: autocert.HostWhitelist(cfg.ACMEDomains...),
+		Email:      cfg.ACMEEmail,
+	}
+
+	if cfg.ACMEDirectory != "" {
+		m.Client = &acme.Client{DirectoryURL: cfg.ACMEDirectory}
+	}
+
+	tlsConfig := m.TLSConfig()
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	// without a fallback the challenge handler redirects everything else to https
+	return tlsConfig, m.HTTPHandler(nil), nil
+}
+
+// certReloader serves the certificate of the cert and key files and loads them again when they change
+type certReloader struct {
+	log      zerolog.Logger
+	certFile string
+	keyFile  string
+
+	m    sync.RWMutex
+	cert *tls.Certificate
+}
+
+func newCertReloader(log zerolog.Logger, certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{
+		log:      log,
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	if err := r.watch(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "tls: could not load certificate %s and key %s", r.certFile, r.keyFile)
+	}
+
+	r.m.Lock()
+	r.cert = &cert
+	r.m.Unlock()
+
+	return nil
+}
+
+// watch the directories rather than the files, renewals often replace the files or swap symlinks
+func (r *certReloader) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "tls: could not watch certificate")
+	}
+
+	dirs := map[string]struct{}{
+		filepath.Dir(r.certFile): {},
+		filepath.Dir(r.keyFile):  {},
+	}
+
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return errors.Wrap(err, "tls: could not watch certificate directory %s", dir)
+		}
+	}
+
+	certFile, keyFile := filepath.Clean(r.certFile), filepath.Clean(r.keyFile)
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				// kubernetes swaps the ..data symlink of mounted secrets
+				name := filepath.Clean(event.Name)
+				if name != certFile && name != keyFile && filepath.Base(name) != "..data" {
+					continue
+				}
+
+				if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+					continue
+				}
+
+				// the pair doesn't match while only one of them is replaced, keep the current one until both are
+				if err := r.reload(); err != nil {
+					r.log.Debug().Err(err).Msg("could not reload certificate yet")
+					continue
+				}
+
+				r.log.Info().Msgf("reloaded certificate %s", r.certFile)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				r.log.Error().Err(err).Msg("error watching certificate")
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *certReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return r.cert, nil
+}
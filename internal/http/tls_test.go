@@ -0,0 +1,109 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate with the serial number and its key
+func writeTestCertificate(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "autobrr"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     domain.TLSConfig
+		acme    bool
+		wantErr string
+	}{
+		{name: "cert_without_key", cfg: domain.TLSConfig{CertFile: "cert.pem"}, wantErr: "tls: both certFile and keyFile are required"},
+		{name: "nothing", cfg: domain.TLSConfig{}, wantErr: "tls: set certFile and keyFile or acmeDomains"},
+		{name: "acme", cfg: domain.TLSConfig{ACMEDomains: []string{"autobrr.example.com"}}, acme: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, challengeHandler, err := newTLSConfig(zerolog.Nop(), tt.cfg, t.TempDir())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, tlsConfig.GetCertificate)
+			assert.Equal(t, tt.acme, challengeHandler != nil)
+		})
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	writeTestCertificate(t, certFile, keyFile, 1)
+
+	reloader, err := newCertReloader(zerolog.Nop(), certFile, keyFile)
+	require.NoError(t, err)
+
+	serial := func() int64 {
+		cert, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+
+		return leaf.SerialNumber.Int64()
+	}
+
+	assert.Equal(t, int64(1), serial())
+
+	writeTestCertificate(t, certFile, keyFile, 2)
+
+	assert.Eventually(t, func() bool { return serial() == 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCertReloader_missingFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := newCertReloader(zerolog.Nop(), filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"))
+	assert.ErrorContains(t, err, "tls: could not load certificate")
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// listenUnix listens on the socket at path with the octal permissions. A socket left behind by an
+// earlier run is removed, one still in use or any other file at path is not.
+func listenUnix(path string, permissions string) (net.Listener, error) {
+	mode := os.FileMode(0o660)
+	if permissions != "" {
+		m, err := strconv.ParseUint(permissions, 8, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid unix socket permissions %q", permissions)
+		}
+		mode = os.FileMode(m)
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, errors.New("could not listen on unix socket %s: file exists and is not a socket", path)
+		}
+
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, errors.New("could not listen on unix socket %s: already in use", path)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, errors.Wrap(err, "could not remove stale unix socket %s", path)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not listen on unix socket %s", path)
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, errors.Wrap(err, "could not set permissions of unix socket %s", path)
+	}
+
+	return listener, nil
+}
+
+// unixConnKey holds the id of the socket connection a request came in on
+type unixConnKey struct{}
+
+var unixConnID atomic.Uint64
+
+// unixConnContext numbers the socket connections, clients of a socket have no address to tell them apart
+func unixConnContext(ctx context.Context, _ net.Conn) context.Context {
+	return context.WithValue(ctx, unixConnKey{}, unixConnID.Add(1))
+}
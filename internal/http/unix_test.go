@@ -0,0 +1,79 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autobrr.sock")
+
+	listener, err := listenUnix(path, "0600")
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK " + clientIP(r)))
+	}), ConnContext: unixConnContext}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+
+	resp, err := client.Get("http://autobrr/api/healthz/liveness")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	// clients of the socket have no ip, they are told apart by connection
+	assert.Regexp(t, `^OK unix:\d+$`, string(body))
+
+	_, err = listenUnix(path, "")
+	assert.ErrorContains(t, err, "already in use")
+}
+
+func TestListenUnix_stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autobrr.sock")
+
+	// a socket nobody listens on anymore, like after a crash
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := listenUnix(path, "")
+	require.NoError(t, err)
+	listener.Close()
+}
+
+func TestListenUnix_errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := listenUnix(filepath.Join(dir, "autobrr.sock"), "rw-rw----")
+	assert.ErrorContains(t, err, "invalid unix socket permissions")
+
+	file := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	_, err = listenUnix(file, "")
+	assert.ErrorContains(t, err, "not a socket")
+}
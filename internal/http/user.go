@@ -0,0 +1,120 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type userService interface {
+	ListUsers(ctx context.Context) ([]domain.User, error)
+	AddUser(ctx context.Context, req domain.CreateUserRequest) error
+	UpdateUserRole(ctx context.Context, id int, role domain.UserRole) error
+	DeleteUser(ctx context.Context, id int) error
+}
+
+type userHandler struct {
+	encoder encoder
+	service userService
+}
+
+func newUserHandler(encoder encoder, service userService) *userHandler {
+	return &userHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h userHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+
+	r.Route("/{userID}", func(r chi.Router) {
+		r.Patch("/role", h.updateRole)
+		r.Delete("/", h.delete)
+	})
+}
+
+func (h userHandler) list(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.ListUsers(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	for i := range users {
+		users[i].Password = ""
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, users)
+}
+
+func (h userHandler) store(w http.ResponseWriter, r *http.Request) {
+	var data domain.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	if err := h.service.AddUser(r.Context(), data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusCreated(w)
+}
+
+func (h userHandler) updateRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "invalid user id"))
+		return
+	}
+
+	var data domain.UpdateUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	if err := h.service.UpdateUserRole(r.Context(), userID, data.Role); err != nil {
+		h.userError(w, userID, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h userHandler) delete(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "invalid user id"))
+		return
+	}
+
+	if err := h.service.DeleteUser(r.Context(), userID); err != nil {
+		h.userError(w, userID, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+// userError responds not found for unknown users, the other errors are rejected changes like removing the last admin
+func (h userHandler) userError(w http.ResponseWriter, userID int, err error) {
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		h.encoder.NotFoundErr(w, errors.New("user %d not found", userID))
+		return
+	}
+
+	h.encoder.StatusError(w, http.StatusBadRequest, err)
+}
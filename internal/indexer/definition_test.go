@@ -1,7 +1,9 @@
 package indexer
 
 import (
+	"regexp"
 	"testing"
+	"text/template"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -33,3 +35,30 @@ func TestYamlExpectations(t *testing.T) {
 		}
 	}
 }
+
+func TestYamlInviteSequences(t *testing.T) {
+	s := &service{definitions: map[string]domain.IndexerDefinition{}}
+	err := s.LoadIndexerDefinitions()
+
+	assert.Nil(t, err)
+
+	for _, d := range s.definitions {
+		if d.IRC == nil {
+			continue
+		}
+
+		for i, step := range d.IRC.Invite {
+			assert.True(t, step.Send != "" || step.Expect != "", "%s invite step %d needs send or expect", d.Identifier, i+1)
+
+			if step.Expect != "" {
+				_, err := regexp.Compile(step.Expect)
+				assert.NoError(t, err, "%s invite step %d expect", d.Identifier, i+1)
+			}
+
+			if step.Send != "" {
+				_, err := template.New("invite").Parse(step.Send)
+				assert.NoError(t, err, "%s invite step %d send", d.Identifier, i+1)
+			}
+		}
+	}
+}
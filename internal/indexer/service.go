@@ -8,10 +8,13 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -39,8 +42,10 @@ type Service interface {
 	GetTorznabIndexers() []domain.IndexerDefinition
 	GetMappedDefinitionByName(name string) (*domain.IndexerDefinition, error)
 	Start() error
+	ReloadIndexers() error
 	TestApi(ctx context.Context, req domain.IndexerTestApiRequest) error
 	ToggleEnabled(ctx context.Context, indexerID int, enabled bool) error
+	GetPipeline(ctx context.Context, id int) (*domain.IndexerPipeline, error)
 }
 
 type service struct {
@@ -53,6 +58,9 @@ type service struct {
 
 	// contains all raw indexer definitions
 	definitions map[string]domain.IndexerDefinition
+
+	// m guards the maps below, they are read by announce and feed goroutines while being reloaded
+	m sync.RWMutex
 	// definition with indexer data
 	mappedDefinitions map[string]*domain.IndexerDefinition
 	// map server:channel:announce to indexer.Identifier
@@ -90,6 +98,10 @@ func (s *service) Store(ctx context.Context, indexer domain.Indexer) (*domain.In
 		indexer.Settings[key] = sanitize.String(val)
 	}
 
+	if err := indexer.Identity.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid request identity")
+	}
+
 	// if indexer is rss or torznab do additional cleanup for identifier
 	if isImplFeed(indexer.Implementation) {
 		// make lowercase
@@ -126,6 +138,10 @@ func (s *service) Update(ctx context.Context, indexer domain.Indexer) (*domain.I
 		indexer.Settings[key] = sanitize.String(val)
 	}
 
+	if err := indexer.Identity.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid request identity")
+	}
+
 	currentIndexer, err := s.repo.FindByID(ctx, int(indexer.ID))
 	if err != nil {
 		return nil, errors.Wrap(err, "could not find indexer by id: %v", indexer.ID)
@@ -212,6 +228,16 @@ func (s *service) FindByID(ctx context.Context, id int) (*domain.Indexer, error)
 	return indexers, err
 }
 
+// GetPipeline returns the effective processing pipeline for the indexer
+func (s *service) GetPipeline(ctx context.Context, id int) (*domain.IndexerPipeline, error) {
+	indexer, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.config.EffectiveIndexerPipeline(indexer.Identifier), nil
+}
+
 func (s *service) List(ctx context.Context) ([]domain.Indexer, error) {
 	indexers, err := s.repo.List(ctx)
 	if err != nil {
@@ -233,6 +259,9 @@ func (s *service) GetBy(ctx context.Context, req domain.GetIndexerRequest) (*dom
 }
 
 func (s *service) GetAll() ([]*domain.IndexerDefinition, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	var res = make([]*domain.IndexerDefinition, 0)
 
 	for _, indexer := range s.mappedDefinitions {
@@ -258,6 +287,8 @@ func (s *service) mapIndexers() (map[string]*domain.IndexerDefinition, error) {
 		return nil, err
 	}
 
+	mappedDefinitions := make(map[string]*domain.IndexerDefinition)
+
 	for _, indexer := range indexers {
 		indexerDefinition, err := s.mapIndexer(indexer)
 		if err != nil {
@@ -268,10 +299,10 @@ func (s *service) mapIndexers() (map[string]*domain.IndexerDefinition, error) {
 			continue
 		}
 
-		s.mappedDefinitions[indexer.Identifier] = indexerDefinition
+		mappedDefinitions[indexer.Identifier] = indexerDefinition
 	}
 
-	return s.mappedDefinitions, nil
+	return mappedDefinitions, nil
 }
 
 func (s *service) mapIndexer(indexer domain.Indexer) (*domain.IndexerDefinition, error) {
@@ -287,6 +318,10 @@ func (s *service) mapIndexer(indexer domain.Indexer) (*domain.IndexerDefinition,
 		return nil, nil
 	}
 
+	// settings are filled in below, don't share them with the template
+	d.Settings = slices.Clone(d.Settings)
+	d.SettingsMap = maps.Clone(d.SettingsMap)
+
 	d.ID = int(indexer.ID)
 	d.Name = indexer.Name
 	d.Identifier = indexer.Identifier
@@ -297,6 +332,7 @@ func (s *service) mapIndexer(indexer domain.Indexer) (*domain.IndexerDefinition,
 
 	d.UseProxy = indexer.UseProxy
 	d.ProxyID = indexer.ProxyID
+	d.Identity = indexer.Identity
 
 	if d.SettingsMap == nil {
 		d.SettingsMap = make(map[string]string)
@@ -322,11 +358,19 @@ func (s *service) mapIndexer(indexer domain.Indexer) (*domain.IndexerDefinition,
 }
 
 func (s *service) updateMapIndexer(indexer domain.Indexer) (*domain.IndexerDefinition, error) {
-	d, ok := s.mappedDefinitions[indexer.Identifier]
+	s.m.RLock()
+	current, ok := s.mappedDefinitions[indexer.Identifier]
+	s.m.RUnlock()
+
 	if !ok {
 		return nil, nil
 	}
 
+	// update a copy, the current definition might be in use by announces
+	d := *current
+	d.Settings = slices.Clone(current.Settings)
+	d.SettingsMap = maps.Clone(current.SettingsMap)
+
 	d.ID = int(indexer.ID)
 	d.Name = indexer.Name
 	d.Identifier = indexer.Identifier
@@ -337,6 +381,7 @@ func (s *service) updateMapIndexer(indexer domain.Indexer) (*domain.IndexerDefin
 
 	d.UseProxy = indexer.UseProxy
 	d.ProxyID = indexer.ProxyID
+	d.Identity = indexer.Identity
 
 	if d.SettingsMap == nil {
 		d.SettingsMap = make(map[string]string)
@@ -358,7 +403,7 @@ func (s *service) updateMapIndexer(indexer domain.Indexer) (*domain.IndexerDefin
 		d.Settings[i] = setting
 	}
 
-	return d, nil
+	return &d, nil
 }
 
 func (s *service) GetTemplates() ([]domain.IndexerDefinition, error) {
@@ -386,17 +431,33 @@ func (s *service) Start() error {
 		}
 	}
 
+	return s.loadIndexers()
+}
+
+// ReloadIndexers rebuilds the in-memory indexers from the database, eg. after they were changed by another instance.
+// The lookups keep serving the previous indexers until the new ones are swapped in.
+func (s *service) ReloadIndexers() error {
+	return s.loadIndexers()
+}
+
+// loadIndexers builds the indexer lookups from the database and replaces the current ones
+func (s *service) loadIndexers() error {
 	// load the indexers' setup by the user
 	indexerDefinitions, err := s.mapIndexers()
 	if err != nil {
 		return err
 	}
 
+	lookupIRCServerDefinition := make(map[string]map[string]*domain.IndexerDefinition)
+	rssIndexers := make(map[string]*domain.IndexerDefinition)
+	torznabIndexers := make(map[string]*domain.IndexerDefinition)
+	newznabIndexers := make(map[string]*domain.IndexerDefinition)
+
 	for _, indexer := range indexerDefinitions {
 		switch indexer.Implementation {
 		case string(domain.IndexerImplementationIRC):
 			// add to irc server lookup table
-			s.mapIRCServerDefinitionLookup(indexer.IRC.Server, indexer)
+			mapIRCServerDefinitionLookup(lookupIRCServerDefinition, indexer.IRC.Server, indexer)
 
 			// check if it has api and add to api service
 			if indexer.Enabled && indexer.HasApi() {
@@ -407,22 +468,33 @@ func (s *service) Start() error {
 
 		// handle feeds
 		case string(domain.IndexerImplementationRSS):
-			s.rssIndexers[indexer.Identifier] = indexer
+			rssIndexers[indexer.Identifier] = indexer
 
 		case string(domain.IndexerImplementationTorznab):
-			s.torznabIndexers[indexer.Identifier] = indexer
+			torznabIndexers[indexer.Identifier] = indexer
 
 		case string(domain.IndexerImplementationNewznab):
-			s.newznabIndexers[indexer.Identifier] = indexer
+			newznabIndexers[indexer.Identifier] = indexer
 		}
 	}
 
+	s.m.Lock()
+	s.mappedDefinitions = indexerDefinitions
+	s.lookupIRCServerDefinition = lookupIRCServerDefinition
+	s.rssIndexers = rssIndexers
+	s.torznabIndexers = torznabIndexers
+	s.newznabIndexers = newznabIndexers
+	s.m.Unlock()
+
 	s.log.Info().Msgf("Loaded %d indexers", len(indexerDefinitions))
 
 	return nil
 }
 
 func (s *service) removeIndexer(indexer domain.Indexer) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	// handle feeds
 	switch indexer.Implementation {
 	case string(domain.IndexerImplementationRSS):
@@ -449,10 +521,13 @@ func (s *service) addIndexer(indexer domain.Indexer) error {
 		return errors.New("addindexer: could not find definition")
 	}
 
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	switch indexer.Implementation {
 	case string(domain.IndexerImplementationIRC):
 		// add to irc server lookup table
-		s.mapIRCServerDefinitionLookup(indexerDefinition.IRC.Server, indexerDefinition)
+		mapIRCServerDefinitionLookup(s.lookupIRCServerDefinition, indexerDefinition.IRC.Server, indexerDefinition)
 
 		// check if it has api and add to api service
 		if indexerDefinition.HasApi() {
@@ -487,10 +562,13 @@ func (s *service) updateIndexer(indexer domain.Indexer) error {
 		return errors.New("update indexer: could not find definition")
 	}
 
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	switch indexer.Implementation {
 	case string(domain.IndexerImplementationIRC):
 		// add to irc server lookup table
-		s.mapIRCServerDefinitionLookup(indexerDefinition.IRC.Server, indexerDefinition)
+		mapIRCServerDefinitionLookup(s.lookupIRCServerDefinition, indexerDefinition.IRC.Server, indexerDefinition)
 
 		// check if it has api and add to api service
 		if indexerDefinition.HasApi() {
@@ -518,15 +596,15 @@ func (s *service) updateIndexer(indexer domain.Indexer) error {
 // mapIRCServerDefinitionLookup map irc stuff to indexer.name
 // map[irc.network.test][indexer1] = indexer1
 // map[irc.network.test][indexer2] = indexer2
-func (s *service) mapIRCServerDefinitionLookup(ircServer string, indexerDefinition *domain.IndexerDefinition) {
+func mapIRCServerDefinitionLookup(lookup map[string]map[string]*domain.IndexerDefinition, ircServer string, indexerDefinition *domain.IndexerDefinition) {
 	if indexerDefinition.IRC != nil {
 		// check if already exists, if ok add it to existing, otherwise create new
-		_, exists := s.lookupIRCServerDefinition[ircServer]
+		_, exists := lookup[ircServer]
 		if !exists {
-			s.lookupIRCServerDefinition[ircServer] = map[string]*domain.IndexerDefinition{}
+			lookup[ircServer] = map[string]*domain.IndexerDefinition{}
 		}
 
-		s.lookupIRCServerDefinition[ircServer][indexerDefinition.Identifier] = indexerDefinition
+		lookup[ircServer][indexerDefinition.Identifier] = indexerDefinition
 	}
 }
 
@@ -673,6 +751,9 @@ func (s *service) GetIndexersByIRCNetwork(server string) []*domain.IndexerDefini
 
 	var indexerDefinitions []*domain.IndexerDefinition
 
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	// get indexer definitions matching irc network from lookup table
 	if srv, idOk := s.lookupIRCServerDefinition[server]; idOk {
 		for _, definition := range srv {
@@ -686,6 +767,9 @@ func (s *service) GetIndexersByIRCNetwork(server string) []*domain.IndexerDefini
 func (s *service) GetTorznabIndexers() []domain.IndexerDefinition {
 	indexerDefinitions := make([]domain.IndexerDefinition, 0)
 
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	for _, definition := range s.torznabIndexers {
 		if definition != nil {
 			indexerDefinitions = append(indexerDefinitions, *definition)
@@ -698,6 +782,9 @@ func (s *service) GetTorznabIndexers() []domain.IndexerDefinition {
 func (s *service) GetRSSIndexers() []domain.IndexerDefinition {
 	indexerDefinitions := make([]domain.IndexerDefinition, 0)
 
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	for _, definition := range s.rssIndexers {
 		if definition != nil {
 			indexerDefinitions = append(indexerDefinitions, *definition)
@@ -716,6 +803,9 @@ func (s *service) getDefinitionByName(name string) *domain.IndexerDefinition {
 }
 
 func (s *service) GetMappedDefinitionByName(name string) (*domain.IndexerDefinition, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	v, ok := s.mappedDefinitions[name]
 	if !ok {
 		return nil, errors.New("unknown indexer identifier: %s", name)
@@ -725,6 +815,9 @@ func (s *service) GetMappedDefinitionByName(name string) (*domain.IndexerDefinit
 }
 
 func (s *service) getMappedDefinitionByName(name string) *domain.IndexerDefinition {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	if v, ok := s.mappedDefinitions[name]; ok {
 		return v
 	}
@@ -733,10 +826,13 @@ func (s *service) getMappedDefinitionByName(name string) *domain.IndexerDefiniti
 }
 
 func (s *service) stopFeed(indexer string) {
-	// verify indexer is torznab indexer
+	s.m.RLock()
 	_, ok := s.torznabIndexers[indexer]
+	_, rssOK := s.rssIndexers[indexer]
+	s.m.RUnlock()
+
+	// verify indexer is torznab indexer
 	if !ok {
-		_, rssOK := s.rssIndexers[indexer]
 		if !rssOK {
 			return
 		}
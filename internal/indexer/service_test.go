@@ -0,0 +1,91 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package indexer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockIndexerRepo only implements List, calling anything else panics
+type mockIndexerRepo struct {
+	domain.IndexerRepo
+	indexers []domain.Indexer
+}
+
+func (r *mockIndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
+	return r.indexers, nil
+}
+
+func TestService_ReloadIndexers(t *testing.T) {
+	repo := &mockIndexerRepo{
+		indexers: []domain.Indexer{
+			{ID: 1, Name: "TorrentLeech", Identifier: "torrentleech", Enabled: true, Implementation: "irc", Settings: map[string]string{"rsskey": "key"}},
+			{ID: 2, Name: "Feed", Identifier: "rss-feed", Enabled: true, Implementation: "rss"},
+		},
+	}
+
+	s := &service{
+		log:         zerolog.Nop(),
+		repo:        repo,
+		definitions: map[string]domain.IndexerDefinition{},
+	}
+
+	assert.NoError(t, s.LoadIndexerDefinitions())
+	assert.NoError(t, s.loadIndexers())
+
+	assert.Len(t, s.GetIndexersByIRCNetwork("irc.torrentleech.org"), 1)
+	assert.Len(t, s.GetRSSIndexers(), 1)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// announce and feed lookups must keep seeing the indexers while they are reloaded
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				assert.Len(t, s.GetIndexersByIRCNetwork("irc.torrentleech.org"), 1)
+				assert.Len(t, s.GetRSSIndexers(), 1)
+
+				def, err := s.GetMappedDefinitionByName("torrentleech")
+				if assert.NoError(t, err) {
+					assert.Equal(t, "key", def.SettingsMap["rsskey"])
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, s.ReloadIndexers())
+	}
+
+	close(stop)
+	wg.Wait()
+
+	// changes from another instance are picked up
+	repo.indexers = repo.indexers[:1]
+	assert.NoError(t, s.ReloadIndexers())
+	assert.Len(t, s.GetRSSIndexers(), 0)
+
+	// the templates are not changed by mapping indexers
+	template := s.getDefinitionByName("torrentleech")
+	assert.Empty(t, template.SettingsMap["rsskey"])
+	for _, setting := range template.Settings {
+		assert.Empty(t, setting.Value)
+	}
+}
@@ -4,10 +4,12 @@
 package irc
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/url"
 	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -104,6 +106,16 @@ type Handler struct {
 
 	authenticated bool
 	saslauthed    bool
+
+	inviteSequences map[string]*inviteSequence
+	inviteWaiter    *inviteWaiter
+	inviteCancel    context.CancelFunc
+
+	statusReplies map[string]time.Time
+	channelOps    *channelOps
+
+	// partRequested holds the channels we sent a PART for, parting any other channel is notified
+	partRequested map[string]struct{}
 }
 
 func NewHandler(log zerolog.Logger, sse *sse.Server, network domain.IrcNetwork, definitions []*domain.IndexerDefinition, releaseSvc release.Service, notificationSvc notification.Service) *Handler {
@@ -122,6 +134,10 @@ func NewHandler(log zerolog.Logger, sse *sse.Server, network domain.IrcNetwork,
 		authenticated:       false,
 		saslauthed:          false,
 		connectionErrors:    []string{},
+		inviteSequences:     map[string]*inviteSequence{},
+		statusReplies:       map[string]time.Time{},
+		partRequested:       map[string]struct{}{},
+		channelOps:          newChannelOps(),
 	}
 
 	// init indexer, announceProcessor
@@ -160,6 +176,10 @@ func (h *Handler) InitIndexers(definitions []*domain.IndexerDefinition) {
 		for _, announcer := range definition.IRC.Announcers {
 			h.validAnnouncers[strings.ToLower(announcer)] = struct{}{}
 		}
+
+		if len(definition.IRC.Invite) > 0 {
+			h.inviteSequences[definition.Identifier] = newInviteSequence(definition)
+		}
 	}
 }
 
@@ -290,11 +310,16 @@ func (h *Handler) Run() (err error) {
 	client.AddCallback("INVITE", h.handleInvite)
 	client.AddCallback("366", h.handleJoined)
 	client.AddCallback("PART", h.handlePart)
+	client.AddCallback("KICK", h.handleKick)
 	client.AddCallback("PRIVMSG", h.onMessage)
 	client.AddCallback("NOTICE", h.onNotice)
 	client.AddCallback("NICK", h.onNick)
 	client.AddCallback("903", h.handleSASLSuccess)
 
+	for _, event := range []string{"353", "MODE", "PART", "KICK", "QUIT", "NICK"} {
+		client.AddCallback(event, h.trackChannelOps)
+	}
+
 	//h.setConnectionStatus()
 	h.saslauthed = false
 
@@ -438,6 +463,8 @@ func (h *Handler) Stop() {
 	h.client = nil
 	h.m.Unlock()
 
+	h.stopInviteSequences()
+
 	if client != nil {
 		h.log.Debug().Msg("Disconnecting...")
 		h.resetChannelHealth()
@@ -467,6 +494,8 @@ func (h *Handler) onConnect(m ircmsg.Message) {
 
 	h.setConnectionStatus()
 
+	h.publishHealth(domain.LiveIRCHealth{Connected: true})
+
 	func() {
 		h.m.Lock()
 		if h.haveDisconnected && h.clientState == ircLive {
@@ -513,8 +542,18 @@ func (h *Handler) onDisconnect(m ircmsg.Message) {
 	// reset authenticated
 	h.authenticated = false
 
+	// the NAMES replies fill the channel operators again after joining
+	h.channelOps.clear()
+
 	h.haveDisconnected = true
 
+	// stop waiting for invite responses, the sequences run again on connect
+	if h.inviteCancel != nil {
+		h.inviteCancel()
+		h.inviteCancel = nil
+	}
+	h.inviteWaiter = nil
+
 	manuallyDisconnected := h.clientState == ircStopped
 
 	// check if we are responsible for disconnect
@@ -527,6 +566,8 @@ func (h *Handler) onDisconnect(m ircmsg.Message) {
 	}
 
 	h.m.Unlock()
+
+	h.publishHealth(domain.LiveIRCHealth{Connected: false})
 }
 
 // onNotice handles NOTICE events
@@ -535,6 +576,10 @@ func (h *Handler) onNotice(msg ircmsg.Message) {
 	case "NickServ":
 		h.handleNickServ(msg)
 	}
+
+	if len(msg.Params) > 1 {
+		h.handleInviteResponse(msg.Nick(), h.cleanMessage(msg.Params[1]))
+	}
 }
 
 // handleNickServ is called from NOTICE events
@@ -650,7 +695,8 @@ func (h *Handler) handleSASLSuccess(msg ircmsg.Message) {
 }
 
 // setAuthenticated sets the states for authenticated, connectionErrors, failedNickServAttempts
-// and then sends inviteCommand and after that JoinChannels
+// and then sends inviteCommand and after that JoinChannels.
+// With invite sequences from the indexer definitions those run instead and join when done.
 func (h *Handler) setAuthenticated() {
 	h.m.Lock()
 	alreadyAuthenticated := h.authenticated
@@ -665,6 +711,11 @@ func (h *Handler) setAuthenticated() {
 		return
 	}
 
+	if h.hasInviteSequences() {
+		h.startInviteSequences()
+		return
+	}
+
 	h.inviteCommand()
 	h.JoinChannels()
 }
@@ -707,6 +758,23 @@ func (h *Handler) onNick(msg ircmsg.Message) {
 	h.authenticate()
 }
 
+// publishHealth streams a change of the connection or a channel to the live stream
+func (h *Handler) publishHealth(health domain.LiveIRCHealth) {
+	if h.sse == nil {
+		return
+	}
+
+	health.NetworkID = h.network.ID
+	health.Network = h.network.Name
+
+	event := domain.NewLiveEvent(domain.LiveEventIRCHealth, health)
+
+	h.sse.Publish(domain.LiveStream, &sse.Event{
+		Event: []byte(event.Type),
+		Data:  event.Bytes(),
+	})
+}
+
 func (h *Handler) publishSSEMsg(msg domain.IrcMessage) {
 	key := genSSEKey(h.network.ID, msg.Channel)
 
@@ -732,6 +800,12 @@ func (h *Handler) onMessage(msg ircmsg.Message) {
 	// publish to SSE stream
 	h.publishSSEMsg(domain.IrcMessage{Channel: channel, Nick: nick, Message: cleanedMsg, Time: time.Now()})
 
+	h.handleInviteResponse(nick, cleanedMsg)
+
+	if h.handleStatusCommand(channel, nick, cleanedMsg) {
+		return
+	}
+
 	// check if message is from a valid channel, if not return
 	if validChannel := h.isValidChannel(channel); !validChannel {
 		return
@@ -817,11 +891,71 @@ func (h *Handler) handlePart(msg ircmsg.Message) {
 		v.resetMonitoring()
 	}
 
+	h.publishHealth(domain.LiveIRCHealth{Connected: true, Channel: channel, Monitoring: false})
+
+	h.m.Lock()
+	_, requested := h.partRequested[channel]
+	delete(h.partRequested, channel)
+	h.m.Unlock()
+
+	// only send notification if we did not initiate the part, e.g. a services or bouncer part
+	if !requested && h.isValidChannel(channel) {
+		h.log.Warn().Msgf("Parted channel %s unexpectedly", channel)
+
+		h.notificationService.Send(domain.NotificationEventIRCChannelParted, domain.NotificationPayload{
+			Subject: "IRC Channel Parted",
+			Message: fmt.Sprintf("Network: %s Channel: %s", h.network.Name, msg.Params[0]),
+		})
+	}
+
 	// TODO remove announceProcessor
 
 	h.log.Debug().Msgf("Left channel %s", channel)
 }
 
+// handleKick listens for KICK events
+func (h *Handler) handleKick(msg ircmsg.Message) {
+	// KICK <channel> <user> [<comment>]
+	if len(msg.Params) < 2 {
+		return
+	}
+
+	if !h.isOurCurrentNick(msg.Params[1]) {
+		h.log.Trace().Msgf("KICK other user: %+v", msg)
+		return
+	}
+
+	channel := strings.ToLower(msg.Params[0])
+
+	reason := ""
+	if len(msg.Params) >= 3 {
+		reason = msg.Params[2]
+	}
+
+	h.log.Warn().Msgf("Kicked from channel %s by %s: %s", channel, msg.Nick(), reason)
+
+	// reset monitoring status
+	if v, ok := h.channelHealth[channel]; ok {
+		v.resetMonitoring()
+	}
+
+	h.publishHealth(domain.LiveIRCHealth{Connected: true, Channel: channel, Monitoring: false})
+
+	if !h.isValidChannel(channel) {
+		return
+	}
+
+	message := fmt.Sprintf("Network: %s Channel: %s Kicked by: %s", h.network.Name, msg.Params[0], msg.Nick())
+	if reason != "" {
+		message = fmt.Sprintf("%s Reason: %s", message, reason)
+	}
+
+	h.notificationService.Send(domain.NotificationEventIRCChannelKicked, domain.NotificationPayload{
+		Subject: "IRC Channel Kicked",
+		Message: message,
+	})
+}
+
 // PartChannel parts/leaves channel
 func (h *Handler) PartChannel(channel string) error {
 	// if using bouncer we do not want to part any channels
@@ -832,6 +966,10 @@ func (h *Handler) PartChannel(channel string) error {
 
 	h.log.Debug().Msgf("Leaving channel %s", channel)
 
+	h.m.Lock()
+	h.partRequested[strings.ToLower(channel)] = struct{}{}
+	h.m.Unlock()
+
 	return h.Send("PART", channel)
 
 	// TODO remove announceProcessor
@@ -879,6 +1017,8 @@ func (h *Handler) handleJoined(msg ircmsg.Message) {
 	}
 	h.m.Unlock()
 
+	h.publishHealth(domain.LiveIRCHealth{Connected: true, Channel: channel, Monitoring: true})
+
 	// if not valid it's considered an extra channel
 	if valid := h.isValidChannel(channel); !valid {
 		h.log.Info().Msgf("Joined extra channel %s", channel)
@@ -927,6 +1067,8 @@ func (h *Handler) handleInvite(msg ircmsg.Message) {
 
 	h.log.Trace().Msgf("INVITE from %s to join: %s", msg.Nick(), channel)
 
+	h.handleInviteResponse(msg.Nick(), "INVITE "+msg.Params[1])
+
 	if validChannel := h.isValidHandlerChannel(channel); !validChannel {
 		h.log.Trace().Msgf("invite from %s to join: %s - invalid channel, skip joining", msg.Nick(), channel)
 		return
@@ -1102,5 +1244,13 @@ func (h *Handler) ReportStatus(netw *domain.IrcNetworkWithHealth) {
 
 	netw.Healthy = channelsHealthy
 
+	for _, seq := range h.inviteSequences {
+		netw.InviteStatus = append(netw.InviteStatus, seq.Status())
+	}
+
+	sort.Slice(netw.InviteStatus, func(i, j int) bool {
+		return netw.InviteStatus[i].Indexer < netw.InviteStatus[j].Indexer
+	})
+
 	netw.ConnectionErrors = slices.Clone(h.connectionErrors)
 }
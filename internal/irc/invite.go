@@ -0,0 +1,287 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/sasha-s/go-deadlock"
+)
+
+const defaultInviteTimeout = 30 * time.Second
+
+// inviteSequence holds the invite steps of an indexer and the status of the last run
+type inviteSequence struct {
+	m deadlock.RWMutex
+
+	definition *domain.IndexerDefinition
+	status     domain.IrcInviteStatus
+}
+
+func newInviteSequence(definition *domain.IndexerDefinition) *inviteSequence {
+	return &inviteSequence{
+		definition: definition,
+		status: domain.IrcInviteStatus{
+			Indexer: definition.Identifier,
+			State:   domain.IrcInviteStatePending,
+			Steps:   len(definition.IRC.Invite),
+		},
+	}
+}
+
+func (s *inviteSequence) setStatus(state domain.IrcInviteState, step, attempt int, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.status.State = state
+	s.status.Step = step
+	s.status.Attempt = attempt
+	s.status.Error = ""
+	s.status.UpdatedAt = time.Now()
+
+	if err != nil {
+		s.status.Error = err.Error()
+	}
+}
+
+func (s *inviteSequence) Status() domain.IrcInviteStatus {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	return s.status
+}
+
+// inviteWaiter waits for a response to an invite step
+type inviteWaiter struct {
+	from    string
+	pattern *regexp.Regexp
+	matched chan struct{}
+}
+
+func (w *inviteWaiter) match(nick, message string) bool {
+	if w.from != "" && !strings.EqualFold(w.from, nick) {
+		return false
+	}
+
+	return w.pattern.MatchString(message)
+}
+
+func (h *Handler) hasInviteSequences() bool {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	return len(h.inviteSequences) > 0
+}
+
+// startInviteSequences runs the invite sequences in the background as the responses arrive on the client loop
+func (h *Handler) startInviteSequences() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	h.m.Lock()
+	if h.inviteCancel != nil {
+		h.inviteCancel()
+	}
+	h.inviteCancel = cancel
+	h.m.Unlock()
+
+	go h.runInviteSequences(ctx)
+}
+
+func (h *Handler) stopInviteSequences() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.inviteCancel != nil {
+		h.inviteCancel()
+		h.inviteCancel = nil
+	}
+
+	h.inviteWaiter = nil
+}
+
+// runInviteSequences runs the invite sequence for every indexer on the network and joins the channels after.
+// Failed sequences are added to the connection errors, channels are joined anyway as some might not need the invite.
+func (h *Handler) runInviteSequences(ctx context.Context) {
+	h.m.RLock()
+	sequences := make([]*inviteSequence, 0, len(h.inviteSequences))
+	for _, seq := range h.inviteSequences {
+		sequences = append(sequences, seq)
+	}
+	h.m.RUnlock()
+
+	sort.Slice(sequences, func(i, j int) bool {
+		return sequences[i].definition.Identifier < sequences[j].definition.Identifier
+	})
+
+	for _, seq := range sequences {
+		if err := h.runInviteSequence(ctx, seq); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			h.log.Error().Err(err).Msgf("invite sequence failed for indexer: %s", seq.definition.Identifier)
+			h.addConnectError(fmt.Sprintf("invite sequence failed for %s: %v", seq.definition.Name, err))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	h.JoinChannels()
+}
+
+func (h *Handler) runInviteSequence(ctx context.Context, seq *inviteSequence) error {
+	vars := h.inviteVars(seq.definition)
+
+	for i, step := range seq.definition.IRC.Invite {
+		if err := h.runInviteStep(ctx, seq, i+1, step, vars); err != nil {
+			seq.setStatus(domain.IrcInviteStateFailed, i+1, seq.Status().Attempt, err)
+			return errors.Wrap(err, "step %d", i+1)
+		}
+	}
+
+	seq.setStatus(domain.IrcInviteStateDone, len(seq.definition.IRC.Invite), 0, nil)
+
+	h.log.Debug().Msgf("invite sequence done for indexer: %s", seq.definition.Identifier)
+
+	return nil
+}
+
+func (h *Handler) runInviteStep(ctx context.Context, seq *inviteSequence, stepNum int, step domain.IndexerIRCInviteStep, vars map[string]string) error {
+	command, err := renderInviteCommand(step.Send, vars)
+	if err != nil {
+		return err
+	}
+
+	if step.Expect == "" {
+		seq.setStatus(domain.IrcInviteStateRunning, stepNum, 1, nil)
+
+		if command == "" {
+			return errors.New("step needs send or expect")
+		}
+
+		return h.sendConnectCommands(command)
+	}
+
+	pattern, err := regexp.Compile(step.Expect)
+	if err != nil {
+		return errors.Wrap(err, "could not compile expect: %s", step.Expect)
+	}
+
+	timeout := defaultInviteTimeout
+	if step.Timeout > 0 {
+		timeout = time.Duration(step.Timeout) * time.Second
+	}
+
+	defer h.setInviteWaiter(nil)
+
+	attempts := step.Retries + 1
+	for attempt := 1; attempt <= attempts; attempt++ {
+		seq.setStatus(domain.IrcInviteStateRunning, stepNum, attempt, nil)
+
+		waiter := &inviteWaiter{
+			from:    step.From,
+			pattern: pattern,
+			matched: make(chan struct{}, 1),
+		}
+
+		// set waiter before sending so a fast response isn't missed
+		h.setInviteWaiter(waiter)
+
+		if command != "" {
+			if err := h.sendConnectCommands(command); err != nil {
+				return err
+			}
+		}
+
+		timer := time.NewTimer(timeout)
+
+		select {
+		case <-waiter.matched:
+			timer.Stop()
+			return nil
+
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		case <-timer.C:
+			h.log.Warn().Msgf("invite step %d for %s: no response matching %q after %s (attempt %d/%d)", stepNum, seq.definition.Identifier, step.Expect, timeout, attempt, attempts)
+		}
+	}
+
+	if step.From != "" {
+		return errors.New("no response from %s matching %q after %d attempts", step.From, step.Expect, attempts)
+	}
+
+	return errors.New("no response matching %q after %d attempts", step.Expect, attempts)
+}
+
+func (h *Handler) setInviteWaiter(waiter *inviteWaiter) {
+	h.m.Lock()
+	h.inviteWaiter = waiter
+	h.m.Unlock()
+}
+
+// handleInviteResponse checks messages and invites against the waiting invite step
+func (h *Handler) handleInviteResponse(nick, message string) {
+	h.m.RLock()
+	waiter := h.inviteWaiter
+	h.m.RUnlock()
+
+	if waiter == nil || !waiter.match(nick, message) {
+		return
+	}
+
+	h.log.Trace().Msgf("invite response from %s matched: %s", nick, message)
+
+	select {
+	case waiter.matched <- struct{}{}:
+	default:
+	}
+}
+
+// inviteVars returns the indexer settings with nick and invite_command from the network
+func (h *Handler) inviteVars(definition *domain.IndexerDefinition) map[string]string {
+	vars := make(map[string]string, len(definition.SettingsMap)+2)
+	for k, v := range definition.SettingsMap {
+		vars[k] = v
+	}
+
+	h.m.RLock()
+	vars["nick"] = h.network.Nick
+	vars["invite_command"] = h.network.InviteCommand
+	h.m.RUnlock()
+
+	return vars
+}
+
+func renderInviteCommand(command string, vars map[string]string) (string, error) {
+	if !strings.Contains(command, "{{") {
+		return command, nil
+	}
+
+	tmpl, err := template.New("invite").Option("missingkey=error").Parse(command)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse send: %s", command)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", errors.Wrap(err, "could not render send")
+	}
+
+	return b.String(), nil
+}
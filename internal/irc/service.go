@@ -268,6 +268,12 @@ func (s *service) checkIfNetworkRestartNeeded(network *domain.IrcNetwork) error
 				return nil
 			}
 
+			if handler.StatusCommand != network.StatusCommand || handler.StatusNicks != network.StatusNicks {
+				s.log.Debug().Msg("updating status command")
+
+				existingHandler.UpdateStatusCommand(network.StatusCommand, network.StatusNicks)
+			}
+
 			if handler.Nick != network.Nick {
 				s.log.Debug().Msg("changing nick")
 
@@ -497,11 +503,14 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 			BouncerAddr:      n.BouncerAddr,
 			UseBouncer:       n.UseBouncer,
 			BotMode:          n.BotMode,
+			StatusCommand:    n.StatusCommand,
+			StatusNicks:      n.StatusNicks,
 			UseProxy:         n.UseProxy,
 			ProxyId:          n.ProxyId,
 			Connected:        false,
 			Channels:         []domain.ChannelWithHealth{},
 			ConnectionErrors: []string{},
+			InviteStatus:     []domain.IrcInviteStatus{},
 		}
 
 		s.lock.RLock()
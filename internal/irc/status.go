@@ -0,0 +1,288 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// statusReplyCooldown limits the status replies per channel so the command can't be used to flood the channel
+const statusReplyCooldown = 30 * time.Second
+
+// handleStatusCommand replies with the health of the connection when the message is the status command of the network
+func (h *Handler) handleStatusCommand(channel, nick, message string) bool {
+	h.m.RLock()
+	command := strings.TrimSpace(h.network.StatusCommand)
+	allowedNicks := h.network.StatusNicks
+	h.m.RUnlock()
+
+	if command == "" || !strings.EqualFold(strings.TrimSpace(message), command) {
+		return false
+	}
+
+	// only reply in channels, never to private messages
+	if !h.isValidHandlerChannel(channel) {
+		return false
+	}
+
+	if !statusNickAllowed(allowedNicks, nick, h.channelOps.isOp(channel, nick)) {
+		h.log.Debug().Str("channel", channel).Str("nick", nick).Msg("ignoring status command from nick not allowed")
+		return true
+	}
+
+	key := strings.ToLower(channel)
+	now := time.Now()
+
+	h.m.Lock()
+	if last, ok := h.statusReplies[key]; ok && now.Sub(last) < statusReplyCooldown {
+		h.m.Unlock()
+		return true
+	}
+	h.statusReplies[key] = now
+	h.m.Unlock()
+
+	if err := h.SendMsg(channel, h.statusSummary(now)); err != nil {
+		h.log.Error().Err(err).Msgf("could not reply to status command in %s", channel)
+	}
+
+	return true
+}
+
+// statusSummary builds a single line with the connection and channel health
+func (h *Handler) statusSummary(now time.Time) string {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	var b strings.Builder
+
+	b.WriteString("autobrr: ")
+
+	if h.connectedSince.IsZero() {
+		b.WriteString("not connected")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "connected for %s as %s", now.Sub(h.connectedSince).Round(time.Second), h.client.CurrentNick())
+
+	for _, channel := range h.network.Channels {
+		name := strings.ToLower(channel.Name)
+
+		chanHealth, ok := h.channelHealth[name]
+		if !ok {
+			fmt.Fprintf(&b, " | %s: not joined", channel.Name)
+			continue
+		}
+
+		chanHealth.m.RLock()
+		monitoring := chanHealth.monitoring
+		lastAnnounce := chanHealth.lastAnnounce
+		chanHealth.m.RUnlock()
+
+		state := "monitoring"
+		if !monitoring {
+			state = "not monitoring"
+		}
+
+		last := "never"
+		if !lastAnnounce.IsZero() {
+			last = now.Sub(lastAnnounce).Round(time.Second).String() + " ago"
+		}
+
+		fmt.Fprintf(&b, " | %s: %s, last announce %s", channel.Name, state, last)
+	}
+
+	return b.String()
+}
+
+// statusNickAllowed reports whether nick is in the comma separated list of nicks.
+// Without a list only the channel operators are allowed.
+func statusNickAllowed(allowedNicks, nick string, isOp bool) bool {
+	if strings.TrimSpace(allowedNicks) == "" {
+		return isOp
+	}
+
+	for _, allowed := range strings.Split(allowedNicks, ",") {
+		if strings.EqualFold(strings.TrimSpace(allowed), nick) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateStatusCommand sets the status command and nicks of the running handler and resets the reply cooldowns
+func (h *Handler) UpdateStatusCommand(command, nicks string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.network.StatusCommand = command
+	h.network.StatusNicks = nicks
+	h.statusReplies = map[string]time.Time{}
+}
+
+// channelOpPrefixes are the NAMES prefixes of channel operators and above
+const channelOpPrefixes = "~&@"
+
+// channelOps tracks the operators of the joined channels from the NAMES replies and the MODE changes
+type channelOps struct {
+	mu  sync.RWMutex
+	ops map[string]map[string]struct{}
+}
+
+func newChannelOps() *channelOps {
+	return &channelOps{
+		ops: map[string]map[string]struct{}{},
+	}
+}
+
+func (c *channelOps) isOp(channel, nick string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.ops[strings.ToLower(channel)][strings.ToLower(nick)]
+	return ok
+}
+
+func (c *channelOps) set(channel, nick string, op bool) {
+	channel = strings.ToLower(channel)
+	nick = strings.ToLower(nick)
+
+	nicks, ok := c.ops[channel]
+	if !ok {
+		if !op {
+			return
+		}
+		nicks = map[string]struct{}{}
+		c.ops[channel] = nicks
+	}
+
+	if op {
+		nicks[nick] = struct{}{}
+	} else {
+		delete(nicks, nick)
+	}
+}
+
+// names adds the operators from a NAMES reply, nicks may have several prefixes with multi-prefix
+func (c *channelOps) names(channel, names string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range strings.Fields(names) {
+		nick := strings.TrimLeft(name, "~&@%+")
+		prefixes := name[:len(name)-len(nick)]
+
+		c.set(channel, nick, strings.ContainsAny(prefixes, channelOpPrefixes))
+	}
+}
+
+// mode applies channel mode changes like "+o-v nick1 nick2"
+func (c *channelOps) mode(channel, modes string, args []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	adding := true
+	for _, mode := range modes {
+		switch mode {
+		case '+':
+			adding = true
+			continue
+		case '-':
+			adding = false
+			continue
+		}
+
+		// modes without a parameter, and the limit when it is removed, don't take an argument
+		if !strings.ContainsRune("qaohvbeIk", mode) && !(mode == 'l' && adding) {
+			continue
+		}
+
+		if len(args) == 0 {
+			return
+		}
+
+		arg := args[0]
+		args = args[1:]
+
+		if strings.ContainsRune("qao", mode) {
+			c.set(channel, arg, adding)
+		}
+	}
+}
+
+// remove drops nick from channel, or from all channels when channel is empty
+func (c *channelOps) remove(channel, nick string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if channel != "" {
+		c.set(channel, nick, false)
+		return
+	}
+
+	for _, nicks := range c.ops {
+		delete(nicks, strings.ToLower(nick))
+	}
+}
+
+func (c *channelOps) rename(oldNick, newNick string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	oldNick = strings.ToLower(oldNick)
+	newNick = strings.ToLower(newNick)
+
+	for _, nicks := range c.ops {
+		if _, ok := nicks[oldNick]; ok {
+			delete(nicks, oldNick)
+			nicks[newNick] = struct{}{}
+		}
+	}
+}
+
+func (c *channelOps) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ops = map[string]map[string]struct{}{}
+}
+
+// trackChannelOps keeps channelOps up to date, the handler only needs it for the status command
+func (h *Handler) trackChannelOps(msg ircmsg.Message) {
+	switch msg.Command {
+	case "353":
+		// RPL_NAMREPLY <client> <symbol> <channel> :<names>
+		if len(msg.Params) >= 4 {
+			h.channelOps.names(msg.Params[2], msg.Params[3])
+		}
+
+	case "MODE":
+		if len(msg.Params) >= 3 && h.isValidHandlerChannel(msg.Params[0]) {
+			h.channelOps.mode(msg.Params[0], msg.Params[1], msg.Params[2:])
+		}
+
+	case "PART":
+		if len(msg.Params) >= 1 {
+			h.channelOps.remove(msg.Params[0], msg.Nick())
+		}
+
+	case "KICK":
+		if len(msg.Params) >= 2 {
+			h.channelOps.remove(msg.Params[0], msg.Params[1])
+		}
+
+	case "QUIT":
+		h.channelOps.remove("", msg.Nick())
+
+	case "NICK":
+		if len(msg.Params) >= 1 {
+			h.channelOps.rename(msg.Nick(), msg.Params[0])
+		}
+	}
+}
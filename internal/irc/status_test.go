@@ -0,0 +1,116 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/indexer"
+
+	"github.com/ergochat/irc-go/ircmsg"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusNickAllowed(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowedNicks string
+		nick         string
+		isOp         bool
+		want         bool
+	}{
+		{name: "listed", allowedNicks: "Alice, bob", nick: "BOB", want: true},
+		{name: "not_listed", allowedNicks: "alice,bob", nick: "mallory", want: false},
+		{name: "not_listed_op", allowedNicks: "alice,bob", nick: "mallory", isOp: true, want: false},
+		{name: "no_list_op", allowedNicks: "", nick: "alice", isOp: true, want: true},
+		{name: "no_list_not_op", allowedNicks: " ", nick: "mallory", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, statusNickAllowed(tt.allowedNicks, tt.nick, tt.isOp))
+		})
+	}
+}
+
+func TestHandler_trackChannelOps(t *testing.T) {
+	h := NewHandler(zerolog.Nop(), nil, domain.IrcNetwork{
+		Channels: []domain.IrcChannel{{Name: "#announce"}},
+	}, nil, nil, nil)
+
+	track := func(line string) {
+		msg, err := ircmsg.ParseLine(line)
+		assert.NoError(t, err)
+		h.trackChannelOps(msg)
+	}
+
+	track(":irc.example.com 353 autobrr = #announce :~founder @Op +voiced @+multi user")
+
+	assert.True(t, h.channelOps.isOp("#Announce", "founder"))
+	assert.True(t, h.channelOps.isOp("#announce", "op"))
+	assert.True(t, h.channelOps.isOp("#announce", "multi"))
+	assert.False(t, h.channelOps.isOp("#announce", "voiced"))
+	assert.False(t, h.channelOps.isOp("#announce", "user"))
+
+	track(":op!op@host MODE #announce +o-o+l user multi 10")
+	assert.True(t, h.channelOps.isOp("#announce", "user"))
+	assert.False(t, h.channelOps.isOp("#announce", "multi"))
+
+	track(":user!user@host NICK newuser")
+	assert.False(t, h.channelOps.isOp("#announce", "user"))
+	assert.True(t, h.channelOps.isOp("#announce", "newuser"))
+
+	track(":op!op@host KICK #announce newuser :bye")
+	assert.False(t, h.channelOps.isOp("#announce", "newuser"))
+
+	track(":op!op@host PART #announce")
+	assert.False(t, h.channelOps.isOp("#announce", "op"))
+
+	track(":founder!founder@host QUIT :gone")
+	assert.False(t, h.channelOps.isOp("#announce", "founder"))
+}
+
+// mockIndexerService only implements GetIndexersByIRCNetwork, calling anything else panics
+type mockIndexerService struct {
+	indexer.Service
+}
+
+func (s *mockIndexerService) GetIndexersByIRCNetwork(server string) []*domain.IndexerDefinition {
+	return nil
+}
+
+func TestService_checkIfNetworkRestartNeeded_StatusCommand(t *testing.T) {
+	network := domain.IrcNetwork{
+		ID:       1,
+		Server:   "irc.example.com",
+		Port:     6697,
+		Nick:     "autobrr",
+		Channels: []domain.IrcChannel{{Name: "#announce"}},
+	}
+
+	h := NewHandler(zerolog.Nop(), nil, network, nil, nil, nil)
+	h.clientState = ircConnecting
+	h.statusReplies["#announce"] = time.Now()
+
+	s := &service{
+		log:            zerolog.Nop(),
+		indexerService: &mockIndexerService{},
+		handlers:       map[int64]*Handler{network.ID: h},
+	}
+
+	updated := network
+	updated.StatusCommand = "!status"
+	updated.StatusNicks = "alice"
+
+	assert.NoError(t, s.checkIfNetworkRestartNeeded(&updated))
+
+	// the running handler uses the new settings without a restart and the cooldown starts over
+	assert.False(t, h.Stopped())
+	assert.Equal(t, "!status", h.GetNetwork().StatusCommand)
+	assert.Equal(t, "alice", h.GetNetwork().StatusNicks)
+	assert.Empty(t, h.statusReplies)
+}
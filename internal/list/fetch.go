@@ -0,0 +1,264 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package list
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// maxListSize keeps a wrong url from loading a huge page into memory
+const maxListSize = 10 << 20
+
+// imdbListRegex matches the user lists of imdb, they have a csv export
+var imdbListRegex = regexp.MustCompile(`/list/(ls\d+)`)
+
+// ldJSONRegex finds the structured data of imdb charts
+var ldJSONRegex = regexp.MustCompile(`(?s)<script type="application/ld\+json">(.*?)</script>`)
+
+// fetchTitles returns the titles of the list
+func (s *service) fetchTitles(ctx context.Context, list *domain.List) ([]string, error) {
+	switch list.Type {
+	case domain.ListTypeTrakt:
+		return s.fetchTrakt(ctx, list)
+	case domain.ListTypeMDBList:
+		return s.fetchMDBList(ctx, list)
+	case domain.ListTypeIMDb:
+		return s.fetchIMDb(ctx, list)
+	case domain.ListTypePlaintext:
+		return s.fetchPlaintext(ctx, list)
+	case domain.ListTypeSonarr, domain.ListTypeRadarr, domain.ListTypeLidarr, domain.ListTypeReadarr:
+		return s.fetchArr(ctx, list)
+	default:
+		return nil, errors.New("unsupported list type: %s", list.Type)
+	}
+}
+
+func (s *service) get(ctx context.Context, listURL string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not make request")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status code: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxListSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read body")
+	}
+
+	return body, nil
+}
+
+type traktItem struct {
+	Type  string      `json:"type"`
+	Title string      `json:"title"` // set by some endpoints that return the media directly
+	Show  *traktMedia `json:"show"`
+	Movie *traktMedia `json:"movie"`
+}
+
+type traktMedia struct {
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+}
+
+// fetchTrakt reads a trakt api url like https://api.trakt.tv/users/{user}/watchlist/shows or a custom list
+func (s *service) fetchTrakt(ctx context.Context, list *domain.List) ([]string, error) {
+	body, err := s.get(ctx, list.URL, map[string]string{
+		"Content-Type":      "application/json",
+		"trakt-api-version": "2",
+		"trakt-api-key":     list.APIKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []traktItem
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal trakt list")
+	}
+
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		switch {
+		case item.Show != nil:
+			titles = append(titles, item.Show.Title)
+		case item.Movie != nil:
+			titles = append(titles, item.Movie.Title)
+		case item.Title != "":
+			titles = append(titles, item.Title)
+		}
+	}
+
+	return titles, nil
+}
+
+type mdblistItem struct {
+	Title string `json:"title"`
+}
+
+// fetchMDBList reads the json export of a mdblist list, https://mdblist.com/lists/{user}/{list}
+func (s *service) fetchMDBList(ctx context.Context, list *domain.List) ([]string, error) {
+	u, err := url.Parse(list.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse url: %s", list.URL)
+	}
+
+	if !strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/json") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/json"
+	}
+
+	if list.APIKey != "" {
+		q := u.Query()
+		q.Set("apikey", list.APIKey)
+		u.RawQuery = q.Encode()
+	}
+
+	body, err := s.get(ctx, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []mdblistItem
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal mdblist list")
+	}
+
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+
+	return titles, nil
+}
+
+type imdbItemList struct {
+	ItemListElement []struct {
+		Item struct {
+			Name string `json:"name"`
+		} `json:"item"`
+	} `json:"itemListElement"`
+}
+
+// fetchIMDb reads the csv export of an imdb user list, or the structured data of a chart like https://www.imdb.com/chart/top
+func (s *service) fetchIMDb(ctx context.Context, list *domain.List) ([]string, error) {
+	u, err := url.Parse(list.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse url: %s", list.URL)
+	}
+
+	if m := imdbListRegex.FindStringSubmatch(u.Path); m != nil {
+		body, err := s.get(ctx, u.Scheme+"://"+u.Host+"/list/"+m[1]+"/export", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return csvTitles(body)
+	}
+
+	body, err := s.get(ctx, list.URL, map[string]string{"Accept-Language": "en-US"})
+	if err != nil {
+		return nil, err
+	}
+
+	m := ldJSONRegex.FindSubmatch(body)
+	if m == nil {
+		return nil, errors.New("could not find the list in the imdb page")
+	}
+
+	var itemList imdbItemList
+	if err := json.Unmarshal(m[1], &itemList); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal imdb list")
+	}
+
+	titles := make([]string, 0, len(itemList.ItemListElement))
+	for _, element := range itemList.ItemListElement {
+		titles = append(titles, element.Item.Name)
+	}
+
+	return titles, nil
+}
+
+// csvTitles returns the title column of an imdb csv export
+func csvTitles(body []byte) ([]string, error) {
+	records, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read imdb csv")
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	column := -1
+	for i, name := range records[0] {
+		if strings.EqualFold(strings.TrimSpace(name), "title") {
+			column = i
+			break
+		}
+	}
+
+	if column < 0 {
+		return nil, errors.New("imdb csv has no title column")
+	}
+
+	titles := make([]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if column < len(record) {
+			titles = append(titles, record[column])
+		}
+	}
+
+	return titles, nil
+}
+
+// fetchPlaintext reads one title per line, empty lines and lines starting with # are skipped
+func (s *service) fetchPlaintext(ctx context.Context, list *domain.List) ([]string, error) {
+	body, err := s.get(ctx, list.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var titles []string
+
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		titles = append(titles, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read list")
+	}
+
+	return titles, nil
+}
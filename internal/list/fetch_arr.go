@@ -0,0 +1,205 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package list
+
+import (
+	"context"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/lidarr"
+	"github.com/autobrr/autobrr/pkg/radarr"
+	"github.com/autobrr/autobrr/pkg/readarr"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+)
+
+// arrClientTypes is the client type each arr list type reads from
+var arrClientTypes = map[domain.ListType]domain.DownloadClientType{
+	domain.ListTypeSonarr:  domain.DownloadClientTypeSonarr,
+	domain.ListTypeRadarr:  domain.DownloadClientTypeRadarr,
+	domain.ListTypeLidarr:  domain.DownloadClientTypeLidarr,
+	domain.ListTypeReadarr: domain.DownloadClientTypeReadarr,
+}
+
+// arrItem is a series, movie, artist or author of an arr client
+type arrItem struct {
+	title     string
+	monitored bool
+	tags      []int64
+}
+
+// fetchArr returns the titles of the monitored items of the arr client of the list which pass the tags of the list
+func (s *service) fetchArr(ctx context.Context, list *domain.List) ([]string, error) {
+	client, err := s.clientSvc.GetClient(ctx, list.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get client with id %d", list.ClientID)
+	}
+
+	if client.Type != arrClientTypes[list.Type] {
+		return nil, errors.New("client %s is not a %s client", client.Name, strings.ToLower(string(list.Type)))
+	}
+
+	if !client.Enabled {
+		return nil, errors.New("client %s is disabled", client.Name)
+	}
+
+	items, tags, err := fetchArrItems(ctx, list.Type, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrTitles(items, tags, list.TagsInclude, list.TagsExclude), nil
+}
+
+// fetchArrItems returns the items of the arr client and its tag labels by id
+func fetchArrItems(ctx context.Context, listType domain.ListType, client *domain.DownloadClient) ([]arrItem, map[int64]string, error) {
+	var items []arrItem
+	tags := map[int64]string{}
+
+	switch listType {
+	case domain.ListTypeSonarr:
+		arr, ok := client.Client.(sonarr.Client)
+		if !ok {
+			return nil, nil, errors.New("could not get sonarr client: %s", client.Name)
+		}
+
+		series, err := arr.GetSeries(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get series of sonarr client: %s", client.Name)
+		}
+
+		for _, show := range series {
+			items = append(items, arrItem{title: show.Title, monitored: show.Monitored, tags: show.Tags})
+		}
+
+		arrTags, err := arr.GetTags(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get tags of sonarr client: %s", client.Name)
+		}
+
+		for _, tag := range arrTags {
+			tags[tag.ID] = tag.Label
+		}
+
+	case domain.ListTypeRadarr:
+		arr, ok := client.Client.(radarr.Client)
+		if !ok {
+			return nil, nil, errors.New("could not get radarr client: %s", client.Name)
+		}
+
+		movies, err := arr.GetMovies(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get movies of radarr client: %s", client.Name)
+		}
+
+		for _, movie := range movies {
+			items = append(items, arrItem{title: movie.Title, monitored: movie.Monitored, tags: movie.Tags})
+		}
+
+		arrTags, err := arr.GetTags(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get tags of radarr client: %s", client.Name)
+		}
+
+		for _, tag := range arrTags {
+			tags[tag.ID] = tag.Label
+		}
+
+	case domain.ListTypeLidarr:
+		arr, ok := client.Client.(lidarr.Client)
+		if !ok {
+			return nil, nil, errors.New("could not get lidarr client: %s", client.Name)
+		}
+
+		artists, err := arr.GetArtists(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get artists of lidarr client: %s", client.Name)
+		}
+
+		for _, artist := range artists {
+			items = append(items, arrItem{title: artist.ArtistName, monitored: artist.Monitored, tags: artist.Tags})
+		}
+
+		arrTags, err := arr.GetTags(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get tags of lidarr client: %s", client.Name)
+		}
+
+		for _, tag := range arrTags {
+			tags[tag.ID] = tag.Label
+		}
+
+	case domain.ListTypeReadarr:
+		arr, ok := client.Client.(readarr.Client)
+		if !ok {
+			return nil, nil, errors.New("could not get readarr client: %s", client.Name)
+		}
+
+		authors, err := arr.GetAuthors(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get authors of readarr client: %s", client.Name)
+		}
+
+		for _, author := range authors {
+			items = append(items, arrItem{title: author.AuthorName, monitored: author.Monitored, tags: author.Tags})
+		}
+
+		arrTags, err := arr.GetTags(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get tags of readarr client: %s", client.Name)
+		}
+
+		for _, tag := range arrTags {
+			tags[tag.ID] = tag.Label
+		}
+
+	default:
+		return nil, nil, errors.New("unsupported arr list type: %s", listType)
+	}
+
+	return items, tags, nil
+}
+
+// arrTitles returns the titles of the monitored items which have one of the include tags, when set, and none of the exclude tags
+func arrTitles(items []arrItem, tags map[int64]string, include []string, exclude []string) []string {
+	titles := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if !item.monitored {
+			continue
+		}
+
+		labels := make([]string, 0, len(item.tags))
+		for _, id := range item.tags {
+			if label, ok := tags[id]; ok {
+				labels = append(labels, label)
+			}
+		}
+
+		if len(include) > 0 && !containsAnyFold(labels, include) {
+			continue
+		}
+
+		if containsAnyFold(labels, exclude) {
+			continue
+		}
+
+		titles = append(titles, item.title)
+	}
+
+	return titles
+}
+
+func containsAnyFold(labels []string, tags []string) bool {
+	for _, label := range labels {
+		for _, tag := range tags {
+			if strings.EqualFold(label, strings.TrimSpace(tag)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package list
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/scheduler"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+
+	"github.com/rs/zerolog"
+)
+
+type Service interface {
+	List(ctx context.Context) ([]*domain.List, error)
+	FindByID(ctx context.Context, id int64) (*domain.List, error)
+	Store(ctx context.Context, list *domain.List) error
+	Update(ctx context.Context, list *domain.List) error
+	Delete(ctx context.Context, id int64) error
+	ToggleEnabled(ctx context.Context, id int64, enabled bool) error
+	RefreshList(ctx context.Context, id int64) error
+	Start() error
+}
+
+type service struct {
+	log        zerolog.Logger
+	repo       domain.ListRepo
+	filterSvc  filter.Service
+	clientSvc  download_client.Service
+	scheduler  scheduler.Service
+	httpClient *http.Client
+}
+
+func NewService(log logger.Logger, repo domain.ListRepo, filterSvc filter.Service, clientSvc download_client.Service, scheduler scheduler.Service) Service {
+	return &service{
+		log:       log.With().Str("module", "list").Logger(),
+		repo:      repo,
+		filterSvc: filterSvc,
+		clientSvc: clientSvc,
+		scheduler: scheduler,
+		httpClient: &http.Client{
+			Timeout:   time.Second * 60,
+			Transport: sharedhttp.Transport,
+		},
+	}
+}
+
+func (s *service) List(ctx context.Context) ([]*domain.List, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *service) FindByID(ctx context.Context, id int64) (*domain.List, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *service) Store(ctx context.Context, list *domain.List) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Store(ctx, list); err != nil {
+		return errors.Wrap(err, "could not store list: %s", list.Name)
+	}
+
+	return s.scheduleList(list)
+}
+
+func (s *service) Update(ctx context.Context, list *domain.List) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		return errors.Wrap(err, "could not update list: %s", list.Name)
+	}
+
+	return s.scheduleList(list)
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return errors.Wrap(err, "could not delete list: %d", id)
+	}
+
+	return s.scheduler.RemoveJobByIdentifier(listJobIdentifier(id))
+}
+
+func (s *service) ToggleEnabled(ctx context.Context, id int64, enabled bool) error {
+	if err := s.repo.ToggleEnabled(ctx, id, enabled); err != nil {
+		return errors.Wrap(err, "could not toggle list: %d", id)
+	}
+
+	list, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "could not find list: %d", id)
+	}
+
+	return s.scheduleList(list)
+}
+
+// Start schedules the refresh jobs of the enabled lists
+func (s *service) Start() error {
+	lists, err := s.repo.List(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "could not get lists")
+	}
+
+	for _, list := range lists {
+		if err := s.scheduleList(list); err != nil {
+			s.log.Error().Err(err).Msgf("could not schedule list: %s", list.Name)
+		}
+	}
+
+	return nil
+}
+
+func listJobIdentifier(id int64) string {
+	return fmt.Sprintf("list-%d", id)
+}
+
+// scheduleList replaces the refresh job of the list, disabled lists are only unscheduled
+func (s *service) scheduleList(list *domain.List) error {
+	identifier := listJobIdentifier(list.ID)
+
+	if err := s.scheduler.RemoveJobByIdentifier(identifier); err != nil {
+		return errors.Wrap(err, "could not remove job: %s", identifier)
+	}
+
+	if !list.Enabled {
+		return nil
+	}
+
+	job := &RefreshJob{
+		Name:   identifier,
+		Log:    s.log.With().Str("job", identifier).Logger(),
+		ListID: list.ID,
+		svc:    s,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(job, time.Duration(list.RefreshInterval)*time.Minute, identifier); err != nil {
+		return errors.Wrap(err, "could not schedule job: %s", identifier)
+	}
+
+	// fill the filters right away instead of waiting for the first interval
+	go job.Run()
+
+	return nil
+}
+
+// RefreshList fetches the titles of the list and replaces the list field of the connected filters with them
+func (s *service) RefreshList(ctx context.Context, id int64) error {
+	list, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "could not find list: %d", id)
+	}
+
+	refreshErr := s.refresh(ctx, list)
+
+	list.LastRefreshTime = time.Now()
+	list.LastRefreshStatus = domain.ListRefreshStatusSuccess
+	list.LastRefreshError = ""
+
+	if refreshErr != nil {
+		list.LastRefreshStatus = domain.ListRefreshStatusError
+		list.LastRefreshError = refreshErr.Error()
+	}
+
+	if err := s.repo.UpdateLastRefresh(ctx, list); err != nil {
+		s.log.Error().Err(err).Msgf("could not update last refresh of list: %s", list.Name)
+	}
+
+	return refreshErr
+}
+
+func (s *service) refresh(ctx context.Context, list *domain.List) error {
+	titles, err := s.fetchTitles(ctx, list)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch list: %s", list.Name)
+	}
+
+	if len(titles) == 0 {
+		// an empty field disables the check, so an empty list would match everything
+		return errors.New("list %s has no titles, filters are left as they are", list.Name)
+	}
+
+	value := filterValue(list.FilterField, titles)
+
+	for _, f := range list.Filters {
+		if err := s.filterSvc.UpdatePartial(ctx, list.FilterUpdate(f.ID, value)); err != nil {
+			return errors.Wrap(err, "could not update filter: %s", f.Name)
+		}
+	}
+
+	s.log.Debug().Msgf("list %s: synced %d titles into %d filters", list.Name, len(titles), len(list.Filters))
+
+	return nil
+}
+
+type RefreshJob struct {
+	Name   string
+	Log    zerolog.Logger
+	ListID int64
+	svc    *service
+}
+
+func (j *RefreshJob) Run() {
+	if err := j.svc.RefreshList(context.Background(), j.ListID); err != nil {
+		j.Log.Error().Err(err).Msg("could not refresh list")
+	}
+}
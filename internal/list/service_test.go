@@ -0,0 +1,196 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package list
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockFilterService only implements UpdatePartial, calling anything else panics
+type mockFilterService struct {
+	filter.Service
+	updates []domain.FilterUpdate
+}
+
+func (s *mockFilterService) UpdatePartial(ctx context.Context, f domain.FilterUpdate) error {
+	s.updates = append(s.updates, f)
+	return nil
+}
+
+func Test_filterValue(t *testing.T) {
+	titles := []string{"Law & Order: Special Victims Unit", "Marvel's Agents of S.H.I.E.L.D.", "The Office", "the office", "!!!"}
+
+	assert.Equal(t, "Law*Order*Special Victims Unit,Marvels Agents of S*H*I*E*L*D,The Office", filterValue(domain.ListFilterFieldShows, titles))
+	assert.Equal(t, "*Law*Order*Special?Victims?Unit*,*Marvels?Agents?of?S*H*I*E*L*D*,*The?Office*", filterValue(domain.ListFilterFieldMatchReleases, titles))
+}
+
+func Test_service_fetchTitles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/me/watchlist/shows":
+			if r.Header.Get("trakt-api-key") != "client-id" || r.Header.Get("trakt-api-version") != "2" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"type":"show","show":{"title":"Show One","year":2020}},{"type":"movie","movie":{"title":"Movie One","year":2021}}]`))
+		case "/lists/user/list/json":
+			if r.URL.Query().Get("apikey") != "key" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"id":1,"title":"Show Two","release_year":2022}]`))
+		case "/list/ls012345678/export":
+			_, _ = w.Write([]byte("Position,Const,Title,Year\n1,tt0000001,\"Movie, Two\",2023\n"))
+		case "/chart/top/":
+			_, _ = w.Write([]byte(`<html><script type="application/ld+json">{"@type":"ItemList","itemListElement":[{"@type":"ListItem","item":{"@type":"Movie","name":"Movie Three"}}]}</script></html>`))
+		case "/shows.txt":
+			_, _ = w.Write([]byte("# my shows\nShow Three\n\n  Show Four  \n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{log: zerolog.Nop(), httpClient: http.DefaultClient}
+
+	tests := []struct {
+		name    string
+		list    domain.List
+		want    []string
+		wantErr string
+	}{
+		{name: "trakt", list: domain.List{Type: domain.ListTypeTrakt, URL: srv.URL + "/users/me/watchlist/shows", APIKey: "client-id"}, want: []string{"Show One", "Movie One"}},
+		{name: "trakt_bad_key", list: domain.List{Type: domain.ListTypeTrakt, URL: srv.URL + "/users/me/watchlist/shows", APIKey: "bad"}, wantErr: "unexpected status code: 403"},
+		{name: "mdblist", list: domain.List{Type: domain.ListTypeMDBList, URL: srv.URL + "/lists/user/list/", APIKey: "key"}, want: []string{"Show Two"}},
+		{name: "imdb_list", list: domain.List{Type: domain.ListTypeIMDb, URL: srv.URL + "/list/ls012345678/?ref_=x"}, want: []string{"Movie, Two"}},
+		{name: "imdb_chart", list: domain.List{Type: domain.ListTypeIMDb, URL: srv.URL + "/chart/top/"}, want: []string{"Movie Three"}},
+		{name: "plaintext", list: domain.List{Type: domain.ListTypePlaintext, URL: srv.URL + "/shows.txt"}, want: []string{"Show Three", "Show Four"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.fetchTitles(context.Background(), &tt.list)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_service_refresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/shows.txt":
+			_, _ = w.Write([]byte("Show One\nShow Two\n"))
+		default:
+			_, _ = w.Write([]byte("# nothing yet\n"))
+		}
+	}))
+	defer srv.Close()
+
+	filterSvc := &mockFilterService{}
+	s := &service{log: zerolog.Nop(), httpClient: http.DefaultClient, filterSvc: filterSvc}
+
+	list := &domain.List{
+		Name:        "shows",
+		Type:        domain.ListTypePlaintext,
+		URL:         srv.URL + "/shows.txt",
+		FilterField: domain.ListFilterFieldShows,
+		Filters:     []domain.ListFilter{{ID: 1, Name: "tv"}, {ID: 2, Name: "tv-4k"}},
+	}
+
+	err := s.refresh(context.Background(), list)
+	assert.NoError(t, err)
+
+	shows := "Show One,Show Two"
+	assert.Equal(t, []domain.FilterUpdate{{ID: 1, Shows: &shows}, {ID: 2, Shows: &shows}}, filterSvc.updates)
+
+	// an empty list leaves the filters alone instead of clearing the field
+	filterSvc.updates = nil
+	list.URL = srv.URL + "/empty.txt"
+
+	err = s.refresh(context.Background(), list)
+	assert.EqualError(t, err, "list shows has no titles, filters are left as they are")
+	assert.Empty(t, filterSvc.updates)
+}
+
+// mockDownloadClientService only implements GetClient, calling anything else panics
+type mockDownloadClientService struct {
+	download_client.Service
+	clients map[int32]*domain.DownloadClient
+}
+
+func (s *mockDownloadClientService) GetClient(ctx context.Context, clientID int32) (*domain.DownloadClient, error) {
+	client, ok := s.clients[clientID]
+	if !ok {
+		return nil, domain.ErrRecordNotFound
+	}
+	return client, nil
+}
+
+func Test_service_fetchArr(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/series":
+			_, _ = w.Write([]byte(`[
+				{"id":1,"title":"Show One","monitored":true,"tags":[1]},
+				{"id":2,"title":"Show Two","monitored":true,"tags":[1,2]},
+				{"id":3,"title":"Show Three","monitored":false,"tags":[1]},
+				{"id":4,"title":"Show Four","monitored":true}
+			]`))
+		case "/api/v3/tag":
+			_, _ = w.Write([]byte(`[{"id":1,"label":"autobrr"},{"id":2,"label":"4k"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &service{
+		log: zerolog.Nop(),
+		clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+			1: {ID: 1, Name: "sonarr", Type: domain.DownloadClientTypeSonarr, Enabled: true, Client: sonarr.New(sonarr.Config{Hostname: srv.URL})},
+			2: {ID: 2, Name: "sonarr-disabled", Type: domain.DownloadClientTypeSonarr, Enabled: false},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		list     domain.List
+		expected []string
+		wantErr  string
+	}{
+		{name: "monitored", list: domain.List{Type: domain.ListTypeSonarr, ClientID: 1}, expected: []string{"Show One", "Show Two", "Show Four"}},
+		{name: "include", list: domain.List{Type: domain.ListTypeSonarr, ClientID: 1, TagsInclude: []string{"AUTOBRR"}}, expected: []string{"Show One", "Show Two"}},
+		{name: "include_exclude", list: domain.List{Type: domain.ListTypeSonarr, ClientID: 1, TagsInclude: []string{"autobrr"}, TagsExclude: []string{"4k"}}, expected: []string{"Show One"}},
+		{name: "wrong_type", list: domain.List{Type: domain.ListTypeRadarr, ClientID: 1}, wantErr: "client sonarr is not a radarr client"},
+		{name: "disabled", list: domain.List{Type: domain.ListTypeSonarr, ClientID: 2}, wantErr: "client sonarr-disabled is disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.fetchTitles(context.Background(), &tt.list)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package list
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// titlePattern turns a title into a wildcard pattern which ignores punctuation, "Law & Order: SVU" becomes "Law*Order*SVU".
+// Apostrophes are dropped since release names leave them out, plain spaces become sep.
+func titlePattern(title string, sep string) string {
+	title = strings.NewReplacer("'", "", "’", "").Replace(title)
+
+	var b strings.Builder
+	var gap []rune
+
+	flush := func() {
+		if len(gap) == 0 {
+			return
+		}
+
+		if b.Len() > 0 {
+			if strings.TrimSpace(string(gap)) == "" && len(gap) == 1 {
+				b.WriteString(sep)
+			} else {
+				b.WriteString("*")
+			}
+		}
+
+		gap = gap[:0]
+	}
+
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			flush()
+			b.WriteRune(r)
+			continue
+		}
+
+		gap = append(gap, r)
+	}
+
+	return b.String()
+}
+
+// filterValue builds the value of the filter field from the titles of a list.
+// Shows are matched against the parsed title, match and except releases against the whole release name.
+func filterValue(field domain.ListFilterField, titles []string) string {
+	seen := make(map[string]struct{}, len(titles))
+	patterns := make([]string, 0, len(titles))
+
+	for _, title := range titles {
+		var pattern string
+
+		switch field {
+		case domain.ListFilterFieldShows:
+			pattern = titlePattern(title, " ")
+		default:
+			pattern = titlePattern(title, "?")
+			if pattern != "" {
+				pattern = "*" + pattern + "*"
+			}
+		}
+
+		if pattern == "" {
+			continue
+		}
+
+		key := strings.ToLower(pattern)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return strings.Join(patterns, ",")
+}
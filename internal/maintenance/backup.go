@@ -0,0 +1,62 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// Backup writes a copy of the database to the configured storage
+func (s *service) Backup(ctx context.Context) (*domain.StorageObject, error) {
+	dir, err := os.MkdirTemp("", "autobrr-backup-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	name := fmt.Sprintf("autobrr-%s.db", time.Now().UTC().Format("20060102-150405"))
+	tmpFile := filepath.Join(dir, name)
+
+	if err := s.repo.Backup(ctx, tmpFile); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(tmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open backup")
+	}
+	defer f.Close()
+
+	object, err := s.storageSvc.Store(ctx, domain.StorageArtifactBackup, name, f)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not store backup")
+	}
+
+	return object, nil
+}
+
+type BackupJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+func (j *BackupJob) Run() {
+	object, err := j.svc.Backup(context.Background())
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not backup database")
+		return
+	}
+
+	j.Log.Info().Msgf("database backup stored as %s", object.Key)
+}
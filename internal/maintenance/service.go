@@ -0,0 +1,117 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/scheduler"
+	"github.com/autobrr/autobrr/internal/storage"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const orphanCleanupInterval = 24 * time.Hour
+
+type Service interface {
+	CleanupOrphans(ctx context.Context, dryRun bool) (*domain.OrphanCleanupReport, error)
+	Backup(ctx context.Context) (*domain.StorageObject, error)
+	Start() error
+}
+
+type service struct {
+	log        zerolog.Logger
+	config     *domain.Config
+	repo       domain.MaintenanceRepo
+	scheduler  scheduler.Service
+	storageSvc storage.Service
+}
+
+func NewService(log logger.Logger, config *domain.Config, repo domain.MaintenanceRepo, scheduler scheduler.Service, storageSvc storage.Service) Service {
+	return &service{
+		log:        log.With().Str("module", "maintenance").Logger(),
+		config:     config,
+		repo:       repo,
+		scheduler:  scheduler,
+		storageSvc: storageSvc,
+	}
+}
+
+func (s *service) Start() error {
+	// deleting data is opt-in, autobrrctl db:cleanup can be used to run it manually
+	if s.config.OrphanCleanup {
+		job := &OrphanCleanupJob{
+			Name: "maintenance-orphan-cleanup",
+			Log:  s.log.With().Str("job", "maintenance-orphan-cleanup").Logger(),
+			svc:  s,
+		}
+
+		if _, err := s.scheduler.ScheduleJob(job, orphanCleanupInterval, job.Name); err != nil {
+			return errors.Wrap(err, "could not schedule job: %s", job.Name)
+		}
+	}
+
+	if schedule := s.storageSvc.Config().BackupSchedule; schedule != "" {
+		backupJob := &BackupJob{
+			Name: "maintenance-backup",
+			Log:  s.log.With().Str("job", "maintenance-backup").Logger(),
+			svc:  s,
+		}
+
+		if _, err := s.scheduler.AddJob(backupJob, schedule, backupJob.Name); err != nil {
+			return errors.Wrap(err, "could not schedule job: %s", backupJob.Name)
+		}
+	}
+
+	return nil
+}
+
+// CleanupOrphans removes rows left behind by deleted releases, filters, clients and feeds
+func (s *service) CleanupOrphans(ctx context.Context, dryRun bool) (*domain.OrphanCleanupReport, error) {
+	results, err := s.repo.CleanupOrphans(ctx, dryRun)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not cleanup orphaned data")
+	}
+
+	report := &domain.OrphanCleanupReport{
+		DryRun:  dryRun,
+		Results: results,
+		RunAt:   time.Now(),
+	}
+
+	for _, result := range results {
+		report.Total += result.Count
+	}
+
+	return report, nil
+}
+
+type OrphanCleanupJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+func (j *OrphanCleanupJob) Run() {
+	report, err := j.svc.CleanupOrphans(context.Background(), false)
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not cleanup orphaned data")
+		return
+	}
+
+	if report.Total == 0 {
+		j.Log.Debug().Msg("no orphaned data found")
+		return
+	}
+
+	for _, result := range report.Results {
+		if result.Count > 0 {
+			j.Log.Info().Msgf("removed %d orphaned rows from %s: %s", result.Count, result.Table, result.Description)
+		}
+	}
+}
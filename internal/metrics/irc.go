@@ -0,0 +1,75 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type IRCNetworkLister interface {
+	GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetworkWithHealth, error)
+}
+
+// ircCollector reads the connection state of the networks when scraped instead of tracking every change
+type ircCollector struct {
+	service IRCNetworkLister
+
+	connected *prometheus.Desc
+	channels  *prometheus.Desc
+}
+
+func newIRCCollector(service IRCNetworkLister) *ircCollector {
+	return &ircCollector{
+		service:   service,
+		connected: prometheus.NewDesc(prometheus.BuildFQName(namespace, "irc", "network_connected"), "Whether the irc network is connected.", []string{"network", "enabled"}, nil),
+		channels:  prometheus.NewDesc(prometheus.BuildFQName(namespace, "irc", "channels_monitoring"), "Channels of the irc network announces are received from.", []string{"network"}, nil),
+	}
+}
+
+func (c *ircCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.connected
+	ch <- c.channels
+}
+
+func (c *ircCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	networks, err := c.service.GetNetworksWithHealth(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.connected, err)
+		return
+	}
+
+	for _, network := range networks {
+		enabled := "false"
+		if network.Enabled {
+			enabled = "true"
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.connected, prometheus.GaugeValue, boolValue(network.Connected), network.Name, enabled)
+
+		monitoring := 0
+		for _, channel := range network.Channels {
+			if channel.Monitoring {
+				monitoring++
+			}
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.channels, prometheus.GaugeValue, float64(monitoring), network.Name)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "autobrr"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	announcesProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "announces_processed_total",
+		Help:      "Releases announced by an indexer and checked against the filters.",
+	}, []string{"indexer"})
+
+	filterChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "filter_checks_total",
+		Help:      "Releases checked by a filter, by result match or rejected.",
+	}, []string{"filter", "result"})
+
+	pushes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "action_pushes_total",
+		Help:      "Releases pushed by actions, by status of the push.",
+	}, []string{"client", "type", "status"})
+
+	announcePushLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "announce_push_latency_seconds",
+		Help:      "Time from the announce of a release until a client accepted it.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+	}, []string{"indexer"})
+
+	feedFetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "feed_fetch_duration_seconds",
+		Help:      "Time to fetch the items of a feed, by result success or error.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"feed", "type", "result"})
+
+	databaseQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "database_query_duration_seconds",
+		Help:      "Time the database took to run a statement, by operation query or exec.",
+		Buckets:   []float64{0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"operation"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		announcesProcessed,
+		filterChecks,
+		pushes,
+		announcePushLatency,
+		feedFetchDuration,
+		databaseQueryDuration,
+	)
+}
+
+// AnnounceProcessed counts a release of the indexer going through the filters
+func AnnounceProcessed(indexer string) {
+	announcesProcessed.WithLabelValues(indexer).Inc()
+}
+
+// FilterChecked counts a release checked by the filter
+func FilterChecked(filter string, match bool) {
+	result := "rejected"
+	if match {
+		result = "match"
+	}
+
+	filterChecks.WithLabelValues(filter, result).Inc()
+}
+
+// ActionPushed counts the outcome of an action, client is empty for actions without download client
+func ActionPushed(status *domain.ReleaseActionStatus) {
+	pushes.WithLabelValues(status.Client, string(status.Type), string(status.Status)).Inc()
+}
+
+// AnnouncePushed observes the time from the announce until the push was accepted
+func AnnouncePushed(indexer string, latency time.Duration) {
+	announcePushLatency.WithLabelValues(indexer).Observe(latency.Seconds())
+}
+
+// FeedFetched observes the time it took to fetch the feed
+func FeedFetched(feed *domain.Feed, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	feedFetchDuration.WithLabelValues(feed.Name, feed.Type, result).Observe(duration.Seconds())
+}
+
+// DatabaseQuery observes the time the database took to run a query or exec
+func DatabaseQuery(operation string, duration time.Duration) {
+	databaseQueryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// Handler serves the metrics in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Setup serves the metrics on their own listener, the irc networks are collected when scraped
+func Setup(log logger.Logger, cfg *domain.Config, irc IRCNetworkLister) {
+	if !cfg.MetricsEnabled {
+		return
+	}
+
+	l := log.With().Str("module", "metrics").Logger()
+
+	registry.MustRegister(newIRCCollector(irc))
+
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", Handler())
+
+		addr := fmt.Sprintf("%s:%d", cfg.MetricsHost, cfg.MetricsPort)
+
+		l.Info().Msgf("Starting metrics server on %s/metrics", addr)
+
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			l.Error().Err(err).Msg("could not start metrics server")
+		}
+	}()
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ircNetworkListerMock struct {
+	networks []domain.IrcNetworkWithHealth
+}
+
+func (m ircNetworkListerMock) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetworkWithHealth, error) {
+	return m.networks, nil
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestHandler(t *testing.T) {
+	AnnounceProcessed("mock")
+	FilterChecked("movies", true)
+	FilterChecked("movies", false)
+	ActionPushed(&domain.ReleaseActionStatus{Client: "qbit", Type: domain.ActionTypeQbittorrent, Status: domain.ReleasePushStatusApproved})
+	AnnouncePushed("mock", 300*time.Millisecond)
+	FeedFetched(&domain.Feed{Name: "feed", Type: "TORZNAB"}, time.Second, nil)
+	DatabaseQuery("query", time.Millisecond)
+
+	body := scrape(t, Handler())
+
+	assert.Contains(t, body, `autobrr_announces_processed_total{indexer="mock"} 1`)
+	assert.Contains(t, body, `autobrr_filter_checks_total{filter="movies",result="match"} 1`)
+	assert.Contains(t, body, `autobrr_filter_checks_total{filter="movies",result="rejected"} 1`)
+	assert.Contains(t, body, `autobrr_action_pushes_total{client="qbit",status="PUSH_APPROVED",type="QBITTORRENT"} 1`)
+	assert.Contains(t, body, `autobrr_announce_push_latency_seconds_bucket{indexer="mock",le="0.5"} 1`)
+	assert.Contains(t, body, `autobrr_feed_fetch_duration_seconds_count{feed="feed",result="success",type="TORZNAB"} 1`)
+	assert.Contains(t, body, `autobrr_database_query_duration_seconds_count{operation="query"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestIRCCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(newIRCCollector(ircNetworkListerMock{networks: []domain.IrcNetworkWithHealth{
+		{
+			Name:      "Network",
+			Enabled:   true,
+			Connected: true,
+			Channels:  []domain.ChannelWithHealth{{Name: "#announce", Monitoring: true}, {Name: "#chat"}},
+		},
+		{Name: "Disabled"},
+	}}))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range m.GetLabel() {
+				key += " " + label.GetName() + "=" + label.GetValue()
+			}
+
+			values[key] = m.GetGauge().GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"autobrr_irc_network_connected enabled=true network=Network":   1,
+		"autobrr_irc_network_connected enabled=false network=Disabled": 0,
+		"autobrr_irc_channels_monitoring network=Network":              1,
+		"autobrr_irc_channels_monitoring network=Disabled":             0,
+	}, values)
+}
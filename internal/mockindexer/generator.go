@@ -0,0 +1,211 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mockindexer
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	defaultRate    = 6
+	defaultMinSize = "500MB"
+	defaultMaxSize = "20GB"
+
+	// maxRate keeps a misconfigured mock indexer from flooding the release pipeline
+	maxRate = 600
+)
+
+var defaultTemplates = []string{
+	"{{ .Title }}.S{{ .Season }}E{{ .Episode }}.{{ .Resolution }}.{{ .Source }}.{{ .Codec }}-{{ .Group }}",
+	"{{ .Title }}.S{{ .Season }}.{{ .Resolution }}.{{ .Source }}.{{ .Codec }}-{{ .Group }}",
+	"{{ .Title }}.{{ .Year }}.{{ .Resolution }}.{{ .Source }}.{{ .Codec }}-{{ .Group }}",
+}
+
+var (
+	titles      = []string{"Mock.Show", "Synthetic.Series", "The.Test.Pattern", "Null.Island", "Fake.Movie", "Dry.Run"}
+	resolutions = []string{"2160p", "1080p", "720p"}
+	sources     = []string{"WEB-DL", "WEBRip", "BluRay", "HDTV"}
+	codecs      = []string{"x264", "x265", "H.264", "H.265"}
+	groups      = []string{"MOCK", "SYNTH", "TESTGRP", "NOGRP"}
+)
+
+// Settings of a mock indexer
+type Settings struct {
+	Rate      float64
+	Templates []string
+	MinSize   uint64
+	MaxSize   uint64
+	URL       string
+}
+
+// ParseSettings reads the indexer settings and applies the defaults
+func ParseSettings(settings map[string]string) (Settings, error) {
+	s := Settings{
+		Rate:      defaultRate,
+		Templates: defaultTemplates,
+		URL:       strings.TrimSpace(settings["url"]),
+	}
+
+	if v := strings.TrimSpace(settings["rate"]); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 {
+			return s, errors.New("invalid rate: %q", v)
+		}
+		s.Rate = min(rate, maxRate)
+	}
+
+	if v := strings.TrimSpace(settings["templates"]); v != "" {
+		s.Templates = nil
+		for _, t := range strings.Split(v, ";") {
+			if t = strings.TrimSpace(t); t != "" {
+				s.Templates = append(s.Templates, t)
+			}
+		}
+	}
+
+	minSize, maxSize := settings["min_size"], settings["max_size"]
+	if strings.TrimSpace(minSize) == "" {
+		minSize = defaultMinSize
+	}
+	if strings.TrimSpace(maxSize) == "" {
+		maxSize = defaultMaxSize
+	}
+
+	var err error
+	if s.MinSize, err = humanize.ParseBytes(minSize); err != nil {
+		return s, errors.Wrap(err, "invalid min size: %q", minSize)
+	}
+	if s.MaxSize, err = humanize.ParseBytes(maxSize); err != nil {
+		return s, errors.Wrap(err, "invalid max size: %q", maxSize)
+	}
+
+	if s.MinSize == 0 || s.MaxSize < s.MinSize {
+		return s, errors.New("invalid size range: %s - %s", minSize, maxSize)
+	}
+
+	if s.MaxSize > maxTorrentSize {
+		return s, errors.New("max size can not be larger than %s", humanize.IBytes(maxTorrentSize))
+	}
+
+	return s, nil
+}
+
+type templateData struct {
+	Title      string
+	Year       int
+	Season     string
+	Episode    string
+	Resolution string
+	Source     string
+	Codec      string
+	Group      string
+	N          int
+}
+
+// Generator creates synthetic releases for a mock indexer
+type Generator struct {
+	indexer   domain.IndexerMinimal
+	settings  Settings
+	baseURL   string
+	templates []*template.Template
+	rand      *rand.Rand
+	n         int
+}
+
+// NewGenerator returns a generator for indexer. The download urls of the releases point to baseURL,
+// which must be the url autobrr is reachable on including the base url.
+func NewGenerator(indexer domain.IndexerMinimal, settings Settings, baseURL string, seed int64) (*Generator, error) {
+	g := &Generator{
+		indexer:  indexer,
+		settings: settings,
+		baseURL:  baseURL,
+		rand:     rand.New(rand.NewSource(seed)),
+	}
+
+	if settings.URL != "" {
+		g.baseURL = settings.URL
+	}
+
+	if !strings.HasSuffix(g.baseURL, "/") {
+		g.baseURL += "/"
+	}
+
+	if len(settings.Templates) == 0 {
+		return nil, errors.New("no release templates")
+	}
+
+	for idx, text := range settings.Templates {
+		tmpl, err := template.New(fmt.Sprintf("template-%d", idx)).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse template: %q", text)
+		}
+
+		g.templates = append(g.templates, tmpl)
+	}
+
+	return g, nil
+}
+
+// Next returns a new release
+func (g *Generator) Next() (*domain.Release, error) {
+	g.n++
+
+	data := templateData{
+		Title:      pick(g.rand, titles),
+		Year:       1990 + g.rand.Intn(35),
+		Season:     fmt.Sprintf("%02d", 1+g.rand.Intn(12)),
+		Episode:    fmt.Sprintf("%02d", 1+g.rand.Intn(24)),
+		Resolution: pick(g.rand, resolutions),
+		Source:     pick(g.rand, sources),
+		Codec:      pick(g.rand, codecs),
+		Group:      pick(g.rand, groups),
+		N:          g.n,
+	}
+
+	var buf bytes.Buffer
+	if err := g.templates[g.rand.Intn(len(g.templates))].Execute(&buf, data); err != nil {
+		return nil, errors.Wrap(err, "could not execute template")
+	}
+
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return nil, errors.New("template produced an empty release name")
+	}
+
+	size := g.settings.MinSize
+	if span := g.settings.MaxSize - g.settings.MinSize; span > 0 {
+		size += uint64(g.rand.Int63n(int64(span)))
+	}
+
+	rls := domain.NewRelease(g.indexer)
+	rls.Implementation = domain.ReleaseImplementationMock
+	rls.ParseString(name)
+	rls.Size = size
+	rls.DownloadURL = g.downloadURL(name, size)
+
+	return rls, nil
+}
+
+func (g *Generator) downloadURL(name string, size uint64) string {
+	v := url.Values{}
+	v.Set("name", name)
+	v.Set("size", strconv.FormatUint(size, 10))
+
+	return g.baseURL + "api/mock/torrent?" + v.Encode()
+}
+
+func pick(r *rand.Rand, values []string) string {
+	return values[r.Intn(len(values))]
+}
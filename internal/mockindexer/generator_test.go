@@ -0,0 +1,118 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mockindexer
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     Settings
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			settings: map[string]string{},
+			want:     Settings{Rate: 6, Templates: defaultTemplates, MinSize: 500_000_000, MaxSize: 20_000_000_000},
+		},
+		{
+			name:     "custom",
+			settings: map[string]string{"rate": "30", "templates": "A.{{ .N }}-GRP; B.{{ .N }}-GRP;", "min_size": "1GiB", "max_size": "2GiB", "url": "http://autobrr:7474/"},
+			want:     Settings{Rate: 30, Templates: []string{"A.{{ .N }}-GRP", "B.{{ .N }}-GRP"}, MinSize: 1 << 30, MaxSize: 2 << 30, URL: "http://autobrr:7474/"},
+		},
+		{
+			name:     "rate_capped",
+			settings: map[string]string{"rate": "100000"},
+			want:     Settings{Rate: maxRate, Templates: defaultTemplates, MinSize: 500_000_000, MaxSize: 20_000_000_000},
+		},
+		{name: "invalid_rate", settings: map[string]string{"rate": "fast"}, wantErr: true},
+		{name: "negative_rate", settings: map[string]string{"rate": "-1"}, wantErr: true},
+		{name: "invalid_size", settings: map[string]string{"min_size": "big"}, wantErr: true},
+		{name: "inverted_sizes", settings: map[string]string{"min_size": "2GB", "max_size": "1GB"}, wantErr: true},
+		{name: "too_large", settings: map[string]string{"max_size": "2TiB"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSettings(tt.settings)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGenerator_Next(t *testing.T) {
+	settings, err := ParseSettings(map[string]string{
+		"templates": "{{ .Title }}.S{{ .Season }}E{{ .Episode }}.{{ .Resolution }}.{{ .Source }}.{{ .Codec }}-{{ .Group }}",
+		"min_size":  "1GB",
+		"max_size":  "2GB",
+	})
+	require.NoError(t, err)
+
+	indexer := domain.IndexerMinimal{ID: 1, Name: "Mock", Identifier: "mock", IdentifierExternal: "Mock"}
+
+	g, err := NewGenerator(indexer, settings, "http://127.0.0.1:7474/autobrr", 1)
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		rls, err := g.Next()
+		require.NoError(t, err)
+
+		assert.Equal(t, indexer, rls.Indexer)
+		assert.Equal(t, domain.ReleaseImplementationMock, rls.Implementation)
+		assert.Equal(t, domain.ReleaseProtocolTorrent, rls.Protocol)
+		assert.NotEmpty(t, rls.Title)
+		assert.NotEmpty(t, rls.Resolution)
+		assert.NotZero(t, rls.Season)
+		assert.NotZero(t, rls.Episode)
+		assert.GreaterOrEqual(t, rls.Size, uint64(1_000_000_000))
+		assert.LessOrEqual(t, rls.Size, uint64(2_000_000_000))
+
+		u, err := url.Parse(rls.DownloadURL)
+		require.NoError(t, err)
+		assert.Equal(t, "/autobrr/api/mock/torrent", u.Path)
+		assert.Equal(t, rls.TorrentName, u.Query().Get("name"))
+		assert.Equal(t, strconv.FormatUint(rls.Size, 10), u.Query().Get("size"))
+	}
+}
+
+func TestGenerator_Counter(t *testing.T) {
+	settings, err := ParseSettings(map[string]string{"templates": "Mock.Release.{{ .N }}-GRP", "url": "http://autobrr.lan/"})
+	require.NoError(t, err)
+
+	g, err := NewGenerator(domain.IndexerMinimal{Identifier: "mock"}, settings, "http://127.0.0.1:7474/", 1)
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		rls, err := g.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "Mock.Release."+strconv.Itoa(i)+"-GRP", rls.TorrentName)
+		assert.Contains(t, rls.DownloadURL, "http://autobrr.lan/api/mock/torrent?")
+	}
+}
+
+func TestNewGenerator_InvalidTemplate(t *testing.T) {
+	_, err := NewGenerator(domain.IndexerMinimal{}, Settings{Templates: []string{"{{ .Title"}, MinSize: 1, MaxSize: 1}, "http://127.0.0.1:7474/", 1)
+	assert.Error(t, err)
+
+	// unknown fields fail when the release is generated
+	g, err := NewGenerator(domain.IndexerMinimal{}, Settings{Templates: []string{"{{ .Unknown }}"}, MinSize: 1, MaxSize: 1}, "http://127.0.0.1:7474/", 1)
+	require.NoError(t, err)
+
+	_, err = g.Next()
+	assert.Error(t, err)
+}
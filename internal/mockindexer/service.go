@@ -0,0 +1,231 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mockindexer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/internal/scheduler"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	announceJobName  = "mock-indexer-announce"
+	announceInterval = 5 * time.Second
+)
+
+type Service interface {
+	Start() error
+	Sync() error
+	Torrent(ctx context.Context, name string, size uint64) ([]byte, error)
+}
+
+type service struct {
+	log        zerolog.Logger
+	config     *domain.Config
+	indexerSvc indexer.Service
+	releaseSvc release.Service
+	scheduler  scheduler.Service
+
+	m        sync.Mutex
+	indexers map[string]*mockIndexer
+
+	// jobM guards scheduled, the announce job only runs while a mock indexer is enabled
+	jobM      sync.Mutex
+	scheduled bool
+}
+
+// mockIndexer is the generator state of a single enabled mock indexer
+type mockIndexer struct {
+	settings  Settings
+	generator *Generator
+	// budget accumulates the announces due since the last run, the fraction carries over
+	budget  float64
+	lastRun time.Time
+}
+
+func NewService(log logger.Logger, config *domain.Config, indexerSvc indexer.Service, releaseSvc release.Service, scheduler scheduler.Service) Service {
+	return &service{
+		log:        log.With().Str("module", "mock-indexer").Logger(),
+		config:     config,
+		indexerSvc: indexerSvc,
+		releaseSvc: releaseSvc,
+		scheduler:  scheduler,
+		indexers:   map[string]*mockIndexer{},
+	}
+}
+
+func (s *service) Start() error {
+	return s.Sync()
+}
+
+// Sync schedules the announce job when a mock indexer is enabled and removes it when none is left.
+// It is called on start and whenever an indexer is added, updated, toggled or deleted.
+func (s *service) Sync() error {
+	s.jobM.Lock()
+	defer s.jobM.Unlock()
+
+	enabled := len(s.enabledIndexers()) > 0
+	if enabled == s.scheduled {
+		return nil
+	}
+
+	if !enabled {
+		if err := s.scheduler.RemoveJobByIdentifier(announceJobName); err != nil {
+			s.log.Error().Err(err).Msg("could not remove mock indexer job")
+			return err
+		}
+
+		s.scheduled = false
+
+		s.m.Lock()
+		clear(s.indexers)
+		s.m.Unlock()
+
+		s.log.Debug().Msg("no mock indexer enabled, stopped announcing")
+		return nil
+	}
+
+	job := &AnnounceJob{
+		Name: announceJobName,
+		Log:  s.log.With().Str("job", announceJobName).Logger(),
+		svc:  s,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(job, announceInterval, job.Name); err != nil {
+		s.log.Error().Err(err).Msg("could not schedule mock indexer job")
+		return err
+	}
+
+	s.scheduled = true
+
+	s.log.Debug().Msgf("mock indexer enabled, announcing every %s", announceInterval)
+
+	return nil
+}
+
+// Torrent returns a fake torrent file. It is only served while a mock indexer is enabled.
+func (s *service) Torrent(ctx context.Context, name string, size uint64) ([]byte, error) {
+	if len(s.enabledIndexers()) == 0 {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return Torrent(name, size)
+}
+
+func (s *service) enabledIndexers() []*domain.IndexerDefinition {
+	definitions, err := s.indexerSvc.GetAll()
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not get indexers")
+		return nil
+	}
+
+	var res []*domain.IndexerDefinition
+	for _, def := range definitions {
+		if def.Enabled && def.Implementation == string(domain.IndexerImplementationMock) {
+			res = append(res, def)
+		}
+	}
+
+	return res
+}
+
+// announce generates the releases that are due for every enabled mock indexer and sends them to the release pipeline
+func (s *service) announce(now time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	active := map[string]struct{}{}
+
+	for _, def := range s.enabledIndexers() {
+		l := s.log.With().Str("indexer", def.Identifier).Logger()
+
+		settings, err := ParseSettings(def.SettingsMap)
+		if err != nil {
+			l.Error().Err(err).Msg("invalid mock indexer settings")
+			continue
+		}
+
+		active[def.Identifier] = struct{}{}
+
+		state, ok := s.indexers[def.Identifier]
+		if !ok || !reflect.DeepEqual(state.settings, settings) {
+			generator, err := NewGenerator(domain.IndexerMinimal{
+				ID:                 def.ID,
+				Name:               def.Name,
+				Identifier:         def.Identifier,
+				IdentifierExternal: def.IdentifierExternal,
+			}, settings, s.baseURL(), now.UnixNano())
+			if err != nil {
+				l.Error().Err(err).Msg("could not setup mock indexer")
+				continue
+			}
+
+			state = &mockIndexer{settings: settings, generator: generator, lastRun: now}
+			s.indexers[def.Identifier] = state
+		}
+
+		state.budget += settings.Rate * now.Sub(state.lastRun).Minutes()
+		state.lastRun = now
+
+		// never catch up more than a minute worth of announces, eg. after the system was suspended
+		state.budget = min(state.budget, max(settings.Rate, 1))
+
+		for ; state.budget >= 1; state.budget-- {
+			rls, err := state.generator.Next()
+			if err != nil {
+				l.Error().Err(err).Msg("could not generate release")
+				break
+			}
+
+			l.Trace().Msgf("announce: %s", rls.TorrentName)
+
+			go s.releaseSvc.Process(rls)
+		}
+	}
+
+	for identifier := range s.indexers {
+		if _, ok := active[identifier]; !ok {
+			delete(s.indexers, identifier)
+		}
+	}
+}
+
+// baseURL is the url the download clients fetch the fake torrents from when the indexer has no url set
+func (s *service) baseURL() string {
+	host := s.config.Host
+	switch host {
+	case "", "0.0.0.0", "::", "[::]":
+		host = "127.0.0.1"
+	}
+
+	baseURL := s.config.BaseURL
+	if !strings.HasPrefix(baseURL, "/") {
+		baseURL = "/" + baseURL
+	}
+
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(s.config.Port)), baseURL)
+}
+
+type AnnounceJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+func (j *AnnounceJob) Run() {
+	j.svc.announce(time.Now())
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mockindexer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/internal/scheduler"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockScheduler only records the scheduled jobs, calling anything else panics
+type mockScheduler struct {
+	scheduler.Service
+	jobs map[string]cron.Job
+}
+
+func (s *mockScheduler) ScheduleJob(job cron.Job, interval time.Duration, identifier string) (int, error) {
+	s.jobs[identifier] = job
+	return len(s.jobs), nil
+}
+
+func (s *mockScheduler) RemoveJobByIdentifier(id string) error {
+	delete(s.jobs, id)
+	return nil
+}
+
+// mockIndexerService returns the configured definitions, calling anything else panics
+type mockIndexerService struct {
+	indexer.Service
+	mu          sync.Mutex
+	definitions []*domain.IndexerDefinition
+}
+
+func (s *mockIndexerService) GetAll() ([]*domain.IndexerDefinition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.definitions, nil
+}
+
+func (s *mockIndexerService) GetMappedDefinitionByName(name string) (*domain.IndexerDefinition, error) {
+	return nil, domain.ErrRecordNotFound
+}
+
+func (s *mockIndexerService) setEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, def := range s.definitions {
+		def.Enabled = enabled
+	}
+}
+
+// mockFilterRepo returns the same filters for every indexer, calling anything else panics
+type mockFilterRepo struct {
+	domain.FilterRepo
+	filters []*domain.Filter
+}
+
+func (r *mockFilterRepo) FindByIndexerIdentifier(ctx context.Context, indexer string) ([]*domain.Filter, error) {
+	// every release gets its own copy since the rejections are stored on the filter
+	filters := make([]*domain.Filter, 0, len(r.filters))
+	for _, f := range r.filters {
+		f := *f
+		filters = append(filters, &f)
+	}
+	return filters, nil
+}
+
+func (r *mockFilterRepo) FindExternalFiltersByID(ctx context.Context, filterID int) ([]domain.FilterExternal, error) {
+	return nil, nil
+}
+
+// mockReleaseRepo only implements storing releases and statuses, calling anything else panics
+type mockReleaseRepo struct {
+	domain.ReleaseRepo
+}
+
+func (r *mockReleaseRepo) Store(ctx context.Context, release *domain.Release) error {
+	release.ID = 1
+	return nil
+}
+
+func (r *mockReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *domain.ReleaseActionStatus) error {
+	return nil
+}
+
+// mockActionService records the pushed releases, calling anything else panics
+type mockActionService struct {
+	action.Service
+	mu     sync.Mutex
+	action *domain.Action
+	pushed []string
+}
+
+func (s *mockActionService) FindByFilterID(ctx context.Context, filterID int, active *bool, withClient bool) ([]*domain.Action, error) {
+	return []*domain.Action{s.action}, nil
+}
+
+func (s *mockActionService) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pushed = append(s.pushed, release.TorrentName)
+	return nil, nil
+}
+
+func (s *mockActionService) pushes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pushed := append([]string(nil), s.pushed...)
+	sort.Strings(pushed)
+	return pushed
+}
+
+func mockDefinition(id int, identifier string, templates string) *domain.IndexerDefinition {
+	return &domain.IndexerDefinition{
+		ID:             id,
+		Name:           identifier,
+		Identifier:     identifier,
+		Implementation: string(domain.IndexerImplementationMock),
+		Enabled:        true,
+		SettingsMap:    map[string]string{"rate": "3", "templates": templates},
+	}
+}
+
+func TestService_Sync(t *testing.T) {
+	sched := &mockScheduler{jobs: map[string]cron.Job{}}
+	indexerSvc := &mockIndexerService{definitions: []*domain.IndexerDefinition{mockDefinition(1, "mock", "Mock.{{ .N }}-GRP")}}
+	indexerSvc.setEnabled(false)
+
+	s := NewService(logger.Mock(), &domain.Config{Host: "127.0.0.1", Port: 7474}, indexerSvc, nil, sched).(*service)
+
+	// nothing is scheduled without an enabled mock indexer
+	require.NoError(t, s.Start())
+	assert.Empty(t, sched.jobs)
+
+	indexerSvc.setEnabled(true)
+	require.NoError(t, s.Sync())
+	assert.Contains(t, sched.jobs, announceJobName)
+
+	indexerSvc.setEnabled(false)
+	require.NoError(t, s.Sync())
+	assert.Empty(t, sched.jobs)
+}
+
+// TestService_Announce runs the announces of the mock indexers through the release and filter services
+// and checks that only the matching releases are pushed by the action
+func TestService_Announce(t *testing.T) {
+	log := logger.Mock()
+	config := &domain.Config{Host: "127.0.0.1", Port: 7474}
+
+	indexerSvc := &mockIndexerService{
+		definitions: []*domain.IndexerDefinition{
+			mockDefinition(1, "mock", "Match.Release.{{ .N }}.1080p.WEB-DL.x264-GRP"),
+			mockDefinition(2, "mock-other", "Other.Release.{{ .N }}.1080p.WEB-DL.x264-GRP"),
+		},
+	}
+
+	filterRepo := &mockFilterRepo{
+		filters: []*domain.Filter{
+			{ID: 1, Name: "match", Enabled: true, MatchReleases: "Match.Release.*"},
+		},
+	}
+
+	actionSvc := &mockActionService{
+		action: &domain.Action{ID: 1, Name: "push", Type: domain.ActionTypeTest, Enabled: true, FilterID: 1},
+	}
+
+	filterSvc := filter.NewService(log, config, filterRepo, nil, actionSvc, nil, &mockReleaseRepo{}, nil, indexerSvc, nil, nil)
+	releaseSvc := release.NewService(log, config, &mockReleaseRepo{}, nil, nil, nil, actionSvc, filterSvc, indexerSvc, nil, nil, nil)
+
+	sched := &mockScheduler{jobs: map[string]cron.Job{}}
+	s := NewService(log, config, indexerSvc, releaseSvc, sched).(*service)
+	s.log = zerolog.Nop()
+
+	require.NoError(t, s.Start())
+	require.Contains(t, sched.jobs, announceJobName)
+
+	now := time.Now()
+
+	// the first run sets up the generators, a minute later 3 announces per indexer are due
+	s.announce(now)
+	s.announce(now.Add(time.Minute))
+
+	want := []string{
+		"Match.Release.1.1080p.WEB-DL.x264-GRP",
+		"Match.Release.2.1080p.WEB-DL.x264-GRP",
+		"Match.Release.3.1080p.WEB-DL.x264-GRP",
+	}
+
+	require.Eventually(t, func() bool {
+		return len(actionSvc.pushes()) >= len(want)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// give stray releases of the other indexer a chance to show up
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, want, actionSvc.pushes())
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mockindexer
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+const (
+	minPieceLength = 256 << 10
+	maxPieces      = 2000
+
+	// maxTorrentSize keeps the piece list of a fake torrent at a sane size
+	maxTorrentSize = 1 << 40
+)
+
+// Torrent builds a fake single file torrent of size bytes. The piece hashes are derived from the name,
+// so the same name and size always give the same info hash. The content does not exist anywhere,
+// clients will add the torrent but never complete it.
+func Torrent(name string, size uint64) ([]byte, error) {
+	if name == "" {
+		return nil, errors.New("torrent name is required")
+	}
+
+	if size == 0 || size > maxTorrentSize {
+		return nil, errors.New("invalid torrent size: %d", size)
+	}
+
+	pieceLength := uint64(minPieceLength)
+	for size/pieceLength > maxPieces {
+		pieceLength *= 2
+	}
+
+	numPieces := (size + pieceLength - 1) / pieceLength
+
+	seed := sha1.Sum([]byte(name))
+	pieces := make([]byte, 0, numPieces*sha1.Size)
+
+	buf := make([]byte, len(seed)+8)
+	copy(buf, seed[:])
+
+	for i := uint64(0); i < numPieces; i++ {
+		binary.BigEndian.PutUint64(buf[len(seed):], i)
+		hash := sha1.Sum(buf)
+		pieces = append(pieces, hash[:]...)
+	}
+
+	private := true
+	info := metainfo.Info{
+		Name:        name,
+		PieceLength: int64(pieceLength),
+		Pieces:      pieces,
+		Length:      int64(size),
+		Private:     &private,
+		Source:      "autobrr-mock",
+	}
+
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode torrent info")
+	}
+
+	mi := metainfo.MetaInfo{
+		InfoBytes: infoBytes,
+		Announce:  "http://127.0.0.1:1/announce",
+		CreatedBy: "autobrr",
+	}
+
+	var out bytes.Buffer
+	if err := mi.Write(&out); err != nil {
+		return nil, errors.Wrap(err, "could not encode torrent")
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mockindexer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTorrent(t *testing.T) {
+	tests := []struct {
+		name            string
+		size            uint64
+		wantPieceLength int64
+		wantPieces      int
+	}{
+		{name: "small", size: 1000, wantPieceLength: 256 << 10, wantPieces: 1},
+		{name: "exact_piece", size: 512 << 10, wantPieceLength: 256 << 10, wantPieces: 2},
+		{name: "large", size: 20_000_000_000, wantPieceLength: 16 << 20, wantPieces: 1193},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Torrent("Mock.Show.S01E01.1080p.WEB-DL.x264-MOCK", tt.size)
+			require.NoError(t, err)
+
+			mi, err := metainfo.Load(bytes.NewReader(data))
+			require.NoError(t, err)
+
+			info, err := mi.UnmarshalInfo()
+			require.NoError(t, err)
+
+			assert.Equal(t, "Mock.Show.S01E01.1080p.WEB-DL.x264-MOCK", info.Name)
+			assert.Equal(t, int64(tt.size), info.TotalLength())
+			assert.Equal(t, tt.wantPieceLength, info.PieceLength)
+			assert.Equal(t, tt.wantPieces, info.NumPieces())
+			require.NotNil(t, info.Private)
+			assert.True(t, *info.Private)
+		})
+	}
+}
+
+func TestTorrent_Deterministic(t *testing.T) {
+	a, err := Torrent("Mock.Release-GRP", 1<<30)
+	require.NoError(t, err)
+
+	b, err := Torrent("Mock.Release-GRP", 1<<30)
+	require.NoError(t, err)
+
+	c, err := Torrent("Other.Release-GRP", 1<<30)
+	require.NoError(t, err)
+
+	hash := func(data []byte) metainfo.Hash {
+		mi, err := metainfo.Load(bytes.NewReader(data))
+		require.NoError(t, err)
+		return mi.HashInfoBytes()
+	}
+
+	assert.Equal(t, hash(a), hash(b))
+	assert.NotEqual(t, hash(a), hash(c))
+}
+
+func TestTorrent_Invalid(t *testing.T) {
+	_, err := Torrent("", 1000)
+	assert.Error(t, err)
+
+	_, err = Torrent("Mock.Release-GRP", 0)
+	assert.Error(t, err)
+
+	_, err = Torrent("Mock.Release-GRP", maxTorrentSize+1)
+	assert.Error(t, err)
+}
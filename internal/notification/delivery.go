@@ -0,0 +1,218 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// deliveryRetryBatch is the number of deliveries retried per run of the retry job
+const deliveryRetryBatch = 50
+
+// deliveryDigestBatch is the number of deferred deliveries sent as digests per run of the retry job
+const deliveryDigestBatch = 500
+
+// queueDelivery stores the failed notification so the retry job sends it again after a backoff
+func (s *service) queueDelivery(notificationID int, event domain.NotificationEvent, payload domain.NotificationPayload, sendErr error, now time.Time) {
+	delivery := &domain.NotificationDelivery{
+		NotificationID: notificationID,
+		Event:          event,
+		Payload:        payload,
+	}
+	delivery.AttemptFailed(sendErr, now)
+
+	if err := s.deliveryRepo.Store(context.Background(), delivery); err != nil {
+		s.log.Error().Err(err).Msgf("could not queue notification %d for %v", notificationID, string(event))
+	}
+}
+
+// deferDelivery stores the notification held back by the quiet hours of the agent, to be sent in a digest when they end
+func (s *service) deferDelivery(notificationID int, event domain.NotificationEvent, payload domain.NotificationPayload, until time.Time) {
+	delivery := &domain.NotificationDelivery{
+		NotificationID: notificationID,
+		Event:          event,
+		Payload:        payload,
+		Status:         domain.NotificationDeliveryStatusDeferred,
+		NextAttemptAt:  until,
+	}
+
+	if err := s.deliveryRepo.Store(context.Background(), delivery); err != nil {
+		s.log.Error().Err(err).Msgf("could not defer notification %d for %v", notificationID, string(event))
+	}
+}
+
+func (s *service) FindDeliveries(ctx context.Context, params domain.NotificationDeliveryQueryParams) ([]domain.NotificationDelivery, error) {
+	deliveries, err := s.deliveryRepo.Find(ctx, params)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find notification deliveries with params: %+v", params)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RetryDeliveries sends the queued notifications which are due, sent ones are removed from the queue
+func (s *service) RetryDeliveries(ctx context.Context) error {
+	now := time.Now()
+
+	deliveries, err := s.deliveryRepo.FindDue(ctx, domain.NotificationDeliveryStatusPending, now, deliveryRetryBatch)
+	if err != nil {
+		return errors.Wrap(err, "could not find due notification deliveries")
+	}
+
+	for _, delivery := range deliveries {
+		if err := s.attemptDelivery(ctx, &delivery, now); err != nil {
+			if delivery.Status == domain.NotificationDeliveryStatusFailed {
+				s.log.Error().Err(err).Msgf("notification %d for %v failed after %d attempts", delivery.NotificationID, string(delivery.Event), delivery.Attempts)
+				continue
+			}
+
+			s.log.Warn().Err(err).Msgf("could not send notification %d for %v, next attempt at %s", delivery.NotificationID, string(delivery.Event), delivery.NextAttemptAt.Format(time.RFC3339))
+		}
+	}
+
+	return s.sendDigests(ctx, now)
+}
+
+// sendDigests sends the deferred deliveries of agents whose quiet hours ended, one digest per agent
+func (s *service) sendDigests(ctx context.Context, now time.Time) error {
+	deferred, err := s.deliveryRepo.FindDue(ctx, domain.NotificationDeliveryStatusDeferred, now, deliveryDigestBatch)
+	if err != nil {
+		return errors.Wrap(err, "could not find deferred notification deliveries")
+	}
+
+	digests := make(map[int][]domain.NotificationDelivery)
+	for _, delivery := range deferred {
+		digests[delivery.NotificationID] = append(digests[delivery.NotificationID], delivery)
+	}
+
+	for notificationID, deliveries := range digests {
+		if err := s.sendDigest(ctx, notificationID, deliveries, now); err != nil {
+			s.log.Warn().Err(err).Msgf("could not send digest of %d notifications to %d, queued for retry", len(deliveries), notificationID)
+		}
+	}
+
+	return nil
+}
+
+// sendDigest sends the deliveries as one notification, a single delivery is sent as is.
+// When the digest can't be sent the deliveries are retried one by one.
+func (s *service) sendDigest(ctx context.Context, notificationID int, deliveries []domain.NotificationDelivery, now time.Time) error {
+	if len(deliveries) == 1 {
+		return s.attemptDelivery(ctx, &deliveries[0], now)
+	}
+
+	sendErr := errors.New("notification agent %d is disabled", notificationID)
+
+	if sender, ok := s.senders[notificationID]; ok {
+		sendErr = sender.Send(domain.NotificationEventDigest, buildDigest(deliveries, now))
+	}
+
+	for _, delivery := range deliveries {
+		if sendErr == nil {
+			if err := s.deliveryRepo.Delete(ctx, delivery.ID); err != nil {
+				s.log.Error().Err(err).Msgf("could not remove sent notification delivery: %d", delivery.ID)
+			}
+			continue
+		}
+
+		delivery.AttemptFailed(sendErr, now)
+
+		if err := s.deliveryRepo.Update(ctx, &delivery); err != nil {
+			s.log.Error().Err(err).Msgf("could not update notification delivery: %d", delivery.ID)
+		}
+	}
+
+	return sendErr
+}
+
+// buildDigest lists the deferred notifications in the order they happened
+func buildDigest(deliveries []domain.NotificationDelivery, now time.Time) domain.NotificationPayload {
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+
+	lines := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		p := delivery.Payload
+
+		title := buildTitle(delivery.Event, p)
+
+		var detail string
+		switch {
+		case p.Body != "":
+			detail = p.Body
+		case p.ReleaseName != "":
+			detail = p.ReleaseName
+		case p.Message != "":
+			detail = p.Message
+		}
+
+		line := title
+		if detail != "" {
+			line = fmt.Sprintf("%s: %s", title, detail)
+		}
+
+		lines = append(lines, "- "+strings.ReplaceAll(line, "\n", " "))
+	}
+
+	return domain.NotificationPayload{
+		Event:     domain.NotificationEventDigest,
+		Subject:   fmt.Sprintf("%d notifications during quiet hours", len(deliveries)),
+		Message:   strings.Join(lines, "\n"),
+		Timestamp: now,
+	}
+}
+
+// ResendDelivery sends the queued notification right away, also when it has failed
+func (s *service) ResendDelivery(ctx context.Context, id int64) error {
+	delivery, err := s.deliveryRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.attemptDelivery(ctx, delivery, time.Now())
+}
+
+func (s *service) DeleteDelivery(ctx context.Context, id int64) error {
+	if err := s.deliveryRepo.Delete(ctx, id); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete notification delivery: %d", id)
+		return err
+	}
+
+	return nil
+}
+
+// attemptDelivery sends the delivery with the sender of its notification agent,
+// removes it from the queue when sent and stores the failed attempt otherwise
+func (s *service) attemptDelivery(ctx context.Context, delivery *domain.NotificationDelivery, now time.Time) error {
+	sendErr := errors.New("notification agent %d is disabled", delivery.NotificationID)
+
+	if sender, ok := s.senders[delivery.NotificationID]; ok {
+		sendErr = sender.Send(delivery.Event, delivery.Payload)
+	}
+
+	if sendErr == nil {
+		if err := s.deliveryRepo.Delete(ctx, delivery.ID); err != nil {
+			return errors.Wrap(err, "could not remove sent notification delivery: %d", delivery.ID)
+		}
+
+		return nil
+	}
+
+	delivery.AttemptFailed(sendErr, now)
+
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		return errors.Wrap(err, "could not update notification delivery: %d", delivery.ID)
+	}
+
+	return sendErr
+}
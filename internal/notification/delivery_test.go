@@ -0,0 +1,152 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDeliveryRepo struct {
+	domain.NotificationDeliveryRepo
+
+	deliveries map[int64]domain.NotificationDelivery
+}
+
+func (r *mockDeliveryRepo) FindDue(_ context.Context, status domain.NotificationDeliveryStatus, now time.Time, _ uint64) ([]domain.NotificationDelivery, error) {
+	var due []domain.NotificationDelivery
+	for _, d := range r.deliveries {
+		if d.Status == status && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (r *mockDeliveryRepo) FindByID(_ context.Context, id int64) (*domain.NotificationDelivery, error) {
+	d, ok := r.deliveries[id]
+	if !ok {
+		return nil, domain.ErrRecordNotFound
+	}
+	return &d, nil
+}
+
+func (r *mockDeliveryRepo) Store(_ context.Context, d *domain.NotificationDelivery) error {
+	d.ID = int64(len(r.deliveries) + 1)
+	r.deliveries[d.ID] = *d
+	return nil
+}
+
+func (r *mockDeliveryRepo) Update(_ context.Context, d *domain.NotificationDelivery) error {
+	r.deliveries[d.ID] = *d
+	return nil
+}
+
+func (r *mockDeliveryRepo) Delete(_ context.Context, id int64) error {
+	delete(r.deliveries, id)
+	return nil
+}
+
+type mockSender struct {
+	err      error
+	sent     []domain.NotificationEvent
+	payloads []domain.NotificationPayload
+}
+
+func (s *mockSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, event)
+	s.payloads = append(s.payloads, payload)
+	return nil
+}
+
+func (s *mockSender) CanSend(domain.NotificationEvent) bool { return true }
+
+func (s *mockSender) Name() string { return "mock" }
+
+func Test_service_RetryDeliveries(t *testing.T) {
+	repo := &mockDeliveryRepo{deliveries: map[int64]domain.NotificationDelivery{}}
+	sender := &mockSender{err: errors.New("unexpected status: 503")}
+
+	s := &service{
+		log:          zerolog.Nop(),
+		deliveryRepo: repo,
+		senders:      map[int]domain.NotificationSender{1: sender},
+	}
+
+	now := time.Now()
+
+	s.queueDelivery(1, domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "release"}, sender.err, now.Add(-time.Hour))
+	s.queueDelivery(2, domain.NotificationEventPushError, domain.NotificationPayload{ReleaseName: "release"}, sender.err, now.Add(-time.Hour))
+	require.Len(t, repo.deliveries, 2)
+
+	// the provider is still down
+	require.NoError(t, s.RetryDeliveries(context.Background()))
+	assert.Equal(t, 2, repo.deliveries[1].Attempts)
+	assert.Equal(t, "unexpected status: 503", repo.deliveries[1].LastError)
+	assert.True(t, repo.deliveries[1].NextAttemptAt.After(now))
+
+	// the agent of the second delivery is disabled
+	assert.Equal(t, "notification agent 2 is disabled", repo.deliveries[2].LastError)
+
+	// resent once the provider is back, sent deliveries leave the queue
+	sender.err = nil
+	require.NoError(t, s.ResendDelivery(context.Background(), 1))
+	assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventPushApproved}, sender.sent)
+	assert.NotContains(t, repo.deliveries, int64(1))
+
+	assert.ErrorIs(t, s.ResendDelivery(context.Background(), 1), domain.ErrRecordNotFound)
+}
+
+func Test_service_sendDigests(t *testing.T) {
+	repo := &mockDeliveryRepo{deliveries: map[int64]domain.NotificationDelivery{}}
+	sender := &mockSender{err: errors.New("unexpected status: 503")}
+	single := &mockSender{}
+
+	s := &service{
+		log:          zerolog.Nop(),
+		deliveryRepo: repo,
+		senders:      map[int]domain.NotificationSender{1: sender, 2: single},
+	}
+
+	now := time.Now()
+
+	s.deferDelivery(1, domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Show.S01E01"}, now.Add(-time.Minute))
+	s.deferDelivery(1, domain.NotificationEventIRCDisconnected, domain.NotificationPayload{Subject: "IRC Disconnected", Message: "Network: Mock"}, now.Add(-time.Minute))
+	s.deferDelivery(1, domain.NotificationEventPushRejected, domain.NotificationPayload{ReleaseName: "Show.S01E02"}, now.Add(time.Hour))
+	s.deferDelivery(2, domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Show.S01E03"}, now.Add(-time.Minute))
+	require.Len(t, repo.deliveries, 4)
+	assert.Equal(t, domain.NotificationDeliveryStatusDeferred, repo.deliveries[1].Status)
+
+	// deferred deliveries are not retried as pending
+	require.NoError(t, s.RetryDeliveries(context.Background()))
+
+	// a single deferred delivery is sent as is
+	assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventPushApproved}, single.sent)
+	assert.NotContains(t, repo.deliveries, int64(4))
+
+	// a failed digest is retried one by one
+	assert.Equal(t, domain.NotificationDeliveryStatusPending, repo.deliveries[1].Status)
+	assert.Equal(t, domain.NotificationDeliveryStatusPending, repo.deliveries[2].Status)
+	assert.Equal(t, domain.NotificationDeliveryStatusDeferred, repo.deliveries[3].Status)
+
+	first, second := repo.deliveries[1], repo.deliveries[2]
+	first.CreatedAt = now.Add(-2 * time.Hour)
+	second.CreatedAt = now.Add(-time.Hour)
+
+	digest := buildDigest([]domain.NotificationDelivery{second, first}, now)
+	assert.Equal(t, domain.NotificationEventDigest, digest.Event)
+	assert.Equal(t, "2 notifications during quiet hours", digest.Subject)
+	assert.Equal(t, "- Push Approved: Show.S01E01\n- IRC Disconnected: Network: Mock", digest.Message)
+}
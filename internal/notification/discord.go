@@ -151,6 +151,16 @@ func (a *discordSender) buildEmbed(event domain.NotificationEvent, payload domai
 		color = RED
 	case domain.NotificationEventIRCReconnected:
 		color = GREEN
+	case domain.NotificationEventIRCChannelParted, domain.NotificationEventIRCChannelKicked:
+		color = RED
+	case domain.NotificationEventFeedError, domain.NotificationEventDownloadClientUnhealthy:
+		color = RED
+	case domain.NotificationEventDelayedReleaseExpired:
+		color = LIGHT_BLUE
+	case domain.NotificationEventSizeMismatch:
+		color = RED
+	case domain.NotificationEventDownloadComplete:
+		color = GREEN
 	case domain.NotificationEventTest:
 		color = LIGHT_BLUE
 	}
@@ -251,5 +261,12 @@ func (a *discordSender) buildEmbed(event domain.NotificationEvent, payload domai
 		embed.Description = payload.Message
 	}
 
+	if payload.Title != "" {
+		embed.Title = payload.Title
+	}
+	if payload.Body != "" {
+		embed.Description = payload.Body
+	}
+
 	return embed
 }
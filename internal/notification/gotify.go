@@ -5,10 +5,10 @@ package notification
 
 import (
 	"bufio"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,9 +19,14 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// gotifyPriorityError is the priority of events which need attention,
+// gotify clients show priorities of 8 and above as high priority
+const gotifyPriorityError = 8
+
 type gotifyMessage struct {
-	Message string `json:"message"`
-	Title   string `json:"title"`
+	Message  string `json:"message"`
+	Title    string `json:"title"`
+	Priority int32  `json:"priority"`
 }
 
 type gotifySender struct {
@@ -50,21 +55,29 @@ func NewGotifySender(log zerolog.Logger, settings domain.Notification) domain.No
 
 func (s *gotifySender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
 	m := gotifyMessage{
-		Message: s.builder.BuildBody(payload),
-		Title:   BuildTitle(event),
+		Message:  s.builder.BuildBody(payload),
+		Title:    buildTitle(event, payload),
+		Priority: s.priority(event),
 	}
 
 	data := url.Values{}
 	data.Set("message", m.Message)
 	data.Set("title", m.Title)
+	data.Set("priority", strconv.Itoa(int(m.Priority)))
+
+	messageURL, err := url.JoinPath(s.Settings.Host, "message")
+	if err != nil {
+		return errors.Wrap(err, "could not parse gotify url: %s", s.Settings.Host)
+	}
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%v/message?token=%v", s.Settings.Host, s.Settings.Token), strings.NewReader(data.Encode()))
+	req, err := http.NewRequest(http.MethodPost, messageURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return errors.Wrap(err, "could not create request for event: %v payload: %v", event, payload)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("X-Gotify-Key", s.Settings.Token)
 
 	res, err := s.httpClient.Do(req)
 	if err != nil {
@@ -89,6 +102,18 @@ func (s *gotifySender) Send(event domain.NotificationEvent, payload domain.Notif
 	return nil
 }
 
+// priority maps the event to a gotify priority, errors and disconnects are raised to a high priority
+// and the other events use the priority of the settings, from 0 (min) to 10 (max)
+func (s *gotifySender) priority(event domain.NotificationEvent) int32 {
+	switch event {
+	case domain.NotificationEventPushError, domain.NotificationEventIRCDisconnected, domain.NotificationEventIRCChannelKicked,
+		domain.NotificationEventFeedError, domain.NotificationEventDownloadClientUnhealthy:
+		return max(s.Settings.Priority, gotifyPriorityError)
+	default:
+		return s.Settings.Priority
+	}
+}
+
 func (s *gotifySender) CanSend(event domain.NotificationEvent) bool {
 	if s.isEnabled() && s.isEnabledEvent(event) {
 		return true
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_gotifySender_Send(t *testing.T) {
+	type request struct {
+		path     string
+		token    string
+		title    string
+		priority string
+	}
+
+	var got []request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Header.Get("X-Gotify-Key") != "app-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"Unauthorized"}`))
+			return
+		}
+
+		got = append(got, request{
+			path:     r.URL.Path,
+			token:    r.Header.Get("X-Gotify-Key"),
+			title:    r.PostForm.Get("title"),
+			priority: r.PostForm.Get("priority"),
+		})
+	}))
+	defer srv.Close()
+
+	sender := NewGotifySender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Host:     srv.URL + "/",
+		Token:    "app-token",
+		Priority: 4,
+		Events:   []string{string(domain.NotificationEventPushApproved), string(domain.NotificationEventPushError)},
+	})
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+	assert.False(t, sender.CanSend(domain.NotificationEventPushRejected))
+
+	assert.NoError(t, sender.Send(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Artist - Album"}))
+	assert.NoError(t, sender.Send(domain.NotificationEventPushError, domain.NotificationPayload{ReleaseName: "Artist - Album"}))
+
+	assert.Equal(t, []request{
+		{path: "/message", token: "app-token", title: "Push Approved", priority: "4"},
+		{path: "/message", token: "app-token", title: "Push Error", priority: "8"},
+	}, got)
+
+	unauthorized := NewGotifySender(zerolog.Nop(), domain.Notification{Enabled: true, Host: srv.URL, Token: "wrong"})
+	assert.EqualError(t, unauthorized.Send(domain.NotificationEventTest, domain.NotificationPayload{}), `unexpected status: 401 body: {"error":"Unauthorized"}`)
+}
@@ -57,7 +57,7 @@ func NewLunaSeaSender(log zerolog.Logger, settings domain.Notification) domain.N
 
 func (s *lunaSeaSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
 	m := LunaSeaMessage{
-		Title: BuildTitle(event),
+		Title: buildTitle(event, payload),
 		Body:  s.builder.BuildBody(payload),
 		Image: defaultImageURL,
 	}
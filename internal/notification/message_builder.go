@@ -25,6 +25,13 @@ type MessageBuilderPlainText struct{}
 
 // BuildBody constructs the body of the notification message.
 func (b *MessageBuilderPlainText) BuildBody(payload domain.NotificationPayload) string {
+	if payload.Body != "" {
+		return formatMessageContent([]ConditionMessagePart{
+			{payload.Sender != "", "%v\n", []interface{}{payload.Sender}},
+			{true, "%v", []interface{}{payload.Body}},
+		})
+	}
+
 	messageParts := []ConditionMessagePart{
 		{payload.Sender != "", "%v\n", []interface{}{payload.Sender}},
 		{payload.Subject != "" && payload.Message != "", "%v\n%v", []interface{}{payload.Subject, payload.Message}},
@@ -45,6 +52,13 @@ func (b *MessageBuilderPlainText) BuildBody(payload domain.NotificationPayload)
 type MessageBuilderHTML struct{}
 
 func (b *MessageBuilderHTML) BuildBody(payload domain.NotificationPayload) string {
+	if payload.Body != "" {
+		return formatMessageContent([]ConditionMessagePart{
+			{payload.Sender != "", "<b>%v</b>\n", []interface{}{html.EscapeString(payload.Sender)}},
+			{true, "%v", []interface{}{html.EscapeString(payload.Body)}},
+		})
+	}
+
 	messageParts := []ConditionMessagePart{
 		{payload.Sender != "", "<b>%v</b>\n", []interface{}{html.EscapeString(payload.Sender)}},
 		{payload.Subject != "" && payload.Message != "", "<b>%v</b> %v\n", []interface{}{html.EscapeString(payload.Subject), html.EscapeString(payload.Message)}},
@@ -71,16 +85,33 @@ func formatMessageContent(messageParts []ConditionMessagePart) string {
 	return builder.String()
 }
 
+// buildTitle is the title rendered from the message template, or the title of the event
+func buildTitle(event domain.NotificationEvent, payload domain.NotificationPayload) string {
+	if payload.Title != "" {
+		return payload.Title
+	}
+
+	return BuildTitle(event)
+}
+
 // BuildTitle constructs the title of the notification message.
 func BuildTitle(event domain.NotificationEvent) string {
 	titles := map[domain.NotificationEvent]string{
-		domain.NotificationEventAppUpdateAvailable: "Autobrr update available",
-		domain.NotificationEventPushApproved:       "Push Approved",
-		domain.NotificationEventPushRejected:       "Push Rejected",
-		domain.NotificationEventPushError:          "Push Error",
-		domain.NotificationEventIRCDisconnected:    "IRC Disconnected",
-		domain.NotificationEventIRCReconnected:     "IRC Reconnected",
-		domain.NotificationEventTest:               "Test",
+		domain.NotificationEventAppUpdateAvailable:      "Autobrr update available",
+		domain.NotificationEventPushApproved:            "Push Approved",
+		domain.NotificationEventPushRejected:            "Push Rejected",
+		domain.NotificationEventPushError:               "Push Error",
+		domain.NotificationEventIRCDisconnected:         "IRC Disconnected",
+		domain.NotificationEventIRCReconnected:          "IRC Reconnected",
+		domain.NotificationEventIRCChannelParted:        "IRC Channel Parted",
+		domain.NotificationEventIRCChannelKicked:        "IRC Channel Kicked",
+		domain.NotificationEventFeedError:               "Feed Error",
+		domain.NotificationEventDownloadClientUnhealthy: "Download Client Unhealthy",
+		domain.NotificationEventDelayedReleaseExpired:   "Delayed Release Expired",
+		domain.NotificationEventSizeMismatch:            "Size Mismatch",
+		domain.NotificationEventDownloadComplete:        "Download Complete",
+		domain.NotificationEventDigest:                  "Notification Digest",
+		domain.NotificationEventTest:                    "Test",
 	}
 
 	if title, ok := titles[event]; ok {
@@ -60,6 +60,19 @@ func TestNotificationBuilderPlainText_BuildBody(t *testing.T) {
 			}},
 			want: "New release: Movie 2024 UHD BluRay 2160p DTS-HD MA 5.1 DV HEVC HYBRID REMUX-GROUP\nStatus: Rejected\nIndexer: mock\nFilter: test\nAction: RADARR: mock\nClient: mock\nRejections: Item already exists\n",
 		},
+		{
+			name: "build body from message template",
+			args: args{payload: domain.NotificationPayload{
+				Event:       domain.NotificationEventPushApproved,
+				ReleaseName: "Movie 2024 UHD BluRay 2160p DTS-HD MA 5.1 DV HEVC HYBRID REMUX-GROUP",
+				Filter:      "test",
+				Status:      domain.ReleasePushStatusApproved,
+				Sender:      "autobrr",
+				Title:       "Grabbed Movie",
+				Body:        "Movie (2024) 2160p from mock",
+			}},
+			want: "autobrr\nMovie (2024) 2160p from mock",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
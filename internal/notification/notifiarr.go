@@ -148,6 +148,12 @@ func (s *notifiarrSender) buildMessage(payload domain.NotificationPayload) notif
 		m.Subject = payload.Subject
 		m.Message = payload.Message
 	}
+	if payload.Title != "" {
+		m.Subject = payload.Title
+	}
+	if payload.Body != "" {
+		m.Message = payload.Body
+	}
 	if payload.ReleaseName != "" {
 		m.ReleaseName = &payload.ReleaseName
 	}
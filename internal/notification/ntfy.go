@@ -50,7 +50,7 @@ func NewNtfySender(log zerolog.Logger, settings domain.Notification) domain.Noti
 func (s *ntfySender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
 	m := ntfyMessage{
 		Message: s.builder.BuildBody(payload),
-		Title:   BuildTitle(event),
+		Title:   buildTitle(event, payload),
 	}
 
 	req, err := http.NewRequest(http.MethodPost, s.Settings.Host, strings.NewReader(m.Message))
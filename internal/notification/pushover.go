@@ -57,7 +57,7 @@ func NewPushoverSender(log zerolog.Logger, settings domain.Notification) domain.
 }
 
 func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
-	title := BuildTitle(event)
+	title := buildTitle(event, payload)
 	message := s.builder.BuildBody(payload)
 
 	m := pushoverMessage{
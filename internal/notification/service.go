@@ -5,6 +5,7 @@ package notification
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -24,22 +25,45 @@ type Service interface {
 	Delete(ctx context.Context, id int) error
 	Send(event domain.NotificationEvent, payload domain.NotificationPayload)
 	Test(ctx context.Context, notification domain.Notification) error
+	FindDeliveries(ctx context.Context, params domain.NotificationDeliveryQueryParams) ([]domain.NotificationDelivery, error)
+	ResendDelivery(ctx context.Context, id int64) error
+	DeleteDelivery(ctx context.Context, id int64) error
+	RetryDeliveries(ctx context.Context) error
+	GetUserPreference(ctx context.Context, username string) (*domain.UserNotificationPreference, error)
+	UpdateUserPreference(ctx context.Context, username string, pref domain.UserNotificationPreference) (*domain.UserNotificationPreference, error)
+	ForgetUserPreference(userID int)
 }
 
 type service struct {
-	log     zerolog.Logger
-	repo    domain.NotificationRepo
-	senders map[int]domain.NotificationSender
+	log      zerolog.Logger
+	repo     domain.NotificationRepo
+	prefRepo domain.NotificationPreferenceRepo
+	// failed notifications waiting for a retry
+	deliveryRepo domain.NotificationDeliveryRepo
+	userRepo     domain.UserRepo
+	senders      map[int]domain.NotificationSender
+	// settings of the senders by notification id, for message templates and quiet hours
+	notifications map[int]domain.Notification
+
+	// preferences by user id
+	preferences map[int]domain.UserNotificationPreference
+	prefMu      sync.RWMutex
 }
 
-func NewService(log logger.Logger, repo domain.NotificationRepo) Service {
+func NewService(log logger.Logger, repo domain.NotificationRepo, prefRepo domain.NotificationPreferenceRepo, deliveryRepo domain.NotificationDeliveryRepo, userRepo domain.UserRepo) Service {
 	s := &service{
-		log:     log.With().Str("module", "notification").Logger(),
-		repo:    repo,
-		senders: make(map[int]domain.NotificationSender),
+		log:           log.With().Str("module", "notification").Logger(),
+		repo:          repo,
+		prefRepo:      prefRepo,
+		deliveryRepo:  deliveryRepo,
+		userRepo:      userRepo,
+		senders:       make(map[int]domain.NotificationSender),
+		notifications: make(map[int]domain.Notification),
+		preferences:   make(map[int]domain.UserNotificationPreference),
 	}
 
 	s.registerSenders()
+	s.loadPreferences()
 
 	return s
 }
@@ -65,6 +89,10 @@ func (s *service) FindByID(ctx context.Context, id int) (*domain.Notification, e
 }
 
 func (s *service) Store(ctx context.Context, notification domain.Notification) (*domain.Notification, error) {
+	if err := notification.Validate(); err != nil {
+		return nil, err
+	}
+
 	_, err := s.repo.Store(ctx, notification)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("could not store notification: %+v", notification)
@@ -78,6 +106,10 @@ func (s *service) Store(ctx context.Context, notification domain.Notification) (
 }
 
 func (s *service) Update(ctx context.Context, notification domain.Notification) (*domain.Notification, error) {
+	if err := notification.Validate(); err != nil {
+		return nil, err
+	}
+
 	_, err := s.repo.Update(ctx, notification)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("could not update notification: %+v", notification)
@@ -99,6 +131,7 @@ func (s *service) Delete(ctx context.Context, id int) error {
 
 	// delete sender
 	delete(s.senders, id)
+	delete(s.notifications, id)
 
 	return nil
 }
@@ -135,9 +168,15 @@ func (s *service) registerSender(notification domain.Notification) {
 			s.senders[notification.ID] = NewPushoverSender(s.log, notification)
 		case domain.NotificationTypeShoutrrr:
 			s.senders[notification.ID] = NewShoutrrrSender(s.log, notification)
+		case domain.NotificationTypeSlack:
+			s.senders[notification.ID] = NewSlackSender(s.log, notification)
 		case domain.NotificationTypeTelegram:
 			s.senders[notification.ID] = NewTelegramSender(s.log, notification)
+		case domain.NotificationTypeWebhook:
+			s.senders[notification.ID] = NewWebhookSender(s.log, notification)
 		}
+
+		s.notifications[notification.ID] = notification
 	}
 
 	return
@@ -149,12 +188,40 @@ func (s *service) Send(event domain.NotificationEvent, payload domain.Notificati
 		s.log.Debug().Msgf("sending notification for %v", string(event))
 	}
 
+	// the release may change after returning, the message templates use a copy of it
+	if payload.Release != nil {
+		release := *payload.Release
+		payload.Release = &release
+	}
+
 	go func() {
-		for _, sender := range s.senders {
+		now := time.Now()
+
+		for id, sender := range s.senders {
 			// check if sender is active and have notification types
 			if sender.CanSend(event) {
-				if err := sender.Send(event, payload); err != nil {
-					s.log.Error().Err(err).Msgf("could not send %s notification for %v", sender.Name(), string(event))
+				if !s.allowedByPreferences(id, event, now) {
+					s.log.Trace().Msgf("skip %s notification for %v due to user preferences", sender.Name(), string(event))
+					continue
+				}
+
+				// the rendered message is queued, the release of the payload is not kept
+				p := s.applyMessageTemplate(id, event, payload)
+
+				if n := s.notifications[id]; n.QuietHoursSkip(event, now) {
+					if n.QuietHoursDeferred() {
+						s.log.Trace().Msgf("defer %s notification for %v to digest after quiet hours", sender.Name(), string(event))
+						s.deferDelivery(id, event, p, n.QuietHoursEndAfter(now))
+						continue
+					}
+
+					s.log.Trace().Msgf("skip %s notification for %v due to quiet hours", sender.Name(), string(event))
+					continue
+				}
+
+				if err := sender.Send(event, p); err != nil {
+					s.log.Error().Err(err).Msgf("could not send %s notification for %v, queued for retry", sender.Name(), string(event))
+					s.queueDelivery(id, event, p, err, now)
 				}
 			}
 		}
@@ -163,6 +230,111 @@ func (s *service) Send(event domain.NotificationEvent, payload domain.Notificati
 	return
 }
 
+// applyMessageTemplate renders the message template of the notification agent for the event into the payload,
+// the default message is kept when there is no template or it fails to render
+func (s *service) applyMessageTemplate(notificationID int, event domain.NotificationEvent, payload domain.NotificationPayload) domain.NotificationPayload {
+	t, ok := s.notifications[notificationID].MessageTemplates[event]
+	if !ok || !t.IsSet() {
+		return payload
+	}
+
+	title, message, err := t.Render(event, payload)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not render message template of notification %d for %v", notificationID, string(event))
+		return payload
+	}
+
+	payload.Title = title
+	payload.Body = message
+
+	return payload
+}
+
+func (s *service) loadPreferences() {
+	prefs, err := s.prefRepo.List(context.Background())
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not load user notification preferences")
+		return
+	}
+
+	s.prefMu.Lock()
+	defer s.prefMu.Unlock()
+
+	for _, pref := range prefs {
+		s.preferences[pref.UserID] = pref
+	}
+}
+
+// allowedByPreferences checks the preferences of users subscribed to the notification agent.
+// Agents without subscribers are always allowed, otherwise at least one subscriber must want the event outside their quiet hours.
+func (s *service) allowedByPreferences(notificationID int, event domain.NotificationEvent, now time.Time) bool {
+	s.prefMu.RLock()
+	defer s.prefMu.RUnlock()
+
+	subscribed := false
+
+	for _, pref := range s.preferences {
+		if !pref.Subscribed(notificationID) {
+			continue
+		}
+
+		subscribed = true
+
+		if pref.WantsEvent(event) && !pref.InQuietHours(now) {
+			return true
+		}
+	}
+
+	return !subscribed
+}
+
+func (s *service) GetUserPreference(ctx context.Context, username string) (*domain.UserNotificationPreference, error) {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find user: %s", username)
+	}
+
+	pref, err := s.prefRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			return &domain.UserNotificationPreference{
+				UserID:        user.ID,
+				Events:        []string{},
+				Notifications: []int{},
+			}, nil
+		}
+
+		s.log.Error().Err(err).Msgf("could not find notification preferences for user: %s", username)
+		return nil, err
+	}
+
+	return pref, nil
+}
+
+func (s *service) UpdateUserPreference(ctx context.Context, username string, pref domain.UserNotificationPreference) (*domain.UserNotificationPreference, error) {
+	if err := pref.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find user: %s", username)
+	}
+
+	pref.UserID = user.ID
+
+	if err := s.prefRepo.Store(ctx, &pref); err != nil {
+		s.log.Error().Err(err).Msgf("could not store notification preferences for user: %s", username)
+		return nil, err
+	}
+
+	s.prefMu.Lock()
+	s.preferences[pref.UserID] = pref
+	s.prefMu.Unlock()
+
+	return &pref, nil
+}
+
 func (s *service) Test(ctx context.Context, notification domain.Notification) error {
 	var agent domain.NotificationSender
 
@@ -257,8 +429,12 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 		agent = NewPushoverSender(s.log, notification)
 	case domain.NotificationTypeShoutrrr:
 		agent = NewShoutrrrSender(s.log, notification)
+	case domain.NotificationTypeSlack:
+		agent = NewSlackSender(s.log, notification)
 	case domain.NotificationTypeTelegram:
 		agent = NewTelegramSender(s.log, notification)
+	case domain.NotificationTypeWebhook:
+		agent = NewWebhookSender(s.log, notification)
 	default:
 		s.log.Error().Msgf("unsupported notification type: %v", notification.Type)
 		return errors.New("unsupported notification type")
@@ -273,6 +449,17 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			continue
 		}
 
+		// render the message templates of the form, so they are tested before being saved
+		if t, ok := notification.MessageTemplates[e.Event]; ok && t.IsSet() {
+			title, message, err := t.Render(e.Event, e)
+			if err != nil {
+				return errors.Wrap(err, "could not render message template for %v", string(e.Event))
+			}
+
+			e.Title = title
+			e.Body = message
+		}
+
 		if err := agent.Send(e.Event, e); err != nil {
 			s.log.Error().Err(err).Msgf("error sending test notification: %#v", notification)
 			return err
@@ -298,3 +485,10 @@ func enabledEvent(events []string, e domain.NotificationEvent) bool {
 
 	return false
 }
+
+// ForgetUserPreference drops the cached preferences of a deleted user, the database removes them with the user
+func (s *service) ForgetUserPreference(userID int) {
+	s.prefMu.Lock()
+	delete(s.preferences, userID)
+	s.prefMu.Unlock()
+}
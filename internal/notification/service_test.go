@@ -0,0 +1,59 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_applyMessageTemplate(t *testing.T) {
+	s := &service{
+		log: zerolog.Nop(),
+		notifications: map[int]domain.Notification{
+			1: {
+				MessageTemplates: map[domain.NotificationEvent]domain.NotificationMessageTemplate{
+					domain.NotificationEventPushApproved: {Title: "Grabbed {{ .Title }}", Message: "{{ .TorrentName }} ({{ .FilterName }})"},
+					domain.NotificationEventPushError:    {Message: "{{ .Missing }}"},
+				},
+			},
+		},
+	}
+
+	release := &domain.Release{TorrentName: "Show.S01E01.1080p.WEB-DL-GRP", Title: "Show", FilterName: "tv"}
+	payload := domain.NotificationPayload{ReleaseName: release.TorrentName, Filter: "tv", Release: release}
+
+	p := s.applyMessageTemplate(1, domain.NotificationEventPushApproved, payload)
+	assert.Equal(t, "Grabbed Show", p.Title)
+	assert.Equal(t, "Show.S01E01.1080p.WEB-DL-GRP (tv)", p.Body)
+
+	// the default message is kept without a template, or when it fails to render
+	assert.Equal(t, payload, s.applyMessageTemplate(1, domain.NotificationEventPushRejected, payload))
+	assert.Equal(t, payload, s.applyMessageTemplate(1, domain.NotificationEventPushError, payload))
+	assert.Equal(t, payload, s.applyMessageTemplate(2, domain.NotificationEventPushApproved, payload))
+}
+
+func Test_service_ForgetUserPreference(t *testing.T) {
+	s := &service{
+		log: zerolog.Nop(),
+		preferences: map[int]domain.UserNotificationPreference{
+			1: {UserID: 1, Events: []string{string(domain.NotificationEventPushError)}, Notifications: []int{1}},
+		},
+	}
+
+	now := time.Now()
+
+	// the only subscriber doesn't want the event
+	assert.False(t, s.allowedByPreferences(1, domain.NotificationEventPushApproved, now))
+
+	// the preferences of a deleted user no longer apply
+	s.ForgetUserPreference(1)
+	assert.True(t, s.allowedByPreferences(1, domain.NotificationEventPushApproved, now))
+	assert.Empty(t, s.preferences)
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+)
+
+// slackAPIURL is the web api of slack, used to post messages with a bot token
+const slackAPIURL = "https://slack.com/api"
+
+// SlackMessage is a message of Block Kit blocks, the text is the fallback shown in notifications
+type SlackMessage struct {
+	Channel string       `json:"channel,omitempty"`
+	Text    string       `json:"text"`
+	Blocks  []SlackBlock `json:"blocks"`
+}
+
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Fields   []SlackText `json:"fields,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackResponse is the response of the web api, errors are returned with status 200 and ok false
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+type slackSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+
+	apiURL     string
+	httpClient *http.Client
+}
+
+func (s *slackSender) Name() string {
+	return "slack"
+}
+
+func NewSlackSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &slackSender{
+		log:      log.With().Str("sender", "slack").Logger(),
+		Settings: settings,
+		apiURL:   slackAPIURL,
+		httpClient: &http.Client{
+			Timeout:   time.Second * 30,
+			Transport: sharedhttp.Transport,
+		},
+	}
+}
+
+// Send posts the message to the incoming webhook, or to the channel with the bot token when no webhook is set
+func (s *slackSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := s.buildMessage(event, payload)
+
+	target := s.Settings.Webhook
+	if target == "" {
+		target = s.apiURL + "/chat.postMessage"
+		m.Channel = s.Settings.Channel
+	}
+
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal json request for event: %v payload: %v", event, payload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return errors.Wrap(err, "could not create request for event: %v payload: %v", event, payload)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "autobrr")
+
+	if s.Settings.Webhook == "" {
+		req.Header.Set("Authorization", "Bearer "+s.Settings.Token)
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "client request error for event: %v payload: %v", event, payload)
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(bufio.NewReader(res.Body))
+	if err != nil {
+		return errors.Wrap(err, "could not read body for event: %v payload: %v", event, payload)
+	}
+
+	s.log.Trace().Msgf("slack status: %d", res.StatusCode)
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("unexpected status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	// incoming webhooks respond with a plain ok, the web api with json
+	if s.Settings.Webhook == "" {
+		var response slackResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return errors.Wrap(err, "could not unmarshal slack response")
+		}
+
+		if !response.OK {
+			return errors.New("slack chat.postMessage failed: %s", response.Error)
+		}
+	}
+
+	s.log.Debug().Msg("notification successfully sent to slack")
+
+	return nil
+}
+
+func (s *slackSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *slackSender) isEnabled() bool {
+	if s.Settings.Enabled {
+		if s.Settings.Webhook != "" {
+			return true
+		}
+
+		if s.Settings.Token == "" || s.Settings.Channel == "" {
+			s.log.Warn().Msg("slack missing webhook url, or bot token and channel")
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (s *slackSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildMessage formats the release fields of the payload as Block Kit blocks
+func (s *slackSender) buildMessage(event domain.NotificationEvent, payload domain.NotificationPayload) SlackMessage {
+	title := BuildTitle(event)
+	description := payload.ReleaseName
+
+	if payload.Subject != "" && payload.Message != "" {
+		title = payload.Subject
+		description = payload.Message
+	}
+
+	if payload.Title != "" {
+		title = payload.Title
+	}
+	if payload.Body != "" {
+		description = payload.Body
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{Type: "plain_text", Text: fmt.Sprintf("%s %s", slackEmoji(event), title)},
+		},
+	}
+
+	if description != "" {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*", slackEscape(description))},
+		})
+	}
+
+	var fields []SlackText
+
+	addField := func(name, value string) {
+		if value != "" {
+			fields = append(fields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", name, slackEscape(value))})
+		}
+	}
+
+	if payload.Status != "" {
+		addField("Status", payload.Status.String())
+	}
+	addField("Indexer", payload.Indexer)
+	addField("Filter", payload.Filter)
+	addField("Action", payload.Action)
+	addField("Action type", string(payload.ActionType))
+	addField("Action client", payload.ActionClient)
+	if payload.Size > 0 {
+		addField("Size", humanize.Bytes(payload.Size))
+	}
+	if payload.Protocol != "" {
+		addField("Protocol", payload.Protocol.String())
+	}
+	if payload.Implementation != "" {
+		addField("Implementation", payload.Implementation.String())
+	}
+
+	// a section holds at most 10 fields
+	for len(fields) > 0 {
+		n := min(len(fields), 10)
+		blocks = append(blocks, SlackBlock{Type: "section", Fields: fields[:n]})
+		fields = fields[n:]
+	}
+
+	if len(payload.Rejections) > 0 {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*Reasons*\n```%s```", slackEscape(strings.Join(payload.Rejections, ", ")))},
+		})
+	}
+
+	timestamp := payload.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	blocks = append(blocks, SlackBlock{
+		Type: "context",
+		Elements: []SlackText{
+			{Type: "mrkdwn", Text: fmt.Sprintf("autobrr | <!date^%d^{date_short_pretty} {time}|%s>", timestamp.Unix(), timestamp.Format(time.RFC3339))},
+		},
+	})
+
+	return SlackMessage{
+		Text:   strings.TrimSpace(title + " " + description),
+		Blocks: blocks,
+	}
+}
+
+// slackEmoji marks the event like the embed colors of discord
+func slackEmoji(event domain.NotificationEvent) string {
+	switch event {
+	case domain.NotificationEventPushApproved, domain.NotificationEventIRCReconnected, domain.NotificationEventDownloadComplete:
+		return ":large_green_circle:"
+	case domain.NotificationEventPushRejected:
+		return ":white_circle:"
+	case domain.NotificationEventPushError, domain.NotificationEventIRCDisconnected, domain.NotificationEventSizeMismatch,
+		domain.NotificationEventIRCChannelParted, domain.NotificationEventIRCChannelKicked, domain.NotificationEventFeedError,
+		domain.NotificationEventDownloadClientUnhealthy:
+		return ":red_circle:"
+	default:
+		return ":large_blue_circle:"
+	}
+}
+
+// slackEscape escapes the control characters of slack mrkdwn
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
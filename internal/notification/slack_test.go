@@ -0,0 +1,105 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_slackSender_Send(t *testing.T) {
+	var messages []SlackMessage
+	var auth []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m SlackMessage
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		messages = append(messages, m)
+		auth = append(auth, r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/webhook":
+			_, _ = w.Write([]byte("ok"))
+		case "/api/chat.postMessage":
+			if m.Channel != "#releases" {
+				_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	payload := domain.NotificationPayload{
+		ReleaseName:    "That.Show.S01E01.1080p.WEB-DL-GROUP",
+		Filter:         "TV",
+		Indexer:        "mock",
+		Size:           1_500_000_000,
+		Status:         domain.ReleasePushStatusRejected,
+		Action:         "Send to Sonarr",
+		Rejections:     []string{"Unknown Series <tvdb>"},
+		Protocol:       domain.ReleaseProtocolTorrent,
+		Implementation: domain.ReleaseImplementationIRC,
+	}
+
+	t.Run("webhook", func(t *testing.T) {
+		sender := NewSlackSender(zerolog.Nop(), domain.Notification{Enabled: true, Webhook: srv.URL + "/webhook", Events: []string{string(domain.NotificationEventPushRejected)}})
+
+		assert.True(t, sender.CanSend(domain.NotificationEventPushRejected))
+		require.NoError(t, sender.Send(domain.NotificationEventPushRejected, payload))
+
+		m := messages[len(messages)-1]
+		assert.Equal(t, "", auth[len(auth)-1])
+		assert.Equal(t, "Push Rejected That.Show.S01E01.1080p.WEB-DL-GROUP", m.Text)
+		require.Len(t, m.Blocks, 5)
+		assert.Equal(t, ":white_circle: Push Rejected", m.Blocks[0].Text.Text)
+		assert.Equal(t, []SlackText{
+			{Type: "mrkdwn", Text: "*Status*\nRejected"},
+			{Type: "mrkdwn", Text: "*Indexer*\nmock"},
+			{Type: "mrkdwn", Text: "*Filter*\nTV"},
+			{Type: "mrkdwn", Text: "*Action*\nSend to Sonarr"},
+			{Type: "mrkdwn", Text: "*Size*\n1.5 GB"},
+			{Type: "mrkdwn", Text: "*Protocol*\ntorrent"},
+			{Type: "mrkdwn", Text: "*Implementation*\nIRC"},
+		}, m.Blocks[2].Fields)
+		assert.Equal(t, "*Reasons*\n```Unknown Series &lt;tvdb&gt;```", m.Blocks[3].Text.Text)
+		assert.Equal(t, "context", m.Blocks[4].Type)
+	})
+
+	t.Run("bot_token", func(t *testing.T) {
+		sender := NewSlackSender(zerolog.Nop(), domain.Notification{Enabled: true, Token: "xoxb-token", Channel: "#releases"})
+		sender.(*slackSender).apiURL = srv.URL + "/api"
+
+		require.NoError(t, sender.Send(domain.NotificationEventPushRejected, payload))
+		assert.Equal(t, "Bearer xoxb-token", auth[len(auth)-1])
+		assert.Equal(t, "#releases", messages[len(messages)-1].Channel)
+	})
+
+	t.Run("bot_token_error", func(t *testing.T) {
+		sender := NewSlackSender(zerolog.Nop(), domain.Notification{Enabled: true, Token: "xoxb-token", Channel: "#missing"})
+		sender.(*slackSender).apiURL = srv.URL + "/api"
+
+		assert.EqualError(t, sender.Send(domain.NotificationEventTest, payload), "slack chat.postMessage failed: channel_not_found")
+	})
+
+	t.Run("not_configured", func(t *testing.T) {
+		sender := NewSlackSender(zerolog.Nop(), domain.Notification{Enabled: true, Token: "xoxb-token", Events: []string{string(domain.NotificationEventPushRejected)}})
+
+		assert.False(t, sender.CanSend(domain.NotificationEventPushRejected))
+	})
+}
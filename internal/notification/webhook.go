@@ -0,0 +1,227 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/rs/zerolog"
+)
+
+// WebhookPayload is the default json body of the webhook and the data of body templates
+type WebhookPayload struct {
+	Event          domain.NotificationEvent     `json:"event"`
+	Title          string                       `json:"title"`
+	Subject        string                       `json:"subject,omitempty"`
+	Message        string                       `json:"message,omitempty"`
+	Body           string                       `json:"body,omitempty"`
+	ReleaseName    string                       `json:"release_name,omitempty"`
+	Filter         string                       `json:"filter,omitempty"`
+	Indexer        string                       `json:"indexer,omitempty"`
+	InfoHash       string                       `json:"info_hash,omitempty"`
+	Size           uint64                       `json:"size,omitempty"`
+	Status         domain.ReleasePushStatus     `json:"status,omitempty"`
+	Action         string                       `json:"action,omitempty"`
+	ActionType     domain.ActionType            `json:"action_type,omitempty"`
+	ActionClient   string                       `json:"action_client,omitempty"`
+	Rejections     []string                     `json:"rejections,omitempty"`
+	Protocol       domain.ReleaseProtocol       `json:"protocol,omitempty"`
+	Implementation domain.ReleaseImplementation `json:"implementation,omitempty"`
+	Timestamp      time.Time                    `json:"timestamp"`
+}
+
+type webhookSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+
+	httpClient *http.Client
+}
+
+func (s *webhookSender) Name() string {
+	return "webhook"
+}
+
+func NewWebhookSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &webhookSender{
+		log:      log.With().Str("sender", "webhook").Logger(),
+		Settings: settings,
+		httpClient: &http.Client{
+			Timeout:   time.Second * 30,
+			Transport: sharedhttp.Transport,
+		},
+	}
+}
+
+// Send posts the event as json to the webhook url, signed with an hmac of the body when a secret is set
+func (s *webhookSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	body, err := s.buildBody(event, payload)
+	if err != nil {
+		return errors.Wrap(err, "could not build body for event: %v", event)
+	}
+
+	headers, err := parseWebhookHeaders(s.Settings.Headers)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.Settings.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not create request for event: %v payload: %v", event, payload)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("X-Autobrr-Event", string(event))
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if s.Settings.Secret != "" {
+		req.Header.Set("X-Autobrr-Signature", "sha256="+webhookSignature(s.Settings.Secret, body))
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "client request error for event: %v payload: %v", event, payload)
+	}
+
+	defer res.Body.Close()
+
+	s.log.Trace().Msgf("webhook status: %d", res.StatusCode)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		body, err := io.ReadAll(bufio.NewReader(res.Body))
+		if err != nil {
+			return errors.Wrap(err, "could not read body for event: %v payload: %v", event, payload)
+		}
+
+		return errors.New("unexpected status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to webhook")
+
+	return nil
+}
+
+func (s *webhookSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *webhookSender) isEnabled() bool {
+	if s.Settings.Enabled {
+		if s.Settings.Webhook == "" {
+			s.log.Warn().Msg("webhook missing url")
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (s *webhookSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildBody marshals the payload, or executes the template of the settings with it and checks the result is valid json
+func (s *webhookSender) buildBody(event domain.NotificationEvent, payload domain.NotificationPayload) ([]byte, error) {
+	data := WebhookPayload{
+		Event:          event,
+		Title:          buildTitle(event, payload),
+		Subject:        payload.Subject,
+		Message:        payload.Message,
+		Body:           payload.Body,
+		ReleaseName:    payload.ReleaseName,
+		Filter:         payload.Filter,
+		Indexer:        payload.Indexer,
+		InfoHash:       payload.InfoHash,
+		Size:           payload.Size,
+		Status:         payload.Status,
+		Action:         payload.Action,
+		ActionType:     payload.ActionType,
+		ActionClient:   payload.ActionClient,
+		Rejections:     payload.Rejections,
+		Protocol:       payload.Protocol,
+		Implementation: payload.Implementation,
+		Timestamp:      payload.Timestamp,
+	}
+
+	if data.Timestamp.IsZero() {
+		data.Timestamp = time.Now()
+	}
+
+	if strings.TrimSpace(s.Settings.Template) == "" {
+		return json.Marshal(data)
+	}
+
+	tmpl, err := template.New("webhook").Funcs(sprig.TxtFuncMap()).Parse(s.Settings.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, errors.Wrap(err, "could not execute template")
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("template is not valid json: %s", buf.String())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseWebhookHeaders parses the custom headers, one "Name: value" per line
+func parseWebhookHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, errors.New("invalid webhook header: %s", line)
+		}
+
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return headers, nil
+}
+
+// webhookSignature is the hex encoded hmac sha256 of the body with the secret
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_webhookSender_Send(t *testing.T) {
+	type request struct {
+		body      string
+		event     string
+		signature string
+		custom    string
+	}
+
+	var got request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		got = request{
+			body:      string(body),
+			event:     r.Header.Get("X-Autobrr-Event"),
+			signature: r.Header.Get("X-Autobrr-Signature"),
+			custom:    r.Header.Get("X-Custom"),
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	payload := domain.NotificationPayload{
+		ReleaseName: `That "Show" S01E01`,
+		Filter:      "TV",
+		Indexer:     "mock",
+		Status:      domain.ReleasePushStatusApproved,
+		Timestamp:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		settings domain.Notification
+		want     request
+		wantErr  string
+	}{
+		{
+			name:     "default_body",
+			settings: domain.Notification{Webhook: srv.URL},
+			want: request{
+				body:  `{"event":"PUSH_APPROVED","title":"Push Approved","release_name":"That \"Show\" S01E01","filter":"TV","indexer":"mock","status":"PUSH_APPROVED","timestamp":"2024-05-01T12:00:00Z"}`,
+				event: "PUSH_APPROVED",
+			},
+		},
+		{
+			name: "template_headers_and_signature",
+			settings: domain.Notification{
+				Webhook:  srv.URL,
+				Template: `{"text": {{ printf "%s: %s" .Title .ReleaseName | toJson }}, "filter": {{ toJson .Filter }}}`,
+				Headers:  "X-Custom: value\n\nAuthorization: Bearer token",
+				Secret:   "secret",
+			},
+			want: request{
+				body:      `{"text": "Push Approved: That \"Show\" S01E01", "filter": "TV"}`,
+				event:     "PUSH_APPROVED",
+				signature: "sha256=" + webhookSignature("secret", []byte(`{"text": "Push Approved: That \"Show\" S01E01", "filter": "TV"}`)),
+				custom:    "value",
+			},
+		},
+		{
+			name:     "template_not_json",
+			settings: domain.Notification{Webhook: srv.URL, Template: `{"text": {{ .ReleaseName }}}`},
+			wantErr:  `could not build body for event: PUSH_APPROVED: template is not valid json: {"text": That "Show" S01E01}`,
+		},
+		{
+			name:     "invalid_header",
+			settings: domain.Notification{Webhook: srv.URL, Headers: "X-Custom value"},
+			wantErr:  "invalid webhook header: X-Custom value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = request{}
+
+			err := NewWebhookSender(zerolog.Nop(), tt.settings).Send(domain.NotificationEventPushApproved, payload)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_webhookSignature(t *testing.T) {
+	// hmac-sha256 test vector of rfc 4231, test case 2
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", webhookSignature("Jefe", []byte("what do ya want for nothing?")))
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/storage"
+
+	"github.com/rs/zerolog"
+)
+
+// announceLogMaxBytes writes the buffered lines early so a busy network can't grow the buffer between runs
+const announceLogMaxBytes = 8 << 20
+
+// announceLog buffers the raw announce lines and writes them to storage on the announce log schedule
+type announceLog struct {
+	log        zerolog.Logger
+	storageSvc storage.Service
+
+	mu  sync.Mutex
+	buf *bytes.Buffer
+}
+
+func newAnnounceLog(log zerolog.Logger, storageSvc storage.Service) *announceLog {
+	return &announceLog{
+		log:        log,
+		storageSvc: storageSvc,
+		buf:        &bytes.Buffer{},
+	}
+}
+
+func (l *announceLog) add(indexer, channel, line string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fmt.Fprintf(l.buf, "%s\t%s\t%s\t%s\n", now.UTC().Format(time.RFC3339), indexer, channel, line)
+
+	if l.buf.Len() < announceLogMaxBytes {
+		return
+	}
+
+	buf := l.swap()
+	go func() {
+		if _, err := l.store(context.Background(), buf, now); err != nil {
+			l.log.Error().Err(err).Msg("could not store announce log")
+		}
+	}()
+}
+
+// swap returns the buffered lines and starts a new buffer, l.mu must be held
+func (l *announceLog) swap() *bytes.Buffer {
+	buf := l.buf
+	l.buf = &bytes.Buffer{}
+	return buf
+}
+
+// flush writes the lines buffered since the last flush, nothing is written without new lines
+func (l *announceLog) flush(ctx context.Context, now time.Time) (*domain.StorageObject, error) {
+	l.mu.Lock()
+	buf := l.swap()
+	l.mu.Unlock()
+
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+
+	return l.store(ctx, buf, now)
+}
+
+func (l *announceLog) store(ctx context.Context, buf *bytes.Buffer, now time.Time) (*domain.StorageObject, error) {
+	name := fmt.Sprintf("announces-%s.log", now.UTC().Format("20060102-150405.000"))
+
+	return l.storageSvc.Store(ctx, domain.StorageArtifactAnnounceLog, name, buf)
+}
+
+// LogAnnounce adds the raw line to the announce log when it is enabled
+func (s *service) LogAnnounce(indexer, channel, line string) {
+	if s.announceLog == nil {
+		return
+	}
+
+	s.announceLog.add(indexer, channel, line, time.Now())
+}
+
+type AnnounceLogJob struct {
+	Name string
+	Log  zerolog.Logger
+	log  *announceLog
+}
+
+func (j *AnnounceLogJob) Run() {
+	object, err := j.log.flush(context.Background(), time.Now())
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not store announce log")
+		return
+	}
+
+	if object != nil {
+		j.Log.Debug().Msgf("announce log stored as %s", object.Key)
+	}
+}
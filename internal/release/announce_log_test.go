@@ -0,0 +1,67 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/storage"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockStorageService only implements Store, calling anything else panics
+type mockStorageService struct {
+	storage.Service
+	stored map[string]string
+}
+
+func (s *mockStorageService) Store(ctx context.Context, artifact domain.StorageArtifact, name string, r io.Reader) (*domain.StorageObject, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	key := string(artifact) + "/" + name
+	s.stored[key] = string(data)
+
+	return &domain.StorageObject{Key: key, Size: int64(len(data))}, nil
+}
+
+func TestAnnounceLog_Flush(t *testing.T) {
+	storageSvc := &mockStorageService{stored: map[string]string{}}
+	l := newAnnounceLog(zerolog.Nop(), storageSvc)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// nothing is written without lines
+	object, err := l.flush(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Nil(t, object)
+	assert.Empty(t, storageSvc.stored)
+
+	l.add("mock", "#announce", "New Torrent: That.Show.S01E01.1080p.WEB.h264-GROUP", now)
+	l.add("mock", "#announce", "New Torrent: That.Show.S01E02.1080p.WEB.h264-GROUP", now)
+
+	object, err = l.flush(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, "announces/announces-20240501-120000.000.log", object.Key)
+
+	lines := strings.Split(strings.TrimSpace(storageSvc.stored[object.Key]), "\n")
+	assert.Equal(t, []string{
+		"2024-05-01T12:00:00Z\tmock\t#announce\tNew Torrent: That.Show.S01E01.1080p.WEB.h264-GROUP",
+		"2024-05-01T12:00:00Z\tmock\t#announce\tNew Torrent: That.Show.S01E02.1080p.WEB.h264-GROUP",
+	}, lines)
+
+	// the next flush only has the lines since the last one
+	object, err = l.flush(context.Background(), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Nil(t, object)
+}
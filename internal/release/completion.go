@@ -0,0 +1,139 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// completionLookback is how long pushed torrents are tracked until they are given up on
+const completionLookback = 7 * 24 * time.Hour
+
+type CompletionCheckJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+// Run checks the download clients for tracked torrents that finished downloading
+func (j *CompletionCheckJob) Run() {
+	if err := j.svc.checkCompletion(context.Background()); err != nil {
+		j.Log.Error().Err(err).Msg("could not check completion of pushed torrents")
+	}
+}
+
+// checkCompletion asks the clients of the tracked pushes which torrents finished downloading,
+// stores the completion time, sends a download complete notification and runs the completion hooks of the action
+func (s *service) checkCompletion(ctx context.Context) error {
+	pushes, err := s.repo.FindTrackedPushes(ctx, time.Now().Add(-completionLookback))
+	if err != nil {
+		return errors.Wrap(err, "could not find tracked pushes")
+	}
+
+	byClient := make(map[int32][]domain.ReleaseTrackedPush)
+	for _, push := range pushes {
+		if push.ClientID == 0 {
+			continue
+		}
+
+		byClient[push.ClientID] = append(byClient[push.ClientID], push)
+	}
+
+	// actions by id, looked up once per check for their completion hooks
+	actions := make(map[int64]*domain.Action)
+
+	for clientID, clientPushes := range byClient {
+		hashes := make([]string, 0, len(clientPushes))
+		for _, push := range clientPushes {
+			hashes = append(hashes, push.InfoHash)
+		}
+
+		completed, err := s.actionSvc.CompletedTorrents(ctx, clientID, hashes)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("could not check completion of %d torrents on client %d", len(hashes), clientID)
+			continue
+		}
+
+		for _, push := range clientPushes {
+			completedAt, ok := completed[strings.ToLower(push.InfoHash)]
+			if !ok {
+				continue
+			}
+
+			if err := s.repo.SetActionStatusCompleted(ctx, push.ActionStatusID, completedAt); err != nil {
+				s.log.Error().Err(err).Msgf("could not store completion of release: %s", push.TorrentName)
+				continue
+			}
+
+			s.log.Debug().Msgf("release %s completed on client %s at %s", push.TorrentName, push.Client, completedAt.Format(time.RFC3339))
+
+			s.runCompletionHooks(ctx, push, actions)
+
+			if s.bus == nil {
+				continue
+			}
+
+			payload := &domain.NotificationPayload{
+				Event:        domain.NotificationEventDownloadComplete,
+				ReleaseName:  push.TorrentName,
+				Filter:       push.Filter,
+				Indexer:      push.Indexer,
+				InfoHash:     push.InfoHash,
+				Size:         push.Size,
+				Action:       push.Action,
+				ActionType:   push.ActionType,
+				ActionClient: push.Client,
+				Protocol:     domain.ReleaseProtocolTorrent,
+				Timestamp:    completedAt,
+			}
+
+			s.bus.Publish("events:notification", &payload.Event, payload)
+		}
+	}
+
+	return nil
+}
+
+// runCompletionHooks runs the completion hooks of the action that pushed the torrent
+func (s *service) runCompletionHooks(ctx context.Context, push domain.ReleaseTrackedPush, actions map[int64]*domain.Action) {
+	if push.ActionID == 0 {
+		return
+	}
+
+	action, ok := actions[push.ActionID]
+	if !ok {
+		var err error
+		action, err = s.actionSvc.Get(ctx, &domain.GetActionRequest{Id: int(push.ActionID)})
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not get action %d for completion hooks of release: %s", push.ActionID, push.TorrentName)
+			return
+		}
+
+		actions[push.ActionID] = action
+	}
+
+	if !action.HasCompletionHooks() {
+		return
+	}
+
+	release, err := s.repo.Get(ctx, &domain.GetReleaseRequest{Id: int(push.ReleaseID)})
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not get release for completion hooks: %s", push.TorrentName)
+		return
+	}
+
+	release.Indexer.Name = push.Indexer
+	release.TorrentHash = push.InfoHash
+
+	if err := s.actionSvc.RunCompletionHooks(ctx, action, release); err != nil {
+		s.log.Error().Err(err).Msgf("completion hooks of action %s failed for release: %s", action.Name, release.TorrentName)
+	}
+}
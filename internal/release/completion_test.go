@@ -0,0 +1,110 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCompletionRepo only implements the tracked push methods, calling anything else panics
+type mockCompletionRepo struct {
+	domain.ReleaseRepo
+	pushes    []domain.ReleaseTrackedPush
+	completed map[int64]time.Time
+}
+
+func (r *mockCompletionRepo) FindTrackedPushes(ctx context.Context, since time.Time) ([]domain.ReleaseTrackedPush, error) {
+	return r.pushes, nil
+}
+
+func (r *mockCompletionRepo) Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error) {
+	return &domain.Release{ID: int64(req.Id), TorrentName: "Completed.Release"}, nil
+}
+
+func (r *mockCompletionRepo) SetActionStatusCompleted(ctx context.Context, id int64, completedAt time.Time) error {
+	r.completed[id] = completedAt
+	return nil
+}
+
+type mockCompletionActionService struct {
+	action.Service
+	completed map[int32]map[string]time.Time
+	actions   map[int]*domain.Action
+	hooksRan  []string
+}
+
+func (s *mockCompletionActionService) Get(ctx context.Context, req *domain.GetActionRequest) (*domain.Action, error) {
+	a, ok := s.actions[req.Id]
+	if !ok {
+		return nil, domain.ErrRecordNotFound
+	}
+	return a, nil
+}
+
+func (s *mockCompletionActionService) RunCompletionHooks(ctx context.Context, action *domain.Action, release *domain.Release) error {
+	s.hooksRan = append(s.hooksRan, action.Name+":"+release.TorrentName+":"+release.TorrentHash)
+	return nil
+}
+
+func (s *mockCompletionActionService) CompletedTorrents(ctx context.Context, clientID int32, hashes []string) (map[string]time.Time, error) {
+	completed, ok := s.completed[clientID]
+	if !ok {
+		return nil, errors.New("client %d unreachable", clientID)
+	}
+	return completed, nil
+}
+
+func TestService_checkCompletion(t *testing.T) {
+	completedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	repo := &mockCompletionRepo{
+		pushes: []domain.ReleaseTrackedPush{
+			{ActionStatusID: 1, ReleaseID: 10, TorrentName: "Completed.Release", ActionID: 5, ClientID: 1, Client: "qbit", InfoHash: "AAAA"},
+			{ActionStatusID: 2, TorrentName: "Downloading.Release", ClientID: 1, Client: "qbit", InfoHash: "bbbb"},
+			{ActionStatusID: 3, TorrentName: "Unreachable.Release", ClientID: 2, Client: "gone", InfoHash: "cccc"},
+			{ActionStatusID: 4, TorrentName: "No.Client.Release", InfoHash: "dddd"},
+		},
+		completed: map[int64]time.Time{},
+	}
+
+	actionSvc := &mockCompletionActionService{
+		completed: map[int32]map[string]time.Time{
+			1: {"aaaa": completedAt},
+		},
+		actions: map[int]*domain.Action{
+			5: {ID: 5, Name: "qbit", CompletionHooks: []domain.CompletionHook{{Type: domain.CompletionHookTypeExec, ExecCmd: "true"}}},
+		},
+	}
+
+	var events []domain.NotificationPayload
+
+	bus := EventBus.New()
+	assert.NoError(t, bus.Subscribe("events:notification", func(event *domain.NotificationEvent, payload *domain.NotificationPayload) {
+		events = append(events, *payload)
+	}))
+
+	s := &service{log: zerolog.Nop(), repo: repo, actionSvc: actionSvc, bus: bus}
+
+	assert.NoError(t, s.checkCompletion(context.Background()))
+
+	assert.Equal(t, map[int64]time.Time{1: completedAt}, repo.completed)
+	assert.Equal(t, []string{"qbit:Completed.Release:AAAA"}, actionSvc.hooksRan)
+
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, domain.NotificationEventDownloadComplete, events[0].Event)
+		assert.Equal(t, "Completed.Release", events[0].ReleaseName)
+		assert.Equal(t, "qbit", events[0].ActionClient)
+		assert.Equal(t, completedAt, events[0].Timestamp)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// consistencyLookback is how far back announces are compared
+	consistencyLookback = 6 * time.Hour
+
+	// consistencyGracePeriod gives feeds time to catch up with IRC and vice versa
+	// before a release is reported as missing
+	consistencyGracePeriod = 30 * time.Minute
+
+	consistencyCheckInterval = 1 * time.Hour
+)
+
+// checkConsistency reports releases only seen by one source for indexers that have both IRC and feed entries.
+// Releases seen after cutoff are skipped since the other source might not have caught up yet.
+func (h *announceHistory) checkConsistency(ctx context.Context, cutoff time.Time) ([]domain.ReleaseConsistencyReport, error) {
+	now := time.Now()
+
+	entries, err := h.entries(ctx, now.Add(-consistencyLookback))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get announce history")
+	}
+
+	// indexer -> source -> torrent name -> first seen
+	seen := make(map[string]map[domain.AnnounceSource]map[string]time.Time)
+
+	for _, entry := range entries {
+		indexer, ok := seen[entry.Indexer]
+		if !ok {
+			indexer = make(map[domain.AnnounceSource]map[string]time.Time)
+			seen[entry.Indexer] = indexer
+		}
+
+		names, ok := indexer[entry.Source]
+		if !ok {
+			names = make(map[string]time.Time)
+			indexer[entry.Source] = names
+		}
+
+		if prev, found := names[entry.TorrentName]; !found || entry.SeenAt.Before(prev) {
+			names[entry.TorrentName] = entry.SeenAt
+		}
+	}
+
+	reports := make([]domain.ReleaseConsistencyReport, 0)
+
+	for identifier, indexer := range seen {
+		since, ok := h.firstSeenSince(identifier)
+		if !ok {
+			continue
+		}
+
+		// skip releases from before both sources were active and settled,
+		// eg. backlog items returned by the first feed run after startup
+		since = since.Add(consistencyGracePeriod)
+
+		irc := indexer[domain.AnnounceSourceIRC]
+		feed := indexer[domain.AnnounceSourceFeed]
+
+		report := domain.ReleaseConsistencyReport{
+			Indexer:      identifier,
+			IRCCount:     len(irc),
+			FeedCount:    len(feed),
+			MissedByIRC:  missingFrom(feed, irc, since, cutoff),
+			MissedByFeed: missingFrom(irc, feed, since, cutoff),
+			CheckedAt:    now,
+		}
+
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Indexer < reports[j].Indexer
+	})
+
+	return reports, nil
+}
+
+// missingFrom returns names in src seen between since and cutoff which are not in other
+func missingFrom(src, other map[string]time.Time, since, cutoff time.Time) []string {
+	missing := make([]string, 0)
+
+	for name, seen := range src {
+		if seen.Before(since) || seen.After(cutoff) {
+			continue
+		}
+
+		if _, ok := other[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+func normalizeAnnounceName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+type ConsistencyCheckJob struct {
+	Name    string
+	Log     zerolog.Logger
+	history *announceHistory
+}
+
+func (j *ConsistencyCheckJob) Run() {
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := j.history.prune(ctx, now.Add(-consistencyLookback)); err != nil {
+		j.Log.Error().Err(err).Msg("could not prune announce history")
+	}
+
+	reports, err := j.history.checkConsistency(ctx, now.Add(-consistencyGracePeriod))
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not check feed consistency")
+		return
+	}
+
+	for _, report := range reports {
+		if len(report.MissedByIRC) == 0 && len(report.MissedByFeed) == 0 {
+			j.Log.Debug().Msgf("indexer %s: irc and feed announces are consistent (irc: %d, feed: %d)", report.Indexer, report.IRCCount, report.FeedCount)
+			continue
+		}
+
+		if len(report.MissedByIRC) > 0 {
+			j.Log.Warn().Strs("releases", report.MissedByIRC).Msgf("indexer %s: %d releases found in feed but not announced on IRC, check the channel and announce parsing", report.Indexer, len(report.MissedByIRC))
+		}
+
+		if len(report.MissedByFeed) > 0 {
+			j.Log.Warn().Strs("releases", report.MissedByFeed).Msgf("indexer %s: %d releases announced on IRC but not found in feed", report.Indexer, len(report.MissedByFeed))
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAnnounceHistoryRepo struct {
+	mu      sync.Mutex
+	entries []domain.AnnounceHistoryEntry
+}
+
+func (r *mockAnnounceHistoryRepo) StoreMany(ctx context.Context, entries []domain.AnnounceHistoryEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *mockAnnounceHistoryRepo) FindSince(ctx context.Context, since time.Time) ([]domain.AnnounceHistoryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []domain.AnnounceHistoryEntry
+	for _, entry := range r.entries {
+		if entry.SeenAt.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (r *mockAnnounceHistoryRepo) DeleteOlderThan(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := r.entries[:0]
+	for _, entry := range r.entries {
+		if entry.SeenAt.Before(before) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	r.entries = entries
+	return nil
+}
+
+func announce(indexer string, impl domain.ReleaseImplementation, name string, seenAt time.Time) *domain.Release {
+	return &domain.Release{
+		Indexer:        domain.IndexerMinimal{Identifier: indexer},
+		Implementation: impl,
+		TorrentName:    name,
+		Timestamp:      seenAt,
+	}
+}
+
+func TestAnnounceHistory_CheckConsistency(t *testing.T) {
+	now := time.Now()
+
+	// both sources must have been active for the grace period before releases are compared
+	warmup := now.Add(-3 * time.Hour)
+	seen := now.Add(-1 * time.Hour)
+
+	tests := []struct {
+		name     string
+		releases []*domain.Release
+		want     []domain.ReleaseConsistencyReport
+	}{
+		{
+			name: "matched",
+			releases: []*domain.Release{
+				announce("mock", domain.ReleaseImplementationIRC, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationRSS, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationIRC, "That.Show.S01E01.1080p.WEB.h264-GROUP", seen),
+				announce("mock", domain.ReleaseImplementationRSS, "that.show.s01e01.1080p.web.h264-group ", seen.Add(time.Minute)),
+			},
+			want: []domain.ReleaseConsistencyReport{
+				{Indexer: "mock", IRCCount: 2, FeedCount: 2, MissedByIRC: []string{}, MissedByFeed: []string{}},
+			},
+		},
+		{
+			name: "feed_only",
+			releases: []*domain.Release{
+				announce("mock", domain.ReleaseImplementationIRC, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationRSS, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationRSS, "That.Show.S01E02.1080p.WEB.h264-GROUP", seen),
+			},
+			want: []domain.ReleaseConsistencyReport{
+				{Indexer: "mock", IRCCount: 1, FeedCount: 2, MissedByIRC: []string{"that.show.s01e02.1080p.web.h264-group"}, MissedByFeed: []string{}},
+			},
+		},
+		{
+			name: "irc_only",
+			releases: []*domain.Release{
+				announce("mock", domain.ReleaseImplementationIRC, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationTorznab, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationIRC, "That.Show.S01E03.1080p.WEB.h264-GROUP", seen),
+			},
+			want: []domain.ReleaseConsistencyReport{
+				{Indexer: "mock", IRCCount: 2, FeedCount: 1, MissedByIRC: []string{}, MissedByFeed: []string{"that.show.s01e03.1080p.web.h264-group"}},
+			},
+		},
+		{
+			name: "within_grace_period",
+			releases: []*domain.Release{
+				announce("mock", domain.ReleaseImplementationIRC, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationRSS, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationIRC, "That.Show.S01E04.1080p.WEB.h264-GROUP", now.Add(-5*time.Minute)),
+			},
+			want: []domain.ReleaseConsistencyReport{
+				{Indexer: "mock", IRCCount: 2, FeedCount: 1, MissedByIRC: []string{}, MissedByFeed: []string{}},
+			},
+		},
+		{
+			name: "single_source",
+			releases: []*domain.Release{
+				announce("mock", domain.ReleaseImplementationIRC, "Warmup", warmup),
+				announce("mock", domain.ReleaseImplementationIRC, "That.Show.S01E05.1080p.WEB.h264-GROUP", seen),
+			},
+			want: []domain.ReleaseConsistencyReport{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newAnnounceHistory(zerolog.Nop(), &mockAnnounceHistoryRepo{}, 2, 1)
+
+			for _, rls := range tt.releases {
+				h.add(rls)
+			}
+
+			got, err := h.checkConsistency(context.Background(), now.Add(-consistencyGracePeriod))
+			assert.NoError(t, err)
+
+			for i := range got {
+				got[i].CheckedAt = time.Time{}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
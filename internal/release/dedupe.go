@@ -0,0 +1,137 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// dedupeEntry is a release pushed within the dedupe window
+type dedupeEntry struct {
+	filterName string
+	indexer    string
+	expires    time.Time
+}
+
+// pushDedupe suppresses pushes of the same release by other filters or indexers within the window.
+// Releases are matched by normalized title and, when known, by infohash.
+type pushDedupe struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*dedupeEntry
+}
+
+func newPushDedupe(window time.Duration) *pushDedupe {
+	return &pushDedupe{
+		window:  window,
+		entries: map[string]*dedupeEntry{},
+	}
+}
+
+// dedupeClaim holds the keys of a release until it is pushed or given back
+type dedupeClaim struct {
+	keys  []string
+	entry *dedupeEntry
+}
+
+// normalizeDedupeTitle drops case and separators so "Show.Name.S01E01-GRP" and "Show Name S01E01 GRP" match
+func normalizeDedupeTitle(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, title)
+}
+
+func dedupeKeys(release *domain.Release) []string {
+	var keys []string
+
+	if title := normalizeDedupeTitle(release.TorrentName); title != "" {
+		keys = append(keys, "title:"+title)
+	}
+
+	if release.TorrentHash != "" {
+		keys = append(keys, "hash:"+strings.ToLower(release.TorrentHash))
+	}
+
+	return keys
+}
+
+// claim marks the release as pushed by the filter. It returns the entry of the earlier push when the
+// release is a duplicate within the window.
+func (d *pushDedupe) claim(release *domain.Release, filterName string, now time.Time) (*dedupeClaim, *dedupeEntry) {
+	keys := dedupeKeys(release)
+	if len(keys) == 0 {
+		return &dedupeClaim{}, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pruneLocked(now)
+
+	for _, key := range keys {
+		if entry, ok := d.entries[key]; ok {
+			return nil, entry
+		}
+	}
+
+	entry := &dedupeEntry{
+		filterName: filterName,
+		indexer:    release.Indexer.Identifier,
+		expires:    now.Add(d.window),
+	}
+
+	for _, key := range keys {
+		d.entries[key] = entry
+	}
+
+	return &dedupeClaim{keys: keys, entry: entry}, nil
+}
+
+// confirm adds the infohash of the pushed release, it is only known once the torrent is downloaded
+func (d *pushDedupe) confirm(c *dedupeClaim, release *domain.Release) {
+	if c.entry == nil || release.TorrentHash == "" {
+		return
+	}
+
+	key := "hash:" + strings.ToLower(release.TorrentHash)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.entries[key]; !ok {
+		d.entries[key] = c.entry
+		c.keys = append(c.keys, key)
+	}
+}
+
+// release gives back the claim when the release was not pushed
+func (d *pushDedupe) release(c *dedupeClaim) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range c.keys {
+		// the key could be claimed again after it expired
+		if d.entries[key] == c.entry {
+			delete(d.entries, key)
+		}
+	}
+}
+
+// pruneLocked must be called with the lock held
+func (d *pushDedupe) pruneLocked(now time.Time) {
+	for key, entry := range d.entries {
+		if !now.Before(entry.expires) {
+			delete(d.entries, key)
+		}
+	}
+}
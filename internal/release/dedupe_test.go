@@ -0,0 +1,81 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dedupeRelease(indexer, name, hash string) *domain.Release {
+	return &domain.Release{
+		Indexer:     domain.IndexerMinimal{Identifier: indexer},
+		TorrentName: name,
+		TorrentHash: hash,
+	}
+}
+
+func TestNormalizeDedupeTitle(t *testing.T) {
+	assert.Equal(t, "shownames01e011080pwebdlh264grp", normalizeDedupeTitle("Show.Name.S01E01.1080p.WEB-DL.H264-GRP"))
+	assert.Equal(t, normalizeDedupeTitle("Show.Name.S01E01.1080p.WEB-DL.H264-GRP"), normalizeDedupeTitle("Show Name S01E01 1080p WEB DL H264 GRP"))
+	assert.Empty(t, normalizeDedupeTitle(" .-_ "))
+}
+
+func TestPushDedupe(t *testing.T) {
+	now := time.Now()
+
+	t.Run("duplicate_title", func(t *testing.T) {
+		d := newPushDedupe(time.Minute)
+
+		claim, dupe := d.claim(dedupeRelease("a", "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", ""), "tv", now)
+		assert.NotNil(t, claim)
+		assert.Nil(t, dupe)
+
+		claim, dupe = d.claim(dedupeRelease("b", "Show Name S01E01 1080p WEB-DL H264-GRP", ""), "tv-other", now.Add(10*time.Second))
+		assert.Nil(t, claim)
+		if assert.NotNil(t, dupe) {
+			assert.Equal(t, "tv", dupe.filterName)
+			assert.Equal(t, "a", dupe.indexer)
+		}
+	})
+
+	t.Run("duplicate_hash", func(t *testing.T) {
+		d := newPushDedupe(time.Minute)
+
+		claim, _ := d.claim(dedupeRelease("a", "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", ""), "tv", now)
+
+		// the hash is only known after the push
+		d.confirm(claim, dedupeRelease("a", "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", "ABCDEF"))
+
+		_, dupe := d.claim(dedupeRelease("b", "Show.Name.S01E01.REPACK.1080p.WEB-DL.H264-GRP", "abcdef"), "tv", now)
+		assert.NotNil(t, dupe)
+	})
+
+	t.Run("window_expired", func(t *testing.T) {
+		d := newPushDedupe(time.Minute)
+
+		d.claim(dedupeRelease("a", "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", ""), "tv", now)
+
+		claim, dupe := d.claim(dedupeRelease("b", "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", ""), "tv", now.Add(time.Minute))
+		assert.NotNil(t, claim)
+		assert.Nil(t, dupe)
+	})
+
+	t.Run("released_claim", func(t *testing.T) {
+		d := newPushDedupe(time.Minute)
+
+		// nothing was pushed so the next filter can try
+		claim, _ := d.claim(dedupeRelease("a", "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", ""), "tv", now)
+		d.release(claim)
+
+		claim, dupe := d.claim(dedupeRelease("a", "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", ""), "tv-other", now)
+		assert.NotNil(t, claim)
+		assert.Nil(t, dupe)
+		assert.Len(t, d.entries, 1)
+	})
+}
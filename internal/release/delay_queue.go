@@ -0,0 +1,172 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// delayedRelease is a release waiting for the delay of its filter
+type delayedRelease struct {
+	pending *domain.ReleasePending
+
+	// release and filter are nil for releases restored after a restart until processed
+	release *domain.Release
+	filter  *domain.Filter
+
+	// expired is set when the delay passed, instead of the release being pushed before
+	expired bool
+
+	timer *time.Timer
+}
+
+// delayQueue holds matched releases until the delay of their filter passed. The releases are stored
+// so they are restored after a restart, and can be listed, cancelled or pushed before the delay passed.
+type delayQueue struct {
+	log  zerolog.Logger
+	repo domain.ReleasePendingRepo
+
+	mu    sync.Mutex
+	items map[int64]*delayedRelease
+
+	// process runs the actions for a delayed release once its delay passed
+	process func(item *delayedRelease)
+}
+
+func newDelayQueue(log zerolog.Logger, repo domain.ReleasePendingRepo, process func(item *delayedRelease)) *delayQueue {
+	return &delayQueue{
+		log:     log,
+		repo:    repo,
+		items:   map[int64]*delayedRelease{},
+		process: process,
+	}
+}
+
+// add stores the release and schedules its actions for runAt
+func (q *delayQueue) add(ctx context.Context, release *domain.Release, filter *domain.Filter, runAt time.Time) error {
+	pending := domain.NewReleasePending(release, runAt)
+
+	if err := q.repo.Store(ctx, pending); err != nil {
+		return errors.Wrap(err, "could not store delayed release")
+	}
+
+	q.schedule(&delayedRelease{pending: pending, release: release, filter: filter})
+
+	return nil
+}
+
+// restore schedules the releases stored before a restart, those past their delay are processed right away
+func (q *delayQueue) restore(ctx context.Context) error {
+	pending, err := q.repo.List(ctx, domain.ReleasePendingTypeDelay)
+	if err != nil {
+		return errors.Wrap(err, "could not list delayed releases")
+	}
+
+	for _, p := range pending {
+		q.schedule(&delayedRelease{pending: p})
+	}
+
+	if len(pending) > 0 {
+		q.log.Info().Msgf("restored %d delayed releases", len(pending))
+	}
+
+	return nil
+}
+
+func (q *delayQueue) schedule(item *delayedRelease) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	id := item.pending.ID
+
+	item.timer = time.AfterFunc(max(time.Until(item.pending.RunAt), 0), func() {
+		if err := q.run(id, true); err != nil && !errors.Is(err, domain.ErrRecordNotFound) {
+			q.log.Error().Err(err).Msgf("could not process delayed release: %s", item.pending.TorrentName)
+		}
+	})
+
+	q.items[id] = item
+}
+
+// list returns the delayed releases ordered by when their actions run
+func (q *delayQueue) list() []domain.ReleasePending {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make([]domain.ReleasePending, 0, len(q.items))
+	for _, item := range q.items {
+		pending = append(pending, *item.pending)
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RunAt.Before(pending[j].RunAt)
+	})
+
+	return pending
+}
+
+// take removes the release from the queue, it returns nil when already processed or cancelled
+func (q *delayQueue) take(id int64) *delayedRelease {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item, ok := q.items[id]
+	if !ok {
+		return nil
+	}
+
+	item.timer.Stop()
+	delete(q.items, id)
+
+	return item
+}
+
+// push runs the actions for the release without waiting for the rest of the delay
+func (q *delayQueue) push(id int64) error {
+	return q.run(id, false)
+}
+
+// run removes the release from the queue and runs its actions
+func (q *delayQueue) run(id int64, expired bool) error {
+	item := q.take(id)
+	if item == nil {
+		return domain.ErrRecordNotFound
+	}
+
+	item.expired = expired
+
+	if err := q.repo.Delete(context.Background(), id); err != nil {
+		q.log.Error().Err(err).Msgf("could not delete delayed release: %s", item.pending.TorrentName)
+	}
+
+	q.process(item)
+
+	return nil
+}
+
+// cancel drops the release, its actions don't run
+func (q *delayQueue) cancel(ctx context.Context, id int64) error {
+	item := q.take(id)
+	if item == nil {
+		return domain.ErrRecordNotFound
+	}
+
+	if err := q.repo.Delete(ctx, id); err != nil {
+		return errors.Wrap(err, "could not delete delayed release")
+	}
+
+	if item.release != nil {
+		item.release.CleanupTemporaryFiles()
+	}
+
+	return nil
+}
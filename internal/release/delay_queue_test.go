@@ -0,0 +1,192 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReleasePendingRepo struct {
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]*domain.ReleasePending
+}
+
+func newMockReleasePendingRepo(pending ...*domain.ReleasePending) *mockReleasePendingRepo {
+	r := &mockReleasePendingRepo{pending: map[int64]*domain.ReleasePending{}}
+	for _, p := range pending {
+		_ = r.Store(context.Background(), p)
+	}
+	return r
+}
+
+func (r *mockReleasePendingRepo) List(ctx context.Context, pendingType domain.ReleasePendingType) ([]*domain.ReleasePending, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []*domain.ReleasePending
+	for _, p := range r.pending {
+		if pendingType != "" && p.Type != pendingType {
+			continue
+		}
+		pending = append(pending, p)
+	}
+	return pending, nil
+}
+
+func (r *mockReleasePendingRepo) Get(ctx context.Context, id int64) (*domain.ReleasePending, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.pending[id]
+	if !ok {
+		return nil, domain.ErrRecordNotFound
+	}
+	return p, nil
+}
+
+func (r *mockReleasePendingRepo) Counts(ctx context.Context) (*domain.ReleasePendingCounts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := &domain.ReleasePendingCounts{}
+	for _, p := range r.pending {
+		switch p.Type {
+		case domain.ReleasePendingTypeDelay:
+			counts.Delay++
+		case domain.ReleasePendingTypeApproval:
+			counts.Approval++
+		}
+	}
+	return counts, nil
+}
+
+func (r *mockReleasePendingRepo) Store(ctx context.Context, pending *domain.ReleasePending) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	pending.ID = r.nextID
+	r.pending[pending.ID] = pending
+	return nil
+}
+
+func (r *mockReleasePendingRepo) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pending[id]; !ok {
+		return domain.ErrRecordNotFound
+	}
+
+	delete(r.pending, id)
+	return nil
+}
+
+func (r *mockReleasePendingRepo) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.pending)
+}
+
+func TestDelayQueue(t *testing.T) {
+	newQueue := func(repo domain.ReleasePendingRepo) (*delayQueue, chan *delayedRelease) {
+		processed := make(chan *delayedRelease, 4)
+		return newDelayQueue(zerolog.Nop(), repo, func(item *delayedRelease) {
+			processed <- item
+		}), processed
+	}
+
+	release := &domain.Release{ID: 1, TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", FilterID: 1, FilterName: "tv"}
+	filter := &domain.Filter{ID: 1, Name: "tv"}
+
+	t.Run("delay_passed", func(t *testing.T) {
+		repo := newMockReleasePendingRepo()
+		q, processed := newQueue(repo)
+
+		assert.NoError(t, q.add(context.Background(), release, filter, time.Now().Add(20*time.Millisecond)))
+		assert.Len(t, q.list(), 1)
+		assert.Equal(t, 1, repo.len())
+
+		select {
+		case item := <-processed:
+			assert.Same(t, release, item.release)
+			assert.Same(t, filter, item.filter)
+			assert.True(t, item.expired)
+		case <-time.After(time.Second):
+			t.Fatal("delayed release not processed")
+		}
+
+		assert.Empty(t, q.list())
+		assert.Equal(t, 0, repo.len())
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		repo := newMockReleasePendingRepo()
+		q, processed := newQueue(repo)
+
+		assert.NoError(t, q.add(context.Background(), release, filter, time.Now().Add(20*time.Millisecond)))
+
+		pending := q.list()
+		assert.NoError(t, q.cancel(context.Background(), pending[0].ID))
+		assert.ErrorIs(t, q.cancel(context.Background(), pending[0].ID), domain.ErrRecordNotFound)
+		assert.Equal(t, 0, repo.len())
+
+		select {
+		case <-processed:
+			t.Fatal("cancelled release processed")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("push_now", func(t *testing.T) {
+		repo := newMockReleasePendingRepo()
+		q, processed := newQueue(repo)
+
+		assert.NoError(t, q.add(context.Background(), release, filter, time.Now().Add(time.Hour)))
+
+		pending := q.list()
+		assert.NoError(t, q.push(pending[0].ID))
+		assert.Len(t, processed, 1)
+		assert.False(t, (<-processed).expired)
+		assert.Empty(t, q.list())
+		assert.Equal(t, 0, repo.len())
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		now := time.Now()
+
+		repo := newMockReleasePendingRepo(
+			&domain.ReleasePending{Type: domain.ReleasePendingTypeDelay, ReleaseID: 1, FilterID: 1, RunAt: now.Add(-time.Minute)},
+			&domain.ReleasePending{Type: domain.ReleasePendingTypeDelay, ReleaseID: 2, FilterID: 1, RunAt: now.Add(time.Hour)},
+			&domain.ReleasePending{Type: domain.ReleasePendingTypeApproval, ReleaseID: 3, FilterID: 1, ActionID: 1, RunAt: now.Add(-time.Minute)},
+		)
+		q, processed := newQueue(repo)
+
+		assert.NoError(t, q.restore(context.Background()))
+
+		// the release past its delay is processed right away, to be loaded by the service
+		select {
+		case item := <-processed:
+			assert.Equal(t, int64(1), item.pending.ReleaseID)
+			assert.Nil(t, item.release)
+		case <-time.After(time.Second):
+			t.Fatal("restored release not processed")
+		}
+
+		pending := q.list()
+		if assert.Len(t, pending, 1) {
+			assert.Equal(t, int64(2), pending[0].ReleaseID)
+		}
+	})
+}
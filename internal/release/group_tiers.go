@@ -0,0 +1,173 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// groupTierItemTTL is how long an episode or movie is remembered after its longest tier delay,
+// later releases of a less preferred group are rejected until then
+const groupTierItemTTL = 24 * time.Hour
+
+type groupTierDecision int
+
+const (
+	groupTierPush groupTierDecision = iota
+	groupTierHold
+	groupTierReject
+)
+
+// heldRelease is a release waiting for the delay of its tier
+type heldRelease struct {
+	tier    int
+	release *domain.Release
+	filter  *domain.Filter
+	timer   *time.Timer
+}
+
+// groupTierItem tracks the releases of one episode or movie for a filter
+type groupTierItem struct {
+	firstSeen time.Time
+	expires   time.Time
+
+	pending *heldRelease
+
+	// ready is the held release processed again once its delay passed
+	ready *domain.Release
+
+	// pushedTier is the best tier pushed, -1 when none was
+	pushedTier int
+}
+
+// groupTierQueue holds matches of less preferred groups until the delay of their tier has passed since
+// the first release was seen, and cancels them when a release of a more preferred group arrives.
+type groupTierQueue struct {
+	mu    sync.Mutex
+	items map[string]*groupTierItem
+
+	// process runs the actions for a held release once its delay passed
+	process func(hold *heldRelease)
+}
+
+func newGroupTierQueue(process func(hold *heldRelease)) *groupTierQueue {
+	return &groupTierQueue{
+		items:   map[string]*groupTierItem{},
+		process: process,
+	}
+}
+
+// groupTierKey identifies the episode or movie regardless of the group that released it
+func groupTierKey(f *domain.Filter, r *domain.Release) string {
+	return fmt.Sprintf("%d|%s|%d|%d|%d|%d|%d", f.ID, strings.ToLower(r.Title), r.Season, r.Episode, r.Year, r.Month, r.Day)
+}
+
+// decide returns whether the release is pushed now, held for a more preferred group or rejected
+func (q *groupTierQueue) decide(f *domain.Filter, r *domain.Release, now time.Time) (groupTierDecision, string) {
+	tier := f.GroupTier(r.Group)
+	if tier < 0 {
+		return groupTierReject, fmt.Sprintf("group tiers: group %q not in any tier", r.Group)
+	}
+
+	key := groupTierKey(f, r)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pruneLocked(now)
+
+	item, ok := q.items[key]
+	if !ok {
+		item = &groupTierItem{firstSeen: now, pushedTier: -1}
+		item.expires = now.Add(f.GroupTiers[len(f.GroupTiers)-1].Delay() + groupTierItemTTL)
+		q.items[key] = item
+	}
+
+	// the delay of the held release passed
+	if item.ready == r {
+		item.ready = nil
+		item.pushedTier = tier
+		return groupTierPush, ""
+	}
+
+	if item.pushedTier >= 0 && tier >= item.pushedTier {
+		return groupTierReject, fmt.Sprintf("group tiers: already grabbed from tier %d", item.pushedTier+1)
+	}
+
+	deadline := item.firstSeen.Add(f.GroupTiers[tier].Delay())
+	if !now.Before(deadline) {
+		// a pending release waits for a later deadline so it's from a less preferred tier
+		q.cancelLocked(item)
+		item.pushedTier = tier
+		return groupTierPush, ""
+	}
+
+	if item.pending != nil {
+		if item.pending.tier <= tier {
+			return groupTierReject, fmt.Sprintf("group tiers: release from tier %d already waiting", item.pending.tier+1)
+		}
+
+		q.cancelLocked(item)
+	}
+
+	hold := &heldRelease{tier: tier, release: r, filter: f}
+	hold.timer = time.AfterFunc(deadline.Sub(now), func() {
+		q.fire(key, hold)
+	})
+	item.pending = hold
+
+	return groupTierHold, ""
+}
+
+// notPushed lets a later release of the same tier try again when the actions did not push the release
+func (q *groupTierQueue) notPushed(f *domain.Filter, r *domain.Release) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if item, ok := q.items[groupTierKey(f, r)]; ok && item.pushedTier == f.GroupTier(r.Group) {
+		item.pushedTier = -1
+	}
+}
+
+func (q *groupTierQueue) fire(key string, hold *heldRelease) {
+	q.mu.Lock()
+
+	item, ok := q.items[key]
+	if !ok || item.pending != hold {
+		// cancelled by a release of a more preferred group
+		q.mu.Unlock()
+		return
+	}
+
+	item.pending = nil
+	item.ready = hold.release
+
+	q.mu.Unlock()
+
+	q.process(hold)
+}
+
+// cancelLocked must be called with the lock held
+func (q *groupTierQueue) cancelLocked(item *groupTierItem) {
+	if item.pending == nil {
+		return
+	}
+
+	item.pending.timer.Stop()
+	item.pending = nil
+}
+
+// pruneLocked must be called with the lock held
+func (q *groupTierQueue) pruneLocked(now time.Time) {
+	for key, item := range q.items {
+		if item.pending == nil && now.After(item.expires) {
+			delete(q.items, key)
+		}
+	}
+}
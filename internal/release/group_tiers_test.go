@@ -0,0 +1,117 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func groupTierFilter() *domain.Filter {
+	return &domain.Filter{
+		ID:   1,
+		Name: "tiers",
+		GroupTiers: []domain.FilterGroupTier{
+			{Groups: []string{"GROUP-A"}},
+			{Groups: []string{"GROUP-B"}, DelayMinutes: 30},
+			{Groups: []string{"*"}, DelayMinutes: 120},
+		},
+	}
+}
+
+func groupTierRelease(group string) *domain.Release {
+	return &domain.Release{TorrentName: "Show.Name.S01E01.1080p.WEB-DL.x264-" + group, Title: "Show Name", Season: 1, Episode: 1, Group: group}
+}
+
+func TestGroupTierQueue(t *testing.T) {
+	t.Run("preferred_group_pushed_immediately", func(t *testing.T) {
+		q := newGroupTierQueue(func(hold *heldRelease) { t.Fatal("unexpected hold") })
+		f := groupTierFilter()
+		now := time.Now()
+
+		decision, _ := q.decide(f, groupTierRelease("GROUP-A"), now)
+		assert.Equal(t, groupTierPush, decision)
+
+		decision, reason := q.decide(f, groupTierRelease("GROUP-B"), now.Add(time.Hour))
+		assert.Equal(t, groupTierReject, decision)
+		assert.Equal(t, "group tiers: already grabbed from tier 1", reason)
+	})
+
+	t.Run("fallback_cancelled_by_preferred_group", func(t *testing.T) {
+		q := newGroupTierQueue(func(hold *heldRelease) { t.Fatal("cancelled hold processed") })
+		f := groupTierFilter()
+		now := time.Now()
+
+		decision, _ := q.decide(f, groupTierRelease("OTHER"), now)
+		assert.Equal(t, groupTierHold, decision)
+
+		decision, _ = q.decide(f, groupTierRelease("GROUP-A"), now.Add(time.Minute))
+		assert.Equal(t, groupTierPush, decision)
+
+		item := q.items[groupTierKey(f, groupTierRelease("OTHER"))]
+		assert.Nil(t, item.pending)
+	})
+
+	t.Run("held_release_processed_after_delay", func(t *testing.T) {
+		processed := make(chan *heldRelease, 1)
+
+		var q *groupTierQueue
+		q = newGroupTierQueue(func(hold *heldRelease) {
+			// like processFilters checking the filter again
+			decision, _ := q.decide(hold.filter, hold.release, time.Now())
+			assert.Equal(t, groupTierPush, decision)
+			processed <- hold
+		})
+		f := groupTierFilter()
+		start := time.Now().Add(-30*time.Minute + 50*time.Millisecond)
+
+		// the fallback waits two hours, then GROUP-B replaces it and is due in 50ms
+		decision, _ := q.decide(f, groupTierRelease("OTHER"), start)
+		assert.Equal(t, groupTierHold, decision)
+
+		groupB := groupTierRelease("GROUP-B")
+		decision, _ = q.decide(f, groupB, time.Now())
+		assert.Equal(t, groupTierHold, decision)
+
+		decision, reason := q.decide(f, groupTierRelease("OTHER"), time.Now())
+		assert.Equal(t, groupTierReject, decision)
+		assert.Equal(t, "group tiers: release from tier 2 already waiting", reason)
+
+		select {
+		case hold := <-processed:
+			assert.Same(t, groupB, hold.release)
+		case <-time.After(5 * time.Second):
+			t.Fatal("held release not processed")
+		}
+	})
+
+	t.Run("not_pushed", func(t *testing.T) {
+		q := newGroupTierQueue(nil)
+		f := groupTierFilter()
+		now := time.Now()
+
+		decision, _ := q.decide(f, groupTierRelease("GROUP-A"), now)
+		assert.Equal(t, groupTierPush, decision)
+
+		// the client rejected it, the next release of the tier can try
+		q.notPushed(f, groupTierRelease("GROUP-A"))
+
+		decision, _ = q.decide(f, groupTierRelease("GROUP-A"), now)
+		assert.Equal(t, groupTierPush, decision)
+	})
+
+	t.Run("group_not_in_tiers", func(t *testing.T) {
+		q := newGroupTierQueue(nil)
+		f := groupTierFilter()
+		f.GroupTiers = f.GroupTiers[:2]
+
+		decision, reason := q.decide(f, groupTierRelease("OTHER"), time.Now())
+		assert.Equal(t, groupTierReject, decision)
+		assert.Equal(t, `group tiers: group "OTHER" not in any tier`, reason)
+	})
+}
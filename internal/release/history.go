@@ -0,0 +1,303 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+// spillQueueSize is the number of batches waiting to be written before announces block
+const spillQueueSize = 4
+
+// spillRequest is a batch to write, done is closed once the batch and everything queued before it was written
+type spillRequest struct {
+	batch []domain.AnnounceHistoryEntry
+	done  chan struct{}
+}
+
+// announceSourceSeen is when a source of an indexer was first and last seen
+type announceSourceSeen struct {
+	first time.Time
+	last  time.Time
+}
+
+// announceHistory keeps recently seen releases in a fixed size ring buffer.
+// When the buffer is full the oldest entries are spilled to the database in batches by a background worker,
+// the worker runs until stop.
+type announceHistory struct {
+	log  zerolog.Logger
+	repo domain.AnnounceHistoryRepo
+
+	mu      sync.Mutex
+	buf     []domain.AnnounceHistoryEntry
+	head    int
+	full    bool
+	pending []domain.AnnounceHistoryEntry
+
+	spillBatchSize int
+	spillQueue     chan spillRequest
+
+	// stopMu guards sending to the spill queue against closing it, batches of a stopped history are written directly
+	stopMu  sync.RWMutex
+	stopped bool
+	done    chan struct{}
+
+	// seen is used to only compare releases seen while both sources were active
+	seen map[string]map[domain.AnnounceSource]*announceSourceSeen
+}
+
+func newAnnounceHistory(log zerolog.Logger, repo domain.AnnounceHistoryRepo, size int, spillBatchSize int) *announceHistory {
+	if size <= 0 {
+		size = 1000
+	}
+
+	if spillBatchSize <= 0 || spillBatchSize > size {
+		spillBatchSize = size
+	}
+
+	h := &announceHistory{
+		log:            log,
+		repo:           repo,
+		buf:            make([]domain.AnnounceHistoryEntry, size),
+		pending:        make([]domain.AnnounceHistoryEntry, 0, spillBatchSize),
+		spillBatchSize: spillBatchSize,
+		spillQueue:     make(chan spillRequest, spillQueueSize),
+		done:           make(chan struct{}),
+		seen:           make(map[string]map[domain.AnnounceSource]*announceSourceSeen),
+	}
+
+	go h.spillWorker()
+
+	return h
+}
+
+func (h *announceHistory) add(rls *domain.Release) {
+	if rls.Indexer.Identifier == "" || rls.TorrentName == "" {
+		return
+	}
+
+	source := domain.AnnounceSourceFeed
+	if rls.Implementation == domain.ReleaseImplementationIRC {
+		source = domain.AnnounceSourceIRC
+	}
+
+	entry := domain.AnnounceHistoryEntry{
+		Indexer:     rls.Indexer.Identifier,
+		Source:      source,
+		TorrentName: normalizeAnnounceName(rls.TorrentName),
+		SeenAt:      rls.Timestamp,
+	}
+
+	h.mu.Lock()
+
+	indexer, ok := h.seen[entry.Indexer]
+	if !ok {
+		indexer = make(map[domain.AnnounceSource]*announceSourceSeen)
+		h.seen[entry.Indexer] = indexer
+	}
+
+	if seen, ok := indexer[source]; ok {
+		seen.last = entry.SeenAt
+	} else {
+		indexer[source] = &announceSourceSeen{first: entry.SeenAt, last: entry.SeenAt}
+	}
+
+	// evict the oldest entry before overwriting it
+	if h.full {
+		h.pending = append(h.pending, h.buf[h.head])
+	}
+
+	h.buf[h.head] = entry
+	h.head = (h.head + 1) % len(h.buf)
+	if h.head == 0 {
+		h.full = true
+	}
+
+	var batch []domain.AnnounceHistoryEntry
+	if len(h.pending) >= h.spillBatchSize {
+		batch = h.pending
+		h.pending = make([]domain.AnnounceHistoryEntry, 0, h.spillBatchSize)
+	}
+
+	h.mu.Unlock()
+
+	if len(batch) > 0 {
+		h.queueSpill(batch)
+	}
+}
+
+// queueSpill hands the batch to the worker, this only blocks when the database can't keep up
+func (h *announceHistory) queueSpill(batch []domain.AnnounceHistoryEntry) {
+	h.stopMu.RLock()
+	defer h.stopMu.RUnlock()
+
+	if h.stopped {
+		h.spill(batch)
+		return
+	}
+
+	h.spillQueue <- spillRequest{batch: batch}
+}
+
+func (h *announceHistory) spillWorker() {
+	defer close(h.done)
+
+	for req := range h.spillQueue {
+		if len(req.batch) > 0 {
+			h.spill(req.batch)
+		}
+
+		if req.done != nil {
+			close(req.done)
+		}
+	}
+}
+
+func (h *announceHistory) spill(batch []domain.AnnounceHistoryEntry) {
+	if err := h.repo.StoreMany(context.Background(), batch); err != nil {
+		h.log.Error().Err(err).Msgf("could not spill %d announce history entries to database", len(batch))
+		return
+	}
+
+	h.log.Trace().Msgf("spilled %d announce history entries to database", len(batch))
+}
+
+// flush waits for queued spills and writes pending evicted entries to the database
+func (h *announceHistory) flush(ctx context.Context) error {
+	if err := h.waitForSpills(ctx); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	batch := h.pending
+	h.pending = make([]domain.AnnounceHistoryEntry, 0, h.spillBatchSize)
+	h.mu.Unlock()
+
+	return h.repo.StoreMany(ctx, batch)
+}
+
+// waitForSpills waits until the worker wrote everything queued so far
+func (h *announceHistory) waitForSpills(ctx context.Context) error {
+	h.stopMu.RLock()
+	defer h.stopMu.RUnlock()
+
+	if h.stopped {
+		return nil
+	}
+
+	done := make(chan struct{})
+
+	select {
+	case h.spillQueue <- spillRequest{done: done}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop writes the queued and pending entries and ends the worker, later batches are written without it
+func (h *announceHistory) stop(ctx context.Context) error {
+	h.stopMu.Lock()
+	if !h.stopped {
+		h.stopped = true
+		close(h.spillQueue)
+	}
+	h.stopMu.Unlock()
+
+	select {
+	case <-h.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return h.flush(ctx)
+}
+
+// entries returns all entries seen since t, both spilled and in memory
+func (h *announceHistory) entries(ctx context.Context, since time.Time) ([]domain.AnnounceHistoryEntry, error) {
+	if err := h.flush(ctx); err != nil {
+		return nil, err
+	}
+
+	stored, err := h.repo.FindSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := stored
+
+	n := h.head
+	if h.full {
+		n = len(h.buf)
+	}
+
+	for i := 0; i < n; i++ {
+		entry := h.buf[i]
+		if entry.SeenAt.Before(since) {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// firstSeenSince returns when both IRC and feed were first seen for the indexer
+func (h *announceHistory) firstSeenSince(indexer string) (time.Time, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sources, ok := h.seen[indexer]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	irc, hasIRC := sources[domain.AnnounceSourceIRC]
+	feed, hasFeed := sources[domain.AnnounceSourceFeed]
+
+	if !hasIRC || !hasFeed {
+		return time.Time{}, false
+	}
+
+	if feed.first.After(irc.first) {
+		return feed.first, true
+	}
+
+	return irc.first, true
+}
+
+// prune removes spilled entries seen before t and forgets the sources which went quiet since
+func (h *announceHistory) prune(ctx context.Context, t time.Time) error {
+	h.mu.Lock()
+	for indexer, sources := range h.seen {
+		for source, seen := range sources {
+			if seen.last.Before(t) {
+				delete(sources, source)
+			}
+		}
+
+		if len(sources) == 0 {
+			delete(h.seen, indexer)
+		}
+	}
+	h.mu.Unlock()
+
+	return h.repo.DeleteOlderThan(ctx, t)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingAnnounceHistoryRepo blocks writes until unblocked to simulate a slow database
+type blockingAnnounceHistoryRepo struct {
+	mockAnnounceHistoryRepo
+	unblock chan struct{}
+}
+
+func (r *blockingAnnounceHistoryRepo) StoreMany(ctx context.Context, entries []domain.AnnounceHistoryEntry) error {
+	<-r.unblock
+	return r.mockAnnounceHistoryRepo.StoreMany(ctx, entries)
+}
+
+func TestAnnounceHistory_SpillAsync(t *testing.T) {
+	repo := &blockingAnnounceHistoryRepo{unblock: make(chan struct{})}
+	h := newAnnounceHistory(zerolog.Nop(), repo, 2, 1)
+
+	now := time.Now()
+
+	added := make(chan struct{})
+	go func() {
+		// fills the buffer and queues spills without waiting for the database
+		for i := 0; i < 2+spillQueueSize; i++ {
+			h.add(announce("mock", domain.ReleaseImplementationIRC, fmt.Sprintf("Release.%d", i), now))
+		}
+		close(added)
+	}()
+
+	select {
+	case <-added:
+	case <-time.After(5 * time.Second):
+		t.Fatal("announces blocked on the database write")
+	}
+
+	close(repo.unblock)
+
+	entries, err := h.entries(context.Background(), now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2+spillQueueSize)
+	assert.Len(t, repo.entries, spillQueueSize)
+}
+
+func TestAnnounceHistory_stop(t *testing.T) {
+	repo := &mockAnnounceHistoryRepo{}
+	h := newAnnounceHistory(zerolog.Nop(), repo, 2, 2)
+
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		h.add(announce("mock", domain.ReleaseImplementationIRC, fmt.Sprintf("Release.%d", i), now))
+	}
+
+	// the evicted entry waiting for a full batch is written on stop
+	assert.NoError(t, h.stop(context.Background()))
+	assert.Len(t, repo.entries, 1)
+
+	select {
+	case <-h.done:
+	default:
+		t.Fatal("spill worker still running")
+	}
+
+	// announces after stop are written without the worker
+	for i := 3; i < 5; i++ {
+		h.add(announce("mock", domain.ReleaseImplementationIRC, fmt.Sprintf("Release.%d", i), now))
+	}
+	assert.Len(t, repo.entries, 3)
+
+	entries, err := h.entries(context.Background(), now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5)
+
+	assert.NoError(t, h.stop(context.Background()))
+}
+
+func TestAnnounceHistory_prune(t *testing.T) {
+	h := newAnnounceHistory(zerolog.Nop(), &mockAnnounceHistoryRepo{}, 10, 1)
+	defer h.stop(context.Background())
+
+	now := time.Now()
+
+	h.add(announce("mock", domain.ReleaseImplementationIRC, "Release.1", now.Add(-2*time.Hour)))
+	h.add(announce("mock", domain.ReleaseImplementationRSS, "Release.1", now.Add(-2*time.Hour)))
+	h.add(announce("mock", domain.ReleaseImplementationIRC, "Release.2", now))
+	h.add(announce("quiet", domain.ReleaseImplementationIRC, "Release.3", now.Add(-2*time.Hour)))
+
+	since, ok := h.firstSeenSince("mock")
+	assert.True(t, ok)
+	assert.True(t, since.Equal(now.Add(-2*time.Hour)))
+
+	assert.NoError(t, h.prune(context.Background(), now.Add(-time.Hour)))
+
+	// the feed went quiet, so did the other indexer
+	_, ok = h.firstSeenSince("mock")
+	assert.False(t, ok)
+	assert.Len(t, h.seen, 1)
+	assert.Len(t, h.seen["mock"], 1)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	// latencyRecentWindow is compared against the whole period to detect regressions
+	latencyRecentWindow = 24 * time.Hour
+
+	// latencyRegressionFactor is how much slower the recent p95 may be before it is flagged
+	latencyRegressionFactor = 1.5
+
+	latencyRegressionMinSamples = 5
+)
+
+// LatencyStats returns push latency percentiles, from announce to client accepted, overall and per indexer for the last days
+func (s *service) LatencyStats(ctx context.Context, days int) (*domain.ReleaseLatencyStats, error) {
+	if days <= 0 {
+		days = 7
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+	recentSince := now.Add(-latencyRecentWindow)
+
+	latencies, err := s.repo.GetPushLatencies(ctx, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get push latencies")
+	}
+
+	stats := &domain.ReleaseLatencyStats{
+		Since: since,
+	}
+
+	if len(latencies) > domain.ReleasePushLatencySampleLimit {
+		latencies = latencies[:domain.ReleasePushLatencySampleLimit]
+
+		sampleSince := latencies[len(latencies)-1].Timestamp
+		stats.SampleCapped = true
+		stats.SampleSince = &sampleSince
+
+		s.log.Debug().Msgf("push latency sample capped at %d pushes since %s", domain.ReleasePushLatencySampleLimit, sampleSince.Format(time.RFC3339))
+	}
+
+	all := make([]int64, 0, len(latencies))
+	byIndexer := make(map[string][]int64)
+	recentByIndexer := make(map[string][]int64)
+
+	for _, l := range latencies {
+		all = append(all, l.LatencyMs)
+		byIndexer[l.Indexer] = append(byIndexer[l.Indexer], l.LatencyMs)
+
+		if l.Timestamp.After(recentSince) {
+			recentByIndexer[l.Indexer] = append(recentByIndexer[l.Indexer], l.LatencyMs)
+		}
+	}
+
+	stats.Overall = domain.NewLatencyPercentiles(all)
+	stats.Indexers = make([]domain.IndexerLatencyStats, 0, len(byIndexer))
+
+	for indexer, values := range byIndexer {
+		indexerStats := domain.IndexerLatencyStats{
+			Indexer: indexer,
+			Latency: domain.NewLatencyPercentiles(values),
+			Recent:  domain.NewLatencyPercentiles(recentByIndexer[indexer]),
+		}
+
+		indexerStats.Regression = isLatencyRegression(indexerStats.Latency, indexerStats.Recent)
+		if indexerStats.Regression {
+			s.log.Warn().Msgf("indexer %s: push latency regression, p95 last 24h %dms compared to %dms over %d days", indexer, indexerStats.Recent.P95, indexerStats.Latency.P95, days)
+		}
+
+		stats.Indexers = append(stats.Indexers, indexerStats)
+	}
+
+	sort.Slice(stats.Indexers, func(i, j int) bool {
+		return stats.Indexers[i].Indexer < stats.Indexers[j].Indexer
+	})
+
+	return stats, nil
+}
+
+func isLatencyRegression(baseline, recent domain.LatencyPercentiles) bool {
+	// nothing to compare against when all samples are recent
+	if recent.Count < latencyRegressionMinSamples || baseline.Count <= recent.Count {
+		return false
+	}
+
+	return float64(recent.P95) > float64(baseline.P95)*latencyRegressionFactor
+}
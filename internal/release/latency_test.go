@@ -0,0 +1,69 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLatencyRepo only implements GetPushLatencies, calling anything else panics
+type mockLatencyRepo struct {
+	domain.ReleaseRepo
+	latencies []domain.ReleasePushLatency
+}
+
+func (r *mockLatencyRepo) GetPushLatencies(ctx context.Context, since time.Time) ([]domain.ReleasePushLatency, error) {
+	latencies := r.latencies
+	if len(latencies) > domain.ReleasePushLatencySampleLimit+1 {
+		latencies = latencies[:domain.ReleasePushLatencySampleLimit+1]
+	}
+	return latencies, nil
+}
+
+func newestFirstLatencies(n int, now time.Time) []domain.ReleasePushLatency {
+	latencies := make([]domain.ReleasePushLatency, 0, n)
+	for i := 0; i < n; i++ {
+		latencies = append(latencies, domain.ReleasePushLatency{
+			Indexer:   "mock",
+			LatencyMs: int64(100 + i%100),
+			Timestamp: now.Add(-time.Duration(i) * time.Second),
+		})
+	}
+	return latencies
+}
+
+func TestService_LatencyStats(t *testing.T) {
+	now := time.Now()
+
+	t.Run("full_sample", func(t *testing.T) {
+		s := &service{log: zerolog.Nop(), repo: &mockLatencyRepo{latencies: newestFirstLatencies(200, now)}}
+
+		stats, err := s.LatencyStats(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.False(t, stats.SampleCapped)
+		assert.Nil(t, stats.SampleSince)
+		assert.Equal(t, 200, stats.Overall.Count)
+		assert.Len(t, stats.Indexers, 1)
+	})
+
+	t.Run("capped_sample", func(t *testing.T) {
+		latencies := newestFirstLatencies(domain.ReleasePushLatencySampleLimit+500, now)
+		s := &service{log: zerolog.Nop(), repo: &mockLatencyRepo{latencies: latencies}}
+
+		stats, err := s.LatencyStats(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.True(t, stats.SampleCapped)
+		assert.Equal(t, domain.ReleasePushLatencySampleLimit, stats.Overall.Count)
+		if assert.NotNil(t, stats.SampleSince) {
+			assert.Equal(t, latencies[domain.ReleasePushLatencySampleLimit-1].Timestamp, *stats.SampleSince)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	removalCheckInterval = 30 * time.Minute
+
+	// removalGracePeriod gives clients time to add pushed torrents before missing ones are no longer tracked
+	removalGracePeriod = 1 * time.Hour
+)
+
+type RemovalJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+// Run removes the torrents that reached a limit of the removal policy of their action from the clients
+func (j *RemovalJob) Run() {
+	if err := j.svc.enforceRemovalPolicies(context.Background()); err != nil {
+		j.Log.Error().Err(err).Msg("could not enforce removal policies")
+	}
+}
+
+// enforceRemovalPolicies asks the clients for the seeding state of the torrents pushed by actions with a removal policy
+// and removes the ones that reached a limit. Torrents removed from the client some other way are no longer tracked.
+func (s *service) enforceRemovalPolicies(ctx context.Context) error {
+	pushes, err := s.repo.FindRemovablePushes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not find removable pushes")
+	}
+
+	byClient := make(map[int32][]domain.ReleaseRemovablePush)
+	for _, push := range pushes {
+		if push.ClientID == 0 {
+			continue
+		}
+
+		byClient[push.ClientID] = append(byClient[push.ClientID], push)
+	}
+
+	for clientID, clientPushes := range byClient {
+		hashes := make([]string, 0, len(clientPushes))
+		for _, push := range clientPushes {
+			hashes = append(hashes, push.InfoHash)
+		}
+
+		states, err := s.actionSvc.TorrentStates(ctx, clientID, hashes)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("could not check removal policies of %d torrents on client %d", len(hashes), clientID)
+			continue
+		}
+
+		now := time.Now()
+
+		for _, push := range clientPushes {
+			state, ok := states[strings.ToLower(push.InfoHash)]
+			if !ok {
+				if now.Sub(push.Timestamp) < removalGracePeriod {
+					continue
+				}
+
+				s.log.Debug().Msgf("release %s is no longer on client %s, stop tracking it for removal", push.TorrentName, push.Client)
+
+				if err := s.repo.SetActionStatusRemoved(ctx, push.ActionStatusID, now); err != nil {
+					s.log.Error().Err(err).Msgf("could not store removal of release: %s", push.TorrentName)
+				}
+				continue
+			}
+
+			reason := push.RemovalReason(state, now)
+			if reason == "" {
+				continue
+			}
+
+			if err := s.actionSvc.RemoveTorrent(ctx, clientID, push.InfoHash, push.RemoveDeleteData); err != nil {
+				s.log.Error().Err(err).Msgf("could not remove release %s from client %s", push.TorrentName, push.Client)
+				continue
+			}
+
+			s.log.Info().Msgf("removed release %s from client %s by removal policy of action %s: %s", push.TorrentName, push.Client, push.Action, reason)
+
+			if err := s.repo.SetActionStatusRemoved(ctx, push.ActionStatusID, now); err != nil {
+				s.log.Error().Err(err).Msgf("could not store removal of release: %s", push.TorrentName)
+			}
+		}
+	}
+
+	return nil
+}
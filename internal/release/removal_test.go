@@ -0,0 +1,79 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRemovalRepo only implements the removable push methods, calling anything else panics
+type mockRemovalRepo struct {
+	domain.ReleaseRepo
+	pushes  []domain.ReleaseRemovablePush
+	removed []int64
+}
+
+func (r *mockRemovalRepo) FindRemovablePushes(ctx context.Context) ([]domain.ReleaseRemovablePush, error) {
+	return r.pushes, nil
+}
+
+func (r *mockRemovalRepo) SetActionStatusRemoved(ctx context.Context, id int64, removedAt time.Time) error {
+	r.removed = append(r.removed, id)
+	return nil
+}
+
+type mockRemovalActionService struct {
+	action.Service
+	states  map[string]domain.TorrentState
+	removed []string
+}
+
+func (s *mockRemovalActionService) TorrentStates(ctx context.Context, clientID int32, hashes []string) (map[string]domain.TorrentState, error) {
+	return s.states, nil
+}
+
+func (s *mockRemovalActionService) RemoveTorrent(ctx context.Context, clientID int32, hash string, deleteData bool) error {
+	if deleteData {
+		hash += "+data"
+	}
+	s.removed = append(s.removed, hash)
+	return nil
+}
+
+func TestService_enforceRemovalPolicies(t *testing.T) {
+	now := time.Now()
+
+	repo := &mockRemovalRepo{
+		pushes: []domain.ReleaseRemovablePush{
+			{ActionStatusID: 1, ClientID: 1, InfoHash: "AAAA", Timestamp: now.Add(-48 * time.Hour), RemoveRatio: 1, RemoveDeleteData: true},
+			{ActionStatusID: 2, ClientID: 1, InfoHash: "bbbb", Timestamp: now.Add(-48 * time.Hour), RemoveRatio: 1},
+			{ActionStatusID: 3, ClientID: 1, InfoHash: "cccc", Timestamp: now.Add(-48 * time.Hour), RemoveAfterDays: 1},
+			// no longer in the client
+			{ActionStatusID: 4, ClientID: 1, InfoHash: "dddd", Timestamp: now.Add(-48 * time.Hour), RemoveRatio: 1},
+			// might not be added to the client yet
+			{ActionStatusID: 5, ClientID: 1, InfoHash: "eeee", Timestamp: now.Add(-time.Minute), RemoveRatio: 1},
+		},
+	}
+
+	actionSvc := &mockRemovalActionService{states: map[string]domain.TorrentState{
+		"aaaa": {Ratio: 1.2},
+		"bbbb": {Ratio: 0.4},
+		"cccc": {},
+	}}
+
+	s := &service{log: zerolog.Nop(), repo: repo, actionSvc: actionSvc}
+
+	assert.NoError(t, s.enforceRemovalPolicies(context.Background()))
+
+	assert.Equal(t, []string{"AAAA+data", "cccc"}, actionSvc.removed)
+	assert.Equal(t, []int64{1, 3, 4}, repo.removed)
+}
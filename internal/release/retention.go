@@ -0,0 +1,123 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const releaseRetentionJobName = "release-retention"
+
+func (s *service) GetRetention(ctx context.Context) (*domain.ReleaseRetention, error) {
+	retention, err := s.retentionRepo.Get(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get release retention")
+	}
+
+	if next, err := s.scheduler.GetNextRun(releaseRetentionJobName); err == nil && !next.IsZero() {
+		retention.NextRun = &next
+	}
+
+	return retention, nil
+}
+
+func (s *service) UpdateRetention(ctx context.Context, retention *domain.ReleaseRetention) error {
+	if retention.Schedule == "" {
+		retention.Schedule = domain.DefaultReleaseRetentionSchedule
+	}
+
+	if err := retention.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.retentionRepo.Update(ctx, retention); err != nil {
+		return errors.Wrap(err, "could not update release retention")
+	}
+
+	return s.scheduleRetention(retention)
+}
+
+// PruneReleases deletes releases outside the retention limits, the limits are used even if the job is disabled
+func (s *service) PruneReleases(ctx context.Context) (*domain.ReleasePruneResult, error) {
+	retention, err := s.retentionRepo.Get(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get release retention")
+	}
+
+	if retention.MaxAgeDays == 0 && retention.MaxRowsPerIndexer == 0 {
+		return &domain.ReleasePruneResult{}, nil
+	}
+
+	result, err := s.retentionRepo.Prune(ctx, retention.MaxAge(), retention.MaxRowsPerIndexer)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not prune releases")
+	}
+
+	if err := s.retentionRepo.StoreRun(ctx, time.Now(), result.Total()); err != nil {
+		s.log.Error().Err(err).Msg("could not store release retention run")
+	}
+
+	return result, nil
+}
+
+func (s *service) startRetention(ctx context.Context) error {
+	retention, err := s.retentionRepo.Get(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get release retention")
+	}
+
+	return s.scheduleRetention(retention)
+}
+
+// scheduleRetention replaces the pruning job with the schedule from retention
+func (s *service) scheduleRetention(retention *domain.ReleaseRetention) error {
+	if err := s.scheduler.RemoveJobByIdentifier(releaseRetentionJobName); err != nil {
+		return errors.Wrap(err, "could not remove job: %s", releaseRetentionJobName)
+	}
+
+	if !retention.Enabled {
+		return nil
+	}
+
+	job := &RetentionJob{
+		Name: releaseRetentionJobName,
+		Log:  s.log.With().Str("job", releaseRetentionJobName).Logger(),
+		svc:  s,
+	}
+
+	if _, err := s.scheduler.AddJob(job, retention.Schedule, job.Name); err != nil {
+		return errors.Wrap(err, "could not schedule job: %s", job.Name)
+	}
+
+	s.log.Debug().Msgf("scheduled release retention with schedule %q, max age %d days, max rows per indexer %d", retention.Schedule, retention.MaxAgeDays, retention.MaxRowsPerIndexer)
+
+	return nil
+}
+
+type RetentionJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+func (j *RetentionJob) Run() {
+	result, err := j.svc.PruneReleases(context.Background())
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not prune releases")
+		return
+	}
+
+	if result.Total() == 0 {
+		j.Log.Debug().Msg("no releases to prune")
+		return
+	}
+
+	j.Log.Info().Msgf("pruned %d releases (%d by age, %d by row limit) and %d action statuses", result.Total(), result.ReleasesByAge, result.ReleasesByRowLimit, result.ActionStatusDeleted)
+}
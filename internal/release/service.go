@@ -5,6 +5,9 @@ package release
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,24 +16,50 @@ import (
 	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/internal/scheduler"
+	"github.com/autobrr/autobrr/internal/storage"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
+	"github.com/dustin/go-humanize"
 	"github.com/rs/zerolog"
 )
 
 type Service interface {
 	Find(ctx context.Context, query domain.ReleaseQueryParams) (*domain.FindReleasesResponse, error)
+	Export(ctx context.Context, params domain.ReleaseExportParams, fn func(release *domain.Release) error) error
+	StoreExport(ctx context.Context, params domain.ReleaseExportParams, format domain.ReleaseExportFormat) (*domain.StorageObject, error)
 	Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error)
 	GetActionStatus(ctx context.Context, req *domain.GetReleaseActionStatusRequest) (*domain.ReleaseActionStatus, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
 	Store(ctx context.Context, release *domain.Release) error
 	StoreReleaseActionStatus(ctx context.Context, actionStatus *domain.ReleaseActionStatus) error
-	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error
+	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error)
 	Process(release *domain.Release)
 	ProcessMultiple(releases []*domain.Release)
+	LogAnnounce(indexer, channel, line string)
 	ProcessManual(ctx context.Context, req *domain.ReleaseProcessReq) error
+	ProcessExternal(ctx context.Context, req *domain.ReleaseProcessExternalReq) error
+	Simulate(ctx context.Context, req *domain.ReleaseSimulateReq) (*domain.ReleaseSimulateResponse, error)
 	Retry(ctx context.Context, req *domain.ReleaseActionRetryReq) error
+	Reprocess(ctx context.Context, req *domain.ReleaseReprocessReq) error
+	RetryFailed(ctx context.Context, req *domain.ReleaseRetryFailedReq) (*domain.ReleaseRetryFailedResponse, error)
+	Annotate(ctx context.Context, req *domain.ReleaseAnnotationReq) (*domain.Release, error)
+	CheckConsistency(ctx context.Context) ([]domain.ReleaseConsistencyReport, error)
+	LatencyStats(ctx context.Context, days int) (*domain.ReleaseLatencyStats, error)
+	GetRetention(ctx context.Context) (*domain.ReleaseRetention, error)
+	UpdateRetention(ctx context.Context, retention *domain.ReleaseRetention) error
+	PruneReleases(ctx context.Context) (*domain.ReleasePruneResult, error)
+	ListPending(ctx context.Context, pendingType domain.ReleasePendingType) ([]domain.ReleasePending, error)
+	PendingCounts(ctx context.Context) (*domain.ReleasePendingCounts, error)
+	CancelPending(ctx context.Context, id int64) error
+	PushPending(ctx context.Context, id int64) error
+	ApprovePending(ctx context.Context, id int64) error
+	RejectPending(ctx context.Context, id int64) error
+	Start() error
+	Stop(ctx context.Context) error
 }
 
 type actionClientTypeKey struct {
@@ -39,28 +68,227 @@ type actionClientTypeKey struct {
 }
 
 type service struct {
-	log  zerolog.Logger
-	repo domain.ReleaseRepo
+	log    zerolog.Logger
+	config *domain.Config
+	repo   domain.ReleaseRepo
+
+	retentionRepo domain.ReleaseRetentionRepo
+	pendingRepo   domain.ReleasePendingRepo
 
 	actionSvc  action.Service
 	filterSvc  filter.Service
 	indexerSvc indexer.Service
+	scheduler  scheduler.Service
+	storageSvc storage.Service
+	bus        EventBus.Bus
+
+	// announceHistory is only set when the feed consistency check is enabled
+	announceHistory *announceHistory
+
+	// announceLog is only set when an announce log schedule is configured
+	announceLog *announceLog
+
+	// pushThrottle is only set when a push budget is configured
+	pushThrottle *pushThrottle
+
+	// pushDedupe is only set when a dedupe window is configured
+	pushDedupe *pushDedupe
+
+	groupTiers *groupTierQueue
+	delayQueue *delayQueue
+
+	// pipelines are the custom pipelines from the config by indexer identifier
+	pipelines map[string]*domain.IndexerPipeline
+}
+
+func NewService(log logger.Logger, config *domain.Config, repo domain.ReleaseRepo, retentionRepo domain.ReleaseRetentionRepo, pendingRepo domain.ReleasePendingRepo, announceHistoryRepo domain.AnnounceHistoryRepo, actionSvc action.Service, filterSvc filter.Service, indexerSvc indexer.Service, scheduler scheduler.Service, storageSvc storage.Service, bus EventBus.Bus) Service {
+	s := &service{
+		log:           log.With().Str("module", "release").Logger(),
+		config:        config,
+		repo:          repo,
+		retentionRepo: retentionRepo,
+		pendingRepo:   pendingRepo,
+		actionSvc:     actionSvc,
+		filterSvc:     filterSvc,
+		indexerSvc:    indexerSvc,
+		scheduler:     scheduler,
+		storageSvc:    storageSvc,
+		bus:           bus,
+		pipelines:     map[string]*domain.IndexerPipeline{},
+	}
+
+	for indexer := range config.Pipeline {
+		pipeline := config.EffectiveIndexerPipeline(indexer)
+		if pipeline.Error != "" {
+			s.log.Error().Msgf("invalid pipeline for indexer %s, using default pipeline: %s", indexer, pipeline.Error)
+			continue
+		}
+
+		s.pipelines[indexer] = pipeline
+
+		s.log.Debug().Msgf("custom pipeline for indexer %s: %v", indexer, pipeline.Stages)
+	}
+
+	if config.FeedConsistencyCheck {
+		s.announceHistory = newAnnounceHistory(s.log.With().Str("history", "announce").Logger(), announceHistoryRepo, config.AnnounceHistoryBufferSize, config.AnnounceHistorySpillBatchSize)
+	}
+
+	if config.Storage.AnnounceLogSchedule != "" && storageSvc != nil {
+		s.announceLog = newAnnounceLog(s.log.With().Str("log", "announce").Logger(), storageSvc)
+	}
+
+	if config.PushBudgetPerHour != "" {
+		limit, err := humanize.ParseBytes(config.PushBudgetPerHour)
+		if err != nil || limit == 0 {
+			s.log.Error().Err(err).Msgf("invalid push budget per hour: %q, push throttling disabled", config.PushBudgetPerHour)
+		} else {
+			s.pushThrottle = newPushThrottle(s.log.With().Str("throttle", "push").Logger(), limit)
+		}
+	}
+
+	if config.DedupeWindow > 0 {
+		s.pushDedupe = newPushDedupe(time.Duration(config.DedupeWindow) * time.Second)
+	}
+
+	s.groupTiers = newGroupTierQueue(s.processGroupTierHold)
+	s.delayQueue = newDelayQueue(s.log.With().Str("queue", "delay").Logger(), pendingRepo, s.processDelayed)
+
+	return s
+}
+
+func (s *service) Start() error {
+	if err := s.delayQueue.restore(context.Background()); err != nil {
+		return err
+	}
+
+	if s.pushThrottle != nil {
+		job := &PushQueueJob{
+			Name: "release-push-queue",
+			Log:  s.log.With().Str("job", "release-push-queue").Logger(),
+			svc:  s,
+		}
+
+		if _, err := s.scheduler.ScheduleJob(job, pushBudgetCheckInterval, job.Name); err != nil {
+			return errors.Wrap(err, "could not schedule job: %s", job.Name)
+		}
+
+		s.log.Debug().Msgf("push budget of %s per hour enabled", humanize.Bytes(s.pushThrottle.limit))
+	}
+
+	if err := s.startRetention(context.Background()); err != nil {
+		return err
+	}
+
+	if s.announceLog != nil {
+		job := &AnnounceLogJob{
+			Name: "release-announce-log",
+			Log:  s.log.With().Str("job", "release-announce-log").Logger(),
+			log:  s.announceLog,
+		}
+
+		if _, err := s.scheduler.AddJob(job, s.config.Storage.AnnounceLogSchedule, job.Name); err != nil {
+			return errors.Wrap(err, "could not schedule job: %s", job.Name)
+		}
+	}
+
+	if s.config.CompletionCheckInterval > 0 {
+		job := &CompletionCheckJob{
+			Name: "release-completion-check",
+			Log:  s.log.With().Str("job", "release-completion-check").Logger(),
+			svc:  s,
+		}
+
+		interval := time.Duration(s.config.CompletionCheckInterval) * time.Minute
+
+		if _, err := s.scheduler.ScheduleJob(job, interval, job.Name); err != nil {
+			return errors.Wrap(err, "could not schedule job: %s", job.Name)
+		}
+
+		s.log.Debug().Msgf("scheduled completion check to run every %s", interval)
+	}
+
+	removalJob := &RemovalJob{
+		Name: "release-removal-policies",
+		Log:  s.log.With().Str("job", "release-removal-policies").Logger(),
+		svc:  s,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(removalJob, removalCheckInterval, removalJob.Name); err != nil {
+		return errors.Wrap(err, "could not schedule job: %s", removalJob.Name)
+	}
+
+	if s.announceHistory == nil {
+		return nil
+	}
+
+	job := &ConsistencyCheckJob{
+		Name:    "release-consistency-check",
+		Log:     s.log.With().Str("job", "release-consistency-check").Logger(),
+		history: s.announceHistory,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(job, consistencyCheckInterval, job.Name); err != nil {
+		return errors.Wrap(err, "could not schedule job: %s", job.Name)
+	}
+
+	s.log.Debug().Msgf("scheduled feed consistency check to run every %s", consistencyCheckInterval)
+
+	return nil
+}
+
+// Stop writes the announce history still in memory to the database and stops its worker
+func (s *service) Stop(ctx context.Context) error {
+	if s.announceHistory == nil {
+		return nil
+	}
+
+	return s.announceHistory.stop(ctx)
 }
 
-func NewService(log logger.Logger, repo domain.ReleaseRepo, actionSvc action.Service, filterSvc filter.Service, indexerSvc indexer.Service) Service {
-	return &service{
-		log:        log.With().Str("module", "release").Logger(),
-		repo:       repo,
-		actionSvc:  actionSvc,
-		filterSvc:  filterSvc,
-		indexerSvc: indexerSvc,
+func (s *service) CheckConsistency(ctx context.Context) ([]domain.ReleaseConsistencyReport, error) {
+	if s.announceHistory == nil {
+		return nil, errors.New("feed consistency check is not enabled")
 	}
+
+	return s.announceHistory.checkConsistency(ctx, time.Now().Add(-consistencyGracePeriod))
 }
 
 func (s *service) Find(ctx context.Context, query domain.ReleaseQueryParams) (*domain.FindReleasesResponse, error) {
 	return s.repo.Find(ctx, query)
 }
 
+func (s *service) Export(ctx context.Context, params domain.ReleaseExportParams, fn func(release *domain.Release) error) error {
+	return s.repo.Export(ctx, params, fn)
+}
+
+// StoreExport writes the export to the configured storage instead of streaming it to the client
+func (s *service) StoreExport(ctx context.Context, params domain.ReleaseExportParams, format domain.ReleaseExportFormat) (*domain.StorageObject, error) {
+	pr, pw := io.Pipe()
+
+	exporter, err := domain.NewReleaseExportWriter(format, pw)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := s.repo.Export(ctx, params, exporter.Write)
+		if err == nil {
+			err = exporter.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	name := fmt.Sprintf("autobrr-releases-%s.%s", time.Now().Format("20060102-150405"), format)
+
+	object, err := s.storageSvc.Store(ctx, domain.StorageArtifactExport, name, pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		return nil, errors.Wrap(err, "could not store release export")
+	}
+
+	return object, nil
+}
+
 func (s *service) Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error) {
 	return s.repo.Get(ctx, req)
 }
@@ -82,10 +310,25 @@ func (s *service) Store(ctx context.Context, release *domain.Release) error {
 }
 
 func (s *service) StoreReleaseActionStatus(ctx context.Context, status *domain.ReleaseActionStatus) error {
-	return s.repo.StoreReleaseActionStatus(ctx, status)
+	if err := s.repo.StoreReleaseActionStatus(ctx, status); err != nil {
+		return err
+	}
+
+	s.publishLive(domain.NewLiveEvent(domain.LiveEventActionStatus, status))
+
+	return nil
+}
+
+// publishLive sends the event to the live stream of the web ui and dashboards
+func (s *service) publishLive(event *domain.LiveEvent) {
+	if s.bus == nil {
+		return
+	}
+
+	s.bus.Publish("events:live", event)
 }
 
-func (s *service) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error {
+func (s *service) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error) {
 	return s.repo.Delete(ctx, req)
 }
 
@@ -96,47 +339,170 @@ func (s *service) ProcessManual(ctx context.Context, req *domain.ReleaseProcessR
 		return err
 	}
 
-	rls := domain.NewRelease(domain.IndexerMinimal{ID: def.ID, Name: def.Name, Identifier: def.Identifier, IdentifierExternal: def.IdentifierExternal})
+	var rls *domain.Release
 
 	switch req.IndexerImplementation {
 	case string(domain.IndexerImplementationIRC):
+		rls, err = s.parseAnnounce(def, req.AnnounceLines)
+		if err != nil {
+			return err
+		}
 
-		// from announce/announce.go
-		tmpVars := map[string]string{}
-		parseFailed := false
+	default:
+		return errors.New("implementation %q is not supported", req.IndexerImplementation)
 
-		for idx, parseLine := range def.IRC.Parse.Lines {
-			match, err := indexer.ParseLine(&s.log, parseLine.Pattern, parseLine.Vars, tmpVars, req.AnnounceLines[idx], parseLine.Ignore)
-			if err != nil {
-				parseFailed = true
-				break
-			}
+	}
 
-			if !match {
-				parseFailed = true
-				break
-			}
+	// process
+	go s.Process(rls)
+
+	return nil
+}
+
+// Simulate parses the announce and checks the release against the filters of the indexer.
+// The release is not stored and no actions are run.
+func (s *service) Simulate(ctx context.Context, req *domain.ReleaseSimulateReq) (*domain.ReleaseSimulateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	def, err := s.indexerSvc.GetMappedDefinitionByName(req.IndexerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	rls, err := s.parseAnnounce(def, req.AnnounceLines)
+	if err != nil {
+		return nil, domain.ValidationErrors{{Field: "announce_lines", Message: err.Error()}}
+	}
+
+	rls.Pipeline = s.pipeline(rls.Indexer.Identifier)
+
+	filters, err := s.filterSvc.FindByIndexerIdentifier(ctx, rls.Indexer.Identifier)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find filters for indexer: %s", rls.Indexer.Identifier)
+	}
+
+	resp := &domain.ReleaseSimulateResponse{
+		Release: rls,
+		Filters: make([]domain.ReleaseSimulateFilterResult, 0, len(filters)),
+	}
+
+	for _, f := range filters {
+		// the checks update the release, eg. when the size has to be checked out of band
+		check := *rls
+
+		match, skipped, err := s.filterSvc.Simulate(ctx, f, &check)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not check filter: %s", f.Name)
 		}
 
-		if parseFailed {
-			return errors.New("parse failed")
+		rejections := f.Rejections
+		if rejections == nil {
+			rejections = []string{}
 		}
 
-		rls.Protocol = domain.ReleaseProtocol(def.Protocol)
+		resp.Filters = append(resp.Filters, domain.ReleaseSimulateFilterResult{
+			FilterID:   f.ID,
+			FilterName: f.Name,
+			Priority:   f.Priority,
+			Match:      match,
+			Rejections: rejections,
+			Skipped:    skipped,
+		})
+	}
+
+	return resp, nil
+}
+
+// parseAnnounce parses the announce lines with the irc parse lines of the indexer definition
+func (s *service) parseAnnounce(def *domain.IndexerDefinition, lines []string) (*domain.Release, error) {
+	if def.IRC == nil || def.IRC.Parse == nil {
+		return nil, errors.New("indexer %s has no irc announces", def.Identifier)
+	}
+
+	if len(lines) < len(def.IRC.Parse.Lines) {
+		return nil, errors.New("expected %d announce lines, got %d", len(def.IRC.Parse.Lines), len(lines))
+	}
+
+	// from announce/announce.go
+	tmpVars := map[string]string{}
 
-		// on lines matched
-		err = def.IRC.Parse.Parse(def, tmpVars, rls)
+	for idx, parseLine := range def.IRC.Parse.Lines {
+		match, err := indexer.ParseLine(&s.log, parseLine.Pattern, parseLine.Vars, tmpVars, lines[idx], parseLine.Ignore)
 		if err != nil {
-			return err
+			return nil, errors.Wrap(err, "parse failed")
 		}
 
-	default:
-		return errors.New("implementation %q is not supported", req.IndexerImplementation)
+		if !match {
+			return nil, errors.New("parse failed: line %d not matching pattern", idx+1)
+		}
+	}
+
+	rls := domain.NewRelease(domain.IndexerMinimal{ID: def.ID, Name: def.Name, Identifier: def.Identifier, IdentifierExternal: def.IdentifierExternal})
+	rls.Protocol = domain.ReleaseProtocol(def.Protocol)
 
+	// on lines matched
+	if err := def.IRC.Parse.Parse(def, tmpVars, rls); err != nil {
+		return nil, err
 	}
 
-	// process
-	go s.Process(rls)
+	return rls, nil
+}
+
+// ProcessExternal processes releases pushed from Prowlarr or Jackett. The indexer is matched by
+// identifier or name, from the request override or else the indexer of each release.
+func (s *service) ProcessExternal(ctx context.Context, req *domain.ReleaseProcessExternalReq) error {
+	indexers, err := s.indexerSvc.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not list indexers")
+	}
+
+	var validationErrs domain.ValidationErrors
+
+	releases := make([]*domain.Release, 0, len(req.Releases))
+
+	for _, ext := range req.Releases {
+		name := req.IndexerIdentifier
+		if name == "" {
+			name = ext.Indexer
+		}
+
+		if name == "" {
+			validationErrs.Add("indexer", "missing indexer for %q", ext.Title)
+			continue
+		}
+
+		idx := matchExternalIndexer(indexers, name)
+		if idx == nil {
+			validationErrs.Add("indexer", "no indexer matching %q for %q", name, ext.Title)
+			continue
+		}
+
+		releases = append(releases, ext.Release(domain.IndexerMinimal{ID: int(idx.ID), Name: idx.Name, Identifier: idx.Identifier, IdentifierExternal: idx.IdentifierExternal}))
+	}
+
+	if err := validationErrs.Err(); err != nil {
+		return err
+	}
+
+	for _, rls := range releases {
+		s.log.Debug().Msgf("process external release %s from indexer %s", rls.TorrentName, rls.Indexer.Identifier)
+
+		go s.Process(rls)
+	}
+
+	return nil
+}
+
+// matchExternalIndexer finds the indexer by identifier, external identifier or name
+func matchExternalIndexer(indexers []domain.Indexer, name string) *domain.Indexer {
+	for i := range indexers {
+		idx := &indexers[i]
+		if strings.EqualFold(idx.Identifier, name) || strings.EqualFold(idx.IdentifierExternal, name) || strings.EqualFold(idx.Name, name) {
+			return idx
+		}
+	}
 
 	return nil
 }
@@ -156,8 +522,17 @@ func (s *service) Process(release *domain.Release) {
 
 	defer release.CleanupTemporaryFiles()
 
+	if s.announceHistory != nil {
+		s.announceHistory.add(release)
+	}
+
+	metrics.AnnounceProcessed(release.Indexer.Identifier)
+
 	ctx := context.Background()
 
+	release.Pipeline = s.pipeline(release.Indexer.Identifier)
+	release.NormalizedCategories = s.normalizeCategories(release)
+
 	// TODO check in config for "Save all releases"
 	// TODO cross-seed check
 	// TODO dupe checks
@@ -182,6 +557,25 @@ func (s *service) Process(release *domain.Release) {
 	return
 }
 
+// normalizeCategories maps the release categories with the category map of the indexer definition
+func (s *service) normalizeCategories(release *domain.Release) []string {
+	categories := release.Categories
+	if release.Category != "" {
+		categories = append(slices.Clone(categories), release.Category)
+	}
+
+	if len(categories) == 0 {
+		return nil
+	}
+
+	var mapping map[string]string
+	if definition, err := s.indexerSvc.GetMappedDefinitionByName(release.Indexer.Identifier); err == nil {
+		mapping = definition.Categories
+	}
+
+	return domain.NormalizeIndexerCategories(mapping, categories)
+}
+
 func (s *service) processFilters(ctx context.Context, filters []*domain.Filter, release *domain.Release) error {
 	// keep track of action clients to avoid sending the same thing all over again
 	// save both client type and client id to potentially try another client of same type
@@ -205,6 +599,17 @@ func (s *service) processFilters(ctx context.Context, filters []*domain.Filter,
 			return err
 		}
 
+		metrics.FilterChecked(f.Name, match)
+
+		s.publishLive(domain.NewLiveEvent(domain.LiveEventReleaseDecision, domain.LiveReleaseDecision{
+			TorrentName: release.TorrentName,
+			Indexer:     release.Indexer.Identifier,
+			FilterID:    f.ID,
+			Filter:      f.Name,
+			Match:       match,
+			Rejections:  f.Rejections,
+		}))
+
 		if !match {
 			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s, no match. rejections: %s", release.Indexer.Name, release.FilterName, release.TorrentName, f.RejectionsString(false))
 
@@ -228,81 +633,366 @@ func (s *service) processFilters(ctx context.Context, filters []*domain.Filter,
 			continue
 		}
 
-		// sleep for the delay period specified in the filter before running actions
-		delay := release.Filter.Delay
+		// hold releases of less preferred groups until their tier delay passed
+		if len(f.GroupTiers) > 0 {
+			decision, reason := s.groupTiers.decide(f, release, time.Now())
+			switch decision {
+			case groupTierReject:
+				l.Debug().Msgf("filter %s rejected release: %s", f.Name, reason)
+				continue
+			case groupTierHold:
+				l.Info().Msgf("release.Process: holding '%s' (%s) for %s until the delay of its group tier passed", release.TorrentName, release.FilterName, release.Indexer.Name)
+
+				// the hold owns the release now, temporary files are downloaded again when processed
+				release.CleanupTemporaryFiles()
+				return nil
+			}
+		}
+
+		// hold the release for the delay period specified in the filter or forced by the indexer pipeline before running actions
+		delay := release.Pipeline.Delay(release.Filter)
 		if delay > 0 {
 			l.Debug().Msgf("release.Process: delaying processing of '%s' (%s) for %s by %d seconds as specified in the filter", release.TorrentName, release.FilterName, release.Indexer.Name, delay)
-			time.Sleep(time.Duration(delay) * time.Second)
-		}
 
-		// save release here to only save those with rejections from actions instead of all releases
-		if release.ID == 0 {
-			release.FilterStatus = domain.ReleaseStatusFilterApproved
+			return s.delayRelease(ctx, l, f, release, time.Now().Add(time.Duration(delay)*time.Second))
+		}
 
-			if err = s.Store(ctx, release); err != nil {
-				l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
-				return err
-			}
+		done, err := s.pushFilter(ctx, l, f, actions, release, triedActionClients)
+		if err != nil {
+			return err
 		}
 
-		var rejections []string
+		// all actions run, decide to stop or continue here
+		if done {
+			break
+		}
+	}
 
-		// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
-		for _, a := range actions {
-			act := a
+	return nil
+}
 
-			// only run enabled actions
-			if !act.Enabled {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer.Name, release.FilterName, release.TorrentName, act.Name)
-				continue
-			}
+// pushFilter runs the actions of the matched filter, it returns false when the next filter should be tried
+func (s *service) pushFilter(ctx context.Context, l zerolog.Logger, f *domain.Filter, actions []*domain.Action, release *domain.Release, triedActionClients map[actionClientTypeKey]struct{}) (bool, error) {
+	// skip releases already pushed by another filter or indexer within the dedupe window
+	var claim *dedupeClaim
+	if s.pushDedupe != nil && !f.SkipDedupe {
+		var dupe *dedupeEntry
+		if claim, dupe = s.pushDedupe.claim(release, f.Name, time.Now()); dupe != nil {
+			l.Info().Msgf("release.Process: skipping duplicate '%s' (%s) for %s, already pushed by filter %s from %s", release.TorrentName, release.FilterName, release.Indexer.Name, dupe.filterName, dupe.indexer)
+			return false, nil
+		}
+	}
 
-			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer.Name, release.FilterName, release.TorrentName, act.Name)
+	// save release here to only save those with rejections from actions instead of all releases
+	if release.ID == 0 {
+		release.FilterStatus = domain.ReleaseStatusFilterApproved
 
-			// keep track of action clients to avoid sending the same thing all over again
-			_, tried := triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}]
-			if tried {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried, skip", release.Indexer.Name, release.FilterName, release.TorrentName)
-				continue
+		if err := s.Store(ctx, release); err != nil {
+			l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
+			if claim != nil {
+				s.pushDedupe.release(claim)
 			}
+			return true, err
+		}
+	}
 
-			// run action
-			status, err := s.runAction(ctx, act, release)
-			if err != nil {
-				l.Error().Err(err).Msgf("release.Process: error running actions for filter: %s", release.FilterName)
-				//continue
+	// queue until the next window when the push budget is used up
+	var reservation pushReservation
+	if s.pushThrottle != nil {
+		var ok bool
+		if reservation, ok = s.pushThrottle.reserve(release.Size); !ok {
+			if claim != nil {
+				s.pushDedupe.release(claim)
 			}
 
-			rejections = status.Rejections
+			// the queue owns the release now, temporary files are downloaded again when processed
+			release.CleanupTemporaryFiles()
+			s.pushThrottle.enqueue(release, f)
+			return true, nil
+		}
+	}
 
-			if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
-				s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.FilterName)
-			}
+	// the actions remove the torrents replaced by a PROPER or REPACK before adding it
+	if f.UpgradeReplace {
+		s.findUpgradeOriginals(ctx, l, release)
+	}
 
-			if len(rejections) > 0 {
-				// if we get action rejection, remember which action client it was from
-				triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}] = struct{}{}
+	var (
+		rejections []string
+		pushed     bool
 
-				// log something and fire events
-				l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
-			}
+		// previous is the status of the last action that ran, for the run conditions of chained actions
+		previous *domain.ReleasePushStatus
+	)
+
+	// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
+	for _, a := range actions {
+		act := a
 
-			// if no rejections consider action approved, run next
+		// only run enabled actions
+		if !act.Enabled {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer.Name, release.FilterName, release.TorrentName, act.Name)
 			continue
 		}
 
-		// if we have rejections from arr, continue to next filter
-		if len(rejections) > 0 {
+		l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer.Name, release.FilterName, release.TorrentName, act.Name)
+
+		// keep track of action clients to avoid sending the same thing all over again
+		_, tried := triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}]
+		if tried {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried, skip", release.Indexer.Name, release.FilterName, release.TorrentName)
 			continue
 		}
 
-		// all actions run, decide to stop or continue here
-		break
+		if !act.ShouldRun(previous) {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' run condition %s not met, skip", release.Indexer.Name, release.FilterName, release.TorrentName, act.Name, act.RunCondition)
+			continue
+		}
+
+		// park the action until a user approves or rejects it
+		if act.RequireApproval {
+			s.parkForApproval(ctx, l, act, release)
+			continue
+		}
+
+		// run action
+		status, err := s.runAction(ctx, act, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("release.Process: error running actions for filter: %s", release.FilterName)
+			//continue
+		}
+
+		rejections = status.Rejections
+		previous = &status.Status
+
+		// only measured here since retries and reprocessing are not a race against other peers
+		if status.Status == domain.ReleasePushStatusApproved {
+			pushed = true
+
+			if !release.Reprocessed {
+				latency := time.Since(release.Timestamp)
+				status.LatencyMs = latency.Milliseconds()
+
+				metrics.AnnouncePushed(release.Indexer.Identifier, latency)
+			}
+		}
+
+		if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+			s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.FilterName)
+		}
+
+		if len(rejections) > 0 {
+			// if we get action rejection, remember which action client it was from
+			triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}] = struct{}{}
+
+			// log something and fire events
+			l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
+
+			// an exec action vetoed the release, skip the remaining actions of this filter
+			if act.Type == domain.ActionTypeExec {
+				break
+			}
+		}
+
+		// if no rejections consider action approved, run next
+		continue
+	}
+
+	// give back the budget when nothing was pushed
+	if !pushed && s.pushThrottle != nil {
+		s.pushThrottle.release(reservation)
+	}
+
+	if !pushed && len(f.GroupTiers) > 0 {
+		s.groupTiers.notPushed(f, release)
+	}
+
+	if claim != nil {
+		if pushed {
+			s.pushDedupe.confirm(claim, release)
+		} else {
+			s.pushDedupe.release(claim)
+		}
+	}
+
+	// if we have rejections from arr, continue to next filter
+	return len(rejections) == 0, nil
+}
+
+// delayRelease stores the release and hands it to the delay queue, which owns the release from now on
+func (s *service) delayRelease(ctx context.Context, l zerolog.Logger, f *domain.Filter, release *domain.Release, runAt time.Time) error {
+	if release.ID == 0 {
+		release.FilterStatus = domain.ReleaseStatusFilterApproved
+
+		if err := s.Store(ctx, release); err != nil {
+			l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
+			return err
+		}
+	}
+
+	if err := s.delayQueue.add(ctx, release, f, runAt); err != nil {
+		l.Error().Err(err).Msg("release.Process: error delaying release")
+		return err
 	}
 
+	// temporary files are downloaded again when processed
+	release.CleanupTemporaryFiles()
+
 	return nil
 }
 
+// processDelayed runs the actions of the filter for a release once its delay passed
+func (s *service) processDelayed(item *delayedRelease) {
+	ctx := context.Background()
+
+	release, f := item.release, item.filter
+	if release == nil {
+		var err error
+		if release, f, err = s.loadPending(ctx, item.pending); err != nil {
+			s.log.Error().Err(err).Msgf("could not load delayed release: %s", item.pending.TorrentName)
+			return
+		}
+	}
+
+	defer release.CleanupTemporaryFiles()
+
+	l := s.log.With().Str("indexer", release.Indexer.Identifier).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+	l.Debug().Msgf("processing delayed release: %s (%s)", release.TorrentName, f.Name)
+
+	if item.expired && s.bus != nil {
+		payload := &domain.NotificationPayload{
+			Event:       domain.NotificationEventDelayedReleaseExpired,
+			Subject:     "Delayed release expired",
+			Message:     fmt.Sprintf("%s (%s)", release.TorrentName, f.Name),
+			ReleaseName: release.TorrentName,
+			Filter:      f.Name,
+			Indexer:     release.Indexer.Name,
+			Size:        release.Size,
+			Protocol:    release.Protocol,
+			Timestamp:   time.Now(),
+			Release:     release,
+		}
+
+		s.bus.Publish("events:notification", &payload.Event, payload)
+	}
+
+	active := true
+	actions, err := s.actionSvc.FindByFilterID(ctx, f.ID, &active, false)
+	if err != nil {
+		l.Error().Err(err).Msgf("release.Process: error finding actions for filter: %s", f.Name)
+		return
+	}
+
+	if len(actions) == 0 {
+		l.Warn().Msgf("release.Process: no active actions found for filter '%s', dropping delayed release", f.Name)
+		return
+	}
+
+	if _, err := s.pushFilter(ctx, l, f, actions, release, map[actionClientTypeKey]struct{}{}); err != nil {
+		l.Error().Err(err).Msgf("error processing delayed release: %s", release.TorrentName)
+	}
+}
+
+// loadPending loads the release and filter of a pending release stored before
+func (s *service) loadPending(ctx context.Context, pending *domain.ReleasePending) (*domain.Release, *domain.Filter, error) {
+	release, err := s.Get(ctx, &domain.GetReleaseRequest{Id: int(pending.ReleaseID)})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find release by id: %d", pending.ReleaseID)
+	}
+
+	indexerInfo, err := s.indexerSvc.GetBy(ctx, domain.GetIndexerRequest{Identifier: release.Indexer.Identifier})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not get indexer by identifier: %s", release.Indexer.Identifier)
+	}
+
+	f, err := s.filterSvc.FindByID(ctx, pending.FilterID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find filter by id: %d", pending.FilterID)
+	}
+
+	// only the columns of the release are stored, the rest is parsed again from the name
+	release.ParseString(release.TorrentName)
+
+	release.Indexer = domain.IndexerMinimal{
+		ID:                 int(indexerInfo.ID),
+		Name:               indexerInfo.Name,
+		Identifier:         indexerInfo.Identifier,
+		IdentifierExternal: indexerInfo.IdentifierExternal,
+	}
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	return release, f, nil
+}
+
+// parkForApproval stores the action for the release until a user approves or rejects it
+func (s *service) parkForApproval(ctx context.Context, l zerolog.Logger, act *domain.Action, release *domain.Release) {
+	if err := s.pendingRepo.Store(ctx, domain.NewReleasePendingApproval(release, act)); err != nil {
+		l.Error().Err(err).Msgf("release.Process: error parking action %s for approval", act.Name)
+		return
+	}
+
+	l.Info().Msgf("release.Process: action '%s' for '%s' (%s) is waiting for manual approval", act.Name, release.TorrentName, release.FilterName)
+}
+
+func (s *service) ListPending(ctx context.Context, pendingType domain.ReleasePendingType) ([]domain.ReleasePending, error) {
+	var pending []domain.ReleasePending
+
+	// delayed releases are listed from the queue since it knows which of them already run
+	if pendingType == "" || pendingType == domain.ReleasePendingTypeDelay {
+		pending = append(pending, s.delayQueue.list()...)
+	}
+
+	if pendingType == "" || pendingType == domain.ReleasePendingTypeApproval {
+		approvals, err := s.pendingRepo.List(ctx, domain.ReleasePendingTypeApproval)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range approvals {
+			pending = append(pending, *p)
+		}
+	}
+
+	return pending, nil
+}
+
+func (s *service) PendingCounts(ctx context.Context) (*domain.ReleasePendingCounts, error) {
+	return s.pendingRepo.Counts(ctx)
+}
+
+func (s *service) CancelPending(ctx context.Context, id int64) error {
+	return s.delayQueue.cancel(ctx, id)
+}
+
+func (s *service) PushPending(ctx context.Context, id int64) error {
+	return s.delayQueue.push(id)
+}
+
+// processGroupTierHold runs the filter again for a held release once the delay of its group tier passed
+func (s *service) processGroupTierHold(hold *heldRelease) {
+	defer hold.release.CleanupTemporaryFiles()
+
+	s.log.Debug().Msgf("processing release held for group tier: %s (%s)", hold.release.TorrentName, hold.filter.Name)
+
+	// the filter is checked again since things like max downloads could have changed while held
+	if err := s.processFilters(context.Background(), []*domain.Filter{hold.filter}, hold.release); err != nil {
+		s.log.Error().Err(err).Msgf("error processing release held for group tier: %s", hold.release.TorrentName)
+	}
+}
+
+// pipeline returns the custom pipeline for the indexer or the default
+func (s *service) pipeline(indexer string) *domain.IndexerPipeline {
+	if p, ok := s.pipelines[strings.ToLower(indexer)]; ok {
+		return p
+	}
+
+	p, _ := domain.NewIndexerPipeline(indexer, nil)
+	return p
+}
+
 func (s *service) ProcessMultiple(releases []*domain.Release) {
 	s.log.Debug().Msgf("process (%d) new releases from feed", len(releases))
 
@@ -326,23 +1016,59 @@ func (s *service) runAction(ctx context.Context, action *domain.Action, release
 	rejections, err := s.actionSvc.RunAction(ctx, action, release)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("release.runAction: error running actions for filter: %s", release.FilterName)
+	}
 
-		status.Status = domain.ReleasePushStatusErr
-		status.Rejections = []string{err.Error()}
+	setActionResult(status, action, release, rejections, err)
+
+	return status, err
+}
+
+// setActionResult updates the status with the outcome of running the action
+func setActionResult(status *domain.ReleaseActionStatus, action *domain.Action, release *domain.Release, rejections []string, err error) {
+	// counted once the status below is final
+	defer metrics.ActionPushed(status)
 
-		return status, err
+	status.Reannounce = action.ReAnnounceAttempts
+
+	// the client that accepted the push, the action falls over to the next client when one fails
+	if action.Client != nil {
+		status.Client = action.Client.Name
 	}
 
-	if rejections != nil {
+	switch {
+	case err != nil:
+		status.Status = domain.ReleasePushStatusErr
+		status.Rejections = []string{err.Error()}
+
+	case rejections != nil:
 		status.Status = domain.ReleasePushStatusRejected
 		status.Rejections = rejections
 
-		return status, nil
+	default:
+		status.Status = domain.ReleasePushStatusApproved
+		status.Rejections = []string{}
+		status.InfoHash = release.TorrentHash
+	}
+}
+
+// findUpgradeOriginals sets the pushed torrents replaced by the release when it's a PROPER or REPACK
+func (s *service) findUpgradeOriginals(ctx context.Context, l zerolog.Logger, release *domain.Release) {
+	params := domain.NewReleaseUpgradeParams(release)
+	if params == nil {
+		return
+	}
+
+	originals, err := s.repo.FindUpgradeOriginals(ctx, params)
+	if err != nil {
+		l.Error().Err(err).Msg("release.Process: error finding releases replaced by upgrade")
+		return
 	}
 
-	status.Status = domain.ReleasePushStatusApproved
+	for _, original := range originals {
+		l.Debug().Msgf("release.Process: upgrade replaces '%s' (%s)", original.TorrentName, original.InfoHash)
+	}
 
-	return status, nil
+	release.Replaces = originals
 }
 
 func (s *service) retryAction(ctx context.Context, action *domain.Action, release *domain.Release) error {
@@ -407,3 +1133,259 @@ func (s *service) Retry(ctx context.Context, req *domain.ReleaseActionRetryReq)
 
 	return nil
 }
+
+// Reprocess runs a stored release through the filters of its indexer again, or only the filter of the request.
+// The release is checked with what's stored, eg. freeleech is unknown. New action statuses are added to the release.
+func (s *service) Reprocess(ctx context.Context, req *domain.ReleaseReprocessReq) error {
+	release, filters, err := s.loadReprocess(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info().Msgf("reprocessing release %s with %d filters", release.TorrentName, len(filters))
+
+	go s.reprocess(release, filters)
+
+	return nil
+}
+
+// loadReprocess returns the stored release as announced and the filters to run it through
+func (s *service) loadReprocess(ctx context.Context, req *domain.ReleaseReprocessReq) (*domain.Release, []*domain.Filter, error) {
+	release, err := s.getStoredRelease(ctx, int64(req.ReleaseID))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reprocess error")
+	}
+
+	release.Reprocessed = true
+
+	filters, err := s.filterSvc.FindByIndexerIdentifier(ctx, release.Indexer.Identifier)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reprocess error: could not find filters for indexer: %s", release.Indexer.Identifier)
+	}
+
+	if req.FilterID != 0 {
+		filters = slices.DeleteFunc(filters, func(f *domain.Filter) bool {
+			return f.ID != req.FilterID
+		})
+
+		if len(filters) == 0 {
+			return nil, nil, domain.ValidationErrors{{Field: "filter_id", Message: fmt.Sprintf("filter %d is not an active filter of indexer %s", req.FilterID, release.Indexer.Name)}}
+		}
+	}
+
+	if len(filters) == 0 {
+		return nil, nil, domain.ValidationErrors{{Field: "filter_id", Message: fmt.Sprintf("indexer %s has no active filters", release.Indexer.Name)}}
+	}
+
+	return release, filters, nil
+}
+
+// getStoredRelease returns the stored release with its indexer and the fields parsed from the name, which aren't stored
+func (s *service) getStoredRelease(ctx context.Context, id int64) (*domain.Release, error) {
+	release, err := s.Get(ctx, &domain.GetReleaseRequest{Id: int(id)})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find release by id: %d", id)
+	}
+
+	indexerInfo, err := s.indexerSvc.GetBy(ctx, domain.GetIndexerRequest{Identifier: release.Indexer.Identifier})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get indexer by identifier: %s", release.Indexer.Identifier)
+	}
+
+	release.Indexer = domain.IndexerMinimal{
+		ID:                 int(indexerInfo.ID),
+		Name:               indexerInfo.Name,
+		Identifier:         indexerInfo.Identifier,
+		IdentifierExternal: indexerInfo.IdentifierExternal,
+	}
+
+	release.ParseString(release.TorrentName)
+
+	return release, nil
+}
+
+func (s *service) reprocess(release *domain.Release, filters []*domain.Filter) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error().Msgf("recovering from panic in release reprocess %s error: %v", release.TorrentName, r)
+		}
+	}()
+
+	defer release.CleanupTemporaryFiles()
+
+	release.Pipeline = s.pipeline(release.Indexer.Identifier)
+	release.NormalizedCategories = s.normalizeCategories(release)
+
+	if err := s.processFilters(context.Background(), filters, release); err != nil {
+		s.log.Error().Err(err).Msgf("release.Reprocess: error processing filters for indexer: %s", release.Indexer.Name)
+	}
+}
+
+// RetryFailed runs the actions of the failed pushes matching the request again in the background.
+// Unlike Retry the failed statuses are updated, so retrying again only picks up what failed again.
+func (s *service) RetryFailed(ctx context.Context, req *domain.ReleaseRetryFailedReq) (*domain.ReleaseRetryFailedResponse, error) {
+	statuses, err := s.repo.FindFailedActionStatuses(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find failed action statuses")
+	}
+
+	s.log.Info().Msgf("retrying %d failed pushes", len(statuses))
+
+	go s.retryFailed(statuses)
+
+	return &domain.ReleaseRetryFailedResponse{Retrying: len(statuses)}, nil
+}
+
+func (s *service) retryFailed(statuses []domain.ReleaseActionStatus) {
+	ctx := context.Background()
+
+	// releases and actions are shared by the statuses, the torrent is only downloaded once
+	releases := map[int64]*domain.Release{}
+	actions := map[int64]*domain.Action{}
+
+	defer func() {
+		for _, release := range releases {
+			release.CleanupTemporaryFiles()
+		}
+	}()
+
+	var failed int
+
+	for i := range statuses {
+		status := &statuses[i]
+
+		if err := s.retryFailedStatus(ctx, status, releases, actions); err != nil {
+			s.log.Error().Err(err).Msgf("release.RetryFailed: could not retry action %s for release %d", status.Action, status.ReleaseID)
+			failed++
+		}
+	}
+
+	s.log.Info().Msgf("retried %d failed pushes, %d failed again", len(statuses), failed)
+}
+
+func (s *service) retryFailedStatus(ctx context.Context, status *domain.ReleaseActionStatus, releases map[int64]*domain.Release, actions map[int64]*domain.Action) error {
+	release, ok := releases[status.ReleaseID]
+	if !ok {
+		var err error
+		if release, err = s.getStoredRelease(ctx, status.ReleaseID); err != nil {
+			return err
+		}
+
+		// it's a retry rather than a race against other peers
+		release.Reprocessed = true
+		releases[status.ReleaseID] = release
+	}
+
+	action, ok := actions[status.ActionID]
+	if !ok {
+		var err error
+		if action, err = s.actionSvc.Get(ctx, &domain.GetActionRequest{Id: int(status.ActionID)}); err != nil {
+			return errors.Wrap(err, "could not get action %d", status.ActionID)
+		}
+		actions[status.ActionID] = action
+	}
+
+	release.FilterName = status.Filter
+	release.FilterID = int(status.FilterID)
+
+	rejections, runErr := s.actionSvc.RunAction(ctx, action, release)
+
+	status.Timestamp = time.Now()
+	setActionResult(status, action, release, rejections, runErr)
+
+	if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+		return errors.Wrap(err, "could not update action status %d", status.ID)
+	}
+
+	return runErr
+}
+
+// pendingApproval returns the pending release with the id when it's waiting for approval
+func (s *service) pendingApproval(ctx context.Context, id int64) (*domain.ReleasePending, *domain.Release, *domain.Action, error) {
+	pending, err := s.pendingRepo.Get(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if pending.Type != domain.ReleasePendingTypeApproval {
+		return nil, nil, nil, errors.Wrap(domain.ErrRecordNotFound, "release %d is not waiting for approval", id)
+	}
+
+	release, _, err := s.loadPending(ctx, pending)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	action, err := s.actionSvc.Get(ctx, &domain.GetActionRequest{Id: pending.ActionID})
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "could not get action by id: %d", pending.ActionID)
+	}
+
+	return pending, release, action, nil
+}
+
+// ApprovePending runs the action parked for approval
+func (s *service) ApprovePending(ctx context.Context, id int64) error {
+	pending, release, action, err := s.pendingApproval(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// deleting claims the action, it was already approved or rejected if nothing was deleted
+	if err := s.pendingRepo.Delete(ctx, pending.ID); err != nil {
+		return err
+	}
+
+	defer release.CleanupTemporaryFiles()
+
+	if release.Filter.UpgradeReplace {
+		s.findUpgradeOriginals(ctx, s.log, release)
+	}
+
+	if err := s.retryAction(ctx, action, release); err != nil {
+		s.log.Error().Err(err).Msgf("release.ApprovePending: error running action: %s", action.Name)
+		return err
+	}
+
+	s.log.Info().Msgf("approved action %s for release %s", action.Name, release.TorrentName)
+
+	return nil
+}
+
+// RejectPending drops the action parked for approval and stores it as rejected
+func (s *service) RejectPending(ctx context.Context, id int64) error {
+	pending, release, action, err := s.pendingApproval(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// deleting claims the action, it was already approved or rejected if nothing was deleted
+	if err := s.pendingRepo.Delete(ctx, pending.ID); err != nil {
+		return err
+	}
+
+	status := domain.NewReleaseActionStatus(action, release)
+	status.Status = domain.ReleasePushStatusRejected
+	status.Rejections = []string{"rejected by manual approval"}
+
+	if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+		return errors.Wrap(err, "could not store action status")
+	}
+
+	s.log.Info().Msgf("rejected action %s for release %s", action.Name, release.TorrentName)
+
+	return nil
+}
+
+// Annotate sets the notes and user tags of a stored release and returns the updated release
+func (s *service) Annotate(ctx context.Context, req *domain.ReleaseAnnotationReq) (*domain.Release, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateAnnotation(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Get(ctx, &domain.GetReleaseRequest{Id: int(req.ReleaseID)})
+}
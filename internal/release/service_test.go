@@ -0,0 +1,376 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/indexer"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockReleaseRepo only implements storing and getting releases and statuses, calling anything else panics
+type mockReleaseRepo struct {
+	domain.ReleaseRepo
+	statuses []*domain.ReleaseActionStatus
+	stored   *domain.Release
+}
+
+func (r *mockReleaseRepo) Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error) {
+	if r.stored == nil || r.stored.ID != int64(req.Id) {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	rls := *r.stored
+	return &rls, nil
+}
+
+func (r *mockReleaseRepo) Store(ctx context.Context, release *domain.Release) error {
+	release.ID = 1
+	return nil
+}
+
+func (r *mockReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *domain.ReleaseActionStatus) error {
+	r.statuses = append(r.statuses, status)
+	return nil
+}
+
+// mockFilterService matches every filter, calling anything else panics
+type mockFilterService struct {
+	filter.Service
+	filters []*domain.Filter
+}
+
+func (s *mockFilterService) FindByIndexerIdentifier(ctx context.Context, indexer string) ([]*domain.Filter, error) {
+	return slices.Clone(s.filters), nil
+}
+
+func (s *mockFilterService) FindByID(ctx context.Context, filterID int) (*domain.Filter, error) {
+	for _, f := range s.filters {
+		if f.ID == filterID {
+			return f, nil
+		}
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (s *mockFilterService) CheckFilter(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	return true, nil
+}
+
+// mockActionService returns the configured actions and rejections, calling anything else panics
+type mockActionService struct {
+	action.Service
+	actions    map[int][]*domain.Action
+	rejections map[string][]string
+	ran        []string
+}
+
+func (s *mockActionService) FindByFilterID(ctx context.Context, filterID int, active *bool, withClient bool) ([]*domain.Action, error) {
+	return s.actions[filterID], nil
+}
+
+func (s *mockActionService) Get(ctx context.Context, req *domain.GetActionRequest) (*domain.Action, error) {
+	for _, actions := range s.actions {
+		for _, a := range actions {
+			if a.ID == req.Id {
+				return a, nil
+			}
+		}
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (s *mockActionService) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.ran = append(s.ran, action.Name)
+	return s.rejections[action.Name], nil
+}
+
+func TestService_processFilters(t *testing.T) {
+	actionSvc := &mockActionService{
+		actions: map[int][]*domain.Action{
+			1: {
+				{Name: "veto", Type: domain.ActionTypeExec, Enabled: true},
+				{Name: "qbit-1", Type: domain.ActionTypeQbittorrent, ClientID: 1, Enabled: true},
+			},
+			2: {
+				{Name: "qbit-2", Type: domain.ActionTypeQbittorrent, ClientID: 2, Enabled: true},
+			},
+		},
+		rejections: map[string][]string{
+			"veto": {"rejected by script"},
+		},
+	}
+
+	s := &service{
+		log:       zerolog.Nop(),
+		repo:      &mockReleaseRepo{},
+		actionSvc: actionSvc,
+		filterSvc: &mockFilterService{},
+	}
+
+	filters := []*domain.Filter{{ID: 1, Name: "vetoed"}, {ID: 2, Name: "next"}}
+
+	release := domain.NewRelease(domain.IndexerMinimal{Name: "Mock", Identifier: "mock"})
+	release.TorrentName = "That.Show.S01E01.1080p.WEB.h264-GROUP"
+
+	assert.NoError(t, s.processFilters(context.Background(), filters, release))
+
+	// the exec rejection skips the remaining actions of the filter and the next filter is tried
+	assert.Equal(t, []string{"veto", "qbit-2"}, actionSvc.ran)
+}
+
+func TestService_processFilters_RequireApproval(t *testing.T) {
+	actionSvc := &mockActionService{
+		actions: map[int][]*domain.Action{
+			1: {
+				{ID: 1, Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, Enabled: true, RequireApproval: true},
+				{ID: 2, Name: "webhook", Type: domain.ActionTypeWebhook, Enabled: true},
+			},
+		},
+	}
+
+	pendingRepo := newMockReleasePendingRepo()
+
+	s := &service{
+		log:         zerolog.Nop(),
+		repo:        &mockReleaseRepo{},
+		pendingRepo: pendingRepo,
+		actionSvc:   actionSvc,
+		filterSvc:   &mockFilterService{},
+	}
+
+	release := domain.NewRelease(domain.IndexerMinimal{Name: "Mock", Identifier: "mock"})
+	release.TorrentName = "That.Show.S01E01.1080p.WEB.h264-GROUP"
+
+	assert.NoError(t, s.processFilters(context.Background(), []*domain.Filter{{ID: 1, Name: "gated"}}, release))
+
+	// only the action without approval ran, the other one is parked
+	assert.Equal(t, []string{"webhook"}, actionSvc.ran)
+
+	pending, err := s.ListPending(context.Background(), domain.ReleasePendingTypeApproval)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, 1, pending[0].ActionID)
+		assert.Equal(t, release.ID, pending[0].ReleaseID)
+		assert.Equal(t, "gated", pending[0].FilterName)
+	}
+
+	counts, err := s.PendingCounts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, counts.Approval)
+}
+
+// stalePendingRepo keeps returning the pending release after it was deleted, like a concurrent request that read it first
+type stalePendingRepo struct {
+	*mockReleasePendingRepo
+	stale *domain.ReleasePending
+}
+
+func (r *stalePendingRepo) Get(ctx context.Context, id int64) (*domain.ReleasePending, error) {
+	return r.stale, nil
+}
+
+func TestService_ApprovePending_once(t *testing.T) {
+	actionSvc := &mockActionService{
+		actions: map[int][]*domain.Action{
+			1: {{ID: 4, Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, Enabled: true, RequireApproval: true}},
+		},
+	}
+
+	repo := &mockReleaseRepo{stored: &domain.Release{
+		ID:          7,
+		Indexer:     domain.IndexerMinimal{Identifier: "mock"},
+		TorrentName: "That.Show.S01E01.1080p.WEB.h264-GROUP",
+	}}
+
+	pending := domain.NewReleasePendingApproval(repo.stored, actionSvc.actions[1][0])
+	pending.FilterID = 1
+	pendingRepo := newMockReleasePendingRepo(pending)
+
+	s := &service{
+		log:         zerolog.Nop(),
+		repo:        repo,
+		pendingRepo: &stalePendingRepo{mockReleasePendingRepo: pendingRepo, stale: pending},
+		actionSvc:   actionSvc,
+		indexerSvc:  &mockIndexerService{indexer: domain.Indexer{ID: 3, Name: "Mock", Identifier: "mock"}},
+		filterSvc:   &mockFilterService{filters: []*domain.Filter{{ID: 1, Name: "gated"}}},
+	}
+
+	assert.NoError(t, s.ApprovePending(context.Background(), pending.ID))
+	assert.Equal(t, []string{"qbit"}, actionSvc.ran)
+	statuses := len(repo.statuses)
+
+	// the action was already approved, it doesn't run or get rejected again
+	assert.ErrorIs(t, s.ApprovePending(context.Background(), pending.ID), domain.ErrRecordNotFound)
+	assert.ErrorIs(t, s.RejectPending(context.Background(), pending.ID), domain.ErrRecordNotFound)
+	assert.Equal(t, []string{"qbit"}, actionSvc.ran)
+	assert.Len(t, repo.statuses, statuses)
+}
+
+func TestService_processFilters_RunCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		rejections map[string][]string
+		want       []string
+	}{
+		{
+			name: "primary_pushed",
+			want: []string{"qbit", "webhook"},
+		},
+		{
+			name:       "fallback",
+			rejections: map[string][]string{"qbit": {"max active downloads reached"}},
+			want:       []string{"qbit", "deluge", "webhook"},
+		},
+		{
+			name:       "fallback_failed",
+			rejections: map[string][]string{"qbit": {"max active downloads reached"}, "deluge": {"not enough free space"}},
+			want:       []string{"qbit", "deluge", "notify-failure", "webhook"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionSvc := &mockActionService{
+				actions: map[int][]*domain.Action{
+					1: {
+						{Name: "on-success-first", Type: domain.ActionTypeTest, Enabled: true, RunCondition: domain.ActionRunConditionOnSuccess},
+						{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, Enabled: true},
+						{Name: "deluge", Type: domain.ActionTypeDelugeV2, ClientID: 2, Enabled: true, RunCondition: domain.ActionRunConditionOnFailure},
+						{Name: "notify-failure", Type: domain.ActionTypeTest, Enabled: true, RunCondition: domain.ActionRunConditionOnFailure},
+						{Name: "webhook", Type: domain.ActionTypeWebhook, Enabled: true, RunCondition: domain.ActionRunConditionAlways},
+					},
+				},
+				rejections: tt.rejections,
+			}
+
+			s := &service{
+				log:       zerolog.Nop(),
+				repo:      &mockReleaseRepo{},
+				actionSvc: actionSvc,
+				filterSvc: &mockFilterService{},
+			}
+
+			release := domain.NewRelease(domain.IndexerMinimal{Name: "Mock", Identifier: "mock"})
+			release.TorrentName = "That.Show.S01E01.1080p.WEB.h264-GROUP"
+
+			assert.NoError(t, s.processFilters(context.Background(), []*domain.Filter{{ID: 1, Name: "chained"}}, release))
+
+			// conditional actions need an action before them and follow the last action that ran
+			assert.Equal(t, tt.want, actionSvc.ran)
+		})
+	}
+}
+
+// mockIndexerService knows a single indexer, calling anything else panics
+type mockIndexerService struct {
+	indexer.Service
+	indexer domain.Indexer
+}
+
+func (s *mockIndexerService) GetBy(ctx context.Context, req domain.GetIndexerRequest) (*domain.Indexer, error) {
+	if req.Identifier != s.indexer.Identifier {
+		return nil, domain.ErrRecordNotFound
+	}
+	return &s.indexer, nil
+}
+
+func (s *mockIndexerService) GetMappedDefinitionByName(name string) (*domain.IndexerDefinition, error) {
+	return nil, domain.ErrRecordNotFound
+}
+
+func TestService_Reprocess(t *testing.T) {
+	actionSvc := &mockActionService{
+		actions: map[int][]*domain.Action{
+			1: {{Name: "qbit-1", Type: domain.ActionTypeQbittorrent, ClientID: 1, Enabled: true}},
+			2: {{Name: "qbit-2", Type: domain.ActionTypeQbittorrent, ClientID: 2, Enabled: true}},
+		},
+	}
+
+	repo := &mockReleaseRepo{stored: &domain.Release{
+		ID:          7,
+		Indexer:     domain.IndexerMinimal{Identifier: "mock"},
+		TorrentName: "That.Show.S01E01.1080p.WEB.h264-GROUP",
+	}}
+
+	s := &service{
+		log:        zerolog.Nop(),
+		repo:       repo,
+		actionSvc:  actionSvc,
+		indexerSvc: &mockIndexerService{indexer: domain.Indexer{ID: 3, Name: "Mock", Identifier: "mock"}},
+		filterSvc:  &mockFilterService{filters: []*domain.Filter{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}}},
+	}
+
+	_, _, err := s.loadReprocess(context.Background(), &domain.ReleaseReprocessReq{ReleaseID: 8})
+	assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+	_, _, err = s.loadReprocess(context.Background(), &domain.ReleaseReprocessReq{ReleaseID: 7, FilterID: 5})
+	assert.ErrorContains(t, err, "filter 5 is not an active filter of indexer Mock")
+
+	release, filters, err := s.loadReprocess(context.Background(), &domain.ReleaseReprocessReq{ReleaseID: 7, FilterID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "Mock", release.Indexer.Name)
+	assert.Equal(t, "1080p", release.Resolution)
+	assert.True(t, release.Reprocessed)
+
+	s.reprocess(release, filters)
+
+	// only the requested filter ran, the statuses belong to the stored release without push latency
+	assert.Equal(t, []string{"qbit-2"}, actionSvc.ran)
+	if assert.NotEmpty(t, repo.statuses) {
+		status := repo.statuses[len(repo.statuses)-1]
+		assert.Equal(t, domain.ReleasePushStatusApproved, status.Status)
+		assert.Equal(t, int64(7), status.ReleaseID)
+		assert.Zero(t, status.LatencyMs)
+	}
+}
+
+func TestService_retryFailed(t *testing.T) {
+	actionSvc := &mockActionService{
+		actions: map[int][]*domain.Action{
+			1: {{ID: 4, Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, Enabled: true}},
+		},
+	}
+
+	repo := &mockReleaseRepo{stored: &domain.Release{
+		ID:          7,
+		Indexer:     domain.IndexerMinimal{Identifier: "mock"},
+		TorrentName: "That.Show.S01E01.1080p.WEB.h264-GROUP",
+	}}
+
+	s := &service{
+		log:        zerolog.Nop(),
+		repo:       repo,
+		actionSvc:  actionSvc,
+		indexerSvc: &mockIndexerService{indexer: domain.Indexer{ID: 3, Name: "Mock", Identifier: "mock"}},
+	}
+
+	failedAt := time.Now().Add(-time.Hour)
+
+	s.retryFailed([]domain.ReleaseActionStatus{
+		{ID: 11, Status: domain.ReleasePushStatusErr, Action: "qbit", ActionID: 4, Filter: "shows", FilterID: 1, ReleaseID: 7, Rejections: []string{"connection refused"}, Timestamp: failedAt},
+		// the action was deleted since
+		{ID: 12, Status: domain.ReleasePushStatusErr, Action: "gone", ActionID: 5, ReleaseID: 7, Timestamp: failedAt},
+	})
+
+	assert.Equal(t, []string{"qbit"}, actionSvc.ran)
+
+	// the failed status is updated instead of adding another one
+	if assert.Len(t, repo.statuses, 1) {
+		status := repo.statuses[0]
+		assert.Equal(t, int64(11), status.ID)
+		assert.Equal(t, domain.ReleasePushStatusApproved, status.Status)
+		assert.Empty(t, status.Rejections)
+		assert.True(t, status.Timestamp.After(failedAt))
+	}
+}
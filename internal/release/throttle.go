@@ -0,0 +1,186 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+)
+
+const (
+	pushBudgetWindow        = 1 * time.Hour
+	pushBudgetCheckInterval = 1 * time.Minute
+
+	// queued releases older than this are dropped, they are likely not worth grabbing anymore
+	pushQueueMaxAge = 24 * time.Hour
+	pushQueueMaxLen = 1000
+)
+
+type queuedRelease struct {
+	release  *domain.Release
+	filter   *domain.Filter
+	queuedAt time.Time
+}
+
+// pushThrottle limits the size of releases pushed to clients per hour.
+// Releases exceeding the budget are queued and ordered by filter priority.
+type pushThrottle struct {
+	log   zerolog.Logger
+	limit uint64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	used        uint64
+	queue       []queuedRelease
+}
+
+func newPushThrottle(log zerolog.Logger, limit uint64) *pushThrottle {
+	return &pushThrottle{
+		log:         log,
+		limit:       limit,
+		windowStart: time.Now().Truncate(pushBudgetWindow),
+	}
+}
+
+// resetWindow must be called with the lock held
+func (t *pushThrottle) resetWindow(now time.Time) {
+	if now.Sub(t.windowStart) < pushBudgetWindow {
+		return
+	}
+
+	t.windowStart = now.Truncate(pushBudgetWindow)
+	t.used = 0
+}
+
+// fitsLocked must be called with the lock held
+func (t *pushThrottle) fitsLocked(size uint64) bool {
+	// unknown sizes can't be budgeted, and a release larger than the
+	// whole budget is allowed at the start of a window to not block forever
+	return size == 0 || t.used == 0 || t.used+size <= t.limit
+}
+
+// pushReservation is a part of the budget held for a release until it is pushed
+type pushReservation struct {
+	window time.Time
+	size   uint64
+}
+
+// reserve takes the size from the budget if it fits, so concurrent releases can't exceed it
+func (t *pushThrottle) reserve(size uint64) (pushReservation, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.resetWindow(time.Now())
+
+	if !t.fitsLocked(size) {
+		return pushReservation{}, false
+	}
+
+	t.used += size
+
+	return pushReservation{window: t.windowStart, size: size}, true
+}
+
+// release gives back a reservation when the release was not pushed
+func (t *pushThrottle) release(r pushReservation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// the budget was already reset by a new window
+	if !t.windowStart.Equal(r.window) {
+		return
+	}
+
+	if t.used < r.size {
+		t.used = 0
+		return
+	}
+
+	t.used -= r.size
+}
+
+func (t *pushThrottle) enqueue(release *domain.Release, filter *domain.Filter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.queue) >= pushQueueMaxLen {
+		t.log.Warn().Msgf("push queue is full, dropping release: %s", release.TorrentName)
+		return
+	}
+
+	t.queue = append(t.queue, queuedRelease{
+		release:  release,
+		filter:   filter,
+		queuedAt: time.Now(),
+	})
+
+	// highest filter priority first, then oldest
+	sort.SliceStable(t.queue, func(i, j int) bool {
+		return t.queue[i].filter.Priority > t.queue[j].filter.Priority
+	})
+
+	t.log.Info().Msgf("push budget of %s per hour exceeded (%s used), queued release: %s (%s)", humanize.Bytes(t.limit), humanize.Bytes(t.used), release.TorrentName, humanize.Bytes(release.Size))
+}
+
+// next pops the highest priority queued release if it fits in the budget.
+// The budget is reserved when the release is processed again.
+func (t *pushThrottle) next() (queuedRelease, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.resetWindow(now)
+
+	// drop stale releases
+	queue := t.queue[:0]
+	for _, item := range t.queue {
+		if now.Sub(item.queuedAt) > pushQueueMaxAge {
+			t.log.Warn().Msgf("dropping release queued for more than %s: %s", pushQueueMaxAge, item.release.TorrentName)
+			continue
+		}
+		queue = append(queue, item)
+	}
+	t.queue = queue
+
+	if len(t.queue) == 0 || !t.fitsLocked(t.queue[0].release.Size) {
+		return queuedRelease{}, false
+	}
+
+	item := t.queue[0]
+	t.queue = t.queue[1:]
+
+	return item, true
+}
+
+type PushQueueJob struct {
+	Name string
+	Log  zerolog.Logger
+	svc  *service
+}
+
+// Run processes queued releases while they fit in the push budget
+func (j *PushQueueJob) Run() {
+	for {
+		item, ok := j.svc.pushThrottle.next()
+		if !ok {
+			return
+		}
+
+		j.Log.Debug().Msgf("processing queued release: %s (%s)", item.release.TorrentName, item.filter.Name)
+
+		// the filter is checked again since things like max downloads could have changed while queued
+		if err := j.svc.processFilters(context.Background(), []*domain.Filter{item.filter}, item.release); err != nil {
+			j.Log.Error().Err(err).Msgf("error processing queued release: %s", item.release.TorrentName)
+		}
+
+		item.release.CleanupTemporaryFiles()
+	}
+}
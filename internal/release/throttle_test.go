@@ -0,0 +1,170 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushThrottle_Reserve(t *testing.T) {
+	t.Run("budget", func(t *testing.T) {
+		throttle := newPushThrottle(zerolog.Nop(), 100)
+
+		first, ok := throttle.reserve(60)
+		assert.True(t, ok)
+
+		_, ok = throttle.reserve(60)
+		assert.False(t, ok)
+
+		// unknown sizes are never throttled
+		_, ok = throttle.reserve(0)
+		assert.True(t, ok)
+
+		throttle.release(first)
+		assert.Equal(t, uint64(0), throttle.used)
+
+		_, ok = throttle.reserve(60)
+		assert.True(t, ok)
+	})
+
+	t.Run("larger_than_budget", func(t *testing.T) {
+		throttle := newPushThrottle(zerolog.Nop(), 100)
+
+		_, ok := throttle.reserve(500)
+		assert.True(t, ok)
+
+		_, ok = throttle.reserve(1)
+		assert.False(t, ok)
+	})
+
+	t.Run("release_after_new_window", func(t *testing.T) {
+		throttle := newPushThrottle(zerolog.Nop(), 100)
+
+		old, ok := throttle.reserve(60)
+		assert.True(t, ok)
+
+		// the reservation is from the previous window which was replaced
+		throttle.release(pushReservation{window: old.window.Add(-pushBudgetWindow), size: old.size})
+		assert.Equal(t, uint64(60), throttle.used)
+
+		throttle.release(old)
+		_, ok = throttle.reserve(30)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(30), throttle.used)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		throttle := newPushThrottle(zerolog.Nop(), 100)
+
+		var (
+			wg       sync.WaitGroup
+			reserved atomic.Int32
+		)
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, ok := throttle.reserve(10); ok {
+					reserved.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(10), reserved.Load())
+		assert.Equal(t, uint64(100), throttle.used)
+	})
+}
+
+func TestPushThrottle_Next(t *testing.T) {
+	throttle := newPushThrottle(zerolog.Nop(), 100)
+
+	low := &domain.Filter{ID: 1, Name: "low", Priority: 1}
+	high := &domain.Filter{ID: 2, Name: "high", Priority: 10}
+
+	throttle.enqueue(&domain.Release{TorrentName: "Low", Size: 50}, low)
+	throttle.enqueue(&domain.Release{TorrentName: "High", Size: 50}, high)
+	throttle.enqueue(&domain.Release{TorrentName: "Stale", Size: 10}, high)
+	for i := range throttle.queue {
+		if throttle.queue[i].release.TorrentName == "Stale" {
+			throttle.queue[i].queuedAt = time.Now().Add(-2 * pushQueueMaxAge)
+		}
+	}
+
+	item, ok := throttle.next()
+	assert.True(t, ok)
+	assert.Equal(t, "High", item.release.TorrentName)
+
+	// the stale release was dropped
+	assert.Len(t, throttle.queue, 1)
+
+	_, ok = throttle.reserve(90)
+	assert.True(t, ok)
+
+	_, ok = throttle.next()
+	assert.False(t, ok)
+	assert.Len(t, throttle.queue, 1)
+}
+
+func TestPushQueueJob_Run(t *testing.T) {
+	actionSvc := &mockActionService{
+		actions: map[int][]*domain.Action{
+			1: {{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, Enabled: true}},
+		},
+	}
+
+	s := &service{
+		log:          zerolog.Nop(),
+		repo:         &mockReleaseRepo{},
+		actionSvc:    actionSvc,
+		filterSvc:    &mockFilterService{},
+		pushThrottle: newPushThrottle(zerolog.Nop(), 100),
+	}
+
+	f := &domain.Filter{ID: 1, Name: "filter"}
+
+	// the budget is used up by a push, following releases are queued
+	for _, name := range []string{"First", "Second", "Third"} {
+		release := domain.NewRelease(domain.IndexerMinimal{Name: "Mock", Identifier: "mock"})
+		release.TorrentName = name
+		release.Size = 60
+
+		assert.NoError(t, s.processFilters(context.Background(), []*domain.Filter{f}, release))
+	}
+
+	assert.Equal(t, []string{"qbit"}, actionSvc.ran)
+	assert.Len(t, s.pushThrottle.queue, 2)
+
+	job := &PushQueueJob{Name: "push-queue", Log: zerolog.Nop(), svc: s}
+
+	// nothing fits until the next window
+	job.Run()
+	assert.Len(t, actionSvc.ran, 1)
+
+	s.pushThrottle.windowStart = s.pushThrottle.windowStart.Add(-pushBudgetWindow)
+
+	job.Run()
+	assert.Len(t, actionSvc.ran, 2)
+	assert.Len(t, s.pushThrottle.queue, 1)
+	assert.Equal(t, uint64(60), s.pushThrottle.used)
+
+	// a rejected push gives back the budget
+	actionSvc.rejections = map[string][]string{"qbit": {"max active downloads reached"}}
+	s.pushThrottle.windowStart = s.pushThrottle.windowStart.Add(-pushBudgetWindow)
+
+	job.Run()
+	assert.Len(t, actionSvc.ran, 3)
+	assert.Len(t, s.pushThrottle.queue, 0)
+	assert.Equal(t, uint64(0), s.pushThrottle.used)
+}
@@ -125,6 +125,16 @@ func (s *DownloadService) downloadTorrentFile(ctx context.Context, indexer *doma
 		req.Header.Set("Cookie", r.RawCookie)
 	}
 
+	// apply the request identity of the indexer to the download host only, the cookie of the release takes precedence
+	if indexer.Identity.IsSet() {
+		identityTransport, err := domain.NewIdentityTransport(httpClient.Transport, indexer.Identity, r.DownloadURL)
+		if err != nil {
+			return errors.Wrap(err, "could not setup identity for indexer: %s", indexer.Name)
+		}
+
+		httpClient.Transport = identityTransport
+	}
+
 	tmpFilePattern := "autobrr-"
 	tmpDir := os.TempDir()
 
@@ -242,7 +252,7 @@ func retryableRequest(httpClient *http.Client, req *http.Request, r *domain.Rele
 
 		r.TorrentTmpFile = tmpFile.Name()
 		r.TorrentHash = meta.HashInfoBytes().String()
-		r.Size = uint64(torrentMetaInfo.TotalLength())
+		r.SetTorrentSize(uint64(torrentMetaInfo.TotalLength()))
 
 		return nil
 	}
@@ -291,6 +301,15 @@ func (s *DownloadService) ResolveMagnetURI(ctx context.Context, r *domain.Releas
 	//req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "autobrr")
 
+	if indexer.Identity.IsSet() {
+		identityTransport, err := domain.NewIdentityTransport(httpClient.Transport, indexer.Identity, r.MagnetURI)
+		if err != nil {
+			return errors.Wrap(err, "could not setup identity for indexer: %s", indexer.Name)
+		}
+
+		httpClient.Transport = identityTransport
+	}
+
 	res, err := httpClient.Do(req)
 	if err != nil {
 		return errors.Wrap(err, "could not make request to resolve magnet uri")
@@ -0,0 +1,232 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package releasedownload
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"golang.org/x/net/publicsuffix"
+)
+
+// TorrentValidation are the checks of the downloaded torrent file of a release
+type TorrentValidation struct {
+	// Announce checks the announce urls belong to the indexer and contain the passkey
+	Announce bool
+
+	// AnnounceHosts are the expected announce hosts, the hosts of the indexer are used when empty
+	AnnounceHosts []string
+
+	// History rejects torrents with an infohash already pushed for another release
+	History bool
+
+	// Private rejects torrents with another private flag when set
+	Private domain.TorrentPrivacy
+}
+
+// passkeySettings are the indexer settings holding the key that's part of the announce urls
+var passkeySettings = []string{"passkey", "torrent_pass"}
+
+// passkeyPattern matches announce url parts that look like a passkey when the indexer has none configured
+var passkeyPattern = regexp.MustCompile(`^[a-zA-Z0-9]{16,}$`)
+
+// ValidateTorrent checks the downloaded torrent file of the release and returns the reasons to reject it
+func (s *DownloadService) ValidateTorrent(ctx context.Context, rls *domain.Release, v TorrentValidation) ([]string, error) {
+	if rls.TorrentTmpFile == "" {
+		return nil, errors.New("torrent file of release %s not downloaded", rls.TorrentName)
+	}
+
+	meta, err := metainfo.LoadFromFile(rls.TorrentTmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load torrent file: %s", rls.TorrentTmpFile)
+	}
+
+	var rejections []string
+
+	if v.Announce {
+		indexer, err := s.indexerRepo.FindByID(ctx, rls.Indexer.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find indexer: %s", rls.Indexer.Name)
+		}
+
+		hosts := v.AnnounceHosts
+		if len(hosts) == 0 {
+			hosts = indexerHosts(indexer.BaseURL, rls.DownloadURL)
+		}
+
+		var passkeys []string
+		for _, key := range passkeySettings {
+			if value := indexer.Settings[key]; value != "" {
+				passkeys = append(passkeys, value)
+			}
+		}
+
+		rejections = append(rejections, announceRejections(announceURLs(meta), hosts, passkeys)...)
+	}
+
+	if v.Private != "" {
+		info, err := meta.UnmarshalInfo()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal info from torrent: %s", rls.TorrentTmpFile)
+		}
+
+		if rejection := privateRejection(info.Private != nil && *info.Private, v.Private); rejection != "" {
+			rejections = append(rejections, rejection)
+		}
+	}
+
+	if v.History {
+		infoHash := meta.HashInfoBytes().String()
+
+		pushed, err := s.repo.CheckInfoHashPushed(ctx, infoHash, rls.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not check history for infohash: %s", infoHash)
+		}
+
+		if pushed {
+			rejections = append(rejections, fmt.Sprintf("torrent validation: infohash %s already pushed", infoHash))
+		}
+	}
+
+	return rejections, nil
+}
+
+// announceURLs returns the announce url and the urls of the announce list without duplicates
+func announceURLs(meta *metainfo.MetaInfo) []string {
+	var urls []string
+
+	if meta.Announce != "" {
+		urls = append(urls, meta.Announce)
+	}
+
+	for _, tier := range meta.AnnounceList {
+		for _, u := range tier {
+			if u != "" && !slices.Contains(urls, u) {
+				urls = append(urls, u)
+			}
+		}
+	}
+
+	return urls
+}
+
+// indexerHosts returns the registered domains of the indexer urls, trackers usually announce on a subdomain
+func indexerHosts(urls ...string) []string {
+	var hosts []string
+
+	for _, rawURL := range urls {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+
+		host, err := publicsuffix.EffectiveTLDPlusOne(u.Hostname())
+		if err != nil {
+			host = u.Hostname()
+		}
+
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// announceRejections checks every announce url is on one of the hosts and contains a passkey.
+// Without passkeys from the indexer settings any long alphanumeric path segment or query value is accepted.
+func announceRejections(announces []string, hosts []string, passkeys []string) []string {
+	if len(announces) == 0 {
+		return []string{"torrent validation: no announce url"}
+	}
+
+	var rejections []string
+
+	for _, announce := range announces {
+		u, err := url.Parse(announce)
+		if err != nil {
+			rejections = append(rejections, fmt.Sprintf("torrent validation: invalid announce url: %s", announce))
+			continue
+		}
+
+		if len(hosts) > 0 && !matchesHost(u.Hostname(), hosts) {
+			rejections = append(rejections, fmt.Sprintf("torrent validation: announce host %s does not belong to %s", u.Hostname(), strings.Join(hosts, ", ")))
+		}
+
+		if !hasPasskey(u, passkeys) {
+			rejections = append(rejections, fmt.Sprintf("torrent validation: no passkey in announce url on %s", u.Hostname()))
+		}
+	}
+
+	return rejections
+}
+
+// matchesHost reports if the host is one of the hosts or a subdomain of one
+func matchesHost(host string, hosts []string) bool {
+	host = strings.ToLower(host)
+
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasPasskey(u *url.URL, passkeys []string) bool {
+	if len(passkeys) > 0 {
+		for _, key := range passkeys {
+			if strings.Contains(u.String(), key) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, segment := range strings.Split(u.Path, "/") {
+		if passkeyPattern.MatchString(segment) {
+			return true
+		}
+	}
+
+	for _, values := range u.Query() {
+		for _, value := range values {
+			if passkeyPattern.MatchString(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// privateRejection checks the private flag of the torrent against the expected privacy
+func privateRejection(private bool, expected domain.TorrentPrivacy) string {
+	switch expected {
+	case domain.TorrentPrivacyPrivate:
+		if !private {
+			return "torrent validation: torrent is not private"
+		}
+	case domain.TorrentPrivacyPublic:
+		if private {
+			return "torrent validation: torrent is private"
+		}
+	}
+
+	return ""
+}
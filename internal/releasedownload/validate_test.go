@@ -0,0 +1,120 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package releasedownload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIndexerRepo only implements FindByID, calling anything else panics
+type mockIndexerRepo struct {
+	domain.IndexerRepo
+	indexer *domain.Indexer
+}
+
+func (r *mockIndexerRepo) FindByID(ctx context.Context, id int) (*domain.Indexer, error) {
+	return r.indexer, nil
+}
+
+// mockReleaseRepo only implements CheckInfoHashPushed, calling anything else panics
+type mockReleaseRepo struct {
+	domain.ReleaseRepo
+	pushed map[string]int64
+}
+
+func (r *mockReleaseRepo) CheckInfoHashPushed(ctx context.Context, infoHash string, excludeReleaseID int64) (bool, error) {
+	releaseID, ok := r.pushed[infoHash]
+	return ok && releaseID != excludeReleaseID, nil
+}
+
+func writeTorrent(t *testing.T, announce string, announceList [][]string, private bool) (string, string) {
+	t.Helper()
+
+	info := metainfo.Info{Name: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP.mkv", PieceLength: 16384, Pieces: make([]byte, 20), Length: 1024, Private: &private}
+
+	infoBytes, err := bencode.Marshal(info)
+	require.NoError(t, err)
+
+	meta := metainfo.MetaInfo{Announce: announce, AnnounceList: announceList, InfoBytes: infoBytes}
+
+	path := filepath.Join(t.TempDir(), "release.torrent")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NoError(t, meta.Write(f))
+
+	return path, meta.HashInfoBytes().String()
+}
+
+func TestDownloadService_ValidateTorrent(t *testing.T) {
+	indexerRepo := &mockIndexerRepo{indexer: &domain.Indexer{Identifier: "mock", Settings: map[string]string{"passkey": "0123456789abcdef0123456789abcdef"}}}
+	releaseRepo := &mockReleaseRepo{pushed: map[string]int64{}}
+
+	s := &DownloadService{repo: releaseRepo, indexerRepo: indexerRepo}
+
+	path, infoHash := writeTorrent(t, "https://tracker.mock.example.com/0123456789abcdef0123456789abcdef/announce", nil, true)
+	rls := &domain.Release{ID: 2, TorrentName: "Show.Name.S01E01.1080p.WEB-DL.H264-GRP", DownloadURL: "https://www.mock.example.com/download/1", TorrentTmpFile: path}
+
+	all := TorrentValidation{Announce: true, History: true, Private: domain.TorrentPrivacyPrivate}
+
+	rejections, err := s.ValidateTorrent(context.Background(), rls, all)
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+
+	// pushed by the release itself, eg. by a previous action
+	releaseRepo.pushed[infoHash] = 2
+
+	rejections, err = s.ValidateTorrent(context.Background(), rls, all)
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+
+	releaseRepo.pushed[infoHash] = 1
+
+	rejections, err = s.ValidateTorrent(context.Background(), rls, all)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"torrent validation: infohash " + infoHash + " already pushed"}, rejections)
+
+	rejections, err = s.ValidateTorrent(context.Background(), rls, TorrentValidation{Announce: true, AnnounceHosts: []string{"flacsfor.me"}, Private: domain.TorrentPrivacyPublic})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"torrent validation: announce host tracker.mock.example.com does not belong to flacsfor.me", "torrent validation: torrent is private"}, rejections)
+}
+
+func Test_announceRejections(t *testing.T) {
+	tests := []struct {
+		name      string
+		announces []string
+		hosts     []string
+		passkeys  []string
+		want      int
+	}{
+		{name: "subdomain_with_passkey", announces: []string{"https://tracker.example.com/abc123/announce"}, hosts: []string{"example.com"}, passkeys: []string{"abc123"}},
+		{name: "wrong_host", announces: []string{"https://tracker.other.org/abc123/announce"}, hosts: []string{"example.com"}, passkeys: []string{"abc123"}, want: 1},
+		{name: "lookalike_host", announces: []string{"https://notexample.com/abc123/announce"}, hosts: []string{"example.com"}, passkeys: []string{"abc123"}, want: 1},
+		{name: "missing_passkey", announces: []string{"https://tracker.example.com/announce"}, hosts: []string{"example.com"}, passkeys: []string{"abc123"}, want: 1},
+		{name: "passkey_in_query", announces: []string{"https://tracker.example.com/announce.php?passkey=0123456789abcdef"}, hosts: []string{"example.com"}},
+		{name: "no_passkey_pattern", announces: []string{"udp://tracker.example.com:1337/announce"}, hosts: []string{"example.com"}, want: 1},
+		{name: "every_url_in_list", announces: []string{"https://tracker.example.com/abc123/announce", "https://backup.other.org/announce"}, hosts: []string{"example.com"}, passkeys: []string{"abc123"}, want: 2},
+		{name: "no_announce", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, announceRejections(tt.announces, tt.hosts, tt.passkeys), tt.want)
+		})
+	}
+}
+
+func Test_indexerHosts(t *testing.T) {
+	assert.Equal(t, []string{"example.com", "example.co.uk"}, indexerHosts("https://www.example.com/", "https://dl.example.com/torrents/1", "https://www.example.co.uk", "", "not a url"))
+}
@@ -5,9 +5,11 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/update"
 
@@ -48,3 +50,83 @@ func (j *CheckUpdatesJob) Run() {
 		j.lastCheckVersion = newRelease.TagName
 	}
 }
+
+// RetryNotificationsJob sends the queued notifications which failed before and are due for a retry
+type RetryNotificationsJob struct {
+	Name     string
+	Log      zerolog.Logger
+	NotifSvc notification.Service
+}
+
+func (j *RetryNotificationsJob) Run() {
+	if err := j.NotifSvc.RetryDeliveries(context.TODO()); err != nil {
+		j.Log.Error().Err(err).Msg("could not retry notifications")
+	}
+}
+
+// DownloadClientHealthJob tests the connection of the enabled download clients,
+// a client is notified once when it becomes unhealthy and again only after it recovered
+type DownloadClientHealthJob struct {
+	Name              string
+	Log               zerolog.Logger
+	NotifSvc          notification.Service
+	DownloadClientSvc download_client.Service
+
+	unhealthy map[int32]struct{}
+}
+
+func (j *DownloadClientHealthJob) Run() {
+	ctx := context.TODO()
+
+	clients, err := j.DownloadClientSvc.List(ctx)
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not list download clients")
+		return
+	}
+
+	if j.unhealthy == nil {
+		j.unhealthy = map[int32]struct{}{}
+	}
+
+	checked := make(map[int32]struct{}, len(clients))
+
+	for _, client := range clients {
+		if !client.Enabled {
+			continue
+		}
+
+		checked[client.ID] = struct{}{}
+
+		_, wasUnhealthy := j.unhealthy[client.ID]
+
+		if err := j.DownloadClientSvc.Test(ctx, client); err != nil {
+			j.unhealthy[client.ID] = struct{}{}
+
+			if wasUnhealthy {
+				continue
+			}
+
+			j.NotifSvc.Send(domain.NotificationEventDownloadClientUnhealthy, domain.NotificationPayload{
+				Subject:      "Download client unhealthy",
+				Message:      fmt.Sprintf("Client: %s Error: %s", client.Name, err.Error()),
+				ActionClient: client.Name,
+				Event:        domain.NotificationEventDownloadClientUnhealthy,
+				Timestamp:    time.Now(),
+			})
+
+			continue
+		}
+
+		if wasUnhealthy {
+			j.Log.Info().Msgf("download client recovered: %s", client.Name)
+			delete(j.unhealthy, client.ID)
+		}
+	}
+
+	// forget clients which were disabled or deleted
+	for id := range j.unhealthy {
+		if _, ok := checked[id]; !ok {
+			delete(j.unhealthy, id)
+		}
+	}
+}
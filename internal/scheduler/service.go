@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/update"
@@ -27,23 +28,25 @@ type Service interface {
 }
 
 type service struct {
-	log             zerolog.Logger
-	config          *domain.Config
-	version         string
-	notificationSvc notification.Service
-	updateSvc       *update.Service
+	log               zerolog.Logger
+	config            *domain.Config
+	version           string
+	notificationSvc   notification.Service
+	updateSvc         *update.Service
+	downloadClientSvc download_client.Service
 
 	cron *cron.Cron
 	jobs map[string]cron.EntryID
 	m    sync.RWMutex
 }
 
-func NewService(log logger.Logger, config *domain.Config, notificationSvc notification.Service, updateSvc *update.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, notificationSvc notification.Service, updateSvc *update.Service, downloadClientSvc download_client.Service) Service {
 	return &service{
-		log:             log.With().Str("module", "scheduler").Logger(),
-		config:          config,
-		notificationSvc: notificationSvc,
-		updateSvc:       updateSvc,
+		log:               log.With().Str("module", "scheduler").Logger(),
+		config:            config,
+		notificationSvc:   notificationSvc,
+		updateSvc:         updateSvc,
+		downloadClientSvc: downloadClientSvc,
 		cron: cron.New(cron.WithChain(
 			cron.Recover(cron.DefaultLogger),
 		)),
@@ -80,6 +83,27 @@ func (s *service) addAppJobs() {
 			s.log.Error().Err(err).Msgf("scheduler.addAppJobs: error adding job: %v", id)
 		}
 	}
+
+	retryNotifications := &RetryNotificationsJob{
+		Name:     "app-retry-notifications",
+		Log:      s.log.With().Str("job", "app-retry-notifications").Logger(),
+		NotifSvc: s.notificationSvc,
+	}
+
+	if id, err := s.ScheduleJob(retryNotifications, time.Minute, "app-retry-notifications"); err != nil {
+		s.log.Error().Err(err).Msgf("scheduler.addAppJobs: error adding job: %v", id)
+	}
+
+	downloadClientHealth := &DownloadClientHealthJob{
+		Name:              "app-download-client-health",
+		Log:               s.log.With().Str("job", "app-download-client-health").Logger(),
+		NotifSvc:          s.notificationSvc,
+		DownloadClientSvc: s.downloadClientSvc,
+	}
+
+	if id, err := s.ScheduleJob(downloadClientHealth, 10*time.Minute, "app-download-client-health"); err != nil {
+		s.log.Error().Err(err).Msgf("scheduler.addAppJobs: error adding job: %v", id)
+	}
 }
 
 func (s *service) Stop() {
@@ -10,9 +10,14 @@ import (
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/feed"
+	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/irc"
+	"github.com/autobrr/autobrr/internal/list"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/maintenance"
+	"github.com/autobrr/autobrr/internal/mockindexer"
+	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/internal/update"
 
@@ -23,25 +28,35 @@ type Server struct {
 	log    zerolog.Logger
 	config *domain.Config
 
-	indexerService indexer.Service
-	ircService     irc.Service
-	feedService    feed.Service
-	scheduler      scheduler.Service
-	updateService  *update.Service
+	indexerService     indexer.Service
+	ircService         irc.Service
+	feedService        feed.Service
+	filterService      filter.Service
+	listService        list.Service
+	releaseService     release.Service
+	maintenanceService maintenance.Service
+	mockIndexerService mockindexer.Service
+	scheduler          scheduler.Service
+	updateService      *update.Service
 
 	stopWG sync.WaitGroup
 	lock   sync.Mutex
 }
 
-func NewServer(log logger.Logger, config *domain.Config, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
+func NewServer(log logger.Logger, config *domain.Config, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, filterSvc filter.Service, listSvc list.Service, releaseSvc release.Service, maintenanceSvc maintenance.Service, mockIndexerSvc mockindexer.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
 	return &Server{
-		log:            log.With().Str("module", "server").Logger(),
-		config:         config,
-		indexerService: indexerSvc,
-		ircService:     ircSvc,
-		feedService:    feedSvc,
-		scheduler:      scheduler,
-		updateService:  updateSvc,
+		log:                log.With().Str("module", "server").Logger(),
+		config:             config,
+		indexerService:     indexerSvc,
+		ircService:         ircSvc,
+		feedService:        feedSvc,
+		filterService:      filterSvc,
+		listService:        listSvc,
+		releaseService:     releaseSvc,
+		maintenanceService: maintenanceSvc,
+		mockIndexerService: mockIndexerSvc,
+		scheduler:          scheduler,
+		updateService:      updateSvc,
 	}
 }
 
@@ -65,6 +80,31 @@ func (s *Server) Start() error {
 		s.log.Error().Err(err).Msg("Could not start feed service")
 	}
 
+	// schedule purging of trashed filters
+	if err := s.filterService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start filter service")
+	}
+
+	// schedule syncing of lists into filters
+	if err := s.listService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start list service")
+	}
+
+	// start feed consistency check
+	if err := s.releaseService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start release service")
+	}
+
+	// schedule orphaned data cleanup
+	if err := s.maintenanceService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start maintenance service")
+	}
+
+	// generate announces for enabled mock indexers
+	if err := s.mockIndexerService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start mock indexer service")
+	}
+
 	return nil
 }
 
@@ -76,6 +116,14 @@ func (s *Server) Shutdown() {
 
 	// stop cron scheduler
 	s.scheduler.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// write the announce history before the database closes
+	if err := s.releaseService.Stop(ctx); err != nil {
+		s.log.Error().Err(err).Msg("Could not stop release service")
+	}
 }
 
 func (s *Server) checkUpdates() {
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package storage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// localBackend stores files in a directory on disk
+type localBackend struct {
+	root string
+}
+
+func newLocalBackend(cfg domain.StorageConfig, configPath string) (*localBackend, error) {
+	root := cfg.Path
+	if root == "" {
+		root = "storage"
+	}
+
+	if !filepath.IsAbs(root) {
+		root = filepath.Join(configPath, root)
+	}
+
+	return &localBackend{root: root}, nil
+}
+
+func (b *localBackend) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" {
+		return "", errors.New("invalid key: %q", key)
+	}
+
+	return filepath.Join(b.root, filepath.FromSlash(strings.TrimPrefix(clean, "/"))), nil
+}
+
+func (b *localBackend) Put(ctx context.Context, key string, r io.Reader) (*domain.StorageObject, error) {
+	dst, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return nil, errors.Wrap(err, "could not create dir for %s", key)
+	}
+
+	// write to a temp file first so a failed write never replaces an existing file
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create temp file for %s", key)
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return nil, errors.Wrap(err, "could not write %s", key)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, errors.Wrap(err, "could not write %s", key)
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return nil, errors.Wrap(err, "could not move %s into place", key)
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not stat %s", key)
+	}
+
+	return &domain.StorageObject{Key: key, Size: size, LastModified: info.ModTime()}, nil
+}
+
+func (b *localBackend) List(ctx context.Context, prefix string) ([]domain.StorageObject, error) {
+	objects := make([]domain.StorageObject, 0)
+
+	err := filepath.WalkDir(b.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.root, p)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, domain.StorageObject{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list %s", b.root)
+	}
+
+	return objects, nil
+}
+
+func (b *localBackend) Delete(ctx context.Context, key string) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Wrap(err, "could not delete %s", key)
+	}
+
+	return nil
+}
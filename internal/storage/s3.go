@@ -0,0 +1,327 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+const (
+	s3DefaultRegion = "us-east-1"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3EmptyHash     = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// s3Backend stores files in an S3 compatible bucket, requests are signed with AWS signature version 4
+type s3Backend struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	prefix    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+
+	client *http.Client
+	now    func() time.Time
+}
+
+func newS3Backend(cfg domain.StorageConfig) (*s3Backend, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 storage requires a bucket")
+	}
+
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("s3 storage requires an access key and secret key")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, errors.New("invalid s3 endpoint: %q", endpoint)
+	}
+
+	return &s3Backend{
+		endpoint:  u,
+		region:    region,
+		bucket:    cfg.Bucket,
+		prefix:    strings.Trim(cfg.Prefix, "/"),
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		pathStyle: cfg.PathStyle,
+		client: &http.Client{
+			Timeout:   time.Minute * 10,
+			Transport: sharedhttp.Transport,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// url returns the url of the bucket, or of the object when key is set
+func (b *s3Backend) url(key string) *url.URL {
+	u := *b.endpoint
+
+	objectPath := ""
+	if key != "" {
+		objectPath = "/" + path.Join(b.prefix, key)
+	}
+
+	if b.pathStyle {
+		u.Path = path.Join("/", u.Path, b.bucket) + objectPath
+	} else {
+		u.Host = b.bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + objectPath
+		if u.Path == "" {
+			u.Path = "/"
+		}
+	}
+
+	return &u
+}
+
+func (b *s3Backend) Put(ctx context.Context, key string, r io.Reader) (*domain.StorageObject, error) {
+	// S3 needs the size and hash of the payload up front, spool it to disk to not keep backups in memory
+	tmp, err := os.CreateTemp("", "autobrr-storage-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create temp file")
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not spool %s", key)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "could not spool %s", key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, b.url(key).String(), io.NopCloser(tmp))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+	req.ContentLength = size
+
+	if err := b.do(req, hex.EncodeToString(hash.Sum(nil)), nil); err != nil {
+		return nil, errors.Wrap(err, "could not upload %s", key)
+	}
+
+	return &domain.StorageObject{Key: key, Size: size, LastModified: b.now()}, nil
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (b *s3Backend) List(ctx context.Context, prefix string) ([]domain.StorageObject, error) {
+	fullPrefix := prefix
+	if b.prefix != "" {
+		fullPrefix = b.prefix + "/" + prefix
+	}
+
+	objects := make([]domain.StorageObject, 0)
+	token := ""
+
+	for {
+		u := b.url("")
+
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", fullPrefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not build request")
+		}
+
+		var result s3ListBucketResult
+		if err := b.do(req, s3EmptyHash, &result); err != nil {
+			return nil, errors.Wrap(err, "could not list %s", fullPrefix)
+		}
+
+		for _, content := range result.Contents {
+			key := content.Key
+			if b.prefix != "" {
+				key = strings.TrimPrefix(key, b.prefix+"/")
+			}
+
+			objects = append(objects, domain.StorageObject{Key: key, Size: content.Size, LastModified: content.LastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+
+		token = result.NextContinuationToken
+	}
+
+	return objects, nil
+}
+
+func (b *s3Backend) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, b.url(key).String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	return b.do(req, s3EmptyHash, nil)
+}
+
+// do signs and sends req, the xml response is decoded into v when set
+func (b *s3Backend) do(req *http.Request, payloadHash string, v any) error {
+	b.sign(req, payloadHash, b.now())
+
+	res, err := b.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.New("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if err := xml.NewDecoder(res.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "could not decode response")
+	}
+
+	return nil
+}
+
+// sign adds the AWS signature version 4 authorization to req, the headers set on req are signed
+func (b *s3Backend) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3URIEncode(req.URL.Path, false),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + b.region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hex.EncodeToString(canonicalHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+b.secretKey), date)
+	key = hmacSHA256(key, b.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s", s3Algorithm, b.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, s3URIEncode(k, true)+"="+s3URIEncode(v, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// s3URIEncode encodes everything but the unreserved characters, slashes are kept in paths
+func s3URIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
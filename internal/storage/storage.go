@@ -0,0 +1,112 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package storage
+
+import (
+	"context"
+	"io"
+	"path"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// Backend stores files by key, keys use / as separator
+type Backend interface {
+	Put(ctx context.Context, key string, r io.Reader) (*domain.StorageObject, error)
+	List(ctx context.Context, prefix string) ([]domain.StorageObject, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func NewBackend(cfg domain.StorageConfig, configPath string) (Backend, error) {
+	switch cfg.Type {
+	case domain.StorageTypeLocal, "":
+		return newLocalBackend(cfg, configPath)
+	case domain.StorageTypeS3:
+		return newS3Backend(cfg)
+	default:
+		return nil, errors.New("unsupported storage type: %q", cfg.Type)
+	}
+}
+
+type Service interface {
+	Store(ctx context.Context, artifact domain.StorageArtifact, name string, r io.Reader) (*domain.StorageObject, error)
+	List(ctx context.Context, artifact domain.StorageArtifact) ([]domain.StorageObject, error)
+	Prune(ctx context.Context, artifact domain.StorageArtifact) (int, error)
+	Config() domain.StorageConfig
+}
+
+type service struct {
+	log     zerolog.Logger
+	cfg     domain.StorageConfig
+	backend Backend
+}
+
+func NewService(log logger.Logger, config *domain.Config) (Service, error) {
+	backend, err := NewBackend(config.Storage, config.ConfigPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not setup storage")
+	}
+
+	return &service{
+		log:     log.With().Str("module", "storage").Logger(),
+		cfg:     config.Storage,
+		backend: backend,
+	}, nil
+}
+
+func (s *service) Config() domain.StorageConfig {
+	return s.cfg
+}
+
+// Store writes r as name for artifact and removes the files of artifact outside the retention
+func (s *service) Store(ctx context.Context, artifact domain.StorageArtifact, name string, r io.Reader) (*domain.StorageObject, error) {
+	key := path.Join(string(artifact), path.Base(name))
+
+	object, err := s.backend.Put(ctx, key, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not store %s", key)
+	}
+
+	s.log.Info().Msgf("stored %s to %s storage (%d bytes)", key, s.cfg.Type, object.Size)
+
+	if _, err := s.Prune(ctx, artifact); err != nil {
+		s.log.Error().Err(err).Msgf("could not apply retention for %s", artifact)
+	}
+
+	return object, nil
+}
+
+func (s *service) List(ctx context.Context, artifact domain.StorageArtifact) ([]domain.StorageObject, error) {
+	return s.backend.List(ctx, string(artifact)+"/")
+}
+
+// Prune removes the files of artifact outside the retention and returns how many were removed
+func (s *service) Prune(ctx context.Context, artifact domain.StorageArtifact) (int, error) {
+	retention := s.cfg.ArtifactRetention(artifact)
+	if retention.Keep == 0 && retention.MaxAgeDays == 0 {
+		return 0, nil
+	}
+
+	objects, err := s.List(ctx, artifact)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not list %s", artifact)
+	}
+
+	removed := 0
+	for _, object := range retention.Expired(objects, time.Now()) {
+		if err := s.backend.Delete(ctx, object.Key); err != nil {
+			return removed, errors.Wrap(err, "could not delete %s", object.Key)
+		}
+
+		s.log.Debug().Msgf("removed %s outside retention", object.Key)
+		removed++
+	}
+
+	return removed, nil
+}
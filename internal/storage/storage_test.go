@@ -0,0 +1,121 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Store(t *testing.T) {
+	dir := t.TempDir()
+
+	backend, err := newLocalBackend(domain.StorageConfig{Path: "storage"}, dir)
+	require.NoError(t, err)
+
+	s := &service{
+		log:     zerolog.Nop(),
+		cfg:     domain.StorageConfig{Type: domain.StorageTypeLocal, Keep: 2},
+		backend: backend,
+	}
+
+	ctx := context.Background()
+
+	for idx, name := range []string{"one.db", "two.db", "three.db"} {
+		object, err := s.Store(ctx, domain.StorageArtifactBackup, name, strings.NewReader(name))
+		require.NoError(t, err)
+		assert.Equal(t, "backups/"+name, object.Key)
+
+		// make sure the files are ordered by modification time
+		modified := time.Now().Add(time.Duration(idx-10) * time.Minute)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "storage", "backups", name), modified, modified))
+	}
+
+	objects, err := s.List(ctx, domain.StorageArtifactBackup)
+	require.NoError(t, err)
+
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	assert.ElementsMatch(t, []string{"backups/two.db", "backups/three.db"}, keys)
+
+	// other artifacts are not part of the backup retention
+	_, err = s.Store(ctx, domain.StorageArtifactExport, "releases.csv", strings.NewReader("id"))
+	require.NoError(t, err)
+
+	objects, err = s.List(ctx, domain.StorageArtifactBackup)
+	require.NoError(t, err)
+	assert.Len(t, objects, 2)
+}
+
+func TestLocalBackend_path(t *testing.T) {
+	b := &localBackend{root: "/data"}
+
+	p, err := b.path("../../etc/passwd")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/data", "etc", "passwd"), p)
+
+	_, err = b.path("/")
+	assert.Error(t, err)
+}
+
+func TestService_Prune_ArtifactRetention(t *testing.T) {
+	dir := t.TempDir()
+
+	backend, err := newLocalBackend(domain.StorageConfig{Path: "storage"}, dir)
+	require.NoError(t, err)
+
+	s := &service{
+		log: zerolog.Nop(),
+		cfg: domain.StorageConfig{
+			Type: domain.StorageTypeLocal,
+			Keep: 1,
+			Retention: map[string]domain.StorageRetention{
+				string(domain.StorageArtifactAnnounceLog): {MaxAgeDays: 7},
+			},
+		},
+		backend: backend,
+	}
+
+	ctx := context.Background()
+
+	put := func(artifact domain.StorageArtifact, name string, age time.Duration) {
+		_, err := backend.Put(ctx, string(artifact)+"/"+name, strings.NewReader(name))
+		require.NoError(t, err)
+
+		modified := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "storage", string(artifact), name), modified, modified))
+	}
+
+	put(domain.StorageArtifactAnnounceLog, "old.log", 10*24*time.Hour)
+	put(domain.StorageArtifactAnnounceLog, "one.log", 2*time.Hour)
+	put(domain.StorageArtifactAnnounceLog, "two.log", time.Hour)
+	put(domain.StorageArtifactBackup, "one.db", 2*time.Hour)
+	put(domain.StorageArtifactBackup, "two.db", time.Hour)
+
+	// the announce logs use their own retention instead of keep
+	removed, err := s.Prune(ctx, domain.StorageArtifactAnnounceLog)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	// the backups use the default retention
+	removed, err = s.Prune(ctx, domain.StorageArtifactBackup)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	objects, err := s.List(ctx, domain.StorageArtifactAnnounceLog)
+	require.NoError(t, err)
+	assert.Len(t, objects, 2)
+}
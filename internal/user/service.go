@@ -8,22 +8,31 @@ import (
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/asaskevich/EventBus"
 )
 
 type Service interface {
 	GetUserCount(ctx context.Context) (int, error)
+	List(ctx context.Context) ([]domain.User, error)
+	FindByID(ctx context.Context, id int) (*domain.User, error)
 	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+	FindByOIDCSubject(ctx context.Context, issuer, subject string) (*domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
 	Update(ctx context.Context, req domain.UpdateUserRequest) error
+	UpdateRole(ctx context.Context, id int, role domain.UserRole) error
+	Delete(ctx context.Context, id int) error
 }
 
 type service struct {
 	repo domain.UserRepo
+	bus  EventBus.Bus
 }
 
-func NewService(repo domain.UserRepo) Service {
+func NewService(repo domain.UserRepo, bus EventBus.Bus) Service {
 	return &service{
 		repo: repo,
+		bus:  bus,
 	}
 }
 
@@ -31,6 +40,14 @@ func (s *service) GetUserCount(ctx context.Context) (int, error) {
 	return s.repo.GetUserCount(ctx)
 }
 
+func (s *service) List(ctx context.Context) ([]domain.User, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *service) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
 func (s *service) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
 	user, err := s.repo.FindByUsername(ctx, username)
 	if err != nil {
@@ -40,14 +57,17 @@ func (s *service) FindByUsername(ctx context.Context, username string) (*domain.
 	return user, nil
 }
 
+func (s *service) FindByOIDCSubject(ctx context.Context, issuer, subject string) (*domain.User, error) {
+	return s.repo.FindByOIDCSubject(ctx, issuer, subject)
+}
+
 func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest) error {
-	userCount, err := s.repo.GetUserCount(ctx)
-	if err != nil {
-		return err
+	if req.Role != "" && !req.Role.Valid() {
+		return errors.New("invalid role: %s", req.Role)
 	}
 
-	if userCount > 0 {
-		return errors.New("only 1 user account is supported at the moment")
+	if _, err := s.repo.FindByUsername(ctx, req.Username); err == nil {
+		return errors.New("user %s already exists", req.Username)
 	}
 
 	return s.repo.Store(ctx, req)
@@ -56,3 +76,58 @@ func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest)
 func (s *service) Update(ctx context.Context, req domain.UpdateUserRequest) error {
 	return s.repo.Update(ctx, req)
 }
+
+// UpdateRole changes the role of the user, the last admin can't be demoted
+func (s *service) UpdateRole(ctx context.Context, id int, role domain.UserRole) error {
+	if !role.Valid() {
+		return errors.New("invalid role: %s", role)
+	}
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == domain.UserRoleAdmin && role != domain.UserRoleAdmin {
+		if err := s.ensureOtherAdmin(ctx); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.UpdateRole(ctx, id, role)
+}
+
+// Delete removes the user with their api keys and notification preferences, the last admin can't be removed
+func (s *service) Delete(ctx context.Context, id int) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == domain.UserRoleAdmin {
+		if err := s.ensureOtherAdmin(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Delete(ctx, user.Username); err != nil {
+		return err
+	}
+
+	s.bus.Publish("user:deleted", user.ID)
+
+	return nil
+}
+
+func (s *service) ensureOtherAdmin(ctx context.Context) error {
+	admins, err := s.repo.GetAdminCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	if admins <= 1 {
+		return errors.New("at least one admin is required")
+	}
+
+	return nil
+}
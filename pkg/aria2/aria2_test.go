@@ -0,0 +1,77 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package aria2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rpcRequest struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func newMockServer(t *testing.T, result string, requests *[]rpcRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jsonrpc", r.URL.Path)
+
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*requests = append(*requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":0,"result":` + result + `}`))
+	}))
+}
+
+func TestClient_AddTorrent(t *testing.T) {
+	var requests []rpcRequest
+	ts := newMockServer(t, `"2089b05ecca3d829"`, &requests)
+	defer ts.Close()
+
+	c := NewClient(Config{Hostname: ts.URL, Secret: "secret"})
+
+	gid, err := c.AddTorrent(context.Background(), []byte("torrent"), &AddOptions{Dir: "/downloads/tv", MaxDownloadLimit: "1000K", Pause: "true"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2089b05ecca3d829", gid)
+
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, "aria2.addTorrent", requests[0].Method)
+		if assert.Len(t, requests[0].Params, 4) {
+			assert.JSONEq(t, `"token:secret"`, string(requests[0].Params[0]))
+			assert.JSONEq(t, `"dG9ycmVudA=="`, string(requests[0].Params[1]))
+			assert.JSONEq(t, `[]`, string(requests[0].Params[2]))
+			assert.JSONEq(t, `{"dir":"/downloads/tv","max-download-limit":"1000K","pause":"true"}`, string(requests[0].Params[3]))
+		}
+	}
+}
+
+func TestClient_TellActive(t *testing.T) {
+	var requests []rpcRequest
+	ts := newMockServer(t, `[{"gid":"2089b05ecca3d829","status":"active"}]`, &requests)
+	defer ts.Close()
+
+	// without a secret the params are still sent as an array, and /jsonrpc is not added twice
+	c := NewClient(Config{Hostname: ts.URL + "/jsonrpc"})
+
+	active, err := c.TellActive(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Status{{GID: "2089b05ecca3d829", Status: "active"}}, active)
+
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, "aria2.tellActive", requests[0].Method)
+		if assert.Len(t, requests[0].Params, 1) {
+			assert.JSONEq(t, `["gid","status"]`, string(requests[0].Params[0]))
+		}
+	}
+}
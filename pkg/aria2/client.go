@@ -0,0 +1,99 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package aria2
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/jsonrpc"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+var (
+	DefaultTimeout = 60 * time.Second
+)
+
+type Client struct {
+	cfg       Config
+	rpcClient jsonrpc.Client
+	http      *http.Client
+	timeout   time.Duration
+
+	log *log.Logger
+}
+
+type Config struct {
+	// Hostname of the rpc interface, /jsonrpc is added when missing
+	Hostname string
+
+	// Secret is the rpc-secret of aria2
+	Secret string
+
+	// TLS skip cert validation
+	TLSSkipVerify bool
+
+	// HTTP Basic auth username
+	BasicUser string
+
+	// HTTP Basic auth password
+	BasicPass string
+
+	Timeout int
+	Log     *log.Logger
+}
+
+func NewClient(cfg Config) *Client {
+	c := &Client{
+		cfg:     cfg,
+		log:     log.New(io.Discard, "", log.LstdFlags),
+		timeout: DefaultTimeout,
+	}
+
+	// override logger if we pass one
+	if cfg.Log != nil {
+		c.log = cfg.Log
+	}
+
+	if cfg.Timeout > 0 {
+		c.timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	httpClient := &http.Client{
+		Timeout:   c.timeout,
+		Transport: sharedhttp.Transport,
+	}
+
+	if cfg.TLSSkipVerify {
+		httpClient.Transport = sharedhttp.TransportTLSInsecure
+	}
+
+	c.http = httpClient
+
+	endpoint := strings.TrimSuffix(cfg.Hostname, "/")
+	if !strings.HasSuffix(endpoint, "/jsonrpc") {
+		endpoint += "/jsonrpc"
+	}
+
+	c.rpcClient = jsonrpc.NewClientWithOpts(endpoint, &jsonrpc.ClientOpts{
+		HTTPClient: httpClient,
+		BasicUser:  cfg.BasicUser,
+		BasicPass:  cfg.BasicPass,
+	})
+
+	return c
+}
+
+// params prepends the secret token aria2 expects as the first param of every call.
+// The params are passed to the rpc client as a single slice so they are always sent as an array.
+func (c *Client) params(params ...interface{}) []interface{} {
+	if c.cfg.Secret == "" {
+		return append([]interface{}{}, params...)
+	}
+
+	return append([]interface{}{"token:" + c.cfg.Secret}, params...)
+}
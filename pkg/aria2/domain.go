@@ -0,0 +1,26 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package aria2
+
+type Version struct {
+	Version         string   `json:"version"`
+	EnabledFeatures []string `json:"enabledFeatures"`
+}
+
+// AddOptions are the aria2 input file options for a new download, aria2 expects all values as strings
+type AddOptions struct {
+	Dir string `json:"dir,omitempty"`
+
+	// MaxDownloadLimit and MaxUploadLimit are the speed limits in bytes, K and M suffixes are supported
+	MaxDownloadLimit string `json:"max-download-limit,omitempty"`
+	MaxUploadLimit   string `json:"max-upload-limit,omitempty"`
+
+	// Pause adds the download paused, "true" or "false"
+	Pause string `json:"pause,omitempty"`
+}
+
+type Status struct {
+	GID    string `json:"gid"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package aria2
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+func (c *Client) Version(ctx context.Context) (*Version, error) {
+	response, err := c.rpcClient.CallCtx(ctx, "aria2.getVersion", c.params())
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	var version *Version
+	if err = response.GetObject(&version); err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// AddUri adds a download from uris pointing to the same resource, eg. a magnet link, and returns its gid
+func (c *Client) AddUri(ctx context.Context, uris []string, opts *AddOptions) (string, error) {
+	response, err := c.rpcClient.CallCtx(ctx, "aria2.addUri", c.params(uris, opts))
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != nil {
+		return "", response.Error
+	}
+
+	var gid string
+	if err = response.GetObject(&gid); err != nil {
+		return "", err
+	}
+
+	return gid, nil
+}
+
+// AddTorrent adds a download from the content of a torrent file and returns its gid
+func (c *Client) AddTorrent(ctx context.Context, torrent []byte, opts *AddOptions) (string, error) {
+	response, err := c.rpcClient.CallCtx(ctx, "aria2.addTorrent", c.params(base64.StdEncoding.EncodeToString(torrent), []string{}, opts))
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != nil {
+		return "", response.Error
+	}
+
+	var gid string
+	if err = response.GetObject(&gid); err != nil {
+		return "", err
+	}
+
+	return gid, nil
+}
+
+// TellActive returns the active downloads
+func (c *Client) TellActive(ctx context.Context) ([]Status, error) {
+	response, err := c.rpcClient.CallCtx(ctx, "aria2.tellActive", c.params([]string{"gid", "status"}))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	var active []Status
+	if err = response.GetObject(&active); err != nil {
+		return nil, err
+	}
+
+	return active, nil
+}
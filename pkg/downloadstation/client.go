@@ -0,0 +1,252 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package downloadstation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+var (
+	DefaultTimeout = 60 * time.Second
+)
+
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	timeout time.Duration
+
+	log *log.Logger
+
+	mu  sync.Mutex
+	sid string
+}
+
+type Config struct {
+	// Host of the DSM web interface, eg. https://nas.local:5001
+	Host     string
+	Username string
+	Password string
+
+	// TLS skip cert validation
+	TLSSkipVerify bool
+
+	// HTTP Basic auth username
+	BasicUser string
+
+	// HTTP Basic auth password
+	BasicPass string
+
+	Timeout int
+	Log     *log.Logger
+}
+
+func NewClient(cfg Config) *Client {
+	c := &Client{
+		cfg:     cfg,
+		log:     log.New(io.Discard, "", log.LstdFlags),
+		timeout: DefaultTimeout,
+	}
+
+	// override logger if we pass one
+	if cfg.Log != nil {
+		c.log = cfg.Log
+	}
+
+	if cfg.Timeout > 0 {
+		c.timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	c.http = &http.Client{
+		Timeout:   c.timeout,
+		Transport: sharedhttp.Transport,
+	}
+
+	if cfg.TLSSkipVerify {
+		c.http.Transport = sharedhttp.TransportTLSInsecure
+	}
+
+	return c
+}
+
+// uploadFile is sent as the multipart file of a call
+type uploadFile struct {
+	field   string
+	name    string
+	content []byte
+}
+
+type response struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// Login starts a new Download Station session
+func (c *Client) Login(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.login(ctx)
+}
+
+func (c *Client) login(ctx context.Context) error {
+	params := url.Values{}
+	params.Set("api", apiAuth)
+	params.Set("version", "3")
+	params.Set("method", "login")
+	params.Set("account", c.cfg.Username)
+	params.Set("passwd", c.cfg.Password)
+	params.Set("session", "DownloadStation")
+	params.Set("format", "sid")
+
+	var res struct {
+		SID string `json:"sid"`
+	}
+
+	if err := c.call(ctx, "auth.cgi", params, nil, &res); err != nil {
+		return errors.Wrap(err, "could not login")
+	}
+
+	c.sid = res.SID
+
+	return nil
+}
+
+// do runs the api call with the session, it logs in first and once more when the session expired
+func (c *Client) do(ctx context.Context, params url.Values, file *uploadFile, result any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sid == "" {
+		if err := c.login(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := c.call(ctx, "entry.cgi", c.withSession(params), file, result)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.SessionExpired() {
+		c.log.Printf("download station session expired, logging in again")
+
+		if err := c.login(ctx); err != nil {
+			return err
+		}
+
+		return c.call(ctx, "entry.cgi", c.withSession(params), file, result)
+	}
+
+	return err
+}
+
+func (c *Client) withSession(params url.Values) url.Values {
+	v := url.Values{}
+	for key, values := range params {
+		v[key] = values
+	}
+	v.Set("_sid", c.sid)
+
+	return v
+}
+
+func (c *Client) call(ctx context.Context, path string, params url.Values, file *uploadFile, result any) error {
+	endpoint, err := url.JoinPath(c.cfg.Host, "/webapi", path)
+	if err != nil {
+		return errors.Wrap(err, "could not build url")
+	}
+
+	var body io.Reader
+	contentType := "application/x-www-form-urlencoded"
+
+	if file != nil {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+
+		// the file must be the last part, Download Station ignores the fields after it
+		for key := range params {
+			if err := w.WriteField(key, params.Get(key)); err != nil {
+				return errors.Wrap(err, "could not write field %s", key)
+			}
+		}
+
+		part, err := w.CreateFormFile(file.field, file.name)
+		if err != nil {
+			return errors.Wrap(err, "could not create file part")
+		}
+
+		if _, err := part.Write(file.content); err != nil {
+			return errors.Wrap(err, "could not write file part")
+		}
+
+		if err := w.Close(); err != nil {
+			return errors.Wrap(err, "could not close multipart writer")
+		}
+
+		body = &buf
+		contentType = w.FormDataContentType()
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	if c.cfg.BasicUser != "" && c.cfg.BasicPass != "" {
+		req.SetBasicAuth(c.cfg.BasicUser, c.cfg.BasicPass)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not make request")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("unexpected status: %d", res.StatusCode)
+	}
+
+	var data response
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return errors.Wrap(err, "could not unmarshal body")
+	}
+
+	if !data.Success {
+		apiErr := &Error{API: params.Get("api")}
+		if data.Error != nil {
+			apiErr.Code = data.Error.Code
+		}
+
+		return apiErr
+	}
+
+	if result == nil || len(data.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data.Data, result); err != nil {
+		return errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return nil
+}
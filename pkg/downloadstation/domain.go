@@ -0,0 +1,113 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package downloadstation
+
+import (
+	"fmt"
+)
+
+const (
+	apiAuth      = "SYNO.API.Auth"
+	apiInfo      = "SYNO.DownloadStation.Info"
+	apiTask      = "SYNO.DownloadStation.Task"
+	apiTaskV2    = "SYNO.DownloadStation2.Task"
+	apiTaskBTV2  = "SYNO.DownloadStation2.Task.BT"
+	statusActive = "downloading"
+)
+
+type Info struct {
+	IsManager     bool   `json:"is_manager"`
+	Version       int    `json:"version"`
+	VersionString string `json:"version_string"`
+}
+
+type Task struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Size   int64  `json:"size"`
+	Status string `json:"status"`
+}
+
+func (t Task) Active() bool {
+	return t.Status == statusActive
+}
+
+type AddOptions struct {
+	// Destination is the shared folder and directory, without a leading slash, eg. downloads/tv
+	Destination string
+}
+
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+)
+
+// Error is returned when the api reports a call as failed
+type Error struct {
+	API  string
+	Code int
+}
+
+var commonErrors = map[int]string{
+	100: "unknown error",
+	101: "invalid parameter",
+	102: "the requested api does not exist",
+	103: "the requested method does not exist",
+	104: "the requested version does not support the functionality",
+	105: "the logged in session does not have permission",
+	106: "session timeout",
+	107: "session interrupted by duplicate login",
+	119: "session id not found",
+}
+
+var authErrors = map[int]string{
+	400: "no such account or incorrect password",
+	401: "account disabled",
+	402: "permission denied",
+	403: "2-step verification code required",
+	404: "failed to authenticate 2-step verification code",
+}
+
+var taskErrors = map[int]string{
+	400: "file upload failed",
+	401: "max number of tasks reached",
+	402: "destination denied",
+	403: "destination does not exist",
+	404: "invalid task id",
+	405: "invalid task action",
+	406: "no default destination",
+	407: "set destination failed",
+	408: "file does not exist",
+}
+
+func (e *Error) Error() string {
+	msg, ok := commonErrors[e.Code]
+	if !ok {
+		switch e.API {
+		case apiAuth:
+			msg, ok = authErrors[e.Code]
+		case apiTask, apiTaskV2, apiTaskBTV2:
+			msg, ok = taskErrors[e.Code]
+		}
+	}
+
+	if !ok {
+		msg = "unknown error"
+	}
+
+	return fmt.Sprintf("%s: error code %d: %s", e.API, e.Code, msg)
+}
+
+// SessionExpired reports if the call can be retried after logging in again
+func (e *Error) SessionExpired() bool {
+	switch e.Code {
+	case 106, 107, 119:
+		return true
+	}
+	return false
+}
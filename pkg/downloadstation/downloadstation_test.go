@@ -0,0 +1,75 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package downloadstation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_AddTorrent(t *testing.T) {
+	logins := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webapi/auth.cgi", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "login", r.PostForm.Get("method"))
+
+		if r.PostForm.Get("passwd") != "password" {
+			w.Write([]byte(`{"success":false,"error":{"code":400}}`))
+			return
+		}
+
+		logins++
+		fmt.Fprintf(w, `{"success":true,"data":{"sid":"sid-%d"}}`, logins)
+	})
+	mux.HandleFunc("/webapi/entry.cgi", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+
+		// the first session expires to check the client logs in again
+		if r.FormValue("_sid") != "sid-2" {
+			w.Write([]byte(`{"success":false,"error":{"code":106}}`))
+			return
+		}
+
+		assert.Equal(t, "SYNO.DownloadStation2.Task", r.FormValue("api"))
+		assert.Equal(t, "create", r.FormValue("method"))
+		assert.Equal(t, `"file"`, r.FormValue("type"))
+		assert.Equal(t, `"downloads/tv"`, r.FormValue("destination"))
+
+		file, header, err := r.FormFile("torrent")
+		if assert.NoError(t, err) {
+			content, _ := io.ReadAll(file)
+			assert.Equal(t, "torrent", string(content))
+			assert.Equal(t, "release.torrent", header.Filename)
+		}
+
+		w.Write([]byte(`{"success":true,"data":{"list_id":[],"task_id":["dbid_100"]}}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	t.Run("add", func(t *testing.T) {
+		c := NewClient(Config{Host: ts.URL, Username: "admin", Password: "password"})
+
+		ids, err := c.AddTorrent(context.Background(), "release.torrent", []byte("torrent"), AddOptions{Destination: "downloads/tv"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"dbid_100"}, ids)
+		assert.Equal(t, 2, logins)
+	})
+
+	t.Run("wrong_password", func(t *testing.T) {
+		c := NewClient(Config{Host: ts.URL, Username: "admin", Password: "wrong"})
+
+		_, err := c.AddTorrent(context.Background(), "release.torrent", []byte("torrent"), AddOptions{})
+		assert.ErrorContains(t, err, "no such account or incorrect password")
+	})
+}
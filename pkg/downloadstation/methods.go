@@ -0,0 +1,105 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package downloadstation
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// Info returns the Download Station version, it's used to test the connection
+func (c *Client) Info(ctx context.Context) (*Info, error) {
+	params := url.Values{}
+	params.Set("api", apiInfo)
+	params.Set("version", "1")
+	params.Set("method", "getinfo")
+
+	var info Info
+	if err := c.do(ctx, params, nil, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// ListTasks returns all tasks
+func (c *Client) ListTasks(ctx context.Context) ([]Task, error) {
+	params := url.Values{}
+	params.Set("api", apiTask)
+	params.Set("version", "1")
+	params.Set("method", "list")
+
+	var res struct {
+		Total int    `json:"total"`
+		Tasks []Task `json:"tasks"`
+	}
+
+	if err := c.do(ctx, params, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res.Tasks, nil
+}
+
+// AddURL creates a task from an url or magnet link and returns the ids of the created tasks
+func (c *Client) AddURL(ctx context.Context, uri string, opts AddOptions) ([]string, error) {
+	params := createParams("url", opts)
+	params.Set("url", jsonValue([]string{uri}))
+
+	return c.create(ctx, params, nil)
+}
+
+// AddTorrent creates a task from the content of a torrent file and returns the ids of the created tasks
+func (c *Client) AddTorrent(ctx context.Context, name string, torrent []byte, opts AddOptions) ([]string, error) {
+	params := createParams("file", opts)
+	params.Set("file", jsonValue([]string{"torrent"}))
+
+	return c.create(ctx, params, &uploadFile{field: "torrent", name: name, content: torrent})
+}
+
+// SetPriority sets the bandwidth priority of bt tasks
+func (c *Client) SetPriority(ctx context.Context, ids []string, priority Priority) error {
+	params := url.Values{}
+	params.Set("api", apiTaskBTV2)
+	params.Set("version", "2")
+	params.Set("method", "set")
+	params.Set("task_id", jsonValue(ids))
+	params.Set("priority", jsonValue(priority))
+
+	return c.do(ctx, params, nil, nil)
+}
+
+func (c *Client) create(ctx context.Context, params url.Values, file *uploadFile) ([]string, error) {
+	var res struct {
+		TaskID []string `json:"task_id"`
+	}
+
+	if err := c.do(ctx, params, file, &res); err != nil {
+		return nil, err
+	}
+
+	return res.TaskID, nil
+}
+
+// createParams are the params of the v2 task api, which expects the values json encoded
+func createParams(taskType string, opts AddOptions) url.Values {
+	params := url.Values{}
+	params.Set("api", apiTaskV2)
+	params.Set("version", "2")
+	params.Set("method", "create")
+	params.Set("type", jsonValue(taskType))
+	params.Set("create_list", "false")
+
+	if opts.Destination != "" {
+		params.Set("destination", jsonValue(opts.Destination))
+	}
+
+	return params
+}
+
+func jsonValue(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package jellyfin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+type Config struct {
+	Hostname string
+	APIKey   string
+
+	Log *log.Logger
+}
+
+type Client interface {
+	Test(ctx context.Context) (*SystemInfoResponse, error)
+	SearchItems(ctx context.Context, query string, itemType string) ([]Item, error)
+	GetEpisodes(ctx context.Context, seriesID string, season int) ([]Item, error)
+}
+
+type client struct {
+	config Config
+	http   *http.Client
+
+	Log *log.Logger
+}
+
+// New create new jellyfin client
+func New(config Config) Client {
+	httpClient := &http.Client{
+		Timeout:   time.Second * 120,
+		Transport: sharedhttp.Transport,
+	}
+
+	c := &client{
+		config: config,
+		http:   httpClient,
+		Log:    log.New(io.Discard, "", log.LstdFlags),
+	}
+
+	if config.Log != nil {
+		c.Log = config.Log
+	}
+
+	return c
+}
+
+type SystemInfoResponse struct {
+	ServerName string `json:"ServerName"`
+	Version    string `json:"Version"`
+}
+
+type MediaStream struct {
+	Type   string `json:"Type"`
+	Width  int    `json:"Width"`
+	Height int    `json:"Height"`
+}
+
+type MediaSource struct {
+	MediaStreams []MediaStream `json:"MediaStreams"`
+}
+
+// Item is a movie, series or episode of the library
+type Item struct {
+	ID                string        `json:"Id"`
+	Name              string        `json:"Name"`
+	Type              string        `json:"Type"`
+	ProductionYear    int           `json:"ProductionYear"`
+	IndexNumber       int           `json:"IndexNumber"`       // episode number
+	ParentIndexNumber int           `json:"ParentIndexNumber"` // season of an episode
+	MediaSources      []MediaSource `json:"MediaSources"`
+}
+
+// VideoSize returns the width and height of the largest video stream of the item
+func (i Item) VideoSize() (width int, height int) {
+	for _, source := range i.MediaSources {
+		for _, stream := range source.MediaStreams {
+			if stream.Type == "Video" && stream.Width*stream.Height > width*height {
+				width, height = stream.Width, stream.Height
+			}
+		}
+	}
+
+	return width, height
+}
+
+type itemsResponse struct {
+	Items []Item `json:"Items"`
+}
+
+func (c *client) get(ctx context.Context, endpoint string, params url.Values, v any) error {
+	u, err := url.Parse(c.config.Hostname)
+	if err != nil {
+		return errors.Wrap(err, "could not parse url: %s", c.config.Hostname)
+	}
+
+	u.Path = path.Join(u.Path, endpoint)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("X-Emby-Token", c.config.APIKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "jellyfin.http.Do(req): %s", endpoint)
+	}
+
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, resp.Body); err != nil {
+		return errors.Wrap(err, "jellyfin.io.Copy")
+	}
+
+	c.Log.Printf("jellyfin %s status: (%v) response: %v\n", endpoint, resp.StatusCode, buf.String())
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("unauthorized: bad api key")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("jellyfin %s got unexpected status code: %d", endpoint, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return nil
+}
+
+func (c *client) Test(ctx context.Context) (*SystemInfoResponse, error) {
+	var response SystemInfoResponse
+	if err := c.get(ctx, "System/Info", nil, &response); err != nil {
+		return nil, errors.Wrap(err, "could not make Test")
+	}
+
+	return &response, nil
+}
+
+// SearchItems returns the items of the type, eg. Movie or Series, of all libraries matching the query
+func (c *client) SearchItems(ctx context.Context, query string, itemType string) ([]Item, error) {
+	params := url.Values{}
+	params.Set("searchTerm", query)
+	params.Set("IncludeItemTypes", itemType)
+	params.Set("Recursive", "true")
+	params.Set("Fields", "MediaSources")
+
+	var response itemsResponse
+	if err := c.get(ctx, "Items", params, &response); err != nil {
+		return nil, errors.Wrap(err, "could not search: %s", query)
+	}
+
+	return response.Items, nil
+}
+
+// GetEpisodes returns the episodes of the season of the series
+func (c *client) GetEpisodes(ctx context.Context, seriesID string, season int) ([]Item, error) {
+	params := url.Values{}
+	params.Set("season", strconv.Itoa(season))
+	params.Set("Fields", "MediaSources")
+
+	var response itemsResponse
+	if err := c.get(ctx, path.Join("Shows", seriesID, "Episodes"), params, &response); err != nil {
+		return nil, errors.Wrap(err, "could not get episodes of: %s", seriesID)
+	}
+
+	return response.Items, nil
+}
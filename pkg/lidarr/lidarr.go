@@ -10,6 +10,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,6 +34,9 @@ type Config struct {
 type Client interface {
 	Test(ctx context.Context) (*SystemStatusResponse, error)
 	Push(ctx context.Context, release Release) ([]string, error)
+	WantedMissing(ctx context.Context) ([]Album, error)
+	GetArtists(ctx context.Context) ([]Artist, error)
+	GetTags(ctx context.Context) ([]Tag, error)
 }
 
 type client struct {
@@ -156,3 +161,122 @@ func (c *client) Push(ctx context.Context, release Release) ([]string, error) {
 
 	return nil, nil
 }
+
+// wantedMissingPageSize is the number of albums fetched per request
+const wantedMissingPageSize = 250
+
+type Artist struct {
+	ID         int64   `json:"id"`
+	ArtistName string  `json:"artistName"`
+	Monitored  bool    `json:"monitored"`
+	Tags       []int64 `json:"tags,omitempty"`
+}
+
+type Album struct {
+	ID       int64   `json:"id"`
+	Title    string  `json:"title"`
+	ArtistID int64   `json:"artistId"`
+	Artist   *Artist `json:"artist,omitempty"`
+}
+
+type wantedMissingResponse struct {
+	Page         int     `json:"page"`
+	PageSize     int     `json:"pageSize"`
+	TotalRecords int     `json:"totalRecords"`
+	Records      []Album `json:"records"`
+}
+
+// WantedMissing returns the monitored albums lidarr is missing, with their artist
+func (c *client) WantedMissing(ctx context.Context) ([]Album, error) {
+	var albums []Album
+
+	for page := 1; ; page++ {
+		params := url.Values{
+			"page":          {strconv.Itoa(page)},
+			"pageSize":      {strconv.Itoa(wantedMissingPageSize)},
+			"monitored":     {"true"},
+			"includeArtist": {"true"},
+		}
+
+		status, res, err := c.getWithParams(ctx, "wanted/missing", params)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get wanted albums")
+		}
+
+		if status == http.StatusUnauthorized {
+			return nil, errors.New("unauthorized: bad credentials")
+		}
+
+		if status != http.StatusOK {
+			return nil, errors.New("lidarr wanted/missing got unexpected status code: %d", status)
+		}
+
+		var response wantedMissingResponse
+		if err = json.Unmarshal(res, &response); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal data")
+		}
+
+		albums = append(albums, response.Records...)
+
+		if len(response.Records) == 0 || len(albums) >= response.TotalRecords {
+			break
+		}
+	}
+
+	c.Log.Printf("lidarr wanted/missing got %d albums\n", len(albums))
+
+	return albums, nil
+}
+
+// GetArtists returns all artists
+func (c *client) GetArtists(ctx context.Context) ([]Artist, error) {
+	status, res, err := c.get(ctx, "artist")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get artists")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("lidarr artist got unexpected status code: %d", status)
+	}
+
+	var response []Artist
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	c.Log.Printf("lidarr artist got %d artists\n", len(response))
+
+	return response, nil
+}
+
+type Tag struct {
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+}
+
+// GetTags returns the tags, items only reference them by id
+func (c *client) GetTags(ctx context.Context) ([]Tag, error) {
+	status, res, err := c.get(ctx, "tag")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tags")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("lidarr tag got unexpected status code: %d", status)
+	}
+
+	var response []Tag
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return response, nil
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package musicbrainz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultHostname is the musicbrainz web service, other hostnames are for tests and mirrors
+const DefaultHostname = "https://musicbrainz.org"
+
+// userAgent identifies the application, musicbrainz blocks requests without a meaningful one
+const userAgent = "autobrr ( https://github.com/autobrr/autobrr )"
+
+type Config struct {
+	Hostname string
+
+	Log *log.Logger
+}
+
+type Client interface {
+	SearchReleaseGroups(ctx context.Context, artist string, title string) ([]ReleaseGroup, error)
+	SearchArtists(ctx context.Context, name string) ([]Artist, error)
+}
+
+type client struct {
+	config      Config
+	http        *http.Client
+	rateLimiter *rate.Limiter
+
+	Log *log.Logger
+}
+
+// New create new musicbrainz client
+func New(config Config) Client {
+	httpClient := &http.Client{
+		Timeout:   time.Second * 30,
+		Transport: sharedhttp.Transport,
+	}
+
+	if config.Hostname == "" {
+		config.Hostname = DefaultHostname
+	}
+
+	c := &client{
+		config:      config,
+		http:        httpClient,
+		rateLimiter: rate.NewLimiter(rate.Every(time.Second), 1), // musicbrainz allows 1 request per second
+		Log:         log.New(io.Discard, "", log.LstdFlags),
+	}
+
+	if config.Log != nil {
+		c.Log = config.Log
+	}
+
+	return c
+}
+
+type Artist struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Score int    `json:"score"` // search relevance from 0 to 100
+}
+
+type ArtistCredit struct {
+	Name   string `json:"name"`
+	Artist Artist `json:"artist"`
+}
+
+type ReleaseGroup struct {
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	Score        int            `json:"score"` // search relevance from 0 to 100
+	ArtistCredit []ArtistCredit `json:"artist-credit"`
+}
+
+type releaseGroupsResponse struct {
+	ReleaseGroups []ReleaseGroup `json:"release-groups"`
+}
+
+type artistsResponse struct {
+	Artists []Artist `json:"artists"`
+}
+
+func (c *client) get(ctx context.Context, endpoint string, params url.Values, v any) error {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return errors.Wrap(err, "error waiting for ratelimiter")
+	}
+
+	u, err := url.Parse(c.config.Hostname)
+	if err != nil {
+		return errors.Wrap(err, "could not parse url: %s", c.config.Hostname)
+	}
+
+	params.Set("fmt", "json")
+
+	u.Path = path.Join(u.Path, "/ws/2", endpoint)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "musicbrainz.http.Do(req): %s", endpoint)
+	}
+
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, resp.Body); err != nil {
+		return errors.Wrap(err, "musicbrainz.io.Copy")
+	}
+
+	c.Log.Printf("musicbrainz %s status: (%v) response: %v\n", endpoint, resp.StatusCode, buf.String())
+
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return errors.New("musicbrainz %s rate limited", endpoint)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("musicbrainz %s got unexpected status code: %d", endpoint, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return nil
+}
+
+// quote escapes the value for a lucene phrase query
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// SearchReleaseGroups returns the albums of the artist matching the title, best match first
+func (c *client) SearchReleaseGroups(ctx context.Context, artist string, title string) ([]ReleaseGroup, error) {
+	params := url.Values{
+		"query": {fmt.Sprintf("releasegroup:%s AND artist:%s", quote(title), quote(artist))},
+		"limit": {"5"},
+	}
+
+	var response releaseGroupsResponse
+	if err := c.get(ctx, "release-group", params, &response); err != nil {
+		return nil, errors.Wrap(err, "could not search release group: %s - %s", artist, title)
+	}
+
+	return response.ReleaseGroups, nil
+}
+
+// SearchArtists returns the artists matching the name, best match first
+func (c *client) SearchArtists(ctx context.Context, name string) ([]Artist, error) {
+	params := url.Values{
+		"query": {fmt.Sprintf("artist:%s", quote(name))},
+		"limit": {"5"},
+	}
+
+	var response artistsResponse
+	if err := c.get(ctx, "artist", params, &response); err != nil {
+		return nil, errors.Wrap(err, "could not search artist: %s", name)
+	}
+
+	return response.Artists, nil
+}
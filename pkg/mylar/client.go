@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mylar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// apiResponse wraps the data of every api command
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// get runs the api command with the params and unmarshals the data of the response into v, when set
+func (c *client) get(ctx context.Context, cmd string, params url.Values, v any) error {
+	u, err := url.Parse(c.config.Hostname)
+	if err != nil {
+		return errors.Wrap(err, "could not parse url: %s", c.config.Hostname)
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("apikey", c.config.APIKey)
+	params.Set("cmd", cmd)
+
+	u.Path = path.Join(u.Path, "/api")
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	if c.config.BasicAuth {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "mylar.http.Do(cmd): %s", cmd)
+	}
+
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, resp.Body); err != nil {
+		return errors.Wrap(err, "mylar.io.Copy")
+	}
+
+	c.Log.Printf("mylar %s status: (%v) response: %v\n", cmd, resp.StatusCode, buf.String())
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("unauthorized: bad credentials")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("mylar %s got unexpected status code: %d", cmd, resp.StatusCode)
+	}
+
+	var response apiResponse
+	if err := json.Unmarshal(buf.Bytes(), &response); err != nil {
+		return errors.Wrap(err, "could not unmarshal data")
+	}
+
+	if !response.Success {
+		return errors.New("mylar %s failed: %s", cmd, response.Error.Message)
+	}
+
+	if v == nil || len(response.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(response.Data, v); err != nil {
+		return errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return nil
+}
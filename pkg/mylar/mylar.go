@@ -0,0 +1,124 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mylar
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+type Config struct {
+	Hostname string
+	APIKey   string
+
+	// basic auth username and password
+	BasicAuth bool
+	Username  string
+	Password  string
+
+	Log *log.Logger
+}
+
+type Client interface {
+	Test(ctx context.Context) (*VersionResponse, error)
+	GetIndex(ctx context.Context) ([]Comic, error)
+	GetComic(ctx context.Context, id string) ([]Issue, error)
+	QueueIssue(ctx context.Context, id string) error
+}
+
+type client struct {
+	config Config
+	http   *http.Client
+
+	Log *log.Logger
+}
+
+// New create new mylar client
+func New(config Config) Client {
+	httpClient := &http.Client{
+		Timeout:   time.Second * 120,
+		Transport: sharedhttp.Transport,
+	}
+
+	c := &client{
+		config: config,
+		http:   httpClient,
+		Log:    log.New(io.Discard, "", log.LstdFlags),
+	}
+
+	if config.Log != nil {
+		c.Log = config.Log
+	}
+
+	return c
+}
+
+type VersionResponse struct {
+	CurrentVersion string `json:"current_version"`
+}
+
+// Comic is a series on the watchlist
+type Comic struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Year   string `json:"year"`
+	Status string `json:"status"`
+}
+
+// Issue is an issue of a comic on the watchlist, the status is eg. Wanted, Skipped, Snatched or Downloaded
+type Issue struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Number string `json:"number"`
+	Status string `json:"status"`
+}
+
+type comicResponse struct {
+	Issues []Issue `json:"issues"`
+}
+
+func (c *client) Test(ctx context.Context) (*VersionResponse, error) {
+	var response VersionResponse
+	if err := c.get(ctx, "getVersion", nil, &response); err != nil {
+		return nil, errors.Wrap(err, "could not make Test")
+	}
+
+	return &response, nil
+}
+
+// GetIndex returns the comics on the watchlist
+func (c *client) GetIndex(ctx context.Context) ([]Comic, error) {
+	var comics []Comic
+	if err := c.get(ctx, "getIndex", nil, &comics); err != nil {
+		return nil, errors.Wrap(err, "could not get watchlist")
+	}
+
+	return comics, nil
+}
+
+// GetComic returns the issues of the comic with the id
+func (c *client) GetComic(ctx context.Context, id string) ([]Issue, error) {
+	var response comicResponse
+	if err := c.get(ctx, "getComic", url.Values{"id": {id}}, &response); err != nil {
+		return nil, errors.Wrap(err, "could not get comic: %s", id)
+	}
+
+	return response.Issues, nil
+}
+
+// QueueIssue marks the issue as wanted, mylar searches its providers for it right away
+func (c *client) QueueIssue(ctx context.Context, id string) error {
+	if err := c.get(ctx, "queueIssue", url.Values{"id": {id}}, nil); err != nil {
+		return errors.Wrap(err, "could not queue issue: %s", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nzbget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+var (
+	DefaultTimeout = 60 * time.Second
+)
+
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	timeout time.Duration
+
+	log *log.Logger
+}
+
+type Config struct {
+	// Host of NZBGet, /jsonrpc is added when missing
+	Host string
+
+	// Username and Password are the ControlUsername and ControlPassword of NZBGet
+	Username string
+	Password string
+
+	// TLS skip cert validation
+	TLSSkipVerify bool
+
+	Timeout int
+	Log     *log.Logger
+}
+
+func NewClient(cfg Config) *Client {
+	c := &Client{
+		cfg:     cfg,
+		log:     log.New(io.Discard, "", log.LstdFlags),
+		timeout: DefaultTimeout,
+	}
+
+	// override logger if we pass one
+	if cfg.Log != nil {
+		c.log = cfg.Log
+	}
+
+	if cfg.Timeout > 0 {
+		c.timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	c.http = &http.Client{
+		Timeout:   c.timeout,
+		Transport: sharedhttp.Transport,
+	}
+
+	if cfg.TLSSkipVerify {
+		c.http.Transport = sharedhttp.TransportTLSInsecure
+	}
+
+	return c
+}
+
+type request struct {
+	Method string `json:"method"`
+	Params []any  `json:"params"`
+	ID     int    `json:"id"`
+}
+
+// response is the json-rpc 1.1 response of NZBGet, which the jsonrpc package does not decode
+type response struct {
+	Version string          `json:"version"`
+	Result  json.RawMessage `json:"result"`
+	Error   *Error          `json:"error"`
+}
+
+type Error struct {
+	Name    string `json:"name"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (c *Client) endpoint() string {
+	endpoint := strings.TrimSuffix(c.cfg.Host, "/")
+	if !strings.HasSuffix(endpoint, "/jsonrpc") {
+		endpoint += "/jsonrpc"
+	}
+
+	return endpoint
+}
+
+func (c *Client) call(ctx context.Context, method string, result any, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+
+	body, err := json.Marshal(request{Method: method, Params: params, ID: 1})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.cfg.Username != "" || c.cfg.Password != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not make request")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusUnauthorized {
+		return errors.New("unauthorized: bad credentials")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("unexpected status: %d", res.StatusCode)
+	}
+
+	var data response
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return errors.Wrap(err, "could not unmarshal body")
+	}
+
+	if data.Error != nil {
+		return errors.Wrap(data.Error, "%s failed", method)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data.Result, result); err != nil {
+		return errors.Wrap(err, "could not unmarshal result")
+	}
+
+	return nil
+}
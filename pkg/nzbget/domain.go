@@ -0,0 +1,59 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nzbget
+
+type Priority int
+
+const (
+	PriorityVeryLow  Priority = -100
+	PriorityLow      Priority = -50
+	PriorityNormal   Priority = 0
+	PriorityHigh     Priority = 50
+	PriorityVeryHigh Priority = 100
+	PriorityForce    Priority = 900
+)
+
+type DupeMode string
+
+const (
+	// DupeModeScore only downloads the nzb with the highest dupe score of the same dupe key
+	DupeModeScore DupeMode = "SCORE"
+
+	// DupeModeAll downloads all nzbs with the same dupe key
+	DupeModeAll DupeMode = "ALL"
+
+	// DupeModeForce downloads the nzb ignoring the duplicate check
+	DupeModeForce DupeMode = "FORCE"
+)
+
+type AppendRequest struct {
+	// Name is the nzb filename, it's used as the name of the download
+	Name string
+
+	// Content is an url NZBGet downloads the nzb from, or the base64 encoded nzb
+	Content string
+
+	Category  string
+	Priority  Priority
+	AddToTop  bool
+	AddPaused bool
+
+	// DupeKey groups nzbs of the same content, eg. the same episode from different posters
+	DupeKey   string
+	DupeScore int
+	DupeMode  DupeMode
+}
+
+type Group struct {
+	NZBID    int64  `json:"NZBID"`
+	NZBName  string `json:"NZBName"`
+	Category string `json:"Category"`
+	Status   string `json:"Status"`
+	DupeKey  string `json:"DupeKey"`
+}
+
+// Active reports if the nzb is downloading or queued, paused nzbs are not active
+func (g Group) Active() bool {
+	return g.Status == "QUEUED" || g.Status == "DOWNLOADING"
+}
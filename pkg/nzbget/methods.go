@@ -0,0 +1,49 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nzbget
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+func (c *Client) Version(ctx context.Context) (string, error) {
+	var version string
+	if err := c.call(ctx, "version", &version); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// Append adds the nzb to the queue and returns its id
+func (c *Client) Append(ctx context.Context, r AppendRequest) (int64, error) {
+	dupeMode := r.DupeMode
+	if dupeMode == "" {
+		dupeMode = DupeModeScore
+	}
+
+	var id int64
+	if err := c.call(ctx, "append", &id, r.Name, r.Content, r.Category, r.Priority, r.AddToTop, r.AddPaused, r.DupeKey, r.DupeScore, dupeMode, []any{}); err != nil {
+		return 0, err
+	}
+
+	// NZBGet returns 0 or a negative id when the nzb could not be added
+	if id <= 0 {
+		return 0, errors.New("could not add nzb: %s", r.Name)
+	}
+
+	return id, nil
+}
+
+// ListGroups returns the nzbs in the queue
+func (c *Client) ListGroups(ctx context.Context) ([]Group, error) {
+	var groups []Group
+	if err := c.call(ctx, "listgroups", &groups, 0); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
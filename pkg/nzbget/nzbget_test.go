@@ -0,0 +1,72 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nzbget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Append(t *testing.T) {
+	var params []any
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jsonrpc", r.URL.Path)
+
+		user, pass, _ := r.BasicAuth()
+		if user != "nzbget" || pass != "tegbzn6789" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req struct {
+			Method string `json:"method"`
+			Params []any  `json:"params"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "append", req.Method)
+		params = req.Params
+
+		// a dupe key of an nzb already downloaded is rejected by NZBGet with id 0
+		if req.Params[6] == "dupe" {
+			w.Write([]byte(`{"version":"1.1","id":1,"result":0}`))
+			return
+		}
+
+		w.Write([]byte(`{"version":"1.1","id":1,"result":42}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(Config{Host: ts.URL, Username: "nzbget", Password: "tegbzn6789"})
+
+	t.Run("append", func(t *testing.T) {
+		id, err := c.Append(context.Background(), AppendRequest{
+			Name:     "Show.Name.S01E01.1080p.WEB-DL.H264-GRP.nzb",
+			Content:  "https://indexer.test/getnzb/1",
+			Category: "tv",
+			Priority: PriorityHigh,
+			DupeKey:  "show-name-s01e01",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), id)
+		assert.Equal(t, []any{"Show.Name.S01E01.1080p.WEB-DL.H264-GRP.nzb", "https://indexer.test/getnzb/1", "tv", float64(50), false, false, "show-name-s01e01", float64(0), "SCORE", []any{}}, params)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		_, err := c.Append(context.Background(), AppendRequest{Name: "release.nzb", DupeKey: "dupe"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		c := NewClient(Config{Host: ts.URL + "/jsonrpc", Username: "nzbget", Password: "wrong"})
+
+		_, err := c.Append(context.Background(), AppendRequest{Name: "release.nzb"})
+		assert.ErrorContains(t, err, "unauthorized")
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package plex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+type Config struct {
+	Hostname string
+	Token    string
+
+	Log *log.Logger
+}
+
+type Client interface {
+	Test(ctx context.Context) (*IdentityResponse, error)
+	Search(ctx context.Context, query string) ([]Metadata, error)
+	GetAllLeaves(ctx context.Context, ratingKey string) ([]Metadata, error)
+}
+
+type client struct {
+	config Config
+	http   *http.Client
+
+	Log *log.Logger
+}
+
+// New create new plex client
+func New(config Config) Client {
+	httpClient := &http.Client{
+		Timeout:   time.Second * 120,
+		Transport: sharedhttp.Transport,
+	}
+
+	c := &client{
+		config: config,
+		http:   httpClient,
+		Log:    log.New(io.Discard, "", log.LstdFlags),
+	}
+
+	if config.Log != nil {
+		c.Log = config.Log
+	}
+
+	return c
+}
+
+type IdentityResponse struct {
+	MachineIdentifier string `json:"machineIdentifier"`
+	Version           string `json:"version"`
+}
+
+// Media is a version of a library item, the resolution is eg. sd, 720, 1080 or 4k
+type Media struct {
+	VideoResolution string `json:"videoResolution"`
+}
+
+// Metadata is a library item, movies and shows are returned by search and episodes by the leaves of a show
+type Metadata struct {
+	RatingKey   string  `json:"ratingKey"`
+	Type        string  `json:"type"`
+	Title       string  `json:"title"`
+	Year        int     `json:"year"`
+	ParentIndex int     `json:"parentIndex"` // season of an episode
+	Index       int     `json:"index"`       // episode number
+	Media       []Media `json:"Media"`
+}
+
+type mediaContainer[T any] struct {
+	MediaContainer T `json:"MediaContainer"`
+}
+
+type hub struct {
+	Type     string     `json:"type"`
+	Metadata []Metadata `json:"Metadata"`
+}
+
+type hubsResponse struct {
+	Hub []hub `json:"Hub"`
+}
+
+type metadataResponse struct {
+	Metadata []Metadata `json:"Metadata"`
+}
+
+func (c *client) get(ctx context.Context, endpoint string, params url.Values, v any) error {
+	u, err := url.Parse(c.config.Hostname)
+	if err != nil {
+		return errors.Wrap(err, "could not parse url: %s", c.config.Hostname)
+	}
+
+	u.Path = path.Join(u.Path, endpoint)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("X-Plex-Token", c.config.Token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "plex.http.Do(req): %s", endpoint)
+	}
+
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, resp.Body); err != nil {
+		return errors.Wrap(err, "plex.io.Copy")
+	}
+
+	c.Log.Printf("plex %s status: (%v) response: %v\n", endpoint, resp.StatusCode, buf.String())
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("unauthorized: bad token")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("plex %s got unexpected status code: %d", endpoint, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return nil
+}
+
+func (c *client) Test(ctx context.Context) (*IdentityResponse, error) {
+	var response mediaContainer[IdentityResponse]
+	if err := c.get(ctx, "identity", nil, &response); err != nil {
+		return nil, errors.Wrap(err, "could not make Test")
+	}
+
+	return &response.MediaContainer, nil
+}
+
+// Search returns the movies and shows of all libraries matching the query
+func (c *client) Search(ctx context.Context, query string) ([]Metadata, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("includeCollections", "0")
+
+	var response mediaContainer[hubsResponse]
+	if err := c.get(ctx, "hubs/search", params, &response); err != nil {
+		return nil, errors.Wrap(err, "could not search: %s", query)
+	}
+
+	var items []Metadata
+	for _, h := range response.MediaContainer.Hub {
+		if h.Type != "movie" && h.Type != "show" {
+			continue
+		}
+
+		items = append(items, h.Metadata...)
+	}
+
+	return items, nil
+}
+
+// GetAllLeaves returns the episodes of the show
+func (c *client) GetAllLeaves(ctx context.Context, ratingKey string) ([]Metadata, error) {
+	var response mediaContainer[metadataResponse]
+	if err := c.get(ctx, path.Join("library/metadata", ratingKey, "allLeaves"), nil, &response); err != nil {
+		return nil, errors.Wrap(err, "could not get episodes of: %s", ratingKey)
+	}
+
+	return response.MediaContainer.Metadata, nil
+}
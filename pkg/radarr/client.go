@@ -16,12 +16,17 @@ import (
 )
 
 func (c *client) get(ctx context.Context, endpoint string) (int, []byte, error) {
+	return c.getWithParams(ctx, endpoint, nil)
+}
+
+func (c *client) getWithParams(ctx context.Context, endpoint string, params url.Values) (int, []byte, error) {
 	u, err := url.Parse(c.config.Hostname)
 	if err != nil {
 		return 0, nil, errors.Wrap(err, "could not parse url: %s", c.config.Hostname)
 	}
 
 	u.Path = path.Join(u.Path, "/api/v3/", endpoint)
+	u.RawQuery = params.Encode()
 	reqUrl := u.String()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, http.NoBody)
@@ -10,6 +10,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -32,6 +33,10 @@ type Config struct {
 type Client interface {
 	Test(ctx context.Context) (*SystemStatusResponse, error)
 	Push(ctx context.Context, release Release) ([]string, error)
+	Parse(ctx context.Context, title string) (*ParseResponse, error)
+	GetMovieFiles(ctx context.Context, movieID int64) ([]MovieFile, error)
+	GetMovies(ctx context.Context) ([]Movie, error)
+	GetTags(ctx context.Context) ([]Tag, error)
 }
 
 type client struct {
@@ -155,3 +160,127 @@ func (c *client) Push(ctx context.Context, release Release) ([]string, error) {
 	// success true
 	return nil, nil
 }
+
+// ParseResponse is the movie radarr matched a release title to, movie is nil when it's unknown
+type ParseResponse struct {
+	Title string `json:"title"`
+	Movie *Movie `json:"movie,omitempty"`
+}
+
+type Movie struct {
+	ID          int64   `json:"id"`
+	Title       string  `json:"title"`
+	Year        int     `json:"year"`
+	TmdbID      int64   `json:"tmdbId"`
+	Monitored   bool    `json:"monitored"`
+	IsAvailable bool    `json:"isAvailable"` // released according to the minimum availability of the movie
+	HasFile     bool    `json:"hasFile"`
+	Tags        []int64 `json:"tags,omitempty"`
+}
+
+type MovieFile struct {
+	ID                  int64 `json:"id"`
+	MovieID             int64 `json:"movieId"`
+	QualityCutoffNotMet bool  `json:"qualityCutoffNotMet"`
+}
+
+// Parse asks radarr which movie the release title is for
+func (c *client) Parse(ctx context.Context, title string) (*ParseResponse, error) {
+	status, res, err := c.getWithParams(ctx, "parse", url.Values{"title": {title}})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse title: %s", title)
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("radarr parse got unexpected status code: %d", status)
+	}
+
+	c.Log.Printf("radarr parse status: (%v) response: %v\n", status, string(res))
+
+	var response ParseResponse
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return &response, nil
+}
+
+// GetMovieFiles returns the files of the movie, they tell if the quality cutoff of the movie is met
+func (c *client) GetMovieFiles(ctx context.Context, movieID int64) ([]MovieFile, error) {
+	status, res, err := c.getWithParams(ctx, "moviefile", url.Values{"movieId": {fmt.Sprintf("%d", movieID)}})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get files of movie: %d", movieID)
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("radarr moviefile got unexpected status code: %d", status)
+	}
+
+	var response []MovieFile
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return response, nil
+}
+
+// GetMovies returns all movies
+func (c *client) GetMovies(ctx context.Context) ([]Movie, error) {
+	status, res, err := c.get(ctx, "movie")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get movies")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("radarr movie got unexpected status code: %d", status)
+	}
+
+	var response []Movie
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	c.Log.Printf("radarr movie got %d movies\n", len(response))
+
+	return response, nil
+}
+
+type Tag struct {
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+}
+
+// GetTags returns the tags, items only reference them by id
+func (c *client) GetTags(ctx context.Context) ([]Tag, error) {
+	status, res, err := c.get(ctx, "tag")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tags")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("radarr tag got unexpected status code: %d", status)
+	}
+
+	var response []Tag
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return response, nil
+}
@@ -33,6 +33,8 @@ type Config struct {
 type Client interface {
 	Test(ctx context.Context) (*SystemStatusResponse, error)
 	Push(ctx context.Context, release Release) ([]string, error)
+	GetAuthors(ctx context.Context) ([]Author, error)
+	GetTags(ctx context.Context) ([]Tag, error)
 }
 
 type client struct {
@@ -160,3 +162,63 @@ func (c *client) Push(ctx context.Context, release Release) ([]string, error) {
 	// successful push
 	return nil, nil
 }
+
+type Author struct {
+	ID         int64   `json:"id"`
+	AuthorName string  `json:"authorName"`
+	Monitored  bool    `json:"monitored"`
+	Tags       []int64 `json:"tags,omitempty"`
+}
+
+// GetAuthors returns all authors
+func (c *client) GetAuthors(ctx context.Context) ([]Author, error) {
+	status, res, err := c.get(ctx, "author")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get authors")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("readarr author got unexpected status code: %d", status)
+	}
+
+	var response []Author
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	c.Log.Printf("readarr author got %d authors\n", len(response))
+
+	return response, nil
+}
+
+type Tag struct {
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+}
+
+// GetTags returns the tags, items only reference them by id
+func (c *client) GetTags(ctx context.Context) ([]Tag, error) {
+	status, res, err := c.get(ctx, "tag")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tags")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("readarr tag got unexpected status code: %d", status)
+	}
+
+	var response []Tag
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return response, nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package remotefs uploads files to remote directories over SFTP, FTP or WebDAV,
+// for clients which can't be reached over an api but poll a watch directory.
+package remotefs
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var (
+	DefaultTimeout = 60 * time.Second
+)
+
+type Client struct {
+	cfg     Config
+	timeout time.Duration
+
+	log *log.Logger
+}
+
+type Config struct {
+	// URL of the remote directory, the scheme picks the protocol:
+	// sftp://host:22/path, ftp://host:21/path, ftps://host/path or http(s)://host/webdav/path
+	URL      string
+	Username string
+	Password string
+
+	// TLSSkipVerify skips the cert validation for ftps and https, and the host key validation for sftp
+	TLSSkipVerify bool
+
+	// HostKeyFingerprint is the SHA256 fingerprint of the sftp host key, eg. SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8
+	HostKeyFingerprint string
+
+	Timeout int
+	Log     *log.Logger
+}
+
+func NewClient(cfg Config) *Client {
+	c := &Client{
+		cfg:     cfg,
+		log:     log.New(io.Discard, "", log.LstdFlags),
+		timeout: DefaultTimeout,
+	}
+
+	// override logger if we pass one
+	if cfg.Log != nil {
+		c.log = cfg.Log
+	}
+
+	if cfg.Timeout > 0 {
+		c.timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	return c
+}
+
+func (c *Client) parseURL() (*url.URL, error) {
+	u, err := url.Parse(c.cfg.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse url: %s", c.cfg.URL)
+	}
+
+	switch u.Scheme {
+	case "sftp", "ftp", "ftps", "http", "https":
+		return u, nil
+	default:
+		return nil, errors.New("unsupported protocol: %q, use sftp, ftp, ftps, http or https", u.Scheme)
+	}
+}
+
+// remotePath joins relative paths with the path of the url
+func remotePath(u *url.URL, name string) string {
+	if strings.HasPrefix(name, "/") {
+		return path.Clean(name)
+	}
+
+	return path.Join("/", u.Path, name)
+}
+
+// Upload saves the content as the file name, missing directories are created.
+// Relative names are saved in the directory of the url.
+func (c *Client) Upload(ctx context.Context, name string, content []byte) error {
+	u, err := c.parseURL()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	name = remotePath(u, name)
+
+	c.log.Printf("upload %d bytes to %s://%s%s", len(content), u.Scheme, u.Host, name)
+
+	switch u.Scheme {
+	case "sftp":
+		return c.sftpUpload(ctx, u, name, content)
+	case "ftp", "ftps":
+		return c.ftpUpload(ctx, u, name, content)
+	default:
+		return c.webdavUpload(ctx, u, name, content)
+	}
+}
+
+// Test connects and logs in to check the settings
+func (c *Client) Test(ctx context.Context) error {
+	u, err := c.parseURL()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	switch u.Scheme {
+	case "sftp":
+		return c.sftpTest(ctx, u)
+	case "ftp", "ftps":
+		return c.ftpTest(ctx, u)
+	default:
+		return c.webdavTest(ctx, u)
+	}
+}
+
+// parentDirs returns the parent directories of the file, from the top most down
+func parentDirs(name string) []string {
+	var dirs []string
+	for dir := path.Dir(name); dir != "/" && dir != "."; dir = path.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+	}
+	return dirs
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package remotefs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/jlaffaye/ftp"
+)
+
+func (c *Client) ftpConnect(ctx context.Context, u *url.URL) (*ftp.ServerConn, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "21")
+	}
+
+	opts := []ftp.DialOption{
+		ftp.DialWithContext(ctx),
+		ftp.DialWithTimeout(c.timeout),
+	}
+
+	if u.Scheme == "ftps" {
+		opts = append(opts, ftp.DialWithExplicitTLS(&tls.Config{
+			ServerName:         u.Hostname(),
+			InsecureSkipVerify: c.cfg.TLSSkipVerify,
+		}))
+	}
+
+	conn, err := ftp.Dial(addr, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to ftp server: %s", addr)
+	}
+
+	username := c.cfg.Username
+	if username == "" {
+		username = "anonymous"
+	}
+
+	if err := conn.Login(username, c.cfg.Password); err != nil {
+		_ = conn.Quit()
+		return nil, errors.Wrap(err, "could not login to ftp server: %s", addr)
+	}
+
+	return conn, nil
+}
+
+func (c *Client) ftpUpload(ctx context.Context, u *url.URL, name string, content []byte) error {
+	conn, err := c.ftpConnect(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer conn.Quit()
+
+	// errors are ignored as the directories might exist already, a missing directory fails the upload
+	for _, dir := range parentDirs(name) {
+		_ = conn.MakeDir(dir)
+	}
+
+	if err := conn.Stor(name, bytes.NewReader(content)); err != nil {
+		return errors.Wrap(err, "could not upload file: %s", name)
+	}
+
+	return nil
+}
+
+func (c *Client) ftpTest(ctx context.Context, u *url.URL) error {
+	conn, err := c.ftpConnect(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer conn.Quit()
+
+	return conn.NoOp()
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package remotefs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_remotePath(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		file string
+		want string
+	}{
+		{name: "relative", url: "sftp://host/watch", file: "tv/Show.torrent", want: "/watch/tv/Show.torrent"},
+		{name: "absolute", url: "sftp://host/watch", file: "/other/Show.torrent", want: "/other/Show.torrent"},
+		{name: "no_path", url: "ftp://host", file: "Show.torrent", want: "/Show.torrent"},
+		{name: "clean", url: "ftp://host/watch/", file: "../Show.torrent", want: "/Show.torrent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, remotePath(u, tt.file))
+		})
+	}
+}
+
+func Test_parentDirs(t *testing.T) {
+	assert.Equal(t, []string{"/watch", "/watch/tv"}, parentDirs("/watch/tv/Show.torrent"))
+	assert.Empty(t, parentDirs("/Show.torrent"))
+}
+
+func TestClient_UnsupportedProtocol(t *testing.T) {
+	c := NewClient(Config{URL: "smb://host/watch"})
+
+	assert.ErrorContains(t, c.Upload(context.Background(), "Show.torrent", []byte("data")), "unsupported protocol")
+	assert.ErrorContains(t, c.Test(context.Background()), "unsupported protocol")
+}
+
+func TestClient_SftpRequiresHostKey(t *testing.T) {
+	c := NewClient(Config{URL: "sftp://127.0.0.1:1/watch"})
+
+	assert.ErrorContains(t, c.Test(context.Background()), "host key")
+}
+
+func TestClient_WebDAV(t *testing.T) {
+	var mu sync.Mutex
+	collections := map[string]bool{"/dav/": true}
+	files := map[string]string{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case "PROPFIND":
+			assert.Equal(t, "0", r.Header.Get("Depth"))
+			w.WriteHeader(http.StatusMultiStatus)
+		case "MKCOL":
+			if collections[r.URL.Path] {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			collections[r.URL.Path] = true
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			files[r.URL.Path] = string(body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{URL: srv.URL + "/dav", Username: "user", Password: "pass"})
+
+	assert.NoError(t, c.Test(context.Background()))
+	assert.NoError(t, c.Upload(context.Background(), "tv/Show.S01E01.torrent", []byte("data")))
+
+	assert.True(t, collections["/dav/tv/"])
+	assert.Equal(t, map[string]string{"/dav/tv/Show.S01E01.torrent": "data"}, files)
+
+	bad := NewClient(Config{URL: srv.URL + "/dav", Username: "user", Password: "wrong"})
+	assert.ErrorContains(t, bad.Test(context.Background()), "unauthorized")
+	assert.Error(t, bad.Upload(context.Background(), "Show.torrent", []byte("data")))
+}
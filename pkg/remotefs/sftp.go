@@ -0,0 +1,347 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package remotefs
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/url"
+	"path"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sftp packet types, see draft-ietf-secsh-filexfer-02 for protocol version 3
+const (
+	sftpPacketInit    = 1
+	sftpPacketVersion = 2
+	sftpPacketOpen    = 3
+	sftpPacketClose   = 4
+	sftpPacketWrite   = 6
+	sftpPacketMkdir   = 14
+	sftpPacketStat    = 17
+	sftpPacketStatus  = 101
+	sftpPacketHandle  = 102
+	sftpPacketAttrs   = 105
+
+	sftpFlagWrite = 0x02
+	sftpFlagCreat = 0x08
+	sftpFlagTrunc = 0x10
+
+	sftpStatusOK = 0
+
+	// sftpMaxWrite is the data size servers must accept in a single write
+	sftpMaxWrite = 32 * 1024
+)
+
+var sftpStatusMessages = map[uint32]string{
+	1: "end of file",
+	2: "no such file",
+	3: "permission denied",
+	4: "failure",
+	5: "bad message",
+	6: "no connection",
+	7: "connection lost",
+	8: "operation unsupported",
+}
+
+// sftpSession is a minimal sftp client, it only supports what's needed to upload files
+type sftpSession struct {
+	client  *ssh.Client
+	session *ssh.Session
+	w       io.WriteCloser
+	r       io.Reader
+	id      uint32
+}
+
+func (c *Client) sftpHostKeyCallback() (ssh.HostKeyCallback, error) {
+	if c.cfg.HostKeyFingerprint != "" {
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if fingerprint := ssh.FingerprintSHA256(key); fingerprint != c.cfg.HostKeyFingerprint {
+				return errors.New("host key mismatch: got %s expected %s", fingerprint, c.cfg.HostKeyFingerprint)
+			}
+			return nil
+		}, nil
+	}
+
+	if c.cfg.TLSSkipVerify {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	return nil, errors.New("sftp requires the host key fingerprint, or skip verification")
+}
+
+func (c *Client) sftpConnect(ctx context.Context, u *url.URL) (*sftpSession, error) {
+	hostKeyCallback, err := c.sftpHostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to sftp server: %s", addr)
+	}
+
+	// the ssh handshake doesn't take a context, close the connection when it's done
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            c.cfg.Username,
+		Auth:            []ssh.AuthMethod{ssh.Password(c.cfg.Password)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         c.timeout,
+	})
+	if err != nil {
+		stop()
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "could not login to sftp server: %s", addr)
+	}
+
+	s := &sftpSession{client: ssh.NewClient(sshConn, chans, reqs)}
+
+	if err := s.start(); err != nil {
+		stop()
+		s.close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *sftpSession) start() error {
+	session, err := s.client.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "could not open ssh session")
+	}
+	s.session = session
+
+	if s.w, err = session.StdinPipe(); err != nil {
+		return errors.Wrap(err, "could not open stdin")
+	}
+
+	if s.r, err = session.StdoutPipe(); err != nil {
+		return errors.Wrap(err, "could not open stdout")
+	}
+
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return errors.Wrap(err, "could not start sftp subsystem")
+	}
+
+	// init carries the version instead of a request id
+	if err := s.send(sftpPacketInit, uint32(3)); err != nil {
+		return err
+	}
+
+	packetType, _, err := s.recv()
+	if err != nil {
+		return err
+	}
+
+	if packetType != sftpPacketVersion {
+		return errors.New("unexpected sftp packet: %d", packetType)
+	}
+
+	return nil
+}
+
+func (s *sftpSession) close() {
+	if s.session != nil {
+		_ = s.session.Close()
+	}
+	_ = s.client.Close()
+}
+
+// send writes a packet, the fields are encoded as uint32, uint64, string or []byte
+func (s *sftpSession) send(packetType byte, fields ...any) error {
+	data := []byte{packetType}
+
+	for _, field := range fields {
+		switch v := field.(type) {
+		case uint32:
+			data = binary.BigEndian.AppendUint32(data, v)
+		case uint64:
+			data = binary.BigEndian.AppendUint64(data, v)
+		case string:
+			data = binary.BigEndian.AppendUint32(data, uint32(len(v)))
+			data = append(data, v...)
+		case []byte:
+			data = binary.BigEndian.AppendUint32(data, uint32(len(v)))
+			data = append(data, v...)
+		}
+	}
+
+	packet := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+
+	if _, err := s.w.Write(append(packet, data...)); err != nil {
+		return errors.Wrap(err, "could not send sftp packet")
+	}
+
+	return nil
+}
+
+func (s *sftpSession) recv() (byte, []byte, error) {
+	var length uint32
+	if err := binary.Read(s.r, binary.BigEndian, &length); err != nil {
+		return 0, nil, errors.Wrap(err, "could not read sftp packet")
+	}
+
+	if length == 0 || length > 256*1024 {
+		return 0, nil, errors.New("invalid sftp packet length: %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(s.r, data); err != nil {
+		return 0, nil, errors.Wrap(err, "could not read sftp packet")
+	}
+
+	return data[0], data[1:], nil
+}
+
+// request sends the packet with the next request id and returns the type and payload of the response
+func (s *sftpSession) request(packetType byte, fields ...any) (byte, []byte, error) {
+	s.id++
+
+	if err := s.send(packetType, append([]any{s.id}, fields...)...); err != nil {
+		return 0, nil, err
+	}
+
+	responseType, payload, err := s.recv()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(payload) < 4 || binary.BigEndian.Uint32(payload) != s.id {
+		return 0, nil, errors.New("unexpected sftp response")
+	}
+
+	return responseType, payload[4:], nil
+}
+
+// status returns the error of a status response
+func sftpStatus(responseType byte, payload []byte) error {
+	if responseType != sftpPacketStatus {
+		return errors.New("unexpected sftp packet: %d", responseType)
+	}
+
+	if len(payload) < 4 {
+		return errors.New("invalid sftp status")
+	}
+
+	code := binary.BigEndian.Uint32(payload)
+	if code == sftpStatusOK {
+		return nil
+	}
+
+	if msg, ok := sftpStatusMessages[code]; ok {
+		return errors.New("sftp error: %s", msg)
+	}
+
+	return errors.New("sftp error: code %d", code)
+}
+
+func (s *sftpSession) mkdir(dir string) error {
+	responseType, payload, err := s.request(sftpPacketMkdir, dir, uint32(0))
+	if err != nil {
+		return err
+	}
+
+	return sftpStatus(responseType, payload)
+}
+
+func (s *sftpSession) stat(name string) error {
+	responseType, payload, err := s.request(sftpPacketStat, name)
+	if err != nil {
+		return err
+	}
+
+	if responseType == sftpPacketAttrs {
+		return nil
+	}
+
+	return sftpStatus(responseType, payload)
+}
+
+func (s *sftpSession) writeFile(name string, content []byte) error {
+	responseType, payload, err := s.request(sftpPacketOpen, name, uint32(sftpFlagWrite|sftpFlagCreat|sftpFlagTrunc), uint32(0))
+	if err != nil {
+		return err
+	}
+
+	if responseType != sftpPacketHandle {
+		return sftpStatus(responseType, payload)
+	}
+
+	if len(payload) < 4 || int(binary.BigEndian.Uint32(payload)) > len(payload)-4 {
+		return errors.New("invalid sftp handle")
+	}
+
+	handle := string(payload[4 : 4+binary.BigEndian.Uint32(payload)])
+
+	for offset := 0; offset < len(content); offset += sftpMaxWrite {
+		chunk := content[offset:min(offset+sftpMaxWrite, len(content))]
+
+		responseType, payload, err := s.request(sftpPacketWrite, handle, uint64(offset), chunk)
+		if err != nil {
+			return err
+		}
+
+		if err := sftpStatus(responseType, payload); err != nil {
+			return err
+		}
+	}
+
+	responseType, payload, err = s.request(sftpPacketClose, handle)
+	if err != nil {
+		return err
+	}
+
+	return sftpStatus(responseType, payload)
+}
+
+func (c *Client) sftpUpload(ctx context.Context, u *url.URL, name string, content []byte) error {
+	s, err := c.sftpConnect(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	// errors are ignored as the directories might exist already, a missing directory fails the upload
+	for _, dir := range parentDirs(name) {
+		_ = s.mkdir(dir)
+	}
+
+	if err := s.writeFile(name, content); err != nil {
+		return errors.Wrap(err, "could not upload file: %s", name)
+	}
+
+	return nil
+}
+
+func (c *Client) sftpTest(ctx context.Context, u *url.URL) error {
+	s, err := c.sftpConnect(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	if dir := remotePath(u, ""); dir != "/" {
+		if err := s.stat(path.Clean(dir)); err != nil {
+			return errors.Wrap(err, "could not find directory: %s", dir)
+		}
+	}
+
+	return nil
+}
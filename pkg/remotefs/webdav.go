@@ -0,0 +1,102 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package remotefs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+func (c *Client) webdavClient() *http.Client {
+	client := &http.Client{
+		Timeout:   c.timeout,
+		Transport: sharedhttp.Transport,
+	}
+
+	if c.cfg.TLSSkipVerify {
+		client.Transport = sharedhttp.TransportTLSInsecure
+	}
+
+	return client
+}
+
+func (c *Client) webdavDo(ctx context.Context, client *http.Client, method string, u *url.URL, name string, body []byte) (int, error) {
+	target := *u
+	target.Path = name
+	target.RawPath = ""
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not build request")
+	}
+
+	if method == "PROPFIND" {
+		req.Header.Set("Depth", "0")
+	}
+
+	if c.cfg.Username != "" || c.cfg.Password != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not make %s request: %s", method, name)
+	}
+
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	return res.StatusCode, nil
+}
+
+func (c *Client) webdavUpload(ctx context.Context, u *url.URL, name string, content []byte) error {
+	client := c.webdavClient()
+
+	// an existing collection responds with 405 Method Not Allowed, a missing one fails the upload
+	for _, dir := range parentDirs(name) {
+		if _, err := c.webdavDo(ctx, client, "MKCOL", u, dir+"/", nil); err != nil {
+			return err
+		}
+	}
+
+	status, err := c.webdavDo(ctx, client, http.MethodPut, u, name, content)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK && status != http.StatusCreated && status != http.StatusNoContent {
+		return errors.New("could not upload file: %s unexpected status: %d", name, status)
+	}
+
+	return nil
+}
+
+func (c *Client) webdavTest(ctx context.Context, u *url.URL) error {
+	status, err := c.webdavDo(ctx, c.webdavClient(), "PROPFIND", u, remotePath(u, ""), nil)
+	if err != nil {
+		return err
+	}
+
+	if status == http.StatusUnauthorized {
+		return errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusMultiStatus {
+		return errors.New("unexpected status: %d, is the url a webdav directory", status)
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -33,6 +34,10 @@ type Config struct {
 type Client interface {
 	Test(ctx context.Context) (*SystemStatusResponse, error)
 	Push(ctx context.Context, release Release) ([]string, error)
+	Parse(ctx context.Context, title string) (*ParseResponse, error)
+	GetEpisodeFile(ctx context.Context, id int64) (*EpisodeFile, error)
+	GetSeries(ctx context.Context) ([]Series, error)
+	GetTags(ctx context.Context) ([]Tag, error)
 }
 
 type client struct {
@@ -158,3 +163,132 @@ func (c *client) Push(ctx context.Context, release Release) ([]string, error) {
 	// successful push
 	return nil, nil
 }
+
+// ParseResponse is the series and episodes sonarr matched a release title to, series is nil when it's unknown
+type ParseResponse struct {
+	Title    string    `json:"title"`
+	Series   *Series   `json:"series,omitempty"`
+	Episodes []Episode `json:"episodes"`
+}
+
+type Series struct {
+	ID        int64   `json:"id"`
+	Title     string  `json:"title"`
+	Monitored bool    `json:"monitored"`
+	Tags      []int64 `json:"tags,omitempty"`
+}
+
+type Episode struct {
+	ID            int64 `json:"id"`
+	SeasonNumber  int   `json:"seasonNumber"`
+	EpisodeNumber int   `json:"episodeNumber"`
+	Monitored     bool  `json:"monitored"`
+	HasFile       bool  `json:"hasFile"`
+	EpisodeFileID int64 `json:"episodeFileId"`
+}
+
+type EpisodeFile struct {
+	ID                  int64 `json:"id"`
+	QualityCutoffNotMet bool  `json:"qualityCutoffNotMet"`
+}
+
+// Parse asks sonarr which series and episodes the release title is for
+func (c *client) Parse(ctx context.Context, title string) (*ParseResponse, error) {
+	status, res, err := c.getWithParams(ctx, "parse", url.Values{"title": {title}})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse title: %s", title)
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("sonarr parse got unexpected status code: %d", status)
+	}
+
+	c.Log.Printf("sonarr parse status: (%v) response: %v\n", status, string(res))
+
+	var response ParseResponse
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return &response, nil
+}
+
+// GetEpisodeFile returns the episode file with the id, it tells if the quality cutoff of the series is met
+func (c *client) GetEpisodeFile(ctx context.Context, id int64) (*EpisodeFile, error) {
+	status, res, err := c.get(ctx, fmt.Sprintf("episodefile/%d", id))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get episode file: %d", id)
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("sonarr episodefile got unexpected status code: %d", status)
+	}
+
+	var response EpisodeFile
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return &response, nil
+}
+
+// GetSeries returns all series
+func (c *client) GetSeries(ctx context.Context) ([]Series, error) {
+	status, res, err := c.get(ctx, "series")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get series")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("sonarr series got unexpected status code: %d", status)
+	}
+
+	var response []Series
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	c.Log.Printf("sonarr series got %d series\n", len(response))
+
+	return response, nil
+}
+
+type Tag struct {
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+}
+
+// GetTags returns the tags, items only reference them by id
+func (c *client) GetTags(ctx context.Context) ([]Tag, error) {
+	status, res, err := c.get(ctx, "tag")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tags")
+	}
+
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("sonarr tag got unexpected status code: %d", status)
+	}
+
+	var response []Tag
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return response, nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package tmdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+// DefaultHostname is the tmdb api, other hostnames are for tests and proxies
+const DefaultHostname = "https://api.themoviedb.org"
+
+type Config struct {
+	Hostname string
+	APIKey   string // v3 api key
+
+	Log *log.Logger
+}
+
+type Client interface {
+	SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error)
+	SearchTV(ctx context.Context, query string, year int) ([]SearchResult, error)
+	MovieExternalIDs(ctx context.Context, id int64) (*ExternalIDs, error)
+	TVExternalIDs(ctx context.Context, id int64) (*ExternalIDs, error)
+}
+
+type client struct {
+	config Config
+	http   *http.Client
+
+	Log *log.Logger
+}
+
+// New create new tmdb client
+func New(config Config) Client {
+	httpClient := &http.Client{
+		Timeout:   time.Second * 30,
+		Transport: sharedhttp.Transport,
+	}
+
+	if config.Hostname == "" {
+		config.Hostname = DefaultHostname
+	}
+
+	c := &client{
+		config: config,
+		http:   httpClient,
+		Log:    log.New(io.Discard, "", log.LstdFlags),
+	}
+
+	if config.Log != nil {
+		c.Log = config.Log
+	}
+
+	return c
+}
+
+// SearchResult is a movie or show, movies have a title and shows a name
+type SearchResult struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Name  string `json:"name"`
+}
+
+type searchResponse struct {
+	Results []SearchResult `json:"results"`
+}
+
+type ExternalIDs struct {
+	IMDbID string `json:"imdb_id"`
+	TVDBID int64  `json:"tvdb_id"`
+}
+
+func (c *client) get(ctx context.Context, endpoint string, params url.Values, v any) error {
+	u, err := url.Parse(c.config.Hostname)
+	if err != nil {
+		return errors.Wrap(err, "could not parse url: %s", c.config.Hostname)
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("api_key", c.config.APIKey)
+
+	u.Path = path.Join(u.Path, "/3", endpoint)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "tmdb.http.Do(req): %s", endpoint)
+	}
+
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, resp.Body); err != nil {
+		return errors.Wrap(err, "tmdb.io.Copy")
+	}
+
+	c.Log.Printf("tmdb %s status: (%v) response: %v\n", endpoint, resp.StatusCode, buf.String())
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("unauthorized: bad api key")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("tmdb %s got unexpected status code: %d", endpoint, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return nil
+}
+
+// SearchMovie returns the movies matching the query, best match first. The year is skipped when 0
+func (c *client) SearchMovie(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	params := url.Values{"query": {query}}
+	if year > 0 {
+		params.Set("year", strconv.Itoa(year))
+	}
+
+	var response searchResponse
+	if err := c.get(ctx, "search/movie", params, &response); err != nil {
+		return nil, errors.Wrap(err, "could not search movie: %s", query)
+	}
+
+	return response.Results, nil
+}
+
+// SearchTV returns the shows matching the query, best match first. The year of the first air date is skipped when 0
+func (c *client) SearchTV(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	params := url.Values{"query": {query}}
+	if year > 0 {
+		params.Set("first_air_date_year", strconv.Itoa(year))
+	}
+
+	var response searchResponse
+	if err := c.get(ctx, "search/tv", params, &response); err != nil {
+		return nil, errors.Wrap(err, "could not search tv: %s", query)
+	}
+
+	return response.Results, nil
+}
+
+func (c *client) MovieExternalIDs(ctx context.Context, id int64) (*ExternalIDs, error) {
+	var response ExternalIDs
+	if err := c.get(ctx, fmt.Sprintf("movie/%d/external_ids", id), nil, &response); err != nil {
+		return nil, errors.Wrap(err, "could not get external ids of movie: %d", id)
+	}
+
+	return &response, nil
+}
+
+func (c *client) TVExternalIDs(ctx context.Context, id int64) (*ExternalIDs, error) {
+	var response ExternalIDs
+	if err := c.get(ctx, fmt.Sprintf("tv/%d/external_ids", id), nil, &response); err != nil {
+		return nil, errors.Wrap(err, "could not get external ids of tv: %d", id)
+	}
+
+	return &response, nil
+}
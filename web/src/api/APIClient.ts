@@ -173,7 +173,9 @@ export async function HttpClient<T = unknown>(
     // It is most likely an error.
     switch (response.status) {
     case 403: {
-      if (AuthContext.get().isLoggedIn) {
+      // the role of the user does not allow the request, the session is fine
+      const missingScope = isJson && (await response.clone().json()).code === "MISSING_SCOPE";
+      if (AuthContext.get().isLoggedIn && !missingScope) {
         return Promise.reject(new Error("Cookie expired or invalid."));
       }
       break;
@@ -231,7 +233,7 @@ const appClient = {
     ...config,
     method: "PATCH"
   }),
-  Delete: (endpoint: string, config: HttpConfig = {}) => HttpClient<void>(endpoint, {
+  Delete: <T = void>(endpoint: string, config: HttpConfig = {}) => HttpClient<T>(endpoint, {
     ...config,
     method: "DELETE"
   })
@@ -244,6 +246,8 @@ export const APIClient = {
     }),
     logout: () => appClient.Post("api/auth/logout"),
     validate: () => appClient.Get<void>("api/auth/validate"),
+    user: () => appClient.Get<CurrentUser>("api/auth/user"),
+    oidcConfig: () => appClient.Get<OIDCConfig>("api/auth/oidc/config"),
     onboard: (username: string, password: string) => appClient.Post("api/auth/onboard", {
       body: { username, password }
     }),
@@ -268,6 +272,21 @@ export const APIClient = {
     }),
     delete: (key: string) => appClient.Delete(`api/keys/${key}`)
   },
+  audit: {
+    find: (offset: number, limit: number) => appClient.Get<AuditResponse>("api/audit", {
+      queryString: { offset, limit }
+    })
+  },
+  users: {
+    getAll: () => appClient.Get<User[]>("api/users"),
+    create: (user: UserCreate) => appClient.Post("api/users", {
+      body: user
+    }),
+    updateRole: (id: number, role: UserRole) => appClient.Patch(`api/users/${id}/role`, {
+      body: { role }
+    }),
+    delete: (id: number) => appClient.Delete(`api/users/${id}`)
+  },
   config: {
     get: () => appClient.Get<Config>("api/config"),
     update: (config: ConfigUpdate) => appClient.Patch("api/config", {
@@ -306,7 +325,29 @@ export const APIClient = {
     toggleEnable: (id: number, enabled: boolean) => appClient.Put(`api/filters/${id}/enabled`, {
       body: { enabled }
     }),
-    delete: (id: number) => appClient.Delete(`api/filters/${id}`)
+    delete: (id: number) => appClient.Delete(`api/filters/${id}`),
+    purge: (id: number) => appClient.Delete(`api/filters/${id}`, {
+      queryString: { permanent: true }
+    }),
+    getTrashed: () => appClient.Get<Filter[]>("api/filters/trash"),
+    restore: (id: number) => appClient.Post(`api/filters/${id}/restore`),
+    moveToGroup: (groupID: number, filterIDs: number[]) => appClient.Post("api/filters/move", {
+      body: { group_id: groupID, filter_ids: filterIDs }
+    }),
+    getGroups: () => appClient.Get<FilterGroup[]>("api/filters/groups"),
+    createGroup: (group: FilterGroup) => appClient.Post<FilterGroup>("api/filters/groups", {
+      body: group
+    }),
+    updateGroup: (group: FilterGroup) => appClient.Put<FilterGroup>(`api/filters/groups/${group.id}`, {
+      body: group
+    }),
+    toggleGroupEnable: (id: number, enabled: boolean) => appClient.Put(`api/filters/groups/${id}/enabled`, {
+      body: { enabled }
+    }),
+    deleteGroup: (id: number) => appClient.Delete(`api/filters/groups/${id}`),
+    dryRun: (req: FilterDryRunRequest) => appClient.Post<FilterDryRunResponse>("api/filters/dry-run", {
+      body: req
+    })
   },
   feeds: {
     find: () => appClient.Get<Feed[]>("api/feeds"),
@@ -373,7 +414,8 @@ export const APIClient = {
     getFile: (file: string) => appClient.Get(`api/logs/files/${file}`)
   },
   events: {
-    logs: () => new EventSource(`${sseBaseUrl()}api/events?stream=logs`, { withCredentials: true })
+    logs: () => new EventSource(`${sseBaseUrl()}api/events?stream=logs`, { withCredentials: true }),
+    live: () => new EventSource(`${sseBaseUrl()}api/events?stream=live`, { withCredentials: true })
   },
   notifications: {
     getAll: () => appClient.Get<ServiceNotification[]>("api/notification"),
@@ -387,7 +429,27 @@ export const APIClient = {
     delete: (id: number) => appClient.Delete(`api/notification/${id}`),
     test: (notification: ServiceNotification) => appClient.Post("api/notification/test", {
       body: notification
-    })
+    }),
+    deliveries: (status?: NotificationDeliveryStatus) => appClient.Get<NotificationDelivery[]>("api/notification/deliveries", {
+      queryString: status ? { status } : undefined
+    }),
+    resendDelivery: (id: number) => appClient.Post(`api/notification/deliveries/${id}/resend`),
+    deleteDelivery: (id: number) => appClient.Delete(`api/notification/deliveries/${id}`)
+  },
+  lists: {
+    list: () => appClient.Get<List[]>("api/lists"),
+    getByID: (id: number) => appClient.Get<List>(`api/lists/${id}`),
+    store: (list: ListCreate) => appClient.Post("api/lists", {
+      body: list
+    }),
+    update: (list: List) => appClient.Put(`api/lists/${list.id}`, {
+      body: list
+    }),
+    delete: (id: number) => appClient.Delete(`api/lists/${id}`),
+    toggleEnable: (id: number, enabled: boolean) => appClient.Patch(`api/lists/${id}/enabled`, {
+      body: { enabled }
+    }),
+    refresh: (id: number) => appClient.Post(`api/lists/${id}/refresh`)
   },
   proxy: {
     list: () => appClient.Get<Proxy[]>("api/proxy"),
@@ -439,17 +501,21 @@ export const APIClient = {
     indexerOptions: () => appClient.Get<string[]>("api/release/indexers"),
     stats: () => appClient.Get<ReleaseStats>("api/release/stats"),
     delete: (params: DeleteParams) => {
-      return appClient.Delete("api/release", {
+      return appClient.Delete<DeleteReleaseResponse>("api/release", {
         queryString: {
           olderThan: params.olderThan,
           indexer: params.indexers,
+          filter: params.filterIds,
           releaseStatus: params.releaseStatuses,
         }
       });
     },
     replayAction: (releaseId: number, actionId: number) => appClient.Post(
       `api/release/${releaseId}/actions/${actionId}/retry`
-    )
+    ),
+    simulate: (req: ReleaseSimulateReq) => appClient.Post<ReleaseSimulateResponse>("api/release/simulate", {
+      body: req
+    })
   },
   updates: {
     check: () => appClient.Get("api/updates/check"),
@@ -7,13 +7,15 @@ import { queryOptions } from "@tanstack/react-query";
 import { APIClient } from "@api/APIClient";
 import {
   ApiKeys,
+  AuditKeys,
   DownloadClientKeys,
   FeedKeys,
   FilterKeys,
   IndexerKeys,
-  IrcKeys, NotificationKeys, ProxyKeys,
+  IrcKeys, ListKeys, NotificationKeys, ProxyKeys,
   ReleaseKeys,
-  SettingsKeys
+  SettingsKeys,
+  UserKeys
 } from "@api/query_keys";
 
 export const FiltersQueryOptions = (indexers: string[], sortOrder: string) =>
@@ -104,29 +106,40 @@ export const ApikeysQueryOptions = () =>
     refetchOnWindowFocus: false,
   });
 
+export const AuditQueryOptions = (offset: number, limit: number) =>
+  queryOptions({
+    queryKey: AuditKeys.list(offset, limit),
+    queryFn: () => APIClient.audit.find(offset, limit),
+    refetchOnWindowFocus: false,
+  });
+
+export const UsersQueryOptions = () =>
+  queryOptions({
+    queryKey: UserKeys.lists(),
+    queryFn: () => APIClient.users.getAll(),
+    refetchOnWindowFocus: false,
+  });
+
 export const ReleasesListQueryOptions = (offset: number, limit: number, filters: ReleaseFilter[]) =>
   queryOptions({
     queryKey: ReleaseKeys.list(offset, limit, filters),
     queryFn: () => APIClient.release.findQuery(offset, limit, filters),
     staleTime: 5000,
-    refetchOnWindowFocus: true,
-    refetchInterval: 15000 // refetch releases table on releases page every 15s
+    refetchOnWindowFocus: true
   });
 
 export const ReleasesLatestQueryOptions = () =>
   queryOptions({
     queryKey: ReleaseKeys.latestActivity(),
     queryFn: () => APIClient.release.findRecent(),
-    refetchOnWindowFocus: true,
-    refetchInterval: 15000  // refetch recent activity table on dashboard page every 15s
+    refetchOnWindowFocus: true
   });
 
 export const ReleasesStatsQueryOptions = () =>
   queryOptions({
     queryKey: ReleaseKeys.stats(),
     queryFn: () => APIClient.release.stats(),
-    refetchOnWindowFocus: true,
-    refetchInterval: 15000  // refetch stats on dashboard page every 15s
+    refetchOnWindowFocus: true
   });
 
 // ReleasesIndexersQueryOptions get basic list of used indexers by identifier
@@ -155,6 +168,13 @@ export const ProxiesQueryOptions = () =>
     refetchOnWindowFocus: false
   });
 
+export const ListsQueryOptions = () =>
+  queryOptions({
+    queryKey: ListKeys.lists(),
+    queryFn: () => APIClient.lists.list(),
+    refetchOnWindowFocus: false
+  });
+
 export const ProxyByIdQueryOptions = (proxyId: number) =>
   queryOptions({
     queryKey: ProxyKeys.detail(proxyId),
@@ -40,6 +40,17 @@ export const ApiKeys = {
   detail: (id: string) => [...ApiKeys.details(), id] as const
 };
 
+export const AuditKeys = {
+  all: ["audit"] as const,
+  lists: () => [...AuditKeys.all, "list"] as const,
+  list: (offset: number, limit: number) => [...AuditKeys.lists(), { offset, limit }] as const
+};
+
+export const UserKeys = {
+  all: ["users"] as const,
+  lists: () => [...UserKeys.all, "list"] as const
+};
+
 export const DownloadClientKeys = {
   all: ["download_clients"] as const,
   lists: () => [...DownloadClientKeys.all, "list"] as const,
@@ -87,3 +98,10 @@ export const ProxyKeys = {
   details: () => [...ProxyKeys.all, "detail"] as const,
   detail: (id: number) => [...ProxyKeys.details(), id] as const
 };
+
+export const ListKeys = {
+  all: ["lists"] as const,
+  lists: () => [...ListKeys.all, "list"] as const,
+  details: () => [...ListKeys.all, "detail"] as const,
+  detail: (id: number) => [...ListKeys.details(), id] as const
+};
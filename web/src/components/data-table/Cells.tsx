@@ -315,11 +315,22 @@ export const ReleaseStatusCell = ({ value }: ReleaseStatusCellProps) => (
             <CellLine title="Client">{v.client}</CellLine>
             <CellLine title="Filter">{v.filter}</CellLine>
             <CellLine title="Time">{simplifyDate(v.timestamp)}</CellLine>
+            {v.completed_at ? (
+              <CellLine title="Completed">{simplifyDate(v.completed_at)}</CellLine>
+            ) : null}
+            {v.removed_at ? (
+              <CellLine title="Removed">{simplifyDate(v.removed_at)}</CellLine>
+            ) : null}
             {v.rejections.length ? (
               <CellLine title="Rejected">
                 {v.rejections.toString()}
               </CellLine>
             ) : null}
+            {v.reannounce?.length ? (
+              <CellLine title="Reannounce">
+                {v.reannounce.map((a) => `#${a.attempt} ${a.working ? "working" : "not working"}${a.message ? ` (${a.message})` : ""}`).join("\n")}
+              </CellLine>
+            ) : null}
           </div>
         </Tooltip>
       </div>
@@ -3,7 +3,7 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-import { useEffect } from "react";
+import { ChangeEvent, useEffect } from "react";
 import { Field, FieldProps, useFormikContext } from "formik";
 import { EyeIcon, EyeSlashIcon, CheckCircleIcon, XCircleIcon } from "@heroicons/react/24/solid";
 import TextareaAutosize from "react-textarea-autosize";
@@ -474,6 +474,8 @@ interface TextAreaAutoResizeProps {
   disabled?: boolean;
   tooltip?: JSX.Element;
   className?: string;
+  // lines edits a string array field, one value per line
+  lines?: boolean;
 }
 
 export const TextAreaAutoResize = ({
@@ -487,7 +489,8 @@ export const TextAreaAutoResize = ({
   hidden,
   tooltip,
   disabled,
-  className = ""
+  className = "",
+  lines
 }: TextAreaAutoResizeProps) => (
   <div
     className={classNames(
@@ -507,11 +510,16 @@ export const TextAreaAutoResize = ({
     <Field name={name}>
       {({
         field,
-        meta
+        meta,
+        form
       }: FieldProps) => (
         <div>
           <TextareaAutosize
             {...field}
+            {...(lines && {
+              value: (field.value ?? []).join("\n"),
+              onChange: (e: ChangeEvent<HTMLTextAreaElement>) => form.setFieldValue(name, e.target.value.split("\n"))
+            })}
             id={name}
             rows={rows}
             maxRows={10}
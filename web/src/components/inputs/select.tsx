@@ -141,6 +141,61 @@ export const IndexerMultiSelect = ({
   </div>
 );
 
+interface DownloadClientMultiSelectProps {
+  name: string;
+  label: string;
+  action: Action;
+  clients: DownloadClient[];
+  tooltip?: JSX.Element;
+}
+
+// DownloadClientMultiSelect selects client ids of the action type in order, other than the client of the action
+export const DownloadClientMultiSelect = ({
+  name,
+  label,
+  action,
+  clients,
+  tooltip
+}: DownloadClientMultiSelectProps) => {
+  const options = clients
+    .filter((c) => c.type === action.type && c.id !== action.client_id)
+    .map((c) => ({ value: c.id, label: c.name }));
+
+  return (
+    <div className="col-span-12 sm:col-span-6">
+      <label
+        htmlFor={name} className="flex ml-px mb-1 text-xs font-bold tracking-wide text-gray-700 uppercase dark:text-gray-100">
+        <div className="flex">
+          {tooltip ? (
+            <DocsTooltip label={label}>{tooltip}</DocsTooltip>
+          ) : label}
+        </div>
+      </label>
+
+      <Field name={name} type="select" multiple={true}>
+        {({
+          field,
+          form: { setFieldValue }
+        }: FieldProps) => (
+          <RMSC
+            {...field}
+            options={options}
+            labelledBy={name}
+            hasSelectAll={false}
+            value={(field.value ?? []).map((id: number) => ({
+              value: id,
+              label: clients.find((c) => c.id === id)?.name ?? `${id}`
+            }))}
+            onChange={(values: MultiSelectOption[]) => {
+              setFieldValue(field.name, values.map((v) => v.value));
+            }}
+          />
+        )}
+      </Field>
+    </div>
+  );
+};
+
 interface DownloadClientSelectProps {
   name: string;
   action: Action;
@@ -251,7 +306,7 @@ export function DownloadClientSelect({
 
 export interface SelectFieldOption {
   label: string;
-  value: string;
+  value: string | number;
 }
 
 export interface SelectFieldProps {
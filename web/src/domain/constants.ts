@@ -115,6 +115,12 @@ export const sourcesMusic = [
 
 export const SOURCES_MUSIC_OPTIONS: MultiSelectOption[] = sourcesMusic.map(v => ({ value: v, label: v, key: v }));
 
+export const SMART_MUSIC_MATCH_OPTIONS: MultiSelectOption[] = [
+  { value: "format", label: "Format", key: "format" },
+  { value: "bitrate", label: "Bitrate", key: "bitrate" },
+  { value: "media", label: "Media", key: "media" }
+];
+
 export const qualityMusic = [
   "192",
   "256",
@@ -258,6 +264,16 @@ export const DownloadClientTypeOptions: RadioFieldsetOption[] = [
     description: "Add torrents directly to Porla",
     value: "PORLA"
   },
+  {
+    label: "aria2",
+    description: "Add torrents directly to aria2",
+    value: "ARIA2"
+  },
+  {
+    label: "Download Station",
+    description: "Add torrents directly to Synology Download Station",
+    value: "DOWNLOAD_STATION"
+  },
   {
     label: "Radarr",
     description: "Send to Radarr and let it decide",
@@ -283,11 +299,37 @@ export const DownloadClientTypeOptions: RadioFieldsetOption[] = [
     description: "Send to Readarr and let it decide",
     value: "READARR"
   },
+  {
+    label: "Mylar3",
+    description: "Queue the comic issue in Mylar3",
+    value: "MYLAR"
+  },
+  {
+    label: "Plex",
+    description: "Check the Plex library in filters",
+    value: "PLEX"
+  },
+  {
+    label: "Jellyfin",
+    description: "Check the Jellyfin library in filters",
+    value: "JELLYFIN"
+  },
   {
     label: "SABnzbd",
     description: "Add nzbs directly to SABnzbd",
     value: "SABNZBD",
     type: "nzb"
+  },
+  {
+    label: "NZBGet",
+    description: "Add nzbs directly to NZBGet",
+    value: "NZBGET",
+    type: "nzb"
+  },
+  {
+    label: "Remote watch dir",
+    description: "Upload torrents and nzbs to a watch directory over SFTP, FTP or WebDAV",
+    value: "REMOTE_WATCH_FOLDER"
   }
 ];
 
@@ -302,12 +344,17 @@ export const ActionTypeOptions: RadioFieldsetOption[] = [
   { label: "rTorrent", description: "Add torrents directly to rTorrent", value: "RTORRENT" },
   { label: "Transmission", description: "Add torrents directly to Transmission", value: "TRANSMISSION" },
   { label: "Porla", description: "Add torrents directly to Porla", value: "PORLA" },
+  { label: "aria2", description: "Add torrents directly to aria2", value: "ARIA2" },
+  { label: "Download Station", description: "Add torrents directly to Synology Download Station", value: "DOWNLOAD_STATION" },
   { label: "Radarr", description: "Send to Radarr and let it decide", value: "RADARR" },
   { label: "Sonarr", description: "Send to Sonarr and let it decide", value: "SONARR" },
   { label: "Lidarr", description: "Send to Lidarr and let it decide", value: "LIDARR" },
   { label: "Whisparr", description: "Send to Whisparr and let it decide", value: "WHISPARR" },
   { label: "Readarr", description: "Send to Readarr and let it decide", value: "READARR" },
-  { label: "SABnzbd", description: "Add to SABnzbd", value: "SABNZBD" }
+  { label: "Mylar3", description: "Queue the comic issue in Mylar3", value: "MYLAR" },
+  { label: "SABnzbd", description: "Add to SABnzbd", value: "SABNZBD" },
+  { label: "NZBGet", description: "Add to NZBGet", value: "NZBGET" },
+  { label: "Remote watch dir", description: "Upload to a watch directory over SFTP, FTP or WebDAV", value: "REMOTE_WATCH_FOLDER" }
 ];
 
 export const ActionTypeNameMap: Record<ActionType, string> = {
@@ -321,12 +368,19 @@ export const ActionTypeNameMap: Record<ActionType, string> = {
   "RTORRENT": "rTorrent",
   "TRANSMISSION": "Transmission",
   "PORLA": "Porla",
+  "ARIA2": "aria2",
+  "DOWNLOAD_STATION": "Download Station",
   "RADARR": "Radarr",
   "SONARR": "Sonarr",
   "LIDARR": "Lidarr",
   "WHISPARR": "Whisparr",
   "READARR": "Readarr",
-  "SABNZBD": "SABnzbd"
+  "MYLAR": "Mylar3",
+  "PLEX": "Plex",
+  "JELLYFIN": "Jellyfin",
+  "SABNZBD": "SABnzbd",
+  "NZBGET": "NZBGet",
+  "REMOTE_WATCH_FOLDER": "Remote watch folder"
 } as const;
 
 export const DOWNLOAD_CLIENTS = [
@@ -336,12 +390,17 @@ export const DOWNLOAD_CLIENTS = [
   "RTORRENT",
   "TRANSMISSION",
   "PORLA",
+  "ARIA2",
+  "DOWNLOAD_STATION",
   "RADARR",
   "SONARR",
   "LIDARR",
   "WHISPARR",
   "READARR",
-  "SABNZBD"
+  "MYLAR",
+  "SABNZBD",
+  "NZBGET",
+  "REMOTE_WATCH_FOLDER"
 ];
 
 export const ActionContentLayoutOptions: SelectGenericOption<ActionContentLayout>[] = [
@@ -350,12 +409,56 @@ export const ActionContentLayoutOptions: SelectGenericOption<ActionContentLayout
   { label: "Don't create subfolder", description: "Don't create subfolder", value: "SUBFOLDER_NONE" }
 ];
 
+export const ActionRunConditionOptions: SelectGenericOption<ActionRunCondition>[] = [
+  { label: "Always", description: "Always run", value: "ALWAYS" },
+  { label: "On success", description: "Run when the previous action pushed the release", value: "ON_SUCCESS" },
+  { label: "On failure", description: "Run when the previous action was rejected or failed", value: "ON_FAILURE" }
+];
+
+export const ActionClientPoolModeOptions: SelectGenericOption<ActionClientPoolMode>[] = [
+  { label: "Failover order", description: "Always use the client first", value: "" },
+  { label: "Round-robin", description: "Rotate between the clients", value: "ROUND_ROBIN" },
+  { label: "Least active", description: "Use the client with the least active torrents", value: "LEAST_ACTIVE" }
+];
+
+export const ActionValidatePrivateOptions: SelectGenericOption<ActionValidatePrivate>[] = [
+  { label: "Don't check", description: "Push torrents with any private flag", value: "" },
+  { label: "Private", description: "Reject torrents that are not private", value: "PRIVATE" },
+  { label: "Public", description: "Reject private torrents", value: "PUBLIC" }
+];
+
+export const ActionCrossSeedOptions: SelectGenericOption<ActionCrossSeed>[] = [
+  { label: "Disabled", description: "Don't search for cross-seeds", value: "" },
+  { label: "On push", description: "Search once the torrent is pushed", value: "ON_PUSH" },
+  { label: "On completion", description: "Search once the client finished downloading", value: "ON_COMPLETE" }
+];
+
+export const ActionCompletionHookTypeOptions: SelectGenericOption<ActionCompletionHookType>[] = [
+  { label: "Exec", description: "Run a program", value: "EXEC" },
+  { label: "Webhook", description: "Send a request to an endpoint", value: "WEBHOOK" },
+  { label: "Plex scan", description: "Scan a Plex library section", value: "PLEX_SCAN" },
+  { label: "Autoscan", description: "Scan the path through Autoscan", value: "AUTOSCAN" },
+  { label: "Jellyfin scan", description: "Scan the path in Jellyfin", value: "JELLYFIN_SCAN" }
+];
+
 export const ActionPriorityOptions: SelectGenericOption<ActionPriorityLayout>[] = [
   { label: "Top of queue", description: "Top of queue", value: "MAX" },
   { label: "Bottom of queue", description: "Bottom of queue", value: "MIN" },
   { label: "Disabled", description: "Disabled", value: "" }
 ];
 
+export const ActionDownloadStationPriorityOptions: SelectGenericOption<ActionPriorityLayout>[] = [
+  { label: "High", description: "High", value: "MAX" },
+  { label: "Low", description: "Low", value: "MIN" },
+  { label: "Normal", description: "Normal", value: "" }
+];
+
+export const ActionNzbgetPriorityOptions: SelectGenericOption<ActionPriorityLayout>[] = [
+  { label: "High", description: "High", value: "MAX" },
+  { label: "Low", description: "Low", value: "MIN" },
+  { label: "Normal", description: "Normal", value: "" }
+];
+
 export const ActionRtorrentRenameOptions: SelectGenericOption<ActionContentLayout>[] = [
   { label: "No", description: "No", value: "ORIGINAL" },
   { label: "Yes", description: "Yes", value: "SUBFOLDER_NONE" }
@@ -415,10 +518,18 @@ export const NotificationTypeOptions: OptionBasicTyped<NotificationType>[] = [
     label: "Shoutrrr",
     value: "SHOUTRRR"
   },
+  {
+    label: "Slack",
+    value: "SLACK"
+  },
   {
     label: "Telegram",
     value: "TELEGRAM"
   },
+  {
+    label: "Webhook",
+    value: "WEBHOOK"
+  },
 ];
 
 export const IrcAuthMechanismTypeOptions: OptionBasicTyped<IrcAuthMechanism>[] = [
@@ -531,6 +642,41 @@ export const EventOptions: SelectOption[] = [
     value: "IRC_RECONNECTED",
     description: "Reconnected to irc network after error"
   },
+  {
+    label: "IRC Channel Parted",
+    value: "IRC_CHANNEL_PARTED",
+    description: "Left an irc announce channel without it being requested"
+  },
+  {
+    label: "IRC Channel Kicked",
+    value: "IRC_CHANNEL_KICKED",
+    description: "Kicked from an irc announce channel"
+  },
+  {
+    label: "Feed Error",
+    value: "FEED_ERROR",
+    description: "Feed could not be fetched, sent once until it recovers"
+  },
+  {
+    label: "Download Client Unhealthy",
+    value: "DOWNLOAD_CLIENT_UNHEALTHY",
+    description: "Download client could not be reached, sent once until it recovers"
+  },
+  {
+    label: "Delayed Release Expired",
+    value: "DELAYED_RELEASE_EXPIRED",
+    description: "Delay of a delayed release passed and it is being processed"
+  },
+  {
+    label: "Size Mismatch",
+    value: "SIZE_MISMATCH",
+    description: "Size reported by the download client differs from the announced size"
+  },
+  {
+    label: "Download Complete",
+    value: "DOWNLOAD_COMPLETE",
+    description: "Download client finished downloading a pushed torrent"
+  },
   {
     label: "New update",
     value: "APP_UPDATE_AVAILABLE",
@@ -538,6 +684,60 @@ export const EventOptions: SelectOption[] = [
   }
 ];
 
+export const QuietHoursModeOptions: SelectGenericOption<QuietHoursMode>[] = [
+  {
+    label: "Suppress",
+    value: "SUPPRESS",
+    description: "Drop events during quiet hours"
+  },
+  {
+    label: "Defer",
+    value: "DEFER",
+    description: "Send the events as one digest when quiet hours end"
+  }
+];
+
+export const APIKeyScopeOptions: SelectGenericOption<APIKeyScope>[] = [
+  {
+    label: "Read",
+    value: "read",
+    description: "Read everything except API keys and logs"
+  },
+  {
+    label: "Releases write",
+    value: "releases:write",
+    description: "Delete, replay and approve releases"
+  },
+  {
+    label: "Filters write",
+    value: "filters:write",
+    description: "Create, update and delete filters and their actions"
+  },
+  {
+    label: "Admin",
+    value: "admin",
+    description: "Full control of the instance"
+  }
+];
+
+export const UserRoleOptions: SelectGenericOption<UserRole>[] = [
+  {
+    label: "Admin",
+    value: "admin",
+    description: "Full control, including users, API keys and logs"
+  },
+  {
+    label: "Operator",
+    value: "operator",
+    description: "Edit filters and actions, manage releases"
+  },
+  {
+    label: "Read-only",
+    value: "read-only",
+    description: "View everything except users, API keys and logs"
+  }
+];
+
 export const FeedDownloadTypeOptions: OptionBasicTyped<FeedDownloadType>[] = [
   {
     label: "Magnet",
@@ -562,12 +762,14 @@ export const tagsMatchLogicOptions: OptionBasic[] = [
 
 export const ExternalFilterTypeOptions: RadioFieldsetOption[] = [
   { label: "Exec", description: "Run a custom command", value: "EXEC" },
-  { label: "Webhook", description: "Run webhook", value: "WEBHOOK" }
+  { label: "Webhook", description: "Run webhook", value: "WEBHOOK" },
+  { label: "Webhook verdict", description: "Send release to webhook and use its verdict", value: "WEBHOOK_VERDICT" }
 ];
 
 export const ExternalFilterTypeNameMap = {
   "EXEC": "Exec",
-  "WEBHOOK": "Webhook"
+  "WEBHOOK": "Webhook",
+  "WEBHOOK_VERDICT": "Webhook verdict"
 };
 
 export const ExternalFilterWebhookMethodOptions: OptionBasicTyped<WebhookMethod>[] = [
@@ -584,3 +786,20 @@ export const ProxyTypeOptions: OptionBasicTyped<ProxyType>[] = [
     value: "SOCKS5"
   },
 ];
+
+export const ListTypeOptions: OptionBasicTyped<ListType>[] = [
+  { label: "Trakt", value: "TRAKT" },
+  { label: "MDBList", value: "MDBLIST" },
+  { label: "IMDb", value: "IMDB" },
+  { label: "Plaintext", value: "PLAINTEXT" },
+  { label: "Sonarr", value: "SONARR" },
+  { label: "Radarr", value: "RADARR" },
+  { label: "Lidarr", value: "LIDARR" },
+  { label: "Readarr", value: "READARR" }
+];
+
+export const ListFilterFieldOptions: OptionBasicTyped<ListFilterField>[] = [
+  { label: "Shows", value: "SHOWS" },
+  { label: "Match releases", value: "MATCH_RELEASES" },
+  { label: "Except releases", value: "EXCEPT_RELEASES" }
+];
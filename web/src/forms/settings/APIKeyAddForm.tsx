@@ -14,6 +14,7 @@ import { Field, Form, Formik, FormikErrors, FormikValues } from "formik";
 import { APIClient } from "@api/APIClient";
 import { ApiKeys } from "@api/query_keys";
 import { DEBUG } from "@components/debug";
+import { APIKeyScopeOptions } from "@domain/constants";
 import Toast from "@components/notifications/Toast";
 
 interface apiKeyAddFormProps {
@@ -35,7 +36,11 @@ export function APIKeyAddForm({ isOpen, toggle }: apiKeyAddFormProps) {
     }
   });
 
-  const handleSubmit = (data: unknown) => mutation.mutate(data as APIKey);
+  const handleSubmit = (data: FormikValues) => mutation.mutate({
+    ...data,
+    // the date input is local midnight, the key expires at the end of that day
+    expires_at: data.expires_at ? new Date(`${data.expires_at}T23:59:59`).toISOString() : null
+  } as APIKey);
   const validate = (values: FormikValues) => {
     const errors = {} as FormikErrors<FormikValues>;
     if (!values.name) {
@@ -62,7 +67,8 @@ export function APIKeyAddForm({ isOpen, toggle }: apiKeyAddFormProps) {
                 <Formik
                   initialValues={{
                     name: "",
-                    scopes: []
+                    scopes: ["read"],
+                    expires_at: ""
                   }}
                   onSubmit={handleSubmit}
                   validate={validate}
@@ -122,6 +128,67 @@ export function APIKeyAddForm({ isOpen, toggle }: apiKeyAddFormProps) {
                               )}
                             </Field>
                           </div>
+
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <label className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2">
+                                Scopes
+                              </label>
+                              <p className="text-xs text-gray-500 dark:text-gray-400">
+                                Keys without scopes have full access.
+                              </p>
+                            </div>
+                            <fieldset className="sm:col-span-2 space-y-3">
+                              <legend className="sr-only">Scopes</legend>
+                              {APIKeyScopeOptions.map((s) => (
+                                <div key={s.value} className="relative flex items-start">
+                                  <div className="flex items-center h-5">
+                                    <Field
+                                      id={`scopes-${s.value}`}
+                                      name="scopes"
+                                      type="checkbox"
+                                      value={s.value}
+                                      className="focus:ring-blue-500 h-4 w-4 text-blue-600 border-gray-300 rounded"
+                                    />
+                                  </div>
+                                  <div className="ml-3 text-sm">
+                                    <label htmlFor={`scopes-${s.value}`} className="font-medium text-gray-900 dark:text-gray-100">
+                                      {s.label}
+                                    </label>
+                                    <p className="text-gray-500">{s.description}</p>
+                                  </div>
+                                </div>
+                              ))}
+                            </fieldset>
+                          </div>
+
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <label
+                                htmlFor="expires_at"
+                                className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2"
+                              >
+                                Expires
+                              </label>
+                              <p className="text-xs text-gray-500 dark:text-gray-400">
+                                Leave empty to never expire.
+                              </p>
+                            </div>
+                            <Field name="expires_at">
+                              {({ field }: FieldProps) => (
+                                <div className="sm:col-span-2">
+                                  <input
+                                    {...field}
+                                    id="expires_at"
+                                    type="date"
+                                    className="block w-full shadow-sm sm:text-sm focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 rounded-md border-gray-300 dark:border-gray-700 bg-gray-100 dark:bg-gray-815 dark:text-gray-100"
+                                  />
+                                </div>
+                              )}
+                            </Field>
+                          </div>
                         </div>
                       </div>
 
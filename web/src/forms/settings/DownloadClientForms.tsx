@@ -46,6 +46,8 @@ interface InitialValuesSettings {
     ignore_slow_torrents_condition?: IgnoreTorrentsCondition;
     download_speed_threshold?: number;
     max_active_downloads?: number;
+    min_free_space?: number;
+    free_space_path?: string;
   };
   external_download_client_id?: number;
   external_download_client?: string;
@@ -147,6 +149,21 @@ function FormFieldsArr() {
   );
 }
 
+function FormFieldsMediaServer() {
+  return (
+    <div className="flex flex-col space-y-4 px-1 mb-4 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        required
+        name="host"
+        label="Host"
+        help="Full url http(s)://domain.ltd:port, eg. http://localhost:32400 for Plex or http://localhost:8096 for Jellyfin"
+      />
+
+      <PasswordFieldWide required name="settings.apikey" label="Token or API key" help="Plex token or Jellyfin API key" />
+    </div>
+  );
+}
+
 function FormFieldsQbit() {
   const {
     values: { port, tls, settings }
@@ -240,6 +257,43 @@ function FormFieldsPorla() {
   );
 }
 
+function FormFieldsAria2() {
+  const {
+    values: { tls, settings }
+  } = useFormikContext<InitialValues>();
+
+  return (
+    <div className="flex flex-col space-y-4 px-1 py-6 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        required
+        name="host"
+        label="Host"
+        help="Eg. http(s)://client.domain.ltd, http(s)://domain.ltd/aria2/jsonrpc, http://domain.ltd:6800"
+      />
+
+      <SwitchGroupWide name="tls" label="TLS" />
+
+      <PasswordFieldWide name="settings.apikey" label="RPC secret" help="The rpc-secret of aria2, if set" />
+
+      {tls && (
+        <SwitchGroupWide
+          name="tls_skip_verify"
+          label="Skip TLS verification (insecure)"
+        />
+      )}
+
+      <SwitchGroupWide name="settings.basic.auth" label="Basic auth" />
+
+      {settings.basic?.auth === true && (
+        <>
+          <TextFieldWide name="settings.basic.username" label="Username" />
+          <PasswordFieldWide name="settings.basic.password" label="Password" />
+        </>
+      )}
+    </div>
+  );
+}
+
 function FormFieldsRTorrent() {
   const {
     values: { tls, settings }
@@ -333,6 +387,112 @@ function FormFieldsTransmission() {
   );
 }
 
+function FormFieldsDownloadStation() {
+  const {
+    values: { tls, settings }
+  } = useFormikContext<InitialValues>();
+
+  return (
+    <div className="flex flex-col space-y-4 px-1 py-6 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        required
+        name="host"
+        label="Host"
+        help="Eg. http(s)://nas.domain.ltd:5001, the address of DSM"
+      />
+
+      <SwitchGroupWide name="tls" label="TLS" />
+
+      {tls && (
+        <SwitchGroupWide
+          name="tls_skip_verify"
+          label="Skip TLS verification (insecure)"
+        />
+      )}
+
+      <TextFieldWide required name="username" label="Username" help="A DSM user with access to Download Station, 2-step verification is not supported" />
+      <PasswordFieldWide required name="password" label="Password" />
+
+      <SwitchGroupWide name="settings.basic.auth" label="Basic auth" />
+
+      {settings.basic?.auth === true && (
+        <>
+          <TextFieldWide name="settings.basic.username" label="Username" />
+          <PasswordFieldWide name="settings.basic.password" label="Password" />
+        </>
+      )}
+    </div>
+  );
+}
+
+function FormFieldsNzbget() {
+  const {
+    values: { tls }
+  } = useFormikContext<InitialValues>();
+
+  return (
+    <div className="flex flex-col space-y-4 px-1 py-6 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        required
+        name="host"
+        label="Host"
+        help="Eg. http://ip:6789 or https://url.com/nzbget"
+      />
+
+      <SwitchGroupWide name="tls" label="TLS" />
+
+      {tls && (
+        <SwitchGroupWide
+          name="tls_skip_verify"
+          label="Skip TLS verification (insecure)"
+        />
+      )}
+
+      <TextFieldWide name="username" label="Username" help="ControlUsername of NZBGet" />
+      <PasswordFieldWide name="password" label="Password" help="ControlPassword of NZBGet" />
+    </div>
+  );
+}
+
+function FormFieldsRemoteWatchFolder() {
+  const {
+    values: { host, tls_skip_verify }
+  } = useFormikContext<InitialValues>();
+
+  return (
+    <div className="flex flex-col space-y-4 px-1 py-6 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        required
+        name="host"
+        label="URL"
+        help="Eg. sftp://host:22/watch, ftp://host/watch, ftps://host/watch or https://host/webdav/watch"
+        tooltip={
+          <div>
+            <p>The protocol is picked from the URL. The watch directory of the actions is relative to the path of the URL, unless it starts with a /.</p>
+          </div>
+        }
+      />
+
+      <TextFieldWide name="username" label="Username" />
+      <PasswordFieldWide name="password" label="Password" />
+
+      <SwitchGroupWide
+        name="tls_skip_verify"
+        label="Skip verification (insecure)"
+        description="Skip the TLS certificate verification for FTPS and HTTPS, and the host key verification for SFTP"
+      />
+
+      {host?.startsWith("sftp://") && !tls_skip_verify && (
+        <TextFieldWide
+          name="settings.apikey"
+          label="Host key fingerprint"
+          help="SHA256 fingerprint of the SSH host key, eg. SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"
+        />
+      )}
+    </div>
+  );
+}
+
 function FormFieldsSabnzbd() {
   const {
     values: { port, tls, settings }
@@ -401,15 +561,45 @@ export const componentMap: componentMapType = {
   RTORRENT: <FormFieldsRTorrent />,
   TRANSMISSION: <FormFieldsTransmission />,
   PORLA: <FormFieldsPorla />,
+  ARIA2: <FormFieldsAria2 />,
+  DOWNLOAD_STATION: <FormFieldsDownloadStation />,
   RADARR: <FormFieldsArr />,
   SONARR: <FormFieldsArr />,
   LIDARR: <FormFieldsArr />,
   WHISPARR: <FormFieldsArr />,
   READARR: <FormFieldsArr />,
-  SABNZBD: <FormFieldsSabnzbd />
+  MYLAR: <FormFieldsArr />,
+  PLEX: <FormFieldsMediaServer />,
+  JELLYFIN: <FormFieldsMediaServer />,
+  SABNZBD: <FormFieldsSabnzbd />,
+  NZBGET: <FormFieldsNzbget />,
+  REMOTE_WATCH_FOLDER: <FormFieldsRemoteWatchFolder />
 };
 
-function FormFieldsRulesBasic() {
+function FormFieldsRulesFreeSpace() {
+  return (
+    <>
+      <NumberFieldWide
+        name="settings.rules.min_free_space"
+        label="Min free space"
+        placeholder="in GB"
+        tooltip={<p>Reject releases which would leave less free space on the disk of the client, in GB (0 is disabled). The rejection is shown in the action status.</p>}
+      />
+      <TextFieldWide
+        name="settings.rules.free_space_path"
+        label="Free space path"
+        placeholder="Optional. Queried from the client when empty"
+        tooltip={<p>Check the free space of this path instead of asking the client. The path must be accessible by autobrr, e.g. a mount of the download disk.</p>}
+      />
+    </>
+  );
+}
+
+interface FormFieldsRulesBasicProps {
+  freeSpace?: boolean;
+}
+
+function FormFieldsRulesBasic({ freeSpace }: FormFieldsRulesBasicProps) {
   const {
     values: { settings }
   } = useFormikContext<InitialValues>();
@@ -441,6 +631,10 @@ function FormFieldsRulesBasic() {
           }
         />
       )}
+
+      {settings && settings.rules?.enabled === true && freeSpace && (
+        <FormFieldsRulesFreeSpace />
+      )}
     </div>
   );
 }
@@ -530,6 +724,8 @@ function FormFieldsRulesQbit() {
               />
             </>
           )}
+
+          <FormFieldsRulesFreeSpace />
         </>
       )}
     </div>
@@ -569,6 +765,8 @@ function FormFieldsRulesTransmission() {
               </>
             }
           />
+
+          <FormFieldsRulesFreeSpace />
         </>
       )}
     </div>
@@ -576,10 +774,13 @@ function FormFieldsRulesTransmission() {
 }
 
 export const rulesComponentMap: componentMapType = {
-  DELUGE_V1: <FormFieldsRulesBasic />,
-  DELUGE_V2: <FormFieldsRulesBasic />,
+  DELUGE_V1: <FormFieldsRulesBasic freeSpace />,
+  DELUGE_V2: <FormFieldsRulesBasic freeSpace />,
   QBITTORRENT: <FormFieldsRulesQbit />,
   PORLA: <FormFieldsRulesBasic />,
+  ARIA2: <FormFieldsRulesBasic />,
+  DOWNLOAD_STATION: <FormFieldsRulesBasic />,
+  NZBGET: <FormFieldsRulesBasic />,
   TRANSMISSION: <FormFieldsRulesTransmission />,
   RADARR: <FormFieldsRulesArr />,
   SONARR: <FormFieldsRulesArr />,
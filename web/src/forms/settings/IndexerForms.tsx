@@ -419,6 +419,9 @@ export function IndexerAddForm({ isOpen, toggle }: AddProps) {
           ircMutation.mutate(network);
         }
       });
+    } else {
+      // indexers without a network or feed, eg. the mock indexer
+      mutation.mutate(formData as Indexer);
     }
   };
 
@@ -721,6 +724,11 @@ interface IndexerUpdateInitialValues {
   base_url: string;
   use_proxy?: boolean;
   proxy_id?: number;
+  identity: {
+    user_agent: string;
+    cookie: string;
+  };
+  identity_headers: string;
   settings: {
     api_key?: string;
     api_user?: string;
@@ -754,7 +762,15 @@ export function IndexerUpdateForm({ isOpen, toggle, indexer }: UpdateProps) {
 
   const onSubmit = (data: unknown) => {
     // TODO clear data depending on type
-    mutation.mutate(data as Indexer);
+    const { identity_headers, ...indexer } = data as IndexerUpdateInitialValues;
+
+    mutation.mutate({
+      ...indexer,
+      identity: {
+        ...indexer.identity,
+        headers: parseIdentityHeaders(identity_headers)
+      }
+    } as unknown as Indexer);
   };
 
   const deleteMutation = useMutation({
@@ -820,6 +836,13 @@ export function IndexerUpdateForm({ isOpen, toggle, indexer }: UpdateProps) {
     base_url: indexer.base_url,
     use_proxy: indexer.use_proxy,
     proxy_id: indexer.proxy_id,
+    identity: {
+      user_agent: indexer.identity?.user_agent ?? "",
+      cookie: indexer.identity?.cookie ?? ""
+    },
+    identity_headers: Object.entries(indexer.identity?.headers ?? {})
+      .map(([name, value]) => `${name}: ${value}`)
+      .join("\n"),
     settings: indexer.settings?.reduce(
       (o: Record<string, string>, obj: IndexerSetting) => ({
         ...o,
@@ -915,8 +938,68 @@ export function IndexerUpdateForm({ isOpen, toggle, indexer }: UpdateProps) {
               </div>
             )}
           </div>
+
+          <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+            <div className="space-y-1 px-4">
+              <DialogTitle className="text-lg font-medium text-gray-900 dark:text-white">
+                Request identity
+              </DialogTitle>
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                Sent with downloads of .torrent files and feeds. Some trackers only allow specific clients.
+              </p>
+            </div>
+
+            <TextFieldWide
+              name="identity.user_agent"
+              label="User-Agent"
+              help="Leave empty to use the default autobrr User-Agent."
+            />
+            <PasswordFieldWide
+              name="identity.cookie"
+              label="Cookie"
+              help="Only used when the indexer or feed has no cookie set."
+            />
+            <div className="space-y-1 p-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4">
+              <label
+                htmlFor="identity_headers"
+                className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2"
+              >
+                Headers
+              </label>
+              <Field name="identity_headers">
+                {({ field }: FieldProps) => (
+                  <div className="sm:col-span-2">
+                    <textarea
+                      {...field}
+                      id="identity_headers"
+                      rows={3}
+                      placeholder="X-Header: value"
+                      className="block w-full shadow-sm sm:text-sm focus:ring-blue-500 focus:border-blue-500 border-gray-300 dark:border-gray-700 bg-gray-100 dark:bg-gray-815 dark:text-gray-100 rounded-md"
+                    />
+                    <p className="mt-2 text-sm text-gray-500 dark:text-gray-400">One header per line.</p>
+                  </div>
+                )}
+              </Field>
+            </div>
+          </div>
         </div>
       )}
     </SlideOver>
   );
 }
+
+// parseIdentityHeaders parses "Name: value" lines into a map of headers
+function parseIdentityHeaders(value: string): Record<string, string> {
+  const headers: Record<string, string> = {};
+
+  value.split("\n").forEach((line) => {
+    const idx = line.indexOf(":");
+    if (idx < 1) {
+      return;
+    }
+
+    headers[line.slice(0, idx).trim()] = line.slice(idx + 1).trim();
+  });
+
+  return headers;
+}
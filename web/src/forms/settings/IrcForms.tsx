@@ -270,6 +270,8 @@ interface IrcNetworkUpdateFormValues {
     use_bouncer: boolean;
     bouncer_addr: string;
     bot_mode: boolean;
+    status_command: string;
+    status_nicks: string;
     channels: Array<IrcChannel>;
     use_proxy: boolean;
     proxy_id: number;
@@ -330,6 +332,8 @@ export function IrcNetworkUpdateForm({
     use_bouncer: network.use_bouncer,
     bouncer_addr: network.bouncer_addr,
     bot_mode: network.bot_mode,
+    status_command: network.status_command,
+    status_nicks: network.status_nicks,
     channels: network.channels,
     use_proxy: network.use_proxy,
     proxy_id: network.proxy_id,
@@ -395,6 +399,17 @@ export function IrcNetworkUpdateForm({
 
           <SwitchGroupWide name="bot_mode" label="IRCv3 Bot Mode"/>
 
+          <TextFieldWide
+            name="status_command"
+            label="Status command"
+            help="Reply with the connection health when this command is sent in a channel. Eg: !status. Leave empty to disable."
+          />
+          <TextFieldWide
+            name="status_nicks"
+            label="Status nicks"
+            help="Comma separated nicks allowed to use the status command. Leave empty to only allow channel operators."
+          />
+
           <div className="border-t border-gray-200 dark:border-gray-700 py-4">
             <div className="flex justify-between px-4">
               <div className="space-y-1">
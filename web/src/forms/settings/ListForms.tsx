@@ -0,0 +1,227 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { toast } from "react-hot-toast";
+
+import { APIClient } from "@api/APIClient";
+import { ListKeys } from "@api/query_keys";
+import { DownloadClientsQueryOptions, FiltersQueryOptions } from "@api/queries";
+import { IndexerMultiSelect, MultiSelect, NumberFieldWide, PasswordFieldWide, SwitchGroupWide, TextFieldWide } from "@components/inputs";
+import { SelectFieldBasic } from "@components/inputs/select_wide";
+import { ListFilterFieldOptions, ListTypeOptions } from "@domain/constants";
+import Toast from "@components/notifications/Toast";
+import { SlideOver } from "@components/panels";
+
+interface AddFormProps {
+  isOpen: boolean;
+  toggle: () => void;
+}
+
+interface UpdateFormProps<T> {
+  isOpen: boolean;
+  toggle: () => void;
+  data: T;
+}
+
+const arrListTypes: ListType[] = ["SONARR", "RADARR", "LIDARR", "READARR"];
+
+function ListFormFields({ type }: { type: ListType }) {
+  const filtersQuery = useQuery(FiltersQueryOptions([], ""));
+  const filterOptions = filtersQuery.data?.map((f) => ({ value: f.id, label: f.name })) ?? [];
+
+  const clientsQuery = useQuery(DownloadClientsQueryOptions());
+  const clientOptions = (clientsQuery.data ?? [])
+    .filter((client) => client.type === type)
+    .map((client) => ({ label: client.name, value: client.id }));
+
+  const isArr = arrListTypes.includes(type);
+
+  return (
+    <div className="py-6 space-y-4 divide-y divide-gray-200 dark:divide-gray-700">
+      <SwitchGroupWide name="enabled" label="Enabled" />
+
+      <TextFieldWide name="name" label="Name" defaultValue="" required={true} />
+
+      <SelectFieldBasic
+        name="type"
+        label="List type"
+        required={true}
+        options={ListTypeOptions}
+        tooltip={<span>Trakt and MDBList lists use their api, IMDb lists are read from the list export and plaintext lists have one title per line. Arr lists are the monitored series, movies, artists or authors of the client.</span>}
+      />
+
+      {isArr ? (
+        <>
+          <SelectFieldBasic
+            name="client_id"
+            label="Client"
+            required={true}
+            options={clientOptions}
+          />
+
+          <div className="px-4 py-4 space-y-4">
+            <MultiSelect
+              name="tags_include"
+              label="Include tags"
+              options={[]}
+              creatable={true}
+              tooltip={<span>Only sync items with one of these tags. Leave empty to sync all monitored items.</span>}
+            />
+            <MultiSelect
+              name="tags_exclude"
+              label="Exclude tags"
+              options={[]}
+              creatable={true}
+              tooltip={<span>Skip items with one of these tags.</span>}
+            />
+          </div>
+        </>
+      ) : (
+        <TextFieldWide
+          name="url"
+          label="URL"
+          required={true}
+          help={type === "TRAKT" ? "Eg. https://api.trakt.tv/users/justin/lists/imdb-top-rated-movies/items" : "URL of the list"}
+          autoComplete="off"
+        />
+      )}
+
+      {(type === "TRAKT" || type === "MDBLIST") && (
+        <PasswordFieldWide
+          name="api_key"
+          label={type === "TRAKT" ? "Client ID" : "API key"}
+          required={type === "TRAKT"}
+          help={type === "TRAKT" ? "Client ID of a Trakt API app" : "Needed for private lists"}
+          autoComplete="off"
+        />
+      )}
+
+      <SelectFieldBasic
+        name="filter_field"
+        label="Filter field"
+        required={true}
+        options={ListFilterFieldOptions}
+        help="The titles of the list replace this field of the filters"
+      />
+
+      <div className="px-4 py-4">
+        <IndexerMultiSelect name="filters" label="Filters" options={filterOptions} />
+      </div>
+
+      <NumberFieldWide
+        name="refresh_interval"
+        label="Refresh interval"
+        help="Minutes between refreshes, at least 15"
+        required={true}
+      />
+    </div>
+  );
+}
+
+export function ListAddForm({ isOpen, toggle }: AddFormProps) {
+  const queryClient = useQueryClient();
+
+  const createMutation = useMutation({
+    mutationFn: (req: ListCreate) => APIClient.lists.store(req),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ListKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body="List added!" t={t} />);
+      toggle();
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="List could not be added" t={t} />);
+    }
+  });
+
+  const onSubmit = (formData: unknown) => createMutation.mutate(formData as ListCreate);
+
+  const initialValues: ListCreate = {
+    enabled: true,
+    name: "",
+    type: "TRAKT",
+    url: "",
+    api_key: "",
+    client_id: 0,
+    tags_include: [],
+    tags_exclude: [],
+    filter_field: "SHOWS",
+    filters: [],
+    refresh_interval: 60
+  };
+
+  return (
+    <SlideOver<ListCreate>
+      type="CREATE"
+      title="List"
+      isOpen={isOpen}
+      toggle={toggle}
+      onSubmit={onSubmit}
+      initialValues={initialValues}
+    >
+      {(values) => <ListFormFields type={values.type} />}
+    </SlideOver>
+  );
+}
+
+export function ListUpdateForm({ isOpen, toggle, data }: UpdateFormProps<List>) {
+  const queryClient = useQueryClient();
+
+  const updateMutation = useMutation({
+    mutationFn: (req: List) => APIClient.lists.update(req),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ListKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`List ${data.name} updated!`} t={t} />);
+      toggle();
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="List could not be updated" t={t} />);
+    }
+  });
+
+  const onSubmit = (formData: unknown) => updateMutation.mutate(formData as List);
+
+  const deleteMutation = useMutation({
+    mutationFn: (listId: number) => APIClient.lists.delete(listId),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ListKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`List ${data.name} was deleted.`} t={t} />);
+    }
+  });
+
+  const deleteFn = () => deleteMutation.mutate(data.id);
+
+  const initialValues: List = {
+    id: data.id,
+    enabled: data.enabled,
+    name: data.name,
+    type: data.type,
+    url: data.url,
+    api_key: data.api_key,
+    client_id: data.client_id,
+    tags_include: data.tags_include ?? [],
+    tags_exclude: data.tags_exclude ?? [],
+    filter_field: data.filter_field,
+    filters: data.filters,
+    refresh_interval: data.refresh_interval
+  };
+
+  return (
+    <SlideOver<List>
+      type="UPDATE"
+      title="List"
+      initialValues={initialValues}
+      onSubmit={onSubmit}
+      deleteAction={deleteFn}
+      isOpen={isOpen}
+      toggle={toggle}
+    >
+      {(values) => <ListFormFields type={values.type} />}
+    </SlideOver>
+  );
+}
@@ -14,13 +14,13 @@ import { toast } from "react-hot-toast";
 
 import { APIClient } from "@api/APIClient";
 import { NotificationKeys } from "@api/query_keys";
-import { EventOptions, NotificationTypeOptions, SelectOption } from "@domain/constants";
+import { EventOptions, NotificationTypeOptions, QuietHoursModeOptions, SelectOption } from "@domain/constants";
 import { DEBUG } from "@components/debug";
 import { SlideOver } from "@components/panels";
 import { ExternalLink } from "@components/ExternalLink";
 import Toast from "@components/notifications/Toast";
 import * as common from "@components/inputs/common";
-import { NumberFieldWide, PasswordFieldWide, SwitchGroupWide, TextFieldWide } from "@components/inputs";
+import { NumberFieldWide, PasswordFieldWide, RadioFieldsetWide, SwitchGroupWide, TextArea, TextFieldWide } from "@components/inputs";
 
 import { componentMapType } from "./DownloadClientForms";
 
@@ -217,6 +217,11 @@ function FormFieldsGotify() {
         help="Application Token"
         required={true}
       />
+      <NumberFieldWide
+        name="priority"
+        label="Priority"
+        help="0 (min) to 10 (max). Push errors and IRC disconnects are sent with at least 8 (high)"
+      />
     </div>
   );
 }
@@ -295,6 +300,174 @@ function FormFieldsShoutrrr() {
   );
 }
 
+function FormFieldsSlack() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <DialogTitle className="text-lg font-medium text-gray-900 dark:text-white">
+          Settings
+        </DialogTitle>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          {"Create an "}
+          <ExternalLink
+            href="https://api.slack.com/messaging/webhooks"
+            className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400"
+          >
+            incoming webhook
+          </ExternalLink>
+          {", or use the bot token of a Slack app with the chat:write scope and a channel."}
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="webhook"
+        label="Webhook URL"
+        help="Slack incoming webhook url"
+        placeholder="https://hooks.slack.com/services/xx/xx/xx"
+      />
+      <PasswordFieldWide
+        name="token"
+        label="Bot Token"
+        help="Used when no webhook url is set"
+        placeholder="xoxb-..."
+      />
+      <TextFieldWide
+        name="channel"
+        label="Channel"
+        help="Channel name or id for the bot token"
+        placeholder="#releases"
+      />
+    </div>
+  );
+}
+
+function FormFieldsWebhook() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <DialogTitle className="text-lg font-medium text-gray-900 dark:text-white">
+          Settings
+        </DialogTitle>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          POSTs the selected events as JSON. The event is sent in the <code>X-Autobrr-Event</code> header
+          and, with a secret, the HMAC-SHA256 of the body in <code>X-Autobrr-Signature</code> as <code>sha256=hex</code>.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="webhook"
+        label="URL"
+        help="Webhook url"
+        placeholder="https://automation.example.com/hooks/autobrr"
+        required={true}
+      />
+      <PasswordFieldWide
+        name="secret"
+        label="Secret"
+        help="HMAC signing secret"
+      />
+      <div className="px-4 space-y-4 py-4">
+        <TextArea
+          name="headers"
+          label="Headers"
+          rows={3}
+          placeholder={"Authorization: Bearer token\nX-Custom: value"}
+          tooltip={<p>Custom headers, one <code>Name: value</code> per line.</p>}
+        />
+        <TextArea
+          name="template"
+          label="Body template"
+          rows={6}
+          placeholder={'{"text": {{ printf "%s: %s" .Title .ReleaseName | toJson }}}'}
+          tooltip={
+            <div>
+              <p>Go template of the JSON body, leave empty to send all fields. Fields are Event, Title, Subject, Message, Body, ReleaseName, Filter, Indexer, InfoHash, Size, Status, Action, ActionType, ActionClient, Rejections, Protocol, Implementation and Timestamp. Use <code>toJson</code> to quote values.</p>
+            </div>
+          }
+        />
+      </div>
+    </div>
+  );
+}
+
+interface MessageTemplateFieldsProps {
+  events: NotificationEvent[];
+}
+
+// MessageTemplateFields replaces the default title and message of the selected events
+const MessageTemplateFields = ({ events }: MessageTemplateFieldsProps) => {
+  if (!events || events.length === 0) {
+    return null;
+  }
+
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <DialogTitle className="text-lg font-medium text-gray-900 dark:text-white">
+          Message templates
+        </DialogTitle>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Replace the default title and message of an event, leave empty to keep the default.
+          Use the <ExternalLink href="https://autobrr.com/filters/macros" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">macros</ExternalLink> of
+          the release, and Event, Status, Action, ActionType, ActionClient, Rejections, Subject and Message of the notification.
+          The title is used by agents which show one.
+        </p>
+      </div>
+
+      {EventOptions.filter((e) => events.includes(e.value as NotificationEvent)).map((e) => (
+        <div key={e.value} className="px-4 space-y-2 py-2">
+          <TextArea
+            name={`message_templates.${e.value}.title`}
+            label={`${e.label} title`}
+            rows={1}
+            placeholder="Grabbed {{ .Title }} ({{ .Year }})"
+          />
+          <TextArea
+            name={`message_templates.${e.value}.message`}
+            label={`${e.label} message`}
+            rows={3}
+            placeholder="{{ .TorrentName }} from {{ .IndexerName }} by {{ .FilterName }}"
+          />
+        </div>
+      ))}
+    </div>
+  );
+};
+
+interface QuietHoursFieldsProps {
+  enabled?: boolean;
+}
+
+// QuietHoursFields hold back the events of the agent during a time range
+const QuietHoursFields = ({ enabled }: QuietHoursFieldsProps) => (
+  <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+    <div className="px-4 space-y-1">
+      <DialogTitle className="text-lg font-medium text-gray-900 dark:text-white">
+        Quiet hours
+      </DialogTitle>
+      <p className="text-sm text-gray-500 dark:text-gray-400">
+        Suppress events during a time range, or defer them to a digest sent when the quiet hours end.
+      </p>
+    </div>
+
+    <SwitchGroupWide name="quiet_hours_enabled" label="Enabled" />
+
+    {enabled && (
+      <div>
+        <TextFieldWide name="quiet_hours_start" label="Start" placeholder="22:00" help="Time in HH:MM" />
+        <TextFieldWide name="quiet_hours_end" label="End" placeholder="07:00" help="Time in HH:MM, may be past midnight" />
+        <TextFieldWide name="quiet_hours_timezone" label="Timezone" placeholder="Europe/Stockholm" help="IANA timezone, leave empty for the server timezone" />
+        <RadioFieldsetWide name="quiet_hours_mode" legend="Mode" options={QuietHoursModeOptions} />
+        <SwitchGroupWide
+          name="quiet_hours_allow_errors"
+          label="Deliver errors"
+          description="Still send errors like push errors, disconnects and failing feeds"
+        />
+      </div>
+    )}
+  </div>
+);
+
 const componentMap: componentMapType = {
   DISCORD: <FormFieldsDiscord />,
   NOTIFIARR: <FormFieldsNotifiarr />,
@@ -303,6 +476,8 @@ const componentMap: componentMapType = {
   GOTIFY: <FormFieldsGotify />,
   NTFY: <FormFieldsNtfy />,
   SHOUTRRR: <FormFieldsShoutrrr />,
+  SLACK: <FormFieldsSlack />,
+  WEBHOOK: <FormFieldsWebhook />,
   LUNASEA: <FormFieldsLunaSea />
 };
 
@@ -380,7 +555,10 @@ export function NotificationAddForm({ isOpen, toggle }: AddProps) {
                     name: "",
                     webhook: "",
                     events: [],
-                    username: ""
+                    username: "",
+                    message_templates: {},
+                    quiet_hours_enabled: false,
+                    quiet_hours_mode: "SUPPRESS"
                   }}
                   onSubmit={onSubmit}
                   validate={validate}
@@ -496,6 +674,8 @@ export function NotificationAddForm({ isOpen, toggle }: AddProps) {
                           </div>
                         </div>
                         {componentMap[values.type]}
+                        <MessageTemplateFields events={values.events} />
+                        <QuietHoursFields enabled={values.quiet_hours_enabled} />
                       </div>
 
                       <div className="flex-shrink-0 px-4 border-t border-gray-200 dark:border-gray-700 py-4 sm:px-6">
@@ -584,7 +764,17 @@ interface InitialValues {
   topic?: string;
   host?: string;
   events: NotificationEvent[];
-  username?: string
+  username?: string;
+  headers?: string;
+  template?: string;
+  secret?: string;
+  message_templates?: NotificationMessageTemplates;
+  quiet_hours_enabled?: boolean;
+  quiet_hours_start?: string;
+  quiet_hours_end?: string;
+  quiet_hours_timezone?: string;
+  quiet_hours_mode?: QuietHoursMode;
+  quiet_hours_allow_errors?: boolean;
 }
 
 export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateProps) {
@@ -635,7 +825,17 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
     topic: notification.topic,
     host: notification.host,
     events: notification.events || [],
-    username: notification.username
+    username: notification.username,
+    headers: notification.headers,
+    template: notification.template,
+    secret: notification.secret,
+    message_templates: notification.message_templates || {},
+    quiet_hours_enabled: notification.quiet_hours_enabled,
+    quiet_hours_start: notification.quiet_hours_start,
+    quiet_hours_end: notification.quiet_hours_end,
+    quiet_hours_timezone: notification.quiet_hours_timezone,
+    quiet_hours_mode: notification.quiet_hours_mode || "SUPPRESS",
+    quiet_hours_allow_errors: notification.quiet_hours_allow_errors
   };
 
   return (
@@ -721,6 +921,8 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
             </div>
           </div>
           {componentMap[values.type]}
+          <MessageTemplateFields events={values.events} />
+          <QuietHoursFields enabled={values.quiet_hours_enabled} />
         </div>
       )}
     </SlideOver>
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { Fragment } from "react";
+import { useMutation, useQueryClient } from "@tanstack/react-query";
+import { toast } from "react-hot-toast";
+import { XMarkIcon } from "@heroicons/react/24/solid";
+import { Dialog, DialogPanel, DialogTitle, Transition, TransitionChild } from "@headlessui/react";
+import type { FieldProps } from "formik";
+import { Field, Form, Formik, FormikErrors, FormikValues } from "formik";
+
+import { APIClient } from "@api/APIClient";
+import { UserKeys } from "@api/query_keys";
+import { DEBUG } from "@components/debug";
+import { UserRoleOptions } from "@domain/constants";
+import Toast from "@components/notifications/Toast";
+
+interface userAddFormProps {
+  isOpen: boolean;
+  toggle: () => void;
+}
+
+export function UserAddForm({ isOpen, toggle }: userAddFormProps) {
+  const queryClient = useQueryClient();
+
+  const mutation = useMutation({
+    mutationFn: (user: UserCreate) => APIClient.users.create(user),
+    onSuccess: (_, user) => {
+      queryClient.invalidateQueries({ queryKey: UserKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`User ${user.username} was added`} t={t}/>);
+
+      toggle();
+    }
+  });
+
+  const handleSubmit = (data: FormikValues) => mutation.mutate(data as UserCreate);
+  const validate = (values: FormikValues) => {
+    const errors = {} as FormikErrors<FormikValues>;
+    if (!values.username) {
+      errors.username = "Required";
+    }
+    if (!values.password) {
+      errors.password = "Required";
+    }
+    return errors;
+  };
+
+  return (
+    <Transition show={isOpen} as={Fragment}>
+      <Dialog as="div" static className="fixed inset-0 overflow-hidden" open={isOpen} onClose={toggle}>
+        <div className="absolute inset-0 overflow-hidden">
+          <DialogPanel className="absolute inset-y-0 right-0 max-w-full flex">
+            <TransitionChild
+              as={Fragment}
+              enter="transform transition ease-in-out duration-500 sm:duration-700"
+              enterFrom="translate-x-full"
+              enterTo="translate-x-0"
+              leave="transform transition ease-in-out duration-500 sm:duration-700"
+              leaveFrom="translate-x-0"
+              leaveTo="translate-x-full"
+            >
+              <div className="w-screen max-w-2xl border-l dark:border-gray-700">
+                <Formik
+                  initialValues={{
+                    username: "",
+                    password: "",
+                    role: "read-only"
+                  }}
+                  onSubmit={handleSubmit}
+                  validate={validate}
+                >
+                  {({ values }) => (
+                    <Form className="h-full flex flex-col bg-white dark:bg-gray-800 shadow-xl overflow-y-auto">
+                      <div className="flex-1">
+                        <div className="px-4 py-6 bg-gray-50 dark:bg-gray-900 sm:px-6">
+                          <div className="flex items-start justify-between space-x-3">
+                            <div className="space-y-1">
+                              <DialogTitle className="text-lg font-medium text-gray-900 dark:text-white">Add user</DialogTitle>
+                              <p className="text-sm text-gray-500 dark:text-gray-400">
+                                Add a user who logs in with a password.
+                              </p>
+                            </div>
+                            <div className="h-7 flex items-center">
+                              <button
+                                type="button"
+                                className="light:bg-white rounded-md text-gray-400 hover:text-gray-500 focus:outline-none focus:ring-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+                                onClick={toggle}
+                              >
+                                <span className="sr-only">Close panel</span>
+                                <XMarkIcon className="h-6 w-6" aria-hidden="true"/>
+                              </button>
+                            </div>
+                          </div>
+                        </div>
+
+                        <div
+                          className="py-6 space-y-6 sm:py-0 sm:space-y-0 sm:divide-y sm:divide-gray-200">
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <label
+                                htmlFor="username"
+                                className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2"
+                              >
+                                Username
+                              </label>
+                            </div>
+                            <Field name="username">
+                              {({
+                                field,
+                                meta
+                              }: FieldProps) => (
+                                <div className="sm:col-span-2">
+                                  <input
+                                    {...field}
+                                    id="username"
+                                    type="text"
+                                    data-1p-ignore
+                                    autoComplete="off"
+                                    className="block w-full shadow-sm sm:text-sm focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 rounded-md border-gray-300 dark:border-gray-700 bg-gray-100 dark:bg-gray-815 dark:text-gray-100"
+                                  />
+                                  {meta.touched && meta.error && <span className="block mt-2 text-red-500">{meta.error}</span>}
+                                </div>
+                              )}
+                            </Field>
+                          </div>
+
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <label
+                                htmlFor="password"
+                                className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2"
+                              >
+                                Password
+                              </label>
+                            </div>
+                            <Field name="password">
+                              {({
+                                field,
+                                meta
+                              }: FieldProps) => (
+                                <div className="sm:col-span-2">
+                                  <input
+                                    {...field}
+                                    id="password"
+                                    type="password"
+                                    data-1p-ignore
+                                    autoComplete="new-password"
+                                    className="block w-full shadow-sm sm:text-sm focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 rounded-md border-gray-300 dark:border-gray-700 bg-gray-100 dark:bg-gray-815 dark:text-gray-100"
+                                  />
+                                  {meta.touched && meta.error && <span className="block mt-2 text-red-500">{meta.error}</span>}
+                                </div>
+                              )}
+                            </Field>
+                          </div>
+
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <label className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2">
+                                Role
+                              </label>
+                            </div>
+                            <fieldset className="sm:col-span-2 space-y-3">
+                              <legend className="sr-only">Role</legend>
+                              {UserRoleOptions.map((s) => (
+                                <div key={s.value} className="relative flex items-start">
+                                  <div className="flex items-center h-5">
+                                    <Field
+                                      id={`role-${s.value}`}
+                                      name="role"
+                                      type="radio"
+                                      value={s.value}
+                                      className="focus:ring-blue-500 h-4 w-4 text-blue-600 border-gray-300"
+                                    />
+                                  </div>
+                                  <div className="ml-3 text-sm">
+                                    <label htmlFor={`role-${s.value}`} className="font-medium text-gray-900 dark:text-gray-100">
+                                      {s.label}
+                                    </label>
+                                    <p className="text-gray-500">{s.description}</p>
+                                  </div>
+                                </div>
+                              ))}
+                            </fieldset>
+                          </div>
+                        </div>
+                      </div>
+
+                      <div
+                        className="flex-shrink-0 px-4 border-t border-gray-200 dark:border-gray-700 py-5 sm:px-6">
+                        <div className="space-x-3 flex justify-end">
+                          <button
+                            type="button"
+                            className="bg-white dark:bg-gray-800 py-2 px-4 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm text-sm font-medium text-gray-700 dark:text-gray-400 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+                            onClick={toggle}
+                          >
+                            Cancel
+                          </button>
+                          <button
+                            type="submit"
+                            className="inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+                          >
+                            Add
+                          </button>
+                        </div>
+                      </div>
+                      <DEBUG values={values}/>
+                    </Form>
+                  )}
+                </Formik>
+              </div>
+            </TransitionChild>
+          </DialogPanel>
+        </div>
+      </Dialog>
+    </Transition>
+  );
+}
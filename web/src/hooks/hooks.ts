@@ -4,6 +4,9 @@
  */
 
 import { useEffect, useState } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import { APIClient } from "@api/APIClient";
+import { IrcKeys, ReleaseKeys } from "@api/query_keys";
 
 export function useToggle(initialValue = false): [boolean, () => void] {
   const [value, setValue] = useState(initialValue);
@@ -58,3 +61,30 @@ export const useMedia = (query: string, defaultState?: boolean) => {
 
   return state;
 };
+
+// useLiveEvents refreshes the releases and irc networks when the server streams a change instead of polling
+export const useLiveEvents = (enabled: boolean) => {
+  const queryClient = useQueryClient();
+
+  useEffect(() => {
+    if (!enabled) {
+      return;
+    }
+
+    const es = APIClient.events.live();
+
+    const invalidateReleases = () => queryClient.invalidateQueries({ queryKey: ReleaseKeys.all });
+    const invalidateIrc = () => queryClient.invalidateQueries({ queryKey: IrcKeys.all });
+
+    // rejected releases are not stored, only matches change the releases
+    es.addEventListener("release-decision", (e: MessageEvent) => {
+      if (JSON.parse(e.data)?.data?.match) {
+        invalidateReleases();
+      }
+    });
+    es.addEventListener("action-status", invalidateReleases);
+    es.addEventListener("irc-health", invalidateIrc);
+
+    return () => es.close();
+  }, [enabled, queryClient]);
+};
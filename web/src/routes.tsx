@@ -30,8 +30,10 @@ import {
   FilterByIdQueryOptions,
   IndexersQueryOptions,
   IrcQueryOptions,
+  ListsQueryOptions,
   NotificationsQueryOptions,
-  ProxiesQueryOptions
+  ProxiesQueryOptions,
+  UsersQueryOptions
 } from "@api/queries";
 import LogSettings from "@screens/settings/Logs";
 import NotificationSettings from "@screens/settings/Notifications";
@@ -47,13 +49,17 @@ import DownloadClientSettings from "@screens/settings/DownloadClient";
 import FeedSettings from "@screens/settings/Feed";
 import { Dashboard } from "@screens/Dashboard";
 import AccountSettings from "@screens/settings/Account";
+import UserSettings from "@screens/settings/Users";
+import AuditSettings from "@screens/settings/Audit";
 import { AuthContext, SettingsContext } from "@utils/Context";
 import { TanStackRouterDevtools } from "@tanstack/router-devtools";
 import { ReactQueryDevtools } from "@tanstack/react-query-devtools";
 import { queryClient } from "@api/QueryClient";
+import ListSettings from "@screens/settings/Lists";
 import ProxySettings from "@screens/settings/Proxy";
 
 import { ErrorPage } from "@components/alerts";
+import { useLiveEvents } from "@hooks/hooks";
 
 const DashboardRoute = createRoute({
   getParentRoute: () => AuthIndexRoute,
@@ -200,6 +206,13 @@ export const SettingsClientsRoute = createRoute({
   component: DownloadClientSettings
 });
 
+export const SettingsListsRoute = createRoute({
+  getParentRoute: () => SettingsRoute,
+  path: 'lists',
+  loader: (opts) => opts.context.queryClient.ensureQueryData(ListsQueryOptions()),
+  component: ListSettings
+});
+
 export const SettingsNotificationsRoute = createRoute({
   getParentRoute: () => SettingsRoute,
   path: 'notifications',
@@ -227,6 +240,19 @@ export const SettingsReleasesRoute = createRoute({
   component: ReleaseSettings
 });
 
+export const SettingsUsersRoute = createRoute({
+  getParentRoute: () => SettingsRoute,
+  path: 'users',
+  loader: (opts) => opts.context.queryClient.ensureQueryData(UsersQueryOptions()),
+  component: UserSettings
+});
+
+export const SettingsAuditRoute = createRoute({
+  getParentRoute: () => SettingsRoute,
+  path: 'audit',
+  component: AuditSettings
+});
+
 export const SettingsAccountRoute = createRoute({
   getParentRoute: () => SettingsRoute,
   path: 'account',
@@ -302,6 +328,8 @@ export const AuthRoute = createRoute({
 
 function AuthenticatedLayout() {
   const isLoggedIn = AuthContext.useSelector((s) => s.isLoggedIn);
+  useLiveEvents(isLoggedIn);
+
   if (!isLoggedIn) {
     const redirect = (
       location.pathname.length > 1
@@ -348,7 +376,7 @@ export const RootRoute = createRootRouteWithContext<{
 });
 
 const filterRouteTree = FiltersRoute.addChildren([FilterIndexRoute, FilterGetByIdRoute.addChildren([FilterGeneralRoute, FilterMoviesTvRoute, FilterMusicRoute, FilterAdvancedRoute, FilterExternalRoute, FilterActionsRoute])])
-const settingsRouteTree = SettingsRoute.addChildren([SettingsIndexRoute, SettingsLogRoute, SettingsIndexersRoute, SettingsIrcRoute, SettingsFeedsRoute, SettingsClientsRoute, SettingsNotificationsRoute, SettingsApiRoute, SettingsProxiesRoute, SettingsReleasesRoute, SettingsAccountRoute])
+const settingsRouteTree = SettingsRoute.addChildren([SettingsIndexRoute, SettingsLogRoute, SettingsIndexersRoute, SettingsIrcRoute, SettingsFeedsRoute, SettingsClientsRoute, SettingsListsRoute, SettingsNotificationsRoute, SettingsApiRoute, SettingsProxiesRoute, SettingsReleasesRoute, SettingsUsersRoute, SettingsAuditRoute, SettingsAccountRoute])
 const authenticatedTree = AuthRoute.addChildren([AuthIndexRoute.addChildren([DashboardRoute, filterRouteTree, ReleasesRoute, settingsRouteTree, LogsRoute])])
 const routeTree = RootRoute.addChildren([
   authenticatedTree,
@@ -6,14 +6,17 @@
 import {
   BellIcon,
   ChatBubbleLeftRightIcon,
+  ClipboardDocumentListIcon,
   CogIcon,
   FolderArrowDownIcon,
   GlobeAltIcon,
   KeyIcon,
+  ListBulletIcon,
   RectangleStackIcon,
   RssIcon,
   Square3Stack3DIcon,
-  UserCircleIcon
+  UserCircleIcon,
+  UsersIcon
 } from "@heroicons/react/24/outline";
 import { Link, Outlet } from "@tanstack/react-router";
 
@@ -33,8 +36,11 @@ const subNavigation: NavTabType[] = [
   { name: "IRC", href: "/settings/irc", icon: ChatBubbleLeftRightIcon },
   { name: "Feeds", href: "/settings/feeds", icon: RssIcon },
   { name: "Clients", href: "/settings/clients", icon: FolderArrowDownIcon },
+  { name: "Lists", href: "/settings/lists", icon: ListBulletIcon },
   { name: "Notifications", href: "/settings/notifications", icon: BellIcon },
   { name: "API keys", href: "/settings/api", icon: KeyIcon },
+  { name: "Users", href: "/settings/users", icon: UsersIcon },
+  { name: "Audit log", href: "/settings/audit", icon: ClipboardDocumentListIcon },
   { name: "Proxies", href: "/settings/proxies", icon: GlobeAltIcon },
   { name: "Releases", href: "/settings/releases", icon: RectangleStackIcon },
   { name: "Account", href: "/settings/account", icon: UserCircleIcon }
@@ -3,7 +3,7 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-import React, { useEffect } from "react";
+import React, { useEffect, useState } from "react";
 import { useForm } from "react-hook-form";
 import { useMutation, useQueryErrorResetBoundary } from "@tanstack/react-query";
 import { useRouter, useSearch } from "@tanstack/react-router";
@@ -12,6 +12,7 @@ import toast from "react-hot-toast";
 import { RocketLaunchIcon } from "@heroicons/react/24/outline";
 
 import { APIClient } from "@api/APIClient";
+import { baseUrl } from "@utils";
 import Toast from "@components/notifications/Toast";
 import { Tooltip } from "@components/tooltips/Tooltip";
 import { PasswordInput, TextInput } from "@components/inputs/text";
@@ -39,11 +40,29 @@ export const Login = () => {
     mode: "onBlur"
   });
 
+  const [oidcConfig, setOIDCConfig] = useState<OIDCConfig>();
+
   useEffect(() => {
     queryErrorResetBoundary.reset()
     // remove user session when visiting login page
     AuthContext.reset();
-  }, [queryErrorResetBoundary]);
+
+    APIClient.auth.oidcConfig().then((config) => {
+      setOIDCConfig(config);
+
+      // after logging in with oidc the provider sends the user back here with a new session
+      if (config.enabled) {
+        APIClient.auth.user().then((user) => {
+          setAuth({ isLoggedIn: true, username: user.username });
+          router.invalidate();
+        }).catch(() => {
+          // not logged in yet
+        });
+      }
+    }).catch(() => {
+      console.info("could not get oidc config");
+    });
+  }, [queryErrorResetBoundary]); // eslint-disable-line react-hooks/exhaustive-deps
 
   const loginMutation = useMutation({
     mutationFn: (data: LoginFormFields) => APIClient.auth.login(data.username, data.password),
@@ -82,48 +101,60 @@ export const Login = () => {
       </div>
       <div className="mx-auto w-full max-w-md rounded-2xl shadow-lg">
         <div className="px-8 pt-8 pb-4 rounded-2xl bg-white dark:bg-gray-800 border border-gray-150 dark:border-gray-775">
-          <form onSubmit={handleSubmit(onSubmit)} className="space-y-6">
-            <TextInput<LoginFormFields>
-              name="username"
-              id="username"
-              label="username"
-              type="text"
-              register={register}
-              rules={{ required: "Username is required" }}
-              errors={formState.errors}
-              autoComplete="username"
-            />
-            <PasswordInput<LoginFormFields>
-              name="password"
-              id="password"
-              label="password"
-              register={register}
-              rules={{ required: "Password is required" }}
-              errors={formState.errors}
-              autoComplete="current-password"
-            />
-            <button
-              type="submit"
-              className="w-full flex items-center justify-center py-2 px-4 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+          {oidcConfig?.enabled && (
+            <a
+              href={`${baseUrl()}api/auth/oidc/login`}
+              className="w-full mb-6 flex items-center justify-center py-2 px-4 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm text-sm font-medium text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
             >
-              <RocketLaunchIcon className="w-4 h-4 mr-1.5" />
-              Sign in
-            </button>
-          </form>
-          <div
-            id="forgot"
-            className="flex mt-2 justify-end items-center text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide"
-          >
-            <Tooltip
-              label={
-                <div className="flex flex-row items-center cursor-pointer">
-                  Forgot? <svg className="ml-1 w-3 h-3 text-gray-500 dark:text-gray-400 fill-current" viewBox="0 0 72 72"><path d="M32 2C15.432 2 2 15.432 2 32s13.432 30 30 30s30-13.432 30-30S48.568 2 32 2m5 49.75H27v-24h10v24m-5-29.5a5 5 0 1 1 0-10a5 5 0 0 1 0 10" /></svg>
-                </div>
-              }
+              Sign in with OpenID Connect
+            </a>
+          )}
+          {!oidcConfig?.disable_built_in_login && (
+            <form onSubmit={handleSubmit(onSubmit)} className="space-y-6">
+              <TextInput<LoginFormFields>
+                name="username"
+                id="username"
+                label="username"
+                type="text"
+                register={register}
+                rules={{ required: "Username is required" }}
+                errors={formState.errors}
+                autoComplete="username"
+              />
+              <PasswordInput<LoginFormFields>
+                name="password"
+                id="password"
+                label="password"
+                register={register}
+                rules={{ required: "Password is required" }}
+                errors={formState.errors}
+                autoComplete="current-password"
+              />
+              <button
+                type="submit"
+                className="w-full flex items-center justify-center py-2 px-4 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+              >
+                <RocketLaunchIcon className="w-4 h-4 mr-1.5" />
+                Sign in
+              </button>
+            </form>
+          )}
+          {!oidcConfig?.disable_built_in_login && (
+            <div
+              id="forgot"
+              className="flex mt-2 justify-end items-center text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide"
             >
-              <p className="py-1">If you forget your password you can reset it via the terminal: <code>autobrrctl --config /home/username/.config/autobrr change-password $USERNAME</code></p>
-            </Tooltip>
-          </div>
+              <Tooltip
+                label={
+                  <div className="flex flex-row items-center cursor-pointer">
+                    Forgot? <svg className="ml-1 w-3 h-3 text-gray-500 dark:text-gray-400 fill-current" viewBox="0 0 72 72"><path d="M32 2C15.432 2 2 15.432 2 32s13.432 30 30 30s30-13.432 30-30S48.568 2 32 2m5 49.75H27v-24h10v24m-5-29.5a5 5 0 1 1 0-10a5 5 0 0 1 0 10" /></svg>
+                  </div>
+                }
+              >
+                <p className="py-1">If you forget your password you can reset it via the terminal: <code>autobrrctl --config /home/username/.config/autobrr change-password $USERNAME</code></p>
+              </Tooltip>
+            </div>
+          )}
         </div>
       </div>
     </div>
@@ -185,8 +185,13 @@ const actionSchema = z.object({
   name: z.string(),
   type: z.enum(["TEST", "EXEC", "WATCH_FOLDER", "WEBHOOK", ...DOWNLOAD_CLIENTS]),
   client_id: z.number().optional(),
+  failover_client_ids: z.array(z.number()).optional(),
+  client_pool_mode: z.string().optional(),
   exec_cmd: z.string().optional(),
   exec_args: z.string().optional(),
+  exec_timeout: z.number().optional(),
+  exec_env: z.array(z.string()).optional(),
+  exec_dir: z.string().optional(),
   watch_folder: z.string().optional(),
   category: z.string().optional(),
   tags: z.string().optional(),
@@ -198,14 +203,48 @@ const actionSchema = z.object({
   limit_download_speed: z.number().optional(),
   limit_ratio: z.number().optional(),
   limit_seed_time: z.number().optional(),
+  limit_inactive_seed_time: z.number().optional(),
+  max_connections: z.number().optional(),
   reannounce_skip: z.boolean().optional(),
   reannounce_delete: z.boolean().optional(),
   reannounce_interval: z.number().optional(),
   reannounce_max_attempts: z.number().optional(),
+  reannounce_jitter: z.number().optional(),
+  require_approval: z.boolean().optional(),
+  remove_ratio: z.number().optional(),
+  remove_seed_time: z.number().optional(),
+  remove_after_days: z.number().optional(),
+  remove_delete_data: z.boolean().optional(),
+  run_condition: z.enum(["ALWAYS", "ON_SUCCESS", "ON_FAILURE"]).optional(),
+  validate_torrent: z.boolean().optional(),
+  validate_announce: z.string().optional(),
+  validate_private: z.string().optional(),
+  dupe_key: z.string().optional(),
+  cross_seed: z.enum(["ON_PUSH", "ON_COMPLETE", ""]).optional(),
+  completion_hooks: z.array(z.object({
+    type: z.enum(["EXEC", "WEBHOOK", "PLEX_SCAN"]),
+    exec_cmd: z.string().optional(),
+    exec_args: z.string().optional(),
+    webhook_host: z.string().optional(),
+    webhook_method: z.string().optional(),
+    webhook_data: z.string().optional(),
+    plex_host: z.string().optional(),
+    plex_token: z.string().optional(),
+    plex_section: z.string().optional(),
+    plex_path: z.string().optional()
+  })).optional(),
+  custom1: z.string().optional(),
+  custom2: z.string().optional(),
+  bandwidth_group: z.string().optional(),
+  move_completed_path: z.string().optional(),
   webhook_host: z.string().optional(),
   webhook_type: z.string().optional(),
   webhook_method: z.string().optional(),
-  webhook_data: z.string().optional()
+  webhook_data: z.string().optional(),
+  webhook_headers: z.array(z.string()).optional(),
+  webhook_secret: z.string().optional(),
+  webhook_retry_attempts: z.number().optional(),
+  webhook_retry_delay_seconds: z.number().optional()
 }).superRefine((value, ctx) => {
   if (DOWNLOAD_CLIENTS.includes(value.type)) {
     if (!value.client_id) {
@@ -222,7 +261,7 @@ const externalFilterSchema = z.object({
   enabled: z.boolean(),
   index: z.number(),
   name: z.string(),
-  type: z.enum(["EXEC", "WEBHOOK"]),
+  type: z.enum(["EXEC", "WEBHOOK", "WEBHOOK_VERDICT"]),
   exec_cmd: z.string().optional(),
   exec_args: z.string().optional(),
   exec_expect_status: z.number().optional(),
@@ -233,7 +272,8 @@ const externalFilterSchema = z.object({
   webhook_expect_status: z.number().optional(),
   webhook_retry_status: z.string().optional(),
   webhook_retry_attempts: z.number().optional(),
-  webhook_retry_delay_seconds: z.number().optional()
+  webhook_retry_delay_seconds: z.number().optional(),
+  webhook_timeout_seconds: z.number().optional()
 }).superRefine((value, ctx) => {
   if (!value.name) {
     ctx.addIssue({
@@ -243,6 +283,14 @@ const externalFilterSchema = z.object({
     });
   }
 
+  if (value.type == "WEBHOOK_VERDICT" && !value.webhook_host) {
+    ctx.addIssue({
+      message: "Must have webhook host",
+      code: z.ZodIssueCode.custom,
+      path: ["webhook_host"]
+    });
+  }
+
   if (value.type == "WEBHOOK") {
     if (!value.webhook_method) {
       ctx.addIssue({
@@ -288,6 +336,8 @@ const schema = z.object({
   name: z.string(),
   max_downloads: z.number().optional(),
   max_downloads_unit: z.string().optional(),
+  max_downloads_size: z.string().optional(),
+  max_downloads_size_unit: z.string().optional(),
   indexers: z.array(indexerSchema).min(1, { message: "Must select at least one indexer" }),
   actions: z.array(actionSchema),
   external: z.array(externalFilterSchema)
@@ -301,6 +351,13 @@ const schema = z.object({
       });
     }
   }
+  if (value.max_downloads_size && !value.max_downloads_size_unit) {
+    ctx.addIssue({
+      message: "Must select Max Downloads Size Per unit when Max Downloads Size is set",
+      code: z.ZodIssueCode.custom,
+      path: ["max_downloads_size_unit"]
+    });
+  }
 });
 
 export const FilterDetails = () => {
@@ -392,10 +449,23 @@ export const FilterDetails = () => {
               enabled: filter.enabled,
               min_size: filter.min_size,
               max_size: filter.max_size,
+              max_files: filter.max_files,
+              match_file_extensions: filter.match_file_extensions,
+              except_file_extensions: filter.except_file_extensions,
+              sonarr_check_client_id: filter.sonarr_check_client_id,
+              radarr_check_client_id: filter.radarr_check_client_id,
+              lidarr_check_client_id: filter.lidarr_check_client_id,
+              library_check_client_id: filter.library_check_client_id,
+              match_ids: filter.match_ids,
+              match_musicbrainz: filter.match_musicbrainz,
               delay: filter.delay,
               priority: filter.priority,
+              group_id: filter.group_id,
               max_downloads: filter.max_downloads,
               max_downloads_unit: filter.max_downloads_unit,
+              max_downloads_size: filter.max_downloads_size,
+              max_downloads_size_unit: filter.max_downloads_size_unit,
+              skip_dedupe: filter.skip_dedupe,
               use_regex: filter.use_regex || false,
               shows: filter.shows,
               years: filter.years,
@@ -412,6 +482,11 @@ export const FilterDetails = () => {
               seasons: filter.seasons,
               episodes: filter.episodes,
               smart_episode: filter.smart_episode,
+              ignore_season_packs: filter.ignore_season_packs,
+              upgrade_replace: filter.upgrade_replace,
+              smart_music: filter.smart_music,
+              smart_music_match: filter.smart_music_match || [],
+              smart_duplicate_days: filter.smart_duplicate_days,
               match_releases: filter.match_releases,
               except_releases: filter.except_releases,
               match_release_groups: filter.match_release_groups,
@@ -422,8 +497,10 @@ export const FilterDetails = () => {
               match_description: filter.match_description,
               except_description: filter.except_description,
               use_regex_description: filter.use_regex_description,
+              advanced_expression: filter.advanced_expression,
               match_categories: filter.match_categories,
               except_categories: filter.except_categories,
+              normalize_categories: filter.normalize_categories,
               tags: filter.tags,
               except_tags: filter.except_tags,
               tags_match_logic: filter.tags_match_logic,
@@ -450,6 +527,11 @@ export const FilterDetails = () => {
               max_seeders: filter.max_seeders,
               min_leechers: filter.min_leechers,
               max_leechers: filter.max_leechers,
+              breaker_threshold: filter.breaker_threshold,
+              breaker_cooldown: filter.breaker_cooldown,
+              breaker_fail_open: filter.breaker_fail_open,
+              schedule: filter.schedule,
+              group_tiers: filter.group_tiers,
               indexers: filter.indexers || [],
               actions: filter.actions || [],
               external: filter.external || []
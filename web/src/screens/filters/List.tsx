@@ -289,6 +289,8 @@ const FilterItemDropdown = ({ filter, onToggle }: FilterItemDropdownProps) => {
         name: string;
         created_at: Date;
         updated_at: Date;
+        created_by: string;
+        updated_by: string;
         indexers: any;
         actions: any;
         actions_count: any;
@@ -303,6 +305,8 @@ const FilterItemDropdown = ({ filter, onToggle }: FilterItemDropdownProps) => {
       delete completeFilter.id;
       delete completeFilter.created_at;
       delete completeFilter.updated_at;
+      delete completeFilter.created_by;
+      delete completeFilter.updated_by;
       delete completeFilter.actions_count;
       delete completeFilter.actions_enabled_count;
       delete completeFilter.indexers;
@@ -640,6 +644,11 @@ function FilterListItem({ filter, idx }: FilterListItemProps) {
               </Link>
             )}
           </span>
+          {filter.updated_by && (
+            <span className="ml-2 whitespace-nowrap text-xs font-medium text-gray-600 dark:text-gray-400" title={filter.created_by ? `Created by ${filter.created_by}` : undefined}>
+              Updated by: <span className="text-gray-850 dark:text-gray-200">{filter.updated_by}</span>
+            </span>
+          )}
         </div>
       </div>
       <span className="hidden md:flex px-4 whitespace-nowrap text-sm font-medium text-gray-900">
@@ -10,18 +10,24 @@ export const FILTER_FIELDS: Record<string, string> = {
   "priority": "number",
   "log_score": "number",
   "max_downloads": "number",
+  "max_files": "number",
+  "smart_duplicate_days": "number",
   "use_regex": "boolean",
   "scene": "boolean",
   "smart_episode": "boolean",
+  "smart_music": "boolean",
   "freeleech": "boolean",
   "perfect_flac": "boolean",
   "download_duplicates": "boolean",
   "cue": "boolean",
   "log": "boolean",
+  "normalize_categories": "boolean",
   "match_releases": "string",
   "except_releases": "string",
   "match_release_groups": "string",
   "except_release_groups": "string",
+  "match_file_extensions": "string",
+  "except_file_extensions": "string",
   "shows": "string",
   "seasons": "string",
   "episodes": "string",
@@ -38,6 +44,7 @@ export const FILTER_FIELDS: Record<string, string> = {
   "match_sites": "string",
   "except_sites": "string",
   "origins": "[]string",
+  "smart_music_match": "[]string",
   "except_origins": "[]string",
   "bonus": "[]string",
   "resolutions": "[]string",
@@ -58,6 +65,9 @@ export const FILTER_FIELDS: Record<string, string> = {
   "max_seeders": "number",
   "min_leechers": "number",
   "max_leechers": "number",
+  "breaker_threshold": "number",
+  "breaker_cooldown": "number",
+  "breaker_fail_open": "boolean",
 } as const;
 
 export const IRC_FIELDS: Record<string, string> = {
@@ -13,9 +13,17 @@ import { ChevronRightIcon, BoltIcon } from "@heroicons/react/24/solid";
 import { classNames } from "@utils";
 import { useToggle } from "@hooks/hooks";
 import { APIClient } from "@api/APIClient";
-import { ActionTypeNameMap, ActionTypeOptions, DOWNLOAD_CLIENTS } from "@domain/constants";
+import {
+  ActionClientPoolModeOptions,
+  ActionRunConditionOptions,
+  ActionTypeNameMap,
+  ActionTypeOptions,
+  ActionValidatePrivateOptions,
+  ActionCrossSeedOptions,
+  DOWNLOAD_CLIENTS
+} from "@domain/constants";
 
-import { Select, TextField } from "@components/inputs";
+import { DownloadClientMultiSelect, NumberField, Select, SwitchGroup, TextField } from "@components/inputs";
 import { DeleteModal } from "@components/modals";
 import { EmptyListState } from "@components/emptystates";
 import Toast from "@components/notifications/Toast";
@@ -26,10 +34,15 @@ import { TitleSubtitle } from "@components/headings";
 import { DownloadClientsQueryOptions } from "@api/queries";
 import { FilterHalfRow, FilterLayout, FilterPage, FilterSection } from "@screens/filters/sections/_components.tsx";
 import {
+  Aria2,
   Arr,
-  Deluge, Exec,
+  CompletionHooks,
+  Deluge, DownloadStation, Exec,
+  Mylar,
+  Nzbget,
   Porla,
   QBittorrent,
+  RemoteWatchFolder,
   RTorrent,
   SABnzbd, Test,
   Transmission, WatchFolder, WebHook
@@ -67,10 +80,13 @@ export function Actions() {
     limit_download_speed: 0,
     limit_ratio: 0,
     limit_seed_time: 0,
+    limit_inactive_seed_time: 0,
     reannounce_skip: false,
     reannounce_delete: false,
     reannounce_interval: 7,
     reannounce_max_attempts: 25,
+    require_approval: false,
+    validate_torrent: false,
     filter_id: values.id,
     webhook_host: "",
     webhook_type: "",
@@ -159,6 +175,10 @@ const TypeForm = (props: ClientActionProps) => {
     return <Transmission {...props} />;
   case "PORLA":
     return <Porla {...props} />;
+  case "ARIA2":
+    return <Aria2 {...props} />;
+  case "DOWNLOAD_STATION":
+    return <DownloadStation {...props} />;
   // arrs
   case "RADARR":
   case "SONARR":
@@ -166,9 +186,13 @@ const TypeForm = (props: ClientActionProps) => {
   case "WHISPARR":
   case "READARR":
     return <Arr {...props} />;
+  case "MYLAR":
+    return <Mylar {...props} />;
   // nzb
   case "SABNZBD":
     return <SABnzbd {...props} />;
+  case "NZBGET":
+    return <Nzbget {...props} />;
   // autobrr actions
   case "TEST":
     return <Test />;
@@ -176,6 +200,8 @@ const TypeForm = (props: ClientActionProps) => {
     return <Exec {...props} />;
   case "WATCH_FOLDER":
     return <WatchFolder {...props} />;
+  case "REMOTE_WATCH_FOLDER":
+    return <RemoteWatchFolder {...props} />;
   case "WEBHOOK":
     return <WebHook {...props} />;
   default:
@@ -295,11 +321,144 @@ function FilterActionsItem({ action, clients, idx, initialEdit, remove }: Filter
                 <FilterHalfRow>
                   <TextField name={`actions.${idx}.name`} label="Name" />
                 </FilterHalfRow>
+
+                <FilterHalfRow>
+                  <Select
+                    name={`actions.${idx}.run_condition`}
+                    label="Run condition"
+                    optionDefaultText="Always"
+                    options={ActionRunConditionOptions}
+                    tooltip={<div><p>Run this action based on the result of the previous action that ran, for example push to a fallback client only when the first one failed.</p></div>}
+                  />
+                </FilterHalfRow>
+
+                <SwitchGroup
+                  name={`actions.${idx}.require_approval`}
+                  label="Require manual approval"
+                  description="Park matched releases as pending until approved or rejected instead of running this action."
+                  className="pt-2 col-span-12"
+                />
               </FilterLayout>
             </FilterSection>
 
             <TypeForm action={action} clients={clients} idx={idx} />
 
+            {DOWNLOAD_CLIENTS.includes(action.type) && (
+              <FilterSection
+                title="Failover"
+                subtitle="Clients to try in order when the client is unreachable or rejects the release"
+              >
+                <FilterLayout>
+                  <DownloadClientMultiSelect
+                    name={`actions.${idx}.failover_client_ids`}
+                    label="Failover clients"
+                    action={action}
+                    clients={clients}
+                    tooltip={<div><p>The clients are tried in the order they were selected. The client that accepted the release is shown in the action status.</p></div>}
+                  />
+                  <FilterHalfRow>
+                    <Select
+                      name={`actions.${idx}.client_pool_mode`}
+                      label="Client pool"
+                      optionDefaultText="Failover order"
+                      options={ActionClientPoolModeOptions}
+                      tooltip={<div><p>Balance pushes across the client and the failover clients. The other clients are still tried as failover when the picked client is unreachable or rejects the release.</p></div>}
+                    />
+                  </FilterHalfRow>
+                </FilterLayout>
+              </FilterSection>
+            )}
+
+            <FilterSection
+              title="Torrent validation"
+              subtitle="Check the torrent file before it's pushed, releases without a torrent file are not checked"
+            >
+              <FilterLayout>
+                <SwitchGroup
+                  name={`actions.${idx}.validate_torrent`}
+                  label="Validate torrent"
+                  description="Reject torrents announcing to another tracker, without the passkey in the announce url or already pushed for another release."
+                  className="col-span-12"
+                />
+                <FilterHalfRow>
+                  <TextField
+                    name={`actions.${idx}.validate_announce`}
+                    label="Announce hosts"
+                    placeholder="eg. tracker.example.com,example.org"
+                    tooltip={<div><p>Comma separated hosts the announce urls must be on, subdomains included. Defaults to the domains of the indexer url and the download url.</p></div>}
+                  />
+                </FilterHalfRow>
+                <FilterHalfRow>
+                  <Select
+                    name={`actions.${idx}.validate_private`}
+                    label="Private flag"
+                    optionDefaultText="Don't check"
+                    options={ActionValidatePrivateOptions}
+                    tooltip={<div><p>Reject torrents with another private flag, eg. a public torrent from a private tracker.</p></div>}
+                  />
+                </FilterHalfRow>
+              </FilterLayout>
+            </FilterSection>
+
+            <CompletionHooks idx={idx} />
+
+            <FilterSection
+              title="Cross-seed"
+              subtitle="Ask the cross-seed instance from the config to search the other indexers for the release"
+            >
+              <FilterLayout>
+                <FilterHalfRow>
+                  <Select
+                    name={`actions.${idx}.cross_seed`}
+                    label="Search"
+                    optionDefaultText="Disabled"
+                    options={ActionCrossSeedOptions}
+                    tooltip={<div><p>On completion needs completion tracking, which is supported for qBittorrent and Transmission.</p></div>}
+                  />
+                </FilterHalfRow>
+              </FilterLayout>
+            </FilterSection>
+
+            {(action.type === "QBITTORRENT" || action.type === "TRANSMISSION") && (
+              <FilterSection
+                title="Removal policy"
+                subtitle="Remove the torrent from the client once any of the limits is reached, 0 disables a limit"
+              >
+                <FilterLayout>
+                  <FilterHalfRow>
+                    <NumberField
+                      name={`actions.${idx}.remove_ratio`}
+                      label="Remove at ratio"
+                      placeholder="eg. 2.0"
+                      step={0.25}
+                      isDecimal
+                    />
+                  </FilterHalfRow>
+                  <FilterHalfRow>
+                    <NumberField
+                      name={`actions.${idx}.remove_seed_time`}
+                      label="Remove after seed time (minutes)"
+                      placeholder="eg. 10080"
+                    />
+                  </FilterHalfRow>
+                  <FilterHalfRow>
+                    <NumberField
+                      name={`actions.${idx}.remove_after_days`}
+                      label="Remove after days"
+                      placeholder="eg. 30"
+                      tooltip={<div><p>Days after the torrent was pushed to the client.</p></div>}
+                    />
+                  </FilterHalfRow>
+                  <SwitchGroup
+                    name={`actions.${idx}.remove_delete_data`}
+                    label="Delete data"
+                    description="Delete the downloaded data along with the torrent."
+                    className="col-span-12"
+                  />
+                </FilterLayout>
+              </FilterSection>
+            )}
+
             <div className="pt-6 pb-4 flex space-x-2 justify-between">
               <button
                 type="button"
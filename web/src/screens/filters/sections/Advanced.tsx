@@ -185,6 +185,22 @@ const Categories = () => {
           </div>
         }
       />
+      <FilterHalfRow>
+        <SwitchGroup
+          name="normalize_categories"
+          label="Normalize categories"
+          className="py-0"
+          description="Also match categories mapped to the shared taxonomy, eg. Movies, TV/HD or Music/Lossless."
+          tooltip={
+            <div>
+              <p>
+                Raw indexer categories are mapped with the category map of the indexer definition,
+                so one filter works across indexers with different category names.
+              </p>
+            </div>
+          }
+        />
+      </FilterHalfRow>
     </CollapsibleSection>
   );
 }
@@ -559,6 +575,35 @@ const RawReleaseTags = () => {
   );
 }
 
+const Expression = () => {
+  const { values } = useFormikContext<Filter>();
+
+  return (
+    <CollapsibleSection
+      defaultOpen={values.advanced_expression !== undefined && values.advanced_expression !== ""}
+      title="Advanced Expression"
+      subtitle={
+        <>
+          <span className="underline underline-offset-2">Advanced users only</span>
+          {": "}Match releases with an expression, checked after all other fields.
+        </>
+      }
+    >
+      <TextAreaAutoResize
+        name="advanced_expression"
+        label="Expression"
+        columns={12}
+        placeholder={"eg. release.Size > parseSize(\"5GB\") && release.Group in [\"A\", \"B\"] && !release.Proper"}
+        tooltip={
+          <div>
+            <p>A CEL expression. Fields of the release are available as release.Field, eg. release.Title, release.Resolution or release.Indexer.Identifier. Supports && || !, == != &lt; &gt;, in, release.Title.matches("regex"), contains, startsWith, endsWith, lowerAscii and sizes like parseSize("700MB").</p>
+          </div>
+        }
+      />
+    </CollapsibleSection>
+  );
+};
+
 export const Advanced = () => {
   return (
     <div className="flex flex-col w-full gap-y-4 py-2 sm:-mx-1">
@@ -572,6 +617,7 @@ export const Advanced = () => {
       <Origins />
       <FeedSpecific />
       <RawReleaseTags />
+      <Expression />
     </div>
   );
 }
@@ -12,7 +12,7 @@ import { classNames } from "@utils";
 import { useToggle } from "@hooks/hooks";
 import { TextAreaAutoResize } from "@components/inputs/input";
 import { EmptyListState } from "@components/emptystates";
-import { NumberField, Select, TextField } from "@components/inputs";
+import { NumberField, Select, SwitchGroup, TextField } from "@components/inputs";
 import {
   ExternalFilterTypeNameMap,
   ExternalFilterTypeOptions,
@@ -81,10 +81,51 @@ export function External() {
           </>
         )}
       </FieldArray>
+
+      <CircuitBreaker />
     </div>
   );
 }
 
+const CircuitBreaker = () => (
+  <FilterSection
+    title="Circuit breaker"
+    subtitle="Stop calling external filters and indexer APIs for a while after repeated failures. The enrichment and external checks have separate breakers."
+  >
+    <FilterLayout>
+      <NumberField
+        name="breaker_threshold"
+        label="Failure threshold"
+        placeholder="0 to disable"
+        min={0}
+        tooltip={
+          <div>
+            <p>Consecutive failed checks before the breaker opens. Errors and 5xx responses from webhooks count as failures.</p>
+          </div>
+        }
+      />
+      <NumberField
+        name="breaker_cooldown"
+        label="Cooldown (seconds)"
+        placeholder="Default: 300"
+        min={0}
+        tooltip={
+          <div>
+            <p>How long the breaker stays open before a single check is let through again.</p>
+          </div>
+        }
+      />
+      <div className="col-span-12 sm:col-span-6">
+        <SwitchGroup
+          name="breaker_fail_open"
+          label="Fail open"
+          description="Let releases pass while the breaker is open instead of rejecting them."
+        />
+      </div>
+    </FilterLayout>
+  </FilterSection>
+);
+
 interface FilterExternalItemProps {
   external: ExternalFilter;
   idx: number;
@@ -364,6 +405,65 @@ const TypeForm = ({ external, idx }: TypeFormProps) => {
       </>
     );
   }
+  case "WEBHOOK_VERDICT": {
+    return (
+      <>
+        <FilterSection
+          title="Request"
+          subtitle="The release is sent as JSON. Respond with an unexpected status or eg. { \"approve\": false, \"reason\": \"why\" } to reject it"
+        >
+          <FilterLayout>
+            <TextField
+              name={`external.${idx}.webhook_host`}
+              label="Endpoint"
+              columns={6}
+              placeholder="Host eg. http://localhost/verdict"
+              tooltip={<p>URL or IP to your API. Pass params and set API tokens etc.</p>}
+            />
+            <TextField
+              name={`external.${idx}.webhook_headers`}
+              label="HTTP Request Headers"
+              columns={6}
+              placeholder="HEADER=custom1;HEADER2=custom2"
+            />
+            <NumberField
+              name={`external.${idx}.webhook_expect_status`}
+              label="Expected HTTP status code"
+              placeholder="Any 2xx"
+            />
+            <NumberField
+              name={`external.${idx}.webhook_timeout_seconds`}
+              label="Timeout in seconds"
+              placeholder="15"
+            />
+          </FilterLayout>
+        </FilterSection>
+        <FilterSection
+          title="Retry"
+          subtitle="Retry behavior on request failure"
+        >
+          <FilterLayout>
+            <TextField
+              name={`external.${idx}.webhook_retry_status`}
+              label="Retry http status code(s)"
+              placeholder="Retry on status eg. 502, 503"
+              columns={6}
+            />
+            <NumberField
+              name={`external.${idx}.webhook_retry_attempts`}
+              label="Maximum retry attempts"
+              placeholder="10"
+            />
+            <NumberField
+              name={`external.${idx}.webhook_retry_delay_seconds`}
+              label="Retry delay in seconds"
+              placeholder="1"
+            />
+          </FilterLayout>
+        </FilterSection>
+      </>
+    );
+  }
 
   default: {
     return null;
@@ -109,6 +109,30 @@ export const General = () => {
               </div>
             }
           />
+          <TextField
+            name="max_downloads_size"
+            label="Max downloads size"
+            columns={6}
+            placeholder="eg. 500GB (empty is infinite)"
+            tooltip={
+              <div>
+                <p>Total size of the releases this filter may push per unit. A release that does not fit in what is left of the budget is rejected. Supports units such as MB, MiB, GB, etc.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <Select
+            name="max_downloads_size_unit"
+            label="Max downloads size per"
+            options={downloadsPerUnitOptions}
+            optionDefaultText="Select unit"
+            tooltip={
+              <div>
+                <p>The unit of time for the size budget. It resets at the start of the next hour, day, week or month.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
         </FilterLayout>
 
         <FilterLayout>
@@ -118,6 +142,52 @@ export const General = () => {
             description="Enable or disable this filter."
             className="pb-2 col-span-12 sm:col-span-6"
           />
+          <SwitchGroup
+            name="skip_dedupe"
+            label="Skip dedupe"
+            description="Push even when another filter or indexer pushed the same release within the dedupe window."
+            className="pb-2 col-span-12 sm:col-span-6"
+          />
+        </FilterLayout>
+      </FilterSection>
+
+      <FilterSection
+        title="Torrent content"
+        subtitle="Check the file list of the torrent before the actions run. The torrent file is downloaded for the check, magnet links are rejected."
+      >
+        <FilterLayout>
+          <NumberField
+            name="max_files"
+            label="Max files"
+            placeholder="Takes any number (0 is infinite)"
+            tooltip={
+              <div>
+                <p>Reject torrents with more files than this.</p>
+              </div>
+            }
+          />
+          <TextField
+            name="match_file_extensions"
+            label="Match file extensions"
+            columns={6}
+            placeholder="eg. mkv,mp4"
+            tooltip={
+              <div>
+                <p>Comma separated. At least one of the files must have one of these extensions.</p>
+              </div>
+            }
+          />
+          <TextField
+            name="except_file_extensions"
+            label="Except file extensions"
+            columns={6}
+            placeholder="eg. exe,lnk,scr"
+            tooltip={
+              <div>
+                <p>Comma separated. Reject torrents with any file with one of these extensions.</p>
+              </div>
+            }
+          />
         </FilterLayout>
       </FilterSection>
     </FilterPage>
@@ -3,9 +3,12 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+import { useQuery } from "@tanstack/react-query";
+
+import { DownloadClientsQueryOptions } from "@api/queries";
 import { DocsLink } from "@components/ExternalLink";
 import { TextAreaAutoResize } from "@components/inputs/input";
-import { MultiSelect, SwitchGroup, TextField } from "@components/inputs";
+import { MultiSelect, NumberField, Select, SwitchGroup, TextField } from "@components/inputs";
 
 import * as CONSTS from "@domain/constants";
 import {
@@ -89,10 +92,89 @@ const SeasonsAndEpisodes = () => (
           description="Do not match episodes older than the last one matched."
         />
       </div>
+      <div className="col-span-12 sm:col-span-6">
+        <SwitchGroup
+          name="ignore_season_packs"
+          label="Ignore season packs"
+          description="With Smart Episode, still match episodes of a season whose season pack was already grabbed."
+        />
+      </div>
+      <div className="col-span-12 sm:col-span-6">
+        <SwitchGroup
+          name="upgrade_replace"
+          label="Replace with PROPER/REPACK"
+          description="Remove the original torrent from the download client before adding its PROPER or REPACK. Downloaded data is kept. Supported for qBittorrent, Deluge, Transmission and rTorrent."
+        />
+      </div>
+      <NumberField
+        name="smart_duplicate_days"
+        label="Duplicate window (days)"
+        placeholder="0 for no limit"
+        min={0}
+        tooltip={
+          <div>
+            <p>Only grabs within this many days count as duplicates for Smart Episode and Smart Music. Leave at 0 to compare against all grabs.</p>
+          </div>
+        }
+      />
     </FilterLayout>
   </FilterSection>
 );
 
+const ArrChecks = () => {
+  const { data } = useQuery(DownloadClientsQueryOptions());
+
+  const clientOptions = (...types: DownloadClientType[]) => [
+    { label: "Don't check", value: 0 },
+    ...(data ?? [])
+      .filter((client) => types.includes(client.type))
+      .map((client) => ({ label: client.name, value: client.id }))
+  ];
+
+  return (
+    <FilterSection
+      title="Sonarr, Radarr and media server"
+      subtitle="Ask Sonarr or Radarr if it wants the release, or Plex or Jellyfin if it already has it, before the actions run. Checked in the external stage of the pipeline."
+    >
+      <FilterLayout>
+        <Select
+          name="sonarr_check_client_id"
+          label="Wanted in Sonarr"
+          optionDefaultText="Don't check"
+          options={clientOptions("SONARR")}
+          tooltip={
+            <div>
+              <p>Only match releases of monitored series with a monitored episode that is missing or below the quality cutoff.</p>
+            </div>
+          }
+        />
+        <Select
+          name="radarr_check_client_id"
+          label="Wanted in Radarr"
+          optionDefaultText="Don't check"
+          options={clientOptions("RADARR")}
+          tooltip={
+            <div>
+              <p>Only match releases of monitored and available movies that are missing or below the quality cutoff of their profile.</p>
+            </div>
+          }
+        />
+        <Select
+          name="library_check_client_id"
+          label="Not in library"
+          optionDefaultText="Don't check"
+          options={clientOptions("PLEX", "JELLYFIN")}
+          tooltip={
+            <div>
+              <p>Reject movies and episodes the Plex or Jellyfin library already has at the same or a better resolution. Season packs are not checked.</p>
+            </div>
+          }
+        />
+      </FilterLayout>
+    </FilterSection>
+  );
+};
+
 const Quality = () => (
   <FilterSection
     title="Quality"
@@ -236,10 +318,22 @@ export const MoviesTv = () => (
             </div>
           }
         />
+        <TextAreaAutoResize
+          name="match_ids"
+          label="Match IDs"
+          columns={12}
+          placeholder="eg. tt0944947,tmdb:1399,tvdb:121361"
+          tooltip={
+            <div>
+              <p>Only match releases of these movies or shows. The parsed title is resolved to its TMDB, TVDB and IMDb ids with the TMDB api key of the config, bare <code>tt</code> ids are IMDb ids.</p>
+            </div>
+          }
+        />
       </FilterLayout>
     </FilterSection>
 
     <SeasonsAndEpisodes />
+    <ArrChecks />
     <Quality />
   </FilterPage>
 );
@@ -4,10 +4,12 @@
  */
 
 import { useFormikContext } from "formik";
+import { useQuery } from "@tanstack/react-query";
 
+import { DownloadClientsQueryOptions } from "@api/queries";
 import { DocsLink } from "@components/ExternalLink";
 import { FilterLayout, FilterPage, FilterRow, FilterSection } from "./_components";
-import { MultiSelect, NumberField, SwitchGroup, TextAreaAutoResize, TextField } from "@components/inputs";
+import { MultiSelect, NumberField, Select, SwitchGroup, TextAreaAutoResize, TextField } from "@components/inputs";
 
 import * as CONSTS from "@domain/constants";
 
@@ -15,6 +17,15 @@ import * as CONSTS from "@domain/constants";
 export const Music = () => {
   const { values } = useFormikContext<Filter>();
 
+  const { data: clients } = useQuery(DownloadClientsQueryOptions());
+
+  const lidarrOptions = [
+    { label: "Don't check", value: 0 },
+    ...(clients ?? [])
+      .filter((client) => client.type === "LIDARR")
+      .map((client) => ({ label: client.name, value: client.id }))
+  ];
+
   return (
     <FilterPage>
       <FilterSection>
@@ -43,6 +54,17 @@ export const Music = () => {
               </div>
             }
           />
+          <TextAreaAutoResize
+            name="match_musicbrainz"
+            label="Match MusicBrainz"
+            columns={12}
+            placeholder="eg. Daft Punk,056e4f3e-d505-4dad-8ec1-d04f521cbb56"
+            tooltip={
+              <div>
+                <p>Only match releases of these artists or albums. The parsed artist and album are looked up at MusicBrainz, entries are artist or release group MBIDs or canonical artist names.</p>
+              </div>
+            }
+          />
         </FilterLayout>
       </FilterSection>
 
@@ -192,6 +214,43 @@ export const Music = () => {
         </span>
         </FilterLayout>
       </FilterSection>
+
+      <FilterSection
+        title="Duplicates"
+        subtitle="Skip albums by the same artists that were already grabbed"
+      >
+        <FilterLayout>
+          <SwitchGroup
+            name="smart_music"
+            label="Smart Music"
+            description="Do not match albums with the same artists, title and year as an earlier grab."
+            className="col-span-12 sm:col-span-6"
+          />
+          <MultiSelect
+            name="smart_music_match"
+            options={CONSTS.SMART_MUSIC_MATCH_OPTIONS}
+            label="Wanted in more than one"
+            columns={6}
+            disabled={!values.smart_music}
+            tooltip={
+              <div>
+                <p>Albums that differ from the earlier grab in one of these are not duplicates, eg. select Format to grab both the FLAC and the MP3. The duplicate window is set on the Movies and TV tab.</p>
+              </div>
+            }
+          />
+          <Select
+            name="lidarr_check_client_id"
+            label="Wanted in Lidarr"
+            optionDefaultText="Don't check"
+            options={lidarrOptions}
+            tooltip={
+              <div>
+                <p>Only match albums on the wanted/missing list of Lidarr. The list is refreshed every 15 minutes, checked in the external stage of the pipeline.</p>
+              </div>
+            }
+          />
+        </FilterLayout>
+      </FilterSection>
     </FilterPage>
   );
 }
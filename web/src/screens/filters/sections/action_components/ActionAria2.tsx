@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { CollapsibleSection, FilterHalfRow, FilterLayout, FilterSection } from "../_components";
+import { DownloadClientSelect, NumberField, SwitchGroup, TextAreaAutoResize, TextField } from "@components/inputs";
+
+export const Aria2 = ({ idx, action, clients }: ClientActionProps) => (
+  <>
+    <FilterSection
+      title="Instance"
+      subtitle={
+        <>Select the <span className="font-bold">specific instance</span> which you want to handle this release filter.</>
+      }
+    >
+      <FilterLayout>
+        <FilterHalfRow>
+          <DownloadClientSelect
+            name={`actions.${idx}.client_id`}
+            action={action}
+            clients={clients}
+          />
+        </FilterHalfRow>
+        <FilterHalfRow>
+          <TextField
+            name={`actions.${idx}.label`}
+            label="Label"
+            placeholder="eg. tv"
+            tooltip={
+              <div>aria2 has no labels, downloads with a label are saved in a sub directory of the directory named after it.</div>
+            }
+          />
+        </FilterHalfRow>
+      </FilterLayout>
+
+      <TextAreaAutoResize
+        name={`actions.${idx}.save_path`}
+        label="Directory"
+        placeholder="eg. /full/path/to/download_folder"
+      />
+
+      <FilterLayout className="pb-6">
+        <FilterHalfRow>
+          <SwitchGroup
+            name={`actions.${idx}.paused`}
+            label="Add paused"
+            description="Add torrent as paused"
+          />
+        </FilterHalfRow>
+      </FilterLayout>
+
+      <CollapsibleSection
+        noBottomBorder
+        title="Limits"
+        subtitle="Configure your speed limits"
+      >
+        <FilterHalfRow>
+          <NumberField
+            name={`actions.${idx}.limit_download_speed`}
+            label="Limit download speed (KiB/s)"
+            placeholder="Takes any number (0 is no limit)"
+          />
+        </FilterHalfRow>
+        <FilterHalfRow>
+          <NumberField
+            name={`actions.${idx}.limit_upload_speed`}
+            label="Limit upload speed (KiB/s)"
+            placeholder="Takes any number (0 is no limit)"
+          />
+        </FilterHalfRow>
+      </CollapsibleSection>
+    </FilterSection>
+  </>
+);
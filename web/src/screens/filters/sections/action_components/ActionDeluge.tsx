@@ -27,7 +27,8 @@ export const Deluge = ({ idx, action, clients }: ClientActionProps) => (
             name={`actions.${idx}.label`}
             label="Label"
             columns={6}
-            placeholder="eg. label1 (must exist in Deluge to work)"
+            placeholder="eg. label1"
+            tooltip={<p>Requires the Label plugin. Missing labels are created, labels are lowercase in Deluge.</p>}
           />
         </FilterHalfRow>
 
@@ -36,6 +37,12 @@ export const Deluge = ({ idx, action, clients }: ClientActionProps) => (
           label="Save path"
           placeholder="eg. /full/path/to/download_folder"
         />
+        <TextAreaAutoResize
+          name={`actions.${idx}.move_completed_path`}
+          label="Move completed path"
+          placeholder="eg. /full/path/to/completed_folder"
+          tooltip={<p>Move the torrent here once completed. Supports macros.</p>}
+        />
       </FilterLayout>
 
       <FilterLayout className="pb-6">
@@ -59,18 +66,35 @@ export const Deluge = ({ idx, action, clients }: ClientActionProps) => (
       <CollapsibleSection
         noBottomBorder
         title="Limits"
-        subtitle="Configure your speed/ratio/seed time limits"
+        subtitle="Configure your speed/ratio/connection limits"
       >
-        <NumberField
-          name={`actions.${idx}.limit_download_speed`}
-          label="Limit download speed (KB/s)"
-          placeholder="Takes any number (0 is no limit)"
-        />
-        <NumberField
-          name={`actions.${idx}.limit_upload_speed`}
-          label="Limit upload speed (KB/s)"
-          placeholder="Takes any number (0 is no limit)"
-        />
+        <FilterLayout>
+          <NumberField
+            name={`actions.${idx}.limit_download_speed`}
+            label="Limit download speed (KiB/s)"
+            placeholder="Takes any number (0 is no limit)"
+          />
+          <NumberField
+            name={`actions.${idx}.limit_upload_speed`}
+            label="Limit upload speed (KiB/s)"
+            placeholder="Takes any number (0 is no limit)"
+          />
+        </FilterLayout>
+
+        <FilterLayout>
+          <NumberField
+            name={`actions.${idx}.limit_ratio`}
+            label="Stop at ratio"
+            placeholder="Takes any number (0 is no limit)"
+            step={0.25}
+            isDecimal
+          />
+          <NumberField
+            name={`actions.${idx}.max_connections`}
+            label="Max connections"
+            placeholder="Takes any number (0 uses the global limit)"
+          />
+        </FilterLayout>
       </CollapsibleSection>
     </FilterSection>
   </>
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { ActionDownloadStationPriorityOptions } from "@domain/constants";
+
+import { FilterHalfRow, FilterLayout, FilterSection } from "../_components";
+import { DownloadClientSelect, Select, TextAreaAutoResize } from "@components/inputs";
+
+export const DownloadStation = ({ idx, action, clients }: ClientActionProps) => (
+  <>
+    <FilterSection
+      title="Instance"
+      subtitle={
+        <>Select the <span className="font-bold">specific instance</span> which you want to handle this release filter.</>
+      }
+    >
+      <FilterLayout>
+        <FilterHalfRow>
+          <DownloadClientSelect
+            name={`actions.${idx}.client_id`}
+            action={action}
+            clients={clients}
+          />
+        </FilterHalfRow>
+        <FilterHalfRow>
+          <Select
+            name={`actions.${idx}.priority`}
+            label="Task priority"
+            optionDefaultText="Normal"
+            options={ActionDownloadStationPriorityOptions}
+          />
+        </FilterHalfRow>
+      </FilterLayout>
+
+      <TextAreaAutoResize
+        name={`actions.${idx}.save_path`}
+        label="Destination"
+        placeholder="eg. downloads/tv"
+        className="pb-6"
+        tooltip={
+          <div>The shared folder and directory to save the task in, without a leading slash. Leave empty to use the default destination of Download Station.</div>
+        }
+      />
+    </FilterSection>
+  </>
+);
@@ -158,14 +158,20 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
           <NumberField
             name={`actions.${idx}.limit_ratio`}
             label="Ratio limit"
-            placeholder="Takes any number (0 is no limit)"
+            placeholder="Takes any number (0 uses the global limit)"
             step={0.25}
             isDecimal
           />
           <NumberField
             name={`actions.${idx}.limit_seed_time`}
             label="Seed time limit (minutes)"
-            placeholder="Takes any number (0 is no limit)"
+            placeholder="Takes any number (0 uses the global limit)"
+          />
+          <NumberField
+            name={`actions.${idx}.limit_inactive_seed_time`}
+            label="Inactive seed time limit (minutes)"
+            placeholder="Takes any number (0 uses the global limit)"
+            tooltip={<p>Stop seeding once the torrent has been inactive for this long. Requires qBittorrent 4.6 or later.</p>}
           />
         </FilterLayout>
       </CollapsibleSection>
@@ -200,6 +206,11 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
             name={`actions.${idx}.reannounce_max_attempts`}
             label="Run reannounce Y times"
           />
+          <NumberField
+            name={`actions.${idx}.reannounce_jitter`}
+            label="Reannounce jitter in seconds"
+            placeholder="Random extra wait added to the interval"
+          />
         </FilterHalfRow>
       </CollapsibleSection>
     </FilterSection>
@@ -31,6 +31,27 @@ export const RTorrent = ({ idx, action, clients }: ClientActionProps) => (
             label="Label"
             columns={6}
             placeholder="eg. label1,label2"
+            tooltip={<p>The ruTorrent label, saved url encoded in d.custom1 like ruTorrent does. Supports macros.</p>}
+          />
+        </FilterHalfRow>
+
+        <FilterHalfRow>
+          <TextField
+            name={`actions.${idx}.custom1`}
+            label="Custom 1"
+            columns={6}
+            placeholder="eg. {{ .Indexer }}"
+            tooltip={<p>Sets d.custom1 as is and replaces the label. Supports macros.</p>}
+          />
+        </FilterHalfRow>
+
+        <FilterHalfRow>
+          <TextField
+            name={`actions.${idx}.custom2`}
+            label="Custom 2"
+            columns={6}
+            placeholder="eg. {{ .TorrentName }}"
+            tooltip={<p>Sets d.custom2 as is. Supports macros.</p>}
           />
         </FilterHalfRow>
       </FilterLayout>
@@ -25,9 +25,19 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
         <FilterHalfRow>
           <TextField
             name={`actions.${idx}.label`}
-            label="Torrent Label"
+            label="Torrent Labels"
             columns={6}
-            placeholder="eg. label1"
+            placeholder="eg. label1,label2"
+            tooltip={<p>Comma separated labels. Supports macros.</p>}
+          />
+        </FilterHalfRow>
+        <FilterHalfRow>
+          <TextField
+            name={`actions.${idx}.bandwidth_group`}
+            label="Bandwidth group"
+            columns={6}
+            placeholder="eg. racing"
+            tooltip={<p>Add the torrent to a bandwidth group to share its speed limits. Requires Transmission 4.0 or later.</p>}
           />
         </FilterHalfRow>
       </FilterLayout>
@@ -112,6 +122,11 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
             name={`actions.${idx}.reannounce_max_attempts`}
             label="Run reannounce Y times"
           />
+          <NumberField
+            name={`actions.${idx}.reannounce_jitter`}
+            label="Reannounce jitter in seconds"
+            placeholder="Random extra wait added to the interval"
+          />
         </FilterHalfRow>
       </CollapsibleSection>
     </FilterSection>
@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { FieldArray, FieldArrayRenderProps, useFormikContext } from "formik";
+import { SquaresPlusIcon, TrashIcon } from "@heroicons/react/24/outline";
+
+import { ActionCompletionHookTypeOptions, ExternalFilterWebhookMethodOptions } from "@domain/constants";
+import { FilterHalfRow, FilterLayout, FilterSection } from "@screens/filters/sections/_components.tsx";
+import { PasswordField, Select, TextAreaAutoResize, TextField } from "@components/inputs";
+
+interface CompletionHooksProps {
+  idx: number;
+}
+
+export const CompletionHooks = ({ idx }: CompletionHooksProps) => {
+  const { values } = useFormikContext<Filter>();
+
+  const hooks = values.actions[idx]?.completion_hooks ?? [];
+
+  return (
+    <FilterSection
+      title="Completion hooks"
+      subtitle="Run after the download client finished downloading the torrent. Requires the completion check interval in the config."
+    >
+      <FieldArray name={`actions.${idx}.completion_hooks`}>
+        {({ remove, push }: FieldArrayRenderProps) => (
+          <div className="col-span-12 space-y-4">
+            {hooks.map((hook, hookIdx) => (
+              <div key={hookIdx} className="rounded-md border border-gray-200 dark:border-gray-700 p-4">
+                <FilterLayout>
+                  <FilterHalfRow>
+                    <Select
+                      name={`actions.${idx}.completion_hooks.${hookIdx}.type`}
+                      label="Hook type"
+                      optionDefaultText="Select hook type"
+                      options={ActionCompletionHookTypeOptions}
+                    />
+                  </FilterHalfRow>
+                  <FilterHalfRow>
+                    <div className="flex justify-end pt-6">
+                      <button
+                        type="button"
+                        className="inline-flex items-center px-3 py-2 rounded-md text-sm text-red-600 dark:text-red-400 hover:bg-gray-100 dark:hover:bg-gray-700"
+                        onClick={() => remove(hookIdx)}
+                      >
+                        <TrashIcon className="w-4 h-4 mr-1" aria-hidden="true" />
+                        Remove
+                      </button>
+                    </div>
+                  </FilterHalfRow>
+
+                  {hook.type === "EXEC" && (
+                    <>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.exec_cmd`}
+                          label="Command"
+                          placeholder="Path to program eg. /bin/test"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.exec_args`}
+                          label="Arguments"
+                          placeholder={"eg. \"{{ .TorrentName }}\" {{ .TorrentHash }}"}
+                          tooltip={<p>Supports macros.</p>}
+                        />
+                      </FilterHalfRow>
+                    </>
+                  )}
+
+                  {hook.type === "WEBHOOK" && (
+                    <>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.webhook_host`}
+                          label="Endpoint"
+                          placeholder="Host eg. http://localhost/webhook"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <Select
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.webhook_method`}
+                          label="HTTP method"
+                          optionDefaultText="Select http method"
+                          options={ExternalFilterWebhookMethodOptions}
+                          tooltip={<div><p>Defaults to POST</p></div>}
+                        />
+                      </FilterHalfRow>
+                      <TextAreaAutoResize
+                        name={`actions.${idx}.completion_hooks.${hookIdx}.webhook_data`}
+                        label="Payload (json)"
+                        columns={12}
+                        placeholder={"Request data: { \"key\": \"value\" }"}
+                        tooltip={<p>A Go template with the release macros, eg. {"{{ .TorrentName }}"}.</p>}
+                      />
+                    </>
+                  )}
+
+                  {hook.type === "PLEX_SCAN" && (
+                    <>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.plex_host`}
+                          label="Plex URL"
+                          placeholder="eg. http://localhost:32400"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <PasswordField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.plex_token`}
+                          label="Plex token"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.plex_section`}
+                          label="Library section id"
+                          placeholder="eg. 2"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.plex_path`}
+                          label="Scan path"
+                          placeholder="eg. /media/tv/{{ .Title }}"
+                          tooltip={<p>Only this folder of the section is scanned. Supports macros, the whole section is scanned when empty.</p>}
+                        />
+                      </FilterHalfRow>
+                    </>
+                  )}
+
+                  {hook.type === "AUTOSCAN" && (
+                    <>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.autoscan_host`}
+                          label="Autoscan URL"
+                          placeholder="eg. http://localhost:3030"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.autoscan_path`}
+                          label="Scan path"
+                          placeholder="eg. /media/tv/{{ .Title }}"
+                          tooltip={<p>Supports macros. Defaults to the save path of the action.</p>}
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.autoscan_username`}
+                          label="Username"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <PasswordField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.autoscan_password`}
+                          label="Password"
+                        />
+                      </FilterHalfRow>
+                    </>
+                  )}
+
+                  {hook.type === "JELLYFIN_SCAN" && (
+                    <>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.jellyfin_host`}
+                          label="Jellyfin URL"
+                          placeholder="eg. http://localhost:8096"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <PasswordField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.jellyfin_api_key`}
+                          label="API key"
+                        />
+                      </FilterHalfRow>
+                      <FilterHalfRow>
+                        <TextField
+                          name={`actions.${idx}.completion_hooks.${hookIdx}.jellyfin_path`}
+                          label="Scan path"
+                          placeholder="eg. /media/tv/{{ .Title }}"
+                          tooltip={<p>Supports macros. Defaults to the save path of the action, all libraries are refreshed without one.</p>}
+                        />
+                      </FilterHalfRow>
+                    </>
+                  )}
+                </FilterLayout>
+              </div>
+            ))}
+
+            <button
+              type="button"
+              className="inline-flex items-center px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm text-sm font-medium text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-700 hover:bg-gray-50 dark:hover:bg-gray-600 focus:outline-none"
+              onClick={() => push({ type: "WEBHOOK" } as ActionCompletionHook)}
+            >
+              <SquaresPlusIcon className="w-5 h-5 mr-1" aria-hidden="true" />
+              Add hook
+            </button>
+          </div>
+        )}
+      </FieldArray>
+    </FilterSection>
+  );
+};
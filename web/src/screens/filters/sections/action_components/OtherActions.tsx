@@ -4,8 +4,17 @@
  */
 
 import { WarningAlert } from "@components/alerts";
+import { ActionNzbgetPriorityOptions, ExternalFilterWebhookMethodOptions } from "@domain/constants";
 import { FilterHalfRow, FilterLayout, FilterSection } from "@screens/filters/sections/_components.tsx";
-import { DownloadClientSelect, NumberField, TextAreaAutoResize, TextField } from "@components/inputs";
+import {
+  DownloadClientSelect,
+  NumberField,
+  PasswordField,
+  Select,
+  SwitchGroup,
+  TextAreaAutoResize,
+  TextField
+} from "@components/inputs";
 
 
 export const SABnzbd = ({ idx, action, clients }: ClientActionProps) => (
@@ -36,6 +45,61 @@ export const SABnzbd = ({ idx, action, clients }: ClientActionProps) => (
   </FilterSection>
 );
 
+export const Nzbget = ({ idx, action, clients }: ClientActionProps) => (
+  <FilterSection
+    title="Instance"
+    subtitle={
+      <>Select the <span className="font-bold">specific instance</span> which you want to handle this release filter.</>
+    }
+  >
+    <FilterLayout>
+      <FilterHalfRow>
+        <DownloadClientSelect
+          name={`actions.${idx}.client_id`}
+          action={action}
+          clients={clients}
+        />
+      </FilterHalfRow>
+      <FilterHalfRow>
+        <TextField
+          name={`actions.${idx}.category`}
+          label="Category"
+          columns={6}
+          placeholder="eg. category"
+        />
+      </FilterHalfRow>
+      <FilterHalfRow>
+        <Select
+          name={`actions.${idx}.priority`}
+          label="Priority"
+          optionDefaultText="Normal"
+          options={ActionNzbgetPriorityOptions}
+        />
+      </FilterHalfRow>
+      <FilterHalfRow>
+        <TextField
+          name={`actions.${idx}.dupe_key`}
+          label="Dupe key"
+          columns={6}
+          placeholder="eg. {{ .Title }}-{{ .Season }}-{{ .Episode }}"
+          tooltip={
+            <div>
+              <p>NZBGet only downloads one nzb with the same dupe key, the others are kept as backups for failed downloads. Supports macros.</p>
+            </div>
+          }
+        />
+      </FilterHalfRow>
+      <FilterHalfRow>
+        <SwitchGroup
+          name={`actions.${idx}.paused`}
+          label="Add paused"
+          description="Add nzb as paused"
+        />
+      </FilterHalfRow>
+    </FilterLayout>
+  </FilterSection>
+);
+
 export const Test = () => (
   <WarningAlert
     alert="Heads up!"
@@ -62,6 +126,31 @@ export const Exec = ({ idx }: ClientActionProps) => (
         label="Arguments"
         placeholder="Arguments eg. --test"
       />
+
+      <FilterHalfRow>
+        <TextField
+          name={`actions.${idx}.exec_dir`}
+          label="Working directory"
+          placeholder="eg. /home/user/scripts"
+          tooltip={<p>The directory the command runs in. Supports macros.</p>}
+        />
+      </FilterHalfRow>
+      <FilterHalfRow>
+        <NumberField
+          name={`actions.${idx}.exec_timeout`}
+          label="Timeout (seconds)"
+          placeholder="Takes any number (0 is no timeout)"
+          tooltip={<p>The command is killed when it runs longer, and the action fails.</p>}
+        />
+      </FilterHalfRow>
+
+      <TextAreaAutoResize
+        name={`actions.${idx}.exec_env`}
+        label="Environment"
+        placeholder={"One KEY=value per line, eg.\nRELEASE_NAME={{ .TorrentName }}"}
+        lines
+        tooltip={<p>Environment variables added to the environment of autobrr. Supports macros.</p>}
+      />
     </FilterLayout>
 
   </FilterSection>
@@ -82,6 +171,36 @@ export const WatchFolder = ({ idx }: ClientActionProps) => (
   </FilterSection>
 );
 
+export const RemoteWatchFolder = ({ idx, action, clients }: ClientActionProps) => (
+  <FilterSection
+    title="Remote Watch Folder Arguments"
+    subtitle="Select the remote watch folder and where autobrr should upload the files it fetches."
+  >
+    <FilterLayout>
+      <FilterHalfRow>
+        <DownloadClientSelect
+          name={`actions.${idx}.client_id`}
+          action={action}
+          clients={clients}
+        />
+      </FilterHalfRow>
+      <FilterHalfRow>
+        <TextField
+          name={`actions.${idx}.watch_folder`}
+          label="Watch directory"
+          columns={6}
+          placeholder="eg. {{ .Indexer }}"
+          tooltip={
+            <div>
+              <p>Relative to the path of the remote watch folder URL, unless it starts with a /. Ending with .torrent or .nzb sets the file name too. Supports macros.</p>
+            </div>
+          }
+        />
+      </FilterHalfRow>
+    </FilterLayout>
+  </FilterSection>
+);
+
 export const WebHook = ({ idx }: ClientActionProps) => (
   <FilterSection
     title="Webhook Arguments"
@@ -97,11 +216,48 @@ export const WebHook = ({ idx }: ClientActionProps) => (
           <p>URL or IP to your API. Pass params and set API tokens etc.</p>
         }
       />
+      <Select
+        name={`actions.${idx}.webhook_method`}
+        label="HTTP method"
+        optionDefaultText="Select http method"
+        options={ExternalFilterWebhookMethodOptions}
+        tooltip={<div><p>Select the HTTP method for this webhook. Defaults to POST</p></div>}
+      />
+      <TextAreaAutoResize
+        name={`actions.${idx}.webhook_headers`}
+        label="HTTP Request Headers"
+        columns={6}
+        placeholder={"One HEADER=value per line, eg.\nAuthorization=Bearer token"}
+        lines
+        tooltip={<p>Supports macros.</p>}
+      />
+      <PasswordField
+        name={`actions.${idx}.webhook_secret`}
+        label="Signing secret"
+        columns={6}
+        tooltip={<p>Signs the payload with HMAC-SHA256, the signature is sent in the X-Autobrr-Signature header as sha256=&lt;hex&gt;.</p>}
+      />
+      <FilterHalfRow>
+        <NumberField
+          name={`actions.${idx}.webhook_retry_attempts`}
+          label="Maximum attempts"
+          placeholder="1"
+          tooltip={<p>Requests failing with a 5xx status code or a connection error are retried until the attempts are used up.</p>}
+        />
+      </FilterHalfRow>
+      <FilterHalfRow>
+        <NumberField
+          name={`actions.${idx}.webhook_retry_delay_seconds`}
+          label="Retry delay in seconds"
+          placeholder="1"
+        />
+      </FilterHalfRow>
     </FilterLayout>
     <TextAreaAutoResize
       name={`actions.${idx}.webhook_data`}
       label="Payload (json)"
       placeholder={"Request data: { \"key\": \"value\" }"}
+      tooltip={<p>A Go template with the release macros, eg. {"{{ .TorrentName }}"}.</p>}
     />
   </FilterSection>
 );
@@ -150,3 +306,23 @@ export const Arr = ({ idx, action, clients }: ClientActionProps) => (
     </FilterLayout>
   </FilterSection>
 );
+
+export const Mylar = ({ idx, action, clients }: ClientActionProps) => (
+  <FilterSection
+    title="Instance"
+    subtitle={
+      <>Select the <span className="font-bold">specific instance</span> which you want to handle this release filter.
+        Mylar3 queues the matching issue from the watchlist and grabs it from its own providers.</>
+    }
+  >
+    <FilterLayout>
+      <FilterHalfRow>
+        <DownloadClientSelect
+          name={`actions.${idx}.client_id`}
+          action={action}
+          clients={clients}
+        />
+      </FilterHalfRow>
+    </FilterLayout>
+  </FilterSection>
+);
@@ -8,4 +8,7 @@ export * from "./ActionQBittorrent";
 export * from "./ActionRTorrent";
 export * from "./ActionTransmission";
 export * from "./ActionPorla";
+export * from "./ActionAria2";
+export * from "./ActionDownloadStation";
 export * from "./OtherActions";
+export * from "./CompletionHooks";
@@ -16,8 +16,9 @@ import { APIClient } from "@api/APIClient";
 import { ApikeysQueryOptions } from "@api/queries";
 import { ApiKeys } from "@api/query_keys";
 import { useToggle } from "@hooks/hooks";
-import { classNames } from "@utils";
+import { baseUrl, classNames } from "@utils";
 import { EmptySimple } from "@components/emptystates";
+import { ExternalLink } from "@components/ExternalLink";
 import { Section } from "./_components";
 import { PlusIcon } from "@heroicons/react/24/solid";
 
@@ -30,7 +31,15 @@ function APISettings() {
   return (
     <Section
       title="API keys"
-      description="Manage your autobrr API keys here."
+      description={
+        <>
+          Manage your autobrr API keys here. The API is described by its{" "}
+          <ExternalLink href={`${baseUrl()}api/openapi.json`} className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">
+            OpenAPI spec
+          </ExternalLink>
+          .
+        </>
+      }
       rightSide={
         <button
           type="button"
@@ -131,8 +140,12 @@ function APIListItem({ apikey }: ApiKeyItemProps) {
             </div>
           </div>
         </div>
-        <div className="col-span-8 flex items-center text-sm font-medium text-gray-900 dark:text-white">
+        <div className="col-span-8 text-sm font-medium text-gray-900 dark:text-white">
           <KeyField value={apikey.key} />
+          <p className="mt-1 text-xs text-gray-500 dark:text-gray-400">
+            {apikey.scopes?.length ? apikey.scopes.join(", ") : "full access"}
+            {apikey.expires_at && ` · expires ${new Date(apikey.expires_at).toLocaleDateString()}`}
+          </p>
         </div>
 
         <div className="col-span-1 hidden sm:flex items-center text-sm font-medium text-gray-900 dark:text-white">
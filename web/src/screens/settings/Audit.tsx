@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useState } from "react";
+import { useQuery } from "@tanstack/react-query";
+
+import { AuditQueryOptions } from "@api/queries";
+import { EmptySimple } from "@components/emptystates";
+import { classNames, simplifyDate } from "@utils";
+import { Section } from "./_components";
+
+const pageSize = 25;
+
+const actionColors: Record<AuditAction, string> = {
+  create: "text-green-600 dark:text-green-500",
+  update: "text-blue-600 dark:text-blue-500",
+  delete: "text-red-600 dark:text-red-500"
+};
+
+function AuditSettings() {
+  const [page, setPage] = useState(0);
+
+  const { data } = useQuery(AuditQueryOptions(page * pageSize, pageSize));
+
+  const count = data?.count ?? 0;
+  const pages = Math.max(1, Math.ceil(count / pageSize));
+
+  return (
+    <Section
+      title="Audit log"
+      description="Changes of filters, actions, indexers, IRC networks, download clients and settings, with who made them."
+    >
+      {data && data.data.length > 0 ? (
+        <>
+          <ul className="min-w-full relative">
+            <li className="hidden sm:grid grid-cols-12 gap-4 mb-2 border-b border-gray-200 dark:border-gray-700">
+              <div className="col-span-3 px-6 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                Time
+              </div>
+              <div className="col-span-5 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                Change
+              </div>
+              <div className="col-span-4 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                By
+              </div>
+            </li>
+
+            {data.data.map((entry) => <AuditListItem key={entry.id} entry={entry} />)}
+          </ul>
+
+          <div className="flex items-center justify-between px-6 py-3 text-sm text-gray-600 dark:text-gray-400">
+            <span>Page {page + 1} of {pages}</span>
+            <div className="flex gap-2">
+              <button
+                type="button"
+                className="px-3 py-1 rounded-md border border-gray-300 dark:border-gray-700 disabled:opacity-50"
+                disabled={page === 0}
+                onClick={() => setPage(page - 1)}
+              >
+                Previous
+              </button>
+              <button
+                type="button"
+                className="px-3 py-1 rounded-md border border-gray-300 dark:border-gray-700 disabled:opacity-50"
+                disabled={page + 1 >= pages}
+                onClick={() => setPage(page + 1)}
+              >
+                Next
+              </button>
+            </div>
+          </div>
+        </>
+      ) : (
+        <EmptySimple title="No changes recorded yet" />
+      )}
+    </Section>
+  );
+}
+
+interface AuditListItemProps {
+  entry: AuditEntry;
+}
+
+function AuditListItem({ entry }: AuditListItemProps) {
+  const target = entry.resource_name || entry.resource_id;
+
+  return (
+    <li className="text-gray-500 dark:text-gray-400">
+      <div className="sm:grid grid-cols-12 gap-4 items-center py-2">
+        <div className="col-span-3 px-6 text-sm">
+          {simplifyDate(entry.created_at)}
+        </div>
+        <div className="col-span-5 text-sm">
+          <span className={classNames("font-medium uppercase text-xs mr-2", actionColors[entry.action])}>
+            {entry.action}
+          </span>
+          <span className="text-gray-900 dark:text-white">{entry.description}</span>
+          {target ? <span className="ml-1">{target}</span> : null}
+        </div>
+        <div className="col-span-4 text-sm">
+          {entry.username ?? "-"}
+          {entry.api_key ? <span className="ml-1 text-xs">(api key {entry.api_key})</span> : null}
+        </div>
+      </div>
+    </li>
+  );
+}
+
+export default AuditSettings;
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useToggle } from "@hooks/hooks.ts";
+import { useMutation, useQueryClient, useSuspenseQuery } from "@tanstack/react-query";
+import { PlusIcon } from "@heroicons/react/24/solid";
+import { toast } from "react-hot-toast";
+
+import { APIClient } from "@api/APIClient";
+import { ListKeys } from "@api/query_keys";
+import { ListsQueryOptions } from "@api/queries";
+import { Section } from "./_components";
+import { EmptySimple } from "@components/emptystates";
+import { Checkbox } from "@components/Checkbox";
+import { ListAddForm, ListUpdateForm } from "@forms/settings/ListForms";
+import Toast from "@components/notifications/Toast";
+import { IsEmptyDate, simplifyDate } from "@utils";
+
+interface ListItemProps {
+  list: List;
+}
+
+function ListItem({ list }: ListItemProps) {
+  const [isOpen, toggleUpdate] = useToggle(false);
+
+  const queryClient = useQueryClient();
+
+  const toggleMutation = useMutation({
+    mutationFn: (enabled: boolean) => APIClient.lists.toggleEnable(list.id, enabled),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ListKeys.lists() });
+
+      toast.custom(t => <Toast type="success" body={`List ${list.name} was ${list.enabled ? "disabled" : "enabled"} successfully.`} t={t} />);
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="List state could not be updated" t={t} />);
+    }
+  });
+
+  const refreshMutation = useMutation({
+    mutationFn: () => APIClient.lists.refresh(list.id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ListKeys.lists() });
+
+      toast.custom(t => <Toast type="success" body={`List ${list.name} was refreshed.`} t={t} />);
+    },
+    onError: () => {
+      queryClient.invalidateQueries({ queryKey: ListKeys.lists() });
+
+      toast.custom((t) => <Toast type="error" body={`List ${list.name} could not be refreshed`} t={t} />);
+    }
+  });
+
+  return (
+    <li>
+      <ListUpdateForm isOpen={isOpen} toggle={toggleUpdate} data={list} />
+
+      <div className="grid grid-cols-12 items-center py-1.5">
+        <div className="col-span-2 sm:col-span-1 flex pl-1 sm:pl-5 items-center">
+          <Checkbox value={list.enabled ?? false} setValue={(newState) => toggleMutation.mutate(newState)} />
+        </div>
+        <div className="col-span-6 sm:col-span-5 pl-12 sm:pr-6 py-3 block flex-col text-sm font-medium text-gray-900 dark:text-white truncate">
+          {list.name}
+          <div className="text-xs text-gray-500 dark:text-gray-400 truncate">
+            {list.filters.map((f) => f.name).join(", ")}
+          </div>
+        </div>
+        <div className="hidden md:block col-span-2 pr-6 py-3 text-left items-center whitespace-nowrap text-sm text-gray-500 dark:text-gray-400 truncate">
+          {list.type}
+        </div>
+        <div
+          className="hidden md:block col-span-2 py-3 text-left items-center whitespace-nowrap text-sm text-gray-500 dark:text-gray-400 truncate"
+          title={list.last_refresh_error || simplifyDate(list.last_refresh_time)}
+        >
+          {list.last_refresh_status === "ERROR" ? (
+            <span className="text-red-500">Error</span>
+          ) : IsEmptyDate(list.last_refresh_time)}
+        </div>
+        <div className="col-span-4 md:col-span-2 flex py-3 whitespace-nowrap text-right text-sm font-medium">
+          <span
+            className="px-3 text-blue-600 dark:text-gray-300 hover:text-blue-900 dark:hover:text-blue-500 cursor-pointer"
+            onClick={() => refreshMutation.mutate()}
+          >
+            Refresh
+          </span>
+          <span
+            className="px-3 text-blue-600 dark:text-gray-300 hover:text-blue-900 dark:hover:text-blue-500 cursor-pointer"
+            onClick={toggleUpdate}
+          >
+            Edit
+          </span>
+        </div>
+      </div>
+    </li>
+  );
+}
+
+function ListSettings() {
+  const [addListIsOpen, toggleAddList] = useToggle(false);
+
+  const listsQuery = useSuspenseQuery(ListsQueryOptions());
+  const lists = listsQuery.data;
+
+  return (
+    <Section
+      title="Lists"
+      description={
+        <>
+          Trakt, MDBList, IMDb, plaintext and Sonarr, Radarr, Lidarr or Readarr lists which keep the shows or release fields of filters up to date.<br/>
+        </>
+      }
+      rightSide={
+        <button
+          type="button"
+          onClick={toggleAddList}
+          className="relative inline-flex items-center px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+        >
+          <PlusIcon className="h-5 w-5 mr-1"/>
+          Add new
+        </button>
+      }
+    >
+      <ListAddForm isOpen={addListIsOpen} toggle={toggleAddList} />
+
+      <div className="flex flex-col">
+        {lists.length ? (
+          <ul className="min-w-full relative">
+            <li className="grid grid-cols-12 border-b border-gray-200 dark:border-gray-700">
+              <div className="flex col-span-2 sm:col-span-1 pl-0 sm:pl-3 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                Enabled
+              </div>
+              <div className="col-span-6 sm:col-span-5 pl-12 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                Name
+              </div>
+              <div className="hidden md:flex col-span-2 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                Type
+              </div>
+              <div className="hidden md:flex col-span-2 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                Last refresh
+              </div>
+            </li>
+            {lists.map((list) => (
+              <ListItem list={list} key={list.id}/>
+            ))}
+          </ul>
+        ) : (
+          <EmptySimple
+            title="No lists"
+            subtitle=""
+            buttonText="Add new list"
+            buttonAction={toggleAddList}
+          />
+        )}
+      </div>
+    </Section>
+  );
+}
+
+export default ListSettings;
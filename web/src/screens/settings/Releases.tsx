@@ -10,7 +10,7 @@ import { MultiSelect as RMSC } from "react-multi-select-component";
 import { AgeSelect } from "@components/inputs"
 
 import { APIClient } from "@api/APIClient";
-import { ReleaseKeys } from "@api/query_keys";
+import { FilterKeys, ReleaseKeys } from "@api/query_keys";
 import Toast from "@components/notifications/Toast";
 import { useToggle } from "@hooks/hooks";
 import { DeleteModal } from "@components/modals";
@@ -52,6 +52,11 @@ interface Indexer {
   value: string;
 }
 
+interface FilterOption {
+  label: string;
+  value: number;
+}
+
 interface ReleaseStatus {
   label: string;
   value: string;
@@ -62,6 +67,7 @@ function DeleteReleases() {
   const [duration, setDuration] = useState<string>("");
   const [parsedDuration, setParsedDuration] = useState<number>();
   const [indexers, setIndexers] = useState<Indexer[]>([]);
+  const [filters, setFilters] = useState<FilterOption[]>([]);
   const [releaseStatuses, setReleaseStatuses] = useState<ReleaseStatus[]>([]);
   const cancelModalButtonRef = useRef<HTMLInputElement | null>(null);
   const [deleteModalIsOpen, toggleDeleteModal] = useToggle(false);
@@ -75,6 +81,12 @@ function DeleteReleases() {
     })),
   });
 
+  const { data: filterOptions } = useQuery({
+    queryKey: FilterKeys.lists(),
+    queryFn: () => APIClient.filters.getAll(),
+    select: data => data.map(filter => ({ value: filter.id, label: filter.name })),
+  });
+
   const releaseStatusOptions = [
     { label: "Approved", value: "PUSH_APPROVED" },
     { label: "Rejected", value: "PUSH_REJECTED" },
@@ -82,16 +94,17 @@ function DeleteReleases() {
   ];
 
   const deleteOlderMutation = useMutation({
-    mutationFn: (params: { olderThan: number, indexers: string[], releaseStatuses: string[] }) =>
+    mutationFn: (params: DeleteParams) =>
       APIClient.release.delete(params),
-    onSuccess: () => {
+    onSuccess: (data) => {
+      const deleted = `${data.deleted} release${data.deleted === 1 ? "" : "s"}`;
       if (parsedDuration === 0) {
         toast.custom((t) => (
-          <Toast type="success" body={"All releases based on criteria were deleted."} t={t} />
+          <Toast type="success" body={`Deleted ${deleted} based on criteria.`} t={t} />
         ));
       } else {
         toast.custom((t) => (
-          <Toast type="success" body={`Releases older than ${getDurationLabel(parsedDuration ?? 0)} were deleted.`} t={t} />
+          <Toast type="success" body={`Deleted ${deleted} older than ${getDurationLabel(parsedDuration ?? 0)}.`} t={t} />
         ));
       }
 
@@ -105,7 +118,12 @@ function DeleteReleases() {
       return;
     }
 
-    deleteOlderMutation.mutate({ olderThan: parsedDuration, indexers: indexers.map(i => i.value), releaseStatuses: releaseStatuses.map(rs => rs.value) });
+    deleteOlderMutation.mutate({
+      olderThan: parsedDuration,
+      indexers: indexers.map(i => i.value),
+      filterIds: filters.map(f => f.value),
+      releaseStatuses: releaseStatuses.map(rs => rs.value)
+    });
   };
 
   return (
@@ -117,22 +135,23 @@ function DeleteReleases() {
         buttonRef={cancelModalButtonRef}
         deleteAction={deleteOlderReleases}
         title="Remove releases"
-        text={`You are about to ${parsedDuration ? `permanently delete all release history records older than ${getDurationLabel(parsedDuration)} for ` : 'delete all release history records for '}${indexers.length ? 'the chosen indexers' : 'all indexers'}${releaseStatuses.length ? ` and with the following release statuses: ${releaseStatuses.map(status => status.label).join(', ')}` : ''}.`}
+        text={`You are about to ${parsedDuration ? `permanently delete all release history records older than ${getDurationLabel(parsedDuration)} for ` : 'delete all release history records for '}${indexers.length ? 'the chosen indexers' : 'all indexers'}${filters.length ? ', pushed by the chosen filters' : ''}${releaseStatuses.length ? ` and with the following release statuses: ${releaseStatuses.map(status => status.label).join(', ')}` : ''}.`}
       />
       <div className="flex flex-col gap-2 w-full">
         <div>
           <h2 className="text-lg leading-4 font-bold text-gray-900 dark:text-white">Delete release history</h2>
           <p className="text-sm mt-1 text-gray-500 dark:text-gray-400">
-            Select the criteria below to permanently delete release history records that are older than the chosen age and optionally match the selected indexers and release statuses:
+            Select the criteria below to permanently delete release history records that are older than the chosen age and optionally match all of the selected indexers, filters and release statuses:
             <ul className="list-disc pl-5 mt-2">
               <li>
                 Older than (e.g., 6 months - all records older than 6 months will be deleted) - <strong className="text-gray-600 dark:text-gray-300">Required</strong>
               </li>
               <li>Indexers - Optional (if none selected, applies to all indexers)</li>
+              <li>Filters - Optional (if none selected, applies to releases of all filters and without a filter)</li>
               <li>Release statuses - Optional (if none selected, applies to all release statuses)</li>
             </ul>
             <p className="mt-2 text-red-600 dark:text-red-500">
-              <strong>Warning:</strong> If no indexers or release statuses are selected, all release history records older than the selected age will be permanently deleted, regardless of indexer or status.
+              <strong>Warning:</strong> If no indexers, filters or release statuses are selected, all release history records older than the selected age will be permanently deleted, regardless of indexer, filter or status.
             </p>
           </p>
         </div>
@@ -152,6 +171,10 @@ function DeleteReleases() {
               label: 'Indexers:',
               content: <RMSC options={indexerOptions?.map(option => ({ value: option.identifier, label: option.name })) || []} value={indexers} onChange={setIndexers} labelledBy="Select indexers" />
             },
+            {
+              label: 'Filters:',
+              content: <RMSC options={filterOptions || []} value={filters} onChange={setFilters} labelledBy="Select filters" />
+            },
             {
               label: 'Release statuses:',
               content: <RMSC options={releaseStatusOptions} value={releaseStatuses} onChange={setReleaseStatuses} labelledBy="Select release statuses" />
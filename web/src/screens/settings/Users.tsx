@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useRef } from "react";
+import { useMutation, useQueryClient, useSuspenseQuery } from "@tanstack/react-query";
+import { toast } from "react-hot-toast";
+import { TrashIcon } from "@heroicons/react/24/outline";
+import { PlusIcon } from "@heroicons/react/24/solid";
+
+import { DeleteModal } from "@components/modals";
+import { UserAddForm } from "@forms/settings/UserAddForm";
+import Toast from "@components/notifications/Toast";
+import { APIClient } from "@api/APIClient";
+import { UsersQueryOptions } from "@api/queries";
+import { UserKeys } from "@api/query_keys";
+import { UserRoleOptions } from "@domain/constants";
+import { useToggle } from "@hooks/hooks";
+import { AuthContext } from "@utils/Context";
+import { classNames } from "@utils";
+import { Section } from "./_components";
+
+function UserSettings() {
+  const [addFormIsOpen, toggleAddForm] = useToggle(false);
+
+  const usersQuery = useSuspenseQuery(UsersQueryOptions());
+
+  return (
+    <Section
+      title="Users"
+      description="Manage who can log in. Operators can edit filters and releases, read-only users can only look around."
+      rightSide={
+        <button
+          type="button"
+          className="relative inline-flex items-center px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+          onClick={toggleAddForm}
+        >
+          <PlusIcon className="h-5 w-5 mr-1" />
+          Add new
+        </button>
+      }
+    >
+      <UserAddForm isOpen={addFormIsOpen} toggle={toggleAddForm} />
+
+      <ul className="min-w-full relative">
+        <li className="hidden sm:grid grid-cols-12 gap-4 mb-2 border-b border-gray-200 dark:border-gray-700">
+          <div className="col-span-5 px-6 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+            Username
+          </div>
+          <div className="col-span-6 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+            Role
+          </div>
+        </li>
+
+        {usersQuery.data?.map((u) => <UserListItem key={u.id} user={u} />)}
+      </ul>
+    </Section>
+  );
+}
+
+interface UserListItemProps {
+  user: User;
+}
+
+function UserListItem({ user }: UserListItemProps) {
+  const cancelModalButtonRef = useRef(null);
+  const [deleteModalIsOpen, toggleDeleteModal] = useToggle(false);
+
+  const username = AuthContext.useSelector((s) => s.username);
+  const isSelf = username === user.username;
+
+  const queryClient = useQueryClient();
+
+  const roleMutation = useMutation({
+    mutationFn: (role: UserRole) => APIClient.users.updateRole(user.id, role),
+    onSuccess: (_, role) => {
+      queryClient.invalidateQueries({ queryKey: UserKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`${user.username} is now ${role}`} t={t} />);
+    },
+    onError: (error) => {
+      toast.custom((t) => <Toast type="error" body={error.message} t={t} />);
+    }
+  });
+
+  const deleteMutation = useMutation({
+    mutationFn: (id: number) => APIClient.users.delete(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: UserKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`User ${user.username} was deleted`} t={t} />);
+    },
+    onError: (error) => {
+      toast.custom((t) => <Toast type="error" body={error.message} t={t} />);
+    }
+  });
+
+  return (
+    <li className="text-gray-500 dark:text-gray-400">
+      <DeleteModal
+        isOpen={deleteModalIsOpen}
+        isLoading={deleteMutation.isPending}
+        toggle={toggleDeleteModal}
+        buttonRef={cancelModalButtonRef}
+        deleteAction={() => {
+          deleteMutation.mutate(user.id);
+          toggleDeleteModal();
+        }}
+        title={`Remove user: ${user.username}`}
+        text="Are you sure you want to remove this user? Their API keys are removed as well."
+      />
+
+      <div className="sm:grid grid-cols-12 gap-4 items-center py-2">
+        <div className="col-span-5 px-2 sm:px-6 py-2 sm:py-0 truncate block sm:text-sm text-md font-medium text-gray-900 dark:text-white">
+          {user.username}
+          {isSelf && <span className="ml-2 text-xs text-gray-500 dark:text-gray-400">(you)</span>}
+        </div>
+        <div className="col-span-6 px-2 sm:px-0 text-sm font-medium text-gray-900 dark:text-white">
+          <select
+            value={user.role}
+            disabled={roleMutation.isPending}
+            onChange={(e) => roleMutation.mutate(e.target.value as UserRole)}
+            className="block w-full sm:w-48 shadow-sm sm:text-sm focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 rounded-md border-gray-300 dark:border-gray-700 bg-gray-100 dark:bg-gray-815 dark:text-gray-100"
+          >
+            {UserRoleOptions.map((o) => (
+              <option key={o.value} value={o.value} title={o.description}>{o.label}</option>
+            ))}
+          </select>
+        </div>
+
+        <div className="col-span-1 flex items-center text-sm font-medium text-gray-900 dark:text-white">
+          <button
+            className={classNames(
+              "text-gray-900 dark:text-gray-300",
+              "font-medium group flex rounded-md items-center px-2 py-2 text-sm"
+            )}
+            onClick={toggleDeleteModal}
+            title="Delete user"
+          >
+            <TrashIcon className="text-red-500 w-5 h-5" aria-hidden="true" />
+          </button>
+        </div>
+      </div>
+    </li>
+  );
+}
+
+export default UserSettings;
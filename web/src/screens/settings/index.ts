@@ -9,9 +9,11 @@ export { default as DownloadClient } from "./DownloadClient";
 export { default as Feed } from "./Feed";
 export { default as Indexer } from "./Indexer";
 export { default as Irc } from "./Irc";
+export { default as Lists } from "./Lists";
 export { default as Logs } from "./Logs";
 export { default as Notification } from "./Notifications";
 export { default as Proxy } from "./Proxy";
 export { default as Release } from "./Releases";
 export { default as RegexPlayground } from "./RegexPlayground";
 export { default as Account } from "./Account";
+export { default as Users } from "./Users";
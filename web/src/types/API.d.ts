@@ -3,16 +3,66 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+type APIKeyScope = "read" | "releases:write" | "filters:write" | "admin";
+
 interface APIKey {
   name: string;
   key: string;
-  scopes: string[];
+  scopes: APIKeyScope[];
+  expires_at?: string | null;
+  user_id?: number;
+  created_at: Date;
+}
+
+type UserRole = "admin" | "operator" | "read-only";
+
+interface User {
+  id: number;
+  username: string;
+  role: UserRole;
   created_at: Date;
 }
 
+type AuditAction = "create" | "update" | "delete";
+
+interface AuditEntry {
+  id: number;
+  action: AuditAction;
+  resource: string;
+  resource_id?: string;
+  resource_name?: string;
+  description: string;
+  username?: string;
+  api_key?: string;
+  created_at: string;
+}
+
+interface AuditResponse {
+  data: AuditEntry[];
+  count: number;
+}
+
+interface UserCreate {
+  username: string;
+  password: string;
+  role: UserRole;
+}
+
 interface UserUpdate {
   username_current: string;
   username_new?: string;
   password_current?: string;
   password_new?: string;
 }
+
+interface CurrentUser {
+  id: number;
+  username: string;
+  role: UserRole;
+  auth_method: "password" | "oidc";
+}
+
+interface OIDCConfig {
+  enabled: boolean;
+  disable_built_in_login: boolean;
+}
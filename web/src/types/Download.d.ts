@@ -10,12 +10,19 @@ type DownloadClientType =
   "RTORRENT" |
   "TRANSMISSION" |
   "PORLA" |
+  "ARIA2" |
+  "DOWNLOAD_STATION" |
   "RADARR" |
   "SONARR" |
   "LIDARR" |
   "WHISPARR" |
   "READARR" |
-  "SABNZBD";
+  "MYLAR" |
+  "PLEX" |
+  "JELLYFIN" |
+  "SABNZBD" |
+  "NZBGET" |
+  "REMOTE_WATCH_FOLDER";
 
 // export enum DownloadClientTypeEnum {
 //     QBITTORRENT = "QBITTORRENT",
@@ -34,6 +41,8 @@ interface DownloadClientRules {
   ignore_slow_torrents_condition: IgnoreTorrentsCondition;
   download_speed_threshold: number;
   upload_speed_threshold: number;
+  min_free_space?: number;
+  free_space_path?: string;
 }
 
 type IgnoreTorrentsCondition = "ALWAYS" | "MAX_DOWNLOADS_REACHED";
@@ -9,12 +9,27 @@ interface Filter {
   enabled: boolean;
   created_at: Date;
   updated_at: Date;
+  created_by?: string;
+  updated_by?: string;
+  deleted_at?: Date;
   min_size: string;
   max_size: string;
+  max_files?: number;
+  match_file_extensions?: string;
+  except_file_extensions?: string;
+  sonarr_check_client_id?: number;
+  radarr_check_client_id?: number;
+  lidarr_check_client_id?: number;
+  library_check_client_id?: number;
+  match_ids?: string;
+  match_musicbrainz?: string;
   delay: number;
   priority: number;
   max_downloads: number;
   max_downloads_unit: string;
+  max_downloads_size?: string;
+  max_downloads_size_unit?: string;
+  skip_dedupe?: boolean;
   match_releases: string;
   except_releases: string;
   use_regex: boolean;
@@ -35,6 +50,11 @@ interface Filter {
   seasons: string;
   episodes: string;
   smart_episode: boolean;
+  ignore_season_packs?: boolean;
+  upgrade_replace?: boolean;
+  smart_music: boolean;
+  smart_music_match: string[];
+  smart_duplicate_days: number;
   resolutions: string[];
   codecs: string[];
   sources: string[];
@@ -59,6 +79,7 @@ interface Filter {
   log_score: string;
   match_categories: string;
   except_categories: string;
+  normalize_categories: boolean;
   match_uploaders: string;
   except_uploaders: string;
   match_language: string[];
@@ -73,6 +94,15 @@ interface Filter {
   max_seeders: number;
   min_leechers: number;
   max_leechers: number;
+  breaker_threshold: number;
+  breaker_cooldown: number;
+  breaker_fail_open: boolean;
+  breakers?: Record<string, FilterBreaker>;
+  group_id?: number;
+  schedule?: FilterSchedule;
+  group_tiers?: FilterGroupTier[];
+  advanced_expression?: string;
+  next_activation?: string;
   actions_count: number;
   actions_enabled_count: number;
   actions: Action[];
@@ -80,6 +110,70 @@ interface Filter {
   external: ExternalFilter[];
 }
 
+interface FilterBreaker {
+  state: "CLOSED" | "OPEN" | "HALF_OPEN";
+  failures: number;
+  trips: number;
+  bypassed: number;
+  last_error?: string;
+  last_failure_at?: string;
+  open_until?: string;
+}
+
+interface FilterGroup {
+  id: number;
+  name: string;
+  enabled: boolean;
+  priority: number;
+  filter_count: number;
+  created_at: Date;
+  updated_at: Date;
+}
+
+interface FilterDryRunRequest {
+  filter_id?: number;
+  filter?: Filter;
+  limit?: number;
+}
+
+interface FilterDryRunResult {
+  release_id: number;
+  name: string;
+  indexer: string;
+  size: number;
+  timestamp: Date;
+  match: boolean;
+  rejections: string[];
+  skipped: string[];
+  size_unknown: boolean;
+}
+
+interface FilterDryRunResponse {
+  filter_id: number;
+  filter_name: string;
+  checked: number;
+  matched: number;
+  results: FilterDryRunResult[];
+}
+
+interface FilterSchedule {
+  timezone?: string;
+  windows?: FilterScheduleWindow[];
+}
+
+interface FilterScheduleWindow {
+  days?: FilterScheduleDay[];
+  start: string;
+  end: string;
+}
+
+type FilterScheduleDay = "sun" | "mon" | "tue" | "wed" | "thu" | "fri" | "sat";
+
+interface FilterGroupTier {
+  groups: string[];
+  delay_minutes: number;
+}
+
 interface Action {
   id: number;
   name: string;
@@ -87,6 +181,9 @@ interface Action {
   enabled: boolean;
   exec_cmd?: string;
   exec_args?: string;
+  exec_timeout?: number;
+  exec_env?: string[];
+  exec_dir?: string;
   watch_folder?: string;
   category?: string;
   tags?: string;
@@ -102,28 +199,82 @@ interface Action {
   limit_download_speed?: number;
   limit_ratio?: number;
   limit_seed_time?: number;
+  limit_inactive_seed_time?: number;
+  max_connections?: number;
   reannounce_skip: boolean;
   reannounce_delete: boolean;
   reannounce_interval: number;
   reannounce_max_attempts: number;
+  reannounce_jitter?: number;
+  require_approval?: boolean;
+  remove_ratio?: number;
+  remove_seed_time?: number;
+  remove_after_days?: number;
+  remove_delete_data?: boolean;
+  run_condition?: ActionRunCondition;
+  validate_torrent?: boolean;
+  validate_announce?: string;
+  validate_private?: ActionValidatePrivate;
+  dupe_key?: string;
+  completion_hooks?: ActionCompletionHook[];
+  cross_seed?: ActionCrossSeed;
+  custom1?: string;
+  custom2?: string;
+  bandwidth_group?: string;
+  move_completed_path?: string;
   webhook_host: string,
   webhook_type: string;
   webhook_method: string;
   webhook_data: string,
   webhook_headers: string[];
+  webhook_secret?: string;
+  webhook_retry_attempts?: number;
+  webhook_retry_delay_seconds?: number;
   external_download_client_id?: number;
   external_download_client?: string;
   client_id?: number;
+  failover_client_ids?: number[];
+  client_pool_mode?: ActionClientPoolMode;
   filter_id?: number;
 }
 
 type ActionContentLayout = "ORIGINAL" | "SUBFOLDER_CREATE" | "SUBFOLDER_NONE";
 
+type ActionRunCondition = "ALWAYS" | "ON_SUCCESS" | "ON_FAILURE";
+
+type ActionClientPoolMode = "ROUND_ROBIN" | "LEAST_ACTIVE" | "";
+
+type ActionValidatePrivate = "PRIVATE" | "PUBLIC" | "";
+
+type ActionCrossSeed = "ON_PUSH" | "ON_COMPLETE" | "";
+
+type ActionCompletionHookType = "EXEC" | "WEBHOOK" | "PLEX_SCAN" | "AUTOSCAN" | "JELLYFIN_SCAN";
+
+interface ActionCompletionHook {
+  type: ActionCompletionHookType;
+  exec_cmd?: string;
+  exec_args?: string;
+  webhook_host?: string;
+  webhook_method?: string;
+  webhook_data?: string;
+  plex_host?: string;
+  plex_token?: string;
+  plex_section?: string;
+  plex_path?: string;
+  autoscan_host?: string;
+  autoscan_username?: string;
+  autoscan_password?: string;
+  autoscan_path?: string;
+  jellyfin_host?: string;
+  jellyfin_api_key?: string;
+  jellyfin_path?: string;
+}
+
 type ActionPriorityLayout = "MAX" | "MIN" | "";
 
 type ActionType = "TEST" | "EXEC" | "WATCH_FOLDER" | "WEBHOOK" | DownloadClientType;
 
-type ExternalType = "EXEC" |  "WEBHOOK";
+type ExternalType = "EXEC" |  "WEBHOOK" | "WEBHOOK_VERDICT";
 
 type WebhookMethod = "GET" | "POST" | "PUT" | "PATCH" | "DELETE";
 
@@ -145,5 +296,6 @@ interface ExternalFilter {
   webhook_retry_status?: string,
   webhook_retry_attempts?: number;
   webhook_retry_delay_seconds?: number;
+  webhook_timeout_seconds?: number;
   filter_id?: number;
 }
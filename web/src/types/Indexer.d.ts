@@ -13,9 +13,16 @@ interface Indexer {
   base_url: string;
   use_proxy?: boolean;
   proxy_id?: number;
+  identity?: IndexerIdentity;
   settings: Array<IndexerSetting>;
 }
 
+interface IndexerIdentity {
+  user_agent?: string;
+  headers?: Record<string, string>;
+  cookie?: string;
+}
+
 interface IndexerMinimal {
   id: number;
   name: string;
@@ -39,6 +46,7 @@ interface IndexerDefinition {
   supports: string[];
   use_proxy?: boolean;
   proxy_id?: number;
+  identity?: IndexerIdentity;
   settings: IndexerSetting[];
   irc: IndexerIRC;
   torznab: IndexerTorznab;
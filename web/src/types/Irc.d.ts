@@ -17,6 +17,8 @@ interface IrcNetwork {
   use_bouncer: boolean;
   bouncer_addr: string;
   bot_mode: boolean;
+  status_command: string;
+  status_nicks: string;
   channels: IrcChannel[];
   connected: boolean;
   connected_since: string;
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+interface List {
+  id: number;
+  name: string;
+  type: ListType;
+  enabled: boolean;
+  url: string;
+  api_key?: string;
+  client_id?: number;
+  tags_include?: string[];
+  tags_exclude?: string[];
+  filter_field: ListFilterField;
+  filters: ListFilter[];
+  refresh_interval: number;
+  last_refresh_time?: string;
+  last_refresh_status?: ListRefreshStatus;
+  last_refresh_error?: string;
+}
+
+interface ListCreate {
+  name: string;
+  type: ListType;
+  enabled: boolean;
+  url: string;
+  api_key?: string;
+  client_id?: number;
+  tags_include?: string[];
+  tags_exclude?: string[];
+  filter_field: ListFilterField;
+  filters: ListFilter[];
+  refresh_interval: number;
+}
+
+interface ListFilter {
+  id: number;
+  name: string;
+}
+
+type ListType = "TRAKT" | "MDBLIST" | "IMDB" | "PLAINTEXT" | "SONARR" | "RADARR" | "LIDARR" | "READARR";
+
+type ListFilterField = "SHOWS" | "MATCH_RELEASES" | "EXCEPT_RELEASES";
+
+type ListRefreshStatus = "SUCCESS" | "ERROR";
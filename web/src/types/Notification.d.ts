@@ -3,13 +3,20 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER" | "GOTIFY" | "NTFY" | "LUNASEA" | "SHOUTRRR";
+type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER" | "GOTIFY" | "NTFY" | "LUNASEA" | "SHOUTRRR" | "SLACK" | "WEBHOOK";
 type NotificationEvent =
   "PUSH_APPROVED"
   | "PUSH_REJECTED"
   | "PUSH_ERROR"
   | "IRC_DISCONNECTED"
   | "IRC_RECONNECTED"
+  | "IRC_CHANNEL_PARTED"
+  | "IRC_CHANNEL_KICKED"
+  | "FEED_ERROR"
+  | "DOWNLOAD_CLIENT_UNHEALTHY"
+  | "DELAYED_RELEASE_EXPIRED"
+  | "SIZE_MISMATCH"
+  | "DOWNLOAD_COMPLETE"
   | "APP_UPDATE_AVAILABLE";
 
 interface ServiceNotification {
@@ -26,4 +33,42 @@ interface ServiceNotification {
   topic?: string;
   host?: string;
   username?: string;
+  headers?: string;
+  template?: string;
+  secret?: string;
+  message_templates?: NotificationMessageTemplates;
+  quiet_hours_enabled?: boolean;
+  quiet_hours_start?: string;
+  quiet_hours_end?: string;
+  quiet_hours_timezone?: string;
+  quiet_hours_mode?: QuietHoursMode;
+  quiet_hours_allow_errors?: boolean;
+}
+
+// QuietHoursMode is what happens to the events of an agent during its quiet hours
+type QuietHoursMode = "SUPPRESS" | "DEFER";
+
+// NotificationMessageTemplate replaces the default title and message of an event with macros
+interface NotificationMessageTemplate {
+  title: string;
+  message: string;
+}
+
+type NotificationMessageTemplates = Partial<Record<NotificationEvent, NotificationMessageTemplate>>;
+
+type NotificationDeliveryStatus = "PENDING" | "FAILED" | "DEFERRED";
+
+// NotificationDelivery is a notification which could not be sent and is queued for a retry
+interface NotificationDelivery {
+  id: number;
+  notification_id: number;
+  notification_name: string;
+  event: NotificationEvent;
+  payload: Record<string, unknown>;
+  status: NotificationDeliveryStatus;
+  attempts: number;
+  last_error: string;
+  next_attempt_at: string;
+  created_at: string;
+  updated_at: string;
 }
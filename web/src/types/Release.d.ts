@@ -47,6 +47,16 @@ interface ReleaseActionStatus {
   release_id: number;
   rejections: string[];
   timestamp: string
+  reannounce?: ReannounceAttempt[];
+  completed_at?: string;
+  removed_at?: string;
+}
+
+interface ReannounceAttempt {
+  attempt: number;
+  timestamp: string;
+  working: boolean;
+  message?: string;
 }
 
 interface ReleaseFindResponse {
@@ -72,5 +82,29 @@ interface ReleaseFilter {
 interface DeleteParams {
   olderThan?: number;
   indexers?: string[];
+  filterIds?: number[];
   releaseStatuses?: string[];
-}
\ No newline at end of file
+}
+
+interface DeleteReleaseResponse {
+  deleted: number;
+}
+
+interface ReleaseSimulateReq {
+  indexer_identifier: string;
+  announce_lines: string[];
+}
+
+interface ReleaseSimulateFilterResult {
+  filter_id: number;
+  filter_name: string;
+  priority: number;
+  match: boolean;
+  rejections: string[];
+  skipped?: string[];
+}
+
+interface ReleaseSimulateResponse {
+  release: Release;
+  filters: ReleaseSimulateFilterResult[];
+}